@@ -0,0 +1,28 @@
+// Package auth authenticates the users of the manager with LDAP or Active Directory and with OpenID Connect.
+//
+// The LDAP client with its BER encoding (RFC 4511) and the OpenID Connect relying party are implemented with the
+// standard library instead of go-ldap and go-oidc, so the manager keeps its small dependency tree. They only cover
+// what the login needs: simple bind, StartTLS and equality searches, and the authorization code flow with PKCE and
+// RS256/ES256 ID tokens. Their decoders are fuzzed and tested against the wire format of Active Directory and the
+// JWS examples of RFC 7515.
+package auth
+
+import (
+	"errors"
+
+	"github.com/siherrmann/queuerManager/model"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrUserNotFound       = errors.New("user not found")
+)
+
+// Authenticator authenticates users of the manager and resolves their role, eg. from LDAP groups.
+type Authenticator interface {
+	// Authenticate checks the password and returns the user with the current role.
+	Authenticate(username string, password string) (*model.User, error)
+	// LookupUser returns the user with the current role without a password to sync the role.
+	// It returns ErrUserNotFound if the user does not exist anymore.
+	LookupUser(username string) (*model.User, error)
+}
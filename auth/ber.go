@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// BER tags used by the LDAP messages, see RFC 4511
+const (
+	berTagBoolean     = 0x01
+	berTagInteger     = 0x02
+	berTagOctetString = 0x04
+	berTagEnumerated  = 0x0a
+	berTagSequence    = 0x30
+	berTagSet         = 0x31
+)
+
+// berMaxLength limits the size of a received element to protect against malformed responses
+const berMaxLength = 16 << 20
+
+// berElement is a decoded BER element with its single byte tag.
+type berElement struct {
+	tag     byte
+	content []byte
+}
+
+// children decodes the content of a constructed element.
+func (e *berElement) children() ([]*berElement, error) {
+	return parseBER(e.content)
+}
+
+// int decodes the content of an INTEGER or ENUMERATED element.
+func (e *berElement) int() (int, error) {
+	if len(e.content) == 0 || len(e.content) > 4 {
+		return 0, fmt.Errorf("invalid integer length %d", len(e.content))
+	}
+	value := int(int8(e.content[0]))
+	for _, b := range e.content[1:] {
+		value = value<<8 | int(b)
+	}
+	return value, nil
+}
+
+func berLength(length int) []byte {
+	if length < 0x80 {
+		return []byte{byte(length)}
+	}
+
+	lengthBytes := []byte{}
+	for length > 0 {
+		lengthBytes = append([]byte{byte(length)}, lengthBytes...)
+		length >>= 8
+	}
+	return append([]byte{0x80 | byte(len(lengthBytes))}, lengthBytes...)
+}
+
+func berTLV(tag byte, content []byte) []byte {
+	encoded := append([]byte{tag}, berLength(len(content))...)
+	return append(encoded, content...)
+}
+
+func berConcat(elements ...[]byte) []byte {
+	content := []byte{}
+	for _, element := range elements {
+		content = append(content, element...)
+	}
+	return content
+}
+
+func berSequence(elements ...[]byte) []byte {
+	return berTLV(berTagSequence, berConcat(elements...))
+}
+
+func berInt(tag byte, value int) []byte {
+	content := []byte{byte(value)}
+	for value > 0x7f || value < -0x80 {
+		value >>= 8
+		content = append([]byte{byte(value)}, content...)
+	}
+	return berTLV(tag, content)
+}
+
+func berOctetString(value string) []byte {
+	return berTLV(berTagOctetString, []byte(value))
+}
+
+func berBool(value bool) []byte {
+	if value {
+		return berTLV(berTagBoolean, []byte{0xff})
+	}
+	return berTLV(berTagBoolean, []byte{0x00})
+}
+
+// readBER reads a single element from the reader.
+func readBER(reader *bufio.Reader) (*berElement, error) {
+	tag, err := reader.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	first, err := reader.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	length := int(first)
+	if first&0x80 != 0 {
+		lengthBytes := int(first & 0x7f)
+		if lengthBytes == 0 || lengthBytes > 4 {
+			return nil, fmt.Errorf("unsupported ber length encoding")
+		}
+		length = 0
+		for range lengthBytes {
+			b, err := reader.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			length = length<<8 | int(b)
+		}
+	}
+	if length > berMaxLength {
+		return nil, fmt.Errorf("ber element too large (%d bytes)", length)
+	}
+
+	content := make([]byte, length)
+	_, err = io.ReadFull(reader, content)
+	if err != nil {
+		return nil, err
+	}
+
+	return &berElement{tag: tag, content: content}, nil
+}
+
+// parseBER decodes all elements of the data.
+func parseBER(data []byte) ([]*berElement, error) {
+	elements := []*berElement{}
+	for len(data) > 0 {
+		if len(data) < 2 {
+			return nil, fmt.Errorf("truncated ber element")
+		}
+		tag := data[0]
+		length := int(data[1])
+		offset := 2
+		if data[1]&0x80 != 0 {
+			lengthBytes := int(data[1] & 0x7f)
+			if lengthBytes == 0 || lengthBytes > 4 || len(data) < 2+lengthBytes {
+				return nil, fmt.Errorf("invalid ber length encoding")
+			}
+			length = 0
+			for _, b := range data[2 : 2+lengthBytes] {
+				length = length<<8 | int(b)
+			}
+			offset += lengthBytes
+		}
+		if length < 0 || len(data) < offset+length {
+			return nil, fmt.Errorf("truncated ber element")
+		}
+
+		elements = append(elements, &berElement{tag: tag, content: data[offset : offset+length]})
+		data = data[offset+length:]
+	}
+	return elements, nil
+}
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBERInt(t *testing.T) {
+	for _, value := range []int{0, 1, 127, 128, 255, 256, 65535, 1 << 23, -1, -128, -129} {
+		elements, err := parseBER(berInt(berTagInteger, value))
+		require.NoError(t, err)
+		require.Len(t, elements, 1)
+
+		decoded, err := elements[0].int()
+		require.NoError(t, err)
+		assert.Equal(t, value, decoded)
+	}
+}
+
+func TestBERLength(t *testing.T) {
+	t.Run("Should encode the minimal length", func(t *testing.T) {
+		assert.Equal(t, []byte{0x7f}, berLength(127))
+		assert.Equal(t, []byte{0x81, 0x80}, berLength(128))
+		assert.Equal(t, []byte{0x82, 0x01, 0x00}, berLength(256))
+	})
+
+	t.Run("Should decode the long lengths of Active Directory", func(t *testing.T) {
+		elements, err := parseBER([]byte{berTagOctetString, 0x84, 0x00, 0x00, 0x00, 0x02, 'o', 'k'})
+		require.NoError(t, err)
+		require.Len(t, elements, 1)
+		assert.Equal(t, "ok", string(elements[0].content))
+	})
+
+	t.Run("Should reject indefinite and truncated lengths", func(t *testing.T) {
+		_, err := parseBER([]byte{berTagSequence, 0x80, 0x00, 0x00})
+		assert.Error(t, err)
+		_, err = parseBER([]byte{berTagOctetString, 0x05, 'a'})
+		assert.Error(t, err)
+		_, err = readBER(bufio.NewReader(bytes.NewReader([]byte{berTagOctetString, 0x84, 0x7f, 0xff, 0xff, 0xff})))
+		assert.ErrorContains(t, err, "too large")
+	})
+}
+
+func FuzzParseBER(f *testing.F) {
+	f.Add(berSequence(berInt(berTagInteger, 1), berOctetString("cn=admin")))
+	f.Add([]byte{berTagSequence, 0x84, 0x00, 0x00, 0x00, 0x03, 0x02, 0x01, 0x01})
+	f.Add([]byte{berTagSequence, 0x85, 0xff})
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		elements, err := parseBER(data)
+		if err != nil {
+			return
+		}
+
+		// Decoded elements encode to elements with the same tag and content
+		for _, element := range elements {
+			reencoded, err := parseBER(berTLV(element.tag, element.content))
+			if err != nil || len(reencoded) != 1 || reencoded[0].tag != element.tag || !bytes.Equal(reencoded[0].content, element.content) {
+				t.Fatalf("element 0x%x does not round trip: %v", element.tag, err)
+			}
+			_, _ = element.int()
+			_, _ = element.children()
+		}
+	})
+}
+
+func FuzzReadBER(f *testing.F) {
+	f.Add(berSequence(berInt(berTagInteger, 1), berTLV(ldapOpBindResponse, berConcat(berInt(berTagEnumerated, 0), berOctetString(""), berOctetString("")))))
+	f.Add([]byte{berTagSequence, 0x84, 0x00, 0x00, 0x00})
+	f.Add([]byte{berTagSequence})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		element, err := readBER(bufio.NewReader(bytes.NewReader(data)))
+		if err != nil {
+			return
+		}
+		if len(element.content) > berMaxLength || len(element.content) > len(data) {
+			t.Fatalf("element of %d bytes read from %d bytes", len(element.content), len(data))
+		}
+
+		// A response that is not a valid LDAP message is rejected without panic
+		if response, err := element.children(); err == nil && len(response) >= 2 {
+			_ = ldapResultError(response[1])
+			_, _ = parseLDAPEntry(response[1])
+		}
+	})
+}
@@ -0,0 +1,546 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/siherrmann/queuerManager/helper"
+	"github.com/siherrmann/queuerManager/model"
+)
+
+// LDAP protocol operations, see RFC 4511
+const (
+	ldapOpBindRequest       = 0x60
+	ldapOpBindResponse      = 0x61
+	ldapOpUnbindRequest     = 0x42
+	ldapOpSearchRequest     = 0x63
+	ldapOpSearchResultEntry = 0x64
+	ldapOpSearchResultDone  = 0x65
+	ldapOpSearchResultRef   = 0x73
+	ldapOpExtendedRequest   = 0x77
+	ldapOpExtendedResponse  = 0x78
+
+	ldapFilterAnd           = 0xa0
+	ldapFilterEqualityMatch = 0xa3
+	ldapAuthSimple          = 0x80
+	ldapExtendedRequestName = 0x80
+
+	ldapResultSuccess            = 0
+	ldapResultInvalidCredentials = 49
+
+	ldapStartTLSOID = "1.3.6.1.4.1.1466.20037"
+)
+
+// LDAPConfig holds the configuration of the LDAP authenticator
+type LDAPConfig struct {
+	URL                string            // Server URL (ldap:// or ldaps://)
+	StartTLS           bool              // Upgrade ldap:// connections with StartTLS
+	InsecureSkipVerify bool              // Skip the certificate verification (only for testing)
+	BindDN             string            // Service account to search users, anonymous if empty
+	BindPassword       string            // Password of the service account
+	BaseDN             string            // Base DN of the user search
+	UserAttribute      string            // Attribute matched with the username (uid, sAMAccountName for AD)
+	UserObjectClass    string            // Optional object class of users (eg. person)
+	GroupAttribute     string            // Attribute with the group DNs of a user (memberOf)
+	GroupRoles         map[string]string // Lowercase group DN or CN to role
+	Timeout            time.Duration     // Timeout of the connection and each request
+}
+
+// LDAPAuthenticator authenticates users with a bind against an LDAP server or Active Directory
+// and maps their group membership to manager roles.
+type LDAPAuthenticator struct {
+	Config LDAPConfig
+}
+
+// NewLDAPAuthenticatorFromEnv creates an LDAP authenticator based on environment variables.
+// It returns nil if QUEUER_MANAGER_LDAP_URL is not set.
+func NewLDAPAuthenticatorFromEnv() (*LDAPAuthenticator, error) {
+	ldapURL := os.Getenv("QUEUER_MANAGER_LDAP_URL")
+	if ldapURL == "" {
+		return nil, nil
+	}
+
+	groupRoles, err := ParseGroupRoles(os.Getenv("QUEUER_MANAGER_LDAP_GROUP_ROLES"))
+	if err != nil {
+		return nil, err
+	}
+
+	config := LDAPConfig{
+		URL:                ldapURL,
+		StartTLS:           helper.GetEnvOrDefault("QUEUER_MANAGER_LDAP_START_TLS", "false") == "true",
+		InsecureSkipVerify: helper.GetEnvOrDefault("QUEUER_MANAGER_LDAP_INSECURE_SKIP_VERIFY", "false") == "true",
+		BindDN:             os.Getenv("QUEUER_MANAGER_LDAP_BIND_DN"),
+		BindPassword:       os.Getenv("QUEUER_MANAGER_LDAP_BIND_PASSWORD"),
+		BaseDN:             os.Getenv("QUEUER_MANAGER_LDAP_BASE_DN"),
+		UserAttribute:      helper.GetEnvOrDefault("QUEUER_MANAGER_LDAP_USER_ATTRIBUTE", "uid"),
+		UserObjectClass:    os.Getenv("QUEUER_MANAGER_LDAP_USER_OBJECT_CLASS"),
+		GroupAttribute:     helper.GetEnvOrDefault("QUEUER_MANAGER_LDAP_GROUP_ATTRIBUTE", "memberOf"),
+		GroupRoles:         groupRoles,
+		Timeout:            10 * time.Second,
+	}
+	if config.BaseDN == "" {
+		return nil, fmt.Errorf("missing required ldap configuration: QUEUER_MANAGER_LDAP_BASE_DN")
+	}
+	if len(config.GroupRoles) == 0 {
+		return nil, fmt.Errorf("missing required ldap configuration: QUEUER_MANAGER_LDAP_GROUP_ROLES")
+	}
+
+	return &LDAPAuthenticator{Config: config}, nil
+}
+
+// Authenticate searches the user with the service account and binds with the user DN and password.
+func (a *LDAPAuthenticator) Authenticate(username string, password string) (*model.User, error) {
+	// An empty password would be an unauthenticated bind, which most servers accept
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := a.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.close()
+
+	entry, err := a.searchUser(conn, username)
+	if err != nil {
+		return nil, err
+	}
+
+	err = conn.bind(entry.dn, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.userFromEntry(username, entry), nil
+}
+
+// LookupUser searches the user with the service account to sync the role without a login.
+func (a *LDAPAuthenticator) LookupUser(username string) (*model.User, error) {
+	conn, err := a.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.close()
+
+	entry, err := a.searchUser(conn, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.userFromEntry(username, entry), nil
+}
+
+// RoleForGroups returns the highest role of the mapped groups. Groups match by their DN or CN.
+func (a *LDAPAuthenticator) RoleForGroups(groups []string) string {
+	roles := []string{}
+	for _, group := range groups {
+		group = strings.ToLower(strings.TrimSpace(group))
+		if role, ok := a.Config.GroupRoles[group]; ok {
+			roles = append(roles, role)
+		}
+		if role, ok := a.Config.GroupRoles[groupCN(group)]; ok {
+			roles = append(roles, role)
+		}
+	}
+	return model.HighestRole(roles...)
+}
+
+func (a *LDAPAuthenticator) connect() (*ldapConn, error) {
+	conn, err := dialLDAP(a.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.Config.BindDN != "" {
+		err = conn.bind(a.Config.BindDN, a.Config.BindPassword)
+		if err != nil {
+			conn.close()
+			return nil, fmt.Errorf("error binding ldap service account: %w", err)
+		}
+	}
+
+	return conn, nil
+}
+
+func (a *LDAPAuthenticator) searchUser(conn *ldapConn, username string) (*ldapEntry, error) {
+	filter := ldapEqualityFilter(a.Config.UserAttribute, username)
+	if a.Config.UserObjectClass != "" {
+		filter = berTLV(ldapFilterAnd, berConcat(ldapEqualityFilter("objectClass", a.Config.UserObjectClass), filter))
+	}
+
+	entries, err := conn.search(a.Config.BaseDN, filter, []string{"displayName", "cn", "mail", a.Config.GroupAttribute})
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, ErrUserNotFound
+	}
+	if len(entries) > 1 {
+		return nil, fmt.Errorf("ldap search returned %d users for %s", len(entries), username)
+	}
+
+	return entries[0], nil
+}
+
+func (a *LDAPAuthenticator) userFromEntry(username string, entry *ldapEntry) *model.User {
+	name := entry.first("displayName")
+	if name == "" {
+		name = entry.first("cn")
+	}
+	groups := entry.values(a.Config.GroupAttribute)
+
+	return &model.User{
+		Username: username,
+		Name:     name,
+		Email:    entry.first("mail"),
+		Groups:   groups,
+		Role:     a.RoleForGroups(groups),
+		Source:   model.USER_SOURCE_LDAP,
+		Active:   true,
+	}
+}
+
+// ParseGroupRoles parses the group to role mapping, eg. "queuer-admins=admin;CN=Ops,OU=Groups,DC=example,DC=com=operator".
+// The role is separated at the last '=', so groups can be DNs. Groups are lowercased.
+func ParseGroupRoles(value string) (map[string]string, error) {
+	groupRoles := map[string]string{}
+	for mapping := range strings.SplitSeq(value, ";") {
+		mapping = strings.TrimSpace(mapping)
+		if mapping == "" {
+			continue
+		}
+
+		index := strings.LastIndex(mapping, "=")
+		if index <= 0 {
+			return nil, fmt.Errorf("invalid group role mapping %q (must be group=role)", mapping)
+		}
+		group := strings.ToLower(strings.TrimSpace(mapping[:index]))
+		role := strings.TrimSpace(mapping[index+1:])
+		if !model.IsValidRole(role) {
+			return nil, fmt.Errorf("invalid role %q for group %s (must be viewer, operator or admin)", role, group)
+		}
+		groupRoles[group] = role
+	}
+	return groupRoles, nil
+}
+
+// groupCN returns the value of the first RDN of a lowercase group DN, eg. queuer-admins of cn=queuer-admins,ou=groups.
+func groupCN(group string) string {
+	rdn, _, _ := strings.Cut(group, ",")
+	attribute, value, found := strings.Cut(rdn, "=")
+	if !found || strings.TrimSpace(attribute) != "cn" {
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
+
+func ldapEqualityFilter(attribute string, value string) []byte {
+	return berTLV(ldapFilterEqualityMatch, berConcat(berOctetString(attribute), berOctetString(value)))
+}
+
+// =======LDAP client=======
+
+// ldapEntry is a search result entry with its attribute values by lowercase attribute name.
+type ldapEntry struct {
+	dn         string
+	attributes map[string][]string
+}
+
+func (e *ldapEntry) values(attribute string) []string {
+	return e.attributes[strings.ToLower(attribute)]
+}
+
+func (e *ldapEntry) first(attribute string) string {
+	values := e.values(attribute)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// ldapConn is a minimal synchronous LDAPv3 client supporting bind, search and StartTLS.
+type ldapConn struct {
+	conn      net.Conn
+	reader    *bufio.Reader
+	messageID int
+	timeout   time.Duration
+}
+
+func dialLDAP(config LDAPConfig) (*ldapConn, error) {
+	serverURL, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ldap url: %w", err)
+	}
+
+	host := serverURL.Host
+	tlsConfig := &tls.Config{
+		ServerName:         serverURL.Hostname(),
+		InsecureSkipVerify: config.InsecureSkipVerify, // #nosec G402 -- Only enabled explicitly for testing.
+		MinVersion:         tls.VersionTLS12,
+	}
+	dialer := &net.Dialer{Timeout: config.Timeout}
+
+	var conn net.Conn
+	switch serverURL.Scheme {
+	case "ldap":
+		if serverURL.Port() == "" {
+			host = net.JoinHostPort(serverURL.Hostname(), "389")
+		}
+		conn, err = dialer.Dial("tcp", host)
+	case "ldaps":
+		if serverURL.Port() == "" {
+			host = net.JoinHostPort(serverURL.Hostname(), "636")
+		}
+		conn, err = tls.DialWithDialer(dialer, "tcp", host, tlsConfig)
+	default:
+		return nil, fmt.Errorf("unsupported ldap url scheme %s (supported: ldap, ldaps)", serverURL.Scheme)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to ldap server: %w", err)
+	}
+
+	c := &ldapConn{
+		conn:    conn,
+		reader:  bufio.NewReader(conn),
+		timeout: config.Timeout,
+	}
+
+	if config.StartTLS && serverURL.Scheme == "ldap" {
+		err = c.startTLS(tlsConfig)
+		if err != nil {
+			c.close()
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// bind authenticates the connection with a simple bind.
+func (c *ldapConn) bind(dn string, password string) error {
+	op := berTLV(ldapOpBindRequest, berConcat(
+		berInt(berTagInteger, 3),
+		berOctetString(dn),
+		berTLV(ldapAuthSimple, []byte(password)),
+	))
+
+	response, err := c.request(op, ldapOpBindResponse)
+	if err != nil {
+		return err
+	}
+
+	return ldapResultError(response)
+}
+
+// search runs a subtree search and returns all entries.
+func (c *ldapConn) search(baseDN string, filter []byte, attributes []string) ([]*ldapEntry, error) {
+	attributeList := [][]byte{}
+	for _, attribute := range attributes {
+		attributeList = append(attributeList, berOctetString(attribute))
+	}
+
+	op := berTLV(ldapOpSearchRequest, berConcat(
+		berOctetString(baseDN),
+		berInt(berTagEnumerated, 2), // scope: wholeSubtree
+		berInt(berTagEnumerated, 0), // derefAliases: never
+		berInt(berTagInteger, 2),    // sizeLimit: two entries are enough to detect ambiguous usernames
+		berInt(berTagInteger, int(c.timeout.Seconds())),
+		berBool(false),
+		filter,
+		berSequence(attributeList...),
+	))
+
+	messageID, err := c.send(op)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []*ldapEntry{}
+	for {
+		response, err := c.receive(messageID)
+		if err != nil {
+			return nil, err
+		}
+
+		switch response.tag {
+		case ldapOpSearchResultEntry:
+			entry, err := parseLDAPEntry(response)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		case ldapOpSearchResultRef:
+			// Referrals to other servers are not followed
+		case ldapOpSearchResultDone:
+			return entries, ldapResultError(response)
+		default:
+			return nil, fmt.Errorf("unexpected ldap response 0x%x", response.tag)
+		}
+	}
+}
+
+// startTLS upgrades the connection to TLS with the StartTLS extended operation.
+func (c *ldapConn) startTLS(tlsConfig *tls.Config) error {
+	op := berTLV(ldapOpExtendedRequest, berTLV(ldapExtendedRequestName, []byte(ldapStartTLSOID)))
+
+	response, err := c.request(op, ldapOpExtendedResponse)
+	if err != nil {
+		return err
+	}
+	err = ldapResultError(response)
+	if err != nil {
+		return fmt.Errorf("error starting tls: %w", err)
+	}
+
+	tlsConn := tls.Client(c.conn, tlsConfig)
+	err = tlsConn.SetDeadline(time.Now().Add(c.timeout))
+	if err != nil {
+		return err
+	}
+	err = tlsConn.Handshake()
+	if err != nil {
+		return fmt.Errorf("error starting tls: %w", err)
+	}
+
+	c.conn = tlsConn
+	c.reader = bufio.NewReader(tlsConn)
+	return nil
+}
+
+// close sends an unbind request and closes the connection.
+func (c *ldapConn) close() {
+	_, _ = c.send(berTLV(ldapOpUnbindRequest, nil))
+	_ = c.conn.Close()
+}
+
+func (c *ldapConn) request(op []byte, responseTag byte) (*berElement, error) {
+	messageID, err := c.send(op)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.receive(messageID)
+	if err != nil {
+		return nil, err
+	}
+	if response.tag != responseTag {
+		return nil, fmt.Errorf("unexpected ldap response 0x%x", response.tag)
+	}
+
+	return response, nil
+}
+
+func (c *ldapConn) send(op []byte) (int, error) {
+	c.messageID++
+	message := berSequence(berInt(berTagInteger, c.messageID), op)
+
+	err := c.conn.SetDeadline(time.Now().Add(c.timeout))
+	if err != nil {
+		return 0, err
+	}
+	_, err = c.conn.Write(message)
+	if err != nil {
+		return 0, fmt.Errorf("error sending ldap request: %w", err)
+	}
+
+	return c.messageID, nil
+}
+
+// receive reads the next message and returns its protocol operation.
+func (c *ldapConn) receive(messageID int) (*berElement, error) {
+	message, err := readBER(c.reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading ldap response: %w", err)
+	}
+	if message.tag != berTagSequence {
+		return nil, fmt.Errorf("invalid ldap message")
+	}
+
+	elements, err := message.children()
+	if err != nil {
+		return nil, err
+	}
+	if len(elements) < 2 {
+		return nil, fmt.Errorf("invalid ldap message")
+	}
+
+	responseID, err := elements[0].int()
+	if err != nil {
+		return nil, err
+	}
+	if responseID != messageID {
+		return nil, fmt.Errorf("unexpected ldap message id %d (expected %d)", responseID, messageID)
+	}
+
+	return elements[1], nil
+}
+
+// ldapResultError returns an error if the LDAPResult of the response is not successful.
+func ldapResultError(response *berElement) error {
+	elements, err := response.children()
+	if err != nil {
+		return err
+	}
+	if len(elements) < 3 {
+		return fmt.Errorf("invalid ldap result")
+	}
+
+	resultCode, err := elements[0].int()
+	if err != nil {
+		return err
+	}
+
+	switch resultCode {
+	case ldapResultSuccess:
+		return nil
+	case ldapResultInvalidCredentials:
+		return ErrInvalidCredentials
+	default:
+		return fmt.Errorf("ldap error %d: %s", resultCode, string(elements[2].content))
+	}
+}
+
+func parseLDAPEntry(response *berElement) (*ldapEntry, error) {
+	elements, err := response.children()
+	if err != nil {
+		return nil, err
+	}
+	if len(elements) < 2 {
+		return nil, fmt.Errorf("invalid ldap search entry")
+	}
+
+	entry := &ldapEntry{
+		dn:         string(elements[0].content),
+		attributes: map[string][]string{},
+	}
+
+	attributes, err := elements[1].children()
+	if err != nil {
+		return nil, err
+	}
+	for _, attribute := range attributes {
+		parts, err := attribute.children()
+		if err != nil || len(parts) < 2 {
+			return nil, errors.Join(fmt.Errorf("invalid ldap attribute"), err)
+		}
+
+		values, err := parts[1].children()
+		if err != nil {
+			return nil, err
+		}
+
+		name := strings.ToLower(string(parts[0].content))
+		for _, value := range values {
+			entry.attributes[name] = append(entry.attributes[name], string(value.content))
+		}
+	}
+
+	return entry, nil
+}
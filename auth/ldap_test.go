@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// adTLV encodes an element with the four byte long form length Active Directory uses in its responses,
+// independent of the minimal encoding of berTLV.
+func adTLV(tag byte, content ...[]byte) []byte {
+	joined := bytes.Join(content, nil)
+	encoded := []byte{tag, 0x84, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(encoded[2:], uint32(len(joined)))
+	return append(encoded, joined...)
+}
+
+// adMessage encodes an LDAP message of a response with the message ID.
+func adMessage(messageID byte, op []byte) []byte {
+	return adTLV(berTagSequence, []byte{berTagInteger, 0x01, messageID}, op)
+}
+
+// adResult encodes the LDAPResult of a response operation with the result code.
+func adResult(tag byte, resultCode byte) []byte {
+	return adTLV(tag, []byte{berTagEnumerated, 0x01, resultCode}, []byte{berTagOctetString, 0x00}, []byte{berTagOctetString, 0x00})
+}
+
+// adAttribute encodes a PartialAttribute of a search result entry.
+func adAttribute(name string, values ...string) []byte {
+	encodedValues := [][]byte{}
+	for _, value := range values {
+		encodedValues = append(encodedValues, adTLV(berTagOctetString, []byte(value)))
+	}
+	return adTLV(berTagSequence, adTLV(berTagOctetString, []byte(name)), adTLV(berTagSet, encodedValues...))
+}
+
+// serveActiveDirectory answers the requests of one connection with the wire format of Active Directory:
+// long form lengths, a search result reference before the result done, and code 49 for a wrong user password.
+// The first request has to be the exact bind of the service account.
+func serveActiveDirectory(t *testing.T, listener net.Listener, expectedBind []byte, userPassword string) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	bind := make([]byte, len(expectedBind))
+	_, err = io.ReadFull(reader, bind)
+	if err != nil || !bytes.Equal(bind, expectedBind) {
+		t.Errorf("unexpected service bind %x", bind)
+		return
+	}
+	_, _ = conn.Write(adMessage(1, adResult(ldapOpBindResponse, ldapResultSuccess)))
+
+	for {
+		message, err := readBER(reader)
+		if err != nil {
+			return
+		}
+		elements, err := message.children()
+		if err != nil || len(elements) < 2 {
+			t.Errorf("invalid ldap message %x", message.content)
+			return
+		}
+		messageID := elements[0].content[0]
+
+		switch elements[1].tag {
+		case ldapOpSearchRequest:
+			_, _ = conn.Write(adMessage(messageID, adTLV(ldapOpSearchResultEntry,
+				adTLV(berTagOctetString, []byte("CN=Alice,OU=Users,DC=example,DC=com")),
+				adTLV(berTagSequence,
+					adAttribute("displayName", "Alice Example"),
+					adAttribute("mail", "alice@example.com"),
+					adAttribute("memberOf", "CN=Staff,OU=Groups,DC=example,DC=com", "CN=queuer-admins,OU=Groups,DC=example,DC=com"),
+				),
+			)))
+			_, _ = conn.Write(adMessage(messageID, adTLV(ldapOpSearchResultRef, adTLV(berTagOctetString, []byte("ldap://DomainDnsZones.example.com/DC=DomainDnsZones,DC=example,DC=com")))))
+			_, _ = conn.Write(adMessage(messageID, adResult(ldapOpSearchResultDone, ldapResultSuccess)))
+		case ldapOpBindRequest:
+			parts, _ := elements[1].children()
+			resultCode := byte(ldapResultInvalidCredentials)
+			if len(parts) == 3 && string(parts[1].content) == "CN=Alice,OU=Users,DC=example,DC=com" && string(parts[2].content) == userPassword {
+				resultCode = ldapResultSuccess
+			}
+			_, _ = conn.Write(adMessage(messageID, adResult(ldapOpBindResponse, resultCode)))
+		case ldapOpUnbindRequest:
+			return
+		}
+	}
+}
+
+func TestLDAPActiveDirectoryInterop(t *testing.T) {
+	serviceDN := "cn=svc,dc=example,dc=com"
+	// LDAPMessage { messageID 1, BindRequest { version 3, name, simple "pw" } } of RFC 4511 in minimal DER
+	expectedBind, err := hex.DecodeString("302602010160210201030418" + hex.EncodeToString([]byte(serviceDN)) + "80027077")
+	require.NoError(t, err)
+
+	newAuthenticator := func(t *testing.T, userPassword string) *LDAPAuthenticator {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { listener.Close() })
+		go serveActiveDirectory(t, listener, expectedBind, userPassword)
+
+		return &LDAPAuthenticator{Config: LDAPConfig{
+			URL:            "ldap://" + listener.Addr().String(),
+			BindDN:         serviceDN,
+			BindPassword:   "pw",
+			BaseDN:         "DC=example,DC=com",
+			UserAttribute:  "sAMAccountName",
+			GroupAttribute: "memberOf",
+			GroupRoles:     map[string]string{"queuer-admins": model.ROLE_ADMIN},
+			Timeout:        5 * time.Second,
+		}}
+	}
+
+	t.Run("Should authenticate with the responses of Active Directory", func(t *testing.T) {
+		authenticator := newAuthenticator(t, "secret")
+
+		user, err := authenticator.Authenticate("alice", "secret")
+		require.NoError(t, err, "Expected Authenticate to not return an error")
+		assert.Equal(t, "alice", user.Username)
+		assert.Equal(t, "Alice Example", user.Name)
+		assert.Equal(t, "alice@example.com", user.Email)
+		assert.Len(t, user.Groups, 2)
+		assert.Equal(t, model.ROLE_ADMIN, user.Role)
+	})
+
+	t.Run("Should reject a wrong password", func(t *testing.T) {
+		authenticator := newAuthenticator(t, "secret")
+
+		_, err := authenticator.Authenticate("alice", "wrong")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+}
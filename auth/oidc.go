@@ -0,0 +1,518 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/siherrmann/queuerManager/helper"
+	"github.com/siherrmann/queuerManager/model"
+)
+
+// ErrInvalidToken is returned if the ID token of the provider can not be verified
+var ErrInvalidToken = errors.New("invalid id token")
+
+// oidcClockSkew is the tolerated clock difference to the provider when checking the token expiry
+const oidcClockSkew = time.Minute
+
+// oidcKeysRefreshInterval limits refetching the signing keys for unknown key IDs, eg. after a key rotation
+const oidcKeysRefreshInterval = time.Minute
+
+// OIDCConfig holds the configuration of the OpenID Connect login
+type OIDCConfig struct {
+	IssuerURL     string            // Issuer of the provider, the discovery document is loaded from it
+	ClientID      string            // Client ID of the manager at the provider
+	ClientSecret  string            // Client secret of the manager at the provider
+	RedirectURL   string            // Callback URL registered at the provider, eg. https://manager.example.com/login/oidc/callback
+	Scopes        []string          // Requested scopes, always including openid
+	UsernameClaim string            // Claim used as username (preferred_username)
+	GroupsClaim   string            // Claim with the groups of the user (groups)
+	GroupRoles    map[string]string // Lowercase group to role
+	DefaultRole   string            // Optional role of users without mapped group
+	Name          string            // Name of the provider shown on the login button
+	Timeout       time.Duration     // Timeout of each request to the provider
+}
+
+// OIDCProvider logs in users with the authorization code flow of an OpenID Connect provider
+// and maps their groups to manager roles. The discovery document and signing keys are loaded on first use.
+type OIDCProvider struct {
+	Config OIDCConfig
+	client *http.Client
+
+	mu            sync.Mutex
+	discovery     *oidcDiscovery
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+	EndSessionEndpoint    string `json:"end_session_endpoint"`
+}
+
+type oidcJWK struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// NewOIDCProviderFromEnv creates an OpenID Connect provider based on environment variables.
+// It returns nil if QUEUER_MANAGER_OIDC_ISSUER_URL is not set.
+func NewOIDCProviderFromEnv() (*OIDCProvider, error) {
+	issuerURL := os.Getenv("QUEUER_MANAGER_OIDC_ISSUER_URL")
+	if issuerURL == "" {
+		return nil, nil
+	}
+
+	groupRoles, err := ParseGroupRoles(os.Getenv("QUEUER_MANAGER_OIDC_GROUP_ROLES"))
+	if err != nil {
+		return nil, err
+	}
+
+	config := OIDCConfig{
+		IssuerURL:     strings.TrimSuffix(issuerURL, "/"),
+		ClientID:      os.Getenv("QUEUER_MANAGER_OIDC_CLIENT_ID"),
+		ClientSecret:  os.Getenv("QUEUER_MANAGER_OIDC_CLIENT_SECRET"),
+		RedirectURL:   os.Getenv("QUEUER_MANAGER_OIDC_REDIRECT_URL"),
+		Scopes:        strings.Fields(helper.GetEnvOrDefault("QUEUER_MANAGER_OIDC_SCOPES", "openid profile email")),
+		UsernameClaim: helper.GetEnvOrDefault("QUEUER_MANAGER_OIDC_USERNAME_CLAIM", "preferred_username"),
+		GroupsClaim:   helper.GetEnvOrDefault("QUEUER_MANAGER_OIDC_GROUPS_CLAIM", "groups"),
+		GroupRoles:    groupRoles,
+		DefaultRole:   os.Getenv("QUEUER_MANAGER_OIDC_DEFAULT_ROLE"),
+		Name:          helper.GetEnvOrDefault("QUEUER_MANAGER_OIDC_NAME", "Single Sign-On"),
+		Timeout:       10 * time.Second,
+	}
+	if config.ClientID == "" {
+		return nil, fmt.Errorf("missing required oidc configuration: QUEUER_MANAGER_OIDC_CLIENT_ID")
+	}
+	if config.RedirectURL == "" {
+		return nil, fmt.Errorf("missing required oidc configuration: QUEUER_MANAGER_OIDC_REDIRECT_URL")
+	}
+	if config.DefaultRole != "" && !model.IsValidRole(config.DefaultRole) {
+		return nil, fmt.Errorf("invalid oidc default role %q (must be viewer, operator or admin)", config.DefaultRole)
+	}
+	if len(config.GroupRoles) == 0 && config.DefaultRole == "" {
+		return nil, fmt.Errorf("missing required oidc configuration: QUEUER_MANAGER_OIDC_GROUP_ROLES or QUEUER_MANAGER_OIDC_DEFAULT_ROLE")
+	}
+
+	return NewOIDCProvider(config), nil
+}
+
+// NewOIDCProvider creates an OpenID Connect provider with the configuration.
+func NewOIDCProvider(config OIDCConfig) *OIDCProvider {
+	if !slices.Contains(config.Scopes, "openid") {
+		config.Scopes = append([]string{"openid"}, config.Scopes...)
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	return &OIDCProvider{
+		Config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// AuthCodeURL returns the URL of the provider the user logs in at.
+// The state protects the callback against CSRF, the nonce is checked in the ID token
+// and the verifier is sent as S256 PKCE challenge.
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state string, nonce string, verifier string) (string, error) {
+	discovery, err := p.loadDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.Config.ClientID},
+		"redirect_uri":          {p.Config.RedirectURL},
+		"scope":                 {strings.Join(p.Config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange redeems the authorization code of the callback, verifies the ID token and returns its user with the current role.
+func (p *OIDCProvider) Exchange(ctx context.Context, code string, nonce string, verifier string) (*model.User, error) {
+	discovery, err := p.loadDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.Config.RedirectURL},
+		"client_id":     {p.Config.ClientID},
+		"code_verifier": {verifier},
+	}
+	if p.Config.ClientSecret != "" {
+		form.Set("client_secret", p.Config.ClientSecret)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting token: %w", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("error reading token response: %w", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint returned %d: %s", response.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var tokenResponse struct {
+		IDToken string `json:"id_token"`
+	}
+	err = json.Unmarshal(body, &tokenResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding token response: %w", err)
+	}
+	if tokenResponse.IDToken == "" {
+		return nil, fmt.Errorf("%w: token response without id_token", ErrInvalidToken)
+	}
+
+	claims, err := p.verifyIDToken(ctx, tokenResponse.IDToken, nonce, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return p.userFromClaims(claims), nil
+}
+
+// EndSessionURL returns the logout URL of the provider, or an empty string if the provider has none.
+func (p *OIDCProvider) EndSessionURL(ctx context.Context, postLogoutRedirectURL string) string {
+	discovery, err := p.loadDiscovery(ctx)
+	if err != nil || discovery.EndSessionEndpoint == "" {
+		return ""
+	}
+
+	query := url.Values{"client_id": {p.Config.ClientID}}
+	if postLogoutRedirectURL != "" {
+		query.Set("post_logout_redirect_uri", postLogoutRedirectURL)
+	}
+	return discovery.EndSessionEndpoint + "?" + query.Encode()
+}
+
+// RoleForGroups returns the highest role of the mapped groups, or the default role if no group is mapped.
+func (p *OIDCProvider) RoleForGroups(groups []string) string {
+	roles := []string{}
+	for _, group := range groups {
+		if role, ok := p.Config.GroupRoles[strings.ToLower(strings.TrimSpace(group))]; ok {
+			roles = append(roles, role)
+		}
+	}
+	if role := model.HighestRole(roles...); role != "" {
+		return role
+	}
+	return p.Config.DefaultRole
+}
+
+// verifyIDToken checks the signature, issuer, audience, expiry and nonce of the ID token and returns its claims.
+func (p *OIDCProvider) verifyIDToken(ctx context.Context, idToken string, nonce string, now time.Time) (map[string]any, error) {
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidToken)
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	err = json.Unmarshal(headerJSON, &header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidToken)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+	key, err := p.signingKey(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	err = verifyJWTSignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature)
+	if err != nil {
+		return nil, err
+	}
+
+	payloadJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed payload", ErrInvalidToken)
+	}
+	claims := map[string]any{}
+	err = json.Unmarshal(payloadJSON, &claims)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed payload", ErrInvalidToken)
+	}
+
+	discovery, err := p.loadDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if issuer, _ := claims["iss"].(string); issuer != discovery.Issuer {
+		return nil, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, issuer)
+	}
+	if !slices.Contains(claimStrings(claims["aud"]), p.Config.ClientID) {
+		return nil, fmt.Errorf("%w: token not issued for client %s", ErrInvalidToken, p.Config.ClientID)
+	}
+	expires, ok := claims["exp"].(float64)
+	if !ok || now.After(time.Unix(int64(expires), 0).Add(oidcClockSkew)) {
+		return nil, fmt.Errorf("%w: token expired", ErrInvalidToken)
+	}
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidToken)
+	}
+	if subject, _ := claims["sub"].(string); subject == "" {
+		return nil, fmt.Errorf("%w: token without subject", ErrInvalidToken)
+	}
+
+	return claims, nil
+}
+
+// userFromClaims creates the user of the verified claims, the subject is kept as external ID.
+func (p *OIDCProvider) userFromClaims(claims map[string]any) *model.User {
+	subject, _ := claims["sub"].(string)
+	email, _ := claims["email"].(string)
+	name, _ := claims["name"].(string)
+
+	username, _ := claims[p.Config.UsernameClaim].(string)
+	if username == "" {
+		username = email
+	}
+	if username == "" {
+		username = subject
+	}
+	if name == "" {
+		name = username
+	}
+
+	groups := claimStrings(claims[p.Config.GroupsClaim])
+	return &model.User{
+		ExternalID: subject,
+		Username:   username,
+		Name:       name,
+		Email:      email,
+		Groups:     groups,
+		Role:       p.RoleForGroups(groups),
+		Source:     model.USER_SOURCE_OIDC,
+		Active:     true,
+	}
+}
+
+// loadDiscovery loads the discovery document of the issuer once.
+func (p *OIDCProvider) loadDiscovery(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	discovery := &oidcDiscovery{}
+	err := p.getJSON(ctx, p.Config.IssuerURL+"/.well-known/openid-configuration", discovery)
+	if err != nil {
+		return nil, fmt.Errorf("error loading oidc discovery document: %w", err)
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != p.Config.IssuerURL {
+		return nil, fmt.Errorf("oidc discovery document of issuer %q does not match %q", discovery.Issuer, p.Config.IssuerURL)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JwksURI == "" {
+		return nil, fmt.Errorf("oidc discovery document is missing endpoints")
+	}
+
+	p.discovery = discovery
+	return discovery, nil
+}
+
+// signingKey returns the key with the ID, the keys are refetched if the ID is unknown, eg. after a key rotation.
+func (p *OIDCProvider) signingKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	discovery, err := p.loadDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	if time.Since(p.keysFetchedAt) < oidcKeysRefreshInterval {
+		return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidToken, kid)
+	}
+
+	var jwks struct {
+		Keys []oidcJWK `json:"keys"`
+	}
+	err = p.getJSON(ctx, discovery.JwksURI, &jwks)
+	if err != nil {
+		return nil, fmt.Errorf("error loading oidc signing keys: %w", err)
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+
+	// Tokens without key ID can be verified if the provider has a single key
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, nil
+		}
+	}
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidToken, kid)
+}
+
+func (p *OIDCProvider) getJSON(ctx context.Context, requestURL string, value any) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %d", requestURL, response.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(response.Body, 1<<20)).Decode(value)
+}
+
+// publicKey decodes an RSA or P-256 EC key of the key set.
+func (k oidcJWK) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+	}
+}
+
+// verifyJWTSignature verifies an RS256 or ES256 signature of the signed token part.
+func verifyJWTSignature(alg string, key crypto.PublicKey, signed []byte, signature []byte) error {
+	digest := sha256.Sum256(signed)
+	switch alg {
+	case "RS256":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok || rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest[:], signature) != nil {
+			return fmt.Errorf("%w: invalid signature", ErrInvalidToken)
+		}
+	case "ES256":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || len(signature) != 64 {
+			return fmt.Errorf("%w: invalid signature", ErrInvalidToken)
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(ecKey, digest[:], r, s) {
+			return fmt.Errorf("%w: invalid signature", ErrInvalidToken)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, alg)
+	}
+	return nil
+}
+
+// claimStrings returns a string or string list claim as list, eg. the audience or the groups.
+func claimStrings(claim any) []string {
+	switch value := claim.(type) {
+	case string:
+		return []string{value}
+	case []any:
+		values := []string{}
+		for _, item := range value {
+			if str, ok := item.(string); ok {
+				values = append(values, str)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOIDCJWSInterop(t *testing.T) {
+	// Example of an ES256 JWS with its public key of RFC 7515 Appendix A.3
+	key, err := oidcJWK{
+		Kty: "EC",
+		Crv: "P-256",
+		X:   "f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU",
+		Y:   "x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0",
+	}.publicKey()
+	require.NoError(t, err, "Expected the key of the RFC to decode")
+
+	signed := []byte("eyJhbGciOiJFUzI1NiJ9.eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ")
+	signature, err := base64.RawURLEncoding.DecodeString("DtEhU3ljbEg8L38VWAfUAqOyKAM6-Xx-F4GawxaepmXFCgfTjDxw5djxLa8ISlSApmWQxfKTUJqPP3-Kg6NU1Q")
+	require.NoError(t, err)
+
+	t.Run("Should verify the signature of the RFC", func(t *testing.T) {
+		assert.NoError(t, verifyJWTSignature("ES256", key, signed, signature))
+	})
+
+	t.Run("Should reject a changed payload and other algorithms", func(t *testing.T) {
+		changed := append([]byte{}, signed...)
+		changed[len(changed)-1] ^= 1
+		assert.ErrorIs(t, verifyJWTSignature("ES256", key, changed, signature), ErrInvalidToken)
+		assert.ErrorIs(t, verifyJWTSignature("RS256", key, signed, signature), ErrInvalidToken)
+		assert.ErrorIs(t, verifyJWTSignature("none", key, signed, nil), ErrInvalidToken)
+	})
+}
+
+// newFuzzOIDCProvider creates a provider with the discovery document and the ES256 key of a test server.
+func newFuzzOIDCProvider(f *testing.F) (*OIDCProvider, *ecdsa.PrivateKey) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(f, err)
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	f.Cleanup(server.Close)
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(oidcDiscovery{
+			Issuer:                server.URL,
+			AuthorizationEndpoint: server.URL + "/authorize",
+			TokenEndpoint:         server.URL + "/token",
+			JwksURI:               server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []oidcJWK{{
+			Kid: "key",
+			Kty: "EC",
+			Crv: "P-256",
+			X:   base64.RawURLEncoding.EncodeToString(privateKey.X.FillBytes(make([]byte, 32))),
+			Y:   base64.RawURLEncoding.EncodeToString(privateKey.Y.FillBytes(make([]byte, 32))),
+		}}})
+	})
+
+	return NewOIDCProvider(OIDCConfig{IssuerURL: server.URL, ClientID: "manager"}), privateKey
+}
+
+// signES256 signs the header and payload with the key as compact JWS.
+func signES256(f *testing.F, privateKey *ecdsa.PrivateKey, header string, payload string) string {
+	signed := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + base64.RawURLEncoding.EncodeToString([]byte(payload))
+	digest := sha256.Sum256([]byte(signed))
+	r, s, err := ecdsa.Sign(rand.Reader, privateKey, digest[:])
+	require.NoError(f, err)
+	signature := append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func FuzzOIDCVerifyIDToken(f *testing.F) {
+	provider, privateKey := newFuzzOIDCProvider(f)
+	now := time.Now()
+	issuer := provider.Config.IssuerURL
+
+	validToken := signES256(f, privateKey, `{"alg":"ES256","kid":"key"}`, `{"iss":"`+issuer+`","aud":"manager","exp":`+strconv.FormatInt(now.Add(time.Hour).Unix(), 10)+`,"nonce":"n","sub":"alice"}`)
+	_, err := provider.verifyIDToken(context.Background(), validToken, "n", now)
+	require.NoError(f, err, "Expected the seed token to be valid")
+
+	f.Add(validToken)
+	f.Add(signES256(f, privateKey, `{"alg":"ES256","kid":"key"}`, `{"iss":"`+issuer+`","aud":["other"],"exp":1e300,"nonce":"n","sub":"alice"}`))
+	f.Add(signES256(f, privateKey, `{"alg":"none"}`, `{}`))
+	f.Add("a.b.c")
+	f.Add("..")
+
+	f.Fuzz(func(t *testing.T, idToken string) {
+		claims, err := provider.verifyIDToken(context.Background(), idToken, "n", now)
+		if err != nil {
+			return
+		}
+
+		// Only tokens signed by the key of the provider for the manager are accepted
+		if claims["nonce"] != "n" || claims["iss"] != issuer || !slices.Contains(claimStrings(claims["aud"]), "manager") {
+			t.Fatalf("accepted token with claims %v", claims)
+		}
+		_ = provider.userFromClaims(claims)
+	})
+}
@@ -0,0 +1,310 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/model"
+)
+
+// Client calls the API of a queuerManager instance, authenticated with an API key as bearer token.
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// ListOptions are the pagination and the filters of a list, eg. `status` or `taskKey` of the jobs.
+// Empty values are not sent, so the defaults of the instance are used.
+type ListOptions struct {
+	LastID  int
+	Limit   int
+	Filters map[string]string
+}
+
+// NewClient creates a client for the instance at the base URL, eg. https://queuer.example.com/queuer.
+func NewClient(baseURL string, apiKey string) (*Client, error) {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q (must be an http or https URL)", baseURL)
+	}
+
+	return &Client{
+		baseURL:    baseURL,
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// =======Tasks=======
+
+// GetTasks lists the tasks.
+func (c *Client) GetTasks(ctx context.Context, options *ListOptions) ([]*qmModel.Task, error) {
+	tasks := []*qmModel.Task{}
+	err := c.do(ctx, http.MethodGet, "/api/task/getTasks", options.query(), nil, "", &tasks)
+	return tasks, err
+}
+
+// GetTaskByKey returns the task with the key.
+func (c *Client) GetTaskByKey(ctx context.Context, key string) (*qmModel.Task, error) {
+	task := &qmModel.Task{}
+	err := c.do(ctx, http.MethodGet, "/api/task/getTaskByKey/"+url.PathEscape(key), nil, nil, "", task)
+	return task, err
+}
+
+// PutTask creates the task of the definition or updates the task with the same key.
+func (c *Client) PutTask(ctx context.Context, definition *qmModel.TaskDefinition) (*qmModel.Task, error) {
+	body, err := json.Marshal(definition)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding task: %w", err)
+	}
+
+	task := &qmModel.Task{}
+	err = c.do(ctx, http.MethodPut, "/api/task/putTask/"+url.PathEscape(definition.Key), nil, bytes.NewReader(body), "application/json", task)
+	return task, err
+}
+
+// ImportTasks imports the tasks of a task export or pipeline export, eg. of ExportTasks, and returns the message of the import.
+func (c *Client) ImportTasks(ctx context.Context, filename string, data []byte) (string, error) {
+	body, contentType, err := multipartBody("task_file", map[string][]byte{filepath.Base(filename): data})
+	if err != nil {
+		return "", err
+	}
+
+	message := &apiMessage{}
+	err = c.do(ctx, http.MethodPost, "/api/task/importTask", nil, body, contentType, message)
+	return message.Message, err
+}
+
+// ExportTasks exports the tasks with the RIDs as JSON, with includes (`schedules`, `webhooks`) as pipeline export.
+func (c *Client) ExportTasks(ctx context.Context, rids []uuid.UUID, includes ...string) (json.RawMessage, error) {
+	query := url.Values{}
+	for _, rid := range rids {
+		query.Add("rid", rid.String())
+	}
+	for _, include := range includes {
+		query.Add("include", include)
+	}
+
+	var export json.RawMessage
+	err := c.do(ctx, http.MethodGet, "/api/task/exportTask", query, nil, "", &export)
+	return export, err
+}
+
+// =======Jobs=======
+
+// AddJob adds a job of the task with the parameters by parameter key.
+func (c *Client) AddJob(ctx context.Context, taskKey string, parameters map[string]any) (*model.Job, error) {
+	body, err := json.Marshal(parameters)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding parameters: %w", err)
+	}
+
+	job := &model.Job{}
+	err = c.do(ctx, http.MethodPost, "/api/job/addJob/"+url.PathEscape(taskKey), nil, bytes.NewReader(body), "application/json", job)
+	return job, err
+}
+
+// GetJobs lists the active jobs, the filters are `status`, `taskKey`, `search`, `from` and `to`.
+func (c *Client) GetJobs(ctx context.Context, options *ListOptions) ([]*model.Job, error) {
+	jobs := []*model.Job{}
+	err := c.do(ctx, http.MethodPost, "/api/job/getJobs", options.query(), nil, "", &jobs)
+	return jobs, err
+}
+
+// CancelJob cancels the active job.
+func (c *Client) CancelJob(ctx context.Context, rid uuid.UUID) (*model.Job, error) {
+	job := &model.Job{}
+	err := c.do(ctx, http.MethodPost, "/api/job/cancelJob/"+rid.String(), nil, nil, "", job)
+	return job, err
+}
+
+// RetryJobs adds the archived jobs again with their original parameters, overridden by the parameters by key.
+func (c *Client) RetryJobs(ctx context.Context, rids []uuid.UUID, parameters map[string]any) ([]*qmModel.JobRetryResult, error) {
+	body, err := json.Marshal(&qmModel.JobRetryRequest{RIDs: rids, Parameters: parameters})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding retry request: %w", err)
+	}
+
+	results := []*qmModel.JobRetryResult{}
+	err = c.do(ctx, http.MethodPost, "/api/jobArchive/retryJobs", nil, bytes.NewReader(body), "application/json", &results)
+	return results, err
+}
+
+// =======Workers=======
+
+// GetWorkers lists the workers.
+func (c *Client) GetWorkers(ctx context.Context, options *ListOptions) ([]*model.Worker, error) {
+	workers := []*model.Worker{}
+	err := c.do(ctx, http.MethodGet, "/api/worker/getWorkers", options.query(), nil, "", &workers)
+	return workers, err
+}
+
+// StopWorkers stops the workers immediately or, gracefully, after their running jobs.
+func (c *Client) StopWorkers(ctx context.Context, rids []uuid.UUID, gracefully bool) (string, error) {
+	query := url.Values{}
+	for _, rid := range rids {
+		query.Add("rid", rid.String())
+	}
+	path := "/api/worker/stopWorkers"
+	if gracefully {
+		path = "/api/worker/stopWorkersGracefully"
+	}
+
+	message := &apiMessage{}
+	err := c.do(ctx, http.MethodPost, path, query, nil, "", message)
+	return message.Message, err
+}
+
+// =======Files=======
+
+// GetFiles lists the files, with a search only the files with the search in their name.
+func (c *Client) GetFiles(ctx context.Context, search string) ([]upload.File, error) {
+	query := url.Values{}
+	if search != "" {
+		query.Set("search", search)
+	}
+
+	files := []upload.File{}
+	err := c.do(ctx, http.MethodGet, "/api/file/getFiles", query, nil, "", &files)
+	return files, err
+}
+
+// UploadFiles uploads the local files with their base names.
+func (c *Client) UploadFiles(ctx context.Context, paths ...string) (string, error) {
+	files := map[string][]byte{}
+	for _, path := range paths {
+		// #nosec G304 -- The paths are the files the user uploads.
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("error reading file %s: %w", path, err)
+		}
+		files[filepath.Base(path)] = data
+	}
+
+	body, contentType, err := multipartBody("files", files)
+	if err != nil {
+		return "", err
+	}
+
+	message := &apiMessage{}
+	err = c.do(ctx, http.MethodPost, "/api/file/uploadFiles", nil, body, contentType, message)
+	return message.Message, err
+}
+
+// DeleteFile deletes the file.
+func (c *Client) DeleteFile(ctx context.Context, name string) (string, error) {
+	message := &apiMessage{}
+	err := c.do(ctx, http.MethodPost, "/api/file/deleteFile/"+url.PathEscape(name), nil, nil, "", message)
+	return message.Message, err
+}
+
+// =======Helpers=======
+
+// apiMessage is the response of the API routes without result and of most errors.
+type apiMessage struct {
+	Message string `json:"message"`
+	Error   string `json:"error"`
+}
+
+// query returns the query parameters of the list options.
+func (o *ListOptions) query() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+	if o.LastID > 0 {
+		query.Set("lastId", strconv.Itoa(o.LastID))
+	}
+	if o.Limit > 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	for key, value := range o.Filters {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	return query
+}
+
+// multipartBody returns a multipart form with the files by name in the field and its content type.
+func multipartBody(field string, files map[string][]byte) (io.Reader, string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for name, data := range files {
+		part, err := writer.CreateFormFile(field, name)
+		if err != nil {
+			return nil, "", fmt.Errorf("error creating form file %s: %w", name, err)
+		}
+		_, err = part.Write(data)
+		if err != nil {
+			return nil, "", fmt.Errorf("error writing form file %s: %w", name, err)
+		}
+	}
+	err := writer.Close()
+	if err != nil {
+		return nil, "", fmt.Errorf("error closing form: %w", err)
+	}
+	return body, writer.FormDataContentType(), nil
+}
+
+// do sends a request with the API key and decodes the JSON response into result.
+// Error responses are returned as error with the message of the instance.
+func (c *Client) do(ctx context.Context, method string, path string, query url.Values, body io.Reader, contentType string, result any) error {
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending request to %s: %w", c.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		apiError := &apiMessage{}
+		if json.Unmarshal(message, apiError) == nil && (apiError.Error != "" || apiError.Message != "") {
+			return fmt.Errorf("%s %s responded with status %d: %s", method, path, resp.StatusCode, apiError.Error+apiError.Message)
+		}
+		return fmt.Errorf("%s %s responded with status %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	if result == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	err = json.NewDecoder(resp.Body).Decode(result)
+	if err != nil {
+		return fmt.Errorf("error decoding response of %s %s: %w", method, path, err)
+	}
+	return nil
+}
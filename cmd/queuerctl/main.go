@@ -0,0 +1,541 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/siherrmann/queuerManager/client"
+	qmModel "github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+)
+
+const (
+	OUTPUT_TABLE = "table"
+	OUTPUT_JSON  = "json"
+)
+
+// errUsage is returned for invalid commands, the usage is printed and queuerctl exits with 2.
+var errUsage = errors.New("invalid usage")
+
+const usage = `queuerctl manages the tasks, jobs, workers and files of a queuerManager instance.
+
+Usage:
+  queuerctl [-url URL] [-api-key KEY] [-o table|json] <resource> <action> [flags] [arguments]
+
+Commands:
+  task list [-limit N] [-last-id ID]
+  task add -f definition.json                 create or update the task of a task definition
+  task import <file>                          import a task export or pipeline export
+  task export [-include schedules,webhooks] [-out file] <key>...
+  job add [-p key=value]... <task key>        values are parsed as JSON, otherwise used as string
+  job list [-status S] [-task KEY] [-search S] [-limit N] [-last-id ID]
+  job cancel <rid>...
+  job retry [-p key=value]... <rid>...        retry archived jobs, optionally with overridden parameters
+  worker list [-limit N] [-last-id ID]
+  worker stop [-graceful] <rid>...
+  file upload <path>...
+  file list [-search S]
+  file delete <name>...
+
+The URL and the API key default to QUEUERCTL_URL and QUEUERCTL_API_KEY.
+Flags of a command must be given before its arguments.
+`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := run(ctx, os.Args[1:], os.Stdout)
+	if errors.Is(err, errUsage) {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	} else if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// cli is a parsed invocation of queuerctl with the client and the output mode.
+type cli struct {
+	client *client.Client
+	output string
+	out    io.Writer
+}
+
+// run parses the global flags and runs the command of the resource and action.
+func run(ctx context.Context, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("queuerctl", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	baseURL := flags.String("url", os.Getenv("QUEUERCTL_URL"), "base URL of the instance")
+	apiKey := flags.String("api-key", os.Getenv("QUEUERCTL_API_KEY"), "API key of the instance")
+	output := flags.String("o", OUTPUT_TABLE, "output mode (table or json)")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if *output != OUTPUT_TABLE && *output != OUTPUT_JSON {
+		return fmt.Errorf("%w: output must be table or json", errUsage)
+	}
+	if flags.NArg() < 2 {
+		return errUsage
+	}
+
+	c, err := client.NewClient(*baseURL, *apiKey)
+	if err != nil {
+		return err
+	}
+	cmd := &cli{client: c, output: *output, out: out}
+
+	resource, action, args := flags.Arg(0), flags.Arg(1), flags.Args()[2:]
+	switch resource + " " + action {
+	case "task list":
+		return cmd.taskList(ctx, args)
+	case "task add":
+		return cmd.taskAdd(ctx, args)
+	case "task import":
+		return cmd.taskImport(ctx, args)
+	case "task export":
+		return cmd.taskExport(ctx, args)
+	case "job add":
+		return cmd.jobAdd(ctx, args)
+	case "job list":
+		return cmd.jobList(ctx, args)
+	case "job cancel":
+		return cmd.jobCancel(ctx, args)
+	case "job retry":
+		return cmd.jobRetry(ctx, args)
+	case "worker list":
+		return cmd.workerList(ctx, args)
+	case "worker stop":
+		return cmd.workerStop(ctx, args)
+	case "file upload":
+		return cmd.fileUpload(ctx, args)
+	case "file list":
+		return cmd.fileList(ctx, args)
+	case "file delete":
+		return cmd.fileDelete(ctx, args)
+	default:
+		return fmt.Errorf("%w: unknown command %s %s", errUsage, resource, action)
+	}
+}
+
+// =======Tasks=======
+
+func (cmd *cli) taskList(ctx context.Context, args []string) error {
+	flags, options := listFlags("task list")
+	if _, err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+
+	tasks, err := cmd.client.GetTasks(ctx, options)
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{}
+	for _, task := range tasks {
+		rows = append(rows, []string{strconv.Itoa(task.ID), task.Key, task.Name, task.Owner, formatTime(task.UpdatedAt)})
+	}
+	return cmd.print(tasks, []string{"ID", "KEY", "NAME", "OWNER", "UPDATED"}, rows)
+}
+
+func (cmd *cli) taskAdd(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("task add", flag.ContinueOnError)
+	file := flags.String("f", "", "task definition JSON file (- for stdin)")
+	if _, err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("%w: task add requires -f", errUsage)
+	}
+
+	data, err := readInput(*file)
+	if err != nil {
+		return err
+	}
+	definition := &qmModel.TaskDefinition{}
+	err = json.Unmarshal(data, definition)
+	if err != nil {
+		return fmt.Errorf("error decoding task definition: %w", err)
+	}
+	if definition.Key == "" {
+		return fmt.Errorf("task definition has no key")
+	}
+
+	task, err := cmd.client.PutTask(ctx, definition)
+	if err != nil {
+		return err
+	}
+	return cmd.print(task, []string{"ID", "RID", "KEY", "NAME"}, [][]string{{strconv.Itoa(task.ID), task.RID.String(), task.Key, task.Name}})
+}
+
+func (cmd *cli) taskImport(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("task import", flag.ContinueOnError)
+	files, err := parseFlags(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		data, err := readInput(file)
+		if err != nil {
+			return err
+		}
+		message, err := cmd.client.ImportTasks(ctx, file, data)
+		if err != nil {
+			return fmt.Errorf("error importing %s: %w", file, err)
+		}
+		if err := cmd.printMessage(message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cmd *cli) taskExport(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("task export", flag.ContinueOnError)
+	include := flags.String("include", "", "comma separated includes of a pipeline export (schedules, webhooks)")
+	outFile := flags.String("out", "", "file to write the export to instead of stdout")
+	keys, err := parseFlags(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	rids := []uuid.UUID{}
+	for _, key := range keys {
+		task, err := cmd.client.GetTaskByKey(ctx, key)
+		if err != nil {
+			return fmt.Errorf("error getting task %s: %w", key, err)
+		}
+		rids = append(rids, task.RID)
+	}
+
+	includes := []string{}
+	if *include != "" {
+		includes = strings.Split(*include, ",")
+	}
+	export, err := cmd.client.ExportTasks(ctx, rids, includes...)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding export: %w", err)
+	}
+	if *outFile != "" {
+		return os.WriteFile(*outFile, append(data, '\n'), 0600)
+	}
+	_, err = fmt.Fprintln(cmd.out, string(data))
+	return err
+}
+
+// =======Jobs=======
+
+func (cmd *cli) jobAdd(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("job add", flag.ContinueOnError)
+	parameters := parameterFlag{}
+	flags.Var(parameters, "p", "parameter as key=value, repeatable")
+	taskKeys, err := parseFlags(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	if len(taskKeys) != 1 {
+		return fmt.Errorf("%w: job add requires exactly one task key", errUsage)
+	}
+
+	job, err := cmd.client.AddJob(ctx, taskKeys[0], parameters)
+	if err != nil {
+		return err
+	}
+	return cmd.print(job, []string{"RID", "TASK", "STATUS", "CREATED"}, [][]string{{job.RID.String(), job.TaskName, job.Status, formatTime(job.CreatedAt)}})
+}
+
+func (cmd *cli) jobList(ctx context.Context, args []string) error {
+	flags, options := listFlags("job list")
+	status := flags.String("status", "", "status of the jobs")
+	taskKey := flags.String("task", "", "task key of the jobs")
+	search := flags.String("search", "", "search in the task name and RID of the jobs")
+	if _, err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+	options.Filters = map[string]string{"status": *status, "taskKey": *taskKey, "search": *search}
+
+	jobs, err := cmd.client.GetJobs(ctx, options)
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{}
+	for _, job := range jobs {
+		rows = append(rows, []string{strconv.Itoa(job.ID), job.RID.String(), job.TaskName, job.Status, strconv.Itoa(job.Attempts), formatTime(job.CreatedAt)})
+	}
+	return cmd.print(jobs, []string{"ID", "RID", "TASK", "STATUS", "ATTEMPTS", "CREATED"}, rows)
+}
+
+func (cmd *cli) jobCancel(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("job cancel", flag.ContinueOnError)
+	ridStrings, err := parseFlags(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	rids, err := parseRIDs(ridStrings)
+	if err != nil {
+		return err
+	}
+
+	jobs := []any{}
+	rows := [][]string{}
+	for _, rid := range rids {
+		job, err := cmd.client.CancelJob(ctx, rid)
+		if err != nil {
+			return fmt.Errorf("error cancelling job %s: %w", rid, err)
+		}
+		jobs = append(jobs, job)
+		rows = append(rows, []string{job.RID.String(), job.TaskName, job.Status})
+	}
+	return cmd.print(jobs, []string{"RID", "TASK", "STATUS"}, rows)
+}
+
+func (cmd *cli) jobRetry(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("job retry", flag.ContinueOnError)
+	parameters := parameterFlag{}
+	flags.Var(parameters, "p", "overridden parameter as key=value, repeatable")
+	ridStrings, err := parseFlags(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	rids, err := parseRIDs(ridStrings)
+	if err != nil {
+		return err
+	}
+
+	results, err := cmd.client.RetryJobs(ctx, rids, parameters)
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{}
+	failed := 0
+	for _, result := range results {
+		jobRID := ""
+		if result.Job != nil {
+			jobRID = result.Job.RID.String()
+		}
+		if result.Error != "" {
+			failed++
+		}
+		rows = append(rows, []string{result.RID.String(), jobRID, result.Error})
+	}
+	err = cmd.print(results, []string{"ARCHIVED RID", "NEW RID", "ERROR"}, rows)
+	if err == nil && failed > 0 {
+		err = fmt.Errorf("%d of %d jobs could not be retried", failed, len(results))
+	}
+	return err
+}
+
+// =======Workers=======
+
+func (cmd *cli) workerList(ctx context.Context, args []string) error {
+	flags, options := listFlags("worker list")
+	if _, err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+
+	workers, err := cmd.client.GetWorkers(ctx, options)
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{}
+	for _, worker := range workers {
+		rows = append(rows, []string{strconv.Itoa(worker.ID), worker.RID.String(), worker.Name, worker.Status, strconv.Itoa(worker.MaxConcurrency), strings.Join(worker.AvailableTasks, ",")})
+	}
+	return cmd.print(workers, []string{"ID", "RID", "NAME", "STATUS", "CONCURRENCY", "TASKS"}, rows)
+}
+
+func (cmd *cli) workerStop(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("worker stop", flag.ContinueOnError)
+	gracefully := flags.Bool("graceful", false, "stop the workers after their running jobs")
+	ridStrings, err := parseFlags(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	rids, err := parseRIDs(ridStrings)
+	if err != nil {
+		return err
+	}
+
+	message, err := cmd.client.StopWorkers(ctx, rids, *gracefully)
+	if err != nil {
+		return err
+	}
+	return cmd.printMessage(message)
+}
+
+// =======Files=======
+
+func (cmd *cli) fileUpload(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("file upload", flag.ContinueOnError)
+	paths, err := parseFlags(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	message, err := cmd.client.UploadFiles(ctx, paths...)
+	if err != nil {
+		return err
+	}
+	return cmd.printMessage(message)
+}
+
+func (cmd *cli) fileList(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("file list", flag.ContinueOnError)
+	search := flags.String("search", "", "search in the file names")
+	if _, err := parseFlags(flags, args, 0); err != nil {
+		return err
+	}
+
+	files, err := cmd.client.GetFiles(ctx, *search)
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{}
+	for _, file := range files {
+		rows = append(rows, []string{file.Name, strconv.FormatInt(file.Size, 10), file.MimeType})
+	}
+	return cmd.print(files, []string{"NAME", "SIZE", "MIME TYPE"}, rows)
+}
+
+func (cmd *cli) fileDelete(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("file delete", flag.ContinueOnError)
+	names, err := parseFlags(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		message, err := cmd.client.DeleteFile(ctx, name)
+		if err != nil {
+			return fmt.Errorf("error deleting file %s: %w", name, err)
+		}
+		if err := cmd.printMessage(message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// =======Helpers=======
+
+// parameterFlag collects repeated key=value flags, the values are parsed as JSON and used as string otherwise.
+type parameterFlag map[string]any
+
+func (p parameterFlag) String() string {
+	return fmt.Sprint(map[string]any(p))
+}
+
+func (p parameterFlag) Set(value string) error {
+	key, rawValue, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("parameter %q must be key=value", value)
+	}
+
+	var parsedValue any
+	if json.Unmarshal([]byte(rawValue), &parsedValue) != nil {
+		parsedValue = rawValue
+	}
+	p[key] = parsedValue
+	return nil
+}
+
+// listFlags returns the flags of a command with the pagination of a list.
+func listFlags(name string) (*flag.FlagSet, *client.ListOptions) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	options := &client.ListOptions{}
+	flags.IntVar(&options.Limit, "limit", 0, "maximum number of entries")
+	flags.IntVar(&options.LastID, "last-id", 0, "ID of the last entry of the previous page")
+	return flags, options
+}
+
+// parseFlags parses the flags of a command and returns its arguments, at least minArgs.
+func parseFlags(flags *flag.FlagSet, args []string, minArgs int) ([]string, error) {
+	flags.SetOutput(io.Discard)
+	if err := flags.Parse(args); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", errUsage, flags.Name(), err)
+	}
+	if flags.NArg() < minArgs {
+		return nil, fmt.Errorf("%w: %s requires at least %d argument(s)", errUsage, flags.Name(), minArgs)
+	}
+	return flags.Args(), nil
+}
+
+// parseRIDs parses the RIDs of the arguments.
+func parseRIDs(ridStrings []string) ([]uuid.UUID, error) {
+	rids := []uuid.UUID{}
+	for _, ridString := range ridStrings {
+		rid, err := uuid.Parse(ridString)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RID %q: %w", ridString, err)
+		}
+		rids = append(rids, rid)
+	}
+	return rids, nil
+}
+
+// readInput reads the file, - reads stdin.
+func readInput(file string) ([]byte, error) {
+	if file == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	// #nosec G304 -- The file is given by the user of the CLI.
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", file, err)
+	}
+	return data, nil
+}
+
+// print writes the value as JSON or the rows as table with the header.
+func (cmd *cli) print(value any, header []string, rows [][]string) error {
+	if cmd.output == OUTPUT_JSON {
+		encoder := json.NewEncoder(cmd.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	}
+
+	writer := tabwriter.NewWriter(cmd.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(writer, strings.Join(row, "\t"))
+	}
+	return writer.Flush()
+}
+
+// printMessage writes the message of the instance as JSON object or as line.
+func (cmd *cli) printMessage(message string) error {
+	if cmd.output == OUTPUT_JSON {
+		return json.NewEncoder(cmd.out).Encode(map[string]string{"message": message})
+	}
+	_, err := fmt.Fprintln(cmd.out, message)
+	return err
+}
+
+// formatTime formats the time for the table output.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format(time.DateTime)
+}
@@ -0,0 +1,407 @@
+package queuerManager
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/siherrmann/queuerManager/helper"
+	mw "github.com/siherrmann/queuerManager/middleware"
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+
+	qh "github.com/siherrmann/queuer/helper"
+	qmodel "github.com/siherrmann/queuer/model"
+)
+
+// Config is the configuration of the manager server. Empty fields fall back to their environment variable
+// and then to their default, so programs embedding the manager only set what they need
+// without setting process-wide environment variables.
+type Config struct {
+	// Port is the port of the HTTP server (QUEUER_MANAGER_PORT, default 3000)
+	Port string
+	// MaxConcurrency is the number of jobs the manager itself runs at once, eg. load test jobs (default 1)
+	MaxConcurrency int
+	// MasterSettings are the settings of the queuer master (default 1m lock timeout and 10s poll interval)
+	MasterSettings *qmodel.MasterSettings
+	// Database is the database of the queuer (QUEUER_DB_*)
+	Database *qh.DatabaseConfiguration
+	// EncryptionKey encrypts the job results in the database (QUEUER_ENCRYPTION_KEY, default unencrypted)
+	EncryptionKey string
+	// StorageMode is the storage of the files, local, s3 or memory (QUEUER_MANAGER_STORAGE_MODE, default local)
+	StorageMode string
+	// StoragePath is the directory of the local storage (QUEUER_MANAGER_STORAGE_PATH, default ./uploads)
+	StoragePath string
+	// Filesystem replaces the storage of the storage mode, eg. a custom storage of the embedding program
+	Filesystem upload.Filesystem
+	// TaskJSONPath is a JSON file of tasks loaded at startup (QUEUER_MANAGER_TASK_JSON)
+	TaskJSONPath string
+	// CSRFTrustedOrigins are the origins allowed to send cross origin requests to the views
+	// (QUEUER_MANAGER_CSRF_TRUSTED_ORIGINS comma separated, default http://localhost:3000 and http://127.0.0.1:3000)
+	CSRFTrustedOrigins []string
+	// LogLevel is the level of the logs of the manager, debug, info, warn or error (QUEUER_MANAGER_LOG_LEVEL, default info)
+	LogLevel string
+	// StaticDir is the directory of the static files of the views (QUEUER_STATIC_DIR, default ./view/static)
+	StaticDir string
+	// BasePath is the path prefix the manager is served under, eg. /queuer behind a reverse proxy
+	// (QUEUER_MANAGER_BASE_PATH, default served at /)
+	BasePath string
+	// TLSCertFile and TLSKeyFile serve the manager with TLS and HTTP/2 with the certificate and its key
+	// (QUEUER_MANAGER_TLS_CERT_FILE and QUEUER_MANAGER_TLS_KEY_FILE, default without TLS)
+	TLSCertFile string
+	TLSKeyFile  string
+	// AutocertDomains serve the manager with TLS and HTTP/2 with certificates of Let's Encrypt for the domains
+	// (QUEUER_MANAGER_AUTOCERT_DOMAINS comma separated, default without autocert)
+	AutocertDomains []string
+	// AutocertCacheDir is the directory the certificates of Let's Encrypt are cached in (QUEUER_MANAGER_AUTOCERT_CACHE_DIR, default ./certs)
+	AutocertCacheDir string
+	// AutocertEmail is the contact email of the Let's Encrypt account (QUEUER_MANAGER_AUTOCERT_EMAIL, optional)
+	AutocertEmail string
+	// HTTPRedirectPort is the port redirecting HTTP to HTTPS with TLS, it also answers the HTTP challenges of Let's Encrypt
+	// (QUEUER_MANAGER_HTTP_REDIRECT_PORT, default without redirect)
+	HTTPRedirectPort string
+	// ShutdownTimeout is the time to drain the running requests on shutdown (QUEUER_MANAGER_SHUTDOWN_TIMEOUT, default 20s)
+	ShutdownTimeout time.Duration
+	// BrandTitle replaces the title in the views and is the sender name of the emails, eg. for customer-facing deployments
+	// (QUEUER_MANAGER_BRAND_TITLE, default without branding)
+	BrandTitle string
+	// BrandLogoURL is the logo in the sidebar, the login and the status pages (QUEUER_MANAGER_BRAND_LOGO_URL, default without logo)
+	BrandLogoURL string
+	// AccessLogRetentionDays is the number of days the access logs of the requests are kept
+	// (QUEUER_MANAGER_ACCESS_LOG_RETENTION_DAYS, default 90)
+	AccessLogRetentionDays int
+	// FileTrashRetentionDays is the number of days the orphaned files of deleted and expired jobs stay in the trash
+	// before they are deleted (QUEUER_MANAGER_FILE_TRASH_RETENTION_DAYS, default 7)
+	FileTrashRetentionDays int
+	// HousekeepingJobs runs the housekeeping of the manager as jobs of its own tasks (QUEUER_MANAGER_HOUSEKEEPING_JOBS, default false)
+	HousekeepingJobs bool
+	// WorkerStaleAfter is the time without heartbeat after which a worker is alerted as stale
+	// (QUEUER_MANAGER_ALERT_WORKER_STALE_AFTER, default 2m)
+	WorkerStaleAfter time.Duration
+	// HealthInterval is the interval the health of the subsystems is persisted for the uptime history
+	// (QUEUER_MANAGER_HEALTH_INTERVAL, default 1m)
+	HealthInterval time.Duration
+	// ScheduleInterval is the interval the due schedules are checked (QUEUER_MANAGER_SCHEDULE_INTERVAL, default 15s)
+	ScheduleInterval time.Duration
+	// ForwardSyncInterval is the interval the results of forwarded jobs are synced back (QUEUER_MANAGER_FORWARD_SYNC_INTERVAL, default 10s)
+	ForwardSyncInterval time.Duration
+	// LDAPSyncInterval is the interval the roles of the users are synced with their LDAP groups (QUEUER_MANAGER_LDAP_SYNC_INTERVAL, default 15m)
+	LDAPSyncInterval time.Duration
+}
+
+// Option changes a field of the configuration of the manager server.
+type Option func(*Config)
+
+// WithPort sets the port of the HTTP server.
+func WithPort(port string) Option {
+	return func(c *Config) { c.Port = port }
+}
+
+// WithMaxConcurrency sets the number of jobs the manager itself runs at once.
+func WithMaxConcurrency(maxConcurrency int) Option {
+	return func(c *Config) { c.MaxConcurrency = maxConcurrency }
+}
+
+// WithMasterSettings sets the settings of the queuer master.
+func WithMasterSettings(masterSettings *qmodel.MasterSettings) Option {
+	return func(c *Config) { c.MasterSettings = masterSettings }
+}
+
+// WithDatabase sets the database of the queuer.
+func WithDatabase(database *qh.DatabaseConfiguration) Option {
+	return func(c *Config) { c.Database = database }
+}
+
+// WithEncryptionKey sets the key the job results are encrypted with in the database.
+func WithEncryptionKey(encryptionKey string) Option {
+	return func(c *Config) { c.EncryptionKey = encryptionKey }
+}
+
+// WithStorage sets the storage mode and the directory of the local storage.
+func WithStorage(storageMode string, storagePath string) Option {
+	return func(c *Config) {
+		c.StorageMode = storageMode
+		c.StoragePath = storagePath
+	}
+}
+
+// WithFilesystem sets the storage of the files, it replaces the storage of the storage mode.
+func WithFilesystem(filesystem upload.Filesystem) Option {
+	return func(c *Config) { c.Filesystem = filesystem }
+}
+
+// WithTaskJSONPath sets the JSON file of the tasks loaded at startup.
+func WithTaskJSONPath(taskJSONPath string) Option {
+	return func(c *Config) { c.TaskJSONPath = taskJSONPath }
+}
+
+// WithCSRFTrustedOrigins sets the origins allowed to send cross origin requests to the views.
+func WithCSRFTrustedOrigins(origins ...string) Option {
+	return func(c *Config) { c.CSRFTrustedOrigins = origins }
+}
+
+// WithLogLevel sets the level of the logs of the manager, debug, info, warn or error.
+func WithLogLevel(logLevel string) Option {
+	return func(c *Config) { c.LogLevel = logLevel }
+}
+
+// WithStaticDir sets the directory of the static files of the views.
+func WithStaticDir(staticDir string) Option {
+	return func(c *Config) { c.StaticDir = staticDir }
+}
+
+// WithBasePath sets the path prefix the manager is served under, eg. /queuer behind a reverse proxy.
+func WithBasePath(basePath string) Option {
+	return func(c *Config) { c.BasePath = basePath }
+}
+
+// WithTLS serves the manager with TLS and HTTP/2 with the certificate and key files.
+func WithTLS(certFile string, keyFile string) Option {
+	return func(c *Config) {
+		c.TLSCertFile = certFile
+		c.TLSKeyFile = keyFile
+	}
+}
+
+// WithAutocert serves the manager with TLS and HTTP/2 with certificates of Let's Encrypt for the domains,
+// cached in the directory.
+func WithAutocert(cacheDir string, domains ...string) Option {
+	return func(c *Config) {
+		c.AutocertCacheDir = cacheDir
+		c.AutocertDomains = domains
+	}
+}
+
+// WithHTTPRedirectPort sets the port redirecting HTTP to HTTPS with TLS.
+func WithHTTPRedirectPort(port string) Option {
+	return func(c *Config) { c.HTTPRedirectPort = port }
+}
+
+// WithShutdownTimeout sets the time to drain the running requests on shutdown.
+func WithShutdownTimeout(shutdownTimeout time.Duration) Option {
+	return func(c *Config) { c.ShutdownTimeout = shutdownTimeout }
+}
+
+// WithBranding sets the title and the logo of the views for customer-facing deployments, the title is also the sender name of the emails.
+func WithBranding(title string, logoURL string) Option {
+	return func(c *Config) {
+		c.BrandTitle = title
+		c.BrandLogoURL = logoURL
+	}
+}
+
+// WithAccessLogRetentionDays sets the number of days the access logs of the requests are kept.
+func WithAccessLogRetentionDays(days int) Option {
+	return func(c *Config) { c.AccessLogRetentionDays = days }
+}
+
+// WithFileTrashRetentionDays sets the number of days the orphaned files stay in the trash before they are deleted.
+func WithFileTrashRetentionDays(days int) Option {
+	return func(c *Config) { c.FileTrashRetentionDays = days }
+}
+
+// WithHousekeepingJobs runs the housekeeping of the manager as jobs of its own tasks.
+func WithHousekeepingJobs() Option {
+	return func(c *Config) { c.HousekeepingJobs = true }
+}
+
+// WithWorkerStaleAfter sets the time without heartbeat after which a worker is alerted as stale.
+func WithWorkerStaleAfter(workerStaleAfter time.Duration) Option {
+	return func(c *Config) { c.WorkerStaleAfter = workerStaleAfter }
+}
+
+// WithHealthInterval sets the interval the health of the subsystems is persisted.
+func WithHealthInterval(healthInterval time.Duration) Option {
+	return func(c *Config) { c.HealthInterval = healthInterval }
+}
+
+// WithScheduleInterval sets the interval the due schedules are checked.
+func WithScheduleInterval(scheduleInterval time.Duration) Option {
+	return func(c *Config) { c.ScheduleInterval = scheduleInterval }
+}
+
+// WithForwardSyncInterval sets the interval the results of forwarded jobs are synced back.
+func WithForwardSyncInterval(forwardSyncInterval time.Duration) Option {
+	return func(c *Config) { c.ForwardSyncInterval = forwardSyncInterval }
+}
+
+// WithLDAPSyncInterval sets the interval the roles of the users are synced with their LDAP groups.
+func WithLDAPSyncInterval(ldapSyncInterval time.Duration) Option {
+	return func(c *Config) { c.LDAPSyncInterval = ldapSyncInterval }
+}
+
+// NewConfig creates a configuration with the options, the fields not set by an option fall back
+// to their environment variable when the server starts.
+func NewConfig(options ...Option) *Config {
+	config := &Config{}
+	for _, option := range options {
+		option(config)
+	}
+	return config
+}
+
+// defaultMasterSettings returns the master settings of the manager without configured settings.
+func defaultMasterSettings() *qmodel.MasterSettings {
+	return &qmodel.MasterSettings{
+		MasterLockTimeout:     time.Minute * 1,
+		MasterPollInterval:    time.Second * 10,
+		WorkerStaleThreshold:  time.Minute * 5,
+		WorkerDeleteThreshold: time.Minute * 100,
+		JobStaleThreshold:     time.Minute * 10,
+		JobDeleteThreshold:    time.Minute * 100,
+	}
+}
+
+// withDefaults returns a copy of the configuration with the empty fields set from the environment variables
+// or their defaults. The database stays nil without configuration, so the queuer and the job streams read QUEUER_DB_* themselves.
+func (c *Config) withDefaults() (*Config, error) {
+	config := *c
+	if config.Port == "" {
+		config.Port = helper.GetEnvOrDefault("QUEUER_MANAGER_PORT", "3000")
+	}
+	if config.MaxConcurrency <= 0 {
+		config.MaxConcurrency = 1
+	}
+	if config.MasterSettings == nil {
+		config.MasterSettings = defaultMasterSettings()
+	}
+	if config.EncryptionKey == "" {
+		config.EncryptionKey = os.Getenv("QUEUER_ENCRYPTION_KEY")
+	}
+	if config.StorageMode == "" {
+		config.StorageMode = helper.GetEnvOrDefault("QUEUER_MANAGER_STORAGE_MODE", upload.STORAGE_MODE_LOCAL)
+	}
+	if config.StoragePath == "" {
+		config.StoragePath = helper.GetEnvOrDefault("QUEUER_MANAGER_STORAGE_PATH", "./uploads")
+	}
+	if config.TaskJSONPath == "" {
+		config.TaskJSONPath = helper.GetEnvOrDefault("QUEUER_MANAGER_TASK_JSON", "")
+	}
+	if len(config.CSRFTrustedOrigins) == 0 {
+		origins := helper.GetEnvOrDefault("QUEUER_MANAGER_CSRF_TRUSTED_ORIGINS", "http://localhost:3000,http://127.0.0.1:3000")
+		for _, origin := range strings.Split(origins, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				config.CSRFTrustedOrigins = append(config.CSRFTrustedOrigins, origin)
+			}
+		}
+	}
+	if config.LogLevel == "" {
+		config.LogLevel = helper.GetEnvOrDefault("QUEUER_MANAGER_LOG_LEVEL", "info")
+	}
+	if _, err := parseLogLevel(config.LogLevel); err != nil {
+		return nil, err
+	}
+	if config.StaticDir == "" {
+		config.StaticDir = helper.GetEnvOrDefault("QUEUER_STATIC_DIR", "./view/static")
+	}
+	if config.BasePath == "" {
+		config.BasePath = helper.GetEnvOrDefault("QUEUER_MANAGER_BASE_PATH", "")
+	}
+	basePath, err := mw.NormalizeBasePath(config.BasePath)
+	if err != nil {
+		return nil, err
+	}
+	config.BasePath = basePath
+	if config.TLSCertFile == "" && config.TLSKeyFile == "" {
+		config.TLSCertFile = helper.GetEnvOrDefault("QUEUER_MANAGER_TLS_CERT_FILE", "")
+		config.TLSKeyFile = helper.GetEnvOrDefault("QUEUER_MANAGER_TLS_KEY_FILE", "")
+	}
+	if len(config.AutocertDomains) == 0 {
+		for _, domain := range strings.Split(helper.GetEnvOrDefault("QUEUER_MANAGER_AUTOCERT_DOMAINS", ""), ",") {
+			if domain = strings.TrimSpace(domain); domain != "" {
+				config.AutocertDomains = append(config.AutocertDomains, domain)
+			}
+		}
+	}
+	if config.AutocertCacheDir == "" {
+		config.AutocertCacheDir = helper.GetEnvOrDefault("QUEUER_MANAGER_AUTOCERT_CACHE_DIR", "./certs")
+	}
+	if config.AutocertEmail == "" {
+		config.AutocertEmail = helper.GetEnvOrDefault("QUEUER_MANAGER_AUTOCERT_EMAIL", "")
+	}
+	if config.HTTPRedirectPort == "" {
+		config.HTTPRedirectPort = helper.GetEnvOrDefault("QUEUER_MANAGER_HTTP_REDIRECT_PORT", "")
+	}
+	if err := config.validateTLS(); err != nil {
+		return nil, err
+	}
+	if config.ShutdownTimeout == 0 {
+		shutdownTimeout, err := time.ParseDuration(helper.GetEnvOrDefault("QUEUER_MANAGER_SHUTDOWN_TIMEOUT", "20s"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid QUEUER_MANAGER_SHUTDOWN_TIMEOUT: %w", err)
+		}
+		config.ShutdownTimeout = shutdownTimeout
+	}
+	if config.ShutdownTimeout <= 0 {
+		return nil, fmt.Errorf("invalid shutdown timeout %s (must be positive)", config.ShutdownTimeout)
+	}
+	if config.BrandTitle == "" {
+		config.BrandTitle = helper.GetEnvOrDefault("QUEUER_MANAGER_BRAND_TITLE", "")
+	}
+	if config.BrandLogoURL == "" {
+		config.BrandLogoURL = helper.GetEnvOrDefault("QUEUER_MANAGER_BRAND_LOGO_URL", "")
+	}
+	if config.AccessLogRetentionDays == 0 {
+		retentionDays, err := strconv.Atoi(helper.GetEnvOrDefault("QUEUER_MANAGER_ACCESS_LOG_RETENTION_DAYS", strconv.Itoa(model.DEFAULT_ACCESS_LOG_RETENTION_DAYS)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid QUEUER_MANAGER_ACCESS_LOG_RETENTION_DAYS: %w", err)
+		}
+		config.AccessLogRetentionDays = retentionDays
+	}
+	if config.AccessLogRetentionDays <= 0 {
+		return nil, fmt.Errorf("invalid access log retention %d days (must be positive)", config.AccessLogRetentionDays)
+	}
+	if config.FileTrashRetentionDays == 0 {
+		retentionDays, err := strconv.Atoi(helper.GetEnvOrDefault("QUEUER_MANAGER_FILE_TRASH_RETENTION_DAYS", strconv.Itoa(model.DEFAULT_FILE_TRASH_RETENTION_DAYS)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid QUEUER_MANAGER_FILE_TRASH_RETENTION_DAYS: %w", err)
+		}
+		config.FileTrashRetentionDays = retentionDays
+	}
+	if config.FileTrashRetentionDays <= 0 {
+		return nil, fmt.Errorf("invalid file trash retention %d days (must be positive)", config.FileTrashRetentionDays)
+	}
+	if !config.HousekeepingJobs {
+		config.HousekeepingJobs = helper.GetEnvOrDefault("QUEUER_MANAGER_HOUSEKEEPING_JOBS", "false") == "true"
+	}
+	intervals := []struct {
+		value        *time.Duration
+		env          string
+		defaultValue string
+	}{
+		{&config.WorkerStaleAfter, "QUEUER_MANAGER_ALERT_WORKER_STALE_AFTER", "2m"},
+		{&config.HealthInterval, "QUEUER_MANAGER_HEALTH_INTERVAL", "1m"},
+		{&config.ScheduleInterval, "QUEUER_MANAGER_SCHEDULE_INTERVAL", "15s"},
+		{&config.ForwardSyncInterval, "QUEUER_MANAGER_FORWARD_SYNC_INTERVAL", "10s"},
+		{&config.LDAPSyncInterval, "QUEUER_MANAGER_LDAP_SYNC_INTERVAL", "15m"},
+	}
+	for _, interval := range intervals {
+		if *interval.value == 0 {
+			value, err := time.ParseDuration(helper.GetEnvOrDefault(interval.env, interval.defaultValue))
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", interval.env, err)
+			}
+			*interval.value = value
+		}
+		if *interval.value <= 0 {
+			return nil, fmt.Errorf("invalid %s %s (must be positive)", interval.env, *interval.value)
+		}
+	}
+	if _, err := strconv.Atoi(config.Port); err != nil {
+		return nil, fmt.Errorf("invalid port %q", config.Port)
+	}
+	return &config, nil
+}
+
+// parseLogLevel parses a log level, debug, info, warn or error.
+func parseLogLevel(logLevel string) (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(logLevel))
+	if err != nil {
+		return level, fmt.Errorf("invalid log level %q (must be debug, info, warn or error)", logLevel)
+	}
+	return level, nil
+}
@@ -0,0 +1,383 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+)
+
+// APIKeyDBHandlerFunctions defines the interface for APIKey database operations.
+type APIKeyDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	InsertAPIKey(apiKey *model.APIKey, keyHash string) (*model.APIKey, error)
+	SelectAPIKey(rid uuid.UUID) (*model.APIKey, error)
+	SelectAPIKeyByHash(keyHash string) (*model.APIKey, error)
+	SelectAllAPIKeys(lastID int, entries int) ([]*model.APIKey, error)
+	UpdateAPIKeyHash(rid uuid.UUID, prefix string, keyHash string) (*model.APIKey, error)
+	UpdateAPIKeyLastUsed(rid uuid.UUID) error
+	RevokeAPIKey(rid uuid.UUID) (*model.APIKey, error)
+}
+
+// APIKeyDBHandler implements APIKeyDBHandlerFunctions and holds the database connection.
+type APIKeyDBHandler struct {
+	db *helper.Database
+}
+
+// NewAPIKeyDBHandler creates a new instance of APIKeyDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing api_key table before creating a new one
+func NewAPIKeyDBHandler(dbConnection *helper.Database, withTableDrop bool) (*APIKeyDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	apiKeyDbHandler := &APIKeyDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := apiKeyDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := apiKeyDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return apiKeyDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'api_key' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r APIKeyDBHandler) CheckTableExistance() (bool, error) {
+	apiKeyExists, err := r.db.CheckTableExistance("api_key")
+	if err != nil {
+		return false, helper.NewError("api_key table", err)
+	}
+	return apiKeyExists, nil
+}
+
+// CreateTable creates the 'api_key' table in the database.
+// If the table already exists, it does not create it again.
+func (r APIKeyDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS api_key (
+			id SERIAL PRIMARY KEY,
+			rid UUID UNIQUE NOT NULL DEFAULT gen_random_uuid(),
+			name VARCHAR(100) NOT NULL,
+			prefix VARCHAR(20) NOT NULL,
+			key_hash VARCHAR(64) UNIQUE NOT NULL,
+			scope VARCHAR(20) NOT NULL,
+			created_by VARCHAR(255) NOT NULL DEFAULT '',
+			last_used_at TIMESTAMP WITH TIME ZONE,
+			revoked_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create api_key table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table api_key")
+
+	return nil
+}
+
+// DropTable drops the 'api_key' table from the database.
+func (r APIKeyDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS api_key`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop api_key table", err)
+	}
+
+	r.db.Logger.Info("Dropped table api_key")
+
+	return nil
+}
+
+// InsertAPIKey inserts an API key with the hash of the key, the key itself is not stored.
+func (r APIKeyDBHandler) InsertAPIKey(apiKey *model.APIKey, keyHash string) (*model.APIKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		INSERT INTO api_key (
+			name,
+			prefix,
+			key_hash,
+			scope,
+			created_by
+		) VALUES ($1, $2, $3, $4, $5)
+		RETURNING
+			id,
+			rid,
+			name,
+			prefix,
+			scope,
+			created_by,
+			last_used_at,
+			revoked_at,
+			created_at,
+			updated_at`
+
+	row := r.db.Instance.QueryRowContext(
+		ctx,
+		query,
+		apiKey.Name,
+		apiKey.Prefix,
+		keyHash,
+		apiKey.Scope,
+		apiKey.CreatedBy,
+	)
+	insertedAPIKey, err := scanAPIKey(row)
+	if err != nil {
+		return nil, helper.NewError("insert api key", err)
+	}
+
+	return insertedAPIKey, nil
+}
+
+// SelectAPIKey retrieves an API key by its RID, including revoked keys.
+func (r APIKeyDBHandler) SelectAPIKey(rid uuid.UUID) (*model.APIKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			rid,
+			name,
+			prefix,
+			scope,
+			created_by,
+			last_used_at,
+			revoked_at,
+			created_at,
+			updated_at
+		FROM api_key
+		WHERE rid = $1
+	`
+
+	apiKey, err := scanAPIKey(r.db.Instance.QueryRowContext(ctx, query, rid))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("api key not found", fmt.Errorf("no api key with rid %s", rid))
+		}
+		return nil, helper.NewError("select api key", err)
+	}
+
+	return apiKey, nil
+}
+
+// SelectAPIKeyByHash retrieves the not revoked API key with the hash of the key, eg. to authenticate a request.
+func (r APIKeyDBHandler) SelectAPIKeyByHash(keyHash string) (*model.APIKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			rid,
+			name,
+			prefix,
+			scope,
+			created_by,
+			last_used_at,
+			revoked_at,
+			created_at,
+			updated_at
+		FROM api_key
+		WHERE key_hash = $1
+			AND revoked_at IS NULL
+	`
+
+	apiKey, err := scanAPIKey(r.db.Instance.QueryRowContext(ctx, query, keyHash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("api key not found", fmt.Errorf("no valid api key"))
+		}
+		return nil, helper.NewError("select api key", err)
+	}
+
+	return apiKey, nil
+}
+
+// SelectAllAPIKeys retrieves the API keys after lastID ordered by ID, including revoked keys.
+func (r APIKeyDBHandler) SelectAllAPIKeys(lastID int, entries int) ([]*model.APIKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			rid,
+			name,
+			prefix,
+			scope,
+			created_by,
+			last_used_at,
+			revoked_at,
+			created_at,
+			updated_at
+		FROM api_key
+		WHERE id > $1
+		ORDER BY id ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, lastID, entries)
+	if err != nil {
+		return nil, helper.NewError("select all api keys", err)
+	}
+	defer rows.Close()
+
+	apiKeys := []*model.APIKey{}
+	for rows.Next() {
+		apiKey, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, helper.NewError("scan api key", err)
+		}
+		apiKeys = append(apiKeys, apiKey)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return apiKeys, nil
+}
+
+// UpdateAPIKeyHash replaces the key of a not revoked API key, eg. to rotate it, the old key is invalid afterwards.
+func (r APIKeyDBHandler) UpdateAPIKeyHash(rid uuid.UUID, prefix string, keyHash string) (*model.APIKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE api_key
+		SET
+			prefix = $2,
+			key_hash = $3,
+			last_used_at = NULL,
+			updated_at = NOW()
+		WHERE rid = $1
+			AND revoked_at IS NULL
+		RETURNING
+			id,
+			rid,
+			name,
+			prefix,
+			scope,
+			created_by,
+			last_used_at,
+			revoked_at,
+			created_at,
+			updated_at`
+
+	apiKey, err := scanAPIKey(r.db.Instance.QueryRowContext(ctx, query, rid, prefix, keyHash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("api key not found", fmt.Errorf("no valid api key with rid %s", rid))
+		}
+		return nil, helper.NewError("update api key hash", err)
+	}
+
+	return apiKey, nil
+}
+
+// UpdateAPIKeyLastUsed sets the last use of an API key to now.
+func (r APIKeyDBHandler) UpdateAPIKeyLastUsed(rid uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `UPDATE api_key SET last_used_at = NOW() WHERE rid = $1`
+	_, err := r.db.Instance.ExecContext(ctx, query, rid)
+	if err != nil {
+		return helper.NewError("update api key last used", err)
+	}
+
+	return nil
+}
+
+// RevokeAPIKey revokes an API key, revoking an already revoked key keeps the first revocation time.
+func (r APIKeyDBHandler) RevokeAPIKey(rid uuid.UUID) (*model.APIKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE api_key
+		SET
+			revoked_at = COALESCE(revoked_at, NOW()),
+			updated_at = NOW()
+		WHERE rid = $1
+		RETURNING
+			id,
+			rid,
+			name,
+			prefix,
+			scope,
+			created_by,
+			last_used_at,
+			revoked_at,
+			created_at,
+			updated_at`
+
+	apiKey, err := scanAPIKey(r.db.Instance.QueryRowContext(ctx, query, rid))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("api key not found", fmt.Errorf("no api key with rid %s", rid))
+		}
+		return nil, helper.NewError("revoke api key", err)
+	}
+
+	return apiKey, nil
+}
+
+// scanAPIKey scans an API key row in the column order of the API key queries.
+func scanAPIKey(row interface{ Scan(dest ...any) error }) (*model.APIKey, error) {
+	apiKey := &model.APIKey{}
+	var lastUsedAt sql.NullTime
+	var revokedAt sql.NullTime
+	err := row.Scan(
+		&apiKey.ID,
+		&apiKey.RID,
+		&apiKey.Name,
+		&apiKey.Prefix,
+		&apiKey.Scope,
+		&apiKey.CreatedBy,
+		&lastUsedAt,
+		&revokedAt,
+		&apiKey.CreatedAt,
+		&apiKey.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if lastUsedAt.Valid {
+		apiKey.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		apiKey.RevokedAt = &revokedAt.Time
+	}
+	return apiKey, nil
+}
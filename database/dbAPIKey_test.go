@@ -0,0 +1,106 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyNewAPIKeyDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewAPIKeyDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		apiKeyDbHandler, err := NewAPIKeyDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewAPIKeyDBHandler to not return an error")
+		require.NotNil(t, apiKeyDbHandler, "Expected NewAPIKeyDBHandler to return a non-nil instance")
+
+		exists, err := apiKeyDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = apiKeyDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewAPIKeyDBHandler with nil database", func(t *testing.T) {
+		_, err := NewAPIKeyDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating APIKeyDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestAPIKeyInsertRotateAndRevokeAPIKey(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	apiKeyDbHandler, err := NewAPIKeyDBHandler(database, true)
+	require.NoError(t, err, "Expected NewAPIKeyDBHandler to not return an error")
+
+	insertedAPIKey, err := apiKeyDbHandler.InsertAPIKey(&model.APIKey{Name: "deploy", Prefix: "qm_abcdefgh", Scope: model.API_KEY_SCOPE_WRITE, CreatedBy: "admin"}, "hash-1")
+	require.NoError(t, err, "Expected InsertAPIKey to not return an error")
+	assert.NotEqual(t, uuid.Nil, insertedAPIKey.RID)
+	assert.Equal(t, model.API_KEY_SCOPE_WRITE, insertedAPIKey.Scope)
+	assert.Nil(t, insertedAPIKey.LastUsedAt)
+	assert.Nil(t, insertedAPIKey.RevokedAt)
+
+	t.Run("Select API key by hash and update last use", func(t *testing.T) {
+		apiKey, err := apiKeyDbHandler.SelectAPIKeyByHash("hash-1")
+		require.NoError(t, err, "Expected SelectAPIKeyByHash to not return an error")
+		assert.Equal(t, insertedAPIKey.RID, apiKey.RID)
+
+		err = apiKeyDbHandler.UpdateAPIKeyLastUsed(apiKey.RID)
+		require.NoError(t, err, "Expected UpdateAPIKeyLastUsed to not return an error")
+
+		apiKey, err = apiKeyDbHandler.SelectAPIKey(apiKey.RID)
+		require.NoError(t, err, "Expected SelectAPIKey to not return an error")
+		assert.NotNil(t, apiKey.LastUsedAt)
+	})
+
+	t.Run("Rotate API key", func(t *testing.T) {
+		rotatedAPIKey, err := apiKeyDbHandler.UpdateAPIKeyHash(insertedAPIKey.RID, "qm_ijklmnop", "hash-2")
+		require.NoError(t, err, "Expected UpdateAPIKeyHash to not return an error")
+		assert.Equal(t, "qm_ijklmnop", rotatedAPIKey.Prefix)
+		assert.Nil(t, rotatedAPIKey.LastUsedAt)
+
+		_, err = apiKeyDbHandler.SelectAPIKeyByHash("hash-1")
+		assert.Error(t, err, "Expected the old key to be invalid after the rotation")
+		_, err = apiKeyDbHandler.SelectAPIKeyByHash("hash-2")
+		assert.NoError(t, err, "Expected the new key to be valid after the rotation")
+	})
+
+	t.Run("Revoke API key", func(t *testing.T) {
+		revokedAPIKey, err := apiKeyDbHandler.RevokeAPIKey(insertedAPIKey.RID)
+		require.NoError(t, err, "Expected RevokeAPIKey to not return an error")
+		require.NotNil(t, revokedAPIKey.RevokedAt)
+
+		_, err = apiKeyDbHandler.SelectAPIKeyByHash("hash-2")
+		assert.Error(t, err, "Expected a revoked key to be invalid")
+		_, err = apiKeyDbHandler.UpdateAPIKeyHash(insertedAPIKey.RID, "qm_qrstuvwx", "hash-3")
+		assert.Error(t, err, "Expected a revoked key to not be rotated")
+
+		apiKeys, err := apiKeyDbHandler.SelectAllAPIKeys(0, 10)
+		require.NoError(t, err, "Expected SelectAllAPIKeys to not return an error")
+		require.Len(t, apiKeys, 1, "Expected revoked keys to be listed")
+		assert.NotNil(t, apiKeys[0].RevokedAt)
+	})
+
+	t.Run("Revoke non-existent API key", func(t *testing.T) {
+		_, err := apiKeyDbHandler.RevokeAPIKey(uuid.New())
+		assert.Error(t, err, "Expected RevokeAPIKey to return an error for a non-existent key")
+	})
+}
@@ -0,0 +1,262 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+)
+
+// AccessLogDBHandlerFunctions defines the interface for AccessLog database operations.
+type AccessLogDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	InsertAccessLogs(accessLogs []*model.AccessLog) error
+	SelectAccessLogs(filter *model.AccessLogFilter, lastID int, entries int) ([]*model.AccessLog, error)
+	DeleteAccessLogsBefore(before time.Time) (int64, error)
+}
+
+// AccessLogDBHandler implements AccessLogDBHandlerFunctions and holds the database connection.
+type AccessLogDBHandler struct {
+	db *helper.Database
+}
+
+// NewAccessLogDBHandler creates a new instance of AccessLogDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing access_log table before creating a new one
+func NewAccessLogDBHandler(dbConnection *helper.Database, withTableDrop bool) (*AccessLogDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	accessLogDbHandler := &AccessLogDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := accessLogDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := accessLogDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return accessLogDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'access_log' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r AccessLogDBHandler) CheckTableExistance() (bool, error) {
+	accessLogExists, err := r.db.CheckTableExistance("access_log")
+	if err != nil {
+		return false, helper.NewError("access_log table", err)
+	}
+	return accessLogExists, nil
+}
+
+// CreateTable creates the 'access_log' table in the database.
+// If the table already exists, it does not create it again.
+func (r AccessLogDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS access_log (
+			id SERIAL PRIMARY KEY,
+			method VARCHAR(10) NOT NULL,
+			route VARCHAR(255) NOT NULL DEFAULT '',
+			path TEXT NOT NULL,
+			query TEXT NOT NULL DEFAULT '',
+			username VARCHAR(100) NOT NULL DEFAULT '',
+			ip VARCHAR(100) NOT NULL DEFAULT '',
+			status INT NOT NULL,
+			latency_ms BIGINT NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_access_log_created_at ON access_log (created_at);
+		CREATE INDEX IF NOT EXISTS idx_access_log_username ON access_log (username, id);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create access_log table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table access_log")
+
+	return nil
+}
+
+// DropTable drops the 'access_log' table from the database.
+func (r AccessLogDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS access_log`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop access_log table", err)
+	}
+
+	r.db.Logger.Info("Dropped table access_log")
+
+	return nil
+}
+
+// InsertAccessLogs inserts the access logs in one transaction, a zero creation time is set to now.
+func (r AccessLogDBHandler) InsertAccessLogs(accessLogs []*model.AccessLog) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := r.db.Instance.BeginTx(ctx, nil)
+	if err != nil {
+		return helper.NewError("begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO access_log (
+			method,
+			route,
+			path,
+			query,
+			username,
+			ip,
+			status,
+			latency_ms,
+			created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE($9, NOW()))`
+
+	for _, accessLog := range accessLogs {
+		var createdAt *time.Time
+		if !accessLog.CreatedAt.IsZero() {
+			createdAt = &accessLog.CreatedAt
+		}
+		_, err = tx.ExecContext(
+			ctx,
+			query,
+			accessLog.Method,
+			accessLog.Route,
+			accessLog.Path,
+			accessLog.Query,
+			accessLog.User,
+			accessLog.IP,
+			accessLog.Status,
+			accessLog.LatencyMs,
+			createdAt,
+		)
+		if err != nil {
+			return helper.NewError("insert access log", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return helper.NewError("commit transaction", err)
+	}
+
+	return nil
+}
+
+// SelectAccessLogs retrieves the access logs matching the filter from the database with pagination, newest first.
+// lastID is the ID of the last entry from the previous page (0 for first page)
+// entries is the maximum number of entries to return
+func (r AccessLogDBHandler) SelectAccessLogs(filter *model.AccessLogFilter, lastID int, entries int) ([]*model.AccessLog, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if filter == nil {
+		filter = &model.AccessLogFilter{}
+	}
+	var from, to *time.Time
+	if !filter.From.IsZero() {
+		from = &filter.From
+	}
+	if !filter.To.IsZero() {
+		to = &filter.To
+	}
+
+	query := `
+		SELECT
+			id,
+			method,
+			route,
+			path,
+			query,
+			username,
+			ip,
+			status,
+			latency_ms,
+			created_at
+		FROM access_log
+		WHERE ($1 = 0 OR id < $1)
+			AND ($3 = '' OR username = $3)
+			AND ($4 = '' OR route LIKE $4 || '%')
+			AND ($5 = '' OR method = $5)
+			AND ($6 = 0 OR status = $6)
+			AND ($7::TIMESTAMPTZ IS NULL OR created_at >= $7)
+			AND ($8::TIMESTAMPTZ IS NULL OR created_at < $8)
+		ORDER BY id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, lastID, entries, filter.User, filter.Route, filter.Method, filter.Status, from, to)
+	if err != nil {
+		return nil, helper.NewError("select access logs", err)
+	}
+	defer rows.Close()
+
+	accessLogs := []*model.AccessLog{}
+	for rows.Next() {
+		accessLog := &model.AccessLog{}
+		err := rows.Scan(
+			&accessLog.ID,
+			&accessLog.Method,
+			&accessLog.Route,
+			&accessLog.Path,
+			&accessLog.Query,
+			&accessLog.User,
+			&accessLog.IP,
+			&accessLog.Status,
+			&accessLog.LatencyMs,
+			&accessLog.CreatedAt,
+		)
+		if err != nil {
+			return nil, helper.NewError("scan access log", err)
+		}
+		accessLogs = append(accessLogs, accessLog)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return accessLogs, nil
+}
+
+// DeleteAccessLogsBefore deletes the access logs older than the time and returns the number of deleted access logs.
+func (r AccessLogDBHandler) DeleteAccessLogsBefore(before time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DELETE FROM access_log WHERE created_at < $1`
+	result, err := r.db.Instance.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, helper.NewError("delete access logs", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, helper.NewError("rows affected", err)
+	}
+
+	return rowsAffected, nil
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLogNewAccessLogDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewAccessLogDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		accessLogDbHandler, err := NewAccessLogDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewAccessLogDBHandler to not return an error")
+		require.NotNil(t, accessLogDbHandler, "Expected NewAccessLogDBHandler to return a non-nil instance")
+
+		exists, err := accessLogDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = accessLogDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewAccessLogDBHandler with nil database", func(t *testing.T) {
+		_, err := NewAccessLogDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating AccessLogDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestAccessLogSelectAccessLogs(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	accessLogDbHandler, err := NewAccessLogDBHandler(database, true)
+	require.NoError(t, err, "Expected NewAccessLogDBHandler to not return an error")
+
+	lastWeek := time.Now().AddDate(0, 0, -7)
+	err = accessLogDbHandler.InsertAccessLogs([]*model.AccessLog{
+		{Method: "POST", Route: "/api/job/cancelJob/:rid", Path: "/api/job/cancelJob/1", User: "alice", IP: "10.0.0.1", Status: 200, LatencyMs: 12, CreatedAt: lastWeek},
+		{Method: "POST", Route: "/api/job/cancelJob/:rid", Path: "/api/job/cancelJob/2", User: "bob", IP: "10.0.0.2", Status: 403, LatencyMs: 3},
+		{Method: "GET", Route: "/api/task/getTasks", Path: "/api/task/getTasks", User: "alice", IP: "10.0.0.1", Status: 200, LatencyMs: 5},
+	})
+	require.NoError(t, err, "Expected InsertAccessLogs to not return an error")
+
+	t.Run("Select all access logs newest first", func(t *testing.T) {
+		accessLogs, err := accessLogDbHandler.SelectAccessLogs(nil, 0, 10)
+		require.NoError(t, err, "Expected SelectAccessLogs to not return an error")
+		require.Len(t, accessLogs, 3)
+		assert.Equal(t, "/api/task/getTasks", accessLogs[0].Route)
+
+		nextPage, err := accessLogDbHandler.SelectAccessLogs(nil, accessLogs[1].ID, 10)
+		require.NoError(t, err, "Expected SelectAccessLogs to not return an error")
+		assert.Len(t, nextPage, 1)
+	})
+
+	t.Run("Select access logs by user, route and time", func(t *testing.T) {
+		accessLogs, err := accessLogDbHandler.SelectAccessLogs(&model.AccessLogFilter{User: "alice", Route: "/api/job/"}, 0, 10)
+		require.NoError(t, err, "Expected SelectAccessLogs to not return an error")
+		require.Len(t, accessLogs, 1)
+		assert.Equal(t, "/api/job/cancelJob/1", accessLogs[0].Path)
+
+		accessLogs, err = accessLogDbHandler.SelectAccessLogs(&model.AccessLogFilter{Method: "POST", From: time.Now().Add(-time.Hour)}, 0, 10)
+		require.NoError(t, err, "Expected SelectAccessLogs to not return an error")
+		require.Len(t, accessLogs, 1)
+		assert.Equal(t, "bob", accessLogs[0].User)
+
+		accessLogs, err = accessLogDbHandler.SelectAccessLogs(&model.AccessLogFilter{Status: 403}, 0, 10)
+		require.NoError(t, err, "Expected SelectAccessLogs to not return an error")
+		assert.Len(t, accessLogs, 1)
+	})
+
+	t.Run("Delete access logs before the retention", func(t *testing.T) {
+		deleted, err := accessLogDbHandler.DeleteAccessLogsBefore(time.Now().AddDate(0, 0, -1))
+		require.NoError(t, err, "Expected DeleteAccessLogsBefore to not return an error")
+		assert.Equal(t, int64(1), deleted)
+
+		accessLogs, err := accessLogDbHandler.SelectAccessLogs(nil, 0, 10)
+		require.NoError(t, err, "Expected SelectAccessLogs to not return an error")
+		assert.Len(t, accessLogs, 2)
+	})
+}
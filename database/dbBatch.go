@@ -0,0 +1,280 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+)
+
+// BatchDBHandlerFunctions defines the interface for Batch database operations.
+type BatchDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	InsertBatch(batch *model.Batch) (*model.Batch, error)
+	SelectBatch(rid uuid.UUID) (*model.Batch, error)
+	SelectAllBatches(lastID int, entries int) ([]*model.Batch, error)
+}
+
+// BatchDBHandler implements BatchDBHandlerFunctions and holds the database connection.
+type BatchDBHandler struct {
+	db *helper.Database
+}
+
+// NewBatchDBHandler creates a new instance of BatchDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing batch tables before creating new ones
+func NewBatchDBHandler(dbConnection *helper.Database, withTableDrop bool) (*BatchDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	batchDbHandler := &BatchDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := batchDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := batchDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return batchDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'batch' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r BatchDBHandler) CheckTableExistance() (bool, error) {
+	batchExists, err := r.db.CheckTableExistance("batch")
+	if err != nil {
+		return false, helper.NewError("batch table", err)
+	}
+	return batchExists, nil
+}
+
+// CreateTable creates the 'batch' and 'batch_job' tables in the database.
+// If the tables already exist, it does not create them again.
+func (r BatchDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS batch (
+			id SERIAL PRIMARY KEY,
+			rid UUID UNIQUE NOT NULL DEFAULT gen_random_uuid(),
+			name VARCHAR(120) NOT NULL DEFAULT '',
+			task_key VARCHAR(100) NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE IF NOT EXISTS batch_job (
+			batch_id INT NOT NULL REFERENCES batch(id) ON DELETE CASCADE,
+			job_rid UUID NOT NULL,
+			position INT NOT NULL,
+			PRIMARY KEY (batch_id, job_rid)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_batch_rid ON batch(rid);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create batch table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table batch")
+
+	return nil
+}
+
+// DropTable drops the 'batch' and 'batch_job' tables from the database.
+func (r BatchDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS batch_job; DROP TABLE IF EXISTS batch`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop batch table", err)
+	}
+
+	r.db.Logger.Info("Dropped table batch")
+
+	return nil
+}
+
+// InsertBatch inserts a new batch record with its job RIDs into the database.
+func (r BatchDBHandler) InsertBatch(batch *model.Batch) (*model.Batch, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := r.db.Instance.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, helper.NewError("begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	newBatch := &model.Batch{}
+	query := `
+		INSERT INTO batch (
+			name,
+			task_key
+		) VALUES ($1, $2)
+		RETURNING
+			id,
+			rid,
+			name,
+			task_key,
+			created_at`
+
+	err = tx.QueryRowContext(ctx, query, batch.Name, batch.TaskKey).Scan(
+		&newBatch.ID,
+		&newBatch.RID,
+		&newBatch.Name,
+		&newBatch.TaskKey,
+		&newBatch.CreatedAt,
+	)
+	if err != nil {
+		return nil, helper.NewError("insert batch", err)
+	}
+
+	for i, jobRID := range batch.JobRIDs {
+		_, err = tx.ExecContext(ctx, `INSERT INTO batch_job (batch_id, job_rid, position) VALUES ($1, $2, $3)`, newBatch.ID, jobRID, i)
+		if err != nil {
+			return nil, helper.NewError("insert batch job", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, helper.NewError("commit transaction", err)
+	}
+
+	newBatch.JobRIDs = batch.JobRIDs
+
+	return newBatch, nil
+}
+
+// SelectBatch retrieves a batch with its job RIDs by RID from the database.
+func (r BatchDBHandler) SelectBatch(rid uuid.UUID) (*model.Batch, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	batch := &model.Batch{}
+	query := `
+		SELECT
+			id,
+			rid,
+			name,
+			task_key,
+			created_at
+		FROM batch
+		WHERE rid = $1
+	`
+
+	err := r.db.Instance.QueryRowContext(ctx, query, rid).Scan(
+		&batch.ID,
+		&batch.RID,
+		&batch.Name,
+		&batch.TaskKey,
+		&batch.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("batch not found", fmt.Errorf("no batch with rid %s", rid))
+		}
+		return nil, helper.NewError("select batch", err)
+	}
+
+	batch.JobRIDs, err = r.selectBatchJobRIDs(ctx, batch.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+// SelectAllBatches retrieves all batches without their job RIDs from the database with pagination.
+// lastID is the ID of the last batch from the previous page (0 for first page)
+// entries is the maximum number of batches to return
+func (r BatchDBHandler) SelectAllBatches(lastID int, entries int) ([]*model.Batch, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			rid,
+			name,
+			task_key,
+			created_at
+		FROM batch
+		WHERE id > $1
+		ORDER BY id ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, lastID, entries)
+	if err != nil {
+		return nil, helper.NewError("select all batches", err)
+	}
+	defer rows.Close()
+
+	batches := []*model.Batch{}
+	for rows.Next() {
+		batch := &model.Batch{}
+		err := rows.Scan(
+			&batch.ID,
+			&batch.RID,
+			&batch.Name,
+			&batch.TaskKey,
+			&batch.CreatedAt,
+		)
+		if err != nil {
+			return nil, helper.NewError("scan batch", err)
+		}
+		batches = append(batches, batch)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return batches, nil
+}
+
+func (r BatchDBHandler) selectBatchJobRIDs(ctx context.Context, batchID int) ([]uuid.UUID, error) {
+	rows, err := r.db.Instance.QueryContext(ctx, `SELECT job_rid FROM batch_job WHERE batch_id = $1 ORDER BY position ASC`, batchID)
+	if err != nil {
+		return nil, helper.NewError("select batch jobs", err)
+	}
+	defer rows.Close()
+
+	jobRIDs := []uuid.UUID{}
+	for rows.Next() {
+		var jobRID uuid.UUID
+		err := rows.Scan(&jobRID)
+		if err != nil {
+			return nil, helper.NewError("scan batch job", err)
+		}
+		jobRIDs = append(jobRIDs, jobRID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return jobRIDs, nil
+}
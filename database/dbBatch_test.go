@@ -0,0 +1,90 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchNewBatchDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewBatchDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		batchDbHandler, err := NewBatchDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewBatchDBHandler to not return an error")
+		require.NotNil(t, batchDbHandler, "Expected NewBatchDBHandler to return a non-nil instance")
+
+		exists, err := batchDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = batchDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewBatchDBHandler with nil database", func(t *testing.T) {
+		_, err := NewBatchDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating BatchDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestBatchInsertAndSelectBatch(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	batchDbHandler, err := NewBatchDBHandler(database, true)
+	require.NoError(t, err, "Expected NewBatchDBHandler to not return an error")
+
+	jobRIDs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	insertedBatch, err := batchDbHandler.InsertBatch(&model.Batch{
+		Name:    "Test Batch",
+		TaskKey: "test-task",
+		JobRIDs: jobRIDs,
+	})
+	require.NoError(t, err, "Expected InsertBatch to not return an error")
+	assert.NotEqual(t, uuid.Nil, insertedBatch.RID, "Expected inserted batch to have a RID")
+	assert.Equal(t, "Test Batch", insertedBatch.Name)
+	assert.Equal(t, "test-task", insertedBatch.TaskKey)
+
+	t.Run("Select existing batch", func(t *testing.T) {
+		batch, err := batchDbHandler.SelectBatch(insertedBatch.RID)
+		require.NoError(t, err, "Expected SelectBatch to not return an error")
+		assert.Equal(t, insertedBatch.ID, batch.ID)
+		assert.Equal(t, jobRIDs, batch.JobRIDs, "Expected job RIDs to be returned in insert order")
+	})
+
+	t.Run("Select non existing batch", func(t *testing.T) {
+		_, err := batchDbHandler.SelectBatch(uuid.New())
+		assert.Error(t, err, "Expected SelectBatch to return an error for non existing batch")
+	})
+
+	t.Run("Select all batches", func(t *testing.T) {
+		_, err := batchDbHandler.InsertBatch(&model.Batch{Name: "Second Batch", TaskKey: "test-task"})
+		require.NoError(t, err)
+
+		batches, err := batchDbHandler.SelectAllBatches(0, 10)
+		require.NoError(t, err, "Expected SelectAllBatches to not return an error")
+		assert.Len(t, batches, 2)
+
+		batches, err = batchDbHandler.SelectAllBatches(batches[0].ID, 10)
+		require.NoError(t, err)
+		assert.Len(t, batches, 1)
+		assert.Equal(t, "Second Batch", batches[0].Name)
+	})
+}
@@ -0,0 +1,226 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+)
+
+// DeprecatedRouteUsageDBHandlerFunctions defines the interface for the usage counters of the deprecated routes.
+type DeprecatedRouteUsageDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	AddDeprecatedRouteUsages(usages []*model.DeprecatedRouteUsage) error
+	SelectAllDeprecatedRouteUsages() ([]*model.DeprecatedRouteUsage, error)
+	DeleteDeprecatedRouteUsages(route string) (int64, error)
+}
+
+// DeprecatedRouteUsageDBHandler implements DeprecatedRouteUsageDBHandlerFunctions and holds the database connection.
+type DeprecatedRouteUsageDBHandler struct {
+	db *helper.Database
+}
+
+// NewDeprecatedRouteUsageDBHandler creates a new instance of DeprecatedRouteUsageDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing deprecated_route_usage table before creating a new one
+func NewDeprecatedRouteUsageDBHandler(dbConnection *helper.Database, withTableDrop bool) (*DeprecatedRouteUsageDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	deprecatedRouteUsageDbHandler := &DeprecatedRouteUsageDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := deprecatedRouteUsageDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := deprecatedRouteUsageDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return deprecatedRouteUsageDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'deprecated_route_usage' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r DeprecatedRouteUsageDBHandler) CheckTableExistance() (bool, error) {
+	deprecatedRouteUsageExists, err := r.db.CheckTableExistance("deprecated_route_usage")
+	if err != nil {
+		return false, helper.NewError("deprecated_route_usage table", err)
+	}
+	return deprecatedRouteUsageExists, nil
+}
+
+// CreateTable creates the 'deprecated_route_usage' table in the database.
+// If the table already exists, it does not create it again.
+func (r DeprecatedRouteUsageDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS deprecated_route_usage (
+			route VARCHAR(255) NOT NULL,
+			caller VARCHAR(255) NOT NULL,
+			user_agent TEXT NOT NULL DEFAULT '',
+			calls BIGINT NOT NULL DEFAULT 0,
+			first_called_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			last_called_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (route, caller)
+		);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create deprecated_route_usage table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table deprecated_route_usage")
+
+	return nil
+}
+
+// DropTable drops the 'deprecated_route_usage' table from the database.
+func (r DeprecatedRouteUsageDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS deprecated_route_usage`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop deprecated_route_usage table", err)
+	}
+
+	r.db.Logger.Info("Dropped table deprecated_route_usage")
+
+	return nil
+}
+
+// AddDeprecatedRouteUsages adds the calls of the usages to the counters of their route and caller in one transaction.
+// The first call is kept, the last call and the user agent are updated.
+func (r DeprecatedRouteUsageDBHandler) AddDeprecatedRouteUsages(usages []*model.DeprecatedRouteUsage) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := r.db.Instance.BeginTx(ctx, nil)
+	if err != nil {
+		return helper.NewError("begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO deprecated_route_usage (
+			route,
+			caller,
+			user_agent,
+			calls,
+			first_called_at,
+			last_called_at
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (route, caller) DO UPDATE
+		SET
+			user_agent = EXCLUDED.user_agent,
+			calls = deprecated_route_usage.calls + EXCLUDED.calls,
+			first_called_at = LEAST(deprecated_route_usage.first_called_at, EXCLUDED.first_called_at),
+			last_called_at = GREATEST(deprecated_route_usage.last_called_at, EXCLUDED.last_called_at)`
+
+	for _, usage := range usages {
+		_, err = tx.ExecContext(
+			ctx,
+			query,
+			usage.Route,
+			usage.Caller,
+			usage.UserAgent,
+			usage.Calls,
+			usage.FirstCalledAt,
+			usage.LastCalledAt,
+		)
+		if err != nil {
+			return helper.NewError("add deprecated route usage", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return helper.NewError("commit transaction", err)
+	}
+
+	return nil
+}
+
+// SelectAllDeprecatedRouteUsages retrieves the usages of all routes ordered by route, the most recent caller first.
+func (r DeprecatedRouteUsageDBHandler) SelectAllDeprecatedRouteUsages() ([]*model.DeprecatedRouteUsage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			route,
+			caller,
+			user_agent,
+			calls,
+			first_called_at,
+			last_called_at
+		FROM deprecated_route_usage
+		ORDER BY route ASC, last_called_at DESC
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query)
+	if err != nil {
+		return nil, helper.NewError("select deprecated route usages", err)
+	}
+	defer rows.Close()
+
+	usages := []*model.DeprecatedRouteUsage{}
+	for rows.Next() {
+		usage := &model.DeprecatedRouteUsage{}
+		err := rows.Scan(
+			&usage.Route,
+			&usage.Caller,
+			&usage.UserAgent,
+			&usage.Calls,
+			&usage.FirstCalledAt,
+			&usage.LastCalledAt,
+		)
+		if err != nil {
+			return nil, helper.NewError("scan deprecated route usage", err)
+		}
+		usages = append(usages, usage)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return usages, nil
+}
+
+// DeleteDeprecatedRouteUsages deletes the usages of the route, eg. to count again after the callers were notified.
+// It returns the number of deleted callers.
+func (r DeprecatedRouteUsageDBHandler) DeleteDeprecatedRouteUsages(route string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DELETE FROM deprecated_route_usage WHERE route = $1`
+	result, err := r.db.Instance.ExecContext(ctx, query, route)
+	if err != nil {
+		return 0, helper.NewError("delete deprecated route usages", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, helper.NewError("rows affected", err)
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecatedRouteUsageNewDeprecatedRouteUsageDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewDeprecatedRouteUsageDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		deprecatedRouteUsageDbHandler, err := NewDeprecatedRouteUsageDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewDeprecatedRouteUsageDBHandler to not return an error")
+		require.NotNil(t, deprecatedRouteUsageDbHandler, "Expected NewDeprecatedRouteUsageDBHandler to return a non-nil instance")
+
+		exists, err := deprecatedRouteUsageDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = deprecatedRouteUsageDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewDeprecatedRouteUsageDBHandler with nil database", func(t *testing.T) {
+		_, err := NewDeprecatedRouteUsageDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating DeprecatedRouteUsageDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestDeprecatedRouteUsageAddDeprecatedRouteUsages(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	deprecatedRouteUsageDbHandler, err := NewDeprecatedRouteUsageDBHandler(database, true)
+	require.NoError(t, err, "Expected NewDeprecatedRouteUsageDBHandler to not return an error")
+
+	route := "POST /api/job/addJob/:taskKey"
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	err = deprecatedRouteUsageDbHandler.AddDeprecatedRouteUsages([]*model.DeprecatedRouteUsage{
+		{Route: route, Caller: "apikey:deploy", UserAgent: "curl/8.0", Calls: 3, FirstCalledAt: start, LastCalledAt: start.Add(time.Minute)},
+		{Route: route, Caller: "alice", UserAgent: "queuerctl", Calls: 1, FirstCalledAt: start, LastCalledAt: start},
+		{Route: "GET /api/job/getJobs", Caller: "alice", Calls: 1, FirstCalledAt: start, LastCalledAt: start},
+	})
+	require.NoError(t, err, "Expected AddDeprecatedRouteUsages to not return an error")
+
+	err = deprecatedRouteUsageDbHandler.AddDeprecatedRouteUsages([]*model.DeprecatedRouteUsage{
+		{Route: route, Caller: "apikey:deploy", UserAgent: "curl/8.1", Calls: 2, FirstCalledAt: start.Add(time.Hour), LastCalledAt: start.Add(time.Hour)},
+	})
+	require.NoError(t, err, "Expected AddDeprecatedRouteUsages to add to the counters")
+
+	t.Run("Select the usages with the added calls", func(t *testing.T) {
+		usages, err := deprecatedRouteUsageDbHandler.SelectAllDeprecatedRouteUsages()
+		require.NoError(t, err, "Expected SelectAllDeprecatedRouteUsages to not return an error")
+		require.Len(t, usages, 3)
+
+		assert.Equal(t, "GET /api/job/getJobs", usages[0].Route)
+		assert.Equal(t, "apikey:deploy", usages[1].Caller, "Expected the most recent caller first")
+		assert.Equal(t, int64(5), usages[1].Calls)
+		assert.Equal(t, "curl/8.1", usages[1].UserAgent)
+		assert.True(t, usages[1].FirstCalledAt.Equal(start), "Expected the first call to be kept")
+		assert.True(t, usages[1].LastCalledAt.Equal(start.Add(time.Hour)))
+		assert.Equal(t, "alice", usages[2].Caller)
+	})
+
+	t.Run("Delete the usages of a route", func(t *testing.T) {
+		deleted, err := deprecatedRouteUsageDbHandler.DeleteDeprecatedRouteUsages(route)
+		require.NoError(t, err, "Expected DeleteDeprecatedRouteUsages to not return an error")
+		assert.Equal(t, int64(2), deleted)
+
+		usages, err := deprecatedRouteUsageDbHandler.SelectAllDeprecatedRouteUsages()
+		require.NoError(t, err)
+		assert.Len(t, usages, 1)
+	})
+}
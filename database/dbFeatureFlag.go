@@ -0,0 +1,267 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+)
+
+// FeatureFlagDBHandlerFunctions defines the interface for FeatureFlag database operations.
+type FeatureFlagDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	InsertFeatureFlag(featureFlag *model.FeatureFlag) (*model.FeatureFlag, error)
+	SelectFeatureFlag(key string) (*model.FeatureFlag, error)
+	SelectAllFeatureFlags() ([]*model.FeatureFlag, error)
+	UpdateFeatureFlagEnabled(key string, enabled bool, updatedBy string) (*model.FeatureFlag, error)
+}
+
+// FeatureFlagDBHandler implements FeatureFlagDBHandlerFunctions and holds the database connection.
+type FeatureFlagDBHandler struct {
+	db *helper.Database
+}
+
+// NewFeatureFlagDBHandler creates a new instance of FeatureFlagDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing feature_flag table before creating a new one
+func NewFeatureFlagDBHandler(dbConnection *helper.Database, withTableDrop bool) (*FeatureFlagDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	featureFlagDbHandler := &FeatureFlagDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := featureFlagDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := featureFlagDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return featureFlagDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'feature_flag' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r FeatureFlagDBHandler) CheckTableExistance() (bool, error) {
+	featureFlagExists, err := r.db.CheckTableExistance("feature_flag")
+	if err != nil {
+		return false, helper.NewError("feature_flag table", err)
+	}
+	return featureFlagExists, nil
+}
+
+// CreateTable creates the 'feature_flag' table in the database.
+// If the table already exists, it does not create it again.
+func (r FeatureFlagDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS feature_flag (
+			id SERIAL PRIMARY KEY,
+			key VARCHAR(100) UNIQUE NOT NULL,
+			description TEXT NOT NULL DEFAULT '',
+			enabled BOOLEAN NOT NULL DEFAULT FALSE,
+			updated_by VARCHAR(255) NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create feature_flag table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table feature_flag")
+
+	return nil
+}
+
+// DropTable drops the 'feature_flag' table from the database.
+func (r FeatureFlagDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS feature_flag`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop feature_flag table", err)
+	}
+
+	r.db.Logger.Info("Dropped table feature_flag")
+
+	return nil
+}
+
+// InsertFeatureFlag inserts a feature flag if its key does not exist yet.
+// An existing flag only gets the new description, so it keeps its state across restarts.
+func (r FeatureFlagDBHandler) InsertFeatureFlag(featureFlag *model.FeatureFlag) (*model.FeatureFlag, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		INSERT INTO feature_flag (
+			key,
+			description,
+			enabled,
+			updated_by
+		) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key) DO UPDATE SET
+			description = EXCLUDED.description
+		RETURNING
+			id,
+			key,
+			description,
+			enabled,
+			updated_by,
+			created_at,
+			updated_at`
+
+	row := r.db.Instance.QueryRowContext(
+		ctx,
+		query,
+		featureFlag.Key,
+		featureFlag.Description,
+		featureFlag.Enabled,
+		featureFlag.UpdatedBy,
+	)
+	insertedFeatureFlag, err := scanFeatureFlag(row)
+	if err != nil {
+		return nil, helper.NewError("insert feature flag", err)
+	}
+
+	return insertedFeatureFlag, nil
+}
+
+// SelectFeatureFlag retrieves a feature flag by its key.
+func (r FeatureFlagDBHandler) SelectFeatureFlag(key string) (*model.FeatureFlag, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			key,
+			description,
+			enabled,
+			updated_by,
+			created_at,
+			updated_at
+		FROM feature_flag
+		WHERE key = $1
+	`
+
+	featureFlag, err := scanFeatureFlag(r.db.Instance.QueryRowContext(ctx, query, key))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("feature flag not found", fmt.Errorf("no feature flag with key %s", key))
+		}
+		return nil, helper.NewError("select feature flag", err)
+	}
+
+	return featureFlag, nil
+}
+
+// SelectAllFeatureFlags retrieves all feature flags ordered by key.
+func (r FeatureFlagDBHandler) SelectAllFeatureFlags() ([]*model.FeatureFlag, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			key,
+			description,
+			enabled,
+			updated_by,
+			created_at,
+			updated_at
+		FROM feature_flag
+		ORDER BY key ASC
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query)
+	if err != nil {
+		return nil, helper.NewError("select all feature flags", err)
+	}
+	defer rows.Close()
+
+	featureFlags := []*model.FeatureFlag{}
+	for rows.Next() {
+		featureFlag, err := scanFeatureFlag(rows)
+		if err != nil {
+			return nil, helper.NewError("scan feature flag", err)
+		}
+		featureFlags = append(featureFlags, featureFlag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return featureFlags, nil
+}
+
+// UpdateFeatureFlagEnabled enables or disables a feature flag and records who changed it.
+func (r FeatureFlagDBHandler) UpdateFeatureFlagEnabled(key string, enabled bool, updatedBy string) (*model.FeatureFlag, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE feature_flag
+		SET
+			enabled = $2,
+			updated_by = $3,
+			updated_at = NOW()
+		WHERE key = $1
+		RETURNING
+			id,
+			key,
+			description,
+			enabled,
+			updated_by,
+			created_at,
+			updated_at`
+
+	featureFlag, err := scanFeatureFlag(r.db.Instance.QueryRowContext(ctx, query, key, enabled, updatedBy))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("feature flag not found", fmt.Errorf("no feature flag with key %s", key))
+		}
+		return nil, helper.NewError("update feature flag", err)
+	}
+
+	return featureFlag, nil
+}
+
+func scanFeatureFlag(row interface{ Scan(dest ...any) error }) (*model.FeatureFlag, error) {
+	featureFlag := &model.FeatureFlag{}
+	err := row.Scan(
+		&featureFlag.ID,
+		&featureFlag.Key,
+		&featureFlag.Description,
+		&featureFlag.Enabled,
+		&featureFlag.UpdatedBy,
+		&featureFlag.CreatedAt,
+		&featureFlag.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return featureFlag, nil
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureFlagNewFeatureFlagDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewFeatureFlagDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		featureFlagDbHandler, err := NewFeatureFlagDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewFeatureFlagDBHandler to not return an error")
+		require.NotNil(t, featureFlagDbHandler, "Expected NewFeatureFlagDBHandler to return a non-nil instance")
+
+		exists, err := featureFlagDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = featureFlagDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewFeatureFlagDBHandler with nil database", func(t *testing.T) {
+		_, err := NewFeatureFlagDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating FeatureFlagDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestFeatureFlagInsertAndUpdateFeatureFlag(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	featureFlagDbHandler, err := NewFeatureFlagDBHandler(database, true)
+	require.NoError(t, err, "Expected NewFeatureFlagDBHandler to not return an error")
+
+	insertedFeatureFlag, err := featureFlagDbHandler.InsertFeatureFlag(&model.FeatureFlag{Key: model.FEATURE_FLAG_SSE_UPDATES, Description: "SSE"})
+	require.NoError(t, err, "Expected InsertFeatureFlag to not return an error")
+	assert.Equal(t, model.FEATURE_FLAG_SSE_UPDATES, insertedFeatureFlag.Key)
+	assert.False(t, insertedFeatureFlag.Enabled)
+
+	t.Run("Update feature flag", func(t *testing.T) {
+		updatedFeatureFlag, err := featureFlagDbHandler.UpdateFeatureFlagEnabled(model.FEATURE_FLAG_SSE_UPDATES, true, "admin")
+		require.NoError(t, err, "Expected UpdateFeatureFlagEnabled to not return an error")
+		assert.True(t, updatedFeatureFlag.Enabled)
+		assert.Equal(t, "admin", updatedFeatureFlag.UpdatedBy)
+
+		_, err = featureFlagDbHandler.UpdateFeatureFlagEnabled("unknown", true, "admin")
+		assert.Error(t, err, "Expected UpdateFeatureFlagEnabled to return an error for an unknown key")
+	})
+
+	t.Run("Insert existing feature flag keeps its state", func(t *testing.T) {
+		featureFlag, err := featureFlagDbHandler.InsertFeatureFlag(&model.FeatureFlag{Key: model.FEATURE_FLAG_SSE_UPDATES, Description: "Server-sent events"})
+		require.NoError(t, err, "Expected InsertFeatureFlag to not return an error")
+		assert.True(t, featureFlag.Enabled, "Expected the existing flag to stay enabled")
+		assert.Equal(t, "Server-sent events", featureFlag.Description)
+	})
+
+	t.Run("Select feature flags", func(t *testing.T) {
+		_, err := featureFlagDbHandler.InsertFeatureFlag(&model.FeatureFlag{Key: model.FEATURE_FLAG_NEW_DASHBOARD})
+		require.NoError(t, err, "Expected InsertFeatureFlag to not return an error")
+
+		featureFlag, err := featureFlagDbHandler.SelectFeatureFlag(model.FEATURE_FLAG_SSE_UPDATES)
+		require.NoError(t, err, "Expected SelectFeatureFlag to not return an error")
+		assert.True(t, featureFlag.Enabled)
+
+		featureFlags, err := featureFlagDbHandler.SelectAllFeatureFlags()
+		require.NoError(t, err, "Expected SelectAllFeatureFlags to not return an error")
+		require.Len(t, featureFlags, 2)
+		assert.Equal(t, model.FEATURE_FLAG_NEW_DASHBOARD, featureFlags[0].Key)
+
+		_, err = featureFlagDbHandler.SelectFeatureFlag("unknown")
+		assert.Error(t, err, "Expected SelectFeatureFlag to return an error for an unknown key")
+	})
+}
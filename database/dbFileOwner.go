@@ -0,0 +1,181 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+)
+
+// FileOwnerDBHandlerFunctions defines the interface for FileOwner database operations.
+type FileOwnerDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	UpsertFileOwner(fileOwner *model.FileOwner) (*model.FileOwner, error)
+	DeleteFileOwner(filename string) error
+	SelectStorageUsage(principal string) (int64, int, error)
+}
+
+// FileOwnerDBHandler implements FileOwnerDBHandlerFunctions and holds the database connection.
+type FileOwnerDBHandler struct {
+	db *helper.Database
+}
+
+// NewFileOwnerDBHandler creates a new instance of FileOwnerDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing file_owner table before creating a new one
+func NewFileOwnerDBHandler(dbConnection *helper.Database, withTableDrop bool) (*FileOwnerDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	fileOwnerDbHandler := &FileOwnerDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := fileOwnerDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := fileOwnerDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return fileOwnerDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'file_owner' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r FileOwnerDBHandler) CheckTableExistance() (bool, error) {
+	fileOwnerExists, err := r.db.CheckTableExistance("file_owner")
+	if err != nil {
+		return false, helper.NewError("file_owner table", err)
+	}
+	return fileOwnerExists, nil
+}
+
+// CreateTable creates the 'file_owner' table in the database.
+// If the table already exists, it does not create it again.
+func (r FileOwnerDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS file_owner (
+			filename VARCHAR(255) PRIMARY KEY,
+			principal VARCHAR(100) NOT NULL,
+			size_bytes BIGINT NOT NULL DEFAULT 0,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_file_owner_principal ON file_owner (principal);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create file_owner table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table file_owner")
+
+	return nil
+}
+
+// DropTable drops the 'file_owner' table from the database.
+func (r FileOwnerDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS file_owner`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop file_owner table", err)
+	}
+
+	r.db.Logger.Info("Dropped table file_owner")
+
+	return nil
+}
+
+// UpsertFileOwner records the principal and size of an uploaded file, a file uploaded again gets the new principal and size.
+func (r FileOwnerDBHandler) UpsertFileOwner(fileOwner *model.FileOwner) (*model.FileOwner, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		INSERT INTO file_owner (
+			filename,
+			principal,
+			size_bytes
+		) VALUES ($1, $2, $3)
+		ON CONFLICT (filename) DO UPDATE
+		SET
+			principal = EXCLUDED.principal,
+			size_bytes = EXCLUDED.size_bytes,
+			updated_at = NOW()
+		RETURNING
+			filename,
+			principal,
+			size_bytes,
+			created_at,
+			updated_at`
+
+	upsertedFileOwner := &model.FileOwner{}
+	err := r.db.Instance.QueryRowContext(ctx, query, fileOwner.Filename, fileOwner.Principal, fileOwner.SizeBytes).Scan(
+		&upsertedFileOwner.Filename,
+		&upsertedFileOwner.Principal,
+		&upsertedFileOwner.SizeBytes,
+		&upsertedFileOwner.CreatedAt,
+		&upsertedFileOwner.UpdatedAt,
+	)
+	if err != nil {
+		return nil, helper.NewError("upsert file owner", err)
+	}
+
+	return upsertedFileOwner, nil
+}
+
+// DeleteFileOwner deletes the owner of a deleted file, it does not return an error if the file has no owner.
+func (r FileOwnerDBHandler) DeleteFileOwner(filename string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DELETE FROM file_owner WHERE filename = $1`
+	_, err := r.db.Instance.ExecContext(ctx, query, filename)
+	if err != nil {
+		return helper.NewError("delete file owner", err)
+	}
+
+	return nil
+}
+
+// SelectStorageUsage returns the bytes and the number of the files uploaded by the principal.
+func (r FileOwnerDBHandler) SelectStorageUsage(principal string) (int64, int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			COALESCE(SUM(size_bytes), 0),
+			COUNT(*)
+		FROM file_owner
+		WHERE principal = $1
+	`
+
+	var bytes int64
+	var files int
+	err := r.db.Instance.QueryRowContext(ctx, query, principal).Scan(&bytes, &files)
+	if err != nil {
+		return 0, 0, helper.NewError("select storage usage", err)
+	}
+
+	return bytes, files, nil
+}
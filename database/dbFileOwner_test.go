@@ -0,0 +1,93 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileOwnerNewFileOwnerDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewFileOwnerDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		fileOwnerDbHandler, err := NewFileOwnerDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewFileOwnerDBHandler to not return an error")
+		require.NotNil(t, fileOwnerDbHandler, "Expected NewFileOwnerDBHandler to return a non-nil instance")
+
+		exists, err := fileOwnerDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = fileOwnerDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewFileOwnerDBHandler with nil database", func(t *testing.T) {
+		_, err := NewFileOwnerDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating FileOwnerDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestFileOwnerSelectStorageUsage(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	fileOwnerDbHandler, err := NewFileOwnerDBHandler(database, true)
+	require.NoError(t, err, "Expected NewFileOwnerDBHandler to not return an error")
+
+	for _, fileOwner := range []*model.FileOwner{
+		{Filename: "a.csv", Principal: "alice", SizeBytes: 100},
+		{Filename: "b.csv", Principal: "alice", SizeBytes: 50},
+		{Filename: "c.csv", Principal: "bob", SizeBytes: 10},
+	} {
+		_, err := fileOwnerDbHandler.UpsertFileOwner(fileOwner)
+		require.NoError(t, err, "Expected UpsertFileOwner to not return an error")
+	}
+
+	t.Run("Sum the files of the principal", func(t *testing.T) {
+		bytes, files, err := fileOwnerDbHandler.SelectStorageUsage("alice")
+		require.NoError(t, err, "Expected SelectStorageUsage to not return an error")
+		assert.Equal(t, int64(150), bytes)
+		assert.Equal(t, 2, files)
+
+		bytes, files, err = fileOwnerDbHandler.SelectStorageUsage("carol")
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), bytes)
+		assert.Equal(t, 0, files)
+	})
+
+	t.Run("Move a file uploaded again to the new principal", func(t *testing.T) {
+		fileOwner, err := fileOwnerDbHandler.UpsertFileOwner(&model.FileOwner{Filename: "a.csv", Principal: "bob", SizeBytes: 200})
+		require.NoError(t, err, "Expected UpsertFileOwner to not return an error")
+		assert.Equal(t, "bob", fileOwner.Principal)
+
+		bytes, _, err := fileOwnerDbHandler.SelectStorageUsage("bob")
+		require.NoError(t, err)
+		assert.Equal(t, int64(210), bytes)
+	})
+
+	t.Run("Delete the owner of a deleted file", func(t *testing.T) {
+		require.NoError(t, fileOwnerDbHandler.DeleteFileOwner("b.csv"))
+		require.NoError(t, fileOwnerDbHandler.DeleteFileOwner("missing.csv"), "Expected no error for a file without owner")
+
+		bytes, files, err := fileOwnerDbHandler.SelectStorageUsage("alice")
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), bytes)
+		assert.Equal(t, 0, files)
+	})
+}
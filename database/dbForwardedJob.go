@@ -0,0 +1,358 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+)
+
+// ForwardedJobDBHandlerFunctions defines the interface for ForwardedJob database operations.
+type ForwardedJobDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	InsertForwardedJob(forwardedJob *model.ForwardedJob) (*model.ForwardedJob, error)
+	UpdateForwardedJobStatus(forwardedJob *model.ForwardedJob) (*model.ForwardedJob, error)
+	SelectForwardedJob(rid uuid.UUID) (*model.ForwardedJob, error)
+	SelectAllForwardedJobs(lastID int, entries int) ([]*model.ForwardedJob, error)
+	SelectUnfinishedForwardedJobs() ([]*model.ForwardedJob, error)
+}
+
+// ForwardedJobDBHandler implements ForwardedJobDBHandlerFunctions and holds the database connection.
+type ForwardedJobDBHandler struct {
+	db *helper.Database
+}
+
+// NewForwardedJobDBHandler creates a new instance of ForwardedJobDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing forwarded_job table before creating a new one
+func NewForwardedJobDBHandler(dbConnection *helper.Database, withTableDrop bool) (*ForwardedJobDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	forwardedJobDbHandler := &ForwardedJobDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := forwardedJobDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := forwardedJobDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return forwardedJobDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'forwarded_job' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r ForwardedJobDBHandler) CheckTableExistance() (bool, error) {
+	forwardedJobExists, err := r.db.CheckTableExistance("forwarded_job")
+	if err != nil {
+		return false, helper.NewError("forwarded_job table", err)
+	}
+	return forwardedJobExists, nil
+}
+
+// CreateTable creates the 'forwarded_job' table in the database.
+// If the table already exists, it does not create it again.
+func (r ForwardedJobDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS forwarded_job (
+			id SERIAL PRIMARY KEY,
+			rid UUID UNIQUE NOT NULL DEFAULT gen_random_uuid(),
+			rule VARCHAR(100) NOT NULL DEFAULT '',
+			task_key VARCHAR(100) NOT NULL,
+			remote_url TEXT NOT NULL,
+			remote_task VARCHAR(100) NOT NULL,
+			remote_rid UUID NOT NULL,
+			parameters JSONB NOT NULL DEFAULT '{}'::jsonb,
+			status VARCHAR(50) NOT NULL,
+			results JSONB NOT NULL DEFAULT '[]'::jsonb,
+			error TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_forwarded_job_status ON forwarded_job (status);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create forwarded_job table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table forwarded_job")
+
+	return nil
+}
+
+// DropTable drops the 'forwarded_job' table from the database.
+func (r ForwardedJobDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS forwarded_job`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop forwarded_job table", err)
+	}
+
+	r.db.Logger.Info("Dropped table forwarded_job")
+
+	return nil
+}
+
+// InsertForwardedJob inserts the record of a job that was added on a remote instance.
+func (r ForwardedJobDBHandler) InsertForwardedJob(forwardedJob *model.ForwardedJob) (*model.ForwardedJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		INSERT INTO forwarded_job (
+			rule,
+			task_key,
+			remote_url,
+			remote_task,
+			remote_rid,
+			parameters,
+			status,
+			results,
+			error
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING
+			id,
+			rid,
+			rule,
+			task_key,
+			remote_url,
+			remote_task,
+			remote_rid,
+			parameters,
+			status,
+			results,
+			error,
+			created_at,
+			updated_at`
+
+	row := r.db.Instance.QueryRowContext(
+		ctx,
+		query,
+		forwardedJob.Rule,
+		forwardedJob.TaskKey,
+		forwardedJob.RemoteURL,
+		forwardedJob.RemoteTask,
+		forwardedJob.RemoteRID,
+		forwardedJob.Parameters,
+		forwardedJob.Status,
+		forwardedJob.Results,
+		forwardedJob.Error,
+	)
+	insertedForwardedJob, err := scanForwardedJob(row)
+	if err != nil {
+		return nil, helper.NewError("insert forwarded job", err)
+	}
+
+	return insertedForwardedJob, nil
+}
+
+// UpdateForwardedJobStatus updates the status, results and error synced back from the remote job.
+func (r ForwardedJobDBHandler) UpdateForwardedJobStatus(forwardedJob *model.ForwardedJob) (*model.ForwardedJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE forwarded_job
+		SET
+			status = $1,
+			results = $2,
+			error = $3,
+			updated_at = NOW()
+		WHERE rid = $4
+		RETURNING
+			id,
+			rid,
+			rule,
+			task_key,
+			remote_url,
+			remote_task,
+			remote_rid,
+			parameters,
+			status,
+			results,
+			error,
+			created_at,
+			updated_at`
+
+	row := r.db.Instance.QueryRowContext(ctx, query, forwardedJob.Status, forwardedJob.Results, forwardedJob.Error, forwardedJob.RID)
+	updatedForwardedJob, err := scanForwardedJob(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("forwarded job not found", fmt.Errorf("no forwarded job with rid %s", forwardedJob.RID))
+		}
+		return nil, helper.NewError("update forwarded job", err)
+	}
+
+	return updatedForwardedJob, nil
+}
+
+// SelectForwardedJob retrieves a forwarded job by its local RID.
+func (r ForwardedJobDBHandler) SelectForwardedJob(rid uuid.UUID) (*model.ForwardedJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			rid,
+			rule,
+			task_key,
+			remote_url,
+			remote_task,
+			remote_rid,
+			parameters,
+			status,
+			results,
+			error,
+			created_at,
+			updated_at
+		FROM forwarded_job
+		WHERE rid = $1
+	`
+
+	forwardedJob, err := scanForwardedJob(r.db.Instance.QueryRowContext(ctx, query, rid))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("forwarded job not found", fmt.Errorf("no forwarded job with rid %s", rid))
+		}
+		return nil, helper.NewError("select forwarded job", err)
+	}
+
+	return forwardedJob, nil
+}
+
+// SelectAllForwardedJobs retrieves the forwarded jobs with pagination, newest first.
+// lastID is the ID of the last entry from the previous page (0 for first page)
+// entries is the maximum number of entries to return
+func (r ForwardedJobDBHandler) SelectAllForwardedJobs(lastID int, entries int) ([]*model.ForwardedJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			rid,
+			rule,
+			task_key,
+			remote_url,
+			remote_task,
+			remote_rid,
+			parameters,
+			status,
+			results,
+			error,
+			created_at,
+			updated_at
+		FROM forwarded_job
+		WHERE $1 = 0 OR id < $1
+		ORDER BY id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, lastID, entries)
+	if err != nil {
+		return nil, helper.NewError("select all forwarded jobs", err)
+	}
+	defer rows.Close()
+
+	return scanForwardedJobs(rows)
+}
+
+// SelectUnfinishedForwardedJobs retrieves the forwarded jobs that have not ended on the remote instance yet, oldest first.
+func (r ForwardedJobDBHandler) SelectUnfinishedForwardedJobs() ([]*model.ForwardedJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			rid,
+			rule,
+			task_key,
+			remote_url,
+			remote_task,
+			remote_rid,
+			parameters,
+			status,
+			results,
+			error,
+			created_at,
+			updated_at
+		FROM forwarded_job
+		WHERE status NOT IN ('SUCCEEDED', 'FAILED', 'CANCELLED')
+		ORDER BY id ASC
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query)
+	if err != nil {
+		return nil, helper.NewError("select unfinished forwarded jobs", err)
+	}
+	defer rows.Close()
+
+	return scanForwardedJobs(rows)
+}
+
+// scanForwardedJob scans a forwarded job row in the column order of the forwarded job queries.
+func scanForwardedJob(row interface{ Scan(dest ...any) error }) (*model.ForwardedJob, error) {
+	forwardedJob := &model.ForwardedJob{}
+	err := row.Scan(
+		&forwardedJob.ID,
+		&forwardedJob.RID,
+		&forwardedJob.Rule,
+		&forwardedJob.TaskKey,
+		&forwardedJob.RemoteURL,
+		&forwardedJob.RemoteTask,
+		&forwardedJob.RemoteRID,
+		&forwardedJob.Parameters,
+		&forwardedJob.Status,
+		&forwardedJob.Results,
+		&forwardedJob.Error,
+		&forwardedJob.CreatedAt,
+		&forwardedJob.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return forwardedJob, nil
+}
+
+func scanForwardedJobs(rows *sql.Rows) ([]*model.ForwardedJob, error) {
+	forwardedJobs := []*model.ForwardedJob{}
+	for rows.Next() {
+		forwardedJob, err := scanForwardedJob(rows)
+		if err != nil {
+			return nil, helper.NewError("scan forwarded job", err)
+		}
+		forwardedJobs = append(forwardedJobs, forwardedJob)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return forwardedJobs, nil
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	qmodel "github.com/siherrmann/queuer/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForwardedJobNewForwardedJobDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewForwardedJobDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		forwardedJobDbHandler, err := NewForwardedJobDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewForwardedJobDBHandler to not return an error")
+		require.NotNil(t, forwardedJobDbHandler, "Expected NewForwardedJobDBHandler to return a non-nil instance")
+
+		exists, err := forwardedJobDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = forwardedJobDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewForwardedJobDBHandler with nil database", func(t *testing.T) {
+		_, err := NewForwardedJobDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating ForwardedJobDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestForwardedJobInsertUpdateAndSelectForwardedJobs(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	forwardedJobDbHandler, err := NewForwardedJobDBHandler(database, true)
+	require.NoError(t, err, "Expected NewForwardedJobDBHandler to not return an error")
+
+	remoteRID := uuid.New()
+	insertedForwardedJob, err := forwardedJobDbHandler.InsertForwardedJob(&model.ForwardedJob{
+		Rule:       "burst",
+		TaskKey:    "convert-file",
+		RemoteURL:  "https://queuer.cluster-b.example.com",
+		RemoteTask: "convert-file-b",
+		RemoteRID:  remoteRID,
+		Parameters: qmodel.ParametersKeyed{"file": "input.pdf"},
+		Status:     qmodel.JobStatusQueued,
+	})
+	require.NoError(t, err, "Expected InsertForwardedJob to not return an error")
+	assert.NotEqual(t, uuid.Nil, insertedForwardedJob.RID)
+	assert.Equal(t, remoteRID, insertedForwardedJob.RemoteRID)
+	assert.Equal(t, "input.pdf", insertedForwardedJob.Parameters["file"])
+
+	unfinishedForwardedJobs, err := forwardedJobDbHandler.SelectUnfinishedForwardedJobs()
+	require.NoError(t, err, "Expected SelectUnfinishedForwardedJobs to not return an error")
+	assert.Len(t, unfinishedForwardedJobs, 1)
+
+	insertedForwardedJob.Status = qmodel.JobStatusSucceeded
+	insertedForwardedJob.Results = qmodel.Parameters{"output.pdf"}
+	updatedForwardedJob, err := forwardedJobDbHandler.UpdateForwardedJobStatus(insertedForwardedJob)
+	require.NoError(t, err, "Expected UpdateForwardedJobStatus to not return an error")
+	assert.Equal(t, qmodel.JobStatusSucceeded, updatedForwardedJob.Status)
+	assert.Equal(t, qmodel.Parameters{"output.pdf"}, updatedForwardedJob.Results)
+
+	unfinishedForwardedJobs, err = forwardedJobDbHandler.SelectUnfinishedForwardedJobs()
+	require.NoError(t, err, "Expected SelectUnfinishedForwardedJobs to not return an error")
+	assert.Empty(t, unfinishedForwardedJobs)
+
+	selectedForwardedJob, err := forwardedJobDbHandler.SelectForwardedJob(insertedForwardedJob.RID)
+	require.NoError(t, err, "Expected SelectForwardedJob to not return an error")
+	assert.Equal(t, qmodel.JobStatusSucceeded, selectedForwardedJob.Status)
+
+	_, err = forwardedJobDbHandler.SelectForwardedJob(uuid.New())
+	assert.Error(t, err, "Expected SelectForwardedJob to return an error for an unknown forwarded job")
+
+	forwardedJobs, err := forwardedJobDbHandler.SelectAllForwardedJobs(0, 10)
+	require.NoError(t, err, "Expected SelectAllForwardedJobs to not return an error")
+	assert.Len(t, forwardedJobs, 1)
+}
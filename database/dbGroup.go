@@ -0,0 +1,351 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+)
+
+// GroupDBHandlerFunctions defines the interface for Group database operations.
+type GroupDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	InsertGroup(group *model.Group) (*model.Group, error)
+	UpdateGroup(group *model.Group) (*model.Group, error)
+	DeleteGroup(rid uuid.UUID) error
+	SelectGroup(rid uuid.UUID) (*model.Group, error)
+	SelectAllGroups(lastID int, entries int) ([]*model.Group, error)
+	SelectGroupsByMember(userRID uuid.UUID) ([]*model.Group, error)
+}
+
+// GroupDBHandler implements GroupDBHandlerFunctions and holds the database connection.
+type GroupDBHandler struct {
+	db *helper.Database
+}
+
+// NewGroupDBHandler creates a new instance of GroupDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing manager_group table before creating a new one
+func NewGroupDBHandler(dbConnection *helper.Database, withTableDrop bool) (*GroupDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	groupDbHandler := &GroupDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := groupDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := groupDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return groupDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'manager_group' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r GroupDBHandler) CheckTableExistance() (bool, error) {
+	groupExists, err := r.db.CheckTableExistance("manager_group")
+	if err != nil {
+		return false, helper.NewError("manager_group table", err)
+	}
+	return groupExists, nil
+}
+
+// CreateTable creates the 'manager_group' table in the database.
+// If the table already exists, it does not create it again.
+func (r GroupDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS manager_group (
+			id SERIAL PRIMARY KEY,
+			rid UUID UNIQUE NOT NULL DEFAULT gen_random_uuid(),
+			external_id VARCHAR(255) NOT NULL DEFAULT '',
+			display_name VARCHAR(255) UNIQUE NOT NULL,
+			members JSONB NOT NULL DEFAULT '[]'::jsonb,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_manager_group_members ON manager_group USING GIN (members);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create manager_group table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table manager_group")
+
+	return nil
+}
+
+// DropTable drops the 'manager_group' table from the database.
+func (r GroupDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS manager_group`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop manager_group table", err)
+	}
+
+	r.db.Logger.Info("Dropped table manager_group")
+
+	return nil
+}
+
+// InsertGroup inserts a new group into the database.
+func (r GroupDBHandler) InsertGroup(group *model.Group) (*model.Group, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	membersJSON, err := marshalMembers(group.Members)
+	if err != nil {
+		return nil, helper.NewError("marshal members", err)
+	}
+
+	query := `
+		INSERT INTO manager_group (
+			external_id,
+			display_name,
+			members
+		) VALUES ($1, $2, $3)
+		RETURNING
+			id,
+			rid,
+			external_id,
+			display_name,
+			members,
+			created_at,
+			updated_at`
+
+	row := r.db.Instance.QueryRowContext(ctx, query, group.ExternalID, group.DisplayName, membersJSON)
+	insertedGroup, err := scanGroup(row)
+	if err != nil {
+		return nil, helper.NewError("insert group", err)
+	}
+
+	return insertedGroup, nil
+}
+
+// UpdateGroup updates the display name and members of a group by RID.
+func (r GroupDBHandler) UpdateGroup(group *model.Group) (*model.Group, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	membersJSON, err := marshalMembers(group.Members)
+	if err != nil {
+		return nil, helper.NewError("marshal members", err)
+	}
+
+	query := `
+		UPDATE manager_group
+		SET
+			external_id = $1,
+			display_name = $2,
+			members = $3,
+			updated_at = NOW()
+		WHERE rid = $4
+		RETURNING
+			id,
+			rid,
+			external_id,
+			display_name,
+			members,
+			created_at,
+			updated_at`
+
+	row := r.db.Instance.QueryRowContext(ctx, query, group.ExternalID, group.DisplayName, membersJSON, group.RID)
+	updatedGroup, err := scanGroup(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("group not found", fmt.Errorf("no group with rid %s", group.RID))
+		}
+		return nil, helper.NewError("update group", err)
+	}
+
+	return updatedGroup, nil
+}
+
+// DeleteGroup deletes a group by RID from the database.
+func (r GroupDBHandler) DeleteGroup(rid uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DELETE FROM manager_group WHERE rid = $1`
+	result, err := r.db.Instance.ExecContext(ctx, query, rid)
+	if err != nil {
+		return helper.NewError("delete group", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return helper.NewError("rows affected", err)
+	}
+	if rowsAffected == 0 {
+		return helper.NewError("group not found", fmt.Errorf("no group with rid %s", rid))
+	}
+
+	return nil
+}
+
+// SelectGroup retrieves a group by RID from the database.
+func (r GroupDBHandler) SelectGroup(rid uuid.UUID) (*model.Group, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			rid,
+			external_id,
+			display_name,
+			members,
+			created_at,
+			updated_at
+		FROM manager_group
+		WHERE rid = $1
+	`
+
+	group, err := scanGroup(r.db.Instance.QueryRowContext(ctx, query, rid))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("group not found", fmt.Errorf("no group with rid %s", rid))
+		}
+		return nil, helper.NewError("select group", err)
+	}
+
+	return group, nil
+}
+
+// SelectAllGroups retrieves all groups from the database with pagination.
+// lastID is the ID of the last group from the previous page (0 for first page)
+// entries is the maximum number of groups to return
+func (r GroupDBHandler) SelectAllGroups(lastID int, entries int) ([]*model.Group, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			rid,
+			external_id,
+			display_name,
+			members,
+			created_at,
+			updated_at
+		FROM manager_group
+		WHERE id > $1
+		ORDER BY id ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, lastID, entries)
+	if err != nil {
+		return nil, helper.NewError("select all groups", err)
+	}
+	defer rows.Close()
+
+	return scanGroups(rows)
+}
+
+// SelectGroupsByMember retrieves all groups the user is a member of.
+func (r GroupDBHandler) SelectGroupsByMember(userRID uuid.UUID) ([]*model.Group, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	memberJSON, err := json.Marshal([]uuid.UUID{userRID})
+	if err != nil {
+		return nil, helper.NewError("marshal member", err)
+	}
+
+	query := `
+		SELECT
+			id,
+			rid,
+			external_id,
+			display_name,
+			members,
+			created_at,
+			updated_at
+		FROM manager_group
+		WHERE members @> $1
+		ORDER BY id ASC
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, memberJSON)
+	if err != nil {
+		return nil, helper.NewError("select groups by member", err)
+	}
+	defer rows.Close()
+
+	return scanGroups(rows)
+}
+
+func marshalMembers(members []uuid.UUID) ([]byte, error) {
+	if members == nil {
+		members = []uuid.UUID{}
+	}
+	return json.Marshal(members)
+}
+
+// scanGroup scans a group row in the column order of the group queries.
+func scanGroup(row interface{ Scan(dest ...any) error }) (*model.Group, error) {
+	group := &model.Group{}
+	var membersData []byte
+	err := row.Scan(
+		&group.ID,
+		&group.RID,
+		&group.ExternalID,
+		&group.DisplayName,
+		&membersData,
+		&group.CreatedAt,
+		&group.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(membersData, &group.Members)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal members: %w", err)
+	}
+
+	return group, nil
+}
+
+func scanGroups(rows *sql.Rows) ([]*model.Group, error) {
+	groups := []*model.Group{}
+	for rows.Next() {
+		group, err := scanGroup(rows)
+		if err != nil {
+			return nil, helper.NewError("scan group", err)
+		}
+		groups = append(groups, group)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return groups, nil
+}
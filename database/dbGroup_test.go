@@ -0,0 +1,93 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupNewGroupDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewGroupDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		groupDbHandler, err := NewGroupDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewGroupDBHandler to not return an error")
+		require.NotNil(t, groupDbHandler, "Expected NewGroupDBHandler to return a non-nil instance")
+
+		exists, err := groupDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = groupDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewGroupDBHandler with nil database", func(t *testing.T) {
+		_, err := NewGroupDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating GroupDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestGroupInsertUpdateAndDeleteGroup(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	groupDbHandler, err := NewGroupDBHandler(database, true)
+	require.NoError(t, err, "Expected NewGroupDBHandler to not return an error")
+
+	member1 := uuid.New()
+	member2 := uuid.New()
+	insertedGroup, err := groupDbHandler.InsertGroup(&model.Group{
+		DisplayName: "queuer-operators",
+		Members:     []uuid.UUID{member1},
+	})
+	require.NoError(t, err, "Expected InsertGroup to not return an error")
+	assert.NotEqual(t, uuid.Nil, insertedGroup.RID)
+	assert.Equal(t, []uuid.UUID{member1}, insertedGroup.Members)
+
+	_, err = groupDbHandler.InsertGroup(&model.Group{DisplayName: "queuer-operators"})
+	assert.Error(t, err, "Expected InsertGroup to return an error for a duplicate display name")
+
+	insertedGroup.Members = []uuid.UUID{member1, member2}
+	updatedGroup, err := groupDbHandler.UpdateGroup(insertedGroup)
+	require.NoError(t, err, "Expected UpdateGroup to not return an error")
+	assert.Len(t, updatedGroup.Members, 2)
+
+	groups, err := groupDbHandler.SelectGroupsByMember(member2)
+	require.NoError(t, err, "Expected SelectGroupsByMember to not return an error")
+	require.Len(t, groups, 1)
+	assert.Equal(t, insertedGroup.RID, groups[0].RID)
+
+	groups, err = groupDbHandler.SelectGroupsByMember(uuid.New())
+	require.NoError(t, err, "Expected SelectGroupsByMember to not return an error")
+	assert.Empty(t, groups)
+
+	groups, err = groupDbHandler.SelectAllGroups(0, 10)
+	require.NoError(t, err, "Expected SelectAllGroups to not return an error")
+	assert.Len(t, groups, 1)
+
+	err = groupDbHandler.DeleteGroup(insertedGroup.RID)
+	require.NoError(t, err, "Expected DeleteGroup to not return an error")
+
+	_, err = groupDbHandler.SelectGroup(insertedGroup.RID)
+	assert.Error(t, err, "Expected SelectGroup to return an error for a deleted group")
+
+	err = groupDbHandler.DeleteGroup(insertedGroup.RID)
+	assert.Error(t, err, "Expected DeleteGroup to return an error for an unknown group")
+}
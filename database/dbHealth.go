@@ -0,0 +1,380 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+)
+
+// HealthDBHandlerFunctions defines the interface for the health history database operations.
+type HealthDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	InsertHealthCheck(check *model.HealthCheck) (*model.HealthCheck, error)
+	SelectHealthDays(since time.Time) (map[string][]*model.HealthDay, error)
+	DeleteHealthChecksBefore(before time.Time) (int64, error)
+	InsertIncident(incident *model.Incident) (*model.Incident, error)
+	UpdateIncidentResolved(rid uuid.UUID, resolvedAt time.Time) (*model.Incident, error)
+	DeleteIncident(rid uuid.UUID) error
+	SelectIncidents(since time.Time) ([]*model.Incident, error)
+}
+
+// HealthDBHandler implements HealthDBHandlerFunctions and holds the database connection.
+type HealthDBHandler struct {
+	db *helper.Database
+}
+
+// NewHealthDBHandler creates a new instance of HealthDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing health_check and health_incident tables before creating new ones
+func NewHealthDBHandler(dbConnection *helper.Database, withTableDrop bool) (*HealthDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	healthDbHandler := &HealthDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := healthDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := healthDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return healthDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'health_check' and 'health_incident' tables exist in the database.
+// It returns true if both tables exist, otherwise false.
+func (r HealthDBHandler) CheckTableExistance() (bool, error) {
+	healthCheckExists, err := r.db.CheckTableExistance("health_check")
+	if err != nil {
+		return false, helper.NewError("health_check table", err)
+	}
+	healthIncidentExists, err := r.db.CheckTableExistance("health_incident")
+	if err != nil {
+		return false, helper.NewError("health_incident table", err)
+	}
+	return healthCheckExists && healthIncidentExists, nil
+}
+
+// CreateTable creates the 'health_check' and 'health_incident' tables in the database.
+// If the tables already exist, it does not create them again.
+func (r HealthDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS health_check (
+			id SERIAL PRIMARY KEY,
+			subsystem VARCHAR(100) NOT NULL,
+			status VARCHAR(20) NOT NULL,
+			message TEXT NOT NULL DEFAULT '',
+			checked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_health_check_checked_at ON health_check(checked_at);
+
+		CREATE TABLE IF NOT EXISTS health_incident (
+			id SERIAL PRIMARY KEY,
+			rid UUID UNIQUE NOT NULL DEFAULT gen_random_uuid(),
+			subsystem VARCHAR(100) NOT NULL DEFAULT '',
+			title VARCHAR(200) NOT NULL,
+			description TEXT NOT NULL DEFAULT '',
+			started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			resolved_at TIMESTAMP WITH TIME ZONE,
+			created_by VARCHAR(255) NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create health tables", err)
+	}
+
+	r.db.Logger.Info("Checked/created tables health_check and health_incident")
+
+	return nil
+}
+
+// DropTable drops the 'health_check' and 'health_incident' tables from the database.
+func (r HealthDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS health_check; DROP TABLE IF EXISTS health_incident`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop health tables", err)
+	}
+
+	r.db.Logger.Info("Dropped tables health_check and health_incident")
+
+	return nil
+}
+
+// InsertHealthCheck inserts a check of a subsystem into the health history.
+func (r HealthDBHandler) InsertHealthCheck(check *model.HealthCheck) (*model.HealthCheck, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	newCheck := &model.HealthCheck{}
+	query := `
+		INSERT INTO health_check (subsystem, status, message)
+		VALUES ($1, $2, $3)
+		RETURNING id, subsystem, status, message, checked_at`
+
+	err := r.db.Instance.QueryRowContext(ctx, query, check.Subsystem, check.Status, check.Message).Scan(
+		&newCheck.ID,
+		&newCheck.Subsystem,
+		&newCheck.Status,
+		&newCheck.Message,
+		&newCheck.CheckedAt,
+	)
+	if err != nil {
+		return nil, helper.NewError("insert health check", err)
+	}
+
+	return newCheck, nil
+}
+
+// SelectHealthDays counts the checks since the time per subsystem and day (UTC) by their status.
+// The days of a subsystem are ordered oldest first, days without checks are not returned.
+func (r HealthDBHandler) SelectHealthDays(since time.Time) (map[string][]*model.HealthDay, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			subsystem,
+			date_trunc('day', checked_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS day,
+			COUNT(*)::INT,
+			COUNT(*) FILTER (WHERE status = $2)::INT,
+			COUNT(*) FILTER (WHERE status = $3)::INT
+		FROM health_check
+		WHERE checked_at >= $1
+		GROUP BY subsystem, day
+		ORDER BY subsystem ASC, day ASC
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, since, model.STATUS_DEGRADED, model.STATUS_DOWN)
+	if err != nil {
+		return nil, helper.NewError("select health days", err)
+	}
+	defer rows.Close()
+
+	days := map[string][]*model.HealthDay{}
+	for rows.Next() {
+		var subsystem string
+		day := &model.HealthDay{}
+		err := rows.Scan(
+			&subsystem,
+			&day.Day,
+			&day.Checks,
+			&day.Degraded,
+			&day.Down,
+		)
+		if err != nil {
+			return nil, helper.NewError("scan health day", err)
+		}
+		days[subsystem] = append(days[subsystem], day)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return days, nil
+}
+
+// DeleteHealthChecksBefore deletes the checks older than the time and returns the number of deleted checks.
+func (r HealthDBHandler) DeleteHealthChecksBefore(before time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DELETE FROM health_check WHERE checked_at < $1`
+	result, err := r.db.Instance.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, helper.NewError("delete health checks", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, helper.NewError("rows affected", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// InsertIncident inserts a new incident into the database, a zero start time starts it now.
+func (r HealthDBHandler) InsertIncident(incident *model.Incident) (*model.Incident, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var startedAt *time.Time
+	if !incident.StartedAt.IsZero() {
+		startedAt = &incident.StartedAt
+	}
+
+	query := `
+		INSERT INTO health_incident (
+			subsystem,
+			title,
+			description,
+			started_at,
+			created_by
+		) VALUES ($1, $2, $3, COALESCE($4, NOW()), $5)
+		RETURNING
+			id,
+			rid,
+			subsystem,
+			title,
+			description,
+			started_at,
+			resolved_at,
+			created_by,
+			created_at`
+
+	row := r.db.Instance.QueryRowContext(
+		ctx,
+		query,
+		incident.Subsystem,
+		incident.Title,
+		incident.Description,
+		startedAt,
+		incident.CreatedBy,
+	)
+	newIncident, err := scanIncident(row)
+	if err != nil {
+		return nil, helper.NewError("insert incident", err)
+	}
+
+	return newIncident, nil
+}
+
+// UpdateIncidentResolved resolves the incident with the RID at the time.
+func (r HealthDBHandler) UpdateIncidentResolved(rid uuid.UUID, resolvedAt time.Time) (*model.Incident, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE health_incident
+		SET resolved_at = $2
+		WHERE rid = $1
+		RETURNING
+			id,
+			rid,
+			subsystem,
+			title,
+			description,
+			started_at,
+			resolved_at,
+			created_by,
+			created_at`
+
+	incident, err := scanIncident(r.db.Instance.QueryRowContext(ctx, query, rid, resolvedAt))
+	if err != nil {
+		return nil, helper.NewError("update incident resolved", err)
+	}
+
+	return incident, nil
+}
+
+// DeleteIncident deletes the incident with the RID from the database.
+func (r HealthDBHandler) DeleteIncident(rid uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DELETE FROM health_incident WHERE rid = $1`
+	result, err := r.db.Instance.ExecContext(ctx, query, rid)
+	if err != nil {
+		return helper.NewError("delete incident", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return helper.NewError("rows affected", err)
+	}
+	if rowsAffected == 0 {
+		return helper.NewError("incident not found", fmt.Errorf("no incident with rid %s", rid))
+	}
+
+	return nil
+}
+
+// SelectIncidents retrieves the incidents that started since the time or are still ongoing, newest first.
+func (r HealthDBHandler) SelectIncidents(since time.Time) ([]*model.Incident, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			rid,
+			subsystem,
+			title,
+			description,
+			started_at,
+			resolved_at,
+			created_by,
+			created_at
+		FROM health_incident
+		WHERE started_at >= $1 OR resolved_at IS NULL OR resolved_at >= $1
+		ORDER BY started_at DESC
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, since)
+	if err != nil {
+		return nil, helper.NewError("select incidents", err)
+	}
+	defer rows.Close()
+
+	incidents := []*model.Incident{}
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, helper.NewError("scan incident", err)
+		}
+		incidents = append(incidents, incident)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return incidents, nil
+}
+
+// scanIncident scans an incident from a row with the columns of the incident table.
+func scanIncident(row interface{ Scan(dest ...any) error }) (*model.Incident, error) {
+	incident := &model.Incident{}
+	err := row.Scan(
+		&incident.ID,
+		&incident.RID,
+		&incident.Subsystem,
+		&incident.Title,
+		&incident.Description,
+		&incident.StartedAt,
+		&incident.ResolvedAt,
+		&incident.CreatedBy,
+		&incident.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return incident, nil
+}
@@ -0,0 +1,137 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthNewHealthDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewHealthDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		healthDbHandler, err := NewHealthDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewHealthDBHandler to not return an error")
+		require.NotNil(t, healthDbHandler, "Expected NewHealthDBHandler to return a non-nil instance")
+
+		exists, err := healthDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = healthDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewHealthDBHandler with nil database", func(t *testing.T) {
+		_, err := NewHealthDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating HealthDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestHealthChecks(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	healthDbHandler, err := NewHealthDBHandler(database, true)
+	require.NoError(t, err, "Expected NewHealthDBHandler to not return an error")
+
+	for _, status := range []string{model.STATUS_OK, model.STATUS_OK, model.STATUS_DEGRADED, model.STATUS_DOWN} {
+		check, err := healthDbHandler.InsertHealthCheck(&model.HealthCheck{Subsystem: model.SUBSYSTEM_DATABASE, Status: status})
+		require.NoError(t, err, "Expected InsertHealthCheck to not return an error")
+		assert.Equal(t, status, check.Status)
+	}
+	_, err = database.Instance.Exec(`INSERT INTO health_check (subsystem, status, checked_at) VALUES ($1, $2, NOW() - INTERVAL '100 days')`, model.SUBSYSTEM_STORAGE, model.STATUS_OK)
+	require.NoError(t, err)
+
+	t.Run("Select health days", func(t *testing.T) {
+		days, err := healthDbHandler.SelectHealthDays(time.Now().Add(-24 * time.Hour))
+		assert.NoError(t, err, "Expected SelectHealthDays to not return an error")
+		require.NotEmpty(t, days[model.SUBSYSTEM_DATABASE], "Expected the checks of the database to be counted")
+		assert.NotContains(t, days, model.SUBSYSTEM_STORAGE, "Expected checks before since to not be counted")
+
+		checks, degraded, down := 0, 0, 0
+		for _, day := range days[model.SUBSYSTEM_DATABASE] {
+			assert.Equal(t, day.Day.UTC().Truncate(24*time.Hour), day.Day.UTC(), "Expected days to start at midnight UTC")
+			checks += day.Checks
+			degraded += day.Degraded
+			down += day.Down
+		}
+		assert.Equal(t, 4, checks)
+		assert.Equal(t, 1, degraded)
+		assert.Equal(t, 1, down)
+	})
+
+	t.Run("Delete health checks before", func(t *testing.T) {
+		deleted, err := healthDbHandler.DeleteHealthChecksBefore(time.Now().AddDate(0, 0, -model.HEALTH_HISTORY_DAYS))
+		assert.NoError(t, err, "Expected DeleteHealthChecksBefore to not return an error")
+		assert.Equal(t, int64(1), deleted, "Expected only the check older than the history to be deleted")
+	})
+}
+
+func TestHealthIncidents(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	healthDbHandler, err := NewHealthDBHandler(database, true)
+	require.NoError(t, err, "Expected NewHealthDBHandler to not return an error")
+
+	incident, err := healthDbHandler.InsertIncident(&model.Incident{
+		Subsystem:   model.SUBSYSTEM_DATABASE,
+		Title:       "Database failover",
+		Description: "Connections dropped during the failover",
+		CreatedBy:   "admin",
+	})
+	require.NoError(t, err, "Expected InsertIncident to not return an error")
+	assert.NotEqual(t, uuid.Nil, incident.RID)
+	assert.False(t, incident.StartedAt.IsZero(), "Expected the incident to start now without a start time")
+	assert.True(t, incident.Ongoing())
+
+	oldStart := time.Now().AddDate(0, 0, -200)
+	oldIncident, err := healthDbHandler.InsertIncident(&model.Incident{Title: "Old outage", StartedAt: oldStart})
+	require.NoError(t, err)
+	_, err = healthDbHandler.UpdateIncidentResolved(oldIncident.RID, oldStart.Add(time.Hour))
+	require.NoError(t, err)
+
+	t.Run("Select incidents", func(t *testing.T) {
+		incidents, err := healthDbHandler.SelectIncidents(time.Now().AddDate(0, 0, -model.HEALTH_HISTORY_DAYS))
+		assert.NoError(t, err, "Expected SelectIncidents to not return an error")
+		require.Len(t, incidents, 1, "Expected only the incident of the history")
+		assert.Equal(t, incident.RID, incidents[0].RID)
+	})
+
+	t.Run("Resolve incident", func(t *testing.T) {
+		resolvedIncident, err := healthDbHandler.UpdateIncidentResolved(incident.RID, time.Now())
+		assert.NoError(t, err, "Expected UpdateIncidentResolved to not return an error")
+		require.NotNil(t, resolvedIncident.ResolvedAt)
+		assert.False(t, resolvedIncident.Ongoing())
+	})
+
+	t.Run("Delete incident", func(t *testing.T) {
+		err := healthDbHandler.DeleteIncident(incident.RID)
+		assert.NoError(t, err, "Expected DeleteIncident to not return an error")
+
+		err = healthDbHandler.DeleteIncident(incident.RID)
+		assert.Error(t, err, "Expected deleting a deleted incident to return an error")
+	})
+}
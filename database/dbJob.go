@@ -0,0 +1,231 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+)
+
+// JobDBHandlerFunctions defines the interface for the filter queries of the jobs in the queue.
+type JobDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	SelectJobRIDsByFilter(filter *model.JobFilter, lastID int, entries int) ([]uuid.UUID, error)
+	SelectJobsCountByFilter(filter *model.JobFilter) (int, error)
+	SelectScheduledJobRIDs(namespace string, now time.Time, lastID int, entries int) ([]uuid.UUID, error)
+	SelectTaskUtilization(taskKey string, since time.Time) (*model.TaskUtilization, error)
+}
+
+// JobDBHandler implements JobDBHandlerFunctions and holds the database connection.
+// The job table is owned by the queuer, so the handler does not create or drop it.
+type JobDBHandler struct {
+	db *helper.Database
+}
+
+// NewJobDBHandler creates a new instance of JobDBHandler.
+func NewJobDBHandler(dbConnection *helper.Database) (*JobDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	return &JobDBHandler{
+		db: dbConnection,
+	}, nil
+}
+
+// CheckTableExistance checks if the 'job' table of the queuer exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r JobDBHandler) CheckTableExistance() (bool, error) {
+	jobExists, err := r.db.CheckTableExistance("job")
+	if err != nil {
+		return false, helper.NewError("job table", err)
+	}
+	return jobExists, nil
+}
+
+// jobFilterTime returns a time of the filter as query parameter, the timestamps of the job table have no time zone and are in UTC.
+func jobFilterTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t.UTC(), Valid: !t.IsZero()}
+}
+
+// SelectJobRIDsByFilter retrieves the RIDs of the jobs in the queue matching the filter with pagination, newest first.
+// Only the RIDs are selected because the parameters and results of jobs can be encrypted by the queuer.
+// The status and creation time are covered by the indexes of the queuer on the job table.
+// lastID is the ID of the last job from the previous page (0 for first page)
+// entries is the maximum number of entries to return
+func (r JobDBHandler) SelectJobRIDsByFilter(filter *model.JobFilter, lastID int, entries int) ([]uuid.UUID, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT job.rid
+		FROM job
+		WHERE ($1 = ''
+				OR job.rid::text ILIKE '%' || $1 || '%'
+				OR job.worker_id::text ILIKE '%' || $1 || '%'
+				OR job.task_name ILIKE '%' || $1 || '%'
+				OR job.status ILIKE '%' || $1 || '%')
+			AND ($2 = '' OR job.status = $2)
+			AND ($3 = '' OR job.task_name = $3)
+			AND ($4::TIMESTAMP IS NULL OR job.created_at >= $4)
+			AND ($5::TIMESTAMP IS NULL OR job.created_at < $5)
+			AND ($8 = '' OR job.task_name IN (SELECT task.key FROM task WHERE task.namespace = $8))
+			AND ($6 = 0
+				OR job.created_at < (
+					SELECT d.created_at
+					FROM job AS d
+					WHERE d.id = $6))
+		ORDER BY job.created_at DESC
+		LIMIT $7
+	`
+
+	rows, err := r.db.Instance.QueryContext(
+		ctx,
+		query,
+		filter.Search,
+		filter.Status,
+		filter.TaskKey,
+		jobFilterTime(filter.From),
+		jobFilterTime(filter.To),
+		lastID,
+		entries,
+		filter.Namespace,
+	)
+	if err != nil {
+		return nil, helper.NewError("select job rids by filter", err)
+	}
+	defer rows.Close()
+
+	rids := []uuid.UUID{}
+	for rows.Next() {
+		var rid uuid.UUID
+		if err := rows.Scan(&rid); err != nil {
+			return nil, helper.NewError("scan job rid", err)
+		}
+		rids = append(rids, rid)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return rids, nil
+}
+
+// SelectJobsCountByFilter counts the jobs in the queue matching the filter.
+func (r JobDBHandler) SelectJobsCountByFilter(filter *model.JobFilter) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT COUNT(*)
+		FROM job
+		WHERE ($1 = ''
+				OR job.rid::text ILIKE '%' || $1 || '%'
+				OR job.worker_id::text ILIKE '%' || $1 || '%'
+				OR job.task_name ILIKE '%' || $1 || '%'
+				OR job.status ILIKE '%' || $1 || '%')
+			AND ($2 = '' OR job.status = $2)
+			AND ($3 = '' OR job.task_name = $3)
+			AND ($4::TIMESTAMP IS NULL OR job.created_at >= $4)
+			AND ($5::TIMESTAMP IS NULL OR job.created_at < $5)
+			AND ($6 = '' OR job.task_name IN (SELECT task.key FROM task WHERE task.namespace = $6))
+	`
+
+	var count int
+	err := r.db.Instance.QueryRowContext(
+		ctx,
+		query,
+		filter.Search,
+		filter.Status,
+		filter.TaskKey,
+		jobFilterTime(filter.From),
+		jobFilterTime(filter.To),
+		filter.Namespace,
+	).Scan(&count)
+	if err != nil {
+		return 0, helper.NewError("count jobs by filter", err)
+	}
+
+	return count, nil
+}
+
+// SelectScheduledJobRIDs retrieves the RIDs of the pending scheduled jobs with pagination, the next job to run first.
+// Pending are the jobs added with a schedule that did not start yet, workers claim scheduled jobs shortly before their time,
+// so claimed jobs waiting for their time are running already and still pending.
+// lastID is the ID of the last job from the previous page (0 for first page)
+// entries is the maximum number of entries to return
+func (r JobDBHandler) SelectScheduledJobRIDs(namespace string, now time.Time, lastID int, entries int) ([]uuid.UUID, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT job.rid
+		FROM job
+		WHERE job.status IN ('SCHEDULED', 'RUNNING')
+			AND job.options->'schedule' IS NOT NULL
+			AND job.scheduled_at > $1
+			AND ($2 = '' OR job.task_name IN (SELECT task.key FROM task WHERE task.namespace = $2))
+			AND ($3 = 0
+				OR (job.scheduled_at, job.id) > (
+					SELECT d.scheduled_at, d.id
+					FROM job AS d
+					WHERE d.id = $3))
+		ORDER BY job.scheduled_at ASC, job.id ASC
+		LIMIT $4
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, jobFilterTime(now), namespace, lastID, entries)
+	if err != nil {
+		return nil, helper.NewError("select scheduled job rids", err)
+	}
+	defer rows.Close()
+
+	rids := []uuid.UUID{}
+	for rows.Next() {
+		var rid uuid.UUID
+		if err := rows.Scan(&rid); err != nil {
+			return nil, helper.NewError("scan job rid", err)
+		}
+		rids = append(rids, rid)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return rids, nil
+}
+
+// SelectTaskUtilization counts the running and active (queued, scheduled or running) jobs of the task in the queue
+// and the jobs of the task added since the time, including the ended jobs in the archive of the queuer.
+// The limits of the utilization are not set, they are fields of the task.
+func (r JobDBHandler) SelectTaskUtilization(taskKey string, since time.Time) (*model.TaskUtilization, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM job WHERE job.task_name = $1 AND job.status = 'RUNNING'),
+			(SELECT COUNT(*) FROM job WHERE job.task_name = $1 AND job.status IN ('QUEUED', 'SCHEDULED', 'RUNNING')),
+			(SELECT COUNT(*) FROM job WHERE job.task_name = $1 AND job.created_at >= $2)
+				+ (SELECT COUNT(*) FROM job_archive WHERE job_archive.task_name = $1 AND job_archive.created_at >= $2)
+	`
+
+	utilization := &model.TaskUtilization{TaskKey: taskKey}
+	err := r.db.Instance.QueryRowContext(ctx, query, taskKey, jobFilterTime(since)).Scan(
+		&utilization.Running,
+		&utilization.Active,
+		&utilization.Added,
+	)
+	if err != nil {
+		return nil, helper.NewError("select task utilization", err)
+	}
+
+	return utilization, nil
+}
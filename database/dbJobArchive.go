@@ -0,0 +1,468 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	qm "github.com/siherrmann/queuer/model"
+)
+
+// JobArchiveDBHandlerFunctions defines the interface for the filter queries of the job archive.
+type JobArchiveDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	SelectJobRIDsByFilter(filter *model.JobArchiveFilter, lastID int, entries int) ([]uuid.UUID, error)
+	SelectJobRIDsByTask(taskKey string, from time.Time, to time.Time, lastRID uuid.UUID, entries int) ([]uuid.UUID, error)
+	SelectJobsCountByFilter(filter *model.JobArchiveFilter) (int, error)
+	SelectFailureGroups(taskKey string, namespace string, from time.Time, to time.Time, entries int) ([]*model.FailureGroup, error)
+	SelectPartitionKey() (string, error)
+	SelectPartitions() ([]*model.JobArchivePartition, error)
+	CreateMonthlyPartition(month time.Time) (*model.JobArchivePartition, error)
+	DropPartition(name string) error
+	SelectTableIndexes(tables []string) ([]*model.TableIndex, error)
+}
+
+// jobArchivePartitionName matches the names of the monthly partitions created by the manager
+var jobArchivePartitionName = regexp.MustCompile(`^` + model.JOB_ARCHIVE_PARTITION_PREFIX + `\d{4}_\d{2}$`)
+
+// jobArchivePartitionBound matches the range bound of a partition, eg. FOR VALUES FROM ('2026-01-01 00:00:00') TO ('2026-02-01 00:00:00')
+var jobArchivePartitionBound = regexp.MustCompile(`FROM \((?:'([^']*)'|MINVALUE)\) TO \((?:'([^']*)'|MAXVALUE)\)`)
+
+// JobArchiveDBHandler implements JobArchiveDBHandlerFunctions and holds the database connection.
+// The job_archive table is owned by the queuer, so the handler does not create or drop it.
+type JobArchiveDBHandler struct {
+	db *helper.Database
+}
+
+// NewJobArchiveDBHandler creates a new instance of JobArchiveDBHandler.
+func NewJobArchiveDBHandler(dbConnection *helper.Database) (*JobArchiveDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	return &JobArchiveDBHandler{
+		db: dbConnection,
+	}, nil
+}
+
+// CheckTableExistance checks if the 'job_archive' table of the queuer exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r JobArchiveDBHandler) CheckTableExistance() (bool, error) {
+	jobArchiveExists, err := r.db.CheckTableExistance("job_archive")
+	if err != nil {
+		return false, helper.NewError("job_archive table", err)
+	}
+	return jobArchiveExists, nil
+}
+
+// SelectJobRIDsByFilter retrieves the RIDs of the archived jobs matching the filter with pagination, newest first.
+// Only the RIDs are selected because the parameters and results of archived jobs can be encrypted by the queuer.
+// lastID is the ID of the last job from the previous page (0 for first page)
+// entries is the maximum number of entries to return
+func (r JobArchiveDBHandler) SelectJobRIDsByFilter(filter *model.JobArchiveFilter, lastID int, entries int) ([]uuid.UUID, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT job_archive.rid
+		FROM job_archive
+		WHERE ($1 = ''
+				OR job_archive.rid::text ILIKE '%' || $1 || '%'
+				OR job_archive.worker_id::text ILIKE '%' || $1 || '%'
+				OR job_archive.task_name ILIKE '%' || $1 || '%'
+				OR job_archive.status ILIKE '%' || $1 || '%')
+			AND ($2 = '' OR job_archive.status = $2)
+			AND ($3 = '' OR job_archive.task_name = $3)
+			AND ($4::UUID IS NULL OR job_archive.worker_rid = $4)
+			AND ($5::FLOAT8 = 0 OR extract(epoch FROM job_archive.updated_at - job_archive.started_at) >= $5)
+			AND ($6::FLOAT8 = 0 OR extract(epoch FROM job_archive.updated_at - job_archive.started_at) <= $6)
+			AND ($7 = 0
+				OR job_archive.created_at < (
+					SELECT u.created_at
+					FROM job_archive AS u
+					WHERE u.id = $7))
+			AND ($9 = '' OR job_archive.error = $9)
+			AND ($10 = '' OR job_archive.task_name IN (SELECT task.key FROM task WHERE task.namespace = $10))
+		ORDER BY job_archive.created_at DESC
+		LIMIT $8
+	`
+
+	rows, err := r.db.Instance.QueryContext(
+		ctx,
+		query,
+		filter.Search,
+		filter.Status,
+		filter.TaskKey,
+		uuid.NullUUID{UUID: filter.WorkerRID, Valid: filter.WorkerRID != uuid.Nil},
+		filter.MinDuration.Seconds(),
+		filter.MaxDuration.Seconds(),
+		lastID,
+		entries,
+		filter.Error,
+		filter.Namespace,
+	)
+	if err != nil {
+		return nil, helper.NewError("select job rids by filter", err)
+	}
+	defer rows.Close()
+
+	rids := []uuid.UUID{}
+	for rows.Next() {
+		var rid uuid.UUID
+		if err := rows.Scan(&rid); err != nil {
+			return nil, helper.NewError("scan job rid", err)
+		}
+		rids = append(rids, rid)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return rids, nil
+}
+
+// SelectJobRIDsByTask retrieves the RIDs of the archived jobs of the task (of all tasks if taskKey is empty)
+// that ended in the time range [from, to), oldest first.
+// lastRID is the RID of the last job from the previous page (uuid.Nil for first page)
+// entries is the maximum number of entries to return
+func (r JobArchiveDBHandler) SelectJobRIDsByTask(taskKey string, from time.Time, to time.Time, lastRID uuid.UUID, entries int) ([]uuid.UUID, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT job_archive.rid
+		FROM job_archive
+		WHERE ($1 = '' OR job_archive.task_name = $1)
+			AND job_archive.updated_at >= $2
+			AND job_archive.updated_at < $3
+			AND ($4::UUID IS NULL
+				OR (job_archive.updated_at, job_archive.id) > (
+					SELECT u.updated_at, u.id
+					FROM job_archive AS u
+					WHERE u.rid = $4))
+		ORDER BY job_archive.updated_at ASC, job_archive.id ASC
+		LIMIT $5
+	`
+
+	rows, err := r.db.Instance.QueryContext(
+		ctx,
+		query,
+		taskKey,
+		from,
+		to,
+		uuid.NullUUID{UUID: lastRID, Valid: lastRID != uuid.Nil},
+		entries,
+	)
+	if err != nil {
+		return nil, helper.NewError("select job rids by task", err)
+	}
+	defer rows.Close()
+
+	rids := []uuid.UUID{}
+	for rows.Next() {
+		var rid uuid.UUID
+		if err := rows.Scan(&rid); err != nil {
+			return nil, helper.NewError("scan job rid", err)
+		}
+		rids = append(rids, rid)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return rids, nil
+}
+
+// SelectJobsCountByFilter counts the archived jobs matching the filter.
+func (r JobArchiveDBHandler) SelectJobsCountByFilter(filter *model.JobArchiveFilter) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT COUNT(*)
+		FROM job_archive
+		WHERE ($1 = ''
+				OR job_archive.rid::text ILIKE '%' || $1 || '%'
+				OR job_archive.worker_id::text ILIKE '%' || $1 || '%'
+				OR job_archive.task_name ILIKE '%' || $1 || '%'
+				OR job_archive.status ILIKE '%' || $1 || '%')
+			AND ($2 = '' OR job_archive.status = $2)
+			AND ($3 = '' OR job_archive.task_name = $3)
+			AND ($4::UUID IS NULL OR job_archive.worker_rid = $4)
+			AND ($5::FLOAT8 = 0 OR extract(epoch FROM job_archive.updated_at - job_archive.started_at) >= $5)
+			AND ($6::FLOAT8 = 0 OR extract(epoch FROM job_archive.updated_at - job_archive.started_at) <= $6)
+			AND ($7 = '' OR job_archive.error = $7)
+			AND ($8 = '' OR job_archive.task_name IN (SELECT task.key FROM task WHERE task.namespace = $8))
+	`
+
+	var count int
+	err := r.db.Instance.QueryRowContext(
+		ctx,
+		query,
+		filter.Search,
+		filter.Status,
+		filter.TaskKey,
+		uuid.NullUUID{UUID: filter.WorkerRID, Valid: filter.WorkerRID != uuid.Nil},
+		filter.MinDuration.Seconds(),
+		filter.MaxDuration.Seconds(),
+		filter.Error,
+		filter.Namespace,
+	).Scan(&count)
+	if err != nil {
+		return 0, helper.NewError("count jobs by filter", err)
+	}
+
+	return count, nil
+}
+
+// SelectFailureGroups retrieves the failed archived jobs of the task (of all tasks if taskKey is empty)
+// that ended in the time range [from, to), grouped by task and error message.
+// The groups with the most failures come first, groups with the same count by their last occurrence.
+// namespace is the namespace of the tasks (all namespaces if empty)
+// entries is the maximum number of groups to return
+func (r JobArchiveDBHandler) SelectFailureGroups(taskKey string, namespace string, from time.Time, to time.Time, entries int) ([]*model.FailureGroup, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			job_archive.task_name,
+			COALESCE(job_archive.error, ''),
+			COUNT(*),
+			MIN(job_archive.updated_at),
+			MAX(job_archive.updated_at),
+			(ARRAY_AGG(job_archive.rid ORDER BY job_archive.updated_at DESC, job_archive.id DESC))[1]
+		FROM job_archive
+		WHERE job_archive.status = $1
+			AND ($2 = '' OR job_archive.task_name = $2)
+			AND job_archive.updated_at >= $3
+			AND job_archive.updated_at < $4
+			AND ($6 = '' OR job_archive.task_name IN (SELECT task.key FROM task WHERE task.namespace = $6))
+		GROUP BY job_archive.task_name, COALESCE(job_archive.error, '')
+		ORDER BY COUNT(*) DESC, MAX(job_archive.updated_at) DESC
+		LIMIT $5
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, qm.JobStatusFailed, taskKey, from, to, entries, namespace)
+	if err != nil {
+		return nil, helper.NewError("select failure groups", err)
+	}
+	defer rows.Close()
+
+	groups := []*model.FailureGroup{}
+	for rows.Next() {
+		group := &model.FailureGroup{}
+		err := rows.Scan(
+			&group.TaskKey,
+			&group.Error,
+			&group.Count,
+			&group.FirstOccurrence,
+			&group.LastOccurrence,
+			&group.LastJobRID,
+		)
+		if err != nil {
+			return nil, helper.NewError("scan failure group", err)
+		}
+		groups = append(groups, group)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return groups, nil
+}
+
+// SelectPartitionKey retrieves the partition key of the job archive, eg. "RANGE (updated_at)".
+// It returns an empty key if the job archive is not partitioned.
+func (r JobArchiveDBHandler) SelectPartitionKey() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var partitionKey sql.NullString
+	err := r.db.Instance.QueryRowContext(ctx, `SELECT pg_get_partkeydef('job_archive'::regclass)`).Scan(&partitionKey)
+	if err != nil {
+		return "", helper.NewError("select partition key", err)
+	}
+	return partitionKey.String, nil
+}
+
+// SelectPartitions retrieves the partitions of the job archive ordered by name.
+// The rows are the estimate of the last analyze, so the report does not have to count large partitions.
+func (r JobArchiveDBHandler) SelectPartitions() ([]*model.JobArchivePartition, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			c.relname,
+			pg_get_expr(c.relpartbound, c.oid),
+			GREATEST(c.reltuples, 0)::BIGINT,
+			pg_total_relation_size(c.oid)
+		FROM pg_inherits AS i
+		JOIN pg_class AS c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'job_archive'::regclass
+		ORDER BY c.relname
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query)
+	if err != nil {
+		return nil, helper.NewError("select partitions", err)
+	}
+	defer rows.Close()
+
+	partitions := []*model.JobArchivePartition{}
+	for rows.Next() {
+		partition := &model.JobArchivePartition{}
+		var bound sql.NullString
+		if err := rows.Scan(&partition.Name, &bound, &partition.Rows, &partition.SizeBytes); err != nil {
+			return nil, helper.NewError("scan partition", err)
+		}
+		partition.From, partition.To = partitionBounds(bound.String)
+		partitions = append(partitions, partition)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return partitions, nil
+}
+
+// partitionBounds parses the range of a partition bound expression, the bounds are zero if they are unbounded or not a range.
+func partitionBounds(bound string) (time.Time, time.Time) {
+	match := jobArchivePartitionBound.FindStringSubmatch(bound)
+	if match == nil {
+		return time.Time{}, time.Time{}
+	}
+	return partitionBoundTime(match[1]), partitionBoundTime(match[2])
+}
+
+// partitionBoundTime parses a timestamp of a partition bound with or without time zone.
+func partitionBoundTime(value string) time.Time {
+	for _, layout := range []string{"2006-01-02 15:04:05-07", "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
+
+// CreateMonthlyPartition creates the partition of the job archive for the month of the time, eg. job_archive_2026_01.
+// The job archive has to be range partitioned by a timestamp, an existing partition of the month is kept.
+func (r JobArchiveDBHandler) CreateMonthlyPartition(month time.Time) (*model.JobArchivePartition, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	from := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	partition := &model.JobArchivePartition{
+		Name: model.JOB_ARCHIVE_PARTITION_PREFIX + from.Format("2006_01"),
+		From: from,
+		To:   from.AddDate(0, 1, 0),
+	}
+
+	// Identifiers and bounds can not be query parameters, both are formatted from the month only
+	query := fmt.Sprintf(
+		`CREATE TABLE IF NOT EXISTS %s PARTITION OF job_archive FOR VALUES FROM ('%s') TO ('%s')`,
+		partition.Name,
+		partition.From.Format("2006-01-02"),
+		partition.To.Format("2006-01-02"),
+	)
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return nil, helper.NewError("create partition", err)
+	}
+
+	return partition, nil
+}
+
+// DropPartition drops a monthly partition of the job archive with all its archived jobs.
+// Only partitions named like the partitions created by CreateMonthlyPartition can be dropped.
+func (r JobArchiveDBHandler) DropPartition(name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if !jobArchivePartitionName.MatchString(name) {
+		return helper.NewError("partition name validation", fmt.Errorf("invalid partition name %q", name))
+	}
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM pg_inherits AS i
+			JOIN pg_class AS c ON c.oid = i.inhrelid
+			WHERE i.inhparent = 'job_archive'::regclass
+				AND c.relname = $1)
+	`
+
+	var exists bool
+	err := r.db.Instance.QueryRowContext(ctx, query, name).Scan(&exists)
+	if err != nil {
+		return helper.NewError("select partition", err)
+	}
+	if !exists {
+		return helper.NewError("partition validation", fmt.Errorf("%s is no partition of the job archive", name))
+	}
+
+	_, err = r.db.Instance.ExecContext(ctx, fmt.Sprintf(`DROP TABLE %s`, name))
+	if err != nil {
+		return helper.NewError("drop partition", err)
+	}
+
+	return nil
+}
+
+// SelectTableIndexes retrieves the indexes of the tables with their columns in index order.
+func (r JobArchiveDBHandler) SelectTableIndexes(tables []string) ([]*model.TableIndex, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			t.relname,
+			i.relname,
+			array_to_string(array_agg(COALESCE(a.attname, '') ORDER BY k.ord), ',')
+		FROM pg_index AS x
+		JOIN pg_class AS i ON i.oid = x.indexrelid
+		JOIN pg_class AS t ON t.oid = x.indrelid
+		CROSS JOIN LATERAL unnest(x.indkey::SMALLINT[]) WITH ORDINALITY AS k(attnum, ord)
+		LEFT JOIN pg_attribute AS a ON a.attrelid = t.oid AND a.attnum = k.attnum
+		WHERE t.relname = ANY(string_to_array($1, ','))
+			AND pg_table_is_visible(t.oid)
+		GROUP BY t.relname, i.relname
+		ORDER BY t.relname, i.relname
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, strings.Join(tables, ","))
+	if err != nil {
+		return nil, helper.NewError("select table indexes", err)
+	}
+	defer rows.Close()
+
+	indexes := []*model.TableIndex{}
+	for rows.Next() {
+		index := &model.TableIndex{}
+		var columns string
+		if err := rows.Scan(&index.Table, &index.Name, &columns); err != nil {
+			return nil, helper.NewError("scan table index", err)
+		}
+		index.Columns = strings.Split(columns, ",")
+		indexes = append(indexes, index)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return indexes, nil
+}
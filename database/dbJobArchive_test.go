@@ -0,0 +1,267 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobArchiveNewJobArchiveDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewJobArchiveDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+		createQueuerTables(t, database)
+
+		jobArchiveDbHandler, err := NewJobArchiveDBHandler(database)
+		assert.NoError(t, err, "Expected NewJobArchiveDBHandler to not return an error")
+		require.NotNil(t, jobArchiveDbHandler, "Expected NewJobArchiveDBHandler to return a non-nil instance")
+
+		exists, err := jobArchiveDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("Invalid call NewJobArchiveDBHandler with nil database", func(t *testing.T) {
+		_, err := NewJobArchiveDBHandler(nil)
+		assert.Error(t, err, "Expected error when creating JobArchiveDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestJobArchiveSelectJobRIDsByFilter(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+	createQueuerTables(t, database)
+
+	jobArchiveDbHandler, err := NewJobArchiveDBHandler(database)
+	require.NoError(t, err, "Expected NewJobArchiveDBHandler to not return an error")
+
+	workerRID := uuid.New()
+	fastRID, slowRID, failedRID, cancelledRID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	_, err = database.Instance.Exec(`
+		INSERT INTO job_archive (rid, worker_rid, task_name, status, started_at, created_at, updated_at) VALUES
+			($1, $5, 'filter-task', 'SUCCEEDED', NOW() - INTERVAL '5 seconds', NOW() - INTERVAL '4 minutes', NOW()),
+			($2, $5, 'filter-task', 'SUCCEEDED', NOW() - INTERVAL '2 minutes', NOW() - INTERVAL '3 minutes', NOW()),
+			($3, NULL, 'filter-task', 'FAILED', NOW() - INTERVAL '10 seconds', NOW() - INTERVAL '2 minutes', NOW()),
+			($4, NULL, 'filter-other', 'CANCELLED', NULL, NOW() - INTERVAL '1 minute', NOW())
+	`, fastRID, slowRID, failedRID, cancelledRID, workerRID)
+	require.NoError(t, err)
+
+	t.Run("Filter by status and task key", func(t *testing.T) {
+		rids, err := jobArchiveDbHandler.SelectJobRIDsByFilter(&model.JobArchiveFilter{Status: "SUCCEEDED", TaskKey: "filter-task"}, 0, 10)
+		require.NoError(t, err, "Expected SelectJobRIDsByFilter to not return an error")
+		assert.Equal(t, []uuid.UUID{slowRID, fastRID}, rids, "Expected the newest job first")
+	})
+
+	t.Run("Filter by worker", func(t *testing.T) {
+		rids, err := jobArchiveDbHandler.SelectJobRIDsByFilter(&model.JobArchiveFilter{WorkerRID: workerRID}, 0, 10)
+		require.NoError(t, err, "Expected SelectJobRIDsByFilter to not return an error")
+		assert.Equal(t, []uuid.UUID{slowRID, fastRID}, rids)
+	})
+
+	t.Run("Filter by duration range", func(t *testing.T) {
+		rids, err := jobArchiveDbHandler.SelectJobRIDsByFilter(&model.JobArchiveFilter{TaskKey: "filter-task", MinDuration: time.Second, MaxDuration: time.Minute}, 0, 10)
+		require.NoError(t, err, "Expected SelectJobRIDsByFilter to not return an error")
+		assert.Equal(t, []uuid.UUID{failedRID, fastRID}, rids, "Expected jobs that never started to have no duration")
+	})
+
+	t.Run("Filter with search and pagination", func(t *testing.T) {
+		rids, err := jobArchiveDbHandler.SelectJobRIDsByFilter(&model.JobArchiveFilter{Search: "filter-"}, 0, 2)
+		require.NoError(t, err, "Expected SelectJobRIDsByFilter to not return an error")
+		assert.Equal(t, []uuid.UUID{cancelledRID, failedRID}, rids)
+
+		var lastID int
+		err = database.Instance.QueryRow(`SELECT id FROM job_archive WHERE rid = $1`, failedRID).Scan(&lastID)
+		require.NoError(t, err)
+
+		rids, err = jobArchiveDbHandler.SelectJobRIDsByFilter(&model.JobArchiveFilter{Search: "filter-"}, lastID, 2)
+		require.NoError(t, err, "Expected SelectJobRIDsByFilter to not return an error")
+		assert.Equal(t, []uuid.UUID{slowRID, fastRID}, rids)
+	})
+}
+
+func TestJobArchiveSelectJobRIDsByTask(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+	createQueuerTables(t, database)
+
+	jobArchiveDbHandler, err := NewJobArchiveDBHandler(database)
+	require.NoError(t, err, "Expected NewJobArchiveDBHandler to not return an error")
+
+	oldRID, firstRID, secondRID, thirdRID, otherRID := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	_, err = database.Instance.Exec(`
+		INSERT INTO job_archive (rid, task_name, status, created_at, updated_at) VALUES
+			($1, 'export-task', 'SUCCEEDED', NOW() - INTERVAL '3 days', NOW() - INTERVAL '2 days'),
+			($2, 'export-task', 'SUCCEEDED', NOW() - INTERVAL '3 hours', NOW() - INTERVAL '2 hours'),
+			($3, 'export-task', 'FAILED', NOW() - INTERVAL '3 hours', NOW() - INTERVAL '1 hour'),
+			($4, 'export-task', 'SUCCEEDED', NOW() - INTERVAL '3 hours', NOW() - INTERVAL '1 hour'),
+			($5, 'export-other', 'SUCCEEDED', NOW() - INTERVAL '3 hours', NOW() - INTERVAL '1 hour')
+	`, oldRID, firstRID, secondRID, thirdRID, otherRID)
+	require.NoError(t, err)
+
+	from := time.Now().Add(-24 * time.Hour)
+	to := time.Now()
+
+	rids, err := jobArchiveDbHandler.SelectJobRIDsByTask("export-task", from, to, uuid.Nil, 10)
+	require.NoError(t, err, "Expected SelectJobRIDsByTask to not return an error")
+	assert.Equal(t, []uuid.UUID{firstRID, secondRID, thirdRID}, rids, "Expected the jobs of the task in the range, oldest first")
+
+	rids, err = jobArchiveDbHandler.SelectJobRIDsByTask("export-task", from, to, uuid.Nil, 2)
+	require.NoError(t, err, "Expected SelectJobRIDsByTask to not return an error")
+	assert.Equal(t, []uuid.UUID{firstRID, secondRID}, rids)
+
+	rids, err = jobArchiveDbHandler.SelectJobRIDsByTask("export-task", from, to, secondRID, 2)
+	require.NoError(t, err, "Expected SelectJobRIDsByTask to not return an error")
+	assert.Equal(t, []uuid.UUID{thirdRID}, rids, "Expected jobs ended at the same time to be paged by ID")
+
+	rids, err = jobArchiveDbHandler.SelectJobRIDsByTask("", from, to, uuid.Nil, 100)
+	require.NoError(t, err, "Expected SelectJobRIDsByTask to not return an error")
+	assert.Subset(t, rids, []uuid.UUID{firstRID, secondRID, thirdRID, otherRID}, "Expected the jobs of all tasks without task key")
+	assert.NotContains(t, rids, oldRID)
+}
+
+func TestJobArchiveSelectFailureGroups(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+	createQueuerTables(t, database)
+
+	jobArchiveDbHandler, err := NewJobArchiveDBHandler(database)
+	require.NoError(t, err, "Expected NewJobArchiveDBHandler to not return an error")
+
+	oldRID, firstRID, lastRID, otherErrorRID, otherTaskRID, succeededRID := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	_, err = database.Instance.Exec(`
+		INSERT INTO job_archive (rid, task_name, status, error, updated_at) VALUES
+			($1, 'failure-task', 'FAILED', 'connection refused', NOW() - INTERVAL '2 days'),
+			($2, 'failure-task', 'FAILED', 'connection refused', NOW() - INTERVAL '3 hours'),
+			($3, 'failure-task', 'FAILED', 'connection refused', NOW() - INTERVAL '1 hour'),
+			($4, 'failure-task', 'FAILED', 'timeout', NOW() - INTERVAL '30 minutes'),
+			($5, 'failure-other', 'FAILED', 'connection refused', NOW() - INTERVAL '2 hours'),
+			($6, 'failure-task', 'SUCCEEDED', '', NOW() - INTERVAL '1 hour')
+	`, oldRID, firstRID, lastRID, otherErrorRID, otherTaskRID, succeededRID)
+	require.NoError(t, err)
+
+	from := time.Now().Add(-24 * time.Hour)
+	to := time.Now()
+
+	t.Run("Groups of a task", func(t *testing.T) {
+		groups, err := jobArchiveDbHandler.SelectFailureGroups("failure-task", "", from, to, 10)
+		require.NoError(t, err, "Expected SelectFailureGroups to not return an error")
+		require.Len(t, groups, 2, "Expected one group per error message of the failed jobs in the range")
+
+		assert.Equal(t, "connection refused", groups[0].Error, "Expected the group with the most failures first")
+		assert.Equal(t, 2, groups[0].Count)
+		assert.Equal(t, lastRID, groups[0].LastJobRID)
+		assert.True(t, groups[0].FirstOccurrence.Before(groups[0].LastOccurrence))
+		assert.Equal(t, "timeout", groups[1].Error)
+		assert.Equal(t, 1, groups[1].Count)
+	})
+
+	t.Run("Groups of all tasks", func(t *testing.T) {
+		groups, err := jobArchiveDbHandler.SelectFailureGroups("", "", from, to, 100)
+		require.NoError(t, err, "Expected SelectFailureGroups to not return an error")
+
+		found := false
+		for _, group := range groups {
+			if group.TaskKey == "failure-other" {
+				found = true
+				assert.Equal(t, 1, group.Count, "Expected the same error of another task in its own group")
+			}
+		}
+		assert.True(t, found, "Expected the groups of all tasks without task key")
+	})
+
+	t.Run("Filter archived jobs by error", func(t *testing.T) {
+		rids, err := jobArchiveDbHandler.SelectJobRIDsByFilter(&model.JobArchiveFilter{TaskKey: "failure-task", Error: "timeout"}, 0, 10)
+		require.NoError(t, err, "Expected SelectJobRIDsByFilter to not return an error")
+		assert.Equal(t, []uuid.UUID{otherErrorRID}, rids)
+
+		count, err := jobArchiveDbHandler.SelectJobsCountByFilter(&model.JobArchiveFilter{TaskKey: "failure-task", Error: "connection refused"})
+		require.NoError(t, err, "Expected SelectJobsCountByFilter to not return an error")
+		assert.Equal(t, 3, count)
+	})
+}
+
+func TestJobArchivePartitions(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+	createQueuerTables(t, database)
+
+	jobArchiveDbHandler, err := NewJobArchiveDBHandler(database)
+	require.NoError(t, err, "Expected NewJobArchiveDBHandler to not return an error")
+
+	t.Run("Job archive of the queuer is not partitioned", func(t *testing.T) {
+		partitionKey, err := jobArchiveDbHandler.SelectPartitionKey()
+		require.NoError(t, err, "Expected SelectPartitionKey to not return an error")
+		assert.Empty(t, partitionKey)
+
+		partitions, err := jobArchiveDbHandler.SelectPartitions()
+		require.NoError(t, err, "Expected SelectPartitions to not return an error")
+		assert.Empty(t, partitions)
+	})
+
+	t.Run("Only monthly partitions can be dropped", func(t *testing.T) {
+		err := jobArchiveDbHandler.DropPartition("job_archive")
+		assert.ErrorContains(t, err, "invalid partition name")
+
+		err = jobArchiveDbHandler.DropPartition("job_archive_2026_01; DROP TABLE job")
+		assert.ErrorContains(t, err, "invalid partition name")
+
+		err = jobArchiveDbHandler.DropPartition("job_archive_2026_01")
+		assert.ErrorContains(t, err, "no partition of the job archive")
+	})
+
+	t.Run("Indexes of the job tables", func(t *testing.T) {
+		indexes, err := jobArchiveDbHandler.SelectTableIndexes([]string{"job", "job_archive"})
+		require.NoError(t, err, "Expected SelectTableIndexes to not return an error")
+		require.NotEmpty(t, indexes)
+
+		tables := map[string]bool{}
+		for _, index := range indexes {
+			tables[index.Table] = true
+			assert.NotEmpty(t, index.Columns)
+		}
+		assert.True(t, tables["job"])
+		assert.True(t, tables["job_archive"])
+	})
+}
+
+func TestPartitionBounds(t *testing.T) {
+	from, to := partitionBounds("FOR VALUES FROM ('2026-01-01 00:00:00') TO ('2026-02-01 00:00:00')")
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), to)
+
+	from, to = partitionBounds("FOR VALUES FROM ('2026-01-01 01:00:00+01') TO (MAXVALUE)")
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.True(t, to.IsZero())
+
+	from, to = partitionBounds("DEFAULT")
+	assert.True(t, from.IsZero())
+	assert.True(t, to.IsZero())
+}
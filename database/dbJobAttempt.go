@@ -0,0 +1,209 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+)
+
+// JobAttemptDBHandlerFunctions defines the interface for JobAttempt database operations.
+type JobAttemptDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	InsertJobAttempt(jobAttempt *model.JobAttempt) error
+	EndJobAttempts(jobRID uuid.UUID, status string, endedAt time.Time) error
+	SelectJobAttempts(jobRID uuid.UUID) ([]*model.JobAttempt, error)
+}
+
+// JobAttemptDBHandler implements JobAttemptDBHandlerFunctions and holds the database connection.
+type JobAttemptDBHandler struct {
+	db *helper.Database
+}
+
+// NewJobAttemptDBHandler creates a new instance of JobAttemptDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing job_attempt table before creating a new one
+func NewJobAttemptDBHandler(dbConnection *helper.Database, withTableDrop bool) (*JobAttemptDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	jobAttemptDbHandler := &JobAttemptDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := jobAttemptDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := jobAttemptDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return jobAttemptDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'job_attempt' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r JobAttemptDBHandler) CheckTableExistance() (bool, error) {
+	jobAttemptExists, err := r.db.CheckTableExistance("job_attempt")
+	if err != nil {
+		return false, helper.NewError("job_attempt table", err)
+	}
+	return jobAttemptExists, nil
+}
+
+// CreateTable creates the 'job_attempt' table in the database.
+// If the table already exists, it does not create it again.
+func (r JobAttemptDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS job_attempt (
+			id SERIAL PRIMARY KEY,
+			job_rid UUID NOT NULL,
+			attempt INT NOT NULL,
+			worker_rid UUID NOT NULL,
+			status VARCHAR(50) NOT NULL DEFAULT '',
+			started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			ended_at TIMESTAMP WITH TIME ZONE,
+			UNIQUE (job_rid, attempt)
+		);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create job_attempt table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table job_attempt")
+
+	return nil
+}
+
+// DropTable drops the 'job_attempt' table from the database.
+func (r JobAttemptDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS job_attempt`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop job_attempt table", err)
+	}
+
+	r.db.Logger.Info("Dropped table job_attempt")
+
+	return nil
+}
+
+// InsertJobAttempt records the start of an attempt of a job.
+// An attempt is only recorded once, so every manager instance can record the same notification.
+func (r JobAttemptDBHandler) InsertJobAttempt(jobAttempt *model.JobAttempt) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		INSERT INTO job_attempt (
+			job_rid,
+			attempt,
+			worker_rid,
+			status,
+			started_at
+		) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (job_rid, attempt) DO NOTHING`
+
+	_, err := r.db.Instance.ExecContext(
+		ctx,
+		query,
+		jobAttempt.JobRID,
+		jobAttempt.Attempt,
+		jobAttempt.WorkerRID,
+		jobAttempt.Status,
+		jobAttempt.StartedAt,
+	)
+	if err != nil {
+		return helper.NewError("insert job attempt", err)
+	}
+
+	return nil
+}
+
+// EndJobAttempts ends the running attempts of a job with the status, ended attempts are not changed.
+func (r JobAttemptDBHandler) EndJobAttempts(jobRID uuid.UUID, status string, endedAt time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE job_attempt
+		SET status = $2, ended_at = GREATEST($3, started_at)
+		WHERE job_rid = $1 AND ended_at IS NULL`
+
+	_, err := r.db.Instance.ExecContext(ctx, query, jobRID, status, endedAt)
+	if err != nil {
+		return helper.NewError("end job attempts", err)
+	}
+
+	return nil
+}
+
+// SelectJobAttempts retrieves the attempts of a job ordered by attempt.
+func (r JobAttemptDBHandler) SelectJobAttempts(jobRID uuid.UUID) ([]*model.JobAttempt, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			job_rid,
+			attempt,
+			worker_rid,
+			status,
+			started_at,
+			ended_at
+		FROM job_attempt
+		WHERE job_rid = $1
+		ORDER BY attempt ASC
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, jobRID)
+	if err != nil {
+		return nil, helper.NewError("select job attempts", err)
+	}
+	defer rows.Close()
+
+	jobAttempts := []*model.JobAttempt{}
+	for rows.Next() {
+		jobAttempt := &model.JobAttempt{}
+		err := rows.Scan(
+			&jobAttempt.ID,
+			&jobAttempt.JobRID,
+			&jobAttempt.Attempt,
+			&jobAttempt.WorkerRID,
+			&jobAttempt.Status,
+			&jobAttempt.StartedAt,
+			&jobAttempt.EndedAt,
+		)
+		if err != nil {
+			return nil, helper.NewError("scan job attempt", err)
+		}
+		jobAttempts = append(jobAttempts, jobAttempt)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return jobAttempts, nil
+}
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	qmodel "github.com/siherrmann/queuer/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobAttemptNewJobAttemptDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewJobAttemptDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		jobAttemptDbHandler, err := NewJobAttemptDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewJobAttemptDBHandler to not return an error")
+		require.NotNil(t, jobAttemptDbHandler, "Expected NewJobAttemptDBHandler to return a non-nil instance")
+
+		exists, err := jobAttemptDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = jobAttemptDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewJobAttemptDBHandler with nil database", func(t *testing.T) {
+		_, err := NewJobAttemptDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating JobAttemptDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestJobAttemptInsertEndAndSelectJobAttempts(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	jobAttemptDbHandler, err := NewJobAttemptDBHandler(database, true)
+	require.NoError(t, err, "Expected NewJobAttemptDBHandler to not return an error")
+
+	jobRID := uuid.New()
+	workerRID := uuid.New()
+	startedAt := time.Now().Add(-time.Minute).Truncate(time.Millisecond)
+	firstAttempt := &model.JobAttempt{JobRID: jobRID, Attempt: 1, WorkerRID: workerRID, Status: qmodel.JobStatusRunning, StartedAt: startedAt}
+
+	err = jobAttemptDbHandler.InsertJobAttempt(firstAttempt)
+	require.NoError(t, err, "Expected InsertJobAttempt to not return an error")
+	err = jobAttemptDbHandler.InsertJobAttempt(firstAttempt)
+	require.NoError(t, err, "Expected InsertJobAttempt to ignore an already recorded attempt")
+
+	err = jobAttemptDbHandler.EndJobAttempts(jobRID, qmodel.JobStatusFailed, startedAt.Add(10*time.Second))
+	require.NoError(t, err, "Expected EndJobAttempts to not return an error")
+
+	err = jobAttemptDbHandler.InsertJobAttempt(&model.JobAttempt{JobRID: jobRID, Attempt: 2, WorkerRID: workerRID, Status: qmodel.JobStatusRunning, StartedAt: startedAt.Add(20 * time.Second)})
+	require.NoError(t, err, "Expected InsertJobAttempt to not return an error")
+
+	jobAttempts, err := jobAttemptDbHandler.SelectJobAttempts(jobRID)
+	require.NoError(t, err, "Expected SelectJobAttempts to not return an error")
+	require.Len(t, jobAttempts, 2)
+	assert.Equal(t, 1, jobAttempts[0].Attempt)
+	assert.Equal(t, qmodel.JobStatusFailed, jobAttempts[0].Status)
+	require.NotNil(t, jobAttempts[0].EndedAt)
+	assert.True(t, startedAt.Add(10*time.Second).Equal(*jobAttempts[0].EndedAt))
+	assert.Equal(t, 2, jobAttempts[1].Attempt)
+	assert.Nil(t, jobAttempts[1].EndedAt, "Expected the second attempt to still run")
+
+	err = jobAttemptDbHandler.EndJobAttempts(jobRID, qmodel.JobStatusSucceeded, startedAt.Add(30*time.Second))
+	require.NoError(t, err, "Expected EndJobAttempts to not return an error")
+
+	jobAttempts, err = jobAttemptDbHandler.SelectJobAttempts(jobRID)
+	require.NoError(t, err)
+	assert.Equal(t, qmodel.JobStatusFailed, jobAttempts[0].Status, "Expected an ended attempt to not change")
+	assert.Equal(t, qmodel.JobStatusSucceeded, jobAttempts[1].Status)
+
+	jobAttempts, err = jobAttemptDbHandler.SelectJobAttempts(uuid.New())
+	require.NoError(t, err)
+	assert.Empty(t, jobAttempts)
+}
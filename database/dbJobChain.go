@@ -0,0 +1,344 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+)
+
+// JobChainDBHandlerFunctions defines the interface for JobChain database operations.
+type JobChainDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	InsertJobChains(jobRID uuid.UUID, taskKey string, dependencies []model.TaskDependency) ([]*model.JobChain, error)
+	ClaimJobChains(jobRID uuid.UUID) ([]*model.JobChain, error)
+	SkipJobChains(jobRID uuid.UUID, reason string) (int, error)
+	UpdateJobChainResult(id int, downstreamJobRID *uuid.UUID, status string, errorMessage string) error
+	SelectJobChainsByJob(jobRID uuid.UUID) ([]*model.JobChain, error)
+}
+
+// JobChainDBHandler implements JobChainDBHandlerFunctions and holds the database connection.
+type JobChainDBHandler struct {
+	db *helper.Database
+}
+
+// NewJobChainDBHandler creates a new instance of JobChainDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing job_chain table before creating a new one
+func NewJobChainDBHandler(dbConnection *helper.Database, withTableDrop bool) (*JobChainDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	jobChainDbHandler := &JobChainDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := jobChainDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := jobChainDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return jobChainDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'job_chain' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r JobChainDBHandler) CheckTableExistance() (bool, error) {
+	jobChainExists, err := r.db.CheckTableExistance("job_chain")
+	if err != nil {
+		return false, helper.NewError("job_chain table", err)
+	}
+	return jobChainExists, nil
+}
+
+// CreateTable creates the 'job_chain' table in the database.
+// If the table already exists, it does not create it again.
+func (r JobChainDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS job_chain (
+			id SERIAL PRIMARY KEY,
+			job_rid UUID NOT NULL,
+			task_key VARCHAR(100) NOT NULL,
+			downstream_task_key VARCHAR(100) NOT NULL,
+			parameter_mapping JSONB NOT NULL DEFAULT '{}'::jsonb,
+			downstream_job_rid UUID,
+			status VARCHAR(20) NOT NULL DEFAULT 'pending',
+			error TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			UNIQUE (job_rid, downstream_task_key)
+		);
+		CREATE INDEX IF NOT EXISTS idx_job_chain_pending ON job_chain (job_rid) WHERE status = 'pending';
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create job_chain table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table job_chain")
+
+	return nil
+}
+
+// DropTable drops the 'job_chain' table from the database.
+func (r JobChainDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS job_chain`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop job_chain table", err)
+	}
+
+	r.db.Logger.Info("Dropped table job_chain")
+
+	return nil
+}
+
+// InsertJobChains registers a pending chain for each downstream task of an added job.
+// Registering the chains of a job again does not change the existing chains.
+func (r JobChainDBHandler) InsertJobChains(jobRID uuid.UUID, taskKey string, dependencies []model.TaskDependency) ([]*model.JobChain, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		INSERT INTO job_chain (
+			job_rid,
+			task_key,
+			downstream_task_key,
+			parameter_mapping
+		) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (job_rid, downstream_task_key) DO NOTHING
+		RETURNING
+			id,
+			job_rid,
+			task_key,
+			downstream_task_key,
+			parameter_mapping,
+			downstream_job_rid,
+			status,
+			error,
+			created_at,
+			updated_at`
+
+	jobChains := []*model.JobChain{}
+	for _, dependency := range dependencies {
+		parameterMapping := dependency.ParameterMapping
+		if parameterMapping == nil {
+			parameterMapping = map[string]string{}
+		}
+		parameterMappingJSON, err := json.Marshal(parameterMapping)
+		if err != nil {
+			return nil, helper.NewError("marshal parameter_mapping", err)
+		}
+
+		rows, err := r.db.Instance.QueryContext(ctx, query, jobRID, taskKey, dependency.TaskKey, parameterMappingJSON)
+		if err != nil {
+			return nil, helper.NewError("insert job chain", err)
+		}
+		for rows.Next() {
+			jobChain, err := scanJobChain(rows)
+			if err != nil {
+				rows.Close()
+				return nil, helper.NewError("scan job chain", err)
+			}
+			jobChains = append(jobChains, jobChain)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, helper.NewError("rows iteration", err)
+		}
+	}
+
+	return jobChains, nil
+}
+
+// ClaimJobChains sets the pending chains of an ended job to running and returns them.
+// Only one manager instance claims a chain, so the downstream job is added once.
+func (r JobChainDBHandler) ClaimJobChains(jobRID uuid.UUID) ([]*model.JobChain, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE job_chain
+		SET
+			status = $2,
+			updated_at = NOW()
+		WHERE job_rid = $1
+			AND status = $3
+		RETURNING
+			id,
+			job_rid,
+			task_key,
+			downstream_task_key,
+			parameter_mapping,
+			downstream_job_rid,
+			status,
+			error,
+			created_at,
+			updated_at`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, jobRID, model.JOB_CHAIN_STATUS_RUNNING, model.JOB_CHAIN_STATUS_PENDING)
+	if err != nil {
+		return nil, helper.NewError("claim job chains", err)
+	}
+	defer rows.Close()
+
+	jobChains := []*model.JobChain{}
+	for rows.Next() {
+		jobChain, err := scanJobChain(rows)
+		if err != nil {
+			return nil, helper.NewError("scan job chain", err)
+		}
+		jobChains = append(jobChains, jobChain)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return jobChains, nil
+}
+
+// SkipJobChains skips the pending chains of a job that did not succeed with the reason as error.
+// It returns the number of skipped chains.
+func (r JobChainDBHandler) SkipJobChains(jobRID uuid.UUID, reason string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE job_chain
+		SET
+			status = $2,
+			error = $3,
+			updated_at = NOW()
+		WHERE job_rid = $1
+			AND status = $4`
+
+	result, err := r.db.Instance.ExecContext(ctx, query, jobRID, model.JOB_CHAIN_STATUS_SKIPPED, reason, model.JOB_CHAIN_STATUS_PENDING)
+	if err != nil {
+		return 0, helper.NewError("skip job chains", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, helper.NewError("rows affected", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// UpdateJobChainResult records the downstream job added for a claimed chain or the error adding it failed with.
+func (r JobChainDBHandler) UpdateJobChainResult(id int, downstreamJobRID *uuid.UUID, status string, errorMessage string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE job_chain
+		SET
+			downstream_job_rid = $2,
+			status = $3,
+			error = $4,
+			updated_at = NOW()
+		WHERE id = $1`
+
+	_, err := r.db.Instance.ExecContext(ctx, query, id, downstreamJobRID, status, errorMessage)
+	if err != nil {
+		return helper.NewError("update job chain result", err)
+	}
+
+	return nil
+}
+
+// SelectJobChainsByJob retrieves the chains of a job in the order they were registered.
+func (r JobChainDBHandler) SelectJobChainsByJob(jobRID uuid.UUID) ([]*model.JobChain, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			job_rid,
+			task_key,
+			downstream_task_key,
+			parameter_mapping,
+			downstream_job_rid,
+			status,
+			error,
+			created_at,
+			updated_at
+		FROM job_chain
+		WHERE job_rid = $1
+		ORDER BY id ASC
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, jobRID)
+	if err != nil {
+		return nil, helper.NewError("select job chains by job", err)
+	}
+	defer rows.Close()
+
+	jobChains := []*model.JobChain{}
+	for rows.Next() {
+		jobChain, err := scanJobChain(rows)
+		if err != nil {
+			return nil, helper.NewError("scan job chain", err)
+		}
+		jobChains = append(jobChains, jobChain)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return jobChains, nil
+}
+
+// scanJobChain scans a job chain row in the column order of the job chain queries.
+func scanJobChain(row interface{ Scan(dest ...any) error }) (*model.JobChain, error) {
+	jobChain := &model.JobChain{}
+	var parameterMappingData []byte
+	err := row.Scan(
+		&jobChain.ID,
+		&jobChain.JobRID,
+		&jobChain.TaskKey,
+		&jobChain.DownstreamTaskKey,
+		&parameterMappingData,
+		&jobChain.DownstreamJobRID,
+		&jobChain.Status,
+		&jobChain.Error,
+		&jobChain.CreatedAt,
+		&jobChain.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(parameterMappingData, &jobChain.ParameterMapping)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal parameter_mapping: %w", err)
+	}
+
+	return jobChain, nil
+}
@@ -0,0 +1,120 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobChainNewJobChainDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewJobChainDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		jobChainDbHandler, err := NewJobChainDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewJobChainDBHandler to not return an error")
+		require.NotNil(t, jobChainDbHandler, "Expected NewJobChainDBHandler to return a non-nil instance")
+
+		exists, err := jobChainDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = jobChainDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewJobChainDBHandler with nil database", func(t *testing.T) {
+		_, err := NewJobChainDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating JobChainDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestJobChainClaimJobChains(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	jobChainDbHandler, err := NewJobChainDBHandler(database, true)
+	require.NoError(t, err, "Expected NewJobChainDBHandler to not return an error")
+
+	jobRID := uuid.New()
+	dependencies := []model.TaskDependency{
+		{TaskKey: "report", ParameterMapping: map[string]string{"file": "output_file"}},
+		{TaskKey: "cleanup"},
+	}
+	insertedJobChains, err := jobChainDbHandler.InsertJobChains(jobRID, "export", dependencies)
+	require.NoError(t, err, "Expected InsertJobChains to not return an error")
+	require.Len(t, insertedJobChains, 2)
+	assert.Equal(t, model.JOB_CHAIN_STATUS_PENDING, insertedJobChains[0].Status)
+	assert.Equal(t, map[string]string{"file": "output_file"}, insertedJobChains[0].ParameterMapping)
+	assert.Equal(t, map[string]string{}, insertedJobChains[1].ParameterMapping)
+
+	jobChains, err := jobChainDbHandler.InsertJobChains(jobRID, "export", dependencies)
+	require.NoError(t, err, "Expected InsertJobChains to not return an error")
+	assert.Empty(t, jobChains, "Expected registered chains to not be registered again")
+
+	claimedJobChains, err := jobChainDbHandler.ClaimJobChains(jobRID)
+	require.NoError(t, err, "Expected ClaimJobChains to not return an error")
+	require.Len(t, claimedJobChains, 2)
+	assert.Equal(t, model.JOB_CHAIN_STATUS_RUNNING, claimedJobChains[0].Status)
+
+	claimedJobChains, err = jobChainDbHandler.ClaimJobChains(jobRID)
+	require.NoError(t, err, "Expected ClaimJobChains to not return an error")
+	assert.Empty(t, claimedJobChains, "Expected claimed chains to not be claimed again")
+
+	downstreamJobRID := uuid.New()
+	err = jobChainDbHandler.UpdateJobChainResult(insertedJobChains[0].ID, &downstreamJobRID, model.JOB_CHAIN_STATUS_ENQUEUED, "")
+	require.NoError(t, err, "Expected UpdateJobChainResult to not return an error")
+
+	selectedJobChains, err := jobChainDbHandler.SelectJobChainsByJob(jobRID)
+	require.NoError(t, err, "Expected SelectJobChainsByJob to not return an error")
+	require.Len(t, selectedJobChains, 2)
+	assert.Equal(t, model.JOB_CHAIN_STATUS_ENQUEUED, selectedJobChains[0].Status)
+	require.NotNil(t, selectedJobChains[0].DownstreamJobRID)
+	assert.Equal(t, downstreamJobRID, *selectedJobChains[0].DownstreamJobRID)
+	assert.Nil(t, selectedJobChains[1].DownstreamJobRID)
+}
+
+func TestJobChainSkipJobChains(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	jobChainDbHandler, err := NewJobChainDBHandler(database, true)
+	require.NoError(t, err, "Expected NewJobChainDBHandler to not return an error")
+
+	jobRID := uuid.New()
+	_, err = jobChainDbHandler.InsertJobChains(jobRID, "export", []model.TaskDependency{{TaskKey: "report"}})
+	require.NoError(t, err, "Expected InsertJobChains to not return an error")
+
+	skipped, err := jobChainDbHandler.SkipJobChains(jobRID, "job FAILED")
+	require.NoError(t, err, "Expected SkipJobChains to not return an error")
+	assert.Equal(t, 1, skipped)
+
+	claimedJobChains, err := jobChainDbHandler.ClaimJobChains(jobRID)
+	require.NoError(t, err, "Expected ClaimJobChains to not return an error")
+	assert.Empty(t, claimedJobChains, "Expected skipped chains to not be claimed")
+
+	selectedJobChains, err := jobChainDbHandler.SelectJobChainsByJob(jobRID)
+	require.NoError(t, err, "Expected SelectJobChainsByJob to not return an error")
+	require.Len(t, selectedJobChains, 1)
+	assert.Equal(t, model.JOB_CHAIN_STATUS_SKIPPED, selectedJobChains[0].Status)
+	assert.Equal(t, "job FAILED", selectedJobChains[0].Error)
+}
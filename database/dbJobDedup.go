@@ -0,0 +1,184 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+)
+
+// JobDedupDBHandlerFunctions defines the interface for JobDedup database operations.
+type JobDedupDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	UpsertJobDedup(jobDedup *model.JobDedup) (*model.JobDedup, error)
+	SelectJobDedup(taskKey string, parametersHash string, since time.Time) (*model.JobDedup, error)
+}
+
+// JobDedupDBHandler implements JobDedupDBHandlerFunctions and holds the database connection.
+type JobDedupDBHandler struct {
+	db *helper.Database
+}
+
+// NewJobDedupDBHandler creates a new instance of JobDedupDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing job_dedup table before creating a new one
+func NewJobDedupDBHandler(dbConnection *helper.Database, withTableDrop bool) (*JobDedupDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	jobDedupDbHandler := &JobDedupDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := jobDedupDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := jobDedupDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return jobDedupDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'job_dedup' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r JobDedupDBHandler) CheckTableExistance() (bool, error) {
+	jobDedupExists, err := r.db.CheckTableExistance("job_dedup")
+	if err != nil {
+		return false, helper.NewError("job_dedup table", err)
+	}
+	return jobDedupExists, nil
+}
+
+// CreateTable creates the 'job_dedup' table in the database.
+// If the table already exists, it does not create it again.
+func (r JobDedupDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS job_dedup (
+			id SERIAL PRIMARY KEY,
+			task_key VARCHAR(100) NOT NULL,
+			parameters_hash CHAR(64) NOT NULL,
+			job_rid UUID NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			UNIQUE (task_key, parameters_hash)
+		);
+		CREATE INDEX IF NOT EXISTS idx_job_dedup_created_at ON job_dedup (created_at);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create job_dedup table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table job_dedup")
+
+	return nil
+}
+
+// DropTable drops the 'job_dedup' table from the database.
+func (r JobDedupDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS job_dedup`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop job_dedup table", err)
+	}
+
+	r.db.Logger.Info("Dropped table job_dedup")
+
+	return nil
+}
+
+// UpsertJobDedup stores the job as latest job of the task with the parameters hash.
+// An existing entry of the task and hash is replaced, so the window starts again with the new job.
+func (r JobDedupDBHandler) UpsertJobDedup(jobDedup *model.JobDedup) (*model.JobDedup, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		INSERT INTO job_dedup (
+			task_key,
+			parameters_hash,
+			job_rid
+		) VALUES ($1, $2, $3)
+		ON CONFLICT (task_key, parameters_hash) DO UPDATE
+		SET
+			job_rid = EXCLUDED.job_rid,
+			created_at = NOW()
+		RETURNING
+			id,
+			task_key,
+			parameters_hash,
+			job_rid,
+			created_at`
+
+	row := r.db.Instance.QueryRowContext(ctx, query, jobDedup.TaskKey, jobDedup.ParametersHash, jobDedup.JobRID)
+	upsertedJobDedup, err := scanJobDedup(row)
+	if err != nil {
+		return nil, helper.NewError("upsert job dedup", err)
+	}
+
+	return upsertedJobDedup, nil
+}
+
+// SelectJobDedup retrieves the latest job of the task with the parameters hash if it was added since the given time.
+func (r JobDedupDBHandler) SelectJobDedup(taskKey string, parametersHash string, since time.Time) (*model.JobDedup, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			task_key,
+			parameters_hash,
+			job_rid,
+			created_at
+		FROM job_dedup
+		WHERE task_key = $1
+			AND parameters_hash = $2
+			AND created_at >= $3
+	`
+
+	jobDedup, err := scanJobDedup(r.db.Instance.QueryRowContext(ctx, query, taskKey, parametersHash, since))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("job dedup not found", fmt.Errorf("no job of task %s with these parameters since %s", taskKey, since))
+		}
+		return nil, helper.NewError("select job dedup", err)
+	}
+
+	return jobDedup, nil
+}
+
+// scanJobDedup scans a job dedup row in the column order of the job dedup queries.
+func scanJobDedup(row interface{ Scan(dest ...any) error }) (*model.JobDedup, error) {
+	jobDedup := &model.JobDedup{}
+	err := row.Scan(
+		&jobDedup.ID,
+		&jobDedup.TaskKey,
+		&jobDedup.ParametersHash,
+		&jobDedup.JobRID,
+		&jobDedup.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return jobDedup, nil
+}
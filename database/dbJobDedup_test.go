@@ -0,0 +1,78 @@
+package database
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobDedupNewJobDedupDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewJobDedupDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		jobDedupDbHandler, err := NewJobDedupDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewJobDedupDBHandler to not return an error")
+		require.NotNil(t, jobDedupDbHandler, "Expected NewJobDedupDBHandler to return a non-nil instance")
+
+		exists, err := jobDedupDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = jobDedupDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewJobDedupDBHandler with nil database", func(t *testing.T) {
+		_, err := NewJobDedupDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating JobDedupDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestJobDedupUpsertAndSelectJobDedup(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	jobDedupDbHandler, err := NewJobDedupDBHandler(database, true)
+	require.NoError(t, err, "Expected NewJobDedupDBHandler to not return an error")
+
+	hash := strings.Repeat("a", 64)
+	firstJobRID := uuid.New()
+	upsertedJobDedup, err := jobDedupDbHandler.UpsertJobDedup(&model.JobDedup{TaskKey: "test-task", ParametersHash: hash, JobRID: firstJobRID})
+	require.NoError(t, err, "Expected UpsertJobDedup to not return an error")
+	assert.Equal(t, firstJobRID, upsertedJobDedup.JobRID)
+	assert.False(t, upsertedJobDedup.CreatedAt.IsZero())
+
+	selectedJobDedup, err := jobDedupDbHandler.SelectJobDedup("test-task", hash, time.Now().Add(-time.Minute))
+	require.NoError(t, err, "Expected SelectJobDedup to not return an error")
+	assert.Equal(t, firstJobRID, selectedJobDedup.JobRID)
+
+	_, err = jobDedupDbHandler.SelectJobDedup("test-task", hash, time.Now().Add(time.Minute))
+	assert.Error(t, err, "Expected SelectJobDedup to return an error for an entry before the window")
+
+	_, err = jobDedupDbHandler.SelectJobDedup("other-task", hash, time.Now().Add(-time.Minute))
+	assert.Error(t, err, "Expected SelectJobDedup to return an error for another task")
+
+	secondJobRID := uuid.New()
+	replacedJobDedup, err := jobDedupDbHandler.UpsertJobDedup(&model.JobDedup{TaskKey: "test-task", ParametersHash: hash, JobRID: secondJobRID})
+	require.NoError(t, err, "Expected UpsertJobDedup to replace the existing entry")
+	assert.Equal(t, upsertedJobDedup.ID, replacedJobDedup.ID)
+	assert.Equal(t, secondJobRID, replacedJobDedup.JobRID)
+}
@@ -0,0 +1,345 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+)
+
+// JobFileDBHandlerFunctions defines the interface for JobFile and TrashedFile database operations.
+type JobFileDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	InsertJobFiles(jobRID uuid.UUID, namespace string, filenames []string) error
+	SelectOrphanedFiles() ([]*model.JobFile, error)
+	InsertTrashedFiles(trashedFiles []*model.TrashedFile) error
+	DeleteReferencedTrashedFiles() (int64, error)
+	SelectTrashedFiles() ([]*model.TrashedFile, error)
+	DeleteFiles(filenames []string) error
+}
+
+// JobFileDBHandler implements JobFileDBHandlerFunctions and holds the database connection.
+type JobFileDBHandler struct {
+	db *helper.Database
+}
+
+// NewJobFileDBHandler creates a new instance of JobFileDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing job_file and file_trash tables before creating new ones
+func NewJobFileDBHandler(dbConnection *helper.Database, withTableDrop bool) (*JobFileDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	jobFileDbHandler := &JobFileDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := jobFileDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := jobFileDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return jobFileDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'job_file' and 'file_trash' tables exist in the database.
+// It returns true if both tables exist, otherwise false.
+func (r JobFileDBHandler) CheckTableExistance() (bool, error) {
+	jobFileExists, err := r.db.CheckTableExistance("job_file")
+	if err != nil {
+		return false, helper.NewError("job_file table", err)
+	}
+	fileTrashExists, err := r.db.CheckTableExistance("file_trash")
+	if err != nil {
+		return false, helper.NewError("file_trash table", err)
+	}
+	return jobFileExists && fileTrashExists, nil
+}
+
+// CreateTable creates the 'job_file' and 'file_trash' tables in the database.
+// The job_file table associates the files of the file parameters to their jobs,
+// the file_trash table has the orphaned files queued for deletion.
+// If the tables already exist, it does not create them again.
+func (r JobFileDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS job_file (
+			job_rid UUID NOT NULL,
+			filename VARCHAR(255) NOT NULL,
+			namespace VARCHAR(100) NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (job_rid, filename)
+		);
+		CREATE INDEX IF NOT EXISTS idx_job_file_filename ON job_file (filename);
+
+		CREATE TABLE IF NOT EXISTS file_trash (
+			filename VARCHAR(255) PRIMARY KEY,
+			namespace VARCHAR(100) NOT NULL DEFAULT '',
+			size_bytes BIGINT NOT NULL DEFAULT 0,
+			trashed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create job_file table", err)
+	}
+
+	r.db.Logger.Info("Checked/created tables job_file and file_trash")
+
+	return nil
+}
+
+// DropTable drops the 'job_file' and 'file_trash' tables from the database.
+func (r JobFileDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS file_trash; DROP TABLE IF EXISTS job_file`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop job_file table", err)
+	}
+
+	r.db.Logger.Info("Dropped tables job_file and file_trash")
+
+	return nil
+}
+
+// InsertJobFiles associates the files to the job, files already associated to the job are skipped.
+func (r JobFileDBHandler) InsertJobFiles(jobRID uuid.UUID, namespace string, filenames []string) error {
+	if len(filenames) == 0 {
+		return nil
+	}
+
+	filenamesJSON, err := json.Marshal(filenames)
+	if err != nil {
+		return helper.NewError("marshal filenames", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		INSERT INTO job_file (
+			job_rid,
+			filename,
+			namespace
+		)
+		SELECT $1, filename, $2
+		FROM jsonb_array_elements_text($3::jsonb) AS filename
+		ON CONFLICT (job_rid, filename) DO NOTHING`
+
+	_, err = r.db.Instance.ExecContext(ctx, query, jobRID, namespace, filenamesJSON)
+	if err != nil {
+		return helper.NewError("insert job files", err)
+	}
+
+	return nil
+}
+
+// SelectOrphanedFiles retrieves the files of which none of the jobs is queued, running or in the archive and that are not in the trash,
+// with the namespace of the task of their last job. Files without a job, eg. uploaded on the files page, are never orphaned.
+func (r JobFileDBHandler) SelectOrphanedFiles() ([]*model.JobFile, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			job_file.filename,
+			(ARRAY_AGG(job_file.namespace ORDER BY job_file.created_at DESC))[1],
+			MAX(job_file.created_at)
+		FROM job_file
+		WHERE NOT EXISTS (SELECT 1 FROM file_trash WHERE file_trash.filename = job_file.filename)
+			AND NOT EXISTS (
+				SELECT 1
+				FROM job_file AS referenced
+				WHERE referenced.filename = job_file.filename
+					AND (
+						EXISTS (SELECT 1 FROM job WHERE job.rid = referenced.job_rid)
+						OR EXISTS (SELECT 1 FROM job_archive WHERE job_archive.rid = referenced.job_rid)
+					)
+			)
+		GROUP BY job_file.filename
+		ORDER BY job_file.filename ASC`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query)
+	if err != nil {
+		return nil, helper.NewError("select orphaned files", err)
+	}
+	defer rows.Close()
+
+	orphanedFiles := []*model.JobFile{}
+	for rows.Next() {
+		orphanedFile := &model.JobFile{}
+		err := rows.Scan(
+			&orphanedFile.Filename,
+			&orphanedFile.Namespace,
+			&orphanedFile.CreatedAt,
+		)
+		if err != nil {
+			return nil, helper.NewError("scan orphaned file", err)
+		}
+		orphanedFiles = append(orphanedFiles, orphanedFile)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, helper.NewError("rows error", err)
+	}
+
+	return orphanedFiles, nil
+}
+
+// InsertTrashedFiles queues the files for deletion, files already in the trash keep the time they were trashed.
+func (r JobFileDBHandler) InsertTrashedFiles(trashedFiles []*model.TrashedFile) error {
+	if len(trashedFiles) == 0 {
+		return nil
+	}
+
+	trashedFilesJSON, err := json.Marshal(trashedFiles)
+	if err != nil {
+		return helper.NewError("marshal trashed files", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		INSERT INTO file_trash (
+			filename,
+			namespace,
+			size_bytes
+		)
+		SELECT filename, namespace, size_bytes
+		FROM jsonb_to_recordset($1::jsonb) AS trashed(filename VARCHAR(255), namespace VARCHAR(100), size_bytes BIGINT)
+		ON CONFLICT (filename) DO NOTHING`
+
+	_, err = r.db.Instance.ExecContext(ctx, query, trashedFilesJSON)
+	if err != nil {
+		return helper.NewError("insert trashed files", err)
+	}
+
+	return nil
+}
+
+// DeleteReferencedTrashedFiles removes the files from the trash that a queued, running or archived job references again
+// and returns the number of removed files.
+func (r JobFileDBHandler) DeleteReferencedTrashedFiles() (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		DELETE FROM file_trash
+		WHERE EXISTS (
+			SELECT 1
+			FROM job_file
+			WHERE job_file.filename = file_trash.filename
+				AND (
+					EXISTS (SELECT 1 FROM job WHERE job.rid = job_file.job_rid)
+					OR EXISTS (SELECT 1 FROM job_archive WHERE job_archive.rid = job_file.job_rid)
+				)
+		)`
+
+	result, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return 0, helper.NewError("delete referenced trashed files", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, helper.NewError("rows affected", err)
+	}
+
+	return deleted, nil
+}
+
+// SelectTrashedFiles retrieves the files queued for deletion, the longest trashed first.
+func (r JobFileDBHandler) SelectTrashedFiles() ([]*model.TrashedFile, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			filename,
+			namespace,
+			size_bytes,
+			trashed_at
+		FROM file_trash
+		ORDER BY trashed_at ASC, filename ASC`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query)
+	if err != nil {
+		return nil, helper.NewError("select trashed files", err)
+	}
+	defer rows.Close()
+
+	trashedFiles := []*model.TrashedFile{}
+	for rows.Next() {
+		trashedFile := &model.TrashedFile{}
+		err := rows.Scan(
+			&trashedFile.Filename,
+			&trashedFile.Namespace,
+			&trashedFile.SizeBytes,
+			&trashedFile.TrashedAt,
+		)
+		if err != nil {
+			return nil, helper.NewError("scan trashed file", err)
+		}
+		trashedFiles = append(trashedFiles, trashedFile)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, helper.NewError("rows error", err)
+	}
+
+	return trashedFiles, nil
+}
+
+// DeleteFiles deletes the job associations and the trash entries of the deleted files.
+func (r JobFileDBHandler) DeleteFiles(filenames []string) error {
+	if len(filenames) == 0 {
+		return nil
+	}
+
+	filenamesJSON, err := json.Marshal(filenames)
+	if err != nil {
+		return helper.NewError("marshal filenames", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		WITH deleted_trash AS (
+			DELETE FROM file_trash
+			WHERE filename IN (SELECT jsonb_array_elements_text($1::jsonb))
+		)
+		DELETE FROM job_file
+		WHERE filename IN (SELECT jsonb_array_elements_text($1::jsonb))`
+
+	_, err = r.db.Instance.ExecContext(ctx, query, filenamesJSON)
+	if err != nil {
+		return helper.NewError("delete files", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobFileNewJobFileDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewJobFileDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		jobFileDbHandler, err := NewJobFileDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewJobFileDBHandler to not return an error")
+		require.NotNil(t, jobFileDbHandler, "Expected NewJobFileDBHandler to return a non-nil instance")
+
+		exists, err := jobFileDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = jobFileDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewJobFileDBHandler with nil database", func(t *testing.T) {
+		_, err := NewJobFileDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating JobFileDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestJobFileOrphanedFiles(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+	createQueuerTables(t, database)
+
+	jobFileDbHandler, err := NewJobFileDBHandler(database, true)
+	require.NoError(t, err, "Expected NewJobFileDBHandler to not return an error")
+
+	queuedRID := uuid.New()
+	_, err = database.Instance.Exec(`INSERT INTO job (rid, status) VALUES ($1, 'QUEUED')`, queuedRID)
+	require.NoError(t, err, "Expected job insert to not return an error")
+	archivedRID := uuid.New()
+	_, err = database.Instance.Exec(`INSERT INTO job_archive (rid, status) VALUES ($1, 'SUCCEEDED')`, archivedRID)
+	require.NoError(t, err, "Expected archived job insert to not return an error")
+	deletedRID := uuid.New()
+
+	require.NoError(t, jobFileDbHandler.InsertJobFiles(queuedRID, "default", []string{"queued.csv", "shared.csv"}))
+	require.NoError(t, jobFileDbHandler.InsertJobFiles(archivedRID, "default", []string{"archived.csv"}))
+	require.NoError(t, jobFileDbHandler.InsertJobFiles(deletedRID, "team", []string{"orphaned.csv", "shared.csv"}))
+
+	t.Run("Select the files without a queued, running or archived job", func(t *testing.T) {
+		orphanedFiles, err := jobFileDbHandler.SelectOrphanedFiles()
+		require.NoError(t, err, "Expected SelectOrphanedFiles to not return an error")
+		require.Len(t, orphanedFiles, 1)
+		assert.Equal(t, "orphaned.csv", orphanedFiles[0].Filename)
+		assert.Equal(t, "team", orphanedFiles[0].Namespace)
+	})
+
+	t.Run("Trash the orphaned files only once", func(t *testing.T) {
+		trashedFile := &model.TrashedFile{Filename: "orphaned.csv", Namespace: "team", SizeBytes: 100}
+		require.NoError(t, jobFileDbHandler.InsertTrashedFiles([]*model.TrashedFile{trashedFile}))
+		require.NoError(t, jobFileDbHandler.InsertTrashedFiles([]*model.TrashedFile{trashedFile}))
+
+		trash, err := jobFileDbHandler.SelectTrashedFiles()
+		require.NoError(t, err, "Expected SelectTrashedFiles to not return an error")
+		require.Len(t, trash, 1)
+		assert.Equal(t, int64(100), trash[0].SizeBytes)
+
+		orphanedFiles, err := jobFileDbHandler.SelectOrphanedFiles()
+		require.NoError(t, err)
+		assert.Empty(t, orphanedFiles, "Expected trashed files to not be orphaned again")
+	})
+
+	t.Run("Restore a trashed file a new job references", func(t *testing.T) {
+		require.NoError(t, jobFileDbHandler.InsertJobFiles(queuedRID, "default", []string{"orphaned.csv"}))
+
+		restored, err := jobFileDbHandler.DeleteReferencedTrashedFiles()
+		require.NoError(t, err, "Expected DeleteReferencedTrashedFiles to not return an error")
+		assert.Equal(t, int64(1), restored)
+
+		trash, err := jobFileDbHandler.SelectTrashedFiles()
+		require.NoError(t, err)
+		assert.Empty(t, trash)
+	})
+
+	t.Run("Delete the files with their jobs and trash entries", func(t *testing.T) {
+		require.NoError(t, jobFileDbHandler.InsertTrashedFiles([]*model.TrashedFile{{Filename: "shared.csv", Namespace: "default"}}))
+		require.NoError(t, jobFileDbHandler.DeleteFiles([]string{"shared.csv"}))
+
+		trash, err := jobFileDbHandler.SelectTrashedFiles()
+		require.NoError(t, err)
+		assert.Empty(t, trash)
+
+		var count int
+		err = database.Instance.QueryRow(`SELECT COUNT(*) FROM job_file WHERE filename = 'shared.csv'`).Scan(&count)
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+}
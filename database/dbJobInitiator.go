@@ -0,0 +1,325 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+)
+
+// JobInitiatorDBHandlerFunctions defines the interface for JobInitiator database operations.
+type JobInitiatorDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	InsertJobInitiator(jobInitiator *model.JobInitiator) (*model.JobInitiator, error)
+	SelectJobInitiator(jobRID uuid.UUID) (*model.JobInitiator, error)
+	SelectJobInitiatorsByInitiator(initiator string, lastID int, entries int) ([]*model.JobInitiator, error)
+	SelectTestJobInitiators(lastID int, entries int) ([]*model.JobInitiator, error)
+	SelectJobInitiatorsCountByInitiator(initiator string) (int, error)
+	SelectJobInitiatorsCountByInitiatorSince(initiator string, since time.Time) (int, error)
+	SelectTestJobInitiatorsCount() (int, error)
+}
+
+// JobInitiatorDBHandler implements JobInitiatorDBHandlerFunctions and holds the database connection.
+type JobInitiatorDBHandler struct {
+	db *helper.Database
+}
+
+// NewJobInitiatorDBHandler creates a new instance of JobInitiatorDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing job_initiator table before creating a new one
+func NewJobInitiatorDBHandler(dbConnection *helper.Database, withTableDrop bool) (*JobInitiatorDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	jobInitiatorDbHandler := &JobInitiatorDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := jobInitiatorDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := jobInitiatorDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return jobInitiatorDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'job_initiator' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r JobInitiatorDBHandler) CheckTableExistance() (bool, error) {
+	jobInitiatorExists, err := r.db.CheckTableExistance("job_initiator")
+	if err != nil {
+		return false, helper.NewError("job_initiator table", err)
+	}
+	return jobInitiatorExists, nil
+}
+
+// CreateTable creates the 'job_initiator' table in the database.
+// If the table already exists, it does not create it again.
+func (r JobInitiatorDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS job_initiator (
+			id SERIAL PRIMARY KEY,
+			job_rid UUID UNIQUE NOT NULL,
+			task_key VARCHAR(100) NOT NULL,
+			initiator VARCHAR(100) NOT NULL,
+			test BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+		ALTER TABLE job_initiator ADD COLUMN IF NOT EXISTS test BOOLEAN NOT NULL DEFAULT FALSE;
+		CREATE INDEX IF NOT EXISTS idx_job_initiator_initiator ON job_initiator (initiator);
+		CREATE INDEX IF NOT EXISTS idx_job_initiator_test ON job_initiator (test) WHERE test;
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create job_initiator table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table job_initiator")
+
+	return nil
+}
+
+// DropTable drops the 'job_initiator' table from the database.
+func (r JobInitiatorDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS job_initiator`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop job_initiator table", err)
+	}
+
+	r.db.Logger.Info("Dropped table job_initiator")
+
+	return nil
+}
+
+// InsertJobInitiator inserts the initiator of an added job into the database.
+func (r JobInitiatorDBHandler) InsertJobInitiator(jobInitiator *model.JobInitiator) (*model.JobInitiator, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		INSERT INTO job_initiator (
+			job_rid,
+			task_key,
+			initiator,
+			test
+		) VALUES ($1, $2, $3, $4)
+		RETURNING
+			id,
+			job_rid,
+			task_key,
+			initiator,
+			test,
+			created_at`
+
+	row := r.db.Instance.QueryRowContext(ctx, query, jobInitiator.JobRID, jobInitiator.TaskKey, jobInitiator.Initiator, jobInitiator.Test)
+	insertedJobInitiator, err := scanJobInitiator(row)
+	if err != nil {
+		return nil, helper.NewError("insert job initiator", err)
+	}
+
+	return insertedJobInitiator, nil
+}
+
+// SelectJobInitiator retrieves the initiator of a job by the job RID.
+func (r JobInitiatorDBHandler) SelectJobInitiator(jobRID uuid.UUID) (*model.JobInitiator, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			job_rid,
+			task_key,
+			initiator,
+			test,
+			created_at
+		FROM job_initiator
+		WHERE job_rid = $1
+	`
+
+	jobInitiator, err := scanJobInitiator(r.db.Instance.QueryRowContext(ctx, query, jobRID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("job initiator not found", fmt.Errorf("no initiator of job %s", jobRID))
+		}
+		return nil, helper.NewError("select job initiator", err)
+	}
+
+	return jobInitiator, nil
+}
+
+// SelectJobInitiatorsByInitiator retrieves the jobs added by the initiator with pagination, newest first.
+// lastID is the ID of the last entry from the previous page (0 for first page)
+// entries is the maximum number of entries to return
+func (r JobInitiatorDBHandler) SelectJobInitiatorsByInitiator(initiator string, lastID int, entries int) ([]*model.JobInitiator, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			job_rid,
+			task_key,
+			initiator,
+			test,
+			created_at
+		FROM job_initiator
+		WHERE initiator = $1
+			AND ($2 = 0 OR id < $2)
+		ORDER BY id DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, initiator, lastID, entries)
+	if err != nil {
+		return nil, helper.NewError("select job initiators by initiator", err)
+	}
+	defer rows.Close()
+
+	jobInitiators := []*model.JobInitiator{}
+	for rows.Next() {
+		jobInitiator, err := scanJobInitiator(rows)
+		if err != nil {
+			return nil, helper.NewError("scan job initiator", err)
+		}
+		jobInitiators = append(jobInitiators, jobInitiator)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return jobInitiators, nil
+}
+
+// SelectTestJobInitiators retrieves the jobs added as test runs with pagination, newest first.
+// lastID is the ID of the last entry from the previous page (0 for first page)
+// entries is the maximum number of entries to return
+func (r JobInitiatorDBHandler) SelectTestJobInitiators(lastID int, entries int) ([]*model.JobInitiator, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			job_rid,
+			task_key,
+			initiator,
+			test,
+			created_at
+		FROM job_initiator
+		WHERE test
+			AND ($1 = 0 OR id < $1)
+		ORDER BY id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, lastID, entries)
+	if err != nil {
+		return nil, helper.NewError("select test job initiators", err)
+	}
+	defer rows.Close()
+
+	jobInitiators := []*model.JobInitiator{}
+	for rows.Next() {
+		jobInitiator, err := scanJobInitiator(rows)
+		if err != nil {
+			return nil, helper.NewError("scan job initiator", err)
+		}
+		jobInitiators = append(jobInitiators, jobInitiator)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return jobInitiators, nil
+}
+
+// scanJobInitiator scans a job initiator row in the column order of the job initiator queries.
+func scanJobInitiator(row interface{ Scan(dest ...any) error }) (*model.JobInitiator, error) {
+	jobInitiator := &model.JobInitiator{}
+	err := row.Scan(
+		&jobInitiator.ID,
+		&jobInitiator.JobRID,
+		&jobInitiator.TaskKey,
+		&jobInitiator.Initiator,
+		&jobInitiator.Test,
+		&jobInitiator.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return jobInitiator, nil
+}
+
+// SelectJobInitiatorsCountByInitiator counts the jobs added by the initiator.
+func (r JobInitiatorDBHandler) SelectJobInitiatorsCountByInitiator(initiator string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `SELECT COUNT(*) FROM job_initiator WHERE initiator = $1`
+
+	var count int
+	err := r.db.Instance.QueryRowContext(ctx, query, initiator).Scan(&count)
+	if err != nil {
+		return 0, helper.NewError("count job initiators by initiator", err)
+	}
+
+	return count, nil
+}
+
+// SelectJobInitiatorsCountByInitiatorSince counts the jobs added by the initiator since the time, eg. for the job quota.
+func (r JobInitiatorDBHandler) SelectJobInitiatorsCountByInitiatorSince(initiator string, since time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `SELECT COUNT(*) FROM job_initiator WHERE initiator = $1 AND created_at >= $2`
+
+	var count int
+	err := r.db.Instance.QueryRowContext(ctx, query, initiator, since).Scan(&count)
+	if err != nil {
+		return 0, helper.NewError("count job initiators by initiator since", err)
+	}
+
+	return count, nil
+}
+
+// SelectTestJobInitiatorsCount counts the jobs added as test runs.
+func (r JobInitiatorDBHandler) SelectTestJobInitiatorsCount() (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `SELECT COUNT(*) FROM job_initiator WHERE test`
+
+	var count int
+	err := r.db.Instance.QueryRowContext(ctx, query).Scan(&count)
+	if err != nil {
+		return 0, helper.NewError("count test job initiators", err)
+	}
+
+	return count, nil
+}
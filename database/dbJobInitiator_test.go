@@ -0,0 +1,140 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobInitiatorNewJobInitiatorDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewJobInitiatorDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		jobInitiatorDbHandler, err := NewJobInitiatorDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewJobInitiatorDBHandler to not return an error")
+		require.NotNil(t, jobInitiatorDbHandler, "Expected NewJobInitiatorDBHandler to return a non-nil instance")
+
+		exists, err := jobInitiatorDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = jobInitiatorDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewJobInitiatorDBHandler with nil database", func(t *testing.T) {
+		_, err := NewJobInitiatorDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating JobInitiatorDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestJobInitiatorInsertAndSelectJobInitiator(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	jobInitiatorDbHandler, err := NewJobInitiatorDBHandler(database, true)
+	require.NoError(t, err, "Expected NewJobInitiatorDBHandler to not return an error")
+
+	jobRID := uuid.New()
+	insertedJobInitiator, err := jobInitiatorDbHandler.InsertJobInitiator(&model.JobInitiator{
+		JobRID:    jobRID,
+		TaskKey:   "test-task",
+		Initiator: "alice",
+	})
+	require.NoError(t, err, "Expected InsertJobInitiator to not return an error")
+	assert.Equal(t, jobRID, insertedJobInitiator.JobRID)
+	assert.False(t, insertedJobInitiator.CreatedAt.IsZero())
+
+	_, err = jobInitiatorDbHandler.InsertJobInitiator(&model.JobInitiator{JobRID: jobRID, TaskKey: "test-task", Initiator: "bob"})
+	assert.Error(t, err, "Expected InsertJobInitiator to return an error for a job that already has an initiator")
+
+	selectedJobInitiator, err := jobInitiatorDbHandler.SelectJobInitiator(jobRID)
+	require.NoError(t, err, "Expected SelectJobInitiator to not return an error")
+	assert.Equal(t, "alice", selectedJobInitiator.Initiator)
+	assert.Equal(t, "test-task", selectedJobInitiator.TaskKey)
+
+	_, err = jobInitiatorDbHandler.SelectJobInitiator(uuid.New())
+	assert.Error(t, err, "Expected SelectJobInitiator to return an error for a job without initiator")
+}
+
+func TestJobInitiatorSelectJobInitiatorsByInitiator(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	jobInitiatorDbHandler, err := NewJobInitiatorDBHandler(database, true)
+	require.NoError(t, err, "Expected NewJobInitiatorDBHandler to not return an error")
+
+	for _, initiator := range []string{"alice", "bob", "alice", "alice"} {
+		_, err := jobInitiatorDbHandler.InsertJobInitiator(&model.JobInitiator{JobRID: uuid.New(), TaskKey: "test-task", Initiator: initiator})
+		require.NoError(t, err)
+	}
+
+	jobInitiators, err := jobInitiatorDbHandler.SelectJobInitiatorsByInitiator("alice", 0, 2)
+	require.NoError(t, err, "Expected SelectJobInitiatorsByInitiator to not return an error")
+	require.Len(t, jobInitiators, 2)
+	assert.Greater(t, jobInitiators[0].ID, jobInitiators[1].ID, "Expected newest job first")
+
+	nextJobInitiators, err := jobInitiatorDbHandler.SelectJobInitiatorsByInitiator("alice", jobInitiators[1].ID, 2)
+	require.NoError(t, err)
+	assert.Len(t, nextJobInitiators, 1)
+
+	noJobInitiators, err := jobInitiatorDbHandler.SelectJobInitiatorsByInitiator("carol", 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, noJobInitiators)
+
+	count, err := jobInitiatorDbHandler.SelectJobInitiatorsCountByInitiatorSince("alice", time.Now().Add(-time.Hour))
+	require.NoError(t, err, "Expected SelectJobInitiatorsCountByInitiatorSince to not return an error")
+	assert.Equal(t, 3, count)
+
+	count, err = jobInitiatorDbHandler.SelectJobInitiatorsCountByInitiatorSince("alice", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 0, count, "Expected no jobs added in the future")
+}
+
+func TestJobInitiatorSelectTestJobInitiators(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	jobInitiatorDbHandler, err := NewJobInitiatorDBHandler(database, true)
+	require.NoError(t, err, "Expected NewJobInitiatorDBHandler to not return an error")
+
+	for _, test := range []bool{true, false, true} {
+		_, err := jobInitiatorDbHandler.InsertJobInitiator(&model.JobInitiator{JobRID: uuid.New(), TaskKey: "test-task", Initiator: "alice", Test: test})
+		require.NoError(t, err)
+	}
+
+	jobInitiators, err := jobInitiatorDbHandler.SelectTestJobInitiators(0, 10)
+	require.NoError(t, err, "Expected SelectTestJobInitiators to not return an error")
+	require.Len(t, jobInitiators, 2)
+	assert.True(t, jobInitiators[0].Test)
+	assert.Greater(t, jobInitiators[0].ID, jobInitiators[1].ID, "Expected newest job first")
+
+	nextJobInitiators, err := jobInitiatorDbHandler.SelectTestJobInitiators(jobInitiators[0].ID, 10)
+	require.NoError(t, err)
+	assert.Len(t, nextJobInitiators, 1)
+}
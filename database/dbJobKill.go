@@ -0,0 +1,179 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+)
+
+// JobKillDBHandlerFunctions defines the interface for JobKill database operations.
+type JobKillDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	InsertJobKill(jobKill *model.JobKill) (*model.JobKill, error)
+	SelectJobKill(jobRID uuid.UUID) (*model.JobKill, error)
+}
+
+// JobKillDBHandler implements JobKillDBHandlerFunctions and holds the database connection.
+type JobKillDBHandler struct {
+	db *helper.Database
+}
+
+// NewJobKillDBHandler creates a new instance of JobKillDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing job_kill table before creating a new one
+func NewJobKillDBHandler(dbConnection *helper.Database, withTableDrop bool) (*JobKillDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	jobKillDbHandler := &JobKillDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := jobKillDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := jobKillDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return jobKillDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'job_kill' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r JobKillDBHandler) CheckTableExistance() (bool, error) {
+	jobKillExists, err := r.db.CheckTableExistance("job_kill")
+	if err != nil {
+		return false, helper.NewError("job_kill table", err)
+	}
+	return jobKillExists, nil
+}
+
+// CreateTable creates the 'job_kill' table in the database.
+// If the table already exists, it does not create it again.
+func (r JobKillDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS job_kill (
+			id SERIAL PRIMARY KEY,
+			job_rid UUID UNIQUE NOT NULL,
+			worker_rid UUID NOT NULL,
+			requested_by VARCHAR(100) NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create job_kill table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table job_kill")
+
+	return nil
+}
+
+// DropTable drops the 'job_kill' table from the database.
+func (r JobKillDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS job_kill`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop job_kill table", err)
+	}
+
+	r.db.Logger.Info("Dropped table job_kill")
+
+	return nil
+}
+
+// InsertJobKill inserts the record of a hard killed job into the database.
+func (r JobKillDBHandler) InsertJobKill(jobKill *model.JobKill) (*model.JobKill, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	newJobKill := &model.JobKill{}
+	query := `
+		INSERT INTO job_kill (
+			job_rid,
+			worker_rid,
+			requested_by
+		) VALUES ($1, $2, $3)
+		RETURNING
+			id,
+			job_rid,
+			worker_rid,
+			requested_by,
+			created_at`
+
+	err := r.db.Instance.QueryRowContext(
+		ctx,
+		query,
+		jobKill.JobRID,
+		jobKill.WorkerRID,
+		jobKill.RequestedBy,
+	).Scan(
+		&newJobKill.ID,
+		&newJobKill.JobRID,
+		&newJobKill.WorkerRID,
+		&newJobKill.RequestedBy,
+		&newJobKill.CreatedAt,
+	)
+	if err != nil {
+		return nil, helper.NewError("insert job kill", err)
+	}
+
+	return newJobKill, nil
+}
+
+// SelectJobKill retrieves the hard kill of a job by the job RID.
+func (r JobKillDBHandler) SelectJobKill(jobRID uuid.UUID) (*model.JobKill, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			job_rid,
+			worker_rid,
+			requested_by,
+			created_at
+		FROM job_kill
+		WHERE job_rid = $1
+	`
+
+	jobKill := &model.JobKill{}
+	err := r.db.Instance.QueryRowContext(ctx, query, jobRID).Scan(
+		&jobKill.ID,
+		&jobKill.JobRID,
+		&jobKill.WorkerRID,
+		&jobKill.RequestedBy,
+		&jobKill.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("job kill not found", fmt.Errorf("no job kill of job %s", jobRID))
+		}
+		return nil, helper.NewError("select job kill", err)
+	}
+
+	return jobKill, nil
+}
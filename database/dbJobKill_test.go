@@ -0,0 +1,75 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobKillNewJobKillDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewJobKillDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		jobKillDbHandler, err := NewJobKillDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewJobKillDBHandler to not return an error")
+		require.NotNil(t, jobKillDbHandler, "Expected NewJobKillDBHandler to return a non-nil instance")
+
+		exists, err := jobKillDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = jobKillDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewJobKillDBHandler with nil database", func(t *testing.T) {
+		_, err := NewJobKillDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating JobKillDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestJobKillInsertAndSelectJobKill(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	jobKillDbHandler, err := NewJobKillDBHandler(database, true)
+	require.NoError(t, err, "Expected NewJobKillDBHandler to not return an error")
+
+	jobRID := uuid.New()
+	workerRID := uuid.New()
+	insertedJobKill, err := jobKillDbHandler.InsertJobKill(&model.JobKill{
+		JobRID:      jobRID,
+		WorkerRID:   workerRID,
+		RequestedBy: "alice",
+	})
+	require.NoError(t, err, "Expected InsertJobKill to not return an error")
+	assert.Equal(t, jobRID, insertedJobKill.JobRID)
+	assert.False(t, insertedJobKill.CreatedAt.IsZero())
+
+	_, err = jobKillDbHandler.InsertJobKill(&model.JobKill{JobRID: jobRID, WorkerRID: workerRID})
+	assert.Error(t, err, "Expected InsertJobKill to return an error for a job that was already killed")
+
+	selectedJobKill, err := jobKillDbHandler.SelectJobKill(jobRID)
+	require.NoError(t, err, "Expected SelectJobKill to not return an error")
+	assert.Equal(t, workerRID, selectedJobKill.WorkerRID)
+	assert.Equal(t, "alice", selectedJobKill.RequestedBy)
+
+	_, err = jobKillDbHandler.SelectJobKill(uuid.New())
+	assert.Error(t, err, "Expected SelectJobKill to return an error for a job that was not killed")
+}
@@ -0,0 +1,222 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	qmodel "github.com/siherrmann/queuer/model"
+)
+
+// JobOverrideDBHandlerFunctions defines the interface for JobOverride database operations.
+type JobOverrideDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	OverrideJobStatus(job *qmodel.Job, jobOverride *model.JobOverride) (*model.JobOverride, error)
+	SelectJobOverride(jobRID uuid.UUID) (*model.JobOverride, error)
+}
+
+// JobOverrideDBHandler implements JobOverrideDBHandlerFunctions and holds the database connection.
+type JobOverrideDBHandler struct {
+	db *helper.Database
+}
+
+// NewJobOverrideDBHandler creates a new instance of JobOverrideDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing job_override table before creating a new one
+func NewJobOverrideDBHandler(dbConnection *helper.Database, withTableDrop bool) (*JobOverrideDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	jobOverrideDbHandler := &JobOverrideDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := jobOverrideDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := jobOverrideDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return jobOverrideDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'job_override' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r JobOverrideDBHandler) CheckTableExistance() (bool, error) {
+	jobOverrideExists, err := r.db.CheckTableExistance("job_override")
+	if err != nil {
+		return false, helper.NewError("job_override table", err)
+	}
+	return jobOverrideExists, nil
+}
+
+// CreateTable creates the 'job_override' table in the database.
+// If the table already exists, it does not create it again.
+func (r JobOverrideDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS job_override (
+			id SERIAL PRIMARY KEY,
+			job_rid UUID UNIQUE NOT NULL,
+			previous_status VARCHAR(50) NOT NULL,
+			status VARCHAR(50) NOT NULL,
+			reason TEXT NOT NULL,
+			requested_by VARCHAR(100) NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create job_override table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table job_override")
+
+	return nil
+}
+
+// DropTable drops the 'job_override' table from the database.
+func (r JobOverrideDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS job_override`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop job_override table", err)
+	}
+
+	r.db.Logger.Info("Dropped table job_override")
+
+	return nil
+}
+
+// OverrideJobStatus ends the active job with the status of the override and records the override in one transaction.
+// The job is moved to the archive like a job ended by its worker, the reason is stored as error of the job.
+// It returns an error if the job is not active anymore, eg. because its worker ended it in the meantime.
+func (r JobOverrideDBHandler) OverrideJobStatus(job *qmodel.Job, jobOverride *model.JobOverride) (*model.JobOverride, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := r.db.Instance.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, helper.NewError("begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	var archivedJobRID uuid.UUID
+	err = tx.QueryRowContext(
+		ctx,
+		`SELECT output_rid FROM update_job_final($1, $2, NULL, $3)`,
+		job.ID,
+		jobOverride.Status,
+		fmt.Sprintf("status overridden by %s: %s", jobOverride.RequestedBy, jobOverride.Reason),
+	).Scan(&archivedJobRID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("job not active", fmt.Errorf("job %s is not active anymore", job.RID))
+		}
+		return nil, helper.NewError("update job final", err)
+	}
+
+	newJobOverride := &model.JobOverride{}
+	query := `
+		INSERT INTO job_override (
+			job_rid,
+			previous_status,
+			status,
+			reason,
+			requested_by
+		) VALUES ($1, $2, $3, $4, $5)
+		RETURNING
+			id,
+			job_rid,
+			previous_status,
+			status,
+			reason,
+			requested_by,
+			created_at`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		archivedJobRID,
+		job.Status,
+		jobOverride.Status,
+		jobOverride.Reason,
+		jobOverride.RequestedBy,
+	).Scan(
+		&newJobOverride.ID,
+		&newJobOverride.JobRID,
+		&newJobOverride.PreviousStatus,
+		&newJobOverride.Status,
+		&newJobOverride.Reason,
+		&newJobOverride.RequestedBy,
+		&newJobOverride.CreatedAt,
+	)
+	if err != nil {
+		return nil, helper.NewError("insert job status override", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, helper.NewError("commit transaction", err)
+	}
+
+	return newJobOverride, nil
+}
+
+// SelectJobOverride retrieves the status override of a job by the job RID.
+func (r JobOverrideDBHandler) SelectJobOverride(jobRID uuid.UUID) (*model.JobOverride, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			job_rid,
+			previous_status,
+			status,
+			reason,
+			requested_by,
+			created_at
+		FROM job_override
+		WHERE job_rid = $1
+	`
+
+	jobOverride := &model.JobOverride{}
+	err := r.db.Instance.QueryRowContext(ctx, query, jobRID).Scan(
+		&jobOverride.ID,
+		&jobOverride.JobRID,
+		&jobOverride.PreviousStatus,
+		&jobOverride.Status,
+		&jobOverride.Reason,
+		&jobOverride.RequestedBy,
+		&jobOverride.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("job status override not found", fmt.Errorf("no status override of job %s", jobRID))
+		}
+		return nil, helper.NewError("select job status override", err)
+	}
+
+	return jobOverride, nil
+}
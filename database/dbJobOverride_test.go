@@ -0,0 +1,42 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobOverrideNewJobOverrideDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewJobOverrideDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		jobOverrideDbHandler, err := NewJobOverrideDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewJobOverrideDBHandler to not return an error")
+		require.NotNil(t, jobOverrideDbHandler, "Expected NewJobOverrideDBHandler to return a non-nil instance")
+
+		exists, err := jobOverrideDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		_, err = jobOverrideDbHandler.SelectJobOverride(uuid.New())
+		assert.Error(t, err, "Expected SelectJobOverride to return an error for a job without override")
+
+		err = jobOverrideDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewJobOverrideDBHandler with nil database", func(t *testing.T) {
+		_, err := NewJobOverrideDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating JobOverrideDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
@@ -0,0 +1,278 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+)
+
+// JobTemplateDBHandlerFunctions defines the interface for JobTemplate database operations.
+type JobTemplateDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	UpsertJobTemplate(jobTemplate *model.JobTemplate) (*model.JobTemplate, error)
+	SelectJobTemplate(rid uuid.UUID) (*model.JobTemplate, error)
+	SelectJobTemplatesByTask(taskKey string) ([]*model.JobTemplate, error)
+	DeleteJobTemplate(rid uuid.UUID) error
+}
+
+// JobTemplateDBHandler implements JobTemplateDBHandlerFunctions and holds the database connection.
+type JobTemplateDBHandler struct {
+	db *helper.Database
+}
+
+// NewJobTemplateDBHandler creates a new instance of JobTemplateDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing job_template table before creating a new one
+func NewJobTemplateDBHandler(dbConnection *helper.Database, withTableDrop bool) (*JobTemplateDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	jobTemplateDbHandler := &JobTemplateDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := jobTemplateDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := jobTemplateDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return jobTemplateDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'job_template' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r JobTemplateDBHandler) CheckTableExistance() (bool, error) {
+	jobTemplateExists, err := r.db.CheckTableExistance("job_template")
+	if err != nil {
+		return false, helper.NewError("job_template table", err)
+	}
+	return jobTemplateExists, nil
+}
+
+// CreateTable creates the 'job_template' table in the database.
+// If the table already exists, it does not create it again.
+func (r JobTemplateDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS job_template (
+			id SERIAL PRIMARY KEY,
+			rid UUID UNIQUE NOT NULL DEFAULT gen_random_uuid(),
+			name VARCHAR(100) NOT NULL,
+			task_key VARCHAR(100) NOT NULL,
+			template_values JSONB NOT NULL DEFAULT '{}'::jsonb,
+			created_by VARCHAR(255) NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			UNIQUE (task_key, name)
+		);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create job_template table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table job_template")
+
+	return nil
+}
+
+// DropTable drops the 'job_template' table from the database.
+func (r JobTemplateDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS job_template`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop job_template table", err)
+	}
+
+	r.db.Logger.Info("Dropped table job_template")
+
+	return nil
+}
+
+// UpsertJobTemplate creates a job template or replaces the values of the template of the task with the same name.
+func (r JobTemplateDBHandler) UpsertJobTemplate(jobTemplate *model.JobTemplate) (*model.JobTemplate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	valuesJSON, err := marshalSampleValues(jobTemplate.Values)
+	if err != nil {
+		return nil, helper.NewError("marshal template values", err)
+	}
+
+	query := `
+		INSERT INTO job_template (
+			name,
+			task_key,
+			template_values,
+			created_by
+		) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (task_key, name) DO UPDATE
+		SET
+			template_values = EXCLUDED.template_values,
+			updated_at = NOW()
+		RETURNING
+			id,
+			rid,
+			name,
+			task_key,
+			template_values,
+			created_by,
+			created_at,
+			updated_at`
+
+	upsertedJobTemplate, err := scanJobTemplate(r.db.Instance.QueryRowContext(
+		ctx,
+		query,
+		jobTemplate.Name,
+		jobTemplate.TaskKey,
+		valuesJSON,
+		jobTemplate.CreatedBy,
+	))
+	if err != nil {
+		return nil, helper.NewError("upsert job template", err)
+	}
+
+	return upsertedJobTemplate, nil
+}
+
+// SelectJobTemplate retrieves a job template by its RID.
+func (r JobTemplateDBHandler) SelectJobTemplate(rid uuid.UUID) (*model.JobTemplate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			rid,
+			name,
+			task_key,
+			template_values,
+			created_by,
+			created_at,
+			updated_at
+		FROM job_template
+		WHERE rid = $1
+	`
+
+	jobTemplate, err := scanJobTemplate(r.db.Instance.QueryRowContext(ctx, query, rid))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("job template not found", fmt.Errorf("no job template with RID %s", rid))
+		}
+		return nil, helper.NewError("select job template", err)
+	}
+
+	return jobTemplate, nil
+}
+
+// SelectJobTemplatesByTask retrieves the job templates of a task ordered by name.
+func (r JobTemplateDBHandler) SelectJobTemplatesByTask(taskKey string) ([]*model.JobTemplate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			rid,
+			name,
+			task_key,
+			template_values,
+			created_by,
+			created_at,
+			updated_at
+		FROM job_template
+		WHERE task_key = $1
+		ORDER BY name ASC
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, taskKey)
+	if err != nil {
+		return nil, helper.NewError("select job templates", err)
+	}
+	defer rows.Close()
+
+	jobTemplates := []*model.JobTemplate{}
+	for rows.Next() {
+		jobTemplate, err := scanJobTemplate(rows)
+		if err != nil {
+			return nil, helper.NewError("scan job template", err)
+		}
+		jobTemplates = append(jobTemplates, jobTemplate)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return jobTemplates, nil
+}
+
+// DeleteJobTemplate deletes a job template by its RID.
+func (r JobTemplateDBHandler) DeleteJobTemplate(rid uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := r.db.Instance.ExecContext(ctx, `DELETE FROM job_template WHERE rid = $1`, rid)
+	if err != nil {
+		return helper.NewError("delete job template", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return helper.NewError("rows affected", err)
+	}
+	if deleted == 0 {
+		return helper.NewError("job template not found", fmt.Errorf("no job template with RID %s", rid))
+	}
+
+	return nil
+}
+
+// scanJobTemplate scans a job template row in the column order of the job template queries.
+func scanJobTemplate(row interface{ Scan(dest ...any) error }) (*model.JobTemplate, error) {
+	jobTemplate := &model.JobTemplate{}
+	var valuesData []byte
+	err := row.Scan(
+		&jobTemplate.ID,
+		&jobTemplate.RID,
+		&jobTemplate.Name,
+		&jobTemplate.TaskKey,
+		&valuesData,
+		&jobTemplate.CreatedBy,
+		&jobTemplate.CreatedAt,
+		&jobTemplate.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(valuesData, &jobTemplate.Values)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal template values: %w", err)
+	}
+
+	return jobTemplate, nil
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobTemplateNewJobTemplateDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewJobTemplateDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		jobTemplateDbHandler, err := NewJobTemplateDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewJobTemplateDBHandler to not return an error")
+		require.NotNil(t, jobTemplateDbHandler, "Expected NewJobTemplateDBHandler to return a non-nil instance")
+
+		exists, err := jobTemplateDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = jobTemplateDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewJobTemplateDBHandler with nil database", func(t *testing.T) {
+		_, err := NewJobTemplateDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating JobTemplateDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestJobTemplateUpsertSelectAndDeleteJobTemplate(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	jobTemplateDbHandler, err := NewJobTemplateDBHandler(database, true)
+	require.NoError(t, err, "Expected NewJobTemplateDBHandler to not return an error")
+
+	insertedJobTemplate, err := jobTemplateDbHandler.UpsertJobTemplate(&model.JobTemplate{
+		Name:      "small",
+		TaskKey:   "test-task",
+		Values:    map[string]string{"count": "3"},
+		CreatedBy: "alice",
+	})
+	require.NoError(t, err, "Expected UpsertJobTemplate to not return an error")
+	assert.NotEqual(t, uuid.Nil, insertedJobTemplate.RID)
+	assert.Equal(t, "3", insertedJobTemplate.Values["count"])
+	assert.Equal(t, "alice", insertedJobTemplate.CreatedBy)
+
+	t.Run("Upsert replaces the values of a template with the same name", func(t *testing.T) {
+		replacedJobTemplate, err := jobTemplateDbHandler.UpsertJobTemplate(&model.JobTemplate{
+			Name:      "small",
+			TaskKey:   "test-task",
+			Values:    map[string]string{"count": "5"},
+			CreatedBy: "bob",
+		})
+		require.NoError(t, err, "Expected UpsertJobTemplate to not return an error")
+		assert.Equal(t, insertedJobTemplate.RID, replacedJobTemplate.RID)
+		assert.Equal(t, "5", replacedJobTemplate.Values["count"])
+		assert.Equal(t, "alice", replacedJobTemplate.CreatedBy, "Expected the creator to not change")
+
+		_, err = jobTemplateDbHandler.UpsertJobTemplate(&model.JobTemplate{Name: "small", TaskKey: "other-task"})
+		require.NoError(t, err, "Expected the same name to be allowed for another task")
+		_, err = jobTemplateDbHandler.UpsertJobTemplate(&model.JobTemplate{Name: "large", TaskKey: "test-task", Values: map[string]string{"count": "100"}})
+		require.NoError(t, err)
+
+		jobTemplates, err := jobTemplateDbHandler.SelectJobTemplatesByTask("test-task")
+		require.NoError(t, err, "Expected SelectJobTemplatesByTask to not return an error")
+		require.Len(t, jobTemplates, 2)
+		assert.Equal(t, "large", jobTemplates[0].Name, "Expected the templates to be ordered by name")
+		assert.Equal(t, "small", jobTemplates[1].Name)
+	})
+
+	t.Run("Delete template", func(t *testing.T) {
+		selectedJobTemplate, err := jobTemplateDbHandler.SelectJobTemplate(insertedJobTemplate.RID)
+		require.NoError(t, err, "Expected SelectJobTemplate to not return an error")
+		assert.Equal(t, "small", selectedJobTemplate.Name)
+
+		err = jobTemplateDbHandler.DeleteJobTemplate(insertedJobTemplate.RID)
+		require.NoError(t, err, "Expected DeleteJobTemplate to not return an error")
+
+		_, err = jobTemplateDbHandler.SelectJobTemplate(insertedJobTemplate.RID)
+		assert.Error(t, err, "Expected SelectJobTemplate to return an error for a deleted template")
+
+		err = jobTemplateDbHandler.DeleteJobTemplate(insertedJobTemplate.RID)
+		assert.Error(t, err, "Expected DeleteJobTemplate to return an error for a deleted template")
+	})
+}
@@ -0,0 +1,180 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobNewJobDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewJobDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+		createQueuerTables(t, database)
+
+		jobDbHandler, err := NewJobDBHandler(database)
+		assert.NoError(t, err, "Expected NewJobDBHandler to not return an error")
+		require.NotNil(t, jobDbHandler, "Expected NewJobDBHandler to return a non-nil instance")
+
+		exists, err := jobDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("Invalid call NewJobDBHandler with nil database", func(t *testing.T) {
+		_, err := NewJobDBHandler(nil)
+		assert.Error(t, err, "Expected error when creating JobDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestJobSelectJobRIDsByFilter(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+	createQueuerTables(t, database)
+
+	jobDbHandler, err := NewJobDBHandler(database)
+	require.NoError(t, err, "Expected NewJobDBHandler to not return an error")
+
+	oldRID, failedRID, queuedRID, otherRID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	_, err = database.Instance.Exec(`
+		INSERT INTO job (rid, task_name, status, created_at, updated_at) VALUES
+			($1, 'filter-task', 'FAILED', (NOW() AT TIME ZONE 'UTC') - INTERVAL '3 hours', NOW()),
+			($2, 'filter-task', 'FAILED', (NOW() AT TIME ZONE 'UTC') - INTERVAL '20 minutes', NOW()),
+			($3, 'filter-task', 'QUEUED', (NOW() AT TIME ZONE 'UTC') - INTERVAL '10 minutes', NOW()),
+			($4, 'filter-other', 'FAILED', (NOW() AT TIME ZONE 'UTC') - INTERVAL '5 minutes', NOW())
+	`, oldRID, failedRID, queuedRID, otherRID)
+	require.NoError(t, err)
+
+	t.Run("Filter by status and task key", func(t *testing.T) {
+		filter := &model.JobFilter{Status: "FAILED", TaskKey: "filter-task"}
+		rids, err := jobDbHandler.SelectJobRIDsByFilter(filter, 0, 10)
+		require.NoError(t, err, "Expected SelectJobRIDsByFilter to not return an error")
+		assert.Equal(t, []uuid.UUID{failedRID, oldRID}, rids, "Expected the newest job first")
+
+		count, err := jobDbHandler.SelectJobsCountByFilter(filter)
+		require.NoError(t, err, "Expected SelectJobsCountByFilter to not return an error")
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("Filter by time range", func(t *testing.T) {
+		filter := &model.JobFilter{Status: "FAILED", From: time.Now().Add(-time.Hour)}
+		rids, err := jobDbHandler.SelectJobRIDsByFilter(filter, 0, 10)
+		require.NoError(t, err, "Expected SelectJobRIDsByFilter to not return an error")
+		assert.Equal(t, []uuid.UUID{otherRID, failedRID}, rids)
+
+		filter = &model.JobFilter{TaskKey: "filter-task", From: time.Now().Add(-time.Hour), To: time.Now().Add(-15 * time.Minute)}
+		rids, err = jobDbHandler.SelectJobRIDsByFilter(filter, 0, 10)
+		require.NoError(t, err, "Expected SelectJobRIDsByFilter to not return an error")
+		assert.Equal(t, []uuid.UUID{failedRID}, rids)
+	})
+
+	t.Run("Filter with search and pagination", func(t *testing.T) {
+		filter := &model.JobFilter{Search: "filter-", Status: "FAILED"}
+		rids, err := jobDbHandler.SelectJobRIDsByFilter(filter, 0, 2)
+		require.NoError(t, err, "Expected SelectJobRIDsByFilter to not return an error")
+		assert.Equal(t, []uuid.UUID{otherRID, failedRID}, rids)
+
+		var lastID int
+		err = database.Instance.QueryRow(`SELECT id FROM job WHERE rid = $1`, failedRID).Scan(&lastID)
+		require.NoError(t, err)
+
+		rids, err = jobDbHandler.SelectJobRIDsByFilter(filter, lastID, 2)
+		require.NoError(t, err, "Expected SelectJobRIDsByFilter to not return an error")
+		assert.Equal(t, []uuid.UUID{oldRID}, rids)
+	})
+}
+
+func TestJobSelectTaskUtilization(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+	createQueuerTables(t, database)
+
+	jobDbHandler, err := NewJobDBHandler(database)
+	require.NoError(t, err, "Expected NewJobDBHandler to not return an error")
+
+	_, err = database.Instance.Exec(`
+		INSERT INTO job (rid, task_name, status, created_at, updated_at) VALUES
+			($1, 'limit-task', 'RUNNING', (NOW() AT TIME ZONE 'UTC') - INTERVAL '10 minutes', NOW()),
+			($2, 'limit-task', 'QUEUED', (NOW() AT TIME ZONE 'UTC') - INTERVAL '10 seconds', NOW()),
+			($3, 'limit-task', 'FAILED', (NOW() AT TIME ZONE 'UTC') - INTERVAL '20 seconds', NOW()),
+			($4, 'limit-other', 'RUNNING', (NOW() AT TIME ZONE 'UTC') - INTERVAL '5 seconds', NOW())
+	`, uuid.New(), uuid.New(), uuid.New(), uuid.New())
+	require.NoError(t, err)
+	_, err = database.Instance.Exec(`
+		INSERT INTO job_archive (rid, task_name, status, created_at, updated_at) VALUES
+			($1, 'limit-task', 'SUCCEEDED', (NOW() AT TIME ZONE 'UTC') - INTERVAL '30 seconds', NOW()),
+			($2, 'limit-task', 'SUCCEEDED', (NOW() AT TIME ZONE 'UTC') - INTERVAL '1 hour', NOW())
+	`, uuid.New(), uuid.New())
+	require.NoError(t, err)
+
+	utilization, err := jobDbHandler.SelectTaskUtilization("limit-task", time.Now().Add(-model.TASK_RATE_LIMIT_WINDOW))
+	require.NoError(t, err, "Expected SelectTaskUtilization to not return an error")
+	assert.Equal(t, "limit-task", utilization.TaskKey)
+	assert.Equal(t, 1, utilization.Running)
+	assert.Equal(t, 2, utilization.Active, "Expected the ended jobs to not be active")
+	assert.Equal(t, 3, utilization.Added, "Expected the archived jobs of the last minute to be counted")
+}
+
+func TestJobSelectScheduledJobRIDs(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+	createQueuerTables(t, database)
+
+	jobDbHandler, err := NewJobDBHandler(database)
+	require.NoError(t, err, "Expected NewJobDBHandler to not return an error")
+
+	laterRID, soonerRID, claimedRID, queuedRID, dueRID := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	_, err = database.Instance.Exec(`
+		INSERT INTO job (rid, task_name, status, options, scheduled_at, created_at, updated_at) VALUES
+			($1, 'scheduled-task', 'SCHEDULED', '{"schedule": {"max_count": 1}}', (NOW() AT TIME ZONE 'UTC') + INTERVAL '2 hours', NOW(), NOW()),
+			($2, 'scheduled-task', 'SCHEDULED', '{"schedule": {"max_count": 1}}', (NOW() AT TIME ZONE 'UTC') + INTERVAL '1 hour', NOW(), NOW()),
+			($3, 'scheduled-task', 'RUNNING', '{"schedule": {"max_count": 1}}', (NOW() AT TIME ZONE 'UTC') + INTERVAL '5 minutes', NOW(), NOW()),
+			($4, 'scheduled-task', 'QUEUED', '{}', (NOW() AT TIME ZONE 'UTC') + INTERVAL '1 hour', NOW(), NOW()),
+			($5, 'scheduled-task', 'RUNNING', '{"schedule": {"max_count": 1}}', (NOW() AT TIME ZONE 'UTC') - INTERVAL '1 minute', NOW(), NOW())
+	`, laterRID, soonerRID, claimedRID, queuedRID, dueRID)
+	require.NoError(t, err)
+
+	t.Run("Select the pending scheduled jobs", func(t *testing.T) {
+		rids, err := jobDbHandler.SelectScheduledJobRIDs("", time.Now(), 0, 10)
+		require.NoError(t, err, "Expected SelectScheduledJobRIDs to not return an error")
+		assert.Equal(t, []uuid.UUID{claimedRID, soonerRID, laterRID}, rids, "Expected the next job to run first and the started jobs to be left out")
+	})
+
+	t.Run("Select the pending scheduled jobs with pagination", func(t *testing.T) {
+		rids, err := jobDbHandler.SelectScheduledJobRIDs("", time.Now(), 0, 2)
+		require.NoError(t, err, "Expected SelectScheduledJobRIDs to not return an error")
+		assert.Equal(t, []uuid.UUID{claimedRID, soonerRID}, rids)
+
+		var lastID int
+		err = database.Instance.QueryRow(`SELECT id FROM job WHERE rid = $1`, soonerRID).Scan(&lastID)
+		require.NoError(t, err)
+
+		rids, err = jobDbHandler.SelectScheduledJobRIDs("", time.Now(), lastID, 2)
+		require.NoError(t, err, "Expected SelectScheduledJobRIDs to not return an error")
+		assert.Equal(t, []uuid.UUID{laterRID}, rids)
+	})
+}
@@ -0,0 +1,281 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+)
+
+// ManagerLeaseDBHandlerFunctions defines the interface for the leases of the manager instances.
+type ManagerLeaseDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	AcquireLease(name string, holder string, ttl time.Duration) (*model.ManagerLease, error)
+	SelectAllLeases() ([]*model.ManagerLease, error)
+	FailoverLease(name string, ttl time.Duration) (*model.ManagerLease, error)
+	ReleaseLeases(holder string) error
+}
+
+// ManagerLeaseDBHandler implements ManagerLeaseDBHandlerFunctions and holds the database connection.
+type ManagerLeaseDBHandler struct {
+	db *helper.Database
+}
+
+// NewManagerLeaseDBHandler creates a new instance of ManagerLeaseDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing manager_lease table before creating a new one
+func NewManagerLeaseDBHandler(dbConnection *helper.Database, withTableDrop bool) (*ManagerLeaseDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	managerLeaseDbHandler := &ManagerLeaseDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := managerLeaseDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := managerLeaseDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return managerLeaseDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'manager_lease' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r ManagerLeaseDBHandler) CheckTableExistance() (bool, error) {
+	managerLeaseExists, err := r.db.CheckTableExistance("manager_lease")
+	if err != nil {
+		return false, helper.NewError("manager_lease table", err)
+	}
+	return managerLeaseExists, nil
+}
+
+// CreateTable creates the 'manager_lease' table in the database.
+// If the table already exists, it does not create it again.
+func (r ManagerLeaseDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS manager_lease (
+			name VARCHAR(100) PRIMARY KEY,
+			holder VARCHAR(100) NOT NULL,
+			acquired_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			renewed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			failover_from VARCHAR(100) NOT NULL DEFAULT '',
+			failover_until TIMESTAMP WITH TIME ZONE
+		);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create manager_lease table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table manager_lease")
+
+	return nil
+}
+
+// DropTable drops the 'manager_lease' table from the database.
+func (r ManagerLeaseDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS manager_lease`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop manager_lease table", err)
+	}
+
+	r.db.Logger.Info("Dropped table manager_lease")
+
+	return nil
+}
+
+// AcquireLease acquires or renews the lease for the holder until now plus the ttl and returns the lease.
+// The lease is only acquired if the holder holds it or it expired, after a failover the previous holder has to wait
+// until the failover ends. If another instance holds the lease, the lease of that instance is returned.
+func (r ManagerLeaseDBHandler) AcquireLease(name string, holder string, ttl time.Duration) (*model.ManagerLease, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		INSERT INTO manager_lease (name, holder, expires_at)
+		VALUES ($1, $2, NOW() + $3 * INTERVAL '1 millisecond')
+		ON CONFLICT (name) DO UPDATE
+		SET
+			holder = EXCLUDED.holder,
+			acquired_at = CASE WHEN manager_lease.holder = EXCLUDED.holder AND manager_lease.expires_at > NOW() THEN manager_lease.acquired_at ELSE NOW() END,
+			renewed_at = NOW(),
+			expires_at = EXCLUDED.expires_at
+		WHERE (manager_lease.holder = EXCLUDED.holder AND manager_lease.expires_at > NOW())
+			OR (manager_lease.expires_at <= NOW() AND (manager_lease.failover_from <> EXCLUDED.holder OR manager_lease.failover_until <= NOW()))
+		RETURNING
+			name,
+			holder,
+			acquired_at,
+			renewed_at,
+			expires_at,
+			failover_from,
+			failover_until`
+
+	lease, err := scanManagerLease(r.db.Instance.QueryRowContext(ctx, query, name, holder, ttl.Milliseconds()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return r.selectLease(ctx, name)
+	}
+	if err != nil {
+		return nil, helper.NewError("acquire lease", err)
+	}
+
+	return lease, nil
+}
+
+// SelectAllLeases retrieves all leases ordered by their name.
+func (r ManagerLeaseDBHandler) SelectAllLeases() ([]*model.ManagerLease, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			name,
+			holder,
+			acquired_at,
+			renewed_at,
+			expires_at,
+			failover_from,
+			failover_until
+		FROM manager_lease
+		ORDER BY name ASC
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query)
+	if err != nil {
+		return nil, helper.NewError("select leases", err)
+	}
+	defer rows.Close()
+
+	leases := []*model.ManagerLease{}
+	for rows.Next() {
+		lease, err := scanManagerLease(rows)
+		if err != nil {
+			return nil, helper.NewError("scan lease", err)
+		}
+		leases = append(leases, lease)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return leases, nil
+}
+
+// FailoverLease expires the lease, so another instance acquires it. The previous holder can not acquire it again
+// for the ttl. It returns an error if no instance ever held the lease.
+func (r ManagerLeaseDBHandler) FailoverLease(name string, ttl time.Duration) (*model.ManagerLease, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE manager_lease
+		SET
+			expires_at = NOW(),
+			failover_from = holder,
+			failover_until = NOW() + $2 * INTERVAL '1 millisecond'
+		WHERE name = $1
+		RETURNING
+			name,
+			holder,
+			acquired_at,
+			renewed_at,
+			expires_at,
+			failover_from,
+			failover_until`
+
+	lease, err := scanManagerLease(r.db.Instance.QueryRowContext(ctx, query, name, ttl.Milliseconds()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, helper.NewError("failover lease", fmt.Errorf("lease %s was never held", name))
+	}
+	if err != nil {
+		return nil, helper.NewError("failover lease", err)
+	}
+
+	return lease, nil
+}
+
+// ReleaseLeases expires the leases of the holder, eg. on shutdown, so other instances acquire them without waiting for the ttl.
+func (r ManagerLeaseDBHandler) ReleaseLeases(holder string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `UPDATE manager_lease SET expires_at = NOW() WHERE holder = $1 AND expires_at > NOW()`
+	_, err := r.db.Instance.ExecContext(ctx, query, holder)
+	if err != nil {
+		return helper.NewError("release leases", err)
+	}
+
+	return nil
+}
+
+// selectLease retrieves the lease by its name.
+func (r ManagerLeaseDBHandler) selectLease(ctx context.Context, name string) (*model.ManagerLease, error) {
+	query := `
+		SELECT
+			name,
+			holder,
+			acquired_at,
+			renewed_at,
+			expires_at,
+			failover_from,
+			failover_until
+		FROM manager_lease
+		WHERE name = $1
+	`
+
+	lease, err := scanManagerLease(r.db.Instance.QueryRowContext(ctx, query, name))
+	if err != nil {
+		return nil, helper.NewError("select lease", err)
+	}
+
+	return lease, nil
+}
+
+// scanManagerLease scans a lease from a row with the columns of the manager_lease table.
+func scanManagerLease(row interface{ Scan(dest ...any) error }) (*model.ManagerLease, error) {
+	lease := &model.ManagerLease{}
+	var failoverUntil sql.NullTime
+	err := row.Scan(
+		&lease.Name,
+		&lease.Holder,
+		&lease.AcquiredAt,
+		&lease.RenewedAt,
+		&lease.ExpiresAt,
+		&lease.FailoverFrom,
+		&failoverUntil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if failoverUntil.Valid {
+		lease.FailoverUntil = &failoverUntil.Time
+	}
+
+	return lease, nil
+}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagerLeaseNewManagerLeaseDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewManagerLeaseDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		managerLeaseDbHandler, err := NewManagerLeaseDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewManagerLeaseDBHandler to not return an error")
+		require.NotNil(t, managerLeaseDbHandler, "Expected NewManagerLeaseDBHandler to return a non-nil instance")
+
+		exists, err := managerLeaseDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = managerLeaseDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewManagerLeaseDBHandler with nil database", func(t *testing.T) {
+		_, err := NewManagerLeaseDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating ManagerLeaseDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestManagerLeaseAcquireLease(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	managerLeaseDbHandler, err := NewManagerLeaseDBHandler(database, true)
+	require.NoError(t, err, "Expected NewManagerLeaseDBHandler to not return an error")
+
+	t.Run("Acquire a new lease", func(t *testing.T) {
+		lease, err := managerLeaseDbHandler.AcquireLease(model.LEASE_SCHEDULER, "instance-a", time.Minute)
+		require.NoError(t, err, "Expected AcquireLease to not return an error")
+		assert.Equal(t, "instance-a", lease.Holder)
+		assert.True(t, lease.IsHeld(time.Now()))
+	})
+
+	t.Run("Renew the lease", func(t *testing.T) {
+		lease, err := managerLeaseDbHandler.AcquireLease(model.LEASE_SCHEDULER, "instance-a", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, "instance-a", lease.Holder)
+		assert.True(t, lease.RenewedAt.After(lease.AcquiredAt), "Expected the renewal to keep the acquisition time")
+	})
+
+	t.Run("Another instance can not acquire a held lease", func(t *testing.T) {
+		lease, err := managerLeaseDbHandler.AcquireLease(model.LEASE_SCHEDULER, "instance-b", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, "instance-a", lease.Holder, "Expected the lease of the holder to be returned")
+	})
+
+	t.Run("Failover the lease", func(t *testing.T) {
+		lease, err := managerLeaseDbHandler.FailoverLease(model.LEASE_SCHEDULER, time.Minute)
+		require.NoError(t, err, "Expected FailoverLease to not return an error")
+		assert.Equal(t, "instance-a", lease.FailoverFrom)
+		assert.False(t, lease.IsHeld(time.Now()))
+
+		lease, err = managerLeaseDbHandler.AcquireLease(model.LEASE_SCHEDULER, "instance-a", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, lease.IsHeld(time.Now()), "Expected the previous holder to not acquire the lease during the failover")
+
+		lease, err = managerLeaseDbHandler.AcquireLease(model.LEASE_SCHEDULER, "instance-b", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, "instance-b", lease.Holder)
+		assert.True(t, lease.IsHeld(time.Now()))
+	})
+
+	t.Run("Failover a lease that was never held", func(t *testing.T) {
+		_, err := managerLeaseDbHandler.FailoverLease("unknown", time.Minute)
+		assert.ErrorContains(t, err, "was never held")
+	})
+
+	t.Run("Release the leases of a holder", func(t *testing.T) {
+		_, err := managerLeaseDbHandler.AcquireLease(model.LEASE_HOUSEKEEPING, "instance-b", time.Minute)
+		require.NoError(t, err)
+
+		err = managerLeaseDbHandler.ReleaseLeases("instance-b")
+		require.NoError(t, err, "Expected ReleaseLeases to not return an error")
+
+		leases, err := managerLeaseDbHandler.SelectAllLeases()
+		require.NoError(t, err, "Expected SelectAllLeases to not return an error")
+		require.Len(t, leases, 2)
+		assert.Equal(t, model.LEASE_HOUSEKEEPING, leases[0].Name)
+		assert.Equal(t, model.LEASE_SCHEDULER, leases[1].Name)
+		for _, lease := range leases {
+			assert.False(t, lease.IsHeld(time.Now()), "Expected the released lease %s to not be held", lease.Name)
+		}
+
+		lease, err := managerLeaseDbHandler.AcquireLease(model.LEASE_HOUSEKEEPING, "instance-a", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, "instance-a", lease.Holder, "Expected another instance to acquire the released lease")
+	})
+}
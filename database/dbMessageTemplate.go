@@ -0,0 +1,252 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+)
+
+// MessageTemplateDBHandlerFunctions defines the interface for MessageTemplate database operations.
+type MessageTemplateDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	UpsertMessageTemplate(messageTemplate *model.MessageTemplate) (*model.MessageTemplate, error)
+	SelectMessageTemplate(key string) (*model.MessageTemplate, error)
+	SelectAllMessageTemplates() ([]*model.MessageTemplate, error)
+	DeleteMessageTemplate(key string) error
+}
+
+// MessageTemplateDBHandler implements MessageTemplateDBHandlerFunctions and holds the database connection.
+type MessageTemplateDBHandler struct {
+	db *helper.Database
+}
+
+// NewMessageTemplateDBHandler creates a new instance of MessageTemplateDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing message_template table before creating a new one
+func NewMessageTemplateDBHandler(dbConnection *helper.Database, withTableDrop bool) (*MessageTemplateDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	messageTemplateDbHandler := &MessageTemplateDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := messageTemplateDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := messageTemplateDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return messageTemplateDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'message_template' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r MessageTemplateDBHandler) CheckTableExistance() (bool, error) {
+	messageTemplateExists, err := r.db.CheckTableExistance("message_template")
+	if err != nil {
+		return false, helper.NewError("message_template table", err)
+	}
+	return messageTemplateExists, nil
+}
+
+// CreateTable creates the 'message_template' table in the database.
+// If the table already exists, it does not create it again.
+func (r MessageTemplateDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS message_template (
+			id SERIAL PRIMARY KEY,
+			key VARCHAR(100) UNIQUE NOT NULL,
+			subject TEXT NOT NULL DEFAULT '',
+			body TEXT NOT NULL,
+			updated_by VARCHAR(255) NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create message_template table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table message_template")
+
+	return nil
+}
+
+// DropTable drops the 'message_template' table from the database.
+func (r MessageTemplateDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS message_template`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop message_template table", err)
+	}
+
+	r.db.Logger.Info("Dropped table message_template")
+
+	return nil
+}
+
+// UpsertMessageTemplate stores the edited template with its key or replaces the stored template with the key.
+func (r MessageTemplateDBHandler) UpsertMessageTemplate(messageTemplate *model.MessageTemplate) (*model.MessageTemplate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		INSERT INTO message_template (
+			key,
+			subject,
+			body,
+			updated_by
+		) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key) DO UPDATE
+		SET
+			subject = EXCLUDED.subject,
+			body = EXCLUDED.body,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = NOW()
+		RETURNING
+			id,
+			key,
+			subject,
+			body,
+			updated_by,
+			created_at,
+			updated_at`
+
+	row := r.db.Instance.QueryRowContext(
+		ctx,
+		query,
+		messageTemplate.Key,
+		messageTemplate.Subject,
+		messageTemplate.Body,
+		messageTemplate.UpdatedBy,
+	)
+	upsertedMessageTemplate, err := scanMessageTemplate(row)
+	if err != nil {
+		return nil, helper.NewError("upsert message template", err)
+	}
+
+	return upsertedMessageTemplate, nil
+}
+
+// SelectMessageTemplate retrieves the stored template with the key.
+func (r MessageTemplateDBHandler) SelectMessageTemplate(key string) (*model.MessageTemplate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			key,
+			subject,
+			body,
+			updated_by,
+			created_at,
+			updated_at
+		FROM message_template
+		WHERE key = $1
+	`
+
+	messageTemplate, err := scanMessageTemplate(r.db.Instance.QueryRowContext(ctx, query, key))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("message template not found", fmt.Errorf("no message template with key %s", key))
+		}
+		return nil, helper.NewError("select message template", err)
+	}
+
+	return messageTemplate, nil
+}
+
+// SelectAllMessageTemplates retrieves all stored templates ordered by key.
+func (r MessageTemplateDBHandler) SelectAllMessageTemplates() ([]*model.MessageTemplate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			key,
+			subject,
+			body,
+			updated_by,
+			created_at,
+			updated_at
+		FROM message_template
+		ORDER BY key ASC
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query)
+	if err != nil {
+		return nil, helper.NewError("select all message templates", err)
+	}
+	defer rows.Close()
+
+	messageTemplates := []*model.MessageTemplate{}
+	for rows.Next() {
+		messageTemplate, err := scanMessageTemplate(rows)
+		if err != nil {
+			return nil, helper.NewError("scan message template", err)
+		}
+		messageTemplates = append(messageTemplates, messageTemplate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return messageTemplates, nil
+}
+
+// DeleteMessageTemplate deletes the stored template with the key, so the default template is used again.
+func (r MessageTemplateDBHandler) DeleteMessageTemplate(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DELETE FROM message_template WHERE key = $1`
+	_, err := r.db.Instance.ExecContext(ctx, query, key)
+	if err != nil {
+		return helper.NewError("delete message template", err)
+	}
+
+	return nil
+}
+
+func scanMessageTemplate(row interface{ Scan(dest ...any) error }) (*model.MessageTemplate, error) {
+	messageTemplate := &model.MessageTemplate{}
+	err := row.Scan(
+		&messageTemplate.ID,
+		&messageTemplate.Key,
+		&messageTemplate.Subject,
+		&messageTemplate.Body,
+		&messageTemplate.UpdatedBy,
+		&messageTemplate.CreatedAt,
+		&messageTemplate.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return messageTemplate, nil
+}
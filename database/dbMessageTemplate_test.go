@@ -0,0 +1,87 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageTemplateNewMessageTemplateDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewMessageTemplateDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		messageTemplateDbHandler, err := NewMessageTemplateDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewMessageTemplateDBHandler to not return an error")
+		require.NotNil(t, messageTemplateDbHandler, "Expected NewMessageTemplateDBHandler to return a non-nil instance")
+
+		exists, err := messageTemplateDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = messageTemplateDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewMessageTemplateDBHandler with nil database", func(t *testing.T) {
+		_, err := NewMessageTemplateDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating MessageTemplateDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestMessageTemplateUpsertMessageTemplate(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	messageTemplateDbHandler, err := NewMessageTemplateDBHandler(database, true)
+	require.NoError(t, err, "Expected NewMessageTemplateDBHandler to not return an error")
+
+	t.Run("Insert and replace template", func(t *testing.T) {
+		messageTemplate, err := messageTemplateDbHandler.UpsertMessageTemplate(&model.MessageTemplate{
+			Key:       model.MESSAGE_TEMPLATE_ALERT_EMAIL,
+			Subject:   "[Acme] {{ .Title }}",
+			Body:      "{{ .Message }}",
+			UpdatedBy: "admin",
+		})
+		require.NoError(t, err, "Expected UpsertMessageTemplate to not return an error")
+		assert.True(t, messageTemplate.IsEdited())
+		assert.Equal(t, "[Acme] {{ .Title }}", messageTemplate.Subject)
+
+		replacedMessageTemplate, err := messageTemplateDbHandler.UpsertMessageTemplate(&model.MessageTemplate{
+			Key:       model.MESSAGE_TEMPLATE_ALERT_EMAIL,
+			Subject:   "{{ .Title }}",
+			Body:      "{{ .Message }} {{ .URL }}",
+			UpdatedBy: "operator",
+		})
+		require.NoError(t, err, "Expected UpsertMessageTemplate to not return an error")
+		assert.Equal(t, messageTemplate.ID, replacedMessageTemplate.ID)
+		assert.Equal(t, "{{ .Message }} {{ .URL }}", replacedMessageTemplate.Body)
+		assert.Equal(t, "operator", replacedMessageTemplate.UpdatedBy)
+
+		messageTemplates, err := messageTemplateDbHandler.SelectAllMessageTemplates()
+		require.NoError(t, err, "Expected SelectAllMessageTemplates to not return an error")
+		assert.Len(t, messageTemplates, 1)
+	})
+
+	t.Run("Delete template", func(t *testing.T) {
+		err := messageTemplateDbHandler.DeleteMessageTemplate(model.MESSAGE_TEMPLATE_ALERT_EMAIL)
+		require.NoError(t, err, "Expected DeleteMessageTemplate to not return an error")
+
+		_, err = messageTemplateDbHandler.SelectMessageTemplate(model.MESSAGE_TEMPLATE_ALERT_EMAIL)
+		assert.Error(t, err, "Expected the deleted template to not be found")
+	})
+}
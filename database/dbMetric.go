@@ -0,0 +1,654 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+)
+
+// MetricDBHandlerFunctions defines the interface for queue metric database operations.
+type MetricDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	InsertMetricSnapshot() (*model.QueueMetric, error)
+	RecordMetricSnapshot(minInterval time.Duration) (*model.QueueMetric, error)
+	SelectMetrics(since time.Time, until time.Time) ([]*model.QueueMetric, error)
+	SelectMetricsHourly(since time.Time, until time.Time) ([]*model.QueueMetric, error)
+	SelectJobRates(since time.Time) (*model.JobRates, error)
+	SelectJobStats(since time.Time, until time.Time, bucket time.Duration, taskName string) ([]*model.JobStats, error)
+	SelectJobsCount() (int, error)
+	SelectWorkersCount() (int, error)
+	SelectJobStatusCounts(since time.Time) ([]*model.StatusCount, error)
+	SelectTaskStats(since time.Time, until time.Time) ([]*model.TaskStats, error)
+	SelectActiveWorkersCount() (int, error)
+	SelectJobHeatmap(since time.Time, until time.Time, timezone string, taskName string) ([]*model.HeatmapCell, error)
+}
+
+// MetricDBHandler implements MetricDBHandlerFunctions and holds the database connection.
+type MetricDBHandler struct {
+	db            *helper.Database
+	withTimescale bool
+}
+
+// NewMetricDBHandler creates a new instance of MetricDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If the timescaledb extension is installed, the metric table is created as hypertable
+// with an hourly continuous aggregate and a retention policy.
+func NewMetricDBHandler(dbConnection *helper.Database, withTableDrop bool) (*MetricDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	metricDbHandler := &MetricDBHandler{
+		db: dbConnection,
+	}
+
+	withTimescale, err := metricDbHandler.checkTimescale()
+	if err != nil {
+		return nil, helper.NewError("check timescale", err)
+	}
+	metricDbHandler.withTimescale = withTimescale
+
+	if withTableDrop {
+		err := metricDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err = metricDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return metricDbHandler, nil
+}
+
+// checkTimescale checks if the timescaledb extension is installed in the database.
+func (r MetricDBHandler) checkTimescale() (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var exists bool
+	err := r.db.Instance.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'timescaledb')`).Scan(&exists)
+	if err != nil {
+		return false, helper.NewError("select timescale extension", err)
+	}
+	return exists, nil
+}
+
+// CheckTableExistance checks if the 'queue_metric' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r MetricDBHandler) CheckTableExistance() (bool, error) {
+	metricExists, err := r.db.CheckTableExistance("queue_metric")
+	if err != nil {
+		return false, helper.NewError("queue_metric table", err)
+	}
+	return metricExists, nil
+}
+
+// CreateTable creates the 'queue_metric' table in the database.
+// If the table already exists, it does not create it again.
+// With timescale the statements are executed one by one,
+// because continuous aggregates can not be created inside a transaction block.
+func (r MetricDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	queries := []string{`
+		CREATE TABLE IF NOT EXISTS queue_metric (
+			time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			jobs_queued INT NOT NULL DEFAULT 0,
+			jobs_scheduled INT NOT NULL DEFAULT 0,
+			jobs_running INT NOT NULL DEFAULT 0,
+			workers_total INT NOT NULL DEFAULT 0,
+			workers_ready INT NOT NULL DEFAULT 0,
+			workers_running INT NOT NULL DEFAULT 0
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_queue_metric_time ON queue_metric(time DESC);`,
+	}
+
+	if r.withTimescale {
+		queries = append(queries,
+			`SELECT create_hypertable('queue_metric', by_range('time'), if_not_exists => TRUE);`,
+			`CREATE MATERIALIZED VIEW IF NOT EXISTS queue_metric_hourly
+			WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+			SELECT
+				time_bucket('1 hour', time) AS bucket,
+				ROUND(AVG(jobs_queued))::INT AS jobs_queued,
+				ROUND(AVG(jobs_scheduled))::INT AS jobs_scheduled,
+				ROUND(AVG(jobs_running))::INT AS jobs_running,
+				ROUND(AVG(workers_total))::INT AS workers_total,
+				ROUND(AVG(workers_ready))::INT AS workers_ready,
+				ROUND(AVG(workers_running))::INT AS workers_running
+			FROM queue_metric
+			GROUP BY bucket
+			WITH NO DATA;`,
+			`SELECT add_continuous_aggregate_policy('queue_metric_hourly',
+				start_offset => INTERVAL '3 hours',
+				end_offset => INTERVAL '1 hour',
+				schedule_interval => INTERVAL '1 hour',
+				if_not_exists => TRUE);`,
+			`SELECT add_retention_policy('queue_metric', INTERVAL '30 days', if_not_exists => TRUE);`,
+		)
+	}
+
+	for _, query := range queries {
+		_, err := r.db.Instance.ExecContext(ctx, query)
+		if err != nil {
+			return helper.NewError("create queue_metric table", err)
+		}
+	}
+
+	r.db.Logger.Info("Checked/created table queue_metric", "timescale", r.withTimescale)
+
+	return nil
+}
+
+// DropTable drops the 'queue_metric' table and its aggregate from the database.
+func (r MetricDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	queries := []string{
+		`DROP MATERIALIZED VIEW IF EXISTS queue_metric_hourly`,
+		`DROP TABLE IF EXISTS queue_metric CASCADE`,
+	}
+	for _, query := range queries {
+		_, err := r.db.Instance.ExecContext(ctx, query)
+		if err != nil {
+			return helper.NewError("drop queue_metric table", err)
+		}
+	}
+
+	r.db.Logger.Info("Dropped table queue_metric")
+
+	return nil
+}
+
+// InsertMetricSnapshot records the current job and worker counts as a new metric row.
+// The counts are computed in the database from the job and worker tables of the queuer.
+func (r MetricDBHandler) InsertMetricSnapshot() (*model.QueueMetric, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	metric := &model.QueueMetric{}
+	query := `
+		INSERT INTO queue_metric (
+			jobs_queued,
+			jobs_scheduled,
+			jobs_running,
+			workers_total,
+			workers_ready,
+			workers_running
+		)
+		SELECT
+			(SELECT COUNT(*) FROM job WHERE status = 'QUEUED'),
+			(SELECT COUNT(*) FROM job WHERE status = 'SCHEDULED'),
+			(SELECT COUNT(*) FROM job WHERE status = 'RUNNING'),
+			(SELECT COUNT(*) FROM worker),
+			(SELECT COUNT(*) FROM worker WHERE status = 'READY'),
+			(SELECT COUNT(*) FROM worker WHERE status = 'RUNNING')
+		RETURNING
+			time,
+			jobs_queued,
+			jobs_scheduled,
+			jobs_running,
+			workers_total,
+			workers_ready,
+			workers_running`
+
+	err := r.db.Instance.QueryRowContext(ctx, query).Scan(
+		&metric.Time,
+		&metric.JobsQueued,
+		&metric.JobsScheduled,
+		&metric.JobsRunning,
+		&metric.WorkersTotal,
+		&metric.WorkersReady,
+		&metric.WorkersRunning,
+	)
+	if err != nil {
+		return nil, helper.NewError("insert queue metric", err)
+	}
+
+	return metric, nil
+}
+
+// RecordMetricSnapshot records a metric snapshot like InsertMetricSnapshot under the advisory lock of the metric recording,
+// so manager instances recording at the same time do not write duplicate snapshots. It returns nil without snapshot
+// if another instance holds the lock or the last snapshot is more recent than the minimum interval.
+func (r MetricDBHandler) RecordMetricSnapshot(minInterval time.Duration) (*model.QueueMetric, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := r.db.Instance.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, helper.NewError("begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	err = tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock(hashtext('queue_metric'))`).Scan(&locked)
+	if err != nil {
+		return nil, helper.NewError("lock queue metric", err)
+	}
+	if !locked {
+		return nil, nil
+	}
+
+	metric := &model.QueueMetric{}
+	query := `
+		INSERT INTO queue_metric (
+			jobs_queued,
+			jobs_scheduled,
+			jobs_running,
+			workers_total,
+			workers_ready,
+			workers_running
+		)
+		SELECT
+			(SELECT COUNT(*) FROM job WHERE status = 'QUEUED'),
+			(SELECT COUNT(*) FROM job WHERE status = 'SCHEDULED'),
+			(SELECT COUNT(*) FROM job WHERE status = 'RUNNING'),
+			(SELECT COUNT(*) FROM worker),
+			(SELECT COUNT(*) FROM worker WHERE status = 'READY'),
+			(SELECT COUNT(*) FROM worker WHERE status = 'RUNNING')
+		WHERE NOT EXISTS (SELECT 1 FROM queue_metric WHERE time > NOW() - make_interval(secs => $1))
+		RETURNING
+			time,
+			jobs_queued,
+			jobs_scheduled,
+			jobs_running,
+			workers_total,
+			workers_ready,
+			workers_running`
+
+	err = tx.QueryRowContext(ctx, query, minInterval.Seconds()).Scan(
+		&metric.Time,
+		&metric.JobsQueued,
+		&metric.JobsScheduled,
+		&metric.JobsRunning,
+		&metric.WorkersTotal,
+		&metric.WorkersReady,
+		&metric.WorkersRunning,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, helper.NewError("insert queue metric", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, helper.NewError("commit transaction", err)
+	}
+
+	return metric, nil
+}
+
+// SelectMetrics retrieves the raw metric snapshots between since and until ordered by time.
+func (r MetricDBHandler) SelectMetrics(since time.Time, until time.Time) ([]*model.QueueMetric, error) {
+	query := `
+		SELECT
+			time,
+			jobs_queued,
+			jobs_scheduled,
+			jobs_running,
+			workers_total,
+			workers_ready,
+			workers_running
+		FROM queue_metric
+		WHERE time >= $1
+			AND time <= $2
+		ORDER BY time ASC
+	`
+
+	return r.selectMetrics(query, since, until)
+}
+
+// SelectMetricsHourly retrieves hourly averages of the metric snapshots between since and until.
+// With timescale the continuous aggregate is used (real time aggregation covers the latest hour),
+// otherwise the raw snapshots are aggregated on the fly.
+func (r MetricDBHandler) SelectMetricsHourly(since time.Time, until time.Time) ([]*model.QueueMetric, error) {
+	query := `
+		SELECT
+			date_trunc('hour', time) AS bucket,
+			ROUND(AVG(jobs_queued))::INT,
+			ROUND(AVG(jobs_scheduled))::INT,
+			ROUND(AVG(jobs_running))::INT,
+			ROUND(AVG(workers_total))::INT,
+			ROUND(AVG(workers_ready))::INT,
+			ROUND(AVG(workers_running))::INT
+		FROM queue_metric
+		WHERE time >= $1
+			AND time <= $2
+		GROUP BY bucket
+		ORDER BY bucket ASC
+	`
+	if r.withTimescale {
+		query = `
+			SELECT
+				bucket,
+				jobs_queued,
+				jobs_scheduled,
+				jobs_running,
+				workers_total,
+				workers_ready,
+				workers_running
+			FROM queue_metric_hourly
+			WHERE bucket >= date_trunc('hour', $1::TIMESTAMPTZ)
+				AND bucket <= $2
+			ORDER BY bucket ASC
+		`
+	}
+
+	return r.selectMetrics(query, since, until)
+}
+
+// SelectJobRates counts the jobs added and ended since the given time and the current backlog of queued and running jobs.
+// Ended jobs are counted from the job archive of the queuer.
+func (r MetricDBHandler) SelectJobRates(since time.Time) (*model.JobRates, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rates := &model.JobRates{Since: since}
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM job WHERE created_at >= $1)
+				+ (SELECT COUNT(*) FROM job_archive WHERE created_at >= $1),
+			(SELECT COUNT(*) FROM job_archive WHERE updated_at >= $1),
+			(SELECT COUNT(*) FROM job WHERE status IN ('QUEUED', 'RUNNING'))
+	`
+
+	err := r.db.Instance.QueryRowContext(ctx, query, since).Scan(
+		&rates.Arrived,
+		&rates.Completed,
+		&rates.Backlog,
+	)
+	if err != nil {
+		return nil, helper.NewError("select job rates", err)
+	}
+
+	return rates, nil
+}
+
+// SelectJobStats counts the jobs that ended between since and until per bucket and averages their duration.
+// If taskName is not empty only the jobs of that task are counted.
+// Ended jobs are counted from the job archive of the queuer.
+func (r MetricDBHandler) SelectJobStats(since time.Time, until time.Time, bucket time.Duration, taskName string) ([]*model.JobStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			to_timestamp(floor(extract(epoch FROM updated_at)::FLOAT8 / $3::FLOAT8) * $3::FLOAT8) AS bucket,
+			COUNT(*) FILTER (WHERE status = 'SUCCEEDED'),
+			COUNT(*) FILTER (WHERE status = 'FAILED'),
+			COUNT(*) FILTER (WHERE status = 'CANCELLED'),
+			COALESCE(AVG(extract(epoch FROM updated_at - started_at)) FILTER (WHERE started_at IS NOT NULL), 0)::FLOAT8
+		FROM job_archive
+		WHERE updated_at >= $1
+			AND updated_at <= $2
+			AND ($4::TEXT = '' OR task_name = $4::TEXT)
+		GROUP BY bucket
+		ORDER BY bucket ASC
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, since, until, bucket.Seconds(), taskName)
+	if err != nil {
+		return nil, helper.NewError("select job stats", err)
+	}
+	defer rows.Close()
+
+	stats := []*model.JobStats{}
+	for rows.Next() {
+		stat := &model.JobStats{}
+		err := rows.Scan(
+			&stat.Time,
+			&stat.Succeeded,
+			&stat.Failed,
+			&stat.Cancelled,
+			&stat.AvgDurationSeconds,
+		)
+		if err != nil {
+			return nil, helper.NewError("scan job stats", err)
+		}
+		stats = append(stats, stat)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return stats, nil
+}
+
+// SelectJobHeatmap counts the jobs that ended between since and until per weekday and hour of their end in the timezone,
+// eg. "Europe/Berlin". If taskName is not empty only the jobs of that task are counted.
+// Only weekdays and hours with ended jobs are returned, ended jobs are counted from the job archive of the queuer.
+func (r MetricDBHandler) SelectJobHeatmap(since time.Time, until time.Time, timezone string, taskName string) ([]*model.HeatmapCell, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			extract(dow FROM updated_at AT TIME ZONE $3)::INT AS weekday,
+			extract(hour FROM updated_at AT TIME ZONE $3)::INT AS hour,
+			COUNT(*)::INT,
+			COUNT(*) FILTER (WHERE status = 'FAILED')::INT
+		FROM job_archive
+		WHERE updated_at >= $1
+			AND updated_at <= $2
+			AND ($4::TEXT = '' OR task_name = $4::TEXT)
+		GROUP BY weekday, hour
+		ORDER BY weekday ASC, hour ASC
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, since, until, timezone, taskName)
+	if err != nil {
+		return nil, helper.NewError("select job heatmap", err)
+	}
+	defer rows.Close()
+
+	cells := []*model.HeatmapCell{}
+	for rows.Next() {
+		cell := &model.HeatmapCell{}
+		err := rows.Scan(
+			&cell.Weekday,
+			&cell.Hour,
+			&cell.Total,
+			&cell.Failed,
+		)
+		if err != nil {
+			return nil, helper.NewError("scan job heatmap", err)
+		}
+		cells = append(cells, cell)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return cells, nil
+}
+
+func (r MetricDBHandler) selectMetrics(query string, since time.Time, until time.Time) ([]*model.QueueMetric, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, since, until)
+	if err != nil {
+		return nil, helper.NewError("select queue metrics", err)
+	}
+	defer rows.Close()
+
+	metrics := []*model.QueueMetric{}
+	for rows.Next() {
+		metric := &model.QueueMetric{}
+		err := rows.Scan(
+			&metric.Time,
+			&metric.JobsQueued,
+			&metric.JobsScheduled,
+			&metric.JobsRunning,
+			&metric.WorkersTotal,
+			&metric.WorkersReady,
+			&metric.WorkersRunning,
+		)
+		if err != nil {
+			return nil, helper.NewError("scan queue metric", err)
+		}
+		metrics = append(metrics, metric)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return metrics, nil
+}
+
+// SelectJobsCount counts the active jobs in the job table of the queuer.
+func (r MetricDBHandler) SelectJobsCount() (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `SELECT COUNT(*) FROM job`
+
+	var count int
+	err := r.db.Instance.QueryRowContext(ctx, query).Scan(&count)
+	if err != nil {
+		return 0, helper.NewError("count jobs", err)
+	}
+
+	return count, nil
+}
+
+// SelectWorkersCount counts the workers in the worker table of the queuer.
+func (r MetricDBHandler) SelectWorkersCount() (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `SELECT COUNT(*) FROM worker`
+
+	var count int
+	err := r.db.Instance.QueryRowContext(ctx, query).Scan(&count)
+	if err != nil {
+		return 0, helper.NewError("count workers", err)
+	}
+
+	return count, nil
+}
+
+// SelectJobStatusCounts counts the active jobs by their current status and the jobs that ended since the given time by their final status.
+// Ended jobs are counted from the job archive of the queuer.
+func (r MetricDBHandler) SelectJobStatusCounts(since time.Time) ([]*model.StatusCount, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT status, SUM(count)::INT FROM (
+			SELECT status, COUNT(*) AS count FROM job GROUP BY status
+			UNION ALL
+			SELECT status, COUNT(*) AS count FROM job_archive WHERE updated_at >= $1 GROUP BY status
+		) AS counts
+		GROUP BY status
+		ORDER BY status ASC
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, since)
+	if err != nil {
+		return nil, helper.NewError("select job status counts", err)
+	}
+	defer rows.Close()
+
+	counts := []*model.StatusCount{}
+	for rows.Next() {
+		count := &model.StatusCount{}
+		err := rows.Scan(
+			&count.Status,
+			&count.Count,
+		)
+		if err != nil {
+			return nil, helper.NewError("scan job status count", err)
+		}
+		counts = append(counts, count)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return counts, nil
+}
+
+// SelectTaskStats counts the jobs that ended between since and until per task and averages their duration.
+// Ended jobs are counted from the job archive of the queuer.
+func (r MetricDBHandler) SelectTaskStats(since time.Time, until time.Time) ([]*model.TaskStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			task_name,
+			COUNT(*) FILTER (WHERE status = 'SUCCEEDED'),
+			COUNT(*) FILTER (WHERE status = 'FAILED'),
+			COUNT(*) FILTER (WHERE status = 'CANCELLED'),
+			COALESCE(AVG(extract(epoch FROM updated_at - started_at)) FILTER (WHERE started_at IS NOT NULL), 0)::FLOAT8
+		FROM job_archive
+		WHERE updated_at >= $1
+			AND updated_at <= $2
+		GROUP BY task_name
+		ORDER BY task_name ASC
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, since, until)
+	if err != nil {
+		return nil, helper.NewError("select task stats", err)
+	}
+	defer rows.Close()
+
+	stats := []*model.TaskStats{}
+	for rows.Next() {
+		stat := &model.TaskStats{}
+		err := rows.Scan(
+			&stat.TaskName,
+			&stat.Succeeded,
+			&stat.Failed,
+			&stat.Cancelled,
+			&stat.AvgDurationSeconds,
+		)
+		if err != nil {
+			return nil, helper.NewError("scan task stats", err)
+		}
+		stats = append(stats, stat)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return stats, nil
+}
+
+// SelectActiveWorkersCount counts the ready and running workers in the worker table of the queuer.
+func (r MetricDBHandler) SelectActiveWorkersCount() (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `SELECT COUNT(*) FROM worker WHERE status IN ('READY', 'RUNNING')`
+
+	var count int
+	err := r.db.Instance.QueryRowContext(ctx, query).Scan(&count)
+	if err != nil {
+		return 0, helper.NewError("count active workers", err)
+	}
+
+	return count, nil
+}
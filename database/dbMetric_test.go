@@ -0,0 +1,355 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createQueuerTables creates minimal job, job archive and worker tables, which are normally created by the queuer.
+func createQueuerTables(t *testing.T, database *helper.Database) {
+	_, err := database.Instance.Exec(`CREATE TABLE IF NOT EXISTS job (status VARCHAR(50) NOT NULL, created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW())`)
+	require.NoError(t, err, "Expected job table creation to not return an error")
+	_, err = database.Instance.Exec(`ALTER TABLE job ADD COLUMN IF NOT EXISTS task_name VARCHAR(100) NOT NULL DEFAULT ''`)
+	require.NoError(t, err, "Expected job task_name column creation to not return an error")
+	_, err = database.Instance.Exec(`ALTER TABLE job ADD COLUMN IF NOT EXISTS rid UUID NOT NULL DEFAULT gen_random_uuid()`)
+	require.NoError(t, err, "Expected job rid column creation to not return an error")
+	_, err = database.Instance.Exec(`CREATE TABLE IF NOT EXISTS job_archive (id BIGSERIAL PRIMARY KEY, rid UUID NOT NULL DEFAULT gen_random_uuid(), worker_id BIGINT NOT NULL DEFAULT 0, worker_rid UUID, task_name VARCHAR(100) NOT NULL DEFAULT '', status VARCHAR(50) NOT NULL, started_at TIMESTAMP WITH TIME ZONE, error TEXT DEFAULT '', created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(), updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW())`)
+	require.NoError(t, err, "Expected job archive table creation to not return an error")
+	_, err = database.Instance.Exec(`CREATE TABLE IF NOT EXISTS worker (status VARCHAR(50) NOT NULL)`)
+	require.NoError(t, err, "Expected worker table creation to not return an error")
+	_, err = database.Instance.Exec(`CREATE TABLE IF NOT EXISTS master (id INTEGER PRIMARY KEY DEFAULT 1, worker_id BIGINT DEFAULT 0, worker_rid UUID, settings JSONB DEFAULT '{}', created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP); INSERT INTO master DEFAULT VALUES ON CONFLICT (id) DO NOTHING`)
+	require.NoError(t, err, "Expected master table creation to not return an error")
+}
+
+func TestMetricNewMetricDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewMetricDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		metricDbHandler, err := NewMetricDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewMetricDBHandler to not return an error")
+		require.NotNil(t, metricDbHandler, "Expected NewMetricDBHandler to return a non-nil instance")
+		assert.False(t, metricDbHandler.withTimescale, "Expected plain postgres to not have timescale")
+
+		exists, err := metricDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = metricDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewMetricDBHandler with nil database", func(t *testing.T) {
+		_, err := NewMetricDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating MetricDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestMetricInsertMetricSnapshot(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+	createQueuerTables(t, database)
+
+	metricDbHandler, err := NewMetricDBHandler(database, true)
+	require.NoError(t, err, "Expected NewMetricDBHandler to not return an error")
+
+	_, err = database.Instance.Exec(`INSERT INTO job (status) VALUES ('QUEUED'), ('QUEUED'), ('RUNNING')`)
+	require.NoError(t, err)
+	_, err = database.Instance.Exec(`INSERT INTO worker (status) VALUES ('READY'), ('RUNNING')`)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = database.Instance.Exec(`DELETE FROM job`)
+		_, _ = database.Instance.Exec(`DELETE FROM worker`)
+	})
+
+	metric, err := metricDbHandler.InsertMetricSnapshot()
+	assert.NoError(t, err, "Expected InsertMetricSnapshot to not return an error")
+	require.NotNil(t, metric, "Expected InsertMetricSnapshot to return a non-nil metric")
+	assert.Equal(t, 2, metric.JobsQueued, "Expected 2 queued jobs")
+	assert.Equal(t, 0, metric.JobsScheduled, "Expected 0 scheduled jobs")
+	assert.Equal(t, 1, metric.JobsRunning, "Expected 1 running job")
+	assert.Equal(t, 2, metric.WorkersTotal, "Expected 2 workers")
+	assert.Equal(t, 1, metric.WorkersReady, "Expected 1 ready worker")
+	assert.Equal(t, 1, metric.WorkersRunning, "Expected 1 running worker")
+	assert.WithinDuration(t, time.Now(), metric.Time, 1*time.Second, "Expected metric time to be now")
+}
+
+func TestMetricRecordMetricSnapshot(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+	createQueuerTables(t, database)
+
+	metricDbHandler, err := NewMetricDBHandler(database, true)
+	require.NoError(t, err, "Expected NewMetricDBHandler to not return an error")
+
+	t.Run("Record a snapshot once per minimum interval", func(t *testing.T) {
+		metric, err := metricDbHandler.RecordMetricSnapshot(time.Hour)
+		assert.NoError(t, err, "Expected RecordMetricSnapshot to not return an error")
+		assert.NotNil(t, metric, "Expected the first snapshot to be recorded")
+
+		metric, err = metricDbHandler.RecordMetricSnapshot(time.Hour)
+		assert.NoError(t, err, "Expected RecordMetricSnapshot to not return an error")
+		assert.Nil(t, metric, "Expected the snapshot within the minimum interval to be skipped")
+
+		metric, err = metricDbHandler.RecordMetricSnapshot(0)
+		assert.NoError(t, err, "Expected RecordMetricSnapshot to not return an error")
+		assert.NotNil(t, metric, "Expected the snapshot without minimum interval to be recorded")
+	})
+
+	t.Run("Skip the snapshot while another instance holds the lock", func(t *testing.T) {
+		tx, err := database.Instance.Begin()
+		require.NoError(t, err)
+		defer tx.Rollback()
+		_, err = tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('queue_metric'))`)
+		require.NoError(t, err)
+
+		metric, err := metricDbHandler.RecordMetricSnapshot(0)
+		assert.NoError(t, err, "Expected RecordMetricSnapshot to not return an error")
+		assert.Nil(t, metric, "Expected the snapshot to be skipped while the lock is held")
+	})
+
+	metrics, err := metricDbHandler.SelectMetrics(time.Now().Add(-time.Minute), time.Now().Add(time.Minute))
+	assert.NoError(t, err, "Expected SelectMetrics to not return an error")
+	assert.Len(t, metrics, 2, "Expected 2 recorded snapshots")
+}
+
+func TestMetricSelectMetrics(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+	createQueuerTables(t, database)
+
+	metricDbHandler, err := NewMetricDBHandler(database, true)
+	require.NoError(t, err, "Expected NewMetricDBHandler to not return an error")
+
+	for i := 0; i < 3; i++ {
+		_, err := metricDbHandler.InsertMetricSnapshot()
+		require.NoError(t, err, "Expected InsertMetricSnapshot to not return an error")
+	}
+
+	t.Run("Select raw metrics", func(t *testing.T) {
+		metrics, err := metricDbHandler.SelectMetrics(time.Now().Add(-time.Minute), time.Now())
+		assert.NoError(t, err, "Expected SelectMetrics to not return an error")
+		assert.Len(t, metrics, 3, "Expected 3 metric snapshots")
+	})
+
+	t.Run("Select hourly metrics", func(t *testing.T) {
+		metrics, err := metricDbHandler.SelectMetricsHourly(time.Now().Add(-time.Hour), time.Now())
+		assert.NoError(t, err, "Expected SelectMetricsHourly to not return an error")
+		assert.NotEmpty(t, metrics, "Expected at least one hourly bucket")
+	})
+
+	t.Run("Select metrics outside range", func(t *testing.T) {
+		metrics, err := metricDbHandler.SelectMetrics(time.Now().Add(-48*time.Hour), time.Now().Add(-24*time.Hour))
+		assert.NoError(t, err, "Expected SelectMetrics to not return an error")
+		assert.Empty(t, metrics, "Expected no metric snapshots")
+	})
+}
+
+func TestMetricSelectJobRates(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+	createQueuerTables(t, database)
+
+	metricDbHandler, err := NewMetricDBHandler(database, true)
+	require.NoError(t, err, "Expected NewMetricDBHandler to not return an error")
+
+	_, err = database.Instance.Exec(`INSERT INTO job (status) VALUES ('QUEUED'), ('RUNNING'), ('SCHEDULED')`)
+	require.NoError(t, err)
+	_, err = database.Instance.Exec(`INSERT INTO job_archive (status, created_at, updated_at) VALUES ('SUCCEEDED', NOW(), NOW()), ('FAILED', NOW() - INTERVAL '2 hours', NOW())`)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = database.Instance.Exec(`DELETE FROM job`)
+		_, _ = database.Instance.Exec(`DELETE FROM job_archive`)
+	})
+
+	rates, err := metricDbHandler.SelectJobRates(time.Now().Add(-time.Hour))
+	assert.NoError(t, err, "Expected SelectJobRates to not return an error")
+	require.NotNil(t, rates, "Expected SelectJobRates to return non-nil rates")
+	assert.Equal(t, 4, rates.Arrived, "Expected 4 jobs to have arrived within the window")
+	assert.Equal(t, 2, rates.Completed, "Expected 2 jobs to have ended within the window")
+	assert.Equal(t, 2, rates.Backlog, "Expected a backlog of 2 queued or running jobs")
+}
+
+func TestMetricSelectJobStats(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+	createQueuerTables(t, database)
+
+	metricDbHandler, err := NewMetricDBHandler(database, true)
+	require.NoError(t, err, "Expected NewMetricDBHandler to not return an error")
+
+	_, err = database.Instance.Exec(`
+		INSERT INTO job_archive (task_name, status, started_at, updated_at) VALUES
+			('task-a', 'SUCCEEDED', NOW() - INTERVAL '10 seconds', NOW()),
+			('task-a', 'FAILED', NOW() - INTERVAL '30 seconds', NOW()),
+			('task-b', 'CANCELLED', NULL, NOW()),
+			('task-a', 'SUCCEEDED', NOW() - INTERVAL '3 hours', NOW() - INTERVAL '2 hours')
+	`)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = database.Instance.Exec(`DELETE FROM job_archive`)
+	})
+
+	t.Run("Select job stats of all tasks", func(t *testing.T) {
+		stats, err := metricDbHandler.SelectJobStats(time.Now().Add(-time.Hour), time.Now(), 24*time.Hour, "")
+		assert.NoError(t, err, "Expected SelectJobStats to not return an error")
+		require.NotEmpty(t, stats, "Expected SelectJobStats to return at least one bucket")
+
+		succeeded, failed, cancelled := 0, 0, 0
+		for _, stat := range stats {
+			succeeded += stat.Succeeded
+			failed += stat.Failed
+			cancelled += stat.Cancelled
+		}
+		assert.Equal(t, 1, succeeded, "Expected 1 succeeded job within the range")
+		assert.Equal(t, 1, failed, "Expected 1 failed job within the range")
+		assert.Equal(t, 1, cancelled, "Expected 1 cancelled job within the range")
+	})
+
+	t.Run("Select job stats filtered by task", func(t *testing.T) {
+		stats, err := metricDbHandler.SelectJobStats(time.Now().Add(-time.Hour), time.Now(), time.Hour, "task-b")
+		assert.NoError(t, err, "Expected SelectJobStats to not return an error")
+		require.Len(t, stats, 1, "Expected one bucket for the cancelled job of task-b")
+		assert.Equal(t, 1, stats[0].Cancelled)
+		assert.Equal(t, 0, stats[0].Succeeded)
+		assert.Equal(t, float64(0), stats[0].AvgDurationSeconds, "Expected no duration for jobs that never started")
+	})
+}
+
+func TestMetricSelectDashboardStats(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+	createQueuerTables(t, database)
+
+	metricDbHandler, err := NewMetricDBHandler(database, true)
+	require.NoError(t, err, "Expected NewMetricDBHandler to not return an error")
+
+	_, err = database.Instance.Exec(`INSERT INTO job (status) VALUES ('QUEUED'), ('QUEUED'), ('RUNNING')`)
+	require.NoError(t, err)
+	_, err = database.Instance.Exec(`
+		INSERT INTO job_archive (task_name, status, started_at, updated_at) VALUES
+			('task-a', 'SUCCEEDED', NOW() - INTERVAL '10 seconds', NOW()),
+			('task-a', 'FAILED', NOW() - INTERVAL '30 seconds', NOW()),
+			('task-b', 'SUCCEEDED', NOW() - INTERVAL '5 seconds', NOW()),
+			('task-a', 'SUCCEEDED', NOW() - INTERVAL '3 hours', NOW() - INTERVAL '2 hours')
+	`)
+	require.NoError(t, err)
+	_, err = database.Instance.Exec(`INSERT INTO worker (status) VALUES ('READY'), ('RUNNING'), ('STOPPED')`)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = database.Instance.Exec(`DELETE FROM job`)
+		_, _ = database.Instance.Exec(`DELETE FROM job_archive`)
+		_, _ = database.Instance.Exec(`DELETE FROM worker`)
+	})
+
+	t.Run("Select job status counts", func(t *testing.T) {
+		counts, err := metricDbHandler.SelectJobStatusCounts(time.Now().Add(-time.Hour))
+		assert.NoError(t, err, "Expected SelectJobStatusCounts to not return an error")
+
+		byStatus := map[string]int{}
+		for _, count := range counts {
+			byStatus[count.Status] = count.Count
+		}
+		assert.Equal(t, map[string]int{"QUEUED": 2, "RUNNING": 1, "SUCCEEDED": 2, "FAILED": 1}, byStatus)
+	})
+
+	t.Run("Select task stats", func(t *testing.T) {
+		stats, err := metricDbHandler.SelectTaskStats(time.Now().Add(-time.Hour), time.Now())
+		assert.NoError(t, err, "Expected SelectTaskStats to not return an error")
+		require.Len(t, stats, 2, "Expected stats for task-a and task-b")
+		assert.Equal(t, "task-a", stats[0].TaskName)
+		assert.Equal(t, 1, stats[0].Succeeded)
+		assert.Equal(t, 1, stats[0].Failed)
+		assert.InDelta(t, 0.5, stats[0].FailureRate(), 0.001)
+		assert.InDelta(t, 20, stats[0].AvgDurationSeconds, 1)
+		assert.Equal(t, "task-b", stats[1].TaskName)
+	})
+
+	t.Run("Select active workers count", func(t *testing.T) {
+		count, err := metricDbHandler.SelectActiveWorkersCount()
+		assert.NoError(t, err, "Expected SelectActiveWorkersCount to not return an error")
+		assert.Equal(t, 2, count, "Expected the ready and running workers to be active")
+	})
+}
+
+func TestMetricSelectJobHeatmap(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+	createQueuerTables(t, database)
+
+	metricDbHandler, err := NewMetricDBHandler(database, true)
+	require.NoError(t, err, "Expected NewMetricDBHandler to not return an error")
+
+	// 2024-01-01 was a Monday
+	_, err = database.Instance.Exec(`
+		INSERT INTO job_archive (task_name, status, updated_at) VALUES
+			('task-a', 'SUCCEEDED', '2024-01-01 22:15:00+00'),
+			('task-a', 'FAILED', '2024-01-01 22:45:00+00'),
+			('task-b', 'SUCCEEDED', '2024-01-03 08:00:00+00')
+	`)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = database.Instance.Exec(`DELETE FROM job_archive`)
+	})
+
+	since := time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Select job heatmap in UTC", func(t *testing.T) {
+		cells, err := metricDbHandler.SelectJobHeatmap(since, until, "UTC", "")
+		assert.NoError(t, err, "Expected SelectJobHeatmap to not return an error")
+		require.Len(t, cells, 2, "Expected cells for Monday 22:00 and Wednesday 08:00")
+		assert.Equal(t, 1, cells[0].Weekday)
+		assert.Equal(t, 22, cells[0].Hour)
+		assert.Equal(t, 2, cells[0].Total)
+		assert.Equal(t, 1, cells[0].Failed)
+		assert.Equal(t, 3, cells[1].Weekday)
+		assert.Equal(t, 8, cells[1].Hour)
+	})
+
+	t.Run("Select job heatmap in another timezone", func(t *testing.T) {
+		cells, err := metricDbHandler.SelectJobHeatmap(since, until, "Europe/Berlin", "task-a")
+		assert.NoError(t, err, "Expected SelectJobHeatmap to not return an error")
+		require.Len(t, cells, 1, "Expected one cell for the jobs of task-a")
+		assert.Equal(t, 1, cells[0].Weekday, "Expected 23:00 in Berlin to still be Monday")
+		assert.Equal(t, 23, cells[0].Hour)
+	})
+}
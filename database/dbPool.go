@@ -0,0 +1,59 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/siherrmann/queuerManager/helper"
+	"github.com/siherrmann/queuerManager/model"
+)
+
+// NewDBPoolConfigFromEnv reads the connection pool settings from the environment variables
+// QUEUER_MANAGER_DB_MAX_OPEN_CONNS, QUEUER_MANAGER_DB_MAX_IDLE_CONNS,
+// QUEUER_MANAGER_DB_CONN_MAX_LIFETIME and QUEUER_MANAGER_DB_CONN_MAX_IDLE_TIME.
+// Durations are parsed with time.ParseDuration (eg. "30m").
+func NewDBPoolConfigFromEnv() (*model.DBPoolConfig, error) {
+	config := &model.DBPoolConfig{}
+
+	var err error
+	config.MaxOpenConns, err = strconv.Atoi(helper.GetEnvOrDefault("QUEUER_MANAGER_DB_MAX_OPEN_CONNS", "0"))
+	if err != nil || config.MaxOpenConns < 0 {
+		return nil, fmt.Errorf("invalid QUEUER_MANAGER_DB_MAX_OPEN_CONNS (must be >= 0)")
+	}
+
+	config.MaxIdleConns, err = strconv.Atoi(helper.GetEnvOrDefault("QUEUER_MANAGER_DB_MAX_IDLE_CONNS", "2"))
+	if err != nil || config.MaxIdleConns < 0 {
+		return nil, fmt.Errorf("invalid QUEUER_MANAGER_DB_MAX_IDLE_CONNS (must be >= 0)")
+	}
+
+	config.ConnMaxLifetime, err = time.ParseDuration(helper.GetEnvOrDefault("QUEUER_MANAGER_DB_CONN_MAX_LIFETIME", "0s"))
+	if err != nil || config.ConnMaxLifetime < 0 {
+		return nil, fmt.Errorf("invalid QUEUER_MANAGER_DB_CONN_MAX_LIFETIME (must be a duration >= 0)")
+	}
+
+	config.ConnMaxIdleTime, err = time.ParseDuration(helper.GetEnvOrDefault("QUEUER_MANAGER_DB_CONN_MAX_IDLE_TIME", "0s"))
+	if err != nil || config.ConnMaxIdleTime < 0 {
+		return nil, fmt.Errorf("invalid QUEUER_MANAGER_DB_CONN_MAX_IDLE_TIME (must be a duration >= 0)")
+	}
+
+	return config, nil
+}
+
+// ConfigureDBPool applies the pool config to the shared database connection.
+func ConfigureDBPool(db *sql.DB, config *model.DBPoolConfig) error {
+	if db == nil {
+		return fmt.Errorf("database connection is nil")
+	}
+	if config == nil {
+		return fmt.Errorf("pool config is nil")
+	}
+
+	db.SetMaxOpenConns(config.MaxOpenConns)
+	db.SetMaxIdleConns(config.MaxIdleConns)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDBPoolConfigFromEnv(t *testing.T) {
+	t.Run("Defaults without env", func(t *testing.T) {
+		config, err := NewDBPoolConfigFromEnv()
+		require.NoError(t, err, "Expected NewDBPoolConfigFromEnv to not return an error")
+		assert.Equal(t, 0, config.MaxOpenConns)
+		assert.Equal(t, 2, config.MaxIdleConns)
+		assert.Equal(t, time.Duration(0), config.ConnMaxLifetime)
+		assert.Equal(t, time.Duration(0), config.ConnMaxIdleTime)
+	})
+
+	t.Run("Values from env", func(t *testing.T) {
+		t.Setenv("QUEUER_MANAGER_DB_MAX_OPEN_CONNS", "20")
+		t.Setenv("QUEUER_MANAGER_DB_MAX_IDLE_CONNS", "5")
+		t.Setenv("QUEUER_MANAGER_DB_CONN_MAX_LIFETIME", "30m")
+		t.Setenv("QUEUER_MANAGER_DB_CONN_MAX_IDLE_TIME", "5m")
+
+		config, err := NewDBPoolConfigFromEnv()
+		require.NoError(t, err, "Expected NewDBPoolConfigFromEnv to not return an error")
+		assert.Equal(t, 20, config.MaxOpenConns)
+		assert.Equal(t, 5, config.MaxIdleConns)
+		assert.Equal(t, 30*time.Minute, config.ConnMaxLifetime)
+		assert.Equal(t, 5*time.Minute, config.ConnMaxIdleTime)
+	})
+
+	t.Run("Invalid values from env", func(t *testing.T) {
+		t.Setenv("QUEUER_MANAGER_DB_MAX_OPEN_CONNS", "-1")
+		_, err := NewDBPoolConfigFromEnv()
+		assert.Error(t, err, "Expected error for negative max open connections")
+
+		t.Setenv("QUEUER_MANAGER_DB_MAX_OPEN_CONNS", "10")
+		t.Setenv("QUEUER_MANAGER_DB_CONN_MAX_LIFETIME", "forever")
+		_, err = NewDBPoolConfigFromEnv()
+		assert.Error(t, err, "Expected error for invalid lifetime")
+	})
+}
+
+func TestConfigureDBPool(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	t.Run("Valid call ConfigureDBPool", func(t *testing.T) {
+		err := ConfigureDBPool(database.Instance, &model.DBPoolConfig{MaxOpenConns: 7, MaxIdleConns: 3})
+		assert.NoError(t, err, "Expected ConfigureDBPool to not return an error")
+		assert.Equal(t, 7, database.Instance.Stats().MaxOpenConnections)
+	})
+
+	t.Run("Invalid call ConfigureDBPool with nil database", func(t *testing.T) {
+		err := ConfigureDBPool(nil, &model.DBPoolConfig{})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "database connection is nil")
+	})
+}
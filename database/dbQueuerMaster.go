@@ -0,0 +1,84 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	qm "github.com/siherrmann/queuer/model"
+)
+
+// QueuerMasterDBHandlerFunctions defines the interface for reading the master of the queuer.
+type QueuerMasterDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	SelectMaster() (*qm.Master, error)
+}
+
+// QueuerMasterDBHandler implements QueuerMasterDBHandlerFunctions and holds the database connection.
+// The master table is owned by the queuer, so the handler does not create or drop it.
+type QueuerMasterDBHandler struct {
+	db *helper.Database
+}
+
+// NewQueuerMasterDBHandler creates a new instance of QueuerMasterDBHandler.
+func NewQueuerMasterDBHandler(dbConnection *helper.Database) (*QueuerMasterDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	return &QueuerMasterDBHandler{
+		db: dbConnection,
+	}, nil
+}
+
+// CheckTableExistance checks if the 'master' table of the queuer exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r QueuerMasterDBHandler) CheckTableExistance() (bool, error) {
+	masterExists, err := r.db.CheckTableExistance("master")
+	if err != nil {
+		return false, helper.NewError("master table", err)
+	}
+	return masterExists, nil
+}
+
+// SelectMaster retrieves the master entry of the queuer, the worker ID is 0 if no worker ever became master.
+// UpdatedAt is the last time the master worker renewed its lock.
+func (r QueuerMasterDBHandler) SelectMaster() (*qm.Master, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			worker_id,
+			worker_rid,
+			settings,
+			created_at,
+			updated_at
+		FROM master
+		WHERE id = 1
+	`
+
+	master := &qm.Master{}
+	var workerRID uuid.NullUUID
+	err := r.db.Instance.QueryRowContext(ctx, query).Scan(
+		&master.ID,
+		&master.WorkerID,
+		&workerRID,
+		&master.Settings,
+		&master.CreatedAt,
+		&master.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("master not found", fmt.Errorf("no master entry of the queuer"))
+		}
+		return nil, helper.NewError("select master", err)
+	}
+	master.WorkerRID = workerRID.UUID
+
+	return master, nil
+}
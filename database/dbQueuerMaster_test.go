@@ -0,0 +1,68 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueuerMasterNewQueuerMasterDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewQueuerMasterDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+		createQueuerTables(t, database)
+
+		queuerMasterDbHandler, err := NewQueuerMasterDBHandler(database)
+		assert.NoError(t, err, "Expected NewQueuerMasterDBHandler to not return an error")
+		require.NotNil(t, queuerMasterDbHandler, "Expected NewQueuerMasterDBHandler to return a non-nil instance")
+
+		exists, err := queuerMasterDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("Invalid call NewQueuerMasterDBHandler with nil database", func(t *testing.T) {
+		_, err := NewQueuerMasterDBHandler(nil)
+		assert.Error(t, err, "Expected error when creating QueuerMasterDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestQueuerMasterSelectMaster(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+	createQueuerTables(t, database)
+
+	queuerMasterDbHandler, err := NewQueuerMasterDBHandler(database)
+	require.NoError(t, err, "Expected NewQueuerMasterDBHandler to not return an error")
+
+	t.Run("Master without worker", func(t *testing.T) {
+		master, err := queuerMasterDbHandler.SelectMaster()
+		require.NoError(t, err, "Expected SelectMaster to not return an error")
+		assert.Equal(t, 0, master.WorkerID)
+		assert.Equal(t, uuid.Nil, master.WorkerRID)
+	})
+
+	t.Run("Master with worker", func(t *testing.T) {
+		workerRID := uuid.New()
+		_, err := database.Instance.Exec(`UPDATE master SET worker_id = 3, worker_rid = $1, updated_at = CURRENT_TIMESTAMP WHERE id = 1`, workerRID)
+		require.NoError(t, err)
+
+		master, err := queuerMasterDbHandler.SelectMaster()
+		require.NoError(t, err, "Expected SelectMaster to not return an error")
+		assert.Equal(t, 3, master.WorkerID)
+		assert.Equal(t, workerRID, master.WorkerRID)
+	})
+}
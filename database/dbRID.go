@@ -0,0 +1,157 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+)
+
+// RIDDBHandlerFunctions defines the interface for the RID generation of the manager tables.
+type RIDDBHandlerFunctions interface {
+	CreateFunction() error
+	SetRIDStrategy(strategy string) error
+	MigrateRIDs(table string) (int64, error)
+}
+
+// RIDDBHandler implements RIDDBHandlerFunctions and holds the database connection.
+// It does not own a table, it changes the RID defaults of the tables of the other handlers.
+type RIDDBHandler struct {
+	db *helper.Database
+}
+
+// NewRIDDBHandler creates a new instance of RIDDBHandler.
+// It initializes the database connection and creates the function generating UUIDv7 RIDs.
+func NewRIDDBHandler(dbConnection *helper.Database) (*RIDDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	ridDbHandler := &RIDDBHandler{
+		db: dbConnection,
+	}
+
+	err := ridDbHandler.CreateFunction()
+	if err != nil {
+		return nil, helper.NewError("create function", err)
+	}
+
+	return ridDbHandler, nil
+}
+
+// CreateFunction creates the 'manager_uuid_v7' function in the database.
+// It returns a UUIDv7 of the timestamp (default now), the random bits of gen_random_uuid are kept
+// and the first 48 bits are replaced with the unix milliseconds of the timestamp.
+func (r RIDDBHandler) CreateFunction() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE OR REPLACE FUNCTION manager_uuid_v7(ts TIMESTAMP WITH TIME ZONE DEFAULT clock_timestamp())
+		RETURNS UUID AS $$
+			SELECT encode(
+				set_bit(
+					set_bit(
+						overlay(
+							uuid_send(gen_random_uuid())
+							PLACING substring(int8send(floor(extract(epoch FROM ts) * 1000)::BIGINT) FROM 3)
+							FROM 1 FOR 6
+						),
+						52, 1
+					),
+					53, 1
+				),
+				'hex'
+			)::UUID;
+		$$ LANGUAGE SQL VOLATILE;
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create manager_uuid_v7 function", err)
+	}
+
+	r.db.Logger.Info("Checked/created function manager_uuid_v7")
+
+	return nil
+}
+
+// SetRIDStrategy sets the default of the RID column of the existing manager tables to the RID strategy.
+// Existing rows keep their RIDs, only the RIDs of new rows are generated with the strategy.
+func (r RIDDBHandler) SetRIDStrategy(strategy string) error {
+	ridDefault := ""
+	switch strategy {
+	case model.RID_STRATEGY_RANDOM:
+		ridDefault = "gen_random_uuid()"
+	case model.RID_STRATEGY_UUIDV7:
+		ridDefault = "manager_uuid_v7()"
+	default:
+		return helper.NewError("rid strategy validation", fmt.Errorf("invalid rid strategy %q (must be one of %s)", strategy, strings.Join(model.RID_STRATEGIES, ", ")))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, table := range model.RID_TABLES {
+		exists, err := r.db.CheckTableExistance(table)
+		if err != nil {
+			return helper.NewError(fmt.Sprintf("%s table", table), err)
+		}
+		if !exists {
+			continue
+		}
+
+		_, err = r.db.Instance.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN rid SET DEFAULT %s;`, table, ridDefault))
+		if err != nil {
+			return helper.NewError(fmt.Sprintf("set rid default of %s", table), err)
+		}
+	}
+
+	r.db.Logger.Info("Set rid strategy", "strategy", strategy)
+
+	return nil
+}
+
+// MigrateRIDs replaces the RIDs of the rows of a table that are no UUIDv7 with UUIDv7 RIDs of their creation time,
+// so the RIDs of existing rows are sorted like new ones. It returns the number of migrated rows, a missing table has none.
+// Migrated rows are no longer found by their previous RIDs, eg. in bookmarked URLs.
+func (r RIDDBHandler) MigrateRIDs(table string) (int64, error) {
+	if !slices.Contains(model.RID_MIGRATABLE_TABLES, table) {
+		return 0, helper.NewError("table validation", fmt.Errorf("rids of table %q can not be migrated (must be one of %s)", table, strings.Join(model.RID_MIGRATABLE_TABLES, ", ")))
+	}
+
+	exists, err := r.db.CheckTableExistance(table)
+	if err != nil {
+		return 0, helper.NewError(fmt.Sprintf("%s table", table), err)
+	}
+	if !exists {
+		return 0, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	// The version is the first character of the third group of the RID
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET rid = manager_uuid_v7(created_at)
+		WHERE substring(rid::TEXT FROM 15 FOR 1) <> '7';
+	`, table)
+
+	result, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return 0, helper.NewError(fmt.Sprintf("migrate rids of %s", table), err)
+	}
+
+	migrated, err := result.RowsAffected()
+	if err != nil {
+		return 0, helper.NewError("rows affected", err)
+	}
+
+	return migrated, nil
+}
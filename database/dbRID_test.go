@@ -0,0 +1,82 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRIDNewRIDDBHandler(t *testing.T) {
+	t.Run("Invalid call NewRIDDBHandler with nil database", func(t *testing.T) {
+		_, err := NewRIDDBHandler(nil)
+		assert.Error(t, err, "Expected error when creating RIDDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestRIDSetRIDStrategyAndMigrateRIDs(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	taskDbHandler, err := NewTaskDBHandler(database, true)
+	require.NoError(t, err, "Expected NewTaskDBHandler to not return an error")
+	ridDbHandler, err := NewRIDDBHandler(database)
+	require.NoError(t, err, "Expected NewRIDDBHandler to not return an error")
+	t.Cleanup(func() {
+		assert.NoError(t, ridDbHandler.SetRIDStrategy(model.RID_STRATEGY_RANDOM))
+	})
+
+	randomTask, err := taskDbHandler.InsertTask(&model.Task{Key: "rid_random", Name: "Random"})
+	require.NoError(t, err, "Expected InsertTask to not return an error")
+	assert.Equal(t, uuid.Version(4), randomTask.RID.Version())
+
+	t.Run("Invalid strategy", func(t *testing.T) {
+		err := ridDbHandler.SetRIDStrategy("sequential")
+		assert.Error(t, err, "Expected SetRIDStrategy to return an error for an invalid strategy")
+	})
+
+	t.Run("UUIDv7 strategy", func(t *testing.T) {
+		err := ridDbHandler.SetRIDStrategy(model.RID_STRATEGY_UUIDV7)
+		require.NoError(t, err, "Expected SetRIDStrategy to not return an error")
+
+		first, err := taskDbHandler.InsertTask(&model.Task{Key: "rid_v7_first", Name: "First"})
+		require.NoError(t, err, "Expected InsertTask to not return an error")
+		second, err := taskDbHandler.InsertTask(&model.Task{Key: "rid_v7_second", Name: "Second"})
+		require.NoError(t, err, "Expected InsertTask to not return an error")
+
+		assert.Equal(t, uuid.Version(7), first.RID.Version())
+		assert.Equal(t, uuid.Version(7), second.RID.Version())
+		assert.Less(t, first.RID.String(), second.RID.String(), "Expected UUIDv7 RIDs to be ordered by creation")
+	})
+
+	t.Run("Migrate RIDs", func(t *testing.T) {
+		migrated, err := ridDbHandler.MigrateRIDs("task")
+		require.NoError(t, err, "Expected MigrateRIDs to not return an error")
+		assert.Equal(t, int64(1), migrated, "Expected only the random RID to be migrated")
+
+		migratedTask, err := taskDbHandler.SelectTaskByKey("rid_random")
+		require.NoError(t, err, "Expected SelectTaskByKey to not return an error")
+		assert.Equal(t, uuid.Version(7), migratedTask.RID.Version())
+		sec, nsec := migratedTask.RID.Time().UnixTime()
+		assert.WithinDuration(t, randomTask.CreatedAt, time.Unix(sec, nsec), time.Millisecond, "Expected the RID to hold the creation time")
+
+		migrated, err = ridDbHandler.MigrateRIDs("task")
+		require.NoError(t, err, "Expected MigrateRIDs to not return an error")
+		assert.Equal(t, int64(0), migrated, "Expected no RIDs to be migrated twice")
+	})
+
+	t.Run("Migrate RIDs of referenced table", func(t *testing.T) {
+		_, err := ridDbHandler.MigrateRIDs("manager_user")
+		assert.Error(t, err, "Expected MigrateRIDs to return an error for users")
+	})
+}
@@ -0,0 +1,220 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+)
+
+// ScaleAuditDBHandlerFunctions defines the interface for ScaleAudit database operations.
+type ScaleAuditDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	InsertScaleAudit(audit *model.ScaleAudit) (*model.ScaleAudit, error)
+	SelectAllScaleAudits(lastID int, entries int) ([]*model.ScaleAudit, error)
+}
+
+// ScaleAuditDBHandler implements ScaleAuditDBHandlerFunctions and holds the database connection.
+type ScaleAuditDBHandler struct {
+	db *helper.Database
+}
+
+// NewScaleAuditDBHandler creates a new instance of ScaleAuditDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing scale_audit table before creating a new one
+func NewScaleAuditDBHandler(dbConnection *helper.Database, withTableDrop bool) (*ScaleAuditDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	scaleAuditDbHandler := &ScaleAuditDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := scaleAuditDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := scaleAuditDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return scaleAuditDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'scale_audit' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r ScaleAuditDBHandler) CheckTableExistance() (bool, error) {
+	scaleAuditExists, err := r.db.CheckTableExistance("scale_audit")
+	if err != nil {
+		return false, helper.NewError("scale_audit table", err)
+	}
+	return scaleAuditExists, nil
+}
+
+// CreateTable creates the 'scale_audit' table in the database.
+// If the table already exists, it does not create it again.
+func (r ScaleAuditDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS scale_audit (
+			id SERIAL PRIMARY KEY,
+			pool VARCHAR(100) NOT NULL DEFAULT '',
+			scaler VARCHAR(50) NOT NULL,
+			desired_count INT NOT NULL,
+			current_workers INT NOT NULL,
+			requested_by VARCHAR(100) NOT NULL DEFAULT '',
+			status VARCHAR(20) NOT NULL,
+			error TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create scale_audit table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table scale_audit")
+
+	return nil
+}
+
+// DropTable drops the 'scale_audit' table from the database.
+func (r ScaleAuditDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS scale_audit`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop scale_audit table", err)
+	}
+
+	r.db.Logger.Info("Dropped table scale_audit")
+
+	return nil
+}
+
+// InsertScaleAudit inserts a new scale audit record into the database.
+func (r ScaleAuditDBHandler) InsertScaleAudit(audit *model.ScaleAudit) (*model.ScaleAudit, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	newAudit := &model.ScaleAudit{}
+	query := `
+		INSERT INTO scale_audit (
+			pool,
+			scaler,
+			desired_count,
+			current_workers,
+			requested_by,
+			status,
+			error
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING
+			id,
+			pool,
+			scaler,
+			desired_count,
+			current_workers,
+			requested_by,
+			status,
+			error,
+			created_at`
+
+	err := r.db.Instance.QueryRowContext(
+		ctx,
+		query,
+		audit.Pool,
+		audit.Scaler,
+		audit.DesiredCount,
+		audit.CurrentWorkers,
+		audit.RequestedBy,
+		audit.Status,
+		audit.Error,
+	).Scan(
+		&newAudit.ID,
+		&newAudit.Pool,
+		&newAudit.Scaler,
+		&newAudit.DesiredCount,
+		&newAudit.CurrentWorkers,
+		&newAudit.RequestedBy,
+		&newAudit.Status,
+		&newAudit.Error,
+		&newAudit.CreatedAt,
+	)
+	if err != nil {
+		return nil, helper.NewError("insert scale audit", err)
+	}
+
+	return newAudit, nil
+}
+
+// SelectAllScaleAudits retrieves the scale audit trail from the database with pagination, newest first.
+// lastID is the ID of the last entry from the previous page (0 for first page)
+// entries is the maximum number of entries to return
+func (r ScaleAuditDBHandler) SelectAllScaleAudits(lastID int, entries int) ([]*model.ScaleAudit, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			pool,
+			scaler,
+			desired_count,
+			current_workers,
+			requested_by,
+			status,
+			error,
+			created_at
+		FROM scale_audit
+		WHERE $1 = 0 OR id < $1
+		ORDER BY id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, lastID, entries)
+	if err != nil {
+		return nil, helper.NewError("select all scale audits", err)
+	}
+	defer rows.Close()
+
+	audits := []*model.ScaleAudit{}
+	for rows.Next() {
+		audit := &model.ScaleAudit{}
+		err := rows.Scan(
+			&audit.ID,
+			&audit.Pool,
+			&audit.Scaler,
+			&audit.DesiredCount,
+			&audit.CurrentWorkers,
+			&audit.RequestedBy,
+			&audit.Status,
+			&audit.Error,
+			&audit.CreatedAt,
+		)
+		if err != nil {
+			return nil, helper.NewError("scan scale audit", err)
+		}
+		audits = append(audits, audit)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return audits, nil
+}
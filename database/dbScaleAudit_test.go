@@ -0,0 +1,88 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaleAuditNewScaleAuditDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewScaleAuditDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		scaleAuditDbHandler, err := NewScaleAuditDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewScaleAuditDBHandler to not return an error")
+		require.NotNil(t, scaleAuditDbHandler, "Expected NewScaleAuditDBHandler to return a non-nil instance")
+
+		exists, err := scaleAuditDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = scaleAuditDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewScaleAuditDBHandler with nil database", func(t *testing.T) {
+		_, err := NewScaleAuditDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating ScaleAuditDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestScaleAuditInsertAndSelectScaleAudits(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	scaleAuditDbHandler, err := NewScaleAuditDBHandler(database, true)
+	require.NoError(t, err, "Expected NewScaleAuditDBHandler to not return an error")
+
+	insertedAudit, err := scaleAuditDbHandler.InsertScaleAudit(&model.ScaleAudit{
+		Pool:           "test-worker",
+		Scaler:         "kubernetes",
+		DesiredCount:   3,
+		CurrentWorkers: 1,
+		RequestedBy:    "127.0.0.1",
+		Status:         "requested",
+	})
+	require.NoError(t, err, "Expected InsertScaleAudit to not return an error")
+	assert.NotZero(t, insertedAudit.ID)
+	assert.Equal(t, "test-worker", insertedAudit.Pool)
+	assert.Equal(t, 3, insertedAudit.DesiredCount)
+	assert.False(t, insertedAudit.CreatedAt.IsZero())
+
+	t.Run("Select all scale audits newest first", func(t *testing.T) {
+		_, err := scaleAuditDbHandler.InsertScaleAudit(&model.ScaleAudit{
+			Pool:         "test-worker",
+			Scaler:       "kubernetes",
+			DesiredCount: 1,
+			Status:       "failed",
+			Error:        "forbidden",
+		})
+		require.NoError(t, err)
+
+		audits, err := scaleAuditDbHandler.SelectAllScaleAudits(0, 10)
+		require.NoError(t, err, "Expected SelectAllScaleAudits to not return an error")
+		require.Len(t, audits, 2)
+		assert.Equal(t, "failed", audits[0].Status)
+		assert.Equal(t, "forbidden", audits[0].Error)
+
+		audits, err = scaleAuditDbHandler.SelectAllScaleAudits(audits[0].ID, 10)
+		require.NoError(t, err)
+		require.Len(t, audits, 1)
+		assert.Equal(t, insertedAudit.ID, audits[0].ID)
+	})
+}
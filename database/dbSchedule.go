@@ -0,0 +1,485 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+)
+
+// ScheduleDBHandlerFunctions defines the interface for Schedule database operations.
+type ScheduleDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	InsertSchedule(schedule *model.Schedule) (*model.Schedule, error)
+	UpdateSchedule(schedule *model.Schedule) (*model.Schedule, error)
+	UpdateScheduleEnabled(rid uuid.UUID, enabled bool, nextRunAt *time.Time) (*model.Schedule, error)
+	ClaimScheduleRun(schedule *model.Schedule, nextRunAt *time.Time) (bool, error)
+	UpdateScheduleRunResult(rid uuid.UUID, jobRID *uuid.UUID, lastError string) error
+	DeleteSchedule(rid uuid.UUID) error
+	SelectSchedule(rid uuid.UUID) (*model.Schedule, error)
+	SelectAllSchedules() ([]*model.Schedule, error)
+	SelectDueSchedules(now time.Time) ([]*model.Schedule, error)
+}
+
+// ScheduleDBHandler implements ScheduleDBHandlerFunctions and holds the database connection.
+type ScheduleDBHandler struct {
+	db *helper.Database
+}
+
+// NewScheduleDBHandler creates a new instance of ScheduleDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing schedule table before creating a new one
+func NewScheduleDBHandler(dbConnection *helper.Database, withTableDrop bool) (*ScheduleDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	scheduleDbHandler := &ScheduleDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := scheduleDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := scheduleDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return scheduleDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'schedule' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r ScheduleDBHandler) CheckTableExistance() (bool, error) {
+	scheduleExists, err := r.db.CheckTableExistance("schedule")
+	if err != nil {
+		return false, helper.NewError("schedule table", err)
+	}
+	return scheduleExists, nil
+}
+
+// CreateTable creates the 'schedule' table in the database.
+// If the table already exists, it does not create it again.
+func (r ScheduleDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS schedule (
+			id SERIAL PRIMARY KEY,
+			rid UUID UNIQUE NOT NULL DEFAULT gen_random_uuid(),
+			name VARCHAR(100) UNIQUE NOT NULL,
+			task_key VARCHAR(100) NOT NULL,
+			cron_expression VARCHAR(100) NOT NULL,
+			parameters JSONB NOT NULL DEFAULT '{}'::jsonb,
+			enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			next_run_at TIMESTAMP WITH TIME ZONE,
+			last_run_at TIMESTAMP WITH TIME ZONE,
+			last_job_rid UUID,
+			last_error TEXT NOT NULL DEFAULT '',
+			created_by VARCHAR(255) NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_schedule_next_run_at ON schedule(next_run_at) WHERE enabled;
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create schedule table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table schedule")
+
+	return nil
+}
+
+// DropTable drops the 'schedule' table from the database.
+func (r ScheduleDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS schedule`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop schedule table", err)
+	}
+
+	r.db.Logger.Info("Dropped table schedule")
+
+	return nil
+}
+
+// InsertSchedule inserts a new schedule into the database.
+func (r ScheduleDBHandler) InsertSchedule(schedule *model.Schedule) (*model.Schedule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		INSERT INTO schedule (
+			name,
+			task_key,
+			cron_expression,
+			parameters,
+			enabled,
+			next_run_at,
+			created_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING
+			id,
+			rid,
+			name,
+			task_key,
+			cron_expression,
+			parameters,
+			enabled,
+			next_run_at,
+			last_run_at,
+			last_job_rid,
+			last_error,
+			created_by,
+			created_at,
+			updated_at`
+
+	row := r.db.Instance.QueryRowContext(
+		ctx,
+		query,
+		schedule.Name,
+		schedule.TaskKey,
+		schedule.CronExpression,
+		schedule.Parameters,
+		schedule.Enabled,
+		schedule.NextRunAt,
+		schedule.CreatedBy,
+	)
+	insertedSchedule, err := scanSchedule(row)
+	if err != nil {
+		return nil, helper.NewError("insert schedule", err)
+	}
+
+	return insertedSchedule, nil
+}
+
+// UpdateSchedule updates the name, task, cron expression, parameters, enabled state and next run of a schedule.
+func (r ScheduleDBHandler) UpdateSchedule(schedule *model.Schedule) (*model.Schedule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE schedule
+		SET
+			name = $2,
+			task_key = $3,
+			cron_expression = $4,
+			parameters = $5,
+			enabled = $6,
+			next_run_at = $7,
+			updated_at = NOW()
+		WHERE rid = $1
+		RETURNING
+			id,
+			rid,
+			name,
+			task_key,
+			cron_expression,
+			parameters,
+			enabled,
+			next_run_at,
+			last_run_at,
+			last_job_rid,
+			last_error,
+			created_by,
+			created_at,
+			updated_at`
+
+	row := r.db.Instance.QueryRowContext(
+		ctx,
+		query,
+		schedule.RID,
+		schedule.Name,
+		schedule.TaskKey,
+		schedule.CronExpression,
+		schedule.Parameters,
+		schedule.Enabled,
+		schedule.NextRunAt,
+	)
+	updatedSchedule, err := scanSchedule(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("schedule not found", fmt.Errorf("no schedule with rid %s", schedule.RID))
+		}
+		return nil, helper.NewError("update schedule", err)
+	}
+
+	return updatedSchedule, nil
+}
+
+// UpdateScheduleEnabled enables or disables a schedule with the next run it runs at.
+func (r ScheduleDBHandler) UpdateScheduleEnabled(rid uuid.UUID, enabled bool, nextRunAt *time.Time) (*model.Schedule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE schedule
+		SET
+			enabled = $2,
+			next_run_at = $3,
+			updated_at = NOW()
+		WHERE rid = $1
+		RETURNING
+			id,
+			rid,
+			name,
+			task_key,
+			cron_expression,
+			parameters,
+			enabled,
+			next_run_at,
+			last_run_at,
+			last_job_rid,
+			last_error,
+			created_by,
+			created_at,
+			updated_at`
+
+	schedule, err := scanSchedule(r.db.Instance.QueryRowContext(ctx, query, rid, enabled, nextRunAt))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("schedule not found", fmt.Errorf("no schedule with rid %s", rid))
+		}
+		return nil, helper.NewError("update schedule enabled", err)
+	}
+
+	return schedule, nil
+}
+
+// ClaimScheduleRun moves a due schedule to its next run and reports if this call claimed the run.
+// The run is only claimed if the schedule is still enabled and due at the next run it was selected with,
+// so with several manager instances only one of them adds the job.
+func (r ScheduleDBHandler) ClaimScheduleRun(schedule *model.Schedule, nextRunAt *time.Time) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE schedule
+		SET
+			next_run_at = $3,
+			last_run_at = NOW()
+		WHERE rid = $1
+			AND enabled
+			AND next_run_at = $2`
+
+	result, err := r.db.Instance.ExecContext(ctx, query, schedule.RID, schedule.NextRunAt, nextRunAt)
+	if err != nil {
+		return false, helper.NewError("claim schedule run", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, helper.NewError("rows affected", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// UpdateScheduleRunResult records the job added by the last run of a schedule or the error adding it failed with.
+func (r ScheduleDBHandler) UpdateScheduleRunResult(rid uuid.UUID, jobRID *uuid.UUID, lastError string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE schedule
+		SET
+			last_job_rid = COALESCE($2, last_job_rid),
+			last_error = $3
+		WHERE rid = $1`
+
+	_, err := r.db.Instance.ExecContext(ctx, query, rid, jobRID, lastError)
+	if err != nil {
+		return helper.NewError("update schedule run result", err)
+	}
+
+	return nil
+}
+
+// DeleteSchedule deletes a schedule by RID from the database.
+func (r ScheduleDBHandler) DeleteSchedule(rid uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DELETE FROM schedule WHERE rid = $1`
+	result, err := r.db.Instance.ExecContext(ctx, query, rid)
+	if err != nil {
+		return helper.NewError("delete schedule", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return helper.NewError("rows affected", err)
+	}
+	if rowsAffected == 0 {
+		return helper.NewError("schedule not found", fmt.Errorf("no schedule with rid %s", rid))
+	}
+
+	return nil
+}
+
+// SelectSchedule retrieves a schedule by RID from the database.
+func (r ScheduleDBHandler) SelectSchedule(rid uuid.UUID) (*model.Schedule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			rid,
+			name,
+			task_key,
+			cron_expression,
+			parameters,
+			enabled,
+			next_run_at,
+			last_run_at,
+			last_job_rid,
+			last_error,
+			created_by,
+			created_at,
+			updated_at
+		FROM schedule
+		WHERE rid = $1
+	`
+
+	schedule, err := scanSchedule(r.db.Instance.QueryRowContext(ctx, query, rid))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("schedule not found", fmt.Errorf("no schedule with rid %s", rid))
+		}
+		return nil, helper.NewError("select schedule", err)
+	}
+
+	return schedule, nil
+}
+
+// SelectAllSchedules retrieves all schedules ordered by name.
+func (r ScheduleDBHandler) SelectAllSchedules() ([]*model.Schedule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			rid,
+			name,
+			task_key,
+			cron_expression,
+			parameters,
+			enabled,
+			next_run_at,
+			last_run_at,
+			last_job_rid,
+			last_error,
+			created_by,
+			created_at,
+			updated_at
+		FROM schedule
+		ORDER BY name ASC
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query)
+	if err != nil {
+		return nil, helper.NewError("select all schedules", err)
+	}
+	defer rows.Close()
+
+	return scanSchedules(rows)
+}
+
+// SelectDueSchedules retrieves the enabled schedules with a next run at or before now, the longest due first.
+func (r ScheduleDBHandler) SelectDueSchedules(now time.Time) ([]*model.Schedule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			rid,
+			name,
+			task_key,
+			cron_expression,
+			parameters,
+			enabled,
+			next_run_at,
+			last_run_at,
+			last_job_rid,
+			last_error,
+			created_by,
+			created_at,
+			updated_at
+		FROM schedule
+		WHERE enabled
+			AND next_run_at <= $1
+		ORDER BY next_run_at ASC
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, helper.NewError("select due schedules", err)
+	}
+	defer rows.Close()
+
+	return scanSchedules(rows)
+}
+
+func scanSchedule(row interface{ Scan(dest ...any) error }) (*model.Schedule, error) {
+	schedule := &model.Schedule{}
+	err := row.Scan(
+		&schedule.ID,
+		&schedule.RID,
+		&schedule.Name,
+		&schedule.TaskKey,
+		&schedule.CronExpression,
+		&schedule.Parameters,
+		&schedule.Enabled,
+		&schedule.NextRunAt,
+		&schedule.LastRunAt,
+		&schedule.LastJobRID,
+		&schedule.LastError,
+		&schedule.CreatedBy,
+		&schedule.CreatedAt,
+		&schedule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func scanSchedules(rows *sql.Rows) ([]*model.Schedule, error) {
+	schedules := []*model.Schedule{}
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, helper.NewError("scan schedule", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return schedules, nil
+}
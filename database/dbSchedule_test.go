@@ -0,0 +1,166 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleNewScheduleDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewScheduleDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		scheduleDbHandler, err := NewScheduleDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewScheduleDBHandler to not return an error")
+		require.NotNil(t, scheduleDbHandler, "Expected NewScheduleDBHandler to return a non-nil instance")
+
+		exists, err := scheduleDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = scheduleDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewScheduleDBHandler with nil database", func(t *testing.T) {
+		_, err := NewScheduleDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating ScheduleDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestScheduleInsertUpdateAndDeleteSchedule(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	scheduleDbHandler, err := NewScheduleDBHandler(database, true)
+	require.NoError(t, err, "Expected NewScheduleDBHandler to not return an error")
+
+	nextRunAt := time.Now().Add(time.Hour).Truncate(time.Minute)
+	insertedSchedule, err := scheduleDbHandler.InsertSchedule(&model.Schedule{
+		Name:           "nightly",
+		TaskKey:        "test-task",
+		CronExpression: "0 2 * * *",
+		Parameters:     map[string]any{"count": float64(3)},
+		Enabled:        true,
+		NextRunAt:      &nextRunAt,
+		CreatedBy:      "alice",
+	})
+	require.NoError(t, err, "Expected InsertSchedule to not return an error")
+	assert.NotEqual(t, uuid.Nil, insertedSchedule.RID)
+	assert.Equal(t, float64(3), insertedSchedule.Parameters["count"])
+	require.NotNil(t, insertedSchedule.NextRunAt)
+	assert.True(t, nextRunAt.Equal(*insertedSchedule.NextRunAt))
+	assert.Nil(t, insertedSchedule.LastRunAt)
+
+	_, err = scheduleDbHandler.InsertSchedule(&model.Schedule{Name: "nightly", TaskKey: "test-task", CronExpression: "* * * * *"})
+	assert.Error(t, err, "Expected InsertSchedule to return an error for a duplicate name")
+
+	t.Run("Update schedule", func(t *testing.T) {
+		insertedSchedule.CronExpression = "0 3 * * *"
+		insertedSchedule.Parameters = map[string]any{"count": float64(5)}
+		updatedSchedule, err := scheduleDbHandler.UpdateSchedule(insertedSchedule)
+		require.NoError(t, err, "Expected UpdateSchedule to not return an error")
+		assert.Equal(t, "0 3 * * *", updatedSchedule.CronExpression)
+		assert.Equal(t, float64(5), updatedSchedule.Parameters["count"])
+
+		_, err = scheduleDbHandler.UpdateSchedule(&model.Schedule{RID: uuid.New(), Name: "other"})
+		assert.Error(t, err, "Expected UpdateSchedule to return an error for a non-existent schedule")
+	})
+
+	t.Run("Disable schedule", func(t *testing.T) {
+		disabledSchedule, err := scheduleDbHandler.UpdateScheduleEnabled(insertedSchedule.RID, false, nil)
+		require.NoError(t, err, "Expected UpdateScheduleEnabled to not return an error")
+		assert.False(t, disabledSchedule.Enabled)
+		assert.Nil(t, disabledSchedule.NextRunAt)
+
+		enabledSchedule, err := scheduleDbHandler.UpdateScheduleEnabled(insertedSchedule.RID, true, &nextRunAt)
+		require.NoError(t, err, "Expected UpdateScheduleEnabled to not return an error")
+		assert.True(t, enabledSchedule.Enabled)
+	})
+
+	t.Run("Delete schedule", func(t *testing.T) {
+		err := scheduleDbHandler.DeleteSchedule(insertedSchedule.RID)
+		require.NoError(t, err, "Expected DeleteSchedule to not return an error")
+
+		_, err = scheduleDbHandler.SelectSchedule(insertedSchedule.RID)
+		assert.Error(t, err, "Expected SelectSchedule to return an error for a deleted schedule")
+
+		err = scheduleDbHandler.DeleteSchedule(insertedSchedule.RID)
+		assert.Error(t, err, "Expected DeleteSchedule to return an error for a deleted schedule")
+	})
+}
+
+func TestScheduleClaimDueSchedules(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	scheduleDbHandler, err := NewScheduleDBHandler(database, true)
+	require.NoError(t, err, "Expected NewScheduleDBHandler to not return an error")
+
+	now := time.Now()
+	dueAt := now.Add(-time.Minute)
+	laterAt := now.Add(time.Hour)
+	dueSchedule, err := scheduleDbHandler.InsertSchedule(&model.Schedule{Name: "due", TaskKey: "test-task", CronExpression: "* * * * *", Enabled: true, NextRunAt: &dueAt})
+	require.NoError(t, err)
+	_, err = scheduleDbHandler.InsertSchedule(&model.Schedule{Name: "later", TaskKey: "test-task", CronExpression: "0 * * * *", Enabled: true, NextRunAt: &laterAt})
+	require.NoError(t, err)
+	_, err = scheduleDbHandler.InsertSchedule(&model.Schedule{Name: "disabled", TaskKey: "test-task", CronExpression: "* * * * *", Enabled: false})
+	require.NoError(t, err)
+
+	dueSchedules, err := scheduleDbHandler.SelectDueSchedules(now)
+	require.NoError(t, err, "Expected SelectDueSchedules to not return an error")
+	require.Len(t, dueSchedules, 1, "Expected only the enabled schedule with a passed next run to be due")
+	assert.Equal(t, dueSchedule.RID, dueSchedules[0].RID)
+
+	nextRunAt := now.Add(time.Minute)
+	claimed, err := scheduleDbHandler.ClaimScheduleRun(dueSchedules[0], &nextRunAt)
+	require.NoError(t, err, "Expected ClaimScheduleRun to not return an error")
+	assert.True(t, claimed)
+
+	claimed, err = scheduleDbHandler.ClaimScheduleRun(dueSchedules[0], &nextRunAt)
+	require.NoError(t, err, "Expected ClaimScheduleRun to not return an error")
+	assert.False(t, claimed, "Expected a run to only be claimed once")
+
+	jobRID := uuid.New()
+	err = scheduleDbHandler.UpdateScheduleRunResult(dueSchedule.RID, &jobRID, "")
+	require.NoError(t, err, "Expected UpdateScheduleRunResult to not return an error")
+
+	ranSchedule, err := scheduleDbHandler.SelectSchedule(dueSchedule.RID)
+	require.NoError(t, err)
+	require.NotNil(t, ranSchedule.LastRunAt)
+	require.NotNil(t, ranSchedule.LastJobRID)
+	assert.Equal(t, jobRID, *ranSchedule.LastJobRID)
+
+	err = scheduleDbHandler.UpdateScheduleRunResult(dueSchedule.RID, nil, "task not found")
+	require.NoError(t, err, "Expected UpdateScheduleRunResult to not return an error")
+
+	failedSchedule, err := scheduleDbHandler.SelectSchedule(dueSchedule.RID)
+	require.NoError(t, err)
+	assert.Equal(t, jobRID, *failedSchedule.LastJobRID, "Expected a failed run to keep the last job")
+	assert.Equal(t, "task not found", failedSchedule.LastError)
+
+	allSchedules, err := scheduleDbHandler.SelectAllSchedules()
+	require.NoError(t, err)
+	assert.Len(t, allSchedules, 3)
+}
@@ -0,0 +1,779 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+)
+
+// queuerJobFunctionSHA256 is the hash of `update_job_initial` of the queuer (queuerSql f0fc93dd45b9, queuer v1.68.0),
+// see jobFunctionHash. The job claiming functions of the manager are derived from this version of the function,
+// so they only replace it if the installed function has this hash.
+const queuerJobFunctionSHA256 = "d1d7d75c56723deba3a2b296e353e5645bd62850e29b8a36e007f9fbd212e678"
+
+// ErrJobFunctionVersion is returned if the installed job claiming function of the queuer is not the version
+// the job claiming functions of the manager are derived from, eg. after an upgrade of the queuer.
+var ErrJobFunctionVersion = errors.New("unsupported version of the job claiming function update_job_initial of the queuer")
+
+// SchedulerDBHandlerFunctions defines the interface for SchedulerPolicy and JobPriority database operations.
+type SchedulerDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	SelectSchedulerPolicy() (*model.SchedulerPolicy, error)
+	UpdateSchedulerPolicy(policy *model.SchedulerPolicy) (*model.SchedulerPolicy, error)
+	ApplySchedulerPolicy(policy *model.SchedulerPolicy) error
+	RestoreJobFunction() error
+	UpsertJobPriority(jobPriority *model.JobPriority) (*model.JobPriority, error)
+	UpsertJobPriorityTx(tx *sql.Tx, jobPriority *model.JobPriority) (*model.JobPriority, error)
+	SelectJobPriorities(jobRIDs []uuid.UUID) (map[uuid.UUID]int, error)
+	DeleteJobPriority(jobRID uuid.UUID) error
+}
+
+// SchedulerDBHandler implements SchedulerDBHandlerFunctions and holds the database connection.
+type SchedulerDBHandler struct {
+	db *helper.Database
+}
+
+// NewSchedulerDBHandler creates a new instance of SchedulerDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing scheduler_policy table before creating a new one
+func NewSchedulerDBHandler(dbConnection *helper.Database, withTableDrop bool) (*SchedulerDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	schedulerDbHandler := &SchedulerDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := schedulerDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := schedulerDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return schedulerDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'scheduler_policy' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r SchedulerDBHandler) CheckTableExistance() (bool, error) {
+	schedulerPolicyExists, err := r.db.CheckTableExistance("scheduler_policy")
+	if err != nil {
+		return false, helper.NewError("scheduler_policy table", err)
+	}
+	return schedulerPolicyExists, nil
+}
+
+// CreateTable creates the 'scheduler_policy', 'task_turn', 'scheduler_job_function' and 'job_priority' tables in the database.
+// The scheduler_policy table has a single row, it is read by the job claiming function of the round robin mode.
+// The task_turn table has the turn of every task in the round robin mode, it is updated by the job claiming function.
+// The scheduler_job_function table has the job claiming function of the queuer replaced by the manager, to restore it.
+// The job_priority table has the priorities of the queued jobs, it is read by the job claiming functions of all modes.
+// If the tables already exist, it does not create them again.
+func (r SchedulerDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS scheduler_policy (
+			id INTEGER PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+			mode VARCHAR(50) NOT NULL DEFAULT 'fifo',
+			task_weights JSONB NOT NULL DEFAULT '{}'::jsonb,
+			updated_by VARCHAR(255) NOT NULL DEFAULT '',
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE IF NOT EXISTS task_turn (
+			task_name VARCHAR(100) PRIMARY KEY,
+			pass DOUBLE PRECISION NOT NULL DEFAULT 0
+		);
+
+		CREATE TABLE IF NOT EXISTS scheduler_job_function (
+			id INTEGER PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+			definition TEXT NOT NULL,
+			sha256 VARCHAR(64) NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE IF NOT EXISTS job_priority (
+			job_rid UUID PRIMARY KEY,
+			priority INT NOT NULL DEFAULT 0,
+			updated_by VARCHAR(255) NOT NULL DEFAULT '',
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create scheduler_policy table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table scheduler_policy")
+
+	return nil
+}
+
+// DropTable drops the 'scheduler_policy', 'task_turn', 'scheduler_job_function' and 'job_priority' tables from the database.
+// The job claiming function of the queuer is restored first, so the job claiming function does not read the dropped tables.
+func (r SchedulerDBHandler) DropTable() error {
+	jobFunctionExists, err := r.db.CheckTableExistance("scheduler_job_function")
+	if err != nil {
+		return helper.NewError("scheduler_job_function table", err)
+	}
+	if jobFunctionExists {
+		err = r.RestoreJobFunction()
+		if err != nil {
+			return helper.NewError("restore job function", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		DROP INDEX IF EXISTS idx_scheduler_job_task_name;
+		DROP TABLE IF EXISTS job_priority;
+		DROP TABLE IF EXISTS scheduler_job_function;
+		DROP TABLE IF EXISTS task_turn;
+		DROP TABLE IF EXISTS scheduler_policy`
+	_, err = r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop scheduler_policy table", err)
+	}
+
+	r.db.Logger.Info("Dropped table scheduler_policy")
+
+	return nil
+}
+
+// SelectSchedulerPolicy retrieves the scheduler policy.
+// Without a stored policy it returns the fifo mode of the queuer.
+func (r SchedulerDBHandler) SelectSchedulerPolicy() (*model.SchedulerPolicy, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			mode,
+			task_weights,
+			updated_by,
+			updated_at
+		FROM scheduler_policy
+		WHERE id = 1
+	`
+
+	policy, err := scanSchedulerPolicy(r.db.Instance.QueryRowContext(ctx, query))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &model.SchedulerPolicy{Mode: model.SCHEDULER_MODE_FIFO, TaskWeights: map[string]int{}}, nil
+		}
+		return nil, helper.NewError("select scheduler policy", err)
+	}
+
+	return policy, nil
+}
+
+// UpdateSchedulerPolicy stores the scheduler policy, it is applied to the job claiming function with ApplySchedulerPolicy.
+func (r SchedulerDBHandler) UpdateSchedulerPolicy(policy *model.SchedulerPolicy) (*model.SchedulerPolicy, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	taskWeights := policy.TaskWeights
+	if taskWeights == nil {
+		taskWeights = map[string]int{}
+	}
+	taskWeightsJSON, err := json.Marshal(taskWeights)
+	if err != nil {
+		return nil, helper.NewError("marshal task weights", err)
+	}
+
+	query := `
+		INSERT INTO scheduler_policy (
+			id,
+			mode,
+			task_weights,
+			updated_by
+		) VALUES (1, $1, $2, $3)
+		ON CONFLICT (id) DO UPDATE SET
+			mode = EXCLUDED.mode,
+			task_weights = EXCLUDED.task_weights,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = NOW()
+		RETURNING
+			mode,
+			task_weights,
+			updated_by,
+			updated_at`
+
+	updatedPolicy, err := scanSchedulerPolicy(r.db.Instance.QueryRowContext(ctx, query, policy.Mode, taskWeightsJSON, policy.UpdatedBy))
+	if err != nil {
+		return nil, helper.NewError("update scheduler policy", err)
+	}
+
+	return updatedPolicy, nil
+}
+
+// ApplySchedulerPolicy replaces the job claiming function `update_job_initial` of the queuer for the mode of the policy.
+// Both functions claim the jobs with a higher priority in the job_priority table first.
+// The round robin mode installs a function taking turns between the tasks by their turn in the task_turn table,
+// the weights are read from the scheduler_policy table on every claim, so changing them needs no new function.
+// The round robin mode creates an index of the claimable jobs by task, so the first jobs of every task are read from it.
+// The fifo mode installs the function claiming the oldest jobs first like the function of the queuer.
+// The function of the queuer is only replaced if it is the version the functions are derived from, else ErrJobFunctionVersion
+// is returned. The replaced function is stored, so RestoreJobFunction can restore it.
+// The queuer only loads its functions if they are missing, so the manager applies the stored policy again on start.
+func (r SchedulerDBHandler) ApplySchedulerPolicy(policy *model.SchedulerPolicy) error {
+	if policy.Mode != model.SCHEDULER_MODE_ROUND_ROBIN {
+		err := r.replaceJobFunction(fifoUpdateJobInitial)
+		if err != nil {
+			return helper.NewError("replace job function with fifo function", err)
+		}
+
+		r.db.Logger.Info("Applied fifo scheduler policy")
+
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.db.Instance.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS idx_scheduler_job_task_name ON job (task_name, created_at) WHERE status IN ('QUEUED', 'SCHEDULED')`)
+	if err != nil {
+		return helper.NewError("create index of the claimable jobs by task", err)
+	}
+
+	err = r.replaceJobFunction(roundRobinUpdateJobInitial)
+	if err != nil {
+		return helper.NewError("replace job function with round robin function", err)
+	}
+
+	r.db.Logger.Info("Applied round robin scheduler policy")
+
+	return nil
+}
+
+// RestoreJobFunction restores the job claiming function of the queuer stored by ApplySchedulerPolicy.
+// The installed function is only replaced if it is a job claiming function of the manager,
+// so a function (re)loaded by the queuer in the meantime is kept.
+func (r SchedulerDBHandler) RestoreJobFunction() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := r.db.Instance.BeginTx(ctx, nil)
+	if err != nil {
+		return helper.NewError("begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	source, _, err := lockJobFunction(ctx, tx)
+	if err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		return helper.NewError("select job function", err)
+	}
+	if !isManagerJobFunction(jobFunctionHash(source)) {
+		return nil
+	}
+
+	var definition string
+	err = tx.QueryRowContext(ctx, `SELECT definition FROM scheduler_job_function WHERE id = 1`).Scan(&definition)
+	if err != nil {
+		return helper.NewError("select stored job function", err)
+	}
+
+	_, err = tx.ExecContext(ctx, definition)
+	if err != nil {
+		return helper.NewError("restore job function", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return helper.NewError("commit transaction", err)
+	}
+
+	r.db.Logger.Info("Restored job function of the queuer")
+
+	return nil
+}
+
+// replaceJobFunction replaces the installed job claiming function with the function of the statement.
+// The function of the queuer is stored before it is replaced the first time, a job claiming function of the manager
+// is replaced directly. Any other function is not replaced, as the manager function could miss its changes.
+// The replacement is serialized with an advisory lock, so manager instances starting together do not store their own function.
+func (r SchedulerDBHandler) replaceJobFunction(statement string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := r.db.Instance.BeginTx(ctx, nil)
+	if err != nil {
+		return helper.NewError("begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	source, definition, err := lockJobFunction(ctx, tx)
+	if err != nil {
+		return helper.NewError("select job function", err)
+	}
+
+	hash := jobFunctionHash(source)
+	switch {
+	case hash == queuerJobFunctionSHA256:
+		query := `
+			INSERT INTO scheduler_job_function (
+				id,
+				definition,
+				sha256
+			) VALUES (1, $1, $2)
+			ON CONFLICT (id) DO UPDATE SET
+				definition = EXCLUDED.definition,
+				sha256 = EXCLUDED.sha256,
+				updated_at = NOW()`
+		_, err = tx.ExecContext(ctx, query, definition, hash)
+		if err != nil {
+			return helper.NewError("store job function", err)
+		}
+	case isManagerJobFunction(hash):
+	default:
+		return fmt.Errorf("%w (installed sha256 %s, supported sha256 %s)", ErrJobFunctionVersion, hash, queuerJobFunctionSHA256)
+	}
+
+	_, err = tx.ExecContext(ctx, statement)
+	if err != nil {
+		return helper.NewError("create job function", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return helper.NewError("commit transaction", err)
+	}
+
+	return nil
+}
+
+// lockJobFunction takes the advisory lock of the job claiming function for the transaction
+// and returns the source and the full definition of the installed function.
+func lockJobFunction(ctx context.Context, tx *sql.Tx) (string, string, error) {
+	_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('update_job_initial'))`)
+	if err != nil {
+		return "", "", err
+	}
+
+	var source, definition string
+	query := `
+		SELECT
+			pg_proc.prosrc,
+			pg_get_functiondef(pg_proc.oid)
+		FROM pg_proc
+		WHERE pg_proc.proname = 'update_job_initial'
+			AND pg_proc.pronamespace = current_schema()::regnamespace`
+	err = tx.QueryRowContext(ctx, query).Scan(&source, &definition)
+	return source, definition, err
+}
+
+// jobFunctionHash returns the SHA-256 of the source of a job claiming function with collapsed whitespace,
+// so a function loaded with other line endings or indentation has the same hash.
+func jobFunctionHash(source string) string {
+	hash := sha256.Sum256([]byte(strings.Join(strings.Fields(source), " ")))
+	return hex.EncodeToString(hash[:])
+}
+
+// jobFunctionSource returns the source of the function of a CREATE FUNCTION statement between its $$ quotes,
+// which is the source Postgres stores for the function.
+func jobFunctionSource(statement string) string {
+	_, source, _ := strings.Cut(statement, "$$")
+	source, _, _ = strings.Cut(source, "$$")
+	return source
+}
+
+// isManagerJobFunction reports whether the hash is the hash of a job claiming function of the manager.
+func isManagerJobFunction(hash string) bool {
+	return hash == jobFunctionHash(jobFunctionSource(fifoUpdateJobInitial)) ||
+		hash == jobFunctionHash(jobFunctionSource(roundRobinUpdateJobInitial))
+}
+
+// UpsertJobPriority inserts or updates the priority of a job.
+func (r SchedulerDBHandler) UpsertJobPriority(jobPriority *model.JobPriority) (*model.JobPriority, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return upsertJobPriority(ctx, r.db.Instance.QueryRowContext, jobPriority)
+}
+
+// UpsertJobPriorityTx inserts or updates the priority of a job within the transaction,
+// eg. the transaction adding the job, so the job is not claimed before its priority is stored.
+func (r SchedulerDBHandler) UpsertJobPriorityTx(tx *sql.Tx, jobPriority *model.JobPriority) (*model.JobPriority, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return upsertJobPriority(ctx, tx.QueryRowContext, jobPriority)
+}
+
+// upsertJobPriority inserts or updates the priority of a job with the query function of the database or a transaction.
+func upsertJobPriority(ctx context.Context, queryRow func(ctx context.Context, query string, args ...any) *sql.Row, jobPriority *model.JobPriority) (*model.JobPriority, error) {
+
+	query := `
+		INSERT INTO job_priority (
+			job_rid,
+			priority,
+			updated_by
+		) VALUES ($1, $2, $3)
+		ON CONFLICT (job_rid) DO UPDATE SET
+			priority = EXCLUDED.priority,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = NOW()
+		RETURNING
+			job_rid,
+			priority,
+			updated_by,
+			updated_at`
+
+	upsertedJobPriority := &model.JobPriority{}
+	err := queryRow(ctx, query, jobPriority.JobRID, jobPriority.Priority, jobPriority.UpdatedBy).Scan(
+		&upsertedJobPriority.JobRID,
+		&upsertedJobPriority.Priority,
+		&upsertedJobPriority.UpdatedBy,
+		&upsertedJobPriority.UpdatedAt,
+	)
+	if err != nil {
+		return nil, helper.NewError("upsert job priority", err)
+	}
+
+	return upsertedJobPriority, nil
+}
+
+// SelectJobPriorities retrieves the priorities of the jobs by job RID, jobs without priority are left out.
+func (r SchedulerDBHandler) SelectJobPriorities(jobRIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	priorities := map[uuid.UUID]int{}
+	if len(jobRIDs) == 0 {
+		return priorities, nil
+	}
+
+	jobRIDsJSON, err := json.Marshal(jobRIDs)
+	if err != nil {
+		return nil, helper.NewError("marshal job rids", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			job_rid,
+			priority
+		FROM job_priority
+		WHERE job_rid IN (SELECT jsonb_array_elements_text($1::jsonb)::uuid)`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, jobRIDsJSON)
+	if err != nil {
+		return nil, helper.NewError("select job priorities", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var jobRID uuid.UUID
+		var priority int
+		err := rows.Scan(&jobRID, &priority)
+		if err != nil {
+			return nil, helper.NewError("scan job priority", err)
+		}
+		priorities[jobRID] = priority
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, helper.NewError("rows error", err)
+	}
+
+	return priorities, nil
+}
+
+// DeleteJobPriority deletes the priority of a job, eg. when the job ended.
+func (r SchedulerDBHandler) DeleteJobPriority(jobRID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DELETE FROM job_priority WHERE job_rid = $1`
+	_, err := r.db.Instance.ExecContext(ctx, query, jobRID)
+	if err != nil {
+		return helper.NewError("delete job priority", err)
+	}
+
+	return nil
+}
+
+func scanSchedulerPolicy(row interface{ Scan(dest ...any) error }) (*model.SchedulerPolicy, error) {
+	policy := &model.SchedulerPolicy{}
+	var taskWeightsData []byte
+	err := row.Scan(
+		&policy.Mode,
+		&taskWeightsData,
+		&policy.UpdatedBy,
+		&policy.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(taskWeightsData, &policy.TaskWeights)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal task_weights: %w", err)
+	}
+
+	return policy, nil
+}
+
+// fifoUpdateJobInitial is `update_job_initial` of the queuer with the claimable jobs ordered by their priority and age.
+const fifoUpdateJobInitial = `
+CREATE OR REPLACE FUNCTION update_job_initial(input_worker_id BIGINT)
+RETURNS TABLE (
+    output_id BIGINT,
+    output_rid UUID,
+    output_worker_id BIGINT,
+    output_worker_rid UUID,
+    output_options JSONB,
+    output_task_name VARCHAR(100),
+    output_parameters JSONB,
+    output_parameters_keyed JSONB,
+    output_status VARCHAR(50),
+    output_scheduled_at TIMESTAMP,
+    output_started_at TIMESTAMP,
+    output_schedule_count INT,
+    output_attempts INT,
+    output_created_at TIMESTAMP,
+    output_updated_at TIMESTAMP
+) AS $$
+BEGIN
+    RETURN QUERY
+    WITH current_concurrency AS (
+        SELECT COUNT(*) AS count
+        FROM job
+        WHERE job.worker_id = input_worker_id
+        AND job.status = 'RUNNING'
+    ),
+    current_worker AS (
+        SELECT
+            worker.id,
+            worker.rid,
+            worker.available_tasks,
+            worker.available_next_interval,
+            worker.max_concurrency,
+            COALESCE(cc.count, 0) AS current_concurrency
+        FROM worker, current_concurrency AS cc
+        WHERE worker.id = input_worker_id
+        AND (worker.max_concurrency > COALESCE(cc.count, 0))
+        FOR UPDATE
+    ),
+    job_ids AS (
+        SELECT j.id
+        FROM current_worker AS cw, current_concurrency AS cc,
+        LATERAL (
+            SELECT job.id
+            FROM job
+            LEFT JOIN job_priority AS jp ON jp.job_rid = job.rid
+            WHERE
+                job.task_name = ANY(cw.available_tasks::VARCHAR[])
+                AND (
+                    job.options->'schedule'->>'next_interval' IS NULL
+                    OR job.options->'schedule'->>'next_interval' = ''
+                    OR job.options->'schedule'->>'next_interval' = ANY(cw.available_next_interval::VARCHAR[])
+                )
+                AND (
+                    job.status = 'QUEUED'
+                    OR (job.status = 'SCHEDULED' AND job.scheduled_at <= (CURRENT_TIMESTAMP + INTERVAL '10 minutes'))
+                )
+            ORDER BY COALESCE(jp.priority, 0) DESC, job.created_at ASC
+            LIMIT (cw.max_concurrency - COALESCE(cc.count, 0))
+            FOR UPDATE OF job SKIP LOCKED
+        ) AS j
+    )
+    UPDATE job SET
+        worker_id = cw.id,
+        worker_rid = cw.rid,
+        status = 'RUNNING',
+        started_at = CURRENT_TIMESTAMP,
+        schedule_count = job.schedule_count + 1,
+        attempts = job.attempts + 1,
+        updated_at = CURRENT_TIMESTAMP
+    FROM current_worker AS cw, job_ids
+    WHERE job.id = ANY(SELECT job_ids.id FROM job_ids)
+    AND EXISTS (SELECT 1 FROM current_worker)
+    RETURNING
+        job.id,
+        job.rid,
+        job.worker_id,
+        job.worker_rid,
+        job.options,
+        job.task_name,
+        job.parameters,
+        job.parameters_keyed,
+        job.status,
+        job.scheduled_at,
+        job.started_at,
+        job.schedule_count,
+        job.attempts,
+        job.created_at,
+        job.updated_at;
+END;
+$$ LANGUAGE plpgsql;
+`
+
+// roundRobinUpdateJobInitial is `update_job_initial` of the queuer with the claimable jobs ordered by their priority and turn.
+// Every task has a pass in the task_turn table, the turn of a job is the pass of its task plus its position in the queue
+// of its task divided by the weight of the task. The pass of a task is moved to the turn of its last claimed job,
+// the pass of a task without claims is raised to one below the highest pass, so an idle task does not claim a backlog of turns.
+// Only the first jobs of every task up to the free concurrency of the worker are ranked and locked, not the whole queue.
+const roundRobinUpdateJobInitial = `
+CREATE OR REPLACE FUNCTION update_job_initial(input_worker_id BIGINT)
+RETURNS TABLE (
+    output_id BIGINT,
+    output_rid UUID,
+    output_worker_id BIGINT,
+    output_worker_rid UUID,
+    output_options JSONB,
+    output_task_name VARCHAR(100),
+    output_parameters JSONB,
+    output_parameters_keyed JSONB,
+    output_status VARCHAR(50),
+    output_scheduled_at TIMESTAMP,
+    output_started_at TIMESTAMP,
+    output_schedule_count INT,
+    output_attempts INT,
+    output_created_at TIMESTAMP,
+    output_updated_at TIMESTAMP
+) AS $$
+BEGIN
+    RETURN QUERY
+    WITH current_concurrency AS (
+        SELECT COUNT(*) AS count
+        FROM job
+        WHERE job.worker_id = input_worker_id
+        AND job.status = 'RUNNING'
+    ),
+    current_worker AS (
+        SELECT
+            worker.id,
+            worker.rid,
+            worker.available_tasks,
+            worker.available_next_interval,
+            worker.max_concurrency,
+            COALESCE(cc.count, 0) AS current_concurrency
+        FROM worker, current_concurrency AS cc
+        WHERE worker.id = input_worker_id
+        AND (worker.max_concurrency > COALESCE(cc.count, 0))
+        FOR UPDATE
+    ),
+    task_passes AS (
+        SELECT
+            available.task_name,
+            GREATEST(
+                COALESCE(tt.pass, 0),
+                COALESCE((
+                    SELECT MAX(other.pass)
+                    FROM task_turn AS other
+                    WHERE other.task_name = ANY(cw.available_tasks::VARCHAR[])
+                ), 0) - 1
+            ) AS pass,
+            GREATEST(COALESCE((sp.task_weights->>available.task_name)::INT, 1), 1) AS weight
+        FROM current_worker AS cw
+        CROSS JOIN LATERAL unnest(cw.available_tasks::VARCHAR[]) AS available(task_name)
+        LEFT JOIN task_turn AS tt ON tt.task_name = available.task_name
+        LEFT JOIN scheduler_policy AS sp ON sp.id = 1
+    ),
+    candidates AS (
+        SELECT
+            j.id,
+            tp.task_name,
+            j.priority,
+            j.created_at,
+            tp.pass + ROW_NUMBER() OVER (PARTITION BY tp.task_name ORDER BY j.priority DESC, j.created_at ASC)::DOUBLE PRECISION
+                / tp.weight AS turn
+        FROM current_worker AS cw, current_concurrency AS cc, task_passes AS tp,
+        LATERAL (
+            SELECT
+                job.id,
+                COALESCE(jp.priority, 0) AS priority,
+                job.created_at
+            FROM job
+            LEFT JOIN job_priority AS jp ON jp.job_rid = job.rid
+            WHERE
+                job.task_name = tp.task_name
+                AND (
+                    job.options->'schedule'->>'next_interval' IS NULL
+                    OR job.options->'schedule'->>'next_interval' = ''
+                    OR job.options->'schedule'->>'next_interval' = ANY(cw.available_next_interval::VARCHAR[])
+                )
+                AND (
+                    job.status = 'QUEUED'
+                    OR (job.status = 'SCHEDULED' AND job.scheduled_at <= (CURRENT_TIMESTAMP + INTERVAL '10 minutes'))
+                )
+            ORDER BY COALESCE(jp.priority, 0) DESC, job.created_at ASC
+            LIMIT (cw.max_concurrency - COALESCE(cc.count, 0))
+            FOR UPDATE OF job SKIP LOCKED
+        ) AS j
+    ),
+    job_ids AS (
+        SELECT
+            candidates.id,
+            candidates.task_name,
+            candidates.turn
+        FROM candidates, current_worker AS cw, current_concurrency AS cc
+        ORDER BY candidates.priority DESC, candidates.turn ASC, candidates.created_at ASC
+        LIMIT (cw.max_concurrency - COALESCE(cc.count, 0))
+    ),
+    turns AS (
+        INSERT INTO task_turn (task_name, pass)
+        SELECT job_ids.task_name, MAX(job_ids.turn)
+        FROM job_ids
+        GROUP BY job_ids.task_name
+        ON CONFLICT (task_name) DO UPDATE SET
+            pass = GREATEST(task_turn.pass, EXCLUDED.pass)
+    )
+    UPDATE job SET
+        worker_id = cw.id,
+        worker_rid = cw.rid,
+        status = 'RUNNING',
+        started_at = CURRENT_TIMESTAMP,
+        schedule_count = job.schedule_count + 1,
+        attempts = job.attempts + 1,
+        updated_at = CURRENT_TIMESTAMP
+    FROM current_worker AS cw, job_ids
+    WHERE job.id = ANY(SELECT job_ids.id FROM job_ids)
+    AND EXISTS (SELECT 1 FROM current_worker)
+    RETURNING
+        job.id,
+        job.rid,
+        job.worker_id,
+        job.worker_rid,
+        job.options,
+        job.task_name,
+        job.parameters,
+        job.parameters_keyed,
+        job.status,
+        job.scheduled_at,
+        job.started_at,
+        job.schedule_count,
+        job.attempts,
+        job.created_at,
+        job.updated_at;
+END;
+$$ LANGUAGE plpgsql;
+`
@@ -0,0 +1,184 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	loadSql "github.com/siherrmann/queuerSql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerNewSchedulerDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewSchedulerDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		schedulerDbHandler, err := NewSchedulerDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewSchedulerDBHandler to not return an error")
+		require.NotNil(t, schedulerDbHandler, "Expected NewSchedulerDBHandler to return a non-nil instance")
+
+		exists, err := schedulerDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = schedulerDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewSchedulerDBHandler with nil database", func(t *testing.T) {
+		_, err := NewSchedulerDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating SchedulerDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestSchedulerUpdateSchedulerPolicy(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+	createQueuerTables(t, database)
+	err = loadSql.LoadJobSql(database.Instance, true)
+	require.NoError(t, err, "Expected the job functions of the queuer to load")
+
+	schedulerDbHandler, err := NewSchedulerDBHandler(database, true)
+	require.NoError(t, err, "Expected NewSchedulerDBHandler to not return an error")
+
+	jobFunctionSource := func() string {
+		var source string
+		err := database.Instance.QueryRow(`SELECT prosrc FROM pg_proc WHERE proname = 'update_job_initial'`).Scan(&source)
+		require.NoError(t, err, "Expected update_job_initial to exist")
+		return source
+	}
+
+	t.Run("Select default policy", func(t *testing.T) {
+		policy, err := schedulerDbHandler.SelectSchedulerPolicy()
+		require.NoError(t, err, "Expected SelectSchedulerPolicy to not return an error")
+		assert.Equal(t, model.SCHEDULER_MODE_FIFO, policy.Mode)
+		assert.Empty(t, policy.TaskWeights)
+	})
+
+	t.Run("Update to round robin", func(t *testing.T) {
+		policy, err := schedulerDbHandler.UpdateSchedulerPolicy(&model.SchedulerPolicy{
+			Mode:        model.SCHEDULER_MODE_ROUND_ROBIN,
+			TaskWeights: map[string]int{"import": 3},
+			UpdatedBy:   "admin",
+		})
+		require.NoError(t, err, "Expected UpdateSchedulerPolicy to not return an error")
+		assert.Equal(t, model.SCHEDULER_MODE_ROUND_ROBIN, policy.Mode)
+		assert.Equal(t, 3, policy.TaskWeight("import"))
+		assert.Equal(t, "admin", policy.UpdatedBy)
+		assert.NotContains(t, jobFunctionSource(), "scheduler_policy", "Expected the policy to not be applied on update")
+
+		err = schedulerDbHandler.ApplySchedulerPolicy(policy)
+		require.NoError(t, err, "Expected ApplySchedulerPolicy to not return an error")
+		assert.Contains(t, jobFunctionSource(), "task_turn", "Expected the round robin job function to be installed")
+
+		selectedPolicy, err := schedulerDbHandler.SelectSchedulerPolicy()
+		require.NoError(t, err, "Expected SelectSchedulerPolicy to not return an error")
+		assert.Equal(t, policy.TaskWeights, selectedPolicy.TaskWeights)
+	})
+
+	t.Run("Update back to fifo", func(t *testing.T) {
+		policy, err := schedulerDbHandler.UpdateSchedulerPolicy(&model.SchedulerPolicy{Mode: model.SCHEDULER_MODE_FIFO, UpdatedBy: "admin"})
+		require.NoError(t, err, "Expected UpdateSchedulerPolicy to not return an error")
+		assert.Equal(t, model.SCHEDULER_MODE_FIFO, policy.Mode)
+
+		err = schedulerDbHandler.ApplySchedulerPolicy(policy)
+		require.NoError(t, err, "Expected ApplySchedulerPolicy to not return an error")
+		assert.NotContains(t, jobFunctionSource(), "scheduler_policy", "Expected the round robin job function to be replaced")
+		assert.Contains(t, jobFunctionSource(), "job_priority", "Expected the fifo job function to claim jobs by priority")
+	})
+
+	t.Run("Restore job function of the queuer", func(t *testing.T) {
+		err := schedulerDbHandler.RestoreJobFunction()
+		require.NoError(t, err, "Expected RestoreJobFunction to not return an error")
+		assert.Equal(t, queuerJobFunctionSHA256, jobFunctionHash(jobFunctionSource()), "Expected the job function of the queuer to be restored")
+
+		err = schedulerDbHandler.RestoreJobFunction()
+		assert.NoError(t, err, "Expected restoring the function of the queuer again to not return an error")
+	})
+
+	t.Run("Do not replace an unsupported job function", func(t *testing.T) {
+		_, err := database.Instance.Exec(`CREATE OR REPLACE FUNCTION update_job_initial(input_worker_id BIGINT) RETURNS VOID AS $$ BEGIN END; $$ LANGUAGE plpgsql`)
+		require.NoError(t, err)
+		defer func() {
+			_, err := database.Instance.Exec(`DROP FUNCTION IF EXISTS update_job_initial(BIGINT)`)
+			require.NoError(t, err)
+			err = loadSql.LoadJobSql(database.Instance, true)
+			require.NoError(t, err)
+		}()
+
+		err = schedulerDbHandler.ApplySchedulerPolicy(&model.SchedulerPolicy{Mode: model.SCHEDULER_MODE_ROUND_ROBIN})
+		assert.ErrorIs(t, err, ErrJobFunctionVersion, "Expected an unsupported job function to not be replaced")
+		assert.NotContains(t, jobFunctionSource(), "task_turn")
+	})
+}
+
+func TestSchedulerJobFunctionHash(t *testing.T) {
+	assert.Equal(t, jobFunctionHash("BEGIN\n    RETURN;\nEND;"), jobFunctionHash(" BEGIN RETURN;\r\n END; "), "Expected the hash to ignore whitespace")
+	assert.True(t, isManagerJobFunction(jobFunctionHash(jobFunctionSource(fifoUpdateJobInitial))))
+	assert.True(t, isManagerJobFunction(jobFunctionHash(jobFunctionSource(roundRobinUpdateJobInitial))))
+	assert.False(t, isManagerJobFunction(queuerJobFunctionSHA256))
+}
+
+func TestSchedulerJobPriority(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	schedulerDbHandler, err := NewSchedulerDBHandler(database, true)
+	require.NoError(t, err, "Expected NewSchedulerDBHandler to not return an error")
+
+	jobRID := uuid.New()
+	otherJobRID := uuid.New()
+
+	t.Run("Upsert job priority", func(t *testing.T) {
+		jobPriority, err := schedulerDbHandler.UpsertJobPriority(&model.JobPriority{JobRID: jobRID, Priority: 10, UpdatedBy: "admin"})
+		require.NoError(t, err, "Expected UpsertJobPriority to not return an error")
+		assert.Equal(t, jobRID, jobPriority.JobRID)
+		assert.Equal(t, 10, jobPriority.Priority)
+		assert.Equal(t, "admin", jobPriority.UpdatedBy)
+
+		jobPriority, err = schedulerDbHandler.UpsertJobPriority(&model.JobPriority{JobRID: jobRID, Priority: -5, UpdatedBy: "operator"})
+		require.NoError(t, err, "Expected UpsertJobPriority to update the priority")
+		assert.Equal(t, -5, jobPriority.Priority)
+		assert.Equal(t, "operator", jobPriority.UpdatedBy)
+	})
+
+	t.Run("Select job priorities", func(t *testing.T) {
+		priorities, err := schedulerDbHandler.SelectJobPriorities([]uuid.UUID{jobRID, otherJobRID})
+		require.NoError(t, err, "Expected SelectJobPriorities to not return an error")
+		assert.Equal(t, map[uuid.UUID]int{jobRID: -5}, priorities, "Expected jobs without priority to be left out")
+
+		priorities, err = schedulerDbHandler.SelectJobPriorities(nil)
+		require.NoError(t, err)
+		assert.Empty(t, priorities)
+	})
+
+	t.Run("Delete job priority", func(t *testing.T) {
+		err := schedulerDbHandler.DeleteJobPriority(jobRID)
+		require.NoError(t, err, "Expected DeleteJobPriority to not return an error")
+
+		priorities, err := schedulerDbHandler.SelectJobPriorities([]uuid.UUID{jobRID})
+		require.NoError(t, err)
+		assert.Empty(t, priorities)
+
+		err = schedulerDbHandler.DeleteJobPriority(otherJobRID)
+		assert.NoError(t, err, "Expected deleting a missing priority to not return an error")
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/siherrmann/queuerManager/model"
@@ -22,11 +23,20 @@ type TaskDBHandlerFunctions interface {
 	DropTable() error
 	InsertTask(task *model.Task) (*model.Task, error)
 	UpdateTask(task *model.Task) (*model.Task, error)
+	UpsertTask(task *model.Task) (*model.Task, bool, error)
 	DeleteTask(rid uuid.UUID) error
+	DeleteTaskByKey(key string) (bool, error)
 	SelectTask(rid uuid.UUID) (*model.Task, error)
 	SelectTaskByKey(key string) (*model.Task, error)
 	SelectAllTasks(lastID int, entries int) ([]*model.Task, error)
-	SelectAllTasksBySearch(search string, lastID int, entries int) ([]*model.Task, error)
+	SelectAllTasksBySearch(search string, namespace string, lastID int, entries int) ([]*model.Task, error)
+	SelectAllTasksByParameter(parameterKey string, lastID int, entries int) ([]*model.Task, error)
+	SelectAllTasksByFilter(filter *model.TaskFilter, lastID int, entries int) ([]*model.Task, error)
+	SelectUpstreamTasks(taskKey string) ([]*model.Task, error)
+	SelectAllTasksCount() (int, error)
+	SelectAllTasksByParameterCount(parameterKey string) (int, error)
+	SelectAllTasksByFilterCount(filter *model.TaskFilter) (int, error)
+	SelectNamespaces() ([]string, error)
 }
 
 // TaskDBHandler implements TaskDBHandlerFunctions and holds the database connection.
@@ -87,12 +97,42 @@ func (r TaskDBHandler) CreateTable() error {
 			input_parameters JSONB NOT NULL DEFAULT '[]'::jsonb,
 			input_parameters_keyed JSONB NOT NULL DEFAULT '[]'::jsonb,
 			output_parameters JSONB NOT NULL DEFAULT '[]'::jsonb,
+			min_worker_version VARCHAR(50) NOT NULL DEFAULT '',
+			worker_version_policy VARCHAR(10) NOT NULL DEFAULT 'warn',
+			owner VARCHAR(100) NOT NULL DEFAULT '',
+			namespace VARCHAR(100) NOT NULL DEFAULT '',
+			dedup_window_minutes INTEGER NOT NULL DEFAULT 0,
+			parameter_docs JSONB NOT NULL DEFAULT '{}'::jsonb,
+			on_success JSONB NOT NULL DEFAULT '[]'::jsonb,
+			alerts JSONB NOT NULL DEFAULT '[]'::jsonb,
+			max_concurrency INTEGER NOT NULL DEFAULT 0,
+			rate_limit_per_minute INTEGER NOT NULL DEFAULT 0,
 			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
 			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
 		);
 
+		ALTER TABLE task ADD COLUMN IF NOT EXISTS min_worker_version VARCHAR(50) NOT NULL DEFAULT '';
+		ALTER TABLE task ADD COLUMN IF NOT EXISTS worker_version_policy VARCHAR(10) NOT NULL DEFAULT 'warn';
+		ALTER TABLE task ADD COLUMN IF NOT EXISTS owner VARCHAR(100) NOT NULL DEFAULT '';
+		ALTER TABLE task ADD COLUMN IF NOT EXISTS dedup_window_minutes INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE task ADD COLUMN IF NOT EXISTS parameter_docs JSONB NOT NULL DEFAULT '{}'::jsonb;
+		ALTER TABLE task ADD COLUMN IF NOT EXISTS on_success JSONB NOT NULL DEFAULT '[]'::jsonb;
+		ALTER TABLE task ADD COLUMN IF NOT EXISTS alerts JSONB NOT NULL DEFAULT '[]'::jsonb;
+		ALTER TABLE task ADD COLUMN IF NOT EXISTS namespace VARCHAR(100) NOT NULL DEFAULT '';
+		ALTER TABLE task ADD COLUMN IF NOT EXISTS max_concurrency INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE task ADD COLUMN IF NOT EXISTS rate_limit_per_minute INTEGER NOT NULL DEFAULT 0;
+
 		CREATE INDEX IF NOT EXISTS idx_task_rid ON task(rid);
 		CREATE INDEX IF NOT EXISTS idx_task_name ON task(name);
+		CREATE INDEX IF NOT EXISTS idx_task_owner ON task(owner);
+		CREATE INDEX IF NOT EXISTS idx_task_namespace ON task(namespace);
+		CREATE INDEX IF NOT EXISTS idx_task_key_pattern ON task(key varchar_pattern_ops);
+		CREATE INDEX IF NOT EXISTS idx_task_created_at ON task(created_at, id);
+		CREATE INDEX IF NOT EXISTS idx_task_updated_at ON task(updated_at, id);
+		CREATE INDEX IF NOT EXISTS idx_task_input_parameters ON task USING GIN (input_parameters jsonb_path_ops);
+		CREATE INDEX IF NOT EXISTS idx_task_input_parameters_keyed ON task USING GIN (input_parameters_keyed jsonb_path_ops);
+		CREATE INDEX IF NOT EXISTS idx_task_output_parameters ON task USING GIN (output_parameters jsonb_path_ops);
+		CREATE INDEX IF NOT EXISTS idx_task_on_success ON task USING GIN (on_success jsonb_path_ops);
 	`
 
 	_, err := r.db.Instance.ExecContext(ctx, query)
@@ -141,6 +181,21 @@ func (r TaskDBHandler) InsertTask(task *model.Task) (*model.Task, error) {
 		return nil, helper.NewError("marshal output_parameters", err)
 	}
 
+	parameterDocsJSON, err := marshalParameterDocs(task.ParameterDocs)
+	if err != nil {
+		return nil, helper.NewError("marshal parameter_docs", err)
+	}
+
+	onSuccessJSON, err := marshalTaskDependencies(task.OnSuccess)
+	if err != nil {
+		return nil, helper.NewError("marshal on_success", err)
+	}
+
+	alertsJSON, err := marshalTaskAlerts(task.Alerts)
+	if err != nil {
+		return nil, helper.NewError("marshal alerts", err)
+	}
+
 	newTask := &model.Task{}
 	query := `
 		INSERT INTO task (
@@ -149,8 +204,18 @@ func (r TaskDBHandler) InsertTask(task *model.Task) (*model.Task, error) {
 			description,
 			input_parameters,
 			input_parameters_keyed,
-			output_parameters
-		) VALUES ($1, $2, $3, $4, $5, $6)
+			output_parameters,
+			min_worker_version,
+			worker_version_policy,
+			owner,
+			namespace,
+			dedup_window_minutes,
+			parameter_docs,
+			on_success,
+			alerts,
+			max_concurrency,
+			rate_limit_per_minute
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		RETURNING
 			id,
 			rid,
@@ -160,13 +225,26 @@ func (r TaskDBHandler) InsertTask(task *model.Task) (*model.Task, error) {
 			input_parameters,
 			input_parameters_keyed,
 			output_parameters,
+			min_worker_version,
+			worker_version_policy,
+			owner,
+			namespace,
+			dedup_window_minutes,
+			parameter_docs,
+			on_success,
+			alerts,
+			max_concurrency,
+			rate_limit_per_minute,
 			created_at,
 			updated_at`
 
 	var input_parametersData []byte
 	var input_parametersKeyedData []byte
 	var outputParametersData []byte
-	err = r.db.Instance.QueryRowContext(ctx, query, task.Key, task.Name, task.Description, input_parametersJSON, input_parametersKeyedJSON, outputParametersJSON).Scan(
+	var parameterDocsData []byte
+	var onSuccessData []byte
+	var alertsData []byte
+	err = r.db.Instance.QueryRowContext(ctx, query, task.Key, task.Name, task.Description, input_parametersJSON, input_parametersKeyedJSON, outputParametersJSON, task.MinWorkerVersion, task.WorkerVersionPolicy, task.Owner, task.Namespace, task.DedupWindowMinutes, parameterDocsJSON, onSuccessJSON, alertsJSON, task.MaxConcurrency, task.RateLimitPerMinute).Scan(
 		&newTask.ID,
 		&newTask.RID,
 		&newTask.Key,
@@ -175,6 +253,16 @@ func (r TaskDBHandler) InsertTask(task *model.Task) (*model.Task, error) {
 		&input_parametersData,
 		&input_parametersKeyedData,
 		&outputParametersData,
+		&newTask.MinWorkerVersion,
+		&newTask.WorkerVersionPolicy,
+		&newTask.Owner,
+		&newTask.Namespace,
+		&newTask.DedupWindowMinutes,
+		&parameterDocsData,
+		&onSuccessData,
+		&alertsData,
+		&newTask.MaxConcurrency,
+		&newTask.RateLimitPerMinute,
 		&newTask.CreatedAt,
 		&newTask.UpdatedAt,
 	)
@@ -197,6 +285,21 @@ func (r TaskDBHandler) InsertTask(task *model.Task) (*model.Task, error) {
 		return nil, helper.NewError("unmarshal output_parameters", err)
 	}
 
+	err = json.Unmarshal(parameterDocsData, &newTask.ParameterDocs)
+	if err != nil {
+		return nil, helper.NewError("unmarshal parameter_docs", err)
+	}
+
+	err = json.Unmarshal(onSuccessData, &newTask.OnSuccess)
+	if err != nil {
+		return nil, helper.NewError("unmarshal on_success", err)
+	}
+
+	err = json.Unmarshal(alertsData, &newTask.Alerts)
+	if err != nil {
+		return nil, helper.NewError("unmarshal alerts", err)
+	}
+
 	return newTask, nil
 }
 
@@ -220,6 +323,21 @@ func (r TaskDBHandler) UpdateTask(task *model.Task) (*model.Task, error) {
 		return nil, helper.NewError("marshal output_parameters", err)
 	}
 
+	parameterDocsJSON, err := marshalParameterDocs(task.ParameterDocs)
+	if err != nil {
+		return nil, helper.NewError("marshal parameter_docs", err)
+	}
+
+	onSuccessJSON, err := marshalTaskDependencies(task.OnSuccess)
+	if err != nil {
+		return nil, helper.NewError("marshal on_success", err)
+	}
+
+	alertsJSON, err := marshalTaskAlerts(task.Alerts)
+	if err != nil {
+		return nil, helper.NewError("marshal alerts", err)
+	}
+
 	updatedTask := &model.Task{}
 	query := `
 		UPDATE task
@@ -230,8 +348,18 @@ func (r TaskDBHandler) UpdateTask(task *model.Task) (*model.Task, error) {
 			input_parameters = $4,
 			input_parameters_keyed = $5,
 			output_parameters = $6,
+			min_worker_version = $7,
+			worker_version_policy = $8,
+			owner = $9,
+			namespace = $10,
+			dedup_window_minutes = $11,
+			parameter_docs = $12,
+			on_success = $13,
+			alerts = $14,
+			max_concurrency = $16,
+			rate_limit_per_minute = $17,
 			updated_at = NOW()
-		WHERE rid = $7
+		WHERE rid = $15
 		RETURNING
 			id,
 			rid,
@@ -241,13 +369,26 @@ func (r TaskDBHandler) UpdateTask(task *model.Task) (*model.Task, error) {
 			input_parameters,
 			input_parameters_keyed,
 			output_parameters,
+			min_worker_version,
+			worker_version_policy,
+			owner,
+			namespace,
+			dedup_window_minutes,
+			parameter_docs,
+			on_success,
+			alerts,
+			max_concurrency,
+			rate_limit_per_minute,
 			created_at,
 			updated_at`
 
 	var input_parametersData []byte
 	var input_parametersKeyedData []byte
 	var outputParametersData []byte
-	err = r.db.Instance.QueryRowContext(ctx, query, task.Key, task.Name, task.Description, input_parametersJSON, input_parametersKeyedJSON, outputParametersJSON, task.RID).Scan(
+	var parameterDocsData []byte
+	var onSuccessData []byte
+	var alertsData []byte
+	err = r.db.Instance.QueryRowContext(ctx, query, task.Key, task.Name, task.Description, input_parametersJSON, input_parametersKeyedJSON, outputParametersJSON, task.MinWorkerVersion, task.WorkerVersionPolicy, task.Owner, task.Namespace, task.DedupWindowMinutes, parameterDocsJSON, onSuccessJSON, alertsJSON, task.RID, task.MaxConcurrency, task.RateLimitPerMinute).Scan(
 		&updatedTask.ID,
 		&updatedTask.RID,
 		&updatedTask.Key,
@@ -256,6 +397,16 @@ func (r TaskDBHandler) UpdateTask(task *model.Task) (*model.Task, error) {
 		&input_parametersData,
 		&input_parametersKeyedData,
 		&outputParametersData,
+		&updatedTask.MinWorkerVersion,
+		&updatedTask.WorkerVersionPolicy,
+		&updatedTask.Owner,
+		&updatedTask.Namespace,
+		&updatedTask.DedupWindowMinutes,
+		&parameterDocsData,
+		&onSuccessData,
+		&alertsData,
+		&updatedTask.MaxConcurrency,
+		&updatedTask.RateLimitPerMinute,
 		&updatedTask.CreatedAt,
 		&updatedTask.UpdatedAt,
 	)
@@ -281,9 +432,195 @@ func (r TaskDBHandler) UpdateTask(task *model.Task) (*model.Task, error) {
 		return nil, helper.NewError("unmarshal output_parameters", err)
 	}
 
+	err = json.Unmarshal(parameterDocsData, &updatedTask.ParameterDocs)
+	if err != nil {
+		return nil, helper.NewError("unmarshal parameter_docs", err)
+	}
+
+	err = json.Unmarshal(onSuccessData, &updatedTask.OnSuccess)
+	if err != nil {
+		return nil, helper.NewError("unmarshal on_success", err)
+	}
+
+	err = json.Unmarshal(alertsData, &updatedTask.Alerts)
+	if err != nil {
+		return nil, helper.NewError("unmarshal alerts", err)
+	}
+
 	return updatedTask, nil
 }
 
+// UpsertTask inserts a task or updates the task with the same key.
+// The RID of an existing task is kept and updated_at is only changed if a value changed.
+// It returns true if the task was created.
+func (r TaskDBHandler) UpsertTask(task *model.Task) (*model.Task, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	input_parametersJSON, err := json.Marshal(task.InputParameters)
+	if err != nil {
+		return nil, false, helper.NewError("marshal input_parameters", err)
+	}
+
+	input_parametersKeyedJSON, err := json.Marshal(task.InputParametersKeyed)
+	if err != nil {
+		return nil, false, helper.NewError("marshal input_parameters_keyed", err)
+	}
+
+	outputParametersJSON, err := json.Marshal(task.OutputParameters)
+	if err != nil {
+		return nil, false, helper.NewError("marshal output_parameters", err)
+	}
+
+	parameterDocsJSON, err := marshalParameterDocs(task.ParameterDocs)
+	if err != nil {
+		return nil, false, helper.NewError("marshal parameter_docs", err)
+	}
+
+	onSuccessJSON, err := marshalTaskDependencies(task.OnSuccess)
+	if err != nil {
+		return nil, false, helper.NewError("marshal on_success", err)
+	}
+
+	alertsJSON, err := marshalTaskAlerts(task.Alerts)
+	if err != nil {
+		return nil, false, helper.NewError("marshal alerts", err)
+	}
+
+	upsertedTask := &model.Task{}
+	query := `
+		INSERT INTO task (
+			key,
+			name,
+			description,
+			input_parameters,
+			input_parameters_keyed,
+			output_parameters,
+			min_worker_version,
+			worker_version_policy,
+			owner,
+			namespace,
+			dedup_window_minutes,
+			parameter_docs,
+			on_success,
+			alerts,
+			max_concurrency,
+			rate_limit_per_minute
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		ON CONFLICT (key) DO UPDATE
+		SET
+			name = EXCLUDED.name,
+			description = EXCLUDED.description,
+			input_parameters = EXCLUDED.input_parameters,
+			input_parameters_keyed = EXCLUDED.input_parameters_keyed,
+			output_parameters = EXCLUDED.output_parameters,
+			min_worker_version = EXCLUDED.min_worker_version,
+			worker_version_policy = EXCLUDED.worker_version_policy,
+			owner = EXCLUDED.owner,
+			namespace = EXCLUDED.namespace,
+			dedup_window_minutes = EXCLUDED.dedup_window_minutes,
+			parameter_docs = EXCLUDED.parameter_docs,
+			on_success = EXCLUDED.on_success,
+			alerts = EXCLUDED.alerts,
+			max_concurrency = EXCLUDED.max_concurrency,
+			rate_limit_per_minute = EXCLUDED.rate_limit_per_minute,
+			updated_at = CASE
+				WHEN (task.name, task.description, task.input_parameters, task.input_parameters_keyed, task.output_parameters, task.min_worker_version, task.worker_version_policy, task.owner, task.namespace, task.dedup_window_minutes, task.parameter_docs, task.on_success, task.alerts, task.max_concurrency, task.rate_limit_per_minute)
+					IS DISTINCT FROM
+					(EXCLUDED.name, EXCLUDED.description, EXCLUDED.input_parameters, EXCLUDED.input_parameters_keyed, EXCLUDED.output_parameters, EXCLUDED.min_worker_version, EXCLUDED.worker_version_policy, EXCLUDED.owner, EXCLUDED.namespace, EXCLUDED.dedup_window_minutes, EXCLUDED.parameter_docs, EXCLUDED.on_success, EXCLUDED.alerts, EXCLUDED.max_concurrency, EXCLUDED.rate_limit_per_minute)
+				THEN NOW()
+				ELSE task.updated_at
+			END
+		RETURNING
+			id,
+			rid,
+			key,
+			name,
+			description,
+			input_parameters,
+			input_parameters_keyed,
+			output_parameters,
+			min_worker_version,
+			worker_version_policy,
+			owner,
+			namespace,
+			dedup_window_minutes,
+			parameter_docs,
+			on_success,
+			alerts,
+			max_concurrency,
+			rate_limit_per_minute,
+			created_at,
+			updated_at,
+			(xmax = 0) AS created`
+
+	var created bool
+	var input_parametersData []byte
+	var input_parametersKeyedData []byte
+	var outputParametersData []byte
+	var parameterDocsData []byte
+	var onSuccessData []byte
+	var alertsData []byte
+	err = r.db.Instance.QueryRowContext(ctx, query, task.Key, task.Name, task.Description, input_parametersJSON, input_parametersKeyedJSON, outputParametersJSON, task.MinWorkerVersion, task.WorkerVersionPolicy, task.Owner, task.Namespace, task.DedupWindowMinutes, parameterDocsJSON, onSuccessJSON, alertsJSON, task.MaxConcurrency, task.RateLimitPerMinute).Scan(
+		&upsertedTask.ID,
+		&upsertedTask.RID,
+		&upsertedTask.Key,
+		&upsertedTask.Name,
+		&upsertedTask.Description,
+		&input_parametersData,
+		&input_parametersKeyedData,
+		&outputParametersData,
+		&upsertedTask.MinWorkerVersion,
+		&upsertedTask.WorkerVersionPolicy,
+		&upsertedTask.Owner,
+		&upsertedTask.Namespace,
+		&upsertedTask.DedupWindowMinutes,
+		&parameterDocsData,
+		&onSuccessData,
+		&alertsData,
+		&upsertedTask.MaxConcurrency,
+		&upsertedTask.RateLimitPerMinute,
+		&upsertedTask.CreatedAt,
+		&upsertedTask.UpdatedAt,
+		&created,
+	)
+	if err != nil {
+		return nil, false, helper.NewError("upsert task", err)
+	}
+
+	err = json.Unmarshal(input_parametersData, &upsertedTask.InputParameters)
+	if err != nil {
+		return nil, false, helper.NewError("unmarshal input_parameters", err)
+	}
+
+	err = json.Unmarshal(input_parametersKeyedData, &upsertedTask.InputParametersKeyed)
+	if err != nil {
+		return nil, false, helper.NewError("unmarshal input_parameters_keyed", err)
+	}
+
+	err = json.Unmarshal(outputParametersData, &upsertedTask.OutputParameters)
+	if err != nil {
+		return nil, false, helper.NewError("unmarshal output_parameters", err)
+	}
+
+	err = json.Unmarshal(parameterDocsData, &upsertedTask.ParameterDocs)
+	if err != nil {
+		return nil, false, helper.NewError("unmarshal parameter_docs", err)
+	}
+
+	err = json.Unmarshal(onSuccessData, &upsertedTask.OnSuccess)
+	if err != nil {
+		return nil, false, helper.NewError("unmarshal on_success", err)
+	}
+
+	err = json.Unmarshal(alertsData, &upsertedTask.Alerts)
+	if err != nil {
+		return nil, false, helper.NewError("unmarshal alerts", err)
+	}
+
+	return upsertedTask, created, nil
+}
+
 // DeleteTask deletes a task record from the database by RID.
 func (r TaskDBHandler) DeleteTask(rid uuid.UUID) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -307,6 +644,26 @@ func (r TaskDBHandler) DeleteTask(rid uuid.UUID) error {
 	return nil
 }
 
+// DeleteTaskByKey deletes a task record from the database by key.
+// It returns false without an error if no task with the key exists, so deleting is idempotent.
+func (r TaskDBHandler) DeleteTaskByKey(key string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DELETE FROM task WHERE key = $1`
+	result, err := r.db.Instance.ExecContext(ctx, query, key)
+	if err != nil {
+		return false, helper.NewError("delete task by key", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, helper.NewError("get rows affected", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
 // SelectTask retrieves a task by RID from the database.
 func (r TaskDBHandler) SelectTask(rid uuid.UUID) (*model.Task, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -323,6 +680,16 @@ func (r TaskDBHandler) SelectTask(rid uuid.UUID) (*model.Task, error) {
 			input_parameters,
 			input_parameters_keyed,
 			output_parameters,
+			min_worker_version,
+			worker_version_policy,
+			owner,
+			namespace,
+			dedup_window_minutes,
+			parameter_docs,
+			on_success,
+			alerts,
+			max_concurrency,
+			rate_limit_per_minute,
 			created_at,
 			updated_at
 		FROM task
@@ -332,6 +699,9 @@ func (r TaskDBHandler) SelectTask(rid uuid.UUID) (*model.Task, error) {
 	var input_parametersData []byte
 	var input_parametersKeyedData []byte
 	var outputParametersData []byte
+	var parameterDocsData []byte
+	var onSuccessData []byte
+	var alertsData []byte
 	err := r.db.Instance.QueryRowContext(ctx, query, rid).Scan(
 		&task.ID,
 		&task.RID,
@@ -341,6 +711,16 @@ func (r TaskDBHandler) SelectTask(rid uuid.UUID) (*model.Task, error) {
 		&input_parametersData,
 		&input_parametersKeyedData,
 		&outputParametersData,
+		&task.MinWorkerVersion,
+		&task.WorkerVersionPolicy,
+		&task.Owner,
+		&task.Namespace,
+		&task.DedupWindowMinutes,
+		&parameterDocsData,
+		&onSuccessData,
+		&alertsData,
+		&task.MaxConcurrency,
+		&task.RateLimitPerMinute,
 		&task.CreatedAt,
 		&task.UpdatedAt,
 	)
@@ -366,6 +746,21 @@ func (r TaskDBHandler) SelectTask(rid uuid.UUID) (*model.Task, error) {
 		return nil, helper.NewError("unmarshal output_parameters", err)
 	}
 
+	err = json.Unmarshal(parameterDocsData, &task.ParameterDocs)
+	if err != nil {
+		return nil, helper.NewError("unmarshal parameter_docs", err)
+	}
+
+	err = json.Unmarshal(onSuccessData, &task.OnSuccess)
+	if err != nil {
+		return nil, helper.NewError("unmarshal on_success", err)
+	}
+
+	err = json.Unmarshal(alertsData, &task.Alerts)
+	if err != nil {
+		return nil, helper.NewError("unmarshal alerts", err)
+	}
+
 	return task, nil
 }
 
@@ -376,7 +771,7 @@ func (r TaskDBHandler) SelectTaskByKey(key string) (*model.Task, error) {
 
 	task := &model.Task{}
 	query := `
-		SELECT id, rid, key, name, description, input_parameters, input_parameters_keyed, output_parameters, created_at, updated_at
+		SELECT id, rid, key, name, description, input_parameters, input_parameters_keyed, output_parameters, min_worker_version, worker_version_policy, owner, namespace, dedup_window_minutes, parameter_docs, on_success, alerts, max_concurrency, rate_limit_per_minute, created_at, updated_at
 		FROM task
 		WHERE key = $1
 	`
@@ -384,6 +779,9 @@ func (r TaskDBHandler) SelectTaskByKey(key string) (*model.Task, error) {
 	var input_parametersData []byte
 	var input_parametersKeyedData []byte
 	var outputParametersData []byte
+	var parameterDocsData []byte
+	var onSuccessData []byte
+	var alertsData []byte
 	err := r.db.Instance.QueryRowContext(ctx, query, key).Scan(
 		&task.ID,
 		&task.RID,
@@ -393,6 +791,16 @@ func (r TaskDBHandler) SelectTaskByKey(key string) (*model.Task, error) {
 		&input_parametersData,
 		&input_parametersKeyedData,
 		&outputParametersData,
+		&task.MinWorkerVersion,
+		&task.WorkerVersionPolicy,
+		&task.Owner,
+		&task.Namespace,
+		&task.DedupWindowMinutes,
+		&parameterDocsData,
+		&onSuccessData,
+		&alertsData,
+		&task.MaxConcurrency,
+		&task.RateLimitPerMinute,
 		&task.CreatedAt,
 		&task.UpdatedAt,
 	)
@@ -418,6 +826,21 @@ func (r TaskDBHandler) SelectTaskByKey(key string) (*model.Task, error) {
 		return nil, helper.NewError("unmarshal output_parameters", err)
 	}
 
+	err = json.Unmarshal(parameterDocsData, &task.ParameterDocs)
+	if err != nil {
+		return nil, helper.NewError("unmarshal parameter_docs", err)
+	}
+
+	err = json.Unmarshal(onSuccessData, &task.OnSuccess)
+	if err != nil {
+		return nil, helper.NewError("unmarshal on_success", err)
+	}
+
+	err = json.Unmarshal(alertsData, &task.Alerts)
+	if err != nil {
+		return nil, helper.NewError("unmarshal alerts", err)
+	}
+
 	return task, nil
 }
 
@@ -438,6 +861,16 @@ func (r TaskDBHandler) SelectAllTasks(lastID int, entries int) ([]*model.Task, e
 			input_parameters,
 			input_parameters_keyed,
 			output_parameters,
+			min_worker_version,
+			worker_version_policy,
+			owner,
+			namespace,
+			dedup_window_minutes,
+			parameter_docs,
+			on_success,
+			alerts,
+			max_concurrency,
+			rate_limit_per_minute,
 			created_at,
 			updated_at
 		FROM task
@@ -458,6 +891,9 @@ func (r TaskDBHandler) SelectAllTasks(lastID int, entries int) ([]*model.Task, e
 		var input_parametersData []byte
 		var input_parametersKeyedData []byte
 		var outputParametersData []byte
+		var parameterDocsData []byte
+		var onSuccessData []byte
+		var alertsData []byte
 
 		err := rows.Scan(
 			&task.ID,
@@ -468,6 +904,16 @@ func (r TaskDBHandler) SelectAllTasks(lastID int, entries int) ([]*model.Task, e
 			&input_parametersData,
 			&input_parametersKeyedData,
 			&outputParametersData,
+			&task.MinWorkerVersion,
+			&task.WorkerVersionPolicy,
+			&task.Owner,
+			&task.Namespace,
+			&task.DedupWindowMinutes,
+			&parameterDocsData,
+			&onSuccessData,
+			&alertsData,
+			&task.MaxConcurrency,
+			&task.RateLimitPerMinute,
 			&task.CreatedAt,
 			&task.UpdatedAt,
 		)
@@ -493,6 +939,24 @@ func (r TaskDBHandler) SelectAllTasks(lastID int, entries int) ([]*model.Task, e
 			task.OutputParameters = []vm.Validation{}
 		}
 
+		err = json.Unmarshal(parameterDocsData, &task.ParameterDocs)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal parameter_docs for task %s: %v", task.RID, err)
+			task.ParameterDocs = map[string]model.ParameterDoc{}
+		}
+
+		err = json.Unmarshal(onSuccessData, &task.OnSuccess)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal on_success for task %s: %v", task.RID, err)
+			task.OnSuccess = []model.TaskDependency{}
+		}
+
+		err = json.Unmarshal(alertsData, &task.Alerts)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal alerts for task %s: %v", task.RID, err)
+			task.Alerts = []string{}
+		}
+
 		tasks = append(tasks, task)
 	}
 
@@ -504,10 +968,11 @@ func (r TaskDBHandler) SelectAllTasks(lastID int, entries int) ([]*model.Task, e
 }
 
 // SelectAllTasksBySearch retrieves tasks matching the search query with pagination.
-// search is the search string to match against rid, key, name, and description
+// search is the search string to match against rid, key, name, description and owner
+// namespace is the namespace of the tasks (all namespaces if empty)
 // lastID is the ID of the last task from the previous page (0 for first page)
 // entries is the maximum number of tasks to return
-func (r TaskDBHandler) SelectAllTasksBySearch(search string, lastID int, entries int) ([]*model.Task, error) {
+func (r TaskDBHandler) SelectAllTasksBySearch(search string, namespace string, lastID int, entries int) ([]*model.Task, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -521,22 +986,35 @@ func (r TaskDBHandler) SelectAllTasksBySearch(search string, lastID int, entries
 			input_parameters,
 			input_parameters_keyed,
 			output_parameters,
+			min_worker_version,
+			worker_version_policy,
+			owner,
+			namespace,
+			dedup_window_minutes,
+			parameter_docs,
+			on_success,
+			alerts,
+			max_concurrency,
+			rate_limit_per_minute,
 			created_at,
 			updated_at
 		FROM task
 		WHERE (task.rid::text ILIKE '%' || $1 || '%'
 				OR task.key ILIKE '%' || $1 || '%'
 				OR task.name ILIKE '%' || $1 || '%'
-				OR task.description ILIKE '%' || $1 || '%')
-			AND (0 = $2
+				OR task.description ILIKE '%' || $1 || '%'
+				OR task.owner ILIKE '%' || $1 || '%')
+			AND ($2 = '' OR task.namespace = $2)
+			AND (0 = $3
 				OR task.created_at < (
 					SELECT t.created_at
 					FROM task AS t
-					WHERE t.id = $2))
+					WHERE t.id = $3))
 		ORDER BY task.created_at DESC
-		LIMIT $3
+		LIMIT $4
 		`,
 		search,
+		namespace,
 		lastID,
 		entries,
 	)
@@ -551,6 +1029,313 @@ func (r TaskDBHandler) SelectAllTasksBySearch(search string, lastID int, entries
 		var input_parametersData []byte
 		var input_parametersKeyedData []byte
 		var outputParametersData []byte
+		var parameterDocsData []byte
+		var onSuccessData []byte
+		var alertsData []byte
+
+		err := rows.Scan(
+			&task.ID,
+			&task.RID,
+			&task.Key,
+			&task.Name,
+			&task.Description,
+			&input_parametersData,
+			&input_parametersKeyedData,
+			&outputParametersData,
+			&task.MinWorkerVersion,
+			&task.WorkerVersionPolicy,
+			&task.Owner,
+			&task.Namespace,
+			&task.DedupWindowMinutes,
+			&parameterDocsData,
+			&onSuccessData,
+			&alertsData,
+			&task.MaxConcurrency,
+			&task.RateLimitPerMinute,
+			&task.CreatedAt,
+			&task.UpdatedAt,
+		)
+		if err != nil {
+			return nil, helper.NewError("scan task", err)
+		}
+
+		err = json.Unmarshal(input_parametersData, &task.InputParameters)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal input_parameters for task %s: %v", task.RID, err)
+			task.InputParameters = []vm.Validation{}
+		}
+
+		err = json.Unmarshal(input_parametersKeyedData, &task.InputParametersKeyed)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal input_parameters_keyed for task %s: %v", task.RID, err)
+			task.InputParametersKeyed = []vm.Validation{}
+		}
+
+		err = json.Unmarshal(outputParametersData, &task.OutputParameters)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal output_parameters for task %s: %v", task.RID, err)
+			task.OutputParameters = []vm.Validation{}
+		}
+
+		err = json.Unmarshal(parameterDocsData, &task.ParameterDocs)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal parameter_docs for task %s: %v", task.RID, err)
+			task.ParameterDocs = map[string]model.ParameterDoc{}
+		}
+
+		err = json.Unmarshal(onSuccessData, &task.OnSuccess)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal on_success for task %s: %v", task.RID, err)
+			task.OnSuccess = []model.TaskDependency{}
+		}
+
+		err = json.Unmarshal(alertsData, &task.Alerts)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal alerts for task %s: %v", task.RID, err)
+			task.Alerts = []string{}
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return tasks, nil
+}
+
+// SelectAllTasksByParameter retrieves the tasks with an input, keyed input or output parameter of the key with pagination.
+// parameterKey is matched exactly by a JSONB containment query on the parameter columns
+// lastID is the ID of the last task from the previous page (0 for first page)
+// entries is the maximum number of tasks to return
+func (r TaskDBHandler) SelectAllTasksByParameter(parameterKey string, lastID int, entries int) ([]*model.Task, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rows, err := r.db.Instance.QueryContext(ctx,
+		`SELECT
+			id,
+			rid,
+			key,
+			name,
+			description,
+			input_parameters,
+			input_parameters_keyed,
+			output_parameters,
+			min_worker_version,
+			worker_version_policy,
+			owner,
+			namespace,
+			dedup_window_minutes,
+			parameter_docs,
+			on_success,
+			alerts,
+			max_concurrency,
+			rate_limit_per_minute,
+			created_at,
+			updated_at
+		FROM task
+		WHERE (task.input_parameters @> jsonb_build_array(jsonb_build_object('Key', $1::text))
+				OR task.input_parameters_keyed @> jsonb_build_array(jsonb_build_object('Key', $1::text))
+				OR task.output_parameters @> jsonb_build_array(jsonb_build_object('Key', $1::text)))
+			AND task.id > $2
+		ORDER BY task.id ASC
+		LIMIT $3
+		`,
+		parameterKey,
+		lastID,
+		entries,
+	)
+	if err != nil {
+		return nil, helper.NewError("select tasks by parameter", err)
+	}
+	defer rows.Close()
+
+	tasks := []*model.Task{}
+	for rows.Next() {
+		task := &model.Task{}
+		var input_parametersData []byte
+		var input_parametersKeyedData []byte
+		var outputParametersData []byte
+		var parameterDocsData []byte
+		var onSuccessData []byte
+		var alertsData []byte
+
+		err := rows.Scan(
+			&task.ID,
+			&task.RID,
+			&task.Key,
+			&task.Name,
+			&task.Description,
+			&input_parametersData,
+			&input_parametersKeyedData,
+			&outputParametersData,
+			&task.MinWorkerVersion,
+			&task.WorkerVersionPolicy,
+			&task.Owner,
+			&task.Namespace,
+			&task.DedupWindowMinutes,
+			&parameterDocsData,
+			&onSuccessData,
+			&alertsData,
+			&task.MaxConcurrency,
+			&task.RateLimitPerMinute,
+			&task.CreatedAt,
+			&task.UpdatedAt,
+		)
+		if err != nil {
+			return nil, helper.NewError("scan task", err)
+		}
+
+		err = json.Unmarshal(input_parametersData, &task.InputParameters)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal input_parameters for task %s: %v", task.RID, err)
+			task.InputParameters = []vm.Validation{}
+		}
+
+		err = json.Unmarshal(input_parametersKeyedData, &task.InputParametersKeyed)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal input_parameters_keyed for task %s: %v", task.RID, err)
+			task.InputParametersKeyed = []vm.Validation{}
+		}
+
+		err = json.Unmarshal(outputParametersData, &task.OutputParameters)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal output_parameters for task %s: %v", task.RID, err)
+			task.OutputParameters = []vm.Validation{}
+		}
+
+		err = json.Unmarshal(parameterDocsData, &task.ParameterDocs)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal parameter_docs for task %s: %v", task.RID, err)
+			task.ParameterDocs = map[string]model.ParameterDoc{}
+		}
+
+		err = json.Unmarshal(onSuccessData, &task.OnSuccess)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal on_success for task %s: %v", task.RID, err)
+			task.OnSuccess = []model.TaskDependency{}
+		}
+
+		err = json.Unmarshal(alertsData, &task.Alerts)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal alerts for task %s: %v", task.RID, err)
+			task.Alerts = []string{}
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return tasks, nil
+}
+
+// taskSortExpressions are the SQL expressions of the sort fields of the task list, %s is the alias of the task table.
+// Tasks are sorted by the expression and their ID, so tasks with the same value keep a stable order between pages.
+var taskSortExpressions = map[string]string{
+	"":                         "%s.id",
+	model.TASK_SORT_NAME:       "COALESCE(%s.name, '')",
+	model.TASK_SORT_KEY:        "%s.key",
+	model.TASK_SORT_CREATED_AT: "%s.created_at",
+	model.TASK_SORT_UPDATED_AT: "%s.updated_at",
+}
+
+// taskFilterCondition is the condition of the filters of the task list, the parameters are in the order of taskFilterArgs.
+const taskFilterCondition = `($1 = ''
+			OR task.input_parameters @> jsonb_build_array(jsonb_build_object('Key', $1::text))
+			OR task.input_parameters_keyed @> jsonb_build_array(jsonb_build_object('Key', $1::text))
+			OR task.output_parameters @> jsonb_build_array(jsonb_build_object('Key', $1::text)))
+		AND ($2 = '' OR task.key LIKE $2 || '%')
+		AND ($3 = false OR jsonb_array_length(task.output_parameters) > 0)
+		AND ($4::TIMESTAMPTZ IS NULL OR task.created_at > $4)
+		AND ($5::TIMESTAMPTZ IS NULL OR task.created_at < $5)
+		AND ($6 = '' OR task.namespace = $6)`
+
+// taskKeyPrefixEscaper escapes the LIKE wildcards in a key prefix, so they match literally
+var taskKeyPrefixEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// taskFilterArgs returns the query parameters of taskFilterCondition.
+func taskFilterArgs(filter *model.TaskFilter) []any {
+	return []any{
+		filter.Parameter,
+		taskKeyPrefixEscaper.Replace(filter.KeyPrefix),
+		filter.WithOutputParameters,
+		sql.NullTime{Time: filter.CreatedAfter, Valid: !filter.CreatedAfter.IsZero()},
+		sql.NullTime{Time: filter.CreatedBefore, Valid: !filter.CreatedBefore.IsZero()},
+		filter.Namespace,
+	}
+}
+
+// SelectAllTasksByFilter retrieves the tasks matching the filter in the sort order of the filter with pagination.
+// lastID is the ID of the last task from the previous page (0 for first page)
+// entries is the maximum number of tasks to return
+func (r TaskDBHandler) SelectAllTasksByFilter(filter *model.TaskFilter, lastID int, entries int) ([]*model.Task, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	sortExpression, ok := taskSortExpressions[filter.Sort]
+	if !ok {
+		return nil, helper.NewError("select tasks by filter", fmt.Errorf("invalid sort %q", filter.Sort))
+	}
+	direction, comparison := "ASC", ">"
+	if filter.Descending {
+		direction, comparison = "DESC", "<"
+	}
+	sortColumn, lastSortColumn := fmt.Sprintf(sortExpression, "task"), fmt.Sprintf(sortExpression, "t")
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			rid,
+			key,
+			name,
+			description,
+			input_parameters,
+			input_parameters_keyed,
+			output_parameters,
+			min_worker_version,
+			worker_version_policy,
+			owner,
+			namespace,
+			dedup_window_minutes,
+			parameter_docs,
+			on_success,
+			alerts,
+			max_concurrency,
+			rate_limit_per_minute,
+			created_at,
+			updated_at
+		FROM task
+		WHERE %[1]s
+			AND ($7 = 0
+				OR (%[2]s, task.id) %[4]s (
+					SELECT %[3]s, t.id
+					FROM task AS t
+					WHERE t.id = $7))
+		ORDER BY %[2]s %[5]s, task.id %[5]s
+		LIMIT $8
+	`, taskFilterCondition, sortColumn, lastSortColumn, comparison, direction)
+
+	args := append(taskFilterArgs(filter), lastID, entries)
+	rows, err := r.db.Instance.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, helper.NewError("select tasks by filter", err)
+	}
+	defer rows.Close()
+
+	tasks := []*model.Task{}
+	for rows.Next() {
+		task := &model.Task{}
+		var input_parametersData []byte
+		var input_parametersKeyedData []byte
+		var outputParametersData []byte
+		var parameterDocsData []byte
+		var onSuccessData []byte
+		var alertsData []byte
 
 		err := rows.Scan(
 			&task.ID,
@@ -561,6 +1346,16 @@ func (r TaskDBHandler) SelectAllTasksBySearch(search string, lastID int, entries
 			&input_parametersData,
 			&input_parametersKeyedData,
 			&outputParametersData,
+			&task.MinWorkerVersion,
+			&task.WorkerVersionPolicy,
+			&task.Owner,
+			&task.Namespace,
+			&task.DedupWindowMinutes,
+			&parameterDocsData,
+			&onSuccessData,
+			&alertsData,
+			&task.MaxConcurrency,
+			&task.RateLimitPerMinute,
 			&task.CreatedAt,
 			&task.UpdatedAt,
 		)
@@ -586,6 +1381,24 @@ func (r TaskDBHandler) SelectAllTasksBySearch(search string, lastID int, entries
 			task.OutputParameters = []vm.Validation{}
 		}
 
+		err = json.Unmarshal(parameterDocsData, &task.ParameterDocs)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal parameter_docs for task %s: %v", task.RID, err)
+			task.ParameterDocs = map[string]model.ParameterDoc{}
+		}
+
+		err = json.Unmarshal(onSuccessData, &task.OnSuccess)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal on_success for task %s: %v", task.RID, err)
+			task.OnSuccess = []model.TaskDependency{}
+		}
+
+		err = json.Unmarshal(alertsData, &task.Alerts)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal alerts for task %s: %v", task.RID, err)
+			task.Alerts = []string{}
+		}
+
 		tasks = append(tasks, task)
 	}
 
@@ -595,3 +1408,238 @@ func (r TaskDBHandler) SelectAllTasksBySearch(search string, lastID int, entries
 
 	return tasks, nil
 }
+
+// SelectUpstreamTasks retrieves the tasks with the task of the key as downstream task in on_success.
+func (r TaskDBHandler) SelectUpstreamTasks(taskKey string) ([]*model.Task, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rows, err := r.db.Instance.QueryContext(ctx,
+		`SELECT
+			id,
+			rid,
+			key,
+			name,
+			description,
+			input_parameters,
+			input_parameters_keyed,
+			output_parameters,
+			min_worker_version,
+			worker_version_policy,
+			owner,
+			namespace,
+			dedup_window_minutes,
+			parameter_docs,
+			on_success,
+			alerts,
+			max_concurrency,
+			rate_limit_per_minute,
+			created_at,
+			updated_at
+		FROM task
+		WHERE task.on_success @> jsonb_build_array(jsonb_build_object('task_key', $1::text))
+		ORDER BY task.id ASC
+		`,
+		taskKey,
+	)
+	if err != nil {
+		return nil, helper.NewError("select upstream tasks", err)
+	}
+	defer rows.Close()
+
+	tasks := []*model.Task{}
+	for rows.Next() {
+		task := &model.Task{}
+		var input_parametersData []byte
+		var input_parametersKeyedData []byte
+		var outputParametersData []byte
+		var parameterDocsData []byte
+		var onSuccessData []byte
+		var alertsData []byte
+
+		err := rows.Scan(
+			&task.ID,
+			&task.RID,
+			&task.Key,
+			&task.Name,
+			&task.Description,
+			&input_parametersData,
+			&input_parametersKeyedData,
+			&outputParametersData,
+			&task.MinWorkerVersion,
+			&task.WorkerVersionPolicy,
+			&task.Owner,
+			&task.Namespace,
+			&task.DedupWindowMinutes,
+			&parameterDocsData,
+			&onSuccessData,
+			&alertsData,
+			&task.MaxConcurrency,
+			&task.RateLimitPerMinute,
+			&task.CreatedAt,
+			&task.UpdatedAt,
+		)
+		if err != nil {
+			return nil, helper.NewError("scan task", err)
+		}
+
+		err = json.Unmarshal(input_parametersData, &task.InputParameters)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal input_parameters for task %s: %v", task.RID, err)
+			task.InputParameters = []vm.Validation{}
+		}
+
+		err = json.Unmarshal(input_parametersKeyedData, &task.InputParametersKeyed)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal input_parameters_keyed for task %s: %v", task.RID, err)
+			task.InputParametersKeyed = []vm.Validation{}
+		}
+
+		err = json.Unmarshal(outputParametersData, &task.OutputParameters)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal output_parameters for task %s: %v", task.RID, err)
+			task.OutputParameters = []vm.Validation{}
+		}
+
+		err = json.Unmarshal(parameterDocsData, &task.ParameterDocs)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal parameter_docs for task %s: %v", task.RID, err)
+			task.ParameterDocs = map[string]model.ParameterDoc{}
+		}
+
+		err = json.Unmarshal(onSuccessData, &task.OnSuccess)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal on_success for task %s: %v", task.RID, err)
+			task.OnSuccess = []model.TaskDependency{}
+		}
+
+		err = json.Unmarshal(alertsData, &task.Alerts)
+		if err != nil {
+			log.Printf("Warning: failed to unmarshal alerts for task %s: %v", task.RID, err)
+			task.Alerts = []string{}
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return tasks, nil
+}
+
+// marshalParameterDocs marshals the parameter documentation of a task, no documentation is stored as empty object.
+func marshalParameterDocs(parameterDocs map[string]model.ParameterDoc) ([]byte, error) {
+	if parameterDocs == nil {
+		parameterDocs = map[string]model.ParameterDoc{}
+	}
+	return json.Marshal(parameterDocs)
+}
+
+// marshalTaskDependencies marshals the downstream tasks of a task, no dependencies are stored as empty array.
+func marshalTaskDependencies(dependencies []model.TaskDependency) ([]byte, error) {
+	if dependencies == nil {
+		dependencies = []model.TaskDependency{}
+	}
+	return json.Marshal(dependencies)
+}
+
+// marshalTaskAlerts marshals the alert kinds of a task, nil is stored as empty list.
+func marshalTaskAlerts(alerts []string) ([]byte, error) {
+	if alerts == nil {
+		alerts = []string{}
+	}
+	return json.Marshal(alerts)
+}
+
+// SelectAllTasksCount counts all tasks.
+func (r TaskDBHandler) SelectAllTasksCount() (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `SELECT COUNT(*) FROM task`
+
+	var count int
+	err := r.db.Instance.QueryRowContext(ctx, query).Scan(&count)
+	if err != nil {
+		return 0, helper.NewError("count tasks", err)
+	}
+
+	return count, nil
+}
+
+// SelectAllTasksByParameterCount counts the tasks with an input, keyed input or output parameter with the key.
+func (r TaskDBHandler) SelectAllTasksByParameterCount(parameterKey string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT COUNT(*)
+		FROM task
+		WHERE task.input_parameters @> jsonb_build_array(jsonb_build_object('Key', $1::text))
+			OR task.input_parameters_keyed @> jsonb_build_array(jsonb_build_object('Key', $1::text))
+			OR task.output_parameters @> jsonb_build_array(jsonb_build_object('Key', $1::text))
+	`
+
+	var count int
+	err := r.db.Instance.QueryRowContext(ctx, query, parameterKey).Scan(&count)
+	if err != nil {
+		return 0, helper.NewError("count tasks by parameter", err)
+	}
+
+	return count, nil
+}
+
+// SelectAllTasksByFilterCount counts the tasks matching the filter, the sort of the filter is ignored.
+func (r TaskDBHandler) SelectAllTasksByFilterCount(filter *model.TaskFilter) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT COUNT(*)
+		FROM task
+		WHERE ` + taskFilterCondition
+
+	var count int
+	err := r.db.Instance.QueryRowContext(ctx, query, taskFilterArgs(filter)...).Scan(&count)
+	if err != nil {
+		return 0, helper.NewError("count tasks by filter", err)
+	}
+
+	return count, nil
+}
+
+// SelectNamespaces retrieves the namespaces of the tasks ordered by name, tasks without namespace are not counted.
+func (r TaskDBHandler) SelectNamespaces() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT DISTINCT namespace
+		FROM task
+		WHERE namespace != ''
+		ORDER BY namespace ASC
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query)
+	if err != nil {
+		return nil, helper.NewError("select namespaces", err)
+	}
+	defer rows.Close()
+
+	namespaces := []string{}
+	for rows.Next() {
+		var namespace string
+		if err := rows.Scan(&namespace); err != nil {
+			return nil, helper.NewError("scan namespace", err)
+		}
+		namespaces = append(namespaces, namespace)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return namespaces, nil
+}
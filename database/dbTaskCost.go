@@ -0,0 +1,322 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+)
+
+// costGroupColumns are the columns of the task_cost table the job costs can be grouped by.
+var costGroupColumns = map[string]string{
+	model.COST_GROUP_TASK:   "task_cost.task_key",
+	model.COST_GROUP_LABEL:  "task_cost.label",
+	model.COST_GROUP_TENANT: "task_cost.tenant",
+}
+
+// TaskCostDBHandlerFunctions defines the interface for TaskCost database operations.
+type TaskCostDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	UpsertTaskCost(taskCost *model.TaskCost) (*model.TaskCost, error)
+	SelectTaskCost(taskKey string) (*model.TaskCost, error)
+	SelectAllTaskCosts() ([]*model.TaskCost, error)
+	DeleteTaskCost(taskKey string) error
+	SelectJobCosts(since time.Time, until time.Time, groupBy string) ([]*model.JobCost, error)
+}
+
+// TaskCostDBHandler implements TaskCostDBHandlerFunctions and holds the database connection.
+type TaskCostDBHandler struct {
+	db *helper.Database
+}
+
+// NewTaskCostDBHandler creates a new instance of TaskCostDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing task_cost table before creating a new one
+func NewTaskCostDBHandler(dbConnection *helper.Database, withTableDrop bool) (*TaskCostDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	taskCostDbHandler := &TaskCostDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := taskCostDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := taskCostDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return taskCostDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'task_cost' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r TaskCostDBHandler) CheckTableExistance() (bool, error) {
+	taskCostExists, err := r.db.CheckTableExistance("task_cost")
+	if err != nil {
+		return false, helper.NewError("task_cost table", err)
+	}
+	return taskCostExists, nil
+}
+
+// CreateTable creates the 'task_cost' table in the database.
+// If the table already exists, it does not create it again.
+func (r TaskCostDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS task_cost (
+			task_key VARCHAR(100) PRIMARY KEY,
+			model VARCHAR(20) NOT NULL,
+			rate DOUBLE PRECISION NOT NULL DEFAULT 0,
+			label VARCHAR(100) NOT NULL DEFAULT '',
+			tenant VARCHAR(100) NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create task_cost table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table task_cost")
+
+	return nil
+}
+
+// DropTable drops the 'task_cost' table from the database.
+func (r TaskCostDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS task_cost`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop task_cost table", err)
+	}
+
+	r.db.Logger.Info("Dropped table task_cost")
+
+	return nil
+}
+
+// UpsertTaskCost creates or replaces the cost model of a task.
+func (r TaskCostDBHandler) UpsertTaskCost(taskCost *model.TaskCost) (*model.TaskCost, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		INSERT INTO task_cost (
+			task_key,
+			model,
+			rate,
+			label,
+			tenant
+		) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (task_key) DO UPDATE
+		SET
+			model = EXCLUDED.model,
+			rate = EXCLUDED.rate,
+			label = EXCLUDED.label,
+			tenant = EXCLUDED.tenant,
+			updated_at = NOW()
+		RETURNING
+			task_key,
+			model,
+			rate,
+			label,
+			tenant,
+			created_at,
+			updated_at`
+
+	row := r.db.Instance.QueryRowContext(
+		ctx,
+		query,
+		taskCost.TaskKey,
+		taskCost.Model,
+		taskCost.Rate,
+		taskCost.Label,
+		taskCost.Tenant,
+	)
+	upsertedTaskCost, err := scanTaskCost(row)
+	if err != nil {
+		return nil, helper.NewError("upsert task cost", err)
+	}
+
+	return upsertedTaskCost, nil
+}
+
+// SelectTaskCost retrieves the cost model of a task by the task key.
+func (r TaskCostDBHandler) SelectTaskCost(taskKey string) (*model.TaskCost, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			task_key,
+			model,
+			rate,
+			label,
+			tenant,
+			created_at,
+			updated_at
+		FROM task_cost
+		WHERE task_key = $1
+	`
+
+	taskCost, err := scanTaskCost(r.db.Instance.QueryRowContext(ctx, query, taskKey))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("task cost not found", fmt.Errorf("no cost model of task %s", taskKey))
+		}
+		return nil, helper.NewError("select task cost", err)
+	}
+
+	return taskCost, nil
+}
+
+// SelectAllTaskCosts retrieves the cost models of all tasks ordered by task key.
+func (r TaskCostDBHandler) SelectAllTaskCosts() ([]*model.TaskCost, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			task_key,
+			model,
+			rate,
+			label,
+			tenant,
+			created_at,
+			updated_at
+		FROM task_cost
+		ORDER BY task_key ASC
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query)
+	if err != nil {
+		return nil, helper.NewError("select all task costs", err)
+	}
+	defer rows.Close()
+
+	taskCosts := []*model.TaskCost{}
+	for rows.Next() {
+		taskCost, err := scanTaskCost(rows)
+		if err != nil {
+			return nil, helper.NewError("scan task cost", err)
+		}
+		taskCosts = append(taskCosts, taskCost)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return taskCosts, nil
+}
+
+// DeleteTaskCost deletes the cost model of a task, it does not return an error if the task has no cost model.
+func (r TaskCostDBHandler) DeleteTaskCost(taskKey string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DELETE FROM task_cost WHERE task_key = $1`
+	_, err := r.db.Instance.ExecContext(ctx, query, taskKey)
+	if err != nil {
+		return helper.NewError("delete task cost", err)
+	}
+
+	return nil
+}
+
+// SelectJobCosts estimates the costs of the jobs that ended between since and until grouped by task key, label or tenant.
+// The costs are calculated with the current cost models of the tasks, jobs of tasks without cost model
+// and jobs that never started are not accounted. Ended jobs are counted from the job archive of the queuer.
+func (r TaskCostDBHandler) SelectJobCosts(since time.Time, until time.Time, groupBy string) ([]*model.JobCost, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	groupColumn, ok := costGroupColumns[groupBy]
+	if !ok {
+		return nil, helper.NewError("cost group validation", fmt.Errorf("invalid cost group %s", groupBy))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			%s AS cost_group,
+			COUNT(*),
+			COALESCE(SUM(extract(epoch FROM job_archive.updated_at - job_archive.started_at)), 0)::FLOAT8,
+			COALESCE(SUM(CASE
+				WHEN task_cost.model = '%s' THEN task_cost.rate
+				ELSE task_cost.rate * extract(epoch FROM job_archive.updated_at - job_archive.started_at)
+			END), 0)::FLOAT8 AS cost
+		FROM job_archive
+		JOIN task_cost ON task_cost.task_key = job_archive.task_name
+		WHERE job_archive.updated_at >= $1
+			AND job_archive.updated_at <= $2
+			AND job_archive.started_at IS NOT NULL
+		GROUP BY cost_group
+		ORDER BY cost DESC, cost_group ASC
+	`, groupColumn, model.COST_MODEL_RUN)
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, since, until)
+	if err != nil {
+		return nil, helper.NewError("select job costs", err)
+	}
+	defer rows.Close()
+
+	jobCosts := []*model.JobCost{}
+	for rows.Next() {
+		jobCost := &model.JobCost{}
+		err := rows.Scan(
+			&jobCost.Group,
+			&jobCost.Jobs,
+			&jobCost.DurationSeconds,
+			&jobCost.Cost,
+		)
+		if err != nil {
+			return nil, helper.NewError("scan job cost", err)
+		}
+		jobCosts = append(jobCosts, jobCost)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return jobCosts, nil
+}
+
+// scanTaskCost scans a task cost row in the column order of the task cost queries.
+func scanTaskCost(row interface{ Scan(dest ...any) error }) (*model.TaskCost, error) {
+	taskCost := &model.TaskCost{}
+	err := row.Scan(
+		&taskCost.TaskKey,
+		&taskCost.Model,
+		&taskCost.Rate,
+		&taskCost.Label,
+		&taskCost.Tenant,
+		&taskCost.CreatedAt,
+		&taskCost.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return taskCost, nil
+}
@@ -0,0 +1,134 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskCostNewTaskCostDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewTaskCostDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		taskCostDbHandler, err := NewTaskCostDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewTaskCostDBHandler to not return an error")
+		require.NotNil(t, taskCostDbHandler, "Expected NewTaskCostDBHandler to return a non-nil instance")
+
+		exists, err := taskCostDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = taskCostDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewTaskCostDBHandler with nil database", func(t *testing.T) {
+		_, err := NewTaskCostDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating TaskCostDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestTaskCostUpsertSelectAndDeleteTaskCost(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	taskCostDbHandler, err := NewTaskCostDBHandler(database, true)
+	require.NoError(t, err, "Expected NewTaskCostDBHandler to not return an error")
+
+	insertedTaskCost, err := taskCostDbHandler.UpsertTaskCost(&model.TaskCost{TaskKey: "test-task", Model: model.COST_MODEL_RUN, Rate: 0.5, Tenant: "acme"})
+	require.NoError(t, err, "Expected UpsertTaskCost to not return an error")
+	assert.Equal(t, model.COST_MODEL_RUN, insertedTaskCost.Model)
+	assert.False(t, insertedTaskCost.CreatedAt.IsZero())
+
+	updatedTaskCost, err := taskCostDbHandler.UpsertTaskCost(&model.TaskCost{TaskKey: "test-task", Model: model.COST_MODEL_SECOND, Rate: 0.01, Label: "analytics"})
+	require.NoError(t, err, "Expected UpsertTaskCost to not return an error for an existing task cost")
+	assert.Equal(t, model.COST_MODEL_SECOND, updatedTaskCost.Model)
+	assert.Equal(t, "analytics", updatedTaskCost.Label)
+	assert.Empty(t, updatedTaskCost.Tenant, "Expected the upsert to replace the whole cost model")
+
+	selectedTaskCost, err := taskCostDbHandler.SelectTaskCost("test-task")
+	require.NoError(t, err, "Expected SelectTaskCost to not return an error")
+	assert.Equal(t, 0.01, selectedTaskCost.Rate)
+
+	taskCosts, err := taskCostDbHandler.SelectAllTaskCosts()
+	require.NoError(t, err, "Expected SelectAllTaskCosts to not return an error")
+	assert.Len(t, taskCosts, 1)
+
+	err = taskCostDbHandler.DeleteTaskCost("test-task")
+	require.NoError(t, err, "Expected DeleteTaskCost to not return an error")
+
+	_, err = taskCostDbHandler.SelectTaskCost("test-task")
+	assert.Error(t, err, "Expected SelectTaskCost to return an error for a deleted task cost")
+}
+
+func TestTaskCostSelectJobCosts(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+	createQueuerTables(t, database)
+
+	taskCostDbHandler, err := NewTaskCostDBHandler(database, true)
+	require.NoError(t, err, "Expected NewTaskCostDBHandler to not return an error")
+
+	_, err = taskCostDbHandler.UpsertTaskCost(&model.TaskCost{TaskKey: "cost-run", Model: model.COST_MODEL_RUN, Rate: 2, Tenant: "acme"})
+	require.NoError(t, err)
+	_, err = taskCostDbHandler.UpsertTaskCost(&model.TaskCost{TaskKey: "cost-second", Model: model.COST_MODEL_SECOND, Rate: 0.5, Tenant: "acme"})
+	require.NoError(t, err)
+
+	// Two runs of 10 seconds each, a job that never started and a job of a task without cost model
+	_, err = database.Instance.Exec(`
+		INSERT INTO job_archive (task_name, status, started_at, updated_at) VALUES
+			('cost-run', 'SUCCEEDED', NOW() - INTERVAL '10 seconds', NOW()),
+			('cost-second', 'FAILED', NOW() - INTERVAL '10 seconds', NOW()),
+			('cost-second', 'CANCELLED', NULL, NOW()),
+			('cost-free', 'SUCCEEDED', NOW() - INTERVAL '10 seconds', NOW())
+	`)
+	require.NoError(t, err)
+	since := time.Now().Add(-time.Hour)
+	until := time.Now().Add(time.Hour)
+
+	t.Run("Group job costs by task", func(t *testing.T) {
+		jobCosts, err := taskCostDbHandler.SelectJobCosts(since, until, model.COST_GROUP_TASK)
+		require.NoError(t, err, "Expected SelectJobCosts to not return an error")
+		require.Len(t, jobCosts, 2)
+		assert.Equal(t, "cost-second", jobCosts[0].Group)
+		assert.Equal(t, 1, jobCosts[0].Jobs)
+		assert.InDelta(t, 5, jobCosts[0].Cost, 0.1)
+		assert.Equal(t, "cost-run", jobCosts[1].Group)
+		assert.Equal(t, 2.0, jobCosts[1].Cost)
+	})
+
+	t.Run("Group job costs by tenant", func(t *testing.T) {
+		jobCosts, err := taskCostDbHandler.SelectJobCosts(since, until, model.COST_GROUP_TENANT)
+		require.NoError(t, err, "Expected SelectJobCosts to not return an error")
+		require.Len(t, jobCosts, 1)
+		assert.Equal(t, "acme", jobCosts[0].Group)
+		assert.Equal(t, 2, jobCosts[0].Jobs)
+		assert.InDelta(t, 7, jobCosts[0].Cost, 0.1)
+		assert.InDelta(t, 20, jobCosts[0].DurationSeconds, 0.1)
+	})
+
+	t.Run("Invalid cost group", func(t *testing.T) {
+		_, err := taskCostDbHandler.SelectJobCosts(since, until, "task_key; DROP TABLE task_cost")
+		assert.Error(t, err, "Expected SelectJobCosts to return an error for an invalid cost group")
+	})
+}
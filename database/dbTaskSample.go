@@ -0,0 +1,208 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+)
+
+// TaskSampleDBHandlerFunctions defines the interface for TaskSample database operations.
+type TaskSampleDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	UpsertTaskSample(taskSample *model.TaskSample) (*model.TaskSample, error)
+	SelectTaskSample(taskKey string) (*model.TaskSample, error)
+	DeleteTaskSample(taskKey string) error
+}
+
+// TaskSampleDBHandler implements TaskSampleDBHandlerFunctions and holds the database connection.
+type TaskSampleDBHandler struct {
+	db *helper.Database
+}
+
+// NewTaskSampleDBHandler creates a new instance of TaskSampleDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing task_sample table before creating a new one
+func NewTaskSampleDBHandler(dbConnection *helper.Database, withTableDrop bool) (*TaskSampleDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	taskSampleDbHandler := &TaskSampleDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := taskSampleDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := taskSampleDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return taskSampleDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'task_sample' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r TaskSampleDBHandler) CheckTableExistance() (bool, error) {
+	taskSampleExists, err := r.db.CheckTableExistance("task_sample")
+	if err != nil {
+		return false, helper.NewError("task_sample table", err)
+	}
+	return taskSampleExists, nil
+}
+
+// CreateTable creates the 'task_sample' table in the database.
+// If the table already exists, it does not create it again.
+func (r TaskSampleDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS task_sample (
+			task_key VARCHAR(100) PRIMARY KEY,
+			sample_values JSONB NOT NULL DEFAULT '{}'::jsonb,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create task_sample table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table task_sample")
+
+	return nil
+}
+
+// DropTable drops the 'task_sample' table from the database.
+func (r TaskSampleDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS task_sample`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop task_sample table", err)
+	}
+
+	r.db.Logger.Info("Dropped table task_sample")
+
+	return nil
+}
+
+// UpsertTaskSample creates or replaces the sample values of a task.
+func (r TaskSampleDBHandler) UpsertTaskSample(taskSample *model.TaskSample) (*model.TaskSample, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	valuesJSON, err := marshalSampleValues(taskSample.Values)
+	if err != nil {
+		return nil, helper.NewError("marshal sample values", err)
+	}
+
+	query := `
+		INSERT INTO task_sample (
+			task_key,
+			sample_values
+		) VALUES ($1, $2)
+		ON CONFLICT (task_key) DO UPDATE
+		SET
+			sample_values = EXCLUDED.sample_values,
+			updated_at = NOW()
+		RETURNING
+			task_key,
+			sample_values,
+			created_at,
+			updated_at`
+
+	upsertedTaskSample, err := scanTaskSample(r.db.Instance.QueryRowContext(ctx, query, taskSample.TaskKey, valuesJSON))
+	if err != nil {
+		return nil, helper.NewError("upsert task sample", err)
+	}
+
+	return upsertedTaskSample, nil
+}
+
+// SelectTaskSample retrieves the sample values of a task by the task key.
+func (r TaskSampleDBHandler) SelectTaskSample(taskKey string) (*model.TaskSample, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			task_key,
+			sample_values,
+			created_at,
+			updated_at
+		FROM task_sample
+		WHERE task_key = $1
+	`
+
+	taskSample, err := scanTaskSample(r.db.Instance.QueryRowContext(ctx, query, taskKey))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("task sample not found", fmt.Errorf("no sample values of task %s", taskKey))
+		}
+		return nil, helper.NewError("select task sample", err)
+	}
+
+	return taskSample, nil
+}
+
+// DeleteTaskSample deletes the sample values of a task, it does not return an error if the task has no sample values.
+func (r TaskSampleDBHandler) DeleteTaskSample(taskKey string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DELETE FROM task_sample WHERE task_key = $1`
+	_, err := r.db.Instance.ExecContext(ctx, query, taskKey)
+	if err != nil {
+		return helper.NewError("delete task sample", err)
+	}
+
+	return nil
+}
+
+func marshalSampleValues(values map[string]string) ([]byte, error) {
+	if values == nil {
+		values = map[string]string{}
+	}
+	return json.Marshal(values)
+}
+
+// scanTaskSample scans a task sample row in the column order of the task sample queries.
+func scanTaskSample(row interface{ Scan(dest ...any) error }) (*model.TaskSample, error) {
+	taskSample := &model.TaskSample{}
+	var valuesData []byte
+	err := row.Scan(
+		&taskSample.TaskKey,
+		&valuesData,
+		&taskSample.CreatedAt,
+		&taskSample.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(valuesData, &taskSample.Values)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal sample values: %w", err)
+	}
+
+	return taskSample, nil
+}
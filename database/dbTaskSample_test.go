@@ -0,0 +1,71 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskSampleNewTaskSampleDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewTaskSampleDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		taskSampleDbHandler, err := NewTaskSampleDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewTaskSampleDBHandler to not return an error")
+		require.NotNil(t, taskSampleDbHandler, "Expected NewTaskSampleDBHandler to return a non-nil instance")
+
+		exists, err := taskSampleDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = taskSampleDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewTaskSampleDBHandler with nil database", func(t *testing.T) {
+		_, err := NewTaskSampleDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating TaskSampleDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestTaskSampleUpsertSelectAndDeleteTaskSample(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	taskSampleDbHandler, err := NewTaskSampleDBHandler(database, true)
+	require.NoError(t, err, "Expected NewTaskSampleDBHandler to not return an error")
+
+	insertedTaskSample, err := taskSampleDbHandler.UpsertTaskSample(&model.TaskSample{TaskKey: "test-task", Values: map[string]string{"param1": "1", "param2": "a"}})
+	require.NoError(t, err, "Expected UpsertTaskSample to not return an error")
+	assert.Equal(t, "1", insertedTaskSample.Values["param1"])
+	assert.False(t, insertedTaskSample.CreatedAt.IsZero())
+
+	updatedTaskSample, err := taskSampleDbHandler.UpsertTaskSample(&model.TaskSample{TaskKey: "test-task", Values: map[string]string{"param1": "2"}})
+	require.NoError(t, err, "Expected UpsertTaskSample to not return an error for an existing task sample")
+	assert.Equal(t, map[string]string{"param1": "2"}, updatedTaskSample.Values, "Expected the upsert to replace all sample values")
+
+	selectedTaskSample, err := taskSampleDbHandler.SelectTaskSample("test-task")
+	require.NoError(t, err, "Expected SelectTaskSample to not return an error")
+	assert.Equal(t, "2", selectedTaskSample.Values["param1"])
+
+	err = taskSampleDbHandler.DeleteTaskSample("test-task")
+	require.NoError(t, err, "Expected DeleteTaskSample to not return an error")
+
+	_, err = taskSampleDbHandler.SelectTaskSample("test-task")
+	assert.Error(t, err, "Expected SelectTaskSample to return an error for a deleted task sample")
+}
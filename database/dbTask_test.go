@@ -168,6 +168,73 @@ func TestTaskUpdateTask(t *testing.T) {
 	assert.True(t, updatedTask.UpdatedAt.After(insertedTask.UpdatedAt), "Expected UpdatedAt to be after original")
 }
 
+func TestTaskParameterDocs(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	taskDbHandler, err := NewTaskDBHandler(database, true)
+	require.NoError(t, err, "Expected NewTaskDBHandler to not return an error")
+
+	insertedTask, err := taskDbHandler.InsertTask(&model.Task{
+		Key:             "test_task_docs",
+		Name:            "Test Task Docs",
+		InputParameters: []vm.Validation{{Key: "date", Type: vm.String, Requirement: "min1"}},
+		ParameterDocs:   map[string]model.ParameterDoc{"date": {Description: "Day of the report", Example: "2026-01-31"}},
+	})
+	require.NoError(t, err, "Expected InsertTask to not return an error")
+	assert.Equal(t, model.ParameterDoc{Description: "Day of the report", Example: "2026-01-31"}, insertedTask.ParameterDocs["date"])
+
+	insertedTask.ParameterDocs = map[string]model.ParameterDoc{"date": {Description: "Day of the report (UTC)"}}
+	updatedTask, err := taskDbHandler.UpdateTask(insertedTask)
+	require.NoError(t, err, "Expected UpdateTask to not return an error")
+	assert.Equal(t, model.ParameterDoc{Description: "Day of the report (UTC)"}, updatedTask.ParameterDocs["date"])
+
+	selectedTask, err := taskDbHandler.SelectTaskByKey("test_task_docs")
+	require.NoError(t, err, "Expected SelectTaskByKey to not return an error")
+	assert.Equal(t, updatedTask.ParameterDocs, selectedTask.ParameterDocs)
+
+	otherTask, err := taskDbHandler.InsertTask(&model.Task{Key: "test_task_no_docs", Name: "Test Task No Docs"})
+	require.NoError(t, err, "Expected InsertTask to not return an error")
+	assert.Empty(t, otherTask.ParameterDocs, "Expected a task without docs to have no parameter docs")
+}
+
+func TestTaskAlerts(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	taskDbHandler, err := NewTaskDBHandler(database, true)
+	require.NoError(t, err, "Expected NewTaskDBHandler to not return an error")
+
+	insertedTask, err := taskDbHandler.InsertTask(&model.Task{
+		Key:    "test_task_alerts",
+		Name:   "Test Task Alerts",
+		Alerts: []string{model.ALERT_JOB_FAILED},
+	})
+	require.NoError(t, err, "Expected InsertTask to not return an error")
+	assert.True(t, insertedTask.HasAlert(model.ALERT_JOB_FAILED))
+
+	insertedTask.Alerts = []string{model.ALERT_WORKER_STALE, model.ALERT_SCHEDULE_MISSED}
+	_, err = taskDbHandler.UpdateTask(insertedTask)
+	require.NoError(t, err, "Expected UpdateTask to not return an error")
+
+	selectedTask, err := taskDbHandler.SelectTaskByKey("test_task_alerts")
+	require.NoError(t, err, "Expected SelectTaskByKey to not return an error")
+	assert.Equal(t, []string{model.ALERT_WORKER_STALE, model.ALERT_SCHEDULE_MISSED}, selectedTask.Alerts)
+	assert.False(t, selectedTask.HasAlert(model.ALERT_JOB_FAILED))
+
+	otherTask, err := taskDbHandler.InsertTask(&model.Task{Key: "test_task_no_alerts", Name: "Test Task No Alerts"})
+	require.NoError(t, err, "Expected InsertTask to not return an error")
+	assert.Empty(t, otherTask.Alerts, "Expected a task without alerts to have no alerts")
+}
+
 func TestTaskUpdateTaskNonExistent(t *testing.T) {
 	helper.SetTestDatabaseConfigEnvs(t, dbPort)
 	dbConfig, err := helper.NewDatabaseConfiguration()
@@ -192,6 +259,70 @@ func TestTaskUpdateTaskNonExistent(t *testing.T) {
 	assert.Contains(t, err.Error(), "task not found", "Expected error message to contain 'task not found'")
 }
 
+func TestTaskUpsertTask(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	taskDbHandler, err := NewTaskDBHandler(database, true)
+	require.NoError(t, err, "Expected NewTaskDBHandler to not return an error")
+
+	task := &model.Task{
+		Key:                  "test_task_upsert",
+		Name:                 "Test Task Upsert",
+		InputParameters:      []vm.Validation{{Key: "input", Type: vm.String, Requirement: "min1"}},
+		InputParametersKeyed: []vm.Validation{},
+		OutputParameters:     []vm.Validation{},
+		WorkerVersionPolicy:  model.WORKER_VERSION_POLICY_WARN,
+		Owner:                "team-data",
+	}
+
+	createdTask, created, err := taskDbHandler.UpsertTask(task)
+	require.NoError(t, err, "Expected UpsertTask to not return an error")
+	assert.True(t, created, "Expected UpsertTask to create the task")
+	assert.Equal(t, "team-data", createdTask.Owner, "Expected owner to be stored")
+
+	unchangedTask, created, err := taskDbHandler.UpsertTask(task)
+	require.NoError(t, err, "Expected UpsertTask to not return an error")
+	assert.False(t, created, "Expected UpsertTask to update the existing task")
+	assert.Equal(t, createdTask.RID, unchangedTask.RID, "Expected the RID to be stable")
+	assert.Equal(t, createdTask.UpdatedAt, unchangedTask.UpdatedAt, "Expected UpdatedAt to be unchanged without changes")
+
+	task.Name = "Updated Task Upsert"
+	updatedTask, created, err := taskDbHandler.UpsertTask(task)
+	require.NoError(t, err, "Expected UpsertTask to not return an error")
+	assert.False(t, created, "Expected UpsertTask to update the existing task")
+	assert.Equal(t, createdTask.RID, updatedTask.RID, "Expected the RID to be stable")
+	assert.Equal(t, "Updated Task Upsert", updatedTask.Name, "Expected updated task name to match")
+	assert.True(t, updatedTask.UpdatedAt.After(createdTask.UpdatedAt), "Expected UpdatedAt to be after original")
+}
+
+func TestTaskDeleteTaskByKey(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	taskDbHandler, err := NewTaskDBHandler(database, true)
+	require.NoError(t, err, "Expected NewTaskDBHandler to not return an error")
+
+	_, err = taskDbHandler.InsertTask(&model.Task{Key: "test_task_delete_by_key", Name: "Test Task Delete By Key"})
+	require.NoError(t, err, "Expected InsertTask to not return an error")
+
+	deleted, err := taskDbHandler.DeleteTaskByKey("test_task_delete_by_key")
+	assert.NoError(t, err, "Expected DeleteTaskByKey to not return an error")
+	assert.True(t, deleted, "Expected DeleteTaskByKey to delete the task")
+
+	deleted, err = taskDbHandler.DeleteTaskByKey("test_task_delete_by_key")
+	assert.NoError(t, err, "Expected DeleteTaskByKey to not return an error for a deleted task")
+	assert.False(t, deleted, "Expected DeleteTaskByKey to report that no task was deleted")
+}
+
 func TestTaskDeleteTask(t *testing.T) {
 	helper.SetTestDatabaseConfigEnvs(t, dbPort)
 	dbConfig, err := helper.NewDatabaseConfiguration()
@@ -373,3 +504,202 @@ func TestTaskSelectAllTasksWithPagination(t *testing.T) {
 	// Verify no overlap
 	assert.NotEqual(t, firstPage[0].ID, secondPage[0].ID, "Expected different tasks in different pages")
 }
+
+func TestTaskSelectAllTasksByParameter(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	taskDbHandler, err := NewTaskDBHandler(database, true)
+	require.NoError(t, err, "Expected NewTaskDBHandler to not return an error")
+
+	tasks := []*model.Task{
+		{Key: "parameter_task_input", Name: "Input", InputParameters: []vm.Validation{{Key: "customer_id", Type: vm.Int}}},
+		{Key: "parameter_task_keyed", Name: "Keyed", InputParametersKeyed: []vm.Validation{{Key: "customer_id", Type: vm.Int}}},
+		{Key: "parameter_task_output", Name: "Output", OutputParameters: []vm.Validation{{Key: "customer_id", Type: vm.Int}}},
+		{Key: "parameter_task_other", Name: "Other", InputParameters: []vm.Validation{{Key: "customer_ids", Type: vm.Array}}},
+	}
+	for _, task := range tasks {
+		_, err := taskDbHandler.InsertTask(task)
+		require.NoError(t, err, "Expected InsertTask to not return an error")
+	}
+
+	foundTasks, err := taskDbHandler.SelectAllTasksByParameter("customer_id", 0, 10)
+	require.NoError(t, err, "Expected SelectAllTasksByParameter to not return an error")
+	require.Len(t, foundTasks, 3, "Expected only the tasks with the exact parameter key")
+	assert.Equal(t, "parameter_task_input", foundTasks[0].Key)
+	assert.Equal(t, "parameter_task_keyed", foundTasks[1].Key)
+	assert.Equal(t, "parameter_task_output", foundTasks[2].Key)
+
+	nextPage, err := taskDbHandler.SelectAllTasksByParameter("customer_id", foundTasks[1].ID, 10)
+	require.NoError(t, err, "Expected SelectAllTasksByParameter to not return an error")
+	require.Len(t, nextPage, 1, "Expected the tasks after the last ID")
+	assert.Equal(t, "parameter_task_output", nextPage[0].Key)
+
+	noTasks, err := taskDbHandler.SelectAllTasksByParameter("unknown", 0, 10)
+	require.NoError(t, err, "Expected SelectAllTasksByParameter to not return an error")
+	assert.Empty(t, noTasks, "Expected no tasks for an unknown parameter key")
+}
+
+func TestTaskSelectAllTasksByFilter(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	taskDbHandler, err := NewTaskDBHandler(database, true)
+	require.NoError(t, err, "Expected NewTaskDBHandler to not return an error")
+
+	tasks := []*model.Task{
+		{Key: "report_daily", Name: "B Daily", OutputParameters: []vm.Validation{{Key: "rows", Type: vm.Int}}},
+		{Key: "report_weekly", Name: "A Weekly"},
+		{Key: "report_monthly", Name: "C Monthly", OutputParameters: []vm.Validation{{Key: "rows", Type: vm.Int}}},
+		{Key: "reportXother", Name: "D Other"},
+	}
+	for _, task := range tasks {
+		_, err := taskDbHandler.InsertTask(task)
+		require.NoError(t, err, "Expected InsertTask to not return an error")
+	}
+
+	t.Run("Sort by name descending with pagination", func(t *testing.T) {
+		filter := &model.TaskFilter{Sort: model.TASK_SORT_NAME, Descending: true}
+		foundTasks, err := taskDbHandler.SelectAllTasksByFilter(filter, 0, 2)
+		require.NoError(t, err, "Expected SelectAllTasksByFilter to not return an error")
+		require.Len(t, foundTasks, 2)
+		assert.Equal(t, "reportXother", foundTasks[0].Key)
+		assert.Equal(t, "report_monthly", foundTasks[1].Key)
+
+		nextPage, err := taskDbHandler.SelectAllTasksByFilter(filter, foundTasks[1].ID, 2)
+		require.NoError(t, err, "Expected SelectAllTasksByFilter to not return an error")
+		require.Len(t, nextPage, 2)
+		assert.Equal(t, "report_daily", nextPage[0].Key)
+		assert.Equal(t, "report_weekly", nextPage[1].Key)
+	})
+
+	t.Run("Key prefix matches wildcards literally", func(t *testing.T) {
+		filter := &model.TaskFilter{KeyPrefix: "report_", Sort: model.TASK_SORT_KEY}
+		foundTasks, err := taskDbHandler.SelectAllTasksByFilter(filter, 0, 10)
+		require.NoError(t, err, "Expected SelectAllTasksByFilter to not return an error")
+		require.Len(t, foundTasks, 3)
+		assert.Equal(t, "report_daily", foundTasks[0].Key)
+
+		count, err := taskDbHandler.SelectAllTasksByFilterCount(filter)
+		require.NoError(t, err, "Expected SelectAllTasksByFilterCount to not return an error")
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("With output parameters and creation time", func(t *testing.T) {
+		filter := &model.TaskFilter{WithOutputParameters: true, CreatedAfter: time.Now().Add(-time.Hour)}
+		foundTasks, err := taskDbHandler.SelectAllTasksByFilter(filter, 0, 10)
+		require.NoError(t, err, "Expected SelectAllTasksByFilter to not return an error")
+		require.Len(t, foundTasks, 2)
+		assert.Equal(t, "report_daily", foundTasks[0].Key)
+		assert.Equal(t, "report_monthly", foundTasks[1].Key)
+
+		foundTasks, err = taskDbHandler.SelectAllTasksByFilter(&model.TaskFilter{CreatedBefore: time.Now().Add(-time.Hour)}, 0, 10)
+		require.NoError(t, err, "Expected SelectAllTasksByFilter to not return an error")
+		assert.Empty(t, foundTasks, "Expected no tasks created before an hour ago")
+	})
+
+	t.Run("Invalid sort", func(t *testing.T) {
+		_, err := taskDbHandler.SelectAllTasksByFilter(&model.TaskFilter{Sort: "owner"}, 0, 10)
+		assert.Error(t, err, "Expected SelectAllTasksByFilter to return an error for an invalid sort")
+	})
+}
+
+func TestTaskNamespaces(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	taskDbHandler, err := NewTaskDBHandler(database, true)
+	require.NoError(t, err, "Expected NewTaskDBHandler to not return an error")
+
+	tasks := []*model.Task{
+		{Key: "billing_invoice", Name: "Invoice", Namespace: "billing"},
+		{Key: "billing_refund", Name: "Refund", Namespace: "billing"},
+		{Key: "data_export", Name: "Export", Namespace: "data"},
+		{Key: "shared_cleanup", Name: "Cleanup"},
+	}
+	for _, task := range tasks {
+		insertedTask, err := taskDbHandler.InsertTask(task)
+		require.NoError(t, err, "Expected InsertTask to not return an error")
+		assert.Equal(t, task.Namespace, insertedTask.Namespace)
+	}
+
+	t.Run("Select the namespaces", func(t *testing.T) {
+		namespaces, err := taskDbHandler.SelectNamespaces()
+		require.NoError(t, err, "Expected SelectNamespaces to not return an error")
+		assert.Equal(t, []string{"billing", "data"}, namespaces)
+	})
+
+	t.Run("Filter by namespace", func(t *testing.T) {
+		filter := &model.TaskFilter{Namespace: "billing", Sort: model.TASK_SORT_KEY}
+		foundTasks, err := taskDbHandler.SelectAllTasksByFilter(filter, 0, 10)
+		require.NoError(t, err, "Expected SelectAllTasksByFilter to not return an error")
+		require.Len(t, foundTasks, 2)
+		assert.Equal(t, "billing_invoice", foundTasks[0].Key)
+
+		count, err := taskDbHandler.SelectAllTasksByFilterCount(filter)
+		require.NoError(t, err, "Expected SelectAllTasksByFilterCount to not return an error")
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("Search in a namespace", func(t *testing.T) {
+		foundTasks, err := taskDbHandler.SelectAllTasksBySearch("e", "data", 0, 10)
+		require.NoError(t, err, "Expected SelectAllTasksBySearch to not return an error")
+		require.Len(t, foundTasks, 1)
+		assert.Equal(t, "data_export", foundTasks[0].Key)
+	})
+
+	t.Run("Update the namespace", func(t *testing.T) {
+		task, err := taskDbHandler.SelectTaskByKey("shared_cleanup")
+		require.NoError(t, err)
+		task.Namespace = "data"
+		updatedTask, err := taskDbHandler.UpdateTask(task)
+		require.NoError(t, err, "Expected UpdateTask to not return an error")
+		assert.Equal(t, "data", updatedTask.Namespace)
+	})
+}
+
+func TestTaskSelectAllTasksCount(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	taskDbHandler, err := NewTaskDBHandler(database, true)
+	require.NoError(t, err, "Expected NewTaskDBHandler to not return an error")
+
+	tasks := []*model.Task{
+		{Key: "count_task_input", Name: "Input", InputParameters: []vm.Validation{{Key: "customer_id", Type: vm.Int}}},
+		{Key: "count_task_output", Name: "Output", OutputParameters: []vm.Validation{{Key: "customer_id", Type: vm.Int}}},
+		{Key: "count_task_other", Name: "Other"},
+	}
+	for _, task := range tasks {
+		_, err := taskDbHandler.InsertTask(task)
+		require.NoError(t, err, "Expected InsertTask to not return an error")
+	}
+
+	count, err := taskDbHandler.SelectAllTasksCount()
+	require.NoError(t, err, "Expected SelectAllTasksCount to not return an error")
+	assert.Equal(t, 3, count)
+
+	count, err = taskDbHandler.SelectAllTasksByParameterCount("customer_id")
+	require.NoError(t, err, "Expected SelectAllTasksByParameterCount to not return an error")
+	assert.Equal(t, 2, count)
+
+	count, err = taskDbHandler.SelectAllTasksByParameterCount("unknown")
+	require.NoError(t, err, "Expected SelectAllTasksByParameterCount to not return an error")
+	assert.Equal(t, 0, count)
+}
@@ -0,0 +1,388 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+)
+
+// UserDBHandlerFunctions defines the interface for User database operations.
+type UserDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	UpsertUser(user *model.User) (*model.User, error)
+	UpdateUser(user *model.User) (*model.User, error)
+	SelectUser(rid uuid.UUID) (*model.User, error)
+	SelectUserByUsername(username string) (*model.User, error)
+	SelectAllUsers(lastID int, entries int) ([]*model.User, error)
+}
+
+// UserDBHandler implements UserDBHandlerFunctions and holds the database connection.
+type UserDBHandler struct {
+	db *helper.Database
+}
+
+// NewUserDBHandler creates a new instance of UserDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing manager_user table before creating a new one
+func NewUserDBHandler(dbConnection *helper.Database, withTableDrop bool) (*UserDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	userDbHandler := &UserDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := userDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := userDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return userDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'manager_user' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r UserDBHandler) CheckTableExistance() (bool, error) {
+	userExists, err := r.db.CheckTableExistance("manager_user")
+	if err != nil {
+		return false, helper.NewError("manager_user table", err)
+	}
+	return userExists, nil
+}
+
+// CreateTable creates the 'manager_user' table in the database ('user' is reserved in postgres).
+// If the table already exists, it does not create it again.
+func (r UserDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS manager_user (
+			id SERIAL PRIMARY KEY,
+			rid UUID UNIQUE NOT NULL DEFAULT gen_random_uuid(),
+			username VARCHAR(255) UNIQUE NOT NULL,
+			name VARCHAR(255) NOT NULL DEFAULT '',
+			email VARCHAR(255) NOT NULL DEFAULT '',
+			role VARCHAR(20) NOT NULL DEFAULT '',
+			groups JSONB NOT NULL DEFAULT '[]'::jsonb,
+			source VARCHAR(20) NOT NULL DEFAULT '',
+			last_login_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create manager_user table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table manager_user")
+
+	return nil
+}
+
+// DropTable drops the 'manager_user' table from the database.
+func (r UserDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS manager_user`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop manager_user table", err)
+	}
+
+	r.db.Logger.Info("Dropped table manager_user")
+
+	return nil
+}
+
+// UpsertUser inserts a user or updates the user with the same username.
+// The last login is only updated if it is set, so syncing a user keeps the last login.
+func (r UserDBHandler) UpsertUser(user *model.User) (*model.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	groups := user.Groups
+	if groups == nil {
+		groups = []string{}
+	}
+	groupsJSON, err := json.Marshal(groups)
+	if err != nil {
+		return nil, helper.NewError("marshal groups", err)
+	}
+
+	query := `
+		INSERT INTO manager_user (
+			external_id,
+			username,
+			name,
+			email,
+			role,
+			groups,
+			source,
+			active,
+			last_login_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (username) DO UPDATE
+		SET
+			external_id = EXCLUDED.external_id,
+			name = EXCLUDED.name,
+			email = EXCLUDED.email,
+			role = EXCLUDED.role,
+			groups = EXCLUDED.groups,
+			source = EXCLUDED.source,
+			active = EXCLUDED.active,
+			last_login_at = COALESCE(EXCLUDED.last_login_at, manager_user.last_login_at),
+			updated_at = NOW()
+		RETURNING
+			id,
+			rid,
+			external_id,
+			username,
+			name,
+			email,
+			role,
+			groups,
+			source,
+			active,
+			last_login_at,
+			created_at,
+			updated_at`
+
+	row := r.db.Instance.QueryRowContext(ctx, query, user.ExternalID, user.Username, user.Name, user.Email, user.Role, groupsJSON, user.Source, user.Active, user.LastLoginAt)
+	upsertedUser, err := scanUser(row)
+	if err != nil {
+		return nil, helper.NewError("upsert user", err)
+	}
+
+	return upsertedUser, nil
+}
+
+// UpdateUser updates a user by RID, in contrast to UpsertUser the username can be changed.
+func (r UserDBHandler) UpdateUser(user *model.User) (*model.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	groups := user.Groups
+	if groups == nil {
+		groups = []string{}
+	}
+	groupsJSON, err := json.Marshal(groups)
+	if err != nil {
+		return nil, helper.NewError("marshal groups", err)
+	}
+
+	query := `
+		UPDATE manager_user
+		SET
+			external_id = $1,
+			username = $2,
+			name = $3,
+			email = $4,
+			role = $5,
+			groups = $6,
+			source = $7,
+			active = $8,
+			updated_at = NOW()
+		WHERE rid = $9
+		RETURNING
+			id,
+			rid,
+			external_id,
+			username,
+			name,
+			email,
+			role,
+			groups,
+			source,
+			active,
+			last_login_at,
+			created_at,
+			updated_at`
+
+	row := r.db.Instance.QueryRowContext(ctx, query, user.ExternalID, user.Username, user.Name, user.Email, user.Role, groupsJSON, user.Source, user.Active, user.RID)
+	updatedUser, err := scanUser(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("user not found", fmt.Errorf("no user with rid %s", user.RID))
+		}
+		return nil, helper.NewError("update user", err)
+	}
+
+	return updatedUser, nil
+}
+
+// SelectUser retrieves a user by RID from the database.
+func (r UserDBHandler) SelectUser(rid uuid.UUID) (*model.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			rid,
+			external_id,
+			username,
+			name,
+			email,
+			role,
+			groups,
+			source,
+			active,
+			last_login_at,
+			created_at,
+			updated_at
+		FROM manager_user
+		WHERE rid = $1
+	`
+
+	user, err := scanUser(r.db.Instance.QueryRowContext(ctx, query, rid))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("user not found", fmt.Errorf("no user with rid %s", rid))
+		}
+		return nil, helper.NewError("select user", err)
+	}
+
+	return user, nil
+}
+
+// SelectUserByUsername retrieves a user by username from the database.
+func (r UserDBHandler) SelectUserByUsername(username string) (*model.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			rid,
+			external_id,
+			username,
+			name,
+			email,
+			role,
+			groups,
+			source,
+			active,
+			last_login_at,
+			created_at,
+			updated_at
+		FROM manager_user
+		WHERE username = $1
+	`
+
+	user, err := scanUser(r.db.Instance.QueryRowContext(ctx, query, username))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helper.NewError("user not found", fmt.Errorf("no user with username %s", username))
+		}
+		return nil, helper.NewError("select user by username", err)
+	}
+
+	return user, nil
+}
+
+// SelectAllUsers retrieves all users from the database with pagination.
+// lastID is the ID of the last user from the previous page (0 for first page)
+// entries is the maximum number of users to return
+func (r UserDBHandler) SelectAllUsers(lastID int, entries int) ([]*model.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			rid,
+			external_id,
+			username,
+			name,
+			email,
+			role,
+			groups,
+			source,
+			active,
+			last_login_at,
+			created_at,
+			updated_at
+		FROM manager_user
+		WHERE id > $1
+		ORDER BY id ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, lastID, entries)
+	if err != nil {
+		return nil, helper.NewError("select all users", err)
+	}
+	defer rows.Close()
+
+	users := []*model.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, helper.NewError("scan user", err)
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return users, nil
+}
+
+// scanUser scans a user row in the column order of the user queries.
+func scanUser(row interface{ Scan(dest ...any) error }) (*model.User, error) {
+	user := &model.User{}
+	var groupsData []byte
+	var lastLoginAt sql.NullTime
+	err := row.Scan(
+		&user.ID,
+		&user.RID,
+		&user.ExternalID,
+		&user.Username,
+		&user.Name,
+		&user.Email,
+		&user.Role,
+		&groupsData,
+		&user.Source,
+		&user.Active,
+		&lastLoginAt,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastLoginAt.Valid {
+		user.LastLoginAt = &lastLoginAt.Time
+	}
+
+	err = json.Unmarshal(groupsData, &user.Groups)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal groups: %w", err)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserNewUserDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewUserDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		userDbHandler, err := NewUserDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewUserDBHandler to not return an error")
+		require.NotNil(t, userDbHandler, "Expected NewUserDBHandler to return a non-nil instance")
+
+		exists, err := userDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = userDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewUserDBHandler with nil database", func(t *testing.T) {
+		_, err := NewUserDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating UserDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestUserUpsertAndSelectUser(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	userDbHandler, err := NewUserDBHandler(database, true)
+	require.NoError(t, err, "Expected NewUserDBHandler to not return an error")
+
+	lastLogin := time.Now()
+	insertedUser, err := userDbHandler.UpsertUser(&model.User{
+		Username:    "jdoe",
+		Name:        "John Doe",
+		Email:       "jdoe@example.com",
+		Role:        model.ROLE_OPERATOR,
+		Groups:      []string{"cn=queuer-operators,ou=groups,dc=example,dc=com"},
+		Source:      model.USER_SOURCE_LDAP,
+		Active:      true,
+		LastLoginAt: &lastLogin,
+	})
+	require.NoError(t, err, "Expected UpsertUser to not return an error")
+	assert.Equal(t, model.ROLE_OPERATOR, insertedUser.Role)
+	assert.Len(t, insertedUser.Groups, 1)
+	require.NotNil(t, insertedUser.LastLoginAt, "Expected the last login to be set")
+
+	// Sync without login keeps the user RID and last login
+	syncedUser, err := userDbHandler.UpsertUser(&model.User{
+		Username: "jdoe",
+		Name:     "John Doe",
+		Role:     model.ROLE_ADMIN,
+		Source:   model.USER_SOURCE_LDAP,
+	})
+	require.NoError(t, err, "Expected UpsertUser to not return an error")
+	assert.Equal(t, insertedUser.RID, syncedUser.RID, "Expected the RID to be stable")
+	assert.Equal(t, model.ROLE_ADMIN, syncedUser.Role)
+	assert.Empty(t, syncedUser.Groups)
+	require.NotNil(t, syncedUser.LastLoginAt, "Expected the last login to be kept")
+
+	selectedUser, err := userDbHandler.SelectUser(insertedUser.RID)
+	require.NoError(t, err, "Expected SelectUser to not return an error")
+	assert.Equal(t, "jdoe", selectedUser.Username)
+
+	selectedUser, err = userDbHandler.SelectUserByUsername("jdoe")
+	require.NoError(t, err, "Expected SelectUserByUsername to not return an error")
+	assert.Equal(t, insertedUser.RID, selectedUser.RID)
+
+	_, err = userDbHandler.SelectUserByUsername("unknown")
+	assert.Error(t, err, "Expected SelectUserByUsername to return an error for an unknown user")
+
+	users, err := userDbHandler.SelectAllUsers(0, 10)
+	require.NoError(t, err, "Expected SelectAllUsers to not return an error")
+	assert.Len(t, users, 1)
+}
+
+func TestUserUpdateUser(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	userDbHandler, err := NewUserDBHandler(database, true)
+	require.NoError(t, err, "Expected NewUserDBHandler to not return an error")
+
+	insertedUser, err := userDbHandler.UpsertUser(&model.User{
+		ExternalID: "00u1",
+		Username:   "jdoe",
+		Source:     model.USER_SOURCE_SCIM,
+		Active:     true,
+	})
+	require.NoError(t, err, "Expected UpsertUser to not return an error")
+	assert.True(t, insertedUser.Active)
+
+	insertedUser.Username = "john.doe"
+	insertedUser.Active = false
+	updatedUser, err := userDbHandler.UpdateUser(insertedUser)
+	require.NoError(t, err, "Expected UpdateUser to not return an error")
+	assert.Equal(t, insertedUser.RID, updatedUser.RID)
+	assert.Equal(t, "john.doe", updatedUser.Username)
+	assert.Equal(t, "00u1", updatedUser.ExternalID)
+	assert.False(t, updatedUser.Active)
+
+	insertedUser.RID = uuid.New()
+	_, err = userDbHandler.UpdateUser(insertedUser)
+	assert.Error(t, err, "Expected UpdateUser to return an error for an unknown user")
+}
@@ -0,0 +1,244 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+)
+
+// WatchDBHandlerFunctions defines the interface for Watch database operations.
+type WatchDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	UpsertWatch(watch *model.Watch) (*model.Watch, error)
+	DeleteWatch(username string, targetType string, target string) (bool, error)
+	SelectWatchesByUser(username string) ([]*model.Watch, error)
+	SelectWatchesOfJob(jobRID uuid.UUID, taskKey string) ([]*model.Watch, error)
+}
+
+// WatchDBHandler implements WatchDBHandlerFunctions and holds the database connection.
+type WatchDBHandler struct {
+	db *helper.Database
+}
+
+// NewWatchDBHandler creates a new instance of WatchDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing watch table before creating a new one
+func NewWatchDBHandler(dbConnection *helper.Database, withTableDrop bool) (*WatchDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	watchDbHandler := &WatchDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := watchDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := watchDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return watchDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'watch' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r WatchDBHandler) CheckTableExistance() (bool, error) {
+	watchExists, err := r.db.CheckTableExistance("watch")
+	if err != nil {
+		return false, helper.NewError("watch table", err)
+	}
+	return watchExists, nil
+}
+
+// CreateTable creates the 'watch' table in the database.
+// If the table already exists, it does not create it again.
+func (r WatchDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS watch (
+			id SERIAL PRIMARY KEY,
+			username VARCHAR(255) NOT NULL,
+			target_type VARCHAR(20) NOT NULL,
+			target VARCHAR(100) NOT NULL,
+			email BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			UNIQUE (username, target_type, target)
+		);
+		CREATE INDEX IF NOT EXISTS idx_watch_target ON watch (target_type, target);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create watch table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table watch")
+
+	return nil
+}
+
+// DropTable drops the 'watch' table from the database.
+func (r WatchDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS watch`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop watch table", err)
+	}
+
+	r.db.Logger.Info("Dropped table watch")
+
+	return nil
+}
+
+// UpsertWatch adds the watch of a user, watching a target again updates if the user is notified by email.
+func (r WatchDBHandler) UpsertWatch(watch *model.Watch) (*model.Watch, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		INSERT INTO watch (
+			username,
+			target_type,
+			target,
+			email
+		) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (username, target_type, target) DO UPDATE
+		SET
+			email = EXCLUDED.email
+		RETURNING
+			id,
+			username,
+			target_type,
+			target,
+			email,
+			created_at`
+
+	upsertedWatch, err := scanWatch(r.db.Instance.QueryRowContext(ctx, query, watch.Username, watch.TargetType, watch.Target, watch.Email))
+	if err != nil {
+		return nil, helper.NewError("upsert watch", err)
+	}
+
+	return upsertedWatch, nil
+}
+
+// DeleteWatch deletes the watch of a user on a target, it returns false if the user did not watch the target.
+func (r WatchDBHandler) DeleteWatch(username string, targetType string, target string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DELETE FROM watch WHERE username = $1 AND target_type = $2 AND target = $3`
+	result, err := r.db.Instance.ExecContext(ctx, query, username, targetType, target)
+	if err != nil {
+		return false, helper.NewError("delete watch", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, helper.NewError("rows affected", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// SelectWatchesByUser retrieves the watches of a user, the latest first.
+func (r WatchDBHandler) SelectWatchesByUser(username string) ([]*model.Watch, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			username,
+			target_type,
+			target,
+			email,
+			created_at
+		FROM watch
+		WHERE username = $1
+		ORDER BY created_at DESC, id DESC
+	`
+
+	return r.selectWatches(ctx, query, username)
+}
+
+// SelectWatchesOfJob retrieves the watches of the job and of the task of the job.
+func (r WatchDBHandler) SelectWatchesOfJob(jobRID uuid.UUID, taskKey string) ([]*model.Watch, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			username,
+			target_type,
+			target,
+			email,
+			created_at
+		FROM watch
+		WHERE (target_type = $1 AND target = $2)
+			OR (target_type = $3 AND target = $4)
+		ORDER BY id ASC
+	`
+
+	return r.selectWatches(ctx, query, model.WATCH_TARGET_JOB, jobRID.String(), model.WATCH_TARGET_TASK, taskKey)
+}
+
+// selectWatches retrieves the watches of a query in the column order of the watch queries.
+func (r WatchDBHandler) selectWatches(ctx context.Context, query string, args ...any) ([]*model.Watch, error) {
+	rows, err := r.db.Instance.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, helper.NewError("select watches", err)
+	}
+	defer rows.Close()
+
+	watches := []*model.Watch{}
+	for rows.Next() {
+		watch, err := scanWatch(rows)
+		if err != nil {
+			return nil, helper.NewError("scan watch", err)
+		}
+		watches = append(watches, watch)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, helper.NewError("rows iteration", err)
+	}
+
+	return watches, nil
+}
+
+// scanWatch scans a watch row in the column order of the watch queries.
+func scanWatch(row interface{ Scan(dest ...any) error }) (*model.Watch, error) {
+	watch := &model.Watch{}
+	err := row.Scan(
+		&watch.ID,
+		&watch.Username,
+		&watch.TargetType,
+		&watch.Target,
+		&watch.Email,
+		&watch.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return watch, nil
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchNewWatchDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewWatchDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		watchDbHandler, err := NewWatchDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewWatchDBHandler to not return an error")
+		require.NotNil(t, watchDbHandler, "Expected NewWatchDBHandler to return a non-nil instance")
+
+		exists, err := watchDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = watchDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewWatchDBHandler with nil database", func(t *testing.T) {
+		_, err := NewWatchDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating WatchDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestWatchSelectWatchesOfJob(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	watchDbHandler, err := NewWatchDBHandler(database, true)
+	require.NoError(t, err, "Expected NewWatchDBHandler to not return an error")
+
+	jobRID := uuid.New()
+	_, err = watchDbHandler.UpsertWatch(&model.Watch{Username: "alice", TargetType: model.WATCH_TARGET_JOB, Target: jobRID.String()})
+	require.NoError(t, err, "Expected UpsertWatch to not return an error")
+	_, err = watchDbHandler.UpsertWatch(&model.Watch{Username: "bob", TargetType: model.WATCH_TARGET_TASK, Target: "export"})
+	require.NoError(t, err, "Expected UpsertWatch to not return an error")
+	_, err = watchDbHandler.UpsertWatch(&model.Watch{Username: "carol", TargetType: model.WATCH_TARGET_TASK, Target: "import"})
+	require.NoError(t, err, "Expected UpsertWatch to not return an error")
+
+	watch, err := watchDbHandler.UpsertWatch(&model.Watch{Username: "bob", TargetType: model.WATCH_TARGET_TASK, Target: "export", Email: true})
+	require.NoError(t, err, "Expected UpsertWatch to not return an error")
+	assert.True(t, watch.Email, "Expected watching a target again to update the email option")
+
+	watches, err := watchDbHandler.SelectWatchesOfJob(jobRID, "export")
+	require.NoError(t, err, "Expected SelectWatchesOfJob to not return an error")
+	require.Len(t, watches, 2, "Expected the watches of the job and of its task")
+	assert.Equal(t, "alice", watches[0].Username)
+	assert.Equal(t, "bob", watches[1].Username)
+
+	watches, err = watchDbHandler.SelectWatchesByUser("bob")
+	require.NoError(t, err, "Expected SelectWatchesByUser to not return an error")
+	assert.Len(t, watches, 1)
+
+	deleted, err := watchDbHandler.DeleteWatch("alice", model.WATCH_TARGET_JOB, jobRID.String())
+	require.NoError(t, err, "Expected DeleteWatch to not return an error")
+	assert.True(t, deleted)
+
+	deleted, err = watchDbHandler.DeleteWatch("alice", model.WATCH_TARGET_JOB, jobRID.String())
+	require.NoError(t, err, "Expected DeleteWatch to not return an error")
+	assert.False(t, deleted, "Expected a deleted watch to not be deleted again")
+
+	watches, err = watchDbHandler.SelectWatchesOfJob(jobRID, "export")
+	require.NoError(t, err, "Expected SelectWatchesOfJob to not return an error")
+	assert.Len(t, watches, 1)
+}
@@ -0,0 +1,181 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+)
+
+// WorkerVersionDBHandlerFunctions defines the interface for WorkerVersion database operations.
+type WorkerVersionDBHandlerFunctions interface {
+	CheckTableExistance() (bool, error)
+	CreateTable() error
+	DropTable() error
+	UpsertWorkerVersion(workerVersion *model.WorkerVersion) (*model.WorkerVersion, error)
+	SelectWorkerVersions(workerRIDs []uuid.UUID) (map[uuid.UUID]string, error)
+}
+
+// WorkerVersionDBHandler implements WorkerVersionDBHandlerFunctions and holds the database connection.
+type WorkerVersionDBHandler struct {
+	db *helper.Database
+}
+
+// NewWorkerVersionDBHandler creates a new instance of WorkerVersionDBHandler.
+// It initializes the database connection and optionally drops existing tables.
+// If withTableDrop is true, it will drop the existing worker_version table before creating a new one
+func NewWorkerVersionDBHandler(dbConnection *helper.Database, withTableDrop bool) (*WorkerVersionDBHandler, error) {
+	if dbConnection == nil {
+		return nil, helper.NewError("database connection validation", fmt.Errorf("database connection is nil"))
+	}
+
+	workerVersionDbHandler := &WorkerVersionDBHandler{
+		db: dbConnection,
+	}
+
+	if withTableDrop {
+		err := workerVersionDbHandler.DropTable()
+		if err != nil {
+			return nil, helper.NewError("drop table", err)
+		}
+	}
+
+	err := workerVersionDbHandler.CreateTable()
+	if err != nil {
+		return nil, helper.NewError("create table", err)
+	}
+
+	return workerVersionDbHandler, nil
+}
+
+// CheckTableExistance checks if the 'worker_version' table exists in the database.
+// It returns true if the table exists, otherwise false.
+func (r WorkerVersionDBHandler) CheckTableExistance() (bool, error) {
+	workerVersionExists, err := r.db.CheckTableExistance("worker_version")
+	if err != nil {
+		return false, helper.NewError("worker_version table", err)
+	}
+	return workerVersionExists, nil
+}
+
+// CreateTable creates the 'worker_version' table in the database.
+// If the table already exists, it does not create it again.
+func (r WorkerVersionDBHandler) CreateTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		CREATE TABLE IF NOT EXISTS worker_version (
+			worker_rid UUID PRIMARY KEY,
+			version VARCHAR(50) NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+	`
+
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("create worker_version table", err)
+	}
+
+	r.db.Logger.Info("Checked/created table worker_version")
+
+	return nil
+}
+
+// DropTable drops the 'worker_version' table from the database.
+func (r WorkerVersionDBHandler) DropTable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `DROP TABLE IF EXISTS worker_version`
+	_, err := r.db.Instance.ExecContext(ctx, query)
+	if err != nil {
+		return helper.NewError("drop worker_version table", err)
+	}
+
+	r.db.Logger.Info("Dropped table worker_version")
+
+	return nil
+}
+
+// UpsertWorkerVersion inserts the version of a worker or updates the version the worker reported before.
+func (r WorkerVersionDBHandler) UpsertWorkerVersion(workerVersion *model.WorkerVersion) (*model.WorkerVersion, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		INSERT INTO worker_version (
+			worker_rid,
+			version
+		) VALUES ($1, $2)
+		ON CONFLICT (worker_rid) DO UPDATE SET
+			version = EXCLUDED.version,
+			updated_at = NOW()
+		RETURNING
+			worker_rid,
+			version,
+			updated_at`
+
+	upsertedWorkerVersion := &model.WorkerVersion{}
+	err := r.db.Instance.QueryRowContext(ctx, query, workerVersion.WorkerRID, workerVersion.Version).Scan(
+		&upsertedWorkerVersion.WorkerRID,
+		&upsertedWorkerVersion.Version,
+		&upsertedWorkerVersion.UpdatedAt,
+	)
+	if err != nil {
+		return nil, helper.NewError("upsert worker version", err)
+	}
+
+	return upsertedWorkerVersion, nil
+}
+
+// SelectWorkerVersions retrieves the versions of the workers by their RID, workers without a reported version are missing.
+func (r WorkerVersionDBHandler) SelectWorkerVersions(workerRIDs []uuid.UUID) (map[uuid.UUID]string, error) {
+	versions := map[uuid.UUID]string{}
+	if len(workerRIDs) == 0 {
+		return versions, nil
+	}
+
+	workerRIDsJSON, err := json.Marshal(workerRIDs)
+	if err != nil {
+		return nil, helper.NewError("marshal worker rids", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT
+			worker_rid,
+			version
+		FROM worker_version
+		WHERE worker_rid IN (SELECT jsonb_array_elements_text($1::jsonb)::uuid)`
+
+	rows, err := r.db.Instance.QueryContext(ctx, query, workerRIDsJSON)
+	if err != nil {
+		return nil, helper.NewError("select worker versions", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var workerRID uuid.UUID
+		var version string
+		err := rows.Scan(&workerRID, &version)
+		if err != nil {
+			return nil, helper.NewError("scan worker version", err)
+		}
+		versions[workerRID] = version
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, helper.NewError("rows error", err)
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerVersionNewWorkerVersionDBHandler(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+
+	t.Run("Valid call NewWorkerVersionDBHandler", func(t *testing.T) {
+		database := helper.NewTestDatabase(dbConfig)
+
+		workerVersionDbHandler, err := NewWorkerVersionDBHandler(database, true)
+		assert.NoError(t, err, "Expected NewWorkerVersionDBHandler to not return an error")
+		require.NotNil(t, workerVersionDbHandler, "Expected NewWorkerVersionDBHandler to return a non-nil instance")
+
+		exists, err := workerVersionDbHandler.CheckTableExistance()
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		err = workerVersionDbHandler.DropTable()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid call NewWorkerVersionDBHandler with nil database", func(t *testing.T) {
+		_, err := NewWorkerVersionDBHandler(nil, true)
+		assert.Error(t, err, "Expected error when creating WorkerVersionDBHandler with nil database")
+		assert.Contains(t, err.Error(), "database connection is nil", "Expected specific error message for nil database connection")
+	})
+}
+
+func TestWorkerVersionUpsertWorkerVersion(t *testing.T) {
+	helper.SetTestDatabaseConfigEnvs(t, dbPort)
+	dbConfig, err := helper.NewDatabaseConfiguration()
+	if err != nil {
+		t.Fatalf("failed to create database configuration: %v", err)
+	}
+	database := helper.NewTestDatabase(dbConfig)
+
+	workerVersionDbHandler, err := NewWorkerVersionDBHandler(database, true)
+	require.NoError(t, err, "Expected NewWorkerVersionDBHandler to not return an error")
+
+	workerRID := uuid.New()
+	otherWorkerRID := uuid.New()
+
+	t.Run("Update the version a worker reported before", func(t *testing.T) {
+		_, err := workerVersionDbHandler.UpsertWorkerVersion(&model.WorkerVersion{WorkerRID: workerRID, Version: "1.3.0"})
+		require.NoError(t, err, "Expected UpsertWorkerVersion to not return an error")
+
+		workerVersion, err := workerVersionDbHandler.UpsertWorkerVersion(&model.WorkerVersion{WorkerRID: workerRID, Version: "1.4.0"})
+		require.NoError(t, err, "Expected UpsertWorkerVersion to not return an error")
+		assert.Equal(t, workerRID, workerVersion.WorkerRID)
+		assert.Equal(t, "1.4.0", workerVersion.Version)
+	})
+
+	t.Run("Select the versions of the reporting workers", func(t *testing.T) {
+		versions, err := workerVersionDbHandler.SelectWorkerVersions([]uuid.UUID{workerRID, otherWorkerRID})
+		require.NoError(t, err, "Expected SelectWorkerVersions to not return an error")
+		assert.Equal(t, map[uuid.UUID]string{workerRID: "1.4.0"}, versions)
+
+		versions, err = workerVersionDbHandler.SelectWorkerVersions(nil)
+		require.NoError(t, err)
+		assert.Empty(t, versions)
+	})
+}
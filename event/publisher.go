@@ -0,0 +1,52 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/siherrmann/queuerManager/helper"
+	"github.com/siherrmann/queuerManager/model"
+)
+
+const (
+	PUBLISHER_MODE_NONE  = "none"
+	PUBLISHER_MODE_NATS  = "nats"
+	PUBLISHER_MODE_KAFKA = "kafka"
+)
+
+// Publisher publishes lifecycle events to a message broker
+type Publisher interface {
+	Publish(ctx context.Context, event *model.Event) error
+	Close() error
+}
+
+// CreatePublisherFromEnv creates a publisher based on environment variables.
+// It returns nil if no publisher is configured.
+func CreatePublisherFromEnv() (Publisher, error) {
+	publisherMode := strings.ToLower(helper.GetEnvOrDefault("QUEUER_MANAGER_EVENT_PUBLISHER", PUBLISHER_MODE_NONE))
+
+	switch publisherMode {
+	case PUBLISHER_MODE_NATS:
+		config := NATSConfig{
+			URL:     helper.GetEnvOrDefault("QUEUER_MANAGER_EVENT_NATS_URL", "nats://localhost:4222"),
+			Subject: helper.GetEnvOrDefault("QUEUER_MANAGER_EVENT_NATS_SUBJECT", "queuer.events"),
+			Token:   os.Getenv("QUEUER_MANAGER_EVENT_NATS_TOKEN"),
+		}
+		return NewPublisherNATS(config)
+	case PUBLISHER_MODE_KAFKA:
+		config := KafkaConfig{
+			RestProxyURL: os.Getenv("QUEUER_MANAGER_EVENT_KAFKA_REST_URL"),
+			Topic:        helper.GetEnvOrDefault("QUEUER_MANAGER_EVENT_KAFKA_TOPIC", "queuer-events"),
+		}
+		if config.RestProxyURL == "" {
+			return nil, fmt.Errorf("missing required kafka configuration: QUEUER_MANAGER_EVENT_KAFKA_REST_URL")
+		}
+		return NewPublisherKafka(config), nil
+	case PUBLISHER_MODE_NONE:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported event publisher: %s (supported: none, nats, kafka)", publisherMode)
+	}
+}
@@ -0,0 +1,70 @@
+package event
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+)
+
+// PublisherKafka publishes events to a Kafka topic through a Kafka REST proxy (v2 API)
+type PublisherKafka struct {
+	client *http.Client
+	config KafkaConfig
+}
+
+// KafkaConfig holds the configuration for the Kafka publisher
+type KafkaConfig struct {
+	RestProxyURL string // Base URL of the Kafka REST proxy
+	Topic        string // Topic all events are published to
+}
+
+// NewPublisherKafka creates a new Kafka publisher with the specified configuration
+func NewPublisherKafka(config KafkaConfig) *PublisherKafka {
+	return &PublisherKafka{
+		client: &http.Client{Timeout: 10 * time.Second},
+		config: config,
+	}
+}
+
+// Publish produces the event as JSON record keyed by the event type
+func (p *PublisherKafka) Publish(ctx context.Context, event *model.Event) error {
+	body, err := json.Marshal(map[string]any{
+		"records": []map[string]any{{"key": event.Type, "value": event}},
+	})
+	if err != nil {
+		return fmt.Errorf("error encoding event: %w", err)
+	}
+
+	topicURL := strings.TrimRight(p.config.RestProxyURL, "/") + "/topics/" + url.PathEscape(p.config.Topic)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, topicURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating kafka request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending kafka request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("kafka REST proxy responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// Close is a no-op, the Kafka publisher holds no open connection
+func (p *PublisherKafka) Close() error {
+	return nil
+}
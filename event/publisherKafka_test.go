@@ -0,0 +1,82 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublisherKafka(t *testing.T) {
+	t.Run("Events are produced as records keyed by their type", func(t *testing.T) {
+		var request *http.Request
+		var body map[string][]map[string]json.RawMessage
+		restProxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(data, &body))
+			request = r
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer restProxy.Close()
+
+		publisher := NewPublisherKafka(KafkaConfig{RestProxyURL: restProxy.URL + "/", Topic: "queuer events"})
+		err := publisher.Publish(context.Background(), model.NewEvent(model.EVENT_JOB_ENDED, map[string]any{"status": "FAILED"}))
+		require.NoError(t, err)
+
+		require.NotNil(t, request)
+		assert.Equal(t, http.MethodPost, request.Method)
+		assert.Equal(t, "/topics/queuer%20events", request.URL.EscapedPath())
+		assert.Equal(t, "application/vnd.kafka.json.v2+json", request.Header.Get("Content-Type"))
+		assert.Equal(t, "application/vnd.kafka.v2+json", request.Header.Get("Accept"))
+		require.Len(t, body["records"], 1)
+		assert.JSONEq(t, `"job.ended"`, string(body["records"][0]["key"]))
+
+		var published model.Event
+		require.NoError(t, json.Unmarshal(body["records"][0]["value"], &published))
+		assert.Equal(t, model.EVENT_JOB_ENDED, published.Type)
+		assert.Equal(t, map[string]any{"status": "FAILED"}, published.Data)
+	})
+
+	t.Run("Error responses of the proxy are returned", func(t *testing.T) {
+		restProxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"error_code":40403,"message":"Topic not found"}`, http.StatusNotFound)
+		}))
+		defer restProxy.Close()
+
+		publisher := NewPublisherKafka(KafkaConfig{RestProxyURL: restProxy.URL, Topic: "missing"})
+		err := publisher.Publish(context.Background(), model.NewEvent(model.EVENT_JOB_ADDED, nil))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "status 404")
+		assert.Contains(t, err.Error(), "Topic not found")
+	})
+}
+
+func TestCreatePublisherFromEnv(t *testing.T) {
+	t.Run("No publisher without configuration", func(t *testing.T) {
+		t.Setenv("QUEUER_MANAGER_EVENT_PUBLISHER", "")
+		publisher, err := CreatePublisherFromEnv()
+		require.NoError(t, err)
+		assert.Nil(t, publisher)
+	})
+
+	t.Run("Kafka requires the REST proxy URL", func(t *testing.T) {
+		t.Setenv("QUEUER_MANAGER_EVENT_PUBLISHER", "kafka")
+		t.Setenv("QUEUER_MANAGER_EVENT_KAFKA_REST_URL", "")
+		_, err := CreatePublisherFromEnv()
+		require.Error(t, err)
+	})
+
+	t.Run("Unsupported publishers are rejected", func(t *testing.T) {
+		t.Setenv("QUEUER_MANAGER_EVENT_PUBLISHER", "rabbitmq")
+		_, err := CreatePublisherFromEnv()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported event publisher")
+	})
+}
@@ -0,0 +1,191 @@
+package event
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+)
+
+// PublisherNATS publishes events to NATS subjects with the plain text NATS client protocol.
+// Events are published to <subject>.<event type>, eg. queuer.events.job.added.
+type PublisherNATS struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	config NATSConfig
+}
+
+// NATSConfig holds the configuration for the NATS publisher
+type NATSConfig struct {
+	URL     string // Server URL (nats:// or tls://)
+	Subject string // Subject prefix of all events
+	Token   string // Optional authentication token
+}
+
+// NewPublisherNATS creates a new NATS publisher and connects to the server
+func NewPublisherNATS(config NATSConfig) (*PublisherNATS, error) {
+	p := &PublisherNATS{config: config}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	err := p.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Publish sends the event as JSON message, the connection is reestablished once if it was lost
+func (p *PublisherNATS) Publish(ctx context.Context, event *model.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding event: %w", err)
+	}
+	subject := p.config.Subject + "." + event.Type
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	err = p.publish(ctx, subject, payload)
+	if err != nil {
+		p.closeConn()
+		err = p.connect()
+		if err != nil {
+			return err
+		}
+		err = p.publish(ctx, subject, payload)
+	}
+	return err
+}
+
+// Close closes the connection to the NATS server
+func (p *PublisherNATS) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closeConn()
+	return nil
+}
+
+// connect dials the server and sends the CONNECT handshake, p.mu has to be locked.
+func (p *PublisherNATS) connect() error {
+	serverURL, err := url.Parse(p.config.URL)
+	if err != nil {
+		return fmt.Errorf("invalid NATS URL: %w", err)
+	}
+
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	var conn net.Conn
+	if serverURL.Scheme == "tls" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", serverURL.Host, &tls.Config{ServerName: serverURL.Hostname(), MinVersion: tls.VersionTLS12})
+	} else {
+		conn, err = dialer.Dial("tcp", serverURL.Host)
+	}
+	if err != nil {
+		return fmt.Errorf("error connecting to NATS: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	info, err := reader.ReadString('\n')
+	if err != nil || !strings.HasPrefix(info, "INFO ") {
+		conn.Close()
+		return fmt.Errorf("error reading NATS server info: %v", err)
+	}
+
+	options := map[string]any{
+		"verbose":  false,
+		"pedantic": false,
+		"name":     "queuer-manager",
+		"lang":     "go",
+		"version":  "1.0.0",
+	}
+	if p.config.Token != "" {
+		options["auth_token"] = p.config.Token
+	}
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("error encoding NATS connect options: %w", err)
+	}
+
+	_, err = fmt.Fprintf(conn, "CONNECT %s\r\nPING\r\n", optionsJSON)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("error sending NATS connect: %w", err)
+	}
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("error reading NATS connect response: %w", err)
+	}
+	if !strings.HasPrefix(response, "PONG") {
+		conn.Close()
+		return fmt.Errorf("NATS connect failed: %s", strings.TrimSpace(response))
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	p.conn = conn
+	go p.readLoop(conn, reader)
+
+	return nil
+}
+
+// publish writes a PUB message, p.mu has to be locked.
+func (p *PublisherNATS) publish(ctx context.Context, subject string, payload []byte) error {
+	if p.conn == nil {
+		return fmt.Errorf("not connected to NATS")
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(5 * time.Second)
+	}
+	_ = p.conn.SetWriteDeadline(deadline)
+
+	_, err := fmt.Fprintf(p.conn, "PUB %s %d\r\n%s\r\n", subject, len(payload), payload)
+	_ = p.conn.SetWriteDeadline(time.Time{})
+	if err != nil {
+		return fmt.Errorf("error publishing to NATS: %w", err)
+	}
+	return nil
+}
+
+// readLoop answers server pings to keep the connection alive until the connection is closed.
+func (p *PublisherNATS) readLoop(conn net.Conn, reader *bufio.Reader) {
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		switch {
+		case strings.HasPrefix(line, "PING"):
+			p.mu.Lock()
+			if p.conn == conn {
+				_, _ = conn.Write([]byte("PONG\r\n"))
+			}
+			p.mu.Unlock()
+		case strings.HasPrefix(line, "-ERR"):
+			log.Printf("NATS error: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+// closeConn closes the current connection, p.mu has to be locked.
+func (p *PublisherNATS) closeConn() {
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn = nil
+	}
+}
@@ -0,0 +1,183 @@
+package event
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type natsMessage struct {
+	subject string
+	payload []byte
+}
+
+// fakeNATSServer is a NATS server speaking the parts of the client protocol the publisher uses.
+type fakeNATSServer struct {
+	listener     net.Listener
+	connectReply string
+	connects     chan map[string]any
+	messages     chan natsMessage
+	pongs        chan struct{}
+	conns        chan net.Conn
+}
+
+func newFakeNATSServer(t *testing.T, connectReply string) *fakeNATSServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &fakeNATSServer{
+		listener:     listener,
+		connectReply: connectReply,
+		connects:     make(chan map[string]any, 10),
+		messages:     make(chan natsMessage, 10),
+		pongs:        make(chan struct{}, 10),
+		conns:        make(chan net.Conn, 10),
+	}
+	go server.accept()
+	t.Cleanup(func() { listener.Close() })
+	return server
+}
+
+func (s *fakeNATSServer) url() string {
+	return "nats://" + s.listener.Addr().String()
+}
+
+func (s *fakeNATSServer) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.conns <- conn
+		go s.serve(conn)
+	}
+}
+
+func (s *fakeNATSServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	_, _ = fmt.Fprintf(conn, "INFO {\"server_id\":\"fake\",\"max_payload\":1048576}\r\n")
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case strings.HasPrefix(line, "CONNECT "):
+			options := map[string]any{}
+			_ = json.Unmarshal([]byte(strings.TrimPrefix(line, "CONNECT ")), &options)
+			s.connects <- options
+		case line == "PING":
+			_, _ = conn.Write([]byte(s.connectReply))
+		case line == "PONG":
+			s.pongs <- struct{}{}
+		case strings.HasPrefix(line, "PUB "):
+			fields := strings.Fields(line)
+			size, err := strconv.Atoi(fields[len(fields)-1])
+			if err != nil {
+				return
+			}
+			payload := make([]byte, size+2)
+			_, err = io.ReadFull(reader, payload)
+			if err != nil {
+				return
+			}
+			s.messages <- natsMessage{subject: fields[1], payload: payload[:size]}
+		}
+	}
+}
+
+func TestPublisherNATS(t *testing.T) {
+	t.Run("Events are published to the subject of their type", func(t *testing.T) {
+		server := newFakeNATSServer(t, "PONG\r\n")
+		publisher, err := NewPublisherNATS(NATSConfig{URL: server.url(), Subject: "queuer.events", Token: "secret"})
+		require.NoError(t, err)
+		defer publisher.Close()
+
+		options := <-server.connects
+		assert.Equal(t, "secret", options["auth_token"])
+		assert.Equal(t, false, options["verbose"])
+
+		err = publisher.Publish(context.Background(), model.NewEvent(model.EVENT_JOB_ENDED, map[string]any{"status": "SUCCEEDED"}))
+		require.NoError(t, err)
+
+		select {
+		case message := <-server.messages:
+			assert.Equal(t, "queuer.events.job.ended", message.subject)
+			var published model.Event
+			require.NoError(t, json.Unmarshal(message.payload, &published))
+			assert.Equal(t, model.EVENT_JOB_ENDED, published.Type)
+			assert.Equal(t, map[string]any{"status": "SUCCEEDED"}, published.Data)
+		case <-time.After(time.Second):
+			t.Fatal("Expected the event to be published")
+		}
+	})
+
+	t.Run("Server pings are answered", func(t *testing.T) {
+		server := newFakeNATSServer(t, "PONG\r\n")
+		publisher, err := NewPublisherNATS(NATSConfig{URL: server.url(), Subject: "queuer.events"})
+		require.NoError(t, err)
+		defer publisher.Close()
+
+		conn := <-server.conns
+		_, err = conn.Write([]byte("PING\r\n"))
+		require.NoError(t, err)
+
+		select {
+		case <-server.pongs:
+		case <-time.After(time.Second):
+			t.Fatal("Expected the publisher to answer the ping")
+		}
+	})
+
+	t.Run("Connect fails with the error of the server", func(t *testing.T) {
+		server := newFakeNATSServer(t, "-ERR 'Authorization Violation'\r\n")
+		_, err := NewPublisherNATS(NATSConfig{URL: server.url(), Subject: "queuer.events", Token: "wrong"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Authorization Violation")
+	})
+
+	t.Run("Publish reconnects after the connection is closed", func(t *testing.T) {
+		server := newFakeNATSServer(t, "PONG\r\n")
+		publisher, err := NewPublisherNATS(NATSConfig{URL: server.url(), Subject: "queuer.events"})
+		require.NoError(t, err)
+		defer publisher.Close()
+		<-server.connects
+
+		require.NoError(t, publisher.Close())
+		err = publisher.Publish(context.Background(), model.NewEvent(model.EVENT_WORKER_REGISTERED, nil))
+		require.NoError(t, err)
+
+		select {
+		case <-server.connects:
+		case <-time.After(time.Second):
+			t.Fatal("Expected the publisher to reconnect")
+		}
+		select {
+		case message := <-server.messages:
+			assert.Equal(t, "queuer.events.worker.registered", message.subject)
+		case <-time.After(time.Second):
+			t.Fatal("Expected the event to be published after reconnecting")
+		}
+	})
+
+	t.Run("Invalid URL is rejected", func(t *testing.T) {
+		_, err := NewPublisherNATS(NATSConfig{URL: "://invalid", Subject: "queuer.events"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid NATS URL")
+	})
+}
@@ -0,0 +1,164 @@
+package forward
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/model"
+)
+
+// Forwarder adds the jobs of the tasks of its rules on remote queuerManager instances
+// and retrieves the state of the remote jobs with the CI API of the remote instances.
+type Forwarder struct {
+	rules  []*qmModel.ForwardRule
+	client *http.Client
+}
+
+// NewForwarder creates a forwarder for the rules, each task can only be forwarded by one rule.
+func NewForwarder(rules []*qmModel.ForwardRule) (*Forwarder, error) {
+	f := &Forwarder{client: &http.Client{Timeout: 30 * time.Second}}
+	tasks := map[string]bool{}
+	for i, rule := range rules {
+		if rule.Task == "" || rule.RemoteURL == "" || rule.Token == "" {
+			return nil, fmt.Errorf("forward rule %d (%s) requires task, remote_url and token", i, rule.Name)
+		}
+		if tasks[rule.Task] {
+			return nil, fmt.Errorf("task %s is forwarded by more than one rule", rule.Task)
+		}
+		tasks[rule.Task] = true
+
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule-%d", i)
+		}
+		if rule.RemoteTask == "" {
+			rule.RemoteTask = rule.Task
+		}
+		rule.RemoteURL = strings.TrimRight(rule.RemoteURL, "/")
+		f.rules = append(f.rules, rule)
+	}
+	return f, nil
+}
+
+// CreateForwarderFromEnv creates a forwarder with the JSON rules of QUEUER_MANAGER_FORWARD_RULES.
+// It returns nil if no rules are configured.
+func CreateForwarderFromEnv() (*Forwarder, error) {
+	rulesJSON := strings.TrimSpace(os.Getenv("QUEUER_MANAGER_FORWARD_RULES"))
+	if rulesJSON == "" {
+		return nil, nil
+	}
+
+	rules := []*qmModel.ForwardRule{}
+	err := json.Unmarshal([]byte(rulesJSON), &rules)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing QUEUER_MANAGER_FORWARD_RULES: %w", err)
+	}
+
+	return NewForwarder(rules)
+}
+
+// Rule returns the rule forwarding the task or nil if the jobs of the task run locally.
+func (f *Forwarder) Rule(taskKey string) *qmModel.ForwardRule {
+	if f == nil {
+		return nil
+	}
+	for _, rule := range f.rules {
+		if rule.Task == taskKey {
+			return rule
+		}
+	}
+	return nil
+}
+
+// ForwardJob adds a job with the parameters on the remote instance of the rule and returns the remote job.
+func (f *Forwarder) ForwardJob(ctx context.Context, rule *qmModel.ForwardRule, parameters map[string]any) (*model.Job, error) {
+	body, err := json.Marshal(parameters)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding parameters: %w", err)
+	}
+
+	jobRun := &qmModel.CIJobRun{}
+	endpoint := rule.RemoteURL + "/api/ci/runJob/" + url.PathEscape(rule.RemoteTask)
+	err = f.do(ctx, rule, http.MethodPost, endpoint, body, jobRun)
+	if err != nil {
+		return nil, err
+	}
+	if jobRun.Job == nil {
+		return nil, fmt.Errorf("remote instance responded without job")
+	}
+
+	return jobRun.Job, nil
+}
+
+// SyncJob retrieves the current state of the remote job of a forwarded job.
+func (f *Forwarder) SyncJob(ctx context.Context, forwardedJob *qmModel.ForwardedJob) (*model.Job, error) {
+	rule := f.ruleByName(forwardedJob.Rule)
+	if rule == nil {
+		return nil, fmt.Errorf("forward rule %s is not configured anymore", forwardedJob.Rule)
+	}
+
+	job := &model.Job{}
+	endpoint := forwardedJob.RemoteURL + "/api/ci/waitJob/" + forwardedJob.RemoteRID.String() + "?timeout=0s"
+	err := f.do(ctx, rule, http.MethodGet, endpoint, nil, job)
+	if err != nil {
+		return nil, err
+	}
+	if job.RID != forwardedJob.RemoteRID {
+		return nil, fmt.Errorf("remote instance responded with job %s instead of %s", job.RID, forwardedJob.RemoteRID)
+	}
+
+	return job, nil
+}
+
+func (f *Forwarder) ruleByName(name string) *qmModel.ForwardRule {
+	for _, rule := range f.rules {
+		if rule.Name == name {
+			return rule
+		}
+	}
+	return nil
+}
+
+// do sends a request with the token of the rule and decodes the JSON response into result.
+func (f *Forwarder) do(ctx context.Context, rule *qmModel.ForwardRule, method string, endpoint string, body []byte, result any) error {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+rule.Token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending request to %s: %w", rule.RemoteURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		remoteError := struct {
+			Error string `json:"error"`
+		}{}
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		if json.Unmarshal(message, &remoteError) == nil && remoteError.Error != "" {
+			return fmt.Errorf("remote instance responded with status %d: %s", resp.StatusCode, remoteError.Error)
+		}
+		return fmt.Errorf("remote instance responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(result)
+	if err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+	return nil
+}
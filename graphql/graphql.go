@@ -0,0 +1,983 @@
+// Package graphql implements the subset of GraphQL used by the API of the manager.
+// The schema has one query type with objects and the scalars String, Int, Float, Boolean, ID and JSON,
+// queries support variables, aliases, fragments, the directives @include and @skip and the introspection with __schema and __type.
+// Mutations, subscriptions, interfaces, unions and enums are not supported, the kinds of the introspection types are strings.
+// Queries are limited in their depth, number of fields and aliases and their cost, so a small query cannot select huge lists.
+package graphql
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DEFAULT_MAX_DEPTH is the default maximum nesting of the selections of a query, the introspection types do not count
+	DEFAULT_MAX_DEPTH = 10
+	// DEFAULT_MAX_FIELDS is the default maximum number of selected fields of a query, fields of fragments count per spread
+	DEFAULT_MAX_FIELDS = 500
+	// DEFAULT_MAX_ALIASES is the default maximum number of aliased fields of a query
+	DEFAULT_MAX_ALIASES = 30
+	// DEFAULT_MAX_COST is the default maximum cost of a query, see Field.Cost
+	DEFAULT_MAX_COST = 10000
+)
+
+// scalars are the built-in scalar types, JSON is any JSON value, eg. the parameters of a job.
+var scalars = []string{"String", "Int", "Float", "Boolean", "ID", "JSON"}
+
+// Request is a GraphQL request, the JSON body of a POST request or the query parameters of a GET request.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Response is the result of a request, data is omitted if the request failed before the execution.
+type Response struct {
+	Data   any      `json:"data,omitempty"`
+	Errors []*Error `json:"errors,omitempty"`
+}
+
+// Error is an error of a request, the path is the response path of the field that failed.
+type Error struct {
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty"`
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Args are the coerced arguments of a field, arguments that are not given have their default or are missing.
+type Args map[string]any
+
+// String returns the string argument or an empty string.
+func (a Args) String(name string) string {
+	value, _ := a[name].(string)
+	return value
+}
+
+// Int returns the int argument or 0.
+func (a Args) Int(name string) int {
+	value, _ := a[name].(int)
+	return value
+}
+
+// Bool returns the boolean argument or false.
+func (a Args) Bool(name string) bool {
+	value, _ := a[name].(bool)
+	return value
+}
+
+// Resolver resolves the value of a field from the value of its parent object, the source.
+type Resolver func(ctx context.Context, source any, args Args) (any, error)
+
+// Argument is an argument of a field, the type is a scalar type reference, eg. String or Int!.
+type Argument struct {
+	Name        string
+	Type        string
+	Default     any
+	Description string
+}
+
+// Cost returns the cost of a field with its arguments from the cost of its selections, eg. the limit times the selections of a list.
+type Cost func(args Args, childCost int) int
+
+// Field is a field of an object, the type is a type reference, eg. [Job!]!.
+// Without resolver the field is read from a map source by its name.
+// Without cost the cost of the field is 1 plus the cost of its selections.
+type Field struct {
+	Name        string
+	Description string
+	Type        string
+	Args        []*Argument
+	Resolve     Resolver
+	Cost        Cost
+
+	typ *typeRef
+}
+
+// Object is an object type of the schema.
+type Object struct {
+	Name        string
+	Description string
+	Fields      []*Field
+}
+
+// field returns the field of the object by name or nil.
+func (o *Object) field(name string) *Field {
+	for _, f := range o.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// NewObject returns an object with the fields of the struct value derived by their JSON tags, eg. to expose a model.
+// Strings, numbers and booleans are mapped to their scalars, UUIDs to ID, other text values like times to String
+// and all other values to JSON. The given fields are added and replace derived fields with the same name.
+func NewObject(name string, description string, value any, fields ...*Field) *Object {
+	object := &Object{Name: name, Description: description}
+
+	valueType := reflect.TypeOf(value)
+	for valueType != nil && valueType.Kind() == reflect.Pointer {
+		valueType = valueType.Elem()
+	}
+	if valueType != nil && valueType.Kind() == reflect.Struct {
+		for _, structField := range reflect.VisibleFields(valueType) {
+			tag := structField.Tag.Get("json")
+			fieldName, options, _ := strings.Cut(tag, ",")
+			if !structField.IsExported() || structField.Anonymous || fieldName == "" || fieldName == "-" {
+				continue
+			}
+			object.Fields = append(object.Fields, &Field{
+				Name:    fieldName,
+				Type:    typeOf(structField.Type, !strings.Contains(options, "omitempty")),
+				Resolve: structFieldResolver(structField.Index),
+			})
+		}
+	}
+
+	for _, f := range fields {
+		if i := slices.IndexFunc(object.Fields, func(derived *Field) bool { return derived.Name == f.Name }); i >= 0 {
+			object.Fields[i] = f
+			continue
+		}
+		object.Fields = append(object.Fields, f)
+	}
+	return object
+}
+
+// typeOf returns the type reference of a Go type, values are non-null if they are no pointer, slice, map or interface.
+func typeOf(t reflect.Type, nonNull bool) string {
+	suffix := ""
+	if nonNull {
+		suffix = "!"
+	}
+
+	switch {
+	case t.Kind() == reflect.Pointer:
+		return typeOf(t.Elem(), false)
+	case t == reflect.TypeOf(uuid.UUID{}):
+		return "ID" + suffix
+	case t.Implements(reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()):
+		return "String" + suffix
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "String" + suffix
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return "Int" + suffix
+	case reflect.Float32, reflect.Float64:
+		return "Float" + suffix
+	case reflect.Bool:
+		return "Boolean" + suffix
+	case reflect.Slice, reflect.Array:
+		if itemType := typeOf(t.Elem(), true); itemType != "JSON" {
+			return "[" + itemType + "]"
+		}
+	}
+	return "JSON"
+}
+
+// structFieldResolver returns a resolver reading the struct field by its index from a struct or struct pointer source.
+func structFieldResolver(index []int) Resolver {
+	return func(ctx context.Context, source any, args Args) (any, error) {
+		value := reflect.ValueOf(source)
+		for value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				return nil, nil
+			}
+			value = value.Elem()
+		}
+		if value.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("invalid source %T", source)
+		}
+		return value.FieldByIndex(index).Interface(), nil
+	}
+}
+
+// Schema is a GraphQL schema with the query type and the object types it references.
+// Queries exceeding one of the limits are rejected before the execution.
+type Schema struct {
+	Query      *Object
+	MaxDepth   int
+	MaxFields  int
+	MaxAliases int
+	MaxCost    int
+
+	objects       []*Object
+	introspection []*Object
+	meta          *Object
+	types         map[string]map[string]any
+	schemaValue   map[string]any
+}
+
+// NewSchema returns a schema with the query type, the objects are the types the fields reference.
+// It returns an error if a type is unknown, an argument is no scalar or a name is defined more than once or starts with __.
+func NewSchema(query *Object, objects ...*Object) (*Schema, error) {
+	if query == nil {
+		return nil, fmt.Errorf("query type is required")
+	}
+
+	schema := &Schema{
+		Query:         query,
+		MaxDepth:      DEFAULT_MAX_DEPTH,
+		MaxFields:     DEFAULT_MAX_FIELDS,
+		MaxAliases:    DEFAULT_MAX_ALIASES,
+		MaxCost:       DEFAULT_MAX_COST,
+		objects:       append([]*Object{query}, objects...),
+		introspection: introspectionObjects(),
+	}
+	schema.meta = schema.metaFields()
+
+	names := map[string]bool{}
+	for _, object := range schema.objects {
+		if names[object.Name] || slices.Contains(scalars, object.Name) {
+			return nil, fmt.Errorf("type %s is defined more than once", object.Name)
+		}
+		if strings.HasPrefix(object.Name, "__") {
+			return nil, fmt.Errorf("type %s starts with __ (reserved for the introspection)", object.Name)
+		}
+		names[object.Name] = true
+	}
+	for _, object := range schema.introspection {
+		names[object.Name] = true
+	}
+
+	for _, object := range append(slices.Concat(schema.objects, schema.introspection), schema.meta) {
+		fieldNames := map[string]bool{}
+		for _, f := range object.Fields {
+			if strings.HasPrefix(f.Name, "__") && object != schema.meta {
+				return nil, fmt.Errorf("field %s.%s starts with __ (reserved for the introspection)", object.Name, f.Name)
+			}
+			if fieldNames[f.Name] {
+				return nil, fmt.Errorf("field %s.%s is defined more than once", object.Name, f.Name)
+			}
+			fieldNames[f.Name] = true
+
+			typ, err := parseTypeRef(f.Type)
+			if err != nil {
+				return nil, fmt.Errorf("invalid type of field %s.%s: %v", object.Name, f.Name, err)
+			}
+			if !names[typ.namedType()] && !slices.Contains(scalars, typ.namedType()) {
+				return nil, fmt.Errorf("unknown type %s of field %s.%s", typ.namedType(), object.Name, f.Name)
+			}
+			f.typ = typ
+
+			for _, arg := range f.Args {
+				argType, err := parseTypeRef(arg.Type)
+				if err != nil || !slices.Contains(scalars, argType.namedType()) {
+					return nil, fmt.Errorf("invalid type %s of argument %s of field %s.%s (must be a scalar)", arg.Type, arg.Name, object.Name, f.Name)
+				}
+			}
+		}
+	}
+
+	schema.introspect()
+	return schema, nil
+}
+
+// object returns the object type by name, including the introspection types, or nil.
+func (s *Schema) object(name string) *Object {
+	for _, object := range slices.Concat(s.objects, s.introspection) {
+		if object.Name == name {
+			return object
+		}
+	}
+	return nil
+}
+
+// field returns the field of the object by name, the introspection fields __schema and __type of the query type, or nil.
+func (s *Schema) field(object *Object, name string) *Field {
+	if object == s.Query && strings.HasPrefix(name, "__") {
+		return s.meta.field(name)
+	}
+	return object.field(name)
+}
+
+// String returns the schema in the schema definition language.
+func (s *Schema) String() string {
+	var sdl strings.Builder
+	sdl.WriteString("\"Any JSON value.\"\nscalar JSON\n")
+	for _, object := range s.objects {
+		sdl.WriteString("\n")
+		writeDescription(&sdl, object.Description, "")
+		sdl.WriteString("type " + object.Name + " {\n")
+		for _, f := range object.Fields {
+			writeDescription(&sdl, f.Description, "  ")
+			sdl.WriteString("  " + f.Name)
+			if len(f.Args) > 0 {
+				args := []string{}
+				for _, arg := range f.Args {
+					definition := arg.Name + ": " + arg.Type
+					if arg.Description != "" {
+						description, _ := json.Marshal(arg.Description)
+						definition = string(description) + " " + definition
+					}
+					if arg.Default != nil {
+						defaultValue, _ := json.Marshal(arg.Default)
+						definition += " = " + string(defaultValue)
+					}
+					args = append(args, definition)
+				}
+				sdl.WriteString("(" + strings.Join(args, ", ") + ")")
+			}
+			sdl.WriteString(": " + f.Type + "\n")
+		}
+		sdl.WriteString("}\n")
+	}
+	return sdl.String()
+}
+
+// writeDescription writes the description as string with the indent, nothing without description.
+func writeDescription(sdl *strings.Builder, description string, indent string) {
+	if description == "" {
+		return
+	}
+	quoted, _ := json.Marshal(description)
+	sdl.WriteString(indent + string(quoted) + "\n")
+}
+
+// typeRef is a parsed type reference, a named type or a list of an item type, optionally non-null.
+type typeRef struct {
+	name    string
+	item    *typeRef
+	nonNull bool
+}
+
+// parseTypeRef parses a type reference, eg. [Job!]!.
+func parseTypeRef(typ string) (*typeRef, error) {
+	ref := &typeRef{}
+	if strings.HasSuffix(typ, "!") {
+		ref.nonNull = true
+		typ = strings.TrimSuffix(typ, "!")
+	}
+
+	if strings.HasPrefix(typ, "[") && strings.HasSuffix(typ, "]") {
+		item, err := parseTypeRef(typ[1 : len(typ)-1])
+		if err != nil {
+			return nil, err
+		}
+		ref.item = item
+		return ref, nil
+	}
+
+	if typ == "" || strings.ContainsAny(typ, "[]! ") {
+		return nil, fmt.Errorf("invalid type %q", typ)
+	}
+	ref.name = typ
+	return ref, nil
+}
+
+// namedType returns the name of the type, the item type of lists.
+func (t *typeRef) namedType() string {
+	if t.item != nil {
+		return t.item.namedType()
+	}
+	return t.name
+}
+
+// String returns the type reference as written in a document.
+func (t *typeRef) String() string {
+	typ := t.name
+	if t.item != nil {
+		typ = "[" + t.item.String() + "]"
+	}
+	if t.nonNull {
+		typ += "!"
+	}
+	return typ
+}
+
+// Execute parses, validates and executes the query of the request.
+// Errors of fields are returned with their path and the data of the other fields, the field is null then.
+func (s *Schema) Execute(ctx context.Context, request *Request) *Response {
+	doc, err := parse(request.Query)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	op, err := doc.operation(request.OperationName)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+	if op.kind != "query" {
+		return &Response{Errors: []*Error{{Message: fmt.Sprintf("%s operations are not supported", op.kind)}}}
+	}
+
+	variables, err := coerceVariables(op, request.Variables)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	v := &validator{schema: s, doc: doc, op: op, variables: variables, fragments: map[string]bool{}}
+	cost := v.validateSelections(s.Query, op.selections, 1)
+	if len(v.errors) == 0 && cost > s.MaxCost {
+		v.errorf("query has a cost of %d, more than the maximum cost %d", cost, s.MaxCost)
+	}
+	if len(v.errors) > 0 {
+		return &Response{Errors: v.errors}
+	}
+
+	e := &executor{schema: s, doc: doc, variables: variables}
+	data, err := e.executeSelections(ctx, s.Query, nil, op.selections, []any{})
+	if err != nil {
+		// A non-null field of the query failed, so the data itself is null
+		return &Response{Data: json.RawMessage("null"), Errors: e.errors}
+	}
+	return &Response{Data: data, Errors: e.errors}
+}
+
+// operation returns the operation of the document by name, the only operation if the name is empty.
+func (d *document) operation(name string) (*operation, error) {
+	if name == "" {
+		if len(d.operations) > 1 {
+			return nil, fmt.Errorf("operationName is required for a document with multiple operations")
+		}
+		return d.operations[0], nil
+	}
+	for _, op := range d.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operation %s", name)
+}
+
+// coerceVariables coerces the variables of the request to the types of the variable definitions of the operation.
+func coerceVariables(op *operation, values map[string]any) (map[string]any, error) {
+	variables := map[string]any{}
+	for _, definition := range op.variables {
+		typ, err := parseTypeRef(definition.typ)
+		if err != nil || !slices.Contains(scalars, typ.namedType()) {
+			return nil, fmt.Errorf("invalid type %s of variable $%s (must be a scalar)", definition.typ, definition.name)
+		}
+
+		value, ok := values[definition.name]
+		if !ok {
+			if !definition.hasDefault {
+				if typ.nonNull {
+					return nil, fmt.Errorf("variable $%s of type %s is required", definition.name, definition.typ)
+				}
+				continue
+			}
+			value = definition.defaultValue
+		}
+
+		coerced, err := coerceInput(value, typ, nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid variable $%s: %v", definition.name, err)
+		}
+		variables[definition.name] = coerced
+	}
+	return variables, nil
+}
+
+// coerceInput coerces an input value to the type, variables in the value are replaced by their coerced values.
+func coerceInput(value any, typ *typeRef, variables map[string]any) (any, error) {
+	if variable, ok := value.(variableValue); ok {
+		value = variables[string(variable)]
+	}
+	if value == nil {
+		if typ.nonNull {
+			return nil, fmt.Errorf("expected non-null value of type %s", typ)
+		}
+		return nil, nil
+	}
+
+	if typ.item != nil {
+		values, ok := value.([]any)
+		if !ok {
+			values = []any{value}
+		}
+		list := []any{}
+		for _, item := range values {
+			coerced, err := coerceInput(item, typ.item, variables)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, coerced)
+		}
+		return list, nil
+	}
+
+	switch typ.name {
+	case "String":
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+	case "ID":
+		switch id := value.(type) {
+		case string:
+			return id, nil
+		case int:
+			return strconv.Itoa(id), nil
+		case float64:
+			if id == math.Trunc(id) {
+				return strconv.FormatFloat(id, 'f', 0, 64), nil
+			}
+		}
+	case "Int":
+		switch i := value.(type) {
+		case int:
+			return i, nil
+		case float64:
+			// JSON numbers of the variables are decoded as float64
+			if i == math.Trunc(i) && math.Abs(i) <= math.MaxInt32 {
+				return int(i), nil
+			}
+		}
+	case "Float":
+		switch f := value.(type) {
+		case int:
+			return float64(f), nil
+		case float64:
+			return f, nil
+		}
+	case "Boolean":
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+	case "JSON":
+		if enum, ok := value.(enumValue); ok {
+			return string(enum), nil
+		}
+		return value, nil
+	}
+
+	return nil, fmt.Errorf("expected value of type %s, got %v", typ, value)
+}
+
+// coerceArguments coerces the arguments of a selected field to the argument definitions of the field.
+func coerceArguments(definitions []*Argument, arguments map[string]any, variables map[string]any) (Args, error) {
+	args := Args{}
+	for _, definition := range definitions {
+		typ, err := parseTypeRef(definition.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		value, ok := arguments[definition.Name]
+		if variable, isVariable := value.(variableValue); isVariable {
+			_, ok = variables[string(variable)]
+		}
+		if !ok {
+			if definition.Default != nil {
+				args[definition.Name] = definition.Default
+				continue
+			}
+			if typ.nonNull {
+				return nil, fmt.Errorf("argument %s of type %s is required", definition.Name, definition.Type)
+			}
+			continue
+		}
+
+		coerced, err := coerceInput(value, typ, variables)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %s: %v", definition.Name, err)
+		}
+		args[definition.Name] = coerced
+	}
+	return args, nil
+}
+
+// skipped reports if a selection is excluded by its @skip or @include directive.
+func skipped(directives []*directive, variables map[string]any) (bool, error) {
+	for _, d := range directives {
+		if d.name != "skip" && d.name != "include" {
+			return false, fmt.Errorf("unknown directive @%s", d.name)
+		}
+		args, err := coerceArguments([]*Argument{{Name: "if", Type: "Boolean!"}}, d.arguments, variables)
+		if err != nil {
+			return false, fmt.Errorf("invalid directive @%s: %v", d.name, err)
+		}
+		if args.Bool("if") == (d.name == "skip") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// validator validates the selections of an operation against the schema before the execution.
+type validator struct {
+	schema    *Schema
+	doc       *document
+	op        *operation
+	variables map[string]any
+	fragments map[string]bool
+	fields    int
+	aliases   int
+	errors    []*Error
+}
+
+// errorf adds a validation error.
+func (v *validator) errorf(format string, args ...any) {
+	v.errors = append(v.errors, &Error{Message: fmt.Sprintf(format, args...)})
+}
+
+// validateSelections validates the selections on the object, the depth is the nesting of the selections.
+// It returns the cost of the selections, the validation stops once the query selects more than the maximum of fields.
+func (v *validator) validateSelections(object *Object, selections []selection, depth int) int {
+	if depth > v.schema.MaxDepth {
+		v.errorf("query is nested deeper than the maximum depth %d", v.schema.MaxDepth)
+		return 0
+	}
+
+	cost := 0
+	for _, sel := range selections {
+		if v.fields > v.schema.MaxFields {
+			break
+		}
+
+		switch sel := sel.(type) {
+		case *field:
+			cost += v.validateField(object, sel, depth)
+		case *fragmentSpread:
+			if _, err := skipped(sel.directives, v.variables); err != nil {
+				v.errorf("%v", err)
+			}
+			frag, ok := v.doc.fragments[sel.name]
+			if !ok {
+				v.errorf("unknown fragment %s", sel.name)
+				continue
+			}
+			if v.fragments[sel.name] {
+				v.errorf("fragment %s spreads itself", sel.name)
+				continue
+			}
+			if frag.typeCondition != object.Name {
+				v.errorf("fragment %s on %s cannot be spread on %s", frag.name, frag.typeCondition, object.Name)
+				continue
+			}
+			v.fragments[sel.name] = true
+			cost += v.validateSelections(object, frag.selections, depth)
+			delete(v.fragments, sel.name)
+		case *inlineFragment:
+			if _, err := skipped(sel.directives, v.variables); err != nil {
+				v.errorf("%v", err)
+			}
+			if sel.typeCondition != "" && sel.typeCondition != object.Name {
+				v.errorf("inline fragment on %s cannot be spread on %s", sel.typeCondition, object.Name)
+				continue
+			}
+			cost += v.validateSelections(object, sel.selections, depth)
+		}
+		// Costs are capped, so the costs of nested lists cannot overflow
+		cost = min(cost, math.MaxInt32)
+	}
+	return cost
+}
+
+// validateField validates a selected field, its arguments and its selections and returns the cost of the field.
+func (v *validator) validateField(object *Object, f *field, depth int) int {
+	v.fields++
+	if v.fields == v.schema.MaxFields+1 {
+		v.errorf("query selects more than the maximum of %d fields", v.schema.MaxFields)
+	}
+	if f.alias != "" {
+		v.aliases++
+		if v.aliases == v.schema.MaxAliases+1 {
+			v.errorf("query has more than the maximum of %d aliases", v.schema.MaxAliases)
+		}
+	}
+
+	if _, err := skipped(f.directives, v.variables); err != nil {
+		v.errorf("%v", err)
+	}
+
+	if f.name == "__typename" {
+		if len(f.arguments) > 0 || f.selections != nil {
+			v.errorf("field __typename has no arguments and no selections")
+		}
+		return 0
+	}
+
+	definition := v.schema.field(object, f.name)
+	if definition == nil {
+		v.errorf("unknown field %s on type %s", f.name, object.Name)
+		return 0
+	}
+
+	for name, value := range f.arguments {
+		if !slices.ContainsFunc(definition.Args, func(arg *Argument) bool { return arg.Name == name }) {
+			v.errorf("unknown argument %s on field %s.%s", name, object.Name, f.name)
+		}
+		v.validateVariables(value)
+	}
+	args, err := coerceArguments(definition.Args, f.arguments, v.variables)
+	if err != nil {
+		v.errorf("field %s.%s: %v", object.Name, f.name, err)
+	}
+
+	childCost := 0
+	fieldObject := v.schema.object(definition.typ.namedType())
+	switch {
+	case fieldObject == nil && f.selections != nil:
+		v.errorf("field %s.%s of type %s has no selections", object.Name, f.name, definition.Type)
+	case fieldObject != nil && f.selections == nil:
+		v.errorf("field %s.%s of type %s requires selections", object.Name, f.name, definition.Type)
+	case fieldObject != nil:
+		// The type references of the introspection are nested deeply, so introspection types do not count for the depth
+		if !strings.HasPrefix(fieldObject.Name, "__") {
+			depth++
+		}
+		childCost = v.validateSelections(fieldObject, f.selections, depth)
+	}
+
+	if definition.Cost != nil && err == nil {
+		return max(definition.Cost(args, childCost), 0)
+	}
+	return 1 + childCost
+}
+
+// validateVariables validates that the variables used in a value are defined by the operation.
+func (v *validator) validateVariables(value any) {
+	switch value := value.(type) {
+	case variableValue:
+		if !slices.ContainsFunc(v.op.variables, func(definition *variableDefinition) bool { return definition.name == string(value) }) {
+			v.errorf("variable $%s is not defined", value)
+		}
+	case []any:
+		for _, item := range value {
+			v.validateVariables(item)
+		}
+	case map[string]any:
+		for _, item := range value {
+			v.validateVariables(item)
+		}
+	}
+}
+
+// errNull is returned by the completion if a value is null because of an error in a non-null position.
+// The error itself is added to the errors of the executor where it occurred.
+var errNull = errors.New("null in non-null position")
+
+// executor executes the validated selections of an operation.
+type executor struct {
+	schema    *Schema
+	doc       *document
+	variables map[string]any
+	errors    []*Error
+}
+
+// errorf adds an error of the field at the path.
+func (e *executor) errorf(path []any, format string, args ...any) {
+	e.errors = append(e.errors, &Error{Message: fmt.Sprintf(format, args...), Path: slices.Clone(path)})
+}
+
+// collectedField is a field of the response with the selections of all fields with its response key.
+type collectedField struct {
+	key    string
+	fields []*field
+}
+
+// collectFields collects the fields of the selections by their response key, in the order of the selections.
+// Fragments are flattened and selections excluded by their directives are skipped.
+func (e *executor) collectFields(selections []selection, collected []*collectedField) []*collectedField {
+	for _, sel := range selections {
+		switch sel := sel.(type) {
+		case *field:
+			if skip, _ := skipped(sel.directives, e.variables); skip {
+				continue
+			}
+			i := slices.IndexFunc(collected, func(c *collectedField) bool { return c.key == sel.responseKey() })
+			if i < 0 {
+				collected = append(collected, &collectedField{key: sel.responseKey(), fields: []*field{sel}})
+				continue
+			}
+			collected[i].fields = append(collected[i].fields, sel)
+		case *fragmentSpread:
+			if skip, _ := skipped(sel.directives, e.variables); skip {
+				continue
+			}
+			collected = e.collectFields(e.doc.fragments[sel.name].selections, collected)
+		case *inlineFragment:
+			if skip, _ := skipped(sel.directives, e.variables); skip {
+				continue
+			}
+			collected = e.collectFields(sel.selections, collected)
+		}
+	}
+	return collected
+}
+
+// executeSelections executes the selections on the object with the source value.
+func (e *executor) executeSelections(ctx context.Context, object *Object, source any, selections []selection, path []any) (*OrderedMap, error) {
+	result := &OrderedMap{}
+	for _, collected := range e.collectFields(selections, nil) {
+		value, err := e.executeField(ctx, object, source, collected, append(path, collected.key))
+		if err != nil {
+			return nil, err
+		}
+		result.Set(collected.key, value)
+	}
+	return result, nil
+}
+
+// executeField resolves and completes a field, errors are added and the field is null then.
+func (e *executor) executeField(ctx context.Context, object *Object, source any, collected *collectedField, path []any) (any, error) {
+	f := collected.fields[0]
+	if f.name == "__typename" {
+		return object.Name, nil
+	}
+
+	definition := e.schema.field(object, f.name)
+	args, err := coerceArguments(definition.Args, f.arguments, e.variables)
+	if err != nil {
+		e.errorf(path, "%v", err)
+		return e.null(definition.typ)
+	}
+
+	resolve := definition.Resolve
+	if resolve == nil {
+		resolve = mapResolver(definition.Name)
+	}
+	value, err := resolve(ctx, source, args)
+	if err != nil {
+		e.errorf(path, "%v", err)
+		return e.null(definition.typ)
+	}
+
+	selections := []selection{}
+	for _, f := range collected.fields {
+		selections = append(selections, f.selections...)
+	}
+	return e.complete(ctx, definition.typ, selections, value, path)
+}
+
+// null returns the null value of a failed field, errNull if the type is non-null.
+func (e *executor) null(typ *typeRef) (any, error) {
+	if typ.nonNull {
+		return nil, errNull
+	}
+	return nil, nil
+}
+
+// complete completes the resolved value to the type, lists item by item and objects by their selections.
+func (e *executor) complete(ctx context.Context, typ *typeRef, selections []selection, value any, path []any) (any, error) {
+	if isNil(value) {
+		if typ.nonNull {
+			e.errorf(path, "cannot return null for non-null field of type %s", typ)
+			return nil, errNull
+		}
+		return nil, nil
+	}
+
+	var completed any
+	var err error
+	switch {
+	case typ.item != nil:
+		completed, err = e.completeList(ctx, typ.item, selections, value, path)
+	case e.schema.object(typ.name) != nil:
+		completed, err = e.executeSelections(ctx, e.schema.object(typ.name), value, selections, path)
+	default:
+		completed = value
+	}
+	if err != nil {
+		return e.null(typ)
+	}
+	return completed, nil
+}
+
+// completeList completes the items of a list value to the item type.
+func (e *executor) completeList(ctx context.Context, itemType *typeRef, selections []selection, value any, path []any) (any, error) {
+	list := reflect.ValueOf(value)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		e.errorf(path, "expected list, got %T", value)
+		return nil, errNull
+	}
+
+	items := make([]any, list.Len())
+	for i := range items {
+		item, err := e.complete(ctx, itemType, selections, list.Index(i).Interface(), append(slices.Clone(path), i))
+		if err != nil {
+			return nil, err
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+// mapResolver returns a resolver reading the field by name from a map source.
+func mapResolver(name string) Resolver {
+	return func(ctx context.Context, source any, args Args) (any, error) {
+		values, ok := source.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("field %s has no resolver", name)
+		}
+		return values[name], nil
+	}
+}
+
+// isNil reports if the value is nil or a nil pointer, slice, map or interface.
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface, reflect.Func:
+		return v.IsNil()
+	}
+	return false
+}
+
+// OrderedMap is a JSON object keeping the order of its keys, the fields of a response are in the order of the selections.
+type OrderedMap struct {
+	keys   []string
+	values map[string]any
+}
+
+// Set sets the value of the key, new keys are appended.
+func (m *OrderedMap) Set(key string, value any) {
+	if m.values == nil {
+		m.values = map[string]any{}
+	}
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Get returns the value of the key.
+func (m *OrderedMap) Get(key string) (any, bool) {
+	value, ok := m.values[key]
+	return value, ok
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	var buffer strings.Builder
+	buffer.WriteString("{")
+	for i, key := range m.keys {
+		if i > 0 {
+			buffer.WriteString(",")
+		}
+		keyJson, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		valueJson, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(keyJson)
+		buffer.WriteString(":")
+		buffer.Write(valueJson)
+	}
+	buffer.WriteString("}")
+	return []byte(buffer.String()), nil
+}
@@ -0,0 +1,186 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+)
+
+// scalarDescriptions are the descriptions of the built-in scalar types in the introspection.
+var scalarDescriptions = map[string]string{
+	"String":  "A UTF-8 string.",
+	"Int":     "A signed 32-bit integer.",
+	"Float":   "A double-precision floating-point number.",
+	"Boolean": "true or false.",
+	"ID":      "A unique identifier serialized as string, eg. a UUID.",
+	"JSON":    "Any JSON value.",
+}
+
+// introspectionObjects returns the introspection types, the kinds and directive locations are strings instead of enums.
+func introspectionObjects() []*Object {
+	includeDeprecated := []*Argument{{Name: "includeDeprecated", Type: "Boolean", Default: false}}
+	deprecation := []*Field{
+		{Name: "isDeprecated", Type: "Boolean!"},
+		{Name: "deprecationReason", Type: "String"},
+	}
+
+	return []*Object{
+		{Name: "__Schema", Description: "The types and directives of the schema.", Fields: []*Field{
+			{Name: "description", Type: "String"},
+			{Name: "types", Type: "[__Type!]!"},
+			{Name: "queryType", Type: "__Type!"},
+			{Name: "mutationType", Type: "__Type"},
+			{Name: "subscriptionType", Type: "__Type"},
+			{Name: "directives", Type: "[__Directive!]!"},
+		}},
+		{Name: "__Type", Description: "A named type or a list or non-null type wrapping its ofType.", Fields: []*Field{
+			{Name: "kind", Type: "String!", Description: "One of SCALAR, OBJECT, LIST, NON_NULL."},
+			{Name: "name", Type: "String"},
+			{Name: "description", Type: "String"},
+			{Name: "specifiedByURL", Type: "String"},
+			{Name: "fields", Type: "[__Field!]", Args: includeDeprecated},
+			{Name: "interfaces", Type: "[__Type!]"},
+			{Name: "possibleTypes", Type: "[__Type!]"},
+			{Name: "enumValues", Type: "[__EnumValue!]", Args: includeDeprecated},
+			{Name: "inputFields", Type: "[__InputValue!]", Args: includeDeprecated},
+			{Name: "ofType", Type: "__Type"},
+			{Name: "isOneOf", Type: "Boolean"},
+		}},
+		{Name: "__Field", Description: "A field of an object type.", Fields: append([]*Field{
+			{Name: "name", Type: "String!"},
+			{Name: "description", Type: "String"},
+			{Name: "args", Type: "[__InputValue!]!", Args: includeDeprecated},
+			{Name: "type", Type: "__Type!"},
+		}, deprecation...)},
+		{Name: "__InputValue", Description: "An argument of a field or directive.", Fields: append([]*Field{
+			{Name: "name", Type: "String!"},
+			{Name: "description", Type: "String"},
+			{Name: "type", Type: "__Type!"},
+			{Name: "defaultValue", Type: "String", Description: "The default value as GraphQL value."},
+		}, deprecation...)},
+		{Name: "__EnumValue", Description: "A value of an enum type, the schema has no enum types.", Fields: append([]*Field{
+			{Name: "name", Type: "String!"},
+			{Name: "description", Type: "String"},
+		}, deprecation...)},
+		{Name: "__Directive", Description: "A directive of the queries.", Fields: []*Field{
+			{Name: "name", Type: "String!"},
+			{Name: "description", Type: "String"},
+			{Name: "locations", Type: "[String!]!"},
+			{Name: "args", Type: "[__InputValue!]!", Args: includeDeprecated},
+			{Name: "isRepeatable", Type: "Boolean!"},
+		}},
+	}
+}
+
+// metaFields returns the introspection fields of the query type.
+func (s *Schema) metaFields() *Object {
+	return &Object{Name: s.Query.Name, Fields: []*Field{
+		{
+			Name: "__schema",
+			Type: "__Schema!",
+			Resolve: func(ctx context.Context, source any, args Args) (any, error) {
+				return s.schemaValue, nil
+			},
+		},
+		{
+			Name: "__type",
+			Type: "__Type",
+			Args: []*Argument{{Name: "name", Type: "String!"}},
+			Resolve: func(ctx context.Context, source any, args Args) (any, error) {
+				if typ, ok := s.types[args.String("name")]; ok {
+					return typ, nil
+				}
+				return nil, nil
+			},
+		},
+	}}
+}
+
+// introspect builds the values of the introspection, the named types reference each other by their fields.
+func (s *Schema) introspect() {
+	s.types = map[string]map[string]any{}
+	types := []any{}
+	for _, name := range scalars {
+		s.types[name] = map[string]any{"kind": "SCALAR", "name": name, "description": scalarDescriptions[name]}
+		types = append(types, s.types[name])
+	}
+
+	objects := slices.Concat(s.objects, s.introspection)
+	for _, object := range objects {
+		s.types[object.Name] = map[string]any{"kind": "OBJECT", "name": object.Name, "description": optional(object.Description), "interfaces": []any{}}
+		types = append(types, s.types[object.Name])
+	}
+	for _, object := range objects {
+		fields := []any{}
+		for _, f := range object.Fields {
+			fields = append(fields, map[string]any{
+				"name":         f.Name,
+				"description":  optional(f.Description),
+				"args":         s.introspectArgs(f.Args),
+				"type":         s.introspectType(f.typ),
+				"isDeprecated": false,
+			})
+		}
+		s.types[object.Name]["fields"] = fields
+	}
+
+	directives := []any{}
+	for _, name := range []string{"include", "skip"} {
+		directives = append(directives, map[string]any{
+			"name":         name,
+			"description":  "Directs the executor to " + name + " this field or fragment only when the if argument is true.",
+			"locations":    []any{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+			"args":         s.introspectArgs([]*Argument{{Name: "if", Type: "Boolean!"}}),
+			"isRepeatable": false,
+		})
+	}
+
+	s.schemaValue = map[string]any{
+		"types":      types,
+		"queryType":  s.types[s.Query.Name],
+		"directives": directives,
+	}
+}
+
+// introspectType returns the introspection value of the type reference, lists and non-null types wrap their type.
+func (s *Schema) introspectType(t *typeRef) map[string]any {
+	typ := s.types[t.name]
+	if t.item != nil {
+		typ = map[string]any{"kind": "LIST", "ofType": s.introspectType(t.item)}
+	}
+	if t.nonNull {
+		return map[string]any{"kind": "NON_NULL", "ofType": typ}
+	}
+	return typ
+}
+
+// introspectArgs returns the introspection values of the arguments, the default values are JSON which is a valid GraphQL value for scalars.
+func (s *Schema) introspectArgs(args []*Argument) []any {
+	values := []any{}
+	for _, arg := range args {
+		typ, err := parseTypeRef(arg.Type)
+		if err != nil {
+			continue
+		}
+		value := map[string]any{
+			"name":         arg.Name,
+			"description":  optional(arg.Description),
+			"type":         s.introspectType(typ),
+			"isDeprecated": false,
+		}
+		if arg.Default != nil {
+			defaultValue, _ := json.Marshal(arg.Default)
+			value["defaultValue"] = string(defaultValue)
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// optional returns nil for an empty string, so missing descriptions are null.
+func optional(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,740 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Token kinds of the lexer.
+const (
+	tokenEOF = iota
+	tokenPunctuator
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+// token is a lexical token of a GraphQL document.
+type token struct {
+	kind  int
+	value string
+	pos   int
+}
+
+// lexer splits a GraphQL document into tokens, whitespace, commas and comments are ignored.
+type lexer struct {
+	source string
+	pos    int
+}
+
+// next returns the next token of the document.
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.source) {
+		c := l.source[l.pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			l.pos++
+		case c == '#':
+			for l.pos < len(l.source) && l.source[l.pos] != '\n' && l.source[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return l.read()
+		}
+	}
+	return token{kind: tokenEOF, pos: l.pos}, nil
+}
+
+// read reads the token starting at the current position.
+func (l *lexer) read() (token, error) {
+	start := l.pos
+	c := l.source[l.pos]
+	switch {
+	case strings.IndexByte("!$&()=:@[]{}|", c) >= 0:
+		l.pos++
+		return token{kind: tokenPunctuator, value: string(c), pos: start}, nil
+	case c == '.':
+		if strings.HasPrefix(l.source[l.pos:], "...") {
+			l.pos += 3
+			return token{kind: tokenPunctuator, value: "...", pos: start}, nil
+		}
+		return token{}, l.errorf(start, "unexpected character %q", c)
+	case c == '_' || isLetter(c):
+		for l.pos < len(l.source) && (l.source[l.pos] == '_' || isLetter(l.source[l.pos]) || isDigit(l.source[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokenName, value: l.source[start:l.pos], pos: start}, nil
+	case c == '-' || isDigit(c):
+		return l.readNumber()
+	case c == '"':
+		if strings.HasPrefix(l.source[l.pos:], `"""`) {
+			return l.readBlockString()
+		}
+		return l.readString()
+	}
+	return token{}, l.errorf(start, "unexpected character %q", c)
+}
+
+// readNumber reads an int or float token.
+func (l *lexer) readNumber() (token, error) {
+	start := l.pos
+	kind := tokenInt
+	if l.source[l.pos] == '-' {
+		l.pos++
+	}
+	digits := l.pos
+	for l.pos < len(l.source) && isDigit(l.source[l.pos]) {
+		l.pos++
+	}
+	if l.pos == digits {
+		return token{}, l.errorf(start, "invalid number")
+	}
+	if l.pos < len(l.source) && l.source[l.pos] == '.' {
+		kind = tokenFloat
+		l.pos++
+		fraction := l.pos
+		for l.pos < len(l.source) && isDigit(l.source[l.pos]) {
+			l.pos++
+		}
+		if l.pos == fraction {
+			return token{}, l.errorf(start, "invalid number")
+		}
+	}
+	if l.pos < len(l.source) && (l.source[l.pos] == 'e' || l.source[l.pos] == 'E') {
+		kind = tokenFloat
+		l.pos++
+		if l.pos < len(l.source) && (l.source[l.pos] == '+' || l.source[l.pos] == '-') {
+			l.pos++
+		}
+		exponent := l.pos
+		for l.pos < len(l.source) && isDigit(l.source[l.pos]) {
+			l.pos++
+		}
+		if l.pos == exponent {
+			return token{}, l.errorf(start, "invalid number")
+		}
+	}
+	return token{kind: kind, value: l.source[start:l.pos], pos: start}, nil
+}
+
+// readString reads a quoted string token with its escape sequences.
+func (l *lexer) readString() (token, error) {
+	start := l.pos
+	l.pos++
+	var value strings.Builder
+	for l.pos < len(l.source) {
+		c := l.source[l.pos]
+		switch c {
+		case '"':
+			l.pos++
+			return token{kind: tokenString, value: value.String(), pos: start}, nil
+		case '\n', '\r':
+			return token{}, l.errorf(start, "unterminated string")
+		case '\\':
+			if l.pos+1 >= len(l.source) {
+				return token{}, l.errorf(start, "unterminated string")
+			}
+			escaped := l.source[l.pos+1]
+			l.pos += 2
+			switch escaped {
+			case '"', '\\', '/':
+				value.WriteByte(escaped)
+			case 'b':
+				value.WriteByte('\b')
+			case 'f':
+				value.WriteByte('\f')
+			case 'n':
+				value.WriteByte('\n')
+			case 'r':
+				value.WriteByte('\r')
+			case 't':
+				value.WriteByte('\t')
+			case 'u':
+				if l.pos+4 > len(l.source) {
+					return token{}, l.errorf(start, "invalid unicode escape")
+				}
+				code, err := strconv.ParseUint(l.source[l.pos:l.pos+4], 16, 32)
+				if err != nil {
+					return token{}, l.errorf(start, "invalid unicode escape")
+				}
+				value.WriteRune(rune(code))
+				l.pos += 4
+			default:
+				return token{}, l.errorf(start, "invalid escape sequence \\%c", escaped)
+			}
+		default:
+			r, size := utf8.DecodeRuneInString(l.source[l.pos:])
+			value.WriteRune(r)
+			l.pos += size
+		}
+	}
+	return token{}, l.errorf(start, "unterminated string")
+}
+
+// readBlockString reads a block string token, the common indentation and the blank first and last lines are removed.
+func (l *lexer) readBlockString() (token, error) {
+	start := l.pos
+	l.pos += 3
+	end := strings.Index(l.source[l.pos:], `"""`)
+	if end < 0 {
+		return token{}, l.errorf(start, "unterminated block string")
+	}
+	raw := strings.ReplaceAll(l.source[l.pos:l.pos+end], `\"""`, `"""`)
+	l.pos += end + 3
+
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	indent := -1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed != "" && (indent < 0 || len(line)-len(trimmed) < indent) {
+			indent = len(line) - len(trimmed)
+		}
+	}
+	for i := 1; i < len(lines) && indent > 0; i++ {
+		lines[i] = lines[i][min(indent, len(lines[i])):]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return token{kind: tokenString, value: strings.Join(lines, "\n"), pos: start}, nil
+}
+
+// errorf returns a syntax error with the line and column of the position.
+func (l *lexer) errorf(pos int, format string, args ...any) error {
+	line := strings.Count(l.source[:pos], "\n") + 1
+	column := pos - strings.LastIndex(l.source[:pos], "\n")
+	return fmt.Errorf("syntax error at line %d column %d: %s", line, column, fmt.Sprintf(format, args...))
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// document is a parsed GraphQL document with its operations and fragments.
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+// operation is a query, mutation or subscription of a document.
+type operation struct {
+	kind       string
+	name       string
+	variables  []*variableDefinition
+	selections []selection
+}
+
+// variableDefinition is a variable of an operation with its type and default value.
+type variableDefinition struct {
+	name         string
+	typ          string
+	defaultValue any
+	hasDefault   bool
+}
+
+// fragment is a named fragment of a document.
+type fragment struct {
+	name          string
+	typeCondition string
+	selections    []selection
+}
+
+// selection is a field, a fragment spread or an inline fragment.
+type selection interface{}
+
+// field is a selected field with its alias, arguments, directives and sub selections.
+type field struct {
+	alias      string
+	name       string
+	arguments  map[string]any
+	directives []*directive
+	selections []selection
+}
+
+// responseKey returns the key of the field in the response, the alias if it has one.
+func (f *field) responseKey() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+// fragmentSpread is a spread of a named fragment.
+type fragmentSpread struct {
+	name       string
+	directives []*directive
+}
+
+// inlineFragment is an inline fragment with an optional type condition.
+type inlineFragment struct {
+	typeCondition string
+	directives    []*directive
+	selections    []selection
+}
+
+// directive is a directive of a selection, eg. @include(if: $withTask).
+type directive struct {
+	name      string
+	arguments map[string]any
+}
+
+// variableValue is a variable used as value, it is replaced by the value of the variable on execution.
+type variableValue string
+
+// enumValue is an enum value, it is coerced to its name as string.
+type enumValue string
+
+// parser parses a GraphQL document from the tokens of the lexer.
+type parser struct {
+	lexer   *lexer
+	current token
+}
+
+// parse parses the GraphQL document.
+func parse(source string) (*document, error) {
+	p := &parser{lexer: &lexer{source: source}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &document{fragments: map[string]*fragment{}}
+	for p.current.kind != tokenEOF {
+		switch {
+		case p.peek(tokenPunctuator, "{"):
+			selections, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, &operation{kind: "query", selections: selections})
+		case p.peek(tokenName, "query") || p.peek(tokenName, "mutation") || p.peek(tokenName, "subscription"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case p.peek(tokenName, "fragment"):
+			frag, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := doc.fragments[frag.name]; ok {
+				return nil, fmt.Errorf("fragment %s is defined more than once", frag.name)
+			}
+			doc.fragments[frag.name] = frag
+		default:
+			return nil, p.unexpected()
+		}
+	}
+
+	if len(doc.operations) == 0 {
+		return nil, fmt.Errorf("document has no operation")
+	}
+	return doc, nil
+}
+
+// advance reads the next token.
+func (p *parser) advance() error {
+	next, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.current = next
+	return nil
+}
+
+// peek reports if the current token is of the kind and has the value.
+func (p *parser) peek(kind int, value string) bool {
+	return p.current.kind == kind && p.current.value == value
+}
+
+// skip advances if the current token is the punctuator, it reports if it was skipped.
+func (p *parser) skip(punctuator string) (bool, error) {
+	if !p.peek(tokenPunctuator, punctuator) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+// expect advances over the punctuator or returns an error if it is not the current token.
+func (p *parser) expect(punctuator string) error {
+	if !p.peek(tokenPunctuator, punctuator) {
+		return p.unexpected()
+	}
+	return p.advance()
+}
+
+// name returns the current name token and advances over it.
+func (p *parser) name() (string, error) {
+	if p.current.kind != tokenName {
+		return "", p.unexpected()
+	}
+	name := p.current.value
+	return name, p.advance()
+}
+
+// unexpected returns a syntax error for the current token.
+func (p *parser) unexpected() error {
+	if p.current.kind == tokenEOF {
+		return p.lexer.errorf(p.current.pos, "unexpected end of document")
+	}
+	return p.lexer.errorf(p.current.pos, "unexpected %q", p.current.value)
+}
+
+// parseOperation parses an operation with its name, variable definitions and selection set.
+func (p *parser) parseOperation() (*operation, error) {
+	op := &operation{kind: p.current.value}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.current.kind == tokenName {
+		op.name = p.current.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	if ok, err := p.skip("("); err != nil {
+		return nil, err
+	} else if ok {
+		for !p.peek(tokenPunctuator, ")") {
+			variable, err := p.parseVariableDefinition()
+			if err != nil {
+				return nil, err
+			}
+			op.variables = append(op.variables, variable)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.selections = selections
+	return op, nil
+}
+
+// parseVariableDefinition parses a variable definition, eg. $status: String = "queued".
+func (p *parser) parseVariableDefinition() (*variableDefinition, error) {
+	if err := p.expect("$"); err != nil {
+		return nil, err
+	}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	typ, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+
+	variable := &variableDefinition{name: name, typ: typ}
+	if ok, err := p.skip("="); err != nil {
+		return nil, err
+	} else if ok {
+		variable.defaultValue, err = p.parseValue(true)
+		if err != nil {
+			return nil, err
+		}
+		variable.hasDefault = true
+	}
+	return variable, nil
+}
+
+// parseType parses a type reference, eg. [String!]!, and returns it as written.
+func (p *parser) parseType() (string, error) {
+	var typ string
+	if ok, err := p.skip("["); err != nil {
+		return "", err
+	} else if ok {
+		itemType, err := p.parseType()
+		if err != nil {
+			return "", err
+		}
+		if err := p.expect("]"); err != nil {
+			return "", err
+		}
+		typ = "[" + itemType + "]"
+	} else {
+		typ, err = p.name()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if ok, err := p.skip("!"); err != nil {
+		return "", err
+	} else if ok {
+		typ += "!"
+	}
+	return typ, nil
+}
+
+// parseFragment parses a named fragment with its type condition.
+func (p *parser) parseFragment() (*fragment, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if name == "on" {
+		return nil, p.lexer.errorf(p.current.pos, "fragment must not be named on")
+	}
+	if !p.peek(tokenName, "on") {
+		return nil, p.unexpected()
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	typeCondition, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	return &fragment{name: name, typeCondition: typeCondition, selections: selections}, nil
+}
+
+// parseSelectionSet parses the selections in braces.
+func (p *parser) parseSelectionSet() ([]selection, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	selections := []selection{}
+	for !p.peek(tokenPunctuator, "}") {
+		sel, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, sel)
+	}
+	if len(selections) == 0 {
+		return nil, p.unexpected()
+	}
+	return selections, p.advance()
+}
+
+// parseSelection parses a field, a fragment spread or an inline fragment.
+func (p *parser) parseSelection() (selection, error) {
+	if ok, err := p.skip("..."); err != nil {
+		return nil, err
+	} else if ok {
+		if p.current.kind == tokenName && p.current.value != "on" {
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			directives, err := p.parseDirectives()
+			if err != nil {
+				return nil, err
+			}
+			return &fragmentSpread{name: name, directives: directives}, nil
+		}
+
+		inline := &inlineFragment{}
+		if p.peek(tokenName, "on") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			inline.typeCondition, err = p.name()
+			if err != nil {
+				return nil, err
+			}
+		}
+		inline.directives, err = p.parseDirectives()
+		if err != nil {
+			return nil, err
+		}
+		inline.selections, err = p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+		return inline, nil
+	}
+
+	f := &field{}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := p.skip(":"); err != nil {
+		return nil, err
+	} else if ok {
+		f.alias = name
+		name, err = p.name()
+		if err != nil {
+			return nil, err
+		}
+	}
+	f.name = name
+
+	f.arguments, err = p.parseArguments()
+	if err != nil {
+		return nil, err
+	}
+	f.directives, err = p.parseDirectives()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek(tokenPunctuator, "{") {
+		f.selections, err = p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// parseArguments parses the arguments in parentheses, it returns nil without arguments.
+func (p *parser) parseArguments() (map[string]any, error) {
+	if ok, err := p.skip("("); err != nil || !ok {
+		return nil, err
+	}
+
+	arguments := map[string]any{}
+	for !p.peek(tokenPunctuator, ")") {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := arguments[name]; ok {
+			return nil, p.lexer.errorf(p.current.pos, "argument %s is given more than once", name)
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		arguments[name], err = p.parseValue(false)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return arguments, p.advance()
+}
+
+// parseDirectives parses the directives of a selection.
+func (p *parser) parseDirectives() ([]*directive, error) {
+	directives := []*directive{}
+	for p.peek(tokenPunctuator, "@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		arguments, err := p.parseArguments()
+		if err != nil {
+			return nil, err
+		}
+		directives = append(directives, &directive{name: name, arguments: arguments})
+	}
+	return directives, nil
+}
+
+// parseValue parses a value, constant values must not contain variables, eg. default values of variables.
+func (p *parser) parseValue(constant bool) (any, error) {
+	current := p.current
+	switch current.kind {
+	case tokenInt:
+		value, err := strconv.Atoi(current.value)
+		if err != nil {
+			return nil, p.lexer.errorf(current.pos, "invalid int %s", current.value)
+		}
+		return value, p.advance()
+	case tokenFloat:
+		value, err := strconv.ParseFloat(current.value, 64)
+		if err != nil {
+			return nil, p.lexer.errorf(current.pos, "invalid float %s", current.value)
+		}
+		return value, p.advance()
+	case tokenString:
+		return current.value, p.advance()
+	case tokenName:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		switch current.value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return enumValue(current.value), nil
+	case tokenPunctuator:
+		switch current.value {
+		case "$":
+			if constant {
+				return nil, p.lexer.errorf(current.pos, "variables are not allowed in constant values")
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			return variableValue(name), nil
+		case "[":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			list := []any{}
+			for !p.peek(tokenPunctuator, "]") {
+				item, err := p.parseValue(constant)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, item)
+			}
+			return list, p.advance()
+		case "{":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			object := map[string]any{}
+			for !p.peek(tokenPunctuator, "}") {
+				name, err := p.name()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expect(":"); err != nil {
+					return nil, err
+				}
+				object[name], err = p.parseValue(constant)
+				if err != nil {
+					return nil, err
+				}
+			}
+			return object, p.advance()
+		}
+	}
+	return nil, p.unexpected()
+}
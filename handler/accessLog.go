@@ -0,0 +1,413 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/labstack/echo/v5"
+)
+
+// accessLogBatchSize is the maximum number of access logs written at once.
+const accessLogBatchSize = 100
+
+// accessLogExportPageSize is the number of access logs loaded at once while streaming the access log export.
+const accessLogExportPageSize = 500
+
+// accessLogExcludedPaths are never logged, the static files and the probes polled by load balancers.
+var accessLogExcludedPaths = []string{"/static/", "/health", "/queuerBanner"}
+
+// accessLogExportHeader is the header of the CSV access log export.
+var accessLogExportHeader = []string{"id", "created_at", "user", "ip", "method", "route", "path", "query", "status", "latency_ms"}
+
+// AccessLogger buffers the access logs of the requests and writes them in batches, so requests do not wait for the database.
+// If the buffer is full the access logs are dropped and counted instead of blocking the requests.
+type AccessLogger struct {
+	write   func(accessLogs []*model.AccessLog) error
+	entries chan *model.AccessLog
+	dropped atomic.Int64
+}
+
+// NewAccessLogger creates an access logger writing the buffered access logs with write, eg. to the database.
+func NewAccessLogger(write func(accessLogs []*model.AccessLog) error, bufferSize int) *AccessLogger {
+	return &AccessLogger{
+		write:   write,
+		entries: make(chan *model.AccessLog, bufferSize),
+	}
+}
+
+// Log buffers the access log, it is dropped if the buffer is full.
+func (l *AccessLogger) Log(accessLog *model.AccessLog) {
+	select {
+	case l.entries <- accessLog:
+	default:
+		l.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of access logs dropped because the buffer was full.
+func (l *AccessLogger) Dropped() int64 {
+	return l.dropped.Load()
+}
+
+// Run writes the buffered access logs every interval and when a batch is full until the context is done.
+// The remaining access logs are written before it returns.
+func (l *AccessLogger) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	batch := make([]*model.AccessLog, 0, accessLogBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		err := l.write(batch)
+		if err != nil {
+			log.Printf("Failed to write %d access logs: %v", len(batch), err)
+		}
+		batch = make([]*model.AccessLog, 0, accessLogBatchSize)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			for {
+				select {
+				case accessLog := <-l.entries:
+					batch = append(batch, accessLog)
+					if len(batch) >= accessLogBatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		case accessLog := <-l.entries:
+			batch = append(batch, accessLog)
+			if len(batch) >= accessLogBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// AccessLogMiddleware logs the route, user, status and latency of the requests.
+// It is registered before the authentication, so rejected requests are logged with the IP of the client.
+func (m *ManagerHandler) AccessLogMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c *echo.Context) error {
+		path := c.Request().URL.Path
+		if m.AccessLogs == nil || slices.ContainsFunc(accessLogExcludedPaths, func(excludedPath string) bool {
+			return strings.HasPrefix(path, excludedPath)
+		}) {
+			return next(c)
+		}
+
+		start := time.Now()
+		// The response of echo is unwrapped before the handler, later middlewares replace the writer of the context
+		response, _ := echo.UnwrapResponse(c.Response())
+
+		handlerErr := next(c)
+
+		accessLog := &model.AccessLog{
+			Method:    c.Request().Method,
+			Route:     c.Path(),
+			Path:      path,
+			Query:     sanitizeRecordedQuery(c.Request().URL.Query()),
+			IP:        c.RealIP(),
+			Status:    accessLogStatus(response, handlerErr),
+			LatencyMs: time.Since(start).Milliseconds(),
+			CreatedAt: start,
+		}
+		if user := model.GetRequestContext(c).User; user != nil {
+			accessLog.User = user.Username
+		}
+		m.AccessLogs.Log(accessLog)
+
+		return handlerErr
+	}
+}
+
+// accessLogStatus returns the status of the response, errors not written yet are written by the error handler of echo afterwards.
+func accessLogStatus(response *echo.Response, handlerErr error) int {
+	if response != nil && response.Committed {
+		return response.Status
+	}
+	if handlerErr != nil {
+		var statusCoder echo.HTTPStatusCoder
+		if errors.As(handlerErr, &statusCoder) {
+			return statusCoder.StatusCode()
+		}
+		return http.StatusInternalServerError
+	}
+	return http.StatusOK
+}
+
+// DeleteExpiredAccessLogs deletes the access logs older than the retention of the deployment.
+func (m *ManagerHandler) DeleteExpiredAccessLogs() (int64, error) {
+	if m.AccessLogDB == nil {
+		return 0, nil
+	}
+
+	deleted, err := m.AccessLogDB.DeleteAccessLogsBefore(time.Now().AddDate(0, 0, -m.accessLogRetentionDays()))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired access logs: %w", err)
+	}
+	return deleted, nil
+}
+
+// accessLogRetentionDays returns the number of days the access logs are kept, the default without configuration.
+func (m *ManagerHandler) accessLogRetentionDays() int {
+	if m.AccessLogRetentionDays <= 0 {
+		return model.DEFAULT_ACCESS_LOG_RETENTION_DAYS
+	}
+	return m.AccessLogRetentionDays
+}
+
+// =======API Handlers=======
+
+// GetAccessLogs retrieves a paginated list of the access logs, newest first,
+// optionally filtered by user, route (prefix), method, status and the RFC3339 times from and to.
+func (m *ManagerHandler) GetAccessLogs(c *echo.Context) error {
+	if m.AccessLogDB == nil {
+		return c.String(http.StatusServiceUnavailable, "Access logs are not enabled")
+	}
+
+	lastId, limit, err := m.apiPagination(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	filter, err := accessLogFilterFromQuery(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	accessLogs, err := m.AccessLogDB.SelectAccessLogs(filter, lastId, limit)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to retrieve access logs")
+	}
+
+	return c.JSON(http.StatusOK, accessLogs)
+}
+
+// ExportAccessLogs streams the access logs matching the filters of GetAccessLogs as download, newest first,
+// eg. /api/accessLog/exportAccessLogs?format=csv&user=alice&route=/api/job/cancelJob&from=&to=. format is csv (default), json or ndjson.
+func (m *ManagerHandler) ExportAccessLogs(c *echo.Context) error {
+	if m.AccessLogDB == nil {
+		return c.String(http.StatusServiceUnavailable, "Access logs are not enabled")
+	}
+
+	filter, err := accessLogFilterFromQuery(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	format := c.QueryParam("format")
+	if format == "" {
+		format = "csv"
+	}
+	contentType, ok := jobArchiveExportFormats[format]
+	if !ok {
+		return c.String(http.StatusBadRequest, "Invalid format (must be csv, json or ndjson)")
+	}
+
+	filename := fmt.Sprintf("access_logs_%s.%s", time.Now().Format("20060102"), format)
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Response().Header().Set("Content-Type", contentType)
+	c.Response().WriteHeader(http.StatusOK)
+
+	writer, err := newAccessLogExportWriter(format, c.Response())
+	if err != nil {
+		return err
+	}
+
+	// Stream the access logs page by page, the response is flushed after each page
+	responseController := http.NewResponseController(c.Response())
+	lastId := 0
+	for {
+		accessLogs, err := m.AccessLogDB.SelectAccessLogs(filter, lastId, accessLogExportPageSize)
+		if err != nil {
+			log.Printf("Error selecting access logs for export: %v", err)
+			return err
+		}
+
+		for _, accessLog := range accessLogs {
+			if err := writer.Write(accessLog); err != nil {
+				return err
+			}
+		}
+
+		if len(accessLogs) < accessLogExportPageSize {
+			return writer.Close()
+		}
+
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+		if err := responseController.Flush(); err != nil {
+			return err
+		}
+		lastId = accessLogs[len(accessLogs)-1].ID
+	}
+}
+
+// =======View Handlers=======
+
+// AccessLogsView renders the access log view with the filters of the query
+func (m *ManagerHandler) AccessLogsView(c *echo.Context) error {
+	c.Response().Header().Add("HX-Push-Url", "/accessLogs")
+	c.Response().Header().Add("HX-Retarget", "#body")
+
+	if m.AccessLogDB == nil {
+		return render(c, screens.AccessLogs([]*model.AccessLog{}, &model.AccessLogFilter{}, "", false))
+	}
+
+	filter, err := accessLogFilterFromQuery(c)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+
+	lastId, limit, err := m.viewPagination(c)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+
+	accessLogs, err := m.AccessLogDB.SelectAccessLogs(filter, lastId, limit)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, "Failed to retrieve access logs")
+	}
+
+	return render(c, screens.AccessLogs(accessLogs, filter, c.Request().URL.RawQuery, true))
+}
+
+// =======Helpers=======
+
+// accessLogFilterFromQuery parses the filters of the access logs from the query parameters user, route (prefix of the route),
+// method, status and the time range from and to as RFC3339 times.
+func accessLogFilterFromQuery(c *echo.Context) (*model.AccessLogFilter, error) {
+	filter := &model.AccessLogFilter{
+		User:   strings.TrimSpace(c.QueryParam("user")),
+		Route:  strings.TrimSpace(c.QueryParam("route")),
+		Method: strings.ToUpper(strings.TrimSpace(c.QueryParam("method"))),
+	}
+
+	if statusStr := c.QueryParam("status"); statusStr != "" {
+		status, err := strconv.Atoi(statusStr)
+		if err != nil || status < 100 || status > 599 {
+			return nil, fmt.Errorf("Invalid status (must be an HTTP status code)")
+		}
+		filter.Status = status
+	}
+
+	if fromStr := c.QueryParam("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid from format (must be RFC3339)")
+		}
+		filter.From = from
+	}
+
+	if toStr := c.QueryParam("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid to format (must be RFC3339)")
+		}
+		filter.To = to
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.From.After(filter.To) {
+		return nil, fmt.Errorf("Invalid time range (from must be before to)")
+	}
+
+	return filter, nil
+}
+
+// accessLogExportWriter writes the access logs of an export in one of the export formats.
+type accessLogExportWriter struct {
+	format  string
+	writer  io.Writer
+	csv     *csv.Writer
+	written int
+}
+
+// newAccessLogExportWriter creates a writer of the format and writes the start of the export.
+func newAccessLogExportWriter(format string, writer io.Writer) (*accessLogExportWriter, error) {
+	exportWriter := &accessLogExportWriter{format: format, writer: writer}
+	switch format {
+	case "csv":
+		exportWriter.csv = csv.NewWriter(writer)
+		return exportWriter, exportWriter.csv.Write(accessLogExportHeader)
+	case "json":
+		_, err := io.WriteString(writer, "[")
+		return exportWriter, err
+	}
+	return exportWriter, nil
+}
+
+// Write writes an access log, a CSV record or the access log as JSON,
+// separated by commas in a JSON array and by newlines in NDJSON.
+func (w *accessLogExportWriter) Write(accessLog *model.AccessLog) error {
+	defer func() { w.written++ }()
+
+	if w.format == "csv" {
+		return w.csv.Write([]string{
+			strconv.Itoa(accessLog.ID),
+			accessLog.CreatedAt.Format(time.RFC3339),
+			accessLog.User,
+			accessLog.IP,
+			accessLog.Method,
+			accessLog.Route,
+			accessLog.Path,
+			accessLog.Query,
+			strconv.Itoa(accessLog.Status),
+			strconv.FormatInt(accessLog.LatencyMs, 10),
+		})
+	}
+
+	accessLogJSON, err := json.Marshal(accessLog)
+	if err != nil {
+		return err
+	}
+	switch {
+	case w.format == "ndjson":
+		accessLogJSON = append(accessLogJSON, '\n')
+	case w.written > 0:
+		accessLogJSON = append([]byte(","), accessLogJSON...)
+	}
+	_, err = w.writer.Write(accessLogJSON)
+	return err
+}
+
+// Flush writes buffered CSV records to the underlying writer.
+func (w *accessLogExportWriter) Flush() error {
+	if w.csv == nil {
+		return nil
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+// Close writes the end of the export.
+func (w *accessLogExportWriter) Close() error {
+	if w.format == "json" {
+		_, err := io.WriteString(w.writer, "]")
+		return err
+	}
+	return w.Flush()
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLogMiddleware(t *testing.T) {
+	var mu sync.Mutex
+	written := []*model.AccessLog{}
+	write := func(accessLogs []*model.AccessLog) error {
+		mu.Lock()
+		defer mu.Unlock()
+		written = append(written, accessLogs...)
+		return nil
+	}
+
+	m := &ManagerHandler{AccessLogs: NewAccessLogger(write, 10)}
+	e := echo.New()
+	e.Use(m.AccessLogMiddleware)
+	e.POST("/api/job/cancelJob/:rid", func(c *echo.Context) error {
+		rc := model.GetRequestContext(c)
+		rc.User = &model.User{Username: "alice"}
+		model.SetRequestContext(c, rc)
+		return c.String(http.StatusOK, "cancelled")
+	})
+	e.GET("/api/task/getTask/:rid", func(c *echo.Context) error {
+		return echo.ErrNotFound
+	})
+	e.GET("/static/app.js", func(c *echo.Context) error {
+		return c.String(http.StatusOK, "")
+	})
+
+	for _, request := range []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/api/job/cancelJob/1?password=secret", nil),
+		httptest.NewRequest(http.MethodGet, "/api/task/getTask/2", nil),
+		httptest.NewRequest(http.MethodGet, "/static/app.js", nil),
+	} {
+		e.ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	m.AccessLogs.Run(ctx, time.Minute)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, written, 2, "Expected the static file to not be logged")
+
+	assert.Equal(t, "/api/job/cancelJob/:rid", written[0].Route)
+	assert.Equal(t, "/api/job/cancelJob/1", written[0].Path)
+	assert.Equal(t, "alice", written[0].User)
+	assert.Equal(t, http.StatusOK, written[0].Status)
+	assert.Equal(t, "password=%5BREDACTED%5D", written[0].Query)
+	assert.NotContains(t, written[0].Query, "secret")
+
+	assert.Equal(t, "", written[1].User)
+	assert.Equal(t, http.StatusNotFound, written[1].Status)
+}
+
+func TestAccessLogger(t *testing.T) {
+	t.Run("Should drop access logs if the buffer is full", func(t *testing.T) {
+		accessLogger := NewAccessLogger(func(accessLogs []*model.AccessLog) error { return nil }, 1)
+		accessLogger.Log(&model.AccessLog{})
+		accessLogger.Log(&model.AccessLog{})
+
+		assert.Equal(t, int64(1), accessLogger.Dropped())
+	})
+}
+
+func TestAccessLogFilterFromQuery(t *testing.T) {
+	e := echo.New()
+	filterFromQuery := func(query string) (*model.AccessLogFilter, error) {
+		req := httptest.NewRequest(http.MethodGet, "/api/accessLog/getAccessLogs?"+query, nil)
+		return accessLogFilterFromQuery(e.NewContext(req, httptest.NewRecorder()))
+	}
+
+	t.Run("Should parse the filters", func(t *testing.T) {
+		filter, err := filterFromQuery("user=alice&route=/api/job/cancelJob&method=post&status=200&from=2026-10-06T00:00:00Z&to=2026-10-07T00:00:00Z")
+		require.NoError(t, err)
+		assert.Equal(t, "alice", filter.User)
+		assert.Equal(t, "POST", filter.Method)
+		assert.Equal(t, 200, filter.Status)
+		assert.Equal(t, time.Date(2026, 10, 6, 0, 0, 0, 0, time.UTC), filter.From.UTC())
+	})
+
+	t.Run("Should reject invalid filters", func(t *testing.T) {
+		_, err := filterFromQuery("status=abc")
+		assert.ErrorContains(t, err, "Invalid status")
+
+		_, err = filterFromQuery("from=yesterday")
+		assert.ErrorContains(t, err, "Invalid from")
+
+		_, err = filterFromQuery("from=2026-10-07T00:00:00Z&to=2026-10-06T00:00:00Z")
+		assert.ErrorContains(t, err, "Invalid time range")
+	})
+}
+
+func TestAccessLogExportWriter(t *testing.T) {
+	accessLogs := []*model.AccessLog{
+		{ID: 2, Method: "POST", Route: "/api/job/cancelJob/:rid", Path: "/api/job/cancelJob/1", User: "alice", Status: 200, LatencyMs: 12, CreatedAt: time.Date(2026, 10, 6, 9, 0, 0, 0, time.UTC)},
+		{ID: 1, Method: "GET", Route: "/api/task/getTasks", Path: "/api/task/getTasks", IP: "10.0.0.1", Status: 401},
+	}
+
+	t.Run("Should write CSV records", func(t *testing.T) {
+		var buffer bytes.Buffer
+		writer, err := newAccessLogExportWriter("csv", &buffer)
+		require.NoError(t, err)
+		for _, accessLog := range accessLogs {
+			require.NoError(t, writer.Write(accessLog))
+		}
+		require.NoError(t, writer.Close())
+
+		assert.Contains(t, buffer.String(), "id,created_at,user,ip,method,route,path,query,status,latency_ms\n")
+		assert.Contains(t, buffer.String(), "2,2026-10-06T09:00:00Z,alice,,POST,/api/job/cancelJob/:rid,/api/job/cancelJob/1,,200,12\n")
+	})
+
+	t.Run("Should write a JSON array", func(t *testing.T) {
+		var buffer bytes.Buffer
+		writer, err := newAccessLogExportWriter("json", &buffer)
+		require.NoError(t, err)
+		for _, accessLog := range accessLogs {
+			require.NoError(t, writer.Write(accessLog))
+		}
+		require.NoError(t, writer.Close())
+
+		exported := []*model.AccessLog{}
+		require.NoError(t, json.Unmarshal(buffer.Bytes(), &exported))
+		assert.Len(t, exported, 2)
+	})
+
+	t.Run("Should not export without database", func(t *testing.T) {
+		m := &ManagerHandler{}
+		req := httptest.NewRequest(http.MethodGet, "/api/accessLog/exportAccessLogs", nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, m.ExportAccessLogs(echo.New().NewContext(req, rec)))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+}
@@ -2,8 +2,11 @@ package handler
 
 import (
 	"fmt"
+	"log"
 	"net/http"
+	"time"
 
+	"github.com/siherrmann/queuerManager/model"
 	"github.com/siherrmann/queuerManager/view/screens"
 
 	"github.com/labstack/echo/v5"
@@ -15,15 +18,27 @@ func (m *ManagerHandler) AddJobView(c *echo.Context) error {
 		return c.String(http.StatusInternalServerError, "Failed to retrieve tasks")
 	}
 
+	// Show the queue forecast on the dashboard if metrics are enabled
+	var forecast *model.QueueForecast
+	if m.MetricDB != nil {
+		forecast, err = m.queueForecast(time.Hour)
+		if err != nil {
+			log.Printf("Failed to calculate queue forecast: %v", err)
+		}
+	}
+
 	c.Response().Header().Add("HX-Push-Url", "/")
 	c.Response().Header().Add("HX-Retarget", "#body")
 
-	return render(c, screens.AddJob(tasks))
+	return render(c, screens.AddJob(tasks, forecast))
 }
 
-// AddJobConfigView renders a task-specific screen with parameter inputs
+// AddJobConfigView renders a task-specific screen with parameter inputs.
+// With test=true the inputs are prefilled with the sample values of the task and the job is added as a test run,
+// otherwise the job templates of the task are listed to run them with one click.
 func (m *ManagerHandler) AddJobConfigView(c *echo.Context) error {
 	taskKey := c.Param("taskKey")
+	test := c.QueryParam("test") == "true"
 	task, err := m.taskDB.SelectTaskByKey(taskKey)
 	if err != nil {
 		return renderPopupOrJson(c, http.StatusBadRequest, "Missing or non-existent task name")
@@ -34,8 +49,28 @@ func (m *ManagerHandler) AddJobConfigView(c *echo.Context) error {
 		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Error listing files: %v", err))
 	}
 
-	c.Response().Header().Add("HX-Push-Url", fmt.Sprintf("/task/%s", task.Key))
+	sampleValues := map[string]string{}
+	if test && m.TaskSampleDB != nil {
+		if taskSample, err := m.TaskSampleDB.SelectTaskSample(task.Key); err == nil {
+			sampleValues = taskSample.Values
+		}
+	}
+
+	// Templates are listed when adding a job, nil hides them if templates are not enabled
+	var jobTemplates []*model.JobTemplate
+	if !test && m.JobTemplateDB != nil {
+		jobTemplates, err = m.JobTemplateDB.SelectJobTemplatesByTask(task.Key)
+		if err != nil {
+			return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Error listing job templates: %v", err))
+		}
+	}
+
+	if test {
+		c.Response().Header().Add("HX-Push-Url", fmt.Sprintf("/task/%s?test=true", task.Key))
+	} else {
+		c.Response().Header().Add("HX-Push-Url", fmt.Sprintf("/task/%s", task.Key))
+	}
 	c.Response().Header().Add("HX-Retarget", "#body")
 
-	return render(c, screens.AddJobConfig(task, files))
+	return render(c, screens.AddJobConfig(task, files, sampleValues, test, jobTemplates))
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/siherrmann/queuerManager/database"
 	qmModel "github.com/siherrmann/queuerManager/model"
 	"github.com/siherrmann/queuerManager/upload"
+	vm "github.com/siherrmann/validator/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -78,6 +79,32 @@ func TestAddJobConfigViewHandler(t *testing.T) {
 		assert.Contains(t, rec.Header().Get("HX-Retarget"), "#body")
 	})
 
+	t.Run("AddJobConfigView with parameter docs", func(t *testing.T) {
+		task, err := tdb.InsertTask(&qmModel.Task{
+			Key:  "test-config-view-docs",
+			Name: "Test Config View Docs",
+			InputParameters: []vm.Validation{
+				{Key: "date", Type: vm.String, Requirement: "min1"},
+				{Key: "count", Type: vm.Int, Requirement: "min1"},
+			},
+			ParameterDocs: map[string]qmModel.ParameterDoc{"date": {Description: "Day of the report", Example: "2026-01-31"}},
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/task/"+task.Key, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: task.Key}})
+
+		err = handler.AddJobConfigView(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `placeholder="2026-01-31"`)
+		assert.Contains(t, rec.Body.String(), "Day of the report")
+		assert.Contains(t, rec.Body.String(), `placeholder="min1"`, "Expected parameters without example to use the requirement as placeholder")
+	})
+
 	t.Run("AddJobConfigView with non-existent task", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/task/nonexistent-task", nil)
 		rec := httptest.NewRecorder()
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/siherrmann/queuerManager/helper"
+	qmModel "github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/model"
+)
+
+// scheduleMissTolerance is the delay after which a late schedule run counts as missed, eg. because the manager was down.
+const scheduleMissTolerance = 5 * time.Minute
+
+// validateTaskAlerts checks that the alerts of a task are alert kinds and removes duplicates.
+func validateTaskAlerts(task *qmModel.Task) error {
+	alerts := []string{}
+	for _, alert := range task.Alerts {
+		if !slices.Contains(qmModel.ALERT_KINDS, alert) {
+			return fmt.Errorf("invalid alert %q (must be one of %s)", alert, strings.Join(qmModel.ALERT_KINDS, ", "))
+		}
+		if !slices.Contains(alerts, alert) {
+			alerts = append(alerts, alert)
+		}
+	}
+	task.Alerts = alerts
+	return nil
+}
+
+// alertURL returns the absolute URL of a view for an alert, or an empty string if no base URL is set.
+func alertURL(path string) string {
+	baseURL := helper.GetEnvOrDefault("QUEUER_MANAGER_BASE_URL", "")
+	if baseURL == "" {
+		return ""
+	}
+	return strings.TrimRight(baseURL, "/") + path
+}
+
+// sendAlert sends an alert without blocking the caller.
+func (m *ManagerHandler) sendAlert(alert *qmModel.Alert) {
+	if m.Alerter == nil {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		err := m.Alerter.Send(ctx, alert)
+		m.Status.Record(qmModel.SUBSYSTEM_NOTIFIER, err)
+		if err != nil {
+			log.Printf("Error sending %s alert: %v", alert.Kind, err)
+		}
+	}()
+}
+
+// AlertJobEnded sends the alert of a failed job if its task opted in to job failure alerts.
+func (m *ManagerHandler) AlertJobEnded(job *model.Job) {
+	if m.Alerter == nil || job == nil || job.Status != model.JobStatusFailed {
+		return
+	}
+
+	task, err := m.taskDB.SelectTaskByKey(job.TaskName)
+	if err != nil || !task.HasAlert(qmModel.ALERT_JOB_FAILED) {
+		return
+	}
+
+	m.sendAlert(&qmModel.Alert{
+		Kind:      qmModel.ALERT_JOB_FAILED,
+		TaskKeys:  []string{task.Key},
+		Title:     fmt.Sprintf("Job failed: %s", task.Key),
+		Message:   fmt.Sprintf("Job %s of task %s failed after %d attempts: %s", job.RID, task.Key, job.Attempts, job.Error),
+		URL:       alertURL("/job?rid=" + job.RID.String()),
+		CreatedAt: time.Now(),
+	})
+}
+
+// alertScheduleMissed sends the alert of a missed run of a schedule if its task opted in to missed schedule alerts.
+func (m *ManagerHandler) alertScheduleMissed(schedule *qmModel.Schedule, reason string) {
+	if m.Alerter == nil {
+		return
+	}
+
+	task, err := m.taskDB.SelectTaskByKey(schedule.TaskKey)
+	if err != nil || !task.HasAlert(qmModel.ALERT_SCHEDULE_MISSED) {
+		return
+	}
+
+	m.sendAlert(&qmModel.Alert{
+		Kind:      qmModel.ALERT_SCHEDULE_MISSED,
+		TaskKeys:  []string{task.Key},
+		Title:     fmt.Sprintf("Schedule missed its run: %s", schedule.Name),
+		Message:   fmt.Sprintf("Schedule %s of task %s %s", schedule.Name, task.Key, reason),
+		URL:       alertURL("/schedules"),
+		CreatedAt: time.Now(),
+	})
+}
+
+// AlertStaleWorkers sends the alerts of the ready and running workers whose last heartbeat is after since and not after until,
+// so every worker is alerted once when it became stale. Only workers with tasks that opted in to stale worker alerts are alerted.
+// It returns the number of alerted workers.
+func (m *ManagerHandler) AlertStaleWorkers(since time.Time, until time.Time) (int, error) {
+	if m.Alerter == nil {
+		return 0, nil
+	}
+
+	alerted := 0
+	optedIn := map[string]bool{}
+	lastID := 0
+	for {
+		workers, err := m.Queuer.GetWorkers(lastID, 100)
+		if err != nil {
+			return alerted, fmt.Errorf("error selecting workers: %w", err)
+		}
+		if len(workers) == 0 {
+			return alerted, nil
+		}
+		lastID = workers[len(workers)-1].ID
+
+		for _, worker := range workers {
+			if worker.Status != model.WorkerStatusReady && worker.Status != model.WorkerStatusRunning {
+				continue
+			}
+			if !worker.UpdatedAt.After(since) || worker.UpdatedAt.After(until) {
+				continue
+			}
+
+			taskKeys := []string{}
+			for _, taskKey := range worker.AvailableTasks {
+				if _, ok := optedIn[taskKey]; !ok {
+					task, err := m.taskDB.SelectTaskByKey(taskKey)
+					optedIn[taskKey] = err == nil && task.HasAlert(qmModel.ALERT_WORKER_STALE)
+				}
+				if optedIn[taskKey] {
+					taskKeys = append(taskKeys, taskKey)
+				}
+			}
+			if len(taskKeys) == 0 {
+				continue
+			}
+
+			m.sendAlert(&qmModel.Alert{
+				Kind:      qmModel.ALERT_WORKER_STALE,
+				TaskKeys:  taskKeys,
+				Title:     fmt.Sprintf("Worker stale: %s", worker.Name),
+				Message:   fmt.Sprintf("Worker %s (%s) of the tasks %s has not sent a heartbeat since %s", worker.Name, worker.RID, strings.Join(taskKeys, ", "), worker.UpdatedAt.Format(time.RFC3339)),
+				URL:       alertURL("/worker?rid=" + worker.RID.String()),
+				CreatedAt: time.Now(),
+			})
+			alerted++
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"testing"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTaskAlerts(t *testing.T) {
+	task := &qmModel.Task{Alerts: []string{qmModel.ALERT_JOB_FAILED, qmModel.ALERT_SCHEDULE_MISSED, qmModel.ALERT_JOB_FAILED}}
+	assert.NoError(t, validateTaskAlerts(task))
+	assert.Equal(t, []string{qmModel.ALERT_JOB_FAILED, qmModel.ALERT_SCHEDULE_MISSED}, task.Alerts, "Expected duplicate alerts to be removed")
+
+	assert.NoError(t, validateTaskAlerts(&qmModel.Task{}))
+
+	err := validateTaskAlerts(&qmModel.Task{Alerts: []string{"job_succeeded"}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "job_succeeded")
+}
+
+func TestAlertURL(t *testing.T) {
+	t.Setenv("QUEUER_MANAGER_BASE_URL", "")
+	assert.Empty(t, alertURL("/schedules"), "Expected no URL without base URL")
+
+	t.Setenv("QUEUER_MANAGER_BASE_URL", "https://manager.example.com/")
+	assert.Equal(t, "https://manager.example.com/schedules", alertURL("/schedules"))
+}
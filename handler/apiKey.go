@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/siherrmann/queuerManager/helper"
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/components"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/a-h/templ"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+)
+
+// apiKeyLastUsedInterval limits the updates of the last use of a key to one per interval
+const apiKeyLastUsedInterval = time.Minute
+
+// =======API Handlers=======
+
+// CreateAPIKey creates an API key with the name and scope of the JSON or form body.
+// The key is only returned in this response, only its hash is stored.
+func (m *ManagerHandler) CreateAPIKey(c *echo.Context) error {
+	if m.APIKeyDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "API keys are not enabled")
+	}
+
+	var apiKeyRequest model.APIKeyRequest
+	if err := c.Bind(&apiKeyRequest); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+	}
+	apiKeyRequest.Name = strings.TrimSpace(apiKeyRequest.Name)
+	if apiKeyRequest.Name == "" || len(apiKeyRequest.Name) > 100 {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Name is required (max 100 characters)")
+	}
+	if !model.IsValidAPIKeyScope(apiKeyRequest.Scope) {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid scope %q (must be %s, %s or %s)", apiKeyRequest.Scope, model.API_KEY_SCOPE_READ, model.API_KEY_SCOPE_WRITE, model.API_KEY_SCOPE_ADMIN))
+	}
+
+	key, prefix, keyHash, err := helper.NewAPIKey()
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to generate API key: %v", err))
+	}
+
+	insertedAPIKey, err := m.APIKeyDB.InsertAPIKey(&model.APIKey{
+		Name:      apiKeyRequest.Name,
+		Prefix:    prefix,
+		Scope:     apiKeyRequest.Scope,
+		CreatedBy: requestedBy(c),
+	}, keyHash)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to create API key: %v", err))
+	}
+
+	apiKeyCreated := &model.APIKeyCreated{APIKey: insertedAPIKey, Key: key}
+	if c.Request().Header.Get("HX-Request") != "" {
+		return renderAPIKeyWithKey(c, http.StatusCreated, apiKeyCreated, "#table_body_api_keys_table", "afterbegin", "closeCreateAPIKey")
+	}
+	return c.JSON(http.StatusCreated, apiKeyCreated)
+}
+
+// GetAPIKeys retrieves the API keys with pagination, including revoked keys.
+func (m *ManagerHandler) GetAPIKeys(c *echo.Context) error {
+	if m.APIKeyDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "API keys are not enabled")
+	}
+
+	lastId, limit, err := m.apiPagination(c)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+
+	apiKeys, err := m.APIKeyDB.SelectAllAPIKeys(lastId, limit)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get API keys: %v", err))
+	}
+
+	return renderPopupOrJson(c, http.StatusOK, apiKeys)
+}
+
+// RotateAPIKey replaces the key of the API key with the RID of the query, the old key stops working immediately.
+func (m *ManagerHandler) RotateAPIKey(c *echo.Context) error {
+	if m.APIKeyDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "API keys are not enabled")
+	}
+
+	rid, err := uuid.Parse(c.QueryParam("rid"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid API key RID format")
+	}
+
+	key, prefix, keyHash, err := helper.NewAPIKey()
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to generate API key: %v", err))
+	}
+
+	rotatedAPIKey, err := m.APIKeyDB.UpdateAPIKeyHash(rid, prefix, keyHash)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "API key not found or revoked")
+	}
+
+	apiKeyCreated := &model.APIKeyCreated{APIKey: rotatedAPIKey, Key: key}
+	if c.Request().Header.Get("HX-Request") != "" {
+		return renderAPIKeyWithKey(c, http.StatusOK, apiKeyCreated, "#table_row_"+rid.String(), "outerHTML", "closeRotateAPIKey")
+	}
+	return c.JSON(http.StatusOK, apiKeyCreated)
+}
+
+// RevokeAPIKeys revokes the API keys with the RIDs of the query.
+func (m *ManagerHandler) RevokeAPIKeys(c *echo.Context) error {
+	if m.APIKeyDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "API keys are not enabled")
+	}
+
+	ridStrings := c.QueryParams()["rid"]
+	if len(ridStrings) == 0 {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Missing API key RID")
+	}
+
+	revokedCount := 0
+	var errors []string
+	for _, ridStr := range ridStrings {
+		rid, err := uuid.Parse(ridStr)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Invalid RID %s: %v", ridStr, err))
+			continue
+		}
+
+		_, err = m.APIKeyDB.RevokeAPIKey(rid)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Failed to revoke API key %s: %v", ridStr, err))
+			continue
+		}
+		revokedCount++
+	}
+
+	status := http.StatusOK
+	message := fmt.Sprintf("Successfully revoked %d API key(s)", revokedCount)
+	if len(errors) > 0 {
+		status = http.StatusPartialContent
+		message = fmt.Sprintf("Revoked %d API keys. Errors: %v", revokedCount, errors)
+	}
+
+	// Swap all rows, so the revoked keys show their revocation time
+	if c.Request().Header.Get("HX-Request") != "" {
+		apiKeys, err := m.APIKeyDB.SelectAllAPIKeys(0, 100)
+		if err != nil {
+			return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get API keys: %v", err))
+		}
+		return renderRowsWithPopup(c, status, screens.APIKeyRows(apiKeys), "#table_body_api_keys_table", "innerHTML", "closeRevokeAPIKey", message)
+	}
+
+	return renderPopupOrJson(c, status, message)
+}
+
+// =======View Handlers=======
+
+// APIKeysView renders the API key list view
+func (m *ManagerHandler) APIKeysView(c *echo.Context) error {
+	if m.APIKeyDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "API keys are not enabled")
+	}
+
+	apiKeys, err := m.APIKeyDB.SelectAllAPIKeys(0, 100)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get API keys: %v", err))
+	}
+
+	c.Response().Header().Add("HX-Push-Url", "/apiKeys")
+	c.Response().Header().Add("HX-Retarget", "#body")
+
+	return render(c, screens.APIKeys(apiKeys))
+}
+
+// CreateAPIKeyPopupView renders the create API key popup
+func (m *ManagerHandler) CreateAPIKeyPopupView(c *echo.Context) error {
+	return renderPopup(c, screens.CreateAPIKeyPopup())
+}
+
+// RotateAPIKeyPopupView renders the rotate API key popup
+func (m *ManagerHandler) RotateAPIKeyPopupView(c *echo.Context) error {
+	if m.APIKeyDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "API keys are not enabled")
+	}
+
+	rid, err := uuid.Parse(c.QueryParam("rid"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid API key RID format")
+	}
+
+	apiKey, err := m.APIKeyDB.SelectAPIKey(rid)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "API key not found")
+	}
+	if apiKey.RevokedAt != nil {
+		return renderPopupOrJson(c, http.StatusConflict, "Revoked API keys cannot be rotated")
+	}
+
+	return renderPopup(c, screens.RotateAPIKeyPopup(apiKey))
+}
+
+// RevokeAPIKeyPopupView renders the revoke API key popup
+func (m *ManagerHandler) RevokeAPIKeyPopupView(c *echo.Context) error {
+	rids := c.QueryParams()["rid"]
+	if len(rids) == 0 {
+		return renderPopupOrJson(c, http.StatusBadRequest, "No API key RIDs provided")
+	}
+
+	return renderPopup(c, screens.RevokeAPIKeyPopup(rids))
+}
+
+// =======Helpers=======
+
+// apiKeyUser returns the user of the bearer API key and records its use.
+// The bootstrap API key is an admin key of the configuration, eg. to create the first keys without login.
+func (m *ManagerHandler) apiKeyUser(key string) (*model.User, error) {
+	if m.BootstrapAPIKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(m.BootstrapAPIKey)) == 1 {
+		return &model.User{Username: "apikey:bootstrap", Name: "bootstrap", Role: model.ROLE_ADMIN, Source: model.USER_SOURCE_API_KEY, Active: true}, nil
+	}
+	if m.APIKeyDB == nil {
+		return nil, fmt.Errorf("API keys are not enabled")
+	}
+
+	apiKey, err := m.APIKeyDB.SelectAPIKeyByHash(helper.HashAPIKey(key))
+	if err != nil {
+		return nil, err
+	}
+
+	if apiKey.LastUsedAt == nil || time.Since(*apiKey.LastUsedAt) > apiKeyLastUsedInterval {
+		err = m.APIKeyDB.UpdateAPIKeyLastUsed(apiKey.RID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return apiKey.User(), nil
+}
+
+// renderAPIKeyWithKey swaps the row of the API key into the table and shows the key once in a popup.
+func renderAPIKeyWithKey(c *echo.Context, status int, apiKey *model.APIKeyCreated, target string, swap string, closeEvent string) error {
+	c.Response().Header().Add("HX-Retarget", target)
+	c.Response().Header().Add("HX-Reswap", swap)
+	c.Response().Header().Add("HX-Trigger-After-Settle", closeEvent)
+
+	return render(c, templ.Join(screens.APIKeyRow(apiKey.APIKey), components.PopupOutOfBand(screens.APIKeyCreatedPopup(apiKey))), status)
+}
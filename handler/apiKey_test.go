@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	akdb, err := database.NewAPIKeyDBHandler(db, true)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.APIKeyDB = akdb
+	e := echo.New()
+
+	next := func(c *echo.Context) error {
+		return c.String(http.StatusOK, requestedBy(c))
+	}
+
+	createAPIKey := func(t *testing.T, body string) (int, *qmModel.APIKeyCreated) {
+		req := httptest.NewRequest(http.MethodPost, "/api/apiKey/createApiKey", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.CreateAPIKey(c)
+		require.NoError(t, err)
+
+		apiKeyCreated := &qmModel.APIKeyCreated{}
+		if rec.Code == http.StatusCreated {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), apiKeyCreated))
+		}
+		return rec.Code, apiKeyCreated
+	}
+
+	requestWithKey := func(key string, role string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/job/cancelJobs", nil)
+		req.Header.Set("Authorization", "Bearer "+key)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.AuthMiddleware(handler.RequireRole(role)(next))(c)
+		require.NoError(t, err)
+		return rec
+	}
+
+	readStatus, readKey := createAPIKey(t, `{"name": "dashboard", "scope": "read"}`)
+	require.Equal(t, http.StatusCreated, readStatus)
+	writeStatus, writeKey := createAPIKey(t, `{"name": "deploy", "scope": "write"}`)
+	require.Equal(t, http.StatusCreated, writeStatus)
+
+	t.Run("CreateAPIKey returns the key once", func(t *testing.T) {
+		assert.True(t, strings.HasPrefix(readKey.Key, readKey.Prefix))
+		assert.Equal(t, qmModel.API_KEY_SCOPE_READ, readKey.Scope)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/apiKey/getApiKeys", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetAPIKeys(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), readKey.Prefix)
+		assert.NotContains(t, rec.Body.String(), readKey.Key, "Expected the key to not be listed")
+	})
+
+	t.Run("CreateAPIKey with invalid scope or without name", func(t *testing.T) {
+		status, _ := createAPIKey(t, `{"name": "root", "scope": "root"}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = createAPIKey(t, `{"name": " ", "scope": "read"}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("Middleware authenticates API keys with their scope", func(t *testing.T) {
+		rec := requestWithKey(readKey.Key, qmModel.ROLE_VIEWER)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "apikey:dashboard", rec.Body.String())
+
+		rec = requestWithKey(readKey.Key, qmModel.ROLE_OPERATOR)
+		assert.Equal(t, http.StatusForbidden, rec.Code, "Expected a read-only key to not act as operator")
+
+		rec = requestWithKey(writeKey.Key, qmModel.ROLE_OPERATOR)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		rec = requestWithKey(writeKey.Key, qmModel.ROLE_ADMIN)
+		assert.Equal(t, http.StatusForbidden, rec.Code, "Expected a read-write key to not act as admin")
+
+		rec = requestWithKey("qm_invalid", qmModel.ROLE_VIEWER)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+
+		apiKey, err := akdb.SelectAPIKey(readKey.RID)
+		require.NoError(t, err)
+		assert.NotNil(t, apiKey.LastUsedAt, "Expected the use of the key to be recorded")
+	})
+
+	t.Run("RotateAPIKey replaces the key", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/apiKey/rotateApiKey?rid="+writeKey.RID.String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.RotateAPIKey(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		rotatedKey := &qmModel.APIKeyCreated{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), rotatedKey))
+		assert.NotEqual(t, writeKey.Key, rotatedKey.Key)
+
+		assert.Equal(t, http.StatusUnauthorized, requestWithKey(writeKey.Key, qmModel.ROLE_OPERATOR).Code)
+		assert.Equal(t, http.StatusOK, requestWithKey(rotatedKey.Key, qmModel.ROLE_OPERATOR).Code)
+		writeKey = rotatedKey
+	})
+
+	t.Run("RevokeAPIKeys invalidates the keys", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/apiKey/revokeApiKeys?rid="+readKey.RID.String()+"&rid="+writeKey.RID.String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.RevokeAPIKeys(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "revoked 2 API key(s)")
+
+		assert.Equal(t, http.StatusUnauthorized, requestWithKey(readKey.Key, qmModel.ROLE_VIEWER).Code)
+		assert.Equal(t, http.StatusUnauthorized, requestWithKey(writeKey.Key, qmModel.ROLE_VIEWER).Code)
+
+		req = httptest.NewRequest(http.MethodPost, "/api/apiKey/rotateApiKey?rid="+readKey.RID.String(), nil)
+		rec = httptest.NewRecorder()
+		c = e.NewContext(req, rec)
+
+		err = handler.RotateAPIKey(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code, "Expected a revoked key to not be rotated")
+	})
+
+	t.Run("Requests without API key are rejected without authenticator", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/job/cancelJobs", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.AuthMiddleware(handler.RequireRole(qmModel.ROLE_OPERATOR)(next))(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+func TestAPIKeysViewHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	akdb, err := database.NewAPIKeyDBHandler(db, true)
+	require.NoError(t, err)
+	_, err = akdb.InsertAPIKey(&qmModel.APIKey{Name: "view-key", Prefix: "qm_viewview", Scope: qmModel.API_KEY_SCOPE_READ}, "view-hash")
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.APIKeyDB = akdb
+	e := echo.New()
+
+	t.Run("Should render the API key list", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/apiKeys", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.APIKeysView(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "view-key")
+		assert.Contains(t, rec.Body.String(), "qm_viewview")
+	})
+
+	t.Run("Should show the created key in a popup for HTMX requests", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/apiKey/createApiKey", strings.NewReader("name=htmx-key&scope=write"))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		req.Header.Set("HX-Request", "true")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.CreateAPIKey(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "closeCreateAPIKey", rec.Header().Get("HX-Trigger-After-Settle"))
+		assert.Contains(t, rec.Body.String(), "htmx-key")
+		assert.Contains(t, rec.Body.String(), "api_key_created_key")
+	})
+}
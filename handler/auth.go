@@ -0,0 +1,519 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/siherrmann/queuerManager/auth"
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+)
+
+// SESSION_COOKIE_NAME is the name of the cookie with the signed session of a logged in user
+const SESSION_COOKIE_NAME = "queuer_manager_session"
+
+// SESSION_MAX_AGE is the lifetime of a session, the role is checked on every request
+const SESSION_MAX_AGE = 12 * time.Hour
+
+// BASIC_AUTH_CACHE_TTL is the time the user of a basic auth login is reused for requests with the same credentials,
+// so API clients do not bind against the authenticator and save the user on every request.
+const BASIC_AUTH_CACHE_TTL = time.Minute
+
+// basicAuthCacheMaxEntries limits the cached basic auth logins, the cache is cleared if it is full of unexpired logins
+const basicAuthCacheMaxEntries = 1000
+
+// publicPathPrefixes are not protected by the login, the Slack, CI and SCIM endpoints have their own authentication
+// The status page is public, so the team can check the health of the manager without a login
+// A prefix matches its exact path and the paths below it, the prefixes ending with a slash only match the paths below them
+var publicPathPrefixes = []string{"/login", "/logout", "/static/", "/health", "/status", "/api/status", "/api/slack/", "/api/ci/", "/scim/"}
+
+// apiKeyRoutePrefix is the prefix of the routes managing the API keys, anonymous sessions can never use them
+const apiKeyRoutePrefix = "/api/apiKey/"
+
+// readOnlyRoutes are routes with other methods than GET that only read or only change the watches or the namespace of the user, so viewers can use them
+var readOnlyRoutes = map[string]bool{
+	http.MethodPost + " /api/job/getJob/:rid":              true,
+	http.MethodPost + " /api/job/getJobs":                  true,
+	http.MethodPost + " /api/job/getJobTimeline/:rid":      true,
+	http.MethodPost + " /api/job/getJobChains/:rid":        true,
+	http.MethodPost + " /api/job/getJobTemplates/:taskKey": true,
+	http.MethodPost + " /api/grafana/metrics":              true,
+	http.MethodPost + " /api/grafana/query":                true,
+	http.MethodPost + " /api/graphql":                      true,
+	http.MethodDelete + " /popup/:id":                      true,
+	http.MethodPost + " /api/watch/addWatch":               true,
+	http.MethodPost + " /api/watch/deleteWatch":            true,
+	http.MethodPost + " /api/namespace/switchNamespace":    true,
+}
+
+// basicAuthCache caches the users of successful basic auth logins by the keyed hash of their credentials.
+type basicAuthCache struct {
+	mu    sync.Mutex
+	users map[string]*basicAuthCacheEntry
+}
+
+// basicAuthCacheEntry is a cached user of a basic auth login with the time it expires.
+type basicAuthCacheEntry struct {
+	user      *model.User
+	expiresAt time.Time
+}
+
+// get returns a copy of the cached user of the credentials, nil if it is missing or expired.
+func (b *basicAuthCache) get(key string, now time.Time) *model.User {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entry, ok := b.users[key]
+	if !ok || !now.Before(entry.expiresAt) {
+		return nil
+	}
+	user := *entry.user
+	return &user
+}
+
+// set caches a copy of the user of the credentials until the TTL expires.
+func (b *basicAuthCache) set(key string, user *model.User, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.users == nil || len(b.users) >= basicAuthCacheMaxEntries {
+		users := map[string]*basicAuthCacheEntry{}
+		for cachedKey, entry := range b.users {
+			if now.Before(entry.expiresAt) && len(users) < basicAuthCacheMaxEntries/2 {
+				users[cachedKey] = entry
+			}
+		}
+		b.users = users
+	}
+	cachedUser := *user
+	b.users[key] = &basicAuthCacheEntry{user: &cachedUser, expiresAt: now.Add(BASIC_AUTH_CACHE_TTL)}
+}
+
+// clear removes all cached logins, eg. after roles changed.
+func (b *basicAuthCache) clear() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.users = nil
+}
+
+// =======API Handlers=======
+// Login authenticates the user from the login form and sets the session cookie.
+func (m *ManagerHandler) Login(c *echo.Context) error {
+	if m.Authenticator == nil {
+		return c.Redirect(http.StatusSeeOther, "/login")
+	}
+
+	username := strings.TrimSpace(c.FormValue("username"))
+	user, err := m.loginUser(username, c.FormValue("password"))
+	if errors.Is(err, auth.ErrInvalidCredentials) || errors.Is(err, auth.ErrUserNotFound) {
+		return m.renderLogin(c, http.StatusUnauthorized, "Invalid username or password")
+	} else if err != nil {
+		log.Printf("Login of %s failed: %v", username, err)
+		return m.renderLogin(c, http.StatusInternalServerError, "Login failed, please try again later")
+	}
+
+	return m.startSession(c, user)
+}
+
+// Logout removes the session cookie.
+// Users of the OpenID Connect provider are also logged out at the provider if it supports it.
+func (m *ManagerHandler) Logout(c *echo.Context) error {
+	c.SetCookie(&http.Cookie{
+		Name:     SESSION_COOKIE_NAME,
+		Value:    "",
+		Path:     cookiePath(c, "/"),
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+
+	if m.OIDC != nil {
+		if endSessionURL := m.OIDC.EndSessionURL(c.Request().Context(), oidcPostLogoutURL(m.OIDC.Config.RedirectURL)); endSessionURL != "" {
+			return c.Redirect(http.StatusSeeOther, endSessionURL)
+		}
+	}
+
+	return c.Redirect(http.StatusSeeOther, "/login")
+}
+
+// =======View Handlers=======
+
+// LoginView renders the login form and the button of the OpenID Connect provider
+func (m *ManagerHandler) LoginView(c *echo.Context) error {
+	if !m.loginEnabled() {
+		return c.Redirect(http.StatusSeeOther, "/")
+	}
+	return m.renderLogin(c, http.StatusOK, "")
+}
+
+// =======Middleware=======
+
+// AuthMiddleware requires a logged in user with a role if an authenticator or OpenID Connect provider is configured.
+// Browsers are authenticated with the session cookie, API clients can use HTTP basic auth or an API key.
+// API requests without a valid API key, session or basic auth login are rejected with 401, also without login.
+// Without login the pages start an anonymous session, so the requests of the pages to the API are authenticated by its cookie.
+// The user is loaded on every request, so role changes of the sync apply immediately.
+func (m *ManagerHandler) AuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c *echo.Context) error {
+		path := c.Request().URL.Path
+		if isPublicPath(path) || m.isPublicDashboardPath(path) {
+			return next(c)
+		}
+
+		isAPI := strings.HasPrefix(path, "/api/")
+		if key, ok := strings.CutPrefix(c.Request().Header.Get("Authorization"), "Bearer "); ok && isAPI {
+			user, err := m.apiKeyUser(key)
+			if err != nil {
+				c.Response().Header().Set("WWW-Authenticate", `Bearer realm="queuerManager"`)
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid API key"})
+			}
+
+			rc := model.GetRequestContext(c)
+			rc.User = user
+			model.SetRequestContext(c, rc)
+
+			return next(c)
+		}
+
+		if !m.loginEnabled() {
+			if m.hasAnonymousSession(c) {
+				return next(c)
+			}
+			if isAPI {
+				c.Response().Header().Set("WWW-Authenticate", `Bearer realm="queuerManager"`)
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "API key required"})
+			}
+			m.startAnonymousSession(c)
+			return next(c)
+		}
+
+		user, err := m.requestUser(c)
+		if err != nil || user.Role == "" || !user.Active {
+			if isAPI {
+				c.Response().Header().Set("WWW-Authenticate", `Basic realm="queuerManager"`)
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Authentication required"})
+			}
+			if c.Request().Header.Get("HX-Request") != "" {
+				c.Response().Header().Set("HX-Redirect", "/login")
+				return c.NoContent(http.StatusUnauthorized)
+			}
+			return c.Redirect(http.StatusSeeOther, "/login")
+		}
+
+		rc := model.GetRequestContext(c)
+		rc.User = user
+		model.SetRequestContext(c, rc)
+
+		return next(c)
+	}
+}
+
+// RequireRole restricts a route to users with at least the role.
+// Without login the anonymous session is allowed on all routes except the API key routes, so keys are only created
+// by users or API keys with the role. Requests with an API key of a lower scope are always rejected.
+func (m *ManagerHandler) RequireRole(role string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			user := model.GetRequestContext(c).User
+			if !m.loginEnabled() && user == nil && !strings.HasPrefix(c.Request().URL.Path, apiKeyRoutePrefix) {
+				return next(c)
+			}
+
+			if user == nil || !model.RoleIncludes(user.Role, role) {
+				return renderPopupOrJson(c, http.StatusForbidden, fmt.Sprintf("This action requires the %s role", role))
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// RBACMiddleware restricts requests that change data to operators, so viewers can only read.
+// It applies to all routes including the routes of extensions, routes that need a higher role are restricted
+// with RequireRole in addition. Public routes with their own authentication and readOnlyRoutes are allowed.
+func (m *ManagerHandler) RBACMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c *echo.Context) error {
+		method := c.Request().Method
+		if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+			return next(c)
+		}
+		if c.Path() == "" || isPublicPath(c.Request().URL.Path) || readOnlyRoutes[method+" "+c.Path()] {
+			return next(c)
+		}
+
+		return m.RequireRole(model.ROLE_OPERATOR)(next)(c)
+	}
+}
+
+// SyncUserRoles updates the role of all users of the authenticator, eg. after their LDAP groups changed.
+// Users that do not exist anymore lose their role. It returns the number of users with a changed role.
+func (m *ManagerHandler) SyncUserRoles() (int, error) {
+	if m.Authenticator == nil || m.UserDB == nil {
+		return 0, nil
+	}
+
+	changed := 0
+	lastID := 0
+	for {
+		users, err := m.UserDB.SelectAllUsers(lastID, 100)
+		if err != nil {
+			return changed, err
+		}
+		if len(users) == 0 {
+			return changed, nil
+		}
+		lastID = users[len(users)-1].ID
+
+		for _, user := range users {
+			if user.Source != model.USER_SOURCE_LDAP {
+				continue
+			}
+
+			syncedUser, err := m.Authenticator.LookupUser(user.Username)
+			if errors.Is(err, auth.ErrUserNotFound) {
+				syncedUser = &model.User{Username: user.Username, Name: user.Name, Email: user.Email, Source: user.Source, Active: user.Active}
+			} else if err != nil {
+				return changed, fmt.Errorf("error looking up user %s: %w", user.Username, err)
+			}
+
+			if syncedUser.Role != user.Role {
+				log.Printf("Role of user %s changed from %q to %q", user.Username, user.Role, syncedUser.Role)
+				m.basicAuthUsers.clear()
+				changed++
+			}
+			_, err = m.UserDB.UpsertUser(syncedUser)
+			if err != nil {
+				return changed, err
+			}
+		}
+	}
+}
+
+// loginUser authenticates the user and saves it with the current role.
+// Users provisioned with SCIM keep the role and activation of the identity provider.
+func (m *ManagerHandler) loginUser(username string, password string) (*model.User, error) {
+	user, err := m.Authenticator.Authenticate(username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.saveLoginUser(user)
+}
+
+// saveLoginUser saves the user of a login with the current role and login time.
+// Users provisioned with SCIM keep the role and activation of the identity provider.
+func (m *ManagerHandler) saveLoginUser(user *model.User) (*model.User, error) {
+	existingUser, err := m.UserDB.SelectUserByUsername(user.Username)
+	if err == nil && existingUser.Source == model.USER_SOURCE_SCIM {
+		user.ExternalID = existingUser.ExternalID
+		user.Role = existingUser.Role
+		user.Groups = existingUser.Groups
+		user.Source = existingUser.Source
+		user.Active = existingUser.Active
+	}
+
+	now := time.Now()
+	user.LastLoginAt = &now
+	return m.UserDB.UpsertUser(user)
+}
+
+// requestUser returns the user of the basic auth header or the session cookie.
+// Basic auth is only available with an authenticator checking passwords, its logins are cached for BASIC_AUTH_CACHE_TTL.
+func (m *ManagerHandler) requestUser(c *echo.Context) (*model.User, error) {
+	if username, password, ok := c.Request().BasicAuth(); ok && m.Authenticator != nil {
+		return m.basicAuthUser(username, password, time.Now())
+	}
+
+	cookie, err := c.Cookie(SESSION_COOKIE_NAME)
+	if err != nil {
+		return nil, err
+	}
+	rid, err := m.parseSessionToken(cookie.Value, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return m.UserDB.SelectUser(rid)
+}
+
+// basicAuthUser returns the cached user of the basic auth credentials or logs the user in and caches it.
+// Only successful logins are cached, so wrong passwords are always checked by the authenticator.
+func (m *ManagerHandler) basicAuthUser(username string, password string, now time.Time) (*model.User, error) {
+	mac := hmac.New(sha256.New, m.SessionKey)
+	mac.Write([]byte(username))
+	mac.Write([]byte{0})
+	mac.Write([]byte(password))
+	key := string(mac.Sum(nil))
+
+	if user := m.basicAuthUsers.get(key, now); user != nil {
+		return user, nil
+	}
+
+	user, err := m.loginUser(username, password)
+	if err != nil {
+		return nil, err
+	}
+	m.basicAuthUsers.set(key, user, now)
+
+	return user, nil
+}
+
+// cookiePath returns the path of a cookie of the manager prefixed with the base path of the request,
+// so the cookies of a manager behind a reverse proxy at /queuer/ are only sent to the manager.
+func cookiePath(c *echo.Context, path string) string {
+	basePath, _ := c.Request().Context().Value("basePath").(string)
+	if basePath != "" && path == "/" {
+		return basePath
+	}
+	return basePath + path
+}
+
+// startSession checks the access of the logged in user, sets the session cookie and redirects to the start page.
+func (m *ManagerHandler) startSession(c *echo.Context, user *model.User) error {
+	if !user.Active {
+		return m.renderLogin(c, http.StatusForbidden, "Your account is deactivated")
+	}
+	if user.Role == "" {
+		return m.renderLogin(c, http.StatusForbidden, "You are not a member of any group with access")
+	}
+
+	expires := time.Now().Add(SESSION_MAX_AGE)
+	c.SetCookie(&http.Cookie{
+		Name:     SESSION_COOKIE_NAME,
+		Value:    m.newSessionToken(user.RID, expires),
+		Path:     cookiePath(c, "/"),
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   c.Scheme() == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return c.Redirect(http.StatusSeeOther, "/")
+}
+
+// newSessionKey returns a random key signing the sessions, sessions are invalidated on restart without a configured key.
+func newSessionKey() []byte {
+	sessionKey := make([]byte, 32)
+	_, err := rand.Read(sessionKey)
+	if err != nil {
+		panic(fmt.Sprintf("failed to create session key: %v", err))
+	}
+	return sessionKey
+}
+
+// startAnonymousSession sets the cookie of an anonymous session without user, it authenticates the requests of the pages
+// to the API without login.
+func (m *ManagerHandler) startAnonymousSession(c *echo.Context) {
+	expires := time.Now().Add(SESSION_MAX_AGE)
+	c.SetCookie(&http.Cookie{
+		Name:     SESSION_COOKIE_NAME,
+		Value:    m.newSessionToken(uuid.Nil, expires),
+		Path:     cookiePath(c, "/"),
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   c.Scheme() == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// hasAnonymousSession reports if the request has the valid cookie of an anonymous session.
+func (m *ManagerHandler) hasAnonymousSession(c *echo.Context) bool {
+	cookie, err := c.Cookie(SESSION_COOKIE_NAME)
+	if err != nil {
+		return false
+	}
+	rid, err := m.parseSessionToken(cookie.Value, time.Now())
+	return err == nil && rid == uuid.Nil
+}
+
+// loginEnabled reports if a login is required, with an authenticator or an OpenID Connect provider.
+func (m *ManagerHandler) loginEnabled() bool {
+	return m.Authenticator != nil || m.OIDC != nil
+}
+
+// renderLogin renders the login page with the configured login methods and the message.
+func (m *ManagerHandler) renderLogin(c *echo.Context, status int, errorMessage string) error {
+	options := &model.LoginOptions{Password: m.Authenticator != nil}
+	if m.OIDC != nil {
+		options.OIDCName = m.OIDC.Config.Name
+	}
+	return render(c, screens.Login(errorMessage, options), status)
+}
+
+// newSessionToken signs the user RID and expiry: base64(<rid>|<expiry>).base64(<hmac>)
+func (m *ManagerHandler) newSessionToken(rid uuid.UUID, expires time.Time) string {
+	return m.signPayload(rid.String() + "|" + strconv.FormatInt(expires.Unix(), 10))
+}
+
+// parseSessionToken verifies the signature and expiry of the token and returns the user RID.
+func (m *ManagerHandler) parseSessionToken(token string, now time.Time) (uuid.UUID, error) {
+	payload, err := m.verifyPayload(token)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid session: %w", err)
+	}
+
+	ridStr, expiresStr, found := strings.Cut(payload, "|")
+	if !found {
+		return uuid.Nil, fmt.Errorf("invalid session")
+	}
+	expires, err := strconv.ParseInt(expiresStr, 10, 64)
+	if err != nil || now.After(time.Unix(expires, 0)) {
+		return uuid.Nil, fmt.Errorf("session expired")
+	}
+
+	return uuid.Parse(ridStr)
+}
+
+// signPayload signs the payload with the session key: base64(<payload>).base64(<hmac>)
+func (m *ManagerHandler) signPayload(payload string) string {
+	mac := hmac.New(sha256.New, m.SessionKey)
+	mac.Write([]byte(payload))
+
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verifyPayload verifies the signature of a value of signPayload and returns the payload.
+func (m *ManagerHandler) verifyPayload(token string) (string, error) {
+	payloadStr, signatureStr, found := strings.Cut(token, ".")
+	if !found {
+		return "", fmt.Errorf("missing signature")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(payloadStr)
+	if err != nil {
+		return "", fmt.Errorf("invalid payload encoding")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(signatureStr)
+	if err != nil {
+		return "", fmt.Errorf("invalid signature encoding")
+	}
+
+	mac := hmac.New(sha256.New, m.SessionKey)
+	mac.Write(payload)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return "", fmt.Errorf("invalid signature")
+	}
+
+	return string(payload), nil
+}
+
+func isPublicPath(path string) bool {
+	for _, prefix := range publicPathPrefixes {
+		if path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
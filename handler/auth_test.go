@@ -0,0 +1,433 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/auth"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAuthenticator authenticates users with a fixed password and role.
+type testAuthenticator struct {
+	password        string
+	roles           map[string]string
+	authentications int
+}
+
+func (a *testAuthenticator) Authenticate(username string, password string) (*qmModel.User, error) {
+	a.authentications++
+	if password != a.password {
+		return nil, auth.ErrInvalidCredentials
+	}
+	return a.LookupUser(username)
+}
+
+func (a *testAuthenticator) LookupUser(username string) (*qmModel.User, error) {
+	role, ok := a.roles[username]
+	if !ok {
+		return nil, auth.ErrUserNotFound
+	}
+	return &qmModel.User{Username: username, Role: role, Source: qmModel.USER_SOURCE_LDAP, Active: true}, nil
+}
+
+func TestAuthHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	udb, err := database.NewUserDBHandler(db, true)
+	require.NoError(t, err)
+
+	authenticator := &testAuthenticator{
+		password: "secret",
+		roles: map[string]string{
+			"alice": qmModel.ROLE_ADMIN,
+			"bob":   qmModel.ROLE_VIEWER,
+			"carol": "",
+		},
+	}
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.Authenticator = authenticator
+	handler.UserDB = udb
+	handler.SessionKey = []byte("test-session-key")
+	e := echo.New()
+
+	login := func(username string, password string) *httptest.ResponseRecorder {
+		form := url.Values{"username": {username}, "password": {password}}
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		require.NoError(t, handler.Login(c))
+		return rec
+	}
+
+	var sessionCookie *http.Cookie
+	t.Run("Login succeeds", func(t *testing.T) {
+		rec := login("alice", "secret")
+		assert.Equal(t, http.StatusSeeOther, rec.Code)
+		assert.Equal(t, "/", rec.Header().Get("Location"))
+
+		for _, cookie := range rec.Result().Cookies() {
+			if cookie.Name == SESSION_COOKIE_NAME {
+				sessionCookie = cookie
+			}
+		}
+		require.NotNil(t, sessionCookie)
+		assert.True(t, sessionCookie.HttpOnly)
+
+		user, err := udb.SelectUserByUsername("alice")
+		require.NoError(t, err)
+		assert.Equal(t, qmModel.ROLE_ADMIN, user.Role)
+		assert.NotNil(t, user.LastLoginAt)
+	})
+
+	t.Run("Login with wrong password", func(t *testing.T) {
+		rec := login("alice", "wrong")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("Login without role", func(t *testing.T) {
+		rec := login("carol", "secret")
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	next := func(c *echo.Context) error {
+		user := qmModel.GetRequestContext(c).User
+		if user == nil {
+			return c.NoContent(http.StatusInternalServerError)
+		}
+		return c.String(http.StatusOK, user.Username)
+	}
+
+	t.Run("Middleware accepts session cookie", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/jobs", nil)
+		req.AddCookie(sessionCookie)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.AuthMiddleware(next)(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "alice", rec.Body.String())
+	})
+
+	t.Run("Middleware accepts basic auth", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/job/getJobs", nil)
+		req.SetBasicAuth("bob", "secret")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.AuthMiddleware(next)(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "bob", rec.Body.String())
+	})
+
+	t.Run("Middleware rejects missing session", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/job/getJobs", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.AuthMiddleware(next)(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+
+		req = httptest.NewRequest(http.MethodGet, "/jobs", nil)
+		rec = httptest.NewRecorder()
+		c = e.NewContext(req, rec)
+
+		err = handler.AuthMiddleware(next)(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusSeeOther, rec.Code)
+		assert.Equal(t, "/login", rec.Header().Get("Location"))
+	})
+
+	t.Run("Middleware skips public paths", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.AuthMiddleware(func(c *echo.Context) error {
+			return c.NoContent(http.StatusNoContent)
+		})(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+
+	t.Run("RequireRole forbids lower role", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/task/addTask", nil)
+		req.SetBasicAuth("bob", "secret")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.AuthMiddleware(handler.RequireRole(qmModel.ROLE_OPERATOR)(next))(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+
+		req = httptest.NewRequest(http.MethodPost, "/api/task/addTask", nil)
+		req.SetBasicAuth("alice", "secret")
+		rec = httptest.NewRecorder()
+		c = e.NewContext(req, rec)
+
+		err = handler.AuthMiddleware(handler.RequireRole(qmModel.ROLE_OPERATOR)(next))(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("Basic auth logins are cached", func(t *testing.T) {
+		authentications := authenticator.authentications
+		for range 3 {
+			req := httptest.NewRequest(http.MethodGet, "/api/task/getTasks", nil)
+			req.SetBasicAuth("alice", "secret")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.AuthMiddleware(next)(c)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+		assert.LessOrEqual(t, authenticator.authentications-authentications, 1, "Expected the login to be authenticated at most once")
+
+		req := httptest.NewRequest(http.MethodGet, "/api/task/getTasks", nil)
+		req.SetBasicAuth("alice", "wrong")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.AuthMiddleware(next)(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, "Expected other credentials to not use the cached login")
+	})
+
+	t.Run("RBACMiddleware allows viewers to read only", func(t *testing.T) {
+		testCases := []struct {
+			method       string
+			path         string
+			expectedCode int
+		}{
+			{http.MethodGet, "/api/task/getTasks", http.StatusOK},
+			{http.MethodPost, "/api/job/getJobs", http.StatusOK},
+			{http.MethodPost, "/api/job/cancelJobs", http.StatusForbidden},
+			{http.MethodPost, "/api/extension/doSomething", http.StatusForbidden},
+		}
+		for _, testCase := range testCases {
+			req := httptest.NewRequest(testCase.method, testCase.path, nil)
+			req.SetBasicAuth("bob", "secret")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath(testCase.path)
+
+			err := handler.AuthMiddleware(handler.RBACMiddleware(next))(c)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedCode, rec.Code, testCase.method+" "+testCase.path)
+		}
+	})
+
+	t.Run("SyncUserRoles applies group changes", func(t *testing.T) {
+		authenticator.roles["bob"] = qmModel.ROLE_OPERATOR
+		delete(authenticator.roles, "alice")
+
+		changed, err := handler.SyncUserRoles()
+		require.NoError(t, err)
+		assert.Equal(t, 2, changed)
+
+		bob, err := udb.SelectUserByUsername("bob")
+		require.NoError(t, err)
+		assert.Equal(t, qmModel.ROLE_OPERATOR, bob.Role)
+
+		alice, err := udb.SelectUserByUsername("alice")
+		require.NoError(t, err)
+		assert.Empty(t, alice.Role)
+
+		// The session of a user without role is not accepted anymore
+		req := httptest.NewRequest(http.MethodGet, "/jobs", nil)
+		req.AddCookie(sessionCookie)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err = handler.AuthMiddleware(next)(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusSeeOther, rec.Code)
+	})
+
+	t.Run("Session token", func(t *testing.T) {
+		rid := uuid.New()
+		now := time.Now()
+		token := handler.newSessionToken(rid, now.Add(time.Hour))
+
+		parsedRID, err := handler.parseSessionToken(token, now)
+		require.NoError(t, err)
+		assert.Equal(t, rid, parsedRID)
+
+		_, err = handler.parseSessionToken(token, now.Add(2*time.Hour))
+		assert.Error(t, err, "expired session should be rejected")
+
+		payload, signature, _ := strings.Cut(token, ".")
+		_, err = handler.parseSessionToken(payload+"x."+signature, now)
+		assert.Error(t, err, "tampered session should be rejected")
+	})
+}
+
+func TestBasicAuthCache(t *testing.T) {
+	cache := &basicAuthCache{}
+	now := time.Now()
+
+	t.Run("Should return a copy of the cached user until the TTL expires", func(t *testing.T) {
+		cache.set("key", &qmModel.User{Username: "alice", Role: qmModel.ROLE_ADMIN}, now)
+
+		user := cache.get("key", now.Add(BASIC_AUTH_CACHE_TTL-time.Second))
+		require.NotNil(t, user)
+		assert.Equal(t, "alice", user.Username)
+		user.Role = qmModel.ROLE_VIEWER
+		assert.Equal(t, qmModel.ROLE_ADMIN, cache.get("key", now).Role, "Expected the cached user to not change")
+
+		assert.Nil(t, cache.get("key", now.Add(BASIC_AUTH_CACHE_TTL)))
+		assert.Nil(t, cache.get("other", now))
+	})
+
+	t.Run("Should drop expired logins if the cache is full", func(t *testing.T) {
+		for i := range basicAuthCacheMaxEntries {
+			cache.users[fmt.Sprintf("expired-%d", i)] = &basicAuthCacheEntry{user: &qmModel.User{Username: "bob"}, expiresAt: now}
+		}
+		cache.set("new", &qmModel.User{Username: "carol"}, now)
+
+		assert.Len(t, cache.users, 2, "Expected only the unexpired logins to be kept")
+		assert.NotNil(t, cache.get("key", now))
+		assert.NotNil(t, cache.get("new", now))
+	})
+
+	t.Run("Should clear all logins", func(t *testing.T) {
+		cache.clear()
+		assert.Nil(t, cache.get("new", now))
+	})
+}
+
+func TestCookiePath(t *testing.T) {
+	e := echo.New()
+
+	t.Run("Should keep the path without base path", func(t *testing.T) {
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/login", nil), httptest.NewRecorder())
+		assert.Equal(t, "/", cookiePath(c, "/"))
+		assert.Equal(t, "/login/oidc", cookiePath(c, "/login/oidc"))
+	})
+
+	t.Run("Should prefix the path with the base path", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/login", nil)
+		req = req.WithContext(context.WithValue(req.Context(), "basePath", "/queuer"))
+		c := e.NewContext(req, httptest.NewRecorder())
+		assert.Equal(t, "/queuer", cookiePath(c, "/"))
+		assert.Equal(t, "/queuer/login/oidc", cookiePath(c, "/login/oidc"))
+	})
+}
+
+func TestIsPublicPath(t *testing.T) {
+	t.Run("Should match the exact path and the paths below it", func(t *testing.T) {
+		assert.True(t, isPublicPath("/status"))
+		assert.True(t, isPublicPath("/health"))
+		assert.True(t, isPublicPath("/login"))
+		assert.True(t, isPublicPath("/login/oidc"))
+		assert.True(t, isPublicPath("/api/status"))
+		assert.True(t, isPublicPath("/api/status/history"), "Expected the history of the public status page to be public")
+	})
+
+	t.Run("Should not match paths only starting with the prefix", func(t *testing.T) {
+		assert.False(t, isPublicPath("/statusX"))
+		assert.False(t, isPublicPath("/healthcheck-admin"))
+		assert.False(t, isPublicPath("/loginAdmin"))
+		assert.False(t, isPublicPath("/api/statusX"))
+	})
+
+	t.Run("Should only match the paths below directory prefixes", func(t *testing.T) {
+		assert.True(t, isPublicPath("/static/css/main.css"))
+		assert.True(t, isPublicPath("/api/slack/command"))
+		assert.True(t, isPublicPath("/scim/v2/Users"))
+		assert.False(t, isPublicPath("/staticX"))
+		assert.False(t, isPublicPath("/api/slackX"))
+		assert.False(t, isPublicPath("/scimX/v2/Users"))
+	})
+}
+
+func TestAuthWithoutLogin(t *testing.T) {
+	handler := NewManagerHandler(upload.NewFilesystemMemory(), nil, nil)
+	handler.BootstrapAPIKey = "qm_bootstrap"
+	e := echo.New()
+
+	next := func(c *echo.Context) error {
+		return c.String(http.StatusOK, requestedBy(c))
+	}
+	request := func(method string, path string, modify func(req *http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if modify != nil {
+			modify(req)
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		role := qmModel.ROLE_OPERATOR
+		if strings.HasPrefix(path, "/api/apiKey/") {
+			role = qmModel.ROLE_ADMIN
+		}
+		require.NoError(t, handler.AuthMiddleware(handler.RequireRole(role)(next))(c))
+		return rec
+	}
+
+	t.Run("Should reject API requests without API key", func(t *testing.T) {
+		rec := request(http.MethodPost, "/api/job/cancelJobs", nil)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, `Bearer realm="queuerManager"`, rec.Header().Get("WWW-Authenticate"))
+
+		rec = request(http.MethodGet, "/api/status", nil)
+		assert.Equal(t, http.StatusOK, rec.Code, "Expected public API routes to be allowed")
+	})
+
+	var sessionCookie *http.Cookie
+	t.Run("Should start an anonymous session on the pages", func(t *testing.T) {
+		rec := request(http.MethodGet, "/jobs", nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		for _, cookie := range rec.Result().Cookies() {
+			if cookie.Name == SESSION_COOKIE_NAME {
+				sessionCookie = cookie
+			}
+		}
+		require.NotNil(t, sessionCookie, "Expected the page to set the session cookie")
+		assert.True(t, sessionCookie.HttpOnly)
+	})
+
+	t.Run("Should allow the API requests of the anonymous session except the API key routes", func(t *testing.T) {
+		rec := request(http.MethodPost, "/api/job/cancelJobs", func(req *http.Request) { req.AddCookie(sessionCookie) })
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		rec = request(http.MethodPost, "/api/apiKey/createApiKey", func(req *http.Request) { req.AddCookie(sessionCookie) })
+		assert.Equal(t, http.StatusForbidden, rec.Code, "Expected the anonymous session to not create API keys")
+
+		forged := &http.Cookie{Name: SESSION_COOKIE_NAME, Value: (&ManagerHandler{SessionKey: []byte("other")}).newSessionToken(uuid.Nil, time.Now().Add(time.Hour))}
+		rec = request(http.MethodPost, "/api/job/cancelJobs", func(req *http.Request) { req.AddCookie(forged) })
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, "Expected a session of another key to be rejected")
+	})
+
+	t.Run("Should authenticate the bootstrap API key as admin", func(t *testing.T) {
+		rec := request(http.MethodPost, "/api/apiKey/createApiKey", func(req *http.Request) { req.Header.Set("Authorization", "Bearer qm_bootstrap") })
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "apikey:bootstrap", rec.Body.String())
+
+		rec = request(http.MethodPost, "/api/apiKey/createApiKey", func(req *http.Request) { req.Header.Set("Authorization", "Bearer qm_wrong") })
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+)
+
+// =======API Handlers=======
+
+// AddBatch adds a batch of jobs for one task, all jobs are validated before any job is added
+func (m *ManagerHandler) AddBatch(c *echo.Context) error {
+	taskKey := c.Param("taskKey")
+	task, err := m.taskDB.SelectTaskByKey(taskKey)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Task not found")
+	}
+
+	var requestData struct {
+		Name string                       `json:"name"`
+		Jobs []map[string]json.RawMessage `json:"jobs"`
+	}
+	if err := json.NewDecoder(c.Request().Body).Decode(&requestData); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+	}
+
+	if len(requestData.Jobs) == 0 {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Batch contains no jobs")
+	}
+	if len(requestData.Jobs) > 10000 {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Batch contains too many jobs (max 10000)")
+	}
+	if requestData.Name == "" {
+		requestData.Name = task.Key
+	}
+
+	// Validate all jobs with the same validation as a single added job
+	type resolvedJob struct {
+		Parameters      []any
+		ParametersKeyed map[string]any
+	}
+	resolvedJobs := []resolvedJob{}
+	for i, jobData := range requestData.Jobs {
+		jobJSON, err := json.Marshal(jobData)
+		if err != nil {
+			return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid job %d: %v", i, err))
+		}
+
+		jobRequest, err := http.NewRequest(http.MethodPost, c.Request().URL.String(), bytes.NewReader(jobJSON))
+		if err != nil {
+			return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to read job %d: %v", i, err))
+		}
+		jobRequest.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		parametersList, parametersKeyed, err := m.resolveJobParameters(c.Request().Context(), task, jobRequest, requestedBy(c))
+		if errors.Is(err, errJobPayloadTooLarge) {
+			return renderPopupOrJson(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("Job %d: %v", i, err))
+		} else if err != nil {
+			return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Validation error in job %d: %v", i, err))
+		}
+		resolvedJobs = append(resolvedJobs, resolvedJob{Parameters: parametersList, ParametersKeyed: parametersKeyed})
+	}
+
+	// Warn or block if no connected worker satisfies the minimum worker version of the task
+	versionWarning, block, err := m.checkWorkerVersion(task)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to check worker version: %v", err))
+	}
+	if block {
+		return renderPopupOrJson(c, http.StatusConflict, versionWarning)
+	}
+	if versionWarning != "" {
+		log.Printf("Warning: %s", versionWarning)
+		c.Response().Header().Add("X-Worker-Version-Warning", versionWarning)
+	}
+
+	// The whole batch is rejected if its jobs would exceed the limits of the task
+	err = m.checkTaskLimits(task, len(resolvedJobs))
+	if err != nil {
+		return renderPopupOrJson(c, addJobErrorStatus(c, err), err.Error())
+	}
+
+	batch := &qmModel.Batch{
+		Name:    requestData.Name,
+		TaskKey: task.Key,
+	}
+	initiator := requestedBy(c)
+	for i, job := range resolvedJobs {
+		jobAdded, err := m.Queuer.AddJob(task.Key, job.ParametersKeyed, job.Parameters...)
+		if err != nil {
+			// Keep track of the jobs that were already added
+			if len(batch.JobRIDs) > 0 {
+				_, _ = m.BatchDB.InsertBatch(batch)
+			}
+			return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to add job %d of batch: %v", i, err))
+		}
+		batch.JobRIDs = append(batch.JobRIDs, jobAdded.RID)
+		m.recordJobInitiator(jobAdded, initiator, false)
+		m.registerJobChains(task, jobAdded, false)
+		m.publishEvent(qmModel.EVENT_JOB_ADDED, jobAdded)
+	}
+
+	insertedBatch, err := m.BatchDB.InsertBatch(batch)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to add batch: %v", err))
+	}
+	m.publishEvent(qmModel.EVENT_BATCH_ADDED, insertedBatch)
+
+	c.Response().Header().Add("HX-Redirect", fmt.Sprintf("/batch?rid=%s", insertedBatch.RID.String()))
+
+	return renderPopupOrJson(c, http.StatusCreated, insertedBatch)
+}
+
+// GetBatch retrieves a specific batch with its aggregated progress by RID
+func (m *ManagerHandler) GetBatch(c *echo.Context) error {
+	rid, err := uuid.Parse(c.Param("rid"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid batch RID format")
+	}
+
+	batch, err := m.BatchDB.SelectBatch(rid)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Batch not found")
+	}
+
+	jobs := m.getBatchJobs(batch)
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"batch":    batch,
+		"progress": batchProgress(batch, jobs),
+	})
+}
+
+// GetBatches retrieves a paginated list of batches
+func (m *ManagerHandler) GetBatches(c *echo.Context) error {
+	lastId, limit, err := m.apiPagination(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	batches, err := m.BatchDB.SelectAllBatches(lastId, limit)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to retrieve batches")
+	}
+
+	return c.JSON(http.StatusOK, batches)
+}
+
+// CancelBatch cancels all jobs of a batch that have not ended yet
+func (m *ManagerHandler) CancelBatch(c *echo.Context) error {
+	rid, err := uuid.Parse(c.QueryParam("rid"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid batch RID format")
+	}
+
+	batch, err := m.BatchDB.SelectBatch(rid)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "Batch not found")
+	}
+
+	cancelledCount := 0
+	var errors []string
+	for _, jobRID := range batch.JobRIDs {
+		// Ended jobs are not in the job table anymore
+		if _, err := m.Queuer.GetJob(jobRID); err != nil {
+			continue
+		}
+
+		cancelledJob, err := m.Queuer.CancelJob(jobRID)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Failed to cancel job %s: %v", jobRID, err))
+			continue
+		}
+		m.publishEvent(qmModel.EVENT_JOB_CANCELLED, cancelledJob)
+		cancelledCount++
+	}
+
+	c.Response().Header().Add("HX-Redirect", fmt.Sprintf("/batch?rid=%s", batch.RID.String()))
+
+	if len(errors) > 0 {
+		return renderPopupOrJson(c, http.StatusPartialContent, fmt.Sprintf("Cancelled %d jobs. Errors: %v", cancelledCount, errors))
+	}
+
+	return renderPopupOrJson(c, http.StatusOK, fmt.Sprintf("Successfully cancelled %d job(s) of batch %s", cancelledCount, batch.Name))
+}
+
+// ExportBatch exports the status and results of all jobs of a batch as JSON file
+func (m *ManagerHandler) ExportBatch(c *echo.Context) error {
+	ridStrings, ok := c.QueryParams()["rid"]
+	if len(ridStrings) == 0 || !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing batch RID"})
+	}
+
+	rid, err := uuid.Parse(ridStrings[0])
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid batch RID format"})
+	}
+
+	batch, err := m.BatchDB.SelectBatch(rid)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Batch not found"})
+	}
+
+	results := []*qmModel.BatchJobResult{}
+	for _, job := range m.getBatchJobs(batch) {
+		results = append(results, &qmModel.BatchJobResult{
+			JobRID:  job.RID,
+			Status:  job.Status,
+			Results: job.Results,
+			Error:   job.Error,
+		})
+	}
+
+	jsonData, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to marshal batch results"})
+	}
+
+	filename := fmt.Sprintf("batch_%s_results.json", batch.RID.String())
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Response().Header().Set("Content-Type", "application/json")
+
+	return c.Blob(http.StatusOK, "application/json", jsonData)
+}
+
+// =======View Handlers=======
+
+// BatchView renders the batch detail view
+func (m *ManagerHandler) BatchView(c *echo.Context) error {
+	ridStrings, ok := c.QueryParams()["rid"]
+	if len(ridStrings) == 0 || !ok {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Missing batch RID")
+	}
+
+	rid, err := uuid.Parse(ridStrings[0])
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid batch RID: %v", err))
+	}
+
+	batch, err := m.BatchDB.SelectBatch(rid)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "Batch not found")
+	}
+
+	jobs := m.getBatchJobs(batch)
+
+	c.Response().Header().Add("HX-Push-Url", fmt.Sprintf("/batch?rid=%s", rid.String()))
+	c.Response().Header().Add("HX-Retarget", "#body")
+
+	return renderStream(c, screens.Batch(batch, batchProgress(batch, jobs), jobs))
+}
+
+// getBatchJobs retrieves all jobs of a batch from the job table or the job archive.
+// Jobs that can not be found anymore are skipped.
+func (m *ManagerHandler) getBatchJobs(batch *qmModel.Batch) []*model.Job {
+	jobs := []*model.Job{}
+	for _, jobRID := range batch.JobRIDs {
+		job, err := m.Queuer.GetJob(jobRID)
+		if err != nil {
+			job, err = m.Queuer.GetJobEnded(jobRID)
+			if err != nil {
+				continue
+			}
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+// batchProgress aggregates the status of the found jobs of a batch.
+func batchProgress(batch *qmModel.Batch, jobs []*model.Job) *qmModel.BatchProgress {
+	progress := &qmModel.BatchProgress{
+		Total:        len(batch.JobRIDs),
+		Missing:      len(batch.JobRIDs) - len(jobs),
+		StatusCounts: map[string]int{},
+	}
+
+	for _, job := range jobs {
+		progress.StatusCounts[job.Status]++
+		if job.Status == model.JobStatusSucceeded || job.Status == model.JobStatusFailed || job.Status == model.JobStatusCancelled {
+			progress.Ended++
+		}
+	}
+
+	if progress.Total > 0 {
+		progress.PercentEnded = (progress.Ended + progress.Missing) * 100 / progress.Total
+	}
+
+	return progress
+}
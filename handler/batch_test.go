@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	vm "github.com/siherrmann/validator/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	bdb, err := database.NewBatchDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.BatchDB = bdb
+	e := echo.New()
+
+	task, err := tdb.InsertTask(&qmModel.Task{
+		Key:                  "test-batch-task",
+		Name:                 "Test Batch Task",
+		InputParameters:      []vm.Validation{{Key: "timeSeconds", Type: vm.Int, Requirement: "min0"}},
+		InputParametersKeyed: []vm.Validation{},
+	})
+	require.NoError(t, err)
+
+	var batch qmModel.Batch
+	t.Run("AddBatch with valid jobs", func(t *testing.T) {
+		body := `{"name": "Test Batch", "jobs": [{"timeSeconds": 0}, {"timeSeconds": 0}, {"timeSeconds": 10}]}`
+
+		req := httptest.NewRequest(http.MethodPost, "/api/batch/addBatch/"+task.Key, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: task.Key}})
+
+		err := handler.AddBatch(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		err = json.Unmarshal(rec.Body.Bytes(), &batch)
+		require.NoError(t, err)
+		assert.Equal(t, "Test Batch", batch.Name)
+		assert.Len(t, batch.JobRIDs, 3)
+		assert.Contains(t, rec.Header().Get("HX-Redirect"), "/batch?rid=")
+	})
+
+	t.Run("AddBatch with invalid job adds no jobs", func(t *testing.T) {
+		jobsBefore, _ := queue.GetJobs(0, 100)
+
+		body := `{"jobs": [{"timeSeconds": 0}, {"timeSeconds": -1}]}`
+
+		req := httptest.NewRequest(http.MethodPost, "/api/batch/addBatch/"+task.Key, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: task.Key}})
+
+		err := handler.AddBatch(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Validation error in job 1")
+
+		jobsAfter, _ := queue.GetJobs(0, 100)
+		assert.Equal(t, len(jobsBefore), len(jobsAfter), "Invalid batch should not add any job")
+	})
+
+	t.Run("AddBatch with no jobs", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/batch/addBatch/"+task.Key, strings.NewReader(`{"jobs": []}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: task.Key}})
+
+		err := handler.AddBatch(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Batch contains no jobs")
+	})
+
+	t.Run("GetBatch returns progress", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/batch/getBatch/"+batch.RID.String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "rid", Value: batch.RID.String()}})
+
+		err := handler.GetBatch(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response struct {
+			Batch    qmModel.Batch         `json:"batch"`
+			Progress qmModel.BatchProgress `json:"progress"`
+		}
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, batch.RID, response.Batch.RID)
+		assert.Equal(t, 3, response.Progress.Total)
+	})
+
+	t.Run("GetBatch with invalid RID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/batch/getBatch/invalid-uuid", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "rid", Value: "invalid-uuid"}})
+
+		err := handler.GetBatch(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("CancelBatch cancels running jobs", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/batch/cancelBatch?rid="+batch.RID.String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.CancelBatch(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Successfully cancelled")
+	})
+
+	t.Run("ExportBatch returns job results", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/batch/exportBatch?rid="+batch.RID.String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.ExportBatch(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get("Content-Disposition"), "attachment")
+
+		var results []*qmModel.BatchJobResult
+		err = json.Unmarshal(rec.Body.Bytes(), &results)
+		require.NoError(t, err)
+		assert.NotEmpty(t, results)
+	})
+}
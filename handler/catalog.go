@@ -0,0 +1,301 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/labstack/echo/v5"
+)
+
+// catalogTaskPageSize is the number of tasks read per query to build the current catalog
+const catalogTaskPageSize = 500
+
+// currentCatalogSnapshot builds the snapshot of the current task catalog, schedules and feature flags.
+func (m *ManagerHandler) currentCatalogSnapshot(createdBy string) (*model.CatalogSnapshot, error) {
+	snapshot := &model.CatalogSnapshot{
+		CreatedAt:    time.Now().UTC(),
+		CreatedBy:    createdBy,
+		Tasks:        map[string]map[string]any{},
+		Schedules:    map[string]map[string]any{},
+		FeatureFlags: map[string]bool{},
+	}
+
+	lastId := 0
+	for {
+		tasks, err := m.taskDB.SelectAllTasks(lastId, catalogTaskPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve tasks: %v", err)
+		}
+		for _, task := range tasks {
+			snapshot.Tasks[task.Key] = taskExport(task)
+			lastId = task.ID
+		}
+		if len(tasks) < catalogTaskPageSize {
+			break
+		}
+	}
+
+	if m.ScheduleDB != nil {
+		schedules, err := m.ScheduleDB.SelectAllSchedules()
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve schedules: %v", err)
+		}
+		for _, schedule := range schedules {
+			snapshot.Schedules[schedule.Name] = map[string]any{
+				"task_key":        schedule.TaskKey,
+				"cron_expression": schedule.CronExpression,
+				"parameters":      schedule.Parameters,
+				"enabled":         schedule.Enabled,
+			}
+		}
+	}
+
+	if m.FeatureFlagDB != nil {
+		featureFlags, err := m.FeatureFlagDB.SelectAllFeatureFlags()
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve feature flags: %v", err)
+		}
+		for _, featureFlag := range featureFlags {
+			snapshot.FeatureFlags[featureFlag.Key] = featureFlag.Enabled
+		}
+	}
+
+	// Compare the current catalog in the same JSON types as a snapshot read from a file
+	return normalizeCatalogSnapshot(snapshot)
+}
+
+// normalizeCatalogSnapshot converts the values of the snapshot to their JSON types.
+func normalizeCatalogSnapshot(snapshot *model.CatalogSnapshot) (*model.CatalogSnapshot, error) {
+	snapshotJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode catalog snapshot: %v", err)
+	}
+	normalized := &model.CatalogSnapshot{}
+	err = json.Unmarshal(snapshotJSON, normalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode catalog snapshot: %v", err)
+	}
+	return normalized, nil
+}
+
+// catalogSnapshotNames returns the names of the catalog snapshots in the filesystem, newest first.
+func (m *ManagerHandler) catalogSnapshotNames() ([]string, error) {
+	files, err := m.Filesystem.ListFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %v", err)
+	}
+
+	names := []string{}
+	for _, file := range files {
+		if strings.HasPrefix(file.Name, model.CATALOG_SNAPSHOT_PREFIX) && strings.HasSuffix(file.Name, ".json") {
+			names = append(names, file.Name)
+		}
+	}
+	slices.Sort(names)
+	slices.Reverse(names)
+	return names, nil
+}
+
+// readCatalogSnapshot reads a catalog snapshot from the filesystem.
+func (m *ManagerHandler) readCatalogSnapshot(name string) (*model.CatalogSnapshot, error) {
+	if filepath.Base(name) != name || !strings.HasPrefix(name, model.CATALOG_SNAPSHOT_PREFIX) {
+		return nil, fmt.Errorf("invalid snapshot name %q", name)
+	}
+
+	file, err := m.Filesystem.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %s not found", name)
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %v", name, err)
+	}
+
+	snapshot := &model.CatalogSnapshot{}
+	err = json.Unmarshal(content, snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %v", name, err)
+	}
+	return snapshot, nil
+}
+
+// catalogChanges compares the requested snapshot, the newest if none is requested, with the current catalog.
+// It returns the name of the compared snapshot, empty if there is no snapshot yet.
+func (m *ManagerHandler) catalogChanges(name string) (string, []model.CatalogChange, error) {
+	if name == "" {
+		names, err := m.catalogSnapshotNames()
+		if err != nil {
+			return "", nil, err
+		}
+		if len(names) == 0 {
+			return "", []model.CatalogChange{}, nil
+		}
+		name = names[0]
+	}
+
+	snapshot, err := m.readCatalogSnapshot(name)
+	if err != nil {
+		return name, nil, err
+	}
+	current, err := m.currentCatalogSnapshot("")
+	if err != nil {
+		return name, nil, err
+	}
+
+	return name, diffCatalogSnapshots(snapshot, current), nil
+}
+
+// diffCatalogSnapshots returns the changes from the snapshot to the current catalog
+// ordered by kind and key. Changed tasks and schedules have a change for every changed field.
+func diffCatalogSnapshots(snapshot *model.CatalogSnapshot, current *model.CatalogSnapshot) []model.CatalogChange {
+	changes := diffCatalogEntries(model.CATALOG_KIND_TASK, snapshot.Tasks, current.Tasks)
+	changes = append(changes, diffCatalogEntries(model.CATALOG_KIND_SCHEDULE, snapshot.Schedules, current.Schedules)...)
+
+	featureFlags := func(flags map[string]bool) map[string]map[string]any {
+		entries := map[string]map[string]any{}
+		for key, enabled := range flags {
+			entries[key] = map[string]any{"enabled": enabled}
+		}
+		return entries
+	}
+	changes = append(changes, diffCatalogEntries(model.CATALOG_KIND_FEATURE_FLAG, featureFlags(snapshot.FeatureFlags), featureFlags(current.FeatureFlags))...)
+
+	return changes
+}
+
+// diffCatalogEntries compares the entries of a kind field by field.
+func diffCatalogEntries(kind string, before map[string]map[string]any, after map[string]map[string]any) []model.CatalogChange {
+	changes := []model.CatalogChange{}
+	keys := slices.Collect(maps.Keys(before))
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		beforeEntry, inBefore := before[key]
+		afterEntry, inAfter := after[key]
+		switch {
+		case !inAfter:
+			changes = append(changes, model.CatalogChange{Kind: kind, Key: key, Change: model.CATALOG_CHANGE_REMOVED, Before: catalogValueJSON(beforeEntry)})
+		case !inBefore:
+			changes = append(changes, model.CatalogChange{Kind: kind, Key: key, Change: model.CATALOG_CHANGE_ADDED, After: catalogValueJSON(afterEntry)})
+		default:
+			fields := slices.Collect(maps.Keys(beforeEntry))
+			for field := range afterEntry {
+				if _, ok := beforeEntry[field]; !ok {
+					fields = append(fields, field)
+				}
+			}
+			slices.Sort(fields)
+
+			for _, field := range fields {
+				beforeValue, afterValue := catalogValueJSON(beforeEntry[field]), catalogValueJSON(afterEntry[field])
+				if beforeValue != afterValue {
+					changes = append(changes, model.CatalogChange{Kind: kind, Key: key, Field: field, Change: model.CATALOG_CHANGE_CHANGED, Before: beforeValue, After: afterValue})
+				}
+			}
+		}
+	}
+
+	return changes
+}
+
+// catalogValueJSON returns the JSON of a catalog value, empty if the value is not set.
+func catalogValueJSON(value any) string {
+	if value == nil {
+		return ""
+	}
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(valueJSON)
+}
+
+// =======API Handlers=======
+
+// CreateCatalogSnapshot stores a snapshot of the current task catalog and settings in the filesystem
+func (m *ManagerHandler) CreateCatalogSnapshot(c *echo.Context) error {
+	snapshot, err := m.currentCatalogSnapshot(requestedBy(c))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, err.Error())
+	}
+
+	snapshotJSON, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, "Failed to marshal catalog snapshot")
+	}
+
+	name := model.CATALOG_SNAPSHOT_PREFIX + snapshot.CreatedAt.Format("20060102-150405") + ".json"
+	err = m.Filesystem.Write(name, bytes.NewReader(snapshotJSON), int64(len(snapshotJSON)))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to save catalog snapshot: %v", err))
+	}
+
+	c.Response().Header().Add("HX-Trigger-After-Settle", "reloadCatalog")
+
+	return renderPopupOrJson(c, http.StatusOK, fmt.Sprintf("Catalog snapshot %s created", name))
+}
+
+// GetCatalogSnapshots returns the names of the catalog snapshots in the filesystem, newest first
+func (m *ManagerHandler) GetCatalogSnapshots(c *echo.Context) error {
+	names, err := m.catalogSnapshotNames()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, names)
+}
+
+// GetCatalogChanges returns the changes of the task catalog and settings since a snapshot, eg. /api/catalog/getChanges?snapshot=name
+func (m *ManagerHandler) GetCatalogChanges(c *echo.Context) error {
+	name, changes, err := m.catalogChanges(c.QueryParam("snapshot"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if name == "" {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "No catalog snapshot found"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"snapshot": name,
+		"changes":  changes,
+	})
+}
+
+// =======View Handlers=======
+
+// CatalogView renders the changelog of the task catalog and settings since a snapshot
+func (m *ManagerHandler) CatalogView(c *echo.Context) error {
+	names, err := m.catalogSnapshotNames()
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, err.Error())
+	}
+
+	name, changes, err := m.catalogChanges(c.QueryParam("snapshot"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+
+	c.Response().Header().Add("HX-Push-Url", "/catalog?snapshot="+url.QueryEscape(name))
+	c.Response().Header().Add("HX-Retarget", "#body")
+
+	return render(c, screens.Catalog(names, name, changes))
+}
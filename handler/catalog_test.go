@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffCatalogSnapshots(t *testing.T) {
+	snapshot := &model.CatalogSnapshot{
+		Tasks: map[string]map[string]any{
+			"export":  {"key": "export", "name": "Export", "dedup_window_minutes": float64(10)},
+			"cleanup": {"key": "cleanup", "name": "Cleanup"},
+		},
+		Schedules:    map[string]map[string]any{"nightly": {"task_key": "export", "enabled": true}},
+		FeatureFlags: map[string]bool{model.FEATURE_FLAG_SSE_UPDATES: false},
+	}
+	current := &model.CatalogSnapshot{
+		Tasks: map[string]map[string]any{
+			"export": {"key": "export", "name": "Export all", "owner": "data-team"},
+			"report": {"key": "report", "name": "Report"},
+		},
+		Schedules:    map[string]map[string]any{"nightly": {"task_key": "export", "enabled": true}},
+		FeatureFlags: map[string]bool{model.FEATURE_FLAG_SSE_UPDATES: true},
+	}
+
+	changes := diffCatalogSnapshots(snapshot, current)
+	assert.Equal(t, []model.CatalogChange{
+		{Kind: model.CATALOG_KIND_TASK, Key: "cleanup", Change: model.CATALOG_CHANGE_REMOVED, Before: `{"key":"cleanup","name":"Cleanup"}`},
+		{Kind: model.CATALOG_KIND_TASK, Key: "export", Field: "dedup_window_minutes", Change: model.CATALOG_CHANGE_CHANGED, Before: "10"},
+		{Kind: model.CATALOG_KIND_TASK, Key: "export", Field: "name", Change: model.CATALOG_CHANGE_CHANGED, Before: `"Export"`, After: `"Export all"`},
+		{Kind: model.CATALOG_KIND_TASK, Key: "export", Field: "owner", Change: model.CATALOG_CHANGE_CHANGED, After: `"data-team"`},
+		{Kind: model.CATALOG_KIND_TASK, Key: "report", Change: model.CATALOG_CHANGE_ADDED, After: `{"key":"report","name":"Report"}`},
+		{Kind: model.CATALOG_KIND_FEATURE_FLAG, Key: model.FEATURE_FLAG_SSE_UPDATES, Field: "enabled", Change: model.CATALOG_CHANGE_CHANGED, Before: "false", After: "true"},
+	}, changes)
+
+	assert.Empty(t, diffCatalogSnapshots(current, current), "Expected no changes without drift")
+}
+
+func TestReadCatalogSnapshot(t *testing.T) {
+	filesystem := upload.NewFilesystemMemory()
+	handler := &ManagerHandler{Filesystem: filesystem}
+
+	for _, name := range []string{"catalog-snapshot-20260101-100000.json", "catalog-snapshot-20260201-100000.json", "report.json"} {
+		content := `{"tasks": {"export": {"key": "export"}}}`
+		require.NoError(t, filesystem.Write(name, strings.NewReader(content), int64(len(content))))
+	}
+
+	names, err := handler.catalogSnapshotNames()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"catalog-snapshot-20260201-100000.json", "catalog-snapshot-20260101-100000.json"}, names)
+
+	snapshot, err := handler.readCatalogSnapshot(names[0])
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]any{"export": {"key": "export"}}, snapshot.Tasks)
+
+	_, err = handler.readCatalogSnapshot("report.json")
+	assert.ErrorContains(t, err, "invalid snapshot name")
+
+	_, err = handler.readCatalogSnapshot("catalog-snapshot-missing.json")
+	assert.ErrorContains(t, err, "not found")
+}
@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+)
+
+// CI_MAX_WAIT is the maximum time a CI request waits for a job to end before it has to poll again
+const CI_MAX_WAIT = 10 * time.Minute
+
+// ciPollInterval is the interval the job status is checked while waiting
+var ciPollInterval = time.Second
+
+// =======API Handlers=======
+
+// CIRunJob adds a job for CI pipelines with a single request.
+// The multipart form contains the job parameters as JSON in the field parameters and the
+// artifacts in the field artifacts. Artifacts are stored in the file subsystem and can be
+// referenced in the parameters with ${artifact:<filename>}, which is replaced by the stored file name.
+func (m *ManagerHandler) CIRunJob(c *echo.Context) error {
+	if status, err := m.checkCIToken(c.Request()); err != nil {
+		return c.JSON(status, map[string]string{"error": err.Error()})
+	}
+
+	taskKey := c.Param("taskKey")
+	task, err := m.taskDB.SelectTaskByKey(taskKey)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Task not found"})
+	}
+
+	parametersJSON := "{}"
+	artifacts := map[string]string{}
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		// Parse multipart form with 32MB max memory
+		err := c.Request().ParseMultipartForm(32 << 20)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("Failed to parse multipart form: %v", err)})
+		}
+		form := c.Request().MultipartForm
+		defer form.RemoveAll() // Clean up temporary files
+
+		if parameters := form.Value["parameters"]; len(parameters) > 0 && parameters[0] != "" {
+			parametersJSON = parameters[0]
+		}
+
+		runID := uuid.New().String()[:8]
+		for _, fileHeader := range form.File["artifacts"] {
+			file, err := fileHeader.Open()
+			if err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to open artifact %s: %v", fileHeader.Filename, err)})
+			}
+			defer file.Close()
+
+			filename := filepath.Base(fileHeader.Filename)
+			storedName := fmt.Sprintf("ci-%s-%s", runID, filename)
+			err = m.Filesystem.Write(storedName, file, fileHeader.Size)
+			if err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to save artifact %s: %v", filename, err)})
+			}
+			m.recordFileOwner(storedName, "ci", fileHeader.Size)
+			artifacts[filename] = storedName
+		}
+	} else {
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("Failed to read request: %v", err)})
+		}
+		if len(bytes.TrimSpace(body)) > 0 {
+			parametersJSON = string(body)
+		}
+	}
+
+	// Replace the artifact references with the stored file names
+	for filename, storedName := range artifacts {
+		parametersJSON = strings.ReplaceAll(parametersJSON, "${artifact:"+filename+"}", storedName)
+	}
+
+	jobRequest, err := http.NewRequest(http.MethodPost, c.Request().URL.String(), strings.NewReader(parametersJSON))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to read parameters: %v", err)})
+	}
+	jobRequest.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	parametersList, parametersKeyed, err := m.resolveJobParameters(c.Request().Context(), task, jobRequest, "ci")
+	if errors.Is(err, errJobPayloadTooLarge) {
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": err.Error()})
+	} else if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("Validation error: %v", err)})
+	}
+
+	versionWarning, block, err := m.checkWorkerVersion(task)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to check worker version: %v", err)})
+	}
+	if block {
+		return c.JSON(http.StatusConflict, map[string]string{"error": versionWarning})
+	}
+	if versionWarning != "" {
+		c.Response().Header().Add("X-Worker-Version-Warning", versionWarning)
+	}
+
+	jobAdded, deduplicated, err := m.addTaskJob(task, parametersList, parametersKeyed)
+	if err != nil {
+		return c.JSON(addJobErrorStatus(c, err), map[string]string{"error": fmt.Sprintf("Failed to add job: %v", err)})
+	}
+	if deduplicated {
+		c.Response().Header().Set(HEADER_JOB_DEDUPLICATED, "true")
+		return c.JSON(http.StatusOK, &qmModel.CIJobRun{
+			Job:          jobAdded,
+			Artifacts:    artifacts,
+			Deduplicated: true,
+		})
+	}
+	m.recordJobInitiator(jobAdded, "ci", false)
+	m.registerJobChains(task, jobAdded, false)
+	m.publishEvent(qmModel.EVENT_JOB_ADDED, jobAdded)
+
+	return c.JSON(http.StatusCreated, &qmModel.CIJobRun{
+		Job:       jobAdded,
+		Artifacts: artifacts,
+	})
+}
+
+// CIWaitJob waits until the job has ended or the timeout (default 5m, max 10m) is reached.
+// It responds with 200 and the ended job or with 202 and the current job if it is still running,
+// so pipelines can repeat the request until the job has ended. A shutdown of the manager also ends the wait with 202.
+func (m *ManagerHandler) CIWaitJob(c *echo.Context) error {
+	if status, err := m.checkCIToken(c.Request()); err != nil {
+		return c.JSON(status, map[string]string{"error": err.Error()})
+	}
+
+	rid, err := uuid.Parse(c.Param("rid"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid job RID format"})
+	}
+
+	timeout := 5 * time.Minute
+	if timeoutStr := c.QueryParam("timeout"); timeoutStr != "" {
+		parsedTimeout, err := time.ParseDuration(timeoutStr)
+		if err != nil || parsedTimeout < 0 || parsedTimeout > CI_MAX_WAIT {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("Invalid timeout (must be a duration up to %s)", CI_MAX_WAIT)})
+		}
+		timeout = parsedTimeout
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(ciPollInterval)
+	defer ticker.Stop()
+
+	for {
+		job, ended, err := m.jobWithEnded(rid)
+		if err != nil {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "Job not found"})
+		}
+		if ended {
+			return c.JSON(http.StatusOK, job)
+		}
+
+		select {
+		case <-c.Request().Context().Done():
+			return c.Request().Context().Err()
+		case <-deadline.C:
+			return c.JSON(http.StatusAccepted, job)
+		case <-m.ShuttingDown():
+			return c.JSON(http.StatusAccepted, job)
+		case <-ticker.C:
+		}
+	}
+}
+
+// CIDownloadArtifact downloads a file of the file subsystem, eg. an output file of a job
+func (m *ManagerHandler) CIDownloadArtifact(c *echo.Context) error {
+	if status, err := m.checkCIToken(c.Request()); err != nil {
+		return c.JSON(status, map[string]string{"error": err.Error()})
+	}
+
+	filename := filepath.Base(c.Param("filename"))
+	file, err := m.Filesystem.Open(filename)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Artifact not found"})
+	}
+	defer file.Close()
+
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	return c.Stream(http.StatusOK, "application/octet-stream", file)
+}
+
+// checkCIToken checks the bearer token of CI requests. The CI endpoints are disabled without a configured token.
+func (m *ManagerHandler) checkCIToken(r *http.Request) (int, error) {
+	return checkBearerToken(r, m.CIToken, "CI")
+}
+
+// checkBearerToken compares the bearer token of the request with the token of an integration.
+// It returns the status code to respond with if the integration is not configured or the token is invalid.
+func checkBearerToken(r *http.Request, expected string, integration string) (int, error) {
+	if expected == "" {
+		return http.StatusServiceUnavailable, fmt.Errorf("%s integration is not configured", integration)
+	}
+
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+		return http.StatusUnauthorized, fmt.Errorf("invalid %s token", integration)
+	}
+
+	return 0, nil
+}
+
+// jobWithEnded retrieves an active or archived job and reports if it has ended.
+func (m *ManagerHandler) jobWithEnded(rid uuid.UUID) (*model.Job, bool, error) {
+	job, err := m.Queuer.GetJob(rid)
+	if err != nil {
+		job, err = m.Queuer.GetJobEnded(rid)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	return job, jobEnded(job), nil
+}
+
+// jobEnded returns true if the job ended with a final status.
+func jobEnded(job *model.Job) bool {
+	return job.Status == model.JobStatusSucceeded || job.Status == model.JobStatusFailed || job.Status == model.JobStatusCancelled
+}
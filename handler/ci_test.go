@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	vm "github.com/siherrmann/validator/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCIHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.CIToken = "test-ci-token"
+	e := echo.New()
+
+	_, err = tdb.InsertTask(&qmModel.Task{
+		Key:                  "test-ci-task",
+		Name:                 "Test CI Task",
+		InputParameters:      []vm.Validation{},
+		InputParametersKeyed: []vm.Validation{{Key: "input_file", Type: vm.String, Requirement: "min1"}},
+	})
+	require.NoError(t, err)
+
+	var run qmModel.CIJobRun
+	t.Run("RunJob with artifact", func(t *testing.T) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		err := writer.WriteField("parameters", `{"input_file": "${artifact:data.csv}"}`)
+		require.NoError(t, err)
+		part, err := writer.CreateFormFile("artifacts", "data.csv")
+		require.NoError(t, err)
+		_, err = part.Write([]byte("a,b\n1,2\n"))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/api/ci/runJob/test-ci-task", body)
+		req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer test-ci-token")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: "test-ci-task"}})
+
+		err = handler.CIRunJob(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		err = json.Unmarshal(rec.Body.Bytes(), &run)
+		require.NoError(t, err)
+		require.NotNil(t, run.Job)
+		storedName := run.Artifacts["data.csv"]
+		require.NotEmpty(t, storedName)
+
+		file, err := fs.Open(storedName)
+		require.NoError(t, err)
+		defer file.Close()
+		content, err := io.ReadAll(file)
+		require.NoError(t, err)
+		assert.Equal(t, "a,b\n1,2\n", string(content))
+	})
+
+	t.Run("WaitJob returns the current job after the timeout", func(t *testing.T) {
+		require.NotNil(t, run.Job)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/ci/waitJob/"+run.Job.RID.String()+"?timeout=0s", nil)
+		req.Header.Set("Authorization", "Bearer test-ci-token")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "rid", Value: run.Job.RID.String()}})
+
+		err := handler.CIWaitJob(c)
+		require.NoError(t, err)
+		assert.Contains(t, []int{http.StatusOK, http.StatusAccepted}, rec.Code)
+		assert.Contains(t, rec.Body.String(), run.Job.RID.String())
+	})
+
+	t.Run("WaitJob with invalid timeout", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/ci/waitJob/"+run.Job.RID.String()+"?timeout=1h", nil)
+		req.Header.Set("Authorization", "Bearer test-ci-token")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "rid", Value: run.Job.RID.String()}})
+
+		err := handler.CIWaitJob(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("DownloadArtifact", func(t *testing.T) {
+		storedName := run.Artifacts["data.csv"]
+		require.NotEmpty(t, storedName)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/ci/downloadArtifact/"+storedName, nil)
+		req.Header.Set("Authorization", "Bearer test-ci-token")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "filename", Value: storedName}})
+
+		err := handler.CIDownloadArtifact(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "a,b\n1,2\n", rec.Body.String())
+	})
+
+	t.Run("RunJob with JSON body and invalid parameters", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/ci/runJob/test-ci-task", strings.NewReader(`{"input_file": ""}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("Authorization", "Bearer test-ci-token")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: "test-ci-task"}})
+
+		err := handler.CIRunJob(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Invalid token is rejected", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/ci/waitJob/"+run.Job.RID.String(), nil)
+		req.Header.Set("Authorization", "Bearer wrong-token")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "rid", Value: run.Job.RID.String()}})
+
+		err := handler.CIWaitJob(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
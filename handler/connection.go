@@ -3,6 +3,8 @@ package handler
 import (
 	"net/http"
 
+	"github.com/siherrmann/queuerManager/model"
+
 	"github.com/labstack/echo/v5"
 )
 
@@ -15,3 +17,12 @@ func (m *ManagerHandler) GetConnections(c *echo.Context) error {
 
 	return c.JSON(http.StatusOK, connections)
 }
+
+// GetPoolStats retrieves the current statistics of the shared database connection pool
+func (m *ManagerHandler) GetPoolStats(c *echo.Context) error {
+	if m.QueuerDB == nil {
+		return c.String(http.StatusServiceUnavailable, "Database connection not available")
+	}
+
+	return c.JSON(http.StatusOK, model.NewDBPoolStats(m.QueuerDB.Stats()))
+}
@@ -64,4 +64,21 @@ func TestGetConnectionsHandlers(t *testing.T) {
 		err = json.Unmarshal(rec.Body.Bytes(), &connections)
 		require.NoError(t, err)
 	})
+
+	t.Run("GetPoolStats returns pool statistics", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/connection/getPoolStats", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetPoolStats(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var stats map[string]interface{}
+		err = json.Unmarshal(rec.Body.Bytes(), &stats)
+		require.NoError(t, err)
+		assert.Contains(t, stats, "open_connections")
+		assert.Contains(t, stats, "in_use")
+		assert.Contains(t, stats, "idle")
+	})
 }
@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	qm "github.com/siherrmann/queuer/model"
+
+	"github.com/labstack/echo/v5"
+)
+
+// coordinationInstanceLimit is the maximum number of manager instances shown in the coordination status.
+const coordinationInstanceLimit = 100
+
+// Coordinator acquires and renews the leases of the background work of a manager instance, so with several instances
+// only one runs the schedules and one the housekeeping. A nil coordinator holds all leases, eg. for a single instance.
+type Coordinator struct {
+	acquire func(name string, holder string, ttl time.Duration) (*model.ManagerLease, error)
+	release func(holder string) error
+	ttl     time.Duration
+
+	mu       sync.RWMutex
+	instance string
+	held     map[string]bool
+}
+
+// NewCoordinator creates a coordinator acquiring the leases with acquire until now plus the ttl and releasing them with release.
+func NewCoordinator(acquire func(name string, holder string, ttl time.Duration) (*model.ManagerLease, error), release func(holder string) error, ttl time.Duration) *Coordinator {
+	return &Coordinator{
+		acquire: acquire,
+		release: release,
+		ttl:     ttl,
+		held:    map[string]bool{},
+	}
+}
+
+// Instance returns the instance the coordinator acquires the leases for, empty until it runs.
+func (c *Coordinator) Instance() string {
+	if c == nil {
+		return ""
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.instance
+}
+
+// TTL returns the time a lease is held without renewal.
+func (c *Coordinator) TTL() time.Duration {
+	if c == nil {
+		return 0
+	}
+	return c.ttl
+}
+
+// Holds reports whether the instance holds the lease, it always does without coordinator.
+func (c *Coordinator) Holds(name string) bool {
+	if c == nil {
+		return true
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.held[name]
+}
+
+// Renew acquires or renews all leases for the instance. A lease that can not be renewed is not held anymore,
+// so with an unreachable database no instance runs the background work twice.
+func (c *Coordinator) Renew() {
+	instance := c.Instance()
+	if instance == "" {
+		return
+	}
+
+	held := map[string]bool{}
+	for _, name := range model.LEASES {
+		lease, err := c.acquire(name, instance, c.ttl)
+		if err != nil {
+			log.Printf("Failed to acquire lease %s: %v", name, err)
+			continue
+		}
+		held[name] = lease.Holder == instance && lease.IsHeld(time.Now())
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name, holds := range held {
+		if holds && !c.held[name] {
+			log.Printf("Instance %s acquired lease %s", instance, name)
+		} else if !holds && c.held[name] {
+			log.Printf("Instance %s lost lease %s", instance, name)
+		}
+	}
+	c.held = held
+}
+
+// Run renews the leases for the instance every interval until the context is done, the interval has to be shorter than the ttl.
+func (c *Coordinator) Run(ctx context.Context, instance string, interval time.Duration) {
+	c.mu.Lock()
+	c.instance = instance
+	c.mu.Unlock()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	c.Renew()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Renew()
+		}
+	}
+}
+
+// Release releases the leases of the instance, so other instances take over without waiting for the ttl.
+func (c *Coordinator) Release() {
+	if c == nil || c.Instance() == "" {
+		return
+	}
+
+	c.mu.Lock()
+	c.held = map[string]bool{}
+	c.mu.Unlock()
+
+	err := c.release(c.Instance())
+	if err != nil {
+		log.Printf("Failed to release leases: %v", err)
+	}
+}
+
+// coordinationStatus returns the holder of the master lock of the queuer, the leases and the manager instances.
+func (m *ManagerHandler) coordinationStatus() (*model.CoordinationStatus, error) {
+	status := &model.CoordinationStatus{
+		Instance:  m.Coordinator.Instance(),
+		Leases:    []*model.ManagerLease{},
+		Instances: []*qm.Worker{},
+	}
+
+	if m.QueuerMasterDB != nil {
+		// Without master entry no worker ever held the master lock
+		master, err := m.QueuerMasterDB.SelectMaster()
+		if err != nil {
+			log.Printf("Failed to select master: %v", err)
+		} else {
+			status.Master = master
+		}
+	}
+
+	if m.ManagerLeaseDB != nil {
+		leases, err := m.ManagerLeaseDB.SelectAllLeases()
+		if err != nil {
+			return nil, fmt.Errorf("failed to select leases: %w", err)
+		}
+		status.Leases = leases
+	}
+	for _, name := range model.LEASES {
+		if !slices.ContainsFunc(status.Leases, func(lease *model.ManagerLease) bool { return lease.Name == name }) {
+			status.Leases = append(status.Leases, &model.ManagerLease{Name: name})
+		}
+	}
+
+	workers, err := m.Queuer.GetWorkersBySearch(model.MANAGER_WORKER_NAME, 0, coordinationInstanceLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get manager instances: %w", err)
+	}
+	for _, worker := range workers {
+		if worker.Name == model.MANAGER_WORKER_NAME {
+			status.Instances = append(status.Instances, worker)
+		}
+	}
+
+	return status, nil
+}
+
+// GetCoordinationStatus returns which manager instance holds the master lock of the queuer and the leases of the
+// scheduler and the housekeeping, and the manager instances with their heartbeat.
+func (m *ManagerHandler) GetCoordinationStatus(c *echo.Context) error {
+	status, err := m.coordinationStatus()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, status)
+}
+
+// FailoverLease expires the lease of ?lease=, so another instance takes it over. The previous holder can not acquire
+// it again for the ttl of the leases, with a single instance it takes the lease back after the ttl.
+func (m *ManagerHandler) FailoverLease(c *echo.Context) error {
+	if m.ManagerLeaseDB == nil || m.Coordinator == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Manager coordination is not enabled")
+	}
+
+	name := c.QueryParam("lease")
+	if !slices.Contains(model.LEASES, name) {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid lease %q", name))
+	}
+
+	lease, err := m.ManagerLeaseDB.FailoverLease(name, m.Coordinator.TTL())
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Failed to failover lease: %v", err))
+	}
+	log.Printf("Lease %s of instance %s failed over by %s", name, lease.FailoverFrom, requestedBy(c))
+
+	// The instance of the request drops the lease right away instead of at its next renewal
+	if lease.FailoverFrom == m.Coordinator.Instance() {
+		m.Coordinator.Renew()
+	}
+
+	c.Response().Header().Add("HX-Trigger-After-Settle", "closeFailoverLease, reloadCoordination")
+
+	return renderPopupOrJson(c, http.StatusOK, fmt.Sprintf("Lease %s released by instance %s", name, lease.FailoverFrom))
+}
+
+// =======View Handlers=======
+
+// CoordinationView renders the holders of the master lock and the leases of the manager instances
+func (m *ManagerHandler) CoordinationView(c *echo.Context) error {
+	status, err := m.coordinationStatus()
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, err.Error())
+	}
+
+	c.Response().Header().Add("HX-Push-Url", "/coordination")
+	c.Response().Header().Add("HX-Retarget", "#body")
+
+	return render(c, screens.Coordination(status, time.Now()))
+}
+
+// FailoverLeasePopupView renders the failover lease popup
+func (m *ManagerHandler) FailoverLeasePopupView(c *echo.Context) error {
+	return renderPopup(c, screens.FailoverLeasePopup(c.QueryParam("lease")))
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoordinator(t *testing.T) {
+	holders := map[string]string{}
+	acquire := func(name string, holder string, ttl time.Duration) (*model.ManagerLease, error) {
+		if holders[name] == "" {
+			holders[name] = holder
+		}
+		return &model.ManagerLease{Name: name, Holder: holders[name], ExpiresAt: time.Now().Add(ttl)}, nil
+	}
+	released := ""
+	release := func(holder string) error {
+		released = holder
+		return nil
+	}
+
+	t.Run("Without coordinator all leases are held", func(t *testing.T) {
+		var coordinator *Coordinator
+		assert.True(t, coordinator.Holds(model.LEASE_SCHEDULER))
+		assert.Empty(t, coordinator.Instance())
+		coordinator.Release()
+	})
+
+	t.Run("No leases are held before the coordinator runs", func(t *testing.T) {
+		coordinator := NewCoordinator(acquire, release, time.Minute)
+		coordinator.Renew()
+		assert.False(t, coordinator.Holds(model.LEASE_SCHEDULER))
+		assert.Empty(t, holders, "Expected no lease to be acquired without instance")
+	})
+
+	coordinator := NewCoordinator(acquire, release, time.Minute)
+	coordinator.instance = "instance-a"
+	holders[model.LEASE_HOUSEKEEPING] = "instance-b"
+
+	t.Run("Renew acquires the free leases", func(t *testing.T) {
+		coordinator.Renew()
+		assert.True(t, coordinator.Holds(model.LEASE_SCHEDULER))
+		assert.False(t, coordinator.Holds(model.LEASE_HOUSEKEEPING), "Expected the lease of another instance to not be held")
+	})
+
+	t.Run("Renew drops taken over leases", func(t *testing.T) {
+		holders[model.LEASE_SCHEDULER] = "instance-b"
+		coordinator.Renew()
+		assert.False(t, coordinator.Holds(model.LEASE_SCHEDULER))
+	})
+
+	t.Run("Release releases the leases of the instance", func(t *testing.T) {
+		holders[model.LEASE_SCHEDULER] = "instance-a"
+		coordinator.Renew()
+		require.True(t, coordinator.Holds(model.LEASE_SCHEDULER))
+
+		coordinator.Release()
+		assert.False(t, coordinator.Holds(model.LEASE_SCHEDULER))
+		assert.Equal(t, "instance-a", released)
+	})
+}
+
+func TestFailoverLeaseWithoutCoordination(t *testing.T) {
+	m := &ManagerHandler{}
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/coordination/failover?lease=scheduler", nil)
+	rec := httptest.NewRecorder()
+	err := m.FailoverLease(e.NewContext(req, rec))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestCoordinationHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	mldb, err := database.NewManagerLeaseDBHandler(db, true)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.ManagerLeaseDB = mldb
+	handler.Coordinator = NewCoordinator(mldb.AcquireLease, mldb.ReleaseLeases, time.Minute)
+	handler.Coordinator.instance = queue.GetCurrentWorkerRID().String()
+	handler.Coordinator.Renew()
+	e := echo.New()
+
+	getStatus := func(t *testing.T) *model.CoordinationStatus {
+		req := httptest.NewRequest(http.MethodGet, "/api/coordination/getStatus", nil)
+		rec := httptest.NewRecorder()
+		err := handler.GetCoordinationStatus(e.NewContext(req, rec))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		status := &model.CoordinationStatus{}
+		err = json.Unmarshal(rec.Body.Bytes(), status)
+		require.NoError(t, err)
+		return status
+	}
+
+	t.Run("GetCoordinationStatus", func(t *testing.T) {
+		status := getStatus(t)
+		assert.Equal(t, handler.Coordinator.Instance(), status.Instance)
+		require.Len(t, status.Leases, len(model.LEASES))
+		for _, lease := range status.Leases {
+			assert.Equal(t, status.Instance, lease.Holder, "Expected the instance to hold lease %s", lease.Name)
+		}
+	})
+
+	t.Run("FailoverLease with an invalid lease", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/coordination/failover?lease=unknown", nil)
+		rec := httptest.NewRecorder()
+		err := handler.FailoverLease(e.NewContext(req, rec))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("FailoverLease", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/coordination/failover?lease=scheduler", nil)
+		rec := httptest.NewRecorder()
+		err := handler.FailoverLease(e.NewContext(req, rec))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.False(t, handler.Coordinator.Holds(model.LEASE_SCHEDULER), "Expected the instance to drop the failed over lease")
+		assert.True(t, handler.Coordinator.Holds(model.LEASE_HOUSEKEEPING))
+
+		handler.Coordinator.Renew()
+		assert.False(t, handler.Coordinator.Holds(model.LEASE_SCHEDULER), "Expected the instance to not take the lease back during the failover")
+	})
+}
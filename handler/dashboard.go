@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/labstack/echo/v5"
+)
+
+// dashboardWindow returns the dashboard window with the name, an empty name selects the default window.
+func dashboardWindow(name string) (model.DashboardWindow, error) {
+	if name == "" {
+		name = model.DASHBOARD_DEFAULT_WINDOW
+	}
+	for _, window := range model.DASHBOARD_WINDOWS {
+		if window.Name == name {
+			return window, nil
+		}
+	}
+	return model.DashboardWindow{}, fmt.Errorf("Invalid window (must be 1h, 6h, 24h, 7d or 30d)")
+}
+
+// dashboardBucket returns the throughput bucket of a window, hourly for windows up to two days and daily above.
+func dashboardBucket(window time.Duration) time.Duration {
+	if window > 48*time.Hour {
+		return 24 * time.Hour
+	}
+	return time.Hour
+}
+
+// fillThroughput returns the throughput with an empty bucket for every bucket between since and until without ended jobs,
+// the buckets are aligned to the unix epoch like the buckets of the job stats.
+func fillThroughput(throughput []*model.JobStats, since time.Time, until time.Time, bucket time.Duration) []*model.JobStats {
+	byTime := map[int64]*model.JobStats{}
+	for _, stat := range throughput {
+		byTime[stat.Time.Unix()] = stat
+	}
+
+	filled := []*model.JobStats{}
+	for t := since.Truncate(bucket); !t.After(until); t = t.Add(bucket) {
+		if stat, ok := byTime[t.Unix()]; ok {
+			filled = append(filled, stat)
+		} else {
+			filled = append(filled, &model.JobStats{Time: t})
+		}
+	}
+	return filled
+}
+
+// dashboardStatsSources returns the data sources of the panels of the dashboard stats between since and until.
+func (m *ManagerHandler) dashboardStatsSources(since time.Time, until time.Time, bucket time.Duration) []dataSource {
+	return []dataSource{
+		{Name: model.DASHBOARD_PANEL_JOBS_PER_STATUS, Load: func(ctx context.Context) (any, error) {
+			jobsPerStatus, err := m.MetricDB.SelectJobStatusCounts(since)
+			if err != nil {
+				return nil, fmt.Errorf("failed to retrieve jobs per status: %v", err)
+			}
+			return jobsPerStatus, nil
+		}},
+		{Name: model.DASHBOARD_PANEL_THROUGHPUT, Load: func(ctx context.Context) (any, error) {
+			throughput, err := m.MetricDB.SelectJobStats(since, until, bucket, "")
+			if err != nil {
+				return nil, fmt.Errorf("failed to retrieve throughput: %v", err)
+			}
+			return fillThroughput(throughput, since, until, bucket), nil
+		}},
+		{Name: model.DASHBOARD_PANEL_TASKS, Load: func(ctx context.Context) (any, error) {
+			tasks, err := m.MetricDB.SelectTaskStats(since, until)
+			if err != nil {
+				return nil, fmt.Errorf("failed to retrieve task stats: %v", err)
+			}
+			return tasks, nil
+		}},
+		{Name: model.DASHBOARD_PANEL_ACTIVE_WORKERS, Load: func(ctx context.Context) (any, error) {
+			activeWorkers, err := m.MetricDB.SelectActiveWorkersCount()
+			if err != nil {
+				return nil, fmt.Errorf("failed to retrieve active workers: %v", err)
+			}
+			return activeWorkers, nil
+		}},
+	}
+}
+
+// dashboardPanel sets the value of a panel from the loaded sources, or the error of the panel if it failed or timed out.
+func dashboardPanel[T any](stats *model.DashboardStats, results map[string]dataSourceResult, panel string, value *T) {
+	loaded, err := dataSourceValue[T](results, panel)
+	if err != nil {
+		stats.Errors[panel] = err.Error()
+		return
+	}
+	*value = loaded
+}
+
+// newDashboardStats builds the aggregate job statistics of the window from the loaded panels.
+func newDashboardStats(window model.DashboardWindow, since time.Time, until time.Time, bucket time.Duration, results map[string]dataSourceResult) *model.DashboardStats {
+	stats := &model.DashboardStats{
+		Window:        window.Name,
+		Since:         since,
+		Until:         until,
+		BucketSeconds: int64(bucket.Seconds()),
+		Errors:        map[string]string{},
+	}
+	dashboardPanel(stats, results, model.DASHBOARD_PANEL_JOBS_PER_STATUS, &stats.JobsPerStatus)
+	dashboardPanel(stats, results, model.DASHBOARD_PANEL_THROUGHPUT, &stats.Throughput)
+	dashboardPanel(stats, results, model.DASHBOARD_PANEL_TASKS, &stats.Tasks)
+	dashboardPanel(stats, results, model.DASHBOARD_PANEL_ACTIVE_WORKERS, &stats.ActiveWorkers)
+	return stats
+}
+
+// dashboardStats collects the aggregate job statistics of the window. The panels are loaded concurrently,
+// so a slow or failing panel is shown with its error instead of blanking the whole dashboard.
+// More sources, eg. the heatmap, are loaded together with the panels and their results are returned.
+func (m *ManagerHandler) dashboardStats(ctx context.Context, window model.DashboardWindow, sources ...dataSource) (*model.DashboardStats, map[string]dataSourceResult) {
+	until := time.Now()
+	since := until.Add(-window.Duration)
+	bucket := dashboardBucket(window.Duration)
+
+	results := loadDataSources(ctx, append(m.dashboardStatsSources(since, until, bucket), sources...)...)
+	return newDashboardStats(window, since, until, bucket, results), results
+}
+
+// heatmapLocation returns the timezone of the job heatmap with the name, an empty name selects UTC.
+func heatmapLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	// Local would group by the timezone of the server, which is not known to the caller
+	location, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, fmt.Errorf("Invalid timezone (must be an IANA timezone like Europe/Berlin)")
+	}
+	return location, nil
+}
+
+// fillHeatmap returns a cell for every hour of every weekday, starting on Monday, with empty cells for the hours without ended jobs.
+func fillHeatmap(cells []*model.HeatmapCell) []*model.HeatmapCell {
+	byWeekdayHour := map[[2]int]*model.HeatmapCell{}
+	for _, cell := range cells {
+		byWeekdayHour[[2]int{cell.Weekday, cell.Hour}] = cell
+	}
+
+	filled := []*model.HeatmapCell{}
+	for i := 1; i <= 7; i++ {
+		weekday := i % 7
+		for hour := 0; hour < 24; hour++ {
+			if cell, ok := byWeekdayHour[[2]int{weekday, hour}]; ok {
+				filled = append(filled, cell)
+			} else {
+				filled = append(filled, &model.HeatmapCell{Weekday: weekday, Hour: hour})
+			}
+		}
+	}
+	return filled
+}
+
+// jobHeatmap collects the job volume and failure rate by weekday and hour of the window in the timezone,
+// optionally of one task.
+func (m *ManagerHandler) jobHeatmap(window model.DashboardWindow, location *time.Location, taskName string) (*model.JobHeatmap, error) {
+	until := time.Now()
+	since := until.Add(-window.Duration)
+
+	cells, err := m.MetricDB.SelectJobHeatmap(since, until, location.String(), taskName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve job heatmap: %v", err)
+	}
+
+	heatmap := &model.JobHeatmap{
+		Window:   window.Name,
+		Timezone: location.String(),
+		Since:    since,
+		Until:    until,
+		Cells:    fillHeatmap(cells),
+	}
+	for _, cell := range heatmap.Cells {
+		heatmap.MaxTotal = max(heatmap.MaxTotal, cell.Total)
+	}
+	return heatmap, nil
+}
+
+// =======API Handlers=======
+
+// GetStats returns the jobs per status, the throughput, the average duration and failure rate per task
+// and the active worker count of a time window, eg. /api/stats?window=7d. window is 1h, 6h, 24h (default), 7d or 30d.
+// The fields of the parts that failed or timed out are empty and their errors are in errors by part.
+func (m *ManagerHandler) GetStats(c *echo.Context) error {
+	if m.MetricDB == nil {
+		return c.String(http.StatusServiceUnavailable, "Metrics are not enabled")
+	}
+
+	window, err := dashboardWindow(c.QueryParam("window"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	// The stats of the loaded panels are returned with the errors of the failed panels
+	stats, _ := m.dashboardStats(c.Request().Context(), window)
+	if len(stats.Errors) == len(model.DASHBOARD_STATS_PANELS) {
+		return c.String(http.StatusInternalServerError, "Failed to calculate stats")
+	}
+
+	return c.JSON(http.StatusOK, stats)
+}
+
+// GetHeatmap returns the job volume and failure rate by hour of day and day of week of a time window,
+// eg. /api/stats/heatmap?window=30d&tz=Europe/Berlin&task=yourTask to plan maintenance windows in low-traffic slots.
+// window is 1h, 6h, 24h, 7d or 30d (default), tz an IANA timezone (default UTC) and task optionally filters by task.
+func (m *ManagerHandler) GetHeatmap(c *echo.Context) error {
+	if m.MetricDB == nil {
+		return c.String(http.StatusServiceUnavailable, "Metrics are not enabled")
+	}
+
+	windowName := c.QueryParam("window")
+	if windowName == "" {
+		windowName = model.HEATMAP_DEFAULT_WINDOW
+	}
+	window, err := dashboardWindow(windowName)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	location, err := heatmapLocation(c.QueryParam("tz"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	heatmap, err := m.jobHeatmap(window, location, c.QueryParam("task"))
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to calculate heatmap")
+	}
+
+	return c.JSON(http.StatusOK, heatmap)
+}
+
+// =======View Handlers=======
+
+// DashboardView renders the charts of the aggregate job statistics of a time window, eg. /dashboard?window=7d
+func (m *ManagerHandler) DashboardView(c *echo.Context) error {
+	if m.MetricDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Metrics are not enabled")
+	}
+
+	window, err := dashboardWindow(c.QueryParam("window"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+
+	// The heatmap always shows the default window, the hours of a shorter window are too few to show a pattern
+	heatmapWindow, err := dashboardWindow(model.HEATMAP_DEFAULT_WINDOW)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, err.Error())
+	}
+
+	// The panels and the heatmap are loaded concurrently, failed panels are shown with their error
+	stats, results := m.dashboardStats(c.Request().Context(), window, dataSource{
+		Name: model.DASHBOARD_PANEL_HEATMAP,
+		Load: func(ctx context.Context) (any, error) {
+			return m.jobHeatmap(heatmapWindow, time.UTC, "")
+		},
+	})
+	var heatmap *model.JobHeatmap
+	dashboardPanel(stats, results, model.DASHBOARD_PANEL_HEATMAP, &heatmap)
+
+	c.Response().Header().Add("HX-Push-Url", "/dashboard?window="+window.Name)
+	c.Response().Header().Add("HX-Retarget", "#body")
+
+	return render(c, screens.Dashboard(stats, heatmap))
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/siherrmann/queuerManager/view/screens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboardWindow(t *testing.T) {
+	window, err := dashboardWindow("")
+	require.NoError(t, err)
+	assert.Equal(t, model.DASHBOARD_DEFAULT_WINDOW, window.Name)
+	assert.Equal(t, 24*time.Hour, window.Duration)
+
+	window, err = dashboardWindow("7d")
+	require.NoError(t, err)
+	assert.Equal(t, 7*24*time.Hour, window.Duration)
+
+	_, err = dashboardWindow("2h")
+	assert.Error(t, err, "Expected only the dashboard windows to be selectable")
+
+	assert.Equal(t, time.Hour, dashboardBucket(24*time.Hour))
+	assert.Equal(t, 24*time.Hour, dashboardBucket(7*24*time.Hour))
+}
+
+func TestFillThroughput(t *testing.T) {
+	since := time.Date(2026, 1, 1, 10, 30, 0, 0, time.UTC)
+	until := since.Add(3 * time.Hour)
+	throughput := []*model.JobStats{
+		{Time: time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC), Succeeded: 2, Failed: 1},
+	}
+
+	filled := fillThroughput(throughput, since, until, time.Hour)
+	require.Len(t, filled, 4, "Expected a bucket for every hour from 10:00 to 13:00")
+	assert.Equal(t, time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC), filled[0].Time)
+	assert.Equal(t, 0, filled[0].Succeeded)
+	assert.Equal(t, 2, filled[1].Succeeded)
+	assert.Equal(t, 1, filled[1].Failed)
+	assert.Equal(t, time.Date(2026, 1, 1, 13, 0, 0, 0, time.UTC), filled[3].Time)
+}
+
+func TestNewDashboardStats(t *testing.T) {
+	window, err := dashboardWindow("24h")
+	require.NoError(t, err)
+	until := time.Now()
+	since := until.Add(-window.Duration)
+
+	results := loadDataSources(
+		context.Background(),
+		dataSource{Name: model.DASHBOARD_PANEL_JOBS_PER_STATUS, Load: func(ctx context.Context) (any, error) {
+			return []*model.StatusCount{{Status: "SUCCEEDED", Count: 3}}, nil
+		}},
+		dataSource{Name: model.DASHBOARD_PANEL_THROUGHPUT, Load: func(ctx context.Context) (any, error) {
+			return fillThroughput(nil, since, until, time.Hour), nil
+		}},
+		dataSource{Name: model.DASHBOARD_PANEL_TASKS, Timeout: 20 * time.Millisecond, Load: func(ctx context.Context) (any, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}},
+		dataSource{Name: model.DASHBOARD_PANEL_ACTIVE_WORKERS, Load: func(ctx context.Context) (any, error) {
+			return 2, nil
+		}},
+	)
+
+	stats := newDashboardStats(window, since, until, time.Hour, results)
+	assert.Equal(t, 3, stats.JobsPerStatus[0].Count)
+	assert.Equal(t, 2, stats.ActiveWorkers)
+	assert.Nil(t, stats.Tasks)
+	require.Len(t, stats.Errors, 1, "Expected only the timed out panel to fail")
+	assert.Contains(t, stats.Errors[model.DASHBOARD_PANEL_TASKS], context.DeadlineExceeded.Error())
+
+	var html bytes.Buffer
+	err = screens.Dashboard(stats, &model.JobHeatmap{Window: model.HEATMAP_DEFAULT_WINDOW}).Render(context.Background(), &html)
+	require.NoError(t, err)
+	assert.Contains(t, html.String(), "Failed to load: data source tasks", "Expected the timed out panel to show its error")
+	assert.Contains(t, html.String(), "Jobs per Status", "Expected the loaded panels to be rendered")
+}
+
+func TestFillHeatmap(t *testing.T) {
+	cells := []*model.HeatmapCell{
+		{Weekday: 0, Hour: 23, Total: 4, Failed: 1},
+		{Weekday: 1, Hour: 0, Total: 2},
+	}
+
+	filled := fillHeatmap(cells)
+	require.Len(t, filled, 7*24, "Expected a cell for every hour of every weekday")
+	assert.Equal(t, 1, filled[0].Weekday, "Expected the heatmap to start on Monday")
+	assert.Equal(t, 0, filled[0].Hour)
+	assert.Equal(t, 2, filled[0].Total)
+	assert.Equal(t, 0, filled[1].Total)
+	assert.Equal(t, 0, filled[len(filled)-1].Weekday, "Expected the heatmap to end on Sunday")
+	assert.Equal(t, 23, filled[len(filled)-1].Hour)
+	assert.Equal(t, 4, filled[len(filled)-1].Total)
+	assert.InDelta(t, 0.25, filled[len(filled)-1].FailureRate(), 0.001)
+}
+
+func TestHeatmapLocation(t *testing.T) {
+	location, err := heatmapLocation("")
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, location)
+
+	location, err = heatmapLocation("Europe/Berlin")
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", location.String())
+
+	_, err = heatmapLocation("Local")
+	assert.Error(t, err, "Expected the server timezone to not be selectable")
+	_, err = heatmapLocation("Mars/Olympus")
+	assert.Error(t, err, "Expected unknown timezones to return an error")
+}
+
+func TestGetStatsHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("dashboarddb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	mdb, err := database.NewMetricDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.MetricDB = mdb
+	e := echo.New()
+
+	t.Run("GetStats with default window", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/stats", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetStats(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var stats model.DashboardStats
+		err = json.Unmarshal(rec.Body.Bytes(), &stats)
+		require.NoError(t, err)
+		assert.Equal(t, model.DASHBOARD_DEFAULT_WINDOW, stats.Window)
+		assert.Equal(t, int64(3600), stats.BucketSeconds)
+		assert.NotEmpty(t, stats.Throughput, "Expected a throughput bucket for every hour of the window")
+	})
+
+	t.Run("GetStats with invalid window", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/stats?window=2h", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetStats(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("GetStats without metrics", func(t *testing.T) {
+		handlerWithoutMetrics := NewManagerHandler(fs, tdb, queue)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/stats", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handlerWithoutMetrics.GetStats(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+}
+
+func TestGetHeatmapHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("heatmapdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	mdb, err := database.NewMetricDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.MetricDB = mdb
+	e := echo.New()
+
+	t.Run("GetHeatmap with default window", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/stats/heatmap?tz=Europe/Berlin", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetHeatmap(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var heatmap model.JobHeatmap
+		err = json.Unmarshal(rec.Body.Bytes(), &heatmap)
+		require.NoError(t, err)
+		assert.Equal(t, model.HEATMAP_DEFAULT_WINDOW, heatmap.Window)
+		assert.Equal(t, "Europe/Berlin", heatmap.Timezone)
+		assert.Len(t, heatmap.Cells, 7*24, "Expected a cell for every hour of every weekday")
+	})
+
+	t.Run("GetHeatmap with invalid timezone", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/stats/heatmap?tz=Mars/Olympus", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetHeatmap(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// defaultDataSourceTimeout is used for data sources without an explicit timeout.
+const defaultDataSourceTimeout = 5 * time.Second
+
+// dataSource describes one source of data (tasks, jobs, archive, workers, ...) a view needs.
+// All sources of a view are loaded concurrently by loadDataSources.
+type dataSource struct {
+	Name    string
+	Timeout time.Duration
+	Load    func(ctx context.Context) (any, error)
+}
+
+// dataSourceResult holds the loaded data or the error of a single data source.
+type dataSourceResult struct {
+	Data any
+	Err  error
+}
+
+// loadDataSources loads all sources concurrently, each bounded by its own timeout.
+// A failing or slow source does not cancel the other sources. Its error is returned
+// in the result map under the source name, so the view can still render the data
+// that was loaded and mark the missing parts instead of failing completely.
+func loadDataSources(ctx context.Context, sources ...dataSource) map[string]dataSourceResult {
+	results := make(map[string]dataSourceResult, len(sources))
+	var mu sync.Mutex
+
+	g, gCtx := errgroup.WithContext(ctx)
+	for _, source := range sources {
+		g.Go(func() error {
+			result := loadDataSource(gCtx, source)
+
+			mu.Lock()
+			results[source.Name] = result
+			mu.Unlock()
+
+			// Errors are collected per source and never cancel the group
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return results
+}
+
+// loadDataSource loads a single source and stops waiting for it after its timeout.
+// The load function keeps running in the background if it does not respect the context,
+// but its late result is discarded.
+func loadDataSource(ctx context.Context, source dataSource) dataSourceResult {
+	timeout := source.Timeout
+	if timeout <= 0 {
+		timeout = defaultDataSourceTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resultChan := make(chan dataSourceResult, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				resultChan <- dataSourceResult{Err: fmt.Errorf("data source %s panicked: %v", source.Name, r)}
+			}
+		}()
+		data, err := source.Load(ctx)
+		resultChan <- dataSourceResult{Data: data, Err: err}
+	}()
+
+	select {
+	case result := <-resultChan:
+		return result
+	case <-ctx.Done():
+		return dataSourceResult{Err: fmt.Errorf("data source %s: %w", source.Name, ctx.Err())}
+	}
+}
+
+// dataSourceValue returns the typed data of a source from the results of loadDataSources.
+// It returns the zero value and an error if the source failed, is missing or has an unexpected type.
+func dataSourceValue[T any](results map[string]dataSourceResult, name string) (T, error) {
+	var zero T
+	result, ok := results[name]
+	if !ok {
+		return zero, fmt.Errorf("data source %s was not loaded", name)
+	}
+	if result.Err != nil {
+		return zero, result.Err
+	}
+	value, ok := result.Data.(T)
+	if !ok {
+		return zero, fmt.Errorf("data source %s has unexpected type %T", name, result.Data)
+	}
+	return value, nil
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDataSources(t *testing.T) {
+	t.Run("Loads all sources concurrently", func(t *testing.T) {
+		start := time.Now()
+		results := loadDataSources(
+			context.Background(),
+			dataSource{Name: "first", Load: func(ctx context.Context) (any, error) {
+				time.Sleep(100 * time.Millisecond)
+				return 1, nil
+			}},
+			dataSource{Name: "second", Load: func(ctx context.Context) (any, error) {
+				time.Sleep(100 * time.Millisecond)
+				return "two", nil
+			}},
+		)
+
+		assert.Less(t, time.Since(start), 190*time.Millisecond, "Expected sources to be loaded concurrently")
+		require.Len(t, results, 2)
+
+		first, err := dataSourceValue[int](results, "first")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, first)
+
+		second, err := dataSourceValue[string](results, "second")
+		assert.NoError(t, err)
+		assert.Equal(t, "two", second)
+	})
+
+	t.Run("Failing source does not affect other sources", func(t *testing.T) {
+		results := loadDataSources(
+			context.Background(),
+			dataSource{Name: "failing", Load: func(ctx context.Context) (any, error) {
+				return nil, errors.New("source failed")
+			}},
+			dataSource{Name: "working", Load: func(ctx context.Context) (any, error) {
+				time.Sleep(50 * time.Millisecond)
+				return 42, nil
+			}},
+		)
+
+		_, err := dataSourceValue[int](results, "failing")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "source failed")
+
+		working, err := dataSourceValue[int](results, "working")
+		assert.NoError(t, err)
+		assert.Equal(t, 42, working)
+	})
+
+	t.Run("Slow source times out", func(t *testing.T) {
+		start := time.Now()
+		results := loadDataSources(
+			context.Background(),
+			dataSource{Name: "slow", Timeout: 50 * time.Millisecond, Load: func(ctx context.Context) (any, error) {
+				time.Sleep(time.Second)
+				return 1, nil
+			}},
+			dataSource{Name: "fast", Load: func(ctx context.Context) (any, error) {
+				return 2, nil
+			}},
+		)
+
+		assert.Less(t, time.Since(start), 500*time.Millisecond, "Expected slow source to be abandoned after its timeout")
+
+		_, err := dataSourceValue[int](results, "slow")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		fast, err := dataSourceValue[int](results, "fast")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, fast)
+	})
+
+	t.Run("Panicking source is reported as error", func(t *testing.T) {
+		results := loadDataSources(
+			context.Background(),
+			dataSource{Name: "panicking", Load: func(ctx context.Context) (any, error) {
+				panic("boom")
+			}},
+		)
+
+		_, err := dataSourceValue[int](results, "panicking")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "panicked")
+	})
+
+	t.Run("Unexpected type and missing source return errors", func(t *testing.T) {
+		results := loadDataSources(
+			context.Background(),
+			dataSource{Name: "number", Load: func(ctx context.Context) (any, error) {
+				return 1, nil
+			}},
+		)
+
+		_, err := dataSourceValue[string](results, "number")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unexpected type")
+
+		_, err = dataSourceValue[int](results, "missing")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "was not loaded")
+	})
+}
@@ -0,0 +1,261 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/siherrmann/queuerManager/helper"
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/labstack/echo/v5"
+)
+
+// DeprecationTracker counts the calls of the deprecated routes per caller and writes the counters periodically,
+// so requests do not wait for the database.
+type DeprecationTracker struct {
+	write  func(usages []*model.DeprecatedRouteUsage) error
+	mu     sync.Mutex
+	usages map[string]*model.DeprecatedRouteUsage
+}
+
+// NewDeprecationTracker creates a deprecation tracker writing the counted calls with write, eg. to the database.
+func NewDeprecationTracker(write func(usages []*model.DeprecatedRouteUsage) error) *DeprecationTracker {
+	return &DeprecationTracker{
+		write:  write,
+		usages: map[string]*model.DeprecatedRouteUsage{},
+	}
+}
+
+// Record counts a call of the deprecated route by the caller at the time.
+func (t *DeprecationTracker) Record(route string, caller string, userAgent string, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := route + "\n" + caller
+	usage, ok := t.usages[key]
+	if !ok {
+		usage = &model.DeprecatedRouteUsage{Route: route, Caller: caller, FirstCalledAt: at}
+		t.usages[key] = usage
+	}
+	usage.UserAgent = userAgent
+	usage.Calls++
+	usage.LastCalledAt = at
+}
+
+// Flush writes the counted calls, if the write fails they are counted again with the next flush.
+func (t *DeprecationTracker) Flush() {
+	t.mu.Lock()
+	pending := t.usages
+	t.usages = map[string]*model.DeprecatedRouteUsage{}
+	t.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	usages := make([]*model.DeprecatedRouteUsage, 0, len(pending))
+	for _, usage := range pending {
+		usages = append(usages, usage)
+	}
+	err := t.write(usages)
+	if err == nil {
+		return
+	}
+	log.Printf("Failed to write %d deprecated route usages: %v", len(usages), err)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key, usage := range pending {
+		if current, ok := t.usages[key]; ok {
+			usage.Calls += current.Calls
+			usage.UserAgent = current.UserAgent
+			usage.LastCalledAt = current.LastCalledAt
+		}
+		t.usages[key] = usage
+	}
+}
+
+// Run writes the counted calls every interval until the context is done, the remaining calls are written before it returns.
+func (t *DeprecationTracker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			t.Flush()
+			return
+		case <-ticker.C:
+			t.Flush()
+		}
+	}
+}
+
+// DeprecateRoute marks the route as deprecated, routes have to be deprecated before the server starts, eg. in the Init of an extension.
+// A route deprecated again gets the new deprecation.
+func (m *ManagerHandler) DeprecateRoute(deprecation *model.Deprecation) error {
+	err := deprecation.Validate()
+	if err != nil {
+		return err
+	}
+
+	if m.Deprecations == nil {
+		m.Deprecations = map[string]*model.Deprecation{}
+	}
+	m.Deprecations[deprecation.Route] = deprecation
+	return nil
+}
+
+// DeprecateRoutesFromEnv marks the routes of the JSON list of QUEUER_MANAGER_DEPRECATED_ROUTES as deprecated,
+// eg. [{"route": "POST /api/job/addJob/:taskKey", "since": "2026-10-01T00:00:00Z", "sunset": "2027-04-01T00:00:00Z", "successor": "/api/v1/jobs"}].
+func (m *ManagerHandler) DeprecateRoutesFromEnv() error {
+	deprecationsJSON := strings.TrimSpace(helper.GetEnvOrDefault("QUEUER_MANAGER_DEPRECATED_ROUTES", ""))
+	if deprecationsJSON == "" {
+		return nil
+	}
+
+	deprecations := []*model.Deprecation{}
+	err := json.Unmarshal([]byte(deprecationsJSON), &deprecations)
+	if err != nil {
+		return fmt.Errorf("error parsing QUEUER_MANAGER_DEPRECATED_ROUTES: %w", err)
+	}
+
+	for _, deprecation := range deprecations {
+		err := m.DeprecateRoute(deprecation)
+		if err != nil {
+			return fmt.Errorf("invalid QUEUER_MANAGER_DEPRECATED_ROUTES: %w", err)
+		}
+	}
+	return nil
+}
+
+// DeprecationMiddleware adds the Deprecation (RFC 9745), Sunset (RFC 8594) and successor Link headers to the responses
+// of the deprecated routes and counts their calls per caller. Requests of the web interface are not counted, as it is
+// no API client. After the sunset the route responds with 410 Gone.
+func (m *ManagerHandler) DeprecationMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c *echo.Context) error {
+		deprecation := m.Deprecations[c.Request().Method+" "+c.Path()]
+		if deprecation == nil {
+			return next(c)
+		}
+
+		now := time.Now()
+		header := c.Response().Header()
+		header.Set("Deprecation", "@"+strconv.FormatInt(deprecation.Since.Unix(), 10))
+		if deprecation.Sunset != nil {
+			header.Set("Sunset", deprecation.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if deprecation.Successor != "" {
+			header.Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, deprecation.Successor))
+		}
+
+		if m.DeprecationUsages != nil && c.Request().Header.Get("HX-Request") == "" {
+			m.DeprecationUsages.Record(deprecation.Route, requestedBy(c), c.Request().UserAgent(), now)
+		}
+
+		if deprecation.IsSunset(now) {
+			return renderPopupOrJson(c, http.StatusGone, deprecationMessage(deprecation))
+		}
+
+		return next(c)
+	}
+}
+
+// deprecationMessage describes the deprecation of the route with its sunset and successor.
+func deprecationMessage(deprecation *model.Deprecation) string {
+	message := fmt.Sprintf("%s is deprecated since %s", deprecation.Route, deprecation.Since.Format("2006-01-02"))
+	if deprecation.Sunset != nil {
+		message += fmt.Sprintf(" with sunset %s", deprecation.Sunset.Format("2006-01-02"))
+	}
+	if deprecation.Successor != "" {
+		message += ", use " + deprecation.Successor + " instead"
+	}
+	if deprecation.Message != "" {
+		message += ": " + deprecation.Message
+	}
+	return message
+}
+
+// deprecationReports returns the deprecated routes ordered by their route with the callers counted in the database.
+func (m *ManagerHandler) deprecationReports() ([]*model.DeprecationReport, error) {
+	callers := map[string][]*model.DeprecatedRouteUsage{}
+	if m.DeprecatedRouteUsageDB != nil {
+		usages, err := m.DeprecatedRouteUsageDB.SelectAllDeprecatedRouteUsages()
+		if err != nil {
+			return nil, err
+		}
+		for _, usage := range usages {
+			callers[usage.Route] = append(callers[usage.Route], usage)
+		}
+	}
+
+	reports := []*model.DeprecationReport{}
+	for route, deprecation := range m.Deprecations {
+		report := &model.DeprecationReport{Deprecation: deprecation, Callers: callers[route]}
+		if report.Callers == nil {
+			report.Callers = []*model.DeprecatedRouteUsage{}
+		}
+		for _, caller := range report.Callers {
+			report.Calls += caller.Calls
+		}
+		reports = append(reports, report)
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Deprecation.Route < reports[j].Deprecation.Route })
+
+	return reports, nil
+}
+
+// GetDeprecationReport returns the deprecated routes with the callers that still call them.
+func (m *ManagerHandler) GetDeprecationReport(c *echo.Context) error {
+	reports, err := m.deprecationReports()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to get deprecation report: %v", err)})
+	}
+
+	return c.JSON(http.StatusOK, reports)
+}
+
+// ResetDeprecationUsages deletes the counted callers of the deprecated route ?route=, eg. after the callers were notified.
+func (m *ManagerHandler) ResetDeprecationUsages(c *echo.Context) error {
+	if m.DeprecatedRouteUsageDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Deprecation usage counters are not enabled")
+	}
+
+	route := c.QueryParam("route")
+	if m.Deprecations[route] == nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Route %q is not deprecated", route))
+	}
+
+	deleted, err := m.DeprecatedRouteUsageDB.DeleteDeprecatedRouteUsages(route)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to reset deprecation usages: %v", err))
+	}
+
+	c.Response().Header().Add("HX-Trigger-After-Settle", "reloadDeprecations")
+
+	return renderPopupOrJson(c, http.StatusOK, fmt.Sprintf("Reset %d callers of %s", deleted, route))
+}
+
+// =======View Handlers=======
+
+// DeprecationsView renders the deprecated routes with the callers that still call them
+func (m *ManagerHandler) DeprecationsView(c *echo.Context) error {
+	reports, err := m.deprecationReports()
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get deprecation report: %v", err))
+	}
+
+	c.Response().Header().Add("HX-Push-Url", "/deprecations")
+	c.Response().Header().Add("HX-Retarget", "#body")
+
+	return render(c, screens.Deprecations(reports, time.Now()))
+}
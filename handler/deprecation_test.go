@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecateRoutesFromEnv(t *testing.T) {
+	t.Run("Valid deprecated routes", func(t *testing.T) {
+		t.Setenv("QUEUER_MANAGER_DEPRECATED_ROUTES", `[{"route": "POST /api/job/addJob/:taskKey", "since": "2026-10-01T00:00:00Z", "sunset": "2027-04-01T00:00:00Z", "successor": "/api/v1/jobs"}]`)
+		m := &ManagerHandler{}
+		err := m.DeprecateRoutesFromEnv()
+		require.NoError(t, err)
+		require.Contains(t, m.Deprecations, "POST /api/job/addJob/:taskKey")
+		assert.Equal(t, "/api/v1/jobs", m.Deprecations["POST /api/job/addJob/:taskKey"].Successor)
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		t.Setenv("QUEUER_MANAGER_DEPRECATED_ROUTES", `[{"route": `)
+		m := &ManagerHandler{}
+		err := m.DeprecateRoutesFromEnv()
+		assert.Error(t, err)
+	})
+
+	t.Run("Route without method", func(t *testing.T) {
+		t.Setenv("QUEUER_MANAGER_DEPRECATED_ROUTES", `[{"route": "/api/job/addJob/:taskKey", "since": "2026-10-01T00:00:00Z"}]`)
+		m := &ManagerHandler{}
+		err := m.DeprecateRoutesFromEnv()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "must be a method and a path")
+	})
+
+	t.Run("Sunset before deprecation", func(t *testing.T) {
+		t.Setenv("QUEUER_MANAGER_DEPRECATED_ROUTES", `[{"route": "GET /api/job/getJobs", "since": "2026-10-01T00:00:00Z", "sunset": "2026-09-01T00:00:00Z"}]`)
+		m := &ManagerHandler{}
+		err := m.DeprecateRoutesFromEnv()
+		assert.Error(t, err)
+	})
+}
+
+func TestDeprecationMiddleware(t *testing.T) {
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	passed := time.Now().Add(-time.Hour)
+
+	usages := []*model.DeprecatedRouteUsage{}
+	m := &ManagerHandler{
+		DeprecationUsages: NewDeprecationTracker(func(written []*model.DeprecatedRouteUsage) error {
+			usages = append(usages, written...)
+			return nil
+		}),
+	}
+	require.NoError(t, m.DeprecateRoute(&model.Deprecation{Route: "POST /api/job/addJob/:taskKey", Since: since, Sunset: &sunset, Successor: "/api/v1/jobs"}))
+	require.NoError(t, m.DeprecateRoute(&model.Deprecation{Route: "GET /api/job/getJobs", Since: since, Sunset: &passed}))
+
+	e := echo.New()
+	e.Use(m.DeprecationMiddleware)
+	ok := func(c *echo.Context) error { return c.String(http.StatusOK, "ok") }
+	e.POST("/api/job/addJob/:taskKey", ok)
+	e.GET("/api/job/getJobs", ok)
+	e.GET("/api/job/getJob", ok)
+
+	t.Run("Deprecated route gets the headers", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/job/addJob/mytask", nil)
+		req.Header.Set("User-Agent", "curl/8.0")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "@1790812800", rec.Header().Get("Deprecation"))
+		assert.Equal(t, sunset.Format(http.TimeFormat), rec.Header().Get("Sunset"))
+		assert.Equal(t, `</api/v1/jobs>; rel="successor-version"`, rec.Header().Get("Link"))
+	})
+
+	t.Run("Requests of the web interface are not counted", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/job/addJob/mytask", nil)
+		req.Header.Set("HX-Request", "true")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotEmpty(t, rec.Header().Get("Deprecation"))
+	})
+
+	t.Run("Route after the sunset is gone", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/job/getJobs", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusGone, rec.Code)
+		assert.Contains(t, rec.Body.String(), "deprecated since 2026-10-01")
+	})
+
+	t.Run("Route without deprecation", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/job/getJob", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("Deprecation"))
+	})
+
+	t.Run("Calls are counted per route", func(t *testing.T) {
+		m.DeprecationUsages.Flush()
+		require.Len(t, usages, 2)
+		calls := map[string]*model.DeprecatedRouteUsage{}
+		for _, usage := range usages {
+			calls[usage.Route] = usage
+		}
+		assert.Equal(t, int64(1), calls["POST /api/job/addJob/:taskKey"].Calls, "Expected the web interface call to not be counted")
+		assert.Equal(t, "curl/8.0", calls["POST /api/job/addJob/:taskKey"].UserAgent)
+		assert.Equal(t, int64(1), calls["GET /api/job/getJobs"].Calls, "Expected calls after the sunset to be counted")
+	})
+}
+
+func TestDeprecationTrackerFlush(t *testing.T) {
+	fail := true
+	written := []*model.DeprecatedRouteUsage{}
+	tracker := NewDeprecationTracker(func(usages []*model.DeprecatedRouteUsage) error {
+		if fail {
+			return errors.New("database unavailable")
+		}
+		written = append(written, usages...)
+		return nil
+	})
+
+	start := time.Now()
+	tracker.Record("GET /api/job/getJobs", "alice", "curl/8.0", start)
+	tracker.Flush()
+	assert.Empty(t, written)
+
+	tracker.Record("GET /api/job/getJobs", "alice", "curl/8.1", start.Add(time.Minute))
+	fail = false
+	tracker.Flush()
+
+	require.Len(t, written, 1, "Expected the failed usages to be merged with the new calls")
+	assert.Equal(t, int64(2), written[0].Calls)
+	assert.Equal(t, "curl/8.1", written[0].UserAgent)
+	assert.True(t, written[0].FirstCalledAt.Equal(start))
+	assert.True(t, written[0].LastCalledAt.Equal(start.Add(time.Minute)))
+
+	tracker.Flush()
+	assert.Len(t, written, 1, "Expected nothing to be written without calls")
+}
+
+func TestDeprecationHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	drudb, err := database.NewDeprecatedRouteUsageDBHandler(db, true)
+	require.NoError(t, err)
+
+	route := "POST /api/job/addJob/:taskKey"
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.DeprecatedRouteUsageDB = drudb
+	require.NoError(t, handler.DeprecateRoute(&model.Deprecation{Route: route, Since: time.Now().Add(-time.Hour), Successor: "/api/v1/jobs"}))
+	e := echo.New()
+
+	now := time.Now()
+	err = drudb.AddDeprecatedRouteUsages([]*model.DeprecatedRouteUsage{
+		{Route: route, Caller: "apikey:deploy", UserAgent: "curl/8.0", Calls: 3, FirstCalledAt: now, LastCalledAt: now},
+		{Route: route, Caller: "alice", UserAgent: "queuerctl", Calls: 2, FirstCalledAt: now, LastCalledAt: now},
+	})
+	require.NoError(t, err)
+
+	t.Run("GetDeprecationReport", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/deprecation/getReport", nil)
+		rec := httptest.NewRecorder()
+		err := handler.GetDeprecationReport(e.NewContext(req, rec))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		reports := []*model.DeprecationReport{}
+		err = json.Unmarshal(rec.Body.Bytes(), &reports)
+		require.NoError(t, err)
+		require.Len(t, reports, 1)
+		assert.Equal(t, route, reports[0].Deprecation.Route)
+		assert.Equal(t, int64(5), reports[0].Calls)
+		assert.Len(t, reports[0].Callers, 2)
+	})
+
+	t.Run("ResetDeprecationUsages of a route that is not deprecated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/deprecation/resetUsages?route="+url.QueryEscape("GET /api/job/getJobs"), nil)
+		rec := httptest.NewRecorder()
+		err := handler.ResetDeprecationUsages(e.NewContext(req, rec))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("ResetDeprecationUsages", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/deprecation/resetUsages?route="+url.QueryEscape(route), nil)
+		rec := httptest.NewRecorder()
+		err := handler.ResetDeprecationUsages(e.NewContext(req, rec))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		usages, err := drudb.SelectAllDeprecatedRouteUsages()
+		require.NoError(t, err)
+		assert.Empty(t, usages)
+	})
+}
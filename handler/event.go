@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	qmodel "github.com/siherrmann/queuer/model"
+	"github.com/siherrmann/queuerManager/model"
+)
+
+// publishEvent publishes a lifecycle event in the background if an event publisher is configured.
+// Publish errors are only logged so they never block the request.
+func (m *ManagerHandler) publishEvent(eventType string, data any) {
+	if m.EventPublisher == nil {
+		return
+	}
+
+	event := model.NewEvent(eventType, data)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := m.EventPublisher.Publish(ctx, event)
+		if err != nil {
+			log.Printf("Error publishing event %s: %v", event.Type, err)
+		}
+	}()
+}
+
+// PublishJobEnded publishes the ended job with its final status, ended jobs are moved to the archive.
+func (m *ManagerHandler) PublishJobEnded(job *qmodel.Job) {
+	if job == nil {
+		return
+	}
+	m.publishEvent(model.EVENT_JOB_ENDED, job)
+}
+
+// PublishRegisteredWorkers publishes the workers created after since and until including until
+// and returns the number of published workers.
+func (m *ManagerHandler) PublishRegisteredWorkers(since time.Time, until time.Time) (int, error) {
+	if m.EventPublisher == nil {
+		return 0, nil
+	}
+
+	published := 0
+	lastID := 0
+	for {
+		workers, err := m.Queuer.GetWorkers(lastID, 100)
+		if err != nil {
+			return published, fmt.Errorf("error selecting workers: %w", err)
+		}
+		if len(workers) == 0 {
+			return published, nil
+		}
+		lastID = workers[len(workers)-1].ID
+
+		for _, worker := range workers {
+			if !worker.CreatedAt.After(since) || worker.CreatedAt.After(until) {
+				continue
+			}
+			m.publishEvent(model.EVENT_WORKER_REGISTERED, worker)
+			published++
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuer/model"
+	"github.com/siherrmann/queuerManager/database"
+	"github.com/siherrmann/queuerManager/event"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []*qmModel.Event
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, e *qmModel.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, e)
+	return nil
+}
+
+func (p *recordingPublisher) Close() error {
+	return nil
+}
+
+func (p *recordingPublisher) eventTypes() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	eventTypes := []string{}
+	for _, e := range p.events {
+		eventTypes = append(eventTypes, e.Type)
+	}
+	return eventTypes
+}
+
+func TestPublishEventHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	e := echo.New()
+
+	t.Run("AddTask publishes task added event", func(t *testing.T) {
+		publisher := &recordingPublisher{}
+		handler := NewManagerHandler(fs, tdb, queue)
+		handler.EventPublisher = publisher
+
+		formData := strings.NewReader("key=test-event-task&name=Test+Event+Task&description=Test")
+
+		req := httptest.NewRequest(http.MethodPost, "/api/task/addTask", formData)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.AddTask(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		assert.Eventually(t, func() bool {
+			eventTypes := publisher.eventTypes()
+			return len(eventTypes) == 1 && eventTypes[0] == qmModel.EVENT_TASK_ADDED
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("PublishJobEnded publishes the final status of the job", func(t *testing.T) {
+		publisher := &recordingPublisher{}
+		handler := NewManagerHandler(fs, tdb, queue)
+		handler.EventPublisher = publisher
+
+		job := &model.Job{RID: uuid.New(), TaskName: "test-task", Status: model.JobStatusFailed, Error: "test error"}
+		handler.PublishJobEnded(job)
+
+		assert.Eventually(t, func() bool {
+			eventTypes := publisher.eventTypes()
+			return len(eventTypes) == 1 && eventTypes[0] == qmModel.EVENT_JOB_ENDED
+		}, time.Second, 10*time.Millisecond)
+
+		publisher.mu.Lock()
+		defer publisher.mu.Unlock()
+		publishedJob, ok := publisher.events[0].Data.(*model.Job)
+		require.True(t, ok, "Expected the ended job as event data")
+		assert.Equal(t, model.JobStatusFailed, publishedJob.Status)
+	})
+
+	t.Run("PublishRegisteredWorkers publishes the workers created in the window", func(t *testing.T) {
+		publisher := &recordingPublisher{}
+		handler := NewManagerHandler(fs, tdb, queue)
+		handler.EventPublisher = publisher
+
+		published, err := handler.PublishRegisteredWorkers(time.Now().Add(-time.Hour), time.Now())
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, published, 1, "Expected the worker of the test queuer to be published")
+
+		published, err = handler.PublishRegisteredWorkers(time.Now(), time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 0, published, "Expected no workers created after the window start")
+
+		assert.Eventually(t, func() bool {
+			eventTypes := publisher.eventTypes()
+			return len(eventTypes) >= 1 && eventTypes[0] == qmModel.EVENT_WORKER_REGISTERED
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Events are produced to the kafka topic", func(t *testing.T) {
+		var mu sync.Mutex
+		var receivedPath, receivedContentType string
+		var receivedBody map[string][]map[string]json.RawMessage
+		restProxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			mu.Lock()
+			defer mu.Unlock()
+			receivedPath = r.URL.Path
+			receivedContentType = r.Header.Get("Content-Type")
+			err = json.Unmarshal(body, &receivedBody)
+			require.NoError(t, err)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer restProxy.Close()
+
+		handler := NewManagerHandler(fs, tdb, queue)
+		handler.EventPublisher = event.NewPublisherKafka(event.KafkaConfig{RestProxyURL: restProxy.URL, Topic: "queuer-events"})
+		handler.publishEvent(qmModel.EVENT_WORKER_STOPPED, map[string]any{"rid": "test"})
+
+		assert.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return receivedPath != ""
+		}, time.Second, 10*time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, "/topics/queuer-events", receivedPath)
+		assert.Equal(t, "application/vnd.kafka.json.v2+json", receivedContentType)
+		require.Len(t, receivedBody["records"], 1)
+		assert.JSONEq(t, `"worker.stopped"`, string(receivedBody["records"][0]["key"]))
+
+		var publishedEvent qmModel.Event
+		err := json.Unmarshal(receivedBody["records"][0]["value"], &publishedEvent)
+		require.NoError(t, err)
+		assert.Equal(t, qmModel.EVENT_WORKER_STOPPED, publishedEvent.Type)
+	})
+}
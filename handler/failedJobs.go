@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/labstack/echo/v5"
+)
+
+// failureGroupLimit is the max number of failure groups of the failed jobs view and API
+const failureGroupLimit = 100
+
+// failureGroups retrieves the failure groups of the failed archived jobs of the task (of all tasks if taskKey is empty)
+// in the namespace (of all namespaces if namespace is empty) that ended in the window.
+func (m *ManagerHandler) failureGroups(taskKey string, namespace string, window model.DashboardWindow) ([]*model.FailureGroup, error) {
+	to := time.Now()
+	from := to.Add(-window.Duration)
+
+	groups, err := m.JobArchiveDB.SelectFailureGroups(taskKey, namespace, from, to, failureGroupLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve failure groups: %v", err)
+	}
+	return groups, nil
+}
+
+// =======API Handlers=======
+
+// GetFailedJobGroups returns the failed archived jobs grouped by task and error message with their count
+// and first and last occurrence, the most frequent failures first, eg. /api/failedJobs/getGroups?window=7d&task=task_key.
+// window is 1h, 6h, 24h (default), 7d or 30d.
+func (m *ManagerHandler) GetFailedJobGroups(c *echo.Context) error {
+	if m.JobArchiveDB == nil {
+		return c.String(http.StatusServiceUnavailable, "Failed jobs are not available without the job archive filters")
+	}
+
+	window, err := dashboardWindow(c.QueryParam("window"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	groups, err := m.failureGroups(c.QueryParam("task"), requestNamespace(c), window)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to retrieve failed jobs")
+	}
+
+	return c.JSON(http.StatusOK, groups)
+}
+
+// =======View Handlers=======
+
+// FailedJobsView renders the failed archived jobs grouped by task and error message, eg. /failedJobs?window=7d&task=task_key
+func (m *ManagerHandler) FailedJobsView(c *echo.Context) error {
+	if m.JobArchiveDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Failed jobs are not available without the job archive filters")
+	}
+
+	window, err := dashboardWindow(c.QueryParam("window"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+
+	taskKey := c.QueryParam("task")
+	groups, err := m.failureGroups(taskKey, requestNamespace(c), window)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, err.Error())
+	}
+
+	c.Response().Header().Add("HX-Push-Url", fmt.Sprintf("/failedJobs?window=%s&task=%s", window.Name, url.QueryEscape(taskKey)))
+	c.Response().Header().Add("HX-Retarget", "#body")
+
+	return render(c, screens.FailedJobs(groups, window.Name, taskKey))
+}
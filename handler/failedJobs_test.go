@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailedJobsHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("failedjobsdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	jobArchiveDB, err := database.NewJobArchiveDBHandler(db)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.JobArchiveDB = jobArchiveDB
+	e := echo.New()
+
+	job, err := queue.AddJob("test-task-failing", nil, 1)
+	require.NoError(t, err)
+	queue.WaitForJobFinished(job.RID, 5*time.Second)
+
+	t.Run("GetFailedJobGroups groups the failed jobs of a task", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/failedJobs/getGroups?task=test-task-failing", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetFailedJobGroups(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var groups []*model.FailureGroup
+		err = json.Unmarshal(rec.Body.Bytes(), &groups)
+		require.NoError(t, err)
+		require.NotEmpty(t, groups, "Expected the failed job in a failure group")
+		for _, group := range groups {
+			assert.Equal(t, "test-task-failing", group.TaskKey)
+			assert.Positive(t, group.Count)
+		}
+	})
+
+	t.Run("GetFailedJobGroups with invalid window", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/failedJobs/getGroups?window=2h", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetFailedJobGroups(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("GetFailedJobGroups without job archive filters", func(t *testing.T) {
+		handlerWithoutArchive := NewManagerHandler(fs, tdb, queue)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/failedJobs/getGroups", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handlerWithoutArchive.GetFailedJobGroups(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+
+	t.Run("FailedJobsView renders the failure groups", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/failedJobs?window=7d&task=test-task-failing", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.FailedJobsView(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "test-task-failing")
+		assert.Contains(t, rec.Body.String(), "/jobArchive?status=FAILED&amp;task=test-task-failing&amp;error=")
+		assert.Equal(t, "/failedJobs?window=7d&task=test-task-failing", rec.Header().Get("HX-Push-Url"))
+	})
+}
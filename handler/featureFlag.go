@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/labstack/echo/v5"
+)
+
+// featureFlagCacheTTL is how long the feature flags are cached, other instances pick up changes after it.
+const featureFlagCacheTTL = 30 * time.Second
+
+// featureFlagCache caches the enabled state of the feature flags, so gating a feature does not query the database.
+type featureFlagCache struct {
+	mu       sync.Mutex
+	enabled  map[string]bool
+	loadedAt time.Time
+}
+
+// =======API Handlers=======
+
+// GetFeatureFlags retrieves all feature flags.
+func (m *ManagerHandler) GetFeatureFlags(c *echo.Context) error {
+	if m.FeatureFlagDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Feature flags are not enabled")
+	}
+
+	featureFlags, err := m.FeatureFlagDB.SelectAllFeatureFlags()
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get feature flags: %v", err))
+	}
+
+	return renderPopupOrJson(c, http.StatusOK, featureFlags)
+}
+
+// UpdateFeatureFlags enables or disables the feature flags with the keys of the query, eg. `?enabled=true&key=a&key=b`.
+func (m *ManagerHandler) UpdateFeatureFlags(c *echo.Context) error {
+	if m.FeatureFlagDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Feature flags are not enabled")
+	}
+
+	keys := c.QueryParams()["key"]
+	if len(keys) == 0 {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Missing feature flag key")
+	}
+	enabled, err := strconv.ParseBool(c.QueryParam("enabled"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid enabled value (must be true or false)")
+	}
+
+	updatedCount := 0
+	var errors []string
+	for _, key := range keys {
+		_, err := m.FeatureFlagDB.UpdateFeatureFlagEnabled(key, enabled, requestedBy(c))
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Failed to update feature flag %s: %v", key, err))
+			continue
+		}
+		updatedCount++
+	}
+	m.invalidateFeatureFlags()
+
+	status := http.StatusOK
+	message := fmt.Sprintf("Successfully updated %d feature flag(s)", updatedCount)
+	if len(errors) > 0 {
+		status = http.StatusPartialContent
+		message = fmt.Sprintf("Updated %d feature flags. Errors: %v", updatedCount, errors)
+	}
+
+	if c.Request().Header.Get("HX-Request") != "" {
+		featureFlags, err := m.FeatureFlagDB.SelectAllFeatureFlags()
+		if err != nil {
+			return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get feature flags: %v", err))
+		}
+		return renderRowsWithPopup(c, status, screens.FeatureFlagRows(featureFlags), "#table_body_feature_flags_table", "innerHTML", "closeUpdateFeatureFlag", message)
+	}
+
+	return renderPopupOrJson(c, status, message)
+}
+
+// =======View Handlers=======
+
+// FeatureFlagsView renders the feature flag list view
+func (m *ManagerHandler) FeatureFlagsView(c *echo.Context) error {
+	if m.FeatureFlagDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Feature flags are not enabled")
+	}
+
+	featureFlags, err := m.FeatureFlagDB.SelectAllFeatureFlags()
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get feature flags: %v", err))
+	}
+
+	c.Response().Header().Add("HX-Push-Url", "/featureFlags")
+	c.Response().Header().Add("HX-Retarget", "#body")
+
+	return render(c, screens.FeatureFlags(featureFlags))
+}
+
+// UpdateFeatureFlagPopupView renders the popup to enable or disable feature flags
+func (m *ManagerHandler) UpdateFeatureFlagPopupView(c *echo.Context) error {
+	keys := c.QueryParams()["key"]
+	if len(keys) == 0 {
+		return renderPopupOrJson(c, http.StatusBadRequest, "No feature flag keys provided")
+	}
+	enabled, err := strconv.ParseBool(c.QueryParam("enabled"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid enabled value (must be true or false)")
+	}
+
+	return renderPopup(c, screens.UpdateFeatureFlagPopup(keys, enabled))
+}
+
+// =======Helpers=======
+
+// InitFeatureFlags creates the default feature flags that do not exist yet.
+// The comma separated enabledKeys are enabled on creation, existing flags keep their state.
+func (m *ManagerHandler) InitFeatureFlags(enabledKeys string) error {
+	if m.FeatureFlagDB == nil {
+		return nil
+	}
+
+	enabled := strings.Split(enabledKeys, ",")
+	for i := range enabled {
+		enabled[i] = strings.TrimSpace(enabled[i])
+	}
+
+	for _, defaultFeatureFlag := range model.DefaultFeatureFlags {
+		featureFlag := *defaultFeatureFlag
+		featureFlag.Enabled = slices.Contains(enabled, featureFlag.Key)
+		_, err := m.FeatureFlagDB.InsertFeatureFlag(&featureFlag)
+		if err != nil {
+			return fmt.Errorf("error creating feature flag %s: %w", featureFlag.Key, err)
+		}
+	}
+	m.invalidateFeatureFlags()
+
+	return nil
+}
+
+// FeatureEnabled reports if the feature flag with the key is enabled.
+// The flags are cached for featureFlagCacheTTL, if they can not be reloaded the cached state is used.
+// Unknown flags and flags without database are disabled.
+func (m *ManagerHandler) FeatureEnabled(key string) bool {
+	if m.FeatureFlagDB == nil || m.featureFlags == nil {
+		return false
+	}
+
+	m.featureFlags.mu.Lock()
+	defer m.featureFlags.mu.Unlock()
+
+	if time.Since(m.featureFlags.loadedAt) > featureFlagCacheTTL {
+		featureFlags, err := m.FeatureFlagDB.SelectAllFeatureFlags()
+		if err != nil {
+			log.Printf("Failed to load feature flags: %v", err)
+		} else {
+			m.featureFlags.enabled = map[string]bool{}
+			for _, featureFlag := range featureFlags {
+				m.featureFlags.enabled[featureFlag.Key] = featureFlag.Enabled
+			}
+		}
+		m.featureFlags.loadedAt = time.Now()
+	}
+
+	return m.featureFlags.enabled[key]
+}
+
+// RequireFeature restricts a route to deployments with the feature flag enabled, otherwise the route is not found.
+func (m *ManagerHandler) RequireFeature(key string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			if !m.FeatureEnabled(key) {
+				return renderPopupOrJson(c, http.StatusNotFound, fmt.Sprintf("Feature %s is not enabled", key))
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// invalidateFeatureFlags reloads the feature flags on the next check, eg. after they were changed.
+func (m *ManagerHandler) invalidateFeatureFlags() {
+	if m.featureFlags == nil {
+		return
+	}
+
+	m.featureFlags.mu.Lock()
+	defer m.featureFlags.mu.Unlock()
+
+	m.featureFlags.loadedAt = time.Time{}
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureFlagHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	ffdb, err := database.NewFeatureFlagDBHandler(db, true)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.FeatureFlagDB = ffdb
+	e := echo.New()
+
+	err = handler.InitFeatureFlags(qmModel.FEATURE_FLAG_SSE_UPDATES)
+	require.NoError(t, err)
+
+	gatedRoute := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.RequireFeature(qmModel.FEATURE_FLAG_NEW_DASHBOARD)(func(c *echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})(c)
+		require.NoError(t, err)
+		return rec.Code
+	}
+
+	t.Run("Should create the default feature flags", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/featureFlag/getFeatureFlags", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetFeatureFlags(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var featureFlags []*qmModel.FeatureFlag
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &featureFlags))
+		assert.Len(t, featureFlags, len(qmModel.DefaultFeatureFlags))
+
+		assert.True(t, handler.FeatureEnabled(qmModel.FEATURE_FLAG_SSE_UPDATES))
+		assert.False(t, handler.FeatureEnabled(qmModel.FEATURE_FLAG_NEW_DASHBOARD))
+		assert.False(t, handler.FeatureEnabled("unknown"))
+		assert.Equal(t, http.StatusNotFound, gatedRoute())
+	})
+
+	t.Run("Should enable feature flags and invalidate the cache", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/featureFlag/updateFeatureFlags?enabled=true&key="+qmModel.FEATURE_FLAG_NEW_DASHBOARD, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.UpdateFeatureFlags(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		assert.True(t, handler.FeatureEnabled(qmModel.FEATURE_FLAG_NEW_DASHBOARD))
+		assert.Equal(t, http.StatusOK, gatedRoute())
+	})
+
+	t.Run("Should keep the state of existing feature flags on init", func(t *testing.T) {
+		err := handler.InitFeatureFlags("")
+		require.NoError(t, err)
+
+		assert.True(t, handler.FeatureEnabled(qmModel.FEATURE_FLAG_NEW_DASHBOARD))
+		assert.True(t, handler.FeatureEnabled(qmModel.FEATURE_FLAG_SSE_UPDATES))
+	})
+
+	t.Run("Should report unknown feature flags", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/featureFlag/updateFeatureFlags?enabled=false&key=unknown", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.UpdateFeatureFlags(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusPartialContent, rec.Code)
+	})
+
+	t.Run("Should reject an invalid enabled value", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/featureFlag/updateFeatureFlags?enabled=maybe&key="+qmModel.FEATURE_FLAG_SSE_UPDATES, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.UpdateFeatureFlags(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
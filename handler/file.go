@@ -2,16 +2,38 @@ package handler
 
 import (
 	"fmt"
+	"log"
+	"mime/multipart"
 	"net/http"
 	"path/filepath"
 	"strings"
 
+	qmModel "github.com/siherrmann/queuerManager/model"
 	"github.com/siherrmann/queuerManager/upload"
 	"github.com/siherrmann/queuerManager/view/screens"
 
 	"github.com/labstack/echo/v5"
+	vm "github.com/siherrmann/validator/model"
 )
 
+// GetFiles lists the files of the filesystem, with `search` only the files with the search in their name.
+func (m *ManagerHandler) GetFiles(c *echo.Context) error {
+	files, err := m.Filesystem.ListFiles()
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to list files: %v", err))
+	}
+
+	search := c.QueryParam("search")
+	filteredFiles := []upload.File{}
+	for _, file := range files {
+		if strings.Contains(file.Name, search) {
+			filteredFiles = append(filteredFiles, file)
+		}
+	}
+
+	return c.JSON(http.StatusOK, filteredFiles)
+}
+
 func (m *ManagerHandler) UploadFiles(c *echo.Context) error {
 	// Parse multipart form with 32MB max memory
 	err := c.Request().ParseMultipartForm(32 << 20)
@@ -42,6 +64,7 @@ func (m *ManagerHandler) UploadFiles(c *echo.Context) error {
 			return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to save file %s: %v", filename, err))
 		}
 
+		m.recordFileOwner(filename, requestedBy(c), fileHeader.Size)
 		uploadedFiles = append(uploadedFiles, filename)
 	}
 
@@ -57,6 +80,7 @@ func (m *ManagerHandler) DeleteFile(c *echo.Context) error {
 	if err != nil {
 		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to delete file %s: %v", filename, err))
 	}
+	m.deleteFileOwner(filename)
 
 	c.Response().Header().Add("HX-Trigger-After-Settle", "reloadFiles")
 
@@ -78,6 +102,7 @@ func (m *ManagerHandler) DeleteFiles(c *echo.Context) error {
 		if err != nil {
 			errors = append(errors, fmt.Sprintf("%s: %v", name, err))
 		} else {
+			m.deleteFileOwner(name)
 			deletedFiles = append(deletedFiles, name)
 		}
 	}
@@ -162,3 +187,126 @@ func (m *ManagerHandler) DeleteFilePopupView(c *echo.Context) error {
 
 	return renderPopup(c, screens.DeleteFilePopup(names))
 }
+
+// prepareParameterFiles parses the files uploaded with the file parameters of the task in a multipart job form
+// and replaces the values of the parameters with the names of the uploaded files, so the file parameters are validated
+// with their names before the files are stored with storeParameterFiles. Without an uploaded file the selected file name is kept.
+// The temporary files of the form are removed by the caller with request.MultipartForm.RemoveAll.
+func (m *ManagerHandler) prepareParameterFiles(task *qmModel.Task, request *http.Request) error {
+	if !strings.HasPrefix(request.Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		return nil
+	}
+
+	// Parse multipart form with 32MB max memory
+	err := request.ParseMultipartForm(32 << 20)
+	if err != nil {
+		return fmt.Errorf("failed to parse multipart form: %w", err)
+	}
+
+	for _, validation := range fileValidations(task) {
+		// The empty file input is sent as empty value next to the selected file name
+		filename := ""
+		for _, value := range request.Form[validation.Key] {
+			if value != "" {
+				filename = value
+				break
+			}
+		}
+		if fileHeader := uploadedParameterFile(request, validation.Key); fileHeader != nil {
+			filename = filepath.Base(fileHeader.Filename)
+		}
+
+		if filename == "" {
+			delete(request.Form, validation.Key)
+		} else {
+			request.Form.Set(validation.Key, filename)
+		}
+	}
+
+	return nil
+}
+
+// storeParameterFiles stores the files uploaded with the file parameters of the task prepared with prepareParameterFiles.
+// The stored files belong to the principal for the storage quota. It returns the names of the files that did not exist before,
+// so they are deleted with deleteParameterFiles if the job is not added, files of the same name of other jobs are kept.
+func (m *ManagerHandler) storeParameterFiles(task *qmModel.Task, request *http.Request, principal string) ([]string, error) {
+	if request.MultipartForm == nil {
+		return nil, nil
+	}
+
+	storedFiles := []string{}
+	for _, validation := range fileValidations(task) {
+		fileHeader := uploadedParameterFile(request, validation.Key)
+		if fileHeader == nil {
+			continue
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			m.deleteParameterFiles(storedFiles)
+			return nil, fmt.Errorf("failed to open file %s: %w", fileHeader.Filename, err)
+		}
+		filename := filepath.Base(fileHeader.Filename)
+		_, statErr := m.Filesystem.Stat(filename)
+		err = m.Filesystem.Write(filename, file, fileHeader.Size)
+		file.Close()
+		if err != nil {
+			m.deleteParameterFiles(storedFiles)
+			return nil, fmt.Errorf("failed to save file %s: %w", filename, err)
+		}
+		m.recordFileOwner(filename, principal, fileHeader.Size)
+		if statErr != nil {
+			storedFiles = append(storedFiles, filename)
+		}
+	}
+
+	return storedFiles, nil
+}
+
+// deleteParameterFiles deletes the files stored with storeParameterFiles for a job that was not added.
+func (m *ManagerHandler) deleteParameterFiles(filenames []string) {
+	for _, filename := range filenames {
+		err := m.Filesystem.Remove(filename)
+		if err != nil {
+			log.Printf("Error deleting file %s of a job that was not added: %v", filename, err)
+			continue
+		}
+		m.deleteFileOwner(filename)
+	}
+}
+
+// uploadedParameterFile returns the file uploaded with the file parameter in a multipart job form, nil without upload.
+func uploadedParameterFile(request *http.Request, key string) *multipart.FileHeader {
+	if request.MultipartForm == nil {
+		return nil
+	}
+	for _, fileHeader := range request.MultipartForm.File[key] {
+		if fileHeader.Filename != "" {
+			return fileHeader
+		}
+	}
+	return nil
+}
+
+// fileValidations returns the validations of the file parameters of the task.
+func fileValidations(task *qmModel.Task) []vm.Validation {
+	validations := []vm.Validation{}
+	for _, validation := range append(append([]vm.Validation{}, task.InputParameters...), task.InputParametersKeyed...) {
+		if string(validation.Type) == qmModel.VALIDATION_TYPE_FILE {
+			validations = append(validations, validation)
+		}
+	}
+	return validations
+}
+
+// validateFile checks that the value of a file parameter is the name of a file of the filesystem.
+func (m *ManagerHandler) validateFile(value any) error {
+	filename, ok := value.(string)
+	if !ok || filename == "" {
+		return fmt.Errorf("must be the name of a file")
+	}
+	if _, err := m.Filesystem.Stat(filename); err != nil {
+		return fmt.Errorf("file %s does not exist", filename)
+	}
+	return nil
+}
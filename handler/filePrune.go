@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+)
+
+// recordJobFiles associates the files of the file parameters of the task to the added job,
+// so the files are pruned once the job is deleted or expired from the archive.
+func (m *ManagerHandler) recordJobFiles(task *qmModel.Task, job *model.Job) {
+	if m.JobFileDB == nil || job == nil {
+		return
+	}
+
+	filenames := []string{}
+	for i, validation := range task.InputParameters {
+		if string(validation.Type) != qmModel.VALIDATION_TYPE_FILE || i >= len(job.Parameters) {
+			continue
+		}
+		if filename, ok := job.Parameters[i].(string); ok && filename != "" {
+			filenames = append(filenames, filename)
+		}
+	}
+	for _, validation := range task.InputParametersKeyed {
+		if string(validation.Type) != qmModel.VALIDATION_TYPE_FILE {
+			continue
+		}
+		if filename, ok := job.ParametersKeyed[validation.Key].(string); ok && filename != "" {
+			filenames = append(filenames, filename)
+		}
+	}
+
+	err := m.JobFileDB.InsertJobFiles(job.RID, task.Namespace, filenames)
+	if err != nil {
+		log.Printf("Error recording files of job %s: %v", job.RID, err)
+	}
+}
+
+// PruneOrphanedFiles moves the files of which all jobs are deleted or expired from the archive to the trash
+// and deletes the files trashed longer than the trash retention. Trashed files a new job references again are restored.
+// Files missing in the filesystem are forgotten without trash. It returns the report of the trashed and reclaimed space.
+func (m *ManagerHandler) PruneOrphanedFiles() (*qmModel.FilePruneReport, error) {
+	report := &qmModel.FilePruneReport{TrashBytesByNamespace: map[string]int64{}}
+	if m.JobFileDB == nil {
+		return report, nil
+	}
+
+	restored, err := m.JobFileDB.DeleteReferencedTrashedFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore referenced files: %w", err)
+	}
+	report.RestoredFiles = restored
+
+	orphanedFiles, err := m.JobFileDB.SelectOrphanedFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to select orphaned files: %w", err)
+	}
+	trashedFiles := []*qmModel.TrashedFile{}
+	missingFiles := []string{}
+	for _, orphanedFile := range orphanedFiles {
+		fileInfo, err := m.Filesystem.Stat(orphanedFile.Filename)
+		if err != nil {
+			missingFiles = append(missingFiles, orphanedFile.Filename)
+			continue
+		}
+		trashedFiles = append(trashedFiles, &qmModel.TrashedFile{
+			Filename:  orphanedFile.Filename,
+			Namespace: orphanedFile.Namespace,
+			SizeBytes: fileInfo.Size(),
+		})
+		report.TrashedFiles++
+		report.TrashedBytes += fileInfo.Size()
+	}
+	err = m.JobFileDB.InsertTrashedFiles(trashedFiles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to trash orphaned files: %w", err)
+	}
+	err = m.JobFileDB.DeleteFiles(missingFiles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to forget missing files: %w", err)
+	}
+
+	trash, err := m.JobFileDB.SelectTrashedFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to select trashed files: %w", err)
+	}
+	purgeBefore := time.Now().AddDate(0, 0, -m.fileTrashRetentionDays())
+	purgedFiles := []string{}
+	for _, trashedFile := range trash {
+		if trashedFile.TrashedAt.After(purgeBefore) {
+			report.TrashFiles++
+			report.TrashBytes += trashedFile.SizeBytes
+			report.TrashBytesByNamespace[trashedFile.Namespace] += trashedFile.SizeBytes
+			continue
+		}
+
+		err := m.Filesystem.Remove(trashedFile.Filename)
+		if err != nil {
+			if _, statErr := m.Filesystem.Stat(trashedFile.Filename); statErr == nil {
+				log.Printf("Error deleting trashed file %s: %v", trashedFile.Filename, err)
+				continue
+			}
+		}
+		m.deleteFileOwner(trashedFile.Filename)
+		purgedFiles = append(purgedFiles, trashedFile.Filename)
+		report.PurgedFiles++
+		report.ReclaimedBytes += trashedFile.SizeBytes
+	}
+	err = m.JobFileDB.DeleteFiles(purgedFiles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete purged files: %w", err)
+	}
+
+	return report, nil
+}
+
+// fileTrashRetentionDays returns the number of days the orphaned files stay in the trash, the default without configuration.
+func (m *ManagerHandler) fileTrashRetentionDays() int {
+	if m.FileTrashRetentionDays <= 0 {
+		return qmModel.DEFAULT_FILE_TRASH_RETENTION_DAYS
+	}
+	return m.FileTrashRetentionDays
+}
+
+// =======API Handlers=======
+
+// GetFileTrash lists the orphaned files queued for deletion with the time they are deleted after.
+func (m *ManagerHandler) GetFileTrash(c *echo.Context) error {
+	if m.JobFileDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "File pruning is not enabled")
+	}
+
+	trash, err := m.JobFileDB.SelectTrashedFiles()
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get trashed files: %v", err))
+	}
+
+	for _, trashedFile := range trash {
+		trashedFile.DeleteAfter = trashedFile.TrashedAt.AddDate(0, 0, m.fileTrashRetentionDays())
+	}
+
+	return c.JSON(http.StatusOK, trash)
+}
+
+// PruneFiles runs the pruning of the orphaned files at once and returns its report.
+func (m *ManagerHandler) PruneFiles(c *echo.Context) error {
+	if m.JobFileDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "File pruning is not enabled")
+	}
+
+	report, err := m.PruneOrphanedFiles()
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to prune files: %v", err))
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
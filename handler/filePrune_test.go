@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneOrphanedFiles(t *testing.T) {
+	t.Run("Should report nothing without job file database", func(t *testing.T) {
+		m := &ManagerHandler{}
+
+		report, err := m.PruneOrphanedFiles()
+		require.NoError(t, err, "Expected PruneOrphanedFiles to not return an error")
+		assert.Zero(t, report.TrashedFiles)
+		assert.Zero(t, report.PurgedFiles)
+		assert.Empty(t, report.TrashBytesByNamespace)
+	})
+
+	t.Run("Should default the trash retention", func(t *testing.T) {
+		m := &ManagerHandler{}
+		assert.Equal(t, model.DEFAULT_FILE_TRASH_RETENTION_DAYS, m.fileTrashRetentionDays())
+
+		m.FileTrashRetentionDays = 30
+		assert.Equal(t, 30, m.fileTrashRetentionDays())
+	})
+}
@@ -2,18 +2,25 @@ package handler
 
 import (
 	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
 	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/labstack/echo/v5"
 	"github.com/siherrmann/queuer/helper"
 	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/testutil/fake"
 	"github.com/siherrmann/queuerManager/upload"
+	vm "github.com/siherrmann/validator/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -125,6 +132,57 @@ func TestUploadFilesHandler(t *testing.T) {
 	})
 }
 
+func TestUploadFilesEncryptedHandler(t *testing.T) {
+	basePath := t.TempDir()
+	t.Setenv("TEST_STORAGE_ENCRYPTION_KEY", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32)))
+	fs, err := upload.NewFilesystemLocalEncrypted(basePath, upload.EnvKeyProvider{Variable: "TEST_STORAGE_ENCRYPTION_KEY"})
+	require.NoError(t, err)
+
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	e := echo.New()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("files", "secret.txt")
+	require.NoError(t, err)
+	_, err = part.Write([]byte("secret content"))
+	require.NoError(t, err)
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/file/uploadFiles", body)
+	req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err = handler.UploadFiles(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// The file on disk must not contain the plaintext
+	onDisk, err := os.ReadFile(filepath.Join(basePath, "secret.txt"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(onDisk), "secret content")
+
+	// Reading through the filesystem decrypts transparently
+	file, err := fs.Open("secret.txt")
+	require.NoError(t, err)
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	require.NoError(t, err)
+	assert.Equal(t, "secret content", string(content))
+
+	t.Run("Invalid key length", func(t *testing.T) {
+		t.Setenv("TEST_STORAGE_ENCRYPTION_KEY", base64.StdEncoding.EncodeToString([]byte("short")))
+		_, err := upload.NewFilesystemLocalEncrypted(basePath, upload.EnvKeyProvider{Variable: "TEST_STORAGE_ENCRYPTION_KEY"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "must be 32 bytes")
+	})
+}
+
 func TestDeleteFileHandler(t *testing.T) {
 	fs := upload.NewFilesystemMemory()
 	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
@@ -312,6 +370,36 @@ func TestFilesViewHandler(t *testing.T) {
 	})
 }
 
+func TestGetFilesHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	e := echo.New()
+
+	err = fs.Write("get-test-1.txt", strings.NewReader("content"), 7)
+	require.NoError(t, err)
+	err = fs.Write("other.txt", strings.NewReader("content"), 7)
+	require.NoError(t, err)
+
+	t.Run("GetFiles with search filter", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/file/getFiles?search=get-test", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetFiles(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		files := []upload.File{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &files))
+		require.Len(t, files, 1)
+		assert.Equal(t, "get-test-1.txt", files[0].Name)
+	})
+}
+
 func TestDeleteFilePopupViewHandler(t *testing.T) {
 	fs := upload.NewFilesystemMemory()
 	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
@@ -367,3 +455,162 @@ func TestAddFilePopupViewHandler(t *testing.T) {
 		assert.Equal(t, http.StatusOK, rec.Code)
 	})
 }
+
+func TestStoreParameterFiles(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	handler := &ManagerHandler{Filesystem: fs, validationFuncs: defaultValidationFuncs()}
+	handler.validationFuncs[qmModel.VALIDATION_TYPE_FILE] = handler.validateFile
+	task := &qmModel.Task{
+		InputParameters: []vm.Validation{
+			{Key: "input", Type: vm.ValidatorType(qmModel.VALIDATION_TYPE_FILE), Requirement: "-"},
+			{Key: "count", Type: vm.Int, Requirement: "min1"},
+		},
+	}
+
+	jobFormRequest := func(t *testing.T, selected string, filename string, content string) *http.Request {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		require.NoError(t, writer.WriteField("input", selected))
+		part, err := writer.CreateFormFile("input", filename)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, writer.WriteField("count", "2"))
+		require.NoError(t, writer.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/api/job/addJob/task", body)
+		req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+		return req
+	}
+
+	t.Run("Uploaded file is validated before it is stored", func(t *testing.T) {
+		req := jobFormRequest(t, "", "data.csv", "a,b")
+		require.NoError(t, handler.prepareParameterFiles(task, req))
+
+		parameters, err := handler.validateJobParameters(task, req)
+		require.NoError(t, err)
+		assert.Equal(t, "data.csv", parameters["input"])
+		assert.EqualValues(t, 2, parameters["count"])
+		_, err = fs.Stat("data.csv")
+		assert.Error(t, err, "Expected the file to not be stored before the job is added")
+
+		storedFiles, err := handler.storeParameterFiles(task, req, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"data.csv"}, storedFiles)
+
+		file, err := fs.Open("data.csv")
+		require.NoError(t, err)
+		defer file.Close()
+		content, err := io.ReadAll(file)
+		require.NoError(t, err)
+		assert.Equal(t, "a,b", string(content))
+	})
+
+	t.Run("Selected file is kept without upload", func(t *testing.T) {
+		req := jobFormRequest(t, "data.csv", "", "")
+		require.NoError(t, handler.prepareParameterFiles(task, req))
+
+		parameters, err := handler.validateJobParameters(task, req)
+		require.NoError(t, err)
+		assert.Equal(t, "data.csv", parameters["input"])
+
+		storedFiles, err := handler.storeParameterFiles(task, req, "alice")
+		require.NoError(t, err)
+		assert.Empty(t, storedFiles)
+	})
+
+	t.Run("Missing file fails the validation", func(t *testing.T) {
+		req := jobFormRequest(t, "missing.csv", "", "")
+		require.NoError(t, handler.prepareParameterFiles(task, req))
+
+		_, err := handler.validateJobParameters(task, req)
+		assert.ErrorContains(t, err, "file missing.csv does not exist")
+	})
+
+	t.Run("Replaced file is not deleted with the files of a job that was not added", func(t *testing.T) {
+		req := jobFormRequest(t, "", "data.csv", "c,d")
+		require.NoError(t, handler.prepareParameterFiles(task, req))
+
+		storedFiles, err := handler.storeParameterFiles(task, req, "alice")
+		require.NoError(t, err)
+		assert.Empty(t, storedFiles, "Expected an existing file to not be returned as new")
+
+		handler.deleteParameterFiles(storedFiles)
+		_, err = fs.Stat("data.csv")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Stored files of a job that was not added are deleted", func(t *testing.T) {
+		req := jobFormRequest(t, "", "new.csv", "e,f")
+		require.NoError(t, handler.prepareParameterFiles(task, req))
+
+		storedFiles, err := handler.storeParameterFiles(task, req, "alice")
+		require.NoError(t, err)
+		require.Equal(t, []string{"new.csv"}, storedFiles)
+
+		handler.deleteParameterFiles(storedFiles)
+		_, err = fs.Stat("new.csv")
+		assert.Error(t, err, "Expected the stored file to be deleted")
+	})
+}
+
+func TestAddJobParameterFiles(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	taskDB := fake.NewTaskDB()
+	handler := NewManagerHandler(fs, taskDB, fake.NewQueuer())
+	e := echo.New()
+
+	_, err := taskDB.InsertTask(&qmModel.Task{
+		Key:  "test-file-task",
+		Name: "Test File Task",
+		InputParameters: []vm.Validation{
+			{Key: "input", Type: vm.ValidatorType(qmModel.VALIDATION_TYPE_FILE), Requirement: "-"},
+			{Key: "count", Type: vm.Int, Requirement: "min1"},
+		},
+	})
+	require.NoError(t, err)
+
+	addJob := func(t *testing.T, target string, filename string, count string) *httptest.ResponseRecorder {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("input", filename)
+		require.NoError(t, err)
+		_, err = part.Write([]byte("a,b"))
+		require.NoError(t, err)
+		require.NoError(t, writer.WriteField("count", count))
+		require.NoError(t, writer.Close())
+
+		req := httptest.NewRequest(http.MethodPost, target, body)
+		req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: "test-file-task"}})
+
+		require.NoError(t, handler.AddJob(c))
+		return rec
+	}
+
+	t.Run("Dry run does not store the file", func(t *testing.T) {
+		rec := addJob(t, "/api/job/addJob/test-file-task?dryRun=true", "dry.csv", "2")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		_, err := fs.Stat("dry.csv")
+		assert.Error(t, err, "Expected the file of a dry run to not be stored")
+	})
+
+	t.Run("Invalid job does not store the file", func(t *testing.T) {
+		rec := addJob(t, "/api/job/addJob/test-file-task", "invalid.csv", "0")
+		require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+
+		_, err := fs.Stat("invalid.csv")
+		assert.Error(t, err, "Expected the file of an invalid job to not be stored")
+	})
+
+	t.Run("Added job stores the file", func(t *testing.T) {
+		rec := addJob(t, "/api/job/addJob/test-file-task", "added.csv", "2")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		_, err := fs.Stat("added.csv")
+		assert.NoError(t, err, "Expected the file of the added job to be stored")
+	})
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+)
+
+// =======API Handlers=======
+
+// GetForwardedJobs retrieves the jobs forwarded to remote instances with pagination, newest first
+func (m *ManagerHandler) GetForwardedJobs(c *echo.Context) error {
+	if m.ForwardDB == nil {
+		return c.String(http.StatusServiceUnavailable, "Job forwarding is not configured")
+	}
+
+	lastId, limit, err := m.apiPagination(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	forwardedJobs, err := m.ForwardDB.SelectAllForwardedJobs(lastId, limit)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to retrieve forwarded jobs")
+	}
+
+	return c.JSON(http.StatusOK, forwardedJobs)
+}
+
+// GetForwardedJob retrieves a forwarded job with the results synced back from the remote instance
+func (m *ManagerHandler) GetForwardedJob(c *echo.Context) error {
+	if m.ForwardDB == nil {
+		return c.String(http.StatusServiceUnavailable, "Job forwarding is not configured")
+	}
+
+	rid, err := uuid.Parse(c.Param("rid"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid forwarded job RID format")
+	}
+
+	forwardedJob, err := m.ForwardDB.SelectForwardedJob(rid)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Forwarded job not found")
+	}
+
+	return c.JSON(http.StatusOK, forwardedJob)
+}
+
+// SyncForwardedJobs updates the status, results and error of all unfinished forwarded jobs
+// from their remote jobs. It returns the number of forwarded jobs that have ended since the last sync.
+func (m *ManagerHandler) SyncForwardedJobs(ctx context.Context) (int, error) {
+	if m.Forwarder == nil || m.ForwardDB == nil {
+		return 0, nil
+	}
+
+	forwardedJobs, err := m.ForwardDB.SelectUnfinishedForwardedJobs()
+	if err != nil {
+		return 0, err
+	}
+
+	ended := 0
+	for _, forwardedJob := range forwardedJobs {
+		remoteJob, err := m.Forwarder.SyncJob(ctx, forwardedJob)
+		if err != nil {
+			// An unreachable remote instance should not block the sync of the other jobs
+			log.Printf("Error syncing forwarded job %s: %v", forwardedJob.RID, err)
+			continue
+		}
+		if remoteJob.Status == forwardedJob.Status && remoteJob.Error == forwardedJob.Error {
+			continue
+		}
+
+		forwardedJob.Status = remoteJob.Status
+		forwardedJob.Results = remoteJob.Results
+		forwardedJob.Error = remoteJob.Error
+		_, err = m.ForwardDB.UpdateForwardedJobStatus(forwardedJob)
+		if err != nil {
+			return ended, err
+		}
+		if forwardedJob.Ended() {
+			ended++
+		}
+	}
+
+	return ended, nil
+}
+
+// forwardJob adds the job on the remote instance of the forward rule instead of the local queue
+// and records it, so the results of the remote job can be synced back.
+func (m *ManagerHandler) forwardJob(c *echo.Context, task *model.Task, rule *model.ForwardRule, parametersList []any, parametersKeyed map[string]any) error {
+	if m.ForwardDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Job forwarding is not configured")
+	}
+
+	// The remote instance validates the parameters by key again
+	parameters := map[string]any{}
+	for key, value := range parametersKeyed {
+		parameters[key] = value
+	}
+	for i, value := range parametersList {
+		if i < len(task.InputParameters) {
+			parameters[task.InputParameters[i].Key] = value
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 30*time.Second)
+	defer cancel()
+
+	remoteJob, err := m.Forwarder.ForwardJob(ctx, rule, parameters)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadGateway, fmt.Sprintf("Failed to forward job with rule %s: %v", rule.Name, err))
+	}
+
+	forwardedJob, err := m.ForwardDB.InsertForwardedJob(&model.ForwardedJob{
+		Rule:       rule.Name,
+		TaskKey:    task.Key,
+		RemoteURL:  rule.RemoteURL,
+		RemoteTask: rule.RemoteTask,
+		RemoteRID:  remoteJob.RID,
+		Parameters: parameters,
+		Status:     remoteJob.Status,
+		Results:    remoteJob.Results,
+		Error:      remoteJob.Error,
+	})
+	if err != nil {
+		log.Printf("Error recording forwarded job %s: %v", remoteJob.RID, err)
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Job was forwarded as remote job %s but could not be recorded", remoteJob.RID))
+	}
+
+	c.Response().Header().Add("X-Forwarded-To", rule.Name)
+	if c.Request().Header.Get("HX-Request") != "" {
+		return renderPopupOrJson(c, http.StatusOK, fmt.Sprintf("Job forwarded with rule %s as remote job %s", rule.Name, remoteJob.RID))
+	}
+
+	return c.JSON(http.StatusOK, forwardedJob)
+}
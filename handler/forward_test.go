@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuer/model"
+	"github.com/siherrmann/queuerManager/database"
+	"github.com/siherrmann/queuerManager/forward"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	vm "github.com/siherrmann/validator/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForwardHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	fdb, err := database.NewForwardedJobDBHandler(db, true)
+	require.NoError(t, err)
+
+	// The remote instance accepts the job with its CI API and has ended it on the first sync
+	remoteRID := uuid.New()
+	var remoteParameters map[string]any
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer remote-ci-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/ci/runJob/remote-convert":
+			_ = json.NewDecoder(r.Body).Decode(&remoteParameters)
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(&qmModel.CIJobRun{Job: &model.Job{RID: remoteRID, TaskName: "remote-convert", Status: model.JobStatusQueued}})
+		case "/api/ci/waitJob/" + remoteRID.String():
+			_ = json.NewEncoder(w).Encode(&model.Job{RID: remoteRID, TaskName: "remote-convert", Status: model.JobStatusSucceeded, Results: model.Parameters{"output.csv"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer remote.Close()
+
+	forwarder, err := forward.NewForwarder([]*qmModel.ForwardRule{
+		{Name: "burst", Task: "test-forward-task", RemoteURL: remote.URL + "/", RemoteTask: "remote-convert", Token: "remote-ci-token"},
+	})
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.Forwarder = forwarder
+	handler.ForwardDB = fdb
+	e := echo.New()
+
+	_, err = tdb.InsertTask(&qmModel.Task{
+		Key:                  "test-forward-task",
+		Name:                 "Test Forward Task",
+		InputParameters:      []vm.Validation{},
+		InputParametersKeyed: []vm.Validation{{Key: "input_file", Type: vm.String, Requirement: "min1"}},
+	})
+	require.NoError(t, err)
+
+	var forwardedJob qmModel.ForwardedJob
+	t.Run("AddJob forwards job of forwarded task", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/job/addJob/test-forward-task", strings.NewReader(`{"input_file": "data.csv"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: "test-forward-task"}})
+
+		err := handler.AddJob(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, "burst", rec.Header().Get("X-Forwarded-To"))
+
+		err = json.Unmarshal(rec.Body.Bytes(), &forwardedJob)
+		require.NoError(t, err)
+		assert.Equal(t, remoteRID, forwardedJob.RemoteRID)
+		assert.Equal(t, "remote-convert", forwardedJob.RemoteTask)
+		assert.Equal(t, model.JobStatusQueued, forwardedJob.Status)
+		assert.Equal(t, map[string]any{"input_file": "data.csv"}, remoteParameters)
+	})
+
+	t.Run("SyncForwardedJobs syncs results back", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		ended, err := handler.SyncForwardedJobs(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, ended)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/forward/getForwardedJob/"+forwardedJob.RID.String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "rid", Value: forwardedJob.RID.String()}})
+
+		err = handler.GetForwardedJob(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var syncedJob qmModel.ForwardedJob
+		err = json.Unmarshal(rec.Body.Bytes(), &syncedJob)
+		require.NoError(t, err)
+		assert.Equal(t, model.JobStatusSucceeded, syncedJob.Status)
+		assert.Equal(t, model.Parameters{"output.csv"}, syncedJob.Results)
+
+		ended, err = handler.SyncForwardedJobs(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, ended, "ended forwarded jobs should not be synced again")
+	})
+
+	t.Run("AddJob fails if remote instance rejects the job", func(t *testing.T) {
+		forwarder, err := forward.NewForwarder([]*qmModel.ForwardRule{
+			{Task: "test-forward-task", RemoteURL: remote.URL, Token: "wrong-token"},
+		})
+		require.NoError(t, err)
+		handler.Forwarder = forwarder
+		defer func() { handler.Forwarder = nil }()
+
+		req := httptest.NewRequest(http.MethodPost, "/api/job/addJob/test-forward-task", strings.NewReader(`{"input_file": "data.csv"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: "test-forward-task"}})
+
+		err = handler.AddJob(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadGateway, rec.Code)
+	})
+}
@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/labstack/echo/v5"
+)
+
+var errUnknownGrafanaMetric = errors.New("unknown metric")
+
+// grafanaTaskPayload is the label filter of the job metrics that are counted from the job archive.
+var grafanaTaskPayload = []model.GrafanaMetricPayload{
+	{Label: "Task", Name: "task", Type: "input", Placeholder: "All tasks"},
+}
+
+// grafanaMetrics are all metrics that can be queried with the Grafana endpoints
+var grafanaMetrics = []model.GrafanaMetric{
+	{Label: "Jobs queued", Value: model.GRAFANA_METRIC_JOBS_QUEUED},
+	{Label: "Jobs scheduled", Value: model.GRAFANA_METRIC_JOBS_SCHEDULED},
+	{Label: "Jobs running", Value: model.GRAFANA_METRIC_JOBS_RUNNING},
+	{Label: "Workers total", Value: model.GRAFANA_METRIC_WORKERS_TOTAL},
+	{Label: "Workers ready", Value: model.GRAFANA_METRIC_WORKERS_READY},
+	{Label: "Workers running", Value: model.GRAFANA_METRIC_WORKERS_RUNNING},
+	{Label: "Jobs succeeded", Value: model.GRAFANA_METRIC_JOBS_SUCCEEDED, Payloads: grafanaTaskPayload},
+	{Label: "Jobs failed", Value: model.GRAFANA_METRIC_JOBS_FAILED, Payloads: grafanaTaskPayload},
+	{Label: "Jobs cancelled", Value: model.GRAFANA_METRIC_JOBS_CANCELLED, Payloads: grafanaTaskPayload},
+	{Label: "Job failure rate", Value: model.GRAFANA_METRIC_JOB_FAILURE_RATE, Payloads: grafanaTaskPayload},
+	{Label: "Job duration (avg seconds)", Value: model.GRAFANA_METRIC_JOB_DURATION_SECONDS, Payloads: grafanaTaskPayload},
+}
+
+// GrafanaHealth answers the connection test of the Grafana JSON datasource
+func (m *ManagerHandler) GrafanaHealth(c *echo.Context) error {
+	if m.MetricDB == nil {
+		return c.String(http.StatusServiceUnavailable, "Metrics are not enabled")
+	}
+	return c.String(http.StatusOK, "OK")
+}
+
+// GrafanaMetrics lists the metrics with their label filters for the Grafana JSON datasource
+func (m *ManagerHandler) GrafanaMetrics(c *echo.Context) error {
+	return c.JSON(http.StatusOK, grafanaMetrics)
+}
+
+// GrafanaQuery answers a time series query of the Grafana JSON datasource
+func (m *ManagerHandler) GrafanaQuery(c *echo.Context) error {
+	if m.MetricDB == nil {
+		return c.String(http.StatusServiceUnavailable, "Metrics are not enabled")
+	}
+
+	var query model.GrafanaQueryRequest
+	if err := c.Bind(&query); err != nil {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("Invalid query: %v", err))
+	}
+	if query.Range.From.IsZero() || query.Range.To.IsZero() || query.Range.From.After(query.Range.To) {
+		return c.String(http.StatusBadRequest, "Invalid time range")
+	}
+
+	interval := grafanaInterval(time.Duration(query.IntervalMs)*time.Millisecond, query.Range.From, query.Range.To)
+
+	series := []*model.GrafanaTimeSeries{}
+	for _, target := range query.Targets {
+		if target.Hide || target.Target == "" {
+			continue
+		}
+
+		taskName, _ := target.Payload["task"].(string)
+		points, err := m.grafanaSeries(target.Target, query.Range.From, query.Range.To, interval, taskName)
+		if errors.Is(err, errUnknownGrafanaMetric) {
+			return c.String(http.StatusBadRequest, fmt.Sprintf("Unknown metric %s", target.Target))
+		} else if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to retrieve metrics")
+		}
+
+		timeSeries := &model.GrafanaTimeSeries{Target: target.Target, Datapoints: [][2]float64{}}
+		for _, point := range points {
+			timeSeries.Datapoints = append(timeSeries.Datapoints, [2]float64{point.Value, float64(point.Time.UnixMilli())})
+		}
+		series = append(series, timeSeries)
+	}
+
+	return c.JSON(http.StatusOK, series)
+}
+
+// GrafanaTimeSeries returns a single metric as flat list of points for the Grafana Infinity datasource.
+// The time range is set with from and to (RFC3339), label filters are set as query parameters (eg. task).
+func (m *ManagerHandler) GrafanaTimeSeries(c *echo.Context) error {
+	if m.MetricDB == nil {
+		return c.String(http.StatusServiceUnavailable, "Metrics are not enabled")
+	}
+
+	metric := c.QueryParam("metric")
+	fromStr := c.QueryParam("from")
+	toStr := c.QueryParam("to")
+	intervalStr := c.QueryParam("interval")
+
+	// Parse to with default
+	to := time.Now()
+	if toStr != "" {
+		parsedTo, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return c.String(http.StatusBadRequest, "Invalid to format (must be RFC3339)")
+		}
+		to = parsedTo
+	}
+
+	// Parse from with default
+	from := to.Add(-24 * time.Hour)
+	if fromStr != "" {
+		parsedFrom, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return c.String(http.StatusBadRequest, "Invalid from format (must be RFC3339)")
+		}
+		from = parsedFrom
+	}
+
+	if from.After(to) {
+		return c.String(http.StatusBadRequest, "Invalid time range (from must be before to)")
+	}
+
+	// Parse interval with default
+	var interval time.Duration
+	if intervalStr != "" {
+		parsedInterval, err := time.ParseDuration(intervalStr)
+		if err != nil {
+			return c.String(http.StatusBadRequest, "Invalid interval (must be a duration, eg. 5m)")
+		}
+		interval = parsedInterval
+	}
+	interval = grafanaInterval(interval, from, to)
+
+	points, err := m.grafanaSeries(metric, from, to, interval, c.QueryParam("task"))
+	if errors.Is(err, errUnknownGrafanaMetric) {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("Unknown metric %q", metric))
+	} else if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to retrieve metrics")
+	}
+
+	return c.JSON(http.StatusOK, points)
+}
+
+// grafanaSeries loads the points of a metric between from and to.
+// Queue metrics are snapshots (hourly averages for ranges over two days), job metrics are aggregated per interval.
+func (m *ManagerHandler) grafanaSeries(metric string, from time.Time, to time.Time, interval time.Duration, taskName string) ([]*model.GrafanaPoint, error) {
+	points := []*model.GrafanaPoint{}
+
+	switch metric {
+	case model.GRAFANA_METRIC_JOBS_QUEUED, model.GRAFANA_METRIC_JOBS_SCHEDULED, model.GRAFANA_METRIC_JOBS_RUNNING,
+		model.GRAFANA_METRIC_WORKERS_TOTAL, model.GRAFANA_METRIC_WORKERS_READY, model.GRAFANA_METRIC_WORKERS_RUNNING:
+		var metrics []*model.QueueMetric
+		var err error
+		if to.Sub(from) > 48*time.Hour {
+			metrics, err = m.MetricDB.SelectMetricsHourly(from, to)
+		} else {
+			metrics, err = m.MetricDB.SelectMetrics(from, to)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, queueMetric := range metrics {
+			points = append(points, &model.GrafanaPoint{Time: queueMetric.Time, Value: float64(queueMetricValue(metric, queueMetric))})
+		}
+	case model.GRAFANA_METRIC_JOBS_SUCCEEDED, model.GRAFANA_METRIC_JOBS_FAILED, model.GRAFANA_METRIC_JOBS_CANCELLED,
+		model.GRAFANA_METRIC_JOB_FAILURE_RATE, model.GRAFANA_METRIC_JOB_DURATION_SECONDS:
+		stats, err := m.MetricDB.SelectJobStats(from, to, interval, taskName)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, stat := range stats {
+			points = append(points, &model.GrafanaPoint{Time: stat.Time, Value: jobStatsValue(metric, stat)})
+		}
+	default:
+		return nil, errUnknownGrafanaMetric
+	}
+
+	return points, nil
+}
+
+func queueMetricValue(metric string, queueMetric *model.QueueMetric) int {
+	switch metric {
+	case model.GRAFANA_METRIC_JOBS_QUEUED:
+		return queueMetric.JobsQueued
+	case model.GRAFANA_METRIC_JOBS_SCHEDULED:
+		return queueMetric.JobsScheduled
+	case model.GRAFANA_METRIC_JOBS_RUNNING:
+		return queueMetric.JobsRunning
+	case model.GRAFANA_METRIC_WORKERS_TOTAL:
+		return queueMetric.WorkersTotal
+	case model.GRAFANA_METRIC_WORKERS_READY:
+		return queueMetric.WorkersReady
+	default:
+		return queueMetric.WorkersRunning
+	}
+}
+
+func jobStatsValue(metric string, stat *model.JobStats) float64 {
+	switch metric {
+	case model.GRAFANA_METRIC_JOBS_SUCCEEDED:
+		return float64(stat.Succeeded)
+	case model.GRAFANA_METRIC_JOBS_FAILED:
+		return float64(stat.Failed)
+	case model.GRAFANA_METRIC_JOBS_CANCELLED:
+		return float64(stat.Cancelled)
+	case model.GRAFANA_METRIC_JOB_FAILURE_RATE:
+		return stat.FailureRate()
+	default:
+		return stat.AvgDurationSeconds
+	}
+}
+
+// grafanaInterval limits the bucket size of job metrics to at least one minute and at most 1000 buckets per range.
+func grafanaInterval(interval time.Duration, from time.Time, to time.Time) time.Duration {
+	return max(interval, time.Minute, to.Sub(from)/1000)
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrafanaHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("metricdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	mdb, err := database.NewMetricDBHandler(db, true)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.MetricDB = mdb
+	e := echo.New()
+
+	_, err = mdb.InsertMetricSnapshot()
+	require.NoError(t, err)
+
+	t.Run("GrafanaHealth", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/grafana/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GrafanaHealth(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("GrafanaMetrics lists metrics with label filters", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/grafana/metrics", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GrafanaMetrics(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var metrics []qmModel.GrafanaMetric
+		err = json.Unmarshal(rec.Body.Bytes(), &metrics)
+		require.NoError(t, err)
+		assert.Len(t, metrics, len(grafanaMetrics))
+	})
+
+	t.Run("GrafanaQuery with queue and job metrics", func(t *testing.T) {
+		from := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+		to := time.Now().Add(time.Minute).UTC().Format(time.RFC3339)
+		body := fmt.Sprintf(`{
+			"range": {"from": %q, "to": %q},
+			"intervalMs": 60000,
+			"targets": [
+				{"refId": "A", "target": "jobs_queued"},
+				{"refId": "B", "target": "job_failure_rate", "payload": {"task": "test-task"}},
+				{"refId": "C", "target": "workers_total", "hide": true}
+			]
+		}`, from, to)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/grafana/query", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GrafanaQuery(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var series []qmModel.GrafanaTimeSeries
+		err = json.Unmarshal(rec.Body.Bytes(), &series)
+		require.NoError(t, err)
+		require.Len(t, series, 2, "Expected hidden targets to be skipped")
+		assert.Equal(t, "jobs_queued", series[0].Target)
+		assert.NotEmpty(t, series[0].Datapoints, "Expected the metric snapshot as datapoint")
+		assert.Equal(t, "job_failure_rate", series[1].Target)
+	})
+
+	t.Run("GrafanaQuery with unknown metric", func(t *testing.T) {
+		body := `{"range": {"from": "2026-01-01T00:00:00Z", "to": "2026-01-02T00:00:00Z"}, "targets": [{"refId": "A", "target": "unknown"}]}`
+
+		req := httptest.NewRequest(http.MethodPost, "/api/grafana/query", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GrafanaQuery(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("GrafanaTimeSeries for the infinity datasource", func(t *testing.T) {
+		query := url.Values{}
+		query.Set("metric", "jobs_succeeded")
+		query.Set("from", time.Now().Add(-time.Hour).UTC().Format(time.RFC3339))
+		query.Set("interval", "5m")
+
+		req := httptest.NewRequest(http.MethodGet, "/api/grafana/timeseries?"+query.Encode(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GrafanaTimeSeries(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var points []qmModel.GrafanaPoint
+		err = json.Unmarshal(rec.Body.Bytes(), &points)
+		require.NoError(t, err)
+	})
+
+	t.Run("GrafanaTimeSeries with invalid interval", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/grafana/timeseries?metric=jobs_failed&interval=often", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GrafanaTimeSeries(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,359 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/siherrmann/queuerManager/graphql"
+	qmModel "github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+)
+
+// graphQLTaskCacheKey is the context key of the tasks resolved by key during a GraphQL request.
+type graphQLTaskCacheKey struct{}
+
+// GraphQL executes a GraphQL query on the tasks, jobs, workers and archived jobs, eg. for dashboards
+// fetching exactly the fields they need in one round trip. The query is the JSON body of a POST request
+// ({"query": "...", "variables": {...}, "operationName": "..."}) or the query parameters of a GET request.
+// Requests failing before the execution respond with 400, errors of fields are returned with the data of the other fields.
+// Only queries are executed, so viewers can use it, and queries above the depth, field, alias or cost limits are rejected,
+// a list costs its limit times the fields selected per entry.
+func (m *ManagerHandler) GraphQL(c *echo.Context) error {
+	request := &graphql.Request{}
+	if c.Request().Method == http.MethodGet {
+		request.Query = c.QueryParam("query")
+		request.OperationName = c.QueryParam("operationName")
+		if variables := c.QueryParam("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &request.Variables); err != nil {
+				return c.JSON(http.StatusBadRequest, graphQLError("Invalid variables (must be a JSON object)"))
+			}
+		}
+	} else if err := json.NewDecoder(c.Request().Body).Decode(request); err != nil {
+		return c.JSON(http.StatusBadRequest, graphQLError("Invalid request body"))
+	}
+
+	if strings.TrimSpace(request.Query) == "" {
+		return c.JSON(http.StatusBadRequest, graphQLError("Query is required"))
+	}
+
+	schema, err := m.graphQLSchema()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, graphQLError(fmt.Sprintf("Invalid schema: %v", err)))
+	}
+
+	ctx := context.WithValue(c.Request().Context(), graphQLTaskCacheKey{}, map[string]*qmModel.Task{})
+	response := schema.Execute(ctx, request)
+	if response.Data == nil {
+		return c.JSON(http.StatusBadRequest, response)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetGraphQLSchema returns the GraphQL schema in the schema definition language, eg. to generate the types of a client.
+func (m *ManagerHandler) GetGraphQLSchema(c *echo.Context) error {
+	schema, err := m.graphQLSchema()
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("Invalid schema: %v", err))
+	}
+
+	return c.String(http.StatusOK, schema.String())
+}
+
+// graphQLError returns the response of a request that failed before the execution.
+func graphQLError(message string) *graphql.Response {
+	return &graphql.Response{Errors: []*graphql.Error{{Message: message}}}
+}
+
+// graphQLSchema returns the GraphQL schema of the tasks, jobs, workers and archived jobs.
+// The fields are the JSON fields of the models, the arguments are named like the query parameters of the REST endpoints.
+func (m *ManagerHandler) graphQLSchema() (*graphql.Schema, error) {
+	pageArgs := []*graphql.Argument{
+		{Name: "lastId", Type: "Int", Default: 0, Description: "ID of the last entry of the previous page."},
+		{Name: "limit", Type: "Int", Description: "Maximum number of entries, default the API page size."},
+	}
+
+	task := graphql.NewObject("Task", "A task definition.", &qmModel.Task{},
+		&graphql.Field{
+			Name:        "jobs",
+			Description: "The jobs of the task in the queue, newest first (needs the job filters).",
+			Type:        "[Job!]!",
+			Args:        append([]*graphql.Argument{{Name: "status", Type: "String"}}, pageArgs...),
+			Cost:        m.graphQLPageCost,
+			Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+				return m.graphQLJobs(source.(*qmModel.Task).Key, args)
+			},
+		},
+		&graphql.Field{
+			Name:        "archived_jobs",
+			Description: "The archived jobs of the task, newest first (needs the job archive filters).",
+			Type:        "[Job!]!",
+			Args:        append([]*graphql.Argument{{Name: "status", Type: "String"}}, pageArgs...),
+			Cost:        m.graphQLPageCost,
+			Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+				return m.graphQLArchivedJobs(source.(*qmModel.Task).Key, args)
+			},
+		},
+	)
+
+	job := graphql.NewObject("Job", "A job in the queue or in the archive.", &model.Job{},
+		&graphql.Field{
+			Name:        "status_name",
+			Description: "The display name of the status.",
+			Type:        "String!",
+			Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+				return qmModel.StatusName(source.(*model.Job).Status), nil
+			},
+		},
+		&graphql.Field{
+			Name:        "task",
+			Description: "The task definition of the job, null if the task was deleted.",
+			Type:        "Task",
+			Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+				return m.graphQLTask(ctx, source.(*model.Job).TaskName)
+			},
+		},
+		&graphql.Field{
+			Name:        "worker",
+			Description: "The worker executing the job, null if no worker picked it up or the worker was removed.",
+			Type:        "Worker",
+			Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+				workerRID := source.(*model.Job).WorkerRID
+				if workerRID == uuid.Nil {
+					return nil, nil
+				}
+				worker, err := m.Queuer.GetWorker(workerRID)
+				if err != nil {
+					return nil, nil
+				}
+				return worker, nil
+			},
+		},
+	)
+
+	worker := graphql.NewObject("Worker", "A worker executing jobs.", &model.Worker{})
+
+	query := &graphql.Object{
+		Name: "Query",
+		Fields: []*graphql.Field{
+			{
+				Name:        "tasks",
+				Description: "The tasks, optionally matching the search and in the namespace.",
+				Type:        "[Task!]!",
+				Args:        append([]*graphql.Argument{{Name: "search", Type: "String"}, {Name: "namespace", Type: "String"}}, pageArgs...),
+				Cost:        m.graphQLPageCost,
+				Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+					limit, err := m.graphQLLimit(args)
+					if err != nil {
+						return nil, err
+					}
+					if search := args.String("search"); search != "" {
+						return m.taskDB.SelectAllTasksBySearch(search, args.String("namespace"), args.Int("lastId"), limit)
+					}
+					if namespace := args.String("namespace"); namespace != "" {
+						return m.taskDB.SelectAllTasksByFilter(&qmModel.TaskFilter{Namespace: namespace}, args.Int("lastId"), limit)
+					}
+					return m.taskDB.SelectAllTasks(args.Int("lastId"), limit)
+				},
+			},
+			{
+				Name:        "task",
+				Description: "A task by key or RID.",
+				Type:        "Task",
+				Args:        []*graphql.Argument{{Name: "key", Type: "String"}, {Name: "rid", Type: "ID"}},
+				Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+					if key := args.String("key"); key != "" {
+						return m.graphQLTask(ctx, key)
+					}
+					rid, err := uuid.Parse(args.String("rid"))
+					if err != nil {
+						return nil, fmt.Errorf("Invalid task RID format (key or rid is required)")
+					}
+					task, err := m.taskDB.SelectTask(rid)
+					if err != nil {
+						return nil, nil
+					}
+					return task, nil
+				},
+			},
+			{
+				Name:        "jobs",
+				Description: "The jobs in the queue, newest first. The status, taskKey and namespace filters need the job filters.",
+				Type:        "[Job!]!",
+				Args: append([]*graphql.Argument{
+					{Name: "status", Type: "String", Description: "One of " + strings.Join(qmModel.JOB_ACTIVE_STATUSES, ", ") + "."},
+					{Name: "taskKey", Type: "String"},
+					{Name: "search", Type: "String"},
+					{Name: "namespace", Type: "String"},
+				}, pageArgs...),
+				Cost: m.graphQLPageCost,
+				Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+					return m.graphQLJobs(args.String("taskKey"), args)
+				},
+			},
+			{
+				Name:        "job",
+				Description: "A job in the queue or in the archive by RID.",
+				Type:        "Job",
+				Args:        []*graphql.Argument{{Name: "rid", Type: "ID!"}},
+				Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+					rid, err := uuid.Parse(args.String("rid"))
+					if err != nil {
+						return nil, fmt.Errorf("Invalid job RID format")
+					}
+					job, err := m.Queuer.GetJob(rid)
+					if err != nil {
+						job, err = m.Queuer.GetJobEnded(rid)
+						if err != nil {
+							return nil, nil
+						}
+					}
+					return job, nil
+				},
+			},
+			{
+				Name:        "archived_jobs",
+				Description: "The archived jobs, newest first. The status, taskKey and namespace filters need the job archive filters.",
+				Type:        "[Job!]!",
+				Args: append([]*graphql.Argument{
+					{Name: "status", Type: "String", Description: "One of " + strings.Join(graphQLArchivedJobStatuses, ", ") + "."},
+					{Name: "taskKey", Type: "String"},
+					{Name: "search", Type: "String"},
+					{Name: "namespace", Type: "String"},
+				}, pageArgs...),
+				Cost: m.graphQLPageCost,
+				Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+					return m.graphQLArchivedJobs(args.String("taskKey"), args)
+				},
+			},
+			{
+				Name:        "workers",
+				Description: "The workers.",
+				Type:        "[Worker!]!",
+				Args:        pageArgs,
+				Cost:        m.graphQLPageCost,
+				Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+					limit, err := m.graphQLLimit(args)
+					if err != nil {
+						return nil, err
+					}
+					return m.Queuer.GetWorkers(args.Int("lastId"), limit)
+				},
+			},
+			{
+				Name:        "worker",
+				Description: "A worker by RID.",
+				Type:        "Worker",
+				Args:        []*graphql.Argument{{Name: "rid", Type: "ID!"}},
+				Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+					rid, err := uuid.Parse(args.String("rid"))
+					if err != nil {
+						return nil, fmt.Errorf("Invalid worker RID format")
+					}
+					worker, err := m.Queuer.GetWorker(rid)
+					if err != nil {
+						return nil, nil
+					}
+					return worker, nil
+				},
+			},
+		},
+	}
+
+	return graphql.NewSchema(query, task, job, worker)
+}
+
+// graphQLArchivedJobStatuses are the statuses the archived jobs can be filtered by.
+var graphQLArchivedJobStatuses = []string{model.JobStatusSucceeded, model.JobStatusFailed, model.JobStatusCancelled}
+
+// graphQLLimit returns the limit argument, the API page size by default and at most the max page size.
+func (m *ManagerHandler) graphQLLimit(args graphql.Args) (int, error) {
+	config := m.paginationConfig()
+	if args.Int("lastId") < 0 {
+		return 0, fmt.Errorf("Invalid lastId format")
+	}
+	limit, ok := args["limit"].(int)
+	if !ok {
+		return config.APIPageSize, nil
+	}
+	if limit <= 0 || limit > config.MaxPageSize {
+		return 0, fmt.Errorf("Invalid limit (must be 1-%d)", config.MaxPageSize)
+	}
+	return limit, nil
+}
+
+// graphQLPageCost returns the cost of a paginated list, the limit times the cost of the selections of an entry.
+func (m *ManagerHandler) graphQLPageCost(args graphql.Args, childCost int) int {
+	limit, err := m.graphQLLimit(args)
+	if err != nil {
+		return 1
+	}
+	return 1 + limit*childCost
+}
+
+// graphQLJobs returns the jobs in the queue matching the status, task key, namespace and search arguments, see GetJobs.
+func (m *ManagerHandler) graphQLJobs(taskKey string, args graphql.Args) ([]*model.Job, error) {
+	limit, err := m.graphQLLimit(args)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &qmModel.JobFilter{Search: args.String("search"), Status: args.String("status"), TaskKey: taskKey, Namespace: args.String("namespace")}
+	if filter.Status != "" && !slices.Contains(qmModel.JOB_ACTIVE_STATUSES, filter.Status) {
+		return nil, fmt.Errorf("Invalid status (must be one of %s)", strings.Join(qmModel.JOB_ACTIVE_STATUSES, ", "))
+	}
+
+	switch {
+	case filter.HasFilters():
+		return m.jobsByFilter(filter, args.Int("lastId"), limit)
+	case filter.Search != "":
+		return m.Queuer.GetJobsBySearch(filter.Search, args.Int("lastId"), limit)
+	}
+	return m.Queuer.GetJobs(args.Int("lastId"), limit)
+}
+
+// graphQLArchivedJobs returns the archived jobs matching the status, task key, namespace and search arguments, see GetJobsArchive.
+func (m *ManagerHandler) graphQLArchivedJobs(taskKey string, args graphql.Args) ([]*model.Job, error) {
+	limit, err := m.graphQLLimit(args)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &qmModel.JobArchiveFilter{Search: args.String("search"), Status: args.String("status"), TaskKey: taskKey, Namespace: args.String("namespace")}
+	if filter.Status != "" && !slices.Contains(graphQLArchivedJobStatuses, filter.Status) {
+		return nil, fmt.Errorf("Invalid status (must be one of %s)", strings.Join(graphQLArchivedJobStatuses, ", "))
+	}
+
+	switch {
+	case filter.HasFilters():
+		return m.jobsEndedByFilter(filter, args.Int("lastId"), limit)
+	case filter.Search != "":
+		return m.Queuer.GetJobsEndedBySearch(filter.Search, args.Int("lastId"), limit)
+	}
+	return m.Queuer.GetJobsEnded(args.Int("lastId"), limit)
+}
+
+// graphQLTask returns the task by key, nil if it does not exist.
+// The tasks are cached for the request, so the task of a list of jobs is selected once per key.
+func (m *ManagerHandler) graphQLTask(ctx context.Context, key string) (*qmModel.Task, error) {
+	cache, _ := ctx.Value(graphQLTaskCacheKey{}).(map[string]*qmModel.Task)
+	if task, ok := cache[key]; ok {
+		return task, nil
+	}
+
+	task, err := m.taskDB.SelectTaskByKey(key)
+	if err != nil {
+		task = nil
+	}
+	if cache != nil {
+		cache[key] = task
+	}
+	return task, nil
+}
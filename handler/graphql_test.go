@@ -0,0 +1,313 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/siherrmann/queuerManager/graphql"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQLSchema(t *testing.T) {
+	m := &ManagerHandler{}
+	schema, err := m.graphQLSchema()
+	require.NoError(t, err, "Expected the GraphQL schema to be valid")
+
+	sdl := schema.String()
+	assert.Contains(t, sdl, "scalar JSON")
+	assert.Contains(t, sdl, "type Query {")
+	assert.Contains(t, sdl, "type Task {")
+	assert.Contains(t, sdl, "type Worker {")
+	assert.Contains(t, sdl, "  rid: ID!\n")
+	assert.Contains(t, sdl, "  task: Task\n", "Expected the job to resolve its task definition")
+	assert.Contains(t, sdl, "  scheduled_at: String\n", "Expected time pointers to be nullable strings")
+	assert.Contains(t, sdl, "  available_tasks: [String!]\n")
+	assert.Contains(t, sdl, "  parameters: JSON\n")
+	assert.Contains(t, sdl, `jobs("One of QUEUED, SCHEDULED, RUNNING, FAILED." status: String, taskKey: String, search: String`)
+}
+
+func TestGraphQL(t *testing.T) {
+	m := &ManagerHandler{}
+	e := echo.New()
+	e.GET("/api/graphql", m.GraphQL)
+	e.POST("/api/graphql", m.GraphQL)
+
+	post := func(body string) (int, *graphql.Response) {
+		req := httptest.NewRequest(http.MethodPost, "/api/graphql", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		response := &graphql.Response{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), response), "Expected a JSON response: %s", rec.Body.String())
+		return rec.Code, response
+	}
+
+	t.Run("Should resolve the typename of the query", func(t *testing.T) {
+		code, response := post(`{"query": "query Dashboard { type: __typename }"}`)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Empty(t, response.Errors)
+		assert.Equal(t, map[string]any{"type": "Query"}, response.Data)
+	})
+
+	t.Run("Should reject invalid queries", func(t *testing.T) {
+		for query, message := range map[string]string{
+			`{ jobs { rid `:                                 "unexpected end of document",
+			`{ jobs { rid unknown } }`:                      "unknown field unknown on type Job",
+			`{ jobs(state: \"queued\") { rid } }`:           "unknown argument state on field Query.jobs",
+			`{ jobs }`:                                      "field Query.jobs of type [Job!]! requires selections",
+			`{ job(rid: $rid) { rid } }`:                    "variable $rid is not defined",
+			`{ job { rid } }`:                               "argument rid of type ID! is required",
+			`{ jobs(limit: \"ten\") { rid } }`:              "invalid argument limit",
+			`{ jobs { rid { id } } }`:                       "field Job.rid of type ID! has no selections",
+			`mutation { jobs { rid } }`:                     "mutation operations are not supported",
+			`{ jobs { ...workerFields } }`:                  "unknown fragment workerFields",
+			`{ jobs { ... on Worker { rid } } }`:            "inline fragment on Worker cannot be spread on Job",
+			`{ jobs { rid @defer } }`:                       "unknown directive @defer",
+			`query A { __typename } query B { __typename }`: "operationName is required",
+		} {
+			code, response := post(`{"query": "` + query + `"}`)
+			assert.Equal(t, http.StatusBadRequest, code, "Expected query %s to be rejected", query)
+			require.NotEmpty(t, response.Errors, "Expected query %s to be rejected", query)
+			assert.Contains(t, response.Errors[0].Message, message)
+			assert.Nil(t, response.Data)
+		}
+	})
+
+	t.Run("Should reject fragment cycles and too deep queries", func(t *testing.T) {
+		code, response := post(`{"query": "{ jobs { ...a } } fragment a on Job { task { jobs { ...a } } }"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		require.NotEmpty(t, response.Errors)
+		assert.Contains(t, response.Errors[0].Message, "fragment a spreads itself")
+
+		code, response = post(`{"query": "{ jobs { task { jobs { task { jobs { task { jobs { task { jobs { task { jobs { rid } } } } } } } } } } } }"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		require.NotEmpty(t, response.Errors)
+		assert.Contains(t, response.Errors[0].Message, "maximum depth 10")
+	})
+
+	t.Run("Should reject queries above the alias, field and cost limits", func(t *testing.T) {
+		aliases := []string{}
+		for i := range graphql.DEFAULT_MAX_ALIASES + 1 {
+			aliases = append(aliases, fmt.Sprintf("a%d: __typename", i))
+		}
+		code, response := post(`{"query": "{ ` + strings.Join(aliases, " ") + ` }"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		require.Len(t, response.Errors, 1)
+		assert.Contains(t, response.Errors[0].Message, "maximum of 30 aliases")
+
+		fragments := "fragment f0 on Query { __typename }"
+		for i := 1; i <= 10; i++ {
+			fragments += fmt.Sprintf(" fragment f%d on Query { ...f%d ...f%d }", i, i-1, i-1)
+		}
+		code, response = post(`{"query": "{ ...f10 } ` + fragments + `"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		require.Len(t, response.Errors, 1, "Expected the validation to stop at the field limit")
+		assert.Contains(t, response.Errors[0].Message, "maximum of 500 fields")
+
+		code, response = post(`{"query": "{ tasks(limit: 100) { jobs(limit: 100) { rid } } }"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		require.Len(t, response.Errors, 1)
+		assert.Contains(t, response.Errors[0].Message, "cost of 10101, more than the maximum cost 10000")
+	})
+
+	t.Run("Should introspect the schema", func(t *testing.T) {
+		code, response := post(`{"query": "{ __schema { queryType { name } mutationType { name } } __type(name: \"Job\") { kind fields { name type { kind ofType { kind name } } } } unknown: __type(name: \"Unknown\") { name } }"}`)
+		assert.Equal(t, http.StatusOK, code)
+		require.Empty(t, response.Errors)
+
+		data := response.Data.(map[string]any)
+		assert.Equal(t, map[string]any{"queryType": map[string]any{"name": "Query"}, "mutationType": nil}, data["__schema"])
+		assert.Nil(t, data["unknown"])
+		job := data["__type"].(map[string]any)
+		assert.Equal(t, "OBJECT", job["kind"])
+		assert.Contains(t, job["fields"], map[string]any{"name": "rid", "type": map[string]any{"kind": "NON_NULL", "ofType": map[string]any{"kind": "SCALAR", "name": "ID"}}})
+	})
+
+	t.Run("Should return field errors with their path", func(t *testing.T) {
+		code, response := post(`{"query": "query($limit: Int) { ok: __typename workers(limit: $limit) { rid } }", "variables": {"limit": 0}}`)
+		assert.Equal(t, http.StatusOK, code)
+		require.Len(t, response.Errors, 1)
+		assert.Contains(t, response.Errors[0].Message, "Invalid limit (must be 1-")
+		assert.Equal(t, []any{"workers"}, response.Errors[0].Path)
+		assert.Nil(t, response.Data, "Expected the data to be null because workers is non-null")
+	})
+
+	t.Run("Should read the query parameters of GET requests", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/graphql?query="+url.QueryEscape("{ __typename }"), nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"data": {"__typename": "Query"}}`, rec.Body.String())
+
+		req = httptest.NewRequest(http.MethodGet, "/api/graphql?query="+url.QueryEscape("{ __typename }")+"&variables=nope", nil)
+		rec = httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+type graphQLTestAuthor struct {
+	ID    int      `json:"id"`
+	Name  string   `json:"name"`
+	Email *string  `json:"email"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+type graphQLTestPost struct {
+	ID       int            `json:"id"`
+	Title    string         `json:"title"`
+	AuthorID int            `json:"author_id"`
+	Meta     map[string]any `json:"meta"`
+}
+
+func TestGraphQLExecute(t *testing.T) {
+	authors := map[int]*graphQLTestAuthor{1: {ID: 1, Name: "Ada", Tags: []string{"math"}}}
+	posts := []*graphQLTestPost{
+		{ID: 1, Title: "Engines", AuthorID: 1, Meta: map[string]any{"draft": false}},
+		{ID: 2, Title: "Orphan", AuthorID: 2},
+	}
+	authorLookups := 0
+
+	author := graphql.NewObject("Author", "", &graphQLTestAuthor{})
+	post := graphql.NewObject("Post", "", &graphQLTestPost{},
+		&graphql.Field{
+			Name: "author",
+			Type: "Author",
+			Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+				authorLookups++
+				return authors[source.(*graphQLTestPost).AuthorID], nil
+			},
+		},
+		&graphql.Field{
+			Name: "required_author",
+			Type: "Author!",
+			Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+				return authors[source.(*graphQLTestPost).AuthorID], nil
+			},
+		},
+	)
+	query := &graphql.Object{Name: "Query", Fields: []*graphql.Field{
+		{
+			Name: "posts",
+			Type: "[Post!]!",
+			Args: []*graphql.Argument{{Name: "first", Type: "Int", Default: 10}, {Name: "title", Type: "String"}},
+			Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+				result := []*graphQLTestPost{}
+				for _, p := range posts[:min(args.Int("first"), len(posts))] {
+					if title := args.String("title"); title == "" || p.Title == title {
+						result = append(result, p)
+					}
+				}
+				return result, nil
+			},
+		},
+		{
+			Name: "failing",
+			Type: "String",
+			Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+				return nil, fmt.Errorf("failed")
+			},
+		},
+	}}
+
+	schema, err := graphql.NewSchema(query, post, author)
+	require.NoError(t, err, "Expected the schema to be valid")
+
+	execute := func(query string, variables map[string]any) string {
+		response := schema.Execute(context.Background(), &graphql.Request{Query: query, Variables: variables})
+		result, err := json.Marshal(response)
+		require.NoError(t, err)
+		return string(result)
+	}
+
+	t.Run("Should resolve nested fields in the order of the selections", func(t *testing.T) {
+		result := execute(`{ posts(first: 1) { title id author { name email tags } meta } }`, nil)
+		assert.Equal(t, `{"data":{"posts":[{"title":"Engines","id":1,"author":{"name":"Ada","email":null,"tags":["math"]},"meta":{"draft":false}}]}}`, result)
+	})
+
+	t.Run("Should apply variables, aliases, fragments and directives", func(t *testing.T) {
+		authorLookups = 0
+		result := execute(`
+			query Posts($title: String!, $withAuthor: Boolean = false, $first: Int) {
+				engines: posts(title: $title, first: $first) { ...postFields author @include(if: $withAuthor) { name } }
+				all: posts { ... on Post { id } id @skip(if: true) }
+			}
+			fragment postFields on Post { id title }
+		`, map[string]any{"title": "Engines", "first": float64(2)})
+		assert.Equal(t, `{"data":{"engines":[{"id":1,"title":"Engines"}],"all":[{"id":1},{"id":2}]}}`, result)
+		assert.Equal(t, 0, authorLookups, "Expected the excluded author to not be resolved")
+	})
+
+	t.Run("Should reject missing and invalid variables", func(t *testing.T) {
+		assert.Contains(t, execute(`query($title: String!) { posts(title: $title) { id } }`, nil), "variable $title of type String! is required")
+		assert.Contains(t, execute(`query($first: Int) { posts(first: $first) { id } }`, map[string]any{"first": 1.5}), "invalid variable $first")
+	})
+
+	t.Run("Should null failed fields and propagate nulls of non-null fields", func(t *testing.T) {
+		result := execute(`{ failing posts { id author { name } } }`, nil)
+		assert.Equal(t, `{"data":{"failing":null,"posts":[{"id":1,"author":{"name":"Ada"}},{"id":2,"author":null}]},"errors":[{"message":"failed","path":["failing"]}]}`, result)
+
+		result = execute(`{ failing posts { id required_author { name } } }`, nil)
+		assert.Equal(t, `{"data":null,"errors":[{"message":"failed","path":["failing"]},{"message":"cannot return null for non-null field of type Author!","path":["posts",1,"required_author"]}]}`, result)
+	})
+
+	t.Run("Should answer the introspection query of the GraphQL clients", func(t *testing.T) {
+		response := schema.Execute(context.Background(), &graphql.Request{Query: graphQLTestIntrospectionQuery})
+		require.Empty(t, response.Errors)
+
+		result, err := json.Marshal(response.Data)
+		require.NoError(t, err)
+		assert.Contains(t, string(result), `{"kind":"OBJECT","name":"Post","description":null,"fields":[{"name":"id","description":null,"args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"Int","ofType":null}},"isDeprecated":false,"deprecationReason":null}`)
+		assert.Contains(t, string(result), `{"name":"first","description":null,"type":{"kind":"SCALAR","name":"Int","ofType":null},"defaultValue":"10"}`)
+		assert.Contains(t, string(result), `{"name":"include","description":"Directs the executor to include this field or fragment only when the if argument is true.","locations":["FIELD","FRAGMENT_SPREAD","INLINE_FRAGMENT"]`)
+		assert.Contains(t, string(result), `"name":"__Schema"`, "Expected the introspection types in the types")
+		assert.NotContains(t, schema.String(), "__Schema", "Expected the SDL without the introspection types")
+	})
+
+	t.Run("Should reject invalid schemas", func(t *testing.T) {
+		_, err := graphql.NewSchema(&graphql.Object{Name: "Query", Fields: []*graphql.Field{{Name: "post", Type: "Post"}}})
+		assert.ErrorContains(t, err, "unknown type Post of field Query.post")
+
+		_, err = graphql.NewSchema(&graphql.Object{Name: "Query", Fields: []*graphql.Field{{Name: "posts", Type: "[Post!]!", Args: []*graphql.Argument{{Name: "author", Type: "Author"}}}}}, post, author)
+		assert.ErrorContains(t, err, "must be a scalar")
+
+		_, err = graphql.NewSchema(&graphql.Object{Name: "Query", Fields: []*graphql.Field{{Name: "__schema", Type: "String"}}})
+		assert.ErrorContains(t, err, "reserved for the introspection")
+	})
+}
+
+// graphQLTestIntrospectionQuery is the introspection query of GraphiQL and the schema generators.
+const graphQLTestIntrospectionQuery = `
+	query IntrospectionQuery {
+		__schema {
+			queryType { name }
+			mutationType { name }
+			subscriptionType { name }
+			types { ...FullType }
+			directives { name description locations args { ...InputValue } }
+		}
+	}
+	fragment FullType on __Type {
+		kind name description
+		fields(includeDeprecated: true) { name description args { ...InputValue } type { ...TypeRef } isDeprecated deprecationReason }
+		inputFields { ...InputValue }
+		interfaces { ...TypeRef }
+		enumValues(includeDeprecated: true) { name description isDeprecated deprecationReason }
+		possibleTypes { ...TypeRef }
+	}
+	fragment InputValue on __InputValue { name description type { ...TypeRef } defaultValue }
+	fragment TypeRef on __Type {
+		kind name
+		ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } } } }
+	}
+`
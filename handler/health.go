@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/labstack/echo/v5"
+)
+
+// =======API Handlers=======
+
+// GetHealthHistory retrieves the daily uptime of the subsystems and the incidents of the last 90 days.
+// It is public without login like the status page, which shows the same history.
+func (m *ManagerHandler) GetHealthHistory(c *echo.Context) error {
+	if m.HealthDB == nil {
+		return c.String(http.StatusServiceUnavailable, "Health history is not enabled")
+	}
+
+	history, err := m.healthHistory(time.Now())
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to get health history")
+	}
+
+	return c.JSON(http.StatusOK, history)
+}
+
+// GetIncidents retrieves the incidents of the last 90 days and the ongoing incidents.
+func (m *ManagerHandler) GetIncidents(c *echo.Context) error {
+	if m.HealthDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Health history is not enabled")
+	}
+
+	incidents, err := m.HealthDB.SelectIncidents(healthHistorySince(time.Now()))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get incidents: %v", err))
+	}
+
+	return renderPopupOrJson(c, http.StatusOK, incidents)
+}
+
+// AddIncident annotates an incident of a subsystem, an empty subsystem marks an incident of the whole manager.
+func (m *ManagerHandler) AddIncident(c *echo.Context) error {
+	if m.HealthDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Health history is not enabled")
+	}
+
+	var incidentRequest model.IncidentRequest
+	if err := c.Bind(&incidentRequest); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+	}
+	incidentRequest.Title = strings.TrimSpace(incidentRequest.Title)
+	if incidentRequest.Title == "" || len(incidentRequest.Title) > 200 {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Title is required (max 200 characters)")
+	}
+	if incidentRequest.Subsystem != "" && !slices.Contains(model.SUBSYSTEMS, incidentRequest.Subsystem) {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid subsystem %q (must be one of %s)", incidentRequest.Subsystem, strings.Join(model.SUBSYSTEMS, ", ")))
+	}
+	if incidentRequest.StartedAt.After(time.Now()) {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Start of the incident must not be in the future")
+	}
+
+	incident, err := m.HealthDB.InsertIncident(&model.Incident{
+		Subsystem:   incidentRequest.Subsystem,
+		Title:       incidentRequest.Title,
+		Description: strings.TrimSpace(incidentRequest.Description),
+		StartedAt:   incidentRequest.StartedAt,
+		CreatedBy:   requestedBy(c),
+	})
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to add incident: %v", err))
+	}
+
+	if c.Request().Header.Get("HX-Request") != "" {
+		return renderRowsWithPopup(c, http.StatusCreated, screens.IncidentRow(incident), "#table_body_incidents_table", "afterbegin", "closeAddIncident", "Successfully added incident")
+	}
+	return c.JSON(http.StatusCreated, incident)
+}
+
+// ResolveIncidents resolves the incidents with the RIDs of the query now, eg. `?rid=a&rid=b`.
+func (m *ManagerHandler) ResolveIncidents(c *echo.Context) error {
+	return m.updateIncidents(c, "resolve", func(rid uuid.UUID) error {
+		_, err := m.HealthDB.UpdateIncidentResolved(rid, time.Now())
+		return err
+	})
+}
+
+// DeleteIncidents deletes the incidents with the RIDs of the query, eg. `?rid=a&rid=b`.
+func (m *ManagerHandler) DeleteIncidents(c *echo.Context) error {
+	return m.updateIncidents(c, "delete", func(rid uuid.UUID) error {
+		return m.HealthDB.DeleteIncident(rid)
+	})
+}
+
+// =======View Handlers=======
+
+// IncidentsView renders the incident list view
+func (m *ManagerHandler) IncidentsView(c *echo.Context) error {
+	if m.HealthDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Health history is not enabled")
+	}
+
+	incidents, err := m.HealthDB.SelectIncidents(healthHistorySince(time.Now()))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get incidents: %v", err))
+	}
+
+	c.Response().Header().Add("HX-Push-Url", "/incidents")
+	c.Response().Header().Add("HX-Retarget", "#body")
+
+	return render(c, screens.Incidents(incidents))
+}
+
+// AddIncidentPopupView renders the popup to annotate an incident
+func (m *ManagerHandler) AddIncidentPopupView(c *echo.Context) error {
+	return renderPopup(c, screens.AddIncidentPopup())
+}
+
+// ResolveIncidentPopupView renders the popup to resolve incidents
+func (m *ManagerHandler) ResolveIncidentPopupView(c *echo.Context) error {
+	rids := c.QueryParams()["rid"]
+	if len(rids) == 0 {
+		return renderPopupOrJson(c, http.StatusBadRequest, "No incident RIDs provided")
+	}
+
+	return renderPopup(c, screens.IncidentActionPopup("resolve", rids))
+}
+
+// DeleteIncidentPopupView renders the popup to delete incidents
+func (m *ManagerHandler) DeleteIncidentPopupView(c *echo.Context) error {
+	rids := c.QueryParams()["rid"]
+	if len(rids) == 0 {
+		return renderPopupOrJson(c, http.StatusBadRequest, "No incident RIDs provided")
+	}
+
+	return renderPopup(c, screens.IncidentActionPopup("delete", rids))
+}
+
+// =======Helpers=======
+
+// RecordHealth persists the checks of the enabled subsystems in the health history
+// and deletes the checks older than the history.
+func (m *ManagerHandler) RecordHealth(ctx context.Context) error {
+	if m.HealthDB == nil {
+		return nil
+	}
+
+	systemStatus := m.systemStatus(ctx)
+	for _, subsystem := range systemStatus.Subsystems {
+		if subsystem.Status == model.STATUS_DISABLED {
+			continue
+		}
+		_, err := m.HealthDB.InsertHealthCheck(&model.HealthCheck{
+			Subsystem: subsystem.Name,
+			Status:    subsystem.Status,
+			Message:   subsystem.Message,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to insert health check of %s: %w", subsystem.Name, err)
+		}
+	}
+
+	_, err := m.HealthDB.DeleteHealthChecksBefore(healthHistorySince(time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to delete old health checks: %w", err)
+	}
+	return nil
+}
+
+// healthHistorySince returns the start of the first day of the health history.
+func healthHistorySince(now time.Time) time.Time {
+	today := now.UTC().Truncate(24 * time.Hour)
+	return today.AddDate(0, 0, -(model.HEALTH_HISTORY_DAYS - 1))
+}
+
+// healthHistory collects the daily uptime of the subsystems and the incidents of the health history.
+func (m *ManagerHandler) healthHistory(now time.Time) (*model.HealthHistory, error) {
+	since := healthHistorySince(now)
+
+	days, err := m.HealthDB.SelectHealthDays(since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve health days: %w", err)
+	}
+	incidents, err := m.HealthDB.SelectIncidents(since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve incidents: %w", err)
+	}
+
+	return &model.HealthHistory{
+		Days:       model.HEALTH_HISTORY_DAYS,
+		Subsystems: subsystemUptimes(days, since),
+		Incidents:  incidents,
+	}, nil
+}
+
+// subsystemUptimes returns the uptime of every subsystem with a day for every day of the history since the day,
+// the days without checks are empty. Subsystems that were never checked, eg. disabled ones, are left out.
+func subsystemUptimes(days map[string][]*model.HealthDay, since time.Time) []*model.SubsystemUptime {
+	uptimes := []*model.SubsystemUptime{}
+	for _, subsystem := range model.SUBSYSTEMS {
+		subsystemDays, ok := days[subsystem]
+		if !ok {
+			continue
+		}
+
+		byDay := map[time.Time]*model.HealthDay{}
+		for _, day := range subsystemDays {
+			byDay[day.Day.UTC()] = day
+		}
+
+		uptime := &model.SubsystemUptime{Name: subsystem, Days: []*model.HealthDay{}}
+		checks, down := 0, 0
+		for i := 0; i < model.HEALTH_HISTORY_DAYS; i++ {
+			dayStart := since.AddDate(0, 0, i)
+			day, ok := byDay[dayStart]
+			if !ok {
+				day = &model.HealthDay{Day: dayStart}
+			}
+			checks += day.Checks
+			down += day.Down
+			uptime.Days = append(uptime.Days, day)
+		}
+
+		uptime.Uptime = 1
+		if checks > 0 {
+			uptime.Uptime = float64(checks-down) / float64(checks)
+		}
+		uptimes = append(uptimes, uptime)
+	}
+	return uptimes
+}
+
+// updateIncidents runs the update of the action for the incidents with the RIDs of the query
+// and swaps the incident rows for HTMX requests.
+func (m *ManagerHandler) updateIncidents(c *echo.Context, action string, update func(rid uuid.UUID) error) error {
+	if m.HealthDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Health history is not enabled")
+	}
+
+	ridStrings := c.QueryParams()["rid"]
+	if len(ridStrings) == 0 {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Missing incident RID")
+	}
+
+	updatedCount := 0
+	var errors []string
+	for _, ridStr := range ridStrings {
+		rid, err := uuid.Parse(ridStr)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Invalid RID %s: %v", ridStr, err))
+			continue
+		}
+
+		err = update(rid)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Failed to %s incident %s: %v", action, ridStr, err))
+			continue
+		}
+		updatedCount++
+	}
+
+	status := http.StatusOK
+	message := fmt.Sprintf("Successfully %sd %d incident(s)", action, updatedCount)
+	if len(errors) > 0 {
+		status = http.StatusPartialContent
+		message = fmt.Sprintf("%sd %d incidents. Errors: %v", strings.ToUpper(action[:1])+action[1:], updatedCount, errors)
+	}
+
+	if c.Request().Header.Get("HX-Request") != "" {
+		incidents, err := m.HealthDB.SelectIncidents(healthHistorySince(time.Now()))
+		if err != nil {
+			return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get incidents: %v", err))
+		}
+		return renderRowsWithPopup(c, status, screens.IncidentRows(incidents), "#table_body_incidents_table", "innerHTML", "closeIncidentAction", message)
+	}
+
+	return renderPopupOrJson(c, status, message)
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthHistorySince(t *testing.T) {
+	now := time.Date(2026, 3, 31, 15, 30, 0, 0, time.UTC)
+
+	since := healthHistorySince(now)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), since, "Expected the history to start 89 days before today at midnight UTC")
+}
+
+func TestSubsystemUptimes(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	days := map[string][]*model.HealthDay{
+		model.SUBSYSTEM_DATABASE: {
+			{Day: since, Checks: 10},
+			{Day: since.AddDate(0, 0, 2), Checks: 10, Degraded: 2, Down: 5},
+		},
+	}
+
+	uptimes := subsystemUptimes(days, since)
+	require.Len(t, uptimes, 1, "Expected only the checked subsystems")
+	assert.Equal(t, model.SUBSYSTEM_DATABASE, uptimes[0].Name)
+	require.Len(t, uptimes[0].Days, model.HEALTH_HISTORY_DAYS, "Expected a day for every day of the history")
+	assert.Equal(t, model.STATUS_OK, uptimes[0].Days[0].Status())
+	assert.Equal(t, "", uptimes[0].Days[1].Status(), "Expected a day without checks to have no status")
+	assert.Equal(t, model.STATUS_DOWN, uptimes[0].Days[2].Status())
+	assert.InDelta(t, 0.75, uptimes[0].Uptime, 0.001, "Expected the uptime of all checks of the history")
+}
+
+func TestIncidentHandlers(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("healthdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	hdb, err := database.NewHealthDBHandler(db, true)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.HealthDB = hdb
+	e := echo.New()
+
+	var incident model.Incident
+	t.Run("AddIncident", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/incident/addIncident", strings.NewReader(`{"subsystem": "Database", "title": "Database failover"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.AddIncident(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &incident))
+		assert.True(t, incident.Ongoing())
+	})
+
+	t.Run("AddIncident with invalid subsystem", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/incident/addIncident", strings.NewReader(`{"subsystem": "Mainframe", "title": "Outage"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.AddIncident(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("ResolveIncidents", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/incident/resolveIncidents?rid="+incident.RID.String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.ResolveIncidents(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("GetHealthHistory", func(t *testing.T) {
+		err := handler.RecordHealth(t.Context())
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/status/history", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err = handler.GetHealthHistory(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var history model.HealthHistory
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &history))
+		require.NotEmpty(t, history.Subsystems, "Expected the recorded checks in the history")
+		assert.Len(t, history.Subsystems[0].Days, model.HEALTH_HISTORY_DAYS)
+		require.Len(t, history.Incidents, 1)
+		assert.False(t, history.Incidents[0].Ongoing(), "Expected the incident to be resolved")
+	})
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/siherrmann/queuerManager/model"
+)
+
+// AddPreTaskSaveHook registers a hook that runs before a task is added, updated or imported.
+// The hook can change the task, an error rejects the save.
+func (m *ManagerHandler) AddPreTaskSaveHook(hook model.TaskHookFunc) {
+	m.preTaskSaveHooks = append(m.preTaskSaveHooks, hook)
+}
+
+// AddPostTaskSaveHook registers a hook that runs after a task was added, updated or imported.
+// Errors of the hook are only logged, the task is already saved.
+func (m *ManagerHandler) AddPostTaskSaveHook(hook model.TaskHookFunc) {
+	m.postTaskSaveHooks = append(m.postTaskSaveHooks, hook)
+}
+
+// AddPreJobSubmitHook registers a hook that runs after the parameters of a job are validated
+// and before the job is added or forwarded. The hook can change the parameters, an error rejects the job.
+func (m *ManagerHandler) AddPreJobSubmitHook(hook model.JobSubmitHookFunc) {
+	m.preJobSubmitHooks = append(m.preJobSubmitHooks, hook)
+}
+
+// runPreTaskSaveHooks runs the pre task save hooks in the order they were registered and stops at the first error.
+func (m *ManagerHandler) runPreTaskSaveHooks(ctx context.Context, task *model.Task) error {
+	for _, hook := range m.preTaskSaveHooks {
+		if err := hook(ctx, task); err != nil {
+			return fmt.Errorf("task %s rejected: %w", task.Key, err)
+		}
+	}
+	return nil
+}
+
+// runPostTaskSaveHooks runs the post task save hooks and logs their errors.
+func (m *ManagerHandler) runPostTaskSaveHooks(ctx context.Context, task *model.Task) {
+	for _, hook := range m.postTaskSaveHooks {
+		if err := hook(ctx, task); err != nil {
+			log.Printf("Post task save hook failed for task %s: %v", task.Key, err)
+		}
+	}
+}
+
+// runPreJobSubmitHooks runs the pre job submit hooks in the order they were registered and stops at the first error.
+func (m *ManagerHandler) runPreJobSubmitHooks(ctx context.Context, submission *model.JobSubmission) error {
+	for _, hook := range m.preJobSubmitHooks {
+		if err := hook(ctx, submission); err != nil {
+			return fmt.Errorf("job rejected: %w", err)
+		}
+	}
+	return nil
+}
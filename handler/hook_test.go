@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	vm "github.com/siherrmann/validator/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLifecycleHooks(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	e := echo.New()
+
+	savedTasks := []string{}
+	handler.AddPreTaskSaveHook(func(ctx context.Context, task *qmModel.Task) error {
+		if !strings.HasPrefix(task.Key, "team-") {
+			return errors.New("task keys must start with team-")
+		}
+		task.Owner = "platform"
+		return nil
+	})
+	handler.AddPostTaskSaveHook(func(ctx context.Context, task *qmModel.Task) error {
+		savedTasks = append(savedTasks, task.Key)
+		return errors.New("post hook errors are only logged")
+	})
+	handler.AddPreJobSubmitHook(func(ctx context.Context, submission *qmModel.JobSubmission) error {
+		if submission.ParametersKeyed["region"] == "forbidden" {
+			return errors.New("region is not allowed")
+		}
+		submission.ParametersKeyed["submitted_by"] = submission.SubmittedBy
+		return nil
+	})
+
+	t.Run("Pre task save hook rejects the task", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/task/addTask", strings.NewReader("key=test-hook-task&name=Test+Hook+Task"))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.AddTask(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "task keys must start with team-")
+
+		_, err = tdb.SelectTaskByKey("test-hook-task")
+		assert.Error(t, err, "Expected rejected task not to be saved")
+		assert.NotContains(t, savedTasks, "test-hook-task")
+	})
+
+	t.Run("Pre task save hook changes the task", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/task/addTask", strings.NewReader("key=team-hook-task&name=Team+Hook+Task"))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.AddTask(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		task, err := tdb.SelectTaskByKey("team-hook-task")
+		require.NoError(t, err)
+		assert.Equal(t, "platform", task.Owner)
+		assert.Contains(t, savedTasks, "team-hook-task")
+	})
+
+	t.Run("Pre job submit hook injects and rejects parameters", func(t *testing.T) {
+		task, err := tdb.InsertTask(&qmModel.Task{
+			Key:                  "team-hook-job-task",
+			Name:                 "Team Hook Job Task",
+			InputParameters:      []vm.Validation{},
+			InputParametersKeyed: []vm.Validation{{Key: "region", Type: vm.String, Requirement: "min1"}},
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/job/createJob", strings.NewReader(`{"task_key": "team-hook-job-task", "parameters": {"region": "eu"}, "dry_run": true}`))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err = handler.CreateJob(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var dryRun qmModel.JobDryRun
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &dryRun))
+		assert.Equal(t, task.Key, dryRun.TaskKey)
+		assert.Equal(t, "eu", dryRun.ParametersKeyed["region"])
+		assert.Equal(t, "192.0.2.1", dryRun.ParametersKeyed["submitted_by"])
+
+		req = httptest.NewRequest(http.MethodPost, "/api/job/createJob", strings.NewReader(`{"task_key": "team-hook-job-task", "parameters": {"region": "forbidden"}}`))
+		rec = httptest.NewRecorder()
+		c = e.NewContext(req, rec)
+
+		err = handler.CreateJob(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "region is not allowed")
+	})
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+)
+
+// HousekeepingTasks returns the task functions of the housekeeping tasks by their key.
+// They are registered at the queuer of the manager, so the manager runs the housekeeping jobs itself.
+func (m *ManagerHandler) HousekeepingTasks() map[string]any {
+	return map[string]any{
+		model.HOUSEKEEPING_TASK_RECORD_METRICS:     m.recordMetricsTask,
+		model.HOUSEKEEPING_TASK_RECORD_HEALTH:      m.recordHealthTask,
+		model.HOUSEKEEPING_TASK_RUN_SCHEDULES:      m.runSchedulesTask,
+		model.HOUSEKEEPING_TASK_DELETE_ACCESS_LOGS: m.deleteAccessLogsTask,
+		model.HOUSEKEEPING_TASK_PRUNE_FILES:        m.pruneFilesTask,
+	}
+}
+
+// RegisterHousekeepingTasks creates or updates the housekeeping tasks, so their jobs are shown and can be retried like other jobs.
+func (m *ManagerHandler) RegisterHousekeepingTasks() error {
+	for _, housekeepingTask := range model.HousekeepingTasks {
+		task := *housekeepingTask
+		_, _, err := m.taskDB.UpsertTask(&task)
+		if err != nil {
+			return fmt.Errorf("error registering housekeeping task %s: %w", task.Key, err)
+		}
+	}
+	return nil
+}
+
+// AddHousekeepingJob adds a job of the housekeeping task, the job is initiated by the manager.
+func (m *ManagerHandler) AddHousekeepingJob(taskKey string) error {
+	if !model.IsHousekeepingTask(taskKey) {
+		return fmt.Errorf("task %s is not a housekeeping task", taskKey)
+	}
+
+	job, err := m.Queuer.AddJob(taskKey, nil)
+	if err != nil {
+		return fmt.Errorf("error adding housekeeping job of %s: %w", taskKey, err)
+	}
+	m.recordJobInitiator(job, model.HOUSEKEEPING_INITIATOR, false)
+
+	return nil
+}
+
+// validateTaskKey checks that the task key does not have the reserved prefix of the housekeeping tasks.
+func validateTaskKey(key string) error {
+	if model.IsHousekeepingTask(key) {
+		return fmt.Errorf("task keys starting with %s are reserved for the housekeeping of the manager", model.HOUSEKEEPING_TASK_PREFIX)
+	}
+	return nil
+}
+
+// recordMetricsTask records a queue metric snapshot, it is recorded as run of the scheduler in the status tracker.
+// Snapshots within the metric interval of the last snapshot are skipped, eg. of housekeeping jobs added while the lease failed over.
+func (m *ManagerHandler) recordMetricsTask() error {
+	if m.MetricDB == nil {
+		return fmt.Errorf("queue metrics are not enabled")
+	}
+
+	_, err := m.MetricDB.RecordMetricSnapshot(model.MetricMinInterval(m.MetricInterval))
+	if err != nil {
+		err = fmt.Errorf("record metrics: %w", err)
+	}
+	m.Status.Record(model.SUBSYSTEM_SCHEDULER, err)
+
+	return err
+}
+
+// recordHealthTask persists the health of the subsystems and deletes the checks older than the health history.
+func (m *ManagerHandler) recordHealthTask() error {
+	return m.RecordHealth(context.Background())
+}
+
+// runSchedulesTask adds the jobs of the due schedules and returns the number of added jobs as result of the job.
+func (m *ManagerHandler) runSchedulesTask() (int, error) {
+	added, err := m.RunDueSchedules(context.Background(), time.Now())
+	if err != nil {
+		err = fmt.Errorf("run schedules: %w", err)
+	}
+	m.Status.Record(model.SUBSYSTEM_SCHEDULER, err)
+
+	return added, err
+}
+
+// deleteAccessLogsTask deletes the expired access logs and returns the number of deleted access logs as result of the job.
+func (m *ManagerHandler) deleteAccessLogsTask() (int64, error) {
+	deleted, err := m.DeleteExpiredAccessLogs()
+	m.Status.Record(model.SUBSYSTEM_SCHEDULER, err)
+
+	return deleted, err
+}
+
+// pruneFilesTask prunes the orphaned files and returns the report of the reclaimed space as result of the job.
+func (m *ManagerHandler) pruneFilesTask() (*model.FilePruneReport, error) {
+	report, err := m.PruneOrphanedFiles()
+	m.Status.Record(model.SUBSYSTEM_SCHEDULER, err)
+
+	return report, err
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHousekeepingTasks(t *testing.T) {
+	t.Run("Should have a task function for every housekeeping task", func(t *testing.T) {
+		m := &ManagerHandler{}
+		tasks := m.HousekeepingTasks()
+
+		assert.Len(t, tasks, len(model.HousekeepingTasks))
+		for _, task := range model.HousekeepingTasks {
+			assert.Contains(t, tasks, task.Key)
+			assert.True(t, model.IsHousekeepingTask(task.Key))
+		}
+	})
+
+	t.Run("Should reject the reserved prefix for other tasks", func(t *testing.T) {
+		assert.NoError(t, validateTaskKey("import-orders"))
+		assert.ErrorContains(t, validateTaskKey(model.HOUSEKEEPING_TASK_PREFIX+"cleanup"), "reserved")
+	})
+
+	t.Run("Should only add jobs of housekeeping tasks", func(t *testing.T) {
+		m := &ManagerHandler{}
+
+		err := m.AddHousekeepingJob("import-orders")
+		assert.ErrorContains(t, err, "not a housekeeping task")
+	})
+}
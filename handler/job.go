@@ -1,38 +1,143 @@
 package handler
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
-	"strconv"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/components"
 	"github.com/siherrmann/queuerManager/view/screens"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v5"
 	"github.com/siherrmann/queuer/model"
+	"github.com/siherrmann/validator"
+	vm "github.com/siherrmann/validator/model"
 )
 
 // =======API Handlers=======
 
-// AddJob handles the addition of a new job
+// AddJob handles the addition of a new job.
+// With dryRun=true the parameters are fully validated and the payload that would be enqueued
+// is returned without adding the job. With test=true the job is tagged as a test run of the task.
+// The priority (query parameter or form field) orders the job in the queue, see jobPriorityFromRequest.
+// With scheduled_at (query parameter or form field) the job is scheduled to run at that time, see jobScheduledAtFromRequest.
 func (m *ManagerHandler) AddJob(c *echo.Context) error {
 	taskKey := c.Param("taskKey")
+	dryRun := c.QueryParam("dryRun") == "true"
+	test := c.QueryParam("test") == "true"
 	task, err := m.taskDB.SelectTaskByKey(taskKey)
 	if err != nil {
 		return c.String(http.StatusNotFound, "Task not found")
 	}
 
-	// Validate regular parameters
-	parameters := map[string]any{}
-	validations := task.InputParameters
-	validations = append(validations, task.InputParametersKeyed...)
-	err = m.validator.UnmapOrUnmarshalValidateAndUpdateWithValidation(c.Request(), &parameters, validations)
+	// Files uploaded with the job form are validated with their names, they are only stored once the job is added
+	err = m.prepareParameterFiles(task, c.Request())
 	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Failed to read files: %v", err))
+	}
+	if c.Request().MultipartForm != nil {
+		defer c.Request().MultipartForm.RemoveAll() // Clean up temporary files
+	}
+
+	parametersList, parametersKeyed, err := m.resolveJobParameters(c.Request().Context(), task, c.Request(), requestedBy(c))
+	if errors.Is(err, errJobPayloadTooLarge) {
+		return renderPopupOrJson(c, http.StatusRequestEntityTooLarge, err.Error())
+	} else if err != nil {
 		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Validation error: %v", err))
 	}
 
-	// Validate keyed parameters (extract from form values with "keyed_" prefix)
+	priority, err := m.jobPriorityFromRequest(c, task)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+
+	scheduledAt, err := jobScheduledAtFromRequest(c, task)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+
+	// Warn or block if no connected worker satisfies the minimum worker version of the task
+	versionWarning, block, err := m.checkWorkerVersion(task)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to check worker version: %v", err))
+	}
+	if block {
+		return renderPopupOrJson(c, http.StatusConflict, versionWarning)
+	}
+	if versionWarning != "" {
+		log.Printf("Warning: %s", versionWarning)
+		c.Response().Header().Add("X-Worker-Version-Warning", versionWarning)
+	}
+
+	if dryRun {
+		dryRunResult := &qmModel.JobDryRun{
+			TaskKey:         taskKey,
+			Parameters:      parametersList,
+			ParametersKeyed: parametersKeyed,
+			ScheduledAt:     scheduledAt,
+			Warnings:        []string{},
+		}
+		if versionWarning != "" {
+			dryRunResult.Warnings = append(dryRunResult.Warnings, versionWarning)
+		}
+		return renderPopupOrJson(c, http.StatusOK, dryRunResult)
+	}
+
+	// Forward the job to the remote instance if the task is forwarded, eg. to burst to another cluster
+	rule := m.Forwarder.Rule(taskKey)
+	if rule != nil && scheduledAt != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Jobs of forwarded tasks can not be scheduled")
+	}
+
+	storedFiles, err := m.storeParameterFiles(task, c.Request(), requestedBy(c))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to store files: %v", err))
+	}
+	if rule != nil {
+		return m.forwardJob(c, task, rule, parametersList, parametersKeyed)
+	}
+
+	// Add job with keyed parameters map and spread parameter list
+	// An existing job with the same parameters is returned within the dedup window of the task
+	jobPriority := &qmModel.JobPriority{Priority: priority, UpdatedBy: requestedBy(c)}
+	jobAdded, deduplicated, err := m.addTaskJobWithOptions(task, jobScheduleOptions(scheduledAt), jobPriority, parametersList, parametersKeyed)
+	if err != nil {
+		m.deleteParameterFiles(storedFiles)
+		return renderPopupOrJson(c, addJobErrorStatus(c, err), fmt.Sprintf("Failed to add job: %v", err))
+	}
+	if deduplicated {
+		m.deleteParameterFiles(storedFiles)
+		c.Response().Header().Set(HEADER_JOB_DEDUPLICATED, "true")
+	} else {
+		m.recordJobInitiator(jobAdded, requestedBy(c), test)
+		m.registerJobChains(task, jobAdded, test)
+		m.publishEvent(qmModel.EVENT_JOB_ADDED, jobAdded)
+	}
+
+	c.Response().Header().Add("HX-Redirect", fmt.Sprintf("/job?rid=%s", jobAdded.RID.String()))
+
+	return renderPopupOrJson(c, http.StatusOK, jobAdded)
+}
+
+// resolveJobParameters validates the request parameters against the task input parameters
+// and splits them into the parameter list and keyed parameter map a job is added with.
+// The pre job submit hooks run with the validated parameters before the payload size is checked.
+func (m *ManagerHandler) resolveJobParameters(ctx context.Context, task *qmModel.Task, request *http.Request, submittedBy string) ([]any, map[string]any, error) {
+	parameters, err := m.validateJobParameters(task, request)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	parametersList := []any{}
 	parametersKeyed := map[string]any{}
 	for _, v := range task.InputParameters {
@@ -46,15 +151,152 @@ func (m *ManagerHandler) AddJob(c *echo.Context) error {
 		}
 	}
 
-	// Add job with keyed parameters map and spread parameter list
-	jobAdded, err := m.Queuer.AddJob(taskKey, parametersKeyed, parametersList...)
+	submission := &qmModel.JobSubmission{
+		Task:            task,
+		Parameters:      parametersList,
+		ParametersKeyed: parametersKeyed,
+		SubmittedBy:     submittedBy,
+	}
+	err = m.runPreJobSubmitHooks(ctx, submission)
 	if err != nil {
-		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to add job: %v", err))
+		return nil, nil, err
 	}
 
-	c.Response().Header().Add("HX-Redirect", fmt.Sprintf("/job?rid=%s", jobAdded.RID.String()))
+	err = m.checkJobPayloadSize(submission.Parameters, submission.ParametersKeyed)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	return renderPopupOrJson(c, http.StatusOK, jobAdded)
+	return submission.Parameters, submission.ParametersKeyed, nil
+}
+
+// validateJobParameters validates the request parameters against the task input parameters with a validator per request
+// and returns them by parameter key, custom validations are checked after the built-in validation.
+func (m *ManagerHandler) validateJobParameters(task *qmModel.Task, request *http.Request) (map[string]any, error) {
+	parameters := map[string]any{}
+	validations := append([]vm.Validation{}, task.InputParameters...)
+	validations = append(validations, task.InputParametersKeyed...)
+	fileKeys := map[string]bool{}
+	for _, validation := range fileValidations(task) {
+		fileKeys[validation.Key] = true
+	}
+	validations, customValidations := m.splitCustomValidations(validations)
+	err := validator.NewValidator().UnmapOrUnmarshalValidateAndUpdateWithValidation(request, &parameters, validations)
+	if err != nil {
+		return nil, err
+	}
+	for key, validationFuncs := range customValidations {
+		value, ok := parameters[key]
+		if !ok {
+			continue
+		}
+		// A file uploaded with the job form is stored after the validation
+		if fileHeader := uploadedParameterFile(request, key); fileKeys[key] && fileHeader != nil && value == filepath.Base(fileHeader.Filename) {
+			continue
+		}
+		for _, validationFunc := range validationFuncs {
+			if err := validationFunc(value); err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+		}
+	}
+
+	return parameters, nil
+}
+
+// CreateJob adds a job from a JSON body for clients without HTMX and responds with the created job as JSON.
+// The parameters are validated like the parameters of AddJob, errors are returned as JSON with an error message.
+// With dry_run the payload that would be enqueued is returned without adding the job.
+// With scheduled_at the job is scheduled to run at that time.
+func (m *ManagerHandler) CreateJob(c *echo.Context) error {
+	var jobCreateRequest qmModel.JobCreateRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&jobCreateRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("Invalid request: %v", err)})
+	}
+	if jobCreateRequest.TaskKey == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Task key is required"})
+	}
+	if jobCreateRequest.Parameters == nil {
+		jobCreateRequest.Parameters = map[string]any{}
+	}
+	if err := m.checkJobPriority(jobCreateRequest.Priority); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if jobCreateRequest.ScheduledAt != nil {
+		if err := validateJobScheduledAt(*jobCreateRequest.ScheduledAt); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+	}
+
+	task, err := m.taskDB.SelectTaskByKey(jobCreateRequest.TaskKey)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Task not found"})
+	}
+
+	parametersJSON, err := json.Marshal(jobCreateRequest.Parameters)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("Invalid parameters: %v", err)})
+	}
+
+	jobRequest, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader(parametersJSON))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to read parameters: %v", err)})
+	}
+	jobRequest.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	parametersList, parametersKeyed, err := m.resolveJobParameters(c.Request().Context(), task, jobRequest, requestedBy(c))
+	if errors.Is(err, errJobPayloadTooLarge) {
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": err.Error()})
+	} else if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("Validation error: %v", err)})
+	}
+
+	versionWarning, block, err := m.checkWorkerVersion(task)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to check worker version: %v", err)})
+	}
+	if block {
+		return c.JSON(http.StatusConflict, map[string]string{"error": versionWarning})
+	}
+	if versionWarning != "" {
+		c.Response().Header().Add("X-Worker-Version-Warning", versionWarning)
+	}
+
+	if jobCreateRequest.DryRun {
+		dryRunResult := &qmModel.JobDryRun{
+			TaskKey:         task.Key,
+			Parameters:      parametersList,
+			ParametersKeyed: parametersKeyed,
+			ScheduledAt:     jobCreateRequest.ScheduledAt,
+			Warnings:        []string{},
+		}
+		if versionWarning != "" {
+			dryRunResult.Warnings = append(dryRunResult.Warnings, versionWarning)
+		}
+		return c.JSON(http.StatusOK, dryRunResult)
+	}
+
+	if rule := m.Forwarder.Rule(task.Key); rule != nil {
+		if jobCreateRequest.ScheduledAt != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Jobs of forwarded tasks can not be scheduled"})
+		}
+		return m.forwardJob(c, task, rule, parametersList, parametersKeyed)
+	}
+
+	jobPriority := &qmModel.JobPriority{Priority: jobCreateRequest.Priority, UpdatedBy: requestedBy(c)}
+	jobAdded, deduplicated, err := m.addTaskJobWithOptions(task, jobScheduleOptions(jobCreateRequest.ScheduledAt), jobPriority, parametersList, parametersKeyed)
+	if err != nil {
+		return c.JSON(addJobErrorStatus(c, err), map[string]string{"error": fmt.Sprintf("Failed to add job: %v", err)})
+	}
+	if deduplicated {
+		c.Response().Header().Set(HEADER_JOB_DEDUPLICATED, "true")
+		return c.JSON(http.StatusOK, jobAdded)
+	}
+	m.recordJobInitiator(jobAdded, requestedBy(c), jobCreateRequest.Test)
+	m.registerJobChains(task, jobAdded, jobCreateRequest.Test)
+	m.publishEvent(qmModel.EVENT_JOB_ADDED, jobAdded)
+
+	return c.JSON(http.StatusCreated, jobAdded)
 }
 
 // GetJob retrieves a specific job by RID
@@ -76,37 +318,145 @@ func (m *ManagerHandler) GetJob(c *echo.Context) error {
 	return renderPopupOrJson(c, http.StatusOK, job)
 }
 
-// GetJobs retrieves a paginated list of jobs
+// GetJobs retrieves a paginated list of jobs.
+// With initiator (or initiator=me for the own jobs) only the jobs added by the initiator are retrieved,
+// the lastId is the ID of the last job initiator then. The same applies to test=true for the test runs of tasks.
 func (m *ManagerHandler) GetJobs(c *echo.Context) error {
-	lastIdStr := c.QueryParam("lastId")
-	limitStr := c.QueryParam("limit")
-
-	// Parse lastId with default
-	lastId := 0
-	if lastIdStr != "" {
-		parsedLastId, err := strconv.Atoi(lastIdStr)
-		if err != nil || parsedLastId < 0 {
-			return renderPopupOrJson(c, http.StatusBadRequest, "Invalid lastId format")
+	initiator := resolveInitiator(c, c.QueryParam("initiator"))
+	test := c.QueryParam("test") == "true"
+
+	lastId, limit, err := m.apiPagination(c)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+
+	filter, err := jobFilterFromQuery(c)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+
+	if withEnvelope(c) {
+		return m.getJobsPage(c, initiator, test, filter, lastId, limit)
+	}
+
+	var jobs []*model.Job
+	if test {
+		jobs, err = m.testJobs(lastId, limit)
+	} else if initiator != "" {
+		jobs, err = m.jobsByInitiator(initiator, lastId, limit)
+	} else if filter.HasFilters() {
+		jobs, err = m.jobsByFilter(filter, lastId, limit)
+	} else {
+		jobs, err = m.Queuer.GetJobs(lastId, limit)
+	}
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, "Failed to retrieve jobs")
+	}
+
+	return renderPopupOrJson(c, http.StatusOK, jobs)
+}
+
+// getJobsPage returns the page envelope of the jobs, the jobs of an initiator or test runs are paginated by their job initiators.
+func (m *ManagerHandler) getJobsPage(c *echo.Context, initiator string, test bool, filter *qmModel.JobFilter, lastId int, limit int) error {
+	if test || initiator != "" {
+		page, err := m.jobInitiatorPage(initiator, test, lastId, limit)
+		if err != nil {
+			return renderPopupOrJson(c, http.StatusInternalServerError, "Failed to retrieve jobs")
 		}
-		lastId = parsedLastId
+		return renderPopupOrJson(c, http.StatusOK, page)
 	}
 
-	// Parse limit with default
-	limit := 10
-	if limitStr != "" {
-		parsedLimit, err := strconv.Atoi(limitStr)
-		if err != nil || parsedLimit <= 0 || parsedLimit > 100 {
-			return renderPopupOrJson(c, http.StatusBadRequest, "Invalid limit (must be 1-100)")
+	if filter.HasFilters() {
+		if m.JobDB == nil {
+			return renderPopupOrJson(c, http.StatusServiceUnavailable, "Job counts are not available")
+		}
+
+		jobs, err := m.jobsByFilter(filter, lastId, pageLimit(limit, true))
+		if err != nil {
+			return renderPopupOrJson(c, http.StatusInternalServerError, "Failed to retrieve jobs")
+		}
+		total, err := m.JobDB.SelectJobsCountByFilter(filter)
+		if err != nil {
+			return renderPopupOrJson(c, http.StatusInternalServerError, "Failed to count jobs")
 		}
-		limit = parsedLimit
+		return renderPopupOrJson(c, http.StatusOK, newPage(jobs, limit, total, func(job *model.Job) int { return job.ID }))
 	}
 
-	jobs, err := m.Queuer.GetJobs(lastId, limit)
+	if m.MetricDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Job counts are not available")
+	}
+
+	jobs, err := m.Queuer.GetJobs(lastId, pageLimit(limit, true))
 	if err != nil {
 		return renderPopupOrJson(c, http.StatusInternalServerError, "Failed to retrieve jobs")
 	}
+	total, err := m.MetricDB.SelectJobsCount()
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, "Failed to count jobs")
+	}
 
-	return renderPopupOrJson(c, http.StatusOK, jobs)
+	return renderPopupOrJson(c, http.StatusOK, newPage(jobs, limit, total, func(job *model.Job) int { return job.ID }))
+}
+
+// jobFilterFromQuery parses the filters of the job list from the query parameters search, status, taskKey
+// and the time range from and to (RFC3339) the jobs were created in, the namespace is described at requestNamespace.
+func jobFilterFromQuery(c *echo.Context) (*qmModel.JobFilter, error) {
+	filter := &qmModel.JobFilter{
+		Search:    c.QueryParam("search"),
+		Status:    c.QueryParam("status"),
+		TaskKey:   c.QueryParam("taskKey"),
+		Namespace: requestNamespace(c),
+	}
+
+	if filter.Status != "" && !slices.Contains(qmModel.JOB_ACTIVE_STATUSES, filter.Status) {
+		return nil, fmt.Errorf("Invalid status (must be one of %s)", strings.Join(qmModel.JOB_ACTIVE_STATUSES, ", "))
+	}
+
+	if fromStr := c.QueryParam("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid from format (must be RFC3339)")
+		}
+		filter.From = from
+	}
+
+	if toStr := c.QueryParam("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid to format (must be RFC3339)")
+		}
+		filter.To = to
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, fmt.Errorf("Invalid time range (from must be before to)")
+	}
+
+	return filter, nil
+}
+
+// jobsByFilter retrieves the jobs in the queue matching the filter, newest first.
+// lastId is the ID of the last job of the previous page, jobs that ended in the meantime are skipped.
+func (m *ManagerHandler) jobsByFilter(filter *qmModel.JobFilter, lastId int, limit int) ([]*model.Job, error) {
+	if m.JobDB == nil {
+		return nil, fmt.Errorf("job filters are not enabled")
+	}
+
+	rids, err := m.JobDB.SelectJobRIDsByFilter(filter, lastId, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := []*model.Job{}
+	for _, rid := range rids {
+		job, err := m.Queuer.GetJob(rid)
+		if err != nil {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
 }
 
 // CancelJob cancels a specific job by RID
@@ -121,6 +471,7 @@ func (m *ManagerHandler) CancelJob(c *echo.Context) error {
 	if err != nil {
 		return renderPopupOrJson(c, http.StatusInternalServerError, "Failed to cancel job")
 	}
+	m.publishEvent(qmModel.EVENT_JOB_CANCELLED, cancelledJob)
 
 	c.Response().Header().Add("HX-Redirect", "/jobArchive")
 
@@ -154,6 +505,7 @@ func (m *ManagerHandler) CancelJobs(c *echo.Context) error {
 			return renderPopupOrJson(c, http.StatusInternalServerError, "Failed to cancel jobs")
 		}
 		cancelledJobs = append(cancelledJobs, cancelledJob)
+		m.publishEvent(qmModel.EVENT_JOB_CANCELLED, cancelledJob)
 	}
 
 	c.Response().Header().Add("HX-Redirect", "/jobArchive")
@@ -161,6 +513,55 @@ func (m *ManagerHandler) CancelJobs(c *echo.Context) error {
 	return renderPopupOrJson(c, http.StatusOK, fmt.Sprintf("%v jobs cancelled successfully", len(cancelledJobs)))
 }
 
+// KillJob hard kills a running job. The job is cancelled like with CancelJob and the worker running it
+// is stopped immediately without waiting for its jobs, so a task ignoring the cancellation of its context
+// can not keep running. Other jobs of the worker are cancelled as well.
+func (m *ManagerHandler) KillJob(c *echo.Context) error {
+	rid, err := uuid.Parse(c.Param("rid"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid job RID format")
+	}
+
+	job, err := m.Queuer.GetJob(rid)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "Job not found")
+	}
+	if job.Status != model.JobStatusRunning || job.WorkerRID == uuid.Nil {
+		return renderPopupOrJson(c, http.StatusConflict, "Only running jobs can be killed, cancel the job instead")
+	}
+
+	cancelledJob, err := m.Queuer.CancelJob(rid)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, "Failed to cancel job")
+	}
+	err = m.Queuer.StopWorker(job.WorkerRID)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Job cancelled, but failed to stop worker %s: %v", job.WorkerRID, err))
+	}
+
+	jobKill := &qmModel.JobKill{
+		JobRID:      rid,
+		WorkerRID:   job.WorkerRID,
+		RequestedBy: requestedBy(c),
+	}
+	if m.JobKillDB != nil {
+		insertedJobKill, err := m.JobKillDB.InsertJobKill(jobKill)
+		if err != nil {
+			log.Printf("Error recording kill of job %s: %v", rid, err)
+		} else {
+			jobKill = insertedJobKill
+		}
+	}
+	m.publishEvent(qmModel.EVENT_JOB_KILLED, cancelledJob)
+
+	if c.Request().Header.Get("HX-Request") != "" {
+		c.Response().Header().Add("HX-Redirect", fmt.Sprintf("/job?rid=%s", rid.String()))
+		return renderPopupOrJson(c, http.StatusOK, fmt.Sprintf("Job killed and worker %s stopped", job.WorkerRID))
+	}
+
+	return c.JSON(http.StatusOK, jobKill)
+}
+
 // DeleteJob deletes a specific job by RID
 func (m *ManagerHandler) DeleteJob(c *echo.Context) error {
 	ridStr := c.Param("rid")
@@ -173,6 +574,7 @@ func (m *ManagerHandler) DeleteJob(c *echo.Context) error {
 	if err != nil {
 		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to delete job: %v", err))
 	}
+	m.publishEvent(qmModel.EVENT_JOB_DELETED, map[string]any{"rid": rid})
 
 	// TODO add loader on trigger
 	c.Response().Header().Add("HX-Trigger-After-Settle", "reloadJobArchive")
@@ -213,35 +615,89 @@ func (m *ManagerHandler) JobView(c *echo.Context) error {
 	return render(c, screens.Job(job), status)
 }
 
+// JobTabView renders a tab panel of the job view, the panels are loaded when their tab is opened.
+func (m *ManagerHandler) JobTabView(c *echo.Context) error {
+	rid, err := uuid.Parse(c.QueryParam("rid"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid job RID format")
+	}
+
+	job, err := m.Queuer.GetJob(rid)
+	if err != nil {
+		job, err = m.Queuer.GetJobEnded(rid)
+		if err != nil {
+			return renderPopupOrJson(c, http.StatusNotFound, "Job not found")
+		}
+	}
+
+	// Show if a cancelled job was hard killed
+	var jobKill *qmModel.JobKill
+	if job.Status == model.JobStatusCancelled && m.JobKillDB != nil {
+		jobKill, _ = m.JobKillDB.SelectJobKill(rid)
+	}
+
+	// Show if the status of an ended job was overridden
+	var jobOverride *qmModel.JobOverride
+	if slices.Contains(jobOverrideStatuses, job.Status) && m.JobOverrideDB != nil {
+		jobOverride, _ = m.JobOverrideDB.SelectJobOverride(rid)
+	}
+
+	switch c.Param("tab") {
+	case "overview":
+		return render(c, screens.JobOverview(job, jobKill))
+	case "parameters":
+		return render(c, screens.JobParameters(job))
+	case "result":
+		return render(c, screens.JobResult(job, jobKill))
+	case "audit":
+		owner, initiator := m.jobOwnership(job)
+		return render(c, screens.JobAudit(job, jobKill, jobOverride, owner, initiator))
+	case "timeline":
+		timeline, err := m.jobTimeline(job, jobEnded(job))
+		if err != nil {
+			return renderPopupOrJson(c, http.StatusInternalServerError, "Failed to get job timeline: "+err.Error())
+		}
+		return render(c, screens.JobTimeline(timeline))
+	case "raw":
+		return render(c, components.JsonCodeView(job))
+	default:
+		return renderPopupOrJson(c, http.StatusNotFound, "Tab not found")
+	}
+}
+
 // JobsView renders the jobs view
 func (m *ManagerHandler) JobsView(c *echo.Context) error {
-	lastIdStr := c.QueryParam("lastId")
-	limitStr := c.QueryParam("limit")
 	search := c.QueryParam("search")
+	initiator := c.QueryParam("initiator")
+	test := c.QueryParam("test") == "true"
 
-	// Parse lastId with default
-	lastId := 0
-	if lastIdStr != "" {
-		parsedLastId, err := strconv.Atoi(lastIdStr)
-		if err != nil || parsedLastId < 0 {
-			return c.String(http.StatusBadRequest, "Invalid lastId format")
-		}
-		lastId = parsedLastId
+	lastId, limit, err := m.viewPagination(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
 	}
 
-	// Parse limit with default
-	limit := 100
-	if limitStr != "" {
-		parsedLimit, err := strconv.Atoi(limitStr)
-		if err != nil || parsedLimit <= 0 || parsedLimit > 100 {
-			return c.String(http.StatusBadRequest, "Invalid limit (must be 1-100)")
-		}
-		limit = parsedLimit
+	filter, err := jobFilterFromQuery(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
 	}
 
 	var jobs []*model.Job
-	var err error
-	if search != "" {
+	if test {
+		jobs, err = m.testJobs(lastId, limit)
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to retrieve jobs")
+		}
+	} else if initiator != "" {
+		jobs, err = m.jobsByInitiator(resolveInitiator(c, initiator), lastId, limit)
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to retrieve jobs")
+		}
+	} else if filter.HasFilters() {
+		jobs, err = m.jobsByFilter(filter, lastId, limit)
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to filter jobs")
+		}
+	} else if search != "" {
 		log.Printf("searching for: %v", search)
 		jobs, err = m.Queuer.GetJobsBySearch(search, lastId, limit)
 		if err != nil {
@@ -255,8 +711,13 @@ func (m *ManagerHandler) JobsView(c *echo.Context) error {
 		}
 	}
 
-	c.Response().Header().Add("HX-Push-Url", fmt.Sprintf("/jobs?search=%s&limit=%d&lastId=%d", search, limit, lastId))
+	filterQuery := filter.Query()
+	filterQuery.Del("search")
+	c.Response().Header().Add("HX-Push-Url", fmt.Sprintf("/jobs?search=%s&initiator=%s&test=%t&limit=%d&lastId=%d&%s", search, initiator, test, limit, lastId, filterQuery.Encode()))
 	c.Response().Header().Add("HX-Retarget", "#body")
 
-	return render(c, screens.Jobs(jobs, search))
+	// New jobs are streamed into the first page of all jobs only, the rows of the other views are only updated
+	streamNewJobs := search == "" && initiator == "" && !test && !filter.HasFilters() && lastId == 0
+
+	return renderStream(c, screens.Jobs(jobs, m.jobPriorities(jobs), filter, streamNewJobs))
 }
@@ -3,8 +3,13 @@ package handler
 import (
 	"fmt"
 	"net/http"
-	"strconv"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
 
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/components"
 	"github.com/siherrmann/queuerManager/view/screens"
 
 	"github.com/google/uuid"
@@ -12,6 +17,9 @@ import (
 	"github.com/siherrmann/queuer/model"
 )
 
+// jobHistoryLimit is the number of archived jobs shown in the history tabs of the detail views
+const jobHistoryLimit = 20
+
 // GetJobArchive retrieves a specific archived job by RID
 func (m *ManagerHandler) GetJobArchive(c *echo.Context) error {
 	ridStr := c.Param("rid")
@@ -28,70 +36,177 @@ func (m *ManagerHandler) GetJobArchive(c *echo.Context) error {
 	return c.JSON(http.StatusOK, job)
 }
 
-// GetJobsArchive retrieves a paginated list of archived jobs
+// GetJobsArchive retrieves a paginated list of archived jobs, optionally filtered by status, task, worker and duration
 func (m *ManagerHandler) GetJobsArchive(c *echo.Context) error {
-	lastIdStr := c.QueryParam("lastId")
-	limitStr := c.QueryParam("limit")
-
-	// Parse lastId with default
-	lastId := 0
-	if lastIdStr != "" {
-		parsedLastId, err := strconv.Atoi(lastIdStr)
-		if err != nil || parsedLastId < 0 {
-			return c.String(http.StatusBadRequest, "Invalid lastId format")
+	lastId, limit, err := m.apiPagination(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	filter, err := jobArchiveFilterFromQuery(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	envelope := withEnvelope(c)
+	if envelope && m.JobArchiveDB == nil {
+		return c.String(http.StatusServiceUnavailable, "Archived job counts are not available")
+	}
+
+	var jobArchives []*model.Job
+	if filter.HasFilters() {
+		jobArchives, err = m.jobsEndedByFilter(filter, lastId, pageLimit(limit, envelope))
+	} else {
+		jobArchives, err = m.Queuer.GetJobsEnded(lastId, pageLimit(limit, envelope))
+	}
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to retrieve archived jobs")
+	}
+	if !envelope {
+		return c.JSON(http.StatusOK, jobArchives)
+	}
+
+	total, err := m.JobArchiveDB.SelectJobsCountByFilter(filter)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to count archived jobs")
+	}
+
+	return c.JSON(http.StatusOK, newPage(jobArchives, limit, total, func(job *model.Job) int { return job.ID }))
+}
+
+// jobArchiveFilterFromQuery parses the filters of the job archive from the query parameters search, status, task,
+// worker (RID of the executing worker), the durations minDuration and maxDuration (eg. "30s" or "5m") and error (exact error message),
+// the namespace is described at requestNamespace.
+func jobArchiveFilterFromQuery(c *echo.Context) (*qmModel.JobArchiveFilter, error) {
+	filter := &qmModel.JobArchiveFilter{
+		Search:    c.QueryParam("search"),
+		Status:    c.QueryParam("status"),
+		TaskKey:   c.QueryParam("task"),
+		Namespace: requestNamespace(c),
+		Error:     c.QueryParam("error"),
+	}
+
+	if filter.Status != "" && !slices.Contains([]string{model.JobStatusSucceeded, model.JobStatusFailed, model.JobStatusCancelled}, filter.Status) {
+		return nil, fmt.Errorf("Invalid status (must be %s, %s or %s)", model.JobStatusSucceeded, model.JobStatusFailed, model.JobStatusCancelled)
+	}
+
+	if workerStr := c.QueryParam("worker"); workerStr != "" {
+		workerRID, err := uuid.Parse(workerStr)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid worker RID format")
 		}
-		lastId = parsedLastId
+		filter.WorkerRID = workerRID
 	}
 
-	// Parse limit with default
-	limit := 10
-	if limitStr != "" {
-		parsedLimit, err := strconv.Atoi(limitStr)
-		if err != nil || parsedLimit <= 0 || parsedLimit > 100 {
-			return c.String(http.StatusBadRequest, "Invalid limit (must be 1-100)")
+	if minDurationStr := c.QueryParam("minDuration"); minDurationStr != "" {
+		minDuration, err := time.ParseDuration(minDurationStr)
+		if err != nil || minDuration < 0 {
+			return nil, fmt.Errorf("Invalid minDuration (must be a duration like 30s)")
 		}
-		limit = parsedLimit
+		filter.MinDuration = minDuration
+	}
+
+	if maxDurationStr := c.QueryParam("maxDuration"); maxDurationStr != "" {
+		maxDuration, err := time.ParseDuration(maxDurationStr)
+		if err != nil || maxDuration < 0 {
+			return nil, fmt.Errorf("Invalid maxDuration (must be a duration like 5m)")
+		}
+		filter.MaxDuration = maxDuration
+	}
+
+	if filter.MaxDuration > 0 && filter.MaxDuration < filter.MinDuration {
+		return nil, fmt.Errorf("Invalid duration range (maxDuration is less than minDuration)")
 	}
 
-	jobArchives, err := m.Queuer.GetJobsEnded(lastId, limit)
+	return filter, nil
+}
+
+// jobArchiveFilterQuery returns the query parameters of the structured filters that are set, eg. to push the url.
+func jobArchiveFilterQuery(filter *qmModel.JobArchiveFilter) string {
+	query := ""
+	if filter.Status != "" {
+		query += "&status=" + url.QueryEscape(filter.Status)
+	}
+	if filter.TaskKey != "" {
+		query += "&task=" + url.QueryEscape(filter.TaskKey)
+	}
+	if filter.WorkerRID != uuid.Nil {
+		query += "&worker=" + filter.WorkerRID.String()
+	}
+	if filter.MinDuration > 0 {
+		query += "&minDuration=" + filter.MinDuration.String()
+	}
+	if filter.MaxDuration > 0 {
+		query += "&maxDuration=" + filter.MaxDuration.String()
+	}
+	if filter.Error != "" {
+		query += "&error=" + url.QueryEscape(filter.Error)
+	}
+	return query
+}
+
+// jobsEndedByFilter retrieves the archived jobs matching the filter, newest first.
+// lastId is the ID of the last archived job of the previous page.
+func (m *ManagerHandler) jobsEndedByFilter(filter *qmModel.JobArchiveFilter, lastId int, limit int) ([]*model.Job, error) {
+	if m.JobArchiveDB == nil {
+		return nil, fmt.Errorf("job archive filters are not enabled")
+	}
+
+	rids, err := m.JobArchiveDB.SelectJobRIDsByFilter(filter, lastId, limit)
 	if err != nil {
-		return c.String(http.StatusInternalServerError, "Failed to retrieve archived jobs")
+		return nil, err
+	}
+
+	jobs := []*model.Job{}
+	for _, rid := range rids {
+		job, err := m.Queuer.GetJobEnded(rid)
+		if err != nil {
+			continue
+		}
+		jobs = append(jobs, job)
 	}
 
-	return c.JSON(http.StatusOK, jobArchives)
+	return jobs, nil
+}
+
+// renderJobHistory renders the latest archived jobs matching the filter as the history tab of a detail view.
+func (m *ManagerHandler) renderJobHistory(c *echo.Context, filter *qmModel.JobArchiveFilter) error {
+	if m.JobArchiveDB == nil {
+		return render(c, components.TabEmpty("Job history is not available without the job archive filters"))
+	}
+
+	jobs, err := m.jobsEndedByFilter(filter, 0, jobHistoryLimit)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to retrieve job history: %v", err))
+	}
+
+	archiveURL := "/jobArchive?" + strings.TrimPrefix(jobArchiveFilterQuery(filter), "&")
+	return render(c, screens.JobHistory(jobs, archiveURL))
 }
 
 // ======View Handlers======
 
 // JobArchiveView renders the job archive view
 func (m *ManagerHandler) JobArchiveView(c *echo.Context) error {
-	lastIdStr := c.QueryParam("lastId")
-	limitStr := c.QueryParam("limit")
 	search := c.QueryParam("search")
 
-	// Parse lastId with default
-	lastId := 0
-	if lastIdStr != "" {
-		parsedLastId, err := strconv.Atoi(lastIdStr)
-		if err != nil || parsedLastId < 0 {
-			return c.String(http.StatusBadRequest, "Invalid lastId format")
-		}
-		lastId = parsedLastId
+	lastId, limit, err := m.viewPagination(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
 	}
 
-	// Parse limit with default
-	limit := 100
-	if limitStr != "" {
-		parsedLimit, err := strconv.Atoi(limitStr)
-		if err != nil || parsedLimit <= 0 || parsedLimit > 100 {
-			return c.String(http.StatusBadRequest, "Invalid limit (must be 1-100)")
-		}
-		limit = parsedLimit
+	filter, err := jobArchiveFilterFromQuery(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
 	}
 
 	var archivedJobs []*model.Job
-	var err error
-	if search != "" {
+	if filter.HasFilters() {
+		archivedJobs, err = m.jobsEndedByFilter(filter, lastId, limit)
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to filter archived jobs")
+		}
+	} else if search != "" {
 		archivedJobs, err = m.Queuer.GetJobsEndedBySearch(search, lastId, limit)
 		if err != nil {
 			return c.String(http.StatusInternalServerError, "Failed to search archived jobs")
@@ -103,10 +218,10 @@ func (m *ManagerHandler) JobArchiveView(c *echo.Context) error {
 		}
 	}
 
-	c.Response().Header().Add("HX-Push-Url", fmt.Sprintf("/jobArchive?search=%s&limit=%d&lastId=%d", search, limit, lastId))
+	c.Response().Header().Add("HX-Push-Url", fmt.Sprintf("/jobArchive?search=%s&limit=%d&lastId=%d%s", search, limit, lastId, jobArchiveFilterQuery(filter)))
 	c.Response().Header().Add("HX-Retarget", "#body")
 
-	return render(c, screens.JobArchive(archivedJobs, search))
+	return render(c, screens.JobArchive(archivedJobs, search, filter))
 }
 
 // ReaddJobFromArchiveView readds a job from the archive back to the queue
@@ -128,6 +243,7 @@ func (m *ManagerHandler) ReaddJobFromArchiveView(c *echo.Context) error {
 	if err != nil {
 		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to re-add job: %v", err))
 	}
+	m.publishEvent(qmModel.EVENT_JOB_READDED, readdedJob)
 
 	return renderPopupOrJson(c, http.StatusOK, fmt.Sprintf("Job %s re-added to queue", readdedJob.RID.String()))
 }
@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+	qmModel "github.com/siherrmann/queuerManager/model"
+)
+
+// jobArchiveExportPageSize is the number of archived jobs loaded at once while streaming the job export
+const jobArchiveExportPageSize = 100
+
+// jobArchiveExportHeader is the header of the CSV job export
+var jobArchiveExportHeader = []string{"rid", "task_name", "status", "status_name", "worker_rid", "attempts", "created_at", "started_at", "ended_at", "parameters", "parameters_keyed", "results", "error"}
+
+// jobArchiveExportFormats are the formats of the job export with their content type
+var jobArchiveExportFormats = map[string]string{
+	"csv":    "text/csv",
+	"json":   "application/json",
+	"ndjson": "application/x-ndjson",
+}
+
+// archiveTimeRange parses the time range of an export of archived jobs from the query parameters from and to.
+// Both are RFC3339 times, to defaults to now and from to 30 days before to.
+func archiveTimeRange(c *echo.Context) (time.Time, time.Time, error) {
+	to := time.Now()
+	if toStr := c.QueryParam("to"); toStr != "" {
+		parsedTo, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("Invalid to format (must be RFC3339)")
+		}
+		to = parsedTo
+	}
+
+	from := to.AddDate(0, 0, -30)
+	if fromStr := c.QueryParam("from"); fromStr != "" {
+		parsedFrom, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("Invalid from format (must be RFC3339)")
+		}
+		from = parsedFrom
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("Invalid time range (from must be before to)")
+	}
+	return from, to, nil
+}
+
+// jobArchiveExportWriter writes the archived jobs of an export in one of the export formats.
+type jobArchiveExportWriter struct {
+	format  string
+	writer  io.Writer
+	csv     *csv.Writer
+	written int
+}
+
+// newJobArchiveExportWriter creates a writer of the format and writes the start of the export.
+func newJobArchiveExportWriter(format string, writer io.Writer) (*jobArchiveExportWriter, error) {
+	exportWriter := &jobArchiveExportWriter{format: format, writer: writer}
+	switch format {
+	case "csv":
+		exportWriter.csv = csv.NewWriter(writer)
+		return exportWriter, exportWriter.csv.Write(jobArchiveExportHeader)
+	case "json":
+		_, err := io.WriteString(writer, "[")
+		return exportWriter, err
+	}
+	return exportWriter, nil
+}
+
+// Write writes an archived job, a CSV record with the parameters and results as JSON
+// or the job as JSON, separated by commas in a JSON array and by newlines in NDJSON.
+func (w *jobArchiveExportWriter) Write(job *model.Job) error {
+	defer func() { w.written++ }()
+
+	if w.format == "csv" {
+		return w.csv.Write(jobArchiveExportRecord(job))
+	}
+
+	jobJSON, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	switch {
+	case w.format == "ndjson":
+		jobJSON = append(jobJSON, '\n')
+	case w.written > 0:
+		jobJSON = append([]byte(","), jobJSON...)
+	}
+	_, err = w.writer.Write(jobJSON)
+	return err
+}
+
+// Flush writes buffered CSV records to the underlying writer.
+func (w *jobArchiveExportWriter) Flush() error {
+	if w.csv == nil {
+		return nil
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+// Close writes the end of the export.
+func (w *jobArchiveExportWriter) Close() error {
+	if w.format == "json" {
+		_, err := io.WriteString(w.writer, "]")
+		return err
+	}
+	return w.Flush()
+}
+
+// jobArchiveExportRecord returns the CSV record of an archived job.
+func jobArchiveExportRecord(job *model.Job) []string {
+	record := []string{
+		job.RID.String(),
+		job.TaskName,
+		job.Status,
+		qmModel.StatusName(job.Status),
+		"",
+		strconv.Itoa(job.Attempts),
+		job.CreatedAt.Format(time.RFC3339),
+		"",
+		job.UpdatedAt.Format(time.RFC3339),
+		taskResultValue([]any(job.Parameters)),
+		"",
+		taskResultValue([]any(job.Results)),
+		job.Error,
+	}
+	if job.WorkerRID != uuid.Nil {
+		record[4] = job.WorkerRID.String()
+	}
+	if job.StartedAt != nil && !job.StartedAt.IsZero() {
+		record[7] = job.StartedAt.Format(time.RFC3339)
+	}
+	if len(job.ParametersKeyed) > 0 {
+		record[10] = taskResultValue(map[string]any(job.ParametersKeyed))
+	}
+	return record
+}
+
+// ExportJobsArchive streams the archived jobs that ended in the time range with their parameters and results as download,
+// eg. /api/jobArchive/export?format=ndjson&from=&to=&taskKey=. format is csv (default), json or ndjson,
+// from and to are RFC3339 times (default the last 30 days) and taskKey optionally limits the export to the jobs of a task.
+func (m *ManagerHandler) ExportJobsArchive(c *echo.Context) error {
+	if m.JobArchiveDB == nil {
+		return c.String(http.StatusServiceUnavailable, "Job archive filters are not enabled")
+	}
+
+	from, to, err := archiveTimeRange(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	format := c.QueryParam("format")
+	if format == "" {
+		format = "csv"
+	}
+	contentType, ok := jobArchiveExportFormats[format]
+	if !ok {
+		return c.String(http.StatusBadRequest, "Invalid format (must be csv, json or ndjson)")
+	}
+
+	taskKey := c.QueryParam("taskKey")
+	name := taskKey
+	if name == "" {
+		name = "all"
+	}
+
+	filename := fmt.Sprintf("jobs_%s_%s_%s.%s", name, from.Format("20060102"), to.Format("20060102"), format)
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Response().Header().Set("Content-Type", contentType)
+	c.Response().WriteHeader(http.StatusOK)
+
+	writer, err := newJobArchiveExportWriter(format, c.Response())
+	if err != nil {
+		return err
+	}
+
+	// Stream the jobs page by page, the response is flushed after each page
+	responseController := http.NewResponseController(c.Response())
+	lastRID := uuid.Nil
+	for {
+		rids, err := m.JobArchiveDB.SelectJobRIDsByTask(taskKey, from, to, lastRID, jobArchiveExportPageSize)
+		if err != nil {
+			log.Printf("Error selecting archived jobs for export: %v", err)
+			return err
+		}
+
+		for _, jobRID := range rids {
+			job, err := m.Queuer.GetJobEnded(jobRID)
+			if err != nil {
+				log.Printf("Error getting archived job %s, skipping: %v", jobRID, err)
+				continue
+			}
+			if err := writer.Write(job); err != nil {
+				return err
+			}
+		}
+
+		if len(rids) < jobArchiveExportPageSize {
+			return writer.Close()
+		}
+
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+		if err := responseController.Flush(); err != nil {
+			return err
+		}
+		lastRID = rids[len(rids)-1]
+	}
+}
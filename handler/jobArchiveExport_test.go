@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveTimeRange(t *testing.T) {
+	e := echo.New()
+	timeRange := func(query string) (time.Time, time.Time, error) {
+		req := httptest.NewRequest(http.MethodGet, "/api/jobArchive/export?"+query, nil)
+		return archiveTimeRange(e.NewContext(req, httptest.NewRecorder()))
+	}
+
+	from, to, err := timeRange("to=2026-02-01T00:00:00Z")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), from, "Expected from to default to 30 days before to")
+	assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), to)
+
+	_, _, err = timeRange("from=yesterday")
+	assert.EqualError(t, err, "Invalid from format (must be RFC3339)")
+
+	_, _, err = timeRange("from=2026-02-01T00:00:00Z&to=2026-01-01T00:00:00Z")
+	assert.EqualError(t, err, "Invalid time range (from must be before to)")
+}
+
+func TestJobArchiveExportWriter(t *testing.T) {
+	startedAt := time.Date(2026, 1, 1, 10, 0, 5, 0, time.UTC)
+	jobs := []*model.Job{
+		{
+			RID:        uuid.New(),
+			TaskName:   "export",
+			Status:     model.JobStatusSucceeded,
+			WorkerRID:  uuid.New(),
+			Attempts:   1,
+			Parameters: model.Parameters{"report.csv", float64(3)},
+			Results:    model.Parameters{map[string]any{"rows": float64(42)}},
+			StartedAt:  &startedAt,
+			CreatedAt:  time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC),
+			UpdatedAt:  time.Date(2026, 1, 1, 10, 1, 0, 0, time.UTC),
+		},
+		{
+			RID:       uuid.New(),
+			TaskName:  "export",
+			Status:    model.JobStatusFailed,
+			Error:     "timeout",
+			CreatedAt: time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC),
+			UpdatedAt: time.Date(2026, 1, 1, 11, 1, 0, 0, time.UTC),
+		},
+	}
+
+	export := func(format string) string {
+		buffer := &bytes.Buffer{}
+		writer, err := newJobArchiveExportWriter(format, buffer)
+		require.NoError(t, err)
+		for _, job := range jobs {
+			require.NoError(t, writer.Write(job))
+		}
+		require.NoError(t, writer.Close())
+		return buffer.String()
+	}
+
+	t.Run("CSV", func(t *testing.T) {
+		lines := strings.Split(strings.TrimSpace(export("csv")), "\n")
+		require.Len(t, lines, 3)
+		assert.Equal(t, strings.Join(jobArchiveExportHeader, ","), lines[0])
+		assert.Equal(t, jobs[0].RID.String()+",export,SUCCEEDED,SUCCEEDED,"+jobs[0].WorkerRID.String()+`,1,2026-01-01T10:00:00Z,2026-01-01T10:00:05Z,2026-01-01T10:01:00Z,"[""report.csv"",3]",,"[{""rows"":42}]",`, lines[1])
+		assert.Equal(t, jobs[1].RID.String()+",export,FAILED,FAILED,,0,2026-01-01T11:00:00Z,,2026-01-01T11:01:00Z,null,,null,timeout", lines[2])
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		exported := []*model.Job{}
+		require.NoError(t, json.Unmarshal([]byte(export("json")), &exported))
+		require.Len(t, exported, 2)
+		assert.Equal(t, jobs[0].RID, exported[0].RID)
+		assert.Equal(t, jobs[0].Results, exported[0].Results)
+		assert.Equal(t, "timeout", exported[1].Error)
+
+		empty := &bytes.Buffer{}
+		writer, err := newJobArchiveExportWriter("json", empty)
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+		assert.Equal(t, "[]", empty.String())
+	})
+
+	t.Run("NDJSON", func(t *testing.T) {
+		lines := strings.Split(strings.TrimSpace(export("ndjson")), "\n")
+		require.Len(t, lines, 2)
+		for i, line := range lines {
+			job := &model.Job{}
+			require.NoError(t, json.Unmarshal([]byte(line), job))
+			assert.Equal(t, jobs[i].RID, job.RID)
+		}
+	})
+}
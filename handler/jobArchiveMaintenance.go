@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/labstack/echo/v5"
+)
+
+// maintenanceQueryIndexes are the common query patterns of the manager on the job tables of the queuer
+// with the leading columns of the index covering them.
+var maintenanceQueryIndexes = []*model.IndexAdvice{
+	{Table: "job", Columns: []string{"created_at"}, Query: "Job list pages, newest first"},
+	{Table: "job", Columns: []string{"status"}, Query: "Job list status filter and queue metrics"},
+	{Table: "job", Columns: []string{"task_name"}, Query: "Job list task filter"},
+	{Table: "job_archive", Columns: []string{"created_at"}, Query: "Job archive pages, newest first"},
+	{Table: "job_archive", Columns: []string{"updated_at"}, Query: "Job archive export and ended job metrics"},
+	{Table: "job_archive", Columns: []string{"task_name", "updated_at"}, Query: "Task results export and task history"},
+	{Table: "job_archive", Columns: []string{"status"}, Query: "Job archive status filter"},
+	{Table: "job_archive", Columns: []string{"worker_rid"}, Query: "Worker history"},
+}
+
+// indexAdvice returns the common query patterns with the existing index covering them,
+// an index covers a pattern if it starts with the columns of the pattern.
+// Missing indexes get the statement to create them without blocking writes.
+func indexAdvice(indexes []*model.TableIndex) []*model.IndexAdvice {
+	advices := []*model.IndexAdvice{}
+	for _, pattern := range maintenanceQueryIndexes {
+		advice := *pattern
+		for _, index := range indexes {
+			if index.Table == pattern.Table && len(index.Columns) >= len(pattern.Columns) && slices.Equal(index.Columns[:len(pattern.Columns)], pattern.Columns) {
+				advice.Index = index.Name
+				break
+			}
+		}
+		if advice.Index == "" {
+			advice.Statement = fmt.Sprintf(
+				"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_%s_%s ON %s (%s);",
+				pattern.Table,
+				strings.Join(pattern.Columns, "_"),
+				pattern.Table,
+				strings.Join(pattern.Columns, ", "),
+			)
+		}
+		advices = append(advices, &advice)
+	}
+	return advices
+}
+
+// jobArchiveMaintenance builds the report of the partitions of the job archive and the indexes of the common queries.
+func (m *ManagerHandler) jobArchiveMaintenance() (*model.JobArchiveMaintenance, error) {
+	partitionKey, err := m.JobArchiveDB.SelectPartitionKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve partition key: %v", err)
+	}
+
+	partitions, err := m.JobArchiveDB.SelectPartitions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve partitions: %v", err)
+	}
+
+	indexes, err := m.JobArchiveDB.SelectTableIndexes([]string{"job", "job_archive"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve indexes: %v", err)
+	}
+
+	return &model.JobArchiveMaintenance{
+		Partitioned:  partitionKey != "",
+		PartitionKey: partitionKey,
+		Partitions:   partitions,
+		Indexes:      indexAdvice(indexes),
+	}, nil
+}
+
+// =======API Handlers=======
+
+// GetJobArchiveMaintenance returns the partitions of the job archive and the indexes of the common queries
+func (m *ManagerHandler) GetJobArchiveMaintenance(c *echo.Context) error {
+	if m.JobArchiveDB == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "Job archive filters are not enabled"})
+	}
+
+	maintenance, err := m.jobArchiveMaintenance()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, maintenance)
+}
+
+// CreateJobArchivePartition creates the monthly partition of the job archive, eg. /api/jobArchive/createPartition?month=2026-01.
+// Without month the partition of the next month is created.
+func (m *ManagerHandler) CreateJobArchivePartition(c *echo.Context) error {
+	if m.JobArchiveDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Job archive filters are not enabled")
+	}
+
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	if monthStr := c.QueryParam("month"); monthStr != "" {
+		parsedMonth, err := time.Parse("2006-01", monthStr)
+		if err != nil {
+			return renderPopupOrJson(c, http.StatusBadRequest, "Invalid month format (must be YYYY-MM)")
+		}
+		month = parsedMonth
+	}
+
+	partitionKey, err := m.JobArchiveDB.SelectPartitionKey()
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to retrieve partition key: %v", err))
+	}
+	if !strings.HasPrefix(partitionKey, "RANGE") {
+		return renderPopupOrJson(c, http.StatusConflict, "The job archive is not range partitioned")
+	}
+
+	partition, err := m.JobArchiveDB.CreateMonthlyPartition(month)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to create partition: %v", err))
+	}
+
+	c.Response().Header().Add("HX-Trigger-After-Settle", "reloadJobArchiveMaintenance")
+
+	return renderPopupOrJson(c, http.StatusOK, fmt.Sprintf("Partition %s created", partition.Name))
+}
+
+// DropJobArchivePartition drops a monthly partition of the job archive with its archived jobs, eg. /api/jobArchive/dropPartition?name=job_archive_2026_01
+func (m *ManagerHandler) DropJobArchivePartition(c *echo.Context) error {
+	if m.JobArchiveDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Job archive filters are not enabled")
+	}
+
+	name := c.QueryParam("name")
+	err := m.JobArchiveDB.DropPartition(name)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Failed to drop partition: %v", err))
+	}
+
+	c.Response().Header().Add("HX-Trigger-After-Settle", "closeDropPartition, reloadJobArchiveMaintenance")
+
+	return renderPopupOrJson(c, http.StatusOK, fmt.Sprintf("Partition %s dropped", name))
+}
+
+// =======View Handlers=======
+
+// JobArchiveMaintenanceView renders the partitions of the job archive and the index report
+func (m *ManagerHandler) JobArchiveMaintenanceView(c *echo.Context) error {
+	if m.JobArchiveDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Job archive filters are not enabled")
+	}
+
+	maintenance, err := m.jobArchiveMaintenance()
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, err.Error())
+	}
+
+	c.Response().Header().Add("HX-Push-Url", "/jobArchive/maintenance")
+	c.Response().Header().Add("HX-Retarget", "#body")
+
+	return render(c, screens.JobArchiveMaintenance(maintenance))
+}
+
+// DropJobArchivePartitionPopupView renders the drop partition popup
+func (m *ManagerHandler) DropJobArchivePartitionPopupView(c *echo.Context) error {
+	return renderPopup(c, screens.DropJobArchivePartitionPopup(c.QueryParam("name")))
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexAdvice(t *testing.T) {
+	indexes := []*model.TableIndex{
+		{Table: "job", Name: "job_pkey", Columns: []string{"id"}},
+		{Table: "job", Name: "idx_job_status", Columns: []string{"status", "created_at"}},
+		{Table: "job_archive", Name: "idx_job_archive_task", Columns: []string{"task_name"}},
+		{Table: "job_archive", Name: "idx_job_archive_created_at", Columns: []string{"created_at"}},
+	}
+
+	advices := indexAdvice(indexes)
+	require.Len(t, advices, len(maintenanceQueryIndexes))
+
+	byPattern := map[string]*model.IndexAdvice{}
+	for _, advice := range advices {
+		byPattern[advice.Table+":"+advice.Query] = advice
+	}
+
+	status := byPattern["job:Job list status filter and queue metrics"]
+	assert.Equal(t, "idx_job_status", status.Index, "Expected an index starting with the columns to cover the pattern")
+	assert.Empty(t, status.Statement)
+
+	archiveCreatedAt := byPattern["job_archive:Job archive pages, newest first"]
+	assert.Equal(t, "idx_job_archive_created_at", archiveCreatedAt.Index, "Expected the index of the same table")
+
+	createdAt := byPattern["job:Job list pages, newest first"]
+	assert.Empty(t, createdAt.Index, "Expected the index of another table to not cover the pattern")
+	assert.Equal(t, "CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_job_created_at ON job (created_at);", createdAt.Statement)
+
+	taskHistory := byPattern["job_archive:Task results export and task history"]
+	assert.Empty(t, taskHistory.Index, "Expected an index with only a prefix of the columns to not cover the pattern")
+	assert.Equal(t, "CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_job_archive_task_name_updated_at ON job_archive (task_name, updated_at);", taskHistory.Statement)
+
+	assert.Empty(t, maintenanceQueryIndexes[0].Index, "Expected the patterns to not be changed")
+}
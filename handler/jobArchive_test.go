@@ -199,6 +199,9 @@ func TestJobArchiveViewHandler(t *testing.T) {
 	require.NoError(t, err)
 
 	handler := NewManagerHandler(fs, tdb, queue)
+	jobArchiveDB, err := database.NewJobArchiveDBHandler(db)
+	require.NoError(t, err)
+	handler.JobArchiveDB = jobArchiveDB
 	e := echo.New()
 
 	t.Run("JobArchiveView renders successfully", func(t *testing.T) {
@@ -237,6 +240,48 @@ func TestJobArchiveViewHandler(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 		assert.Contains(t, rec.Body.String(), "Invalid lastId format")
 	})
+
+	t.Run("JobArchiveView with filters", func(t *testing.T) {
+		job, err := queue.AddJob("test-task-failing", nil, 1)
+		require.NoError(t, err)
+		queue.WaitForJobFinished(job.RID, 5*time.Second)
+
+		req := httptest.NewRequest(http.MethodGet, "/jobArchive?status=FAILED&task=test-task-failing&maxDuration=1m", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err = handler.JobArchiveView(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), job.RID.String())
+		assert.Contains(t, rec.Header().Get("HX-Push-Url"), "&status=FAILED&task=test-task-failing&maxDuration=1m0s")
+	})
+
+	t.Run("JobArchiveView with filters that match no job", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/jobArchive?worker="+uuid.New().String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.JobArchiveView(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Clear Filters")
+	})
+
+	t.Run("JobArchiveView with invalid filters", func(t *testing.T) {
+		for _, query := range []string{"status=RUNNING", "worker=invalid", "minDuration=soon", "minDuration=5m&maxDuration=1m"} {
+			req := httptest.NewRequest(http.MethodGet, "/jobArchive?"+query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.JobArchiveView(c)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code, "Expected %s to be rejected", query)
+		}
+	})
 }
 
 func TestReaddJobFromArchiveViewHandler(t *testing.T) {
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+	vm "github.com/siherrmann/validator/model"
+)
+
+// registerJobChains registers the on success dependencies of the task for an added job.
+// Test runs do not chain downstream jobs. A failed register does not fail adding the job.
+func (m *ManagerHandler) registerJobChains(task *qmModel.Task, job *model.Job, test bool) {
+	if m.JobChainDB == nil || task == nil || job == nil || test || len(task.OnSuccess) == 0 {
+		return
+	}
+
+	_, err := m.JobChainDB.InsertJobChains(job.RID, task.Key, task.OnSuccess)
+	if err != nil {
+		log.Printf("Error registering chains of job %s: %v", job.RID, err)
+	}
+}
+
+// RunJobChains adds the jobs of the downstream tasks of an ended job without blocking the caller.
+// The chains of a job that did not succeed are skipped. Every manager instance is notified about
+// ended jobs, the chains are claimed in the database so a downstream job is only added once.
+func (m *ManagerHandler) RunJobChains(job *model.Job) {
+	if m.JobChainDB == nil || job == nil {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if job.Status != model.JobStatusSucceeded {
+			_, err := m.JobChainDB.SkipJobChains(job.RID, fmt.Sprintf("Job ended with status %s", job.Status))
+			if err != nil {
+				log.Printf("Error skipping chains of job %s: %v", job.RID, err)
+			}
+			return
+		}
+
+		jobChains, err := m.JobChainDB.ClaimJobChains(job.RID)
+		if err != nil {
+			log.Printf("Error claiming chains of job %s: %v", job.RID, err)
+			return
+		}
+		if len(jobChains) == 0 {
+			return
+		}
+
+		// The notified job is not decrypted, the results are read from the archive
+		results := job.Results
+		if endedJob, err := m.Queuer.GetJobEnded(job.RID); err == nil {
+			results = endedJob.Results
+		}
+		outputParameters := []vm.Validation{}
+		if task, err := m.taskDB.SelectTaskByKey(job.TaskName); err == nil {
+			outputParameters = task.OutputParameters
+		}
+
+		for _, jobChain := range jobChains {
+			status := qmModel.JOB_CHAIN_STATUS_ENQUEUED
+			errorMessage := ""
+			var downstreamJobRID *uuid.UUID
+
+			downstreamJob, err := m.runJobChain(ctx, jobChain, outputParameters, results)
+			if err != nil {
+				status = qmModel.JOB_CHAIN_STATUS_FAILED
+				errorMessage = err.Error()
+				log.Printf("Error adding chained job of task %s for job %s: %v", jobChain.DownstreamTaskKey, job.RID, err)
+			} else {
+				downstreamJobRID = &downstreamJob.RID
+			}
+
+			err = m.JobChainDB.UpdateJobChainResult(jobChain.ID, downstreamJobRID, status, errorMessage)
+			if err != nil {
+				log.Printf("Error recording chain %d of job %s: %v", jobChain.ID, job.RID, err)
+			}
+		}
+	}()
+}
+
+// runJobChain adds the job of the downstream task of a claimed chain with the mapped results of the ended job.
+func (m *ManagerHandler) runJobChain(ctx context.Context, jobChain *qmModel.JobChain, outputParameters []vm.Validation, results []any) (*model.Job, error) {
+	task, err := m.taskDB.SelectTaskByKey(jobChain.DownstreamTaskKey)
+	if err != nil {
+		return nil, fmt.Errorf("task %s not found", jobChain.DownstreamTaskKey)
+	}
+	if m.Forwarder.Rule(task.Key) != nil {
+		return nil, fmt.Errorf("task %s is forwarded, forwarded tasks can not be chained", task.Key)
+	}
+
+	parameters, err := jobChainParameters(outputParameters, results, jobChain.ParameterMapping)
+	if err != nil {
+		return nil, err
+	}
+
+	initiator := qmModel.JOB_CHAIN_INITIATOR_PREFIX + jobChain.TaskKey
+	parametersList, parametersKeyed, err := m.resolveScheduleParameters(ctx, task, parameters, initiator)
+	if err != nil {
+		return nil, fmt.Errorf("validation error: %v", err)
+	}
+
+	versionWarning, block, err := m.checkWorkerVersion(task)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check worker version: %v", err)
+	}
+	if block {
+		return nil, fmt.Errorf("%s", versionWarning)
+	}
+
+	jobAdded, deduplicated, err := m.addTaskJob(task, parametersList, parametersKeyed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add job: %v", err)
+	}
+	if !deduplicated {
+		m.recordJobInitiator(jobAdded, initiator, false)
+		m.registerJobChains(task, jobAdded, false)
+		m.publishEvent(qmModel.EVENT_JOB_ADDED, jobAdded)
+	}
+
+	return jobAdded, nil
+}
+
+// jobChainParameters maps the results of an ended job to the input parameters of the downstream job.
+// The results are in the order of the output parameters of the upstream task.
+func jobChainParameters(outputParameters []vm.Validation, results []any, parameterMapping map[string]string) (map[string]any, error) {
+	parameters := map[string]any{}
+	for inputKey, outputKey := range parameterMapping {
+		index := slices.IndexFunc(outputParameters, func(v vm.Validation) bool { return v.Key == outputKey })
+		if index < 0 {
+			return nil, fmt.Errorf("output parameter %q not found", outputKey)
+		}
+		if index >= len(results) {
+			return nil, fmt.Errorf("job has no result for output parameter %q", outputKey)
+		}
+		parameters[inputKey] = results[index]
+	}
+	return parameters, nil
+}
+
+// GetJobChains returns the chains registered for a job with the downstream jobs that were added.
+func (m *ManagerHandler) GetJobChains(c *echo.Context) error {
+	if m.JobChainDB == nil {
+		return c.JSON(http.StatusOK, []*qmModel.JobChain{})
+	}
+
+	rid, err := uuid.Parse(c.Param("rid"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid job RID format"})
+	}
+
+	jobChains, err := m.JobChainDB.SelectJobChainsByJob(rid)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, jobChains)
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"testing"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+
+	vm "github.com/siherrmann/validator/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobChainParameters(t *testing.T) {
+	outputParameters := []vm.Validation{{Key: "output_file"}, {Key: "rows"}}
+	results := []any{"export.csv", float64(42)}
+
+	t.Run("Maps the results to the input parameters", func(t *testing.T) {
+		parameters, err := jobChainParameters(outputParameters, results, map[string]string{"input_file": "output_file", "count": "rows"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"input_file": "export.csv", "count": float64(42)}, parameters)
+	})
+
+	t.Run("Without mapping no parameters are passed", func(t *testing.T) {
+		parameters, err := jobChainParameters(outputParameters, results, nil)
+		require.NoError(t, err)
+		assert.Empty(t, parameters)
+	})
+
+	t.Run("Unknown output parameter", func(t *testing.T) {
+		_, err := jobChainParameters(outputParameters, results, map[string]string{"input_file": "missing"})
+		assert.EqualError(t, err, `output parameter "missing" not found`)
+	})
+
+	t.Run("Missing result", func(t *testing.T) {
+		_, err := jobChainParameters(outputParameters, results[:1], map[string]string{"count": "rows"})
+		assert.EqualError(t, err, `job has no result for output parameter "rows"`)
+	})
+}
+
+func TestTaskDependenciesFromRequest(t *testing.T) {
+	dependencies, err := taskDependenciesFromRequest("  ")
+	require.NoError(t, err)
+	assert.Empty(t, dependencies)
+
+	dependencies, err = taskDependenciesFromRequest(`[{"task_key": "report", "parameter_mapping": {"input_file": "output_file"}}]`)
+	require.NoError(t, err)
+	assert.Equal(t, []qmModel.TaskDependency{{TaskKey: "report", ParameterMapping: map[string]string{"input_file": "output_file"}}}, dependencies)
+
+	_, err = taskDependenciesFromRequest(`{"task_key": "report"}`)
+	assert.ErrorContains(t, err, "Invalid on_success JSON")
+}
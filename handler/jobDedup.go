@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/model"
+)
+
+// HEADER_JOB_DEDUPLICATED is set on the response if an existing job was returned instead of adding a new one
+const HEADER_JOB_DEDUPLICATED = "X-Job-Deduplicated"
+
+// validateDedupWindow checks the dedup window of a task.
+func validateDedupWindow(task *qmModel.Task) error {
+	if task.DedupWindowMinutes < 0 || task.DedupWindowMinutes > qmModel.MAX_DEDUP_WINDOW_MINUTES {
+		return fmt.Errorf("invalid dedup_window_minutes (must be between 0 and %d)", qmModel.MAX_DEDUP_WINDOW_MINUTES)
+	}
+	return nil
+}
+
+// jobParametersHash returns the SHA-256 hash of the JSON of the job parameters.
+func jobParametersHash(parametersList []any, parametersKeyed map[string]any) (string, error) {
+	parametersJSON, err := json.Marshal(map[string]any{
+		"parameters":       parametersList,
+		"parameters_keyed": parametersKeyed,
+	})
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(parametersJSON)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// addTaskJob adds a job of the task and reports if an existing job was returned instead.
+// If the task has a dedup window and a job with the same parameters was added within it,
+// that job is returned as long as it did not fail or was cancelled.
+// A failed dedup lookup or record does not fail adding the job.
+// A new job is not added if it would exceed the max concurrency or the rate limit per minute of the task.
+// The files of the file parameters of a new job are associated to it for the pruning of the orphaned files.
+func (m *ManagerHandler) addTaskJob(task *qmModel.Task, parametersList []any, parametersKeyed map[string]any) (*model.Job, bool, error) {
+	return m.addTaskJobWithOptions(task, nil, nil, parametersList, parametersKeyed)
+}
+
+// addTaskJobWithOptions adds a job of the task with the options and the priority like addTaskJob, eg. scheduled with jobScheduleOptions.
+// Without options the job is added with the default options of the queuer, without priority with the default priority.
+func (m *ManagerHandler) addTaskJobWithOptions(task *qmModel.Task, options *model.Options, jobPriority *qmModel.JobPriority, parametersList []any, parametersKeyed map[string]any) (*model.Job, bool, error) {
+	if task.DedupWindowMinutes <= 0 || m.JobDedupDB == nil {
+		err := m.checkTaskLimits(task, 1)
+		if err != nil {
+			return nil, false, err
+		}
+		jobAdded, err := m.addQueuerJob(task, options, jobPriority, parametersList, parametersKeyed)
+		if err != nil {
+			return nil, false, err
+		}
+		m.recordJobFiles(task, jobAdded)
+		return jobAdded, false, nil
+	}
+
+	parametersHash, err := jobParametersHash(parametersList, parametersKeyed)
+	if err != nil {
+		return nil, false, fmt.Errorf("error hashing job parameters: %v", err)
+	}
+
+	since := time.Now().Add(-time.Duration(task.DedupWindowMinutes) * time.Minute)
+	if jobDedup, err := m.JobDedupDB.SelectJobDedup(task.Key, parametersHash, since); err == nil {
+		job, _, err := m.jobWithEnded(jobDedup.JobRID)
+		if err == nil && job.Status != model.JobStatusFailed && job.Status != model.JobStatusCancelled {
+			return job, true, nil
+		}
+	}
+
+	err = m.checkTaskLimits(task, 1)
+	if err != nil {
+		return nil, false, err
+	}
+	jobAdded, err := m.addQueuerJob(task, options, jobPriority, parametersList, parametersKeyed)
+	if err != nil {
+		return nil, false, err
+	}
+	m.recordJobFiles(task, jobAdded)
+
+	_, err = m.JobDedupDB.UpsertJobDedup(&qmModel.JobDedup{
+		TaskKey:        task.Key,
+		ParametersHash: parametersHash,
+		JobRID:         jobAdded.RID,
+	})
+	if err != nil {
+		log.Printf("Error recording dedup of job %s: %v", jobAdded.RID, err)
+	}
+
+	return jobAdded, false, nil
+}
+
+// addQueuerJob adds the job to the queue, with the options if the job has options.
+// A job with a priority other than the default is added in one transaction with its priority,
+// so no worker claims the job before its priority is stored.
+func (m *ManagerHandler) addQueuerJob(task *qmModel.Task, options *model.Options, jobPriority *qmModel.JobPriority, parametersList []any, parametersKeyed map[string]any) (*model.Job, error) {
+	if jobPriority == nil || jobPriority.Priority == qmModel.JOB_PRIORITY_DEFAULT {
+		if options == nil {
+			return m.Queuer.AddJob(task.Key, parametersKeyed, parametersList...)
+		}
+		return m.Queuer.AddJobWithOptions(options, task.Key, parametersKeyed, parametersList...)
+	}
+	if m.SchedulerDB == nil || m.QueuerDB == nil {
+		return nil, fmt.Errorf("job priorities are not configured")
+	}
+
+	tx, err := m.QueuerDB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error beginning transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var jobAdded *model.Job
+	if options == nil {
+		jobAdded, err = m.Queuer.AddJobTx(tx, task.Key, parametersKeyed, parametersList...)
+	} else {
+		jobAdded, err = m.Queuer.AddJobWithOptionsTx(tx, options, task.Key, parametersKeyed, parametersList...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = m.SchedulerDB.UpsertJobPriorityTx(tx, &qmModel.JobPriority{
+		JobRID:    jobAdded.RID,
+		Priority:  jobPriority.Priority,
+		UpdatedBy: jobPriority.UpdatedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error storing job priority: %v", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("error committing job: %v", err)
+	}
+
+	return jobAdded, nil
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuer/model"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	vm "github.com/siherrmann/validator/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobParametersHash(t *testing.T) {
+	hash, err := jobParametersHash([]any{1, "a"}, map[string]any{"b": true})
+	require.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	sameHash, err := jobParametersHash([]any{1, "a"}, map[string]any{"b": true})
+	require.NoError(t, err)
+	assert.Equal(t, hash, sameHash, "Expected the same parameters to have the same hash")
+
+	otherHash, err := jobParametersHash([]any{2, "a"}, map[string]any{"b": true})
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, otherHash, "Expected other parameters to have another hash")
+}
+
+func TestValidateDedupWindow(t *testing.T) {
+	assert.NoError(t, validateDedupWindow(&qmModel.Task{}))
+	assert.NoError(t, validateDedupWindow(&qmModel.Task{DedupWindowMinutes: 30}))
+	assert.Error(t, validateDedupWindow(&qmModel.Task{DedupWindowMinutes: -1}))
+	assert.Error(t, validateDedupWindow(&qmModel.Task{DedupWindowMinutes: qmModel.MAX_DEDUP_WINDOW_MINUTES + 1}))
+}
+
+func TestJobDedupHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	jddb, err := database.NewJobDedupDBHandler(db, true)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.JobDedupDB = jddb
+	e := echo.New()
+
+	task, err := tdb.InsertTask(&qmModel.Task{
+		Key:                  "test-dedup-job-task",
+		Name:                 "Test Dedup Job Task",
+		InputParameters:      []vm.Validation{{Key: "count", Type: vm.Int, Requirement: "min1"}},
+		InputParametersKeyed: []vm.Validation{},
+		DedupWindowMinutes:   10,
+	})
+	require.NoError(t, err)
+
+	createJob := func(count int) (*httptest.ResponseRecorder, *model.Job) {
+		body := fmt.Sprintf(`{"task_key": "%s", "parameters": {"count": %d}}`, task.Key, count)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.CreateJob(c)
+		require.NoError(t, err)
+
+		var job model.Job
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &job))
+		return rec, &job
+	}
+
+	t.Run("Same parameters within the window return the existing job", func(t *testing.T) {
+		rec, firstJob := createJob(3)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Empty(t, rec.Header().Get(HEADER_JOB_DEDUPLICATED))
+
+		rec, secondJob := createJob(3)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "true", rec.Header().Get(HEADER_JOB_DEDUPLICATED))
+		assert.Equal(t, firstJob.RID, secondJob.RID, "Expected the existing job to be returned")
+	})
+
+	t.Run("Other parameters add a new job", func(t *testing.T) {
+		_, firstJob := createJob(4)
+		rec, secondJob := createJob(5)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Empty(t, rec.Header().Get(HEADER_JOB_DEDUPLICATED))
+		assert.NotEqual(t, firstJob.RID, secondJob.RID)
+	})
+
+	t.Run("Cancelled job is not returned", func(t *testing.T) {
+		_, firstJob := createJob(6)
+		_, err := queue.CancelJob(firstJob.RID)
+		require.NoError(t, err)
+
+		rec, secondJob := createJob(6)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.NotEqual(t, firstJob.RID, secondJob.RID, "Expected a new job for a cancelled job")
+	})
+}
@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/labstack/echo/v5"
+)
+
+// jobImportMaxRows is the maximum number of rows of a bulk job submission
+const jobImportMaxRows = 10000
+
+// AddJobsBulk adds a job of the task for each row of the uploaded CSV or JSON file.
+// A CSV file has a header row with the parameter keys, a JSON file is an array of parameter objects.
+// Each row is validated against the input parameters of the task like a single added job,
+// invalid rows are skipped and reported with their error in the per-row report.
+func (m *ManagerHandler) AddJobsBulk(c *echo.Context) error {
+	taskKey := c.Param("taskKey")
+	task, err := m.taskDB.SelectTaskByKey(taskKey)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "Task not found")
+	}
+
+	// Forwarded jobs are added one by one on the remote instance
+	if m.Forwarder.Rule(task.Key) != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Bulk submission is not supported for forwarded tasks")
+	}
+
+	file, err := c.FormFile("job_file")
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "No file uploaded")
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, "Failed to open file")
+	}
+	defer src.Close()
+
+	var rowRequests []*http.Request
+	switch strings.ToLower(filepath.Ext(file.Filename)) {
+	case ".csv":
+		rowRequests, err = jobImportCSVRequests(src)
+	case ".json":
+		rowRequests, err = jobImportJSONRequests(src)
+	default:
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid file type (must be .csv or .json)")
+	}
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+
+	if len(rowRequests) == 0 {
+		return renderPopupOrJson(c, http.StatusBadRequest, "No jobs found in file")
+	}
+	if len(rowRequests) > jobImportMaxRows {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("File contains too many jobs (max %d)", jobImportMaxRows))
+	}
+
+	// Warn or block if no connected worker satisfies the minimum worker version of the task
+	versionWarning, block, err := m.checkWorkerVersion(task)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to check worker version: %v", err))
+	}
+	if block {
+		return renderPopupOrJson(c, http.StatusConflict, versionWarning)
+	}
+	if versionWarning != "" {
+		log.Printf("Warning: %s", versionWarning)
+		c.Response().Header().Add("X-Worker-Version-Warning", versionWarning)
+	}
+
+	report := &qmModel.JobImportReport{
+		TaskKey: task.Key,
+		Rows:    []qmModel.JobImportRow{},
+	}
+	initiator := requestedBy(c)
+	for i, rowRequest := range rowRequests {
+		row := qmModel.JobImportRow{Row: i + 1}
+
+		parametersList, parametersKeyed, err := m.resolveJobParameters(c.Request().Context(), task, rowRequest, initiator)
+		if errors.Is(err, errJobPayloadTooLarge) {
+			row.Error = err.Error()
+		} else if err != nil {
+			row.Error = fmt.Sprintf("Validation error: %v", err)
+		} else {
+			jobAdded, deduplicated, err := m.addTaskJob(task, parametersList, parametersKeyed)
+			if err != nil {
+				row.Error = fmt.Sprintf("Failed to add job: %v", err)
+			} else {
+				row.JobRID = &jobAdded.RID
+				row.Deduplicated = deduplicated
+				if !deduplicated {
+					m.recordJobInitiator(jobAdded, initiator, false)
+					m.registerJobChains(task, jobAdded, false)
+					m.publishEvent(qmModel.EVENT_JOB_ADDED, jobAdded)
+				}
+			}
+		}
+
+		if row.Error != "" {
+			report.Failed++
+		} else {
+			report.Added++
+		}
+		report.Rows = append(report.Rows, row)
+	}
+
+	status := http.StatusCreated
+	message := fmt.Sprintf("Successfully added %d jobs", report.Added)
+	if report.Added == 0 {
+		status = http.StatusBadRequest
+		message = fmt.Sprintf("No jobs added, all %d rows have errors", report.Failed)
+	} else if report.Failed > 0 {
+		status = http.StatusPartialContent
+		message = fmt.Sprintf("Added %d jobs, %d rows have errors", report.Added, report.Failed)
+	}
+
+	// Show the report in the import popup
+	if c.Request().Header.Get("HX-Request") != "" {
+		return renderRowsWithPopup(c, status, screens.ImportJobsReport(report), "#import_jobs_result", "innerHTML", "", message)
+	}
+
+	return c.JSON(status, report)
+}
+
+// jobImportCSVRequests returns a form request per record of the CSV file with the values by the keys of the header row.
+// Empty values are left out, so optional parameters can be empty.
+func jobImportCSVRequests(src io.Reader) ([]*http.Request, error) {
+	records, err := csv.NewReader(src).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("Invalid CSV format: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	keys := records[0]
+	for i, key := range keys {
+		keys[i] = strings.TrimSpace(key)
+	}
+
+	rowRequests := []*http.Request{}
+	for _, record := range records[1:] {
+		form := url.Values{}
+		for i, value := range record {
+			if value != "" {
+				form.Set(keys[i], value)
+			}
+		}
+
+		rowRequest, err := http.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		rowRequest.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rowRequests = append(rowRequests, rowRequest)
+	}
+
+	return rowRequests, nil
+}
+
+// jobImportJSONRequests returns a JSON request per object of the JSON array of the file.
+func jobImportJSONRequests(src io.Reader) ([]*http.Request, error) {
+	var rows []map[string]json.RawMessage
+	if err := json.NewDecoder(src).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("Invalid JSON format: %v", err)
+	}
+
+	rowRequests := []*http.Request{}
+	for i, row := range rows {
+		rowJSON, err := json.Marshal(row)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid job %d: %v", i+1, err)
+		}
+
+		rowRequest, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader(rowJSON))
+		if err != nil {
+			return nil, err
+		}
+		rowRequest.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rowRequests = append(rowRequests, rowRequest)
+	}
+
+	return rowRequests, nil
+}
+
+// ======Popup Handlers======
+
+// ImportJobsPopupView renders the popup to add jobs of the task from a CSV or JSON file
+func (m *ManagerHandler) ImportJobsPopupView(c *echo.Context) error {
+	task, err := m.taskDB.SelectTaskByKey(c.QueryParam("taskKey"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "Task not found")
+	}
+
+	return renderPopup(c, screens.ImportJobsPopup(task))
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	vm "github.com/siherrmann/validator/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobImportCSVRequests(t *testing.T) {
+	rowRequests, err := jobImportCSVRequests(strings.NewReader("count, region\n3,eu-west\n5,\n"))
+	require.NoError(t, err)
+	require.Len(t, rowRequests, 2)
+
+	require.NoError(t, rowRequests[0].ParseForm())
+	assert.Equal(t, "3", rowRequests[0].PostForm.Get("count"))
+	assert.Equal(t, "eu-west", rowRequests[0].PostForm.Get("region"))
+
+	require.NoError(t, rowRequests[1].ParseForm())
+	_, ok := rowRequests[1].PostForm["region"]
+	assert.False(t, ok, "Expected empty values to be left out")
+
+	_, err = jobImportCSVRequests(strings.NewReader("count\n3,4\n"))
+	assert.Error(t, err, "Expected rows with more values than the header to be invalid")
+}
+
+func TestAddJobsBulkHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	e := echo.New()
+
+	task, err := tdb.InsertTask(&qmModel.Task{
+		Key:                  "test-bulk-task",
+		Name:                 "Test Bulk Task",
+		InputParameters:      []vm.Validation{{Key: "count", Type: vm.Int, Requirement: "min1"}},
+		InputParametersKeyed: []vm.Validation{{Key: "region", Type: vm.String, Requirement: "min1", OmitEmpty: true}},
+	})
+	require.NoError(t, err)
+
+	addJobsBulk := func(filename string, content string) *httptest.ResponseRecorder {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("job_file", filename)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(content))
+		require.NoError(t, err)
+		writer.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/api/job/addJobsBulk/"+task.Key, body)
+		req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: task.Key}})
+
+		require.NoError(t, handler.AddJobsBulk(c))
+		return rec
+	}
+
+	t.Run("AddJobsBulk from CSV with an invalid row", func(t *testing.T) {
+		rec := addJobsBulk("jobs.csv", "count,region\n3,eu-west\n0,eu-west\n5,\n")
+		require.Equal(t, http.StatusPartialContent, rec.Code, rec.Body.String())
+
+		var report qmModel.JobImportReport
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		assert.Equal(t, 2, report.Added)
+		assert.Equal(t, 1, report.Failed)
+		require.Len(t, report.Rows, 3)
+		assert.Contains(t, report.Rows[1].Error, "Validation error")
+		assert.Nil(t, report.Rows[1].JobRID)
+
+		require.NotNil(t, report.Rows[0].JobRID)
+		job, _, err := handler.jobWithEnded(*report.Rows[0].JobRID)
+		require.NoError(t, err)
+		assert.Equal(t, []any{float64(3)}, []any(job.Parameters))
+		assert.Equal(t, "eu-west", job.ParametersKeyed["region"])
+	})
+
+	t.Run("AddJobsBulk from JSON", func(t *testing.T) {
+		rec := addJobsBulk("jobs.json", `[{"count": 1}, {"count": 2, "region": "us-east"}]`)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		var report qmModel.JobImportReport
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		assert.Equal(t, 2, report.Added)
+		assert.Equal(t, 0, report.Failed)
+	})
+
+	t.Run("AddJobsBulk from HTMX renders the report", func(t *testing.T) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("job_file", "jobs.csv")
+		require.NoError(t, err)
+		_, err = part.Write([]byte("count\n4\n"))
+		require.NoError(t, err)
+		writer.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/api/job/addJobsBulk/"+task.Key, body)
+		req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+		req.Header.Set("HX-Request", "true")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: task.Key}})
+
+		require.NoError(t, handler.AddJobsBulk(c))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "#import_jobs_result", rec.Header().Get("HX-Retarget"))
+		assert.Contains(t, rec.Body.String(), "1 jobs added, 0 rows with errors")
+	})
+
+	t.Run("AddJobsBulk with invalid files", func(t *testing.T) {
+		rec := addJobsBulk("jobs.txt", "count\n1\n")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Invalid file type")
+
+		rec = addJobsBulk("jobs.json", `{"count": 1}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Invalid JSON format")
+
+		rec = addJobsBulk("jobs.csv", "count\n")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "No jobs found")
+
+		rec = addJobsBulk("jobs.csv", "count\n0\n")
+		assert.Equal(t, http.StatusBadRequest, rec.Code, "Expected a bad request if no row was added")
+	})
+}
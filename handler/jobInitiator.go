@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"log"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+)
+
+// initiatorMe filters the jobs by the initiator of the request, eg. /jobs?initiator=me
+const initiatorMe = "me"
+
+// recordJobInitiator records who added the job and if it is a test run. A failed record does not fail adding the job.
+func (m *ManagerHandler) recordJobInitiator(job *model.Job, initiator string, test bool) {
+	if m.JobInitiatorDB == nil || job == nil {
+		return
+	}
+
+	_, err := m.JobInitiatorDB.InsertJobInitiator(&qmModel.JobInitiator{
+		JobRID:    job.RID,
+		TaskKey:   job.TaskName,
+		Initiator: initiator,
+		Test:      test,
+	})
+	if err != nil {
+		log.Printf("Error recording initiator of job %s: %v", job.RID, err)
+	}
+}
+
+// resolveInitiator returns the initiator to filter the jobs by, "me" is the initiator of the request.
+func resolveInitiator(c *echo.Context, initiator string) string {
+	if initiator == initiatorMe {
+		return requestedBy(c)
+	}
+	return initiator
+}
+
+// jobsByInitiator retrieves the active and archived jobs added by the initiator, newest first.
+// lastId is the ID of the last job initiator of the previous page, jobs that were deleted are skipped.
+func (m *ManagerHandler) jobsByInitiator(initiator string, lastId int, limit int) ([]*model.Job, error) {
+	if m.JobInitiatorDB == nil {
+		return []*model.Job{}, nil
+	}
+
+	jobInitiators, err := m.JobInitiatorDB.SelectJobInitiatorsByInitiator(initiator, lastId, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.jobsOfJobInitiators(jobInitiators), nil
+}
+
+// testJobs retrieves the active and archived jobs added as test runs, newest first.
+// lastId is the ID of the last job initiator of the previous page, jobs that were deleted are skipped.
+func (m *ManagerHandler) testJobs(lastId int, limit int) ([]*model.Job, error) {
+	if m.JobInitiatorDB == nil {
+		return []*model.Job{}, nil
+	}
+
+	jobInitiators, err := m.JobInitiatorDB.SelectTestJobInitiators(lastId, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.jobsOfJobInitiators(jobInitiators), nil
+}
+
+// jobInitiatorPage retrieves the page envelope of the jobs added by the initiator or as test runs, newest first.
+// The lastId of the page is the ID of the last job initiator, like the lastId of jobsByInitiator and testJobs.
+func (m *ManagerHandler) jobInitiatorPage(initiator string, test bool, lastId int, limit int) (*qmModel.Page[*model.Job], error) {
+	if m.JobInitiatorDB == nil {
+		return &qmModel.Page[*model.Job]{Items: []*model.Job{}}, nil
+	}
+
+	var jobInitiators []*qmModel.JobInitiator
+	var total int
+	var err error
+	if test {
+		jobInitiators, err = m.JobInitiatorDB.SelectTestJobInitiators(lastId, pageLimit(limit, true))
+		if err == nil {
+			total, err = m.JobInitiatorDB.SelectTestJobInitiatorsCount()
+		}
+	} else {
+		jobInitiators, err = m.JobInitiatorDB.SelectJobInitiatorsByInitiator(initiator, lastId, pageLimit(limit, true))
+		if err == nil {
+			total, err = m.JobInitiatorDB.SelectJobInitiatorsCountByInitiator(initiator)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	page := newPage(jobInitiators, limit, total, func(jobInitiator *qmModel.JobInitiator) int { return jobInitiator.ID })
+	return &qmModel.Page[*model.Job]{
+		Items:   m.jobsOfJobInitiators(page.Items),
+		Total:   page.Total,
+		LastID:  page.LastID,
+		HasMore: page.HasMore,
+	}, nil
+}
+
+// jobsOfJobInitiators retrieves the active or archived jobs of the job initiators, jobs that were deleted are skipped.
+func (m *ManagerHandler) jobsOfJobInitiators(jobInitiators []*qmModel.JobInitiator) []*model.Job {
+	jobs := []*model.Job{}
+	for _, jobInitiator := range jobInitiators {
+		job, err := m.Queuer.GetJob(jobInitiator.JobRID)
+		if err != nil {
+			job, err = m.Queuer.GetJobEnded(jobInitiator.JobRID)
+			if err != nil {
+				continue
+			}
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs
+}
+
+// jobOwnership returns the owner of the task and the initiator of the job, empty if they are unknown.
+func (m *ManagerHandler) jobOwnership(job *model.Job) (string, string) {
+	owner := ""
+	if m.taskDB != nil {
+		if task, err := m.taskDB.SelectTaskByKey(job.TaskName); err == nil {
+			owner = task.Owner
+		}
+	}
+
+	initiator := ""
+	if m.JobInitiatorDB != nil {
+		if jobInitiator, err := m.JobInitiatorDB.SelectJobInitiator(job.RID); err == nil {
+			initiator = jobInitiator.Initiator
+		}
+	}
+
+	return owner, initiator
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuer/model"
+	"github.com/siherrmann/queuerManager/database"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetJobsByInitiatorHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	jidb, err := database.NewJobInitiatorDBHandler(db, true)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.JobInitiatorDB = jidb
+	e := echo.New()
+
+	aliceJob, err := queue.AddJob("test-task", nil, 1)
+	require.NoError(t, err)
+	handler.recordJobInitiator(aliceJob, "alice", false)
+
+	// Without login the initiator of a request is its IP
+	ownJob, err := queue.AddJob("test-task", nil, 1)
+	require.NoError(t, err)
+	handler.recordJobInitiator(ownJob, "192.0.2.1", false)
+
+	testJob, err := queue.AddJob("test-task", nil, 1)
+	require.NoError(t, err)
+	handler.recordJobInitiator(testJob, "alice", true)
+
+	getJobs := func(t *testing.T, target string) []*model.Job {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetJobs(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var jobs []*model.Job
+		err = json.Unmarshal(rec.Body.Bytes(), &jobs)
+		require.NoError(t, err)
+		return jobs
+	}
+
+	t.Run("GetJobs of an initiator", func(t *testing.T) {
+		jobs := getJobs(t, "/api/job/getJobs?initiator=alice")
+		require.Len(t, jobs, 2)
+		assert.Equal(t, testJob.RID, jobs[0].RID)
+		assert.Equal(t, aliceJob.RID, jobs[1].RID)
+	})
+
+	t.Run("GetJobs of the test runs", func(t *testing.T) {
+		jobs := getJobs(t, "/api/job/getJobs?test=true")
+		require.Len(t, jobs, 1)
+		assert.Equal(t, testJob.RID, jobs[0].RID)
+	})
+
+	t.Run("GetJobs of the initiator of the request", func(t *testing.T) {
+		jobs := getJobs(t, "/api/job/getJobs?initiator=me")
+		require.Len(t, jobs, 1)
+		assert.Equal(t, ownJob.RID, jobs[0].RID)
+	})
+
+	t.Run("GetJobs of an initiator without jobs", func(t *testing.T) {
+		jobs := getJobs(t, "/api/job/getJobs?initiator=bob")
+		assert.Empty(t, jobs)
+	})
+}
@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/siherrmann/queuerManager/helper"
+	qmModel "github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/model"
+)
+
+// jobKPIDurationBuckets are the upper bounds in seconds of the buckets of the job duration histogram
+var jobKPIDurationBuckets = []float64{1, 5, 15, 60, 300, 900, 3600, 14400}
+
+// jobKPISeries are the counters of the ended jobs with the same label values.
+type jobKPISeries struct {
+	labels        map[string]string
+	ended         map[string]float64
+	buckets       []float64
+	durationSum   float64
+	durationCount float64
+}
+
+// JobKPIs counts the ended jobs by status and their durations per combination of the values of the allowed labels.
+// The number of series is bounded by the max series, the jobs of further label combinations
+// are counted in one series with all labels set to other.
+type JobKPIs struct {
+	mu        sync.Mutex
+	labels    []string
+	maxSeries int
+	series    map[string]*jobKPISeries
+}
+
+// NewJobKPIs creates a new instance of JobKPIs broken down by the labels, which must be job KPI labels.
+func NewJobKPIs(labels []string, maxSeries int) (*JobKPIs, error) {
+	allowed := []string{}
+	for _, label := range labels {
+		if !slices.Contains(qmModel.KPI_LABELS, label) {
+			return nil, fmt.Errorf("invalid kpi label %q (must be one of %s)", label, strings.Join(qmModel.KPI_LABELS, ", "))
+		}
+		if !slices.Contains(allowed, label) {
+			allowed = append(allowed, label)
+		}
+	}
+	if maxSeries < 1 {
+		return nil, fmt.Errorf("invalid kpi max series %d (must be at least 1)", maxSeries)
+	}
+
+	return &JobKPIs{
+		labels:    allowed,
+		maxSeries: maxSeries,
+		series:    map[string]*jobKPISeries{},
+	}, nil
+}
+
+// NewJobKPIsFromEnv creates the job KPIs with the comma separated labels of QUEUER_MANAGER_KPI_LABELS (default task, none for no labels)
+// and the max series of QUEUER_MANAGER_KPI_MAX_SERIES (default 100).
+func NewJobKPIsFromEnv() (*JobKPIs, error) {
+	labels := []string{}
+	labelsStr := strings.TrimSpace(helper.GetEnvOrDefault("QUEUER_MANAGER_KPI_LABELS", qmModel.KPI_LABEL_TASK))
+	if labelsStr != "none" {
+		for _, label := range strings.Split(labelsStr, ",") {
+			if label = strings.TrimSpace(label); label != "" {
+				labels = append(labels, label)
+			}
+		}
+	}
+
+	maxSeries, err := strconv.Atoi(helper.GetEnvOrDefault("QUEUER_MANAGER_KPI_MAX_SERIES", "100"))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing QUEUER_MANAGER_KPI_MAX_SERIES: %w", err)
+	}
+
+	return NewJobKPIs(labels, maxSeries)
+}
+
+// Labels returns the labels the job KPIs are broken down by.
+func (k *JobKPIs) Labels() []string {
+	return slices.Clone(k.labels)
+}
+
+// Record counts an ended job with the values of the labels and its status.
+// The duration is nil for jobs that never started, they are only counted as ended.
+func (k *JobKPIs) Record(values map[string]string, status string, durationSeconds *float64) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	series := k.seriesOf(values)
+	series.ended[status]++
+	if durationSeconds == nil {
+		return
+	}
+	for i, bound := range jobKPIDurationBuckets {
+		if *durationSeconds <= bound {
+			series.buckets[i]++
+			break
+		}
+	}
+	series.durationSum += *durationSeconds
+	series.durationCount++
+}
+
+// seriesOf returns the series of the label values, new series beyond the max series are counted in the other series.
+// The lock must be held by the caller.
+func (k *JobKPIs) seriesOf(values map[string]string) *jobKPISeries {
+	labels := map[string]string{}
+	keyParts := []string{}
+	for _, label := range k.labels {
+		labels[label] = values[label]
+		keyParts = append(keyParts, values[label])
+	}
+	key := strings.Join(keyParts, "\x00")
+
+	if series, ok := k.series[key]; ok {
+		return series
+	}
+
+	if len(k.series) >= k.maxSeries {
+		keyParts = keyParts[:0]
+		for _, label := range k.labels {
+			labels[label] = qmModel.KPI_LABEL_VALUE_OTHER
+			keyParts = append(keyParts, qmModel.KPI_LABEL_VALUE_OTHER)
+		}
+		key = strings.Join(keyParts, "\x00")
+		if series, ok := k.series[key]; ok {
+			return series
+		}
+	}
+
+	series := &jobKPISeries{
+		labels:  labels,
+		ended:   map[string]float64{},
+		buckets: make([]float64, len(jobKPIDurationBuckets)),
+	}
+	k.series[key] = series
+	return series
+}
+
+// metrics returns the metrics of the job KPIs with the allowed labels.
+func (k *JobKPIs) metrics() []qmModel.ObservabilityMetric {
+	return []qmModel.ObservabilityMetric{
+		{Name: qmModel.PROMETHEUS_METRIC_JOBS_ENDED_TOTAL, Help: "Jobs that ended since the start of the manager by status.", Type: "counter", Labels: append(k.Labels(), "status")},
+		{Name: qmModel.PROMETHEUS_METRIC_JOB_DURATION_SECONDS, Help: "Duration of the jobs that ended since the start of the manager.", Type: "histogram", Unit: "s", Labels: k.Labels()},
+	}
+}
+
+// samples returns the samples of the job KPIs, the series are sorted by their label values.
+func (k *JobKPIs) samples() map[string][]prometheusSample {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	keys := []string{}
+	for key := range k.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	samples := map[string][]prometheusSample{}
+	for _, key := range keys {
+		series := k.series[key]
+
+		statuses := []string{}
+		for status := range series.ended {
+			statuses = append(statuses, status)
+		}
+		sort.Strings(statuses)
+		for _, status := range statuses {
+			labels := map[string]string{"status": status}
+			for label, value := range series.labels {
+				labels[label] = value
+			}
+			samples[qmModel.PROMETHEUS_METRIC_JOBS_ENDED_TOTAL] = append(samples[qmModel.PROMETHEUS_METRIC_JOBS_ENDED_TOTAL], prometheusSample{labels: labels, value: series.ended[status]})
+		}
+
+		if series.durationCount == 0 {
+			continue
+		}
+		cumulative := 0.0
+		for i, bound := range jobKPIDurationBuckets {
+			cumulative += series.buckets[i]
+			samples[qmModel.PROMETHEUS_METRIC_JOB_DURATION_SECONDS] = append(samples[qmModel.PROMETHEUS_METRIC_JOB_DURATION_SECONDS], prometheusSample{
+				suffix: "_bucket",
+				labels: withLabel(series.labels, "le", strconv.FormatFloat(bound, 'g', -1, 64)),
+				value:  cumulative,
+			})
+		}
+		samples[qmModel.PROMETHEUS_METRIC_JOB_DURATION_SECONDS] = append(samples[qmModel.PROMETHEUS_METRIC_JOB_DURATION_SECONDS],
+			prometheusSample{suffix: "_bucket", labels: withLabel(series.labels, "le", "+Inf"), value: series.durationCount},
+			prometheusSample{suffix: "_sum", labels: series.labels, value: series.durationSum},
+			prometheusSample{suffix: "_count", labels: series.labels, value: series.durationCount},
+		)
+	}
+	return samples
+}
+
+// withLabel returns a copy of the labels with the additional label.
+func withLabel(labels map[string]string, label string, value string) map[string]string {
+	copied := map[string]string{label: value}
+	for key, labelValue := range labels {
+		copied[key] = labelValue
+	}
+	return copied
+}
+
+// jobKPILabelValues returns the values of the allowed labels of the job KPIs for an ended job.
+// The owner and initiator and the cost model of the task are only looked up if their labels are allowed.
+func (m *ManagerHandler) jobKPILabelValues(job *model.Job) map[string]string {
+	labels := m.JobKPIs.Labels()
+	values := map[string]string{qmModel.KPI_LABEL_TASK: job.TaskName}
+
+	if slices.Contains(labels, qmModel.KPI_LABEL_OWNER) || slices.Contains(labels, qmModel.KPI_LABEL_INITIATOR) {
+		values[qmModel.KPI_LABEL_OWNER], values[qmModel.KPI_LABEL_INITIATOR] = m.jobOwnership(job)
+	}
+
+	if m.TaskCostDB != nil && (slices.Contains(labels, qmModel.KPI_LABEL_TENANT) || slices.Contains(labels, qmModel.KPI_LABEL_COST_LABEL)) {
+		if taskCost, err := m.TaskCostDB.SelectTaskCost(job.TaskName); err == nil {
+			values[qmModel.KPI_LABEL_TENANT] = taskCost.Tenant
+			values[qmModel.KPI_LABEL_COST_LABEL] = taskCost.Label
+		}
+	}
+
+	return values
+}
+
+// RecordJobKPIs counts an ended job in the job KPIs without blocking the caller.
+func (m *ManagerHandler) RecordJobKPIs(job *model.Job) {
+	if m.JobKPIs == nil || job == nil {
+		return
+	}
+
+	go func() {
+		var durationSeconds *float64
+		if job.StartedAt != nil && !job.StartedAt.IsZero() {
+			duration := job.UpdatedAt.Sub(*job.StartedAt).Seconds()
+			durationSeconds = &duration
+		}
+		m.JobKPIs.Record(m.jobKPILabelValues(job), strings.ToLower(job.Status), durationSeconds)
+	}()
+}
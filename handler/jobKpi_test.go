@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJobKPIs(t *testing.T) {
+	kpis, err := NewJobKPIs([]string{model.KPI_LABEL_TASK, model.KPI_LABEL_OWNER, model.KPI_LABEL_TASK}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{model.KPI_LABEL_TASK, model.KPI_LABEL_OWNER}, kpis.Labels(), "Expected duplicate labels to be removed")
+
+	_, err = NewJobKPIs([]string{"job_rid"}, 10)
+	assert.ErrorContains(t, err, "invalid kpi label", "Expected only allowlisted labels")
+
+	_, err = NewJobKPIs([]string{model.KPI_LABEL_TASK}, 0)
+	assert.ErrorContains(t, err, "invalid kpi max series")
+}
+
+func TestJobKPIsPrometheusText(t *testing.T) {
+	kpis, err := NewJobKPIs([]string{model.KPI_LABEL_TASK}, 2)
+	require.NoError(t, err)
+
+	duration := 10.0
+	kpis.Record(map[string]string{model.KPI_LABEL_TASK: "export", model.KPI_LABEL_OWNER: "data-team"}, "succeeded", &duration)
+	kpis.Record(map[string]string{model.KPI_LABEL_TASK: "export"}, "failed", nil)
+	kpis.Record(map[string]string{model.KPI_LABEL_TASK: "report"}, "succeeded", nil)
+	kpis.Record(map[string]string{model.KPI_LABEL_TASK: "cleanup"}, "succeeded", nil)
+	kpis.Record(map[string]string{model.KPI_LABEL_TASK: "import"}, "cancelled", nil)
+
+	text := prometheusText(kpis.metrics(), kpis.samples())
+	assert.Contains(t, text, "# TYPE queuer_manager_jobs_ended_total counter")
+	assert.Contains(t, text, `queuer_manager_jobs_ended_total{task="export",status="succeeded"} 1`)
+	assert.Contains(t, text, `queuer_manager_jobs_ended_total{task="export",status="failed"} 1`)
+	assert.Contains(t, text, `queuer_manager_jobs_ended_total{task="report",status="succeeded"} 1`)
+	assert.Contains(t, text, `queuer_manager_jobs_ended_total{task="other",status="succeeded"} 1`, "Expected tasks beyond the max series to be counted as other")
+	assert.Contains(t, text, `queuer_manager_jobs_ended_total{task="other",status="cancelled"} 1`)
+	assert.NotContains(t, text, "data-team", "Expected labels outside of the allowlist to be dropped")
+
+	assert.Contains(t, text, "# TYPE queuer_manager_job_duration_seconds histogram")
+	assert.Contains(t, text, `queuer_manager_job_duration_seconds_bucket{task="export",le="5"} 0`)
+	assert.Contains(t, text, `queuer_manager_job_duration_seconds_bucket{task="export",le="15"} 1`)
+	assert.Contains(t, text, `queuer_manager_job_duration_seconds_bucket{task="export",le="+Inf"} 1`)
+	assert.Contains(t, text, `queuer_manager_job_duration_seconds_sum{task="export"} 10`)
+	assert.Contains(t, text, `queuer_manager_job_duration_seconds_count{task="export"} 1`)
+	assert.NotContains(t, text, `queuer_manager_job_duration_seconds_count{task="report"}`, "Expected no durations of jobs that never started")
+}
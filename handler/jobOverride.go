@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+)
+
+// jobOverrideStatuses are the statuses an active job can be ended with by a status override
+var jobOverrideStatuses = []string{
+	model.JobStatusFailed,
+	model.JobStatusCancelled,
+	model.JobStatusSucceeded,
+}
+
+// =======API Handlers=======
+
+// OverrideJobStatus ends an active job with the requested status, eg. to fail a zombie running job whose worker died
+// without updating the queue. The reason is required, the override is recorded and shown on the job page.
+func (m *ManagerHandler) OverrideJobStatus(c *echo.Context) error {
+	if m.JobOverrideDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Job status overrides are not configured")
+	}
+
+	rid, err := uuid.Parse(c.Param("rid"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid job RID format")
+	}
+
+	var request qmModel.JobOverrideRequest
+	if err := c.Bind(&request); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+	}
+	request.Reason = strings.TrimSpace(request.Reason)
+	if request.Reason == "" {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Reason is required")
+	}
+	if len(request.Reason) > qmModel.JOB_OVERRIDE_MAX_REASON {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Reason must be at most %d characters", qmModel.JOB_OVERRIDE_MAX_REASON))
+	}
+	if !slices.Contains(jobOverrideStatuses, request.Status) {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid status (must be one of %s)", strings.Join(jobOverrideStatuses, ", ")))
+	}
+
+	job, err := m.Queuer.GetJob(rid)
+	if err != nil {
+		if _, endedErr := m.Queuer.GetJobEnded(rid); endedErr == nil {
+			return renderPopupOrJson(c, http.StatusConflict, "Job has already ended")
+		}
+		return renderPopupOrJson(c, http.StatusNotFound, "Job not found")
+	}
+
+	jobOverride, err := m.JobOverrideDB.OverrideJobStatus(job, &qmModel.JobOverride{
+		Status:      request.Status,
+		Reason:      request.Reason,
+		RequestedBy: requestedBy(c),
+	})
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to override job status: %v", err))
+	}
+	m.publishEvent(qmModel.EVENT_JOB_STATUS_OVERRIDDEN, jobOverride)
+
+	if c.Request().Header.Get("HX-Request") != "" {
+		c.Response().Header().Add("HX-Redirect", fmt.Sprintf("/job?rid=%s", rid.String()))
+		return renderPopupOrJson(c, http.StatusOK, fmt.Sprintf("Job status overridden from %s to %s", jobOverride.PreviousStatus, jobOverride.Status))
+	}
+
+	return c.JSON(http.StatusOK, jobOverride)
+}
+
+// =======View Handlers=======
+
+// OverrideJobStatusPopupView renders the popup to override the status of an active job.
+func (m *ManagerHandler) OverrideJobStatusPopupView(c *echo.Context) error {
+	rid, err := uuid.Parse(c.QueryParam("rid"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid job RID format")
+	}
+
+	job, err := m.Queuer.GetJob(rid)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "Job not found or already ended")
+	}
+
+	return renderPopup(c, screens.OverrideJobStatusPopup(job, jobOverrideStatuses))
+}
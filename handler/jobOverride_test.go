@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuer/model"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverrideJobStatusHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	jodb, err := database.NewJobOverrideDBHandler(db, true)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.JobOverrideDB = jodb
+	e := echo.New()
+
+	overrideJobStatus := func(rid string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/job/overrideJobStatus/"+rid, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "rid", Value: rid}})
+
+		require.NoError(t, handler.OverrideJobStatus(c))
+		return rec
+	}
+
+	t.Run("Override fails an active job with the reason", func(t *testing.T) {
+		job, err := queue.AddJob("test-task", nil, 10)
+		require.NoError(t, err)
+
+		rec := overrideJobStatus(job.RID.String(), `{"status": "FAILED", "reason": "worker was OOM killed"}`)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var jobOverride qmModel.JobOverride
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &jobOverride))
+		assert.Equal(t, job.RID, jobOverride.JobRID)
+		assert.Equal(t, model.JobStatusFailed, jobOverride.Status)
+		assert.Equal(t, "worker was OOM killed", jobOverride.Reason)
+
+		endedJob, err := queue.GetJobEnded(job.RID)
+		require.NoError(t, err, "Expected the job to be moved to the archive")
+		assert.Equal(t, model.JobStatusFailed, endedJob.Status)
+		assert.Contains(t, endedJob.Error, "worker was OOM killed")
+
+		recordedJobOverride, err := jodb.SelectJobOverride(job.RID)
+		require.NoError(t, err, "Expected the override to be recorded")
+		assert.Equal(t, jobOverride.PreviousStatus, recordedJobOverride.PreviousStatus)
+
+		rec = overrideJobStatus(job.RID.String(), `{"status": "SUCCEEDED", "reason": "again"}`)
+		assert.Equal(t, http.StatusConflict, rec.Code, "Expected ended jobs to not be overridden")
+	})
+
+	t.Run("Override without reason or with invalid status", func(t *testing.T) {
+		rid := uuid.New().String()
+
+		rec := overrideJobStatus(rid, `{"status": "FAILED", "reason": "  "}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Reason is required")
+
+		rec = overrideJobStatus(rid, `{"status": "RUNNING", "reason": "stuck"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Invalid status")
+	})
+
+	t.Run("Override with invalid or non-existent RID", func(t *testing.T) {
+		rec := overrideJobStatus("invalid-uuid", `{"status": "FAILED", "reason": "stuck"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = overrideJobStatus(uuid.New().String(), `{"status": "FAILED", "reason": "stuck"}`)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+)
+
+// validateJobPriority checks the priority of a job is in the range of the job priorities.
+func validateJobPriority(priority int) error {
+	if priority < qmModel.JOB_PRIORITY_MIN || priority > qmModel.JOB_PRIORITY_MAX {
+		return fmt.Errorf("invalid priority (must be between %d and %d)", qmModel.JOB_PRIORITY_MIN, qmModel.JOB_PRIORITY_MAX)
+	}
+	return nil
+}
+
+// jobPriorityFromRequest returns the priority of a job added with the job form from the priority query parameter,
+// or from the priority form field if the task has no parameter with that key. Without priority the default priority is returned.
+func (m *ManagerHandler) jobPriorityFromRequest(c *echo.Context, task *qmModel.Task) (int, error) {
+	priorityStr := c.QueryParam(qmModel.JOB_PRIORITY_FIELD)
+	if priorityStr == "" && !task.HasParameter(qmModel.JOB_PRIORITY_FIELD) {
+		priorityStr = c.Request().FormValue(qmModel.JOB_PRIORITY_FIELD)
+	}
+	if strings.TrimSpace(priorityStr) == "" {
+		return qmModel.JOB_PRIORITY_DEFAULT, nil
+	}
+
+	priority, err := strconv.Atoi(strings.TrimSpace(priorityStr))
+	if err != nil {
+		return 0, fmt.Errorf("invalid priority %q", priorityStr)
+	}
+	return priority, m.checkJobPriority(priority)
+}
+
+// checkJobPriority validates the priority of a job to add, a priority other than the default needs the scheduler database
+// and the scheduler override, as only the job claiming functions of the manager read the priorities.
+func (m *ManagerHandler) checkJobPriority(priority int) error {
+	err := validateJobPriority(priority)
+	if err != nil {
+		return err
+	}
+	if priority != qmModel.JOB_PRIORITY_DEFAULT && !m.jobPrioritiesEnabled() {
+		return fmt.Errorf("job priorities are not configured (need the scheduler override QUEUER_MANAGER_SCHEDULER_OVERRIDE)")
+	}
+	return nil
+}
+
+// jobPrioritiesEnabled reports whether the workers claim the jobs by their priority.
+func (m *ManagerHandler) jobPrioritiesEnabled() bool {
+	return m.SchedulerDB != nil && m.SchedulerOverride
+}
+
+// jobPriorities returns the priorities of the jobs by RID for the job list, jobs without priority are left out.
+func (m *ManagerHandler) jobPriorities(jobs []*model.Job) map[uuid.UUID]int {
+	if m.SchedulerDB == nil || len(jobs) == 0 {
+		return map[uuid.UUID]int{}
+	}
+
+	jobRIDs := make([]uuid.UUID, 0, len(jobs))
+	for _, job := range jobs {
+		jobRIDs = append(jobRIDs, job.RID)
+	}
+
+	priorities, err := m.SchedulerDB.SelectJobPriorities(jobRIDs)
+	if err != nil {
+		log.Printf("Error getting job priorities: %v", err)
+		return map[uuid.UUID]int{}
+	}
+	return priorities
+}
+
+// DeleteEndedJobPriority deletes the priority of an ended job, it is called for the jobs deleted from the queue.
+func (m *ManagerHandler) DeleteEndedJobPriority(job *model.Job) {
+	if m.SchedulerDB == nil || job == nil {
+		return
+	}
+
+	err := m.SchedulerDB.DeleteJobPriority(job.RID)
+	if err != nil {
+		log.Printf("Error deleting priority of job %s: %v", job.RID, err)
+	}
+}
+
+// =======API Handlers=======
+
+// SetJobPriority changes the priority of a queued or scheduled job, the workers claim jobs with a higher priority first.
+func (m *ManagerHandler) SetJobPriority(c *echo.Context) error {
+	if !m.jobPrioritiesEnabled() {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Job priorities are not configured")
+	}
+
+	rid, err := uuid.Parse(c.Param("rid"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid job RID format")
+	}
+
+	var request qmModel.JobPriorityRequest
+	if err := c.Bind(&request); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+	}
+	if request.Priority == nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Priority is required")
+	}
+	err = validateJobPriority(*request.Priority)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+
+	job, err := m.Queuer.GetJob(rid)
+	if err != nil {
+		if _, endedErr := m.Queuer.GetJobEnded(rid); endedErr == nil {
+			return renderPopupOrJson(c, http.StatusConflict, "Job has already ended")
+		}
+		return renderPopupOrJson(c, http.StatusNotFound, "Job not found")
+	}
+	if job.Status != model.JobStatusQueued && job.Status != model.JobStatusScheduled {
+		return renderPopupOrJson(c, http.StatusConflict, fmt.Sprintf("Only queued or scheduled jobs can change their priority, the job is %s", job.Status))
+	}
+
+	jobPriority, err := m.SchedulerDB.UpsertJobPriority(&qmModel.JobPriority{
+		JobRID:    job.RID,
+		Priority:  *request.Priority,
+		UpdatedBy: requestedBy(c),
+	})
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to set job priority: %v", err))
+	}
+	m.JobStream.Publish(job)
+
+	if c.Request().Header.Get("HX-Request") != "" {
+		c.Response().Header().Add("HX-Redirect", fmt.Sprintf("/job?rid=%s", rid.String()))
+		return renderPopupOrJson(c, http.StatusOK, fmt.Sprintf("Job priority set to %d", jobPriority.Priority))
+	}
+
+	return c.JSON(http.StatusOK, jobPriority)
+}
+
+// =======View Handlers=======
+
+// SetJobPriorityPopupView renders the popup to change the priority of a queued job.
+func (m *ManagerHandler) SetJobPriorityPopupView(c *echo.Context) error {
+	rid, err := uuid.Parse(c.QueryParam("rid"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid job RID format")
+	}
+
+	job, err := m.Queuer.GetJob(rid)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "Job not found or already ended")
+	}
+
+	return renderPopup(c, screens.SetJobPriorityPopup(job, m.jobPriorities([]*model.Job{job})[job.RID]))
+}
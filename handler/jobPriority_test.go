@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/testutil/fake"
+	"github.com/siherrmann/queuerManager/upload"
+	vm "github.com/siherrmann/validator/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobPriorityFromRequest(t *testing.T) {
+	m := &ManagerHandler{}
+	e := echo.New()
+	task := &qmModel.Task{Key: "report"}
+
+	jobPriority := func(task *qmModel.Task, target string, body string) (int, error) {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		return m.jobPriorityFromRequest(e.NewContext(req, httptest.NewRecorder()), task)
+	}
+
+	priority, err := jobPriority(task, "/api/job/addJob/report", "")
+	require.NoError(t, err)
+	assert.Equal(t, qmModel.JOB_PRIORITY_DEFAULT, priority)
+
+	priority, err = jobPriority(task, "/api/job/addJob/report", "priority=0")
+	require.NoError(t, err)
+	assert.Equal(t, 0, priority)
+
+	_, err = jobPriority(task, "/api/job/addJob/report", "priority=10")
+	assert.ErrorContains(t, err, "not configured", "Expected priorities to need the scheduler database")
+
+	_, err = jobPriority(task, "/api/job/addJob/report?priority=101", "")
+	assert.ErrorContains(t, err, "between -100 and 100")
+
+	_, err = jobPriority(task, "/api/job/addJob/report", "priority=high")
+	assert.ErrorContains(t, err, "invalid priority")
+
+	taskWithPriority := &qmModel.Task{Key: "report", InputParametersKeyed: []vm.Validation{{Key: "priority"}}}
+	priority, err = jobPriority(taskWithPriority, "/api/job/addJob/report", "priority=high")
+	require.NoError(t, err, "Expected the priority parameter of the task to not be read as job priority")
+	assert.Equal(t, qmModel.JOB_PRIORITY_DEFAULT, priority)
+}
+
+func TestSetJobPriorityWithoutSchedulerDatabase(t *testing.T) {
+	handler := NewManagerHandler(upload.NewFilesystemMemory(), fake.NewTaskDB(), fake.NewQueuer())
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/job/setPriority/"+uuid.New().String(), strings.NewReader(`{"priority": 10}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, handler.SetJobPriority(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestAddQueuerJobWithPriorityWithoutSchedulerDatabase(t *testing.T) {
+	queuer := fake.NewQueuer()
+	handler := NewManagerHandler(upload.NewFilesystemMemory(), fake.NewTaskDB(), queuer)
+
+	_, err := handler.addQueuerJob(&qmModel.Task{Key: "report"}, nil, &qmModel.JobPriority{Priority: 10}, nil, nil)
+	assert.ErrorContains(t, err, "not configured")
+
+	jobs, err := queuer.GetJobs(0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, jobs, "Expected no job to be added without its priority")
+}
+
+func TestSetJobPriorityHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	sdb, err := database.NewSchedulerDBHandler(db, true)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.SchedulerDB = sdb
+	handler.SchedulerOverride = true
+	e := echo.New()
+
+	setJobPriority := func(rid string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/job/setPriority/"+rid, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "rid", Value: rid}})
+
+		require.NoError(t, handler.SetJobPriority(c))
+		return rec
+	}
+
+	t.Run("Set the priority of a queued job", func(t *testing.T) {
+		job, err := queue.AddJob("test-task", nil, 10)
+		require.NoError(t, err)
+
+		rec := setJobPriority(job.RID.String(), `{"priority": 50}`)
+		if rec.Code == http.StatusConflict {
+			t.Skip("Job was claimed before its priority could be set")
+		}
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var jobPriority qmModel.JobPriority
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &jobPriority))
+		assert.Equal(t, job.RID, jobPriority.JobRID)
+		assert.Equal(t, 50, jobPriority.Priority)
+	})
+
+	t.Run("Add a job with its priority", func(t *testing.T) {
+		job, err := handler.addQueuerJob(&qmModel.Task{Key: "test-task"}, nil, &qmModel.JobPriority{Priority: 20, UpdatedBy: "admin"}, []any{10}, nil)
+		require.NoError(t, err)
+
+		priorities, err := sdb.SelectJobPriorities([]uuid.UUID{job.RID})
+		require.NoError(t, err)
+		assert.Equal(t, map[uuid.UUID]int{job.RID: 20}, priorities, "Expected the priority to be stored with the job")
+	})
+
+	t.Run("Invalid requests are rejected", func(t *testing.T) {
+		rid := uuid.New().String()
+		assert.Equal(t, http.StatusBadRequest, setJobPriority(rid, `{}`).Code)
+		assert.Equal(t, http.StatusBadRequest, setJobPriority(rid, `{"priority": 1000}`).Code)
+		assert.Equal(t, http.StatusBadRequest, setJobPriority("invalid-uuid", `{"priority": 1}`).Code)
+		assert.Equal(t, http.StatusNotFound, setJobPriority(rid, `{"priority": 1}`).Code)
+	})
+}
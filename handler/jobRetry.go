@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+)
+
+// jobRetryMaxJobs is the max number of archived jobs retried with one request
+const jobRetryMaxJobs = 100
+
+// jobParameterValues returns the parameters of a job by the parameter keys of its task,
+// the parameter list is matched with the input parameters of the task by position.
+func jobParameterValues(task *qmModel.Task, job *model.Job) map[string]any {
+	values := map[string]any{}
+	for i, v := range task.InputParameters {
+		if i < len(job.Parameters) {
+			values[v.Key] = job.Parameters[i]
+		}
+	}
+	for _, v := range task.InputParametersKeyed {
+		if value, ok := job.ParametersKeyed[v.Key]; ok {
+			values[v.Key] = value
+		}
+	}
+	return values
+}
+
+// jobParameterFormValues returns the parameters as values of the parameter inputs of a form,
+// values that are neither strings, numbers nor booleans are JSON encoded.
+func jobParameterFormValues(values map[string]any) map[string]string {
+	formValues := map[string]string{}
+	for key, value := range values {
+		switch v := value.(type) {
+		case nil:
+			formValues[key] = ""
+		case string:
+			formValues[key] = v
+		case float64:
+			formValues[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			formValues[key] = strconv.FormatBool(v)
+		default:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				formValues[key] = fmt.Sprint(v)
+			} else {
+				formValues[key] = string(encoded)
+			}
+		}
+	}
+	return formValues
+}
+
+// jobRetryRequestFromContext reads the archived jobs to retry with the parameter overrides from a JSON body
+// or from a form, where rid holds the RIDs and all other fields are parameter overrides.
+func jobRetryRequestFromContext(c *echo.Context) (*qmModel.JobRetryRequest, error) {
+	retryRequest := &qmModel.JobRetryRequest{Parameters: map[string]any{}}
+
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		if err := json.NewDecoder(c.Request().Body).Decode(retryRequest); err != nil {
+			return nil, fmt.Errorf("Invalid request: %v", err)
+		}
+	} else {
+		form, err := c.FormValues()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse form with job RIDs")
+		}
+		for key, values := range form {
+			if len(values) == 0 {
+				continue
+			}
+			if key != "rid" {
+				retryRequest.Parameters[key] = values[0]
+				continue
+			}
+			for _, ridStr := range values {
+				rid, err := uuid.Parse(ridStr)
+				if err != nil {
+					return nil, fmt.Errorf("Invalid job RID format: %s", ridStr)
+				}
+				retryRequest.RIDs = append(retryRequest.RIDs, rid)
+			}
+		}
+	}
+
+	if len(retryRequest.RIDs) == 0 {
+		return nil, fmt.Errorf("No job RIDs provided")
+	}
+	if len(retryRequest.RIDs) > jobRetryMaxJobs {
+		return nil, fmt.Errorf("Too many jobs (at most %d jobs can be retried at once)", jobRetryMaxJobs)
+	}
+	return retryRequest, nil
+}
+
+// retryJob adds an archived job again with its original parameters and the overrides,
+// the parameters are validated like the parameters of the add job form of the task.
+func (m *ManagerHandler) retryJob(c *echo.Context, rid uuid.UUID, overrides map[string]any) (*model.Job, error) {
+	job, err := m.Queuer.GetJobEnded(rid)
+	if err != nil {
+		return nil, fmt.Errorf("archived job not found")
+	}
+
+	task, err := m.taskDB.SelectTaskByKey(job.TaskName)
+	if err != nil {
+		return nil, fmt.Errorf("task %s not found", job.TaskName)
+	}
+
+	values := jobParameterValues(task, job)
+	for key, value := range overrides {
+		if !task.HasParameter(key) {
+			return nil, fmt.Errorf("task %s has no parameter %s", task.Key, key)
+		}
+		values[key] = value
+	}
+
+	// The values are sent as form values like the values of a job template, so they are converted to the parameter types
+	parametersList, parametersKeyed, err := m.resolveJobParameters(c.Request().Context(), task, jobTemplateValuesRequest(jobParameterFormValues(values)), requestedBy(c))
+	if err != nil {
+		return nil, fmt.Errorf("validation error: %v", err)
+	}
+
+	versionWarning, block, err := m.checkWorkerVersion(task)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check worker version: %v", err)
+	}
+	if block {
+		return nil, fmt.Errorf("%s", versionWarning)
+	}
+	if m.Forwarder.Rule(task.Key) != nil {
+		return nil, fmt.Errorf("jobs of task %s are forwarded to a remote instance", task.Key)
+	}
+
+	jobAdded, deduplicated, err := m.addTaskJob(task, parametersList, parametersKeyed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add job: %v", err)
+	}
+	if !deduplicated {
+		m.recordJobInitiator(jobAdded, requestedBy(c), false)
+		m.registerJobChains(task, jobAdded, false)
+		m.publishEvent(qmModel.EVENT_JOB_READDED, jobAdded)
+	}
+
+	return jobAdded, nil
+}
+
+// =======API Handlers=======
+
+// RetryJobs adds archived jobs again with their original parameters, optionally overridden by parameter key.
+// The body is a JSON object with the rids and the parameters, eg. {"rids": ["..."], "parameters": {"limit": 10}},
+// or a form with the rid fields and the parameters as further fields. The result of each job is returned,
+// a single job retried from the UI redirects to the added job.
+func (m *ManagerHandler) RetryJobs(c *echo.Context) error {
+	retryRequest, err := jobRetryRequestFromContext(c)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+
+	results := []*qmModel.JobRetryResult{}
+	errorMessages := []string{}
+	for _, rid := range retryRequest.RIDs {
+		result := &qmModel.JobRetryResult{RID: rid}
+		result.Job, err = m.retryJob(c, rid, retryRequest.Parameters)
+		if err != nil {
+			result.Error = err.Error()
+			errorMessages = append(errorMessages, fmt.Sprintf("%s: %v", rid, err))
+		}
+		results = append(results, result)
+	}
+
+	if c.Request().Header.Get("HX-Request") == "" {
+		return c.JSON(http.StatusOK, results)
+	}
+
+	if len(errorMessages) > 0 {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Retried %d of %d jobs. %s", len(results)-len(errorMessages), len(results), strings.Join(errorMessages, "; ")))
+	}
+	if len(results) == 1 {
+		c.Response().Header().Add("HX-Redirect", fmt.Sprintf("/job?rid=%s", results[0].Job.RID.String()))
+	}
+	c.Response().Header().Add("HX-Trigger-After-Settle", "closeRetryJob")
+
+	return renderPopupOrJson(c, http.StatusOK, fmt.Sprintf("%d jobs retried", len(results)))
+}
+
+// =======View Handlers=======
+
+// RetryJobPopupView renders the popup to retry an archived job with the original parameters prefilled for editing
+func (m *ManagerHandler) RetryJobPopupView(c *echo.Context) error {
+	ridStrings := c.QueryParams()["rid"]
+	if len(ridStrings) != 1 {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Please select exactly one job")
+	}
+
+	rid, err := uuid.Parse(ridStrings[0])
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid job RID: %v", err))
+	}
+
+	job, err := m.Queuer.GetJobEnded(rid)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "Archived job not found")
+	}
+
+	task, err := m.taskDB.SelectTaskByKey(job.TaskName)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "Task not found")
+	}
+
+	files, err := m.Filesystem.ListFiles()
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Error listing files: %v", err))
+	}
+
+	return renderPopup(c, screens.RetryJobPopup(job, task, files, jobParameterFormValues(jobParameterValues(task, job))))
+}
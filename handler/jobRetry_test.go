@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuer/model"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	vm "github.com/siherrmann/validator/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobParameterValues(t *testing.T) {
+	task := &qmModel.Task{
+		InputParameters:      []vm.Validation{{Key: "name", Type: vm.String}, {Key: "count", Type: vm.Int}},
+		InputParametersKeyed: []vm.Validation{{Key: "options", Type: vm.Map}},
+	}
+	job := &model.Job{
+		Parameters:      model.Parameters{"export", float64(3)},
+		ParametersKeyed: model.ParametersKeyed{"options": map[string]any{"dry": true}, "unknown": "x"},
+	}
+
+	values := jobParameterValues(task, job)
+	assert.Equal(t, map[string]any{"name": "export", "count": float64(3), "options": map[string]any{"dry": true}}, values)
+	assert.Equal(t, map[string]string{"name": "export", "count": "3", "options": `{"dry":true}`}, jobParameterFormValues(values))
+
+	assert.True(t, task.HasParameter("options"))
+	assert.False(t, task.HasParameter("unknown"))
+}
+
+func TestJobRetryRequestFromContext(t *testing.T) {
+	e := echo.New()
+	rid := uuid.New()
+
+	t.Run("Retry request from JSON", func(t *testing.T) {
+		body := `{"rids": ["` + rid.String() + `"], "parameters": {"count": 5}}`
+		req := httptest.NewRequest(http.MethodPost, "/api/jobArchive/retryJobs", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		retryRequest, err := jobRetryRequestFromContext(c)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{rid}, retryRequest.RIDs)
+		assert.Equal(t, map[string]any{"count": float64(5)}, retryRequest.Parameters)
+	})
+
+	t.Run("Retry request from form", func(t *testing.T) {
+		form := url.Values{"rid": {rid.String()}, "count": {"5"}}
+		req := httptest.NewRequest(http.MethodPost, "/api/jobArchive/retryJobs", strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		retryRequest, err := jobRetryRequestFromContext(c)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{rid}, retryRequest.RIDs)
+		assert.Equal(t, map[string]any{"count": "5"}, retryRequest.Parameters)
+	})
+
+	t.Run("Retry request without RIDs", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/jobArchive/retryJobs", strings.NewReader(`{"rids": []}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		_, err := jobRetryRequestFromContext(c)
+		assert.ErrorContains(t, err, "No job RIDs provided")
+	})
+
+	t.Run("Retry request with invalid RID", func(t *testing.T) {
+		form := url.Values{"rid": {"invalid"}}
+		req := httptest.NewRequest(http.MethodPost, "/api/jobArchive/retryJobs", strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		_, err := jobRetryRequestFromContext(c)
+		assert.ErrorContains(t, err, "Invalid job RID format")
+	})
+}
+
+func TestRetryJobsHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	e := echo.New()
+
+	t.Run("RetryJobs with unknown RID", func(t *testing.T) {
+		rid := uuid.New()
+		body := `{"rids": ["` + rid.String() + `"]}`
+		req := httptest.NewRequest(http.MethodPost, "/api/jobArchive/retryJobs", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.RetryJobs(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var results []*qmModel.JobRetryResult
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &results))
+		require.Len(t, results, 1)
+		assert.Equal(t, rid, results[0].RID)
+		assert.Nil(t, results[0].Job)
+		assert.Equal(t, "archived job not found", results[0].Error)
+	})
+
+	t.Run("RetryJobPopupView with multiple RIDs", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/jobArchive/retryJobPopup?rid="+uuid.New().String()+"&rid="+uuid.New().String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.RetryJobPopupView(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Please select exactly one job")
+	})
+}
+
+func TestRetryJobParametersValidation(t *testing.T) {
+	handler := &ManagerHandler{validationFuncs: defaultValidationFuncs()}
+	task := &qmModel.Task{
+		InputParameters:      []vm.Validation{{Key: "count", Type: vm.Int, Requirement: "min1"}},
+		InputParametersKeyed: []vm.Validation{{Key: "options", Type: vm.Map, Requirement: "-"}},
+	}
+
+	values := map[string]any{"count": "5", "options": map[string]any{"dry": true}}
+	parameters, err := handler.validateJobParameters(task, jobTemplateValuesRequest(jobParameterFormValues(values)))
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, parameters["count"], "Expected the form value of an override to be converted to the parameter type")
+	assert.Equal(t, map[string]any{"dry": true}, parameters["options"])
+
+	values["count"] = float64(0)
+	_, err = handler.validateJobParameters(task, jobTemplateValuesRequest(jobParameterFormValues(values)))
+	assert.Error(t, err, "Expected overrides to be validated against the task")
+}
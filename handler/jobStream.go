@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+)
+
+// jobStreamBuffer is the number of job changes buffered per subscriber, changes of slow subscribers are dropped.
+const jobStreamBuffer = 100
+
+// jobStreamKeepAlive is the interval of the keep alive comments of the job events, so proxies keep the connection open.
+const jobStreamKeepAlive = 30 * time.Second
+
+// Job events of the job event stream, a job event carries the rendered table row of the job.
+const (
+	JOB_EVENT_JOB       = "job"
+	JOB_EVENT_JOB_ENDED = "jobEnded"
+)
+
+// JobStream fans the changes of the jobs out to the subscribed job event streams.
+type JobStream struct {
+	mu          sync.Mutex
+	subscribers map[chan *model.Job]struct{}
+}
+
+// NewJobStream creates a new instance of JobStream.
+func NewJobStream() *JobStream {
+	return &JobStream{
+		subscribers: map[chan *model.Job]struct{}{},
+	}
+}
+
+// Subscribe subscribes to the job changes, the returned function unsubscribes again.
+func (s *JobStream) Subscribe() (<-chan *model.Job, func()) {
+	jobs := make(chan *model.Job, jobStreamBuffer)
+
+	s.mu.Lock()
+	s.subscribers[jobs] = struct{}{}
+	s.mu.Unlock()
+
+	return jobs, func() {
+		s.mu.Lock()
+		delete(s.subscribers, jobs)
+		s.mu.Unlock()
+	}
+}
+
+// Publish sends a changed job to all subscribers without blocking.
+func (s *JobStream) Publish(job *model.Job) {
+	if job == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for jobs := range s.subscribers {
+		select {
+		case jobs <- job:
+		default:
+		}
+	}
+}
+
+// JobEvents streams the added and updated jobs as server-sent events until the client disconnects.
+// Active jobs are sent as job event with the rendered table row, ended jobs as jobEnded event with the RID.
+func (m *ManagerHandler) JobEvents(c *echo.Context) error {
+	jobs, unsubscribe := m.JobStream.Subscribe()
+	defer unsubscribe()
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-cache")
+	c.Response().Header().Set(echo.HeaderConnection, "keep-alive")
+	c.Response().Header().Set("X-Accel-Buffering", "no")
+	c.Response().WriteHeader(http.StatusOK)
+
+	responseController := http.NewResponseController(c.Response())
+	if err := responseController.Flush(); err != nil {
+		return err
+	}
+
+	keepAlive := time.NewTicker(jobStreamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-m.ShuttingDown():
+			return nil
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(c.Response(), ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+		case job := <-jobs:
+			event, data, err := m.jobEvent(c, job)
+			if err != nil {
+				log.Printf("Error rendering event of job %s: %v", job.RID, err)
+				continue
+			}
+			if err := writeServerSentEvent(c.Response(), event, data); err != nil {
+				return nil
+			}
+		}
+
+		if err := responseController.Flush(); err != nil {
+			return nil
+		}
+	}
+}
+
+// jobEvent returns the event and data of a changed job for the job event stream.
+func (m *ManagerHandler) jobEvent(c *echo.Context, job *model.Job) (string, string, error) {
+	switch job.Status {
+	case model.JobStatusSucceeded, model.JobStatusFailed, model.JobStatusCancelled:
+		return JOB_EVENT_JOB_ENDED, job.RID.String(), nil
+	}
+
+	var row bytes.Buffer
+	if err := screens.JobRow(job, m.jobPriorities([]*model.Job{job})[job.RID]).Render(c.Request().Context(), &row); err != nil {
+		return "", "", err
+	}
+	return JOB_EVENT_JOB, row.String(), nil
+}
+
+// writeServerSentEvent writes an event with its data, every line of the data is sent as data field.
+func writeServerSentEvent(w http.ResponseWriter, event string, data string) error {
+	var message strings.Builder
+	message.WriteString("event: " + event + "\n")
+	for _, line := range strings.Split(data, "\n") {
+		message.WriteString("data: " + line + "\n")
+	}
+	message.WriteString("\n")
+
+	_, err := w.Write([]byte(message.String()))
+	return err
+}
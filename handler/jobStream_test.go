@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobStream(t *testing.T) {
+	t.Run("Publish sends the job to all subscribers", func(t *testing.T) {
+		stream := NewJobStream()
+		firstJobs, unsubscribeFirst := stream.Subscribe()
+		secondJobs, unsubscribeSecond := stream.Subscribe()
+
+		job := &model.Job{RID: uuid.New()}
+		stream.Publish(job)
+		assert.Equal(t, job, <-firstJobs)
+		assert.Equal(t, job, <-secondJobs)
+
+		unsubscribeFirst()
+		stream.Publish(&model.Job{RID: uuid.New()})
+		assert.Empty(t, firstJobs, "Expected no job after unsubscribing")
+		assert.Len(t, secondJobs, 1)
+		unsubscribeSecond()
+	})
+
+	t.Run("Publish does not block on full subscribers", func(t *testing.T) {
+		stream := NewJobStream()
+		jobs, unsubscribe := stream.Subscribe()
+		defer unsubscribe()
+
+		for i := 0; i < jobStreamBuffer+10; i++ {
+			stream.Publish(&model.Job{RID: uuid.New()})
+		}
+		assert.Len(t, jobs, jobStreamBuffer)
+	})
+}
+
+func TestJobEventsHandler(t *testing.T) {
+	handler := NewManagerHandler(upload.NewFilesystemMemory(), nil, nil)
+	e := echo.New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/events/jobs", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	done := make(chan error)
+	go func() {
+		done <- handler.JobEvents(c)
+	}()
+
+	require.Eventually(t, func() bool {
+		handler.JobStream.mu.Lock()
+		defer handler.JobStream.mu.Unlock()
+		return len(handler.JobStream.subscribers) == 1
+	}, time.Second, 10*time.Millisecond, "Expected the handler to subscribe to the job stream")
+
+	runningJob := &model.Job{RID: uuid.New(), TaskName: "stream-task", Status: model.JobStatusRunning}
+	endedJob := &model.Job{RID: uuid.New(), TaskName: "stream-task", Status: model.JobStatusSucceeded}
+	handler.JobStream.Publish(runningJob)
+	handler.JobStream.Publish(endedJob)
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get(echo.HeaderContentType))
+	body := rec.Body.String()
+	assert.Contains(t, body, "event: job\ndata: ")
+	assert.Contains(t, body, "table_row_"+runningJob.RID.String())
+	assert.Contains(t, body, "event: jobEnded\ndata: "+endedJob.RID.String()+"\n\n")
+
+	handler.JobStream.mu.Lock()
+	defer handler.JobStream.mu.Unlock()
+	assert.Empty(t, handler.JobStream.subscribers, "Expected the handler to unsubscribe")
+}
@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	vm "github.com/siherrmann/validator/model"
+)
+
+// =======API Handlers=======
+
+// SaveJobTemplate saves the parameter values as named job template of the task with the key of the path.
+// A JSON body has the name and the values, the add job form is sent with the name as HX-Prompt header.
+// Only the values of input parameters of the task are saved, a template of the task with the same name is replaced.
+func (m *ManagerHandler) SaveJobTemplate(c *echo.Context) error {
+	if m.JobTemplateDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Job templates are not enabled")
+	}
+
+	task, err := m.taskDB.SelectTaskByKey(c.Param("taskKey"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "Task not found")
+	}
+
+	var jobTemplateRequest qmModel.JobTemplateRequest
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		if err := c.Bind(&jobTemplateRequest); err != nil {
+			return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		}
+	} else {
+		form, err := c.FormValues()
+		if err != nil {
+			return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid form: %v", err))
+		}
+		jobTemplateRequest.Name = c.Request().Header.Get("HX-Prompt")
+		jobTemplateRequest.Values = map[string]string{}
+		for key := range form {
+			jobTemplateRequest.Values[key] = form.Get(key)
+		}
+	}
+
+	jobTemplateRequest.Name = strings.TrimSpace(jobTemplateRequest.Name)
+	if jobTemplateRequest.Name == "" || len(jobTemplateRequest.Name) > 100 {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Template name is required (max 100 characters)")
+	}
+
+	values := map[string]string{}
+	validations := append([]vm.Validation{}, task.InputParameters...)
+	validations = append(validations, task.InputParametersKeyed...)
+	for _, v := range validations {
+		if value, ok := jobTemplateRequest.Values[v.Key]; ok {
+			values[v.Key] = value
+		}
+	}
+
+	_, err = m.validateJobParameters(task, jobTemplateValuesRequest(values))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Validation error: %v", err))
+	}
+
+	jobTemplate, err := m.JobTemplateDB.UpsertJobTemplate(&qmModel.JobTemplate{
+		Name:      jobTemplateRequest.Name,
+		TaskKey:   task.Key,
+		Values:    values,
+		CreatedBy: requestedBy(c),
+	})
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to save job template: %v", err))
+	}
+
+	if c.Request().Header.Get("HX-Request") != "" {
+		return m.renderJobTemplatesWithPopup(c, task.Key, fmt.Sprintf("Saved the job template %s", jobTemplate.Name))
+	}
+	return c.JSON(http.StatusOK, jobTemplate)
+}
+
+// GetJobTemplates retrieves the job templates of the task with the key of the path.
+func (m *ManagerHandler) GetJobTemplates(c *echo.Context) error {
+	if m.JobTemplateDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Job templates are not enabled")
+	}
+
+	jobTemplates, err := m.JobTemplateDB.SelectJobTemplatesByTask(c.Param("taskKey"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get job templates: %v", err))
+	}
+
+	return renderPopupOrJson(c, http.StatusOK, jobTemplates)
+}
+
+// RunJobTemplate adds a job of the task of the job template with the RID of the path with the values of the template.
+// The values are validated like the values of the add job form, as the task may have changed since saving the template.
+func (m *ManagerHandler) RunJobTemplate(c *echo.Context) error {
+	if m.JobTemplateDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Job templates are not enabled")
+	}
+
+	rid, err := uuid.Parse(c.Param("rid"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid job template RID format")
+	}
+
+	jobTemplate, err := m.JobTemplateDB.SelectJobTemplate(rid)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "Job template not found")
+	}
+
+	task, err := m.taskDB.SelectTaskByKey(jobTemplate.TaskKey)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "Task not found")
+	}
+
+	parametersList, parametersKeyed, err := m.resolveJobParameters(c.Request().Context(), task, jobTemplateValuesRequest(jobTemplate.Values), requestedBy(c))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Validation error: %v", err))
+	}
+
+	versionWarning, block, err := m.checkWorkerVersion(task)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to check worker version: %v", err))
+	}
+	if block {
+		return renderPopupOrJson(c, http.StatusConflict, versionWarning)
+	}
+	if versionWarning != "" {
+		log.Printf("Warning: %s", versionWarning)
+		c.Response().Header().Add("X-Worker-Version-Warning", versionWarning)
+	}
+
+	if rule := m.Forwarder.Rule(task.Key); rule != nil {
+		return m.forwardJob(c, task, rule, parametersList, parametersKeyed)
+	}
+
+	jobAdded, deduplicated, err := m.addTaskJob(task, parametersList, parametersKeyed)
+	if err != nil {
+		return renderPopupOrJson(c, addJobErrorStatus(c, err), fmt.Sprintf("Failed to add job: %v", err))
+	}
+	if deduplicated {
+		c.Response().Header().Set(HEADER_JOB_DEDUPLICATED, "true")
+	} else {
+		m.recordJobInitiator(jobAdded, requestedBy(c), false)
+		m.registerJobChains(task, jobAdded, false)
+		m.publishEvent(qmModel.EVENT_JOB_ADDED, jobAdded)
+	}
+
+	c.Response().Header().Add("HX-Redirect", fmt.Sprintf("/job?rid=%s", jobAdded.RID.String()))
+
+	return renderPopupOrJson(c, http.StatusOK, jobAdded)
+}
+
+// DeleteJobTemplate deletes the job template with the RID of the path.
+func (m *ManagerHandler) DeleteJobTemplate(c *echo.Context) error {
+	if m.JobTemplateDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Job templates are not enabled")
+	}
+
+	rid, err := uuid.Parse(c.Param("rid"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid job template RID format")
+	}
+
+	jobTemplate, err := m.JobTemplateDB.SelectJobTemplate(rid)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "Job template not found")
+	}
+
+	err = m.JobTemplateDB.DeleteJobTemplate(rid)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to delete job template: %v", err))
+	}
+
+	if c.Request().Header.Get("HX-Request") != "" {
+		return m.renderJobTemplatesWithPopup(c, jobTemplate.TaskKey, fmt.Sprintf("Deleted the job template %s", jobTemplate.Name))
+	}
+	return renderPopupOrJson(c, http.StatusOK, "Job template deleted successfully")
+}
+
+// =======Helpers=======
+
+// jobTemplateValuesRequest builds a form request of the values of a job template,
+// so they are validated like the values of the add job form. Without values an empty JSON object is sent.
+func jobTemplateValuesRequest(values map[string]string) *http.Request {
+	if len(values) == 0 {
+		request, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader("{}"))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		return request
+	}
+
+	form := url.Values{}
+	for key, value := range values {
+		form.Set(key, value)
+	}
+
+	request, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	return request
+}
+
+// renderJobTemplatesWithPopup swaps the job templates of the task into the add job view and shows the message as popup.
+func (m *ManagerHandler) renderJobTemplatesWithPopup(c *echo.Context, taskKey string, message string) error {
+	jobTemplates, err := m.JobTemplateDB.SelectJobTemplatesByTask(taskKey)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get job templates: %v", err))
+	}
+	return renderRowsWithPopup(c, http.StatusOK, screens.JobTemplates(jobTemplates), "#job_templates", "outerHTML", "", message)
+}
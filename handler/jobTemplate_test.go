@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuer/model"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	vm "github.com/siherrmann/validator/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobTemplateHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	jtdb, err := database.NewJobTemplateDBHandler(db, true)
+	require.NoError(t, err)
+	jidb, err := database.NewJobInitiatorDBHandler(db, true)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.JobTemplateDB = jtdb
+	handler.JobInitiatorDB = jidb
+	e := echo.New()
+
+	task, err := tdb.InsertTask(&qmModel.Task{
+		Key:                  "test-template-task",
+		Name:                 "Test Template Task",
+		InputParameters:      []vm.Validation{{Key: "count", Type: vm.Int, Requirement: "min1"}},
+		InputParametersKeyed: []vm.Validation{},
+	})
+	require.NoError(t, err)
+
+	var jobTemplate qmModel.JobTemplate
+	t.Run("Save job template from JSON", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/job/saveJobTemplate/"+task.Key, strings.NewReader(`{"name": "three", "values": {"count": "3", "unknown": "ignored"}}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: task.Key}})
+
+		require.NoError(t, handler.SaveJobTemplate(c))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &jobTemplate))
+		assert.Equal(t, "three", jobTemplate.Name)
+		assert.Equal(t, map[string]string{"count": "3"}, jobTemplate.Values)
+	})
+
+	t.Run("Save job template from the add job form", func(t *testing.T) {
+		form := url.Values{"count": {"7"}}
+		req := httptest.NewRequest(http.MethodPost, "/api/job/saveJobTemplate/"+task.Key, strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		req.Header.Set("HX-Request", "true")
+		req.Header.Set("HX-Prompt", "seven")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: task.Key}})
+
+		require.NoError(t, handler.SaveJobTemplate(c))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, "#job_templates", rec.Header().Get("HX-Retarget"))
+		assert.Contains(t, rec.Body.String(), "seven")
+		assert.Contains(t, rec.Body.String(), "three")
+	})
+
+	t.Run("Save invalid job template", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/job/saveJobTemplate/"+task.Key, strings.NewReader(`{"name": "zero", "values": {"count": "0"}}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: task.Key}})
+
+		require.NoError(t, handler.SaveJobTemplate(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Validation error")
+
+		req = httptest.NewRequest(http.MethodPost, "/api/job/saveJobTemplate/"+task.Key, strings.NewReader(`{"name": " ", "values": {"count": "3"}}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec = httptest.NewRecorder()
+		c = e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: task.Key}})
+
+		require.NoError(t, handler.SaveJobTemplate(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Template name is required")
+	})
+
+	t.Run("AddJobConfigView lists the job templates", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/task/"+task.Key, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: task.Key}})
+
+		require.NoError(t, handler.AddJobConfigView(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "/api/job/runTemplate/"+jobTemplate.RID.String())
+	})
+
+	t.Run("Run job template", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/job/runTemplate/"+jobTemplate.RID.String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "rid", Value: jobTemplate.RID.String()}})
+
+		require.NoError(t, handler.RunJobTemplate(c))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var addedJob model.Job
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &addedJob))
+		job, _, err := handler.jobWithEnded(addedJob.RID)
+		require.NoError(t, err)
+		assert.Equal(t, task.Key, job.TaskName)
+		assert.Equal(t, []any{float64(3)}, []any(job.Parameters))
+	})
+
+	t.Run("Delete job template", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/job/deleteJobTemplate/"+jobTemplate.RID.String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "rid", Value: jobTemplate.RID.String()}})
+
+		require.NoError(t, handler.DeleteJobTemplate(c))
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		req = httptest.NewRequest(http.MethodPost, "/api/job/runTemplate/"+jobTemplate.RID.String(), nil)
+		rec = httptest.NewRecorder()
+		c = e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "rid", Value: jobTemplate.RID.String()}})
+
+		require.NoError(t, handler.RunJobTemplate(c))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+)
+
+// GetJobTimeline retrieves the timeline of a job with the queue waits and the durations of its attempts.
+func (m *ManagerHandler) GetJobTimeline(c *echo.Context) error {
+	rid, err := uuid.Parse(c.Param("rid"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid job RID format")
+	}
+
+	job, ended, err := m.jobWithEnded(rid)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "Job not found")
+	}
+
+	timeline, err := m.jobTimeline(job, ended)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, "Failed to get job timeline: "+err.Error())
+	}
+
+	return renderPopupOrJson(c, http.StatusOK, timeline)
+}
+
+// RecordJobAttempt records the attempts of a changed job, a running job starts an attempt
+// and any other status ends the running attempt of the job.
+func (m *ManagerHandler) RecordJobAttempt(job *model.Job) error {
+	if m.JobAttemptDB == nil || job == nil {
+		return nil
+	}
+
+	if job.Status == model.JobStatusRunning {
+		if job.StartedAt == nil || job.StartedAt.IsZero() {
+			return nil
+		}
+		return m.JobAttemptDB.InsertJobAttempt(&qmModel.JobAttempt{
+			JobRID:    job.RID,
+			Attempt:   max(job.Attempts, 1),
+			WorkerRID: job.WorkerRID,
+			Status:    job.Status,
+			StartedAt: *job.StartedAt,
+		})
+	}
+	return m.JobAttemptDB.EndJobAttempts(job.RID, job.Status, job.UpdatedAt)
+}
+
+// jobTimeline builds the timeline of the job from its recorded attempts.
+func (m *ManagerHandler) jobTimeline(job *model.Job, ended bool) (*qmModel.JobTimeline, error) {
+	attempts := []*qmModel.JobAttempt{}
+	if m.JobAttemptDB != nil {
+		var err error
+		attempts, err = m.JobAttemptDB.SelectJobAttempts(job.RID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return qmModel.NewJobTimeline(job, ended, attempts, time.Now()), nil
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuer/model"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJobTimeline(t *testing.T) {
+	createdAt := time.Date(2026, time.March, 13, 10, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return createdAt.Add(time.Duration(seconds) * time.Second) }
+	atPtr := func(seconds int) *time.Time { t := at(seconds); return &t }
+	workerRID := uuid.New()
+
+	t.Run("Ended job with retried attempts", func(t *testing.T) {
+		job := &model.Job{RID: uuid.New(), TaskName: "task", Status: model.JobStatusSucceeded, Attempts: 2, CreatedAt: createdAt, StartedAt: atPtr(40), UpdatedAt: at(60)}
+		attempts := []*qmModel.JobAttempt{
+			{Attempt: 1, WorkerRID: workerRID, Status: model.JobStatusFailed, StartedAt: at(10), EndedAt: atPtr(20)},
+			{Attempt: 2, WorkerRID: workerRID, Status: model.JobStatusRunning, StartedAt: at(40)},
+		}
+
+		timeline := qmModel.NewJobTimeline(job, true, attempts, at(100))
+		require.Len(t, timeline.Segments, 4)
+		assert.Equal(t, []string{qmModel.JOB_TIMELINE_QUEUED, qmModel.JOB_TIMELINE_ATTEMPT, qmModel.JOB_TIMELINE_QUEUED, qmModel.JOB_TIMELINE_ATTEMPT}, []string{timeline.Segments[0].Kind, timeline.Segments[1].Kind, timeline.Segments[2].Kind, timeline.Segments[3].Kind})
+		assert.Equal(t, model.JobStatusSucceeded, timeline.Segments[3].Status, "Expected the last attempt to end with the job")
+		assert.Equal(t, float64(30), timeline.QueueWaitSeconds)
+		assert.Equal(t, float64(30), timeline.RunSeconds)
+		assert.Equal(t, float64(60), timeline.TotalSeconds)
+		assert.Equal(t, at(10), *timeline.StartedAt)
+		assert.Equal(t, at(60), *timeline.FinishedAt)
+		assert.Equal(t, 2, timeline.Attempts)
+	})
+
+	t.Run("Running job without recorded attempts", func(t *testing.T) {
+		job := &model.Job{RID: uuid.New(), Status: model.JobStatusRunning, Attempts: 1, WorkerRID: workerRID, CreatedAt: createdAt, StartedAt: atPtr(5), UpdatedAt: at(5)}
+
+		timeline := qmModel.NewJobTimeline(job, false, nil, at(20))
+		require.Len(t, timeline.Segments, 2)
+		assert.Equal(t, qmModel.JOB_TIMELINE_ATTEMPT, timeline.Segments[1].Kind)
+		assert.Nil(t, timeline.Segments[1].EndedAt, "Expected the running attempt to have no end")
+		assert.Equal(t, float64(15), timeline.RunSeconds)
+		assert.Nil(t, timeline.FinishedAt)
+	})
+
+	t.Run("Scheduled job waiting in the queue", func(t *testing.T) {
+		job := &model.Job{RID: uuid.New(), Status: model.JobStatusQueued, CreatedAt: createdAt, ScheduledAt: atPtr(30), UpdatedAt: at(30)}
+
+		timeline := qmModel.NewJobTimeline(job, false, nil, at(50))
+		require.Len(t, timeline.Segments, 2)
+		assert.Equal(t, qmModel.JOB_TIMELINE_SCHEDULED, timeline.Segments[0].Kind)
+		assert.Equal(t, float64(30), timeline.Segments[0].DurationSeconds)
+		assert.Equal(t, float64(20), timeline.QueueWaitSeconds, "Expected the wait for the scheduled time to not be a queue wait")
+		assert.Nil(t, timeline.StartedAt)
+	})
+}
+
+func TestJobTimelineHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	jadb, err := database.NewJobAttemptDBHandler(db, true)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.JobAttemptDB = jadb
+	e := echo.New()
+
+	job, err := queue.AddJob("test-task", nil, 10)
+	require.NoError(t, err)
+
+	startedAt := time.Now().Add(-time.Minute)
+	runningJob := *job
+	runningJob.Status = model.JobStatusRunning
+	runningJob.Attempts = 1
+	runningJob.StartedAt = &startedAt
+	require.NoError(t, handler.RecordJobAttempt(&runningJob))
+
+	failedJob := runningJob
+	failedJob.Status = model.JobStatusQueued
+	failedJob.UpdatedAt = startedAt.Add(10 * time.Second)
+	require.NoError(t, handler.RecordJobAttempt(&failedJob))
+
+	t.Run("Get job timeline", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/job/getJobTimeline/"+job.RID.String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "rid", Value: job.RID.String()}})
+
+		require.NoError(t, handler.GetJobTimeline(c))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var timeline qmModel.JobTimeline
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &timeline))
+		assert.Equal(t, job.RID, timeline.JobRID)
+		assert.Equal(t, 1, timeline.Attempts)
+		assert.InDelta(t, 10, timeline.RunSeconds, 0.001)
+	})
+
+	t.Run("Get timeline of unknown job", func(t *testing.T) {
+		rid := uuid.New().String()
+		req := httptest.NewRequest(http.MethodPost, "/api/job/getJobTimeline/"+rid, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "rid", Value: rid}})
+
+		require.NoError(t, handler.GetJobTimeline(c))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
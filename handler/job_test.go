@@ -95,6 +95,265 @@ func TestAddJobHandler(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, rec.Code)
 		assert.Contains(t, rec.Body.String(), "Task not found")
 	})
+
+	t.Run("AddJob with dry run does not add a job", func(t *testing.T) {
+		task, err := tdb.InsertTask(&qmModel.Task{
+			Key:                  "test-job-task-dry-run",
+			Name:                 "Test Job Task Dry Run",
+			InputParameters:      []vm.Validation{{Key: "count", Type: vm.Int, Requirement: "min1"}},
+			InputParametersKeyed: []vm.Validation{},
+		})
+		require.NoError(t, err)
+
+		jobsBefore, _ := queue.GetJobs(0, 100)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/job/addJob/"+task.Key+"?dryRun=true", strings.NewReader(`{"count": 3}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: task.Key}})
+
+		err = handler.AddJob(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var dryRun qmModel.JobDryRun
+		err = json.Unmarshal(rec.Body.Bytes(), &dryRun)
+		require.NoError(t, err)
+		assert.Equal(t, task.Key, dryRun.TaskKey)
+		assert.Len(t, dryRun.Parameters, 1)
+
+		jobsAfter, _ := queue.GetJobs(0, 100)
+		assert.Equal(t, len(jobsBefore), len(jobsAfter), "Dry run should not add a job")
+	})
+
+	t.Run("AddJob with dry run and invalid parameters", func(t *testing.T) {
+		task, err := tdb.SelectTaskByKey("test-job-task-dry-run")
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/job/addJob/"+task.Key+"?dryRun=true", strings.NewReader(`{"count": 0}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: task.Key}})
+
+		err = handler.AddJob(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Validation error")
+	})
+
+	t.Run("AddJob blocked by unsatisfied min worker version", func(t *testing.T) {
+		task, err := tdb.InsertTask(&qmModel.Task{
+			Key:                 "test-job-task-version-block",
+			Name:                "Test Job Task Version Block",
+			MinWorkerVersion:    "99.0.0",
+			WorkerVersionPolicy: qmModel.WORKER_VERSION_POLICY_BLOCK,
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/job/addJob/"+task.Key, strings.NewReader("{}"))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: task.Key}})
+
+		err = handler.AddJob(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Contains(t, rec.Body.String(), "minimum worker version 99.0.0")
+	})
+
+	t.Run("AddJob warns on unsatisfied min worker version", func(t *testing.T) {
+		task, err := tdb.InsertTask(&qmModel.Task{
+			Key:                 "test-job-task-version-warn",
+			Name:                "Test Job Task Version Warn",
+			MinWorkerVersion:    "99.0.0",
+			WorkerVersionPolicy: qmModel.WORKER_VERSION_POLICY_WARN,
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/job/addJob/"+task.Key, strings.NewReader("{}"))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: task.Key}})
+
+		err = handler.AddJob(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get("X-Worker-Version-Warning"), "minimum worker version 99.0.0")
+	})
+
+	t.Run("AddJob with oversized parameters", func(t *testing.T) {
+		task, err := tdb.InsertTask(&qmModel.Task{
+			Key:                  "test-job-task-payload-limit",
+			Name:                 "Test Job Task Payload Limit",
+			InputParameters:      []vm.Validation{{Key: "text", Type: vm.String, Requirement: "min1"}},
+			InputParametersKeyed: []vm.Validation{},
+		})
+		require.NoError(t, err)
+
+		handler.MaxJobPayloadBytes = 100
+		defer func() { handler.MaxJobPayloadBytes = DEFAULT_MAX_JOB_PAYLOAD_BYTES }()
+
+		body := fmt.Sprintf(`{"text": "%s"}`, strings.Repeat("a", 200))
+		req := httptest.NewRequest(http.MethodPost, "/api/job/addJob/"+task.Key, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: task.Key}})
+
+		err = handler.AddJob(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.Contains(t, rec.Body.String(), "exceed the limit of 100 bytes")
+	})
+
+	t.Run("AddJob with custom validations", func(t *testing.T) {
+		handler.AddValidationFunc("even", func(value any) error {
+			if fmt.Sprint(value) != "2" && fmt.Sprint(value) != "4" {
+				return fmt.Errorf("value %v is not even", value)
+			}
+			return nil
+		})
+
+		task, err := tdb.InsertTask(&qmModel.Task{
+			Key:  "test-job-task-custom-validation",
+			Name: "Test Job Task Custom Validation",
+			InputParameters: []vm.Validation{
+				{Key: "schedule", Type: vm.String, Requirement: "cron"},
+				{Key: "count", Type: vm.Int, Requirement: "min1 && even"},
+			},
+			InputParametersKeyed: []vm.Validation{},
+		})
+		require.NoError(t, err)
+
+		testCases := []struct {
+			name         string
+			body         string
+			expectedCode int
+		}{
+			{"valid parameters", `{"schedule": "*/15 * * * 1-5", "count": 2}`, http.StatusOK},
+			{"invalid cron expression", `{"schedule": "every minute", "count": 2}`, http.StatusBadRequest},
+			{"invalid custom requirement", `{"schedule": "0 0 * * *", "count": 3}`, http.StatusBadRequest},
+		}
+		for _, testCase := range testCases {
+			req := httptest.NewRequest(http.MethodPost, "/api/job/addJob/"+task.Key+"?dryRun=true", strings.NewReader(testCase.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: task.Key}})
+
+			err = handler.AddJob(c)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedCode, rec.Code, testCase.name)
+		}
+	})
+}
+
+func TestCreateJobHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	e := echo.New()
+
+	task, err := tdb.InsertTask(&qmModel.Task{
+		Key:                  "test-create-job-task",
+		Name:                 "Test Create Job Task",
+		InputParameters:      []vm.Validation{{Key: "count", Type: vm.Int, Requirement: "min1"}},
+		InputParametersKeyed: []vm.Validation{},
+	})
+	require.NoError(t, err)
+
+	t.Run("CreateJob with valid parameters", func(t *testing.T) {
+		body := fmt.Sprintf(`{"task_key": "%s", "parameters": {"count": 3}}`, task.Key)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.CreateJob(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Empty(t, rec.Header().Get("HX-Redirect"))
+
+		var job model.Job
+		err = json.Unmarshal(rec.Body.Bytes(), &job)
+		require.NoError(t, err)
+		assert.Equal(t, task.Key, job.TaskName)
+		assert.NotEqual(t, uuid.Nil, job.RID)
+
+		addedJob, err := queue.GetJob(job.RID)
+		require.NoError(t, err, "Expected the created job to be queued")
+		assert.Equal(t, task.Key, addedJob.TaskName)
+	})
+
+	t.Run("CreateJob with dry run does not add a job", func(t *testing.T) {
+		jobsBefore, _ := queue.GetJobs(0, 100)
+
+		body := fmt.Sprintf(`{"task_key": "%s", "parameters": {"count": 3}, "dry_run": true}`, task.Key)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.CreateJob(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var dryRun qmModel.JobDryRun
+		err = json.Unmarshal(rec.Body.Bytes(), &dryRun)
+		require.NoError(t, err)
+		assert.Len(t, dryRun.Parameters, 1)
+
+		jobsAfter, _ := queue.GetJobs(0, 100)
+		assert.Equal(t, len(jobsBefore), len(jobsAfter), "Dry run should not add a job")
+	})
+
+	t.Run("CreateJob with invalid parameters", func(t *testing.T) {
+		body := fmt.Sprintf(`{"task_key": "%s", "parameters": {"count": 0}}`, task.Key)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.CreateJob(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Validation error")
+	})
+
+	t.Run("CreateJob with non-existent task", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs", strings.NewReader(`{"task_key": "NonExistentTask"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.CreateJob(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Task not found")
+	})
+
+	t.Run("CreateJob without task key or with invalid JSON", func(t *testing.T) {
+		for _, body := range []string{`{"parameters": {}}`, `{"task_key":`} {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs", strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.CreateJob(c)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+			assert.Contains(t, rec.Body.String(), "error", body)
+		}
+	})
 }
 
 func TestGetJobHandler(t *testing.T) {
@@ -233,6 +492,36 @@ func TestGetJobsHandler(t *testing.T) {
 	})
 }
 
+func TestJobFilterFromQuery(t *testing.T) {
+	e := echo.New()
+	filterFromQuery := func(query string) (*qmModel.JobFilter, error) {
+		req := httptest.NewRequest(http.MethodGet, "/jobs?"+query, nil)
+		return jobFilterFromQuery(e.NewContext(req, httptest.NewRecorder()))
+	}
+
+	filter, err := filterFromQuery("search=export")
+	require.NoError(t, err)
+	assert.False(t, filter.HasFilters(), "Expected the search to not be a filter")
+
+	filter, err = filterFromQuery("status=FAILED&taskKey=export&from=2026-01-01T10:00:00Z&to=2026-01-01T11:00:00Z")
+	require.NoError(t, err)
+	assert.True(t, filter.HasFilters())
+	assert.Equal(t, model.JobStatusFailed, filter.Status)
+	assert.Equal(t, "export", filter.TaskKey)
+	assert.Equal(t, time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC), filter.From)
+	assert.Equal(t, time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC), filter.To)
+	assert.Equal(t, "from=2026-01-01T10%3A00%3A00Z&status=FAILED&taskKey=export&to=2026-01-01T11%3A00%3A00Z", filter.Query().Encode())
+
+	_, err = filterFromQuery("status=SUCCEEDED")
+	assert.ErrorContains(t, err, "Invalid status")
+
+	_, err = filterFromQuery("from=yesterday")
+	assert.EqualError(t, err, "Invalid from format (must be RFC3339)")
+
+	_, err = filterFromQuery("from=2026-01-01T11:00:00Z&to=2026-01-01T10:00:00Z")
+	assert.EqualError(t, err, "Invalid time range (from must be before to)")
+}
+
 func TestCancelJobHandler(t *testing.T) {
 	fs := upload.NewFilesystemMemory()
 	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
@@ -293,6 +582,43 @@ func TestCancelJobHandler(t *testing.T) {
 	})
 }
 
+func TestKillJobHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	e := echo.New()
+
+	t.Run("KillJob with invalid RID format", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/job/killJob/invalid-uuid", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "rid", Value: "invalid-uuid"}})
+
+		err := handler.KillJob(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Invalid job RID format")
+	})
+
+	t.Run("KillJob with non-existent RID", func(t *testing.T) {
+		nonExistentRID := uuid.New()
+		req := httptest.NewRequest(http.MethodPost, "/api/job/killJob/"+nonExistentRID.String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "rid", Value: nonExistentRID.String()}})
+
+		err := handler.KillJob(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Job not found")
+	})
+}
+
 func TestCancelJobsHandler(t *testing.T) {
 	fs := upload.NewFilesystemMemory()
 	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
@@ -447,6 +773,79 @@ func TestJobViewHandler(t *testing.T) {
 	})
 }
 
+func TestJobTabViewHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	e := echo.New()
+
+	job, err := queue.AddJob("test-task", nil, 1)
+	require.NoError(t, err)
+	job = queue.WaitForJobFinished(job.RID, 5*time.Second)
+	require.NotNil(t, job)
+
+	t.Run("JobView renders the tabs without the panels", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/job?rid="+job.RID.String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.JobView(c)
+		require.NoError(t, err)
+
+		assert.Contains(t, rec.Body.String(), "/job/tab/overview?rid="+job.RID.String())
+		assert.NotContains(t, rec.Body.String(), "Job Results")
+	})
+
+	t.Run("JobTabView renders each tab", func(t *testing.T) {
+		expected := map[string]string{
+			"overview":   "Task Name",
+			"parameters": "Parameters keyed",
+			"result":     "Job Results",
+			"audit":      "Initiated By",
+			"raw":        "language-json",
+		}
+		for tab, content := range expected {
+			req := httptest.NewRequest(http.MethodGet, "/job/tab/"+tab+"?rid="+job.RID.String(), nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPathValues([]echo.PathValue{{Name: "tab", Value: tab}})
+
+			err := handler.JobTabView(c)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, rec.Code, "Expected tab %s to render", tab)
+			assert.Contains(t, rec.Body.String(), content, "Expected tab %s to contain %s", tab, content)
+		}
+	})
+
+	t.Run("JobTabView with unknown tab", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/job/tab/files?rid="+job.RID.String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "tab", Value: "files"}})
+
+		err := handler.JobTabView(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("JobTabView with invalid RID format", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/job/tab/overview?rid=invalid", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "tab", Value: "overview"}})
+
+		err := handler.JobTabView(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
 func TestJobsViewHandler(t *testing.T) {
 	fs := upload.NewFilesystemMemory()
 	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
@@ -477,6 +876,19 @@ func TestJobsViewHandler(t *testing.T) {
 		assert.Contains(t, rec.Header().Get("HX-Push-Url"), "/jobs")
 	})
 
+	t.Run("JobsView streams the response", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/jobs", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.JobsView(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, rec.Flushed, "Expected the page to be flushed while rendering")
+		assert.Contains(t, rec.Body.String(), "jobs_table")
+	})
+
 	t.Run("JobsView with search parameter", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/jobs?search=test", nil)
 		rec := httptest.NewRecorder()
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+)
+
+const SERVICE_ACCOUNT_PATH = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// KubernetesScaler scales worker deployments with the scale subresource of the Kubernetes API.
+// The service account of the manager only needs get and patch on deployments/scale,
+// so the RBAC role can be restricted to the configured deployments.
+type KubernetesScaler struct {
+	APIServer string
+	TokenPath string
+	Namespace string
+	// Deployments maps worker pools to deployment names, the empty pool is used for all other pools
+	Deployments map[string]string
+	Client      *http.Client
+}
+
+// NewKubernetesScalerFromEnv creates a scaler with the in-cluster config of the pod.
+// Deployments are configured with QUEUER_MANAGER_K8S_WORKER_DEPLOYMENT, either a single deployment name
+// or a list of pool=deployment pairs, the namespace defaults to the namespace of the service account.
+// It returns nil if no deployment is configured.
+func NewKubernetesScalerFromEnv() (*KubernetesScaler, error) {
+	deploymentConfig := os.Getenv("QUEUER_MANAGER_K8S_WORKER_DEPLOYMENT")
+	if deploymentConfig == "" {
+		return nil, nil
+	}
+
+	deployments, err := parseDeployments(deploymentConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, fmt.Errorf("kubernetes scaling needs the in-cluster config, KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT are not set")
+	}
+
+	namespace := os.Getenv("QUEUER_MANAGER_K8S_NAMESPACE")
+	if namespace == "" {
+		namespaceBytes, err := os.ReadFile(SERVICE_ACCOUNT_PATH + "/namespace")
+		if err != nil {
+			return nil, fmt.Errorf("error reading service account namespace: %w", err)
+		}
+		namespace = strings.TrimSpace(string(namespaceBytes))
+	}
+
+	caCert, err := os.ReadFile(SERVICE_ACCOUNT_PATH + "/ca.crt")
+	if err != nil {
+		return nil, fmt.Errorf("error reading service account CA certificate: %w", err)
+	}
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("error parsing service account CA certificate")
+	}
+
+	return &KubernetesScaler{
+		APIServer:   "https://" + net.JoinHostPort(host, port),
+		TokenPath:   SERVICE_ACCOUNT_PATH + "/token",
+		Namespace:   namespace,
+		Deployments: deployments,
+		Client: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}},
+		},
+	}, nil
+}
+
+// RequestScale sets the replicas of the deployment of the requested pool
+func (s *KubernetesScaler) RequestScale(ctx context.Context, request *model.ScaleRequest) error {
+	deployment, ok := s.Deployments[request.Pool]
+	if !ok {
+		deployment, ok = s.Deployments[""]
+	}
+	if !ok {
+		return fmt.Errorf("no deployment configured for pool %s", poolName(request.Pool))
+	}
+
+	// The token is read for every request because projected service account tokens are rotated
+	token, err := os.ReadFile(s.TokenPath)
+	if err != nil {
+		return fmt.Errorf("error reading service account token: %w", err)
+	}
+
+	body, err := json.Marshal(map[string]any{"spec": map[string]any{"replicas": request.DesiredCount}})
+	if err != nil {
+		return fmt.Errorf("error encoding scale patch: %w", err)
+	}
+
+	scaleURL := fmt.Sprintf("%s/apis/apps/v1/namespaces/%s/deployments/%s/scale", s.APIServer, url.PathEscape(s.Namespace), url.PathEscape(deployment))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, scaleURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating scale request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending scale request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("service account is not allowed to scale deployment %s/%s, check the RBAC role", s.Namespace, deployment)
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("deployment %s/%s not found", s.Namespace, deployment)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("kubernetes API responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// parseDeployments parses a single deployment name or a comma separated list of pool=deployment pairs.
+func parseDeployments(config string) (map[string]string, error) {
+	deployments := map[string]string{}
+	for _, entry := range strings.Split(config, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		pool, deployment, found := strings.Cut(entry, "=")
+		if !found {
+			pool, deployment = "", pool
+		}
+		pool, deployment = strings.TrimSpace(pool), strings.TrimSpace(deployment)
+		if deployment == "" {
+			return nil, fmt.Errorf("invalid worker deployment entry %q", entry)
+		}
+		deployments[pool] = deployment
+	}
+	return deployments, nil
+}
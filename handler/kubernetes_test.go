@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesScaler(t *testing.T) {
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	err := os.WriteFile(tokenPath, []byte("test-token\n"), 0600)
+	require.NoError(t, err)
+
+	var receivedPath, receivedContentType, receivedAuthorization string
+	var receivedPatch map[string]map[string]int
+	apiServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/apis/apps/v1/namespaces/workers/deployments/forbidden-worker/scale" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		receivedPath = r.URL.Path
+		receivedContentType = r.Header.Get("Content-Type")
+		receivedAuthorization = r.Header.Get("Authorization")
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		err = json.Unmarshal(body, &receivedPatch)
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer apiServer.Close()
+
+	deployments, err := parseDeployments("queuer-worker, gpu=gpu-worker, locked=forbidden-worker")
+	require.NoError(t, err)
+
+	scaler := &KubernetesScaler{
+		APIServer:   apiServer.URL,
+		TokenPath:   tokenPath,
+		Namespace:   "workers",
+		Deployments: deployments,
+		Client:      apiServer.Client(),
+	}
+
+	t.Run("RequestScale patches the deployment of the pool", func(t *testing.T) {
+		err := scaler.RequestScale(context.Background(), &qmModel.ScaleRequest{Pool: "gpu", DesiredCount: 4})
+		require.NoError(t, err)
+		assert.Equal(t, "/apis/apps/v1/namespaces/workers/deployments/gpu-worker/scale", receivedPath)
+		assert.Equal(t, "application/merge-patch+json", receivedContentType)
+		assert.Equal(t, "Bearer test-token", receivedAuthorization)
+		assert.Equal(t, 4, receivedPatch["spec"]["replicas"])
+	})
+
+	t.Run("RequestScale uses the default deployment for other pools", func(t *testing.T) {
+		err := scaler.RequestScale(context.Background(), &qmModel.ScaleRequest{Pool: "other", DesiredCount: 1})
+		require.NoError(t, err)
+		assert.Equal(t, "/apis/apps/v1/namespaces/workers/deployments/queuer-worker/scale", receivedPath)
+		assert.Equal(t, 1, receivedPatch["spec"]["replicas"])
+	})
+
+	t.Run("RequestScale without RBAC permission", func(t *testing.T) {
+		err := scaler.RequestScale(context.Background(), &qmModel.ScaleRequest{Pool: "locked", DesiredCount: 2})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "RBAC")
+	})
+
+	t.Run("RequestScale without configured deployment", func(t *testing.T) {
+		scalerWithoutDefault := &KubernetesScaler{
+			APIServer:   apiServer.URL,
+			TokenPath:   tokenPath,
+			Namespace:   "workers",
+			Deployments: map[string]string{"gpu": "gpu-worker"},
+			Client:      apiServer.Client(),
+		}
+
+		err := scalerWithoutDefault.RequestScale(context.Background(), &qmModel.ScaleRequest{Pool: "other", DesiredCount: 2})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no deployment configured")
+	})
+
+	t.Run("parseDeployments with invalid entry", func(t *testing.T) {
+		_, err := parseDeployments("gpu=")
+		assert.Error(t, err)
+	})
+}
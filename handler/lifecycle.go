@@ -0,0 +1,16 @@
+package handler
+
+// BeginShutdown ends the job and watch event streams and the CI long polls, so the HTTP server can drain
+// the remaining requests instead of waiting for the open streams until the drain timeout.
+func (m *ManagerHandler) BeginShutdown() {
+	m.shutdownOnce.Do(func() {
+		if m.shutdown != nil {
+			close(m.shutdown)
+		}
+	})
+}
+
+// ShuttingDown returns a channel that is closed when the shutdown of the manager began.
+func (m *ManagerHandler) ShuttingDown() <-chan struct{} {
+	return m.shutdown
+}
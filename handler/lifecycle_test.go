@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBeginShutdown(t *testing.T) {
+	t.Run("BeginShutdown can be called more than once", func(t *testing.T) {
+		handler := &ManagerHandler{shutdown: make(chan struct{})}
+		handler.BeginShutdown()
+		handler.BeginShutdown()
+
+		select {
+		case <-handler.ShuttingDown():
+		default:
+			t.Fatal("Expected the shutdown channel to be closed")
+		}
+	})
+
+	t.Run("BeginShutdown ends the open job event streams", func(t *testing.T) {
+		handler := &ManagerHandler{JobStream: NewJobStream(), shutdown: make(chan struct{})}
+
+		req := httptest.NewRequest(http.MethodGet, "/events/jobs", nil)
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+
+		done := make(chan error)
+		go func() {
+			done <- handler.JobEvents(c)
+		}()
+
+		handler.BeginShutdown()
+		select {
+		case err := <-done:
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected the job event stream to end on shutdown")
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/siherrmann/queuerManager/helper"
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	queuerModel "github.com/siherrmann/queuer/model"
+	vm "github.com/siherrmann/validator/model"
+)
+
+// loadTestBatchSize is the number of synthetic jobs inserted with one batch insert.
+const loadTestBatchSize = 1000
+
+// LoadTestEnabled reports if the dev-only load test mode is enabled with QUEUER_MANAGER_LOAD_TEST=true.
+func LoadTestEnabled() bool {
+	return helper.GetEnvOrDefault("QUEUER_MANAGER_LOAD_TEST", "false") == "true"
+}
+
+// LoadTestTask is the task function executed by synthetic load test jobs, it is registered with model.LOAD_TEST_TASK_KEY.
+// It sleeps for the given duration and fails if requested.
+func LoadTestTask(durationMs int, fail bool) error {
+	time.Sleep(time.Duration(durationMs) * time.Millisecond)
+	if fail {
+		return fmt.Errorf("synthetic load test failure")
+	}
+	return nil
+}
+
+// GenerateLoadTest generates synthetic tasks and jobs to reproducibly measure list views and the DB layer.
+// The synthetic tasks only fill the task lists, the jobs are added to the load test task in batches, so they are run by the task function.
+func (m *ManagerHandler) GenerateLoadTest(c *echo.Context) error {
+	var requestData struct {
+		Tasks         int     `json:"tasks" form:"tasks" query:"tasks"`
+		Jobs          int     `json:"jobs" form:"jobs" query:"jobs"`
+		FailureRate   float64 `json:"failure_rate" form:"failure_rate" query:"failure_rate"`
+		MinDurationMs int     `json:"min_duration_ms" form:"min_duration_ms" query:"min_duration_ms"`
+		MaxDurationMs int     `json:"max_duration_ms" form:"max_duration_ms" query:"max_duration_ms"`
+		Seed          uint64  `json:"seed" form:"seed" query:"seed"`
+	}
+
+	if err := c.Bind(&requestData); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+	}
+
+	if requestData.Tasks < 0 || requestData.Tasks > 10000 {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid tasks (must be 0-10000)")
+	}
+	if requestData.Jobs < 0 || requestData.Jobs > 100000 {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid jobs (must be 0-100000)")
+	}
+	if requestData.FailureRate < 0 || requestData.FailureRate > 1 {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid failure_rate (must be 0-1)")
+	}
+	if requestData.MinDurationMs < 0 || requestData.MaxDurationMs < requestData.MinDurationMs {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid durations (must be 0 <= min_duration_ms <= max_duration_ms)")
+	}
+
+	// The same seed generates the same failures and durations
+	seed := requestData.Seed
+	if seed == 0 {
+		seed = uint64(time.Now().UnixNano())
+	}
+	random := rand.New(rand.NewPCG(seed, seed))
+
+	start := time.Now()
+	result := &model.LoadTestResult{
+		RunID: uuid.New().String()[:8],
+	}
+
+	loadTestTask := *model.LoadTestTask
+	_, _, err := m.taskDB.UpsertTask(&loadTestTask)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to register load test task: %v", err))
+	}
+
+	for i := 0; i < requestData.Tasks; i++ {
+		task := &model.Task{
+			Key:             fmt.Sprintf("%s-%s-%d", model.LOAD_TEST_TASK_KEY, result.RunID, i),
+			Name:            fmt.Sprintf("Load Test Task %s %d", result.RunID, i),
+			Description:     "Synthetic task generated by the load test mode, its jobs are added to the load test task",
+			InputParameters: append([]vm.Validation{}, model.LoadTestTask.InputParameters...),
+		}
+		_, err := m.taskDB.InsertTask(task)
+		if err != nil {
+			return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to add synthetic task %d: %v", i, err))
+		}
+		result.TasksCreated++
+	}
+
+	batchJobs := []queuerModel.BatchJob{}
+	jobsFailing := 0
+	for i := 0; i < requestData.Jobs; i++ {
+		durationMs := requestData.MinDurationMs
+		if requestData.MaxDurationMs > requestData.MinDurationMs {
+			durationMs += random.IntN(requestData.MaxDurationMs - requestData.MinDurationMs + 1)
+		}
+		fail := random.Float64() < requestData.FailureRate
+
+		batchJobs = append(batchJobs, queuerModel.BatchJob{
+			Task:       model.LOAD_TEST_TASK_KEY,
+			Parameters: []interface{}{durationMs, fail},
+		})
+		if fail {
+			jobsFailing++
+		}
+
+		if len(batchJobs) == loadTestBatchSize || i == requestData.Jobs-1 {
+			err := m.Queuer.AddJobs(batchJobs)
+			if err != nil {
+				return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to add synthetic jobs %d-%d: %v", result.JobsCreated, i, err))
+			}
+			result.JobsCreated += len(batchJobs)
+			result.JobsFailing += jobsFailing
+			batchJobs = []queuerModel.BatchJob{}
+			jobsFailing = 0
+		}
+	}
+
+	result.Duration = time.Since(start)
+
+	return renderPopupOrJson(c, http.StatusOK, result)
+}
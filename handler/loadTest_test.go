@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/testutil/fake"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateLoadTestHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	e := echo.New()
+
+	t.Run("GenerateLoadTest with valid data", func(t *testing.T) {
+		formData := strings.NewReader("tasks=3&jobs=5&failure_rate=1&min_duration_ms=1&max_duration_ms=5&seed=42")
+
+		req := httptest.NewRequest(http.MethodPost, "/api/loadTest/generate", formData)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GenerateLoadTest(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var result qmModel.LoadTestResult
+		err = json.Unmarshal(rec.Body.Bytes(), &result)
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.TasksCreated)
+		assert.Equal(t, 5, result.JobsCreated)
+		assert.Equal(t, 5, result.JobsFailing)
+
+		tasks, err := tdb.SelectAllTasksBySearch(result.RunID, "", 0, 100)
+		require.NoError(t, err)
+		assert.Len(t, tasks, 3)
+	})
+
+	t.Run("GenerateLoadTest with invalid failure rate", func(t *testing.T) {
+		formData := strings.NewReader("jobs=5&failure_rate=2")
+
+		req := httptest.NewRequest(http.MethodPost, "/api/loadTest/generate", formData)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GenerateLoadTest(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Invalid failure_rate")
+	})
+
+	t.Run("GenerateLoadTest with invalid durations", func(t *testing.T) {
+		formData := strings.NewReader("jobs=5&min_duration_ms=10&max_duration_ms=5")
+
+		req := httptest.NewRequest(http.MethodPost, "/api/loadTest/generate", formData)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GenerateLoadTest(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Invalid durations")
+	})
+}
+
+func TestGenerateLoadTestJobs(t *testing.T) {
+	taskDB := fake.NewTaskDB()
+	queuer := fake.NewQueuer()
+	handler := NewManagerHandler(upload.NewFilesystemMemory(), taskDB, queuer)
+	e := echo.New()
+
+	t.Run("Should add the jobs in batches to the registered load test task", func(t *testing.T) {
+		formData := strings.NewReader("tasks=2&jobs=1500&failure_rate=0.5&min_duration_ms=1&max_duration_ms=5&seed=7")
+
+		req := httptest.NewRequest(http.MethodPost, "/api/loadTest/generate", formData)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GenerateLoadTest(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var result qmModel.LoadTestResult
+		err = json.Unmarshal(rec.Body.Bytes(), &result)
+		require.NoError(t, err)
+		assert.Equal(t, 1500, result.JobsCreated)
+		assert.Greater(t, result.JobsFailing, 0)
+		assert.Less(t, result.JobsFailing, 1500)
+
+		task, err := taskDB.SelectTaskByKey(qmModel.LOAD_TEST_TASK_KEY)
+		require.NoError(t, err, "Expected the load test task to be registered")
+		require.Len(t, task.InputParameters, 2)
+		assert.Equal(t, "fail", task.InputParameters[1].Key)
+
+		jobs, err := queuer.GetJobs(0, 2000)
+		require.NoError(t, err)
+		require.Len(t, jobs, 1500)
+		failing := 0
+		for _, job := range jobs {
+			assert.Equal(t, qmModel.LOAD_TEST_TASK_KEY, job.TaskName)
+			require.Len(t, job.Parameters, 2)
+			if job.Parameters[1] == true {
+				failing++
+			}
+		}
+		assert.Equal(t, result.JobsFailing, failing)
+	})
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/siherrmann/queuer"
 	"github.com/siherrmann/queuer/helper"
+	qmModel "github.com/siherrmann/queuerManager/model"
 	"github.com/testcontainers/testcontainers-go"
 )
 
@@ -46,6 +47,7 @@ func TestMain(m *testing.M) {
 	queue = queuer.NewQueuerWithDB("TestQueuer", 10, "", dbConf)
 	queue.AddTaskWithName(testTask, "test-task")
 	queue.AddTaskWithName(testTaskFailing, "test-task-failing")
+	queue.AddTaskWithName(LoadTestTask, qmModel.LOAD_TEST_TASK_KEY)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	queue.Start(ctx, cancel)
@@ -1,36 +1,148 @@
 package handler
 
 import (
+	"database/sql"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/siherrmann/queuer"
+	"github.com/siherrmann/queuerManager/auth"
 	"github.com/siherrmann/queuerManager/database"
+	"github.com/siherrmann/queuerManager/event"
+	"github.com/siherrmann/queuerManager/forward"
+	"github.com/siherrmann/queuerManager/helper"
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/notify"
 	"github.com/siherrmann/queuerManager/upload"
 
 	"github.com/labstack/echo/v5"
-	"github.com/siherrmann/validator"
 )
 
 type ManagerHandler struct {
-	Queuer     *queuer.Queuer
-	Filesystem upload.Filesystem
-	validator  *validator.Validator
-	taskDB     *database.TaskDBHandler
+	Queuer                 QueuerClient
+	QueuerDB               *sql.DB
+	Filesystem             upload.Filesystem
+	MetricDB               *database.MetricDBHandler
+	BatchDB                *database.BatchDBHandler
+	MaxJobPayloadBytes     int
+	Pagination             *model.PaginationConfig
+	Quotas                 *model.QuotaConfig
+	WorkerScaler           WorkerScaler
+	ScaleAuditDB           *database.ScaleAuditDBHandler
+	WorkerLogs             WorkerLogSource
+	EventPublisher         event.Publisher
+	SlackSigningSecret     string
+	CIToken                string
+	PublicDashboardPath    string
+	Authenticator          auth.Authenticator
+	OIDC                   *auth.OIDCProvider
+	UserDB                 *database.UserDBHandler
+	SessionKey             []byte
+	BootstrapAPIKey        string
+	ScimToken              string
+	GroupDB                *database.GroupDBHandler
+	ScimGroupRoles         map[string]string
+	Notifications          *notify.Dispatcher
+	Alerter                *notify.Alerter
+	JobKPIs                *JobKPIs
+	Forwarder              *forward.Forwarder
+	ForwardDB              *database.ForwardedJobDBHandler
+	JobKillDB              *database.JobKillDBHandler
+	JobOverrideDB          *database.JobOverrideDBHandler
+	JobAttemptDB           *database.JobAttemptDBHandler
+	JobTemplateDB          *database.JobTemplateDBHandler
+	JobInitiatorDB         *database.JobInitiatorDBHandler
+	JobDedupDB             *database.JobDedupDBHandler
+	TaskCostDB             *database.TaskCostDBHandler
+	JobDB                  *database.JobDBHandler
+	JobArchiveDB           *database.JobArchiveDBHandler
+	TaskSampleDB           *database.TaskSampleDBHandler
+	QueuerMasterDB         *database.QueuerMasterDBHandler
+	APIKeyDB               *database.APIKeyDBHandler
+	FeatureFlagDB          *database.FeatureFlagDBHandler
+	ScheduleDB             *database.ScheduleDBHandler
+	JobChainDB             *database.JobChainDBHandler
+	WatchDB                *database.WatchDBHandler
+	HealthDB               *database.HealthDBHandler
+	SchedulerDB            *database.SchedulerDBHandler
+	SchedulerOverride      bool
+	MessageTemplateDB      *database.MessageTemplateDBHandler
+	MessageTemplates       *notify.Templates
+	AccessLogDB            *database.AccessLogDBHandler
+	AccessLogs             *AccessLogger
+	AccessLogRetentionDays int
+	FileOwnerDB            *database.FileOwnerDBHandler
+	JobFileDB              *database.JobFileDBHandler
+	WorkerVersionDB        *database.WorkerVersionDBHandler
+	FileTrashRetentionDays int
+	MetricInterval         time.Duration
+	ManagerLeaseDB         *database.ManagerLeaseDBHandler
+	Coordinator            *Coordinator
+	Deprecations           map[string]*model.Deprecation
+	DeprecatedRouteUsageDB *database.DeprecatedRouteUsageDBHandler
+	DeprecationUsages      *DeprecationTracker
+	Mailer                 *notify.Mailer
+	Status                 *StatusTracker
+	QueuerBreaker          *QueuerBreaker
+	Recorder               *RequestRecorder
+	JobStream              *JobStream
+	WatchStream            *WatchStream
+	taskDB                 database.TaskDBHandlerFunctions
+	validationFuncs        map[string]model.ValidationFunc
+	preTaskSaveHooks       []model.TaskHookFunc
+	postTaskSaveHooks      []model.TaskHookFunc
+	preJobSubmitHooks      []model.JobSubmitHookFunc
+	featureFlags           *featureFlagCache
+	basicAuthUsers         *basicAuthCache
+	shutdown               chan struct{}
+	shutdownOnce           sync.Once
 }
 
-func NewManagerHandler(filesystem upload.Filesystem, taskDB *database.TaskDBHandler, queuerInstance *queuer.Queuer) *ManagerHandler {
-	return &ManagerHandler{
-		Queuer:     queuerInstance,
-		Filesystem: filesystem,
-		validator:  validator.NewValidator(),
-		taskDB:     taskDB,
+// NewManagerHandler creates the manager handler with the filesystem, the task database and the queuer.
+// With the queuer of the queuer package its database connection is used for the health checks and the pool stats,
+// other queuers like fake.Queuer have no database connection.
+func NewManagerHandler(filesystem upload.Filesystem, taskDB database.TaskDBHandlerFunctions, queuerInstance QueuerClient) *ManagerHandler {
+	m := &ManagerHandler{
+		Queuer:             queuerInstance,
+		Filesystem:         filesystem,
+		taskDB:             taskDB,
+		validationFuncs:    defaultValidationFuncs(),
+		MaxJobPayloadBytes: maxJobPayloadBytesFromEnv(),
+		Pagination:         paginationConfigFromEnv(),
+		Quotas:             quotaConfigFromEnv(),
+		Status:             NewStatusTracker(),
+		Recorder:           NewRequestRecorder(recorderCapacity),
+		JobStream:          NewJobStream(),
+		WatchStream:        NewWatchStream(),
+		featureFlags:       &featureFlagCache{},
+		SessionKey:         newSessionKey(),
+		basicAuthUsers:     &basicAuthCache{},
+		shutdown:           make(chan struct{}),
 	}
+	if q, ok := queuerInstance.(*queuer.Queuer); ok && q != nil {
+		m.QueuerDB = q.DB
+	}
+	m.QueuerBreaker = NewQueuerBreaker(m.pingQueuer, queuerRetryIntervalFromEnv())
+	m.validationFuncs[model.VALIDATION_TYPE_FILE] = m.validateFile
+	return m
 }
 
 // Health check handler
 func (m *ManagerHandler) HealthCheck(c *echo.Context) error {
-	return c.JSON(http.StatusOK, map[string]string{
+	health := map[string]any{
 		"status":  "healthy",
 		"service": "queuer-manager",
-	})
+		"build":   helper.GetBuildInfo(),
+	}
+	if m.QueuerDB != nil {
+		health["database_pool"] = model.NewDBPoolStats(m.QueuerDB.Stats())
+	}
+
+	return c.JSON(http.StatusOK, health)
+}
+
+// GetVersion returns the version, git commit and build time of the manager.
+func (m *ManagerHandler) GetVersion(c *echo.Context) error {
+	return c.JSON(http.StatusOK, helper.GetBuildInfo())
 }
@@ -1,15 +1,18 @@
 package handler
 
 import (
+	"encoding/json"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"runtime"
 	"testing"
 
 	"github.com/labstack/echo/v5"
 	"github.com/siherrmann/queuer/helper"
 	"github.com/siherrmann/queuerManager/database"
+	"github.com/siherrmann/queuerManager/model"
 	"github.com/siherrmann/queuerManager/upload"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -35,5 +38,34 @@ func TestHealthCheck(t *testing.T) {
 		assert.Equal(t, http.StatusOK, rec.Code)
 		assert.Contains(t, rec.Body.String(), "healthy")
 		assert.Contains(t, rec.Body.String(), "queuer-manager")
+		assert.Contains(t, rec.Body.String(), "database_pool")
+		assert.Contains(t, rec.Body.String(), "build")
+	})
+}
+
+func TestGetVersion(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	e := echo.New()
+
+	t.Run("Should return the build info", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/version", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetVersion(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var buildInfo model.BuildInfo
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &buildInfo))
+		assert.NotEmpty(t, buildInfo.Version)
+		assert.NotEmpty(t, buildInfo.Commit)
+		assert.NotEmpty(t, buildInfo.BuildTime)
+		assert.Equal(t, runtime.Version(), buildInfo.GoVersion)
 	})
 }
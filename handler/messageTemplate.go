@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/siherrmann/queuerManager/helper"
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/notify"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	qmodel "github.com/siherrmann/queuer/model"
+)
+
+// =======API Handlers=======
+
+// GetMessageTemplates returns the templates of the notifications, the edited templates instead of their default templates.
+func (m *ManagerHandler) GetMessageTemplates(c *echo.Context) error {
+	return c.JSON(http.StatusOK, m.messageTemplates())
+}
+
+// UpdateMessageTemplate stores the subject and body of the JSON or form body as template with the key of the query, eg. `?key=watch_email`.
+// The template is rendered with sample data first, so a broken template is not stored.
+func (m *ManagerHandler) UpdateMessageTemplate(c *echo.Context) error {
+	if m.MessageTemplateDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Message templates are not enabled")
+	}
+
+	messageTemplate, _, err := m.messageTemplateFromRequest(c)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+	_, err = m.renderMessageTemplateSample(messageTemplate)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid template: %v", err))
+	}
+	messageTemplate.UpdatedBy = requestedBy(c)
+
+	updatedMessageTemplate, err := m.MessageTemplateDB.UpsertMessageTemplate(messageTemplate)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to update message template: %v", err))
+	}
+	m.MessageTemplates.Invalidate()
+	updatedMessageTemplate.Description = messageTemplate.Description
+
+	if c.Request().Header.Get("HX-Request") != "" {
+		return renderRowsWithPopup(c, http.StatusOK, screens.MessageTemplateSettings(updatedMessageTemplate), "#message_template_"+updatedMessageTemplate.Key, "outerHTML", "", "Template saved")
+	}
+	return c.JSON(http.StatusOK, updatedMessageTemplate)
+}
+
+// ResetMessageTemplate deletes the edited template with the key of the query, so the default template is used again.
+func (m *ManagerHandler) ResetMessageTemplate(c *echo.Context) error {
+	if m.MessageTemplateDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Message templates are not enabled")
+	}
+
+	key := c.QueryParam("key")
+	defaultTemplate := model.DefaultMessageTemplate(key)
+	if defaultTemplate == nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Unknown message template %q", key))
+	}
+
+	err := m.MessageTemplateDB.DeleteMessageTemplate(key)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to reset message template: %v", err))
+	}
+	m.MessageTemplates.Invalidate()
+
+	if c.Request().Header.Get("HX-Request") != "" {
+		return renderRowsWithPopup(c, http.StatusOK, screens.MessageTemplateSettings(defaultTemplate), "#message_template_"+key, "outerHTML", "", "Template reset to the default")
+	}
+	return c.JSON(http.StatusOK, defaultTemplate)
+}
+
+// PreviewMessageTemplate renders the subject and body of the request as template with the key of the query with sample data without storing it.
+func (m *ManagerHandler) PreviewMessageTemplate(c *echo.Context) error {
+	messageTemplate, _, err := m.messageTemplateFromRequest(c)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+
+	rendered, err := m.renderMessageTemplateSample(messageTemplate)
+	if c.Request().Header.Get("HX-Request") != "" {
+		return render(c, screens.MessageTemplatePreview(rendered, err))
+	}
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid template: %v", err))
+	}
+	return c.JSON(http.StatusOK, rendered)
+}
+
+// TestMessageTemplate renders the template of the request with sample data and sends it without storing it.
+// Email templates are sent to `to` or the email address of the user, webhook templates are posted to the URL in `to`.
+func (m *ManagerHandler) TestMessageTemplate(c *echo.Context) error {
+	messageTemplate, to, err := m.messageTemplateFromRequest(c)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+	rendered, err := m.renderMessageTemplateSample(messageTemplate)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid template: %v", err))
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 30*time.Second)
+	defer cancel()
+
+	if messageTemplate.IsWebhook() {
+		webhookURL, err := url.Parse(to)
+		if to == "" || err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Host == "" {
+			return renderPopupOrJson(c, http.StatusBadRequest, "Missing or invalid webhook URL (must be an http or https URL)")
+		}
+		err = notify.PostWebhookPayload(ctx, to, rendered.Body)
+		if err != nil {
+			return renderPopupOrJson(c, http.StatusBadGateway, fmt.Sprintf("Failed to send test webhook: %v", err))
+		}
+		return renderPopupOrJson(c, http.StatusOK, fmt.Sprintf("Test webhook sent to %s", webhookURL.Host))
+	}
+
+	if m.Mailer == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Emails are not configured")
+	}
+	if to == "" && m.UserDB != nil {
+		user, err := m.UserDB.SelectUserByUsername(requestedBy(c))
+		if err == nil {
+			to = user.Email
+		}
+	}
+	if to == "" {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Missing email address to send the test email to")
+	}
+
+	err = m.Mailer.Send(ctx, to, rendered.Subject, rendered.Body)
+	m.Status.Record(model.SUBSYSTEM_NOTIFIER, err)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadGateway, fmt.Sprintf("Failed to send test email: %v", err))
+	}
+	return renderPopupOrJson(c, http.StatusOK, fmt.Sprintf("Test email sent to %s", to))
+}
+
+// =======View Handlers=======
+
+// MessageTemplatesView renders the message template view
+func (m *ManagerHandler) MessageTemplatesView(c *echo.Context) error {
+	c.Response().Header().Add("HX-Push-Url", "/messageTemplates")
+	c.Response().Header().Add("HX-Retarget", "#body")
+
+	return render(c, screens.MessageTemplates(m.messageTemplates(), m.MessageTemplateDB != nil))
+}
+
+// =======Helpers=======
+
+// messageTemplates returns the edited templates and the default templates of the keys without edited template.
+func (m *ManagerHandler) messageTemplates() []*model.MessageTemplate {
+	messageTemplates := []*model.MessageTemplate{}
+	for _, defaultTemplate := range model.DefaultMessageTemplates {
+		messageTemplates = append(messageTemplates, m.MessageTemplates.Get(defaultTemplate.Key))
+	}
+	return messageTemplates
+}
+
+// messageTemplateFromRequest returns the template with the key of the query and the subject and body of the body,
+// and the recipient of a test send.
+func (m *ManagerHandler) messageTemplateFromRequest(c *echo.Context) (*model.MessageTemplate, string, error) {
+	key := c.QueryParam("key")
+	messageTemplate := model.DefaultMessageTemplate(key)
+	if messageTemplate == nil {
+		return nil, "", fmt.Errorf("unknown message template %q", key)
+	}
+
+	var request model.MessageTemplateRequest
+	if err := c.Bind(&request); err != nil {
+		return nil, "", fmt.Errorf("invalid request: %v", err)
+	}
+	if strings.TrimSpace(request.Body) == "" {
+		return nil, "", fmt.Errorf("missing template body")
+	}
+	if !messageTemplate.IsWebhook() && strings.TrimSpace(request.Subject) == "" {
+		return nil, "", fmt.Errorf("missing template subject")
+	}
+
+	messageTemplate.Subject = strings.TrimSpace(request.Subject)
+	messageTemplate.Body = request.Body
+	if messageTemplate.IsWebhook() {
+		messageTemplate.Subject = ""
+	}
+	return messageTemplate, strings.TrimSpace(request.To), nil
+}
+
+// renderMessageTemplateSample renders the template with sample data of its notification.
+func (m *ManagerHandler) renderMessageTemplateSample(messageTemplate *model.MessageTemplate) (*model.RenderedMessage, error) {
+	return notify.RenderTemplate(messageTemplate, messageTemplateSampleData(messageTemplate.Key), m.MessageTemplates.Brand())
+}
+
+// messageTemplateSampleData returns a sample of the data the template with the key is rendered with.
+func messageTemplateSampleData(key string) any {
+	startedAt := time.Now().Add(-90 * time.Second)
+	job := &qmodel.Job{
+		RID:       uuid.MustParse("00000000-0000-4000-8000-000000000001"),
+		TaskName:  "sample-task",
+		Status:    qmodel.JobStatusFailed,
+		Error:     "sample error: connection refused",
+		Attempts:  3,
+		StartedAt: &startedAt,
+		UpdatedAt: time.Now(),
+	}
+	baseURL := helper.GetEnvOrDefault("QUEUER_MANAGER_BASE_URL", "")
+
+	switch key {
+	case model.MESSAGE_TEMPLATE_WATCH_EMAIL:
+		return model.NewWatchNotification(&model.Watch{Username: "sample-user", TargetType: model.WATCH_TARGET_TASK, Target: job.TaskName}, job, baseURL)
+	case model.MESSAGE_TEMPLATE_ALERT_EMAIL:
+		return &model.Alert{
+			Kind:      model.ALERT_JOB_FAILED,
+			TaskKeys:  []string{job.TaskName},
+			Title:     fmt.Sprintf("Job failed: %s", job.TaskName),
+			Message:   fmt.Sprintf("Job %s of task %s failed after %d attempts: %s", job.RID, job.TaskName, job.Attempts, job.Error),
+			URL:       alertURL("/job?rid=" + job.RID.String()),
+			CreatedAt: time.Now(),
+		}
+	default:
+		notification := model.NewJobNotification("sample-rule", job, baseURL)
+		notification.Owner = "sample-team"
+		notification.Initiator = "sample-user"
+		return notification
+	}
+}
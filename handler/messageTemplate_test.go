@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/notify"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageTemplateHandlers(t *testing.T) {
+	m := &ManagerHandler{MessageTemplates: notify.NewTemplates(nil, "Acme Jobs"), Status: NewStatusTracker()}
+	e := echo.New()
+
+	post := func(t *testing.T, handler echo.HandlerFunc, key string, request *model.MessageTemplateRequest) *httptest.ResponseRecorder {
+		body, err := json.Marshal(request)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/api/messageTemplate/test?key="+key, strings.NewReader(string(body)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		require.NoError(t, handler(e.NewContext(req, rec)))
+		return rec
+	}
+
+	t.Run("Should list the default templates", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/messageTemplate/getMessageTemplates", nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, m.GetMessageTemplates(e.NewContext(req, rec)))
+
+		messageTemplates := []*model.MessageTemplate{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &messageTemplates))
+		assert.Len(t, messageTemplates, len(model.DefaultMessageTemplates))
+	})
+
+	t.Run("Should preview the template with sample data", func(t *testing.T) {
+		rec := post(t, m.PreviewMessageTemplate, model.MESSAGE_TEMPLATE_WATCH_EMAIL, &model.MessageTemplateRequest{
+			Subject: "[{{ brand }}] {{ .TaskName }} {{ .StatusName }}",
+			Body:    "{{ .Error }}",
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		rendered := &model.RenderedMessage{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), rendered))
+		assert.Equal(t, "[Acme Jobs] sample-task "+model.StatusName("FAILED"), rendered.Subject)
+		assert.Equal(t, "sample error: connection refused", rendered.Body)
+	})
+
+	t.Run("Should reject invalid templates", func(t *testing.T) {
+		rec := post(t, m.PreviewMessageTemplate, model.MESSAGE_TEMPLATE_JOB_WEBHOOK, &model.MessageTemplateRequest{Body: `{"task": {{ .TaskName }}}`})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "valid JSON")
+
+		rec = post(t, m.PreviewMessageTemplate, model.MESSAGE_TEMPLATE_ALERT_EMAIL, &model.MessageTemplateRequest{Subject: "{{ .Missing }}", Body: "body"})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = post(t, m.PreviewMessageTemplate, "unknown", &model.MessageTemplateRequest{Body: "body"})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "unknown message template")
+	})
+
+	t.Run("Should send the test webhook", func(t *testing.T) {
+		var received string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			received = string(body)
+		}))
+		defer server.Close()
+
+		rec := post(t, m.TestMessageTemplate, model.MESSAGE_TEMPLATE_JOB_WEBHOOK, &model.MessageTemplateRequest{
+			Body: `{"text": {{ json .TaskName }}, "brand": {{ json brand }}}`,
+			To:   server.URL,
+		})
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.JSONEq(t, `{"text": "sample-task", "brand": "Acme Jobs"}`, received)
+	})
+
+	t.Run("Should not update templates without database", func(t *testing.T) {
+		rec := post(t, m.UpdateMessageTemplate, model.MESSAGE_TEMPLATE_ALERT_EMAIL, &model.MessageTemplateRequest{Subject: "{{ .Title }}", Body: "{{ .Message }}"})
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/labstack/echo/v5"
+)
+
+// GetMetrics retrieves the queue metric history for the requested time range and resolution
+func (m *ManagerHandler) GetMetrics(c *echo.Context) error {
+	if m.MetricDB == nil {
+		return c.String(http.StatusServiceUnavailable, "Metrics are not enabled")
+	}
+
+	sinceStr := c.QueryParam("since")
+	untilStr := c.QueryParam("until")
+	resolution := c.QueryParam("resolution")
+
+	// Parse until with default
+	until := time.Now()
+	if untilStr != "" {
+		parsedUntil, err := time.Parse(time.RFC3339, untilStr)
+		if err != nil {
+			return c.String(http.StatusBadRequest, "Invalid until format (must be RFC3339)")
+		}
+		until = parsedUntil
+	}
+
+	// Parse since with default
+	since := until.Add(-24 * time.Hour)
+	if sinceStr != "" {
+		parsedSince, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			return c.String(http.StatusBadRequest, "Invalid since format (must be RFC3339)")
+		}
+		since = parsedSince
+	}
+
+	if since.After(until) {
+		return c.String(http.StatusBadRequest, "Invalid time range (since must be before until)")
+	}
+
+	var metrics []*model.QueueMetric
+	var err error
+	switch resolution {
+	case "", model.METRIC_RESOLUTION_HOUR:
+		metrics, err = m.MetricDB.SelectMetricsHourly(since, until)
+	case model.METRIC_RESOLUTION_RAW:
+		metrics, err = m.MetricDB.SelectMetrics(since, until)
+	default:
+		return c.String(http.StatusBadRequest, "Invalid resolution (must be raw or hour)")
+	}
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to retrieve metrics")
+	}
+
+	return c.JSON(http.StatusOK, metrics)
+}
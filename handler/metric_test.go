@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMetricsHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("metricdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	mdb, err := database.NewMetricDBHandler(db, true)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.MetricDB = mdb
+	e := echo.New()
+
+	_, err = mdb.InsertMetricSnapshot()
+	require.NoError(t, err)
+
+	t.Run("GetMetrics with raw resolution", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/metric/getMetrics?resolution=raw", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetMetrics(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var metrics []*qmModel.QueueMetric
+		err = json.Unmarshal(rec.Body.Bytes(), &metrics)
+		require.NoError(t, err)
+		assert.Len(t, metrics, 1)
+	})
+
+	t.Run("GetMetrics with default hourly resolution", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/metric/getMetrics", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetMetrics(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var metrics []*qmModel.QueueMetric
+		err = json.Unmarshal(rec.Body.Bytes(), &metrics)
+		require.NoError(t, err)
+		assert.NotEmpty(t, metrics)
+	})
+
+	t.Run("GetMetrics with invalid resolution", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/metric/getMetrics?resolution=minute", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetMetrics(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Invalid resolution")
+	})
+
+	t.Run("GetMetrics with invalid since", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/metric/getMetrics?since=yesterday", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetMetrics(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Invalid since format")
+	})
+
+	t.Run("GetMetrics without metric database", func(t *testing.T) {
+		handlerWithoutMetrics := NewManagerHandler(fs, tdb, queue)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/metric/getMetrics", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handlerWithoutMetrics.GetMetrics(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/components"
+
+	"github.com/labstack/echo/v5"
+)
+
+// namespacePattern are the valid namespaces, eg. team-data or billing.reports
+var namespacePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,99}$`)
+
+// validateNamespace checks the namespace of a task, an empty namespace is no namespace.
+func validateNamespace(namespace string) error {
+	if namespace != "" && !namespacePattern.MatchString(namespace) {
+		return fmt.Errorf("Invalid namespace %q (must be lowercase letters, digits, '.', '_' or '-' with at most 100 characters)", namespace)
+	}
+	return nil
+}
+
+// requestNamespace returns the namespace the lists of the request are filtered by, the query parameter namespace
+// or else the namespace selected with the namespace switcher. An empty namespace does not filter.
+func requestNamespace(c *echo.Context) string {
+	if c.QueryParams().Has("namespace") {
+		return c.QueryParam("namespace")
+	}
+	return model.GetRequestContext(c).Namespace
+}
+
+// =======API Handlers=======
+
+// GetNamespaces returns the namespaces of the tasks ordered by name.
+func (m *ManagerHandler) GetNamespaces(c *echo.Context) error {
+	namespaces, err := m.taskDB.SelectNamespaces()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to get namespaces: %v", err)})
+	}
+
+	return c.JSON(http.StatusOK, namespaces)
+}
+
+// SwitchNamespace selects the namespace the lists of the web interface are filtered by, an empty namespace shows all namespaces.
+// The namespace is kept in a cookie, so the other users of the manager are not affected.
+func (m *ManagerHandler) SwitchNamespace(c *echo.Context) error {
+	requestData := &model.NamespaceRequest{}
+	if err := c.Bind(requestData); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+	}
+	if err := validateNamespace(requestData.Namespace); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+
+	cookie := &http.Cookie{
+		Name:     model.NAMESPACE_COOKIE_NAME,
+		Value:    requestData.Namespace,
+		Path:     cookiePath(c, "/"),
+		MaxAge:   365 * 24 * 60 * 60,
+		HttpOnly: true,
+		Secure:   c.Scheme() == "https",
+		SameSite: http.SameSiteLaxMode,
+	}
+	if requestData.Namespace == "" {
+		cookie.MaxAge = -1
+	}
+	c.SetCookie(cookie)
+
+	if c.Request().Header.Get("HX-Request") != "" {
+		c.Response().Header().Set("HX-Refresh", "true")
+		return c.NoContent(http.StatusOK)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"namespace": requestData.Namespace})
+}
+
+// =======View Handlers=======
+
+// NamespaceSwitcherView renders the namespace switcher of the menu with the namespaces of the tasks
+func (m *ManagerHandler) NamespaceSwitcherView(c *echo.Context) error {
+	namespaces, err := m.taskDB.SelectNamespaces()
+	if err != nil {
+		return c.NoContent(http.StatusOK)
+	}
+
+	return render(c, components.NamespaceSwitcher(namespaces, model.GetRequestContext(c).Namespace))
+}
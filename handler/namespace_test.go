@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateNamespace(t *testing.T) {
+	assert.NoError(t, validateNamespace(""))
+	assert.NoError(t, validateNamespace("team-data"))
+	assert.NoError(t, validateNamespace("billing.reports_2"))
+	assert.Error(t, validateNamespace("Team"))
+	assert.Error(t, validateNamespace("-data"))
+	assert.Error(t, validateNamespace("team data"))
+	assert.Error(t, validateNamespace(strings.Repeat("a", 101)))
+}
+
+func TestRequestNamespace(t *testing.T) {
+	e := echo.New()
+
+	t.Run("Namespace of the switcher", func(t *testing.T) {
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/jobs", nil), httptest.NewRecorder())
+		model.SetRequestContext(c, model.RequestContext{Namespace: "billing"})
+		assert.Equal(t, "billing", requestNamespace(c))
+	})
+
+	t.Run("Query parameter overrides the switcher", func(t *testing.T) {
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/jobs?namespace=data", nil), httptest.NewRecorder())
+		model.SetRequestContext(c, model.RequestContext{Namespace: "billing"})
+		assert.Equal(t, "data", requestNamespace(c))
+	})
+
+	t.Run("Empty query parameter shows all namespaces", func(t *testing.T) {
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/jobs?namespace=", nil), httptest.NewRecorder())
+		model.SetRequestContext(c, model.RequestContext{Namespace: "billing"})
+		assert.Empty(t, requestNamespace(c))
+	})
+}
+
+func TestSwitchNamespace(t *testing.T) {
+	m := &ManagerHandler{}
+	e := echo.New()
+
+	switchNamespace := func(t *testing.T, body string, hx bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/namespace/switchNamespace", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		if hx {
+			req.Header.Set("HX-Request", "true")
+		}
+		rec := httptest.NewRecorder()
+		err := m.SwitchNamespace(e.NewContext(req, rec))
+		require.NoError(t, err)
+		return rec
+	}
+
+	t.Run("Select a namespace", func(t *testing.T) {
+		rec := switchNamespace(t, "namespace=billing", true)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "true", rec.Header().Get("HX-Refresh"))
+		cookies := rec.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, model.NAMESPACE_COOKIE_NAME, cookies[0].Name)
+		assert.Equal(t, "billing", cookies[0].Value)
+		assert.True(t, cookies[0].HttpOnly)
+	})
+
+	t.Run("Select all namespaces", func(t *testing.T) {
+		rec := switchNamespace(t, "namespace=", false)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		cookies := rec.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Less(t, cookies[0].MaxAge, 0, "Expected the cookie to be deleted")
+	})
+
+	t.Run("Invalid namespace", func(t *testing.T) {
+		rec := switchNamespace(t, "namespace=Not+Valid", false)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Empty(t, rec.Result().Cookies())
+	})
+}
+
+func TestNamespaceHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	e := echo.New()
+
+	for _, task := range []*model.Task{
+		{Key: "namespace-billing-task", Name: "Billing Task", Namespace: "billing"},
+		{Key: "namespace-data-task", Name: "Data Task", Namespace: "data"},
+	} {
+		_, err := tdb.InsertTask(task)
+		require.NoError(t, err)
+	}
+
+	t.Run("GetNamespaces", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/namespace/getNamespaces", nil)
+		rec := httptest.NewRecorder()
+		err := handler.GetNamespaces(e.NewContext(req, rec))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		namespaces := []string{}
+		err = json.Unmarshal(rec.Body.Bytes(), &namespaces)
+		require.NoError(t, err)
+		assert.Contains(t, namespaces, "billing")
+		assert.Contains(t, namespaces, "data")
+	})
+
+	t.Run("GetTasks of the namespace", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/task/getTasks?namespace=billing", nil)
+		rec := httptest.NewRecorder()
+		err := handler.GetTasks(e.NewContext(req, rec))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		tasks := []*model.Task{}
+		err = json.Unmarshal(rec.Body.Bytes(), &tasks)
+		require.NoError(t, err)
+		require.NotEmpty(t, tasks)
+		for _, task := range tasks {
+			assert.Equal(t, "billing", task.Namespace)
+		}
+	})
+
+	t.Run("AddTask with an invalid namespace", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/task/addTask", strings.NewReader("key=namespace-invalid-task&name=Invalid&namespace=Not+Valid"))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		err := handler.AddTask(e.NewContext(req, rec))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
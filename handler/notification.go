@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/model"
+)
+
+// NotifyJobEnded sends the notifications of the matching rules for an ended job without blocking the caller.
+func (m *ManagerHandler) NotifyJobEnded(job *model.Job) {
+	if m.Notifications == nil || job == nil {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		owner, initiator := m.jobOwnership(job)
+		err := m.Notifications.NotifyJob(ctx, job, owner, initiator)
+		m.Status.Record(qmModel.SUBSYSTEM_NOTIFIER, err)
+		if err != nil {
+			log.Printf("Error sending notifications of job %s: %v", job.RID, err)
+		}
+	}()
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuer/model"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/notify"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifyJobEndedHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	received := map[string]map[string]any{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		payload := map[string]any{}
+		_ = json.Unmarshal(body, &payload)
+
+		mu.Lock()
+		received[strings.TrimPrefix(r.URL.Path, "/")] = payload
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	dispatcher, err := notify.NewDispatcher([]*qmModel.NotificationRule{
+		{Name: "teams", Format: qmModel.NOTIFICATION_FORMAT_TEAMS, URL: server.URL + "/teams"},
+		{Name: "discord", Format: qmModel.NOTIFICATION_FORMAT_DISCORD, URL: server.URL + "/discord", Tasks: []string{"notify-task"}, Statuses: []string{"failed", "succeeded"}},
+		{Name: "other-task", Format: qmModel.NOTIFICATION_FORMAT_WEBHOOK, URL: server.URL + "/webhook", Tasks: []string{"other-task"}},
+	}, "https://queuer.example.com/")
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.Notifications = dispatcher
+
+	receivedPaths := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		paths := []string{}
+		for path := range received {
+			paths = append(paths, path)
+		}
+		return paths
+	}
+
+	startedAt := time.Now().Add(-90 * time.Second)
+	job := &model.Job{
+		RID:       uuid.New(),
+		TaskName:  "notify-task",
+		Status:    model.JobStatusFailed,
+		Error:     "connection refused",
+		Attempts:  3,
+		StartedAt: &startedAt,
+		UpdatedAt: startedAt.Add(90 * time.Second),
+	}
+
+	t.Run("Failed job notifies matching rules", func(t *testing.T) {
+		handler.NotifyJobEnded(job)
+
+		assert.Eventually(t, func() bool {
+			return len(receivedPaths()) == 2
+		}, 2*time.Second, 10*time.Millisecond)
+		assert.ElementsMatch(t, []string{"teams", "discord"}, receivedPaths())
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		attachments, ok := received["teams"]["attachments"].([]any)
+		require.True(t, ok, "Expected teams message with attachments")
+		require.Len(t, attachments, 1)
+		card := attachments[0].(map[string]any)["content"].(map[string]any)
+		assert.Equal(t, "AdaptiveCard", card["type"])
+		actions := card["actions"].([]any)
+		assert.Equal(t, "https://queuer.example.com/job?rid="+job.RID.String(), actions[0].(map[string]any)["url"])
+
+		embeds, ok := received["discord"]["embeds"].([]any)
+		require.True(t, ok, "Expected discord message with embeds")
+		embed := embeds[0].(map[string]any)
+		assert.Equal(t, "Job failed: notify-task", embed["title"])
+		assert.Equal(t, "https://queuer.example.com/job?rid="+job.RID.String(), embed["url"])
+		assert.Contains(t, embed["description"], "connection refused")
+		assert.Contains(t, embed["fields"], map[string]any{"name": "Duration", "value": "1m30s", "inline": true})
+	})
+
+	t.Run("Succeeded job only notifies rules with the status", func(t *testing.T) {
+		mu.Lock()
+		received = map[string]map[string]any{}
+		mu.Unlock()
+
+		succeededJob := *job
+		succeededJob.Status = model.JobStatusSucceeded
+		succeededJob.Error = ""
+		handler.NotifyJobEnded(&succeededJob)
+
+		assert.Eventually(t, func() bool {
+			return len(receivedPaths()) == 1
+		}, 2*time.Second, 10*time.Millisecond)
+		assert.Equal(t, []string{"discord"}, receivedPaths())
+	})
+
+	t.Run("Invalid rule format", func(t *testing.T) {
+		_, err := notify.NewDispatcher([]*qmModel.NotificationRule{{Format: "pager", URL: server.URL}}, "")
+		assert.Error(t, err)
+	})
+}
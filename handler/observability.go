@@ -0,0 +1,286 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/siherrmann/queuerManager/helper"
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/labstack/echo/v5"
+)
+
+// observabilityMetricsPath is the path Prometheus scrapes the metrics from
+const observabilityMetricsPath = "/api/observability/metrics"
+
+// observabilityRecentWindow is the time window of the recent job metrics
+const observabilityRecentWindow = 15 * time.Minute
+
+// observabilityMetrics are all metrics of the Prometheus endpoint in the order they are written
+var observabilityMetrics = []model.ObservabilityMetric{
+	{Name: model.PROMETHEUS_METRIC_BUILD_INFO, Help: "Build information of the manager, always 1.", Type: "gauge", Labels: []string{"version", "commit", "go_version"}},
+	{Name: model.PROMETHEUS_METRIC_SUBSYSTEM_UP, Help: "1 if the subsystem of the manager is ok, 0 if it is degraded or down.", Type: "gauge", Labels: []string{"subsystem"}},
+	{Name: model.PROMETHEUS_METRIC_JOBS_QUEUED, Help: "Jobs waiting for a worker in the latest queue snapshot.", Type: "gauge"},
+	{Name: model.PROMETHEUS_METRIC_JOBS_SCHEDULED, Help: "Jobs scheduled for later in the latest queue snapshot.", Type: "gauge"},
+	{Name: model.PROMETHEUS_METRIC_JOBS_RUNNING, Help: "Running jobs in the latest queue snapshot.", Type: "gauge"},
+	{Name: model.PROMETHEUS_METRIC_WORKERS_TOTAL, Help: "Connected workers in the latest queue snapshot.", Type: "gauge"},
+	{Name: model.PROMETHEUS_METRIC_WORKERS_READY, Help: "Workers ready for jobs in the latest queue snapshot.", Type: "gauge"},
+	{Name: model.PROMETHEUS_METRIC_WORKERS_RUNNING, Help: "Workers running jobs in the latest queue snapshot.", Type: "gauge"},
+	{Name: model.PROMETHEUS_METRIC_RECENT_JOBS_ENDED, Help: "Jobs that ended in the last 15 minutes by status.", Type: "gauge", Labels: []string{"status"}},
+	{Name: model.PROMETHEUS_METRIC_RECENT_JOB_FAILURE_RATE, Help: "Share of failed jobs of the jobs that ended in the last 15 minutes.", Type: "gauge", Unit: "percentunit"},
+	{Name: model.PROMETHEUS_METRIC_RECENT_JOB_DURATION_AVG_S, Help: "Average duration of the jobs that ended in the last 15 minutes.", Type: "gauge", Unit: "s"},
+}
+
+// prometheusLabelEscaper escapes the label values of the Prometheus text format
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// prometheusSample is a value of a metric with the values of its labels.
+// The suffix is appended to the name of the metric, eg. _bucket, _sum and _count of a histogram.
+type prometheusSample struct {
+	suffix string
+	labels map[string]string
+	value  float64
+}
+
+// =======API Handlers=======
+
+// GetObservabilityMetrics writes the metrics of the manager in the Prometheus text format.
+// Queue metrics are only written if metrics are enabled, they are the values of the latest snapshot.
+// The job KPIs are the counters of the jobs that ended since the start of the manager broken down by the allowed labels.
+func (m *ManagerHandler) GetObservabilityMetrics(c *echo.Context) error {
+	samples := map[string][]prometheusSample{}
+
+	buildInfo := helper.GetBuildInfo()
+	samples[model.PROMETHEUS_METRIC_BUILD_INFO] = []prometheusSample{{
+		labels: map[string]string{"version": buildInfo.Version, "commit": buildInfo.Commit, "go_version": buildInfo.GoVersion},
+		value:  1,
+	}}
+
+	for _, subsystem := range m.systemStatus(c.Request().Context()).Subsystems {
+		if subsystem.Status == model.STATUS_DISABLED {
+			continue
+		}
+		up := 0.0
+		if subsystem.Status == model.STATUS_OK {
+			up = 1
+		}
+		samples[model.PROMETHEUS_METRIC_SUBSYSTEM_UP] = append(samples[model.PROMETHEUS_METRIC_SUBSYSTEM_UP], prometheusSample{
+			labels: map[string]string{"subsystem": subsystem.Name},
+			value:  up,
+		})
+	}
+
+	if m.MetricDB != nil {
+		now := time.Now()
+		queueMetrics, err := m.MetricDB.SelectMetrics(now.Add(-observabilityRecentWindow), now)
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to retrieve metrics")
+		}
+		if len(queueMetrics) > 0 {
+			latest := queueMetrics[len(queueMetrics)-1]
+			samples[model.PROMETHEUS_METRIC_JOBS_QUEUED] = []prometheusSample{{value: float64(latest.JobsQueued)}}
+			samples[model.PROMETHEUS_METRIC_JOBS_SCHEDULED] = []prometheusSample{{value: float64(latest.JobsScheduled)}}
+			samples[model.PROMETHEUS_METRIC_JOBS_RUNNING] = []prometheusSample{{value: float64(latest.JobsRunning)}}
+			samples[model.PROMETHEUS_METRIC_WORKERS_TOTAL] = []prometheusSample{{value: float64(latest.WorkersTotal)}}
+			samples[model.PROMETHEUS_METRIC_WORKERS_READY] = []prometheusSample{{value: float64(latest.WorkersReady)}}
+			samples[model.PROMETHEUS_METRIC_WORKERS_RUNNING] = []prometheusSample{{value: float64(latest.WorkersRunning)}}
+		}
+
+		jobStats, err := m.MetricDB.SelectJobStats(now.Add(-observabilityRecentWindow), now, observabilityRecentWindow, "")
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to retrieve metrics")
+		}
+		recent := recentJobStats(jobStats)
+		samples[model.PROMETHEUS_METRIC_RECENT_JOBS_ENDED] = []prometheusSample{
+			{labels: map[string]string{"status": "succeeded"}, value: float64(recent.Succeeded)},
+			{labels: map[string]string{"status": "failed"}, value: float64(recent.Failed)},
+			{labels: map[string]string{"status": "cancelled"}, value: float64(recent.Cancelled)},
+		}
+		samples[model.PROMETHEUS_METRIC_RECENT_JOB_FAILURE_RATE] = []prometheusSample{{value: recent.FailureRate()}}
+		samples[model.PROMETHEUS_METRIC_RECENT_JOB_DURATION_AVG_S] = []prometheusSample{{value: recent.AvgDurationSeconds}}
+	}
+
+	metrics := observabilityMetrics
+	if m.JobKPIs != nil {
+		metrics = append(slices.Clone(metrics), m.JobKPIs.metrics()...)
+		for name, kpiSamples := range m.JobKPIs.samples() {
+			samples[name] = kpiSamples
+		}
+	}
+
+	return c.Blob(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(prometheusText(metrics, samples)))
+}
+
+// GetObservabilityBundle returns Prometheus alert rules and a Grafana dashboard for the metrics of the Prometheus endpoint.
+// With format=rules or format=dashboard only the rule file or the dashboard is returned, so it can be saved as is.
+func (m *ManagerHandler) GetObservabilityBundle(c *echo.Context) error {
+	bundle := &model.ObservabilityBundle{
+		MetricsPath:      observabilityMetricsPath,
+		Metrics:          observabilityMetrics,
+		PrometheusRules:  observabilityRules(),
+		GrafanaDashboard: observabilityDashboard(),
+	}
+
+	switch c.QueryParam("format") {
+	case "":
+		return c.JSON(http.StatusOK, bundle)
+	case "rules":
+		return c.JSON(http.StatusOK, bundle.PrometheusRules)
+	case "dashboard":
+		return c.JSON(http.StatusOK, bundle.GrafanaDashboard)
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("Invalid format %q (must be rules or dashboard)", c.QueryParam("format"))})
+	}
+}
+
+// =======Helpers=======
+
+// recentJobStats sums the job stats of the buckets, the average duration is weighted by the ended jobs.
+func recentJobStats(jobStats []*model.JobStats) *model.JobStats {
+	recent := &model.JobStats{}
+	durationSum := 0.0
+	for _, stat := range jobStats {
+		ended := stat.Succeeded + stat.Failed + stat.Cancelled
+		recent.Succeeded += stat.Succeeded
+		recent.Failed += stat.Failed
+		recent.Cancelled += stat.Cancelled
+		durationSum += stat.AvgDurationSeconds * float64(ended)
+	}
+	if ended := recent.Succeeded + recent.Failed + recent.Cancelled; ended > 0 {
+		recent.AvgDurationSeconds = durationSum / float64(ended)
+	}
+	return recent
+}
+
+// prometheusText writes the samples of the metrics in the Prometheus text format, metrics without samples are skipped.
+// The buckets of histograms get their upper bound as le label.
+func prometheusText(metrics []model.ObservabilityMetric, samples map[string][]prometheusSample) string {
+	var b strings.Builder
+	for _, metric := range metrics {
+		metricSamples := samples[metric.Name]
+		if len(metricSamples) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&b, "# HELP %s %s\n", metric.Name, metric.Help)
+		fmt.Fprintf(&b, "# TYPE %s %s\n", metric.Name, metric.Type)
+		for _, sample := range metricSamples {
+			b.WriteString(metric.Name + sample.suffix)
+			labels := []string{}
+			for _, label := range metric.Labels {
+				labels = append(labels, fmt.Sprintf(`%s="%s"`, label, prometheusLabelEscaper.Replace(sample.labels[label])))
+			}
+			if sample.suffix == "_bucket" {
+				labels = append(labels, fmt.Sprintf(`le="%s"`, sample.labels["le"]))
+			}
+			if len(labels) > 0 {
+				b.WriteString("{" + strings.Join(labels, ",") + "}")
+			}
+			fmt.Fprintf(&b, " %g\n", sample.value)
+		}
+	}
+	return b.String()
+}
+
+// observabilityRules returns the alert rules for the metrics of the Prometheus endpoint.
+func observabilityRules() model.PrometheusRuleFile {
+	return model.PrometheusRuleFile{Groups: []model.PrometheusRuleGroup{{
+		Name: "queuer-manager",
+		Rules: []model.PrometheusRule{
+			{
+				Alert:       "QueuerManagerDown",
+				Expr:        fmt.Sprintf("absent(%s)", model.PROMETHEUS_METRIC_BUILD_INFO),
+				For:         "5m",
+				Labels:      map[string]string{"severity": "critical"},
+				Annotations: map[string]string{"summary": "Queuer manager is not scraped", "description": "No metrics of the queuer manager were scraped for 5 minutes."},
+			},
+			{
+				Alert:       "QueuerManagerSubsystemDegraded",
+				Expr:        fmt.Sprintf("%s == 0", model.PROMETHEUS_METRIC_SUBSYSTEM_UP),
+				For:         "5m",
+				Labels:      map[string]string{"severity": "warning"},
+				Annotations: map[string]string{"summary": "Subsystem {{ $labels.subsystem }} is degraded", "description": "The subsystem {{ $labels.subsystem }} of the queuer manager is degraded or down, see the status page."},
+			},
+			{
+				Alert:       "QueuerManagerNoReadyWorkers",
+				Expr:        fmt.Sprintf("%s > 0 and %s == 0", model.PROMETHEUS_METRIC_JOBS_QUEUED, model.PROMETHEUS_METRIC_WORKERS_READY),
+				For:         "10m",
+				Labels:      map[string]string{"severity": "critical"},
+				Annotations: map[string]string{"summary": "Jobs are queued without ready workers", "description": "{{ $value }} jobs are queued, but no worker is ready for 10 minutes."},
+			},
+			{
+				Alert:       "QueuerManagerQueueBacklog",
+				Expr:        fmt.Sprintf("%s > 100", model.PROMETHEUS_METRIC_JOBS_QUEUED),
+				For:         "15m",
+				Labels:      map[string]string{"severity": "warning"},
+				Annotations: map[string]string{"summary": "Queue backlog is growing", "description": "{{ $value }} jobs are queued for 15 minutes, consider scaling the workers."},
+			},
+			{
+				Alert:       "QueuerManagerHighJobFailureRate",
+				Expr:        fmt.Sprintf("%s > 0.2", model.PROMETHEUS_METRIC_RECENT_JOB_FAILURE_RATE),
+				For:         "15m",
+				Labels:      map[string]string{"severity": "warning"},
+				Annotations: map[string]string{"summary": "High job failure rate", "description": "{{ $value | humanizePercentage }} of the recently ended jobs failed."},
+			},
+		},
+	}}}
+}
+
+// observabilityDashboard returns a Grafana dashboard with a panel for each metric of the Prometheus endpoint.
+// The Prometheus datasource is selected when the dashboard is imported.
+func observabilityDashboard() map[string]any {
+	datasource := map[string]any{"type": "prometheus", "uid": "${DS_PROMETHEUS}"}
+
+	panels := []map[string]any{}
+	for i, metric := range observabilityMetrics {
+		if metric.Name == model.PROMETHEUS_METRIC_BUILD_INFO {
+			continue
+		}
+
+		legend := ""
+		if len(metric.Labels) > 0 {
+			legend = "{{" + metric.Labels[0] + "}}"
+		}
+		unit := metric.Unit
+		if unit == "" {
+			unit = "short"
+		}
+
+		position := len(panels)
+		panels = append(panels, map[string]any{
+			"id":          i,
+			"type":        "timeseries",
+			"title":       metric.Name,
+			"description": metric.Help,
+			"datasource":  datasource,
+			"gridPos":     map[string]int{"h": 8, "w": 12, "x": (position % 2) * 12, "y": (position / 2) * 8},
+			"fieldConfig": map[string]any{"defaults": map[string]any{"unit": unit}, "overrides": []any{}},
+			"targets": []map[string]any{{
+				"refId":        "A",
+				"datasource":   datasource,
+				"expr":         metric.Name,
+				"legendFormat": legend,
+			}},
+		})
+	}
+
+	return map[string]any{
+		"__inputs": []map[string]string{{
+			"name":     "DS_PROMETHEUS",
+			"label":    "Prometheus",
+			"type":     "datasource",
+			"pluginId": "prometheus",
+		}},
+		"title":         "Queuer Manager",
+		"uid":           "queuer-manager",
+		"tags":          []string{"queuer"},
+		"timezone":      "browser",
+		"schemaVersion": 39,
+		"refresh":       "30s",
+		"time":          map[string]string{"from": "now-6h", "to": "now"},
+		"panels":        panels,
+	}
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObservabilityHandler(t *testing.T) {
+	handler := NewManagerHandler(upload.NewFilesystemMemory(), nil, queue)
+	e := echo.New()
+
+	exposedMetrics := map[string]bool{}
+	for _, metric := range observabilityMetrics {
+		exposedMetrics[metric.Name] = true
+	}
+	metricNamePattern := regexp.MustCompile(`queuer_manager_[a-z_]+`)
+
+	t.Run("Metrics are written in the Prometheus text format", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/observability/metrics", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetObservabilityMetrics(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentType), "text/plain")
+		assert.Contains(t, rec.Body.String(), "# TYPE queuer_manager_build_info gauge")
+		assert.Contains(t, rec.Body.String(), `queuer_manager_subsystem_up{subsystem="Storage"} 1`)
+		assert.NotContains(t, rec.Body.String(), qmModel.PROMETHEUS_METRIC_JOBS_QUEUED, "Expected no queue metrics without metric database")
+	})
+
+	t.Run("Bundle only uses exposed metrics", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/observability/bundle", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetObservabilityBundle(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var bundle qmModel.ObservabilityBundle
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &bundle))
+		assert.Equal(t, "/api/observability/metrics", bundle.MetricsPath)
+		require.Len(t, bundle.PrometheusRules.Groups, 1)
+		assert.NotEmpty(t, bundle.PrometheusRules.Groups[0].Rules)
+
+		for _, rule := range bundle.PrometheusRules.Groups[0].Rules {
+			for _, name := range metricNamePattern.FindAllString(rule.Expr, -1) {
+				assert.True(t, exposedMetrics[name], "Expected metric %s of alert %s to be exposed", name, rule.Alert)
+			}
+		}
+
+		panels, ok := bundle.GrafanaDashboard["panels"].([]any)
+		require.True(t, ok)
+		assert.Len(t, panels, len(observabilityMetrics)-1)
+		for _, name := range metricNamePattern.FindAllString(rec.Body.String(), -1) {
+			assert.True(t, exposedMetrics[name], "Expected metric %s of the dashboard to be exposed", name)
+		}
+	})
+
+	t.Run("Bundle with format", func(t *testing.T) {
+		testCases := []struct {
+			format       string
+			expectedCode int
+			expectedKey  string
+		}{
+			{"rules", http.StatusOK, "groups"},
+			{"dashboard", http.StatusOK, "panels"},
+			{"yaml", http.StatusBadRequest, "error"},
+		}
+		for _, testCase := range testCases {
+			req := httptest.NewRequest(http.MethodGet, "/api/observability/bundle?format="+testCase.format, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.GetObservabilityBundle(c)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedCode, rec.Code, testCase.format)
+
+			var response map[string]any
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Contains(t, response, testCase.expectedKey, testCase.format)
+		}
+	})
+}
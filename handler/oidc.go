@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v5"
+)
+
+// OIDC_COOKIE_NAME is the name of the cookie with the signed state, nonce and PKCE verifier of a running OpenID Connect login
+const OIDC_COOKIE_NAME = "queuer_manager_oidc"
+
+// OIDC_LOGIN_MAX_AGE is the time a user has to log in at the provider
+const OIDC_LOGIN_MAX_AGE = 10 * time.Minute
+
+var errInvalidOIDCLogin = errors.New("invalid oidc login")
+
+// =======API Handlers=======
+
+// OIDCLogin redirects to the login of the OpenID Connect provider.
+// The state, nonce and PKCE verifier are kept in a signed cookie until the callback.
+func (m *ManagerHandler) OIDCLogin(c *echo.Context) error {
+	if m.OIDC == nil {
+		return c.Redirect(http.StatusSeeOther, "/login")
+	}
+
+	state, nonce, verifier := randomOIDCValue(), randomOIDCValue(), randomOIDCValue()
+	authCodeURL, err := m.OIDC.AuthCodeURL(c.Request().Context(), state, nonce, verifier)
+	if err != nil {
+		log.Printf("OIDC login failed: %v", err)
+		return m.renderLogin(c, http.StatusBadGateway, "Login provider not reachable, please try again later")
+	}
+
+	expires := time.Now().Add(OIDC_LOGIN_MAX_AGE)
+	c.SetCookie(&http.Cookie{
+		Name:     OIDC_COOKIE_NAME,
+		Value:    m.signPayload(strings.Join([]string{state, nonce, verifier, strconv.FormatInt(expires.Unix(), 10)}, "|")),
+		Path:     cookiePath(c, "/login/oidc"),
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   c.Scheme() == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return c.Redirect(http.StatusFound, authCodeURL)
+}
+
+// OIDCCallback completes the login at the OpenID Connect provider and sets the session cookie.
+func (m *ManagerHandler) OIDCCallback(c *echo.Context) error {
+	if m.OIDC == nil {
+		return c.Redirect(http.StatusSeeOther, "/login")
+	}
+
+	cookie, err := c.Cookie(OIDC_COOKIE_NAME)
+	c.SetCookie(&http.Cookie{
+		Name:     OIDC_COOKIE_NAME,
+		Value:    "",
+		Path:     cookiePath(c, "/login/oidc"),
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+	if err != nil {
+		return m.renderLogin(c, http.StatusBadRequest, "Login expired, please try again")
+	}
+
+	if providerError := c.QueryParam("error"); providerError != "" {
+		log.Printf("OIDC login rejected by provider: %s %s", providerError, c.QueryParam("error_description"))
+		return m.renderLogin(c, http.StatusUnauthorized, "Login was rejected by the provider")
+	}
+
+	nonce, verifier, err := m.parseOIDCCookie(cookie.Value, c.QueryParam("state"), time.Now())
+	if err != nil {
+		return m.renderLogin(c, http.StatusBadRequest, "Login expired, please try again")
+	}
+
+	user, err := m.OIDC.Exchange(c.Request().Context(), c.QueryParam("code"), nonce, verifier)
+	if err != nil {
+		log.Printf("OIDC login failed: %v", err)
+		return m.renderLogin(c, http.StatusUnauthorized, "Login failed, please try again")
+	}
+
+	savedUser, err := m.saveLoginUser(user)
+	if err != nil {
+		log.Printf("OIDC login of %s failed: %v", user.Username, err)
+		return m.renderLogin(c, http.StatusInternalServerError, "Login failed, please try again later")
+	}
+
+	return m.startSession(c, savedUser)
+}
+
+// =======Helpers=======
+
+// parseOIDCCookie verifies the signature, expiry and state of the login cookie and returns the nonce and PKCE verifier.
+func (m *ManagerHandler) parseOIDCCookie(value string, state string, now time.Time) (string, string, error) {
+	payload, err := m.verifyPayload(value)
+	if err != nil {
+		return "", "", err
+	}
+
+	parts := strings.Split(payload, "|")
+	if len(parts) != 4 {
+		return "", "", errInvalidOIDCLogin
+	}
+	if state == "" || subtle.ConstantTimeCompare([]byte(parts[0]), []byte(state)) != 1 {
+		return "", "", errInvalidOIDCLogin
+	}
+	expires, err := strconv.ParseInt(parts[3], 10, 64)
+	if err != nil || now.After(time.Unix(expires, 0)) {
+		return "", "", errInvalidOIDCLogin
+	}
+
+	return parts[1], parts[2], nil
+}
+
+// oidcPostLogoutURL returns the login page of the manager for the redirect after the logout at the provider.
+func oidcPostLogoutURL(redirectURL string) string {
+	loginURL, err := url.Parse(redirectURL)
+	if err != nil || loginURL.Host == "" {
+		return ""
+	}
+	loginURL.Path = "/login"
+	loginURL.RawQuery = ""
+	return loginURL.String()
+}
+
+// randomOIDCValue returns a random URL safe value for the state, nonce and PKCE verifier.
+func randomOIDCValue() string {
+	value := make([]byte, 32)
+	_, _ = rand.Read(value)
+	return base64.RawURLEncoding.EncodeToString(value)
+}
@@ -0,0 +1,287 @@
+package handler
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/auth"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testOIDCProvider is an OpenID Connect provider issuing RS256 ID tokens with fixed claims for the code "valid-code".
+type testOIDCProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	claims map[string]any
+	nonce  string
+}
+
+func newTestOIDCProvider(t *testing.T) *testOIDCProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	provider := &testOIDCProvider{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 provider.server.URL,
+			"authorization_endpoint": provider.server.URL + "/authorize",
+			"token_endpoint":         provider.server.URL + "/token",
+			"jwks_uri":               provider.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kid": "test-key",
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != "valid-code" || r.FormValue("code_verifier") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": provider.idToken(t)})
+	})
+	provider.server = httptest.NewServer(mux)
+	t.Cleanup(provider.server.Close)
+
+	return provider
+}
+
+func (p *testOIDCProvider) idToken(t *testing.T) string {
+	claims := map[string]any{
+		"iss":   p.server.URL,
+		"aud":   "queuer-manager",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"nonce": p.nonce,
+	}
+	for key, value := range p.claims {
+		claims[key] = value
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "kid": "test-key", "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestOIDCHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	udb, err := database.NewUserDBHandler(db, true)
+	require.NoError(t, err)
+
+	provider := newTestOIDCProvider(t)
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.OIDC = auth.NewOIDCProvider(auth.OIDCConfig{
+		IssuerURL:     provider.server.URL,
+		ClientID:      "queuer-manager",
+		RedirectURL:   "http://manager.example.com/login/oidc/callback",
+		UsernameClaim: "preferred_username",
+		GroupsClaim:   "groups",
+		GroupRoles:    map[string]string{"queuer-admins": qmModel.ROLE_ADMIN},
+		Name:          "Test SSO",
+	})
+	handler.UserDB = udb
+	handler.SessionKey = []byte("test-session-key")
+	e := echo.New()
+
+	// startLogin redirects to the provider and returns the login cookie and the state and nonce of the redirect
+	startLogin := func(t *testing.T) (*http.Cookie, url.Values) {
+		req := httptest.NewRequest(http.MethodGet, "/login/oidc", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		require.NoError(t, handler.OIDCLogin(c))
+		require.Equal(t, http.StatusFound, rec.Code)
+
+		location, err := url.Parse(rec.Header().Get("Location"))
+		require.NoError(t, err)
+		assert.Equal(t, "/authorize", location.Path)
+		assert.Equal(t, "S256", location.Query().Get("code_challenge_method"))
+
+		var loginCookie *http.Cookie
+		for _, cookie := range rec.Result().Cookies() {
+			if cookie.Name == OIDC_COOKIE_NAME {
+				loginCookie = cookie
+			}
+		}
+		require.NotNil(t, loginCookie)
+		return loginCookie, location.Query()
+	}
+
+	callback := func(loginCookie *http.Cookie, query url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/login/oidc/callback?"+query.Encode(), nil)
+		if loginCookie != nil {
+			req.AddCookie(loginCookie)
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		require.NoError(t, handler.OIDCCallback(c))
+		return rec
+	}
+
+	t.Run("Login view shows the provider", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/login", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		require.NoError(t, handler.LoginView(c))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Login with Test SSO")
+		assert.NotContains(t, rec.Body.String(), "login_password", "Expected no password form without authenticator")
+	})
+
+	t.Run("Login succeeds with mapped group", func(t *testing.T) {
+		loginCookie, authQuery := startLogin(t)
+		provider.nonce = authQuery.Get("nonce")
+		provider.claims = map[string]any{"sub": "user-1", "preferred_username": "dana", "name": "Dana", "groups": []string{"queuer-admins"}}
+
+		rec := callback(loginCookie, url.Values{"code": {"valid-code"}, "state": {authQuery.Get("state")}})
+		assert.Equal(t, http.StatusSeeOther, rec.Code)
+		assert.Equal(t, "/", rec.Header().Get("Location"))
+
+		var sessionCookie *http.Cookie
+		for _, cookie := range rec.Result().Cookies() {
+			if cookie.Name == SESSION_COOKIE_NAME {
+				sessionCookie = cookie
+			}
+		}
+		require.NotNil(t, sessionCookie)
+
+		user, err := udb.SelectUserByUsername("dana")
+		require.NoError(t, err)
+		assert.Equal(t, qmModel.ROLE_ADMIN, user.Role)
+		assert.Equal(t, qmModel.USER_SOURCE_OIDC, user.Source)
+		assert.Equal(t, "user-1", user.ExternalID)
+
+		req := httptest.NewRequest(http.MethodGet, "/jobs", nil)
+		req.AddCookie(sessionCookie)
+		rec = httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		err = handler.AuthMiddleware(func(c *echo.Context) error {
+			return c.String(http.StatusOK, qmModel.GetRequestContext(c).User.Username)
+		})(c)
+		require.NoError(t, err)
+		assert.Equal(t, "dana", rec.Body.String())
+	})
+
+	t.Run("Login without mapped group is forbidden", func(t *testing.T) {
+		loginCookie, authQuery := startLogin(t)
+		provider.nonce = authQuery.Get("nonce")
+		provider.claims = map[string]any{"sub": "user-2", "preferred_username": "erin", "groups": []string{"other"}}
+
+		rec := callback(loginCookie, url.Values{"code": {"valid-code"}, "state": {authQuery.Get("state")}})
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("Login with wrong state is rejected", func(t *testing.T) {
+		loginCookie, authQuery := startLogin(t)
+		provider.nonce = authQuery.Get("nonce")
+
+		rec := callback(loginCookie, url.Values{"code": {"valid-code"}, "state": {"other-state"}})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = callback(nil, url.Values{"code": {"valid-code"}, "state": {authQuery.Get("state")}})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Login with wrong nonce is rejected", func(t *testing.T) {
+		loginCookie, authQuery := startLogin(t)
+		provider.nonce = "other-nonce"
+		provider.claims = map[string]any{"sub": "user-1", "preferred_username": "dana", "groups": []string{"queuer-admins"}}
+
+		rec := callback(loginCookie, url.Values{"code": {"valid-code"}, "state": {authQuery.Get("state")}})
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("Login round trip under a base path", func(t *testing.T) {
+		withBasePath := func(req *http.Request) *http.Request {
+			return req.WithContext(context.WithValue(req.Context(), "basePath", "/queuer"))
+		}
+
+		req := withBasePath(httptest.NewRequest(http.MethodGet, "/login/oidc", nil))
+		rec := httptest.NewRecorder()
+		require.NoError(t, handler.OIDCLogin(e.NewContext(req, rec)))
+		require.Equal(t, http.StatusFound, rec.Code)
+
+		var loginCookie *http.Cookie
+		for _, cookie := range rec.Result().Cookies() {
+			if cookie.Name == OIDC_COOKIE_NAME {
+				loginCookie = cookie
+			}
+		}
+		require.NotNil(t, loginCookie)
+		assert.Equal(t, "/queuer/login/oidc", loginCookie.Path, "Expected the login cookie to be sent to the callback under the base path")
+
+		location, err := url.Parse(rec.Header().Get("Location"))
+		require.NoError(t, err)
+		provider.nonce = location.Query().Get("nonce")
+		provider.claims = map[string]any{"sub": "user-1", "preferred_username": "dana", "groups": []string{"queuer-admins"}}
+
+		query := url.Values{"code": {"valid-code"}, "state": {location.Query().Get("state")}}
+		req = withBasePath(httptest.NewRequest(http.MethodGet, "/login/oidc/callback?"+query.Encode(), nil))
+		req.AddCookie(loginCookie)
+		rec = httptest.NewRecorder()
+		require.NoError(t, handler.OIDCCallback(e.NewContext(req, rec)))
+		assert.Equal(t, http.StatusSeeOther, rec.Code)
+
+		cookiePaths := map[string]string{}
+		for _, cookie := range rec.Result().Cookies() {
+			cookiePaths[cookie.Name] = cookie.Path
+		}
+		assert.Equal(t, "/queuer/login/oidc", cookiePaths[OIDC_COOKIE_NAME], "Expected the login cookie to be removed under the base path")
+		assert.Equal(t, "/queuer", cookiePaths[SESSION_COOKIE_NAME], "Expected the session cookie to be only sent under the base path")
+
+		req = withBasePath(httptest.NewRequest(http.MethodGet, "/logout", nil))
+		rec = httptest.NewRecorder()
+		require.NoError(t, handler.Logout(e.NewContext(req, rec)))
+		require.Len(t, rec.Result().Cookies(), 1)
+		assert.Equal(t, "/queuer", rec.Result().Cookies()[0].Path, "Expected the logout to remove the session cookie under the base path")
+	})
+
+	t.Run("Middleware redirects to the login", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/jobs", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		err := handler.AuthMiddleware(func(c *echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusSeeOther, rec.Code)
+		assert.Equal(t, "/login", rec.Header().Get("Location"))
+	})
+}
@@ -0,0 +1,422 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/siherrmann/queuerManager/graphql"
+	"github.com/siherrmann/queuerManager/helper"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+)
+
+// openAPIOperation documents an API route beyond its path, the request and response are values of their models.
+// With Files the request is a multipart form with the files.
+type openAPIOperation struct {
+	Summary  string
+	Query    []string
+	Request  any
+	Files    bool
+	Response any
+}
+
+// openAPIPaginationQuery are the query parameters of the paginated list endpoints, see pagination.
+var openAPIPaginationQuery = []string{"lastId", "limit", "envelope"}
+
+// openAPIOperations documents the API routes by method and path, other API routes are described by their path only.
+var openAPIOperations = map[string]openAPIOperation{
+	"POST /api/job/addJob/:taskKey":                    {Summary: "Add a job of the task with the parameters as form values or JSON", Query: []string{"dryRun", "test", "priority", "scheduled_at"}, Request: map[string]any{}, Response: &model.Job{}},
+	"POST /api/job/cancelJob/:rid":                     {Summary: "Cancel the job", Response: &model.Job{}},
+	"POST /api/job/cancelJobs":                         {Summary: "Cancel the jobs with the rid form values"},
+	"POST /api/job/deleteJob/:rid":                     {Summary: "Delete the job"},
+	"POST /api/job/getJob/:rid":                        {Summary: "Get the job", Response: &model.Job{}},
+	"POST /api/job/setPriority/:rid":                   {Summary: "Change the priority of a queued or scheduled job", Request: &qmModel.JobPriorityRequest{}, Response: &qmModel.JobPriority{}},
+	"POST /api/job/getJobs":                            {Summary: "List the jobs", Query: append([]string{"status", "taskKey", "search", "from", "to"}, openAPIPaginationQuery...), Response: []*model.Job{}},
+	"POST /api/job/getScheduledJobs":                   {Summary: "List the pending scheduled jobs, the next job to run first", Query: openAPIPaginationQuery, Response: []*model.Job{}},
+	"POST /api/v1/jobs":                                {Summary: "Add a job from a JSON body", Request: &qmModel.JobCreateRequest{}, Response: &model.Job{}},
+	"GET /api/jobArchive/getJob/:rid":                  {Summary: "Get the archived job", Response: &model.Job{}},
+	"GET /api/jobArchive/getJobs":                      {Summary: "List the archived jobs", Query: openAPIPaginationQuery, Response: []*model.Job{}},
+	"POST /api/jobArchive/retryJobs":                   {Summary: "Add the archived jobs again, optionally with overridden parameters", Request: &qmModel.JobRetryRequest{}, Response: []*qmModel.JobRetryResult{}},
+	"GET /api/worker/getWorker/:rid":                   {Summary: "Get the worker", Response: &model.Worker{}},
+	"GET /api/worker/getWorkers":                       {Summary: "List the workers", Query: openAPIPaginationQuery, Response: []*model.Worker{}},
+	"POST /api/worker/stopWorkers":                     {Summary: "Stop the workers with the rid query parameters immediately", Query: []string{"rid"}},
+	"POST /api/worker/stopWorkersGracefully":           {Summary: "Stop the workers with the rid query parameters after their running jobs", Query: []string{"rid"}},
+	"POST /api/task/updateTask":                        {Summary: "Update the task with the rid query parameter", Query: []string{"rid"}, Response: &qmModel.Task{}},
+	"POST /api/task/deleteTasks":                       {Summary: "Delete the tasks with the rid query parameters", Query: []string{"rid"}},
+	"GET /api/task/getTask/:rid":                       {Summary: "Get the task", Response: &qmModel.Task{}},
+	"GET /api/task/getTaskByName/:name":                {Summary: "Get the task by its name", Response: &qmModel.Task{}},
+	"GET /api/task/getTaskByKey/:key":                  {Summary: "Get the task by its key", Response: &qmModel.Task{}},
+	"GET /api/task/getTaskUtilization/:key":            {Summary: "Get the running, queued and added jobs of the task with its max concurrency and rate limit per minute", Response: &qmModel.TaskUtilization{}},
+	"PUT /api/task/putTask/:key":                       {Summary: "Create or update the task with the definition", Request: &qmModel.TaskDefinition{}, Response: &qmModel.Task{}},
+	"DELETE /api/task/deleteTaskByKey/:key":            {Summary: "Delete the task by its key"},
+	"GET /api/task/getTasks":                           {Summary: "List the tasks", Query: openAPIPaginationQuery, Response: []*qmModel.Task{}},
+	"GET /api/task/exportTask":                         {Summary: "Export the tasks with the rid query parameters", Query: []string{"rid", "include"}},
+	"GET /api/file/getFiles":                           {Summary: "List the files", Query: []string{"search"}, Response: []upload.File{}},
+	"POST /api/file/uploadFiles":                       {Summary: "Upload the files of the files form field", Files: true},
+	"POST /api/file/deleteFile/:filename":              {Summary: "Delete the file"},
+	"POST /api/file/deleteFiles":                       {Summary: "Delete the files with the name query parameters", Query: []string{"name"}},
+	"GET /api/connection/getConnections":               {Summary: "List the database connections", Response: []*model.Connection{}},
+	"GET /api/connection/getPoolStats":                 {Summary: "Get the statistics of the database pool", Response: &qmModel.DBPoolStats{}},
+	"GET /api/scheduler/getPolicy":                     {Summary: "Get the order in which the workers claim the queued jobs", Response: &qmModel.SchedulerPolicy{}},
+	"POST /api/scheduler/updatePolicy":                 {Summary: "Update the mode and task weights of the scheduler policy", Request: &qmModel.SchedulerPolicyRequest{}, Response: &qmModel.SchedulerPolicy{}},
+	"GET /api/messageTemplate/getMessageTemplates":     {Summary: "List the templates of the notification emails and webhook payloads", Response: []*qmModel.MessageTemplate{}},
+	"POST /api/messageTemplate/updateMessageTemplate":  {Summary: "Update the template with the key query parameter", Query: []string{"key"}, Request: &qmModel.MessageTemplateRequest{}, Response: &qmModel.MessageTemplate{}},
+	"POST /api/messageTemplate/resetMessageTemplate":   {Summary: "Reset the template with the key query parameter to the default template", Query: []string{"key"}, Response: &qmModel.MessageTemplate{}},
+	"POST /api/messageTemplate/previewMessageTemplate": {Summary: "Render the template of the request with sample data", Query: []string{"key"}, Request: &qmModel.MessageTemplateRequest{}, Response: &qmModel.RenderedMessage{}},
+	"POST /api/messageTemplate/testMessageTemplate":    {Summary: "Send the template of the request with sample data to the email address or webhook URL in to", Query: []string{"key"}, Request: &qmModel.MessageTemplateRequest{}},
+	"GET /api/accessLog/getAccessLogs":                 {Summary: "List the access logs of the requests, newest first", Query: append([]string{"user", "route", "method", "status", "from", "to"}, openAPIPaginationQuery[:2]...), Response: []*qmModel.AccessLog{}},
+	"GET /api/accessLog/exportAccessLogs":              {Summary: "Export the access logs matching the filters as csv, json or ndjson", Query: []string{"format", "user", "route", "method", "status", "from", "to"}},
+	"GET /api/quota/getUsage":                          {Summary: "Get the jobs added in the last 24 hours, the storage, limits and warnings of the principal of the request", Response: &qmModel.QuotaUsage{}},
+	"GET /api/quota/getPrincipalUsage":                 {Summary: "Get the quota usage of a principal, eg. apikey:deploy", Query: []string{"principal"}, Response: &qmModel.QuotaUsage{}},
+	"GET /api/coordination/getStatus":                  {Summary: "Get the holders of the master lock and the leases of the manager instances", Response: &qmModel.CoordinationStatus{}},
+	"POST /api/coordination/failover":                  {Summary: "Release the lease of the scheduler or the housekeeping, so another instance takes it over", Query: []string{"lease"}},
+	"GET /api/namespace/getNamespaces":                 {Summary: "List the namespaces of the tasks", Response: []string{}},
+	"POST /api/namespace/switchNamespace":              {Summary: "Select the namespace the lists of the web interface are filtered by", Request: &qmModel.NamespaceRequest{}},
+	"GET /api/deprecation/getReport":                   {Summary: "Get the deprecated routes with the callers that still call them", Response: []*qmModel.DeprecationReport{}},
+	"POST /api/deprecation/resetUsages":                {Summary: "Reset the counted callers of a deprecated route", Query: []string{"route"}},
+	"GET /api/graphql":                                 {Summary: "Execute a GraphQL query on the tasks, jobs, workers and archived jobs", Query: []string{"query", "variables", "operationName"}, Response: &graphql.Response{}},
+	"POST /api/graphql":                                {Summary: "Execute a GraphQL query on the tasks, jobs, workers and archived jobs", Request: &graphql.Request{}, Response: &graphql.Response{}},
+	"GET /api/graphql/schema":                          {Summary: "Get the GraphQL schema in the schema definition language"},
+	"GET /api/version":                                 {Summary: "Get the build info of the manager", Response: helper.GetBuildInfo()},
+}
+
+// openAPIMethods are the methods of the routes documented in the OpenAPI document.
+var openAPIMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// openAPINonAlphanumeric splits the path segments into the words of an operation ID.
+var openAPINonAlphanumeric = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// =======API Handlers=======
+
+// GetOpenAPI returns the OpenAPI 3.0 document of the API routes, eg. to generate a client SDK.
+func (m *ManagerHandler) GetOpenAPI(c *echo.Context) error {
+	basePath, _ := c.Request().Context().Value("basePath").(string)
+	return c.JSON(http.StatusOK, openAPIDocument(c.Echo().Router().Routes(), basePath, m.Deprecations))
+}
+
+// =======View Handlers=======
+
+// OpenAPIDocsView renders the Swagger UI of the OpenAPI document
+func (m *ManagerHandler) OpenAPIDocsView(c *echo.Context) error {
+	return render(c, screens.OpenAPIDocs())
+}
+
+// =======Helpers=======
+
+// openAPIDocument describes the routes below /api with the documented operations and the schemas of their models,
+// deprecated routes are marked as deprecated with their deprecation as description.
+func openAPIDocument(routes echo.Routes, basePath string, deprecations map[string]*qmModel.Deprecation) *qmModel.OpenAPIDocument {
+	schemas := newOpenAPISchemas()
+	document := &qmModel.OpenAPIDocument{
+		OpenAPI: "3.0.3",
+		Info: qmModel.OpenAPIInfo{
+			Title:       "Queuer Manager API",
+			Description: "Authenticate with an API key as bearer token or with the session cookie of a login.",
+			Version:     helper.GetBuildInfo().Version,
+		},
+		Servers:  []qmModel.OpenAPIServer{{URL: basePath + "/"}},
+		Paths:    map[string]map[string]*qmModel.OpenAPIOperation{},
+		Security: []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}},
+	}
+
+	sortedRoutes := append(echo.Routes{}, routes...)
+	sort.SliceStable(sortedRoutes, func(i, j int) bool { return sortedRoutes[i].Path < sortedRoutes[j].Path })
+
+	operationIDs := map[string]bool{}
+	for _, route := range sortedRoutes {
+		if !strings.HasPrefix(route.Path, "/api/") || route.Path == "/api/docs" || !slices.Contains(openAPIMethods, route.Method) {
+			continue
+		}
+
+		operation := openAPIOperations[route.Method+" "+route.Path]
+		path, parameters := openAPIPath(route.Path)
+		for _, query := range operation.Query {
+			parameters = append(parameters, &qmModel.OpenAPIParameter{Name: query, In: "query", Schema: &qmModel.OpenAPISchema{Type: "string"}})
+		}
+
+		// Actions of different groups with the same name are prefixed with their group, eg. jobArchiveGetJob
+		operationID := openAPIOperationID(route)
+		if operationIDs[operationID] {
+			operationID = openAPITag(route.Path) + strings.ToUpper(operationID[:1]) + operationID[1:]
+		}
+		operationIDs[operationID] = true
+
+		summary := operation.Summary
+		if summary == "" {
+			summary = openAPISummary(operationID)
+		}
+
+		apiOperation := &qmModel.OpenAPIOperation{
+			OperationID: operationID,
+			Summary:     summary,
+			Tags:        []string{openAPITag(route.Path)},
+			Parameters:  parameters,
+			Responses: map[string]*qmModel.OpenAPIResponse{
+				"200":     {Description: "OK"},
+				"default": {Description: "Error message", Content: map[string]*qmModel.OpenAPIMediaType{"text/plain": {Schema: &qmModel.OpenAPISchema{Type: "string"}}}},
+			},
+		}
+		if deprecation := deprecations[route.Method+" "+route.Path]; deprecation != nil {
+			apiOperation.Deprecated = true
+			apiOperation.Description = deprecationMessage(deprecation)
+		}
+		if operation.Request != nil {
+			schema := schemas.schema(reflect.TypeOf(operation.Request))
+			apiOperation.RequestBody = &qmModel.OpenAPIRequestBody{
+				Required: true,
+				Content: map[string]*qmModel.OpenAPIMediaType{
+					echo.MIMEApplicationJSON: {Schema: schema},
+					echo.MIMEApplicationForm: {Schema: schema},
+				},
+			}
+		}
+		if operation.Files {
+			apiOperation.RequestBody = &qmModel.OpenAPIRequestBody{
+				Required: true,
+				Content: map[string]*qmModel.OpenAPIMediaType{
+					echo.MIMEMultipartForm: {Schema: &qmModel.OpenAPISchema{
+						Type: "object",
+						Properties: map[string]*qmModel.OpenAPISchema{
+							"files": {Type: "array", Items: &qmModel.OpenAPISchema{Type: "string", Format: "binary"}},
+						},
+					}},
+				},
+			}
+		}
+		if operation.Response != nil {
+			apiOperation.Responses["200"].Content = map[string]*qmModel.OpenAPIMediaType{
+				echo.MIMEApplicationJSON: {Schema: schemas.schema(reflect.TypeOf(operation.Response))},
+			}
+		}
+
+		if document.Paths[path] == nil {
+			document.Paths[path] = map[string]*qmModel.OpenAPIOperation{}
+		}
+		document.Paths[path][strings.ToLower(route.Method)] = apiOperation
+	}
+
+	document.Components = qmModel.OpenAPIComponents{
+		Schemas: schemas.components,
+		SecuritySchemes: map[string]*qmModel.OpenAPISecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer", Description: "API key"},
+			"cookieAuth": {Type: "apiKey", In: "cookie", Name: SESSION_COOKIE_NAME, Description: "Session of a login"},
+		},
+	}
+	return document
+}
+
+// openAPIPath converts the Echo path to an OpenAPI path with its path parameters, eg. /job/:rid to /job/{rid}.
+func openAPIPath(echoPath string) (string, []*qmModel.OpenAPIParameter) {
+	parameters := []*qmModel.OpenAPIParameter{}
+	segments := strings.Split(echoPath, "/")
+	for i, segment := range segments {
+		name := ""
+		if strings.HasPrefix(segment, ":") {
+			name = segment[1:]
+		} else if segment == "*" {
+			name = "path"
+		}
+		if name != "" {
+			segments[i] = "{" + name + "}"
+			parameters = append(parameters, &qmModel.OpenAPIParameter{Name: name, In: "path", Required: true, Schema: &qmModel.OpenAPISchema{Type: "string"}})
+		}
+	}
+	return strings.Join(segments, "/"), parameters
+}
+
+// openAPIOperationID is the action of the route, eg. getTask for /api/task/getTask/:rid,
+// or the method with the path below /api for routes without action, eg. getStatsForecast for /api/stats/forecast.
+func openAPIOperationID(route echo.RouteInfo) string {
+	segments := []string{}
+	for _, segment := range strings.Split(strings.TrimPrefix(route.Path, "/api/"), "/") {
+		if segment != "" && segment != "*" && !strings.HasPrefix(segment, ":") {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) > 1 {
+		action := segments[len(segments)-1]
+		if strings.ToLower(action) != action && !openAPINonAlphanumeric.MatchString(action) {
+			return action
+		}
+	}
+
+	id := strings.ToLower(route.Method)
+	for _, segment := range segments {
+		for _, word := range openAPINonAlphanumeric.Split(segment, -1) {
+			if word != "" {
+				id += strings.ToUpper(word[:1]) + word[1:]
+			}
+		}
+	}
+	return id
+}
+
+// openAPISummary splits the operation ID into words, eg. GetJobTimeline is "Get job timeline" and GetOpenAPI "Get open API".
+func openAPISummary(operationID string) string {
+	runes := []rune(operationID)
+	words := []string{}
+	start := 0
+	for i := 1; i <= len(runes); i++ {
+		boundary := i == len(runes)
+		if !boundary && unicode.IsUpper(runes[i]) {
+			// A word starts after a lower case letter or at the last capital of an acronym followed by a word
+			boundary = !unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))
+		}
+		if boundary {
+			word := string(runes[start:i])
+			if strings.ToUpper(word) != word || len(word) == 1 {
+				word = strings.ToLower(word)
+			}
+			words = append(words, word)
+			start = i
+		}
+	}
+	summary := strings.Join(words, " ")
+	return strings.ToUpper(summary[:1]) + summary[1:]
+}
+
+// openAPITag groups the operations by the first segment below /api, eg. job for /api/job/getJob/:rid.
+func openAPITag(path string) string {
+	segments := strings.Split(strings.TrimPrefix(path, "/api/"), "/")
+	return segments[0]
+}
+
+// openAPISchemas builds the schemas of the models, structs are added to the components once and referenced.
+type openAPISchemas struct {
+	components map[string]*qmModel.OpenAPISchema
+	names      map[reflect.Type]string
+}
+
+// newOpenAPISchemas creates the schemas without components.
+func newOpenAPISchemas() *openAPISchemas {
+	return &openAPISchemas{
+		components: map[string]*qmModel.OpenAPISchema{},
+		names:      map[reflect.Type]string{},
+	}
+}
+
+var (
+	openAPITimeType       = reflect.TypeOf(time.Time{})
+	openAPIUUIDType       = reflect.TypeOf(uuid.UUID{})
+	openAPIRawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schema returns the schema of the type from its JSON encoding.
+func (s *openAPISchemas) schema(t reflect.Type) *qmModel.OpenAPISchema {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nullable = true
+	}
+
+	switch t {
+	case openAPITimeType:
+		return &qmModel.OpenAPISchema{Type: "string", Format: "date-time", Nullable: nullable}
+	case openAPIUUIDType:
+		return &qmModel.OpenAPISchema{Type: "string", Format: "uuid", Nullable: nullable}
+	case openAPIRawMessageType:
+		return &qmModel.OpenAPISchema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &qmModel.OpenAPISchema{Type: "boolean", Nullable: nullable}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &qmModel.OpenAPISchema{Type: "integer", Format: "int32", Nullable: nullable}
+	case reflect.Int64, reflect.Uint64:
+		return &qmModel.OpenAPISchema{Type: "integer", Format: "int64", Nullable: nullable}
+	case reflect.Float32, reflect.Float64:
+		return &qmModel.OpenAPISchema{Type: "number", Nullable: nullable}
+	case reflect.String:
+		return &qmModel.OpenAPISchema{Type: "string", Nullable: nullable}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &qmModel.OpenAPISchema{Type: "string", Format: "byte", Nullable: nullable}
+		}
+		return &qmModel.OpenAPISchema{Type: "array", Items: s.schema(t.Elem()), Nullable: nullable}
+	case reflect.Map:
+		return &qmModel.OpenAPISchema{Type: "object", AdditionalProperties: s.schema(t.Elem()), Nullable: nullable}
+	case reflect.Struct:
+		return &qmModel.OpenAPISchema{Ref: "#/components/schemas/" + s.component(t)}
+	default:
+		// Interfaces hold any value
+		return &qmModel.OpenAPISchema{}
+	}
+}
+
+// component adds the schema of the struct to the components and returns its name.
+func (s *openAPISchemas) component(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+
+	// Structs of different packages with the same name are numbered, eg. Job and Job2
+	name := openAPISchemaName(t)
+	for i := 2; s.components[name] != nil; i++ {
+		name = fmt.Sprintf("%s%d", openAPISchemaName(t), i)
+	}
+	s.names[t] = name
+	// Reserved before the fields, so recursive structs reference it
+	schema := &qmModel.OpenAPISchema{Type: "object", Properties: map[string]*qmModel.OpenAPISchema{}}
+	s.components[name] = schema
+
+	s.addFields(schema, t)
+	return name
+}
+
+// addFields adds the exported fields of the struct to the properties of the schema by their JSON name,
+// the fields of embedded structs are inlined like by the JSON encoding.
+func (s *openAPISchemas) addFields(schema *qmModel.OpenAPISchema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				s.addFields(schema, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = s.schema(field.Type)
+	}
+}
+
+// openAPISchemaName is the name of the struct without package, eg. PageTask for Page[*model.Task].
+func openAPISchemaName(t reflect.Type) string {
+	name := t.Name()
+	base, typeArguments, ok := strings.Cut(name, "[")
+	if !ok {
+		return name
+	}
+	for _, typeArgument := range strings.Split(strings.TrimSuffix(typeArguments, "]"), ",") {
+		typeArgument = typeArgument[strings.LastIndex(typeArgument, ".")+1:]
+		base += strings.TrimLeft(typeArgument, "*[]")
+	}
+	return base
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOpenAPIHandler(t *testing.T) {
+	handler := &ManagerHandler{}
+	e := echo.New()
+	e.GET("/api/openapi.json", handler.GetOpenAPI)
+	e.GET("/api/docs", handler.OpenAPIDocsView)
+	e.GET("/api/task/getTasks", handler.GetTasks)
+	e.GET("/api/task/getTask/:rid", handler.GetTask)
+	e.PUT("/api/task/putTask/:key", handler.PutTask)
+	e.POST("/api/file/uploadFiles", handler.UploadFiles)
+	e.GET("/api/connection/getConnections", handler.GetConnections)
+	e.GET("/api/jobArchive/getJob/:rid", handler.GetJobArchive)
+	e.POST("/api/job/getJob/:rid", handler.GetJob)
+	e.GET("/jobs", handler.JobsView)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var document qmModel.OpenAPIDocument
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &document))
+	assert.Equal(t, "3.0.3", document.OpenAPI)
+	assert.NotContains(t, document.Paths, "/jobs", "Expected only API routes to be documented")
+	assert.NotContains(t, document.Paths, "/api/docs", "Expected the Swagger UI not to be documented")
+
+	t.Run("Path parameters and operation IDs", func(t *testing.T) {
+		operation := document.Paths["/api/task/getTask/{rid}"]["get"]
+		require.NotNil(t, operation)
+		assert.Equal(t, "getTask", operation.OperationID)
+		assert.Equal(t, []string{"task"}, operation.Tags)
+		require.Len(t, operation.Parameters, 1)
+		assert.Equal(t, "rid", operation.Parameters[0].Name)
+		assert.Equal(t, "path", operation.Parameters[0].In)
+		assert.Equal(t, "#/components/schemas/Task", operation.Responses["200"].Content[echo.MIMEApplicationJSON].Schema.Ref)
+
+		assert.Equal(t, "getJob", document.Paths["/api/job/getJob/{rid}"]["post"].OperationID)
+		assert.Equal(t, "jobArchiveGetJob", document.Paths["/api/jobArchive/getJob/{rid}"]["get"].OperationID, "Expected duplicate actions to be prefixed with their group")
+	})
+
+	t.Run("Models are described in the components", func(t *testing.T) {
+		task := document.Components.Schemas["Task"]
+		require.NotNil(t, task)
+		assert.Equal(t, "string", task.Properties["rid"].Type)
+		assert.Equal(t, "uuid", task.Properties["rid"].Format)
+		assert.Equal(t, "date-time", task.Properties["created_at"].Format)
+		assert.Equal(t, "array", task.Properties["input_parameters"].Type)
+
+		operation := document.Paths["/api/task/putTask/{key}"]["put"]
+		require.NotNil(t, operation)
+		require.NotNil(t, operation.RequestBody)
+		assert.Equal(t, "#/components/schemas/TaskDefinition", operation.RequestBody.Content[echo.MIMEApplicationJSON].Schema.Ref)
+		assert.Contains(t, document.Components.Schemas, "Connection")
+	})
+
+	t.Run("Uploads are multipart forms", func(t *testing.T) {
+		operation := document.Paths["/api/file/uploadFiles"]["post"]
+		require.NotNil(t, operation)
+		assert.Equal(t, "binary", operation.RequestBody.Content[echo.MIMEMultipartForm].Schema.Properties["files"].Items.Format)
+	})
+
+	t.Run("Undocumented routes are summarized by their path", func(t *testing.T) {
+		operation := document.Paths["/api/openapi.json"]["get"]
+		require.NotNil(t, operation)
+		assert.Equal(t, "getOpenapiJson", operation.OperationID)
+		assert.Equal(t, "Get openapi json", operation.Summary)
+	})
+
+	t.Run("Swagger UI loads the document", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/docs", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "SwaggerUIBundle")
+		assert.Contains(t, rec.Body.String(), "openapi.json")
+	})
+}
+
+func TestOpenAPISummary(t *testing.T) {
+	assert.Equal(t, "Get job timeline", openAPISummary("GetJobTimeline"))
+	assert.Equal(t, "Get open API", openAPISummary("GetOpenAPI"))
+	assert.Equal(t, "Create API key", openAPISummary("CreateAPIKey"))
+	assert.Equal(t, "Get", openAPISummary("get"))
+}
+
+func TestOpenAPISchemaName(t *testing.T) {
+	schemas := newOpenAPISchemas()
+	schema := schemas.schema(reflect.TypeOf(&qmModel.Page[*qmModel.Task]{}))
+	assert.Equal(t, "#/components/schemas/PageTask", schema.Ref)
+	assert.Equal(t, "#/components/schemas/Task", schemas.components["PageTask"].Properties["items"].Items.Ref)
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/siherrmann/queuerManager/helper"
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/labstack/echo/v5"
+)
+
+// paginationConfigFromEnv reads the page sizes from QUEUER_MANAGER_API_PAGE_SIZE, QUEUER_MANAGER_VIEW_PAGE_SIZE
+// and QUEUER_MANAGER_MAX_PAGE_SIZE. Invalid values fall back to the defaults, the default page sizes are capped by the max page size.
+func paginationConfigFromEnv() *model.PaginationConfig {
+	config := &model.PaginationConfig{
+		APIPageSize:  pageSizeFromEnv("QUEUER_MANAGER_API_PAGE_SIZE", model.DEFAULT_API_PAGE_SIZE),
+		ViewPageSize: pageSizeFromEnv("QUEUER_MANAGER_VIEW_PAGE_SIZE", model.DEFAULT_VIEW_PAGE_SIZE),
+		MaxPageSize:  pageSizeFromEnv("QUEUER_MANAGER_MAX_PAGE_SIZE", model.DEFAULT_MAX_PAGE_SIZE),
+	}
+	config.APIPageSize = min(config.APIPageSize, config.MaxPageSize)
+	config.ViewPageSize = min(config.ViewPageSize, config.MaxPageSize)
+	return config
+}
+
+// pageSizeFromEnv reads a positive page size from the environment variable, invalid values fall back to the default.
+func pageSizeFromEnv(key string, defaultPageSize int) int {
+	value := helper.GetEnvOrDefault(key, strconv.Itoa(defaultPageSize))
+	pageSize, err := strconv.Atoi(value)
+	if err != nil || pageSize <= 0 {
+		log.Printf("Invalid %s %q, using default %d", key, value, defaultPageSize)
+		return defaultPageSize
+	}
+	return pageSize
+}
+
+// paginationConfig returns the page sizes of the deployment, the defaults if the handler has none.
+func (m *ManagerHandler) paginationConfig() *model.PaginationConfig {
+	if m.Pagination == nil {
+		return &model.PaginationConfig{
+			APIPageSize:  model.DEFAULT_API_PAGE_SIZE,
+			ViewPageSize: model.DEFAULT_VIEW_PAGE_SIZE,
+			MaxPageSize:  model.DEFAULT_MAX_PAGE_SIZE,
+		}
+	}
+	return m.Pagination
+}
+
+// apiPagination parses the lastId and limit query parameters of an API endpoint, see pagination.
+func (m *ManagerHandler) apiPagination(c *echo.Context) (int, int, error) {
+	return m.pagination(c, m.paginationConfig().APIPageSize)
+}
+
+// viewPagination parses the lastId and limit query parameters of a view, see pagination.
+func (m *ManagerHandler) viewPagination(c *echo.Context) (int, int, error) {
+	return m.pagination(c, m.paginationConfig().ViewPageSize)
+}
+
+// pagination parses the lastId (default 0) and limit (default the page size) query parameters.
+// The limit is enforced to be between 1 and the max page size of the deployment.
+func (m *ManagerHandler) pagination(c *echo.Context, pageSize int) (int, int, error) {
+	maxPageSize := m.paginationConfig().MaxPageSize
+	lastIdStr := c.QueryParam("lastId")
+	limitStr := c.QueryParam("limit")
+
+	// Parse lastId with default
+	lastId := 0
+	if lastIdStr != "" {
+		parsedLastId, err := strconv.Atoi(lastIdStr)
+		if err != nil || parsedLastId < 0 {
+			return 0, 0, fmt.Errorf("Invalid lastId format")
+		}
+		lastId = parsedLastId
+	}
+
+	// Parse limit with default
+	limit := pageSize
+	if limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err != nil || parsedLimit <= 0 || parsedLimit > maxPageSize {
+			return 0, 0, fmt.Errorf("Invalid limit (must be 1-%d)", maxPageSize)
+		}
+		limit = parsedLimit
+	}
+
+	return lastId, limit, nil
+}
+
+// withEnvelope reports if a list API endpoint returns a page envelope instead of a bare array, eg. ?envelope=true.
+// The bare array stays the default so existing clients keep working.
+func withEnvelope(c *echo.Context) bool {
+	return c.QueryParam("envelope") == "true"
+}
+
+// pageLimit returns the number of entries to retrieve for a page.
+// A page envelope retrieves one more entry than the limit to know if there are more pages.
+func pageLimit(limit int, envelope bool) int {
+	if envelope {
+		return limit + 1
+	}
+	return limit
+}
+
+// newPage returns the page envelope of the entries retrieved with pageLimit.
+// The lastId of the page is the ID of its last entry, returned by idOf.
+func newPage[T any](entries []T, limit int, total int, idOf func(T) int) *model.Page[T] {
+	page := &model.Page[T]{Items: entries, Total: total}
+	if len(entries) > limit {
+		page.Items = entries[:limit]
+		page.HasMore = true
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	if len(page.Items) > 0 {
+		page.LastID = idOf(page.Items[len(page.Items)-1])
+	}
+	return page
+}
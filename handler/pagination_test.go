@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginationConfigFromEnv(t *testing.T) {
+	t.Run("Defaults without environment", func(t *testing.T) {
+		config := paginationConfigFromEnv()
+		assert.Equal(t, qmModel.DEFAULT_API_PAGE_SIZE, config.APIPageSize)
+		assert.Equal(t, qmModel.DEFAULT_VIEW_PAGE_SIZE, config.ViewPageSize)
+		assert.Equal(t, qmModel.DEFAULT_MAX_PAGE_SIZE, config.MaxPageSize)
+	})
+
+	t.Run("Default page sizes are capped by the max page size", func(t *testing.T) {
+		t.Setenv("QUEUER_MANAGER_VIEW_PAGE_SIZE", "500")
+		t.Setenv("QUEUER_MANAGER_MAX_PAGE_SIZE", "250")
+		config := paginationConfigFromEnv()
+		assert.Equal(t, 250, config.ViewPageSize)
+		assert.Equal(t, 250, config.MaxPageSize)
+	})
+
+	t.Run("Invalid values fall back to the defaults", func(t *testing.T) {
+		t.Setenv("QUEUER_MANAGER_API_PAGE_SIZE", "0")
+		t.Setenv("QUEUER_MANAGER_MAX_PAGE_SIZE", "many")
+		config := paginationConfigFromEnv()
+		assert.Equal(t, qmModel.DEFAULT_API_PAGE_SIZE, config.APIPageSize)
+		assert.Equal(t, qmModel.DEFAULT_MAX_PAGE_SIZE, config.MaxPageSize)
+	})
+}
+
+func TestPagination(t *testing.T) {
+	handler := &ManagerHandler{Pagination: &qmModel.PaginationConfig{APIPageSize: 5, ViewPageSize: 20, MaxPageSize: 50}}
+	e := echo.New()
+
+	paginate := func(query string, view bool) (int, int, error) {
+		req := httptest.NewRequest(http.MethodGet, "/"+query, nil)
+		c := e.NewContext(req, httptest.NewRecorder())
+		if view {
+			return handler.viewPagination(c)
+		}
+		return handler.apiPagination(c)
+	}
+
+	lastId, limit, err := paginate("", false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, lastId)
+	assert.Equal(t, 5, limit)
+
+	lastId, limit, err = paginate("?lastId=7", true)
+	require.NoError(t, err)
+	assert.Equal(t, 7, lastId)
+	assert.Equal(t, 20, limit)
+
+	_, limit, err = paginate("?limit=50", false)
+	require.NoError(t, err)
+	assert.Equal(t, 50, limit)
+
+	_, _, err = paginate("?limit=51", false)
+	assert.EqualError(t, err, "Invalid limit (must be 1-50)")
+
+	_, _, err = paginate("?lastId=-1", false)
+	assert.EqualError(t, err, "Invalid lastId format")
+
+	t.Run("Handlers without config use the defaults", func(t *testing.T) {
+		handler := &ManagerHandler{}
+		req := httptest.NewRequest(http.MethodGet, "/?limit=101", nil)
+		_, _, err := handler.viewPagination(e.NewContext(req, httptest.NewRecorder()))
+		assert.EqualError(t, err, "Invalid limit (must be 1-100)")
+	})
+}
+
+func TestNewPage(t *testing.T) {
+	idOf := func(id int) int { return id }
+
+	page := newPage([]int{3, 4, 5, 6}, 3, 10, idOf)
+	assert.Equal(t, []int{3, 4, 5}, page.Items)
+	assert.Equal(t, 10, page.Total)
+	assert.Equal(t, 5, page.LastID)
+	assert.True(t, page.HasMore)
+
+	page = newPage([]int{9, 10}, 3, 10, idOf)
+	assert.Equal(t, []int{9, 10}, page.Items)
+	assert.Equal(t, 10, page.LastID)
+	assert.False(t, page.HasMore, "Expected no more pages after the last page")
+
+	page = newPage[int](nil, 3, 0, idOf)
+	assert.Equal(t, []int{}, page.Items, "Expected an empty list instead of null")
+	assert.Equal(t, 0, page.LastID)
+	assert.False(t, page.HasMore)
+}
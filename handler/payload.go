@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/siherrmann/queuerManager/helper"
+)
+
+// DEFAULT_MAX_JOB_PAYLOAD_BYTES is the default limit of the encoded parameters of a single job (1 MiB)
+const DEFAULT_MAX_JOB_PAYLOAD_BYTES = 1 << 20
+
+var errJobPayloadTooLarge = errors.New("job parameters too large")
+
+// maxJobPayloadBytesFromEnv reads the job payload limit from QUEUER_MANAGER_MAX_JOB_PAYLOAD_BYTES.
+// Invalid values fall back to the default, 0 disables the limit.
+func maxJobPayloadBytesFromEnv() int {
+	value := helper.GetEnvOrDefault("QUEUER_MANAGER_MAX_JOB_PAYLOAD_BYTES", strconv.Itoa(DEFAULT_MAX_JOB_PAYLOAD_BYTES))
+	maxBytes, err := strconv.Atoi(value)
+	if err != nil || maxBytes < 0 {
+		log.Printf("Invalid QUEUER_MANAGER_MAX_JOB_PAYLOAD_BYTES %q, using default %d", value, DEFAULT_MAX_JOB_PAYLOAD_BYTES)
+		return DEFAULT_MAX_JOB_PAYLOAD_BYTES
+	}
+	return maxBytes
+}
+
+// checkJobPayloadSize returns errJobPayloadTooLarge if the JSON encoded parameters exceed the configured limit.
+func (m *ManagerHandler) checkJobPayloadSize(parameters []any, parametersKeyed map[string]any) error {
+	if m.MaxJobPayloadBytes <= 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(map[string]any{"parameters": parameters, "parameters_keyed": parametersKeyed})
+	if err != nil {
+		return fmt.Errorf("error encoding job parameters: %w", err)
+	}
+	if len(payload) > m.MaxJobPayloadBytes {
+		return fmt.Errorf("%w: %s exceed the limit of %s", errJobPayloadTooLarge, formatBytes(len(payload)), formatBytes(m.MaxJobPayloadBytes))
+	}
+	return nil
+}
+
+// formatBytes formats a byte count in a human readable unit.
+func formatBytes(bytes int) string {
+	switch {
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(bytes)/(1<<20))
+	case bytes >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(bytes)/(1<<10))
+	default:
+		return fmt.Sprintf("%d bytes", bytes)
+	}
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/siherrmann/queuerManager/view/components"
+
+	"github.com/labstack/echo/v5"
+)
+
+// =======Popup Handlers=======
+
+// DismissPopup removes the message popup with the ID of the path out of band,
+// eg. when the auto-dismiss timer of an info or success popup expires.
+func (m *ManagerHandler) DismissPopup(c *echo.Context) error {
+	id := c.Param("id")
+	if !components.IsPopupID(id) {
+		return c.String(http.StatusBadRequest, "Invalid popup ID")
+	}
+
+	c.Response().Header().Add("HX-Reswap", "none")
+
+	return render(c, components.PopupDismiss(id))
+}
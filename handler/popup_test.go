@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/siherrmann/queuerManager/view/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDismissPopupHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	e := echo.New()
+
+	t.Run("DismissPopup with valid ID", func(t *testing.T) {
+		id := components.NewPopupID(components.POPUP_KIND_SUCCESS)
+
+		req := httptest.NewRequest(http.MethodDelete, "/popup/"+id, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "id", Value: id}})
+
+		err := handler.DismissPopup(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "none", rec.Header().Get("HX-Reswap"))
+		assert.Equal(t, `<div id="`+id+`" hx-swap-oob="delete"></div>`, rec.Body.String())
+	})
+
+	t.Run("DismissPopup with invalid ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/popup/body", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "id", Value: "body"}})
+
+		err := handler.DismissPopup(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Message popups replace popups of the same kind", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/task/addTask", nil)
+		req.Header.Set("HX-Request", "true")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := renderPopupOrJson(c, http.StatusBadRequest, "Task key is required")
+		require.NoError(t, err)
+
+		assert.Contains(t, rec.Body.String(), `data-popup-kind="error"`)
+		assert.Contains(t, rec.Body.String(), "if popup is not me remove popup")
+		assert.NotContains(t, rec.Body.String(), "hx-delete", "Expected errors to not be dismissed automatically")
+	})
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+)
+
+// ValidatePublicDashboardPath checks the path of the public dashboard, it must be an absolute path
+// that does not overlap the routes of the manager.
+func ValidatePublicDashboardPath(path string) error {
+	if path == "" {
+		return nil
+	}
+	if !strings.HasPrefix(path, "/") || path == "/" || strings.ContainsAny(path, ":*?# ") {
+		return fmt.Errorf("invalid public dashboard path %q (must be an absolute path like /public/dashboard)", path)
+	}
+	for _, prefix := range []string{"/api/", "/static/", "/events/", "/scim/"} {
+		if strings.HasPrefix(path, prefix) {
+			return fmt.Errorf("invalid public dashboard path %q (must not start with %s)", path, prefix)
+		}
+	}
+	return nil
+}
+
+// isPublicDashboardPath reports if the path is the path of the enabled public dashboard.
+func (m *ManagerHandler) isPublicDashboardPath(path string) bool {
+	return m.PublicDashboardPath != "" && path == m.PublicDashboardPath
+}
+
+// newPublicDashboard creates the public dashboard from the job status counts of the window,
+// the active worker count and the recently ended jobs. The parameters, results and errors of the jobs are left out.
+func newPublicDashboard(counts []*qmModel.StatusCount, activeWorkers int, endedJobs []*model.Job, now time.Time) *qmModel.PublicDashboard {
+	dashboard := &qmModel.PublicDashboard{
+		ActiveWorkers: activeWorkers,
+		RecentJobs:    []*qmModel.PublicJob{},
+		CheckedAt:     now,
+	}
+
+	for _, count := range counts {
+		switch count.Status {
+		case model.JobStatusQueued, model.JobStatusScheduled:
+			dashboard.QueueDepth += count.Count
+		case model.JobStatusRunning:
+			dashboard.Running += count.Count
+		case model.JobStatusSucceeded:
+			dashboard.Succeeded += count.Count
+		case model.JobStatusFailed:
+			dashboard.Failed += count.Count
+		case model.JobStatusCancelled:
+			dashboard.Cancelled += count.Count
+		}
+	}
+	if dashboard.Ended() > 0 {
+		dashboard.SuccessRate = float64(dashboard.Succeeded) / float64(dashboard.Ended())
+	}
+
+	for _, job := range endedJobs {
+		publicJob := &qmModel.PublicJob{
+			TaskName:   job.TaskName,
+			Status:     job.Status,
+			StatusName: qmModel.StatusName(job.Status),
+			EndedAt:    job.UpdatedAt,
+		}
+		if job.StartedAt != nil {
+			publicJob.Duration = job.UpdatedAt.Sub(*job.StartedAt).Round(time.Second).String()
+		}
+		dashboard.RecentJobs = append(dashboard.RecentJobs, publicJob)
+	}
+
+	return dashboard
+}
+
+// publicDashboard collects the queue depth, the success rate and the recently ended jobs of the public dashboard.
+func (m *ManagerHandler) publicDashboard() (*qmModel.PublicDashboard, error) {
+	now := time.Now()
+
+	counts, err := m.MetricDB.SelectJobStatusCounts(now.Add(-qmModel.PUBLIC_DASHBOARD_WINDOW))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve jobs per status: %v", err)
+	}
+
+	activeWorkers, err := m.MetricDB.SelectActiveWorkersCount()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve active workers: %v", err)
+	}
+
+	endedJobs, err := m.Queuer.GetJobsEnded(0, qmModel.PUBLIC_DASHBOARD_RECENT_JOBS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve recent jobs: %v", err)
+	}
+
+	return newPublicDashboard(counts, activeWorkers, endedJobs, now), nil
+}
+
+// =======View Handlers=======
+
+// PublicDashboardView renders the read-only public dashboard without login at QUEUER_MANAGER_PUBLIC_DASHBOARD_PATH.
+// The page reloads its content every 30 seconds, `?format=json` returns the dashboard as JSON.
+func (m *ManagerHandler) PublicDashboardView(c *echo.Context) error {
+	if m.MetricDB == nil {
+		return c.String(http.StatusServiceUnavailable, "Metrics are not enabled")
+	}
+
+	dashboard, err := m.publicDashboard()
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to load dashboard")
+	}
+
+	if c.QueryParam("format") == "json" {
+		return c.JSON(http.StatusOK, dashboard)
+	}
+	if c.Request().Header.Get("HX-Request") != "" {
+		return render(c, screens.PublicDashboardContent(dashboard))
+	}
+	return render(c, screens.PublicDashboard(dashboard, m.PublicDashboardPath))
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPublicDashboard(t *testing.T) {
+	now := time.Now()
+	startedAt := now.Add(-90 * time.Second)
+	counts := []*qmModel.StatusCount{
+		{Status: model.JobStatusQueued, Count: 4},
+		{Status: model.JobStatusScheduled, Count: 1},
+		{Status: model.JobStatusRunning, Count: 2},
+		{Status: model.JobStatusSucceeded, Count: 6},
+		{Status: model.JobStatusFailed, Count: 1},
+		{Status: model.JobStatusCancelled, Count: 1},
+	}
+	jobs := []*model.Job{
+		{RID: uuid.New(), TaskName: "export", Status: model.JobStatusFailed, Error: "secret connection string", StartedAt: &startedAt, UpdatedAt: now},
+		{RID: uuid.New(), TaskName: "import", Status: model.JobStatusCancelled, UpdatedAt: now},
+	}
+
+	dashboard := newPublicDashboard(counts, 3, jobs, now)
+	assert.Equal(t, 5, dashboard.QueueDepth, "Expected queued and scheduled jobs to be in the queue")
+	assert.Equal(t, 2, dashboard.Running)
+	assert.Equal(t, 8, dashboard.Ended())
+	assert.InDelta(t, 0.75, dashboard.SuccessRate, 0.0001)
+	assert.Equal(t, 3, dashboard.ActiveWorkers)
+
+	require.Len(t, dashboard.RecentJobs, 2)
+	assert.Equal(t, "export", dashboard.RecentJobs[0].TaskName)
+	assert.Equal(t, "1m30s", dashboard.RecentJobs[0].Duration)
+	assert.Empty(t, dashboard.RecentJobs[1].Duration, "Expected no duration of a job that never started")
+
+	empty := newPublicDashboard(nil, 0, nil, now)
+	assert.Zero(t, empty.SuccessRate)
+	assert.NotNil(t, empty.RecentJobs)
+}
+
+func TestValidatePublicDashboardPath(t *testing.T) {
+	assert.NoError(t, ValidatePublicDashboardPath(""))
+	assert.NoError(t, ValidatePublicDashboardPath("/public/dashboard"))
+	assert.Error(t, ValidatePublicDashboardPath("public"))
+	assert.Error(t, ValidatePublicDashboardPath("/"))
+	assert.Error(t, ValidatePublicDashboardPath("/public/:id"))
+	assert.Error(t, ValidatePublicDashboardPath("/api/public"))
+}
+
+func TestPublicDashboardAuth(t *testing.T) {
+	handler := &ManagerHandler{
+		Authenticator:       &testAuthenticator{password: "secret", roles: map[string]string{}},
+		PublicDashboardPath: "/public/dashboard",
+	}
+	next := func(c *echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+
+	e := echo.New()
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{path: "/public/dashboard", status: http.StatusOK},
+		{path: "/dashboard", status: http.StatusSeeOther},
+		{path: "/public/dashboard/other", status: http.StatusSeeOther},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, test.path, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		require.NoError(t, handler.AuthMiddleware(next)(c))
+		assert.Equal(t, test.status, rec.Code, "Unexpected status of %s", test.path)
+	}
+}
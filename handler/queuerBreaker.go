@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/siherrmann/queuerManager/helper"
+	"github.com/siherrmann/queuerManager/view/components"
+
+	"github.com/labstack/echo/v5"
+)
+
+// queuerUnavailableMessage is the response of the requests to the queuer while the breaker is open
+const queuerUnavailableMessage = "Queue backend unavailable, retrying automatically"
+
+// queuerPingTimeout is the timeout of a ping of the queuer database by the breaker
+const queuerPingTimeout = 2 * time.Second
+
+// queuerBreakerPathPrefixes are the views and endpoints that need the queuer, they are rejected while the breaker is open
+var queuerBreakerPathPrefixes = []string{"/job", "/worker", "/batch", "/api/job", "/api/worker", "/api/batch", "/api/v1/jobs", "/api/ci/"}
+
+// QueuerBreaker is a circuit breaker for the database of the queuer. It opens if the database does not respond
+// to a ping after a request to the queuer failed, requests to the queuer are rejected while it is open.
+// After the retry interval the next request pings the database again and closes the breaker if it responds.
+type QueuerBreaker struct {
+	mu            sync.Mutex
+	ping          func(ctx context.Context) error
+	retryInterval time.Duration
+	openedAt      time.Time
+	checkedAt     time.Time
+	lastError     error
+}
+
+// NewQueuerBreaker creates a new closed instance of QueuerBreaker.
+func NewQueuerBreaker(ping func(ctx context.Context) error, retryInterval time.Duration) *QueuerBreaker {
+	return &QueuerBreaker{
+		ping:          ping,
+		retryInterval: retryInterval,
+	}
+}
+
+// queuerRetryIntervalFromEnv reads the retry interval of the breaker from QUEUER_MANAGER_QUEUER_RETRY_INTERVAL, 5s by default.
+func queuerRetryIntervalFromEnv() time.Duration {
+	retryInterval, err := time.ParseDuration(helper.GetEnvOrDefault("QUEUER_MANAGER_QUEUER_RETRY_INTERVAL", "5s"))
+	if err != nil || retryInterval <= 0 {
+		return 5 * time.Second
+	}
+	return retryInterval
+}
+
+// Check pings the database of the queuer, the breaker opens if the ping fails and closes if it succeeds.
+func (b *QueuerBreaker) Check(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, queuerPingTimeout)
+	defer cancel()
+	err := b.ping(ctx)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.checkedAt = time.Now()
+	b.lastError = err
+	if err == nil && !b.openedAt.IsZero() {
+		log.Printf("Queue backend available again after %v", b.checkedAt.Sub(b.openedAt).Round(time.Second))
+		b.openedAt = time.Time{}
+	} else if err != nil && b.openedAt.IsZero() {
+		log.Printf("Queue backend unavailable: %v", err)
+		b.openedAt = b.checkedAt
+	}
+	return err
+}
+
+// Allow reports whether requests to the queuer are allowed, that is if the breaker is closed.
+// If the breaker is open and the retry interval passed since the last check, the database is checked again.
+func (b *QueuerBreaker) Allow(ctx context.Context) bool {
+	b.mu.Lock()
+	if b.openedAt.IsZero() {
+		b.mu.Unlock()
+		return true
+	}
+	retry := time.Since(b.checkedAt) >= b.retryInterval
+	if retry {
+		// Only one request checks the database per retry interval
+		b.checkedAt = time.Now()
+	}
+	b.mu.Unlock()
+
+	return retry && b.Check(ctx) == nil
+}
+
+// OpenedAt returns when the breaker opened and the error of the last check, a zero time if it is closed.
+func (b *QueuerBreaker) OpenedAt() (time.Time, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.openedAt, b.lastError
+}
+
+// pingQueuer pings the database of the queuer.
+func (m *ManagerHandler) pingQueuer(ctx context.Context) error {
+	if m.QueuerDB == nil {
+		return fmt.Errorf("no database connection")
+	}
+	return m.QueuerDB.PingContext(ctx)
+}
+
+// isQueuerBreakerPath reports if the path needs the queuer.
+func isQueuerBreakerPath(path string) bool {
+	for _, prefix := range queuerBreakerPathPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// QueuerBreakerMiddleware rejects the requests to the queuer with 503 while the breaker is open.
+// A request to the queuer that fails with a server error checks the database, so the breaker opens if it is unavailable.
+func (m *ManagerHandler) QueuerBreakerMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c *echo.Context) error {
+		if m.QueuerBreaker == nil || !isQueuerBreakerPath(c.Request().URL.Path) {
+			return next(c)
+		}
+
+		if !m.QueuerBreaker.Allow(c.Request().Context()) {
+			c.Response().Header().Set("Retry-After", fmt.Sprintf("%.0f", m.QueuerBreaker.retryInterval.Seconds()))
+			return renderPopupOrJson(c, http.StatusServiceUnavailable, queuerUnavailableMessage)
+		}
+
+		// Unwrapped before the handler, later middlewares can replace the response writer
+		response, unwrapErr := echo.UnwrapResponse(c.Response())
+		err := next(c)
+		if err != nil || (unwrapErr == nil && response.Status >= http.StatusInternalServerError) {
+			_ = m.QueuerBreaker.Check(c.Request().Context())
+		}
+		return err
+	}
+}
+
+// =======View Handlers=======
+
+// QueuerBannerView renders the banner shown on all pages while the queue backend is unavailable, empty if it is available
+func (m *ManagerHandler) QueuerBannerView(c *echo.Context) error {
+	if m.QueuerBreaker == nil || m.QueuerBreaker.Allow(c.Request().Context()) {
+		return c.NoContent(http.StatusOK)
+	}
+
+	openedAt, _ := m.QueuerBreaker.OpenedAt()
+	return render(c, components.QueuerUnavailableBanner(openedAt))
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueuerBreaker(t *testing.T) {
+	var pingErr error
+	pings := 0
+	breaker := NewQueuerBreaker(func(ctx context.Context) error {
+		pings++
+		return pingErr
+	}, time.Hour)
+
+	assert.True(t, breaker.Allow(context.Background()), "Expected a new breaker to be closed")
+	assert.Equal(t, 0, pings, "Expected a closed breaker to not ping")
+
+	pingErr = fmt.Errorf("connection refused")
+	require.Error(t, breaker.Check(context.Background()))
+	openedAt, lastErr := breaker.OpenedAt()
+	assert.False(t, openedAt.IsZero())
+	assert.EqualError(t, lastErr, "connection refused")
+
+	pingErr = nil
+	assert.False(t, breaker.Allow(context.Background()), "Expected the open breaker to wait for the retry interval")
+	assert.Equal(t, 1, pings)
+
+	breaker.retryInterval = 0
+	assert.True(t, breaker.Allow(context.Background()), "Expected the breaker to close when the ping succeeds")
+	assert.Equal(t, 2, pings)
+	openedAt, _ = breaker.OpenedAt()
+	assert.True(t, openedAt.IsZero())
+}
+
+func TestQueuerBreakerMiddleware(t *testing.T) {
+	pingErr := fmt.Errorf("connection refused")
+	handler := &ManagerHandler{}
+	handler.QueuerBreaker = NewQueuerBreaker(func(ctx context.Context) error { return pingErr }, time.Hour)
+	e := echo.New()
+
+	serve := func(path string, status int) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		err := handler.QueuerBreakerMiddleware(func(c *echo.Context) error {
+			return c.String(status, "handled")
+		})(c)
+		require.NoError(t, err)
+		return rec
+	}
+
+	rec := serve("/api/job/getJobs", http.StatusInternalServerError)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code, "Expected the failed request to open the breaker")
+
+	rec = serve("/api/worker/getWorkers", http.StatusOK)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), queuerUnavailableMessage)
+	assert.Equal(t, "3600", rec.Header().Get("Retry-After"))
+
+	rec = serve("/api/task/getTasks", http.StatusOK)
+	assert.Equal(t, http.StatusOK, rec.Code, "Expected requests that do not need the queuer to be allowed")
+
+	pingErr = nil
+	handler.QueuerBreaker.retryInterval = 0
+	rec = serve("/jobs", http.StatusOK)
+	assert.Equal(t, http.StatusOK, rec.Code, "Expected the breaker to recover when the database responds")
+}
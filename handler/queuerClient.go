@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"database/sql"
+
+	"github.com/siherrmann/queuer/model"
+
+	"github.com/google/uuid"
+)
+
+// QueuerClient is the part of the queuer the manager handler uses to add, read and cancel jobs and to manage workers.
+// It is implemented by *queuer.Queuer and by fake.Queuer of the testutil/fake package, so the handlers can be
+// unit tested without a database.
+type QueuerClient interface {
+	AddJob(task interface{}, parametersKeyed map[string]interface{}, parameters ...interface{}) (*model.Job, error)
+	AddJobWithOptions(options *model.Options, task interface{}, parametersKeyed map[string]interface{}, parameters ...interface{}) (*model.Job, error)
+	AddJobTx(tx *sql.Tx, task interface{}, parametersKeyed map[string]interface{}, parameters ...interface{}) (*model.Job, error)
+	AddJobWithOptionsTx(tx *sql.Tx, options *model.Options, task interface{}, parametersKeyed map[string]interface{}, parameters ...interface{}) (*model.Job, error)
+	AddJobs(batchJobs []model.BatchJob) error
+	CancelJob(jobRid uuid.UUID) (*model.Job, error)
+	DeleteJob(jobRid uuid.UUID) error
+	ReaddJobFromArchive(jobRid uuid.UUID) (*model.Job, error)
+	GetJob(jobRid uuid.UUID) (*model.Job, error)
+	GetJobs(lastId int, entries int) ([]*model.Job, error)
+	GetJobsBySearch(search string, lastId int, entries int) ([]*model.Job, error)
+	GetJobEnded(jobRid uuid.UUID) (*model.Job, error)
+	GetJobsEnded(lastId int, entries int) ([]*model.Job, error)
+	GetJobsEndedBySearch(search string, lastId int, entries int) ([]*model.Job, error)
+	GetWorker(workerRid uuid.UUID) (*model.Worker, error)
+	GetWorkers(lastId int, entries int) ([]*model.Worker, error)
+	GetWorkersBySearch(search string, lastId int, entries int) ([]*model.Worker, error)
+	StopWorker(workerRid uuid.UUID) error
+	StopWorkerGracefully(workerRid uuid.UUID) error
+	GetConnections() ([]*model.Connection, error)
+	Stop() error
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/testutil/fake"
+	"github.com/siherrmann/queuerManager/upload"
+	vm "github.com/siherrmann/validator/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagerHandlerWithFakes(t *testing.T) {
+	taskDB := fake.NewTaskDB()
+	fakeQueuer := fake.NewQueuer()
+	handler := NewManagerHandler(upload.NewFilesystemMemory(), taskDB, fakeQueuer)
+	e := echo.New()
+
+	task, err := taskDB.InsertTask(&qmModel.Task{
+		Key:                  "test-fake-task",
+		Name:                 "Test Fake Task",
+		InputParameters:      []vm.Validation{{Key: "count", Type: vm.Int, Requirement: "min1"}},
+		InputParametersKeyed: []vm.Validation{},
+	})
+	require.NoError(t, err)
+
+	job := &model.Job{}
+	t.Run("CreateJob adds the job to the queuer", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs", strings.NewReader(`{"task_key": "test-fake-task", "parameters": {"count": 3}}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		err := handler.CreateJob(e.NewContext(req, rec))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), job))
+		assert.Equal(t, task.Key, job.TaskName)
+		assert.Equal(t, model.JobStatusQueued, job.Status)
+
+		jobs, err := fakeQueuer.GetJobs(0, 10)
+		require.NoError(t, err)
+		assert.Len(t, jobs, 1)
+	})
+
+	t.Run("CreateJob with invalid parameters is rejected", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs", strings.NewReader(`{"task_key": "test-fake-task", "parameters": {"count": 0}}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		err := handler.CreateJob(e.NewContext(req, rec))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("GetJob finds the ended job in the archive", func(t *testing.T) {
+		_, err := fakeQueuer.EndJob(job.RID, model.JobStatusSucceeded, []interface{}{"done"}, "")
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/job/getJob/"+job.RID.String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "rid", Value: job.RID.String()}})
+
+		err = handler.GetJob(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		endedJob := &model.Job{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), endedJob))
+		assert.Equal(t, model.JobStatusSucceeded, endedJob.Status)
+	})
+}
+
+func TestFakeTaskDB(t *testing.T) {
+	taskDB := fake.NewTaskDB()
+
+	first, err := taskDB.InsertTask(&qmModel.Task{Key: "report-daily", Name: "Daily Report", Namespace: "reports"})
+	require.NoError(t, err)
+	_, err = taskDB.InsertTask(&qmModel.Task{Key: "report-weekly", Name: "Weekly Report", Namespace: "reports"})
+	require.NoError(t, err)
+	_, err = taskDB.InsertTask(&qmModel.Task{Key: "cleanup", Name: "Cleanup"})
+	require.NoError(t, err)
+
+	_, err = taskDB.InsertTask(&qmModel.Task{Key: "cleanup"})
+	assert.Error(t, err, "Expected duplicate keys to be rejected")
+
+	tasks, err := taskDB.SelectAllTasks(first.ID, 10)
+	require.NoError(t, err)
+	assert.Len(t, tasks, 2)
+
+	tasks, err = taskDB.SelectAllTasksByFilter(&qmModel.TaskFilter{KeyPrefix: "report-", Sort: qmModel.TASK_SORT_NAME, Descending: true}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "report-weekly", tasks[0].Key)
+
+	namespaces, err := taskDB.SelectNamespaces()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"reports"}, namespaces)
+
+	upserted, created, err := taskDB.UpsertTask(&qmModel.Task{Key: "cleanup", Name: "Cleanup Old Jobs"})
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, "Cleanup Old Jobs", upserted.Name)
+
+	deleted, err := taskDB.DeleteTaskByKey("cleanup")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	_, err = taskDB.SelectTaskByKey("cleanup")
+	assert.Error(t, err)
+}
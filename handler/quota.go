@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/siherrmann/queuerManager/helper"
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/labstack/echo/v5"
+)
+
+// quotaConfigFromEnv reads the fair-use limits per principal from QUEUER_MANAGER_QUOTA_JOBS_SOFT and
+// QUEUER_MANAGER_QUOTA_JOBS_HARD (jobs added in 24 hours) and QUEUER_MANAGER_QUOTA_STORAGE_SOFT_MB and
+// QUEUER_MANAGER_QUOTA_STORAGE_HARD_MB (megabytes of uploaded files). Unset or invalid limits are disabled.
+func quotaConfigFromEnv() *model.QuotaConfig {
+	return &model.QuotaConfig{
+		JobsSoft:         quotaLimitFromEnv("QUEUER_MANAGER_QUOTA_JOBS_SOFT"),
+		JobsHard:         quotaLimitFromEnv("QUEUER_MANAGER_QUOTA_JOBS_HARD"),
+		StorageSoftBytes: int64(quotaLimitFromEnv("QUEUER_MANAGER_QUOTA_STORAGE_SOFT_MB")) << 20,
+		StorageHardBytes: int64(quotaLimitFromEnv("QUEUER_MANAGER_QUOTA_STORAGE_HARD_MB")) << 20,
+	}
+}
+
+// quotaLimitFromEnv reads a limit from the environment variable, 0 (disabled) if it is unset or invalid.
+func quotaLimitFromEnv(key string) int {
+	value := helper.GetEnvOrDefault(key, "")
+	if value == "" {
+		return 0
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		log.Printf("Invalid %s %q, the limit is disabled", key, value)
+		return 0
+	}
+	return limit
+}
+
+// quotas returns the fair-use limits of the deployment, no limits if the handler has none.
+func (m *ManagerHandler) quotas() *model.QuotaConfig {
+	if m.Quotas == nil {
+		return &model.QuotaConfig{}
+	}
+	return m.Quotas
+}
+
+// quotaUsage returns the jobs added by the principal in the job window and the storage of its files.
+func (m *ManagerHandler) quotaUsage(principal string) (*model.QuotaUsage, error) {
+	usage := &model.QuotaUsage{Principal: principal, Limits: m.quotas()}
+
+	var err error
+	if m.JobInitiatorDB != nil {
+		usage.Jobs, err = m.JobInitiatorDB.SelectJobInitiatorsCountByInitiatorSince(principal, time.Now().Add(-model.QUOTA_JOB_WINDOW))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if m.FileOwnerDB != nil {
+		usage.StorageBytes, usage.Files, err = m.FileOwnerDB.SelectStorageUsage(principal)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	usage.Warnings = quotaWarnings(usage)
+	return usage, nil
+}
+
+// quotaWarnings returns the warnings of the soft limits the usage reached.
+func quotaWarnings(usage *model.QuotaUsage) []string {
+	warnings := []string{}
+	if soft := usage.Limits.JobsSoft; soft > 0 && usage.Jobs >= soft {
+		warnings = append(warnings, jobQuotaMessage(usage.Jobs, soft))
+	}
+	if soft := usage.Limits.StorageSoftBytes; soft > 0 && usage.StorageBytes >= soft {
+		warnings = append(warnings, storageQuotaMessage(usage.StorageBytes, soft))
+	}
+	return warnings
+}
+
+// jobQuotaMessage describes the jobs added in the job window compared to a limit.
+func jobQuotaMessage(jobs int, limit int) string {
+	return fmt.Sprintf("%d of %d jobs added in the last %s", jobs, limit, model.QUOTA_JOB_WINDOW)
+}
+
+// storageQuotaMessage describes the storage of the uploaded files compared to a limit.
+func storageQuotaMessage(bytes int64, limit int64) string {
+	return fmt.Sprintf("%s of %s of files uploaded", formatBytes(int(bytes)), formatBytes(int(limit)))
+}
+
+// JobQuotaMiddleware rejects job submissions of principals that added as many jobs as the hard limit in the job window
+// with 429 and adds the QUOTA_WARNING_HEADER to the submissions above the soft limit. The jobs are counted by their
+// initiator, so jobs added by schedules, job chains and integrations do not count. If the count fails the submission is allowed.
+func (m *ManagerHandler) JobQuotaMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c *echo.Context) error {
+		limits := m.quotas()
+		if (limits.JobsSoft == 0 && limits.JobsHard == 0) || m.JobInitiatorDB == nil {
+			return next(c)
+		}
+
+		jobs, err := m.JobInitiatorDB.SelectJobInitiatorsCountByInitiatorSince(requestedBy(c), time.Now().Add(-model.QUOTA_JOB_WINDOW))
+		if err != nil {
+			log.Printf("Error counting jobs for the job quota: %v", err)
+			return next(c)
+		}
+
+		if limits.JobsHard > 0 && jobs >= limits.JobsHard {
+			c.Response().Header().Set("Retry-After", strconv.Itoa(int(time.Hour.Seconds())))
+			return renderPopupOrJson(c, http.StatusTooManyRequests, "Job quota exceeded: "+jobQuotaMessage(jobs, limits.JobsHard))
+		}
+		if limits.JobsSoft > 0 && jobs >= limits.JobsSoft {
+			c.Response().Header().Add(model.QUOTA_WARNING_HEADER, jobQuotaMessage(jobs, limits.JobsSoft))
+		}
+
+		return next(c)
+	}
+}
+
+// StorageQuotaMiddleware rejects multipart uploads that would exceed the hard storage limit of the principal with 413
+// and adds the QUOTA_WARNING_HEADER to the uploads above the soft limit. The size of an upload is its content length.
+// If the usage can not be selected the upload is allowed.
+func (m *ManagerHandler) StorageQuotaMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c *echo.Context) error {
+		limits := m.quotas()
+		if (limits.StorageSoftBytes == 0 && limits.StorageHardBytes == 0) || m.FileOwnerDB == nil {
+			return next(c)
+		}
+		if !strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+			return next(c)
+		}
+
+		storageBytes, _, err := m.FileOwnerDB.SelectStorageUsage(requestedBy(c))
+		if err != nil {
+			log.Printf("Error selecting the storage usage for the storage quota: %v", err)
+			return next(c)
+		}
+
+		storageBytes += max(c.Request().ContentLength, 0)
+		if limits.StorageHardBytes > 0 && storageBytes > limits.StorageHardBytes {
+			return renderPopupOrJson(c, http.StatusRequestEntityTooLarge, "Storage quota exceeded: "+storageQuotaMessage(storageBytes, limits.StorageHardBytes))
+		}
+		if limits.StorageSoftBytes > 0 && storageBytes >= limits.StorageSoftBytes {
+			c.Response().Header().Add(model.QUOTA_WARNING_HEADER, storageQuotaMessage(storageBytes, limits.StorageSoftBytes))
+		}
+
+		return next(c)
+	}
+}
+
+// recordFileOwner records the principal and size of an uploaded file for the storage quota.
+// A failed record does not fail the upload.
+func (m *ManagerHandler) recordFileOwner(filename string, principal string, size int64) {
+	if m.FileOwnerDB == nil {
+		return
+	}
+
+	_, err := m.FileOwnerDB.UpsertFileOwner(&model.FileOwner{Filename: filename, Principal: principal, SizeBytes: size})
+	if err != nil {
+		log.Printf("Error recording owner of file %s: %v", filename, err)
+	}
+}
+
+// deleteFileOwner deletes the owner of a deleted file, so it does not count for the storage quota anymore.
+func (m *ManagerHandler) deleteFileOwner(filename string) {
+	if m.FileOwnerDB == nil {
+		return
+	}
+
+	err := m.FileOwnerDB.DeleteFileOwner(filename)
+	if err != nil {
+		log.Printf("Error deleting owner of file %s: %v", filename, err)
+	}
+}
+
+// GetQuotaUsage returns the usage and the fair-use limits of the principal of the request.
+func (m *ManagerHandler) GetQuotaUsage(c *echo.Context) error {
+	usage, err := m.quotaUsage(requestedBy(c))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to get quota usage: %v", err)})
+	}
+
+	return c.JSON(http.StatusOK, usage)
+}
+
+// GetPrincipalQuotaUsage returns the usage and the fair-use limits of a principal, eg. ?principal=apikey:deploy
+func (m *ManagerHandler) GetPrincipalQuotaUsage(c *echo.Context) error {
+	principal := c.QueryParam("principal")
+	if principal == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Principal is required"})
+	}
+
+	usage, err := m.quotaUsage(principal)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to get quota usage: %v", err)})
+	}
+
+	return c.JSON(http.StatusOK, usage)
+}
+
+// =======View Handlers=======
+
+// ProfileView renders the profile of the user of the request with its quota usage
+func (m *ManagerHandler) ProfileView(c *echo.Context) error {
+	usage, err := m.quotaUsage(requestedBy(c))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get quota usage: %v", err))
+	}
+
+	c.Response().Header().Add("HX-Push-Url", "/profile")
+	c.Response().Header().Add("HX-Retarget", "#body")
+
+	return render(c, screens.Profile(model.GetRequestContext(c).User, usage))
+}
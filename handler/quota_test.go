@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaConfigFromEnv(t *testing.T) {
+	t.Run("Without limits", func(t *testing.T) {
+		quotas := quotaConfigFromEnv()
+		assert.False(t, quotas.HasLimits())
+	})
+
+	t.Run("With limits", func(t *testing.T) {
+		t.Setenv("QUEUER_MANAGER_QUOTA_JOBS_SOFT", "80")
+		t.Setenv("QUEUER_MANAGER_QUOTA_JOBS_HARD", "100")
+		t.Setenv("QUEUER_MANAGER_QUOTA_STORAGE_SOFT_MB", "5")
+		t.Setenv("QUEUER_MANAGER_QUOTA_STORAGE_HARD_MB", "invalid")
+
+		quotas := quotaConfigFromEnv()
+		assert.True(t, quotas.HasLimits())
+		assert.Equal(t, 80, quotas.JobsSoft)
+		assert.Equal(t, 100, quotas.JobsHard)
+		assert.Equal(t, int64(5*1024*1024), quotas.StorageSoftBytes)
+		assert.Equal(t, int64(0), quotas.StorageHardBytes, "Expected an invalid limit to be disabled")
+	})
+}
+
+func TestQuotaWarnings(t *testing.T) {
+	limits := &qmModel.QuotaConfig{JobsSoft: 2, StorageSoftBytes: 1024}
+
+	warnings := quotaWarnings(&qmModel.QuotaUsage{Jobs: 1, StorageBytes: 512, Limits: limits})
+	assert.Empty(t, warnings)
+
+	warnings = quotaWarnings(&qmModel.QuotaUsage{Jobs: 2, StorageBytes: 2048, Limits: limits})
+	require.Len(t, warnings, 2)
+	assert.Contains(t, warnings[0], "2 of 2 jobs added in the last 24h0m0s")
+	assert.Contains(t, warnings[1], "of files uploaded")
+
+	warnings = quotaWarnings(&qmModel.QuotaUsage{Jobs: 100, StorageBytes: 1 << 30, Limits: &qmModel.QuotaConfig{}})
+	assert.Empty(t, warnings, "Expected no warnings without limits")
+}
+
+func TestQuotaMiddlewaresWithoutLimits(t *testing.T) {
+	m := &ManagerHandler{}
+	e := echo.New()
+	next := func(c *echo.Context) error {
+		return c.NoContent(http.StatusCreated)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/job/addJob/test-task", nil)
+	rec := httptest.NewRecorder()
+	err := m.JobQuotaMiddleware(m.StorageQuotaMiddleware(next))(e.NewContext(req, rec))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Empty(t, rec.Header().Get(qmModel.QUOTA_WARNING_HEADER))
+}
+
+func TestQuotaHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	jidb, err := database.NewJobInitiatorDBHandler(db, true)
+	require.NoError(t, err)
+	fodb, err := database.NewFileOwnerDBHandler(db, true)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.JobInitiatorDB = jidb
+	handler.FileOwnerDB = fodb
+	handler.Quotas = &qmModel.QuotaConfig{JobsSoft: 1, JobsHard: 2, StorageSoftBytes: 1024, StorageHardBytes: 4096}
+	e := echo.New()
+	next := func(c *echo.Context) error {
+		return c.NoContent(http.StatusCreated)
+	}
+
+	// Without login the principal of a request is its IP
+	addJob := func(t *testing.T) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/job/addJob/test-task", nil)
+		rec := httptest.NewRecorder()
+		err := handler.JobQuotaMiddleware(next)(e.NewContext(req, rec))
+		require.NoError(t, err)
+		return rec
+	}
+
+	uploadFile := func(t *testing.T, size int) *httptest.ResponseRecorder {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("files", "quota.txt")
+		require.NoError(t, err)
+		_, err = part.Write([]byte(strings.Repeat("x", size)))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/api/file/uploadFiles", body)
+		req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+		rec := httptest.NewRecorder()
+		err = handler.StorageQuotaMiddleware(next)(e.NewContext(req, rec))
+		require.NoError(t, err)
+		return rec
+	}
+
+	t.Run("Job quota below the soft limit", func(t *testing.T) {
+		rec := addJob(t)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Empty(t, rec.Header().Get(qmModel.QUOTA_WARNING_HEADER))
+	})
+
+	t.Run("Job quota above the soft limit", func(t *testing.T) {
+		job, err := queue.AddJob("test-task", nil, 1)
+		require.NoError(t, err)
+		handler.recordJobInitiator(job, "192.0.2.1", false)
+
+		rec := addJob(t)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Contains(t, rec.Header().Get(qmModel.QUOTA_WARNING_HEADER), "1 of 1 jobs")
+	})
+
+	t.Run("Job quota at the hard limit", func(t *testing.T) {
+		job, err := queue.AddJob("test-task", nil, 1)
+		require.NoError(t, err)
+		handler.recordJobInitiator(job, "192.0.2.1", false)
+
+		rec := addJob(t)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "3600", rec.Header().Get("Retry-After"))
+	})
+
+	t.Run("Storage quota above the soft limit", func(t *testing.T) {
+		handler.recordFileOwner("quota.txt", "192.0.2.1", 2048)
+
+		rec := uploadFile(t, 100)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Contains(t, rec.Header().Get(qmModel.QUOTA_WARNING_HEADER), "of files uploaded")
+	})
+
+	t.Run("Storage quota above the hard limit", func(t *testing.T) {
+		rec := uploadFile(t, 4096)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	})
+
+	t.Run("Storage quota after deleting the file", func(t *testing.T) {
+		handler.deleteFileOwner("quota.txt")
+
+		rec := uploadFile(t, 100)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Empty(t, rec.Header().Get(qmModel.QUOTA_WARNING_HEADER))
+	})
+
+	t.Run("GetQuotaUsage", func(t *testing.T) {
+		handler.recordFileOwner("usage.txt", "192.0.2.1", 512)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/quota/getUsage", nil)
+		rec := httptest.NewRecorder()
+		err := handler.GetQuotaUsage(e.NewContext(req, rec))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		usage := &qmModel.QuotaUsage{}
+		err = json.Unmarshal(rec.Body.Bytes(), usage)
+		require.NoError(t, err)
+		assert.Equal(t, "192.0.2.1", usage.Principal)
+		assert.Equal(t, 2, usage.Jobs)
+		assert.Equal(t, int64(512), usage.StorageBytes)
+		assert.Equal(t, 1, usage.Files)
+		assert.Len(t, usage.Warnings, 1)
+	})
+
+	t.Run("GetPrincipalQuotaUsage without principal", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/quota/getPrincipalUsage", nil)
+		rec := httptest.NewRecorder()
+		err := handler.GetPrincipalQuotaUsage(e.NewContext(req, rec))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,433 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/labstack/echo/v5"
+)
+
+// recorderCapacity is the number of requests kept by the request recorder.
+const recorderCapacity = 100
+
+// recorderMaxBodyBytes is the maximum size of a recorded request or response body.
+const recorderMaxBodyBytes = 4096
+
+// recorderExcludedPaths are never recorded, the recorder itself and the routes handling secrets.
+var recorderExcludedPaths = []string{"/static/", "/recorder", "/api/recorder", "/login", "/apiKey", "/api/apiKey", "/scim/", "/api/slack/", "/api/ci/"}
+
+// recorderSecretHeaders are the headers whose values are redacted.
+var recorderSecretHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Csrf-Token", "X-Slack-Signature", "Proxy-Authorization"}
+
+// recorderSecretFields are substrings of form, query and JSON field names whose values are redacted.
+// Key fields are matched by suffix instead, so eg. the task_key of a job stays readable.
+var recorderSecretFields = []string{"password", "secret", "token", "signature", "credential"}
+
+// recorderRedacted replaces the values of redacted headers and fields.
+const recorderRedacted = "[REDACTED]"
+
+// RequestRecorder captures sanitized request and response pairs of the selected routes into a ring buffer.
+// It is disabled by default and toggled by admins to debug HTMX interactions.
+type RequestRecorder struct {
+	mu         sync.Mutex
+	enabled    bool
+	routes     []string
+	recordings []*model.RecordedRequest
+	next       int
+	lastID     int
+}
+
+// NewRequestRecorder creates a new disabled instance of RequestRecorder keeping the last capacity requests.
+func NewRequestRecorder(capacity int) *RequestRecorder {
+	return &RequestRecorder{
+		routes:     []string{},
+		recordings: make([]*model.RecordedRequest, 0, capacity),
+	}
+}
+
+// Settings returns the current settings of the recorder.
+func (r *RequestRecorder) Settings() *model.RecorderSettings {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return &model.RecorderSettings{
+		Enabled:  r.enabled,
+		Routes:   append([]string{}, r.routes...),
+		Capacity: cap(r.recordings),
+	}
+}
+
+// Configure enables or disables the recorder and sets the recorded route prefixes.
+// Routes are trimmed and can be separated by new lines or commas, so a textarea can be used.
+func (r *RequestRecorder) Configure(enabled bool, routes []string) {
+	normalizedRoutes := splitRecorderRoutes(routes)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.enabled = enabled
+	r.routes = normalizedRoutes
+}
+
+// Recordings returns the recorded requests, newest first.
+func (r *RequestRecorder) Recordings() []*model.RecordedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recordings := make([]*model.RecordedRequest, 0, len(r.recordings))
+	for i := 1; i <= len(r.recordings); i++ {
+		recordings = append(recordings, r.recordings[(r.next-i+len(r.recordings))%len(r.recordings)])
+	}
+	return recordings
+}
+
+// Recording returns the recorded request with the id, nil if it is not in the buffer anymore.
+func (r *RequestRecorder) Recording(id int) *model.RecordedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, recording := range r.recordings {
+		if recording.ID == id {
+			return recording
+		}
+	}
+	return nil
+}
+
+// Clear removes all recorded requests.
+func (r *RequestRecorder) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.recordings = r.recordings[:0]
+	r.next = 0
+}
+
+// shouldRecord returns if the recorder is enabled and the path is one of the selected routes.
+func (r *RequestRecorder) shouldRecord(path string) bool {
+	for _, excludedPath := range recorderExcludedPaths {
+		if strings.HasPrefix(path, excludedPath) {
+			return false
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.enabled {
+		return false
+	}
+	if len(r.routes) == 0 {
+		return true
+	}
+	for _, route := range r.routes {
+		if strings.HasPrefix(path, route) {
+			return true
+		}
+	}
+	return false
+}
+
+// add adds the recording to the ring buffer, overwriting the oldest one if the buffer is full.
+func (r *RequestRecorder) add(recording *model.RecordedRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	recording.ID = r.lastID
+	if len(r.recordings) < cap(r.recordings) {
+		r.recordings = append(r.recordings, recording)
+	} else {
+		r.recordings[r.next] = recording
+	}
+	r.next = (r.next + 1) % cap(r.recordings)
+}
+
+// RecorderMiddleware records the requests of the selected routes while the recorder is enabled.
+// The request body is restored for the handler and the response is still written to the client.
+func (m *ManagerHandler) RecorderMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c *echo.Context) error {
+		if m.Recorder == nil || !m.Recorder.shouldRecord(c.Request().URL.Path) {
+			return next(c)
+		}
+
+		start := time.Now()
+		requestBody, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return err
+		}
+		c.Request().Body = io.NopCloser(bytes.NewReader(requestBody))
+
+		writer := &recorderResponseWriter{ResponseWriter: c.Response()}
+		c.SetResponse(writer)
+
+		handlerErr := next(c)
+
+		recording := &model.RecordedRequest{
+			Time:            start,
+			Method:          c.Request().Method,
+			Path:            c.Request().URL.Path,
+			Query:           sanitizeRecordedQuery(c.Request().URL.Query()),
+			HxRequest:       c.Request().Header.Get("HX-Request") != "",
+			RequestHeaders:  sanitizeRecordedHeaders(c.Request().Header),
+			RequestBody:     sanitizeRecordedBody(c.Request().Header.Get(echo.HeaderContentType), requestBody),
+			Status:          writer.status,
+			ResponseHeaders: sanitizeRecordedHeaders(writer.Header()),
+			ResponseBody:    sanitizeRecordedBody(writer.Header().Get(echo.HeaderContentType), writer.body.Bytes()),
+			DurationMs:      time.Since(start).Milliseconds(),
+		}
+		if user := model.GetRequestContext(c).User; user != nil {
+			recording.User = user.Username
+		}
+		if handlerErr != nil {
+			recording.Error = handlerErr.Error()
+		}
+		m.Recorder.add(recording)
+
+		return handlerErr
+	}
+}
+
+// =======API Handlers=======
+
+// GetRecorder returns the settings of the request recorder and the recorded requests, newest first.
+func (m *ManagerHandler) GetRecorder(c *echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]any{
+		"settings":   m.Recorder.Settings(),
+		"recordings": m.Recorder.Recordings(),
+	})
+}
+
+// GetRecording returns the recorded request with the id of the query.
+func (m *ManagerHandler) GetRecording(c *echo.Context) error {
+	recording, err := m.recordingFromQuery(c)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, recording)
+}
+
+// UpdateRecorder enables or disables the request recorder and sets the recorded routes of the JSON or form body.
+func (m *ManagerHandler) UpdateRecorder(c *echo.Context) error {
+	var settings model.RecorderSettings
+	if err := c.Bind(&settings); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+	}
+	for _, route := range splitRecorderRoutes(settings.Routes) {
+		if !strings.HasPrefix(route, "/") {
+			return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid route %q (must start with /)", route))
+		}
+	}
+
+	m.Recorder.Configure(settings.Enabled, settings.Routes)
+
+	if c.Request().Header.Get("HX-Request") != "" {
+		c.Response().Header().Add("HX-Retarget", "#recorder_settings")
+		c.Response().Header().Add("HX-Reswap", "outerHTML")
+		return render(c, screens.RecorderSettings(m.Recorder.Settings()))
+	}
+	return c.JSON(http.StatusOK, m.Recorder.Settings())
+}
+
+// ClearRecorder removes all recorded requests.
+func (m *ManagerHandler) ClearRecorder(c *echo.Context) error {
+	m.Recorder.Clear()
+
+	if c.Request().Header.Get("HX-Request") != "" {
+		return renderRowsWithPopup(c, http.StatusOK, screens.RecordedRequestRows(nil), "#table_body_recorder_table", "innerHTML", "closeClearRecorder", "Recorded requests cleared")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// =======View Handlers=======
+
+// RecorderView renders the request recorder view
+func (m *ManagerHandler) RecorderView(c *echo.Context) error {
+	c.Response().Header().Add("HX-Push-Url", "/recorder")
+	c.Response().Header().Add("HX-Retarget", "#body")
+
+	return render(c, screens.Recorder(m.Recorder.Settings(), m.Recorder.Recordings()))
+}
+
+// RecordingPopupView renders the popup with the request and response of a recorded request
+func (m *ManagerHandler) RecordingPopupView(c *echo.Context) error {
+	recording, err := m.recordingFromQuery(c)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, err.Error())
+	}
+
+	return renderPopup(c, screens.RecordingPopup(recording))
+}
+
+// ClearRecorderPopupView renders the clear recorded requests popup
+func (m *ManagerHandler) ClearRecorderPopupView(c *echo.Context) error {
+	return renderPopup(c, screens.ClearRecorderPopup())
+}
+
+// =======Helpers=======
+
+// recordingFromQuery returns the recorded request with the id of the query.
+func (m *ManagerHandler) recordingFromQuery(c *echo.Context) (*model.RecordedRequest, error) {
+	id, err := strconv.Atoi(c.QueryParam("id"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid recording id")
+	}
+
+	recording := m.Recorder.Recording(id)
+	if recording == nil {
+		return nil, fmt.Errorf("recording %d not found", id)
+	}
+	return recording, nil
+}
+
+// recorderResponseWriter captures the status and the beginning of the body while writing the response.
+type recorderResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *recorderResponseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *recorderResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if remaining := recorderMaxBodyBytes + 1 - w.body.Len(); remaining > 0 {
+		w.body.Write(b[:min(len(b), remaining)])
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recorderResponseWriter) Flush() {
+	err := http.NewResponseController(w.ResponseWriter).Flush()
+	if err != nil && errors.Is(err, http.ErrNotSupported) {
+		panic(errors.New("response writer flushing is not supported"))
+	}
+}
+
+func (w *recorderResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *recorderResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// splitRecorderRoutes splits the routes at new lines and commas and drops empty routes.
+func splitRecorderRoutes(routes []string) []string {
+	splitRoutes := []string{}
+	for _, route := range routes {
+		for _, prefix := range strings.FieldsFunc(route, func(c rune) bool { return c == '\n' || c == '\r' || c == ',' }) {
+			if prefix = strings.TrimSpace(prefix); prefix != "" {
+				splitRoutes = append(splitRoutes, prefix)
+			}
+		}
+	}
+	return splitRoutes
+}
+
+// isRecorderSecretField returns if the value of the field name has to be redacted.
+func isRecorderSecretField(name string) bool {
+	name = strings.ToLower(name)
+	for _, field := range recorderSecretFields {
+		if strings.Contains(name, field) {
+			return true
+		}
+	}
+	return name == "key" || strings.HasSuffix(name, "api_key") || strings.HasSuffix(name, "apikey")
+}
+
+// sanitizeRecordedHeaders joins the header values and redacts the secret headers.
+func sanitizeRecordedHeaders(header http.Header) map[string]string {
+	headers := map[string]string{}
+	for name, values := range header {
+		headers[name] = strings.Join(values, ", ")
+	}
+	for _, name := range recorderSecretHeaders {
+		if _, ok := headers[http.CanonicalHeaderKey(name)]; ok {
+			headers[http.CanonicalHeaderKey(name)] = recorderRedacted
+		}
+	}
+	return headers
+}
+
+// sanitizeRecordedQuery redacts the secret fields of the query.
+func sanitizeRecordedQuery(query url.Values) string {
+	for name := range query {
+		if isRecorderSecretField(name) {
+			query[name] = []string{recorderRedacted}
+		}
+	}
+	return query.Encode()
+}
+
+// sanitizeRecordedBody redacts the secret fields of JSON and form bodies and truncates the body.
+// Multipart bodies are not recorded, as they contain uploaded files.
+func sanitizeRecordedBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == echo.MIMEApplicationJSON:
+		var value any
+		if err := json.Unmarshal(body, &value); err == nil {
+			if sanitized, err := json.Marshal(sanitizeRecordedJSON(value)); err == nil {
+				body = sanitized
+			}
+		}
+	case mediaType == echo.MIMEApplicationForm:
+		if form, err := url.ParseQuery(string(body)); err == nil {
+			body = []byte(sanitizeRecordedQuery(form))
+		}
+	case strings.HasPrefix(mediaType, "multipart/"):
+		return fmt.Sprintf("[multipart body of %d bytes]", len(body))
+	}
+
+	if len(body) > recorderMaxBodyBytes {
+		return string(body[:recorderMaxBodyBytes]) + "...[truncated]"
+	}
+	return string(body)
+}
+
+// sanitizeRecordedJSON redacts the secret fields of the JSON value recursively.
+func sanitizeRecordedJSON(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for name, fieldValue := range v {
+			if isRecorderSecretField(name) {
+				v[name] = recorderRedacted
+			} else {
+				v[name] = sanitizeRecordedJSON(fieldValue)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = sanitizeRecordedJSON(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestRecorder(t *testing.T) {
+	t.Run("Should not record while disabled", func(t *testing.T) {
+		recorder := NewRequestRecorder(3)
+
+		assert.False(t, recorder.shouldRecord("/api/job/addJob"))
+	})
+
+	t.Run("Should record only the selected routes", func(t *testing.T) {
+		recorder := NewRequestRecorder(3)
+		recorder.Configure(true, []string{"/api/job\n /jobs ,"})
+
+		assert.Equal(t, []string{"/api/job", "/jobs"}, recorder.Settings().Routes)
+		assert.True(t, recorder.shouldRecord("/api/job/addJob"))
+		assert.True(t, recorder.shouldRecord("/jobs"))
+		assert.False(t, recorder.shouldRecord("/api/task/getTasks"))
+	})
+
+	t.Run("Should never record excluded routes", func(t *testing.T) {
+		recorder := NewRequestRecorder(3)
+		recorder.Configure(true, nil)
+
+		assert.True(t, recorder.shouldRecord("/api/task/getTasks"))
+		assert.False(t, recorder.shouldRecord("/api/recorder/getRecorder"))
+		assert.False(t, recorder.shouldRecord("/api/apiKey/createApiKey"))
+		assert.False(t, recorder.shouldRecord("/login"))
+	})
+
+	t.Run("Should keep the newest recordings in the ring buffer", func(t *testing.T) {
+		recorder := NewRequestRecorder(3)
+		for i := 0; i < 5; i++ {
+			recorder.add(&model.RecordedRequest{Path: fmt.Sprintf("/path/%d", i)})
+		}
+
+		recordings := recorder.Recordings()
+		require.Len(t, recordings, 3)
+		assert.Equal(t, "/path/4", recordings[0].Path)
+		assert.Equal(t, "/path/2", recordings[2].Path)
+		assert.Nil(t, recorder.Recording(1), "Expected the oldest recording to be overwritten")
+		assert.NotNil(t, recorder.Recording(5))
+
+		recorder.Clear()
+		assert.Empty(t, recorder.Recordings())
+	})
+}
+
+func TestSanitizeRecordedBody(t *testing.T) {
+	t.Run("Should redact secret JSON fields", func(t *testing.T) {
+		body := sanitizeRecordedBody(echo.MIMEApplicationJSON, []byte(`{"task_key":"task","parameters":{"password":"secret"},"key":"qm_abc"}`))
+
+		assert.Contains(t, body, `"task_key":"task"`)
+		assert.Contains(t, body, `"password":"[REDACTED]"`)
+		assert.Contains(t, body, `"key":"[REDACTED]"`)
+		assert.NotContains(t, body, "qm_abc")
+	})
+
+	t.Run("Should redact secret form fields", func(t *testing.T) {
+		body := sanitizeRecordedBody(echo.MIMEApplicationForm, []byte("name=test&client_secret=abc"))
+
+		assert.Contains(t, body, "name=test")
+		assert.NotContains(t, body, "abc")
+	})
+
+	t.Run("Should truncate long bodies", func(t *testing.T) {
+		body := sanitizeRecordedBody(echo.MIMETextPlain, []byte(strings.Repeat("a", recorderMaxBodyBytes+10)))
+
+		assert.True(t, strings.HasSuffix(body, "...[truncated]"))
+		assert.Len(t, body, recorderMaxBodyBytes+len("...[truncated]"))
+	})
+}
+
+func TestRecorderMiddleware(t *testing.T) {
+	m := &ManagerHandler{Recorder: NewRequestRecorder(10)}
+	m.Recorder.Configure(true, []string{"/api/job"})
+
+	handler := m.RecorderMiddleware(func(c *echo.Context) error {
+		var body map[string]any
+		if err := json.NewDecoder(c.Request().Body).Decode(&body); err != nil {
+			return err
+		}
+		c.Response().Header().Set("HX-Trigger", "jobAdded")
+		return c.JSON(http.StatusCreated, body)
+	})
+
+	t.Run("Should record the sanitized request and response", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/api/job/addJob?token=abc", strings.NewReader(`{"task_key":"task","token":"abc"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("Authorization", "Bearer qm_abc")
+		req.Header.Set("HX-Request", "true")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"token":"abc"`, "Expected the client to get the unchanged response")
+
+		recordings := m.Recorder.Recordings()
+		require.Len(t, recordings, 1)
+		recording := recordings[0]
+		assert.Equal(t, http.MethodPost, recording.Method)
+		assert.Equal(t, "/api/job/addJob", recording.Path)
+		assert.True(t, recording.HxRequest)
+		assert.Equal(t, http.StatusCreated, recording.Status)
+		assert.Equal(t, recorderRedacted, recording.RequestHeaders["Authorization"])
+		assert.Equal(t, "jobAdded", recording.ResponseHeaders["Hx-Trigger"])
+		assert.NotContains(t, recording.Query, "abc")
+		assert.NotContains(t, recording.RequestBody, "abc")
+		assert.NotContains(t, recording.ResponseBody, "abc")
+		assert.Contains(t, recording.ResponseBody, `"task_key":"task"`)
+	})
+
+	t.Run("Should not record other routes", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/api/task/addTask", strings.NewReader(`{}`))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler(c)
+		require.NoError(t, err)
+		assert.Len(t, m.Recorder.Recordings(), 1)
+	})
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/siherrmann/queuerManager/helper"
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/labstack/echo/v5"
+)
+
+// WorkerScaler forwards scale requests of operators to an external orchestrator.
+// Extensions can set their own implementation on the manager handler, eg. to publish a queue message.
+type WorkerScaler interface {
+	RequestScale(ctx context.Context, request *model.ScaleRequest) error
+}
+
+// WebhookScaler posts scale requests as JSON to a webhook.
+// If a secret is set, the body is signed with HMAC-SHA256 in the X-Queuer-Signature header.
+type WebhookScaler struct {
+	URL    string
+	Secret string
+	Client *http.Client
+}
+
+// NewWebhookScalerFromEnv creates a webhook scaler from QUEUER_MANAGER_SCALE_WEBHOOK_URL
+// and QUEUER_MANAGER_SCALE_WEBHOOK_SECRET. It returns nil if no webhook URL is set.
+func NewWebhookScalerFromEnv() *WebhookScaler {
+	url := os.Getenv("QUEUER_MANAGER_SCALE_WEBHOOK_URL")
+	if url == "" {
+		return nil
+	}
+	return &WebhookScaler{
+		URL:    url,
+		Secret: os.Getenv("QUEUER_MANAGER_SCALE_WEBHOOK_SECRET"),
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// RequestScale sends the scale request to the webhook and expects a 2xx response
+func (s *WebhookScaler) RequestScale(ctx context.Context, request *model.ScaleRequest) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("error encoding scale request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(s.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Queuer-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending webhook request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// =======API Handlers=======
+
+// ScaleWorkers forwards a request for a desired worker count of a pool to the external orchestrator
+func (m *ManagerHandler) ScaleWorkers(c *echo.Context) error {
+	if m.WorkerScaler == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Worker scaling is not configured")
+	}
+
+	pool := c.FormValue("pool")
+	desiredCount, err := strconv.Atoi(c.FormValue("desired_count"))
+	if err != nil || desiredCount < 1 || desiredCount > 1000 {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid desired_count (must be 1-1000)")
+	}
+
+	currentWorkers, err := m.countPoolWorkers(pool)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to retrieve workers: %v", err))
+	}
+
+	scaleRequest := &model.ScaleRequest{
+		Pool:           pool,
+		DesiredCount:   desiredCount,
+		CurrentWorkers: currentWorkers,
+		RequestedBy:    c.RealIP(),
+		RequestedAt:    time.Now(),
+	}
+	err = m.WorkerScaler.RequestScale(c.Request().Context(), scaleRequest)
+	m.auditScaleRequest(scaleRequest, err)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadGateway, fmt.Sprintf("Failed to request scaling: %v", err))
+	}
+	m.publishEvent(model.EVENT_WORKER_SCALE_REQUESTED, scaleRequest)
+
+	return renderPopupOrJson(c, http.StatusOK, fmt.Sprintf("Requested %d worker(s) for pool %s (currently %d)", desiredCount, poolName(pool), currentWorkers))
+}
+
+// GetScaleAudits retrieves a paginated list of the scale audit trail, newest first
+func (m *ManagerHandler) GetScaleAudits(c *echo.Context) error {
+	if m.ScaleAuditDB == nil {
+		return c.String(http.StatusServiceUnavailable, "Scale audit is not available")
+	}
+
+	lastId, limit, err := m.apiPagination(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	audits, err := m.ScaleAuditDB.SelectAllScaleAudits(lastId, limit)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to retrieve scale audits")
+	}
+
+	return c.JSON(http.StatusOK, audits)
+}
+
+// =======View Handlers=======
+
+// ScaleWorkersPopupView renders the scale workers popup
+func (m *ManagerHandler) ScaleWorkersPopupView(c *echo.Context) error {
+	if m.WorkerScaler == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Worker scaling is not configured")
+	}
+
+	pool := c.QueryParam("pool")
+	currentWorkers, err := m.countPoolWorkers(pool)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to retrieve workers: %v", err))
+	}
+
+	return renderPopup(c, screens.ScaleWorkersPopup(pool, currentWorkers))
+}
+
+// countPoolWorkers counts the connected (ready or running) workers of a pool, all pools if pool is empty.
+func (m *ManagerHandler) countPoolWorkers(pool string) (int, error) {
+	workers, err := m.Queuer.GetWorkers(0, 1000)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, worker := range workers {
+		if worker.Status != "READY" && worker.Status != "RUNNING" {
+			continue
+		}
+		if pool == "" || helper.WorkerPool(worker.Name) == pool {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// auditScaleRequest records a scale request with its result in the audit trail.
+// Audit errors are only logged so they never block scaling.
+func (m *ManagerHandler) auditScaleRequest(request *model.ScaleRequest, scaleErr error) {
+	if m.ScaleAuditDB == nil {
+		return
+	}
+
+	audit := &model.ScaleAudit{
+		Pool:           request.Pool,
+		Scaler:         scalerName(m.WorkerScaler),
+		DesiredCount:   request.DesiredCount,
+		CurrentWorkers: request.CurrentWorkers,
+		RequestedBy:    request.RequestedBy,
+		Status:         "requested",
+	}
+	if scaleErr != nil {
+		audit.Status = "failed"
+		audit.Error = scaleErr.Error()
+	}
+
+	_, err := m.ScaleAuditDB.InsertScaleAudit(audit)
+	if err != nil {
+		log.Printf("Error recording scale audit: %v", err)
+	}
+}
+
+func scalerName(scaler WorkerScaler) string {
+	switch scaler.(type) {
+	case *KubernetesScaler:
+		return "kubernetes"
+	case *WebhookScaler:
+		return "webhook"
+	default:
+		return "custom"
+	}
+}
+
+func poolName(pool string) string {
+	if pool == "" {
+		return "all"
+	}
+	return pool
+}
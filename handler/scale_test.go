@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaleWorkersHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	sadb, err := database.NewScaleAuditDBHandler(db, true)
+	require.NoError(t, err)
+
+	var receivedRequest qmModel.ScaleRequest
+	var receivedSignature string
+	var expectedSignature string
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		mac := hmac.New(sha256.New, []byte("test-secret"))
+		mac.Write(body)
+		expectedSignature = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		receivedSignature = r.Header.Get("X-Queuer-Signature")
+
+		err = json.Unmarshal(body, &receivedRequest)
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer webhook.Close()
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.WorkerScaler = &WebhookScaler{URL: webhook.URL, Secret: "test-secret", Client: webhook.Client()}
+	handler.ScaleAuditDB = sadb
+	e := echo.New()
+
+	t.Run("ScaleWorkers with valid data", func(t *testing.T) {
+		formData := strings.NewReader("pool=&desired_count=3")
+
+		req := httptest.NewRequest(http.MethodPost, "/api/worker/scaleWorkers", formData)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.ScaleWorkers(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		currentWorkers, err := handler.countPoolWorkers("")
+		require.NoError(t, err)
+		assert.Equal(t, 3, receivedRequest.DesiredCount)
+		assert.Equal(t, "", receivedRequest.Pool)
+		assert.Equal(t, currentWorkers, receivedRequest.CurrentWorkers)
+		assert.False(t, receivedRequest.RequestedAt.IsZero())
+		assert.Equal(t, expectedSignature, receivedSignature)
+	})
+
+	t.Run("ScaleWorkers with invalid desired count", func(t *testing.T) {
+		formData := strings.NewReader("pool=&desired_count=0")
+
+		req := httptest.NewRequest(http.MethodPost, "/api/worker/scaleWorkers", formData)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.ScaleWorkers(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("ScaleWorkers with failing webhook", func(t *testing.T) {
+		failingWebhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer failingWebhook.Close()
+
+		handlerWithFailingWebhook := NewManagerHandler(fs, tdb, queue)
+		handlerWithFailingWebhook.WorkerScaler = &WebhookScaler{URL: failingWebhook.URL, Client: failingWebhook.Client()}
+
+		formData := strings.NewReader("pool=&desired_count=2")
+
+		req := httptest.NewRequest(http.MethodPost, "/api/worker/scaleWorkers", formData)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handlerWithFailingWebhook.ScaleWorkers(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadGateway, rec.Code)
+	})
+
+	t.Run("GetScaleAudits returns the audit trail", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/worker/getScaleAudits", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetScaleAudits(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var audits []*qmModel.ScaleAudit
+		err = json.Unmarshal(rec.Body.Bytes(), &audits)
+		require.NoError(t, err)
+		require.Len(t, audits, 1)
+		assert.Equal(t, "webhook", audits[0].Scaler)
+		assert.Equal(t, 3, audits[0].DesiredCount)
+		assert.Equal(t, "requested", audits[0].Status)
+	})
+
+	t.Run("ScaleWorkers without scaler", func(t *testing.T) {
+		handlerWithoutScaler := NewManagerHandler(fs, tdb, queue)
+
+		formData := strings.NewReader("pool=&desired_count=2")
+
+		req := httptest.NewRequest(http.MethodPost, "/api/worker/scaleWorkers", formData)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handlerWithoutScaler.ScaleWorkers(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+}
@@ -0,0 +1,485 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/siherrmann/queuerManager/helper"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+)
+
+// =======API Handlers=======
+
+// GetSchedules retrieves all schedules.
+func (m *ManagerHandler) GetSchedules(c *echo.Context) error {
+	if m.ScheduleDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Schedules are not enabled")
+	}
+
+	schedules, err := m.ScheduleDB.SelectAllSchedules()
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get schedules: %v", err))
+	}
+
+	return renderPopupOrJson(c, http.StatusOK, schedules)
+}
+
+// GetSchedule retrieves a schedule by RID.
+func (m *ManagerHandler) GetSchedule(c *echo.Context) error {
+	if m.ScheduleDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Schedules are not enabled")
+	}
+
+	rid, err := uuid.Parse(c.Param("rid"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid schedule RID format")
+	}
+
+	schedule, err := m.ScheduleDB.SelectSchedule(rid)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "Schedule not found")
+	}
+
+	return renderPopupOrJson(c, http.StatusOK, schedule)
+}
+
+// AddSchedule adds a schedule with the name, task, cron expression and job parameters of the JSON or form body.
+// The parameters are validated like the parameters of an added job of the task.
+func (m *ManagerHandler) AddSchedule(c *echo.Context) error {
+	if m.ScheduleDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Schedules are not enabled")
+	}
+
+	var scheduleRequest qmModel.ScheduleRequest
+	if err := c.Bind(&scheduleRequest); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+	}
+
+	schedule, err := m.scheduleFromRequest(c.Request().Context(), &scheduleRequest)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+	schedule.CreatedBy = requestedBy(c)
+
+	insertedSchedule, err := m.ScheduleDB.InsertSchedule(schedule)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to add schedule: %v", err))
+	}
+
+	if c.Request().Header.Get("HX-Request") != "" {
+		if currentPath(c) == "/schedules" {
+			return renderRowsWithPopup(c, http.StatusCreated, screens.ScheduleRow(insertedSchedule), "#table_body_schedules_table", "afterbegin", "closeAddSchedule", "Schedule added successfully")
+		}
+		c.Response().Header().Add("HX-Redirect", "/schedules")
+		return renderPopupOrJson(c, http.StatusCreated, "Schedule added successfully")
+	}
+	return c.JSON(http.StatusCreated, insertedSchedule)
+}
+
+// UpdateSchedule updates the schedule with the RID of the query with the JSON or form body.
+// The next run is calculated from the cron expression again.
+func (m *ManagerHandler) UpdateSchedule(c *echo.Context) error {
+	if m.ScheduleDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Schedules are not enabled")
+	}
+
+	rid, err := uuid.Parse(c.QueryParam("rid"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid schedule RID format")
+	}
+
+	var scheduleRequest qmModel.ScheduleRequest
+	if err := c.Bind(&scheduleRequest); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+	}
+
+	schedule, err := m.scheduleFromRequest(c.Request().Context(), &scheduleRequest)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+	schedule.RID = rid
+
+	updatedSchedule, err := m.ScheduleDB.UpdateSchedule(schedule)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, fmt.Sprintf("Failed to update schedule: %v", err))
+	}
+
+	if c.Request().Header.Get("HX-Request") != "" {
+		return renderRowsWithPopup(c, http.StatusOK, screens.ScheduleRow(updatedSchedule), "#table_row_"+rid.String(), "outerHTML", "closeUpdateSchedule", "Schedule updated successfully")
+	}
+	return c.JSON(http.StatusOK, updatedSchedule)
+}
+
+// UpdateSchedulesEnabled enables or disables the schedules with the RIDs of the query, eg. `?enabled=true&rid=a&rid=b`.
+// Enabled schedules run next at the next time of their cron expression, runs missed while disabled are skipped.
+func (m *ManagerHandler) UpdateSchedulesEnabled(c *echo.Context) error {
+	if m.ScheduleDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Schedules are not enabled")
+	}
+
+	ridStrings := c.QueryParams()["rid"]
+	if len(ridStrings) == 0 {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Missing schedule RID")
+	}
+	enabled, err := strconv.ParseBool(c.QueryParam("enabled"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid enabled value (must be true or false)")
+	}
+
+	updatedCount := 0
+	var errors []string
+	for _, ridString := range ridStrings {
+		rid, err := uuid.Parse(ridString)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Invalid schedule RID %s", ridString))
+			continue
+		}
+
+		schedule, err := m.ScheduleDB.SelectSchedule(rid)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Schedule %s not found", rid))
+			continue
+		}
+
+		var nextRunAt *time.Time
+		if enabled {
+			nextRunAt, err = nextScheduleRun(schedule.CronExpression, time.Now())
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("Failed to enable schedule %s: %v", schedule.Name, err))
+				continue
+			}
+		}
+
+		_, err = m.ScheduleDB.UpdateScheduleEnabled(rid, enabled, nextRunAt)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Failed to update schedule %s: %v", schedule.Name, err))
+			continue
+		}
+		updatedCount++
+	}
+
+	status := http.StatusOK
+	message := fmt.Sprintf("Successfully updated %d schedule(s)", updatedCount)
+	if len(errors) > 0 {
+		status = http.StatusPartialContent
+		message = fmt.Sprintf("Updated %d schedules. Errors: %v", updatedCount, errors)
+	}
+
+	if c.Request().Header.Get("HX-Request") != "" {
+		return m.renderSchedulesWithPopup(c, status, "closeUpdateScheduleEnabled", message)
+	}
+
+	return renderPopupOrJson(c, status, message)
+}
+
+// DeleteSchedules deletes the schedules with the RIDs of the query, jobs already added by them are kept.
+func (m *ManagerHandler) DeleteSchedules(c *echo.Context) error {
+	if m.ScheduleDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Schedules are not enabled")
+	}
+
+	ridStrings := c.QueryParams()["rid"]
+	if len(ridStrings) == 0 {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Missing schedule RID")
+	}
+
+	deletedCount := 0
+	var errors []string
+	for _, ridString := range ridStrings {
+		rid, err := uuid.Parse(ridString)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Invalid schedule RID %s", ridString))
+			continue
+		}
+
+		err = m.ScheduleDB.DeleteSchedule(rid)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Failed to delete schedule %s: %v", rid, err))
+			continue
+		}
+		deletedCount++
+	}
+
+	status := http.StatusOK
+	message := fmt.Sprintf("Successfully deleted %d schedule(s)", deletedCount)
+	if len(errors) > 0 {
+		status = http.StatusPartialContent
+		message = fmt.Sprintf("Deleted %d schedules. Errors: %v", deletedCount, errors)
+	}
+
+	if c.Request().Header.Get("HX-Request") != "" {
+		return m.renderSchedulesWithPopup(c, status, "closeDeleteSchedule", message)
+	}
+
+	return renderPopupOrJson(c, status, message)
+}
+
+// =======View Handlers=======
+
+// SchedulesView renders the schedule list view
+func (m *ManagerHandler) SchedulesView(c *echo.Context) error {
+	if m.ScheduleDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Schedules are not enabled")
+	}
+
+	schedules, err := m.ScheduleDB.SelectAllSchedules()
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get schedules: %v", err))
+	}
+
+	c.Response().Header().Add("HX-Push-Url", "/schedules")
+	c.Response().Header().Add("HX-Retarget", "#body")
+
+	return render(c, screens.Schedules(schedules))
+}
+
+// AddSchedulePopupView renders the popup to add a schedule
+func (m *ManagerHandler) AddSchedulePopupView(c *echo.Context) error {
+	tasks, err := m.taskDB.SelectAllTasks(0, 1000)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get tasks: %v", err))
+	}
+
+	return renderPopup(c, screens.AddSchedulePopup(tasks))
+}
+
+// UpdateSchedulePopupView renders the popup to update the schedule with the RID of the query
+func (m *ManagerHandler) UpdateSchedulePopupView(c *echo.Context) error {
+	if m.ScheduleDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Schedules are not enabled")
+	}
+
+	rid, err := uuid.Parse(c.QueryParam("rid"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid schedule RID format")
+	}
+
+	schedule, err := m.ScheduleDB.SelectSchedule(rid)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "Schedule not found")
+	}
+
+	tasks, err := m.taskDB.SelectAllTasks(0, 1000)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get tasks: %v", err))
+	}
+
+	return renderPopup(c, screens.UpdateSchedulePopup(schedule, tasks))
+}
+
+// UpdateScheduleEnabledPopupView renders the popup to enable or disable schedules
+func (m *ManagerHandler) UpdateScheduleEnabledPopupView(c *echo.Context) error {
+	rids := c.QueryParams()["rid"]
+	if len(rids) == 0 {
+		return renderPopupOrJson(c, http.StatusBadRequest, "No schedule RIDs provided")
+	}
+	enabled, err := strconv.ParseBool(c.QueryParam("enabled"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid enabled value (must be true or false)")
+	}
+
+	return renderPopup(c, screens.UpdateScheduleEnabledPopup(rids, enabled))
+}
+
+// DeleteSchedulePopupView renders the popup to delete schedules
+func (m *ManagerHandler) DeleteSchedulePopupView(c *echo.Context) error {
+	rids := c.QueryParams()["rid"]
+	if len(rids) == 0 {
+		return renderPopupOrJson(c, http.StatusBadRequest, "No schedule RIDs provided")
+	}
+
+	return renderPopup(c, screens.DeleteSchedulePopup(rids))
+}
+
+// =======Helpers=======
+
+// RunDueSchedules adds a job for every enabled schedule that is due at now and returns the number of added jobs.
+// Every schedule is claimed before its job is added, so with several manager instances the job is only added once.
+// Runs missed while the manager was down are run once, the next run is the next time of the cron expression after now.
+// Late runs and runs that failed to add their job are alerted as missed if the task opted in.
+func (m *ManagerHandler) RunDueSchedules(ctx context.Context, now time.Time) (int, error) {
+	if m.ScheduleDB == nil {
+		return 0, nil
+	}
+
+	schedules, err := m.ScheduleDB.SelectDueSchedules(now)
+	if err != nil {
+		return 0, fmt.Errorf("error selecting due schedules: %w", err)
+	}
+
+	added := 0
+	var errs []string
+	for _, schedule := range schedules {
+		// A cron expression without next run disables the schedule until it is updated
+		nextRunAt, nextRunErr := nextScheduleRun(schedule.CronExpression, now)
+
+		claimed, err := m.ScheduleDB.ClaimScheduleRun(schedule, nextRunAt)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("schedule %s: %v", schedule.Name, err))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		// Runs much later than planned were missed, eg. while the manager was down
+		if schedule.NextRunAt != nil && now.Sub(*schedule.NextRunAt) > scheduleMissTolerance {
+			m.alertScheduleMissed(schedule, fmt.Sprintf("missed its run at %s and runs late", schedule.NextRunAt.Format(time.RFC3339)))
+		}
+
+		var jobRID *uuid.UUID
+		lastError := ""
+		jobAdded, err := m.runSchedule(ctx, schedule)
+		if err != nil {
+			lastError = err.Error()
+			errs = append(errs, fmt.Sprintf("schedule %s: %v", schedule.Name, err))
+			m.alertScheduleMissed(schedule, fmt.Sprintf("failed to add its job: %v", err))
+		} else {
+			jobRID = &jobAdded.RID
+			added++
+		}
+		if nextRunErr != nil {
+			lastError = strings.TrimSpace(lastError + " " + nextRunErr.Error())
+		}
+
+		err = m.ScheduleDB.UpdateScheduleRunResult(schedule.RID, jobRID, lastError)
+		if err != nil {
+			log.Printf("Error recording run of schedule %s: %v", schedule.Name, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return added, fmt.Errorf("error running schedules: %s", strings.Join(errs, "; "))
+	}
+	return added, nil
+}
+
+// runSchedule adds a job of the task of the schedule with its parameters,
+// the parameters are validated like the parameters of an added job.
+func (m *ManagerHandler) runSchedule(ctx context.Context, schedule *qmModel.Schedule) (*model.Job, error) {
+	task, err := m.taskDB.SelectTaskByKey(schedule.TaskKey)
+	if err != nil {
+		return nil, fmt.Errorf("task %s not found", schedule.TaskKey)
+	}
+
+	initiator := qmModel.SCHEDULE_INITIATOR_PREFIX + schedule.Name
+	parametersList, parametersKeyed, err := m.resolveScheduleParameters(ctx, task, schedule.Parameters, initiator)
+	if err != nil {
+		return nil, fmt.Errorf("validation error: %v", err)
+	}
+
+	versionWarning, block, err := m.checkWorkerVersion(task)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check worker version: %v", err)
+	}
+	if block {
+		return nil, fmt.Errorf("%s", versionWarning)
+	}
+
+	jobAdded, deduplicated, err := m.addTaskJob(task, parametersList, parametersKeyed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add job: %v", err)
+	}
+	if !deduplicated {
+		m.recordJobInitiator(jobAdded, initiator, false)
+		m.registerJobChains(task, jobAdded, false)
+		m.publishEvent(qmModel.EVENT_JOB_ADDED, jobAdded)
+	}
+
+	return jobAdded, nil
+}
+
+// resolveScheduleParameters validates the parameters of a schedule like the JSON body of an added job.
+func (m *ManagerHandler) resolveScheduleParameters(ctx context.Context, task *qmModel.Task, parameters map[string]any, submittedBy string) ([]any, map[string]any, error) {
+	if parameters == nil {
+		parameters = map[string]any{}
+	}
+	parametersJSON, err := json.Marshal(parameters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	jobRequest, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader(parametersJSON))
+	if err != nil {
+		return nil, nil, err
+	}
+	jobRequest.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	return m.resolveJobParameters(ctx, task, jobRequest, submittedBy)
+}
+
+// scheduleFromRequest validates a schedule request and returns the schedule with its next run if it is enabled.
+func (m *ManagerHandler) scheduleFromRequest(ctx context.Context, scheduleRequest *qmModel.ScheduleRequest) (*qmModel.Schedule, error) {
+	scheduleRequest.Name = strings.TrimSpace(scheduleRequest.Name)
+	if scheduleRequest.Name == "" || len(scheduleRequest.Name) > 100 {
+		return nil, fmt.Errorf("name is required (max 100 characters)")
+	}
+
+	err := helper.ValidateCron(scheduleRequest.CronExpression)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := m.taskDB.SelectTaskByKey(scheduleRequest.TaskKey)
+	if err != nil {
+		return nil, fmt.Errorf("task %q not found", scheduleRequest.TaskKey)
+	}
+
+	parameters := map[string]any{}
+	if strings.TrimSpace(scheduleRequest.Parameters) != "" {
+		err := json.Unmarshal([]byte(scheduleRequest.Parameters), &parameters)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parameters (must be a JSON object): %v", err)
+		}
+	}
+	_, _, err = m.resolveScheduleParameters(ctx, task, parameters, qmModel.SCHEDULE_INITIATOR_PREFIX+scheduleRequest.Name)
+	if err != nil {
+		return nil, fmt.Errorf("validation error: %v", err)
+	}
+
+	schedule := &qmModel.Schedule{
+		Name:           scheduleRequest.Name,
+		TaskKey:        task.Key,
+		CronExpression: strings.Join(strings.Fields(scheduleRequest.CronExpression), " "),
+		Parameters:     parameters,
+		Enabled:        scheduleRequest.Enabled,
+	}
+	if schedule.Enabled {
+		schedule.NextRunAt, err = nextScheduleRun(schedule.CronExpression, time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return schedule, nil
+}
+
+// nextScheduleRun returns the next run of the cron expression after the given time.
+func nextScheduleRun(cronExpression string, after time.Time) (*time.Time, error) {
+	nextRunAt, err := helper.NextCronRun(cronExpression, after)
+	if err != nil {
+		return nil, err
+	}
+	return &nextRunAt, nil
+}
+
+// renderSchedulesWithPopup swaps all rows of the schedules table and shows the message as popup.
+func (m *ManagerHandler) renderSchedulesWithPopup(c *echo.Context, status int, closeEvent string, message string) error {
+	schedules, err := m.ScheduleDB.SelectAllSchedules()
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get schedules: %v", err))
+	}
+	return renderRowsWithPopup(c, status, screens.ScheduleRows(schedules), "#table_body_schedules_table", "innerHTML", closeEvent, message)
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	vm "github.com/siherrmann/validator/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextScheduleRun(t *testing.T) {
+	after := time.Date(2026, time.March, 13, 10, 17, 30, 0, time.UTC) // Friday
+
+	tests := []struct {
+		name           string
+		cronExpression string
+		expected       time.Time
+	}{
+		{"Every minute", "* * * * *", time.Date(2026, time.March, 13, 10, 18, 0, 0, time.UTC)},
+		{"Every 15 minutes", "*/15 * * * *", time.Date(2026, time.March, 13, 10, 30, 0, 0, time.UTC)},
+		{"Daily at 2am", "0 2 * * *", time.Date(2026, time.March, 14, 2, 0, 0, 0, time.UTC)},
+		{"Weekdays at 9am", "0 9 * * 1-5", time.Date(2026, time.March, 16, 9, 0, 0, 0, time.UTC)},
+		{"Sundays as 7", "30 8 * * 7", time.Date(2026, time.March, 15, 8, 30, 0, 0, time.UTC)},
+		{"First of month", "0 0 1 * *", time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"Day of month or day of week", "0 0 20 * 6", time.Date(2026, time.March, 14, 0, 0, 0, 0, time.UTC)},
+		{"Leap day", "0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nextRunAt, err := nextScheduleRun(test.cronExpression, after)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, *nextRunAt)
+		})
+	}
+
+	t.Run("Invalid or never running expressions", func(t *testing.T) {
+		_, err := nextScheduleRun("0 2 * *", after)
+		assert.Error(t, err)
+		_, err = nextScheduleRun("0 0 31 2 *", after)
+		assert.Error(t, err)
+	})
+}
+
+func TestScheduleHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	sdb, err := database.NewScheduleDBHandler(db, true)
+	require.NoError(t, err)
+	jidb, err := database.NewJobInitiatorDBHandler(db, true)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.ScheduleDB = sdb
+	handler.JobInitiatorDB = jidb
+	e := echo.New()
+
+	task, err := tdb.InsertTask(&qmModel.Task{
+		Key:                  "test-schedule-task",
+		Name:                 "Test Schedule Task",
+		InputParameters:      []vm.Validation{{Key: "count", Type: vm.Int, Requirement: "min1"}},
+		InputParametersKeyed: []vm.Validation{},
+	})
+	require.NoError(t, err)
+
+	addSchedule := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/schedule/addSchedule", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		require.NoError(t, handler.AddSchedule(c))
+		return rec
+	}
+
+	var schedule qmModel.Schedule
+	t.Run("Add schedule with next run", func(t *testing.T) {
+		rec := addSchedule(`{"name": "every-minute", "task_key": "` + task.Key + `", "cron_expression": "* * * * *", "parameters": "{\"count\": 3}", "enabled": true}`)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &schedule))
+		assert.Equal(t, task.Key, schedule.TaskKey)
+		assert.Equal(t, float64(3), schedule.Parameters["count"])
+		require.NotNil(t, schedule.NextRunAt)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), *schedule.NextRunAt, time.Minute)
+	})
+
+	t.Run("Add schedule with invalid cron, task or parameters", func(t *testing.T) {
+		rec := addSchedule(`{"name": "invalid", "task_key": "` + task.Key + `", "cron_expression": "* * *", "parameters": "{\"count\": 3}"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "must have 5 fields")
+
+		rec = addSchedule(`{"name": "invalid", "task_key": "unknown-task", "cron_expression": "* * * * *"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "not found")
+
+		rec = addSchedule(`{"name": "invalid", "task_key": "` + task.Key + `", "cron_expression": "* * * * *", "parameters": "{\"count\": 0}"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Validation error")
+	})
+
+	t.Run("Run due schedules adds the job once", func(t *testing.T) {
+		now := schedule.NextRunAt.Add(time.Second)
+		added, err := handler.RunDueSchedules(context.Background(), now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, added)
+
+		added, err = handler.RunDueSchedules(context.Background(), now)
+		require.NoError(t, err)
+		assert.Equal(t, 0, added, "Expected a claimed run to not add the job again")
+
+		ranSchedule, err := sdb.SelectSchedule(schedule.RID)
+		require.NoError(t, err)
+		require.NotNil(t, ranSchedule.LastJobRID)
+		assert.Empty(t, ranSchedule.LastError)
+		assert.True(t, ranSchedule.NextRunAt.After(now))
+
+		job, _, err := handler.jobWithEnded(*ranSchedule.LastJobRID)
+		require.NoError(t, err)
+		assert.Equal(t, task.Key, job.TaskName)
+		assert.Equal(t, []any{float64(3)}, []any(job.Parameters))
+
+		jobInitiator, err := jidb.SelectJobInitiator(job.RID)
+		require.NoError(t, err)
+		assert.Equal(t, qmModel.SCHEDULE_INITIATOR_PREFIX+"every-minute", jobInitiator.Initiator)
+	})
+
+	t.Run("Disabled schedules do not run", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/schedule/updateSchedulesEnabled?enabled=false&rid="+schedule.RID.String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		require.NoError(t, handler.UpdateSchedulesEnabled(c))
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		disabledSchedule, err := sdb.SelectSchedule(schedule.RID)
+		require.NoError(t, err)
+		assert.False(t, disabledSchedule.Enabled)
+		assert.Nil(t, disabledSchedule.NextRunAt)
+
+		added, err := handler.RunDueSchedules(context.Background(), time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 0, added)
+	})
+
+	t.Run("Update and delete schedule", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/schedule/updateSchedule?rid="+schedule.RID.String(), strings.NewReader(`{"name": "nightly", "task_key": "`+task.Key+`", "cron_expression": "0  2 * * *", "parameters": "{\"count\": 5}", "enabled": true}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		require.NoError(t, handler.UpdateSchedule(c))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var updatedSchedule qmModel.Schedule
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updatedSchedule))
+		assert.Equal(t, "nightly", updatedSchedule.Name)
+		assert.Equal(t, "0 2 * * *", updatedSchedule.CronExpression)
+		assert.True(t, updatedSchedule.Enabled)
+		require.NotNil(t, updatedSchedule.NextRunAt)
+		assert.Equal(t, 2, updatedSchedule.NextRunAt.Local().Hour())
+
+		req = httptest.NewRequest(http.MethodPost, "/api/schedule/deleteSchedules?rid="+schedule.RID.String(), nil)
+		rec = httptest.NewRecorder()
+		c = e.NewContext(req, rec)
+		require.NoError(t, handler.DeleteSchedules(c))
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		_, err := sdb.SelectSchedule(schedule.RID)
+		assert.Error(t, err, "Expected the schedule to be deleted")
+	})
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+)
+
+// parseJobScheduledAt parses the time a job is scheduled at, either RFC3339 or the datetime of the job form in UTC.
+func parseJobScheduledAt(value string) (time.Time, error) {
+	scheduledAt, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return scheduledAt, nil
+	}
+	scheduledAt, err = time.ParseInLocation(qmModel.JOB_SCHEDULED_AT_FORM_LAYOUT, value, time.UTC)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid scheduled_at %q (must be RFC3339)", value)
+	}
+	return scheduledAt, nil
+}
+
+// validateJobScheduledAt checks the time a job is scheduled at is in the future.
+func validateJobScheduledAt(scheduledAt time.Time) error {
+	if !scheduledAt.After(time.Now()) {
+		return fmt.Errorf("invalid scheduled_at (must be in the future)")
+	}
+	return nil
+}
+
+// jobScheduledAtFromRequest returns the time a job added with the job form is scheduled at from the scheduled_at query parameter,
+// or from the scheduled_at form field if the task has no parameter with that key. Without scheduled_at the job is queued right away.
+func jobScheduledAtFromRequest(c *echo.Context, task *qmModel.Task) (*time.Time, error) {
+	scheduledAtStr := c.QueryParam(qmModel.JOB_SCHEDULED_AT_FIELD)
+	if scheduledAtStr == "" && !task.HasParameter(qmModel.JOB_SCHEDULED_AT_FIELD) {
+		scheduledAtStr = c.Request().FormValue(qmModel.JOB_SCHEDULED_AT_FIELD)
+	}
+	if strings.TrimSpace(scheduledAtStr) == "" {
+		return nil, nil
+	}
+
+	scheduledAt, err := parseJobScheduledAt(strings.TrimSpace(scheduledAtStr))
+	if err != nil {
+		return nil, err
+	}
+	err = validateJobScheduledAt(scheduledAt)
+	if err != nil {
+		return nil, err
+	}
+	return &scheduledAt, nil
+}
+
+// jobScheduleOptions returns the options of a job running once at the time, nil if the job is not scheduled.
+func jobScheduleOptions(scheduledAt *time.Time) *model.Options {
+	if scheduledAt == nil {
+		return nil
+	}
+	return &model.Options{
+		Schedule: &model.Schedule{
+			Start:    scheduledAt.UTC(),
+			MaxCount: 1,
+		},
+	}
+}
+
+// scheduledJobs retrieves the pending scheduled jobs of the namespace, the next job to run first.
+// lastId is the ID of the last job of the previous page, jobs that ended in the meantime are skipped.
+func (m *ManagerHandler) scheduledJobs(namespace string, lastId int, limit int) ([]*model.Job, error) {
+	if m.JobDB == nil {
+		return nil, fmt.Errorf("scheduled jobs are not enabled")
+	}
+
+	rids, err := m.JobDB.SelectScheduledJobRIDs(namespace, time.Now(), lastId, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := []*model.Job{}
+	for _, rid := range rids {
+		job, err := m.Queuer.GetJob(rid)
+		if err != nil {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// =======API Handlers=======
+
+// GetScheduledJobs retrieves a paginated list of the pending scheduled jobs, the next job to run first.
+// Pending scheduled jobs can be cancelled with CancelJob before they run.
+func (m *ManagerHandler) GetScheduledJobs(c *echo.Context) error {
+	if m.JobDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Scheduled jobs are not available")
+	}
+
+	lastId, limit, err := m.apiPagination(c)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+
+	jobs, err := m.scheduledJobs(requestNamespace(c), lastId, limit)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, "Failed to retrieve scheduled jobs")
+	}
+
+	return renderPopupOrJson(c, http.StatusOK, jobs)
+}
+
+// =======View Handlers=======
+
+// ScheduledJobsView renders the list of the pending scheduled jobs.
+func (m *ManagerHandler) ScheduledJobsView(c *echo.Context) error {
+	if m.JobDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Scheduled jobs are not available")
+	}
+
+	lastId, limit, err := m.viewPagination(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	jobs, err := m.scheduledJobs(requestNamespace(c), lastId, limit)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to retrieve scheduled jobs")
+	}
+
+	c.Response().Header().Add("HX-Push-Url", fmt.Sprintf("/scheduledJobs?limit=%d&lastId=%d", limit, lastId))
+	c.Response().Header().Add("HX-Retarget", "#body")
+
+	return render(c, screens.ScheduledJobs(jobs))
+}
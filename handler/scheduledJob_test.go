@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuer/model"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/testutil/fake"
+	"github.com/siherrmann/queuerManager/upload"
+	vm "github.com/siherrmann/validator/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJobScheduledAt(t *testing.T) {
+	scheduledAt, err := parseJobScheduledAt("2030-01-02T03:04:05+02:00")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2030, 1, 2, 1, 4, 5, 0, time.UTC), scheduledAt.UTC())
+
+	scheduledAt, err = parseJobScheduledAt("2030-01-02T03:04")
+	require.NoError(t, err, "Expected the datetime of the job form to be parsed")
+	assert.Equal(t, time.Date(2030, 1, 2, 3, 4, 0, 0, time.UTC), scheduledAt, "Expected the datetime of the job form to be in UTC")
+
+	_, err = parseJobScheduledAt("tomorrow")
+	assert.ErrorContains(t, err, "must be RFC3339")
+
+	assert.NoError(t, validateJobScheduledAt(time.Now().Add(time.Minute)))
+	assert.ErrorContains(t, validateJobScheduledAt(time.Now().Add(-time.Minute)), "must be in the future")
+
+	assert.Nil(t, jobScheduleOptions(nil))
+	options := jobScheduleOptions(&scheduledAt)
+	require.NotNil(t, options.Schedule)
+	assert.Equal(t, scheduledAt, options.Schedule.Start)
+	assert.Equal(t, 1, options.Schedule.MaxCount, "Expected the scheduled job to run once")
+	assert.NoError(t, options.IsValid())
+}
+
+func TestAddJobScheduledAt(t *testing.T) {
+	taskDB := fake.NewTaskDB()
+	fakeQueuer := fake.NewQueuer()
+	handler := NewManagerHandler(upload.NewFilesystemMemory(), taskDB, fakeQueuer)
+	e := echo.New()
+
+	_, err := taskDB.InsertTask(&qmModel.Task{
+		Key:                  "test-scheduled-task",
+		Name:                 "Test Scheduled Task",
+		InputParameters:      []vm.Validation{},
+		InputParametersKeyed: []vm.Validation{},
+	})
+	require.NoError(t, err)
+
+	createJob := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		require.NoError(t, handler.CreateJob(e.NewContext(req, rec)))
+		return rec
+	}
+
+	t.Run("CreateJob with scheduled_at schedules the job", func(t *testing.T) {
+		scheduledAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		rec := createJob(fmt.Sprintf(`{"task_key": "test-scheduled-task", "scheduled_at": %q}`, scheduledAt.Format(time.RFC3339)))
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		job := &model.Job{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), job))
+		assert.Equal(t, model.JobStatusScheduled, job.Status)
+		require.NotNil(t, job.ScheduledAt)
+		assert.True(t, scheduledAt.Equal(*job.ScheduledAt), "Expected the job to be scheduled at %v, got %v", scheduledAt, job.ScheduledAt)
+	})
+
+	t.Run("CreateJob with scheduled_at in the past is rejected", func(t *testing.T) {
+		rec := createJob(fmt.Sprintf(`{"task_key": "test-scheduled-task", "scheduled_at": %q}`, time.Now().Add(-time.Hour).Format(time.RFC3339)))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "must be in the future")
+	})
+
+	t.Run("AddJob with scheduled_at form field schedules the job", func(t *testing.T) {
+		scheduledAt := time.Now().Add(2 * time.Hour).UTC()
+		form := "scheduled_at=" + scheduledAt.Format(qmModel.JOB_SCHEDULED_AT_FORM_LAYOUT)
+		req := httptest.NewRequest(http.MethodPost, "/api/job/addJob/test-scheduled-task", strings.NewReader(form))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: "test-scheduled-task"}})
+
+		require.NoError(t, handler.AddJob(c))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		job := &model.Job{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), job))
+		assert.Equal(t, model.JobStatusScheduled, job.Status)
+		require.NotNil(t, job.ScheduledAt)
+		assert.Equal(t, scheduledAt.Truncate(time.Minute), job.ScheduledAt.UTC())
+	})
+
+	t.Run("AddJob with invalid scheduled_at is rejected", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/job/addJob/test-scheduled-task?scheduled_at=soon", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: "test-scheduled-task"}})
+
+		require.NoError(t, handler.AddJob(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Scheduled jobs are not available without the job database", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/job/getScheduledJobs", nil)
+		rec := httptest.NewRecorder()
+
+		require.NoError(t, handler.GetScheduledJobs(e.NewContext(req, rec)))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+}
+
+func TestGetScheduledJobsHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	jdb, err := database.NewJobDBHandler(db)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.JobDB = jdb
+	e := echo.New()
+
+	laterAt, soonerAt := time.Now().Add(2*time.Hour), time.Now().Add(time.Hour)
+	later, err := queue.AddJobWithOptions(jobScheduleOptions(&laterAt), "test-task", nil, 10)
+	require.NoError(t, err)
+	sooner, err := queue.AddJobWithOptions(jobScheduleOptions(&soonerAt), "test-task", nil, 10)
+	require.NoError(t, err)
+
+	t.Run("List the pending scheduled jobs", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/job/getScheduledJobs", nil)
+		rec := httptest.NewRecorder()
+
+		require.NoError(t, handler.GetScheduledJobs(e.NewContext(req, rec)))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var jobs []*model.Job
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &jobs))
+		require.Len(t, jobs, 2)
+		assert.Equal(t, sooner.RID, jobs[0].RID, "Expected the next job to run first")
+		assert.Equal(t, later.RID, jobs[1].RID)
+	})
+
+	t.Run("Cancelled scheduled jobs are not pending", func(t *testing.T) {
+		_, err := queue.CancelJob(sooner.RID)
+		require.NoError(t, err)
+
+		jobs, err := handler.scheduledJobs("", 0, 10)
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, later.RID, jobs[0].RID)
+	})
+}
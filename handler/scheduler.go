@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/siherrmann/queuerManager/database"
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/labstack/echo/v5"
+)
+
+// =======API Handlers=======
+
+// GetSchedulerPolicy returns the scheduler policy, in which order the workers claim the queued jobs.
+func (m *ManagerHandler) GetSchedulerPolicy(c *echo.Context) error {
+	if m.SchedulerDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Scheduler policy is not enabled")
+	}
+
+	policy, err := m.SchedulerDB.SelectSchedulerPolicy()
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get scheduler policy: %v", err))
+	}
+
+	return c.JSON(http.StatusOK, policy)
+}
+
+// UpdateSchedulerPolicy sets the mode and task weights of the scheduler policy of the JSON or form body.
+// With the scheduler override the policy is applied to the database at once, so all workers claim their next jobs with it.
+// Without it only the fifo mode of the queuer is available.
+func (m *ManagerHandler) UpdateSchedulerPolicy(c *echo.Context) error {
+	if m.SchedulerDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Scheduler policy is not enabled")
+	}
+
+	var request model.SchedulerPolicyRequest
+	if err := c.Bind(&request); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+	}
+	policy, err := schedulerPolicyFromRequest(&request)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+	policy.UpdatedBy = requestedBy(c)
+	if policy.Mode != model.SCHEDULER_MODE_FIFO && !m.SchedulerOverride {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Round robin mode needs the scheduler override (QUEUER_MANAGER_SCHEDULER_OVERRIDE)")
+	}
+
+	updatedPolicy, err := m.SchedulerDB.UpdateSchedulerPolicy(policy)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to update scheduler policy: %v", err))
+	}
+	if m.SchedulerOverride {
+		err = m.SchedulerDB.ApplySchedulerPolicy(updatedPolicy)
+		if err != nil {
+			return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to apply scheduler policy: %v", err))
+		}
+	}
+
+	if c.Request().Header.Get("HX-Request") != "" {
+		tasks, err := m.taskDB.SelectAllTasks(0, 1000)
+		if err != nil {
+			return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get tasks: %v", err))
+		}
+		c.Response().Header().Add("HX-Retarget", "#scheduler_policy")
+		c.Response().Header().Add("HX-Reswap", "outerHTML")
+		return render(c, screens.SchedulerPolicySettings(updatedPolicy, tasks, m.SchedulerOverride))
+	}
+	return c.JSON(http.StatusOK, updatedPolicy)
+}
+
+// =======View Handlers=======
+
+// SchedulerPolicyView renders the scheduler policy view
+func (m *ManagerHandler) SchedulerPolicyView(c *echo.Context) error {
+	if m.SchedulerDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Scheduler policy is not enabled")
+	}
+
+	policy, err := m.SchedulerDB.SelectSchedulerPolicy()
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get scheduler policy: %v", err))
+	}
+	tasks, err := m.taskDB.SelectAllTasks(0, 1000)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to get tasks: %v", err))
+	}
+
+	c.Response().Header().Add("HX-Push-Url", "/scheduler")
+	c.Response().Header().Add("HX-Retarget", "#body")
+
+	return render(c, screens.SchedulerPolicy(policy, tasks, m.SchedulerOverride))
+}
+
+// =======Helpers=======
+
+// InitSchedulerPolicy applies the stored scheduler policy on start if the scheduler override is enabled,
+// also the fifo mode so the job priorities are claimed first. Without the override a job claiming function
+// replaced before is restored to the function of the queuer.
+// If the installed job claiming function of the queuer is an unsupported version, the override is disabled
+// and the queuer keeps its function, the manager only logs a warning.
+func (m *ManagerHandler) InitSchedulerPolicy() error {
+	if m.SchedulerDB == nil {
+		return nil
+	}
+
+	if !m.SchedulerOverride {
+		err := m.SchedulerDB.RestoreJobFunction()
+		if err != nil {
+			return fmt.Errorf("error restoring job function: %w", err)
+		}
+		return nil
+	}
+
+	policy, err := m.SchedulerDB.SelectSchedulerPolicy()
+	if err != nil {
+		return fmt.Errorf("error getting scheduler policy: %w", err)
+	}
+
+	err = m.SchedulerDB.ApplySchedulerPolicy(policy)
+	if errors.Is(err, database.ErrJobFunctionVersion) {
+		log.Printf("Warning: scheduler override disabled: %v", err)
+		m.SchedulerOverride = false
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("error applying scheduler policy: %w", err)
+	}
+
+	return nil
+}
+
+// schedulerPolicyFromRequest validates the request and returns the policy of it.
+// The weights of the form are added to the task weights, weights of the default weight are not stored.
+func schedulerPolicyFromRequest(request *model.SchedulerPolicyRequest) (*model.SchedulerPolicy, error) {
+	mode := strings.TrimSpace(request.Mode)
+	if mode == "" {
+		mode = model.SCHEDULER_MODE_FIFO
+	}
+	if mode != model.SCHEDULER_MODE_FIFO && mode != model.SCHEDULER_MODE_ROUND_ROBIN {
+		return nil, fmt.Errorf("invalid mode %q (must be %s or %s)", mode, model.SCHEDULER_MODE_FIFO, model.SCHEDULER_MODE_ROUND_ROBIN)
+	}
+
+	taskWeights := map[string]int{}
+	for taskName, weight := range request.TaskWeights {
+		taskWeights[taskName] = weight
+	}
+	for line := range strings.Lines(request.Weights) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		taskName, weightValue, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid weight %q (must be task=weight)", line)
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(weightValue))
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight %q (must be task=weight)", line)
+		}
+		taskWeights[strings.TrimSpace(taskName)] = weight
+	}
+
+	for taskName, weight := range taskWeights {
+		if taskName == "" {
+			return nil, fmt.Errorf("missing task of weight %d", weight)
+		}
+		if weight < 1 || weight > 100 {
+			return nil, fmt.Errorf("invalid weight %d of task %s (must be between 1 and 100)", weight, taskName)
+		}
+		if weight == model.SCHEDULER_DEFAULT_TASK_WEIGHT {
+			delete(taskWeights, taskName)
+		}
+	}
+
+	return &model.SchedulerPolicy{Mode: mode, TaskWeights: taskWeights}, nil
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/siherrmann/queuerManager/database"
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerPolicyFromRequest(t *testing.T) {
+	t.Run("Should default to fifo", func(t *testing.T) {
+		policy, err := schedulerPolicyFromRequest(&model.SchedulerPolicyRequest{})
+
+		require.NoError(t, err)
+		assert.Equal(t, model.SCHEDULER_MODE_FIFO, policy.Mode)
+		assert.Empty(t, policy.TaskWeights)
+	})
+
+	t.Run("Should merge form and JSON weights without default weights", func(t *testing.T) {
+		policy, err := schedulerPolicyFromRequest(&model.SchedulerPolicyRequest{
+			Mode:        model.SCHEDULER_MODE_ROUND_ROBIN,
+			TaskWeights: map[string]int{"report": 2},
+			Weights:     "import = 3\r\n\nexport=1\n",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, model.SCHEDULER_MODE_ROUND_ROBIN, policy.Mode)
+		assert.Equal(t, map[string]int{"report": 2, "import": 3}, policy.TaskWeights)
+		assert.Equal(t, 1, policy.TaskWeight("export"))
+		assert.Equal(t, 3, policy.TaskWeight("import"))
+	})
+
+	t.Run("Should reject invalid policies", func(t *testing.T) {
+		_, err := schedulerPolicyFromRequest(&model.SchedulerPolicyRequest{Mode: "lifo"})
+		assert.ErrorContains(t, err, "invalid mode")
+
+		_, err = schedulerPolicyFromRequest(&model.SchedulerPolicyRequest{Weights: "import"})
+		assert.ErrorContains(t, err, "must be task=weight")
+
+		_, err = schedulerPolicyFromRequest(&model.SchedulerPolicyRequest{Weights: "import=0"})
+		assert.ErrorContains(t, err, "must be between 1 and 100")
+
+		_, err = schedulerPolicyFromRequest(&model.SchedulerPolicyRequest{Weights: "=2"})
+		assert.ErrorContains(t, err, "missing task")
+	})
+}
+
+func TestUpdateSchedulerPolicyWithoutOverride(t *testing.T) {
+	handler := &ManagerHandler{SchedulerDB: &database.SchedulerDBHandler{}}
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/scheduler/updatePolicy", strings.NewReader(`{"mode": "round_robin"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, handler.UpdateSchedulerPolicy(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "Expected the round robin mode to need the scheduler override")
+	assert.Contains(t, rec.Body.String(), "QUEUER_MANAGER_SCHEDULER_OVERRIDE")
+}
@@ -0,0 +1,841 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+)
+
+// SCIM_MAX_PAGE_SIZE is the maximum count of resources returned by a SCIM list request
+const SCIM_MAX_PAGE_SIZE = 200
+
+// MIMEApplicationScimJSON is the content type of the SCIM responses
+const MIMEApplicationScimJSON = "application/scim+json"
+
+// scimFilterRegex matches the supported filters, eg. userName eq "jdoe"
+var scimFilterRegex = regexp.MustCompile(`(?i)^\s*(\w+)\s+eq\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+// scimMemberFilterRegex matches the member path of a remove operation, eg. members[value eq "<id>"]
+var scimMemberFilterRegex = regexp.MustCompile(`(?i)^members\[\s*value\s+eq\s+"([^"]+)"\s*\]$`)
+
+// =======Middleware=======
+
+// ScimTokenMiddleware checks the bearer token of the identity provider. The SCIM endpoints are disabled without a configured token.
+func (m *ManagerHandler) ScimTokenMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c *echo.Context) error {
+		if status, err := checkBearerToken(c.Request(), m.ScimToken, "SCIM"); err != nil {
+			return scimError(c, status, "", err.Error())
+		}
+		if m.UserDB == nil || m.GroupDB == nil {
+			return scimError(c, http.StatusServiceUnavailable, "", "SCIM integration is not configured")
+		}
+		return next(c)
+	}
+}
+
+// =======API Handlers=======
+
+// ScimServiceProviderConfig describes the supported SCIM features
+func (m *ManagerHandler) ScimServiceProviderConfig(c *echo.Context) error {
+	return scimJSON(c, http.StatusOK, map[string]any{
+		"schemas":        []string{model.SCIM_SCHEMA_SERVICE_PROVIDER_CONFIG},
+		"patch":          map[string]bool{"supported": true},
+		"bulk":           map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]any{"supported": true, "maxResults": SCIM_MAX_PAGE_SIZE},
+		"changePassword": map[string]bool{"supported": false},
+		"sort":           map[string]bool{"supported": false},
+		"etag":           map[string]bool{"supported": false},
+		"authenticationSchemes": []map[string]string{{
+			"type":        "oauthbearertoken",
+			"name":        "OAuth Bearer Token",
+			"description": "Authentication with the token of QUEUER_MANAGER_SCIM_TOKEN",
+		}},
+	})
+}
+
+// ScimGetUsers lists the users, optionally filtered by userName or externalId
+func (m *ManagerHandler) ScimGetUsers(c *echo.Context) error {
+	startIndex, count, err := scimPagination(c)
+	if err != nil {
+		return scimError(c, http.StatusBadRequest, "invalidValue", err.Error())
+	}
+	attribute, value, err := parseScimFilter(c.QueryParam("filter"))
+	if err != nil {
+		return scimError(c, http.StatusBadRequest, "invalidFilter", err.Error())
+	}
+
+	users, err := m.allUsers()
+	if err != nil {
+		return scimError(c, http.StatusInternalServerError, "", fmt.Sprintf("Failed to get users: %v", err))
+	}
+
+	filteredUsers := []*model.User{}
+	for _, user := range users {
+		switch attribute {
+		case "":
+		case "username":
+			if !strings.EqualFold(user.Username, value) {
+				continue
+			}
+		case "externalid":
+			if user.ExternalID != value {
+				continue
+			}
+		default:
+			return scimError(c, http.StatusBadRequest, "invalidFilter", fmt.Sprintf("Filtering users by %s is not supported", attribute))
+		}
+		filteredUsers = append(filteredUsers, user)
+	}
+
+	resources := []*model.ScimUser{}
+	for _, user := range scimPage(filteredUsers, startIndex, count) {
+		scimUser, err := m.scimUserFromUser(c, user)
+		if err != nil {
+			return scimError(c, http.StatusInternalServerError, "", fmt.Sprintf("Failed to get groups of user: %v", err))
+		}
+		resources = append(resources, scimUser)
+	}
+
+	return scimJSON(c, http.StatusOK, &model.ScimListResponse{
+		Schemas:      []string{model.SCIM_SCHEMA_LIST_RESPONSE},
+		TotalResults: len(filteredUsers),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+// ScimGetUser returns a single user
+func (m *ManagerHandler) ScimGetUser(c *echo.Context) error {
+	user, err := m.scimUser(c.Param("id"))
+	if err != nil {
+		return scimError(c, http.StatusNotFound, "", "User not found")
+	}
+
+	scimUser, err := m.scimUserFromUser(c, user)
+	if err != nil {
+		return scimError(c, http.StatusInternalServerError, "", fmt.Sprintf("Failed to get groups of user: %v", err))
+	}
+
+	return scimJSON(c, http.StatusOK, scimUser)
+}
+
+// ScimCreateUser provisions a new user. The role of the user is set by the groups it is added to.
+func (m *ManagerHandler) ScimCreateUser(c *echo.Context) error {
+	scimUser := &model.ScimUser{}
+	err := json.NewDecoder(c.Request().Body).Decode(scimUser)
+	if err != nil {
+		return scimError(c, http.StatusBadRequest, "invalidSyntax", fmt.Sprintf("Invalid user: %v", err))
+	}
+	if strings.TrimSpace(scimUser.UserName) == "" {
+		return scimError(c, http.StatusBadRequest, "invalidValue", "userName is required")
+	}
+
+	_, err = m.UserDB.SelectUserByUsername(scimUser.UserName)
+	if err == nil {
+		return scimError(c, http.StatusConflict, "uniqueness", fmt.Sprintf("User %s already exists", scimUser.UserName))
+	}
+
+	user := &model.User{Source: model.USER_SOURCE_SCIM}
+	applyScimUser(user, scimUser)
+	createdUser, err := m.UserDB.UpsertUser(user)
+	if err != nil {
+		return scimError(c, http.StatusInternalServerError, "", fmt.Sprintf("Failed to create user: %v", err))
+	}
+
+	response, err := m.scimUserFromUser(c, createdUser)
+	if err != nil {
+		return scimError(c, http.StatusInternalServerError, "", fmt.Sprintf("Failed to get groups of user: %v", err))
+	}
+	c.Response().Header().Set("Location", response.Meta.Location)
+
+	return scimJSON(c, http.StatusCreated, response)
+}
+
+// ScimReplaceUser replaces the attributes of a user, eg. to deactivate it with active false.
+// Existing users, eg. of LDAP, are taken over by the identity provider.
+func (m *ManagerHandler) ScimReplaceUser(c *echo.Context) error {
+	user, err := m.scimUser(c.Param("id"))
+	if err != nil {
+		return scimError(c, http.StatusNotFound, "", "User not found")
+	}
+
+	scimUser := &model.ScimUser{}
+	err = json.NewDecoder(c.Request().Body).Decode(scimUser)
+	if err != nil {
+		return scimError(c, http.StatusBadRequest, "invalidSyntax", fmt.Sprintf("Invalid user: %v", err))
+	}
+	if strings.TrimSpace(scimUser.UserName) == "" {
+		return scimError(c, http.StatusBadRequest, "invalidValue", "userName is required")
+	}
+
+	applyScimUser(user, scimUser)
+
+	return m.saveScimUser(c, user)
+}
+
+// ScimPatchUser updates single attributes of a user. Identity providers deactivate users with
+// a replace operation of active, unsupported attributes are ignored.
+func (m *ManagerHandler) ScimPatchUser(c *echo.Context) error {
+	user, err := m.scimUser(c.Param("id"))
+	if err != nil {
+		return scimError(c, http.StatusNotFound, "", "User not found")
+	}
+
+	patchRequest := &model.ScimPatchRequest{}
+	err = json.NewDecoder(c.Request().Body).Decode(patchRequest)
+	if err != nil {
+		return scimError(c, http.StatusBadRequest, "invalidSyntax", fmt.Sprintf("Invalid patch request: %v", err))
+	}
+
+	for _, operation := range patchRequest.Operations {
+		op := strings.ToLower(operation.Op)
+		if op != "add" && op != "replace" {
+			return scimError(c, http.StatusBadRequest, "invalidValue", fmt.Sprintf("Unsupported operation %s for users", operation.Op))
+		}
+
+		// Without path the value contains the attributes to replace
+		if operation.Path == "" {
+			attributes := map[string]json.RawMessage{}
+			err := json.Unmarshal(operation.Value, &attributes)
+			if err != nil {
+				return scimError(c, http.StatusBadRequest, "invalidValue", fmt.Sprintf("Invalid patch value: %v", err))
+			}
+			for path, value := range attributes {
+				err := applyScimUserAttribute(user, path, value)
+				if err != nil {
+					return scimError(c, http.StatusBadRequest, "invalidValue", err.Error())
+				}
+			}
+			continue
+		}
+
+		err := applyScimUserAttribute(user, operation.Path, operation.Value)
+		if err != nil {
+			return scimError(c, http.StatusBadRequest, "invalidValue", err.Error())
+		}
+	}
+
+	return m.saveScimUser(c, user)
+}
+
+// ScimDeleteUser deprovisions a user. The user is deactivated and removed from all groups,
+// but kept so it can still be seen who for example added a job.
+func (m *ManagerHandler) ScimDeleteUser(c *echo.Context) error {
+	user, err := m.scimUser(c.Param("id"))
+	if err != nil {
+		return scimError(c, http.StatusNotFound, "", "User not found")
+	}
+
+	groups, err := m.GroupDB.SelectGroupsByMember(user.RID)
+	if err != nil {
+		return scimError(c, http.StatusInternalServerError, "", fmt.Sprintf("Failed to get groups of user: %v", err))
+	}
+	for _, group := range groups {
+		group.Members = slices.DeleteFunc(group.Members, func(member uuid.UUID) bool { return member == user.RID })
+		_, err := m.GroupDB.UpdateGroup(group)
+		if err != nil {
+			return scimError(c, http.StatusInternalServerError, "", fmt.Sprintf("Failed to update group %s: %v", group.DisplayName, err))
+		}
+	}
+
+	user.Source = model.USER_SOURCE_SCIM
+	user.Active = false
+	user.Role = ""
+	user.Groups = []string{}
+	_, err = m.UserDB.UpdateUser(user)
+	if err != nil {
+		return scimError(c, http.StatusInternalServerError, "", fmt.Sprintf("Failed to deactivate user: %v", err))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ScimGetGroups lists the groups, optionally filtered by displayName or externalId
+func (m *ManagerHandler) ScimGetGroups(c *echo.Context) error {
+	startIndex, count, err := scimPagination(c)
+	if err != nil {
+		return scimError(c, http.StatusBadRequest, "invalidValue", err.Error())
+	}
+	attribute, value, err := parseScimFilter(c.QueryParam("filter"))
+	if err != nil {
+		return scimError(c, http.StatusBadRequest, "invalidFilter", err.Error())
+	}
+
+	groups, err := m.allGroups()
+	if err != nil {
+		return scimError(c, http.StatusInternalServerError, "", fmt.Sprintf("Failed to get groups: %v", err))
+	}
+
+	filteredGroups := []*model.Group{}
+	for _, group := range groups {
+		switch attribute {
+		case "":
+		case "displayname":
+			if !strings.EqualFold(group.DisplayName, value) {
+				continue
+			}
+		case "externalid":
+			if group.ExternalID != value {
+				continue
+			}
+		default:
+			return scimError(c, http.StatusBadRequest, "invalidFilter", fmt.Sprintf("Filtering groups by %s is not supported", attribute))
+		}
+		filteredGroups = append(filteredGroups, group)
+	}
+
+	resources := []*model.ScimGroup{}
+	for _, group := range scimPage(filteredGroups, startIndex, count) {
+		resources = append(resources, scimGroupFromGroup(c, group))
+	}
+
+	return scimJSON(c, http.StatusOK, &model.ScimListResponse{
+		Schemas:      []string{model.SCIM_SCHEMA_LIST_RESPONSE},
+		TotalResults: len(filteredGroups),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+// ScimGetGroup returns a single group
+func (m *ManagerHandler) ScimGetGroup(c *echo.Context) error {
+	group, err := m.scimGroup(c.Param("id"))
+	if err != nil {
+		return scimError(c, http.StatusNotFound, "", "Group not found")
+	}
+
+	return scimJSON(c, http.StatusOK, scimGroupFromGroup(c, group))
+}
+
+// ScimCreateGroup creates a group, its members get the role mapped to the display name.
+func (m *ManagerHandler) ScimCreateGroup(c *echo.Context) error {
+	scimGroup := &model.ScimGroup{}
+	err := json.NewDecoder(c.Request().Body).Decode(scimGroup)
+	if err != nil {
+		return scimError(c, http.StatusBadRequest, "invalidSyntax", fmt.Sprintf("Invalid group: %v", err))
+	}
+	if strings.TrimSpace(scimGroup.DisplayName) == "" {
+		return scimError(c, http.StatusBadRequest, "invalidValue", "displayName is required")
+	}
+
+	members, err := m.scimMembers(scimGroup.Members)
+	if err != nil {
+		return scimError(c, http.StatusBadRequest, "invalidValue", err.Error())
+	}
+
+	createdGroup, err := m.GroupDB.InsertGroup(&model.Group{
+		ExternalID:  scimGroup.ExternalID,
+		DisplayName: scimGroup.DisplayName,
+		Members:     members,
+	})
+	if err != nil {
+		return scimError(c, http.StatusConflict, "uniqueness", fmt.Sprintf("Failed to create group: %v", err))
+	}
+
+	err = m.syncScimUserRoles(members)
+	if err != nil {
+		return scimError(c, http.StatusInternalServerError, "", fmt.Sprintf("Failed to update roles of members: %v", err))
+	}
+
+	response := scimGroupFromGroup(c, createdGroup)
+	c.Response().Header().Set("Location", response.Meta.Location)
+
+	return scimJSON(c, http.StatusCreated, response)
+}
+
+// ScimReplaceGroup replaces the display name and members of a group
+func (m *ManagerHandler) ScimReplaceGroup(c *echo.Context) error {
+	group, err := m.scimGroup(c.Param("id"))
+	if err != nil {
+		return scimError(c, http.StatusNotFound, "", "Group not found")
+	}
+
+	scimGroup := &model.ScimGroup{}
+	err = json.NewDecoder(c.Request().Body).Decode(scimGroup)
+	if err != nil {
+		return scimError(c, http.StatusBadRequest, "invalidSyntax", fmt.Sprintf("Invalid group: %v", err))
+	}
+	if strings.TrimSpace(scimGroup.DisplayName) == "" {
+		return scimError(c, http.StatusBadRequest, "invalidValue", "displayName is required")
+	}
+
+	members, err := m.scimMembers(scimGroup.Members)
+	if err != nil {
+		return scimError(c, http.StatusBadRequest, "invalidValue", err.Error())
+	}
+
+	previousMembers := group.Members
+	group.ExternalID = scimGroup.ExternalID
+	group.DisplayName = scimGroup.DisplayName
+	group.Members = members
+
+	return m.saveScimGroup(c, group, previousMembers)
+}
+
+// ScimPatchGroup adds, removes or replaces members of a group or replaces its display name
+func (m *ManagerHandler) ScimPatchGroup(c *echo.Context) error {
+	group, err := m.scimGroup(c.Param("id"))
+	if err != nil {
+		return scimError(c, http.StatusNotFound, "", "Group not found")
+	}
+
+	patchRequest := &model.ScimPatchRequest{}
+	err = json.NewDecoder(c.Request().Body).Decode(patchRequest)
+	if err != nil {
+		return scimError(c, http.StatusBadRequest, "invalidSyntax", fmt.Sprintf("Invalid patch request: %v", err))
+	}
+
+	previousMembers := slices.Clone(group.Members)
+	for _, operation := range patchRequest.Operations {
+		err := m.applyScimGroupOperation(group, operation)
+		if err != nil {
+			return scimError(c, http.StatusBadRequest, "invalidValue", err.Error())
+		}
+	}
+
+	return m.saveScimGroup(c, group, previousMembers)
+}
+
+// ScimDeleteGroup deletes a group, its members lose the role of the group
+func (m *ManagerHandler) ScimDeleteGroup(c *echo.Context) error {
+	group, err := m.scimGroup(c.Param("id"))
+	if err != nil {
+		return scimError(c, http.StatusNotFound, "", "Group not found")
+	}
+
+	err = m.GroupDB.DeleteGroup(group.RID)
+	if err != nil {
+		return scimError(c, http.StatusInternalServerError, "", fmt.Sprintf("Failed to delete group: %v", err))
+	}
+
+	err = m.syncScimUserRoles(group.Members)
+	if err != nil {
+		return scimError(c, http.StatusInternalServerError, "", fmt.Sprintf("Failed to update roles of members: %v", err))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// =======Helpers=======
+
+func scimJSON(c *echo.Context, status int, value any) error {
+	c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationScimJSON)
+	return c.JSON(status, value)
+}
+
+func scimError(c *echo.Context, status int, scimType string, detail string) error {
+	return scimJSON(c, status, &model.ScimError{
+		Schemas:  []string{model.SCIM_SCHEMA_ERROR},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+// scimPagination parses the 1-based startIndex and the count of a list request
+func scimPagination(c *echo.Context) (int, int, error) {
+	startIndex := 1
+	if startIndexStr := c.QueryParam("startIndex"); startIndexStr != "" {
+		parsedStartIndex, err := strconv.Atoi(startIndexStr)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid startIndex %q", startIndexStr)
+		}
+		startIndex = max(parsedStartIndex, 1)
+	}
+
+	count := SCIM_MAX_PAGE_SIZE
+	if countStr := c.QueryParam("count"); countStr != "" {
+		parsedCount, err := strconv.Atoi(countStr)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid count %q", countStr)
+		}
+		count = min(max(parsedCount, 0), SCIM_MAX_PAGE_SIZE)
+	}
+
+	return startIndex, count, nil
+}
+
+func scimPage[T any](resources []T, startIndex int, count int) []T {
+	start := min(startIndex-1, len(resources))
+	end := min(start+count, len(resources))
+	return resources[start:end]
+}
+
+// parseScimFilter parses an equality filter and returns the lowercase attribute and the value.
+func parseScimFilter(filter string) (string, string, error) {
+	if strings.TrimSpace(filter) == "" {
+		return "", "", nil
+	}
+
+	matches := scimFilterRegex.FindStringSubmatch(filter)
+	if matches == nil {
+		return "", "", fmt.Errorf("unsupported filter %q, only eq filters are supported", filter)
+	}
+
+	value, err := strconv.Unquote(`"` + matches[2] + `"`)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid filter value %q", matches[2])
+	}
+
+	return strings.ToLower(matches[1]), value, nil
+}
+
+func (m *ManagerHandler) scimUser(id string) (*model.User, error) {
+	rid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return m.UserDB.SelectUser(rid)
+}
+
+func (m *ManagerHandler) scimGroup(id string) (*model.Group, error) {
+	rid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return m.GroupDB.SelectGroup(rid)
+}
+
+// scimMembers parses the member references and checks that the users exist.
+func (m *ManagerHandler) scimMembers(references []model.ScimReference) ([]uuid.UUID, error) {
+	members := []uuid.UUID{}
+	for _, reference := range references {
+		member, err := m.scimUser(reference.Value)
+		if err != nil {
+			return nil, fmt.Errorf("member %s not found", reference.Value)
+		}
+		if !slices.Contains(members, member.RID) {
+			members = append(members, member.RID)
+		}
+	}
+	return members, nil
+}
+
+func (m *ManagerHandler) allUsers() ([]*model.User, error) {
+	allUsers := []*model.User{}
+	lastID := 0
+	for {
+		users, err := m.UserDB.SelectAllUsers(lastID, 100)
+		if err != nil {
+			return nil, err
+		}
+		if len(users) == 0 {
+			return allUsers, nil
+		}
+		allUsers = append(allUsers, users...)
+		lastID = users[len(users)-1].ID
+	}
+}
+
+func (m *ManagerHandler) allGroups() ([]*model.Group, error) {
+	allGroups := []*model.Group{}
+	lastID := 0
+	for {
+		groups, err := m.GroupDB.SelectAllGroups(lastID, 100)
+		if err != nil {
+			return nil, err
+		}
+		if len(groups) == 0 {
+			return allGroups, nil
+		}
+		allGroups = append(allGroups, groups...)
+		lastID = groups[len(groups)-1].ID
+	}
+}
+
+// saveScimUser saves the user with the role of its groups and responds with the updated user.
+func (m *ManagerHandler) saveScimUser(c *echo.Context, user *model.User) error {
+	user.Source = model.USER_SOURCE_SCIM
+	err := m.setScimUserRole(user)
+	if err != nil {
+		return scimError(c, http.StatusInternalServerError, "", fmt.Sprintf("Failed to get groups of user: %v", err))
+	}
+
+	updatedUser, err := m.UserDB.UpdateUser(user)
+	if err != nil {
+		return scimError(c, http.StatusConflict, "uniqueness", fmt.Sprintf("Failed to update user: %v", err))
+	}
+
+	response, err := m.scimUserFromUser(c, updatedUser)
+	if err != nil {
+		return scimError(c, http.StatusInternalServerError, "", fmt.Sprintf("Failed to get groups of user: %v", err))
+	}
+
+	return scimJSON(c, http.StatusOK, response)
+}
+
+// saveScimGroup saves the group and updates the roles of the previous and current members.
+func (m *ManagerHandler) saveScimGroup(c *echo.Context, group *model.Group, previousMembers []uuid.UUID) error {
+	updatedGroup, err := m.GroupDB.UpdateGroup(group)
+	if err != nil {
+		return scimError(c, http.StatusConflict, "uniqueness", fmt.Sprintf("Failed to update group: %v", err))
+	}
+
+	changedMembers := slices.Clone(previousMembers)
+	for _, member := range updatedGroup.Members {
+		if !slices.Contains(changedMembers, member) {
+			changedMembers = append(changedMembers, member)
+		}
+	}
+	err = m.syncScimUserRoles(changedMembers)
+	if err != nil {
+		return scimError(c, http.StatusInternalServerError, "", fmt.Sprintf("Failed to update roles of members: %v", err))
+	}
+
+	return scimJSON(c, http.StatusOK, scimGroupFromGroup(c, updatedGroup))
+}
+
+// syncScimUserRoles updates the role and groups of the users after their group membership changed.
+func (m *ManagerHandler) syncScimUserRoles(members []uuid.UUID) error {
+	for _, member := range members {
+		user, err := m.UserDB.SelectUser(member)
+		if err != nil {
+			// Deleted users can still be referenced by a group
+			continue
+		}
+
+		user.Source = model.USER_SOURCE_SCIM
+		err = m.setScimUserRole(user)
+		if err != nil {
+			return err
+		}
+		_, err = m.UserDB.UpdateUser(user)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setScimUserRole sets the groups of the user and the highest role mapped to them.
+func (m *ManagerHandler) setScimUserRole(user *model.User) error {
+	groups, err := m.GroupDB.SelectGroupsByMember(user.RID)
+	if err != nil {
+		return err
+	}
+
+	groupNames := []string{}
+	roles := []string{}
+	for _, group := range groups {
+		groupNames = append(groupNames, group.DisplayName)
+		roles = append(roles, m.ScimGroupRoles[strings.ToLower(group.DisplayName)])
+	}
+	user.Groups = groupNames
+	user.Role = model.HighestRole(roles...)
+
+	return nil
+}
+
+func (m *ManagerHandler) applyScimGroupOperation(group *model.Group, operation model.ScimPatchOperation) error {
+	op := strings.ToLower(operation.Op)
+	path := strings.ToLower(operation.Path)
+
+	// Remove a single member, eg. members[value eq "<id>"]
+	if matches := scimMemberFilterRegex.FindStringSubmatch(operation.Path); matches != nil && op == "remove" {
+		group.Members = slices.DeleteFunc(group.Members, func(member uuid.UUID) bool { return member.String() == matches[1] })
+		return nil
+	}
+
+	switch {
+	case path == "" && op != "remove":
+		// Without path the value contains the attributes to replace
+		scimGroup := &model.ScimGroup{}
+		err := json.Unmarshal(operation.Value, scimGroup)
+		if err != nil {
+			return fmt.Errorf("invalid patch value: %v", err)
+		}
+		if scimGroup.DisplayName != "" {
+			group.DisplayName = scimGroup.DisplayName
+		}
+		if scimGroup.ExternalID != "" {
+			group.ExternalID = scimGroup.ExternalID
+		}
+		if scimGroup.Members != nil {
+			members, err := m.scimMembers(scimGroup.Members)
+			if err != nil {
+				return err
+			}
+			group.Members = members
+		}
+	case path == "displayname" && op != "remove":
+		err := json.Unmarshal(operation.Value, &group.DisplayName)
+		if err != nil || strings.TrimSpace(group.DisplayName) == "" {
+			return fmt.Errorf("invalid displayName")
+		}
+	case path == "externalid":
+		group.ExternalID = ""
+		if op != "remove" {
+			err := json.Unmarshal(operation.Value, &group.ExternalID)
+			if err != nil {
+				return fmt.Errorf("invalid externalId")
+			}
+		}
+	case path == "members":
+		references := []model.ScimReference{}
+		if len(operation.Value) > 0 {
+			err := json.Unmarshal(operation.Value, &references)
+			if err != nil {
+				return fmt.Errorf("invalid members: %v", err)
+			}
+		}
+
+		switch op {
+		case "add":
+			members, err := m.scimMembers(references)
+			if err != nil {
+				return err
+			}
+			for _, member := range members {
+				if !slices.Contains(group.Members, member) {
+					group.Members = append(group.Members, member)
+				}
+			}
+		case "replace":
+			members, err := m.scimMembers(references)
+			if err != nil {
+				return err
+			}
+			group.Members = members
+		case "remove":
+			// Without value all members are removed
+			if len(references) == 0 {
+				group.Members = []uuid.UUID{}
+			}
+			for _, reference := range references {
+				group.Members = slices.DeleteFunc(group.Members, func(member uuid.UUID) bool { return member.String() == reference.Value })
+			}
+		default:
+			return fmt.Errorf("unsupported operation %s", operation.Op)
+		}
+	default:
+		return fmt.Errorf("unsupported operation %s of %s for groups", operation.Op, operation.Path)
+	}
+
+	return nil
+}
+
+// applyScimUser sets the attributes of the SCIM user, active defaults to true.
+func applyScimUser(user *model.User, scimUser *model.ScimUser) {
+	user.Username = strings.TrimSpace(scimUser.UserName)
+	user.ExternalID = scimUser.ExternalID
+	user.Name = scimUser.DisplayName
+	if user.Name == "" && scimUser.Name != nil {
+		user.Name = scimUser.Name.Formatted
+		if user.Name == "" {
+			user.Name = strings.TrimSpace(scimUser.Name.GivenName + " " + scimUser.Name.FamilyName)
+		}
+	}
+	user.Email = ""
+	for _, email := range scimUser.Emails {
+		if user.Email == "" || email.Primary {
+			user.Email = email.Value
+		}
+	}
+	user.Active = scimUser.Active == nil || *scimUser.Active
+}
+
+// applyScimUserAttribute sets a single attribute of a patch operation, unsupported attributes are ignored.
+func applyScimUserAttribute(user *model.User, path string, value json.RawMessage) error {
+	var err error
+	switch strings.ToLower(path) {
+	case "active":
+		// Some identity providers send the boolean as string
+		var active any
+		err = json.Unmarshal(value, &active)
+		switch activeValue := active.(type) {
+		case bool:
+			user.Active = activeValue
+		case string:
+			user.Active, err = strconv.ParseBool(activeValue)
+		default:
+			err = fmt.Errorf("must be a boolean")
+		}
+	case "username":
+		err = json.Unmarshal(value, &user.Username)
+		if err == nil && strings.TrimSpace(user.Username) == "" {
+			err = fmt.Errorf("must not be empty")
+		}
+	case "externalid":
+		err = json.Unmarshal(value, &user.ExternalID)
+	case "displayname", "name.formatted":
+		err = json.Unmarshal(value, &user.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value of %s: %v", path, err)
+	}
+	return nil
+}
+
+// scimUserFromUser converts the user to its SCIM representation with the groups of the user.
+func (m *ManagerHandler) scimUserFromUser(c *echo.Context, user *model.User) (*model.ScimUser, error) {
+	groups, err := m.GroupDB.SelectGroupsByMember(user.RID)
+	if err != nil {
+		return nil, err
+	}
+
+	references := []model.ScimReference{}
+	for _, group := range groups {
+		references = append(references, model.ScimReference{Value: group.RID.String(), Display: group.DisplayName})
+	}
+
+	scimUser := &model.ScimUser{
+		Schemas:     []string{model.SCIM_SCHEMA_USER},
+		ID:          user.RID.String(),
+		ExternalID:  user.ExternalID,
+		UserName:    user.Username,
+		DisplayName: user.Name,
+		Active:      &user.Active,
+		Groups:      references,
+		Meta: &model.ScimMeta{
+			ResourceType: "User",
+			Created:      &user.CreatedAt,
+			LastModified: &user.UpdatedAt,
+			Location:     scimLocation(c, "Users", user.RID),
+		},
+	}
+	if user.Name != "" {
+		scimUser.Name = &model.ScimName{Formatted: user.Name}
+	}
+	if user.Email != "" {
+		scimUser.Emails = []model.ScimEmail{{Value: user.Email, Type: "work", Primary: true}}
+	}
+
+	return scimUser, nil
+}
+
+// scimGroupFromGroup converts the group to its SCIM representation.
+func scimGroupFromGroup(c *echo.Context, group *model.Group) *model.ScimGroup {
+	members := []model.ScimReference{}
+	for _, member := range group.Members {
+		members = append(members, model.ScimReference{Value: member.String()})
+	}
+
+	return &model.ScimGroup{
+		Schemas:     []string{model.SCIM_SCHEMA_GROUP},
+		ID:          group.RID.String(),
+		ExternalID:  group.ExternalID,
+		DisplayName: group.DisplayName,
+		Members:     members,
+		Meta: &model.ScimMeta{
+			ResourceType: "Group",
+			Created:      &group.CreatedAt,
+			LastModified: &group.UpdatedAt,
+			Location:     scimLocation(c, "Groups", group.RID),
+		},
+	}
+}
+
+func scimLocation(c *echo.Context, resourceType string, rid uuid.UUID) string {
+	return fmt.Sprintf("%s://%s/scim/v2/%s/%s", c.Scheme(), c.Request().Host, resourceType, rid)
+}
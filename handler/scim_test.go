@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScimHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	udb, err := database.NewUserDBHandler(db, true)
+	require.NoError(t, err)
+	gdb, err := database.NewGroupDBHandler(db, true)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.ScimToken = "test-scim-token"
+	handler.UserDB = udb
+	handler.GroupDB = gdb
+	handler.ScimGroupRoles = map[string]string{"queuer-operators": qmModel.ROLE_OPERATOR}
+	e := echo.New()
+
+	scimRequest := func(method string, target string, body string, id string, h func(c *echo.Context) error) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, MIMEApplicationScimJSON)
+		req.Header.Set("Authorization", "Bearer test-scim-token")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if id != "" {
+			c.SetPathValues([]echo.PathValue{{Name: "id", Value: id}})
+		}
+		require.NoError(t, handler.ScimTokenMiddleware(h)(c))
+		return rec
+	}
+
+	t.Run("Invalid token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/scim/v2/Users", nil)
+		req.Header.Set("Authorization", "Bearer wrong")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.ScimTokenMiddleware(handler.ScimGetUsers)(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, MIMEApplicationScimJSON, rec.Header().Get(echo.HeaderContentType))
+	})
+
+	var user qmModel.ScimUser
+	t.Run("Create user", func(t *testing.T) {
+		rec := scimRequest(http.MethodPost, "/scim/v2/Users", `{
+			"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+			"userName": "jdoe",
+			"externalId": "00u1",
+			"name": {"givenName": "John", "familyName": "Doe"},
+			"emails": [{"value": "jdoe@example.com", "primary": true}]
+		}`, "", handler.ScimCreateUser)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &user))
+		assert.Equal(t, "jdoe", user.UserName)
+		require.NotNil(t, user.Active)
+		assert.True(t, *user.Active)
+		assert.NotEmpty(t, rec.Header().Get("Location"))
+
+		rec = scimRequest(http.MethodPost, "/scim/v2/Users", `{"userName": "jdoe"}`, "", handler.ScimCreateUser)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("Get users with filter", func(t *testing.T) {
+		rec := scimRequest(http.MethodGet, `/scim/v2/Users?filter=userName+eq+%22jdoe%22`, "", "", handler.ScimGetUsers)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var response struct {
+			TotalResults int                `json:"totalResults"`
+			Resources    []qmModel.ScimUser `json:"Resources"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, 1, response.TotalResults)
+		require.Len(t, response.Resources, 1)
+		assert.Equal(t, user.ID, response.Resources[0].ID)
+
+		rec = scimRequest(http.MethodGet, `/scim/v2/Users?filter=userName+eq+%22unknown%22`, "", "", handler.ScimGetUsers)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, 0, response.TotalResults)
+
+		rec = scimRequest(http.MethodGet, `/scim/v2/Users?filter=userName+sw+%22j%22`, "", "", handler.ScimGetUsers)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	var group qmModel.ScimGroup
+	t.Run("Create group maps role", func(t *testing.T) {
+		rec := scimRequest(http.MethodPost, "/scim/v2/Groups", `{
+			"displayName": "queuer-operators",
+			"members": [{"value": "`+user.ID+`"}]
+		}`, "", handler.ScimCreateGroup)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &group))
+		assert.Len(t, group.Members, 1)
+
+		dbUser, err := udb.SelectUserByUsername("jdoe")
+		require.NoError(t, err)
+		assert.Equal(t, qmModel.ROLE_OPERATOR, dbUser.Role)
+		assert.Equal(t, []string{"queuer-operators"}, dbUser.Groups)
+		assert.Equal(t, qmModel.USER_SOURCE_SCIM, dbUser.Source)
+
+		rec = scimRequest(http.MethodPost, "/scim/v2/Groups", `{
+			"displayName": "other",
+			"members": [{"value": "`+uuid.New().String()+`"}]
+		}`, "", handler.ScimCreateGroup)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, "unknown members should be rejected")
+	})
+
+	t.Run("Patch group removes member", func(t *testing.T) {
+		rec := scimRequest(http.MethodPatch, "/scim/v2/Groups/"+group.ID, `{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [{"op": "remove", "path": "members[value eq \"`+user.ID+`\"]"}]
+		}`, group.ID, handler.ScimPatchGroup)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		dbUser, err := udb.SelectUserByUsername("jdoe")
+		require.NoError(t, err)
+		assert.Empty(t, dbUser.Role)
+
+		rec = scimRequest(http.MethodPatch, "/scim/v2/Groups/"+group.ID, `{
+			"Operations": [{"op": "add", "path": "members", "value": [{"value": "`+user.ID+`"}]}]
+		}`, group.ID, handler.ScimPatchGroup)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		dbUser, err = udb.SelectUserByUsername("jdoe")
+		require.NoError(t, err)
+		assert.Equal(t, qmModel.ROLE_OPERATOR, dbUser.Role)
+	})
+
+	t.Run("Patch user deactivates", func(t *testing.T) {
+		rec := scimRequest(http.MethodPatch, "/scim/v2/Users/"+user.ID, `{
+			"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+			"Operations": [{"op": "Replace", "path": "active", "value": "False"}]
+		}`, user.ID, handler.ScimPatchUser)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		dbUser, err := udb.SelectUserByUsername("jdoe")
+		require.NoError(t, err)
+		assert.False(t, dbUser.Active)
+
+		rec = scimRequest(http.MethodPatch, "/scim/v2/Users/"+user.ID, `{
+			"Operations": [{"op": "replace", "value": {"active": true}}]
+		}`, user.ID, handler.ScimPatchUser)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		dbUser, err = udb.SelectUserByUsername("jdoe")
+		require.NoError(t, err)
+		assert.True(t, dbUser.Active)
+	})
+
+	t.Run("Delete user deprovisions", func(t *testing.T) {
+		rec := scimRequest(http.MethodDelete, "/scim/v2/Users/"+user.ID, "", user.ID, handler.ScimDeleteUser)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+
+		dbUser, err := udb.SelectUserByUsername("jdoe")
+		require.NoError(t, err)
+		assert.False(t, dbUser.Active)
+		assert.Empty(t, dbUser.Role)
+
+		rec = scimRequest(http.MethodGet, "/scim/v2/Groups/"+group.ID, "", group.ID, handler.ScimGetGroup)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &group))
+		assert.Empty(t, group.Members)
+	})
+
+	t.Run("Delete group", func(t *testing.T) {
+		rec := scimRequest(http.MethodDelete, "/scim/v2/Groups/"+group.ID, "", group.ID, handler.ScimDeleteGroup)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+
+		rec = scimRequest(http.MethodGet, "/scim/v2/Groups/"+group.ID, "", group.ID, handler.ScimGetGroup)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+)
+
+// SLACK_MAX_REQUEST_AGE is the maximum age of a signed Slack request to prevent replay attacks
+const SLACK_MAX_REQUEST_AGE = 5 * time.Minute
+
+const slackUsage = "Usage: `/queuer run <task> key=value ...` or `/queuer status <rid>`"
+
+// SlackCommand handles the Slack slash command `/queuer run <task> key=value ...` and `/queuer status <rid>`.
+// Requests are verified with the signing secret of the Slack app. Command errors are answered
+// with status 200 and an ephemeral message, because Slack does not show the body of other status codes.
+func (m *ManagerHandler) SlackCommand(c *echo.Context) error {
+	if m.SlackSigningSecret == "" {
+		return c.String(http.StatusServiceUnavailable, "Slack integration is not configured")
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, 1<<20))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Failed to read request")
+	}
+
+	err = verifySlackSignature(m.SlackSigningSecret, c.Request().Header, body, time.Now())
+	if err != nil {
+		return c.String(http.StatusUnauthorized, err.Error())
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid request")
+	}
+
+	args, err := splitSlackArgs(form.Get("text"))
+	if err != nil {
+		return slackResponse(c, qmModel.SLACK_RESPONSE_EPHEMERAL, err.Error())
+	}
+	if len(args) == 0 {
+		return slackResponse(c, qmModel.SLACK_RESPONSE_EPHEMERAL, slackUsage)
+	}
+
+	switch args[0] {
+	case "run":
+		return m.slackRunJob(c, form.Get("user_name"), args[1:])
+	case "status":
+		return m.slackJobStatus(c, args[1:])
+	default:
+		return slackResponse(c, qmModel.SLACK_RESPONSE_EPHEMERAL, fmt.Sprintf("Unknown command `%s`. %s", args[0], slackUsage))
+	}
+}
+
+// slackRunJob adds a job with the key=value parameters, validated the same way as a job added from a form.
+func (m *ManagerHandler) slackRunJob(c *echo.Context, userName string, args []string) error {
+	if len(args) == 0 {
+		return slackResponse(c, qmModel.SLACK_RESPONSE_EPHEMERAL, "Missing task. "+slackUsage)
+	}
+
+	task, err := m.taskDB.SelectTaskByKey(args[0])
+	if err != nil {
+		return slackResponse(c, qmModel.SLACK_RESPONSE_EPHEMERAL, fmt.Sprintf("Task `%s` not found", args[0]))
+	}
+
+	parameters := url.Values{}
+	for _, arg := range args[1:] {
+		key, value, found := strings.Cut(arg, "=")
+		if !found || key == "" {
+			return slackResponse(c, qmModel.SLACK_RESPONSE_EPHEMERAL, fmt.Sprintf("Invalid parameter `%s` (must be key=value)", arg))
+		}
+		parameters.Add(key, value)
+	}
+
+	jobRequest, err := http.NewRequest(http.MethodPost, c.Request().URL.String(), strings.NewReader(parameters.Encode()))
+	if err != nil {
+		return slackResponse(c, qmModel.SLACK_RESPONSE_EPHEMERAL, fmt.Sprintf("Failed to read parameters: %v", err))
+	}
+	jobRequest.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+
+	parametersList, parametersKeyed, err := m.resolveJobParameters(c.Request().Context(), task, jobRequest, "slack:"+userName)
+	if errors.Is(err, errJobPayloadTooLarge) {
+		return slackResponse(c, qmModel.SLACK_RESPONSE_EPHEMERAL, err.Error())
+	} else if err != nil {
+		return slackResponse(c, qmModel.SLACK_RESPONSE_EPHEMERAL, fmt.Sprintf("Validation error: %v", err))
+	}
+
+	versionWarning, block, err := m.checkWorkerVersion(task)
+	if err != nil {
+		return slackResponse(c, qmModel.SLACK_RESPONSE_EPHEMERAL, fmt.Sprintf("Failed to check worker version: %v", err))
+	}
+	if block {
+		return slackResponse(c, qmModel.SLACK_RESPONSE_EPHEMERAL, versionWarning)
+	}
+
+	jobAdded, deduplicated, err := m.addTaskJob(task, parametersList, parametersKeyed)
+	if err != nil {
+		return slackResponse(c, qmModel.SLACK_RESPONSE_EPHEMERAL, fmt.Sprintf("Failed to add job: %v", err))
+	}
+	if deduplicated {
+		return slackResponse(c, qmModel.SLACK_RESPONSE_EPHEMERAL, fmt.Sprintf("Job `%s` of task `%s` with the same parameters was already added within the last %d minutes", jobAdded.RID.String(), task.Key, task.DedupWindowMinutes))
+	}
+	m.recordJobInitiator(jobAdded, "slack:"+userName, false)
+	m.registerJobChains(task, jobAdded, false)
+	m.publishEvent(qmModel.EVENT_JOB_ADDED, jobAdded)
+
+	text := fmt.Sprintf("Job `%s` of task `%s` added by %s", jobAdded.RID.String(), task.Key, userName)
+	if versionWarning != "" {
+		text += "\nWarning: " + versionWarning
+	}
+	return slackResponse(c, qmModel.SLACK_RESPONSE_IN_CHANNEL, text)
+}
+
+// slackJobStatus answers the status of an active or archived job.
+func (m *ManagerHandler) slackJobStatus(c *echo.Context, args []string) error {
+	if len(args) != 1 {
+		return slackResponse(c, qmModel.SLACK_RESPONSE_EPHEMERAL, "Missing job RID. "+slackUsage)
+	}
+
+	rid, err := uuid.Parse(args[0])
+	if err != nil {
+		return slackResponse(c, qmModel.SLACK_RESPONSE_EPHEMERAL, fmt.Sprintf("Invalid job RID `%s`", args[0]))
+	}
+
+	job, err := m.Queuer.GetJob(rid)
+	if err != nil {
+		job, err = m.Queuer.GetJobEnded(rid)
+		if err != nil {
+			return slackResponse(c, qmModel.SLACK_RESPONSE_EPHEMERAL, fmt.Sprintf("Job `%s` not found", rid.String()))
+		}
+	}
+
+	text := fmt.Sprintf("Job `%s` of task `%s` is *%s*", job.RID.String(), job.TaskName, job.Status)
+	if job.Status == model.JobStatusFailed {
+		text += fmt.Sprintf("\nError: %v", job.Error)
+	}
+	return slackResponse(c, qmModel.SLACK_RESPONSE_EPHEMERAL, text)
+}
+
+// verifySlackSignature checks the X-Slack-Signature of the request body, see
+// https://api.slack.com/authentication/verifying-requests-from-slack
+func verifySlackSignature(signingSecret string, header http.Header, body []byte, now time.Time) error {
+	timestampStr := header.Get("X-Slack-Request-Timestamp")
+	signature := header.Get("X-Slack-Signature")
+	if timestampStr == "" || signature == "" {
+		return fmt.Errorf("missing slack signature")
+	}
+
+	timestamp, err := strconv.ParseInt(timestampStr, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid slack request timestamp")
+	}
+	age := now.Sub(time.Unix(timestamp, 0))
+	if age > SLACK_MAX_REQUEST_AGE || age < -SLACK_MAX_REQUEST_AGE {
+		return fmt.Errorf("slack request timestamp too old")
+	}
+
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte("v0:" + timestampStr + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return fmt.Errorf("invalid slack signature")
+	}
+
+	return nil
+}
+
+// splitSlackArgs splits the command text at spaces, values can be quoted with double quotes
+// (eg. text="hello world"). Smart quotes inserted by the Slack client are treated as double quotes.
+func splitSlackArgs(text string) ([]string, error) {
+	text = strings.NewReplacer("“", `"`, "”", `"`).Replace(text)
+
+	args := []string{}
+	var current strings.Builder
+	inQuotes, hasArg := false, false
+	for _, r := range text {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			hasArg = true
+		case (r == ' ' || r == '\t' || r == '\n') && !inQuotes:
+			if hasArg {
+				args = append(args, current.String())
+				current.Reset()
+				hasArg = false
+			}
+		default:
+			current.WriteRune(r)
+			hasArg = true
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unclosed quote in command")
+	}
+	if hasArg {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
+
+func slackResponse(c *echo.Context, responseType string, text string) error {
+	return c.JSON(http.StatusOK, &qmModel.SlackResponse{ResponseType: responseType, Text: text})
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	vm "github.com/siherrmann/validator/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSlackRequest(t *testing.T, secret string, text string, timestamp time.Time) *http.Request {
+	t.Helper()
+
+	body := url.Values{"text": {text}, "user_name": {"tester"}}.Encode()
+	timestampStr := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestampStr + ":" + body))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/slack/command", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	req.Header.Set("X-Slack-Request-Timestamp", timestampStr)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func TestSlackCommandHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.SlackSigningSecret = "test-signing-secret"
+	e := echo.New()
+
+	_, err = tdb.InsertTask(&qmModel.Task{
+		Key:                  "test-slack-task",
+		Name:                 "Test Slack Task",
+		InputParameters:      []vm.Validation{},
+		InputParametersKeyed: []vm.Validation{{Key: "message", Type: vm.String, Requirement: "min1"}},
+	})
+	require.NoError(t, err)
+
+	var jobRID string
+	t.Run("Run adds a job", func(t *testing.T) {
+		req := newSlackRequest(t, "test-signing-secret", `run test-slack-task message="hello slack"`, time.Now())
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.SlackCommand(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response qmModel.SlackResponse
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, qmModel.SLACK_RESPONSE_IN_CHANNEL, response.ResponseType, response.Text)
+
+		args, err := splitSlackArgs(response.Text)
+		require.NoError(t, err)
+		jobRID = strings.Trim(args[1], "`")
+	})
+
+	t.Run("Status answers the job status", func(t *testing.T) {
+		require.NotEmpty(t, jobRID)
+
+		req := newSlackRequest(t, "test-signing-secret", "status "+jobRID, time.Now())
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.SlackCommand(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response qmModel.SlackResponse
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Contains(t, response.Text, jobRID)
+		assert.Contains(t, response.Text, "test-slack-task")
+	})
+
+	t.Run("Run with invalid parameter", func(t *testing.T) {
+		req := newSlackRequest(t, "test-signing-secret", "run test-slack-task message=", time.Now())
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.SlackCommand(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response qmModel.SlackResponse
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		require.NoError(t, err)
+		assert.Equal(t, qmModel.SLACK_RESPONSE_EPHEMERAL, response.ResponseType)
+		assert.Contains(t, response.Text, "Validation error")
+	})
+
+	t.Run("Invalid signature is rejected", func(t *testing.T) {
+		req := newSlackRequest(t, "wrong-secret", "status "+jobRID, time.Now())
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.SlackCommand(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("Old requests are rejected", func(t *testing.T) {
+		req := newSlackRequest(t, "test-signing-secret", "status "+jobRID, time.Now().Add(-10*time.Minute))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.SlackCommand(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("splitSlackArgs with quotes", func(t *testing.T) {
+		args, err := splitSlackArgs(`run task text=“hello world” count=2`)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"run", "task", "text=hello world", "count=2"}, args)
+
+		_, err = splitSlackArgs(`run task text="hello`)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/labstack/echo/v5"
+)
+
+// GetForecast forecasts the time to drain the queue and the projected backlog in one hour
+// from the job arrival and completion rates of the requested window
+func (m *ManagerHandler) GetForecast(c *echo.Context) error {
+	if m.MetricDB == nil {
+		return c.String(http.StatusServiceUnavailable, "Metrics are not enabled")
+	}
+
+	windowStr := c.QueryParam("window")
+
+	// Parse window with default
+	window := time.Hour
+	if windowStr != "" {
+		parsedWindow, err := time.ParseDuration(windowStr)
+		if err != nil || parsedWindow < time.Minute || parsedWindow > 24*time.Hour {
+			return c.String(http.StatusBadRequest, "Invalid window (must be a duration between 1m and 24h)")
+		}
+		window = parsedWindow
+	}
+
+	forecast, err := m.queueForecast(window)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to calculate forecast")
+	}
+
+	return c.JSON(http.StatusOK, forecast)
+}
+
+// GetCosts estimates the costs of the jobs that ended in the requested time range with the cost models of their tasks,
+// grouped by task, label or tenant. With format=csv the costs are exported as CSV file for chargeback.
+func (m *ManagerHandler) GetCosts(c *echo.Context) error {
+	if m.TaskCostDB == nil {
+		return c.String(http.StatusServiceUnavailable, "Cost accounting is not enabled")
+	}
+
+	sinceStr := c.QueryParam("since")
+	untilStr := c.QueryParam("until")
+	groupBy := c.QueryParam("groupBy")
+	format := c.QueryParam("format")
+
+	// Parse until with default
+	until := time.Now()
+	if untilStr != "" {
+		parsedUntil, err := time.Parse(time.RFC3339, untilStr)
+		if err != nil {
+			return c.String(http.StatusBadRequest, "Invalid until format (must be RFC3339)")
+		}
+		until = parsedUntil
+	}
+
+	// Parse since with default
+	since := until.AddDate(0, 0, -30)
+	if sinceStr != "" {
+		parsedSince, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			return c.String(http.StatusBadRequest, "Invalid since format (must be RFC3339)")
+		}
+		since = parsedSince
+	}
+
+	if since.After(until) {
+		return c.String(http.StatusBadRequest, "Invalid time range (since must be before until)")
+	}
+
+	if groupBy == "" {
+		groupBy = model.COST_GROUP_TASK
+	}
+	if groupBy != model.COST_GROUP_TASK && groupBy != model.COST_GROUP_LABEL && groupBy != model.COST_GROUP_TENANT {
+		return c.String(http.StatusBadRequest, "Invalid groupBy (must be task, label or tenant)")
+	}
+	if format != "" && format != "json" && format != "csv" {
+		return c.String(http.StatusBadRequest, "Invalid format (must be json or csv)")
+	}
+
+	jobCosts, err := m.TaskCostDB.SelectJobCosts(since, until, groupBy)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to calculate costs")
+	}
+
+	if format != "csv" {
+		return c.JSON(http.StatusOK, jobCosts)
+	}
+
+	filename := fmt.Sprintf("costs_by_%s_%s_%s.csv", groupBy, since.Format("20060102"), until.Format("20060102"))
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Response().Header().Set("Content-Type", "text/csv")
+	c.Response().WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(c.Response())
+	err = writer.Write([]string{groupBy, "jobs", "duration_seconds", "cost", "since", "until"})
+	if err != nil {
+		return err
+	}
+	for _, jobCost := range jobCosts {
+		err = writer.Write([]string{
+			jobCost.Group,
+			strconv.Itoa(jobCost.Jobs),
+			strconv.FormatFloat(jobCost.DurationSeconds, 'f', 3, 64),
+			strconv.FormatFloat(jobCost.Cost, 'f', 4, 64),
+			since.Format(time.RFC3339),
+			until.Format(time.RFC3339),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// queueForecast calculates the queue forecast from the job rates of the last window.
+func (m *ManagerHandler) queueForecast(window time.Duration) (*model.QueueForecast, error) {
+	rates, err := m.MetricDB.SelectJobRates(time.Now().Add(-window))
+	if err != nil {
+		return nil, err
+	}
+	return model.NewQueueForecast(rates, window), nil
+}
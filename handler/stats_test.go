@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	vm "github.com/siherrmann/validator/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetForecastHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("metricdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	mdb, err := database.NewMetricDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.MetricDB = mdb
+	e := echo.New()
+
+	t.Run("GetForecast with default window", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/stats/forecast", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetForecast(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var forecast qmModel.QueueForecast
+		err = json.Unmarshal(rec.Body.Bytes(), &forecast)
+		require.NoError(t, err)
+		assert.Equal(t, time.Hour, forecast.Window)
+		assert.GreaterOrEqual(t, forecast.ProjectedBacklog1h, 0)
+	})
+
+	t.Run("GetForecast with invalid window", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/stats/forecast?window=10s", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetForecast(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("GetForecast without metrics", func(t *testing.T) {
+		handlerWithoutMetrics := NewManagerHandler(fs, tdb, queue)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/stats/forecast", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handlerWithoutMetrics.GetForecast(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+}
+
+func TestGetCostsHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskcostdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	tcdb, err := database.NewTaskCostDBHandler(db, true)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.TaskCostDB = tcdb
+	e := echo.New()
+
+	task, err := tdb.InsertTask(&qmModel.Task{
+		Key:                  "test-cost-task",
+		Name:                 "Test Cost Task",
+		InputParameters:      []vm.Validation{},
+		InputParametersKeyed: []vm.Validation{},
+	})
+	require.NoError(t, err)
+
+	t.Run("PutTaskCost with valid cost model", func(t *testing.T) {
+		body := `{"model": "run", "rate": 1.5, "tenant": "acme"}`
+
+		req := httptest.NewRequest(http.MethodPut, "/api/task/putTaskCost/"+task.Key, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "key", Value: task.Key}})
+
+		err := handler.PutTaskCost(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var taskCost qmModel.TaskCost
+		err = json.Unmarshal(rec.Body.Bytes(), &taskCost)
+		require.NoError(t, err)
+		assert.Equal(t, task.Key, taskCost.TaskKey)
+		assert.Equal(t, "acme", taskCost.Tenant)
+	})
+
+	t.Run("PutTaskCost with invalid cost model", func(t *testing.T) {
+		body := `{"model": "hour", "rate": 1}`
+
+		req := httptest.NewRequest(http.MethodPut, "/api/task/putTaskCost/"+task.Key, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "key", Value: task.Key}})
+
+		err := handler.PutTaskCost(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("PutTaskCost of unknown task", func(t *testing.T) {
+		body := `{"model": "second", "rate": 0.01}`
+
+		req := httptest.NewRequest(http.MethodPut, "/api/task/putTaskCost/unknown-task", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "key", Value: "unknown-task"}})
+
+		err := handler.PutTaskCost(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("GetCosts as CSV", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/stats/costs?groupBy=tenant&format=csv", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetCosts(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get("Content-Disposition"), "costs_by_tenant")
+		assert.True(t, strings.HasPrefix(rec.Body.String(), "tenant,jobs,duration_seconds,cost,since,until\n"))
+	})
+
+	t.Run("GetCosts with invalid groupBy", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/stats/costs?groupBy=worker", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetCosts(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
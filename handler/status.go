@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/labstack/echo/v5"
+)
+
+// statusRecentErrors is the number of recent errors kept per subsystem.
+const statusRecentErrors = 10
+
+// statusCheckTimeout is the timeout of the checks of the database and storage of the status.
+const statusCheckTimeout = 5 * time.Second
+
+// StatusTracker keeps the last run and the recent errors of the subsystems running in the background.
+type StatusTracker struct {
+	mu         sync.Mutex
+	lastRun    map[string]time.Time
+	lastFailed map[string]bool
+	errors     map[string][]model.StatusError
+}
+
+// NewStatusTracker creates a new instance of StatusTracker.
+func NewStatusTracker() *StatusTracker {
+	return &StatusTracker{
+		lastRun:    map[string]time.Time{},
+		lastFailed: map[string]bool{},
+		errors:     map[string][]model.StatusError{},
+	}
+}
+
+// Record records a run of the subsystem, a non nil error is added to the recent errors of the subsystem.
+func (s *StatusTracker) Record(subsystem string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.lastRun[subsystem] = now
+	s.lastFailed[subsystem] = err != nil
+	if err != nil {
+		subsystemErrors := append(s.errors[subsystem], model.StatusError{Time: now, Message: err.Error()})
+		if len(subsystemErrors) > statusRecentErrors {
+			subsystemErrors = subsystemErrors[len(subsystemErrors)-statusRecentErrors:]
+		}
+		s.errors[subsystem] = subsystemErrors
+	}
+}
+
+// subsystemStatus returns the status of a tracked subsystem, it is degraded if its last run failed.
+// The recent errors are returned newest first.
+func (s *StatusTracker) subsystemStatus(subsystem string) *model.SubsystemStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subsystemStatus := &model.SubsystemStatus{
+		Name:         subsystem,
+		Status:       model.STATUS_OK,
+		Message:      "Running",
+		LastCheck:    s.lastRun[subsystem],
+		RecentErrors: []model.StatusError{},
+	}
+	for i := len(s.errors[subsystem]) - 1; i >= 0; i-- {
+		subsystemStatus.RecentErrors = append(subsystemStatus.RecentErrors, s.errors[subsystem][i])
+	}
+
+	if subsystemStatus.LastCheck.IsZero() {
+		subsystemStatus.Message = "No run yet"
+	} else if s.lastFailed[subsystem] {
+		subsystemStatus.Status = model.STATUS_DEGRADED
+		subsystemStatus.Message = "Last run failed"
+	}
+	return subsystemStatus
+}
+
+// =======API Handlers=======
+
+// GetStatus retrieves the health of the subsystems, it responds with 503 if a subsystem is down.
+func (m *ManagerHandler) GetStatus(c *echo.Context) error {
+	systemStatus := m.systemStatus(c.Request().Context())
+	return c.JSON(statusCode(systemStatus), systemStatus)
+}
+
+// =======View Handlers=======
+
+// StatusView renders the status page with the health of the subsystems
+func (m *ManagerHandler) StatusView(c *echo.Context) error {
+	systemStatus := m.systemStatus(c.Request().Context())
+
+	// The status page is shown without the history if it can not be loaded, it is also used by uptime checks
+	var history *model.HealthHistory
+	if m.HealthDB != nil {
+		var err error
+		history, err = m.healthHistory(time.Now())
+		if err != nil {
+			log.Printf("Failed to get health history: %v", err)
+		}
+	}
+
+	return render(c, screens.Status(systemStatus, history), statusCode(systemStatus))
+}
+
+// =======Helpers=======
+
+// statusCode returns 503 if the system is down, so the status page can be used by uptime checks.
+func statusCode(systemStatus *model.SystemStatus) int {
+	if systemStatus.Status == model.STATUS_DOWN {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+// systemStatus checks the database, the queuer master and the storage
+// and collects the status of the notifier and the scheduler from the status tracker.
+func (m *ManagerHandler) systemStatus(ctx context.Context) *model.SystemStatus {
+	return model.NewSystemStatus([]*model.SubsystemStatus{
+		m.databaseStatus(ctx),
+		m.masterStatus(),
+		m.storageStatus(),
+		m.notifierStatus(),
+		m.Status.subsystemStatus(model.SUBSYSTEM_SCHEDULER),
+	})
+}
+
+// databaseStatus pings the database of the queuer.
+func (m *ManagerHandler) databaseStatus(ctx context.Context) *model.SubsystemStatus {
+	if m.QueuerDB == nil {
+		m.Status.Record(model.SUBSYSTEM_DATABASE, fmt.Errorf("no database connection"))
+		return m.checkedStatus(model.SUBSYSTEM_DATABASE, model.STATUS_DOWN, "No database connection")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, statusCheckTimeout)
+	defer cancel()
+	err := m.QueuerDB.PingContext(ctx)
+	m.Status.Record(model.SUBSYSTEM_DATABASE, err)
+	if err != nil {
+		return m.checkedStatus(model.SUBSYSTEM_DATABASE, model.STATUS_DOWN, fmt.Sprintf("Ping failed: %v", err))
+	}
+
+	stats := m.QueuerDB.Stats()
+	return m.checkedStatus(model.SUBSYSTEM_DATABASE, model.STATUS_OK, fmt.Sprintf("%d open connections, %d in use", stats.OpenConnections, stats.InUse))
+}
+
+// masterStatus checks if a worker holds the master lock of the queuer and renewed it within the lock timeout.
+func (m *ManagerHandler) masterStatus() *model.SubsystemStatus {
+	if m.QueuerMasterDB == nil {
+		return m.checkedStatus(model.SUBSYSTEM_MASTER, model.STATUS_DISABLED, "Master check is not enabled")
+	}
+
+	master, err := m.QueuerMasterDB.SelectMaster()
+	m.Status.Record(model.SUBSYSTEM_MASTER, err)
+	if err != nil {
+		return m.checkedStatus(model.SUBSYSTEM_MASTER, model.STATUS_DOWN, fmt.Sprintf("Failed to select master: %v", err))
+	}
+	if master.WorkerID == 0 {
+		return m.checkedStatus(model.SUBSYSTEM_MASTER, model.STATUS_DOWN, "No worker is master")
+	}
+
+	lockTimeout := master.Settings.MasterLockTimeout
+	if lockTimeout <= 0 {
+		lockTimeout = time.Minute
+	}
+	if time.Since(master.UpdatedAt) > lockTimeout {
+		return m.checkedStatus(model.SUBSYSTEM_MASTER, model.STATUS_DEGRADED, fmt.Sprintf("Worker %s did not renew the master lock since %s", master.WorkerRID, master.UpdatedAt.Format(time.RFC3339)))
+	}
+
+	return m.checkedStatus(model.SUBSYSTEM_MASTER, model.STATUS_OK, fmt.Sprintf("Worker %s is master", master.WorkerRID))
+}
+
+// storageStatus lists the files of the storage backend.
+func (m *ManagerHandler) storageStatus() *model.SubsystemStatus {
+	if m.Filesystem == nil {
+		return m.checkedStatus(model.SUBSYSTEM_STORAGE, model.STATUS_DISABLED, "No storage configured")
+	}
+
+	files, err := m.Filesystem.ListFiles()
+	m.Status.Record(model.SUBSYSTEM_STORAGE, err)
+	if err != nil {
+		return m.checkedStatus(model.SUBSYSTEM_STORAGE, model.STATUS_DOWN, fmt.Sprintf("Failed to list files: %v", err))
+	}
+
+	return m.checkedStatus(model.SUBSYSTEM_STORAGE, model.STATUS_OK, fmt.Sprintf("%d files", len(files)))
+}
+
+// checkedStatus returns the status of a subsystem checked on request with the recent errors of the status tracker.
+func (m *ManagerHandler) checkedStatus(subsystem string, status string, message string) *model.SubsystemStatus {
+	subsystemStatus := m.Status.subsystemStatus(subsystem)
+	subsystemStatus.Status = status
+	subsystemStatus.Message = message
+	if status == model.STATUS_DISABLED {
+		subsystemStatus.LastCheck = time.Time{}
+	}
+	return subsystemStatus
+}
+
+// notifierStatus returns the status of the notifications of ended jobs.
+func (m *ManagerHandler) notifierStatus() *model.SubsystemStatus {
+	subsystemStatus := m.Status.subsystemStatus(model.SUBSYSTEM_NOTIFIER)
+	if m.Notifications == nil {
+		subsystemStatus.Status = model.STATUS_DISABLED
+		subsystemStatus.Message = "Notifications are not configured"
+	}
+	return subsystemStatus
+}
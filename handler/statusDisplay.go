@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/siherrmann/queuerManager/helper"
+	"github.com/siherrmann/queuerManager/model"
+)
+
+// ConfigureStatusDisplaysFromEnv sets the custom display names and colors of the statuses
+// from the JSON of QUEUER_MANAGER_STATUS_DISPLAY, eg. {"CANCELLED": {"name": "Aborted", "color": "orange"}}.
+// Without the variable all statuses keep their names and default colors.
+func ConfigureStatusDisplaysFromEnv() error {
+	displaysJSON := strings.TrimSpace(helper.GetEnvOrDefault("QUEUER_MANAGER_STATUS_DISPLAY", ""))
+	if displaysJSON == "" {
+		return model.SetStatusDisplays(nil)
+	}
+
+	displays := map[string]model.StatusDisplay{}
+	err := json.Unmarshal([]byte(displaysJSON), &displays)
+	if err != nil {
+		return fmt.Errorf("error parsing QUEUER_MANAGER_STATUS_DISPLAY: %w", err)
+	}
+
+	return model.SetStatusDisplays(displays)
+}
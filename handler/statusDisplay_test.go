@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/siherrmann/queuer/model"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/components"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigureStatusDisplaysFromEnv(t *testing.T) {
+	t.Cleanup(func() { require.NoError(t, qmModel.SetStatusDisplays(nil)) })
+
+	t.Run("Default status display", func(t *testing.T) {
+		t.Setenv("QUEUER_MANAGER_STATUS_DISPLAY", "")
+		require.NoError(t, ConfigureStatusDisplaysFromEnv())
+
+		assert.Equal(t, model.JobStatusCancelled, qmModel.StatusName(model.JobStatusCancelled))
+		assert.Equal(t, qmModel.STATUS_COLOR_RED, qmModel.StatusColor(model.JobStatusFailed))
+		assert.Equal(t, qmModel.STATUS_COLOR_GRAY, qmModel.StatusColor(model.JobStatusCancelled))
+	})
+
+	t.Run("Custom status display", func(t *testing.T) {
+		t.Setenv("QUEUER_MANAGER_STATUS_DISPLAY", `{"cancelled": {"name": "Aborted", "color": "Orange"}, "FAILED": {"name": "Broken"}}`)
+		require.NoError(t, ConfigureStatusDisplaysFromEnv())
+
+		assert.Equal(t, "Aborted", qmModel.StatusName(model.JobStatusCancelled))
+		assert.Equal(t, qmModel.STATUS_COLOR_ORANGE, qmModel.StatusColor(model.JobStatusCancelled))
+		assert.Contains(t, components.GetStatusClass(model.JobStatusCancelled), "bg-orange-100")
+		assert.Equal(t, "Broken", qmModel.StatusName(model.JobStatusFailed))
+		assert.Equal(t, qmModel.STATUS_COLOR_RED, qmModel.StatusColor(model.JobStatusFailed), "Expected the default color without custom color")
+
+		notification := qmModel.NewJobNotification("failures", &model.Job{Status: model.JobStatusFailed}, "")
+		assert.Equal(t, model.JobStatusFailed, notification.Status, "Expected the status of notifications to stay unchanged")
+		assert.Equal(t, "Broken", notification.StatusName)
+	})
+
+	t.Run("Invalid status display", func(t *testing.T) {
+		t.Setenv("QUEUER_MANAGER_STATUS_DISPLAY", `{"CANCELLED": {"color": "pink"}}`)
+		assert.ErrorContains(t, ConfigureStatusDisplaysFromEnv(), `invalid color "pink" of status CANCELLED`)
+
+		t.Setenv("QUEUER_MANAGER_STATUS_DISPLAY", `[1, 2]`)
+		assert.ErrorContains(t, ConfigureStatusDisplaysFromEnv(), "error parsing QUEUER_MANAGER_STATUS_DISPLAY")
+	})
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusTracker(t *testing.T) {
+	t.Run("Should report a subsystem without runs as ok", func(t *testing.T) {
+		tracker := NewStatusTracker()
+
+		subsystemStatus := tracker.subsystemStatus(model.SUBSYSTEM_SCHEDULER)
+		assert.Equal(t, model.STATUS_OK, subsystemStatus.Status)
+		assert.True(t, subsystemStatus.LastCheck.IsZero())
+		assert.Empty(t, subsystemStatus.RecentErrors)
+	})
+
+	t.Run("Should report a failed last run as degraded", func(t *testing.T) {
+		tracker := NewStatusTracker()
+		tracker.Record(model.SUBSYSTEM_SCHEDULER, errors.New("record metrics: connection refused"))
+
+		subsystemStatus := tracker.subsystemStatus(model.SUBSYSTEM_SCHEDULER)
+		assert.Equal(t, model.STATUS_DEGRADED, subsystemStatus.Status)
+		assert.False(t, subsystemStatus.LastCheck.IsZero())
+		require.Len(t, subsystemStatus.RecentErrors, 1)
+		assert.Equal(t, "record metrics: connection refused", subsystemStatus.RecentErrors[0].Message)
+
+		tracker.Record(model.SUBSYSTEM_SCHEDULER, nil)
+		subsystemStatus = tracker.subsystemStatus(model.SUBSYSTEM_SCHEDULER)
+		assert.Equal(t, model.STATUS_OK, subsystemStatus.Status)
+		assert.Len(t, subsystemStatus.RecentErrors, 1, "Expected the recent errors to be kept after a successful run")
+	})
+
+	t.Run("Should keep only the newest recent errors", func(t *testing.T) {
+		tracker := NewStatusTracker()
+		for i := 0; i < statusRecentErrors+5; i++ {
+			tracker.Record(model.SUBSYSTEM_NOTIFIER, fmt.Errorf("error %d", i))
+		}
+
+		subsystemStatus := tracker.subsystemStatus(model.SUBSYSTEM_NOTIFIER)
+		require.Len(t, subsystemStatus.RecentErrors, statusRecentErrors)
+		assert.Equal(t, fmt.Sprintf("error %d", statusRecentErrors+4), subsystemStatus.RecentErrors[0].Message)
+	})
+}
+
+func TestGetStatusHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	e := echo.New()
+
+	t.Run("Should return the status of all subsystems", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/status", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetStatus(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var systemStatus model.SystemStatus
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &systemStatus))
+		require.Len(t, systemStatus.Subsystems, 5)
+		assert.Equal(t, model.SUBSYSTEM_DATABASE, systemStatus.Subsystems[0].Name)
+		assert.Equal(t, model.STATUS_OK, systemStatus.Subsystems[0].Status)
+		assert.Equal(t, model.STATUS_DISABLED, systemStatus.Subsystems[1].Status, "Expected the master check to be disabled without database handler")
+		assert.Equal(t, model.STATUS_OK, systemStatus.Subsystems[2].Status)
+		assert.Equal(t, model.STATUS_DISABLED, systemStatus.Subsystems[3].Status, "Expected the notifier to be disabled without notifications")
+	})
+
+	t.Run("Should check the queuer master", func(t *testing.T) {
+		masterDb := helper.NewDatabaseWithDB("master", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+		queuerMasterDB, err := database.NewQueuerMasterDBHandler(masterDb)
+		require.NoError(t, err)
+		handler.QueuerMasterDB = queuerMasterDB
+		defer func() { handler.QueuerMasterDB = nil }()
+
+		subsystemStatus := handler.masterStatus()
+		assert.Equal(t, model.SUBSYSTEM_MASTER, subsystemStatus.Name)
+		assert.NotEqual(t, model.STATUS_DISABLED, subsystemStatus.Status)
+		assert.False(t, subsystemStatus.LastCheck.IsZero())
+	})
+}
+
+func TestStatusViewHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.Status.Record(model.SUBSYSTEM_SCHEDULER, errors.New("sync forwarded jobs: remote unavailable"))
+	e := echo.New()
+
+	t.Run("Should render the status page with recent errors", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/status", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.StatusView(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "System Status")
+		assert.Contains(t, rec.Body.String(), model.SUBSYSTEM_MASTER)
+		assert.Contains(t, rec.Body.String(), "sync forwarded jobs: remote unavailable")
+	})
+}
@@ -3,11 +3,16 @@ package handler
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
-	"strconv"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/components"
 	"github.com/siherrmann/queuerManager/view/screens"
 
 	"github.com/google/uuid"
@@ -15,17 +20,30 @@ import (
 	vm "github.com/siherrmann/validator/model"
 )
 
+// taskParameterPrefixes are the prefixes of the parameter rows of the task forms
+var taskParameterPrefixes = []string{"validations", "validations_keyed", "output_parameters"}
+
 // =======API Handlers=======
 
 // AddTask handles the addition of a new task
 func (m *ManagerHandler) AddTask(c *echo.Context) error {
 	var requestData struct {
-		Key              string `json:"key" form:"key"`
-		Name             string `json:"name" form:"name"`
-		Description      string `json:"description" form:"description"`
-		Validations      string `json:"validations" form:"validations"`
-		ValidationsKeyed string `json:"validations_keyed" form:"validations_keyed"`
-		OutputParameters string `json:"output_parameters" form:"output_parameters"`
+		Key                 string   `json:"key" form:"key"`
+		Name                string   `json:"name" form:"name"`
+		Description         string   `json:"description" form:"description"`
+		Validations         string   `json:"validations" form:"validations"`
+		ValidationsKeyed    string   `json:"validations_keyed" form:"validations_keyed"`
+		OutputParameters    string   `json:"output_parameters" form:"output_parameters"`
+		MinWorkerVersion    string   `json:"min_worker_version" form:"min_worker_version"`
+		WorkerVersionPolicy string   `json:"worker_version_policy" form:"worker_version_policy"`
+		Owner               string   `json:"owner" form:"owner"`
+		Namespace           string   `json:"namespace" form:"namespace"`
+		DedupWindowMinutes  int      `json:"dedup_window_minutes" form:"dedup_window_minutes"`
+		MaxConcurrency      int      `json:"max_concurrency" form:"max_concurrency"`
+		RateLimitPerMinute  int      `json:"rate_limit_per_minute" form:"rate_limit_per_minute"`
+		ParameterDocs       string   `json:"parameter_docs" form:"parameter_docs"`
+		OnSuccess           string   `json:"on_success" form:"on_success"`
+		Alerts              []string `json:"alerts" form:"alerts"`
 	}
 
 	if err := c.Bind(&requestData); err != nil {
@@ -35,33 +53,34 @@ func (m *ManagerHandler) AddTask(c *echo.Context) error {
 	if requestData.Key == "" {
 		return renderPopupOrJson(c, http.StatusBadRequest, "Task key is required")
 	}
+	if err := validateTaskKey(requestData.Key); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
 
 	if requestData.Name == "" {
 		return renderPopupOrJson(c, http.StatusBadRequest, "Task name is required")
 	}
 
-	// Parse validations JSON
-	var validations []vm.Validation
-	if requestData.Validations != "" {
-		if err := json.Unmarshal([]byte(requestData.Validations), &validations); err != nil {
-			return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid validations JSON: %v", err))
-		}
+	// Parse the parameter rows of the form or the validations JSON of API requests
+	validations, err := m.taskValidationsFromRequest(c, "validations", requestData.Validations, nil)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
 	}
-
-	// Parse validations_keyed JSON
-	var validationsKeyed []vm.Validation
-	if requestData.ValidationsKeyed != "" {
-		if err := json.Unmarshal([]byte(requestData.ValidationsKeyed), &validationsKeyed); err != nil {
-			return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid validations_keyed JSON: %v", err))
-		}
+	validationsKeyed, err := m.taskValidationsFromRequest(c, "validations_keyed", requestData.ValidationsKeyed, nil)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
 	}
-
-	// Parse output_parameters JSON
-	var outputParameters []vm.Validation
-	if requestData.OutputParameters != "" {
-		if err := json.Unmarshal([]byte(requestData.OutputParameters), &outputParameters); err != nil {
-			return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid output_parameters JSON: %v", err))
-		}
+	outputParameters, err := m.taskValidationsFromRequest(c, "output_parameters", requestData.OutputParameters, nil)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+	parameterDocs, err := taskParameterDocsFromRequest(c, requestData.ParameterDocs)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+	onSuccess, err := taskDependenciesFromRequest(requestData.OnSuccess)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
 	}
 
 	task := &model.Task{
@@ -71,13 +90,54 @@ func (m *ManagerHandler) AddTask(c *echo.Context) error {
 		InputParameters:      validations,
 		InputParametersKeyed: validationsKeyed,
 		OutputParameters:     outputParameters,
+		MinWorkerVersion:     requestData.MinWorkerVersion,
+		WorkerVersionPolicy:  requestData.WorkerVersionPolicy,
+		Owner:                requestData.Owner,
+		Namespace:            requestData.Namespace,
+		DedupWindowMinutes:   requestData.DedupWindowMinutes,
+		MaxConcurrency:       requestData.MaxConcurrency,
+		RateLimitPerMinute:   requestData.RateLimitPerMinute,
+		ParameterDocs:        parameterDocs,
+		OnSuccess:            onSuccess,
+		Alerts:               requestData.Alerts,
+	}
+
+	if err := validateWorkerVersionSettings(task); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+	if err := validateNamespace(task.Namespace); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+	if err := validateDedupWindow(task); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+	if err := validateTaskLimits(task); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+	if err := validateTaskAlerts(task); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+	if err := validateParameterDocs(task); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+	if err := m.validateTaskDependencies(task); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+	if err := m.runPreTaskSaveHooks(c.Request().Context(), task); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
 	}
 
 	insertedTask, err := m.taskDB.InsertTask(task)
 	if err != nil {
 		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to add task: %v", err))
 	}
+	m.runPostTaskSaveHooks(c.Request().Context(), insertedTask)
+	m.publishEvent(model.EVENT_TASK_ADDED, insertedTask)
 
+	// Add the row to the tasks table in place
+	if currentPath(c) == "/tasks" {
+		return renderRowsWithPopup(c, http.StatusCreated, screens.TaskRow(insertedTask), "#table_body_tasks_table", "afterbegin", "closeAddTask", "Task added successfully")
+	}
 	c.Response().Header().Add("HX-Redirect", "/tasks")
 
 	return renderPopupOrJson(c, http.StatusCreated, "Task added successfully", insertedTask)
@@ -95,49 +155,94 @@ func (m *ManagerHandler) UpdateTask(c *echo.Context) error {
 		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid task RID: %v", err))
 	}
 
+	// The parameter rows can not edit all settings of the validations, they are kept from the existing task
+	existingTask, err := m.taskDB.SelectTask(rid)
+	if err != nil {
+		existingTask = &model.Task{}
+	}
+
+	task, err := m.taskFromUpdateRequest(c, rid, existingTask)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err := m.runPreTaskSaveHooks(c.Request().Context(), task); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+
+	updatedTask, err := m.taskDB.UpdateTask(task)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to update task: %v", err))
+	}
+	m.runPostTaskSaveHooks(c.Request().Context(), updatedTask)
+	m.publishEvent(model.EVENT_TASK_UPDATED, updatedTask)
+
+	// Replace the row of the task in place, the task details are reloaded with the updated task
+	if currentPath(c) == "/tasks" {
+		return renderRowsWithPopup(c, http.StatusOK, screens.TaskRow(updatedTask), "#table_row_"+updatedTask.RID.String(), "outerHTML", "closeUpdateTask", "Task updated successfully")
+	}
+	c.Response().Header().Add("HX-Redirect", fmt.Sprintf("/task?rid=%s", updatedTask.RID.String()))
+
+	return renderPopupOrJson(c, http.StatusOK, "Task updated successfully", updatedTask)
+}
+
+// taskFromUpdateRequest returns the task of the form or JSON body of an update of the existing task.
+// The task is validated but the pre-save hooks are not run.
+func (m *ManagerHandler) taskFromUpdateRequest(c *echo.Context, rid uuid.UUID, existingTask *model.Task) (*model.Task, error) {
 	var requestData struct {
-		Key              string `json:"key" form:"key"`
-		Name             string `json:"name" form:"name"`
-		Description      string `json:"description" form:"description"`
-		Validations      string `json:"validations" form:"validations"`
-		ValidationsKeyed string `json:"validations_keyed" form:"validations_keyed"`
-		OutputParameters string `json:"output_parameters" form:"output_parameters"`
+		Key                 string   `json:"key" form:"key"`
+		Name                string   `json:"name" form:"name"`
+		Description         string   `json:"description" form:"description"`
+		Validations         string   `json:"validations" form:"validations"`
+		ValidationsKeyed    string   `json:"validations_keyed" form:"validations_keyed"`
+		OutputParameters    string   `json:"output_parameters" form:"output_parameters"`
+		MinWorkerVersion    string   `json:"min_worker_version" form:"min_worker_version"`
+		WorkerVersionPolicy string   `json:"worker_version_policy" form:"worker_version_policy"`
+		Owner               string   `json:"owner" form:"owner"`
+		Namespace           string   `json:"namespace" form:"namespace"`
+		DedupWindowMinutes  int      `json:"dedup_window_minutes" form:"dedup_window_minutes"`
+		MaxConcurrency      int      `json:"max_concurrency" form:"max_concurrency"`
+		RateLimitPerMinute  int      `json:"rate_limit_per_minute" form:"rate_limit_per_minute"`
+		ParameterDocs       string   `json:"parameter_docs" form:"parameter_docs"`
+		OnSuccess           string   `json:"on_success" form:"on_success"`
+		Alerts              []string `json:"alerts" form:"alerts"`
 	}
 
 	if err := c.Bind(&requestData); err != nil {
-		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return nil, fmt.Errorf("Invalid request: %v", err)
 	}
 
 	if requestData.Key == "" {
-		return renderPopupOrJson(c, http.StatusBadRequest, "Task key is required")
+		return nil, fmt.Errorf("Task key is required")
+	}
+	if err := validateTaskKey(requestData.Key); err != nil {
+		return nil, err
 	}
 
 	if requestData.Name == "" {
-		return renderPopupOrJson(c, http.StatusBadRequest, "Task name is required")
+		return nil, fmt.Errorf("Task name is required")
 	}
 
-	// Parse validations JSON
-	var validations []vm.Validation
-	if requestData.Validations != "" {
-		if err := json.Unmarshal([]byte(requestData.Validations), &validations); err != nil {
-			return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid validations JSON: %v", err))
-		}
+	// Parse the parameter rows of the form or the validations JSON of API requests
+	validations, err := m.taskValidationsFromRequest(c, "validations", requestData.Validations, existingTask.InputParameters)
+	if err != nil {
+		return nil, err
 	}
-
-	// Parse validations_keyed JSON
-	var validationsKeyed []vm.Validation
-	if requestData.ValidationsKeyed != "" {
-		if err := json.Unmarshal([]byte(requestData.ValidationsKeyed), &validationsKeyed); err != nil {
-			return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid validations_keyed JSON: %v", err))
-		}
+	validationsKeyed, err := m.taskValidationsFromRequest(c, "validations_keyed", requestData.ValidationsKeyed, existingTask.InputParametersKeyed)
+	if err != nil {
+		return nil, err
 	}
-
-	// Parse output_parameters JSON
-	var outputParameters []vm.Validation
-	if requestData.OutputParameters != "" {
-		if err := json.Unmarshal([]byte(requestData.OutputParameters), &outputParameters); err != nil {
-			return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid output_parameters JSON: %v", err))
-		}
+	outputParameters, err := m.taskValidationsFromRequest(c, "output_parameters", requestData.OutputParameters, existingTask.OutputParameters)
+	if err != nil {
+		return nil, err
+	}
+	parameterDocs, err := taskParameterDocsFromRequest(c, requestData.ParameterDocs)
+	if err != nil {
+		return nil, err
+	}
+	onSuccess, err := taskDependenciesFromRequest(requestData.OnSuccess)
+	if err != nil {
+		return nil, err
 	}
 
 	task := &model.Task{
@@ -148,16 +253,211 @@ func (m *ManagerHandler) UpdateTask(c *echo.Context) error {
 		InputParameters:      validations,
 		InputParametersKeyed: validationsKeyed,
 		OutputParameters:     outputParameters,
+		MinWorkerVersion:     requestData.MinWorkerVersion,
+		WorkerVersionPolicy:  requestData.WorkerVersionPolicy,
+		Owner:                requestData.Owner,
+		Namespace:            requestData.Namespace,
+		DedupWindowMinutes:   requestData.DedupWindowMinutes,
+		MaxConcurrency:       requestData.MaxConcurrency,
+		RateLimitPerMinute:   requestData.RateLimitPerMinute,
+		ParameterDocs:        parameterDocs,
+		OnSuccess:            onSuccess,
+		Alerts:               requestData.Alerts,
 	}
 
-	updatedTask, err := m.taskDB.UpdateTask(task)
+	if err := validateWorkerVersionSettings(task); err != nil {
+		return nil, err
+	}
+	if err := validateNamespace(task.Namespace); err != nil {
+		return nil, err
+	}
+	if err := validateDedupWindow(task); err != nil {
+		return nil, err
+	}
+	if err := validateTaskLimits(task); err != nil {
+		return nil, err
+	}
+	if err := validateTaskAlerts(task); err != nil {
+		return nil, err
+	}
+	if err := validateParameterDocs(task); err != nil {
+		return nil, err
+	}
+	if err := m.validateTaskDependencies(task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// taskValidationsFromRequest returns the validations of the parameter rows with the prefix of the task form.
+// API requests without parameter rows send the validations as JSON array in the field of the same name.
+// Settings the parameter rows can not edit are kept from the existing validations.
+func (m *ManagerHandler) taskValidationsFromRequest(c *echo.Context, prefix string, validationsJSON string, existing []vm.Validation) ([]vm.Validation, error) {
+	form, err := c.FormValues()
 	if err != nil {
-		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to update task: %v", err))
+		return nil, fmt.Errorf("Invalid form: %v", err)
 	}
 
-	c.Response().Header().Add("HX-Redirect", "/tasks")
+	validations, ok, err := m.validationsFromForm(form, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid %s: %v", prefix, err)
+	}
+	if ok {
+		return keepValidationDetails(validations, existing), nil
+	}
 
-	return renderPopupOrJson(c, http.StatusOK, "Task updated successfully", updatedTask)
+	if validationsJSON != "" {
+		if err := json.Unmarshal([]byte(validationsJSON), &validations); err != nil {
+			return nil, fmt.Errorf("Invalid %s JSON: %v", prefix, err)
+		}
+	}
+	return validations, nil
+}
+
+// taskParameterDocsFromRequest returns the docs of the description and example fields of the input parameter rows of the task form.
+// API requests without parameter rows send the docs as JSON object by parameter key in the parameter_docs field.
+func taskParameterDocsFromRequest(c *echo.Context, parameterDocsJSON string) (map[string]model.ParameterDoc, error) {
+	form, err := c.FormValues()
+	if err != nil {
+		return nil, fmt.Errorf("Invalid form: %v", err)
+	}
+
+	parameterDocs := map[string]model.ParameterDoc{}
+	hasRows := false
+	for _, prefix := range []string{"validations", "validations_keyed"} {
+		if _, ok := form[prefix+"_rows"]; !ok {
+			continue
+		}
+		hasRows = true
+
+		// Rows without doc fields, eg. of API requests, have no docs
+		keys := form[prefix+"_key"]
+		descriptions := form[prefix+"_description"]
+		examples := form[prefix+"_example"]
+		if len(descriptions) == 0 && len(examples) == 0 {
+			continue
+		}
+		if len(descriptions) != len(keys) || len(examples) != len(keys) {
+			return nil, fmt.Errorf("Invalid %s: incomplete parameter rows", prefix)
+		}
+		for i, key := range keys {
+			doc := model.ParameterDoc{
+				Description: strings.TrimSpace(descriptions[i]),
+				Example:     strings.TrimSpace(examples[i]),
+			}
+			if doc.Description != "" || doc.Example != "" {
+				parameterDocs[strings.TrimSpace(key)] = doc
+			}
+		}
+	}
+	if hasRows {
+		return parameterDocs, nil
+	}
+
+	if parameterDocsJSON != "" {
+		if err := json.Unmarshal([]byte(parameterDocsJSON), &parameterDocs); err != nil {
+			return nil, fmt.Errorf("Invalid parameter_docs JSON: %v", err)
+		}
+	}
+	return parameterDocs, nil
+}
+
+// validateParameterDocs checks that the parameter docs of the task only document input parameters of the task.
+func validateParameterDocs(task *model.Task) error {
+	for key := range task.ParameterDocs {
+		isInput := func(v vm.Validation) bool { return v.Key == key }
+		if !slices.ContainsFunc(task.InputParameters, isInput) && !slices.ContainsFunc(task.InputParametersKeyed, isInput) {
+			return fmt.Errorf("invalid parameter_docs: %q is no input parameter of the task", key)
+		}
+	}
+	return nil
+}
+
+// PutTask creates or updates the task with the key of the path, so it can be applied repeatedly.
+// The body is a task definition in the export format. The RID of an existing task is kept.
+// It responds with 201 if the task was created and 200 if it was updated.
+func (m *ManagerHandler) PutTask(c *echo.Context) error {
+	key := c.Param("key")
+	if key == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Task key is required"})
+	}
+
+	var definition model.TaskDefinition
+	if err := c.Bind(&definition); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("Invalid request: %v", err)})
+	}
+
+	if definition.Key != "" && definition.Key != key {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Task key of the body does not match the path"})
+	}
+	definition.Key = key
+	if err := validateTaskKey(key); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if definition.Name == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Task name is required"})
+	}
+
+	task := definition.ToTask()
+	if err := validateWorkerVersionSettings(task); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err := validateNamespace(task.Namespace); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err := validateDedupWindow(task); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err := validateTaskLimits(task); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err := validateTaskAlerts(task); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err := validateParameterDocs(task); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err := m.validateTaskDependencies(task); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err := m.runPreTaskSaveHooks(c.Request().Context(), task); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	upsertedTask, created, err := m.taskDB.UpsertTask(task)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to save task: %v", err)})
+	}
+	m.runPostTaskSaveHooks(c.Request().Context(), upsertedTask)
+
+	if created {
+		m.publishEvent(model.EVENT_TASK_ADDED, upsertedTask)
+		return c.JSON(http.StatusCreated, upsertedTask)
+	}
+	m.publishEvent(model.EVENT_TASK_UPDATED, upsertedTask)
+
+	return c.JSON(http.StatusOK, upsertedTask)
+}
+
+// DeleteTaskByKey deletes the task with the key of the path.
+// Deleting a task that does not exist also responds with 204, so it can be applied repeatedly.
+func (m *ManagerHandler) DeleteTaskByKey(c *echo.Context) error {
+	key := c.Param("key")
+	if key == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Task key is required"})
+	}
+
+	deleted, err := m.taskDB.DeleteTaskByKey(key)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to delete task: %v", err)})
+	}
+	if deleted {
+		m.publishEvent(model.EVENT_TASK_DELETED, map[string]any{"key": key})
+	}
+
+	return c.NoContent(http.StatusNoContent)
 }
 
 // DeleteTasks deletes multiple tasks by RIDs
@@ -182,6 +482,7 @@ func (m *ManagerHandler) DeleteTasks(c *echo.Context) error {
 			errors = append(errors, fmt.Sprintf("Failed to delete task %s: %v", ridStr, err))
 			continue
 		}
+		m.publishEvent(model.EVENT_TASK_DELETED, map[string]any{"rid": rid})
 		deletedCount++
 	}
 
@@ -226,37 +527,140 @@ func (m *ManagerHandler) GetTaskByName(c *echo.Context) error {
 	return c.JSON(http.StatusOK, task)
 }
 
-// GetTasks retrieves a paginated list of tasks
+// GetTaskByKey retrieves a specific task by key, eg. to import an existing task into a declarative configuration
+func (m *ManagerHandler) GetTaskByKey(c *echo.Context) error {
+	key := c.Param("key")
+	if key == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Task key is required"})
+	}
+
+	task, err := m.taskDB.SelectTaskByKey(key)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Task not found"})
+	}
+
+	return c.JSON(http.StatusOK, task)
+}
+
+// GetTasks retrieves a paginated list of tasks.
+// With parameter only the tasks with an input or output parameter of the key are retrieved,
+// the other filters and the sort are described at taskFilterFromQuery.
 func (m *ManagerHandler) GetTasks(c *echo.Context) error {
-	lastIdStr := c.QueryParam("lastId")
-	limitStr := c.QueryParam("limit")
-
-	// Parse lastId with default
-	lastId := 0
-	if lastIdStr != "" {
-		parsedLastId, err := strconv.Atoi(lastIdStr)
-		if err != nil || parsedLastId < 0 {
-			return c.String(http.StatusBadRequest, "Invalid lastId format")
-		}
-		lastId = parsedLastId
+	filter, err := taskFilterFromQuery(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
 	}
 
-	// Parse limit with default
-	limit := 10
-	if limitStr != "" {
-		parsedLimit, err := strconv.Atoi(limitStr)
-		if err != nil || parsedLimit <= 0 || parsedLimit > 100 {
-			return c.String(http.StatusBadRequest, "Invalid limit (must be 1-100)")
-		}
-		limit = parsedLimit
+	lastId, limit, err := m.apiPagination(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
 	}
 
-	tasks, err := m.taskDB.SelectAllTasks(lastId, limit)
+	envelope := withEnvelope(c)
+	var tasks []*model.Task
+	if filter.HasFilters() {
+		tasks, err = m.taskDB.SelectAllTasksByFilter(filter, lastId, pageLimit(limit, envelope))
+	} else {
+		tasks, err = m.taskDB.SelectAllTasks(lastId, pageLimit(limit, envelope))
+	}
 	if err != nil {
 		return c.String(http.StatusInternalServerError, "Failed to retrieve tasks")
 	}
+	if !envelope {
+		return c.JSON(http.StatusOK, tasks)
+	}
+
+	var total int
+	if filter.HasFilters() {
+		total, err = m.taskDB.SelectAllTasksByFilterCount(filter)
+	} else {
+		total, err = m.taskDB.SelectAllTasksCount()
+	}
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to count tasks")
+	}
+
+	return c.JSON(http.StatusOK, newPage(tasks, limit, total, func(task *model.Task) int { return task.ID }))
+}
+
+// taskFilterFromQuery parses the sort and filters of the task list from the query parameters parameter, keyPrefix,
+// withOutputParameters, createdAfter and createdBefore (RFC3339 or a date like 2026-01-31), sort and order (asc or desc),
+// the namespace is described at requestNamespace.
+func taskFilterFromQuery(c *echo.Context) (*model.TaskFilter, error) {
+	filter := &model.TaskFilter{
+		Namespace:            requestNamespace(c),
+		Parameter:            c.QueryParam("parameter"),
+		KeyPrefix:            c.QueryParam("keyPrefix"),
+		WithOutputParameters: c.QueryParam("withOutputParameters") == "true",
+		Sort:                 c.QueryParam("sort"),
+	}
+
+	if filter.Sort != "" && !slices.Contains(model.TASK_SORTS, filter.Sort) {
+		return nil, fmt.Errorf("Invalid sort (must be one of %s)", strings.Join(model.TASK_SORTS, ", "))
+	}
+
+	switch c.QueryParam("order") {
+	case "", "asc":
+	case "desc":
+		filter.Descending = true
+	default:
+		return nil, fmt.Errorf("Invalid order (must be asc or desc)")
+	}
+
+	var err error
+	filter.CreatedAfter, err = taskFilterTime(c.QueryParam("createdAfter"))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid createdAfter format (must be RFC3339 or YYYY-MM-DD)")
+	}
+	filter.CreatedBefore, err = taskFilterTime(c.QueryParam("createdBefore"))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid createdBefore format (must be RFC3339 or YYYY-MM-DD)")
+	}
+
+	if !filter.CreatedAfter.IsZero() && !filter.CreatedBefore.IsZero() && !filter.CreatedAfter.Before(filter.CreatedBefore) {
+		return nil, fmt.Errorf("Invalid time range (createdAfter must be before createdBefore)")
+	}
 
-	return c.JSON(http.StatusOK, tasks)
+	return filter, nil
+}
+
+// taskFilterTime parses a time of the task filters, a date is the start of the day in UTC.
+func taskFilterTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Parse(time.DateOnly, value)
+	}
+	return parsed, nil
+}
+
+// taskFilterQuery returns the query parameters of the sort and filters that are set, eg. to push the url.
+func taskFilterQuery(filter *model.TaskFilter) string {
+	query := ""
+	if filter.Parameter != "" {
+		query += "&parameter=" + url.QueryEscape(filter.Parameter)
+	}
+	if filter.KeyPrefix != "" {
+		query += "&keyPrefix=" + url.QueryEscape(filter.KeyPrefix)
+	}
+	if filter.WithOutputParameters {
+		query += "&withOutputParameters=true"
+	}
+	if !filter.CreatedAfter.IsZero() {
+		query += "&createdAfter=" + url.QueryEscape(filter.CreatedAfter.Format(time.RFC3339))
+	}
+	if !filter.CreatedBefore.IsZero() {
+		query += "&createdBefore=" + url.QueryEscape(filter.CreatedBefore.Format(time.RFC3339))
+	}
+	if filter.Sort != "" {
+		query += "&sort=" + filter.Sort
+	}
+	if filter.Descending {
+		query += "&order=desc"
+	}
+	return query
 }
 
 // =======View Handlers=======
@@ -284,36 +688,68 @@ func (m *ManagerHandler) TaskView(c *echo.Context) error {
 	return render(c, screens.Task(task))
 }
 
-// TasksView renders the tasks list view
+// TaskTabView renders a tab panel of the task view, the panels are loaded when their tab is opened.
+func (m *ManagerHandler) TaskTabView(c *echo.Context) error {
+	rid, err := uuid.Parse(c.QueryParam("rid"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid task RID: %v", err))
+	}
+
+	task, err := m.taskDB.SelectTask(rid)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "Task not found")
+	}
+
+	switch c.Param("tab") {
+	case "overview":
+		// The overview is shown without utilization if the jobs can not be counted
+		utilization, err := m.taskUtilization(task)
+		if err != nil {
+			log.Printf("Error getting utilization of task %s: %v", task.Key, err)
+		}
+		return render(c, screens.TaskOverview(task, utilization))
+	case "parameters":
+		return render(c, screens.TaskParameters(task))
+	case "dependencies":
+		upstreamTasks, err := m.taskDB.SelectUpstreamTasks(task.Key)
+		if err != nil {
+			return renderPopupOrJson(c, http.StatusInternalServerError, err.Error())
+		}
+		return render(c, screens.TaskDependencyGraph(task, upstreamTasks))
+	case "history":
+		return m.renderJobHistory(c, &model.JobArchiveFilter{TaskKey: task.Key})
+	case "raw":
+		return render(c, components.JsonCodeView(task))
+	default:
+		return renderPopupOrJson(c, http.StatusNotFound, "Tab not found")
+	}
+}
+
+// TasksView renders the tasks list view, the sort and filters take precedence over the search.
+// The search is limited to the namespace, so it is not overridden by the namespace alone.
 func (m *ManagerHandler) TasksView(c *echo.Context) error {
-	lastIdStr := c.QueryParam("lastId")
-	limitStr := c.QueryParam("limit")
 	search := c.QueryParam("search")
 
-	// Parse lastId with default
-	lastId := 0
-	if lastIdStr != "" {
-		parsedLastId, err := strconv.Atoi(lastIdStr)
-		if err != nil || parsedLastId < 0 {
-			return c.String(http.StatusBadRequest, "Invalid lastId format")
-		}
-		lastId = parsedLastId
+	filter, err := taskFilterFromQuery(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
 	}
 
-	// Parse limit with default
-	limit := 100
-	if limitStr != "" {
-		parsedLimit, err := strconv.Atoi(limitStr)
-		if err != nil || parsedLimit <= 0 || parsedLimit > 100 {
-			return c.String(http.StatusBadRequest, "Invalid limit (must be 1-100)")
-		}
-		limit = parsedLimit
+	lastId, limit, err := m.viewPagination(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
 	}
 
+	namespaceOnly := *filter == model.TaskFilter{Namespace: filter.Namespace}
+
 	var tasks []*model.Task
-	var err error
-	if search != "" {
-		tasks, err = m.taskDB.SelectAllTasksBySearch(search, lastId, limit)
+	if filter.HasFilters() && (search == "" || !namespaceOnly) {
+		tasks, err = m.taskDB.SelectAllTasksByFilter(filter, lastId, limit)
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to filter tasks")
+		}
+	} else if search != "" {
+		tasks, err = m.taskDB.SelectAllTasksBySearch(search, filter.Namespace, lastId, limit)
 		if err != nil {
 			return c.String(http.StatusInternalServerError, "Failed to search tasks")
 		}
@@ -325,17 +761,42 @@ func (m *ManagerHandler) TasksView(c *echo.Context) error {
 		}
 	}
 
-	c.Response().Header().Add("HX-Push-Url", fmt.Sprintf("/tasks?search=%s&limit=%d&lastId=%d", search, limit, lastId))
+	c.Response().Header().Add("HX-Push-Url", fmt.Sprintf("/tasks?search=%s&limit=%d&lastId=%d%s", search, limit, lastId, taskFilterQuery(filter)))
 	c.Response().Header().Add("HX-Retarget", "#body")
 
-	return render(c, screens.Tasks(tasks, search))
+	return renderStream(c, screens.Tasks(tasks, search, filter))
+}
+
+// TaskRowView renders the row of a task in the tasks table, so a single row can be refreshed in place
+func (m *ManagerHandler) TaskRowView(c *echo.Context) error {
+	rid, err := uuid.Parse(c.QueryParam("rid"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid task RID format")
+	}
+
+	task, err := m.taskDB.SelectTask(rid)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Task not found")
+	}
+
+	return render(c, screens.TaskRow(task))
+}
+
+// ParameterRowView renders an empty parameter row of the task forms, it is appended by the "Add Parameter" buttons
+func (m *ManagerHandler) ParameterRowView(c *echo.Context) error {
+	prefix := c.QueryParam("prefix")
+	if !slices.Contains(taskParameterPrefixes, prefix) {
+		return c.String(http.StatusBadRequest, "Invalid parameter prefix")
+	}
+
+	return render(c, screens.ParameterRow(prefix, vm.Validation{Type: vm.String}, model.ParameterDoc{}, m.validationTypes()))
 }
 
 // =======Popup Handlers=======
 
 // AddTaskPopupView renders the add task popup
 func (m *ManagerHandler) AddTaskPopupView(c *echo.Context) error {
-	return renderPopup(c, screens.AddTaskPopup())
+	return renderPopup(c, screens.AddTaskPopup(m.validationTypes()))
 }
 
 // UpdateTaskPopupView renders the update task popup
@@ -351,7 +812,7 @@ func (m *ManagerHandler) UpdateTaskPopupView(c *echo.Context) error {
 		return renderPopupOrJson(c, http.StatusNotFound, "Task not found")
 	}
 
-	return renderPopup(c, screens.UpdateTaskPopup(task))
+	return renderPopup(c, screens.UpdateTaskPopup(task, m.validationTypes()))
 }
 
 // DeleteTaskPopupView renders the delete task confirmation popup
@@ -371,14 +832,24 @@ func (m *ManagerHandler) ImportTaskPopupView(c *echo.Context) error {
 	return renderPopup(c, screens.ImportTaskPopup())
 }
 
-// ExportTask exports selected tasks as JSON array file
+// ExportTask exports selected tasks as JSON array file.
+// With `include=schedules` and/or `include=webhooks` the export is a pipeline object with the tasks
+// and the schedules and notification rules of the tasks, eg. `?rid=a&include=schedules&include=webhooks`.
 func (m *ManagerHandler) ExportTask(c *echo.Context) error {
 	ridStrings, ok := c.QueryParams()["rid"]
 	if len(ridStrings) == 0 || !ok {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing task RIDs"})
 	}
 
+	includes := c.QueryParams()["include"]
+	for _, include := range includes {
+		if include != PIPELINE_INCLUDE_SCHEDULES && include != PIPELINE_INCLUDE_WEBHOOKS {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid include (must be schedules or webhooks)"})
+		}
+	}
+
 	var exportTasks []map[string]interface{}
+	var taskKeys []string
 
 	for _, ridStr := range ridStrings {
 		rid, err := uuid.Parse(ridStr)
@@ -393,35 +864,86 @@ func (m *ManagerHandler) ExportTask(c *echo.Context) error {
 			continue
 		}
 
-		// Create a clean export without ID and timestamps
-		exportTask := map[string]interface{}{
-			"key":                    task.Key,
-			"name":                   task.Name,
-			"description":            task.Description,
-			"input_parameters":       task.InputParameters,
-			"input_parameters_keyed": task.InputParametersKeyed,
-			"output_parameters":      task.OutputParameters,
-		}
-		exportTasks = append(exportTasks, exportTask)
+		exportTasks = append(exportTasks, taskExport(task))
+		taskKeys = append(taskKeys, task.Key)
 	}
 
 	if len(exportTasks) == 0 {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": "No valid tasks found to export"})
 	}
 
-	jsonData, err := json.MarshalIndent(exportTasks, "", "  ")
+	var export interface{} = exportTasks
+	filename := "tasks_export.json"
+	if len(includes) > 0 {
+		pipeline := map[string]interface{}{"tasks": exportTasks}
+		if slices.Contains(includes, PIPELINE_INCLUDE_SCHEDULES) {
+			schedules, err := m.pipelineSchedules(taskKeys)
+			if err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get schedules"})
+			}
+			pipeline["schedules"] = schedules
+		}
+		if slices.Contains(includes, PIPELINE_INCLUDE_WEBHOOKS) {
+			pipeline["webhooks"] = m.pipelineWebhooks(taskKeys)
+		}
+		export = pipeline
+		filename = "pipeline_export.json"
+	}
+
+	jsonData, err := json.MarshalIndent(export, "", "  ")
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to marshal tasks"})
 	}
 
-	filename := "tasks_export.json"
 	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
 	c.Response().Header().Set("Content-Type", "application/json")
 
 	return c.Blob(http.StatusOK, "application/json", jsonData)
 }
 
-// ImportTask imports tasks from JSON array file
+// taskExport returns a clean export of the task without ID and timestamps, in the format of the task import
+func taskExport(task *model.Task) map[string]interface{} {
+	exportTask := map[string]interface{}{
+		"key":                    task.Key,
+		"name":                   task.Name,
+		"description":            task.Description,
+		"input_parameters":       task.InputParameters,
+		"input_parameters_keyed": task.InputParametersKeyed,
+		"output_parameters":      task.OutputParameters,
+	}
+	if task.Owner != "" {
+		exportTask["owner"] = task.Owner
+	}
+	if task.Namespace != "" {
+		exportTask["namespace"] = task.Namespace
+	}
+	if task.DedupWindowMinutes > 0 {
+		exportTask["dedup_window_minutes"] = task.DedupWindowMinutes
+	}
+	if task.MaxConcurrency > 0 {
+		exportTask["max_concurrency"] = task.MaxConcurrency
+	}
+	if task.RateLimitPerMinute > 0 {
+		exportTask["rate_limit_per_minute"] = task.RateLimitPerMinute
+	}
+	if len(task.ParameterDocs) > 0 {
+		exportTask["parameter_docs"] = task.ParameterDocs
+	}
+	if len(task.OnSuccess) > 0 {
+		exportTask["on_success"] = task.OnSuccess
+	}
+	if len(task.Alerts) > 0 {
+		exportTask["alerts"] = task.Alerts
+	}
+	if task.MinWorkerVersion != "" {
+		exportTask["min_worker_version"] = task.MinWorkerVersion
+		exportTask["worker_version_policy"] = task.WorkerVersionPolicy
+	}
+	return exportTask
+}
+
+// ImportTask imports tasks from JSON array file or a pipeline file with tasks, schedules and webhooks.
+// The schedules are added after the tasks, so they can reference the imported tasks.
 func (m *ManagerHandler) ImportTask(c *echo.Context) error {
 	file, err := c.FormFile("task_file")
 	if err != nil {
@@ -434,24 +956,22 @@ func (m *ManagerHandler) ImportTask(c *echo.Context) error {
 	}
 	defer src.Close()
 
-	var tasksData []struct {
-		Key                  string          `json:"key"`
-		Name                 string          `json:"name"`
-		Description          string          `json:"description"`
-		InputParameters      []vm.Validation `json:"input_parameters"`
-		InputParametersKeyed []vm.Validation `json:"input_parameters_keyed"`
-		OutputParameters     []vm.Validation `json:"output_parameters"`
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, "Failed to read file")
 	}
 
-	if err := json.NewDecoder(src).Decode(&tasksData); err != nil {
+	pipeline, err := decodeTaskImport(data)
+	if err != nil {
 		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid JSON format: %v", err))
 	}
 
-	if len(tasksData) == 0 {
+	tasksData := pipeline.Tasks
+	if len(tasksData) == 0 && len(pipeline.Schedules) == 0 && len(pipeline.Webhooks) == 0 {
 		return renderPopupOrJson(c, http.StatusBadRequest, "No tasks found in JSON file")
 	}
 
-	importedCount := 0
+	importedTasks := []*model.Task{}
 	var errors []string
 
 	for _, taskData := range tasksData {
@@ -460,29 +980,75 @@ func (m *ManagerHandler) ImportTask(c *echo.Context) error {
 			continue
 		}
 
-		task := &model.Task{
-			Key:                  taskData.Key,
-			Name:                 taskData.Name,
-			Description:          taskData.Description,
-			InputParameters:      taskData.InputParameters,
-			InputParametersKeyed: taskData.InputParametersKeyed,
-			OutputParameters:     taskData.OutputParameters,
+		task := taskData.ToTask()
+		if err := validateTaskKey(task.Key); err != nil {
+			errors = append(errors, fmt.Sprintf("Skipped task '%s': %v", taskData.Key, err))
+			continue
+		}
+		if err := validateWorkerVersionSettings(task); err != nil {
+			errors = append(errors, fmt.Sprintf("Skipped task '%s': %v", taskData.Key, err))
+			continue
+		}
+		if err := validateNamespace(task.Namespace); err != nil {
+			errors = append(errors, fmt.Sprintf("Skipped task '%s': %v", taskData.Key, err))
+			continue
+		}
+		if err := validateDedupWindow(task); err != nil {
+			errors = append(errors, fmt.Sprintf("Skipped task '%s': %v", taskData.Key, err))
+			continue
+		}
+		if err := validateTaskLimits(task); err != nil {
+			errors = append(errors, fmt.Sprintf("Skipped task '%s': %v", taskData.Key, err))
+			continue
+		}
+		if err := validateTaskAlerts(task); err != nil {
+			errors = append(errors, fmt.Sprintf("Skipped task '%s': %v", taskData.Key, err))
+			continue
+		}
+		if err := validateParameterDocs(task); err != nil {
+			errors = append(errors, fmt.Sprintf("Skipped task '%s': %v", taskData.Key, err))
+			continue
+		}
+		if err := m.validateTaskDependencies(task); err != nil {
+			errors = append(errors, fmt.Sprintf("Skipped task '%s': %v", taskData.Key, err))
+			continue
+		}
+		if err := m.runPreTaskSaveHooks(c.Request().Context(), task); err != nil {
+			errors = append(errors, err.Error())
+			continue
 		}
 
-		_, err := m.taskDB.InsertTask(task)
+		insertedTask, err := m.taskDB.InsertTask(task)
 		if err != nil {
 			errors = append(errors, fmt.Sprintf("Failed to import task '%s': %v", taskData.Key, err))
 			continue
 		}
-		importedCount++
+		m.runPostTaskSaveHooks(c.Request().Context(), insertedTask)
+		m.publishEvent(model.EVENT_TASK_ADDED, insertedTask)
+		importedTasks = append(importedTasks, insertedTask)
 	}
 
-	c.Response().Header().Add("HX-Redirect", "/tasks")
+	importedSchedules, scheduleErrors := m.importPipelineSchedules(c.Request().Context(), pipeline.Schedules, requestedBy(c))
+	errors = append(errors, scheduleErrors...)
+	errors = append(errors, m.checkPipelineWebhooks(pipeline.Webhooks)...)
+
+	imported := fmt.Sprintf("%d tasks", len(importedTasks))
+	if len(pipeline.Schedules) > 0 {
+		imported = fmt.Sprintf("%d tasks and %d schedules", len(importedTasks), importedSchedules)
+	}
 
+	status := http.StatusCreated
+	message := fmt.Sprintf("Successfully imported %s", imported)
 	if len(errors) > 0 {
-		errorMsg := fmt.Sprintf("Imported %d tasks with errors: %v", importedCount, errors)
-		return renderPopupOrJson(c, http.StatusPartialContent, errorMsg)
+		status = http.StatusPartialContent
+		message = fmt.Sprintf("Imported %s with errors: %v", imported, errors)
+	}
+
+	// Add the rows of the imported tasks to the tasks table in place
+	if currentPath(c) == "/tasks" {
+		return renderRowsWithPopup(c, status, screens.TaskRows(importedTasks), "#table_body_tasks_table", "afterbegin", "closeImportTasks", message)
 	}
+	c.Response().Header().Add("HX-Redirect", "/tasks")
 
-	return renderPopupOrJson(c, http.StatusCreated, fmt.Sprintf("Successfully imported %d tasks", importedCount))
+	return renderPopupOrJson(c, status, message)
 }
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/labstack/echo/v5"
+)
+
+// =======API Handlers=======
+
+// PutTaskCost creates or replaces the cost model of the task with the key of the path.
+func (m *ManagerHandler) PutTaskCost(c *echo.Context) error {
+	if m.TaskCostDB == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "Cost accounting is not enabled"})
+	}
+
+	key := c.Param("key")
+	if key == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Task key is required"})
+	}
+
+	var taskCost model.TaskCost
+	if err := c.Bind(&taskCost); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("Invalid request: %v", err)})
+	}
+	taskCost.TaskKey = key
+
+	if err := taskCost.Validate(); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	_, err := m.taskDB.SelectTaskByKey(key)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Task not found"})
+	}
+
+	upsertedTaskCost, err := m.TaskCostDB.UpsertTaskCost(&taskCost)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to save task cost: %v", err)})
+	}
+
+	return c.JSON(http.StatusOK, upsertedTaskCost)
+}
+
+// GetTaskCost retrieves the cost model of the task with the key of the path.
+func (m *ManagerHandler) GetTaskCost(c *echo.Context) error {
+	if m.TaskCostDB == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "Cost accounting is not enabled"})
+	}
+
+	taskCost, err := m.TaskCostDB.SelectTaskCost(c.Param("key"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Task cost not found"})
+	}
+
+	return c.JSON(http.StatusOK, taskCost)
+}
+
+// GetTaskCosts retrieves the cost models of all tasks.
+func (m *ManagerHandler) GetTaskCosts(c *echo.Context) error {
+	if m.TaskCostDB == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "Cost accounting is not enabled"})
+	}
+
+	taskCosts, err := m.TaskCostDB.SelectAllTaskCosts()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve task costs"})
+	}
+
+	return c.JSON(http.StatusOK, taskCosts)
+}
+
+// DeleteTaskCost deletes the cost model of the task with the key of the path, its jobs are not accounted anymore.
+// Deleting a cost model that does not exist also responds with 204.
+func (m *ManagerHandler) DeleteTaskCost(c *echo.Context) error {
+	if m.TaskCostDB == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "Cost accounting is not enabled"})
+	}
+
+	err := m.TaskCostDB.DeleteTaskCost(c.Param("key"))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to delete task cost: %v", err)})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	vm "github.com/siherrmann/validator/model"
+)
+
+// taskDependenciesFromRequest parses the on success dependencies JSON of the task form or API request.
+func taskDependenciesFromRequest(onSuccessJSON string) ([]model.TaskDependency, error) {
+	dependencies := []model.TaskDependency{}
+	if strings.TrimSpace(onSuccessJSON) == "" {
+		return dependencies, nil
+	}
+	if err := json.Unmarshal([]byte(onSuccessJSON), &dependencies); err != nil {
+		return nil, fmt.Errorf("Invalid on_success JSON: %v", err)
+	}
+	return dependencies, nil
+}
+
+// validateTaskDependencies checks the on success dependencies of the task.
+// The mapped outputs have to be output parameters of the task and a task is only chained once.
+// If the downstream task exists, the mapped inputs have to be its input parameters and
+// the downstream tasks must not lead back to the task, so chained jobs can not add each other endlessly.
+func (m *ManagerHandler) validateTaskDependencies(task *model.Task) error {
+	downstreamKeys := []string{}
+	for _, dependency := range task.OnSuccess {
+		if dependency.TaskKey == "" {
+			return fmt.Errorf("invalid on_success: task_key is required")
+		}
+		if dependency.TaskKey == task.Key {
+			return fmt.Errorf("invalid on_success: a task can not chain itself")
+		}
+		if slices.Contains(downstreamKeys, dependency.TaskKey) {
+			return fmt.Errorf("invalid on_success: task %q is chained more than once", dependency.TaskKey)
+		}
+		downstreamKeys = append(downstreamKeys, dependency.TaskKey)
+
+		// A downstream task that does not exist yet fails the chained jobs until it is added
+		downstreamTask, err := m.taskDB.SelectTaskByKey(dependency.TaskKey)
+		if err != nil {
+			downstreamTask = nil
+		}
+		for inputKey, outputKey := range dependency.ParameterMapping {
+			if !slices.ContainsFunc(task.OutputParameters, func(v vm.Validation) bool { return v.Key == outputKey }) {
+				return fmt.Errorf("invalid on_success: %q is no output parameter of the task", outputKey)
+			}
+			if downstreamTask == nil {
+				continue
+			}
+			isInput := func(v vm.Validation) bool { return v.Key == inputKey }
+			if !slices.ContainsFunc(downstreamTask.InputParameters, isInput) && !slices.ContainsFunc(downstreamTask.InputParametersKeyed, isInput) {
+				return fmt.Errorf("invalid on_success: %q is no input parameter of task %q", inputKey, dependency.TaskKey)
+			}
+		}
+	}
+
+	// Walk the existing downstream tasks, the task itself is checked with its new dependencies
+	visited := map[string]bool{task.Key: true}
+	queue := downstreamKeys
+	for len(queue) > 0 {
+		taskKey := queue[0]
+		queue = queue[1:]
+		if visited[taskKey] {
+			continue
+		}
+		visited[taskKey] = true
+
+		downstreamTask, err := m.taskDB.SelectTaskByKey(taskKey)
+		if err != nil {
+			continue
+		}
+		for _, dependency := range downstreamTask.OnSuccess {
+			if dependency.TaskKey == task.Key {
+				return fmt.Errorf("invalid on_success: task %q chains back to the task", taskKey)
+			}
+			queue = append(queue, dependency.TaskKey)
+		}
+	}
+
+	return nil
+}
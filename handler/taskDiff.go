@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	vm "github.com/siherrmann/validator/model"
+)
+
+// taskDiffMaxCells is the max product of the line counts of two values diffed line by line,
+// larger values are shown as all lines removed and added.
+const taskDiffMaxCells = 1000000
+
+// taskDiffFields returns the values of the fields of a task compared by the task diff in the order of the update form.
+func taskDiffFields(task *model.Task) []model.KeyValuePair {
+	return []model.KeyValuePair{
+		{Key: "key", Value: taskDiffValue(task.Key)},
+		{Key: "name", Value: taskDiffValue(task.Name)},
+		{Key: "owner", Value: taskDiffValue(task.Owner)},
+		{Key: "namespace", Value: taskDiffValue(task.Namespace)},
+		{Key: "dedup_window_minutes", Value: taskDiffValue(task.DedupWindowMinutes)},
+		{Key: "max_concurrency", Value: taskDiffValue(task.MaxConcurrency)},
+		{Key: "rate_limit_per_minute", Value: taskDiffValue(task.RateLimitPerMinute)},
+		{Key: "description", Value: taskDiffValue(task.Description)},
+		{Key: "input_parameters", Value: taskDiffValue(task.InputParameters)},
+		{Key: "input_parameters_keyed", Value: taskDiffValue(task.InputParametersKeyed)},
+		{Key: "output_parameters", Value: taskDiffValue(task.OutputParameters)},
+		{Key: "parameter_docs", Value: taskDiffValue(task.ParameterDocs)},
+		{Key: "on_success", Value: taskDiffValue(task.OnSuccess)},
+		{Key: "alerts", Value: taskDiffValue(task.Alerts)},
+		{Key: "min_worker_version", Value: taskDiffValue(task.MinWorkerVersion)},
+		{Key: "worker_version_policy", Value: taskDiffValue(task.WorkerVersionPolicy)},
+	}
+}
+
+// taskDiffValue returns a value of a task field for the diff, strings as they are and other values as indented JSON.
+// Empty lists and maps are empty like missing ones, so they do not show up as changes.
+func taskDiffValue(value any) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+
+	valueJSON, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	switch string(valueJSON) {
+	case "null", "[]", "{}":
+		return ""
+	}
+	return string(valueJSON)
+}
+
+// diffLines returns the side-by-side diff of the lines of two values by their longest common subsequence.
+// Removed lines followed by added lines are paired as changed rows.
+func diffLines(before string, after string) []model.TaskDiffLine {
+	beforeLines, afterLines := splitDiffLines(before), splitDiffLines(after)
+	n, m := len(beforeLines), len(afterLines)
+
+	// Operations of the diff, ' ' for unchanged, '-' for removed and '+' for added lines
+	ops := []byte{}
+	if n*m > taskDiffMaxCells {
+		ops = append([]byte(strings.Repeat("-", n)), strings.Repeat("+", m)...)
+	} else {
+		lcs := make([][]int, n+1)
+		for i := range lcs {
+			lcs[i] = make([]int, m+1)
+		}
+		for i := n - 1; i >= 0; i-- {
+			for j := m - 1; j >= 0; j-- {
+				if beforeLines[i] == afterLines[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+
+		i, j := 0, 0
+		for i < n || j < m {
+			switch {
+			case i < n && j < m && beforeLines[i] == afterLines[j]:
+				ops = append(ops, ' ')
+				i++
+				j++
+			case j < m && (i == n || lcs[i][j+1] >= lcs[i+1][j]):
+				ops = append(ops, '+')
+				j++
+			default:
+				ops = append(ops, '-')
+				i++
+			}
+		}
+	}
+
+	lines := []model.TaskDiffLine{}
+	i, j := 0, 0
+	for k := 0; k < len(ops); {
+		if ops[k] == ' ' {
+			lines = append(lines, model.TaskDiffLine{Before: beforeLines[i], After: afterLines[j]})
+			i++
+			j++
+			k++
+			continue
+		}
+
+		removed, added := []string{}, []string{}
+		for ; k < len(ops) && ops[k] != ' '; k++ {
+			if ops[k] == '-' {
+				removed = append(removed, beforeLines[i])
+				i++
+			} else {
+				added = append(added, afterLines[j])
+				j++
+			}
+		}
+		for r := 0; r < max(len(removed), len(added)); r++ {
+			switch {
+			case r < len(removed) && r < len(added):
+				lines = append(lines, model.TaskDiffLine{Before: removed[r], After: added[r], Change: model.CATALOG_CHANGE_CHANGED})
+			case r < len(removed):
+				lines = append(lines, model.TaskDiffLine{Before: removed[r], Change: model.CATALOG_CHANGE_REMOVED})
+			default:
+				lines = append(lines, model.TaskDiffLine{After: added[r], Change: model.CATALOG_CHANGE_ADDED})
+			}
+		}
+	}
+
+	return lines
+}
+
+// splitDiffLines returns the lines of a value, an empty value has no lines.
+func splitDiffLines(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(strings.ReplaceAll(value, "\r\n", "\n"), "\n")
+}
+
+// taskParameterWarnings returns the warnings of removed parameters and of parameters with a changed type.
+func taskParameterWarnings(kind string, before []vm.Validation, after []vm.Validation) []string {
+	afterByKey := map[string]vm.Validation{}
+	for _, validation := range after {
+		afterByKey[validation.Key] = validation
+	}
+
+	warnings := []string{}
+	for _, validation := range before {
+		afterValidation, ok := afterByKey[validation.Key]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("The %s %s is removed", kind, validation.Key))
+			continue
+		}
+		if afterValidation.Type != validation.Type {
+			warnings = append(warnings, fmt.Sprintf("The type of the %s %s changes from %s to %s", kind, validation.Key, validation.Type, afterValidation.Type))
+		}
+	}
+	return warnings
+}
+
+// diffTask returns the changes from the current to the proposed definition of a task with the warnings of destructive changes.
+func diffTask(current *model.Task, proposed *model.Task) *model.TaskDiff {
+	diff := &model.TaskDiff{
+		RID:      current.RID,
+		Key:      current.Key,
+		Fields:   []*model.TaskFieldDiff{},
+		Warnings: []string{},
+	}
+
+	proposedFields := taskDiffFields(proposed)
+	for i, field := range taskDiffFields(current) {
+		before, after := field.Value, proposedFields[i].Value
+		if before == after {
+			continue
+		}
+		diff.Fields = append(diff.Fields, &model.TaskFieldDiff{
+			Field:  field.Key,
+			Before: before,
+			After:  after,
+			Lines:  diffLines(before, after),
+		})
+	}
+
+	if current.Key != proposed.Key {
+		diff.Warnings = append(diff.Warnings, fmt.Sprintf("The key changes from %s to %s, workers, schedules and job templates of the task refer to it by its key", current.Key, proposed.Key))
+	}
+	diff.Warnings = append(diff.Warnings, taskParameterWarnings("input parameter", current.InputParameters, proposed.InputParameters)...)
+	diff.Warnings = append(diff.Warnings, taskParameterWarnings("keyed input parameter", current.InputParametersKeyed, proposed.InputParametersKeyed)...)
+	diff.Warnings = append(diff.Warnings, taskParameterWarnings("output parameter", current.OutputParameters, proposed.OutputParameters)...)
+
+	return diff
+}
+
+// =======API Handlers=======
+
+// DiffTask returns the changes an update of a task would make without saving it, eg. /api/task/diffTask?rid=...
+// The body is the same as of UpdateTask, requests of the update popup get the side-by-side diff rendered.
+func (m *ManagerHandler) DiffTask(c *echo.Context) error {
+	rid, err := uuid.Parse(c.QueryParam("rid"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid task RID: %v", err))
+	}
+
+	current, err := m.taskDB.SelectTask(rid)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "Task not found")
+	}
+
+	proposed, err := m.taskFromUpdateRequest(c, rid, current)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+
+	diff := diffTask(current, proposed)
+	if c.Request().Header.Get("HX-Request") == "" {
+		return c.JSON(http.StatusOK, diff)
+	}
+
+	return render(c, screens.TaskDiff(diff))
+}
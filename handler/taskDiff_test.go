@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	vm "github.com/siherrmann/validator/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffLines(t *testing.T) {
+	lines := diffLines("a\nb\nc\nd", "a\nx\nc\nd\ne")
+	assert.Equal(t, []qmModel.TaskDiffLine{
+		{Before: "a", After: "a"},
+		{Before: "b", After: "x", Change: qmModel.CATALOG_CHANGE_CHANGED},
+		{Before: "c", After: "c"},
+		{Before: "d", After: "d"},
+		{After: "e", Change: qmModel.CATALOG_CHANGE_ADDED},
+	}, lines)
+
+	lines = diffLines("a\nb", "")
+	assert.Equal(t, []qmModel.TaskDiffLine{
+		{Before: "a", Change: qmModel.CATALOG_CHANGE_REMOVED},
+		{Before: "b", Change: qmModel.CATALOG_CHANGE_REMOVED},
+	}, lines)
+}
+
+func TestDiffTask(t *testing.T) {
+	current := &qmModel.Task{
+		Key:  "diff-task",
+		Name: "Diff Task",
+		InputParameters: []vm.Validation{
+			{Key: "count", Type: vm.Int, Requirement: "min1"},
+			{Key: "name", Type: vm.String, Requirement: "-"},
+		},
+	}
+	proposed := &qmModel.Task{
+		Key:             "diff-task-renamed",
+		Name:            "Diff Task",
+		InputParameters: []vm.Validation{{Key: "count", Type: vm.String, Requirement: "min1"}},
+	}
+
+	diff := diffTask(current, proposed)
+	require.Len(t, diff.Fields, 2, "Expected only the changed fields")
+	assert.Equal(t, "key", diff.Fields[0].Field)
+	assert.Equal(t, "input_parameters", diff.Fields[1].Field)
+	assert.Contains(t, diff.Fields[1].Before, `"Key": "name"`, "Expected the parameters as indented JSON")
+	assert.Len(t, diff.Warnings, 3, "Expected warnings for the key, the removed parameter and the changed type")
+
+	diff = diffTask(current, current)
+	assert.Empty(t, diff.Fields)
+	assert.Empty(t, diff.Warnings)
+}
+
+func TestDiffTaskHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	e := echo.New()
+
+	task, err := tdb.InsertTask(&qmModel.Task{
+		Key:             "test-diff-task",
+		Name:            "Original Name",
+		InputParameters: []vm.Validation{{Key: "count", Type: vm.Int, Requirement: "min1"}},
+	})
+	require.NoError(t, err)
+
+	t.Run("DiffTask returns the changes without saving them", func(t *testing.T) {
+		form := url.Values{"key": {"test-diff-task"}, "name": {"Updated Name"}, "validations_rows": {"true"}}
+
+		req := httptest.NewRequest(http.MethodPost, "/api/task/diffTask?rid="+task.RID.String(), strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.DiffTask(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var diff qmModel.TaskDiff
+		err = json.Unmarshal(rec.Body.Bytes(), &diff)
+		require.NoError(t, err)
+		require.Len(t, diff.Fields, 2)
+		assert.Equal(t, "name", diff.Fields[0].Field)
+		assert.Equal(t, "input_parameters", diff.Fields[1].Field)
+		assert.Equal(t, []string{"The input parameter count is removed"}, diff.Warnings)
+
+		unchangedTask, err := tdb.SelectTask(task.RID)
+		require.NoError(t, err)
+		assert.Equal(t, "Original Name", unchangedTask.Name, "Expected the task to not be updated")
+	})
+
+	t.Run("DiffTask renders the side-by-side diff for the popup", func(t *testing.T) {
+		form := url.Values{"key": {"test-diff-task"}, "name": {"Updated Name"}}
+
+		req := httptest.NewRequest(http.MethodPost, "/api/task/diffTask?rid="+task.RID.String(), strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		req.Header.Set("HX-Request", "true")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.DiffTask(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Original Name")
+		assert.Contains(t, rec.Body.String(), "Updated Name")
+	})
+
+	t.Run("DiffTask with unknown task", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/task/diffTask?rid=00000000-0000-0000-0000-000000000001", strings.NewReader("key=x&name=y"))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.DiffTask(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
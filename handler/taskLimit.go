@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/labstack/echo/v5"
+)
+
+var errTaskLimitExceeded = errors.New("task limit exceeded")
+
+// validateTaskLimits checks the max concurrency and the rate limit per minute of a task.
+func validateTaskLimits(task *model.Task) error {
+	if task.MaxConcurrency < 0 || task.MaxConcurrency > model.MAX_TASK_LIMIT {
+		return fmt.Errorf("invalid max_concurrency (must be between 0 and %d)", model.MAX_TASK_LIMIT)
+	}
+	if task.RateLimitPerMinute < 0 || task.RateLimitPerMinute > model.MAX_TASK_LIMIT {
+		return fmt.Errorf("invalid rate_limit_per_minute (must be between 0 and %d)", model.MAX_TASK_LIMIT)
+	}
+	return nil
+}
+
+// taskUtilization returns the running, active and added jobs of the task with its limits.
+func (m *ManagerHandler) taskUtilization(task *model.Task) (*model.TaskUtilization, error) {
+	if m.JobDB == nil {
+		return nil, fmt.Errorf("job database is not initialized")
+	}
+
+	utilization, err := m.JobDB.SelectTaskUtilization(task.Key, time.Now().Add(-model.TASK_RATE_LIMIT_WINDOW))
+	if err != nil {
+		return nil, err
+	}
+	utilization.MaxConcurrency = task.MaxConcurrency
+	utilization.RateLimitPerMinute = task.RateLimitPerMinute
+	return utilization, nil
+}
+
+// checkTaskLimits returns errTaskLimitExceeded if adding the jobs would exceed the max concurrency
+// or the rate limit per minute of the task. If the jobs can not be counted adding them is allowed.
+func (m *ManagerHandler) checkTaskLimits(task *model.Task, jobs int) error {
+	if (task.MaxConcurrency <= 0 && task.RateLimitPerMinute <= 0) || m.JobDB == nil {
+		return nil
+	}
+
+	utilization, err := m.taskUtilization(task)
+	if err != nil {
+		log.Printf("Error counting jobs of task %s for its limits: %v", task.Key, err)
+		return nil
+	}
+
+	if utilization.ConcurrencyExceeded(jobs) {
+		return fmt.Errorf("%w: task %s has %d of %d jobs queued or running", errTaskLimitExceeded, task.Key, utilization.Active, task.MaxConcurrency)
+	}
+	if utilization.RateLimitExceeded(jobs) {
+		return fmt.Errorf("%w: task %s added %d of %d jobs in the last minute", errTaskLimitExceeded, task.Key, utilization.Added, task.RateLimitPerMinute)
+	}
+	return nil
+}
+
+// addJobErrorStatus returns the status of an error adding a job, 429 with Retry-After if a limit of the task is exceeded.
+func addJobErrorStatus(c *echo.Context, err error) int {
+	if errors.Is(err, errTaskLimitExceeded) {
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(model.TASK_RATE_LIMIT_WINDOW.Seconds())))
+		return http.StatusTooManyRequests
+	}
+	return http.StatusInternalServerError
+}
+
+// GetTaskUtilization returns the running, active and added jobs of the task by its key with its limits.
+func (m *ManagerHandler) GetTaskUtilization(c *echo.Context) error {
+	task, err := m.taskDB.SelectTaskByKey(c.Param("key"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Task not found"})
+	}
+
+	utilization, err := m.taskUtilization(task)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to get task utilization: %v", err)})
+	}
+
+	return c.JSON(http.StatusOK, utilization)
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	vm "github.com/siherrmann/validator/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTaskLimits(t *testing.T) {
+	assert.NoError(t, validateTaskLimits(&qmModel.Task{}))
+	assert.NoError(t, validateTaskLimits(&qmModel.Task{MaxConcurrency: 5, RateLimitPerMinute: 60}))
+	assert.Error(t, validateTaskLimits(&qmModel.Task{MaxConcurrency: -1}))
+	assert.Error(t, validateTaskLimits(&qmModel.Task{RateLimitPerMinute: qmModel.MAX_TASK_LIMIT + 1}))
+}
+
+func TestCheckTaskLimitsWithoutJobDatabase(t *testing.T) {
+	m := &ManagerHandler{}
+	err := m.checkTaskLimits(&qmModel.Task{Key: "limited", MaxConcurrency: 1, RateLimitPerMinute: 1}, 1)
+	assert.NoError(t, err, "Expected jobs to be allowed if they can not be counted")
+}
+
+func TestAddJobErrorStatus(t *testing.T) {
+	e := echo.New()
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), rec)
+	status := addJobErrorStatus(c, fmt.Errorf("%w: task limited added 5 of 5 jobs in the last minute", errTaskLimitExceeded))
+	assert.Equal(t, http.StatusTooManyRequests, status)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), rec)
+	status = addJobErrorStatus(c, fmt.Errorf("connection refused"))
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Empty(t, rec.Header().Get("Retry-After"))
+}
+
+func TestTaskLimitHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	jdb, err := database.NewJobDBHandler(db)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.JobDB = jdb
+	e := echo.New()
+
+	task, err := tdb.InsertTask(&qmModel.Task{
+		Key:                  "test-limited-task",
+		Name:                 "Test Limited Task",
+		InputParameters:      []vm.Validation{{Key: "count", Type: vm.Int, Requirement: "min1"}},
+		InputParametersKeyed: []vm.Validation{},
+		RateLimitPerMinute:   2,
+	})
+	require.NoError(t, err)
+
+	createJob := func(count int) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"task_key": "%s", "parameters": {"count": %d}}`, task.Key, count)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		err := handler.CreateJob(e.NewContext(req, rec))
+		require.NoError(t, err)
+		return rec
+	}
+
+	t.Run("Jobs above the rate limit are rejected", func(t *testing.T) {
+		assert.Equal(t, http.StatusCreated, createJob(1).Code)
+		assert.Equal(t, http.StatusCreated, createJob(2).Code)
+
+		rec := createJob(3)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+		assert.Contains(t, rec.Body.String(), "2 of 2 jobs in the last minute")
+	})
+
+	t.Run("GetTaskUtilization", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/task/getTaskUtilization/"+task.Key, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "key", Value: task.Key}})
+
+		err := handler.GetTaskUtilization(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		utilization := &qmModel.TaskUtilization{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), utilization))
+		assert.Equal(t, 2, utilization.Added)
+		assert.Equal(t, 2, utilization.RateLimitPerMinute)
+	})
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/notify"
+)
+
+// Sections of the task export that can be included with the include query parameter
+const (
+	PIPELINE_INCLUDE_SCHEDULES = "schedules"
+	PIPELINE_INCLUDE_WEBHOOKS  = "webhooks"
+)
+
+// decodeTaskImport decodes a task import file, either a JSON array of tasks or a pipeline object with tasks, schedules and webhooks.
+func decodeTaskImport(data []byte) (*model.PipelineDefinition, error) {
+	pipeline := &model.PipelineDefinition{}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		err := json.Unmarshal(data, &pipeline.Tasks)
+		return pipeline, err
+	}
+
+	err := json.Unmarshal(data, pipeline)
+	return pipeline, err
+}
+
+// pipelineSchedules returns the definitions of the schedules of the tasks with the keys.
+func (m *ManagerHandler) pipelineSchedules(taskKeys []string) ([]model.ScheduleDefinition, error) {
+	definitions := []model.ScheduleDefinition{}
+	if m.ScheduleDB == nil {
+		return definitions, nil
+	}
+
+	schedules, err := m.ScheduleDB.SelectAllSchedules()
+	if err != nil {
+		return nil, err
+	}
+	for _, schedule := range schedules {
+		if !slices.Contains(taskKeys, schedule.TaskKey) {
+			continue
+		}
+		definitions = append(definitions, model.ScheduleDefinition{
+			Name:           schedule.Name,
+			TaskKey:        schedule.TaskKey,
+			CronExpression: schedule.CronExpression,
+			Parameters:     schedule.Parameters,
+			Enabled:        schedule.Enabled,
+		})
+	}
+	return definitions, nil
+}
+
+// pipelineWebhooks returns the notification rules of the tasks with the keys, with only these tasks.
+// Rules of all tasks are left out, as well as the webhook URLs, because they are secrets of the environment.
+func (m *ManagerHandler) pipelineWebhooks(taskKeys []string) []model.NotificationRule {
+	webhooks := []model.NotificationRule{}
+	if m.Notifications == nil {
+		return webhooks
+	}
+
+	for _, rule := range m.Notifications.Rules() {
+		tasks := []string{}
+		for _, task := range rule.Tasks {
+			if slices.Contains(taskKeys, task) {
+				tasks = append(tasks, task)
+			}
+		}
+		if len(tasks) == 0 {
+			continue
+		}
+		rule.Tasks = tasks
+		rule.URL = ""
+		webhooks = append(webhooks, rule)
+	}
+	return webhooks
+}
+
+// importPipelineSchedules adds the schedules of an imported pipeline, they are validated like added schedules.
+// It returns the number of added schedules and the errors of the skipped schedules.
+func (m *ManagerHandler) importPipelineSchedules(ctx context.Context, schedules []model.ScheduleDefinition, createdBy string) (int, []string) {
+	if len(schedules) == 0 {
+		return 0, nil
+	}
+	if m.ScheduleDB == nil {
+		return 0, []string{fmt.Sprintf("Skipped %d schedules: schedules are not enabled", len(schedules))}
+	}
+
+	imported := 0
+	errors := []string{}
+	for _, definition := range schedules {
+		parameters, err := json.Marshal(definition.Parameters)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Skipped schedule '%s': %v", definition.Name, err))
+			continue
+		}
+
+		schedule, err := m.scheduleFromRequest(ctx, &model.ScheduleRequest{
+			Name:           definition.Name,
+			TaskKey:        definition.TaskKey,
+			CronExpression: definition.CronExpression,
+			Parameters:     string(parameters),
+			Enabled:        definition.Enabled,
+		})
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Skipped schedule '%s': %v", definition.Name, err))
+			continue
+		}
+		schedule.CreatedBy = createdBy
+
+		_, err = m.ScheduleDB.InsertSchedule(schedule)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Failed to import schedule '%s': %v", definition.Name, err))
+			continue
+		}
+		imported++
+	}
+	return imported, errors
+}
+
+// checkPipelineWebhooks checks the webhooks of an imported pipeline against the notification rules of this manager.
+// The rules are configured with QUEUER_MANAGER_NOTIFICATION_RULES, so webhooks that are invalid, reference unknown tasks
+// or are not configured by name are reported to be added to the configuration.
+func (m *ManagerHandler) checkPipelineWebhooks(webhooks []model.NotificationRule) []string {
+	configured := []string{}
+	if m.Notifications != nil {
+		for _, rule := range m.Notifications.Rules() {
+			configured = append(configured, rule.Name)
+		}
+	}
+
+	errors := []string{}
+	for i, webhook := range webhooks {
+		if webhook.Name == "" {
+			errors = append(errors, fmt.Sprintf("Skipped webhook %d: missing name", i))
+			continue
+		}
+		if _, err := notify.NewNotifier(webhook.Format, webhook.URL); err != nil {
+			errors = append(errors, fmt.Sprintf("Skipped webhook '%s': %v", webhook.Name, err))
+			continue
+		}
+		unknownTasks := []string{}
+		for _, task := range webhook.Tasks {
+			if _, err := m.taskDB.SelectTaskByKey(task); err != nil {
+				unknownTasks = append(unknownTasks, task)
+			}
+		}
+		if len(unknownTasks) > 0 {
+			errors = append(errors, fmt.Sprintf("Skipped webhook '%s': tasks %v not found", webhook.Name, unknownTasks))
+			continue
+		}
+		if !slices.Contains(configured, webhook.Name) {
+			errors = append(errors, fmt.Sprintf("Webhook '%s' is not configured, add it to QUEUER_MANAGER_NOTIFICATION_RULES", webhook.Name))
+		}
+	}
+	return errors
+}
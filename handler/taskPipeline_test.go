@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"testing"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeTaskImport(t *testing.T) {
+	t.Run("Decode a JSON array of tasks", func(t *testing.T) {
+		pipeline, err := decodeTaskImport([]byte(` [{"key": "export"}]`))
+		require.NoError(t, err)
+		require.Len(t, pipeline.Tasks, 1)
+		assert.Equal(t, "export", pipeline.Tasks[0].Key)
+		assert.Empty(t, pipeline.Schedules)
+	})
+
+	t.Run("Decode a pipeline", func(t *testing.T) {
+		pipeline, err := decodeTaskImport([]byte(`{
+			"tasks": [{"key": "export"}],
+			"schedules": [{"name": "nightly", "task_key": "export", "cron_expression": "0 2 * * *", "parameters": {"count": 3}, "enabled": true}],
+			"webhooks": [{"name": "ops", "format": "teams", "tasks": ["export"]}]
+		}`))
+		require.NoError(t, err)
+		require.Len(t, pipeline.Tasks, 1)
+		require.Len(t, pipeline.Schedules, 1)
+		assert.Equal(t, "export", pipeline.Schedules[0].TaskKey)
+		assert.EqualValues(t, 3, pipeline.Schedules[0].Parameters["count"])
+		require.Len(t, pipeline.Webhooks, 1)
+		assert.Equal(t, "ops", pipeline.Webhooks[0].Name)
+	})
+
+	t.Run("Decode invalid JSON", func(t *testing.T) {
+		_, err := decodeTaskImport([]byte(`{"tasks": `))
+		assert.Error(t, err)
+	})
+}
+
+func TestPipelineWebhooks(t *testing.T) {
+	dispatcher, err := notify.NewDispatcher([]*qmModel.NotificationRule{
+		{Name: "all", URL: "https://hooks.example.com/all"},
+		{Name: "ops", Format: qmModel.NOTIFICATION_FORMAT_TEAMS, URL: "https://hooks.example.com/ops", Tasks: []string{"export", "other"}},
+		{Name: "other", URL: "https://hooks.example.com/other", Tasks: []string{"other"}},
+	}, "")
+	require.NoError(t, err)
+
+	handler := &ManagerHandler{Notifications: dispatcher}
+
+	t.Run("Export the rules of the tasks without their URLs", func(t *testing.T) {
+		webhooks := handler.pipelineWebhooks([]string{"export"})
+		require.Len(t, webhooks, 1)
+		assert.Equal(t, "ops", webhooks[0].Name)
+		assert.Equal(t, []string{"export"}, webhooks[0].Tasks, "Expected only the exported tasks")
+		assert.Empty(t, webhooks[0].URL, "Expected the webhook URL to not be exported")
+		assert.Len(t, dispatcher.Rules()[1].Tasks, 2, "Expected the rules of the dispatcher to be unchanged")
+	})
+
+	t.Run("Report webhooks that are not configured", func(t *testing.T) {
+		errors := handler.checkPipelineWebhooks([]qmModel.NotificationRule{
+			{Name: "all"},
+			{Name: "missing"},
+			{Name: "invalid", Format: "pager"},
+			{},
+		})
+		require.Len(t, errors, 3)
+		assert.Contains(t, errors[0], "QUEUER_MANAGER_NOTIFICATION_RULES")
+		assert.Contains(t, errors[1], "pager")
+		assert.Contains(t, errors[2], "missing name")
+	})
+}
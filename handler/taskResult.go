@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	vm "github.com/siherrmann/validator/model"
+)
+
+// taskResultsPageSize is the number of archived jobs loaded at once while streaming the results export
+const taskResultsPageSize = 100
+
+// taskResultColumn is a result column of the results export, the result at the index of the job results
+// or the value of the inner key if the result is a map. An index of -1 is the JSON of all results.
+type taskResultColumn struct {
+	Name     string
+	Index    int
+	InnerKey string
+}
+
+// ExportTaskResults streams the results of the archived jobs of the task that ended in the time range as CSV file.
+// from and to are RFC3339 times (default the last 30 days), the results are flattened into columns
+// by the output parameters of the task, map results with inner validations get a column per inner key.
+func (m *ManagerHandler) ExportTaskResults(c *echo.Context) error {
+	if m.JobArchiveDB == nil {
+		return c.String(http.StatusServiceUnavailable, "Job archive filters are not enabled")
+	}
+
+	rid, err := uuid.Parse(c.Param("rid"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid task RID format")
+	}
+
+	from, to, err := archiveTimeRange(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	format := c.QueryParam("format")
+	if format != "" && format != "csv" {
+		return c.String(http.StatusBadRequest, "Invalid format (must be csv)")
+	}
+
+	task, err := m.taskDB.SelectTask(rid)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Task not found")
+	}
+
+	columns := taskResultColumns(task.OutputParameters)
+	header := []string{"job_rid", "status", "status_name", "worker_rid", "created_at", "started_at", "ended_at", "error"}
+	for _, column := range columns {
+		header = append(header, column.Name)
+	}
+
+	filename := fmt.Sprintf("results_%s_%s_%s.csv", task.Key, from.Format("20060102"), to.Format("20060102"))
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Response().Header().Set("Content-Type", "text/csv")
+	c.Response().WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(c.Response())
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	// Stream the jobs page by page, the response is flushed after each page
+	responseController := http.NewResponseController(c.Response())
+	lastRID := uuid.Nil
+	for {
+		rids, err := m.JobArchiveDB.SelectJobRIDsByTask(task.Key, from, to, lastRID, taskResultsPageSize)
+		if err != nil {
+			log.Printf("Error selecting archived jobs of task %s: %v", task.Key, err)
+			return err
+		}
+
+		for _, jobRID := range rids {
+			job, err := m.Queuer.GetJobEnded(jobRID)
+			if err != nil {
+				log.Printf("Error getting archived job %s, skipping: %v", jobRID, err)
+				continue
+			}
+			if err := writer.Write(taskResultRecord(job, columns)); err != nil {
+				return err
+			}
+		}
+
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+		if err := responseController.Flush(); err != nil {
+			return err
+		}
+
+		if len(rids) < taskResultsPageSize {
+			return nil
+		}
+		lastRID = rids[len(rids)-1]
+	}
+}
+
+// taskResultColumns returns the result columns of the output parameters of a task.
+// A task without output parameters has a single result column with the JSON of all results.
+func taskResultColumns(outputParameters []vm.Validation) []taskResultColumn {
+	if len(outputParameters) == 0 {
+		return []taskResultColumn{{Name: "result", Index: -1}}
+	}
+
+	columns := []taskResultColumn{}
+	for i, outputParameter := range outputParameters {
+		name := outputParameter.Key
+		if name == "" {
+			name = fmt.Sprintf("result_%d", i+1)
+		}
+
+		if (outputParameter.Type == vm.Map || outputParameter.Type == vm.Struct) && len(outputParameter.InnerValidation) > 0 {
+			for _, innerValidation := range outputParameter.InnerValidation {
+				columns = append(columns, taskResultColumn{Name: name + "." + innerValidation.Key, Index: i, InnerKey: innerValidation.Key})
+			}
+			continue
+		}
+		columns = append(columns, taskResultColumn{Name: name, Index: i})
+	}
+	return columns
+}
+
+// taskResultRecord returns the CSV record of an archived job with the values of the result columns.
+func taskResultRecord(job *model.Job, columns []taskResultColumn) []string {
+	record := []string{
+		job.RID.String(),
+		job.Status,
+		qmModel.StatusName(job.Status),
+		"",
+		job.CreatedAt.Format(time.RFC3339),
+		"",
+		job.UpdatedAt.Format(time.RFC3339),
+		job.Error,
+	}
+	if job.WorkerRID != uuid.Nil {
+		record[3] = job.WorkerRID.String()
+	}
+	if job.StartedAt != nil && !job.StartedAt.IsZero() {
+		record[5] = job.StartedAt.Format(time.RFC3339)
+	}
+
+	for _, column := range columns {
+		if column.Index < 0 {
+			record = append(record, taskResultValue([]any(job.Results)))
+			continue
+		}
+		if column.Index >= len(job.Results) {
+			record = append(record, "")
+			continue
+		}
+
+		value := job.Results[column.Index]
+		if column.InnerKey != "" {
+			innerValues, ok := value.(map[string]any)
+			if !ok {
+				record = append(record, "")
+				continue
+			}
+			value = innerValues[column.InnerKey]
+		}
+		record = append(record, taskResultValue(value))
+	}
+	return record
+}
+
+// taskResultValue formats a result value as CSV value, maps and arrays are formatted as JSON.
+func taskResultValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(valueJSON)
+}
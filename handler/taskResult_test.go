@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"encoding/csv"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuer/model"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	vm "github.com/siherrmann/validator/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskResultRecord(t *testing.T) {
+	columns := taskResultColumns([]vm.Validation{
+		{Key: "count", Type: vm.Int},
+		{Key: "output", Type: vm.Map, InnerValidation: []vm.Validation{{Key: "text", Type: vm.String}, {Key: "language", Type: vm.String}}},
+		{Key: "tags", Type: vm.Array},
+	})
+	names := []string{}
+	for _, column := range columns {
+		names = append(names, column.Name)
+	}
+	assert.Equal(t, []string{"count", "output.text", "output.language", "tags"}, names)
+
+	startedAt := time.Date(2026, time.March, 13, 10, 0, 0, 0, time.UTC)
+	job := &model.Job{
+		RID:       uuid.New(),
+		Status:    model.JobStatusSucceeded,
+		StartedAt: &startedAt,
+		Results:   model.Parameters{float64(3), map[string]any{"text": "Hallo", "language": "de"}, []any{"a", "b"}},
+		CreatedAt: startedAt.Add(-time.Minute),
+		UpdatedAt: startedAt.Add(time.Minute),
+	}
+
+	record := taskResultRecord(job, columns)
+	assert.Equal(t, []string{
+		job.RID.String(), model.JobStatusSucceeded, model.JobStatusSucceeded, "", "2026-03-13T09:59:00Z", "2026-03-13T10:00:00Z", "2026-03-13T10:01:00Z", "",
+		"3", "Hallo", "de", `["a","b"]`,
+	}, record)
+
+	t.Run("Missing results are empty", func(t *testing.T) {
+		job.Results = model.Parameters{float64(1), "not a map"}
+		record := taskResultRecord(job, columns)
+		assert.Equal(t, []string{"1", "", "", ""}, record[8:])
+	})
+
+	t.Run("Tasks without output parameters export all results as JSON", func(t *testing.T) {
+		record := taskResultRecord(job, taskResultColumns(nil))
+		assert.Equal(t, `[1,"not a map"]`, record[8])
+	})
+}
+
+func TestExportTaskResultsHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	jobArchiveDB, err := database.NewJobArchiveDBHandler(db)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.JobArchiveDB = jobArchiveDB
+	e := echo.New()
+
+	task, err := tdb.InsertTask(&qmModel.Task{
+		Key:                  "test-task",
+		Name:                 "Test Task",
+		InputParameters:      []vm.Validation{{Key: "seconds", Type: vm.Int}},
+		InputParametersKeyed: []vm.Validation{},
+	})
+	require.NoError(t, err)
+
+	job, err := queue.AddJob("test-task", nil, 0)
+	require.NoError(t, err)
+	queue.WaitForJobFinished(job.RID, 5*time.Second)
+
+	exportResults := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/task/"+task.RID.String()+"/exportResults"+query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "rid", Value: task.RID.String()}})
+
+		require.NoError(t, handler.ExportTaskResults(c))
+		return rec
+	}
+
+	t.Run("ExportTaskResults streams the archived jobs as CSV", func(t *testing.T) {
+		rec := exportResults("?format=csv")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+		assert.Contains(t, rec.Header().Get("Content-Disposition"), "results_test-task_")
+
+		records, err := csv.NewReader(strings.NewReader(rec.Body.String())).ReadAll()
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, len(records), 2)
+		assert.Equal(t, []string{"job_rid", "status", "status_name", "worker_rid", "created_at", "started_at", "ended_at", "error", "result"}, records[0])
+
+		found := false
+		for _, record := range records[1:] {
+			if record[0] == job.RID.String() {
+				found = true
+				assert.Equal(t, model.JobStatusSucceeded, record[1])
+			}
+		}
+		assert.True(t, found, "Expected the archived job in the export")
+	})
+
+	t.Run("ExportTaskResults outside of the time range", func(t *testing.T) {
+		rec := exportResults("?from=2020-01-01T00:00:00Z&to=2020-02-01T00:00:00Z")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		records, err := csv.NewReader(strings.NewReader(rec.Body.String())).ReadAll()
+		require.NoError(t, err)
+		assert.Len(t, records, 1, "Expected only the header")
+	})
+
+	t.Run("ExportTaskResults with invalid parameters", func(t *testing.T) {
+		rec := exportResults("?format=xlsx")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = exportResults("?from=yesterday")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = exportResults("?from=2026-02-01T00:00:00Z&to=2026-01-01T00:00:00Z")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/labstack/echo/v5"
+	vm "github.com/siherrmann/validator/model"
+)
+
+// =======API Handlers=======
+
+// PutTaskSample creates or replaces the sample values of the task with the key of the path.
+func (m *ManagerHandler) PutTaskSample(c *echo.Context) error {
+	if m.TaskSampleDB == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "Task samples are not enabled"})
+	}
+
+	key := c.Param("key")
+	if key == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Task key is required"})
+	}
+
+	var taskSample model.TaskSample
+	if err := c.Bind(&taskSample); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("Invalid request: %v", err)})
+	}
+	taskSample.TaskKey = key
+
+	_, err := m.taskDB.SelectTaskByKey(key)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Task not found"})
+	}
+
+	upsertedTaskSample, err := m.TaskSampleDB.UpsertTaskSample(&taskSample)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to save task sample: %v", err)})
+	}
+
+	return c.JSON(http.StatusOK, upsertedTaskSample)
+}
+
+// SaveTaskSample saves the values of the test run form as the sample values of the task with the key of the path.
+// Only the values of input parameters of the task are saved.
+func (m *ManagerHandler) SaveTaskSample(c *echo.Context) error {
+	if m.TaskSampleDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Task samples are not enabled")
+	}
+
+	task, err := m.taskDB.SelectTaskByKey(c.Param("key"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "Task not found")
+	}
+
+	form, err := c.FormValues()
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid form: %v", err))
+	}
+
+	values := map[string]string{}
+	validations := append([]vm.Validation{}, task.InputParameters...)
+	validations = append(validations, task.InputParametersKeyed...)
+	for _, v := range validations {
+		if form.Has(v.Key) {
+			values[v.Key] = form.Get(v.Key)
+		}
+	}
+
+	_, err = m.TaskSampleDB.UpsertTaskSample(&model.TaskSample{TaskKey: task.Key, Values: values})
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to save task sample: %v", err))
+	}
+
+	return renderPopupOrJson(c, http.StatusOK, fmt.Sprintf("Saved the sample values of task %s", task.Key))
+}
+
+// GetTaskSample retrieves the sample values of the task with the key of the path.
+func (m *ManagerHandler) GetTaskSample(c *echo.Context) error {
+	if m.TaskSampleDB == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "Task samples are not enabled"})
+	}
+
+	taskSample, err := m.TaskSampleDB.SelectTaskSample(c.Param("key"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Task sample not found"})
+	}
+
+	return c.JSON(http.StatusOK, taskSample)
+}
+
+// DeleteTaskSample deletes the sample values of the task with the key of the path.
+// Deleting sample values that do not exist also responds with 204.
+func (m *ManagerHandler) DeleteTaskSample(c *echo.Context) error {
+	if m.TaskSampleDB == nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "Task samples are not enabled"})
+	}
+
+	err := m.TaskSampleDB.DeleteTaskSample(c.Param("key"))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to delete task sample: %v", err)})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
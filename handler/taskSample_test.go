@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	vm "github.com/siherrmann/validator/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskSampleTestRun(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+	tsdb, err := database.NewTaskSampleDBHandler(db, true)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	handler.TaskSampleDB = tsdb
+	e := echo.New()
+
+	task, err := tdb.InsertTask(&qmModel.Task{
+		Key:             "test-sample-task",
+		Name:            "Test Sample Task",
+		InputParameters: []vm.Validation{{Key: "region", Type: vm.String}},
+	})
+	require.NoError(t, err)
+
+	t.Run("SaveTaskSample saves the values of the task parameters", func(t *testing.T) {
+		form := url.Values{"region": {"eu-west"}, "unknown": {"ignored"}}
+		req := httptest.NewRequest(http.MethodPost, "/api/task/saveTaskSample/"+task.Key, strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "key", Value: task.Key}})
+
+		err := handler.SaveTaskSample(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		taskSample, err := tsdb.SelectTaskSample(task.Key)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"region": "eu-west"}, taskSample.Values)
+	})
+
+	t.Run("AddJobConfigView prefills the test run with the sample values", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/task/"+task.Key+"?test=true", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "taskKey", Value: task.Key}})
+
+		err := handler.AddJobConfigView(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `value="eu-west"`)
+		assert.Contains(t, rec.Body.String(), "/api/job/addJob/"+task.Key+"?test=true")
+		assert.Contains(t, rec.Header().Get("HX-Push-Url"), "?test=true")
+	})
+
+	t.Run("SaveTaskSample with nonexistent task", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/task/saveTaskSample/nonexistent-task", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "key", Value: "nonexistent-task"}})
+
+		err := handler.SaveTaskSample(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/siherrmann/queuerManager/helper"
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/labstack/echo/v5"
+	vm "github.com/siherrmann/validator/model"
+)
+
+// builtinValidatorTypes are the types inferred from task signatures, other types are custom validations
+var builtinValidatorTypes = []vm.ValidatorType{vm.String, vm.Int, vm.Float, vm.Bool, vm.Array, vm.Map, vm.Struct}
+
+// =======API Handlers=======
+
+// ImportTaskSignatures creates or updates the tasks of the task signatures a worker or CLI posts as JSON array.
+// The validations are inferred from the parameter types. Validations of existing tasks that were refined
+// by hand (eg. with a requirement or custom validation) are kept as long as the parameter has the same type.
+func (m *ManagerHandler) ImportTaskSignatures(c *echo.Context) error {
+	var signatures []model.TaskSignature
+	if err := c.Bind(&signatures); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("Invalid request: %v", err)})
+	}
+	if len(signatures) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "No task signatures found"})
+	}
+
+	result := &model.TaskSignatureImport{Created: []string{}, Updated: []string{}, Errors: []string{}}
+	for _, signature := range signatures {
+		definition, err := helper.TaskDefinitionFromSignature(&signature)
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+
+		task := definition.ToTask()
+		if err := validateTaskKey(task.Key); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		existingTask, err := m.taskDB.SelectTaskByKey(task.Key)
+		if err == nil {
+			mergeTaskFromSignature(task, existingTask, &signature)
+		}
+		if err := m.runPreTaskSaveHooks(c.Request().Context(), task); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+
+		upsertedTask, created, err := m.taskDB.UpsertTask(task)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("Failed to save task '%s': %v", task.Key, err))
+			continue
+		}
+		m.runPostTaskSaveHooks(c.Request().Context(), upsertedTask)
+
+		if created {
+			m.publishEvent(model.EVENT_TASK_ADDED, upsertedTask)
+			result.Created = append(result.Created, upsertedTask.Key)
+		} else {
+			m.publishEvent(model.EVENT_TASK_UPDATED, upsertedTask)
+			result.Updated = append(result.Updated, upsertedTask.Key)
+		}
+	}
+
+	if len(result.Errors) > 0 {
+		return c.JSON(http.StatusPartialContent, result)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// mergeTaskFromSignature keeps the settings of the existing task that can not be inferred from the signature.
+func mergeTaskFromSignature(task *model.Task, existingTask *model.Task, signature *model.TaskSignature) {
+	if signature.Name == "" {
+		task.Name = existingTask.Name
+	}
+	if signature.Description == "" {
+		task.Description = existingTask.Description
+	}
+	task.InputParameters = mergeValidations(task.InputParameters, existingTask.InputParameters)
+	task.InputParametersKeyed = mergeValidations(task.InputParametersKeyed, existingTask.InputParametersKeyed)
+	task.OutputParameters = mergeValidations(task.OutputParameters, existingTask.OutputParameters)
+	task.MinWorkerVersion = existingTask.MinWorkerVersion
+	task.WorkerVersionPolicy = existingTask.WorkerVersionPolicy
+	task.Owner = existingTask.Owner
+	task.Namespace = existingTask.Namespace
+	task.DedupWindowMinutes = existingTask.DedupWindowMinutes
+	task.MaxConcurrency = existingTask.MaxConcurrency
+	task.RateLimitPerMinute = existingTask.RateLimitPerMinute
+	task.OnSuccess = existingTask.OnSuccess
+	task.Alerts = existingTask.Alerts
+
+	// Docs of parameters the signature no longer has are dropped
+	task.ParameterDocs = map[string]model.ParameterDoc{}
+	for key, doc := range existingTask.ParameterDocs {
+		isInput := func(v vm.Validation) bool { return v.Key == key }
+		if slices.ContainsFunc(task.InputParameters, isInput) || slices.ContainsFunc(task.InputParametersKeyed, isInput) {
+			task.ParameterDocs[key] = doc
+		}
+	}
+}
+
+// mergeValidations replaces the inferred validations with the existing validations of the same key
+// if they have the same type or a custom validation type.
+func mergeValidations(inferred []vm.Validation, existing []vm.Validation) []vm.Validation {
+	merged := make([]vm.Validation, 0, len(inferred))
+	for _, validation := range inferred {
+		index := slices.IndexFunc(existing, func(v vm.Validation) bool { return v.Key == validation.Key })
+		if index >= 0 && (existing[index].Type == validation.Type || !slices.Contains(builtinValidatorTypes, existing[index].Type)) {
+			validation = existing[index]
+		}
+		merged = append(merged, validation)
+	}
+	return merged
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/database"
+	qmHelper "github.com/siherrmann/queuerManager/helper"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/upload"
+	vm "github.com/siherrmann/validator/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSignatureOptions struct {
+	Language string   `json:"language"`
+	Tags     []string `json:"tags"`
+	Internal string   `json:"-"`
+}
+
+func TestImportTaskSignaturesHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	e := echo.New()
+
+	importSignatures := func(signatures any) *httptest.ResponseRecorder {
+		body, err := json.Marshal(signatures)
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/task/importTaskSignatures", strings.NewReader(string(body)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err = handler.ImportTaskSignatures(c)
+		require.NoError(t, err)
+		return rec
+	}
+
+	taskFunc := func(text string, retries *int, options testSignatureOptions) (map[string]any, error) {
+		return nil, nil
+	}
+	signature, err := qmHelper.NewTaskSignature("test-signature-task", taskFunc, "text", "retries", "options")
+	require.NoError(t, err)
+
+	t.Run("NewTaskSignature introspects the function", func(t *testing.T) {
+		require.Len(t, signature.Parameters, 3)
+		assert.Equal(t, "string", signature.Parameters[0].Type)
+		assert.Equal(t, "*int", signature.Parameters[1].Type)
+		assert.Equal(t, "struct", signature.Parameters[2].Type)
+		assert.Len(t, signature.Parameters[2].Fields, 2, "fields ignored in JSON should be skipped")
+		require.Len(t, signature.Results, 1, "the error result should be skipped")
+		assert.Equal(t, "map[string]any", signature.Results[0].Type)
+
+		_, err := qmHelper.NewTaskSignature("not-a-function", "text")
+		assert.Error(t, err)
+	})
+
+	t.Run("ImportTaskSignatures creates the task", func(t *testing.T) {
+		rec := importSignatures([]*qmModel.TaskSignature{signature})
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var result qmModel.TaskSignatureImport
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+		assert.Equal(t, []string{"test-signature-task"}, result.Created)
+
+		task, err := tdb.SelectTaskByKey("test-signature-task")
+		require.NoError(t, err)
+		require.Len(t, task.InputParameters, 3)
+		assert.Equal(t, vm.String, task.InputParameters[0].Type)
+		assert.Equal(t, vm.Int, task.InputParameters[1].Type)
+		assert.True(t, task.InputParameters[1].OmitEmpty)
+		assert.Equal(t, vm.Struct, task.InputParameters[2].Type)
+		assert.Len(t, task.InputParameters[2].InnerValidation, 2)
+		require.Len(t, task.OutputParameters, 1)
+		assert.Equal(t, vm.Map, task.OutputParameters[0].Type)
+	})
+
+	t.Run("ImportTaskSignatures keeps refined validations", func(t *testing.T) {
+		task, err := tdb.SelectTaskByKey("test-signature-task")
+		require.NoError(t, err)
+		task.Description = "Translates text."
+		task.InputParameters[0].Requirement = "min1"
+		_, err = tdb.UpdateTask(task)
+		require.NoError(t, err)
+
+		// The retries parameter changed its type, so its validation is inferred again
+		changedSignature := *signature
+		changedSignature.Parameters = []qmModel.ParameterSignature{
+			{Name: "text", Type: "string"},
+			{Name: "retries", Type: "float64"},
+		}
+		rec := importSignatures([]qmModel.TaskSignature{changedSignature})
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var result qmModel.TaskSignatureImport
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+		assert.Equal(t, []string{"test-signature-task"}, result.Updated)
+
+		task, err = tdb.SelectTaskByKey("test-signature-task")
+		require.NoError(t, err)
+		assert.Equal(t, "Translates text.", task.Description)
+		require.Len(t, task.InputParameters, 2)
+		assert.Equal(t, "min1", task.InputParameters[0].Requirement)
+		assert.Equal(t, vm.Float, task.InputParameters[1].Type)
+	})
+
+	t.Run("ImportTaskSignatures reports unsupported types", func(t *testing.T) {
+		rec := importSignatures([]qmModel.TaskSignature{
+			{Key: "test-signature-invalid", Parameters: []qmModel.ParameterSignature{{Name: "callback", Type: "func()"}}},
+		})
+		require.Equal(t, http.StatusPartialContent, rec.Code, rec.Body.String())
+
+		var result qmModel.TaskSignatureImport
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+		assert.Len(t, result.Errors, 1)
+		assert.Empty(t, result.Created)
+	})
+
+	t.Run("ImportTaskSignatures with empty list", func(t *testing.T) {
+		rec := importSignatures([]qmModel.TaskSignature{})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
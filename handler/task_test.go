@@ -8,9 +8,11 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v5"
@@ -63,6 +65,30 @@ func TestAddTaskHandler(t *testing.T) {
 		assert.Len(t, task.InputParameters, 1)
 	})
 
+	t.Run("AddTask from the tasks table", func(t *testing.T) {
+		formData := strings.NewReader("key=test-add-task-row&name=Test Add Task Row")
+
+		req := httptest.NewRequest(http.MethodPost, "/api/task/addTask", formData)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		req.Header.Set("HX-Request", "true")
+		req.Header.Set("HX-Current-URL", "http://localhost:3000/tasks?search=&limit=100&lastId=0")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.AddTask(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Empty(t, rec.Header().Get("HX-Redirect"))
+		assert.Equal(t, "#table_body_tasks_table", rec.Header().Get("HX-Retarget"))
+		assert.Equal(t, "closeAddTask", rec.Header().Get("HX-Trigger-After-Settle"))
+
+		task, err := tdb.SelectTaskByKey("test-add-task-row")
+		require.NoError(t, err)
+		assert.Contains(t, rec.Body.String(), `<tr id="table_row_`+task.RID.String()+`">`)
+		assert.Contains(t, rec.Body.String(), `hx-swap-oob="beforeend:#body"`)
+	})
+
 	t.Run("AddTask with missing key", func(t *testing.T) {
 		formData := strings.NewReader("name=Test&description=Test")
 
@@ -107,6 +133,118 @@ func TestAddTaskHandler(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 		assert.Contains(t, rec.Body.String(), "Invalid validations JSON")
 	})
+
+	t.Run("AddTask with parameter rows", func(t *testing.T) {
+		form := url.Values{
+			"key":                    {"test-add-task-parameters"},
+			"name":                   {"Test Add Task Parameters"},
+			"validations_rows":       {"true"},
+			"validations_key":        {"input", "retries", "recipient"},
+			"validations_type":       {"string", "int", "email"},
+			"validations_condition":  {"min", "-", "custom"},
+			"validations_value":      {"1", "", "con@ && max50"},
+			"validations_optional":   {"false", "true", "false"},
+			"output_parameters_rows": {"true"},
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/api/task/addTask", strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.AddTask(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		task, err := tdb.SelectTaskByKey("test-add-task-parameters")
+		require.NoError(t, err)
+		require.Len(t, task.InputParameters, 3)
+		assert.Equal(t, vm.Validation{Key: "input", Type: vm.String, Requirement: "min1"}, task.InputParameters[0])
+		assert.Equal(t, vm.Validation{Key: "retries", Type: vm.Int, Requirement: "-", OmitEmpty: true}, task.InputParameters[1])
+		assert.Equal(t, vm.ValidatorType("email"), task.InputParameters[2].Type)
+		assert.Equal(t, "con@ && max50", task.InputParameters[2].Requirement)
+		assert.Empty(t, task.OutputParameters)
+	})
+
+	t.Run("AddTask with parameter docs", func(t *testing.T) {
+		form := url.Values{
+			"key":                           {"test-add-task-docs"},
+			"name":                          {"Test Add Task Docs"},
+			"validations_rows":              {"true"},
+			"validations_key":               {"date", "count"},
+			"validations_type":              {"string", "int"},
+			"validations_condition":         {"min", "-"},
+			"validations_value":             {"1", ""},
+			"validations_optional":          {"false", "true"},
+			"validations_description":       {"Day of the report", " "},
+			"validations_example":           {"2026-01-31", ""},
+			"validations_keyed_rows":        {"true"},
+			"validations_keyed_key":         {"region"},
+			"validations_keyed_type":        {"string"},
+			"validations_keyed_condition":   {"-"},
+			"validations_keyed_value":       {""},
+			"validations_keyed_optional":    {"false"},
+			"validations_keyed_example":     {"eu-west"},
+			"validations_keyed_description": {""},
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/api/task/addTask", strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.AddTask(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		task, err := tdb.SelectTaskByKey("test-add-task-docs")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]qmModel.ParameterDoc{
+			"date":   {Description: "Day of the report", Example: "2026-01-31"},
+			"region": {Example: "eu-west"},
+		}, task.ParameterDocs)
+	})
+
+	t.Run("AddTask with docs of unknown parameters", func(t *testing.T) {
+		formData := strings.NewReader(`key=test-add-task-unknown-docs&name=Test&parameter_docs={"missing":{"description":"Unknown"}}`)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/task/addTask", formData)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.AddTask(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "is no input parameter")
+	})
+
+	t.Run("AddTask with invalid parameter rows", func(t *testing.T) {
+		form := url.Values{
+			"key":                   {"test-add-task-invalid-parameters"},
+			"name":                  {"Test"},
+			"validations_rows":      {"true"},
+			"validations_key":       {"input"},
+			"validations_type":      {"string"},
+			"validations_condition": {"min"},
+			"validations_value":     {""},
+			"validations_optional":  {"false"},
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/api/task/addTask", strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.AddTask(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Invalid validations")
+		assert.Contains(t, rec.Body.String(), "requires a value")
+	})
 }
 
 func TestUpdateTaskHandler(t *testing.T) {
@@ -178,6 +316,46 @@ func TestUpdateTaskHandler(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 		assert.Contains(t, rec.Body.String(), "Failed to update task")
 	})
+
+	t.Run("UpdateTask with parameter rows keeps inner validations", func(t *testing.T) {
+		innerValidation := []vm.Validation{{Key: "name", Type: vm.String, Requirement: "min1"}}
+		task, err := tdb.InsertTask(&qmModel.Task{
+			Key:  "test-update-task-parameters",
+			Name: "Original Name",
+			InputParametersKeyed: []vm.Validation{
+				{Key: "config", Type: vm.Struct, Requirement: "-", InnerValidation: innerValidation},
+			},
+		})
+		require.NoError(t, err)
+
+		form := url.Values{
+			"key":                         {"test-update-task-parameters"},
+			"name":                        {"Updated Name"},
+			"validations_keyed_rows":      {"true"},
+			"validations_keyed_key":       {"config", "limit"},
+			"validations_keyed_type":      {"struct", "int"},
+			"validations_keyed_condition": {"-", "max"},
+			"validations_keyed_value":     {"", "10"},
+			"validations_keyed_optional":  {"true", "false"},
+		}
+
+		req := httptest.NewRequest(http.MethodPatch, "/api/task/updateTask?rid="+task.RID.String(), strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err = handler.UpdateTask(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		updatedTask, err := tdb.SelectTask(task.RID)
+		require.NoError(t, err)
+		require.Len(t, updatedTask.InputParametersKeyed, 2)
+		assert.True(t, updatedTask.InputParametersKeyed[0].OmitEmpty)
+		assert.Equal(t, innerValidation, updatedTask.InputParametersKeyed[0].InnerValidation)
+		assert.Equal(t, "max10", updatedTask.InputParametersKeyed[1].Requirement)
+	})
 }
 
 func TestDeleteTasksHandler(t *testing.T) {
@@ -342,6 +520,93 @@ func TestGetTaskByNameHandler(t *testing.T) {
 	})
 }
 
+func TestPutTaskHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	e := echo.New()
+
+	putTask := func(key string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/task/putTask/"+key, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "key", Value: key}})
+
+		err := handler.PutTask(c)
+		require.NoError(t, err)
+		return rec
+	}
+
+	var createdTask qmModel.Task
+	t.Run("PutTask creates the task", func(t *testing.T) {
+		rec := putTask("test-put-task", `{"name": "Test Put Task", "input_parameters_keyed": [{"Key": "message", "Type": "string", "Requirement": "min1"}]}`)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		err := json.Unmarshal(rec.Body.Bytes(), &createdTask)
+		require.NoError(t, err)
+		assert.Equal(t, "test-put-task", createdTask.Key)
+		assert.Len(t, createdTask.InputParametersKeyed, 1)
+	})
+
+	t.Run("PutTask updates the task with a stable RID", func(t *testing.T) {
+		rec := putTask("test-put-task", `{"key": "test-put-task", "name": "Updated Put Task"}`)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var updatedTask qmModel.Task
+		err := json.Unmarshal(rec.Body.Bytes(), &updatedTask)
+		require.NoError(t, err)
+		assert.Equal(t, createdTask.RID, updatedTask.RID)
+		assert.Equal(t, "Updated Put Task", updatedTask.Name)
+		assert.Empty(t, updatedTask.InputParametersKeyed)
+	})
+
+	t.Run("PutTask with mismatching key", func(t *testing.T) {
+		rec := putTask("test-put-task", `{"key": "other-task", "name": "Other Task"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("PutTask with missing name", func(t *testing.T) {
+		rec := putTask("test-put-task", `{}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("GetTaskByKey", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/task/getTaskByKey/test-put-task", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "key", Value: "test-put-task"}})
+
+		err := handler.GetTaskByKey(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var fetchedTask qmModel.Task
+		err = json.Unmarshal(rec.Body.Bytes(), &fetchedTask)
+		require.NoError(t, err)
+		assert.Equal(t, createdTask.RID, fetchedTask.RID)
+	})
+
+	t.Run("DeleteTaskByKey is idempotent", func(t *testing.T) {
+		for range 2 {
+			req := httptest.NewRequest(http.MethodDelete, "/api/task/deleteTaskByKey/test-put-task", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPathValues([]echo.PathValue{{Name: "key", Value: "test-put-task"}})
+
+			err := handler.DeleteTaskByKey(c)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusNoContent, rec.Code)
+		}
+
+		_, err := tdb.SelectTaskByKey("test-put-task")
+		assert.Error(t, err)
+	})
+}
+
 func TestGetTasksHandler(t *testing.T) {
 	fs := upload.NewFilesystemMemory()
 	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
@@ -416,6 +681,97 @@ func TestGetTasksHandler(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 		assert.Contains(t, rec.Body.String(), "Invalid limit")
 	})
+
+	t.Run("GetTasks by parameter key", func(t *testing.T) {
+		_, err := tdb.InsertTask(&qmModel.Task{
+			Key:             "test-get-tasks-customer",
+			Name:            "Test Task Customer",
+			InputParameters: []vm.Validation{{Key: "customer_id", Type: vm.Int}},
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/task/getTasks?parameter=customer_id", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err = handler.GetTasks(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var tasks []*qmModel.Task
+		err = json.Unmarshal(rec.Body.Bytes(), &tasks)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, "test-get-tasks-customer", tasks[0].Key)
+	})
+
+	t.Run("GetTasks sorted and filtered by key prefix", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/task/getTasks?keyPrefix=test-get-tasks-&sort=name&order=desc&limit=2", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetTasks(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var tasks []*qmModel.Task
+		err = json.Unmarshal(rec.Body.Bytes(), &tasks)
+		require.NoError(t, err)
+		require.Len(t, tasks, 2)
+		assert.Equal(t, "Test Task Customer", tasks[0].Name)
+		assert.Equal(t, "Test Task 4", tasks[1].Name)
+	})
+
+	t.Run("GetTasks with invalid sort", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/task/getTasks?sort=owner", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetTasks(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Invalid sort")
+	})
+}
+
+func TestTaskFilterFromQuery(t *testing.T) {
+	e := echo.New()
+	filterFromQuery := func(query string) (*qmModel.TaskFilter, error) {
+		req := httptest.NewRequest(http.MethodGet, "/api/task/getTasks?"+query, nil)
+		return taskFilterFromQuery(e.NewContext(req, httptest.NewRecorder()))
+	}
+
+	filter, err := filterFromQuery("")
+	require.NoError(t, err)
+	assert.False(t, filter.HasFilters())
+
+	filter, err = filterFromQuery("keyPrefix=report_&withOutputParameters=true&createdAfter=2026-01-01&createdBefore=2026-02-01T12:00:00Z&sort=updated_at&order=desc")
+	require.NoError(t, err)
+	assert.Equal(t, "report_", filter.KeyPrefix)
+	assert.True(t, filter.WithOutputParameters)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), filter.CreatedAfter)
+	assert.Equal(t, time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC), filter.CreatedBefore)
+	assert.Equal(t, qmModel.TASK_SORT_UPDATED_AT, filter.Sort)
+	assert.True(t, filter.Descending)
+	assert.Equal(t, "&keyPrefix=report_&withOutputParameters=true&createdAfter=2026-01-01T00%3A00%3A00Z&createdBefore=2026-02-01T12%3A00%3A00Z&sort=updated_at&order=desc", taskFilterQuery(filter))
+
+	filter, err = filterFromQuery("namespace=billing")
+	require.NoError(t, err)
+	assert.Equal(t, "billing", filter.Namespace)
+	assert.True(t, filter.HasFilters())
+	assert.Empty(t, taskFilterQuery(filter), "Expected the namespace to not be pushed to the url")
+
+	_, err = filterFromQuery("order=up")
+	assert.EqualError(t, err, "Invalid order (must be asc or desc)")
+
+	_, err = filterFromQuery("createdAfter=yesterday")
+	assert.EqualError(t, err, "Invalid createdAfter format (must be RFC3339 or YYYY-MM-DD)")
+
+	_, err = filterFromQuery("createdAfter=2026-02-01&createdBefore=2026-01-01")
+	assert.EqualError(t, err, "Invalid time range (createdAfter must be before createdBefore)")
 }
 
 func TestExportTaskHandler(t *testing.T) {
@@ -454,6 +810,31 @@ func TestExportTaskHandler(t *testing.T) {
 		require.NoError(t, err)
 		assert.Len(t, exportedTasks, 1)
 		assert.Equal(t, "test-export-task", exportedTasks[0]["key"])
+		assert.NotContains(t, exportedTasks[0], "parameter_docs")
+	})
+
+	t.Run("ExportTask with parameter docs", func(t *testing.T) {
+		task, err := tdb.InsertTask(&qmModel.Task{
+			Key:             "test-export-task-docs",
+			Name:            "Test Export Task Docs",
+			InputParameters: []vm.Validation{{Key: "date", Type: "string", Requirement: "min1"}},
+			ParameterDocs:   map[string]qmModel.ParameterDoc{"date": {Description: "Day of the report", Example: "2026-01-31"}},
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/task/exportTask?rid="+task.RID.String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err = handler.ExportTask(c)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var exportedTasks []qmModel.TaskDefinition
+		err = json.Unmarshal(rec.Body.Bytes(), &exportedTasks)
+		require.NoError(t, err)
+		require.Len(t, exportedTasks, 1)
+		assert.Equal(t, task.ParameterDocs, exportedTasks[0].ParameterDocs)
 	})
 
 	t.Run("ExportTask with no RIDs", func(t *testing.T) {
@@ -590,6 +971,130 @@ func TestTaskViewHandler(t *testing.T) {
 	})
 }
 
+func TestTaskTabViewHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	e := echo.New()
+
+	task, err := tdb.InsertTask(&qmModel.Task{
+		Key:         "test-task-tabs",
+		Name:        "Test Task Tabs",
+		Description: "Task with tabs",
+	})
+	require.NoError(t, err)
+
+	t.Run("TaskTabView renders each tab", func(t *testing.T) {
+		expected := map[string]string{
+			"overview":   "Task with tabs",
+			"parameters": "Output Parameters",
+			"history":    "Job history is not available",
+			"raw":        "test-task-tabs",
+		}
+		for tab, content := range expected {
+			req := httptest.NewRequest(http.MethodGet, "/task/tab/"+tab+"?rid="+task.RID.String(), nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPathValues([]echo.PathValue{{Name: "tab", Value: tab}})
+
+			err := handler.TaskTabView(c)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, rec.Code, "Expected tab %s to render", tab)
+			assert.Contains(t, rec.Body.String(), content, "Expected tab %s to contain %s", tab, content)
+		}
+	})
+
+	t.Run("TaskTabView with nonexistent task", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/task/tab/overview?rid="+uuid.New().String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "tab", Value: "overview"}})
+
+		err := handler.TaskTabView(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestTaskRowViewHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	e := echo.New()
+
+	t.Run("TaskRowView with valid RID", func(t *testing.T) {
+		task, err := tdb.InsertTask(&qmModel.Task{
+			Key:  "test-task-row-view",
+			Name: "Test Task Row View",
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/task/taskRow?rid="+task.RID.String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err = handler.TaskRowView(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, strings.HasPrefix(rec.Body.String(), `<tr id="table_row_`+task.RID.String()+`">`))
+		assert.Contains(t, rec.Body.String(), "Test Task Row View")
+	})
+
+	t.Run("TaskRowView with invalid RID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/task/taskRow?rid=invalid", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.TaskRowView(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestParameterRowViewHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	e := echo.New()
+
+	t.Run("ParameterRowView with valid prefix", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/task/parameterRow?prefix=validations_keyed", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.ParameterRowView(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `name="validations_keyed_key"`)
+		assert.Contains(t, rec.Body.String(), `<option value="cron">cron</option>`)
+	})
+
+	t.Run("ParameterRowView with invalid prefix", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/task/parameterRow?prefix=key", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.ParameterRowView(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
 func TestTasksViewHandler(t *testing.T) {
 	fs := upload.NewFilesystemMemory()
 	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
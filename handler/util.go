@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 
+	"github.com/siherrmann/queuerManager/model"
 	"github.com/siherrmann/queuerManager/view/components"
 
 	"github.com/a-h/templ"
@@ -25,6 +27,20 @@ func render(ctx *echo.Context, t templ.Component, status ...int) error {
 	return ctx.HTML(http.StatusOK, buf.String())
 }
 
+// renderStream renders the component directly into the response instead of buffering the whole page first.
+// The response is sent with chunked transfer encoding, so large tables reach the browser while they are still rendering.
+// As the status is written before rendering, a render error can not change the status code anymore.
+func renderStream(ctx *echo.Context, t templ.Component, status ...int) error {
+	ctx.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
+	if len(status) > 0 {
+		ctx.Response().WriteHeader(status[0])
+	} else {
+		ctx.Response().WriteHeader(http.StatusOK)
+	}
+
+	return t.Render(ctx.Request().Context(), ctx.Response())
+}
+
 func renderPopup(c *echo.Context, component templ.Component) error {
 	c.Response().Header().Add("HX-Retarget", "#body")
 	c.Response().Header().Add("HX-Reswap", "beforeend")
@@ -50,6 +66,32 @@ func renderPopupHTTP(writer http.ResponseWriter, component templ.Component) erro
 	return renderHTTP(writer, component)
 }
 
+// renderRowsWithPopup swaps the rows into the target of the current page instead of reloading it,
+// closes the popup the request was sent from with the close event (if any) and shows the message as popup.
+func renderRowsWithPopup(c *echo.Context, status int, rows templ.Component, target string, swap string, closeEvent string, message string) error {
+	c.Response().Header().Add("HX-Retarget", target)
+	c.Response().Header().Add("HX-Reswap", swap)
+	if closeEvent != "" {
+		c.Response().Header().Add("HX-Trigger-After-Settle", closeEvent)
+	}
+
+	popup := components.PopupSuccess("Info", message)
+	if status < 200 || status >= 300 {
+		popup = components.PopupError("Error", message)
+	}
+
+	return render(c, templ.Join(rows, components.PopupOutOfBand(popup)), status)
+}
+
+// currentPath returns the path of the page an HTMX request was sent from
+func currentPath(c *echo.Context) string {
+	currentURL, err := url.Parse(c.Request().Header.Get("HX-Current-URL"))
+	if err != nil {
+		return ""
+	}
+	return currentURL.Path
+}
+
 func renderPopupOrJson(c *echo.Context, status int, value ...any) error {
 	// No value to render
 	if len(value) == 0 {
@@ -84,3 +126,11 @@ func renderPopupOrJson(c *echo.Context, status int, value ...any) error {
 	// Multiple values, return as array
 	return c.JSON(status, value)
 }
+
+// requestedBy returns the name of the logged in user or the IP of the request without login
+func requestedBy(c *echo.Context) string {
+	if user := model.GetRequestContext(c).User; user != nil {
+		return user.Username
+	}
+	return c.RealIP()
+}
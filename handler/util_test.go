@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/components"
+
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flushCountingRecorder is a response recorder counting the flushes of the response.
+type flushCountingRecorder struct {
+	*httptest.ResponseRecorder
+	flushes int
+}
+
+func (r *flushCountingRecorder) Flush() {
+	r.flushes++
+	r.ResponseRecorder.Flush()
+}
+
+func TestRenderStream(t *testing.T) {
+	rows := []model.Mapper{}
+	for i := range 45 {
+		rows = append(rows, model.UniversalMapper{Data: []model.UniversalSubMapper{{Key: "name", Data: fmt.Sprintf("row %d", i)}}})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := &flushCountingRecorder{ResponseRecorder: httptest.NewRecorder()}
+	c := echo.New().NewContext(req, rec)
+
+	err := renderStream(c, components.Table("test", []model.KeyValuePair{{Key: "name", Value: "Name"}}, rows, false))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 3, rec.flushes, "Expected a page of 45 rows to be flushed after 20 and 40 rows and when the rendering ends")
+	assert.Contains(t, rec.Body.String(), "row 44")
+}
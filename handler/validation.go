@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/siherrmann/queuerManager/helper"
+	"github.com/siherrmann/queuerManager/model"
+
+	vm "github.com/siherrmann/validator/model"
+)
+
+// defaultValidationFuncs are the custom validations that are available without registration
+func defaultValidationFuncs() map[string]model.ValidationFunc {
+	return map[string]model.ValidationFunc{
+		"cron":  helper.ValidateCron,
+		"email": helper.ValidateEmail,
+		"s3uri": helper.ValidateS3URI,
+	}
+}
+
+// AddValidationFunc registers a custom validation with the given name.
+// The name can be used as type (validated as string first) or as "&&" joined part of the requirement
+// of task input parameters.
+func (m *ManagerHandler) AddValidationFunc(name string, fn model.ValidationFunc) {
+	m.validationFuncs[name] = fn
+}
+
+// splitCustomValidations replaces custom types and requirements of the validations with built-in ones
+// and returns the custom validation functions to run for each parameter key.
+func (m *ManagerHandler) splitCustomValidations(validations []vm.Validation) ([]vm.Validation, map[string][]model.ValidationFunc) {
+	builtInValidations := make([]vm.Validation, 0, len(validations))
+	customValidations := map[string][]model.ValidationFunc{}
+
+	for _, validation := range validations {
+		if fn, ok := m.validationFuncs[string(validation.Type)]; ok {
+			validation.Type = vm.String
+			customValidations[validation.Key] = append(customValidations[validation.Key], fn)
+		}
+
+		// Requirements with alternatives are left to the built-in validator
+		if !strings.Contains(validation.Requirement, "||") {
+			var requirements []string
+			var funcs []model.ValidationFunc
+			for _, requirement := range strings.Split(validation.Requirement, "&&") {
+				requirement = strings.TrimSpace(requirement)
+				if fn, ok := m.validationFuncs[requirement]; ok {
+					funcs = append(funcs, fn)
+				} else if requirement != "" {
+					requirements = append(requirements, requirement)
+				}
+			}
+			if len(funcs) > 0 {
+				customValidations[validation.Key] = append(customValidations[validation.Key], funcs...)
+				validation.Requirement = "-"
+				if len(requirements) > 0 {
+					validation.Requirement = strings.Join(requirements, " && ")
+				}
+			}
+		}
+
+		builtInValidations = append(builtInValidations, validation)
+	}
+
+	return builtInValidations, customValidations
+}
+
+// validationTypes returns the built-in validator types followed by the sorted names of the custom validations,
+// they are the types of the parameter rows of the task forms.
+func (m *ManagerHandler) validationTypes() []string {
+	types := make([]string, 0, len(builtinValidatorTypes)+len(m.validationFuncs))
+	for _, validatorType := range builtinValidatorTypes {
+		types = append(types, string(validatorType))
+	}
+
+	customTypes := make([]string, 0, len(m.validationFuncs))
+	for name := range m.validationFuncs {
+		customTypes = append(customTypes, name)
+	}
+	sort.Strings(customTypes)
+
+	return append(types, customTypes...)
+}
+
+// validationsFromForm parses the parameter rows with the given prefix of a task form into validations.
+// Each row submits the fields <prefix>_key, <prefix>_type, <prefix>_condition, <prefix>_value and <prefix>_optional,
+// the hidden field <prefix>_rows marks that the form has parameter rows. It returns false if the form has no
+// parameter rows with the prefix, eg. for API requests with the validations as JSON.
+func (m *ManagerHandler) validationsFromForm(form url.Values, prefix string) ([]vm.Validation, bool, error) {
+	if _, ok := form[prefix+"_rows"]; !ok {
+		return nil, false, nil
+	}
+
+	keys := form[prefix+"_key"]
+	types := form[prefix+"_type"]
+	conditions := form[prefix+"_condition"]
+	values := form[prefix+"_value"]
+	optionals := form[prefix+"_optional"]
+	if len(types) != len(keys) || len(conditions) != len(keys) || len(values) != len(keys) || len(optionals) != len(keys) {
+		return nil, true, fmt.Errorf("incomplete parameter rows")
+	}
+
+	validationTypes := m.validationTypes()
+	validations := make([]vm.Validation, 0, len(keys))
+	for i, key := range keys {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, true, fmt.Errorf("parameter %d has no key", i+1)
+		}
+		if slices.ContainsFunc(validations, func(v vm.Validation) bool { return v.Key == key }) {
+			return nil, true, fmt.Errorf("duplicate parameter key %q", key)
+		}
+		if !slices.Contains(validationTypes, types[i]) {
+			return nil, true, fmt.Errorf("invalid type %q of parameter %q", types[i], key)
+		}
+
+		requirement, err := helper.JoinRequirement(conditions[i], values[i])
+		if err != nil {
+			return nil, true, fmt.Errorf("invalid requirement of parameter %q: %w", key, err)
+		}
+
+		validations = append(validations, vm.Validation{
+			Key:         key,
+			Type:        vm.ValidatorType(types[i]),
+			Requirement: requirement,
+			OmitEmpty:   optionals[i] == "true",
+		})
+	}
+
+	return validations, true, nil
+}
+
+// keepValidationDetails keeps the settings of the existing validations the parameter rows can not edit
+// (groups, default and inner validation) for parameters with the same key and type.
+func keepValidationDetails(validations []vm.Validation, existing []vm.Validation) []vm.Validation {
+	for i, validation := range validations {
+		index := slices.IndexFunc(existing, func(v vm.Validation) bool { return v.Key == validation.Key })
+		if index >= 0 && existing[index].Type == validation.Type {
+			validations[i].Groups = existing[index].Groups
+			validations[i].Default = existing[index].Default
+			validations[i].InnerValidation = existing[index].InnerValidation
+		}
+	}
+	return validations
+}
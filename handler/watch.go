@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/siherrmann/queuerManager/helper"
+	qmModel "github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+)
+
+// WATCH_EVENT is the event of the watch event stream, it carries the notification as JSON.
+const WATCH_EVENT = "watch"
+
+// WatchStream fans the notifications of the watches out to the subscribed watch event streams of their users.
+// It also remembers the last status of the changed jobs, so watchers are only notified about changed statuses.
+type WatchStream struct {
+	mu          sync.Mutex
+	subscribers map[chan *qmModel.WatchNotification]string
+	statuses    map[uuid.UUID]string
+}
+
+// NewWatchStream creates a new instance of WatchStream.
+func NewWatchStream() *WatchStream {
+	return &WatchStream{
+		subscribers: map[chan *qmModel.WatchNotification]string{},
+		statuses:    map[uuid.UUID]string{},
+	}
+}
+
+// Subscribe subscribes to the notifications of the user, the returned function unsubscribes again.
+func (s *WatchStream) Subscribe(username string) (<-chan *qmModel.WatchNotification, func()) {
+	notifications := make(chan *qmModel.WatchNotification, jobStreamBuffer)
+
+	s.mu.Lock()
+	s.subscribers[notifications] = username
+	s.mu.Unlock()
+
+	return notifications, func() {
+		s.mu.Lock()
+		delete(s.subscribers, notifications)
+		s.mu.Unlock()
+	}
+}
+
+// Publish sends a notification to the subscribers of its user without blocking.
+func (s *WatchStream) Publish(notification *qmModel.WatchNotification) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for notifications, username := range s.subscribers {
+		if username != notification.Username {
+			continue
+		}
+		select {
+		case notifications <- notification:
+		default:
+		}
+	}
+}
+
+// statusChanged reports if the status of a job differs from its last status, the statuses of ended jobs are forgotten.
+func (s *WatchStream) statusChanged(job *model.Job) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lastStatus, ok := s.statuses[job.RID]
+	switch job.Status {
+	case model.JobStatusSucceeded, model.JobStatusFailed, model.JobStatusCancelled:
+		delete(s.statuses, job.RID)
+	default:
+		s.statuses[job.RID] = job.Status
+	}
+	return !ok || lastStatus != job.Status
+}
+
+// watchTargetFromContext reads the target type and target of a watch from the query or form.
+// Job targets must be job RIDs and task targets the keys of existing tasks.
+func (m *ManagerHandler) watchTargetFromContext(c *echo.Context) (string, string, error) {
+	targetType := c.FormValue("target_type")
+	target := c.FormValue("target")
+
+	switch targetType {
+	case qmModel.WATCH_TARGET_JOB:
+		rid, err := uuid.Parse(target)
+		if err != nil {
+			return "", "", fmt.Errorf("Invalid job RID: %v", err)
+		}
+		return targetType, rid.String(), nil
+	case qmModel.WATCH_TARGET_TASK:
+		if _, err := m.taskDB.SelectTaskByKey(target); err != nil {
+			return "", "", fmt.Errorf("Task %s not found", target)
+		}
+		return targetType, target, nil
+	default:
+		return "", "", fmt.Errorf("Invalid target type (must be job or task)")
+	}
+}
+
+// NotifyJobWatchers notifies the watchers of a changed job and of its task if its status changed.
+// The notifications are sent to the watch event streams of the users and by email for watches with email.
+func (m *ManagerHandler) NotifyJobWatchers(job *model.Job) error {
+	if m.WatchDB == nil || job == nil || !m.WatchStream.statusChanged(job) {
+		return nil
+	}
+
+	watches, err := m.WatchDB.SelectWatchesOfJob(job.RID, job.TaskName)
+	if err != nil {
+		return fmt.Errorf("failed to select watches of job %s: %w", job.RID, err)
+	}
+
+	baseURL := helper.GetEnvOrDefault("QUEUER_MANAGER_BASE_URL", "")
+	notified := map[string]bool{}
+	for _, watch := range watches {
+		// A user watching the job and its task is notified once
+		if notified[watch.Username] {
+			continue
+		}
+		notified[watch.Username] = true
+
+		notification := qmModel.NewWatchNotification(watch, job, baseURL)
+		m.WatchStream.Publish(notification)
+		if watch.Email {
+			m.emailWatchNotification(notification)
+		}
+	}
+
+	return nil
+}
+
+// emailWatchNotification sends a watch notification to the email address of its user without blocking the caller.
+func (m *ManagerHandler) emailWatchNotification(notification *qmModel.WatchNotification) {
+	if m.Mailer == nil || m.UserDB == nil {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		user, err := m.UserDB.SelectUserByUsername(notification.Username)
+		if err != nil || user.Email == "" {
+			log.Printf("Error sending watch notification of job %s: no email address of user %s", notification.JobRID, notification.Username)
+			return
+		}
+
+		email, err := m.MessageTemplates.Render(qmModel.MESSAGE_TEMPLATE_WATCH_EMAIL, notification)
+		if err == nil {
+			err = m.Mailer.Send(ctx, user.Email, email.Subject, email.Body)
+		}
+		m.Status.Record(qmModel.SUBSYSTEM_NOTIFIER, err)
+		if err != nil {
+			log.Printf("Error sending watch notification of job %s to %s: %v", notification.JobRID, notification.Username, err)
+		}
+	}()
+}
+
+// =======API Handlers=======
+
+// AddWatch lets the user watch a job or the jobs of a task, eg. /api/watch/addWatch?target_type=task&target=task_key&email=true.
+// Watching a target again updates if the user is notified by email.
+func (m *ManagerHandler) AddWatch(c *echo.Context) error {
+	targetType, target, err := m.watchTargetFromContext(c)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+
+	email := false
+	if emailStr := c.FormValue("email"); emailStr != "" {
+		email, err = strconv.ParseBool(emailStr)
+		if err != nil {
+			return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid email option: %v", err))
+		}
+	}
+	if email && (m.Mailer == nil || m.UserDB == nil) {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Email notifications are not configured")
+	}
+
+	watch, err := m.WatchDB.UpsertWatch(&qmModel.Watch{
+		Username:   requestedBy(c),
+		TargetType: targetType,
+		Target:     target,
+		Email:      email,
+	})
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to watch %s: %v", targetType, err))
+	}
+
+	if c.Request().Header.Get("HX-Request") == "" {
+		return c.JSON(http.StatusOK, watch)
+	}
+	return renderPopupOrJson(c, http.StatusOK, fmt.Sprintf("Watching %s %s", targetType, target))
+}
+
+// DeleteWatch stops the user from watching a job or task, eg. /api/watch/deleteWatch?target_type=job&target=...
+func (m *ManagerHandler) DeleteWatch(c *echo.Context) error {
+	targetType, target, err := m.watchTargetFromContext(c)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, err.Error())
+	}
+
+	deleted, err := m.WatchDB.DeleteWatch(requestedBy(c), targetType, target)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to unwatch %s: %v", targetType, err))
+	}
+	if !deleted {
+		return renderPopupOrJson(c, http.StatusNotFound, fmt.Sprintf("Not watching %s %s", targetType, target))
+	}
+
+	return renderPopupOrJson(c, http.StatusOK, fmt.Sprintf("Stopped watching %s %s", targetType, target))
+}
+
+// GetWatches returns the watches of the user.
+func (m *ManagerHandler) GetWatches(c *echo.Context) error {
+	watches, err := m.WatchDB.SelectWatchesByUser(requestedBy(c))
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to retrieve watches")
+	}
+
+	return c.JSON(http.StatusOK, watches)
+}
+
+// WatchEvents streams the notifications of the watches of the user as server-sent events until the client disconnects.
+func (m *ManagerHandler) WatchEvents(c *echo.Context) error {
+	notifications, unsubscribe := m.WatchStream.Subscribe(requestedBy(c))
+	defer unsubscribe()
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-cache")
+	c.Response().Header().Set(echo.HeaderConnection, "keep-alive")
+	c.Response().Header().Set("X-Accel-Buffering", "no")
+	c.Response().WriteHeader(http.StatusOK)
+
+	responseController := http.NewResponseController(c.Response())
+	if err := responseController.Flush(); err != nil {
+		return err
+	}
+
+	keepAlive := time.NewTicker(jobStreamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-m.ShuttingDown():
+			return nil
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(c.Response(), ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+		case notification := <-notifications:
+			data, err := json.Marshal(notification)
+			if err != nil {
+				log.Printf("Error encoding watch notification of job %s: %v", notification.JobRID, err)
+				continue
+			}
+			if err := writeServerSentEvent(c.Response(), WATCH_EVENT, string(data)); err != nil {
+				return nil
+			}
+		}
+
+		if err := responseController.Flush(); err != nil {
+			return nil
+		}
+	}
+}
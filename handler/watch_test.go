@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/model"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchStream(t *testing.T) {
+	t.Run("Publish sends the notification to the subscribers of its user", func(t *testing.T) {
+		stream := NewWatchStream()
+		aliceNotifications, unsubscribeAlice := stream.Subscribe("alice")
+		defer unsubscribeAlice()
+		bobNotifications, unsubscribeBob := stream.Subscribe("bob")
+		defer unsubscribeBob()
+
+		notification := &qmModel.WatchNotification{Username: "alice", JobRID: uuid.New()}
+		stream.Publish(notification)
+		assert.Equal(t, notification, <-aliceNotifications)
+		assert.Empty(t, bobNotifications, "Expected no notification of another user")
+	})
+
+	t.Run("statusChanged only reports changed statuses", func(t *testing.T) {
+		stream := NewWatchStream()
+		job := &model.Job{RID: uuid.New(), Status: model.JobStatusQueued}
+
+		assert.True(t, stream.statusChanged(job), "Expected the first status to be a change")
+		assert.False(t, stream.statusChanged(job), "Expected the same status to not be a change")
+
+		job.Status = model.JobStatusRunning
+		assert.True(t, stream.statusChanged(job))
+
+		job.Status = model.JobStatusSucceeded
+		assert.True(t, stream.statusChanged(job))
+		assert.Empty(t, stream.statuses, "Expected the status of an ended job to be forgotten")
+	})
+}
+
+func TestNewWatchNotification(t *testing.T) {
+	watch := &qmModel.Watch{Username: "alice", TargetType: qmModel.WATCH_TARGET_TASK, Target: "export"}
+	job := &model.Job{RID: uuid.New(), TaskName: "export", Status: model.JobStatusFailed, Error: "timeout"}
+
+	notification := qmModel.NewWatchNotification(watch, job, "https://manager.example.com/")
+	assert.Equal(t, "alice", notification.Username)
+	assert.Equal(t, "timeout", notification.Error)
+	assert.Equal(t, "https://manager.example.com/job?rid="+job.RID.String(), notification.URL)
+	assert.Contains(t, notification.Message, "export")
+}
@@ -3,8 +3,9 @@ package handler
 import (
 	"fmt"
 	"net/http"
-	"strconv"
 
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/components"
 	"github.com/siherrmann/queuerManager/view/screens"
 
 	"github.com/google/uuid"
@@ -30,35 +31,30 @@ func (m *ManagerHandler) GetWorker(c *echo.Context) error {
 
 // GetWorkers retrieves a paginated list of workers
 func (m *ManagerHandler) GetWorkers(c *echo.Context) error {
-	lastIdStr := c.QueryParam("lastId")
-	limitStr := c.QueryParam("limit")
-
-	// Parse lastId with default
-	lastId := 0
-	if lastIdStr != "" {
-		parsedLastId, err := strconv.Atoi(lastIdStr)
-		if err != nil || parsedLastId < 0 {
-			return c.String(http.StatusBadRequest, "Invalid lastId format")
-		}
-		lastId = parsedLastId
+	lastId, limit, err := m.apiPagination(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
 	}
 
-	// Parse limit with default
-	limit := 100
-	if limitStr != "" {
-		parsedLimit, err := strconv.Atoi(limitStr)
-		if err != nil || parsedLimit <= 0 || parsedLimit > 100 {
-			return c.String(http.StatusBadRequest, "Invalid limit (must be 1-100)")
-		}
-		limit = parsedLimit
+	envelope := withEnvelope(c)
+	if envelope && m.MetricDB == nil {
+		return c.String(http.StatusServiceUnavailable, "Worker counts are not available")
 	}
 
-	workers, err := m.Queuer.GetWorkers(lastId, limit)
+	workers, err := m.Queuer.GetWorkers(lastId, pageLimit(limit, envelope))
 	if err != nil {
 		return c.String(http.StatusInternalServerError, "Failed to retrieve workers")
 	}
+	if !envelope {
+		return c.JSON(http.StatusOK, workers)
+	}
+
+	total, err := m.MetricDB.SelectWorkersCount()
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to count workers")
+	}
 
-	return c.JSON(http.StatusOK, workers)
+	return c.JSON(http.StatusOK, newPage(workers, limit, total, func(worker *model.Worker) int { return worker.ID }))
 }
 
 // =======View Handlers=======
@@ -86,34 +82,42 @@ func (m *ManagerHandler) WorkerView(c *echo.Context) error {
 	return render(c, screens.Worker(worker))
 }
 
+// WorkerTabView renders a tab panel of the worker view, the panels are loaded when their tab is opened.
+func (m *ManagerHandler) WorkerTabView(c *echo.Context) error {
+	rid, err := uuid.Parse(c.QueryParam("rid"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid worker RID: %v", err))
+	}
+
+	worker, err := m.Queuer.GetWorker(rid)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "Worker not found")
+	}
+
+	switch c.Param("tab") {
+	case "overview":
+		return render(c, screens.WorkerOverview(worker))
+	case "logs":
+		return render(c, screens.WorkerLogs(worker))
+	case "history":
+		return m.renderJobHistory(c, &qmModel.JobArchiveFilter{WorkerRID: worker.RID})
+	case "raw":
+		return render(c, components.JsonCodeView(worker))
+	default:
+		return renderPopupOrJson(c, http.StatusNotFound, "Tab not found")
+	}
+}
+
 // WorkersView renders the workers list page
 func (m *ManagerHandler) WorkersView(c *echo.Context) error {
-	lastIdStr := c.QueryParam("lastId")
-	limitStr := c.QueryParam("limit")
 	search := c.QueryParam("search")
 
-	// Parse lastId with default
-	lastId := 0
-	if lastIdStr != "" {
-		parsedLastId, err := strconv.Atoi(lastIdStr)
-		if err != nil || parsedLastId < 0 {
-			return c.String(http.StatusBadRequest, "Invalid lastId format")
-		}
-		lastId = parsedLastId
-	}
-
-	// Parse limit with default
-	limit := 1000
-	if limitStr != "" {
-		parsedLimit, err := strconv.Atoi(limitStr)
-		if err != nil || parsedLimit <= 0 || parsedLimit > 100 {
-			return c.String(http.StatusBadRequest, "Invalid limit (must be 1-100)")
-		}
-		limit = parsedLimit
+	lastId, limit, err := m.viewPagination(c)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
 	}
 
 	var workers []*model.Worker
-	var err error
 	if search != "" {
 		workers, err = m.Queuer.GetWorkersBySearch(search, lastId, limit)
 		if err != nil {
@@ -129,7 +133,7 @@ func (m *ManagerHandler) WorkersView(c *echo.Context) error {
 	c.Response().Header().Add("HX-Push-Url", fmt.Sprintf("/workers?search=%s&limit=%d&lastId=%d", search, limit, lastId))
 	c.Response().Header().Add("HX-Retarget", "#body")
 
-	return render(c, screens.Workers(workers, search))
+	return renderStream(c, screens.Workers(workers, search))
 }
 
 // StopWorkersView handles stopping workers
@@ -154,6 +158,7 @@ func (m *ManagerHandler) StopWorkersView(c *echo.Context) error {
 		if err != nil {
 			return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to stop worker %s: %v", rid, err))
 		}
+		m.publishEvent(qmModel.EVENT_WORKER_STOPPED, map[string]any{"rid": rid})
 	}
 
 	return renderPopupOrJson(c, http.StatusOK, fmt.Sprintf("Successfully requested stop for %d worker(s)", len(rids)))
@@ -181,6 +186,7 @@ func (m *ManagerHandler) StopWorkersGracefullyView(c *echo.Context) error {
 		if err != nil {
 			return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to gracefully stop worker %s: %v", rid, err))
 		}
+		m.publishEvent(qmModel.EVENT_WORKER_STOPPED_GRACEFULLY, map[string]any{"rid": rid})
 	}
 
 	return renderPopupOrJson(c, http.StatusOK, fmt.Sprintf("Successfully requested graceful stop for %d worker(s)", len(rids)))
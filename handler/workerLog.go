@@ -0,0 +1,282 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/view/screens"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/model"
+)
+
+// maxWorkerLogBytes is the maximum size of the end of a worker log that is read to tail its lines
+const maxWorkerLogBytes = 1 << 20
+
+// WorkerLogSource reads the recent log output of a worker.
+// Extensions can set their own implementation on the manager handler, eg. to query a log aggregator.
+type WorkerLogSource interface {
+	TailLogs(ctx context.Context, worker *model.Worker, lines int) ([]string, error)
+}
+
+// HTTPWorkerLogSource tails the logs from a log endpoint exposed by the workers.
+// The URL template can contain {name} and {rid}, they are replaced by the name and RID of the worker.
+// The number of requested lines is sent as the lines query parameter, the endpoint responds with plain text lines.
+type HTTPWorkerLogSource struct {
+	URLTemplate string
+	Client      *http.Client
+}
+
+// FileWorkerLogSource tails the logs the workers write to <name>.log in a shared directory.
+type FileWorkerLogSource struct {
+	Dir string
+}
+
+// NewWorkerLogSourceFromEnv creates a worker log source from QUEUER_MANAGER_WORKER_LOG_URL
+// or else QUEUER_MANAGER_WORKER_LOG_DIR. It returns nil if neither is set.
+func NewWorkerLogSourceFromEnv() WorkerLogSource {
+	if urlTemplate := os.Getenv("QUEUER_MANAGER_WORKER_LOG_URL"); urlTemplate != "" {
+		return &HTTPWorkerLogSource{
+			URLTemplate: urlTemplate,
+			Client:      &http.Client{Timeout: 10 * time.Second},
+		}
+	}
+	if dir := os.Getenv("QUEUER_MANAGER_WORKER_LOG_DIR"); dir != "" {
+		return &FileWorkerLogSource{Dir: dir}
+	}
+	return nil
+}
+
+// TailLogs requests the last lines of the worker log from the log endpoint of the worker
+func (s *HTTPWorkerLogSource) TailLogs(ctx context.Context, worker *model.Worker, lines int) ([]string, error) {
+	logURL, err := url.Parse(strings.NewReplacer(
+		"{name}", url.PathEscape(worker.Name),
+		"{rid}", worker.RID.String(),
+	).Replace(s.URLTemplate))
+	if err != nil {
+		return nil, fmt.Errorf("invalid worker log URL: %w", err)
+	}
+	query := logURL.Query()
+	query.Set("lines", strconv.Itoa(lines))
+	logURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, logURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating worker log request: %w", err)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting worker log: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("worker log endpoint responded with status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxWorkerLogBytes))
+	if err != nil {
+		return nil, fmt.Errorf("error reading worker log: %w", err)
+	}
+	return lastLines(body, lines), nil
+}
+
+// TailLogs reads the last lines of the log file of the worker, only the end of large files is read
+func (s *FileWorkerLogSource) TailLogs(ctx context.Context, worker *model.Worker, lines int) ([]string, error) {
+	fileName := worker.Name + ".log"
+	if filepath.Base(fileName) != fileName || strings.HasPrefix(fileName, ".") {
+		return nil, fmt.Errorf("invalid worker name for a log file: %s", worker.Name)
+	}
+
+	file, err := os.Open(filepath.Join(s.Dir, fileName))
+	if err != nil {
+		return nil, fmt.Errorf("error opening worker log: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error reading worker log: %w", err)
+	}
+	offset := max(info.Size()-maxWorkerLogBytes, 0)
+
+	content := make([]byte, info.Size()-offset)
+	_, err = file.ReadAt(content, offset)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("error reading worker log: %w", err)
+	}
+
+	// The first line is cut if the file is larger than the read end
+	if offset > 0 {
+		if index := bytes.IndexByte(content, '\n'); index >= 0 {
+			content = content[index+1:]
+		}
+	}
+	return lastLines(content, lines), nil
+}
+
+// lastLines returns the last non empty lines of the content
+func lastLines(content []byte, lines int) []string {
+	result := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxWorkerLogBytes)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line != "" {
+			result = append(result, line)
+		}
+	}
+	if len(result) > lines {
+		result = result[len(result)-lines:]
+	}
+	return result
+}
+
+// workerLogLevel detects the level of a log line, eg. of slog text (level=INFO) or JSON ("level":"info")
+// output or a leading level word like [ERROR]. It returns an empty level if none is recognized.
+func workerLogLevel(line string) string {
+	lower := strings.ToLower(line)
+	for _, prefix := range []string{"level=", `"level":"`, `"level": "`} {
+		if index := strings.Index(lower, prefix); index >= 0 {
+			value := strings.FieldsFunc(lower[index+len(prefix):], func(r rune) bool { return r < 'a' || r > 'z' })
+			if len(value) > 0 {
+				return normalizeWorkerLogLevel(value[0])
+			}
+		}
+	}
+
+	fields := strings.Fields(lower)
+	for _, field := range fields[:min(len(fields), 4)] {
+		if level := normalizeWorkerLogLevel(strings.Trim(field, "[]:")); level != "" {
+			return level
+		}
+	}
+	return ""
+}
+
+// normalizeWorkerLogLevel returns the worker log level of a level name, empty if it is no level name.
+func normalizeWorkerLogLevel(level string) string {
+	switch level {
+	case "debug", "trace":
+		return qmModel.WORKER_LOG_LEVEL_DEBUG
+	case "info":
+		return qmModel.WORKER_LOG_LEVEL_INFO
+	case "warn", "warning":
+		return qmModel.WORKER_LOG_LEVEL_WARN
+	case "error", "err", "fatal", "panic":
+		return qmModel.WORKER_LOG_LEVEL_ERROR
+	default:
+		return ""
+	}
+}
+
+// filterWorkerLogLines detects the levels of the lines and keeps the lines of the minimum level and above.
+// Lines without a level belong to the previous line, so a stack trace is kept with its error.
+func filterWorkerLogLines(lines []string, minLevel string) []qmModel.WorkerLogLine {
+	minIndex := max(slices.Index(qmModel.WORKER_LOG_LEVELS, minLevel), 0)
+
+	logLines := []qmModel.WorkerLogLine{}
+	previousLevel := ""
+	for _, line := range lines {
+		level := workerLogLevel(line)
+		effectiveLevel := level
+		if effectiveLevel == "" {
+			effectiveLevel = previousLevel
+		}
+		previousLevel = effectiveLevel
+
+		if minIndex > 0 && slices.Index(qmModel.WORKER_LOG_LEVELS, effectiveLevel) < minIndex {
+			continue
+		}
+		logLines = append(logLines, qmModel.WorkerLogLine{Line: line, Level: level})
+	}
+	return logLines
+}
+
+// tailWorkerLogs reads the recent log lines of the worker filtered by the level and lines query parameters.
+func (m *ManagerHandler) tailWorkerLogs(c *echo.Context, worker *model.Worker) ([]qmModel.WorkerLogLine, int, error) {
+	if m.WorkerLogs == nil {
+		return nil, http.StatusServiceUnavailable, fmt.Errorf("Worker logs are not configured")
+	}
+
+	level := c.QueryParam("level")
+	if level != "" && !slices.Contains(qmModel.WORKER_LOG_LEVELS, level) {
+		return nil, http.StatusBadRequest, fmt.Errorf("Invalid level (must be one of %s)", strings.Join(qmModel.WORKER_LOG_LEVELS, ", "))
+	}
+
+	lines := qmModel.DEFAULT_WORKER_LOG_LINES
+	if linesStr := c.QueryParam("lines"); linesStr != "" {
+		parsedLines, err := strconv.Atoi(linesStr)
+		if err != nil || parsedLines < 1 || parsedLines > qmModel.MAX_WORKER_LOG_LINES {
+			return nil, http.StatusBadRequest, fmt.Errorf("Invalid lines (must be 1-%d)", qmModel.MAX_WORKER_LOG_LINES)
+		}
+		lines = parsedLines
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 15*time.Second)
+	defer cancel()
+
+	logLines, err := m.WorkerLogs.TailLogs(ctx, worker, lines)
+	if err != nil {
+		return nil, http.StatusBadGateway, fmt.Errorf("Failed to read worker logs: %v", err)
+	}
+	return filterWorkerLogLines(logLines, level), http.StatusOK, nil
+}
+
+// =======API Handlers=======
+
+// GetWorkerLogs returns the recent log lines of a worker, eg. /api/worker/getWorkerLogs/:rid?level=warn&lines=100
+func (m *ManagerHandler) GetWorkerLogs(c *echo.Context) error {
+	rid, err := uuid.Parse(c.Param("rid"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid worker RID format")
+	}
+
+	worker, err := m.Queuer.GetWorker(rid)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Worker not found")
+	}
+
+	logLines, status, err := m.tailWorkerLogs(c, worker)
+	if err != nil {
+		return c.String(status, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, logLines)
+}
+
+// =======View Handlers=======
+
+// WorkerLogsView renders the recent log lines of a worker in the logs tab of the worker view
+func (m *ManagerHandler) WorkerLogsView(c *echo.Context) error {
+	rid, err := uuid.Parse(c.QueryParam("rid"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid worker RID: %v", err))
+	}
+
+	worker, err := m.Queuer.GetWorker(rid)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "Worker not found")
+	}
+
+	logLines, _, err := m.tailWorkerLogs(c, worker)
+	if err != nil {
+		return render(c, screens.WorkerLogLines(nil, err.Error()))
+	}
+
+	return render(c, screens.WorkerLogLines(logLines, ""))
+}
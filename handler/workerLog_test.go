@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerLogLevel(t *testing.T) {
+	tests := map[string]string{
+		`time=2026-01-01T10:00:00Z level=INFO msg="job started"`:      qmModel.WORKER_LOG_LEVEL_INFO,
+		`{"time":"2026-01-01T10:00:00Z","level":"WARN","msg":"slow"}`: qmModel.WORKER_LOG_LEVEL_WARN,
+		`2026/01/01 10:00:00 [ERROR] job failed`:                      qmModel.WORKER_LOG_LEVEL_ERROR,
+		`DEBUG: polling for jobs`:                                     qmModel.WORKER_LOG_LEVEL_DEBUG,
+		`	at main.run(main.go:12)`:                                    "",
+	}
+	for line, expectedLevel := range tests {
+		assert.Equal(t, expectedLevel, workerLogLevel(line), line)
+	}
+}
+
+func TestFilterWorkerLogLines(t *testing.T) {
+	lines := []string{
+		"level=DEBUG msg=polling",
+		"level=INFO msg=started",
+		"level=ERROR msg=failed",
+		"	at main.run(main.go:12)",
+		"level=WARN msg=slow",
+	}
+
+	logLines := filterWorkerLogLines(lines, "")
+	require.Len(t, logLines, 5)
+	assert.Equal(t, "", logLines[3].Level)
+
+	logLines = filterWorkerLogLines(lines, qmModel.WORKER_LOG_LEVEL_WARN)
+	require.Len(t, logLines, 3)
+	assert.Equal(t, "level=ERROR msg=failed", logLines[0].Line)
+	assert.Equal(t, "	at main.run(main.go:12)", logLines[1].Line, "Expected the stack trace to be kept with its error")
+	assert.Equal(t, qmModel.WORKER_LOG_LEVEL_WARN, logLines[2].Level)
+}
+
+func TestFileWorkerLogSource(t *testing.T) {
+	dir := t.TempDir()
+	lines := []string{}
+	for i := range 10 {
+		lines = append(lines, fmt.Sprintf("level=INFO msg=line%d", i))
+	}
+	err := os.WriteFile(filepath.Join(dir, "image-worker.log"), []byte(strings.Join(lines, "\n")+"\n"), 0o644)
+	require.NoError(t, err)
+
+	source := &FileWorkerLogSource{Dir: dir}
+
+	t.Run("Tails the last lines", func(t *testing.T) {
+		logLines, err := source.TailLogs(context.Background(), &model.Worker{Name: "image-worker"}, 3)
+		require.NoError(t, err)
+		assert.Equal(t, lines[7:], logLines)
+	})
+
+	t.Run("Missing log file", func(t *testing.T) {
+		_, err := source.TailLogs(context.Background(), &model.Worker{Name: "other-worker"}, 3)
+		assert.Error(t, err)
+	})
+
+	t.Run("Worker names can not leave the directory", func(t *testing.T) {
+		_, err := source.TailLogs(context.Background(), &model.Worker{Name: "../image-worker"}, 3)
+		assert.ErrorContains(t, err, "invalid worker name")
+	})
+}
+
+func TestHTTPWorkerLogSource(t *testing.T) {
+	var requestedPath, requestedLines string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+		requestedLines = r.URL.Query().Get("lines")
+		fmt.Fprint(w, "level=INFO msg=one\nlevel=INFO msg=two\nlevel=ERROR msg=three\n")
+	}))
+	defer server.Close()
+
+	worker := &model.Worker{RID: uuid.New(), Name: "image-worker"}
+	source := &HTTPWorkerLogSource{URLTemplate: server.URL + "/logs/{name}/{rid}", Client: server.Client()}
+
+	logLines, err := source.TailLogs(context.Background(), worker, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"level=INFO msg=two", "level=ERROR msg=three"}, logLines)
+	assert.Equal(t, "/logs/image-worker/"+worker.RID.String(), requestedPath)
+	assert.Equal(t, "2", requestedLines)
+
+	t.Run("Error status of the log endpoint", func(t *testing.T) {
+		failingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer failingServer.Close()
+
+		source := &HTTPWorkerLogSource{URLTemplate: failingServer.URL, Client: failingServer.Client()}
+		_, err := source.TailLogs(context.Background(), worker, 2)
+		assert.EqualError(t, err, "worker log endpoint responded with status 404")
+	})
+}
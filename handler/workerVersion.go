@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/siherrmann/queuerManager/helper"
+	"github.com/siherrmann/queuerManager/model"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	queuerModel "github.com/siherrmann/queuer/model"
+)
+
+// validateWorkerVersionSettings checks the minimum worker version and policy of a task
+// and sets the default policy if it is empty.
+func validateWorkerVersionSettings(task *model.Task) error {
+	if task.MinWorkerVersion != "" {
+		_, err := helper.CompareVersions(task.MinWorkerVersion, task.MinWorkerVersion)
+		if err != nil {
+			return fmt.Errorf("invalid min_worker_version: %v", err)
+		}
+	}
+
+	switch task.WorkerVersionPolicy {
+	case "":
+		task.WorkerVersionPolicy = model.WORKER_VERSION_POLICY_WARN
+	case model.WORKER_VERSION_POLICY_WARN, model.WORKER_VERSION_POLICY_BLOCK:
+	default:
+		return fmt.Errorf("invalid worker_version_policy (must be warn or block)")
+	}
+
+	return nil
+}
+
+// checkWorkerVersion checks if at least one connected (ready or running) worker having the task
+// reported a version satisfying the minimum worker version of the task.
+// It returns a message if no worker satisfies it and if adding the job should be blocked.
+func (m *ManagerHandler) checkWorkerVersion(task *model.Task) (string, bool, error) {
+	if task.MinWorkerVersion == "" {
+		return "", false, nil
+	}
+
+	workers, err := m.Queuer.GetWorkers(0, 1000)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to retrieve workers: %v", err)
+	}
+
+	workerRIDs := []uuid.UUID{}
+	for _, worker := range workers {
+		if worker.Status != queuerModel.WorkerStatusReady && worker.Status != queuerModel.WorkerStatusRunning {
+			continue
+		}
+		if !slices.Contains(worker.AvailableTasks, task.Key) {
+			continue
+		}
+		workerRIDs = append(workerRIDs, worker.RID)
+	}
+
+	if len(workerRIDs) > 0 && m.WorkerVersionDB != nil {
+		versions, err := m.WorkerVersionDB.SelectWorkerVersions(workerRIDs)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to retrieve worker versions: %v", err)
+		}
+		for _, version := range versions {
+			comparison, err := helper.CompareVersions(version, task.MinWorkerVersion)
+			if err == nil && comparison >= 0 {
+				return "", false, nil
+			}
+		}
+	}
+
+	message := fmt.Sprintf("No connected worker satisfies the minimum worker version %s of task %s", task.MinWorkerVersion, task.Key)
+	return message, task.WorkerVersionPolicy == model.WORKER_VERSION_POLICY_BLOCK, nil
+}
+
+// =======API Handlers=======
+
+// SetWorkerVersion stores the version of the task code of a worker, workers report it once they are started.
+func (m *ManagerHandler) SetWorkerVersion(c *echo.Context) error {
+	if m.WorkerVersionDB == nil {
+		return renderPopupOrJson(c, http.StatusServiceUnavailable, "Worker versions are not enabled")
+	}
+
+	rid, err := uuid.Parse(c.Param("rid"))
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, "Invalid worker RID format")
+	}
+
+	var requestData struct {
+		Version string `json:"version" form:"version"`
+	}
+	if err := c.Bind(&requestData); err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+	}
+	_, err = helper.CompareVersions(requestData.Version, requestData.Version)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusBadRequest, fmt.Sprintf("Invalid version: %v", err))
+	}
+
+	_, err = m.Queuer.GetWorker(rid)
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusNotFound, "Worker not found")
+	}
+
+	workerVersion, err := m.WorkerVersionDB.UpsertWorkerVersion(&model.WorkerVersion{WorkerRID: rid, Version: requestData.Version})
+	if err != nil {
+		return renderPopupOrJson(c, http.StatusInternalServerError, fmt.Sprintf("Failed to set worker version: %v", err))
+	}
+
+	return c.JSON(http.StatusOK, workerVersion)
+}
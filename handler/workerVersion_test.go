@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v5"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuer/model"
+	"github.com/siherrmann/queuerManager/database"
+	qmModel "github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/testutil/fake"
+	"github.com/siherrmann/queuerManager/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckWorkerVersion(t *testing.T) {
+	db := helper.NewDatabaseWithDB("worker_version", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	workerVersionDB, err := database.NewWorkerVersionDBHandler(db, true)
+	require.NoError(t, err)
+
+	queuer := fake.NewQueuer()
+	handler := NewManagerHandler(upload.NewFilesystemMemory(), fake.NewTaskDB(), queuer)
+	handler.WorkerVersionDB = workerVersionDB
+	e := echo.New()
+
+	task := &qmModel.Task{Key: "versioned-task", MinWorkerVersion: "1.4.0", WorkerVersionPolicy: qmModel.WORKER_VERSION_POLICY_BLOCK}
+	otherWorker := queuer.AddWorker(&model.Worker{Name: "other-worker", Status: model.WorkerStatusRunning, AvailableTasks: []string{"other-task"}})
+	worker := queuer.AddWorker(&model.Worker{Name: "worker", Status: model.WorkerStatusReady, AvailableTasks: []string{task.Key}})
+
+	setWorkerVersion := func(rid string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/worker/setWorkerVersion/"+rid, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "rid", Value: rid}})
+
+		err := handler.SetWorkerVersion(c)
+		require.NoError(t, err)
+		return rec
+	}
+
+	t.Run("Should block without a worker having the task reporting the version", func(t *testing.T) {
+		rec := setWorkerVersion(otherWorker.RID.String(), `{"version": "2.0.0"}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		message, block, err := handler.checkWorkerVersion(task)
+		require.NoError(t, err)
+		assert.True(t, block)
+		assert.Contains(t, message, "minimum worker version 1.4.0")
+	})
+
+	t.Run("Should block with an older version of the worker having the task", func(t *testing.T) {
+		rec := setWorkerVersion(worker.RID.String(), `{"version": "1.3.9"}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		_, block, err := handler.checkWorkerVersion(task)
+		require.NoError(t, err)
+		assert.True(t, block)
+	})
+
+	t.Run("Should pass with the version of the worker having the task", func(t *testing.T) {
+		rec := setWorkerVersion(worker.RID.String(), `{"version": "1.4.0"}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		message, block, err := handler.checkWorkerVersion(task)
+		require.NoError(t, err)
+		assert.False(t, block)
+		assert.Empty(t, message)
+	})
+
+	t.Run("Should reject an invalid version", func(t *testing.T) {
+		rec := setWorkerVersion(worker.RID.String(), `{"version": "latest"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
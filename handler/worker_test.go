@@ -228,6 +228,47 @@ func TestWorkerViewHandler(t *testing.T) {
 	})
 }
 
+func TestWorkerTabViewHandler(t *testing.T) {
+	fs := upload.NewFilesystemMemory()
+	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	tdb, err := database.NewTaskDBHandler(db, false)
+	require.NoError(t, err)
+
+	handler := NewManagerHandler(fs, tdb, queue)
+	e := echo.New()
+
+	workers, err := queue.GetWorkers(0, 1)
+	require.NoError(t, err)
+	require.Greater(t, len(workers), 0)
+	workerRID := workers[0].RID
+
+	t.Run("WorkerTabView renders each tab", func(t *testing.T) {
+		for _, tab := range []string{"overview", "history", "raw"} {
+			req := httptest.NewRequest(http.MethodGet, "/worker/tab/"+tab+"?rid="+workerRID.String(), nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPathValues([]echo.PathValue{{Name: "tab", Value: tab}})
+
+			err := handler.WorkerTabView(c)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, rec.Code, "Expected tab %s to render", tab)
+		}
+	})
+
+	t.Run("WorkerTabView with unknown tab", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/worker/tab/files?rid="+workerRID.String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPathValues([]echo.PathValue{{Name: "tab", Value: "files"}})
+
+		err := handler.WorkerTabView(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
 func TestWorkersViewHandler(t *testing.T) {
 	fs := upload.NewFilesystemMemory()
 	db := helper.NewDatabaseWithDB("taskdb", queue.DB, slog.New(slog.NewTextHandler(os.Stdout, nil)))
@@ -0,0 +1,32 @@
+package helper
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// API_KEY_PREFIX marks the API keys of the manager, eg. for secret scanners
+const API_KEY_PREFIX = "qm_"
+
+// apiKeyPrefixLength is the length of the key prefix stored to identify a key
+const apiKeyPrefixLength = len(API_KEY_PREFIX) + 8
+
+// NewAPIKey generates a random API key and returns it with its display prefix and hash.
+func NewAPIKey() (key string, prefix string, hash string, err error) {
+	secret := make([]byte, 32)
+	_, err = rand.Read(secret)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	key = API_KEY_PREFIX + base64.RawURLEncoding.EncodeToString(secret)
+	return key, key[:apiKeyPrefixLength], HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hex encoded SHA-256 hash of the key, the keys are random so no salt is needed.
+func HashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
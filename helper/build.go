@@ -0,0 +1,58 @@
+package helper
+
+import (
+	"runtime"
+	"runtime/debug"
+
+	"github.com/siherrmann/queuerManager/model"
+)
+
+// The build info is set with ldflags, eg.
+// -X github.com/siherrmann/queuerManager/helper.Version=v1.2.3
+// -X github.com/siherrmann/queuerManager/helper.Commit=$(git rev-parse --short HEAD)
+// -X github.com/siherrmann/queuerManager/helper.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)
+var (
+	Version   = ""
+	Commit    = ""
+	BuildTime = ""
+)
+
+// GetBuildInfo returns the build info of the running manager.
+// Values not set with ldflags are taken from the module and vcs info embedded by the go toolchain.
+func GetBuildInfo() model.BuildInfo {
+	buildInfo := model.BuildInfo{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if buildInfo.Version == "" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			buildInfo.Version = info.Main.Version
+		}
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if buildInfo.Commit == "" {
+					buildInfo.Commit = setting.Value
+				}
+			case "vcs.time":
+				if buildInfo.BuildTime == "" {
+					buildInfo.BuildTime = setting.Value
+				}
+			}
+		}
+	}
+
+	if buildInfo.Version == "" {
+		buildInfo.Version = "dev"
+	}
+	if buildInfo.Commit == "" {
+		buildInfo.Commit = "unknown"
+	}
+	if buildInfo.BuildTime == "" {
+		buildInfo.BuildTime = "unknown"
+	}
+	return buildInfo
+}
@@ -0,0 +1,130 @@
+package helper
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchLimit is how far ahead the next run of a cron expression is searched,
+// expressions without a run within it (eg. "0 0 31 2 *") never run.
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// CronSchedule is a parsed five field cron expression.
+// Every field is the set of allowed values, the day fields keep if they were restricted,
+// because a day matches either restricted day field like in standard cron.
+type CronSchedule struct {
+	minutes            map[int]bool
+	hours              map[int]bool
+	daysOfMonth        map[int]bool
+	months             map[int]bool
+	daysOfWeek         map[int]bool
+	daysOfMonthLimited bool
+	daysOfWeekLimited  bool
+}
+
+// ParseCron parses a standard five field cron expression, see ValidateCron for the supported syntax.
+func ParseCron(expression string) (*CronSchedule, error) {
+	err := ValidateCron(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.Fields(expression)
+	values := [5]map[int]bool{}
+	for i, field := range fields {
+		values[i] = expandCronField(field, cronFieldRanges[i][0], cronFieldRanges[i][1])
+	}
+
+	// Sunday is 0 and 7
+	if values[4][7] {
+		values[4][0] = true
+	}
+
+	return &CronSchedule{
+		minutes:            values[0],
+		hours:              values[1],
+		daysOfMonth:        values[2],
+		months:             values[3],
+		daysOfWeek:         values[4],
+		daysOfMonthLimited: !strings.HasPrefix(fields[2], "*"),
+		daysOfWeekLimited:  !strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+// Next returns the first time after the given time the schedule runs at, in the location of the given time.
+func (s *CronSchedule) Next(after time.Time) (time.Time, error) {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(cronSearchLimit)
+
+	for t.Before(limit) {
+		if !s.months[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.hours[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.minutes[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("cron expression has no run within %d years", int(cronSearchLimit.Hours()/24/366))
+}
+
+func (s *CronSchedule) matchesDay(t time.Time) bool {
+	dayOfMonth := s.daysOfMonth[t.Day()]
+	dayOfWeek := s.daysOfWeek[int(t.Weekday())]
+	if s.daysOfMonthLimited && s.daysOfWeekLimited {
+		return dayOfMonth || dayOfWeek
+	}
+	return dayOfMonth && dayOfWeek
+}
+
+// NextCronRun returns the first time after the given time the cron expression runs at.
+func NextCronRun(expression string, after time.Time) (time.Time, error) {
+	schedule, err := ParseCron(expression)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(after)
+}
+
+// expandCronField returns the values of a field that was checked with validateCronField.
+func expandCronField(field string, min int, max int) map[int]bool {
+	values := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			step, _ = strconv.Atoi(stepPart)
+		}
+
+		from, to := min, max
+		if rangePart != "*" {
+			fromPart, toPart, isRange := strings.Cut(rangePart, "-")
+			from, _ = strconv.Atoi(fromPart)
+			to = from
+			if isRange {
+				to, _ = strconv.Atoi(toPart)
+			} else if hasStep {
+				// A single value with step like "5/15" runs from the value to the end of the range
+				to = max
+			}
+		}
+
+		for value := from; value <= to; value += step {
+			values[value] = true
+		}
+	}
+	return values
+}
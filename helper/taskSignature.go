@@ -0,0 +1,193 @@
+package helper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	vm "github.com/siherrmann/validator/model"
+)
+
+// NewTaskSignature introspects a task function as it is registered on a worker, eg. with queuer.AddTask.
+// Go does not keep the names of parameters, so they can be given in the order of the parameters,
+// missing names default to param1, param2, ... An error result of the function is not part of the results.
+func NewTaskSignature(key string, task any, parameterNames ...string) (*model.TaskSignature, error) {
+	taskType := reflect.TypeOf(task)
+	if taskType == nil || taskType.Kind() != reflect.Func {
+		return nil, fmt.Errorf("task %s must be a function, got %T", key, task)
+	}
+	if len(parameterNames) > taskType.NumIn() {
+		return nil, fmt.Errorf("task %s has %d parameters, got %d parameter names", key, taskType.NumIn(), len(parameterNames))
+	}
+
+	signature := &model.TaskSignature{
+		Key:        key,
+		Name:       key,
+		Parameters: []model.ParameterSignature{},
+		Results:    []model.ParameterSignature{},
+	}
+	for i := range taskType.NumIn() {
+		name := fmt.Sprintf("param%d", i+1)
+		if i < len(parameterNames) {
+			name = parameterNames[i]
+		}
+		signature.Parameters = append(signature.Parameters, parameterSignature(name, taskType.In(i), map[reflect.Type]bool{}))
+	}
+
+	errorType := reflect.TypeFor[error]()
+	for i := range taskType.NumOut() {
+		if taskType.Out(i) == errorType {
+			continue
+		}
+		name := fmt.Sprintf("result%d", len(signature.Results)+1)
+		signature.Results = append(signature.Results, parameterSignature(name, taskType.Out(i), map[reflect.Type]bool{}))
+	}
+
+	return signature, nil
+}
+
+// TaskDefinitionFromSignature generates the task definition of a task signature.
+// The validations only check the types of the parameters, all parameters except pointers are required.
+func TaskDefinitionFromSignature(signature *model.TaskSignature) (*model.TaskDefinition, error) {
+	if signature.Key == "" {
+		return nil, fmt.Errorf("task signature without key")
+	}
+
+	definition := &model.TaskDefinition{
+		Key:         signature.Key,
+		Name:        signature.Name,
+		Description: signature.Description,
+	}
+	if definition.Name == "" {
+		definition.Name = signature.Key
+	}
+
+	var err error
+	definition.InputParameters, err = ValidationsFromParameterSignatures(signature.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("parameters of task %s: %w", signature.Key, err)
+	}
+	definition.InputParametersKeyed, err = ValidationsFromParameterSignatures(signature.ParametersKeyed)
+	if err != nil {
+		return nil, fmt.Errorf("keyed parameters of task %s: %w", signature.Key, err)
+	}
+	definition.OutputParameters, err = ValidationsFromParameterSignatures(signature.Results)
+	if err != nil {
+		return nil, fmt.Errorf("results of task %s: %w", signature.Key, err)
+	}
+
+	return definition, nil
+}
+
+// ValidationsFromParameterSignatures infers the validations of the parameters from their Go types.
+func ValidationsFromParameterSignatures(parameters []model.ParameterSignature) ([]vm.Validation, error) {
+	validations := []vm.Validation{}
+	for _, parameter := range parameters {
+		if parameter.Name == "" {
+			return nil, fmt.Errorf("parameter of type %s without name", parameter.Type)
+		}
+
+		validationType, optional, err := validatorTypeFromGoType(parameter.Type)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", parameter.Name, err)
+		}
+
+		validation := vm.Validation{
+			Key:         parameter.Name,
+			Type:        validationType,
+			Requirement: "-",
+			OmitEmpty:   optional,
+		}
+		if validationType == vm.Struct {
+			validation.InnerValidation, err = ValidationsFromParameterSignatures(parameter.Fields)
+			if err != nil {
+				return nil, fmt.Errorf("parameter %s: %w", parameter.Name, err)
+			}
+		}
+		validations = append(validations, validation)
+	}
+	return validations, nil
+}
+
+// validatorTypeFromGoType returns the validator type of a Go type of a parameter signature
+// and if the parameter is optional, which are pointers.
+func validatorTypeFromGoType(goType string) (vm.ValidatorType, bool, error) {
+	optional := strings.HasPrefix(goType, "*")
+	goType = strings.TrimPrefix(goType, "*")
+
+	switch {
+	case goType == "string", goType == "time.Time":
+		return vm.String, optional, nil
+	case goType == "int", goType == "int8", goType == "int16", goType == "int32", goType == "int64",
+		goType == "uint", goType == "uint8", goType == "uint16", goType == "uint32", goType == "uint64":
+		return vm.Int, optional, nil
+	case goType == "float32", goType == "float64":
+		return vm.Float, optional, nil
+	case goType == "bool":
+		return vm.Bool, optional, nil
+	case strings.HasPrefix(goType, "[]"):
+		return vm.Array, optional, nil
+	case strings.HasPrefix(goType, "map["):
+		return vm.Map, optional, nil
+	case goType == "struct":
+		return vm.Struct, optional, nil
+	default:
+		return "", false, fmt.Errorf("cannot infer validation of type %s", goType)
+	}
+}
+
+// parameterSignature describes the type by its kind, so named types like "type Mode string" are described as "string".
+// Struct types are described with their exported fields, seen prevents endless recursion of recursive types.
+func parameterSignature(name string, t reflect.Type, seen map[reflect.Type]bool) model.ParameterSignature {
+	parameter := model.ParameterSignature{Name: name, Type: goTypeName(t)}
+
+	structType := t
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct || structType == reflect.TypeFor[time.Time]() || seen[structType] {
+		return parameter
+	}
+	seen[structType] = true
+	defer delete(seen, structType)
+
+	for _, field := range reflect.VisibleFields(structType) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+
+		fieldName := field.Name
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if jsonName == "-" {
+			continue
+		} else if jsonName != "" {
+			fieldName = jsonName
+		}
+		parameter.Fields = append(parameter.Fields, parameterSignature(fieldName, field.Type, seen))
+	}
+	return parameter
+}
+
+// goTypeName returns the Go type name of a parameter signature, eg. "*int", "[]string" or "map[string]struct".
+func goTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return "*" + goTypeName(t.Elem())
+	case reflect.Slice, reflect.Array:
+		return "[]" + goTypeName(t.Elem())
+	case reflect.Map:
+		return "map[" + goTypeName(t.Key()) + "]" + goTypeName(t.Elem())
+	case reflect.Struct:
+		if t == reflect.TypeFor[time.Time]() {
+			return "time.Time"
+		}
+		return "struct"
+	case reflect.Interface:
+		return "any"
+	default:
+		return t.Kind().String()
+	}
+}
@@ -0,0 +1,128 @@
+package helper
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/siherrmann/queuerManager/model"
+)
+
+// ValidateEmail checks that the value is a single email address without display name
+func ValidateEmail(value any) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("email must be a string, got %T", value)
+	}
+	address, err := mail.ParseAddress(s)
+	if err != nil || address.Address != s {
+		return fmt.Errorf("invalid email address %q", s)
+	}
+	return nil
+}
+
+// ValidateS3URI checks that the value is an URI like "s3://bucket/key"
+func ValidateS3URI(value any) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("s3 uri must be a string, got %T", value)
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme != "s3" || u.Host == "" {
+		return fmt.Errorf("invalid s3 uri %q (must be s3://bucket/key)", s)
+	}
+	return nil
+}
+
+// cronFieldRanges are the allowed ranges of the minute, hour, day of month, month and day of week fields
+var cronFieldRanges = [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+
+// ValidateCron checks that the value is a standard five field cron expression.
+// Fields support "*", numbers, ranges ("1-5"), lists ("1,2") and steps ("*/15").
+func ValidateCron(value any) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("cron expression must be a string, got %T", value)
+	}
+
+	fields := strings.Fields(s)
+	if len(fields) != len(cronFieldRanges) {
+		return fmt.Errorf("invalid cron expression %q (must have 5 fields)", s)
+	}
+	for i, field := range fields {
+		if err := validateCronField(field, cronFieldRanges[i][0], cronFieldRanges[i][1]); err != nil {
+			return fmt.Errorf("invalid cron expression %q: %w", s, err)
+		}
+	}
+	return nil
+}
+
+func validateCronField(field string, min int, max int) error {
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step, hasStep := strings.Cut(part, "/")
+		if hasStep {
+			if n, err := strconv.Atoi(step); err != nil || n < 1 {
+				return fmt.Errorf("invalid step %q", step)
+			}
+		}
+		if rangePart == "*" {
+			continue
+		}
+
+		from, to, isRange := strings.Cut(rangePart, "-")
+		if !isRange {
+			to = from
+		}
+		fromValue, err := strconv.Atoi(from)
+		if err != nil || fromValue < min || fromValue > max {
+			return fmt.Errorf("value %q out of range %d-%d", from, min, max)
+		}
+		toValue, err := strconv.Atoi(to)
+		if err != nil || toValue < fromValue || toValue > max {
+			return fmt.Errorf("value %q out of range %d-%d", to, min, max)
+		}
+	}
+	return nil
+}
+
+// SplitRequirement splits a requirement with a single condition into the condition and its value, eg. "min1" into "min" and "1".
+// Combined or unknown requirements are split into model.REQUIREMENT_CUSTOM and the whole requirement.
+func SplitRequirement(requirement string) (string, string) {
+	requirement = strings.TrimSpace(requirement)
+	if requirement == "" || requirement == "-" {
+		return "-", ""
+	}
+	if len(requirement) > 3 && !strings.ContainsAny(requirement, "&|()") {
+		condition := requirement[:3]
+		if slices.ContainsFunc(model.RequirementConditions, func(c model.KeyValuePair) bool { return c.Key == condition }) {
+			return condition, requirement[3:]
+		}
+	}
+	return model.REQUIREMENT_CUSTOM, requirement
+}
+
+// JoinRequirement builds the requirement of a condition and its value of the requirement builder.
+// The value of a custom condition is the whole requirement, an empty requirement is "-".
+func JoinRequirement(condition string, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	switch condition {
+	case "", "-":
+		return "-", nil
+	case model.REQUIREMENT_CUSTOM:
+		if value == "" {
+			return "-", nil
+		}
+		return value, nil
+	}
+
+	if !slices.ContainsFunc(model.RequirementConditions, func(c model.KeyValuePair) bool { return c.Key == condition }) {
+		return "", fmt.Errorf("invalid condition %q", condition)
+	}
+	if value == "" {
+		return "", fmt.Errorf("condition %q requires a value", condition)
+	}
+	return condition + value, nil
+}
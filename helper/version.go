@@ -0,0 +1,70 @@
+package helper
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CompareVersions compares two dotted versions like "1.2.3" or "v1.2" numerically.
+// Missing parts count as 0 and pre-release or build suffixes ("-rc1", "+build") are ignored.
+// It returns -1 if a < b, 0 if a == b and 1 if a > b.
+func CompareVersions(a string, b string) (int, error) {
+	partsA, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	partsB, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := 0; i < max(len(partsA), len(partsB)); i++ {
+		var partA, partB int
+		if i < len(partsA) {
+			partA = partsA[i]
+		}
+		if i < len(partsB) {
+			partB = partsB[i]
+		}
+
+		if partA < partB {
+			return -1, nil
+		} else if partA > partB {
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// WorkerPool returns the name of a worker without its "@" suffix (eg. "gpu-worker@2" is in the pool "gpu-worker"),
+// which groups workers of the same kind into a pool.
+func WorkerPool(workerName string) string {
+	index := strings.LastIndex(workerName, "@")
+	if index < 0 {
+		return workerName
+	}
+	return workerName[:index]
+}
+
+func parseVersion(version string) ([]int, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(trimmed, "-+"); i >= 0 {
+		trimmed = trimmed[:i]
+	}
+	if trimmed == "" {
+		return nil, fmt.Errorf("invalid version %q", version)
+	}
+
+	parts := []int{}
+	for _, part := range strings.Split(trimmed, ".") {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return nil, fmt.Errorf("invalid version %q", version)
+		}
+		parts = append(parts, number)
+	}
+
+	return parts, nil
+}
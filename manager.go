@@ -2,49 +2,94 @@ package queuerManager
 
 import (
 	"context"
+	"crypto/tls"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"log"
 	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/siherrmann/queuer"
+	"github.com/siherrmann/queuerManager/auth"
 	"github.com/siherrmann/queuerManager/database"
+	"github.com/siherrmann/queuerManager/event"
+	"github.com/siherrmann/queuerManager/forward"
 	"github.com/siherrmann/queuerManager/handler"
 	"github.com/siherrmann/queuerManager/helper"
+	mw "github.com/siherrmann/queuerManager/middleware"
 	"github.com/siherrmann/queuerManager/model"
+	"github.com/siherrmann/queuerManager/notify"
 	"github.com/siherrmann/queuerManager/upload"
 
-	"github.com/labstack/echo/v5"
 	"github.com/a-h/templ"
+	"github.com/labstack/echo/v5"
+	qdb "github.com/siherrmann/queuer/database"
 	qh "github.com/siherrmann/queuer/helper"
 	qmodel "github.com/siherrmann/queuer/model"
 )
 
 type ManagerApp struct {
-	Port           string
-	MaxConcurrency int
-	StaticDir      string
-	Extensions     []Extension
-	SidebarLogo    templ.Component
+	Port            string
+	MaxConcurrency  int
+	StaticDir       string
+	Extensions      []Extension
+	SidebarLogo     templ.Component
+	ValidationFuncs map[string]model.ValidationFunc
+
+	// hooks
+	preTaskSaveHooks  []model.TaskHookFunc
+	postTaskSaveHooks []model.TaskHookFunc
+	preJobSubmitHooks []model.JobSubmitHookFunc
+	startHooks        []model.LifecycleHookFunc
+	shutdownHooks     []model.LifecycleHookFunc
 
 	// internals
+	config *Config
+	queuer *queuer.Queuer
 	mh     *handler.ManagerHandler
 	echo   *echo.Echo
 	ctx    context.Context
 	cancel context.CancelFunc
+	done   chan struct{}
 }
 
+// NewManagerApp creates the manager app with the port and max concurrency,
+// the rest of the configuration is read from the environment variables.
 func NewManagerApp(port string, maxConcurrency int) *ManagerApp {
-	ctx, cancel := context.WithCancel(context.Background())
-	return &ManagerApp{
+	return NewManagerAppWithConfig(&Config{
 		Port:           port,
 		MaxConcurrency: maxConcurrency,
 		StaticDir:      "./view/static",
-		Extensions:     []Extension{},
-		ctx:            ctx,
-		cancel:         cancel,
+	})
+}
+
+// NewManagerAppWithConfig creates the manager app with the configuration changed by the options.
+// The empty fields of the configuration fall back to their environment variables when the app starts.
+func NewManagerAppWithConfig(config *Config, options ...Option) *ManagerApp {
+	appConfig := &Config{}
+	if config != nil {
+		*appConfig = *config
+	}
+	for _, option := range options {
+		option(appConfig)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ManagerApp{
+		Port:            appConfig.Port,
+		MaxConcurrency:  appConfig.MaxConcurrency,
+		StaticDir:       appConfig.StaticDir,
+		Extensions:      []Extension{},
+		ValidationFuncs: map[string]model.ValidationFunc{},
+		config:          appConfig,
+		ctx:             ctx,
+		cancel:          cancel,
+		done:            make(chan struct{}),
 	}
 }
 
@@ -52,42 +97,349 @@ func (app *ManagerApp) RegisterExtension(ext Extension) {
 	app.Extensions = append(app.Extensions, ext)
 }
 
+// AddValidationFunc registers a custom validation for task input parameters, eg. "cron" or "s3uri".
+// The name can be used as type or as requirement of the parameters and is checked when a job is added.
+func (app *ManagerApp) AddValidationFunc(name string, fn model.ValidationFunc) {
+	app.ValidationFuncs[name] = fn
+}
+
+// AddPreTaskSaveHook registers a hook that runs before a task is added, updated or imported,
+// eg. to enforce naming conventions. The hook can change the task, an error rejects the save.
+func (app *ManagerApp) AddPreTaskSaveHook(hook model.TaskHookFunc) {
+	app.preTaskSaveHooks = append(app.preTaskSaveHooks, hook)
+}
+
+// AddPostTaskSaveHook registers a hook that runs after a task was added, updated or imported.
+// Errors of the hook are only logged.
+func (app *ManagerApp) AddPostTaskSaveHook(hook model.TaskHookFunc) {
+	app.postTaskSaveHooks = append(app.postTaskSaveHooks, hook)
+}
+
+// AddPreJobSubmitHook registers a hook that runs with the validated parameters before a job is added,
+// eg. to inject parameters centrally. The hook can change the parameters, an error rejects the job.
+func (app *ManagerApp) AddPreJobSubmitHook(hook model.JobSubmitHookFunc) {
+	app.preJobSubmitHooks = append(app.preJobSubmitHooks, hook)
+}
+
+// AddStartHook registers a hook that runs after the manager is initialized and before the HTTP server starts,
+// eg. to warm up caches. An error of the hook stops the startup.
+func (app *ManagerApp) AddStartHook(hook model.LifecycleHookFunc) {
+	app.startHooks = append(app.startHooks, hook)
+}
+
+// AddShutdownHook registers a hook that runs after the HTTP server drained its requests and before the queuer
+// and the database are stopped, eg. to flush buffers. The hooks run in reverse order of their registration,
+// their context ends with the shutdown timeout and their errors are only logged.
+func (app *ManagerApp) AddShutdownHook(hook model.LifecycleHookFunc) {
+	app.shutdownHooks = append(app.shutdownHooks, hook)
+}
+
+// Handler returns the manager handler of the app, it is nil until the manager is initialized with Start or NewEcho.
+func (app *ManagerApp) Handler() *handler.ManagerHandler {
+	return app.mh
+}
+
+// Queuer returns the queuer of the app, it is nil until the manager is initialized with Start or NewEcho.
+func (app *ManagerApp) Queuer() *queuer.Queuer {
+	return app.queuer
+}
+
+// Done returns a channel that is closed when the manager is shut down and its queuer is stopped.
+func (app *ManagerApp) Done() <-chan struct{} {
+	return app.done
+}
+
+// Start initializes the manager and serves the HTTP server until SIGINT or SIGTERM is received or the queuer stops.
+// On shutdown the server stops accepting connections and drains the running requests for up to
+// QUEUER_MANAGER_SHUTDOWN_TIMEOUT (default 20s), then the shutdown hooks run and the queuer and the database are stopped.
+// With a TLS certificate or autocert domains the server is served with TLS and HTTP/2.
 func (app *ManagerApp) Start() {
 	defer app.cancel()
 
-	// Initialize queuer instance
-	queuerInstance := queuer.NewQueuer("manager-server", app.MaxConcurrency)
+	config, err := app.resolveConfig()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	var tlsConfig *tls.Config
+	var redirect http.Handler
+	if config.tlsEnabled() {
+		tlsConfig, redirect, err = config.newTLSConfig()
+		if err != nil {
+			log.Fatalf("Failed to set up TLS: %v", err)
+		}
+	}
+
+	err = app.setup(config)
+	if err != nil {
+		log.Fatalf("Failed to set up manager: %v", err)
+	}
+
+	buildInfo := helper.GetBuildInfo()
+	slog.Info("Starting queuer manager", "version", buildInfo.Version, "commit", buildInfo.Commit, "build_time", buildInfo.BuildTime, "go_version", buildInfo.GoVersion, "port", config.Port, "tls", config.tlsEnabled())
+
+	// Shut down on SIGINT and SIGTERM and when the queuer cancels the context
+	signalCtx, stopSignals := signal.NotifyContext(app.ctx, os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	startConfig := echo.StartConfig{
+		Address:         ":" + config.Port,
+		GracefulTimeout: config.ShutdownTimeout,
+		BeforeServeFunc: func(server *http.Server) error {
+			// End the event streams and long polls at the start of the shutdown, so they do not hold up the drain
+			server.RegisterOnShutdown(app.mh.BeginShutdown)
+			return nil
+		},
+		OnShutdownError: func(err error) {
+			slog.Error("Failed to drain the requests within the shutdown timeout", "timeout", config.ShutdownTimeout, "error", err)
+		},
+	}
+	if config.tlsEnabled() {
+		startConfig.TLSConfig = tlsConfig
+		if config.HTTPRedirectPort != "" {
+			go serveHTTPRedirect(signalCtx, config.HTTPRedirectPort, redirect)
+		}
+	}
+	serveErr := startConfig.Start(signalCtx, app.echo)
+	if serveErr != nil {
+		slog.Error("Failed to serve", "error", serveErr)
+	}
+
+	app.shutdown(config.ShutdownTimeout)
+	if serveErr != nil {
+		os.Exit(1)
+	}
+}
+
+// NewEcho initializes the manager with the context and returns its Echo instance with all routes and middlewares
+// without starting the HTTP server, eg. to serve it with a custom server or to mount it with MountOn.
+// The manager is shut down when the context is done.
+func (app *ManagerApp) NewEcho(ctx context.Context) (*echo.Echo, error) {
+	app.ctx, app.cancel = context.WithCancel(ctx)
+
+	config, err := app.resolveConfig()
+	if err != nil {
+		app.cancel()
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	err = app.setup(config)
+	if err != nil {
+		app.cancel()
+		return nil, err
+	}
+
+	go func() {
+		<-app.ctx.Done()
+		app.shutdown(config.ShutdownTimeout)
+	}()
+
+	return app.echo, nil
+}
+
+// resolveConfig returns the configuration of the app with defaults.
+func (app *ManagerApp) resolveConfig() (*Config, error) {
+	// The exported fields of the app can be changed after its creation
+	appConfig := *app.config
+	appConfig.Port = app.Port
+	appConfig.MaxConcurrency = app.MaxConcurrency
+	appConfig.StaticDir = app.StaticDir
+	return appConfig.withDefaults()
+}
+
+// setup initializes the queuer, the manager handler, the extensions and the background routines
+// and creates the Echo instance with all routes, then the start hooks run.
+func (app *ManagerApp) setup(config *Config) error {
+	// Initialize queuer instance, without database configuration it is read from the environment variables
+	queuerInstance := queuer.NewQueuerWithDB(model.MANAGER_WORKER_NAME, config.MaxConcurrency, config.EncryptionKey, config.Database)
+	app.queuer = queuerInstance
+
+	// Configure the shared database connection pool
+	poolConfig, err := database.NewDBPoolConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("failed to read database pool config: %w", err)
+	}
+	err = database.ConfigureDBPool(queuerInstance.DB, poolConfig)
+	if err != nil {
+		return fmt.Errorf("failed to configure database pool: %w", err)
+	}
+
+	// Register the synthetic task so the manager itself runs the load test jobs
+	if handler.LoadTestEnabled() {
+		queuerInstance.AddTaskWithName(handler.LoadTestTask, model.LOAD_TEST_TASK_KEY)
+		slog.Warn("Load test mode is enabled, do not use in production")
+	}
 
 	// Initialize manager handler
-	mh, err := InitManagerHandler(app.ctx, app.cancel, queuerInstance)
+	mh, err := initManagerHandler(app.ctx, app.cancel, queuerInstance, config)
 	if err != nil {
-		log.Fatalf("Failed to initialize manager handler: %v", err)
+		return fmt.Errorf("failed to initialize manager handler: %w", err)
 	}
 	app.mh = mh
 
+	// Register custom validations
+	for name, fn := range app.ValidationFuncs {
+		app.mh.AddValidationFunc(name, fn)
+	}
+
+	// Register lifecycle hooks
+	for _, hook := range app.preTaskSaveHooks {
+		app.mh.AddPreTaskSaveHook(hook)
+	}
+	for _, hook := range app.postTaskSaveHooks {
+		app.mh.AddPostTaskSaveHook(hook)
+	}
+	for _, hook := range app.preJobSubmitHooks {
+		app.mh.AddPreJobSubmitHook(hook)
+	}
+
 	// Initialize extensions and collect sidebar items
 	var sidebarItems []model.SidebarItem
 	for _, ext := range app.Extensions {
 		if err := ext.Init(app.ctx, app.mh); err != nil {
-			log.Fatalf("Failed to initialize extension: %v", err)
+			return fmt.Errorf("failed to initialize extension: %w", err)
 		}
 		sidebarItems = append(sidebarItems, ext.SidebarItems()...)
 	}
 
+	// Run the housekeeping of the manager as jobs of its own tasks, so they are shown in the jobs views
+	housekeepingJobs := config.HousekeepingJobs
+	if housekeepingJobs {
+		for taskKey, task := range app.mh.HousekeepingTasks() {
+			queuerInstance.AddTaskWithName(task, taskKey)
+		}
+		err = app.mh.RegisterHousekeepingTasks()
+		if err != nil {
+			return fmt.Errorf("failed to register housekeeping tasks: %w", err)
+		}
+	}
+
 	// Start the queuer with master settings
-	masterSettings := &qmodel.MasterSettings{
-		MasterLockTimeout:     time.Minute * 1,
-		MasterPollInterval:    time.Second * 10,
-		WorkerStaleThreshold:  time.Minute * 5,
-		WorkerDeleteThreshold: time.Minute * 100,
-		JobStaleThreshold:     time.Minute * 10,
-		JobDeleteThreshold:    time.Minute * 100,
+	masterSettings := config.MasterSettings
+	queuerInstance.Start(app.ctx, app.cancel, masterSettings)
+
+	// Renew the leases of the background work, the instance is identified by the RID of its worker
+	if app.mh.Coordinator != nil {
+		go app.mh.Coordinator.Run(app.ctx, queuerInstance.GetCurrentWorkerRID().String(), leaseRenewInterval)
+	}
+
+	// Notify about ended jobs, ended jobs are moved to the archive
+	if app.mh.Notifications != nil {
+		err = queuerInstance.ListenForJobDelete(app.mh.NotifyJobEnded)
+		if err != nil {
+			return fmt.Errorf("failed to listen for ended jobs: %w", err)
+		}
+	}
+
+	// Alert about failed jobs and stale workers of the opted in tasks
+	if app.mh.Alerter != nil {
+		err = queuerInstance.ListenForJobDelete(app.mh.AlertJobEnded)
+		if err != nil {
+			return fmt.Errorf("failed to listen for ended jobs: %w", err)
+		}
+		go alertStaleWorkers(app.ctx, app.mh, config.WorkerStaleAfter, staleWorkerCheckInterval)
+	}
+
+	// Publish the ended jobs and the registered workers to the event publisher
+	if app.mh.EventPublisher != nil {
+		err = queuerInstance.ListenForJobDelete(app.mh.PublishJobEnded)
+		if err != nil {
+			return fmt.Errorf("failed to listen for ended jobs: %w", err)
+		}
+		go publishRegisteredWorkers(app.ctx, app.mh, workerRegisteredCheckInterval)
+	}
+
+	// Count ended jobs in the job KPIs
+	err = queuerInstance.ListenForJobDelete(app.mh.RecordJobKPIs)
+	if err != nil {
+		return fmt.Errorf("failed to listen for ended jobs: %w", err)
+	}
+
+	// Delete the priorities of the ended jobs
+	if app.mh.SchedulerDB != nil {
+		err = queuerInstance.ListenForJobDelete(app.mh.DeleteEndedJobPriority)
+		if err != nil {
+			return fmt.Errorf("failed to listen for ended jobs: %w", err)
+		}
+	}
+
+	// Add the jobs of the downstream tasks of succeeded jobs
+	if app.mh.JobChainDB != nil {
+		err = queuerInstance.ListenForJobDelete(app.mh.RunJobChains)
+		if err != nil {
+			return fmt.Errorf("failed to listen for ended jobs: %w", err)
+		}
+	}
+
+	// Stream the changes of the active and ended jobs to the job event streams,
+	// without database configuration it is read from the environment variables like the queuer does
+	jobDbConfig := config.Database
+	if jobDbConfig == nil {
+		jobDbConfig, err = qh.NewDatabaseConfiguration()
+		if err != nil {
+			return fmt.Errorf("failed to read database configuration: %w", err)
+		}
+	}
+	go streamJobChanges(app.ctx, jobDbConfig, "job", app.mh.JobStream)
+	go streamJobChanges(app.ctx, jobDbConfig, "job_archive", app.mh.JobStream)
+	go recordJobAttempts(app.ctx, app.mh)
+	go notifyJobWatchers(app.ctx, app.mh)
+
+	// Record queue metrics every poll interval
+	app.mh.MetricInterval = masterSettings.MasterPollInterval
+	if app.mh.MetricDB != nil && housekeepingJobs {
+		go addHousekeepingJobs(app.ctx, app.mh, model.HOUSEKEEPING_TASK_RECORD_METRICS, masterSettings.MasterPollInterval)
+	} else if app.mh.MetricDB != nil {
+		go recordMetrics(app.ctx, app.mh.MetricDB, app.mh.Status, app.mh.Coordinator, masterSettings.MasterPollInterval)
+	}
+
+	// Persist the health of the subsystems for the uptime history of the status page
+	if housekeepingJobs {
+		go addHousekeepingJobs(app.ctx, app.mh, model.HOUSEKEEPING_TASK_RECORD_HEALTH, config.HealthInterval)
+	} else {
+		go recordHealth(app.ctx, app.mh, config.HealthInterval)
+	}
+
+	// Add the jobs of the due schedules, cron expressions have a resolution of one minute
+	if housekeepingJobs {
+		go addHousekeepingJobs(app.ctx, app.mh, model.HOUSEKEEPING_TASK_RUN_SCHEDULES, config.ScheduleInterval)
+	} else {
+		go runSchedules(app.ctx, app.mh, config.ScheduleInterval)
+	}
+
+	// Write the access logs of the requests and delete them after the access log retention
+	go app.mh.AccessLogs.Run(app.ctx, accessLogFlushInterval)
+	go app.mh.DeprecationUsages.Run(app.ctx, deprecationUsageFlushInterval)
+	if housekeepingJobs {
+		go addHousekeepingJobs(app.ctx, app.mh, model.HOUSEKEEPING_TASK_DELETE_ACCESS_LOGS, accessLogCleanupInterval)
+	} else {
+		go deleteExpiredAccessLogs(app.ctx, app.mh, accessLogCleanupInterval)
+	}
+
+	// Move the orphaned files of deleted and expired jobs to the trash and delete them after the trash retention
+	if housekeepingJobs {
+		go addHousekeepingJobs(app.ctx, app.mh, model.HOUSEKEEPING_TASK_PRUNE_FILES, fileCleanupInterval)
+	} else {
+		go pruneOrphanedFiles(app.ctx, app.mh, fileCleanupInterval)
+	}
+
+	// Sync the results of forwarded jobs back from the remote instances
+	if app.mh.Forwarder != nil {
+		go syncForwardedJobs(app.ctx, app.mh, config.ForwardSyncInterval)
+	}
+
+	// Sync the roles of the users with their LDAP groups
+	if app.mh.Authenticator != nil {
+		go syncUserRoles(app.ctx, app.mh, config.LDAPSyncInterval)
 	}
-	app.mh.Queuer.Start(app.ctx, app.cancel, masterSettings)
 
 	app.echo = echo.New()
 
-	// Custom Sidebar Middleware
+	// Custom Sidebar Middleware, with the branding of the deployment
+	var branding *model.Branding
+	if config.BrandTitle != "" || config.BrandLogoURL != "" {
+		branding = &model.Branding{Title: config.BrandTitle, LogoURL: config.BrandLogoURL}
+	}
 	app.echo.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c *echo.Context) error {
 			req := c.Request()
@@ -95,26 +447,61 @@ func (app *ManagerApp) Start() {
 			if app.SidebarLogo != nil {
 				ctx = context.WithValue(ctx, "sidebarLogo", app.SidebarLogo)
 			}
+			if branding != nil {
+				ctx = context.WithValue(ctx, "branding", branding)
+			}
 			c.SetRequest(req.WithContext(ctx))
 			return next(c)
 		}
 	})
 
-	SetupRoutes(app.echo, app.mh)
-	app.echo.Static("/static/", app.StaticDir)
+	setupRoutes(app.echo, app.mh, mw.NewMiddleware(config.CSRFTrustedOrigins...), config.BasePath)
+	app.echo.Static("/static/", config.StaticDir)
 
 	// Setup extension routes
 	for _, ext := range app.Extensions {
 		ext.SetupRoutes(app.echo, app.mh)
 	}
 
-	err = app.echo.Start(":" + app.Port)
-	if err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	for _, hook := range app.startHooks {
+		err = hook(app.ctx)
+		if err != nil {
+			return fmt.Errorf("start hook failed: %w", err)
+		}
 	}
 
-	<-app.ctx.Done()
+	return nil
+}
+
+// shutdown stops the manager after the HTTP server drained its requests. It runs the shutdown hooks in reverse order,
+// stops the background routines, closes the event publisher and finally stops the queuer, which closes the database.
+func (app *ManagerApp) shutdown(timeout time.Duration) {
 	slog.Info("Shutting down manager server")
+	app.mh.BeginShutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	for i := len(app.shutdownHooks) - 1; i >= 0; i-- {
+		err := app.shutdownHooks[i](ctx)
+		if err != nil {
+			slog.Error("Shutdown hook failed", "error", err)
+		}
+	}
+
+	app.cancel()
+	app.mh.Coordinator.Release()
+
+	if app.mh.EventPublisher != nil {
+		_ = app.mh.EventPublisher.Close()
+	}
+
+	err := app.mh.Queuer.Stop()
+	if err != nil {
+		slog.Error("Failed to stop queuer", "error", err)
+	}
+
+	slog.Info("Manager server stopped")
+	close(app.done)
 }
 
 // ManagerServer initializes the manager handler, sets up routes, and starts the Echo server.
@@ -124,19 +511,44 @@ func ManagerServer(port string, maxConcurrency int) {
 	app.Start()
 }
 
+// ManagerServerWithConfig initializes the manager with the configuration changed by the options and starts the Echo server,
+// eg. ManagerServerWithConfig(nil, WithPort("8080"), WithStorage("memory", "")) in a program embedding the manager.
+func ManagerServerWithConfig(config *Config, options ...Option) {
+	app := NewManagerAppWithConfig(config, options...)
+	app.Start()
+}
+
 // InitManagerHandler creates and configures the manager handler, including initializing the queuer, setting up the filesystem, and loading tasks from a JSON file if specified.
+// The configuration is read from the environment variables.
 // It returns the initialized manager handler or an error if initialization fails.
 func InitManagerHandler(ctx context.Context, cancel context.CancelFunc, queuerInstance *queuer.Queuer) (*handler.ManagerHandler, error) {
-	// Create filesystem from environment variables
-	filesystem, err := upload.CreateFilesystemFromEnv()
+	config, err := (&Config{}).withDefaults()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create filesystem: %w", err)
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return initManagerHandler(ctx, cancel, queuerInstance, config)
+}
+
+// initManagerHandler creates and configures the manager handler with the configuration with defaults.
+func initManagerHandler(ctx context.Context, cancel context.CancelFunc, queuerInstance *queuer.Queuer, config *Config) (*handler.ManagerHandler, error) {
+	// Create the filesystem of the storage mode if no filesystem is configured
+	filesystem := config.Filesystem
+	if filesystem == nil {
+		var err error
+		filesystem, err = upload.CreateFilesystem(config.StorageMode, config.StoragePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create filesystem: %w", err)
+		}
 	}
 
 	// Logger
+	logLevel, err := parseLogLevel(config.LogLevel)
+	if err != nil {
+		return nil, err
+	}
 	opts := qh.PrettyHandlerOptions{
 		SlogOpts: slog.HandlerOptions{
-			Level: slog.LevelInfo,
+			Level: logLevel,
 		},
 	}
 	logger := slog.New(qh.NewPrettyHandler(os.Stdout, opts))
@@ -152,8 +564,316 @@ func InitManagerHandler(ctx context.Context, cancel context.CancelFunc, queuerIn
 		return nil, fmt.Errorf("failed to create task database handler: %w", err)
 	}
 
+	// Initialize metric database handler
+	metricDb := &qh.Database{
+		Name:     "metric",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	metricDB, err := database.NewMetricDBHandler(metricDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metric database handler: %w", err)
+	}
+
+	// Initialize batch database handler
+	batchDb := &qh.Database{
+		Name:     "batch",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	batchDB, err := database.NewBatchDBHandler(batchDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create batch database handler: %w", err)
+	}
+
+	// Initialize scale audit database handler
+	scaleAuditDb := &qh.Database{
+		Name:     "scale_audit",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	scaleAuditDB, err := database.NewScaleAuditDBHandler(scaleAuditDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scale audit database handler: %w", err)
+	}
+
+	// Initialize job kill database handler
+	jobKillDb := &qh.Database{
+		Name:     "job_kill",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	jobKillDB, err := database.NewJobKillDBHandler(jobKillDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job kill database handler: %w", err)
+	}
+
+	// Initialize job override database handler
+	jobOverrideDb := &qh.Database{
+		Name:     "job_override",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	jobOverrideDB, err := database.NewJobOverrideDBHandler(jobOverrideDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job override database handler: %w", err)
+	}
+
+	// Initialize job attempt database handler
+	jobAttemptDb := &qh.Database{
+		Name:     "job_attempt",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	jobAttemptDB, err := database.NewJobAttemptDBHandler(jobAttemptDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job attempt database handler: %w", err)
+	}
+
+	// Initialize job template database handler
+	jobTemplateDb := &qh.Database{
+		Name:     "job_template",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	jobTemplateDB, err := database.NewJobTemplateDBHandler(jobTemplateDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job template database handler: %w", err)
+	}
+
+	// Initialize job initiator database handler
+	jobInitiatorDb := &qh.Database{
+		Name:     "job_initiator",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	jobInitiatorDB, err := database.NewJobInitiatorDBHandler(jobInitiatorDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job initiator database handler: %w", err)
+	}
+
+	// Initialize job dedup database handler
+	jobDedupDb := &qh.Database{
+		Name:     "job_dedup",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	jobDedupDB, err := database.NewJobDedupDBHandler(jobDedupDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job dedup database handler: %w", err)
+	}
+
+	// Initialize task cost database handler
+	taskCostDb := &qh.Database{
+		Name:     "task_cost",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	taskCostDB, err := database.NewTaskCostDBHandler(taskCostDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create task cost database handler: %w", err)
+	}
+
+	// Initialize job database handler for the job list filters
+	jobDb := &qh.Database{
+		Name:     "job",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	jobDB, err := database.NewJobDBHandler(jobDb)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job database handler: %w", err)
+	}
+
+	// Initialize job archive database handler for the archive filters
+	jobArchiveDb := &qh.Database{
+		Name:     "job_archive",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	jobArchiveDB, err := database.NewJobArchiveDBHandler(jobArchiveDb)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job archive database handler: %w", err)
+	}
+
+	// Initialize task sample database handler for the test runs of tasks
+	taskSampleDb := &qh.Database{
+		Name:     "task_sample",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	taskSampleDB, err := database.NewTaskSampleDBHandler(taskSampleDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create task sample database handler: %w", err)
+	}
+
+	// Initialize queuer master database handler for the status page
+	queuerMasterDb := &qh.Database{
+		Name:     "master",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	queuerMasterDB, err := database.NewQueuerMasterDBHandler(queuerMasterDb)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create queuer master database handler: %w", err)
+	}
+
+	// Initialize API key database handler for the authentication of API clients
+	apiKeyDb := &qh.Database{
+		Name:     "api_key",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	apiKeyDB, err := database.NewAPIKeyDBHandler(apiKeyDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api key database handler: %w", err)
+	}
+
+	// Initialize feature flag database handler to gate experimental features
+	featureFlagDb := &qh.Database{
+		Name:     "feature_flag",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	featureFlagDB, err := database.NewFeatureFlagDBHandler(featureFlagDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create feature flag database handler: %w", err)
+	}
+
+	// Initialize scheduler database handler to store the order in which the workers claim the queued jobs
+	schedulerDb := &qh.Database{
+		Name:     "scheduler",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	schedulerDB, err := database.NewSchedulerDBHandler(schedulerDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scheduler database handler: %w", err)
+	}
+
+	// Initialize schedule database handler to add jobs of tasks at the times of cron expressions
+	scheduleDb := &qh.Database{
+		Name:     "schedule",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	scheduleDB, err := database.NewScheduleDBHandler(scheduleDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create schedule database handler: %w", err)
+	}
+
+	// Initialize job chain database handler
+	jobChainDb := &qh.Database{
+		Name:     "job_chain",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	jobChainDB, err := database.NewJobChainDBHandler(jobChainDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job chain database handler: %w", err)
+	}
+
+	// Initialize watch database handler for the job and task subscriptions of the users
+	watchDb := &qh.Database{
+		Name:     "watch",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	watchDB, err := database.NewWatchDBHandler(watchDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create watch database handler: %w", err)
+	}
+
+	// Initialize health database handler for the health history and the incidents of the status page
+	healthDb := &qh.Database{
+		Name:     "health",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	healthDB, err := database.NewHealthDBHandler(healthDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create health database handler: %w", err)
+	}
+
+	// Initialize message template database handler for the edited templates of the notifications
+	messageTemplateDb := &qh.Database{
+		Name:     "message_template",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	messageTemplateDB, err := database.NewMessageTemplateDBHandler(messageTemplateDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create message template database handler: %w", err)
+	}
+
+	// Initialize access log database handler for the access logs of the requests
+	accessLogDb := &qh.Database{
+		Name:     "access_log",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	accessLogDB, err := database.NewAccessLogDBHandler(accessLogDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create access log database handler: %w", err)
+	}
+
+	// Initialize file owner database handler for the storage quota of the principals
+	fileOwnerDb := &qh.Database{
+		Name:     "file_owner",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	fileOwnerDB, err := database.NewFileOwnerDBHandler(fileOwnerDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file owner database handler: %w", err)
+	}
+
+	// Initialize worker version database handler for the minimum worker versions of the tasks
+	workerVersionDb := &qh.Database{
+		Name:     "worker_version",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	workerVersionDB, err := database.NewWorkerVersionDBHandler(workerVersionDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create worker version database handler: %w", err)
+	}
+
+	// Initialize job file database handler for the pruning of the orphaned files of deleted and expired jobs
+	jobFileDb := &qh.Database{
+		Name:     "job_file",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	jobFileDB, err := database.NewJobFileDBHandler(jobFileDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job file database handler: %w", err)
+	}
+
+	// Initialize manager lease database handler for the coordination of several manager instances
+	managerLeaseDb := &qh.Database{
+		Name:     "manager_lease",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	managerLeaseDB, err := database.NewManagerLeaseDBHandler(managerLeaseDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create manager lease database handler: %w", err)
+	}
+
+	// Initialize deprecated route usage database handler for the remaining callers of the deprecated routes
+	deprecatedRouteUsageDb := &qh.Database{
+		Name:     "deprecated_route_usage",
+		Logger:   logger,
+		Instance: queuerInstance.DB,
+	}
+	deprecatedRouteUsageDB, err := database.NewDeprecatedRouteUsageDBHandler(deprecatedRouteUsageDb, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create deprecated route usage database handler: %w", err)
+	}
+
 	// Load tasks from JSON file if path is provided
-	taskJSONPath := helper.GetEnvOrDefault("QUEUER_MANAGER_TASK_JSON", "")
+	taskJSONPath := config.TaskJSONPath
 	if taskJSONPath != "" {
 		err := loadTasksFromJSON(taskJSONPath, taskDB, logger)
 		if err != nil {
@@ -163,10 +883,599 @@ func InitManagerHandler(ctx context.Context, cancel context.CancelFunc, queuerIn
 
 	// Create and configure manager handler
 	mh := handler.NewManagerHandler(filesystem, taskDB, queuerInstance)
+	mh.MetricDB = metricDB
+	mh.BatchDB = batchDB
+	mh.ScaleAuditDB = scaleAuditDB
+	mh.JobKillDB = jobKillDB
+	mh.JobOverrideDB = jobOverrideDB
+	mh.JobAttemptDB = jobAttemptDB
+	mh.JobTemplateDB = jobTemplateDB
+	mh.JobInitiatorDB = jobInitiatorDB
+	mh.JobDedupDB = jobDedupDB
+	mh.TaskCostDB = taskCostDB
+	mh.JobDB = jobDB
+	mh.JobArchiveDB = jobArchiveDB
+	mh.TaskSampleDB = taskSampleDB
+	mh.QueuerMasterDB = queuerMasterDB
+	mh.APIKeyDB = apiKeyDB
+	mh.FeatureFlagDB = featureFlagDB
+	mh.SchedulerDB = schedulerDB
+	// Replace the job claiming function of the queuer for the round robin mode and the job priorities
+	mh.SchedulerOverride = helper.GetEnvOrDefault("QUEUER_MANAGER_SCHEDULER_OVERRIDE", "false") == "true"
+	mh.ScheduleDB = scheduleDB
+	mh.JobChainDB = jobChainDB
+	mh.WatchDB = watchDB
+	mh.HealthDB = healthDB
+	mh.MessageTemplateDB = messageTemplateDB
+	mh.MessageTemplates = notify.NewTemplates(messageTemplateDB.SelectAllMessageTemplates, config.BrandTitle)
+	mh.AccessLogDB = accessLogDB
+	mh.AccessLogs = handler.NewAccessLogger(accessLogDB.InsertAccessLogs, accessLogBufferSize)
+	mh.AccessLogRetentionDays = config.AccessLogRetentionDays
+	mh.FileOwnerDB = fileOwnerDB
+	mh.JobFileDB = jobFileDB
+	mh.WorkerVersionDB = workerVersionDB
+	mh.FileTrashRetentionDays = config.FileTrashRetentionDays
+	mh.ManagerLeaseDB = managerLeaseDB
+	mh.Coordinator = handler.NewCoordinator(managerLeaseDB.AcquireLease, managerLeaseDB.ReleaseLeases, leaseTTL)
+	mh.DeprecatedRouteUsageDB = deprecatedRouteUsageDB
+	mh.DeprecationUsages = handler.NewDeprecationTracker(deprecatedRouteUsageDB.AddDeprecatedRouteUsages)
+	err = mh.DeprecateRoutesFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize deprecated routes: %w", err)
+	}
+	err = mh.InitFeatureFlags(helper.GetEnvOrDefault("QUEUER_MANAGER_FEATURE_FLAGS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize feature flags: %w", err)
+	}
+	err = mh.InitSchedulerPolicy()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize scheduler policy: %w", err)
+	}
+	mh.SlackSigningSecret = helper.GetEnvOrDefault("QUEUER_MANAGER_SLACK_SIGNING_SECRET", "")
+	mh.CIToken = helper.GetEnvOrDefault("QUEUER_MANAGER_CI_TOKEN", "")
+
+	// Show a read-only dashboard without login at the path, eg. on office monitors
+	mh.PublicDashboardPath = helper.GetEnvOrDefault("QUEUER_MANAGER_PUBLIC_DASHBOARD_PATH", "")
+	err = handler.ValidatePublicDashboardPath(mh.PublicDashboardPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Scale worker deployments in kubernetes directly, otherwise forward to the webhook
+	kubernetesScaler, err := handler.NewKubernetesScalerFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes scaler: %w", err)
+	}
+	if kubernetesScaler != nil {
+		mh.WorkerScaler = kubernetesScaler
+	} else if scaler := handler.NewWebhookScalerFromEnv(); scaler != nil {
+		mh.WorkerScaler = scaler
+	}
+
+	// Tail worker logs from their log endpoint or a shared log directory if configured
+	mh.WorkerLogs = handler.NewWorkerLogSourceFromEnv()
+
+	// Publish lifecycle events to kafka or nats if configured
+	publisher, err := event.CreatePublisherFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event publisher: %w", err)
+	}
+	if publisher != nil {
+		mh.EventPublisher = publisher
+	}
+
+	// Custom display names and colors of the statuses in the views, exports and notifications
+	err = handler.ConfigureStatusDisplaysFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure status displays: %w", err)
+	}
+
+	// Count the ended jobs per task or label for the business KPIs of the Prometheus endpoint
+	jobKPIs, err := handler.NewJobKPIsFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job kpis: %w", err)
+	}
+	mh.JobKPIs = jobKPIs
+
+	// Send notifications of ended jobs to the webhooks of the notification rules
+	dispatcher, err := notify.CreateDispatcherFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create notification dispatcher: %w", err)
+	}
+	if dispatcher != nil {
+		dispatcher.SetTemplates(mh.MessageTemplates)
+	}
+	mh.Notifications = dispatcher
+
+	// Send the notifications of watches with email with the SMTP server if configured
+	mailer, err := notify.CreateMailerFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mailer: %w", err)
+	}
+	if mailer != nil && config.BrandTitle != "" {
+		mailer.SetSenderName(config.BrandTitle)
+	}
+	mh.Mailer = mailer
+
+	// Send the alerts of failed jobs, stale workers and missed schedule runs of the opted in tasks by email and to Slack
+	alerter, err := notify.CreateAlerterFromEnv(mailer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create alerter: %w", err)
+	}
+	if alerter != nil {
+		alerter.SetTemplates(mh.MessageTemplates)
+	}
+	mh.Alerter = alerter
+
+	// Forward the jobs of selected tasks to remote instances
+	forwarder, err := forward.CreateForwarderFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job forwarder: %w", err)
+	}
+	if forwarder != nil {
+		forwardDb := &qh.Database{
+			Name:     "forwarded_job",
+			Logger:   logger,
+			Instance: queuerInstance.DB,
+		}
+		forwardDB, err := database.NewForwardedJobDBHandler(forwardDb, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create forwarded job database handler: %w", err)
+		}
+		mh.Forwarder = forwarder
+		mh.ForwardDB = forwardDB
+	}
+
+	// Require a login if LDAP or OpenID Connect is configured, users can also be provisioned with SCIM
+	authenticator, err := auth.NewLDAPAuthenticatorFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ldap authenticator: %w", err)
+	}
+	oidcProvider, err := auth.NewOIDCProviderFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create oidc provider: %w", err)
+	}
+	scimToken := helper.GetEnvOrDefault("QUEUER_MANAGER_SCIM_TOKEN", "")
+	if authenticator != nil || oidcProvider != nil || scimToken != "" {
+		userDb := &qh.Database{
+			Name:     "user",
+			Logger:   logger,
+			Instance: queuerInstance.DB,
+		}
+		userDB, err := database.NewUserDBHandler(userDb, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create user database handler: %w", err)
+		}
+		mh.UserDB = userDB
+	}
+	if scimToken != "" {
+		groupRoles, err := auth.ParseGroupRoles(helper.GetEnvOrDefault("QUEUER_MANAGER_SCIM_GROUP_ROLES", ""))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse scim group roles: %w", err)
+		}
+
+		groupDb := &qh.Database{
+			Name:     "group",
+			Logger:   logger,
+			Instance: queuerInstance.DB,
+		}
+		groupDB, err := database.NewGroupDBHandler(groupDb, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create group database handler: %w", err)
+		}
+
+		mh.ScimToken = scimToken
+		mh.GroupDB = groupDB
+		mh.ScimGroupRoles = groupRoles
+	}
+	if authenticator != nil {
+		mh.Authenticator = authenticator
+	}
+	if oidcProvider != nil {
+		mh.OIDC = oidcProvider
+	}
+	// Without a configured key the random key of the handler signs the sessions, so they are invalidated on restart.
+	// The anonymous sessions without login are signed with it too.
+	if sessionKey := helper.GetEnvOrDefault("QUEUER_MANAGER_SESSION_KEY", ""); sessionKey != "" {
+		mh.SessionKey = []byte(sessionKey)
+	}
+	mh.BootstrapAPIKey = helper.GetEnvOrDefault("QUEUER_MANAGER_BOOTSTRAP_API_KEY", "")
+
+	// Generate the RIDs of new rows with the configured strategy after all manager tables are created
+	err = setupRIDStrategy(queuerInstance.DB, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set rid strategy: %w", err)
+	}
 
 	return mh, nil
 }
 
+// setupRIDStrategy sets the RID strategy of QUEUER_MANAGER_RID_STRATEGY (random or uuidv7, default random)
+// and migrates the RIDs of existing rows to UUIDv7 if QUEUER_MANAGER_RID_MIGRATE is true.
+func setupRIDStrategy(instance *sql.DB, logger *slog.Logger) error {
+	ridDb := &qh.Database{
+		Name:     "rid",
+		Logger:   logger,
+		Instance: instance,
+	}
+	ridDB, err := database.NewRIDDBHandler(ridDb)
+	if err != nil {
+		return fmt.Errorf("failed to create rid database handler: %w", err)
+	}
+
+	strategy := helper.GetEnvOrDefault("QUEUER_MANAGER_RID_STRATEGY", model.RID_STRATEGY_RANDOM)
+	err = ridDB.SetRIDStrategy(strategy)
+	if err != nil {
+		return err
+	}
+
+	if helper.GetEnvOrDefault("QUEUER_MANAGER_RID_MIGRATE", "false") != "true" {
+		return nil
+	}
+	if strategy != model.RID_STRATEGY_UUIDV7 {
+		return fmt.Errorf("QUEUER_MANAGER_RID_MIGRATE requires QUEUER_MANAGER_RID_STRATEGY=%s", model.RID_STRATEGY_UUIDV7)
+	}
+	for _, table := range model.RID_MIGRATABLE_TABLES {
+		migrated, err := ridDB.MigrateRIDs(table)
+		if err != nil {
+			return err
+		}
+		if migrated > 0 {
+			logger.Info("Migrated rids to uuidv7", "table", table, "rows", migrated)
+		}
+	}
+
+	return nil
+}
+
+// leaseTTL is the time a lease of the background work is held without renewal, another instance takes it over afterwards.
+const leaseTTL = 30 * time.Second
+
+// leaseRenewInterval is the interval the leases of the background work are renewed.
+const leaseRenewInterval = 10 * time.Second
+
+// deprecationUsageFlushInterval is the interval the counted calls of the deprecated routes are written.
+const deprecationUsageFlushInterval = time.Minute
+
+// accessLogBufferSize is the number of access logs buffered until they are written, further access logs are dropped.
+const accessLogBufferSize = 1000
+
+// accessLogFlushInterval is the interval the buffered access logs are written.
+const accessLogFlushInterval = 5 * time.Second
+
+// accessLogCleanupInterval is the interval the expired access logs are deleted.
+const accessLogCleanupInterval = time.Hour
+
+// fileCleanupInterval is the interval the orphaned files are pruned.
+const fileCleanupInterval = time.Hour
+
+// jobStreamRetryInterval is the interval a lost job change listener is opened again after.
+const jobStreamRetryInterval = 10 * time.Second
+
+// recordMetrics inserts a queue metric snapshot every interval until the context is done.
+// The runs are recorded as runs of the scheduler in the status tracker, only the instance holding the housekeeping lease records them
+// and the advisory lock of the metric recording skips duplicate snapshots while the lease fails over.
+func recordMetrics(ctx context.Context, metricDB database.MetricDBHandlerFunctions, status *handler.StatusTracker, coordinator *handler.Coordinator, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !coordinator.Holds(model.LEASE_HOUSEKEEPING) {
+				continue
+			}
+			_, err := metricDB.RecordMetricSnapshot(model.MetricMinInterval(interval))
+			status.Record(model.SUBSYSTEM_SCHEDULER, wrapError("record metrics", err))
+			if err != nil {
+				slog.Warn("Failed to record queue metrics", "error", err)
+			}
+		}
+	}
+}
+
+// recordHealth persists the health of the subsystems every interval until the context is done.
+func recordHealth(ctx context.Context, mh *handler.ManagerHandler, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !mh.Coordinator.Holds(model.LEASE_HOUSEKEEPING) {
+				continue
+			}
+			err := mh.RecordHealth(ctx)
+			if err != nil {
+				slog.Warn("Failed to record health", "error", err)
+			}
+		}
+	}
+}
+
+// streamJobChanges publishes the jobs of the notifications of the channel to the job stream until the context is done.
+// A lost listener connection is opened again after the retry interval.
+func streamJobChanges(ctx context.Context, dbConfig *qh.DatabaseConfiguration, channel string, stream *handler.JobStream) {
+	for {
+		listener, err := qdb.NewQueuerDBListener(dbConfig, channel)
+		if err != nil {
+			slog.Warn("Failed to listen for job changes", "channel", channel, "error", err)
+		} else {
+			listenCtx, cancel := context.WithCancel(ctx)
+			listener.Listen(listenCtx, cancel, func(data string) {
+				job := &qmodel.JobFromNotification{}
+				err := json.Unmarshal([]byte(data), job)
+				if err != nil {
+					slog.Warn("Failed to read job change", "channel", channel, "error", err)
+					return
+				}
+				stream.Publish(job.ToJob())
+			})
+			cancel()
+			_ = listener.Listener.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(jobStreamRetryInterval):
+		}
+	}
+}
+
+// recordJobAttempts records the attempts of the changed jobs of the job stream until the context is done.
+func recordJobAttempts(ctx context.Context, mh *handler.ManagerHandler) {
+	jobs, unsubscribe := mh.JobStream.Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-jobs:
+			err := mh.RecordJobAttempt(job)
+			if err != nil {
+				slog.Warn("Failed to record job attempt", "job_rid", job.RID, "error", err)
+			}
+		}
+	}
+}
+
+// notifyJobWatchers notifies the watchers of the changed jobs of the job stream until the context is done.
+func notifyJobWatchers(ctx context.Context, mh *handler.ManagerHandler) {
+	jobs, unsubscribe := mh.JobStream.Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-jobs:
+			err := mh.NotifyJobWatchers(job)
+			if err != nil {
+				slog.Warn("Failed to notify job watchers", "job_rid", job.RID, "error", err)
+			}
+		}
+	}
+}
+
+// staleWorkerCheckInterval is the interval the workers are checked for missing heartbeats.
+const staleWorkerCheckInterval = 30 * time.Second
+
+// alertStaleWorkers alerts about the workers that became stale since the last check every interval until the context is done.
+// A worker is stale if its last heartbeat is older than stale after.
+func alertStaleWorkers(ctx context.Context, mh *handler.ManagerHandler, staleAfter time.Duration, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	since := time.Now().Add(-staleAfter)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			until := now.Add(-staleAfter)
+			if !mh.Coordinator.Holds(model.LEASE_HOUSEKEEPING) {
+				since = until
+				continue
+			}
+			alerted, err := mh.AlertStaleWorkers(since, until)
+			if err != nil {
+				slog.Warn("Failed to check stale workers", "error", err)
+				continue
+			}
+			if alerted > 0 {
+				slog.Info("Alerted stale workers", "alerted", alerted)
+			}
+			since = until
+		}
+	}
+}
+
+// workerRegisteredCheckInterval is the interval the workers are checked for new registrations.
+const workerRegisteredCheckInterval = 10 * time.Second
+
+// publishRegisteredWorkers publishes the workers registered since the last check every interval until the context is done,
+// if the instance holds the housekeeping lease.
+func publishRegisteredWorkers(ctx context.Context, mh *handler.ManagerHandler, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	since := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case until := <-ticker.C:
+			if !mh.Coordinator.Holds(model.LEASE_HOUSEKEEPING) {
+				since = until
+				continue
+			}
+			_, err := mh.PublishRegisteredWorkers(since, until)
+			if err != nil {
+				slog.Warn("Failed to publish registered workers", "error", err)
+				continue
+			}
+			since = until
+		}
+	}
+}
+
+// runSchedules adds the jobs of the due schedules every interval until the context is done, if the instance holds the scheduler lease.
+func runSchedules(ctx context.Context, mh *handler.ManagerHandler, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if !mh.Coordinator.Holds(model.LEASE_SCHEDULER) {
+				continue
+			}
+			added, err := mh.RunDueSchedules(ctx, now)
+			mh.Status.Record(model.SUBSYSTEM_SCHEDULER, wrapError("run schedules", err))
+			if err != nil {
+				slog.Warn("Failed to run schedules", "error", err)
+			}
+			if added > 0 {
+				slog.Info("Added jobs of schedules", "added", added)
+			}
+		}
+	}
+}
+
+// addHousekeepingJobs adds a job of the housekeeping task every interval until the context is done, if the instance holds the lease of the task.
+func addHousekeepingJobs(ctx context.Context, mh *handler.ManagerHandler, taskKey string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !mh.Coordinator.Holds(model.LeaseOfHousekeepingTask(taskKey)) {
+				continue
+			}
+			err := mh.AddHousekeepingJob(taskKey)
+			if err != nil {
+				mh.Status.Record(model.SUBSYSTEM_SCHEDULER, err)
+				slog.Warn("Failed to add housekeeping job", "task_key", taskKey, "error", err)
+			}
+		}
+	}
+}
+
+// deleteExpiredAccessLogs deletes the access logs older than the access log retention every interval until the context is done.
+func deleteExpiredAccessLogs(ctx context.Context, mh *handler.ManagerHandler, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !mh.Coordinator.Holds(model.LEASE_HOUSEKEEPING) {
+				continue
+			}
+			deleted, err := mh.DeleteExpiredAccessLogs()
+			mh.Status.Record(model.SUBSYSTEM_SCHEDULER, wrapError("delete access logs", err))
+			if err != nil {
+				slog.Warn("Failed to delete expired access logs", "error", err)
+			} else if deleted > 0 {
+				slog.Info("Deleted expired access logs", "deleted", deleted)
+			}
+		}
+	}
+}
+
+// pruneOrphanedFiles prunes the orphaned files every interval until the context is done and logs the report of the reclaimed space.
+func pruneOrphanedFiles(ctx context.Context, mh *handler.ManagerHandler, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !mh.Coordinator.Holds(model.LEASE_HOUSEKEEPING) {
+				continue
+			}
+			report, err := mh.PruneOrphanedFiles()
+			mh.Status.Record(model.SUBSYSTEM_SCHEDULER, wrapError("prune files", err))
+			if err != nil {
+				slog.Warn("Failed to prune orphaned files", "error", err)
+			} else if report.TrashedFiles > 0 || report.PurgedFiles > 0 {
+				slog.Info("Pruned orphaned files", "trashed", report.TrashedFiles, "trashed_bytes", report.TrashedBytes, "purged", report.PurgedFiles, "reclaimed_bytes", report.ReclaimedBytes, "trash_bytes", report.TrashBytes)
+			}
+		}
+	}
+}
+
+// syncUserRoles updates the roles of the users with their groups every interval until the context is done.
+func syncUserRoles(ctx context.Context, mh *handler.ManagerHandler, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !mh.Coordinator.Holds(model.LEASE_HOUSEKEEPING) {
+				continue
+			}
+			changed, err := mh.SyncUserRoles()
+			mh.Status.Record(model.SUBSYSTEM_SCHEDULER, wrapError("sync user roles", err))
+			if err != nil {
+				slog.Warn("Failed to sync user roles", "error", err)
+			} else if changed > 0 {
+				slog.Info("Synced user roles", "changed", changed)
+			}
+		}
+	}
+}
+
+// syncForwardedJobs syncs the status and results of the forwarded jobs every interval until the context is done.
+func syncForwardedJobs(ctx context.Context, mh *handler.ManagerHandler, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !mh.Coordinator.Holds(model.LEASE_HOUSEKEEPING) {
+				continue
+			}
+			ended, err := mh.SyncForwardedJobs(ctx)
+			mh.Status.Record(model.SUBSYSTEM_SCHEDULER, wrapError("sync forwarded jobs", err))
+			if err != nil {
+				slog.Warn("Failed to sync forwarded jobs", "error", err)
+			} else if ended > 0 {
+				slog.Info("Synced forwarded jobs", "ended", ended)
+			}
+		}
+	}
+}
+
+// wrapError prefixes the error with the name of the background run, it returns nil for a nil error.
+func wrapError(run string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", run, err)
+}
+
 func loadTasksFromJSON(filePath string, taskDB database.TaskDBHandlerFunctions, logger *slog.Logger) error {
 	// #nosec G304 -- Accepting file path from env variable is intentional and controlled.
 	data, err := os.ReadFile(filePath)
@@ -1,10 +1,12 @@
 package queuerManager
 
 import (
+	"log"
 	"net/http"
 
 	"github.com/siherrmann/queuerManager/handler"
 	mw "github.com/siherrmann/queuerManager/middleware"
+	"github.com/siherrmann/queuerManager/model"
 
 	"github.com/labstack/echo/v5"
 	"github.com/labstack/echo/v5/middleware"
@@ -12,7 +14,16 @@ import (
 
 // SetupRoutes configures all API routes for the manager service
 func SetupRoutes(e *echo.Echo, h *handler.ManagerHandler) {
+	setupRoutes(e, h, mw.NewMiddleware(), "")
+}
+
+// setupRoutes configures all API routes for the manager service with the middlewares, eg. with the trusted origins of the CSRF protection,
+// and serves them under the base path if it is not empty
+func setupRoutes(e *echo.Echo, h *handler.ManagerHandler, m *mw.Middleware, basePath string) {
 	// Middleware
+	if basePath != "" {
+		e.Pre(mw.BasePathMiddleware(basePath))
+	}
 	// e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
@@ -21,74 +32,352 @@ func SetupRoutes(e *echo.Echo, h *handler.ManagerHandler) {
 	}))
 
 	// Custom Middleware
-	m := mw.NewMiddleware()
 	e.Use(m.RequestContextMiddleware)
+	e.Use(h.AccessLogMiddleware)
+	e.Use(h.AuthMiddleware)
+	e.Use(h.DeprecationMiddleware)
+	e.Use(h.RBACMiddleware)
+	e.Use(h.QueuerBreakerMiddleware)
+
+	// Roles, only checked if a login is configured, viewers can only read
+	operator := h.RequireRole(model.ROLE_OPERATOR)
+	admin := h.RequireRole(model.ROLE_ADMIN)
 
 	// View routes
 	e.GET("/health", h.HealthCheck, m.CsrfMiddleware())
+	e.GET("/status", h.StatusView, m.CsrfMiddleware())
+	e.GET("/queuerBanner", h.QueuerBannerView, m.CsrfMiddleware())
+	e.GET("/namespaceSwitcher", h.NamespaceSwitcherView, m.CsrfMiddleware())
+	e.GET("/login", h.LoginView, m.CsrfMiddleware())
+	e.POST("/login", h.Login, m.CsrfMiddleware())
+	e.GET("/login/oidc", h.OIDCLogin)
+	e.GET("/login/oidc/callback", h.OIDCCallback)
+	e.GET("/logout", h.Logout)
 	e.GET("/", h.AddJobView, m.CsrfMiddleware())
+	e.DELETE("/popup/:id", h.DismissPopup, m.CsrfMiddleware())
 	e.GET("/task/:taskKey", h.AddJobConfigView, m.CsrfMiddleware())
 
+	e.GET("/apiKeys", h.APIKeysView, m.CsrfMiddleware(), admin)
+	e.GET("/apiKey/createApiKeyPopup", h.CreateAPIKeyPopupView, m.CsrfMiddleware(), admin)
+	e.GET("/apiKey/rotateApiKeyPopup", h.RotateAPIKeyPopupView, m.CsrfMiddleware(), admin)
+	e.GET("/apiKey/revokeApiKeyPopup", h.RevokeAPIKeyPopupView, m.CsrfMiddleware(), admin)
+
+	e.GET("/featureFlags", h.FeatureFlagsView, m.CsrfMiddleware(), admin)
+	e.GET("/featureFlag/updateFeatureFlagPopup", h.UpdateFeatureFlagPopupView, m.CsrfMiddleware(), admin)
+	e.GET("/scheduler", h.SchedulerPolicyView, m.CsrfMiddleware(), admin)
+	e.GET("/messageTemplates", h.MessageTemplatesView, m.CsrfMiddleware(), admin)
+	e.GET("/catalog", h.CatalogView, m.CsrfMiddleware(), admin)
+
+	e.GET("/incidents", h.IncidentsView, m.CsrfMiddleware(), admin)
+	e.GET("/incident/addIncidentPopup", h.AddIncidentPopupView, m.CsrfMiddleware(), admin)
+	e.GET("/incident/resolveIncidentPopup", h.ResolveIncidentPopupView, m.CsrfMiddleware(), admin)
+	e.GET("/incident/deleteIncidentPopup", h.DeleteIncidentPopupView, m.CsrfMiddleware(), admin)
+
+	e.GET("/recorder", h.RecorderView, m.CsrfMiddleware(), admin)
+	e.GET("/accessLogs", h.AccessLogsView, m.CsrfMiddleware(), admin)
+	e.GET("/coordination", h.CoordinationView, m.CsrfMiddleware(), admin)
+	e.GET("/deprecations", h.DeprecationsView, m.CsrfMiddleware(), admin)
+	e.GET("/coordination/failoverLeasePopup", h.FailoverLeasePopupView, m.CsrfMiddleware(), admin)
+	e.GET("/recorder/recordingPopup", h.RecordingPopupView, m.CsrfMiddleware(), admin)
+	e.GET("/recorder/clearRecorderPopup", h.ClearRecorderPopupView, m.CsrfMiddleware(), admin)
+
 	e.GET("/files", h.FilesView, m.CsrfMiddleware())
 	e.GET("/file", h.FileView, m.CsrfMiddleware())
-	e.GET("/file/addFilePopup", h.AddFilePopupView, m.CsrfMiddleware())
-	e.GET("/file/deleteFilePopup", h.DeleteFilePopupView, m.CsrfMiddleware())
+	e.GET("/file/addFilePopup", h.AddFilePopupView, m.CsrfMiddleware(), admin)
+	e.GET("/file/deleteFilePopup", h.DeleteFilePopupView, m.CsrfMiddleware(), admin)
 
 	e.GET("/job", h.JobView, m.CsrfMiddleware())
+	e.GET("/job/tab/:tab", h.JobTabView, m.CsrfMiddleware())
+	e.GET("/job/overrideJobStatusPopup", h.OverrideJobStatusPopupView, m.CsrfMiddleware(), admin)
+	e.GET("/job/setJobPriorityPopup", h.SetJobPriorityPopupView, m.CsrfMiddleware(), operator)
+	e.GET("/job/importJobsPopup", h.ImportJobsPopupView, m.CsrfMiddleware(), operator)
+	e.GET("/dashboard", h.DashboardView, m.CsrfMiddleware())
 	e.GET("/jobs", h.JobsView, m.CsrfMiddleware())
+	e.GET("/events/jobs", h.JobEvents)
+	e.GET("/events/watches", h.WatchEvents)
 	e.GET("/jobArchive", h.JobArchiveView, m.CsrfMiddleware())
-	e.GET("/jobArchive/readdJob", h.ReaddJobFromArchiveView, m.CsrfMiddleware())
+	e.GET("/jobArchive/readdJob", h.ReaddJobFromArchiveView, m.CsrfMiddleware(), operator)
+	e.GET("/jobArchive/retryJobPopup", h.RetryJobPopupView, m.CsrfMiddleware(), operator)
+	e.GET("/jobArchive/maintenance", h.JobArchiveMaintenanceView, m.CsrfMiddleware(), admin)
+	e.GET("/jobArchive/dropPartitionPopup", h.DropJobArchivePartitionPopupView, m.CsrfMiddleware(), admin)
+	e.GET("/failedJobs", h.FailedJobsView, m.CsrfMiddleware())
+	e.GET("/scheduledJobs", h.ScheduledJobsView, m.CsrfMiddleware())
+	e.GET("/batch", h.BatchView, m.CsrfMiddleware())
 
 	e.GET("/worker", h.WorkerView, m.CsrfMiddleware())
+	e.GET("/profile", h.ProfileView, m.CsrfMiddleware())
+	e.GET("/worker/tab/:tab", h.WorkerTabView, m.CsrfMiddleware())
+	e.GET("/worker/logs", h.WorkerLogsView, m.CsrfMiddleware())
 	e.GET("/workers", h.WorkersView, m.CsrfMiddleware())
-	e.GET("/worker/stopWorkers", h.StopWorkersView, m.CsrfMiddleware())
-	e.GET("/worker/stopWorkersGracefully", h.StopWorkersGracefullyView, m.CsrfMiddleware())
+	e.GET("/worker/stopWorkers", h.StopWorkersView, m.CsrfMiddleware(), admin)
+	e.GET("/worker/stopWorkersGracefully", h.StopWorkersGracefullyView, m.CsrfMiddleware(), admin)
+	e.GET("/worker/scaleWorkersPopup", h.ScaleWorkersPopupView, m.CsrfMiddleware())
 
 	e.GET("/tasks", h.TasksView, m.CsrfMiddleware())
 	e.GET("/task", h.TaskView, m.CsrfMiddleware())
+	e.GET("/task/tab/:tab", h.TaskTabView, m.CsrfMiddleware())
+	e.GET("/task/taskRow", h.TaskRowView, m.CsrfMiddleware())
+	e.GET("/task/parameterRow", h.ParameterRowView, m.CsrfMiddleware())
 	e.GET("/task/addTaskPopup", h.AddTaskPopupView, m.CsrfMiddleware())
 	e.GET("/task/updateTaskPopup", h.UpdateTaskPopupView, m.CsrfMiddleware())
 	e.GET("/task/deleteTaskPopup", h.DeleteTaskPopupView, m.CsrfMiddleware())
 	e.GET("/task/importTaskPopup", h.ImportTaskPopupView, m.CsrfMiddleware())
 
+	e.GET("/schedules", h.SchedulesView, m.CsrfMiddleware())
+	e.GET("/schedule/addSchedulePopup", h.AddSchedulePopupView, m.CsrfMiddleware(), operator)
+	e.GET("/schedule/updateSchedulePopup", h.UpdateSchedulePopupView, m.CsrfMiddleware(), operator)
+	e.GET("/schedule/updateScheduleEnabledPopup", h.UpdateScheduleEnabledPopupView, m.CsrfMiddleware(), operator)
+	e.GET("/schedule/deleteSchedulePopup", h.DeleteSchedulePopupView, m.CsrfMiddleware(), operator)
+
 	// API routes
 	api := e.Group("/api")
+	// Public without login below /api/status, the JSON of the public status page: the health of the subsystems
+	// and their uptime history with the incidents annotated by the admins
+	api.GET("/status", h.GetStatus)
+	api.GET("/status/history", h.GetHealthHistory)
+	api.GET("/version", h.GetVersion)
+	api.GET("/openapi.json", h.GetOpenAPI)
+	api.GET("/docs", h.OpenAPIDocsView)
+	api.GET("/graphql", h.GraphQL)
+	api.POST("/graphql", h.GraphQL)
+	api.GET("/graphql/schema", h.GetGraphQLSchema)
 
 	jobs := api.Group("/job")
-	jobs.POST("/addJob/:taskKey", h.AddJob)
-	jobs.POST("/cancelJob/:rid", h.CancelJob)
-	jobs.POST("/cancelJobs", h.CancelJobs)
-	jobs.POST("/deleteJob/:rid", h.DeleteJob)
+	jobs.POST("/addJob/:taskKey", h.AddJob, operator, h.JobQuotaMiddleware, h.StorageQuotaMiddleware)
+	jobs.POST("/addJobsBulk/:taskKey", h.AddJobsBulk, operator, h.JobQuotaMiddleware)
+	jobs.POST("/cancelJob/:rid", h.CancelJob, operator)
+	jobs.POST("/cancelJobs", h.CancelJobs, operator)
+	jobs.POST("/killJob/:rid", h.KillJob, operator)
+	jobs.POST("/overrideJobStatus/:rid", h.OverrideJobStatus, admin)
+	jobs.POST("/setPriority/:rid", h.SetJobPriority, operator)
+	jobs.POST("/deleteJob/:rid", h.DeleteJob, operator)
 	jobs.POST("/getJob/:rid", h.GetJob)
+	jobs.POST("/getJobTimeline/:rid", h.GetJobTimeline)
+	jobs.POST("/getJobChains/:rid", h.GetJobChains)
+	jobs.POST("/saveJobTemplate/:taskKey", h.SaveJobTemplate, operator)
+	jobs.POST("/getJobTemplates/:taskKey", h.GetJobTemplates)
+	jobs.POST("/runTemplate/:rid", h.RunJobTemplate, operator, h.JobQuotaMiddleware)
+	jobs.POST("/deleteJobTemplate/:rid", h.DeleteJobTemplate, operator)
 	jobs.POST("/getJobs", h.GetJobs)
+	jobs.POST("/getScheduledJobs", h.GetScheduledJobs)
+
+	apiKeys := api.Group("/apiKey")
+	apiKeys.POST("/createApiKey", h.CreateAPIKey, admin)
+	apiKeys.GET("/getApiKeys", h.GetAPIKeys, admin)
+	apiKeys.POST("/rotateApiKey", h.RotateAPIKey, admin)
+	apiKeys.POST("/revokeApiKeys", h.RevokeAPIKeys, admin)
+
+	featureFlags := api.Group("/featureFlag")
+	featureFlags.GET("/getFeatureFlags", h.GetFeatureFlags)
+	featureFlags.POST("/updateFeatureFlags", h.UpdateFeatureFlags, admin)
+
+	scheduler := api.Group("/scheduler")
+	scheduler.GET("/getPolicy", h.GetSchedulerPolicy)
+	scheduler.POST("/updatePolicy", h.UpdateSchedulerPolicy, admin)
+
+	messageTemplate := api.Group("/messageTemplate")
+	messageTemplate.GET("/getMessageTemplates", h.GetMessageTemplates)
+	messageTemplate.POST("/updateMessageTemplate", h.UpdateMessageTemplate, admin)
+	messageTemplate.POST("/resetMessageTemplate", h.ResetMessageTemplate, admin)
+	messageTemplate.POST("/previewMessageTemplate", h.PreviewMessageTemplate, admin)
+	messageTemplate.POST("/testMessageTemplate", h.TestMessageTemplate, admin)
+
+	// Not below /api/status, which is public
+	incidents := api.Group("/incident")
+	incidents.GET("/getIncidents", h.GetIncidents, admin)
+	incidents.POST("/addIncident", h.AddIncident, admin)
+	incidents.POST("/resolveIncidents", h.ResolveIncidents, admin)
+	incidents.POST("/deleteIncidents", h.DeleteIncidents, admin)
+
+	catalog := api.Group("/catalog")
+	catalog.POST("/createSnapshot", h.CreateCatalogSnapshot, admin)
+	catalog.GET("/getSnapshots", h.GetCatalogSnapshots, admin)
+	catalog.GET("/getChanges", h.GetCatalogChanges, admin)
+
+	accessLog := api.Group("/accessLog")
+	accessLog.GET("/getAccessLogs", h.GetAccessLogs, admin)
+	accessLog.GET("/exportAccessLogs", h.ExportAccessLogs, admin)
+
+	quota := api.Group("/quota")
+	quota.GET("/getUsage", h.GetQuotaUsage)
+	quota.GET("/getPrincipalUsage", h.GetPrincipalQuotaUsage, admin)
+
+	coordination := api.Group("/coordination")
+	coordination.GET("/getStatus", h.GetCoordinationStatus)
+	coordination.POST("/failover", h.FailoverLease, admin)
+
+	namespace := api.Group("/namespace")
+	namespace.GET("/getNamespaces", h.GetNamespaces)
+	namespace.POST("/switchNamespace", h.SwitchNamespace)
+
+	deprecation := api.Group("/deprecation")
+	deprecation.GET("/getReport", h.GetDeprecationReport, admin)
+	deprecation.POST("/resetUsages", h.ResetDeprecationUsages, admin)
+
+	recorder := api.Group("/recorder")
+	recorder.GET("/getRecorder", h.GetRecorder, admin)
+	recorder.GET("/getRecording", h.GetRecording, admin)
+	recorder.POST("/updateRecorder", h.UpdateRecorder, admin)
+	recorder.POST("/clearRecorder", h.ClearRecorder, admin)
+
+	// JSON only job API for clients without HTMX
+	v1 := api.Group("/v1")
+	v1.POST("/jobs", h.CreateJob, operator, h.JobQuotaMiddleware)
+
+	batches := api.Group("/batch")
+	batches.POST("/addBatch/:taskKey", h.AddBatch, operator, h.JobQuotaMiddleware)
+	batches.GET("/getBatch/:rid", h.GetBatch)
+	batches.GET("/getBatches", h.GetBatches)
+	batches.POST("/cancelBatch", h.CancelBatch, operator)
+	batches.GET("/exportBatch", h.ExportBatch)
+
+	forwardedJobs := api.Group("/forward")
+	forwardedJobs.GET("/getForwardedJob/:rid", h.GetForwardedJob)
+	forwardedJobs.GET("/getForwardedJobs", h.GetForwardedJobs)
 
 	jobArchives := api.Group("/jobArchive")
 	jobArchives.GET("/getJob/:rid", h.GetJobArchive)
 	jobArchives.GET("/getJobs", h.GetJobsArchive)
+	jobArchives.GET("/export", h.ExportJobsArchive)
+	jobArchives.POST("/retryJobs", h.RetryJobs, operator, h.JobQuotaMiddleware)
+	jobArchives.GET("/getMaintenance", h.GetJobArchiveMaintenance, admin)
+	jobArchives.POST("/createPartition", h.CreateJobArchivePartition, admin)
+	jobArchives.POST("/dropPartition", h.DropJobArchivePartition, admin)
+
+	failedJobs := api.Group("/failedJobs")
+	failedJobs.GET("/getGroups", h.GetFailedJobGroups)
+
+	watches := api.Group("/watch")
+	watches.POST("/addWatch", h.AddWatch)
+	watches.POST("/deleteWatch", h.DeleteWatch)
+	watches.GET("/getWatches", h.GetWatches)
 
 	workers := api.Group("/worker")
 	workers.GET("/getWorker/:rid", h.GetWorker)
 	workers.GET("/getWorkers", h.GetWorkers)
+	workers.GET("/getWorkerLogs/:rid", h.GetWorkerLogs)
+	workers.POST("/setWorkerVersion/:rid", h.SetWorkerVersion, operator)
+	workers.POST("/stopWorkers", h.StopWorkersView, admin)
+	workers.POST("/stopWorkersGracefully", h.StopWorkersGracefullyView, admin)
+	workers.POST("/scaleWorkers", h.ScaleWorkers, admin)
+	workers.GET("/getScaleAudits", h.GetScaleAudits)
 
 	tasks := api.Group("/task")
-	tasks.POST("/addTask", h.AddTask)
-	tasks.POST("/updateTask", h.UpdateTask)
-	tasks.POST("/deleteTasks", h.DeleteTasks)
+	tasks.POST("/addTask", h.AddTask, admin)
+	tasks.POST("/updateTask", h.UpdateTask, admin)
+	tasks.POST("/diffTask", h.DiffTask, admin)
+	tasks.POST("/deleteTasks", h.DeleteTasks, admin)
 	tasks.GET("/getTask/:rid", h.GetTask)
 	tasks.GET("/getTaskByName/:name", h.GetTaskByName)
+	tasks.GET("/getTaskByKey/:key", h.GetTaskByKey)
+	tasks.PUT("/putTask/:key", h.PutTask, admin)
+	tasks.DELETE("/deleteTaskByKey/:key", h.DeleteTaskByKey, admin)
 	tasks.GET("/getTasks", h.GetTasks)
 	tasks.GET("/exportTask", h.ExportTask)
-	tasks.POST("/importTask", h.ImportTask)
+	tasks.GET("/:rid/exportResults", h.ExportTaskResults)
+	tasks.POST("/importTask", h.ImportTask, admin)
+	tasks.POST("/importTaskSignatures", h.ImportTaskSignatures, admin)
+	tasks.PUT("/putTaskCost/:key", h.PutTaskCost, admin)
+	tasks.GET("/getTaskCost/:key", h.GetTaskCost)
+	tasks.GET("/getTaskCosts", h.GetTaskCosts)
+	tasks.GET("/getTaskUtilization/:key", h.GetTaskUtilization)
+	tasks.DELETE("/deleteTaskCost/:key", h.DeleteTaskCost, admin)
+	tasks.PUT("/putTaskSample/:key", h.PutTaskSample, admin)
+	tasks.POST("/saveTaskSample/:key", h.SaveTaskSample, admin)
+	tasks.GET("/getTaskSample/:key", h.GetTaskSample)
+	tasks.DELETE("/deleteTaskSample/:key", h.DeleteTaskSample, admin)
+
+	schedules := api.Group("/schedule")
+	schedules.POST("/addSchedule", h.AddSchedule, operator)
+	schedules.POST("/updateSchedule", h.UpdateSchedule, operator)
+	schedules.POST("/updateSchedulesEnabled", h.UpdateSchedulesEnabled, operator)
+	schedules.POST("/deleteSchedules", h.DeleteSchedules, operator)
+	schedules.GET("/getSchedule/:rid", h.GetSchedule)
+	schedules.GET("/getSchedules", h.GetSchedules)
 
 	files := api.Group("/file")
-	files.POST("/uploadFiles", h.UploadFiles)
-	files.POST("/deleteFile/:filename", h.DeleteFile)
-	files.POST("/deleteFiles", h.DeleteFiles)
+	files.GET("/getFiles", h.GetFiles)
+	files.POST("/uploadFiles", h.UploadFiles, admin, h.StorageQuotaMiddleware)
+	files.POST("/deleteFile/:filename", h.DeleteFile, admin)
+	files.POST("/deleteFiles", h.DeleteFiles, admin)
+	files.GET("/getTrash", h.GetFileTrash, admin)
+	files.POST("/pruneFiles", h.PruneFiles, admin)
+
+	metrics := api.Group("/metric")
+	metrics.GET("/getMetrics", h.GetMetrics)
+
+	stats := api.Group("/stats")
+	stats.GET("", h.GetStats)
+	stats.GET("/forecast", h.GetForecast)
+	stats.GET("/costs", h.GetCosts)
+	stats.GET("/heatmap", h.GetHeatmap)
+
+	// Prometheus metrics and the generated alert rules and dashboard for them
+	observability := api.Group("/observability")
+	observability.GET("/metrics", h.GetObservabilityMetrics)
+	observability.GET("/bundle", h.GetObservabilityBundle)
+
+	// Grafana JSON and Infinity datasource endpoints
+	grafana := api.Group("/grafana")
+	grafana.GET("", h.GrafanaHealth)
+	grafana.GET("/", h.GrafanaHealth)
+	grafana.POST("/metrics", h.GrafanaMetrics)
+	grafana.POST("/query", h.GrafanaQuery)
+	grafana.GET("/timeseries", h.GrafanaTimeSeries)
+
+	slack := api.Group("/slack")
+	slack.POST("/command", h.SlackCommand)
+
+	// CI pipeline endpoints (eg. GitHub Actions)
+	ci := api.Group("/ci")
+	ci.POST("/runJob/:taskKey", h.CIRunJob)
+	ci.GET("/waitJob/:rid", h.CIWaitJob)
+	ci.GET("/downloadArtifact/:filename", h.CIDownloadArtifact)
+
+	// SCIM 2.0 provisioning of users and groups by the identity provider
+	scim := e.Group("/scim/v2", h.ScimTokenMiddleware)
+	scim.GET("/ServiceProviderConfig", h.ScimServiceProviderConfig)
+	scim.GET("/Users", h.ScimGetUsers)
+	scim.POST("/Users", h.ScimCreateUser)
+	scim.GET("/Users/:id", h.ScimGetUser)
+	scim.PUT("/Users/:id", h.ScimReplaceUser)
+	scim.PATCH("/Users/:id", h.ScimPatchUser)
+	scim.DELETE("/Users/:id", h.ScimDeleteUser)
+	scim.GET("/Groups", h.ScimGetGroups)
+	scim.POST("/Groups", h.ScimCreateGroup)
+	scim.GET("/Groups/:id", h.ScimGetGroup)
+	scim.PUT("/Groups/:id", h.ScimReplaceGroup)
+	scim.PATCH("/Groups/:id", h.ScimPatchGroup)
+	scim.DELETE("/Groups/:id", h.ScimDeleteGroup)
 
 	connections := api.Group("/connection")
 	connections.GET("/getConnections", h.GetConnections)
+	connections.GET("/getPoolStats", h.GetPoolStats)
+
+	// Dev-only routes
+	if handler.LoadTestEnabled() {
+		loadTests := api.Group("/loadTest")
+		loadTests.POST("/generate", h.GenerateLoadTest, admin)
+	}
+
+	// Read-only dashboard without login, it is not registered if its path is already a route, so no route becomes public
+	if h.PublicDashboardPath != "" {
+		if _, err := e.Router().Routes().FilterByPath(h.PublicDashboardPath); err == nil {
+			log.Printf("Public dashboard path %s is already a route, the public dashboard is disabled", h.PublicDashboardPath)
+			h.PublicDashboardPath = ""
+		} else {
+			e.GET(h.PublicDashboardPath, h.PublicDashboardView)
+		}
+	}
 
 	e.Use(middleware.GzipWithConfig(middleware.GzipConfig{
 		Level: 5,
+		// Server-sent events are flushed per event and not compressed
+		Skipper: func(c *echo.Context) bool {
+			return c.Request().Header.Get(echo.HeaderAccept) == "text/event-stream"
+		},
 	}))
+
+	// Registered after gzip, so the recorder sees the uncompressed responses
+	e.Use(h.RecorderMiddleware)
 }
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+)
+
+// basePathRedirectHeaders are the response headers with paths of the manager, which are prefixed with the base path.
+var basePathRedirectHeaders = []string{"Location", "HX-Redirect", "HX-Location", "HX-Push-Url", "HX-Replace-Url"}
+
+// NormalizeBasePath returns the base path with a leading and without a trailing slash, eg. "queuer/" is "/queuer".
+// An empty path or "/" is the root and returns an empty base path.
+func NormalizeBasePath(basePath string) (string, error) {
+	basePath = strings.Trim(strings.TrimSpace(basePath), "/")
+	if basePath == "" {
+		return "", nil
+	}
+	if strings.ContainsAny(basePath, "?#:* ") || strings.Contains(basePath, "//") {
+		return "", fmt.Errorf("invalid base path %q (must be a plain path like /queuer)", basePath)
+	}
+	return "/" + basePath, nil
+}
+
+// BasePathMiddleware serves the manager under the base path, eg. behind a reverse proxy at /queuer/.
+// It has to be registered with Echo#Pre, so the base path is removed from the request path before the routing.
+// Requests without the base path are served unchanged, so proxies that remove the prefix themselves work too.
+func BasePathMiddleware(basePath string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			req, w := WithBasePath(c.Request(), c.Response(), basePath)
+			c.SetRequest(req)
+			c.SetResponse(w)
+			return next(c)
+		}
+	}
+}
+
+// WithBasePath returns the request without the base path in its path and with the base path in its context,
+// so the views prefix their links, and the response writer prefixing the paths of the redirect headers.
+func WithBasePath(req *http.Request, w http.ResponseWriter, basePath string) (*http.Request, http.ResponseWriter) {
+	req = req.WithContext(context.WithValue(req.Context(), "basePath", basePath))
+	if req.URL.Path == basePath || strings.HasPrefix(req.URL.Path, basePath+"/") {
+		url := *req.URL
+		url.Path = "/" + strings.TrimPrefix(strings.TrimPrefix(req.URL.Path, basePath), "/")
+		url.RawPath = ""
+		req.URL = &url
+	}
+	return req, &basePathResponseWriter{ResponseWriter: w, basePath: basePath}
+}
+
+// basePathResponseWriter prefixes the paths of the redirect headers with the base path.
+type basePathResponseWriter struct {
+	http.ResponseWriter
+	basePath    string
+	wroteHeader bool
+}
+
+// WriteHeader prefixes the redirect headers before the headers are written.
+func (w *basePathResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		for _, header := range basePathRedirectHeaders {
+			path := w.Header().Get(header)
+			if strings.HasPrefix(path, "/") && !strings.HasPrefix(path, "//") {
+				w.Header().Set(header, w.basePath+path)
+			}
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write writes the headers with the status OK if they were not written before.
+func (w *basePathResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the original response writer, so the event streams can flush it.
+func (w *basePathResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -5,10 +5,17 @@ import (
 )
 
 type Middleware struct {
-	csrfKey []byte
+	csrfKey            []byte
+	csrfTrustedOrigins []string
 }
 
-func NewMiddleware() *Middleware {
+// NewMiddleware creates the middlewares, the CSRF protection allows cross origin requests of the trusted origins,
+// by default of the manager on localhost:3000.
+func NewMiddleware(csrfTrustedOrigins ...string) *Middleware {
+	if len(csrfTrustedOrigins) == 0 {
+		csrfTrustedOrigins = []string{"http://localhost:3000", "http://127.0.0.1:3000"}
+	}
+
 	csrfKey := make([]byte, 32)
 	n, err := rand.Read(csrfKey)
 	if err != nil {
@@ -19,6 +26,7 @@ func NewMiddleware() *Middleware {
 	}
 
 	return &Middleware{
-		csrfKey: csrfKey,
+		csrfKey:            csrfKey,
+		csrfTrustedOrigins: csrfTrustedOrigins,
 	}
 }
@@ -11,8 +11,9 @@ func (r Middleware) CsrfMiddleware() echo.MiddlewareFunc {
 	cop := http.NewCrossOriginProtection()
 	cop.SetDenyHandler(http.HandlerFunc(handler.HandleCSRFErrorView))
 
-	_ = cop.AddTrustedOrigin("http://localhost:3000")
-	_ = cop.AddTrustedOrigin("http://127.0.0.1:3000")
+	for _, origin := range r.csrfTrustedOrigins {
+		_ = cop.AddTrustedOrigin(origin)
+	}
 
 	return echo.WrapMiddleware(cop.Handler)
 }
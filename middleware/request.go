@@ -12,6 +12,9 @@ func (r *Middleware) RequestContextMiddleware(next echo.HandlerFunc) echo.Handle
 
 		rc.Url = c.Request().URL.Path
 		rc.HxRequest = c.Request().Header.Get("hx-request") == "true"
+		if cookie, err := c.Cookie(model.NAMESPACE_COOKIE_NAME); err == nil {
+			rc.Namespace = cookie.Value
+		}
 
 		model.SetRequestContext(c, rc)
 
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// DEFAULT_ACCESS_LOG_RETENTION_DAYS is the number of days the access logs are kept by default.
+const DEFAULT_ACCESS_LOG_RETENTION_DAYS = 90
+
+// AccessLog is the access log entry of a request to the manager.
+// Route is the registered route, eg. /api/job/cancelJob, and User the username of the session or API key,
+// empty without login, so IP identifies the client then.
+type AccessLog struct {
+	ID        int       `json:"id"`
+	Method    string    `json:"method"`
+	Route     string    `json:"route"`
+	Path      string    `json:"path"`
+	Query     string    `json:"query"`
+	User      string    `json:"user"`
+	IP        string    `json:"ip"`
+	Status    int       `json:"status"`
+	LatencyMs int64     `json:"latency_ms"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AccessLogFilter filters the access logs by user, route prefix, method, status and time range.
+// Empty fields and zero times do not filter.
+type AccessLogFilter struct {
+	User   string    `json:"user"`
+	Route  string    `json:"route"`
+	Method string    `json:"method"`
+	Status int       `json:"status"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+)
+
+// Kinds of the alerts, tasks opt in to the alerts of their failed jobs, stale workers and missed schedule runs by kind.
+const (
+	ALERT_JOB_FAILED      = "job_failed"
+	ALERT_WORKER_STALE    = "worker_stale"
+	ALERT_SCHEDULE_MISSED = "schedule_missed"
+)
+
+// ALERT_KINDS are the kinds of alerts a task can opt in to.
+var ALERT_KINDS = []string{ALERT_JOB_FAILED, ALERT_WORKER_STALE, ALERT_SCHEDULE_MISSED}
+
+// Alert is an alert about a failed job, a stale worker or a missed schedule run, it is sent by email and to Slack.
+// The task keys are the opted in tasks the alert is about. The URL links the view of the job, worker or schedules if the base URL is set.
+type Alert struct {
+	Kind      string    `json:"kind"`
+	TaskKeys  []string  `json:"task_keys"`
+	Title     string    `json:"title"`
+	Message   string    `json:"message"`
+	URL       string    `json:"url,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
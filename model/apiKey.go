@@ -0,0 +1,74 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scopes of the API keys, read-only keys act as viewer, read-write keys as operator and admin keys as admin.
+const (
+	API_KEY_SCOPE_READ  = "read"
+	API_KEY_SCOPE_WRITE = "write"
+	API_KEY_SCOPE_ADMIN = "admin"
+)
+
+// USER_SOURCE_API_KEY is the source of the user of a request authenticated with an API key
+const USER_SOURCE_API_KEY = "apikey"
+
+var apiKeyScopeRoles = map[string]string{
+	API_KEY_SCOPE_READ:  ROLE_VIEWER,
+	API_KEY_SCOPE_WRITE: ROLE_OPERATOR,
+	API_KEY_SCOPE_ADMIN: ROLE_ADMIN,
+}
+
+// IsValidAPIKeyScope reports if the scope is one of the API key scopes.
+func IsValidAPIKeyScope(scope string) bool {
+	_, ok := apiKeyScopeRoles[scope]
+	return ok
+}
+
+// APIKey is a key for API clients sent as `Authorization: Bearer <key>`.
+// Only the hash of the key is stored, the prefix identifies the key in lists.
+// Revoked keys are kept to show when they were revoked.
+type APIKey struct {
+	ID         int        `json:"id"`
+	RID        uuid.UUID  `json:"rid"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scope      string     `json:"scope"`
+	CreatedBy  string     `json:"created_by"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// Role returns the role the requests with the key act as.
+func (a *APIKey) Role() string {
+	return apiKeyScopeRoles[a.Scope]
+}
+
+// User returns the user of the requests authenticated with the key.
+func (a *APIKey) User() *User {
+	return &User{
+		RID:      a.RID,
+		Username: "apikey:" + a.Name,
+		Name:     a.Name,
+		Role:     a.Role(),
+		Source:   USER_SOURCE_API_KEY,
+		Active:   a.RevokedAt == nil,
+	}
+}
+
+// APIKeyRequest is the body to create an API key.
+type APIKeyRequest struct {
+	Name  string `json:"name" form:"name"`
+	Scope string `json:"scope" form:"scope"`
+}
+
+// APIKeyCreated is a created or rotated API key with the key, the key is only returned once.
+type APIKeyCreated struct {
+	*APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Batch groups many jobs of a task that were submitted together.
+type Batch struct {
+	ID        int         `json:"id"`
+	RID       uuid.UUID   `json:"rid"`
+	Name      string      `json:"name"`
+	TaskKey   string      `json:"task_key"`
+	JobRIDs   []uuid.UUID `json:"job_rids"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// BatchProgress is the aggregated status of all jobs of a batch.
+// Jobs that can not be found anymore (eg. deleted from the archive) are counted as missing.
+type BatchProgress struct {
+	Total        int            `json:"total"`
+	Ended        int            `json:"ended"`
+	Missing      int            `json:"missing"`
+	StatusCounts map[string]int `json:"status_counts"`
+	PercentEnded int            `json:"percent_ended"`
+}
+
+// BatchJobResult is the exported result of a single job of a batch.
+type BatchJobResult struct {
+	JobRID  uuid.UUID `json:"job_rid"`
+	Status  string    `json:"status"`
+	Results any       `json:"results"`
+	Error   any       `json:"error"`
+}
@@ -0,0 +1,9 @@
+package model
+
+// BuildInfo is the version, git commit and build time of the running manager.
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
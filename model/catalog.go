@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// CATALOG_SNAPSHOT_PREFIX is the prefix of the names of the catalog snapshot files in the filesystem
+const CATALOG_SNAPSHOT_PREFIX = "catalog-snapshot-"
+
+// Kinds of the entries of a catalog snapshot
+const (
+	CATALOG_KIND_TASK         = "task"
+	CATALOG_KIND_SCHEDULE     = "schedule"
+	CATALOG_KIND_FEATURE_FLAG = "feature_flag"
+)
+
+// Changes of a catalog entry since a snapshot
+const (
+	CATALOG_CHANGE_ADDED   = "added"
+	CATALOG_CHANGE_REMOVED = "removed"
+	CATALOG_CHANGE_CHANGED = "changed"
+)
+
+// CatalogSnapshot is the task catalog and settings of a deployment at a point in time.
+// Tasks are in the format of the task export and schedules are keyed by name, both without IDs and timestamps.
+type CatalogSnapshot struct {
+	CreatedAt    time.Time                 `json:"created_at"`
+	CreatedBy    string                    `json:"created_by"`
+	Tasks        map[string]map[string]any `json:"tasks"`
+	Schedules    map[string]map[string]any `json:"schedules"`
+	FeatureFlags map[string]bool           `json:"feature_flags"`
+}
+
+// CatalogChange is a difference between a catalog snapshot and the current catalog.
+// Field is empty if the whole entry was added or removed, Before and After are the JSON of the values.
+type CatalogChange struct {
+	Kind   string `json:"kind"`
+	Key    string `json:"key"`
+	Field  string `json:"field,omitempty"`
+	Change string `json:"change"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
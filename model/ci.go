@@ -0,0 +1,11 @@
+package model
+
+import "github.com/siherrmann/queuer/model"
+
+// CIJobRun is the response of a job added by a CI pipeline.
+// Artifacts maps the uploaded file names to the names they are stored with in the file subsystem.
+type CIJobRun struct {
+	Job          *model.Job        `json:"job"`
+	Artifacts    map[string]string `json:"artifacts"`
+	Deduplicated bool              `json:"deduplicated,omitempty"`
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"time"
+
+	qm "github.com/siherrmann/queuer/model"
+)
+
+// MANAGER_WORKER_NAME is the name of the queuer worker of every manager instance, the instances are identified by its RID.
+const MANAGER_WORKER_NAME = "manager-server"
+
+// Leases of the background work of the manager, only the instance holding a lease runs its work.
+const (
+	LEASE_SCHEDULER    = "scheduler"
+	LEASE_HOUSEKEEPING = "housekeeping"
+)
+
+// LEASES are the leases the manager instances compete for.
+var LEASES = []string{LEASE_SCHEDULER, LEASE_HOUSEKEEPING}
+
+// LeaseOfHousekeepingTask returns the lease of the instance adding the jobs of the housekeeping task.
+func LeaseOfHousekeepingTask(taskKey string) string {
+	if taskKey == HOUSEKEEPING_TASK_RUN_SCHEDULES {
+		return LEASE_SCHEDULER
+	}
+	return LEASE_HOUSEKEEPING
+}
+
+// ManagerLease is held by one manager instance until it expires, the holder renews it before.
+// After a manual failover the previous holder can not acquire the lease again until FailoverUntil,
+// so another instance takes it over.
+type ManagerLease struct {
+	Name          string     `json:"name"`
+	Holder        string     `json:"holder"`
+	AcquiredAt    time.Time  `json:"acquired_at"`
+	RenewedAt     time.Time  `json:"renewed_at"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	FailoverFrom  string     `json:"failover_from,omitempty"`
+	FailoverUntil *time.Time `json:"failover_until,omitempty"`
+}
+
+// IsHeld reports whether the lease is held by an instance at the time.
+func (l *ManagerLease) IsHeld(now time.Time) bool {
+	return l.Holder != "" && l.ExpiresAt.After(now)
+}
+
+// CoordinationStatus shows which manager instance holds the master lock of the queuer and the leases of the manager.
+// Instance is the instance that answered the request, Master is nil if the master check is not enabled.
+type CoordinationStatus struct {
+	Instance  string          `json:"instance"`
+	Master    *qm.Master      `json:"master,omitempty"`
+	Leases    []*ManagerLease `json:"leases"`
+	Instances []*qm.Worker    `json:"instances"`
+}
@@ -0,0 +1,182 @@
+package model
+
+import "time"
+
+// DashboardWindow is a selectable time window of the dashboard.
+type DashboardWindow struct {
+	Name     string
+	Duration time.Duration
+}
+
+// DASHBOARD_WINDOWS are the selectable time windows of the dashboard.
+var DASHBOARD_WINDOWS = []DashboardWindow{
+	{Name: "1h", Duration: time.Hour},
+	{Name: "6h", Duration: 6 * time.Hour},
+	{Name: "24h", Duration: 24 * time.Hour},
+	{Name: "7d", Duration: 7 * 24 * time.Hour},
+	{Name: "30d", Duration: 30 * 24 * time.Hour},
+}
+
+// DASHBOARD_DEFAULT_WINDOW is the time window of the dashboard without a selected window.
+const DASHBOARD_DEFAULT_WINDOW = "24h"
+
+// The panels of the dashboard, they are loaded concurrently and a failed panel is shown with its error.
+const (
+	DASHBOARD_PANEL_JOBS_PER_STATUS = "jobs_per_status"
+	DASHBOARD_PANEL_THROUGHPUT      = "throughput"
+	DASHBOARD_PANEL_TASKS           = "tasks"
+	DASHBOARD_PANEL_ACTIVE_WORKERS  = "active_workers"
+	DASHBOARD_PANEL_HEATMAP         = "heatmap"
+)
+
+// DASHBOARD_STATS_PANELS are the panels of the dashboard stats.
+var DASHBOARD_STATS_PANELS = []string{
+	DASHBOARD_PANEL_JOBS_PER_STATUS,
+	DASHBOARD_PANEL_THROUGHPUT,
+	DASHBOARD_PANEL_TASKS,
+	DASHBOARD_PANEL_ACTIVE_WORKERS,
+}
+
+// StatusCount is the number of jobs with a status.
+type StatusCount struct {
+	Status string `json:"status"`
+	Count  int    `json:"count"`
+}
+
+// TaskStats are the counts and the average duration of the jobs of a task that ended in a time window.
+type TaskStats struct {
+	TaskName           string  `json:"task_name"`
+	Succeeded          int     `json:"succeeded"`
+	Failed             int     `json:"failed"`
+	Cancelled          int     `json:"cancelled"`
+	AvgDurationSeconds float64 `json:"avg_duration_seconds"`
+}
+
+// Ended returns the number of jobs of the task that ended in the window.
+func (s *TaskStats) Ended() int {
+	return s.Succeeded + s.Failed + s.Cancelled
+}
+
+// FailureRate returns the share of failed jobs of all jobs of the task that ended in the window.
+func (s *TaskStats) FailureRate() float64 {
+	if s.Ended() == 0 {
+		return 0
+	}
+	return float64(s.Failed) / float64(s.Ended())
+}
+
+// DashboardStats are the aggregate job statistics of a time window.
+// JobsPerStatus counts the active jobs by their current status and the jobs that ended in the window by their final status.
+// Throughput holds the ended jobs per bucket of BucketSeconds, hourly for windows up to two days and daily above.
+// Errors holds the errors of the panels that failed or timed out by panel, their fields are empty.
+type DashboardStats struct {
+	Window        string            `json:"window"`
+	Since         time.Time         `json:"since"`
+	Until         time.Time         `json:"until"`
+	BucketSeconds int64             `json:"bucket_seconds"`
+	JobsPerStatus []*StatusCount    `json:"jobs_per_status"`
+	Throughput    []*JobStats       `json:"throughput"`
+	Tasks         []*TaskStats      `json:"tasks"`
+	ActiveWorkers int               `json:"active_workers"`
+	Errors        map[string]string `json:"errors,omitempty"`
+}
+
+// Ended returns the number of jobs that ended in the window.
+func (s *DashboardStats) Ended() int {
+	ended := 0
+	for _, task := range s.Tasks {
+		ended += task.Ended()
+	}
+	return ended
+}
+
+// FailureRate returns the share of failed jobs of all jobs that ended in the window.
+func (s *DashboardStats) FailureRate() float64 {
+	ended, failed := 0, 0
+	for _, task := range s.Tasks {
+		ended += task.Ended()
+		failed += task.Failed
+	}
+	if ended == 0 {
+		return 0
+	}
+	return float64(failed) / float64(ended)
+}
+
+// AvgDurationSeconds returns the average duration of the jobs that ended in the window, weighted by the ended jobs per task.
+func (s *DashboardStats) AvgDurationSeconds() float64 {
+	ended, total := 0, 0.0
+	for _, task := range s.Tasks {
+		ended += task.Ended()
+		total += task.AvgDurationSeconds * float64(task.Ended())
+	}
+	if ended == 0 {
+		return 0
+	}
+	return total / float64(ended)
+}
+
+// PUBLIC_DASHBOARD_WINDOW is the time window of the success rate of the public dashboard.
+const PUBLIC_DASHBOARD_WINDOW = 24 * time.Hour
+
+// PUBLIC_DASHBOARD_RECENT_JOBS is the number of recently ended jobs shown on the public dashboard.
+const PUBLIC_DASHBOARD_RECENT_JOBS = 10
+
+// PublicJob is a recently ended job on the public dashboard, without its RID, parameters, results and error.
+type PublicJob struct {
+	TaskName   string    `json:"task_name"`
+	Status     string    `json:"status"`
+	StatusName string    `json:"status_name"`
+	EndedAt    time.Time `json:"ended_at"`
+	Duration   string    `json:"duration"`
+}
+
+// PublicDashboard is the limited read-only status of the queue shown without login, eg. on office monitors.
+// QueueDepth counts the active jobs waiting to run, the success rate is the share of succeeded jobs
+// of all jobs that ended in the last PUBLIC_DASHBOARD_WINDOW.
+type PublicDashboard struct {
+	QueueDepth    int          `json:"queue_depth"`
+	Running       int          `json:"running"`
+	Succeeded     int          `json:"succeeded"`
+	Failed        int          `json:"failed"`
+	Cancelled     int          `json:"cancelled"`
+	SuccessRate   float64      `json:"success_rate"`
+	ActiveWorkers int          `json:"active_workers"`
+	RecentJobs    []*PublicJob `json:"recent_jobs"`
+	CheckedAt     time.Time    `json:"checked_at"`
+}
+
+// Ended returns the number of jobs that ended in the window of the public dashboard.
+func (d *PublicDashboard) Ended() int {
+	return d.Succeeded + d.Failed + d.Cancelled
+}
+
+// HEATMAP_DEFAULT_WINDOW is the time window of the job heatmap without a selected window.
+const HEATMAP_DEFAULT_WINDOW = "30d"
+
+// HeatmapCell counts the jobs that ended in an hour of a weekday, the weekday is 0 for Sunday like time.Weekday.
+type HeatmapCell struct {
+	Weekday int `json:"weekday"`
+	Hour    int `json:"hour"`
+	Total   int `json:"total"`
+	Failed  int `json:"failed"`
+}
+
+// FailureRate returns the share of failed jobs of all jobs that ended in the hour of the weekday.
+func (c *HeatmapCell) FailureRate() float64 {
+	if c.Total == 0 {
+		return 0
+	}
+	return float64(c.Failed) / float64(c.Total)
+}
+
+// JobHeatmap is the job volume and failure rate by hour of day and day of week of a time window in a timezone.
+// Cells has a cell for every hour of every weekday, ordered by weekday starting on Monday and by hour.
+type JobHeatmap struct {
+	Window   string         `json:"window"`
+	Timezone string         `json:"timezone"`
+	Since    time.Time      `json:"since"`
+	Until    time.Time      `json:"until"`
+	Cells    []*HeatmapCell `json:"cells"`
+	MaxTotal int            `json:"max_total"`
+}
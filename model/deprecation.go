@@ -0,0 +1,56 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Deprecation marks an API route as deprecated, Route is the method and the registered path, eg. POST /api/job/addJob/:taskKey.
+// Responses of the route get the Deprecation header, the Sunset header if the route has a sunset and a Link to the successor.
+// After the sunset the route responds with 410 Gone.
+type Deprecation struct {
+	Route     string     `json:"route"`
+	Since     time.Time  `json:"since"`
+	Sunset    *time.Time `json:"sunset,omitempty"`
+	Successor string     `json:"successor,omitempty"`
+	Message   string     `json:"message,omitempty"`
+}
+
+// Validate checks that the route has a method and a path and the sunset is not before the deprecation.
+func (d *Deprecation) Validate() error {
+	method, path, found := strings.Cut(d.Route, " ")
+	if !found || method == "" || method != strings.ToUpper(method) || !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("route %q must be a method and a path, eg. POST /api/job/addJob/:taskKey", d.Route)
+	}
+	if d.Since.IsZero() {
+		return fmt.Errorf("route %s needs the time it was deprecated since", d.Route)
+	}
+	if d.Sunset != nil && d.Sunset.Before(d.Since) {
+		return fmt.Errorf("sunset of route %s is before its deprecation", d.Route)
+	}
+	return nil
+}
+
+// IsSunset reports whether the sunset of the route passed at the time.
+func (d *Deprecation) IsSunset(now time.Time) bool {
+	return d.Sunset != nil && !now.Before(*d.Sunset)
+}
+
+// DeprecatedRouteUsage counts the calls of a deprecated route by a caller, the user, API key or IP of the requests.
+// UserAgent is the user agent of the last call, eg. to find the script of an API key.
+type DeprecatedRouteUsage struct {
+	Route         string    `json:"route"`
+	Caller        string    `json:"caller"`
+	UserAgent     string    `json:"user_agent"`
+	Calls         int64     `json:"calls"`
+	FirstCalledAt time.Time `json:"first_called_at"`
+	LastCalledAt  time.Time `json:"last_called_at"`
+}
+
+// DeprecationReport is a deprecated route with the callers that still call it, the most recent caller first.
+type DeprecationReport struct {
+	Deprecation *Deprecation            `json:"deprecation"`
+	Calls       int64                   `json:"calls"`
+	Callers     []*DeprecatedRouteUsage `json:"callers"`
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EVENT_JOB_ADDED                 = "job.added"
+	EVENT_JOB_CANCELLED             = "job.cancelled"
+	EVENT_JOB_KILLED                = "job.killed"
+	EVENT_JOB_STATUS_OVERRIDDEN     = "job.status_overridden"
+	EVENT_JOB_DELETED               = "job.deleted"
+	EVENT_JOB_READDED               = "job.readded"
+	EVENT_JOB_ENDED                 = "job.ended"
+	EVENT_BATCH_ADDED               = "batch.added"
+	EVENT_TASK_ADDED                = "task.added"
+	EVENT_TASK_UPDATED              = "task.updated"
+	EVENT_TASK_DELETED              = "task.deleted"
+	EVENT_WORKER_REGISTERED         = "worker.registered"
+	EVENT_WORKER_STOPPED            = "worker.stopped"
+	EVENT_WORKER_STOPPED_GRACEFULLY = "worker.stopped_gracefully"
+	EVENT_WORKER_SCALE_REQUESTED    = "worker.scale_requested"
+)
+
+// Event is a lifecycle event of a job, worker or task that is published to downstream systems.
+type Event struct {
+	ID   uuid.UUID `json:"id"`
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	Data any       `json:"data"`
+}
+
+// NewEvent creates an event of the given type with a new ID.
+func NewEvent(eventType string, data any) *Event {
+	return &Event{
+		ID:   uuid.New(),
+		Type: eventType,
+		Time: time.Now(),
+		Data: data,
+	}
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// Keys of the feature flags of experimental features.
+const (
+	FEATURE_FLAG_NEW_DASHBOARD = "new_dashboard"
+	FEATURE_FLAG_SSE_UPDATES   = "sse_updates"
+)
+
+// DefaultFeatureFlags are the feature flags of the manager, they are created disabled if they do not exist yet.
+var DefaultFeatureFlags = []*FeatureFlag{
+	{Key: FEATURE_FLAG_NEW_DASHBOARD, Description: "New dashboard on the start page"},
+	{Key: FEATURE_FLAG_SSE_UPDATES, Description: "Live updates of jobs and workers with server-sent events instead of polling"},
+}
+
+// FeatureFlag gates an experimental feature per deployment.
+type FeatureFlag struct {
+	ID          int       `json:"id"`
+	Key         string    `json:"key"`
+	Description string    `json:"description"`
+	Enabled     bool      `json:"enabled"`
+	UpdatedBy   string    `json:"updated_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+// JobRates are the job arrivals and completions within a window together with the current backlog.
+type JobRates struct {
+	Since     time.Time `json:"since"`
+	Arrived   int       `json:"arrived"`
+	Completed int       `json:"completed"`
+	Backlog   int       `json:"backlog"`
+}
+
+// QueueForecast is a simple linear forecast of the queue based on recent job rates.
+// TimeToDrainSeconds is nil if the queue does not drain at the current rates.
+type QueueForecast struct {
+	Window             time.Duration `json:"window"`
+	Backlog            int           `json:"backlog"`
+	ArrivalRate        float64       `json:"arrival_rate_per_minute"`
+	CompletionRate     float64       `json:"completion_rate_per_minute"`
+	TimeToDrainSeconds *int64        `json:"time_to_drain_seconds"`
+	ProjectedBacklog1h int           `json:"projected_backlog_1h"`
+}
+
+// NewQueueForecast calculates the forecast from the job rates measured over the window.
+func NewQueueForecast(rates *JobRates, window time.Duration) *QueueForecast {
+	forecast := &QueueForecast{
+		Window:  window,
+		Backlog: rates.Backlog,
+	}
+
+	minutes := window.Minutes()
+	if minutes <= 0 {
+		return forecast
+	}
+	forecast.ArrivalRate = float64(rates.Arrived) / minutes
+	forecast.CompletionRate = float64(rates.Completed) / minutes
+
+	netRate := forecast.CompletionRate - forecast.ArrivalRate
+	forecast.ProjectedBacklog1h = max(0, int(math.Round(float64(rates.Backlog)-netRate*60)))
+
+	if rates.Backlog == 0 {
+		drain := int64(0)
+		forecast.TimeToDrainSeconds = &drain
+	} else if netRate > 0 {
+		drain := int64(math.Ceil(float64(rates.Backlog) / netRate * 60))
+		forecast.TimeToDrainSeconds = &drain
+	}
+
+	return forecast
+}
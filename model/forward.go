@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/model"
+)
+
+// ForwardRule forwards the jobs of a task to a remote queuerManager instance, eg. to burst to another cluster.
+// The jobs are added with the CI API of the remote instance, so the token is the CI token of the remote instance.
+// RemoteTask maps the task key to the key of the task on the remote instance, it defaults to the same key.
+type ForwardRule struct {
+	Name       string `json:"name"`
+	Task       string `json:"task"`
+	RemoteURL  string `json:"remote_url"`
+	RemoteTask string `json:"remote_task"`
+	Token      string `json:"token"`
+}
+
+// ForwardedJob is the local record of a job forwarded to a remote instance.
+// Status, results and error are synced back from the remote job until it has ended.
+type ForwardedJob struct {
+	ID         int                   `json:"id"`
+	RID        uuid.UUID             `json:"rid"`
+	Rule       string                `json:"rule"`
+	TaskKey    string                `json:"task_key"`
+	RemoteURL  string                `json:"remote_url"`
+	RemoteTask string                `json:"remote_task"`
+	RemoteRID  uuid.UUID             `json:"remote_rid"`
+	Parameters model.ParametersKeyed `json:"parameters"`
+	Status     string                `json:"status"`
+	Results    model.Parameters      `json:"results"`
+	Error      string                `json:"error"`
+	CreatedAt  time.Time             `json:"created_at"`
+	UpdatedAt  time.Time             `json:"updated_at"`
+}
+
+// Ended reports if the remote job has ended, ended jobs are not synced anymore.
+func (f *ForwardedJob) Ended() bool {
+	return f.Status == model.JobStatusSucceeded || f.Status == model.JobStatusFailed || f.Status == model.JobStatusCancelled
+}
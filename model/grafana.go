@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+const (
+	GRAFANA_METRIC_JOBS_QUEUED          = "jobs_queued"
+	GRAFANA_METRIC_JOBS_SCHEDULED       = "jobs_scheduled"
+	GRAFANA_METRIC_JOBS_RUNNING         = "jobs_running"
+	GRAFANA_METRIC_WORKERS_TOTAL        = "workers_total"
+	GRAFANA_METRIC_WORKERS_READY        = "workers_ready"
+	GRAFANA_METRIC_WORKERS_RUNNING      = "workers_running"
+	GRAFANA_METRIC_JOBS_SUCCEEDED       = "jobs_succeeded"
+	GRAFANA_METRIC_JOBS_FAILED          = "jobs_failed"
+	GRAFANA_METRIC_JOBS_CANCELLED       = "jobs_cancelled"
+	GRAFANA_METRIC_JOB_FAILURE_RATE     = "job_failure_rate"
+	GRAFANA_METRIC_JOB_DURATION_SECONDS = "job_duration_avg_seconds"
+)
+
+// GrafanaMetric is a selectable metric of the Grafana JSON datasource.
+// Payloads are the label filters the metric supports.
+type GrafanaMetric struct {
+	Label    string                 `json:"label"`
+	Value    string                 `json:"value"`
+	Payloads []GrafanaMetricPayload `json:"payloads"`
+}
+
+// GrafanaMetricPayload is a label filter of a Grafana metric.
+type GrafanaMetricPayload struct {
+	Label       string `json:"label"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Placeholder string `json:"placeholder,omitempty"`
+}
+
+// GrafanaQueryRequest is the query request of the Grafana JSON datasource.
+type GrafanaQueryRequest struct {
+	Range struct {
+		From time.Time `json:"from"`
+		To   time.Time `json:"to"`
+	} `json:"range"`
+	IntervalMs int64           `json:"intervalMs"`
+	Targets    []GrafanaTarget `json:"targets"`
+}
+
+// GrafanaTarget is a single queried metric with its label filters.
+type GrafanaTarget struct {
+	RefID   string         `json:"refId"`
+	Target  string         `json:"target"`
+	Payload map[string]any `json:"payload"`
+	Hide    bool           `json:"hide"`
+}
+
+// GrafanaTimeSeries is a time series response of the Grafana JSON datasource,
+// datapoints are [value, unix milliseconds] pairs.
+type GrafanaTimeSeries struct {
+	Target     string       `json:"target"`
+	Datapoints [][2]float64 `json:"datapoints"`
+}
+
+// GrafanaPoint is a single point of a time series in the flat format of the Infinity datasource.
+type GrafanaPoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Group is a group of users provisioned with SCIM, its display name is mapped to a role.
+type Group struct {
+	ID          int         `json:"id"`
+	RID         uuid.UUID   `json:"rid"`
+	ExternalID  string      `json:"external_id"`
+	DisplayName string      `json:"display_name"`
+	Members     []uuid.UUID `json:"members"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HEALTH_HISTORY_DAYS is the number of days of the health history kept and shown on the status page.
+const HEALTH_HISTORY_DAYS = 90
+
+// HealthCheck is a persisted check of a subsystem of the manager.
+type HealthCheck struct {
+	ID        int       `json:"id"`
+	Subsystem string    `json:"subsystem"`
+	Status    string    `json:"status"`
+	Message   string    `json:"message"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// HealthDay counts the checks of a subsystem on a day (UTC) by their status.
+type HealthDay struct {
+	Day      time.Time `json:"day"`
+	Checks   int       `json:"checks"`
+	Degraded int       `json:"degraded"`
+	Down     int       `json:"down"`
+}
+
+// Status returns the worst status of the checks of the day, empty if the subsystem was not checked on the day.
+func (d *HealthDay) Status() string {
+	switch {
+	case d.Checks == 0:
+		return ""
+	case d.Down > 0:
+		return STATUS_DOWN
+	case d.Degraded > 0:
+		return STATUS_DEGRADED
+	default:
+		return STATUS_OK
+	}
+}
+
+// Uptime returns the share of the checks of the day the subsystem was not down, 1 without checks.
+func (d *HealthDay) Uptime() float64 {
+	if d.Checks == 0 {
+		return 1
+	}
+	return float64(d.Checks-d.Down) / float64(d.Checks)
+}
+
+// SubsystemUptime is the health history of a subsystem with a day for every day of the history, oldest first.
+// Uptime is the share of all checks of the history the subsystem was not down.
+type SubsystemUptime struct {
+	Name   string       `json:"name"`
+	Uptime float64      `json:"uptime"`
+	Days   []*HealthDay `json:"days"`
+}
+
+// Incident is an incident of a subsystem annotated by an admin, ResolvedAt is nil while it is ongoing.
+// An empty subsystem marks an incident of the whole manager.
+type Incident struct {
+	ID          int        `json:"id"`
+	RID         uuid.UUID  `json:"rid"`
+	Subsystem   string     `json:"subsystem"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	StartedAt   time.Time  `json:"started_at"`
+	ResolvedAt  *time.Time `json:"resolved_at"`
+	CreatedBy   string     `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// Ongoing reports if the incident is not resolved yet.
+func (i *Incident) Ongoing() bool {
+	return i.ResolvedAt == nil
+}
+
+// IncidentRequest is the request to annotate an incident, StartedAt defaults to now.
+type IncidentRequest struct {
+	Subsystem   string    `json:"subsystem" form:"subsystem"`
+	Title       string    `json:"title" form:"title"`
+	Description string    `json:"description" form:"description"`
+	StartedAt   time.Time `json:"started_at" form:"-"`
+}
+
+// HealthHistory is the uptime of the subsystems and the incidents of the last days.
+type HealthHistory struct {
+	Days       int                `json:"days"`
+	Subsystems []*SubsystemUptime `json:"subsystems"`
+	Incidents  []*Incident        `json:"incidents"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import "context"
+
+// TaskHookFunc is called with a task that is added, updated or imported.
+// Hooks before the save can change the task and reject the save by returning an error.
+type TaskHookFunc func(ctx context.Context, task *Task) error
+
+// LifecycleHookFunc is called when the manager started or shuts down.
+// Start hooks can stop the startup by returning an error, the errors of shutdown hooks are only logged.
+type LifecycleHookFunc func(ctx context.Context) error
+
+// JobSubmitHookFunc is called with a validated job before it is added or forwarded.
+// It can change the parameters of the submission and reject the job by returning an error.
+type JobSubmitHookFunc func(ctx context.Context, submission *JobSubmission) error
+
+// JobSubmission is a job that is about to be submitted, passed to the job submit hooks.
+type JobSubmission struct {
+	Task            *Task
+	Parameters      []any
+	ParametersKeyed map[string]any
+	SubmittedBy     string
+}
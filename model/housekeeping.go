@@ -0,0 +1,33 @@
+package model
+
+import "strings"
+
+// HOUSEKEEPING_TASK_PREFIX is the reserved prefix of the keys of the housekeeping tasks of the manager.
+const HOUSEKEEPING_TASK_PREFIX = "queuer-manager."
+
+// Keys of the housekeeping tasks, the manager runs their jobs itself.
+const (
+	HOUSEKEEPING_TASK_RECORD_METRICS     = HOUSEKEEPING_TASK_PREFIX + "record-metrics"
+	HOUSEKEEPING_TASK_RECORD_HEALTH      = HOUSEKEEPING_TASK_PREFIX + "record-health"
+	HOUSEKEEPING_TASK_RUN_SCHEDULES      = HOUSEKEEPING_TASK_PREFIX + "run-schedules"
+	HOUSEKEEPING_TASK_DELETE_ACCESS_LOGS = HOUSEKEEPING_TASK_PREFIX + "delete-access-logs"
+	HOUSEKEEPING_TASK_PRUNE_FILES        = HOUSEKEEPING_TASK_PREFIX + "prune-files"
+)
+
+// HOUSEKEEPING_INITIATOR is the initiator and owner of the housekeeping jobs and tasks.
+const HOUSEKEEPING_INITIATOR = "queuer-manager"
+
+// HousekeepingTasks are the tasks of the background work of the manager, they are created or updated on start
+// if the housekeeping runs as jobs, so the jobs can be listed, audited and retried like the jobs of other tasks.
+var HousekeepingTasks = []*Task{
+	{Key: HOUSEKEEPING_TASK_RECORD_METRICS, Name: "Record queue metrics", Description: "Records a snapshot of the queue metrics for the dashboard", Owner: HOUSEKEEPING_INITIATOR},
+	{Key: HOUSEKEEPING_TASK_RECORD_HEALTH, Name: "Record health", Description: "Records the health of the subsystems for the status page and deletes the checks older than the health history", Owner: HOUSEKEEPING_INITIATOR},
+	{Key: HOUSEKEEPING_TASK_RUN_SCHEDULES, Name: "Run schedules", Description: "Adds the jobs of the due schedules and returns the number of added jobs", Owner: HOUSEKEEPING_INITIATOR},
+	{Key: HOUSEKEEPING_TASK_DELETE_ACCESS_LOGS, Name: "Delete access logs", Description: "Deletes the access logs older than the access log retention and returns the number of deleted access logs", Owner: HOUSEKEEPING_INITIATOR},
+	{Key: HOUSEKEEPING_TASK_PRUNE_FILES, Name: "Prune orphaned files", Description: "Moves the files of deleted and expired jobs to the trash, deletes them after the trash retention and returns the report of the reclaimed space", Owner: HOUSEKEEPING_INITIATOR},
+}
+
+// IsHousekeepingTask returns if the task key has the reserved prefix of the housekeeping tasks.
+func IsHousekeepingTask(taskKey string) bool {
+	return strings.HasPrefix(taskKey, HOUSEKEEPING_TASK_PREFIX)
+}
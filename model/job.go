@@ -0,0 +1,95 @@
+package model
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	qm "github.com/siherrmann/queuer/model"
+)
+
+// JOB_ACTIVE_STATUSES are the statuses of the jobs in the queue, the job list can be filtered by them
+var JOB_ACTIVE_STATUSES = []string{qm.JobStatusQueued, qm.JobStatusScheduled, qm.JobStatusRunning, qm.JobStatusFailed}
+
+// JOB_SCHEDULED_AT_FIELD is the query parameter and form field of the time an added job is scheduled at.
+const JOB_SCHEDULED_AT_FIELD = "scheduled_at"
+
+// JOB_SCHEDULED_AT_FORM_LAYOUT is the layout of the datetime input of the job form, the time is in UTC.
+const JOB_SCHEDULED_AT_FORM_LAYOUT = "2006-01-02T15:04"
+
+// JobDryRun is the payload that would be enqueued by AddJob, returned instead of adding the job with dryRun=true.
+type JobDryRun struct {
+	TaskKey         string         `json:"task_key"`
+	Parameters      []any          `json:"parameters"`
+	ParametersKeyed map[string]any `json:"parameters_keyed"`
+	ScheduledAt     *time.Time     `json:"scheduled_at,omitempty"`
+	Warnings        []string       `json:"warnings"`
+}
+
+// JobCreateRequest is the JSON body of the job API for clients without HTMX.
+// The parameters are validated by key against the input parameters of the task,
+// the priority orders the job in the queue (between JOB_PRIORITY_MIN and JOB_PRIORITY_MAX).
+// With scheduled_at (RFC3339, in the future) the job is scheduled and runs at that time.
+type JobCreateRequest struct {
+	TaskKey     string         `json:"task_key"`
+	Parameters  map[string]any `json:"parameters"`
+	Priority    int            `json:"priority"`
+	ScheduledAt *time.Time     `json:"scheduled_at,omitempty"`
+	Test        bool           `json:"test"`
+	DryRun      bool           `json:"dry_run"`
+}
+
+// JobImportRow is the result of a row of a bulk job submission, the row counts from 1 without the CSV header.
+// JobRID is the added (or deduplicated) job, Error the reason the row was not added.
+type JobImportRow struct {
+	Row          int        `json:"row"`
+	JobRID       *uuid.UUID `json:"job_rid,omitempty"`
+	Deduplicated bool       `json:"deduplicated,omitempty"`
+	Error        string     `json:"error,omitempty"`
+}
+
+// JobImportReport is the per-row report of a bulk job submission from a CSV or JSON file.
+type JobImportReport struct {
+	TaskKey string         `json:"task_key"`
+	Added   int            `json:"added"`
+	Failed  int            `json:"failed"`
+	Rows    []JobImportRow `json:"rows"`
+}
+
+// JobFilter are the structured filters of the job list, empty fields do not filter.
+// From and To are the time range the jobs were created in, From inclusive and To exclusive.
+// Namespace matches the jobs of the tasks in the namespace.
+type JobFilter struct {
+	Search    string    `json:"search"`
+	Status    string    `json:"status"`
+	TaskKey   string    `json:"task_key"`
+	Namespace string    `json:"namespace"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+}
+
+// HasFilters reports whether any structured filter is set, the free-text search alone is no structured filter.
+func (f *JobFilter) HasFilters() bool {
+	return f.Status != "" || f.TaskKey != "" || f.Namespace != "" || !f.From.IsZero() || !f.To.IsZero()
+}
+
+// Query returns the query parameters of the search and the structured filters that are set, eg. for the links of the job list.
+func (f *JobFilter) Query() url.Values {
+	query := url.Values{}
+	if f.Search != "" {
+		query.Set("search", f.Search)
+	}
+	if f.Status != "" {
+		query.Set("status", f.Status)
+	}
+	if f.TaskKey != "" {
+		query.Set("taskKey", f.TaskKey)
+	}
+	if !f.From.IsZero() {
+		query.Set("from", f.From.Format(time.RFC3339))
+	}
+	if !f.To.IsZero() {
+		query.Set("to", f.To.Format(time.RFC3339))
+	}
+	return query
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobArchiveFilter are the structured filters of the job archive, empty fields do not filter.
+// The duration of a job is the time from its start until it ended, jobs that never started have no duration.
+// Error matches the error message of a job exactly, eg. to list the jobs of a failure group.
+// Namespace matches the jobs of the tasks in the namespace.
+type JobArchiveFilter struct {
+	Search      string        `json:"search"`
+	Status      string        `json:"status"`
+	TaskKey     string        `json:"task_key"`
+	Namespace   string        `json:"namespace"`
+	WorkerRID   uuid.UUID     `json:"worker_rid"`
+	MinDuration time.Duration `json:"min_duration"`
+	MaxDuration time.Duration `json:"max_duration"`
+	Error       string        `json:"error"`
+}
+
+// HasFilters reports whether any structured filter is set, the free-text search alone is no structured filter.
+func (f *JobArchiveFilter) HasFilters() bool {
+	return f.Status != "" || f.TaskKey != "" || f.Namespace != "" || f.WorkerRID != uuid.Nil || f.MinDuration > 0 || f.MaxDuration > 0 || f.Error != ""
+}
+
+// FailureGroup are the failed archived jobs of a task with the same error message.
+type FailureGroup struct {
+	TaskKey         string    `json:"task_key"`
+	Error           string    `json:"error"`
+	Count           int       `json:"count"`
+	FirstOccurrence time.Time `json:"first_occurrence"`
+	LastOccurrence  time.Time `json:"last_occurrence"`
+	LastJobRID      uuid.UUID `json:"last_job_rid"`
+}
+
+// JOB_ARCHIVE_PARTITION_PREFIX is the name prefix of the monthly partitions of the job archive, eg. job_archive_2026_01
+const JOB_ARCHIVE_PARTITION_PREFIX = "job_archive_"
+
+// JobArchivePartition is a partition of a partitioned job archive with its range [From, To).
+// From and To are zero for the default partition and for unbounded ranges, Rows is the estimate of the planner.
+type JobArchivePartition struct {
+	Name      string    `json:"name"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Rows      int64     `json:"rows"`
+	SizeBytes int64     `json:"size_bytes"`
+}
+
+// TableIndex is an index of a table with its columns in index order, columns of expressions are empty.
+type TableIndex struct {
+	Table   string   `json:"table"`
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+}
+
+// IndexAdvice is a common query pattern of the manager with the columns an index needs to cover it.
+// Index is the name of the existing index covering it, Statement creates the index if it is missing.
+type IndexAdvice struct {
+	Table     string   `json:"table"`
+	Columns   []string `json:"columns"`
+	Query     string   `json:"query"`
+	Index     string   `json:"index,omitempty"`
+	Statement string   `json:"statement,omitempty"`
+}
+
+// JobArchiveMaintenance is the report of the partitions of the job archive and the indexes of the common queries.
+type JobArchiveMaintenance struct {
+	Partitioned  bool                   `json:"partitioned"`
+	PartitionKey string                 `json:"partition_key,omitempty"`
+	Partitions   []*JobArchivePartition `json:"partitions"`
+	Indexes      []*IndexAdvice         `json:"indexes"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JOB_CHAIN_INITIATOR_PREFIX is the prefix of the initiator of the jobs added by a chain, followed by the upstream task key.
+const JOB_CHAIN_INITIATOR_PREFIX = "chain:"
+
+// Job chain statuses, a pending chain is claimed with status running when its job ended,
+// then the downstream job is enqueued or the chain failed. Chains of jobs that did not succeed are skipped.
+const (
+	JOB_CHAIN_STATUS_PENDING  = "pending"
+	JOB_CHAIN_STATUS_RUNNING  = "running"
+	JOB_CHAIN_STATUS_ENQUEUED = "enqueued"
+	JOB_CHAIN_STATUS_FAILED   = "failed"
+	JOB_CHAIN_STATUS_SKIPPED  = "skipped"
+)
+
+// JobChain is a downstream job registered when a job of a task with on success dependencies is added.
+// The parameter mapping is the mapping of the dependency at the time the job was added.
+type JobChain struct {
+	ID                int               `json:"id"`
+	JobRID            uuid.UUID         `json:"job_rid"`
+	TaskKey           string            `json:"task_key"`
+	DownstreamTaskKey string            `json:"downstream_task_key"`
+	ParameterMapping  map[string]string `json:"parameter_mapping"`
+	DownstreamJobRID  *uuid.UUID        `json:"downstream_job_rid,omitempty"`
+	Status            string            `json:"status"`
+	Error             string            `json:"error,omitempty"`
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+}
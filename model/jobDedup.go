@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MAX_DEDUP_WINDOW_MINUTES is the longest dedup window of a task (one week)
+const MAX_DEDUP_WINDOW_MINUTES = 7 * 24 * 60
+
+// JobDedup is the latest job added for a task with the same parameters,
+// the parameters are identified by the hash of their JSON, so encrypted jobs can be deduplicated too.
+type JobDedup struct {
+	ID             int       `json:"id"`
+	TaskKey        string    `json:"task_key"`
+	ParametersHash string    `json:"parameters_hash"`
+	JobRID         uuid.UUID `json:"job_rid"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DEFAULT_FILE_TRASH_RETENTION_DAYS is the number of days an orphaned file stays in the trash before it is deleted
+const DEFAULT_FILE_TRASH_RETENTION_DAYS = 7
+
+// JobFile associates a file passed with a file parameter to the job, with the namespace of the task of the job.
+// A file is orphaned once none of its jobs is queued, running or in the archive, eg. after the archive retention.
+type JobFile struct {
+	JobRID    uuid.UUID `json:"job_rid"`
+	Filename  string    `json:"filename"`
+	Namespace string    `json:"namespace"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TrashedFile is an orphaned file queued for deletion, it is deleted after the trash retention
+// unless a new job references it again. DeleteAfter is the end of the trash retention, it is not stored.
+type TrashedFile struct {
+	Filename    string    `json:"filename"`
+	Namespace   string    `json:"namespace"`
+	SizeBytes   int64     `json:"size_bytes"`
+	TrashedAt   time.Time `json:"trashed_at"`
+	DeleteAfter time.Time `json:"delete_after"`
+}
+
+// FilePruneReport is the result of a run of the pruning of the orphaned files.
+// Trashed are the orphaned files queued for deletion in the run, restored the trashed files referenced by a new job again
+// and purged the files deleted after the trash retention with the reclaimed space.
+// The trash and its bytes are the files queued for deletion after the run, by namespace in TrashBytesByNamespace.
+type FilePruneReport struct {
+	TrashedFiles          int              `json:"trashed_files"`
+	TrashedBytes          int64            `json:"trashed_bytes"`
+	RestoredFiles         int64            `json:"restored_files"`
+	PurgedFiles           int              `json:"purged_files"`
+	ReclaimedBytes        int64            `json:"reclaimed_bytes"`
+	TrashFiles            int              `json:"trash_files"`
+	TrashBytes            int64            `json:"trash_bytes"`
+	TrashBytesByNamespace map[string]int64 `json:"trash_bytes_by_namespace"`
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobInitiator records who added a job, eg. the username of the user, "ci" or the Slack user,
+// so jobs can be listed by their initiator and notifications can name whom to ping.
+// Test marks jobs added as a test run of the task with its sample values.
+type JobInitiator struct {
+	ID        int       `json:"id"`
+	JobRID    uuid.UUID `json:"job_rid"`
+	TaskKey   string    `json:"task_key"`
+	Initiator string    `json:"initiator"`
+	Test      bool      `json:"test"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobKill records the hard kill of a running job. Unlike a cancel, which only cancels the context of the task,
+// a hard kill also stops the worker running the job, so a task ignoring the cancellation can not keep running.
+type JobKill struct {
+	ID          int       `json:"id"`
+	JobRID      uuid.UUID `json:"job_rid"`
+	WorkerRID   uuid.UUID `json:"worker_rid"`
+	RequestedBy string    `json:"requested_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JOB_OVERRIDE_MAX_REASON is the maximum length of the reason of a job status override
+const JOB_OVERRIDE_MAX_REASON = 500
+
+// JobOverride records a manual override of the status of an active job, eg. to fail a running job
+// whose worker died without updating the queue. The job is ended with the status and moved to the archive.
+type JobOverride struct {
+	ID             int       `json:"id"`
+	JobRID         uuid.UUID `json:"job_rid"`
+	PreviousStatus string    `json:"previous_status"`
+	Status         string    `json:"status"`
+	Reason         string    `json:"reason"`
+	RequestedBy    string    `json:"requested_by"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// JobOverrideRequest is the body to override the status of a job, the reason is required.
+type JobOverrideRequest struct {
+	Status string `json:"status" form:"status"`
+	Reason string `json:"reason" form:"reason"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Range of the priority of a job, the workers claim the queued jobs with a higher priority first.
+// Jobs without priority have the default priority, jobs with the same priority are claimed in the order of the scheduler policy.
+const (
+	JOB_PRIORITY_MIN     = -100
+	JOB_PRIORITY_MAX     = 100
+	JOB_PRIORITY_DEFAULT = 0
+)
+
+// JOB_PRIORITY_FIELD is the query parameter and form field of the priority of a job added with the job form.
+const JOB_PRIORITY_FIELD = "priority"
+
+// JobPriority is the priority of a queued or scheduled job, it is deleted when the job ended.
+type JobPriority struct {
+	JobRID    uuid.UUID `json:"job_rid"`
+	Priority  int       `json:"priority"`
+	UpdatedBy string    `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// JobPriorityRequest is the body to change the priority of a queued job.
+type JobPriorityRequest struct {
+	Priority *int `json:"priority" form:"priority"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	qm "github.com/siherrmann/queuer/model"
+)
+
+// JobRetryRequest retries archived jobs with their original parameters.
+// The parameters override the original parameters of all retried jobs by parameter key.
+type JobRetryRequest struct {
+	RIDs       []uuid.UUID    `json:"rids"`
+	Parameters map[string]any `json:"parameters"`
+}
+
+// JobRetryResult is the job added for a retried archived job, or the error if it could not be retried.
+type JobRetryResult struct {
+	RID   uuid.UUID `json:"rid"`
+	Job   *qm.Job   `json:"job,omitempty"`
+	Error string    `json:"error,omitempty"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobTemplate is a named preset of the values of the input parameters of a task by parameter key.
+// The values are the values of the add job form, running the template adds a job with them.
+type JobTemplate struct {
+	ID        int               `json:"id"`
+	RID       uuid.UUID         `json:"rid"`
+	Name      string            `json:"name"`
+	TaskKey   string            `json:"task_key"`
+	Values    map[string]string `json:"values"`
+	CreatedBy string            `json:"created_by"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// JobTemplateRequest is the JSON body to save a job template of a task.
+type JobTemplateRequest struct {
+	Name   string            `json:"name"`
+	Values map[string]string `json:"values"`
+}
@@ -0,0 +1,172 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/model"
+)
+
+// Kinds of the segments of a job timeline.
+const (
+	JOB_TIMELINE_SCHEDULED = "scheduled"
+	JOB_TIMELINE_QUEUED    = "queued"
+	JOB_TIMELINE_ATTEMPT   = "attempt"
+)
+
+// JobAttempt records one run of a job by a worker, it is recorded from the job notifications.
+// Retries of a worker within one run are part of the attempt, EndedAt is nil while the attempt runs.
+type JobAttempt struct {
+	ID        int        `json:"id"`
+	JobRID    uuid.UUID  `json:"job_rid"`
+	Attempt   int        `json:"attempt"`
+	WorkerRID uuid.UUID  `json:"worker_rid"`
+	Status    string     `json:"status"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+}
+
+// JobTimelineSegment is one phase of a job, a running segment has no end and lasts until now.
+type JobTimelineSegment struct {
+	Kind            string     `json:"kind"`
+	Attempt         int        `json:"attempt,omitempty"`
+	WorkerRID       *uuid.UUID `json:"worker_rid,omitempty"`
+	Status          string     `json:"status,omitempty"`
+	StartedAt       time.Time  `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at"`
+	DurationSeconds float64    `json:"duration_seconds"`
+}
+
+// JobTimeline is the chronological timeline of a job from being queued to being finished.
+// QueueWaitSeconds sums the queued segments, RunSeconds sums the attempts.
+type JobTimeline struct {
+	JobRID           uuid.UUID            `json:"job_rid"`
+	TaskKey          string               `json:"task_key"`
+	Status           string               `json:"status"`
+	QueuedAt         time.Time            `json:"queued_at"`
+	ScheduledAt      *time.Time           `json:"scheduled_at"`
+	StartedAt        *time.Time           `json:"started_at"`
+	FinishedAt       *time.Time           `json:"finished_at"`
+	Attempts         int                  `json:"attempts"`
+	QueueWaitSeconds float64              `json:"queue_wait_seconds"`
+	RunSeconds       float64              `json:"run_seconds"`
+	TotalSeconds     float64              `json:"total_seconds"`
+	Segments         []JobTimelineSegment `json:"segments"`
+}
+
+// NewJobTimeline builds the timeline of a job from its recorded attempts ordered by attempt.
+// Without recorded attempts, eg. for jobs started before the recording, the start of the job is used as single attempt.
+// The end of an ended job is its last update, the segments of an active job last until now.
+func NewJobTimeline(job *model.Job, ended bool, attempts []*JobAttempt, now time.Time) *JobTimeline {
+	timeline := &JobTimeline{
+		JobRID:      job.RID,
+		TaskKey:     job.TaskName,
+		Status:      job.Status,
+		QueuedAt:    job.CreatedAt,
+		ScheduledAt: job.ScheduledAt,
+		Attempts:    job.Attempts,
+		Segments:    []JobTimelineSegment{},
+	}
+
+	end := now
+	if ended {
+		finishedAt := job.UpdatedAt
+		timeline.FinishedAt = &finishedAt
+		end = finishedAt
+	}
+
+	if len(attempts) == 0 && job.StartedAt != nil && !job.StartedAt.IsZero() {
+		attempts = []*JobAttempt{{
+			JobRID:    job.RID,
+			Attempt:   max(job.Attempts, 1),
+			WorkerRID: job.WorkerRID,
+			Status:    job.Status,
+			StartedAt: *job.StartedAt,
+		}}
+		if !ended && job.Status != model.JobStatusRunning {
+			updatedAt := job.UpdatedAt
+			attempts[0].EndedAt = &updatedAt
+		}
+	}
+	if len(attempts) > 0 {
+		startedAt := attempts[0].StartedAt
+		timeline.StartedAt = &startedAt
+		timeline.Attempts = max(timeline.Attempts, attempts[len(attempts)-1].Attempt)
+	}
+
+	// Waiting for the scheduled time is not part of the queue wait
+	waitFrom := job.CreatedAt
+	if job.ScheduledAt != nil && job.ScheduledAt.After(job.CreatedAt) {
+		scheduledUntil := *job.ScheduledAt
+		if timeline.StartedAt != nil && timeline.StartedAt.Before(scheduledUntil) {
+			scheduledUntil = *timeline.StartedAt
+		}
+		if end.Before(scheduledUntil) {
+			scheduledUntil = end
+		}
+		timeline.addSegment(JobTimelineSegment{Kind: JOB_TIMELINE_SCHEDULED}, job.CreatedAt, &scheduledUntil, now)
+		waitFrom = scheduledUntil
+	}
+
+	running := false
+	for i, attempt := range attempts {
+		if attempt.StartedAt.After(waitFrom) {
+			queuedUntil := attempt.StartedAt
+			timeline.addSegment(JobTimelineSegment{Kind: JOB_TIMELINE_QUEUED}, waitFrom, &queuedUntil, now)
+		}
+
+		// The last attempt of an ended job ends with the job if its end was not recorded
+		status := attempt.Status
+		attemptEnd := attempt.EndedAt
+		if attemptEnd == nil && (ended || i < len(attempts)-1) {
+			attemptEnd = &end
+			if ended && i == len(attempts)-1 {
+				status = job.Status
+			}
+		}
+		workerRID := attempt.WorkerRID
+		timeline.addSegment(JobTimelineSegment{
+			Kind:      JOB_TIMELINE_ATTEMPT,
+			Attempt:   attempt.Attempt,
+			WorkerRID: &workerRID,
+			Status:    status,
+		}, attempt.StartedAt, attemptEnd, now)
+
+		running = attemptEnd == nil
+		if attemptEnd != nil {
+			waitFrom = *attemptEnd
+		}
+	}
+
+	// An active job waits for its next attempt until now, a job ended before running was queued until its end
+	if !running && waitFrom.Before(end) {
+		if !ended {
+			timeline.addSegment(JobTimelineSegment{Kind: JOB_TIMELINE_QUEUED}, waitFrom, nil, now)
+		} else if len(attempts) == 0 {
+			timeline.addSegment(JobTimelineSegment{Kind: JOB_TIMELINE_QUEUED}, waitFrom, &end, now)
+		}
+	}
+
+	timeline.TotalSeconds = end.Sub(job.CreatedAt).Seconds()
+	return timeline
+}
+
+// addSegment adds a segment from start to end or until now and sums its duration.
+func (t *JobTimeline) addSegment(segment JobTimelineSegment, start time.Time, end *time.Time, now time.Time) {
+	segment.StartedAt = start
+	segment.EndedAt = end
+	if end != nil {
+		segment.DurationSeconds = end.Sub(start).Seconds()
+	} else {
+		segment.DurationSeconds = now.Sub(start).Seconds()
+	}
+	segment.DurationSeconds = max(segment.DurationSeconds, 0)
+
+	switch segment.Kind {
+	case JOB_TIMELINE_QUEUED:
+		t.QueueWaitSeconds += segment.DurationSeconds
+	case JOB_TIMELINE_ATTEMPT:
+		t.RunSeconds += segment.DurationSeconds
+	}
+	t.Segments = append(t.Segments, segment)
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	vm "github.com/siherrmann/validator/model"
+)
+
+// LOAD_TEST_TASK_KEY is the task key the task function of the load test is registered with and all synthetic load test jobs are added with.
+const LOAD_TEST_TASK_KEY = "load-test"
+
+// LoadTestTask is the task of the synthetic load test jobs, its parameters are the parameters of the task function.
+var LoadTestTask = &Task{
+	Key:         LOAD_TEST_TASK_KEY,
+	Name:        "Load Test",
+	Description: "Synthetic task executing the jobs generated by the load test mode",
+	InputParameters: []vm.Validation{
+		{Key: "duration_ms", Type: vm.Int, Requirement: "min0"},
+		{Key: "fail", Type: vm.Bool, Requirement: "-"},
+	},
+}
+
+// LoadTestResult summarizes a synthetic load test generation run.
+type LoadTestResult struct {
+	RunID        string        `json:"run_id"`
+	TasksCreated int           `json:"tasks_created"`
+	JobsCreated  int           `json:"jobs_created"`
+	JobsFailing  int           `json:"jobs_failing"`
+	Duration     time.Duration `json:"duration"`
+}
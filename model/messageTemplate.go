@@ -0,0 +1,85 @@
+package model
+
+import "time"
+
+// Keys of the message templates of the notifications.
+const (
+	MESSAGE_TEMPLATE_WATCH_EMAIL = "watch_email"
+	MESSAGE_TEMPLATE_ALERT_EMAIL = "alert_email"
+	MESSAGE_TEMPLATE_JOB_WEBHOOK = "job_webhook"
+)
+
+// DefaultMessageTemplates are the templates of the notifications if they are not edited, they are not stored.
+// The templates are Go text templates, the data is the watch notification, the alert or the job notification.
+var DefaultMessageTemplates = []*MessageTemplate{
+	{
+		Key:         MESSAGE_TEMPLATE_WATCH_EMAIL,
+		Description: "Email of the notifications of watched jobs and tasks, the data is the watch notification",
+		Subject:     "Job {{ .StatusName }}: {{ .TaskName }}",
+		Body:        "{{ .Message }}{{ if .Error }}\n\nError: {{ .Error }}{{ end }}\n\n{{ .URL }}",
+	},
+	{
+		Key:         MESSAGE_TEMPLATE_ALERT_EMAIL,
+		Description: "Email of the alerts of failed jobs, stale workers and missed schedule runs, the data is the alert",
+		Subject:     "{{ .Title }}",
+		Body:        "{{ .Message }}{{ if .URL }}\n\n{{ .URL }}{{ end }}",
+	},
+	{
+		Key:         MESSAGE_TEMPLATE_JOB_WEBHOOK,
+		Description: "JSON payload of the notification rules with the webhook format, the data is the job notification",
+		Body:        "{{ json . }}",
+	},
+}
+
+// DefaultMessageTemplate returns the default template with the key, nil if there is none.
+func DefaultMessageTemplate(key string) *MessageTemplate {
+	for _, messageTemplate := range DefaultMessageTemplates {
+		if messageTemplate.Key == key {
+			copied := *messageTemplate
+			return &copied
+		}
+	}
+	return nil
+}
+
+// MessageTemplate is an edited template of a notification with the key of a default template.
+// Webhook templates have no subject and have to render valid JSON.
+type MessageTemplate struct {
+	ID          int       `json:"id"`
+	Key         string    `json:"key"`
+	Description string    `json:"description"`
+	Subject     string    `json:"subject"`
+	Body        string    `json:"body"`
+	UpdatedBy   string    `json:"updated_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// IsWebhook reports if the template renders the JSON payload of a webhook instead of an email.
+func (t *MessageTemplate) IsWebhook() bool {
+	return t.Key == MESSAGE_TEMPLATE_JOB_WEBHOOK
+}
+
+// IsEdited reports if the template is stored instead of the default template.
+func (t *MessageTemplate) IsEdited() bool {
+	return t.ID > 0
+}
+
+// MessageTemplateRequest is the subject and body of an edited template, or of a preview or test send.
+type MessageTemplateRequest struct {
+	Subject string `json:"subject" form:"subject"`
+	Body    string `json:"body" form:"body"`
+	To      string `json:"to" form:"to"`
+}
+
+// RenderedMessage is a template rendered with the data of a notification.
+type RenderedMessage struct {
+	Subject string `json:"subject,omitempty"`
+	Body    string `json:"body"`
+}
+
+// Branding replaces the title and the logo of the views and the sender name of the emails for customer-facing deployments.
+type Branding struct {
+	Title   string `json:"title"`
+	LogoURL string `json:"logo_url,omitempty"`
+}
@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+const (
+	METRIC_RESOLUTION_RAW  = "raw"
+	METRIC_RESOLUTION_HOUR = "hour"
+)
+
+// MetricMinInterval returns the minimum interval between the metric snapshots recorded every interval,
+// a tenth of the interval is the tolerance for the tickers of the manager instances.
+func MetricMinInterval(interval time.Duration) time.Duration {
+	return interval - interval/10
+}
+
+// QueueMetric is a snapshot of the queue state at a point in time.
+// For aggregated resolutions the counts are the rounded averages of the bucket.
+type QueueMetric struct {
+	Time           time.Time `json:"time"`
+	JobsQueued     int       `json:"jobs_queued"`
+	JobsScheduled  int       `json:"jobs_scheduled"`
+	JobsRunning    int       `json:"jobs_running"`
+	WorkersTotal   int       `json:"workers_total"`
+	WorkersReady   int       `json:"workers_ready"`
+	WorkersRunning int       `json:"workers_running"`
+}
+
+// JobStats are the counts and the average duration of the jobs that ended in a time bucket.
+type JobStats struct {
+	Time               time.Time `json:"time"`
+	Succeeded          int       `json:"succeeded"`
+	Failed             int       `json:"failed"`
+	Cancelled          int       `json:"cancelled"`
+	AvgDurationSeconds float64   `json:"avg_duration_seconds"`
+}
+
+// FailureRate returns the share of failed jobs of all jobs that ended in the bucket.
+func (s *JobStats) FailureRate() float64 {
+	ended := s.Succeeded + s.Failed + s.Cancelled
+	if ended == 0 {
+		return 0
+	}
+	return float64(s.Failed) / float64(ended)
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/model"
+)
+
+// Formats of the notification rules
+const (
+	NOTIFICATION_FORMAT_WEBHOOK = "webhook"
+	NOTIFICATION_FORMAT_TEAMS   = "teams"
+	NOTIFICATION_FORMAT_DISCORD = "discord"
+)
+
+// NotificationRule sends notifications of ended jobs to a webhook in the format of the rule.
+// Without tasks the rule matches all tasks, without statuses it matches failed jobs.
+type NotificationRule struct {
+	Name     string   `json:"name"`
+	Format   string   `json:"format"`
+	URL      string   `json:"url"`
+	Tasks    []string `json:"tasks"`
+	Statuses []string `json:"statuses"`
+}
+
+// Matches reports if the job has one of the tasks and statuses of the rule.
+func (r *NotificationRule) Matches(job *model.Job) bool {
+	if len(r.Tasks) > 0 && !slices.Contains(r.Tasks, job.TaskName) {
+		return false
+	}
+
+	statuses := r.Statuses
+	if len(statuses) == 0 {
+		statuses = []string{model.JobStatusFailed}
+	}
+	return slices.ContainsFunc(statuses, func(status string) bool {
+		return strings.EqualFold(status, job.Status)
+	})
+}
+
+// JobNotification is the notification of an ended job, it is sent as JSON with the webhook format.
+// The owner of the task and the initiator of the job tell on-call whom to ping.
+type JobNotification struct {
+	Rule       string     `json:"rule"`
+	JobRID     uuid.UUID  `json:"job_rid"`
+	TaskName   string     `json:"task_name"`
+	Status     string     `json:"status"`
+	StatusName string     `json:"status_name"`
+	Error      string     `json:"error,omitempty"`
+	Attempts   int        `json:"attempts"`
+	StartedAt  *time.Time `json:"started_at"`
+	EndedAt    time.Time  `json:"ended_at"`
+	Duration   string     `json:"duration"`
+	Owner      string     `json:"owner,omitempty"`
+	Initiator  string     `json:"initiator,omitempty"`
+	URL        string     `json:"url,omitempty"`
+}
+
+// NewJobNotification creates the notification of the job, the URL links the job view if the base URL is set.
+func NewJobNotification(rule string, job *model.Job, baseURL string) *JobNotification {
+	notification := &JobNotification{
+		Rule:       rule,
+		JobRID:     job.RID,
+		TaskName:   job.TaskName,
+		Status:     job.Status,
+		StatusName: StatusName(job.Status),
+		Error:      job.Error,
+		Attempts:   job.Attempts,
+		StartedAt:  job.StartedAt,
+		EndedAt:    job.UpdatedAt,
+	}
+	if job.StartedAt != nil {
+		notification.Duration = job.UpdatedAt.Sub(*job.StartedAt).Round(time.Millisecond).String()
+	}
+	if baseURL != "" {
+		notification.URL = strings.TrimRight(baseURL, "/") + "/job?rid=" + job.RID.String()
+	}
+	return notification
+}
@@ -0,0 +1,72 @@
+package model
+
+// Metrics of the Prometheus endpoint, the alert rules and dashboard of the observability bundle use the same names.
+const (
+	PROMETHEUS_METRIC_BUILD_INFO                = "queuer_manager_build_info"
+	PROMETHEUS_METRIC_SUBSYSTEM_UP              = "queuer_manager_subsystem_up"
+	PROMETHEUS_METRIC_JOBS_QUEUED               = "queuer_manager_jobs_queued"
+	PROMETHEUS_METRIC_JOBS_SCHEDULED            = "queuer_manager_jobs_scheduled"
+	PROMETHEUS_METRIC_JOBS_RUNNING              = "queuer_manager_jobs_running"
+	PROMETHEUS_METRIC_WORKERS_TOTAL             = "queuer_manager_workers_total"
+	PROMETHEUS_METRIC_WORKERS_READY             = "queuer_manager_workers_ready"
+	PROMETHEUS_METRIC_WORKERS_RUNNING           = "queuer_manager_workers_running"
+	PROMETHEUS_METRIC_RECENT_JOBS_ENDED         = "queuer_manager_recent_jobs_ended"
+	PROMETHEUS_METRIC_RECENT_JOB_FAILURE_RATE   = "queuer_manager_recent_job_failure_rate"
+	PROMETHEUS_METRIC_RECENT_JOB_DURATION_AVG_S = "queuer_manager_recent_job_duration_avg_seconds"
+	PROMETHEUS_METRIC_JOBS_ENDED_TOTAL          = "queuer_manager_jobs_ended_total"
+	PROMETHEUS_METRIC_JOB_DURATION_SECONDS      = "queuer_manager_job_duration_seconds"
+)
+
+// Labels the job KPIs can be broken down by, the owner is the owner of the task,
+// the tenant and cost label are the ones of the cost model of the task.
+const (
+	KPI_LABEL_TASK       = "task"
+	KPI_LABEL_OWNER      = "owner"
+	KPI_LABEL_INITIATOR  = "initiator"
+	KPI_LABEL_TENANT     = "tenant"
+	KPI_LABEL_COST_LABEL = "cost_label"
+)
+
+// KPI_LABELS are all labels the job KPIs can be broken down by.
+var KPI_LABELS = []string{KPI_LABEL_TASK, KPI_LABEL_OWNER, KPI_LABEL_INITIATOR, KPI_LABEL_TENANT, KPI_LABEL_COST_LABEL}
+
+// KPI_LABEL_VALUE_OTHER is the value of all labels of the jobs exceeding the max series of the job KPIs.
+const KPI_LABEL_VALUE_OTHER = "other"
+
+// ObservabilityMetric is a metric of the Prometheus endpoint with its help text and labels.
+type ObservabilityMetric struct {
+	Name   string   `json:"name"`
+	Help   string   `json:"help"`
+	Type   string   `json:"type"`
+	Unit   string   `json:"unit"`
+	Labels []string `json:"labels"`
+}
+
+// ObservabilityBundle are the monitoring definitions generated for the metrics of the manager.
+// The Prometheus rules are a rule file (JSON is valid YAML), the Grafana dashboard can be imported as is.
+type ObservabilityBundle struct {
+	MetricsPath      string                `json:"metrics_path"`
+	Metrics          []ObservabilityMetric `json:"metrics"`
+	PrometheusRules  PrometheusRuleFile    `json:"prometheus_rules"`
+	GrafanaDashboard map[string]any        `json:"grafana_dashboard"`
+}
+
+// PrometheusRuleFile is a Prometheus rule file with its rule groups.
+type PrometheusRuleFile struct {
+	Groups []PrometheusRuleGroup `json:"groups"`
+}
+
+// PrometheusRuleGroup is a group of Prometheus alert rules.
+type PrometheusRuleGroup struct {
+	Name  string           `json:"name"`
+	Rules []PrometheusRule `json:"rules"`
+}
+
+// PrometheusRule is a Prometheus alert rule.
+type PrometheusRule struct {
+	Alert       string            `json:"alert"`
+	Expr        string            `json:"expr"`
+	For         string            `json:"for"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+}
@@ -0,0 +1,87 @@
+package model
+
+// OpenAPIDocument is an OpenAPI 3.0 document describing the API routes of the manager.
+type OpenAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Servers    []OpenAPIServer                         `json:"servers"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths"`
+	Components OpenAPIComponents                       `json:"components"`
+	Security   []map[string][]string                   `json:"security"`
+}
+
+// OpenAPIInfo is the title and version of the API.
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// OpenAPIServer is the URL the API is served at, relative to the document.
+type OpenAPIServer struct {
+	URL string `json:"url"`
+}
+
+// OpenAPIOperation is an operation of a path with its method.
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Summary     string                      `json:"summary"`
+	Description string                      `json:"description,omitempty"`
+	Tags        []string                    `json:"tags"`
+	Parameters  []*OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+	Deprecated  bool                        `json:"deprecated,omitempty"`
+}
+
+// OpenAPIParameter is a path or query parameter of an operation.
+type OpenAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required"`
+	Schema      *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPIRequestBody is the body of an operation by content type.
+type OpenAPIRequestBody struct {
+	Required bool                         `json:"required"`
+	Content  map[string]*OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse is a response of an operation by content type.
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIMediaType is the schema of a body of a content type.
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPISchema is the schema of a value, Ref references a schema of the components.
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+// OpenAPIComponents are the schemas of the models and the security schemes referenced by the operations.
+type OpenAPIComponents struct {
+	Schemas         map[string]*OpenAPISchema         `json:"schemas"`
+	SecuritySchemes map[string]*OpenAPISecurityScheme `json:"securitySchemes"`
+}
+
+// OpenAPISecurityScheme is a way to authenticate the requests, eg. with an API key as bearer token.
+type OpenAPISecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme,omitempty"`
+	In          string `json:"in,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
@@ -0,0 +1,25 @@
+package model
+
+// Default page sizes and hard cap of the paginated lists.
+const (
+	DEFAULT_API_PAGE_SIZE  = 10
+	DEFAULT_VIEW_PAGE_SIZE = 100
+	DEFAULT_MAX_PAGE_SIZE  = 100
+)
+
+// PaginationConfig holds the page sizes of the paginated lists of a deployment.
+// API endpoints return APIPageSize and views ViewPageSize entries without a limit, no request gets more than MaxPageSize entries.
+type PaginationConfig struct {
+	APIPageSize  int `json:"api_page_size"`
+	ViewPageSize int `json:"view_page_size"`
+	MaxPageSize  int `json:"max_page_size"`
+}
+
+// Page is the envelope of a page of a paginated list, returned by the list API endpoints with envelope=true.
+// LastID is the lastId to request the next page with, HasMore is false on the last page.
+type Page[T any] struct {
+	Items   []T  `json:"items"`
+	Total   int  `json:"total"`
+	LastID  int  `json:"lastId"`
+	HasMore bool `json:"hasMore"`
+}
@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// QUOTA_JOB_WINDOW is the window the job submissions of a principal are counted in for the job quota
+const QUOTA_JOB_WINDOW = 24 * time.Hour
+
+// QUOTA_WARNING_HEADER is the response header with the warning of a request above a soft limit
+const QUOTA_WARNING_HEADER = "X-Quota-Warning"
+
+// QuotaConfig holds the fair-use limits per principal, the user, API key or IP of a request without login.
+// The job limits are the jobs added in the QUOTA_JOB_WINDOW, the storage limits the bytes of the uploaded files.
+// Above a soft limit the requests get a warning, at a hard limit they are rejected. A limit of 0 is disabled.
+type QuotaConfig struct {
+	JobsSoft         int   `json:"jobs_soft"`
+	JobsHard         int   `json:"jobs_hard"`
+	StorageSoftBytes int64 `json:"storage_soft_bytes"`
+	StorageHardBytes int64 `json:"storage_hard_bytes"`
+}
+
+// HasLimits reports whether any limit is set.
+func (q *QuotaConfig) HasLimits() bool {
+	return q.JobsSoft > 0 || q.JobsHard > 0 || q.StorageSoftBytes > 0 || q.StorageHardBytes > 0
+}
+
+// QuotaUsage is the usage of a principal with the limits and the warnings of the exceeded soft limits.
+type QuotaUsage struct {
+	Principal    string       `json:"principal"`
+	Jobs         int          `json:"jobs"`
+	StorageBytes int64        `json:"storage_bytes"`
+	Files        int          `json:"files"`
+	Limits       *QuotaConfig `json:"limits"`
+	Warnings     []string     `json:"warnings"`
+}
+
+// FileOwner records the principal that uploaded a file with its size, the storage usage of a principal
+// is the size of its files. Files uploaded again belong to the last principal that uploaded them.
+type FileOwner struct {
+	Filename  string    `json:"filename"`
+	Principal string    `json:"principal"`
+	SizeBytes int64     `json:"size_bytes"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// RecordedRequest is a sanitized request and response pair captured by the request recorder.
+// Secret headers and fields are redacted and the bodies are truncated.
+type RecordedRequest struct {
+	ID              int               `json:"id"`
+	Time            time.Time         `json:"time"`
+	Method          string            `json:"method"`
+	Path            string            `json:"path"`
+	Query           string            `json:"query"`
+	HxRequest       bool              `json:"hx_request"`
+	User            string            `json:"user"`
+	RequestHeaders  map[string]string `json:"request_headers"`
+	RequestBody     string            `json:"request_body"`
+	Status          int               `json:"status"`
+	ResponseHeaders map[string]string `json:"response_headers"`
+	ResponseBody    string            `json:"response_body"`
+	Error           string            `json:"error,omitempty"`
+	DurationMs      int64             `json:"duration_ms"`
+}
+
+// RecorderSettings are the settings of the request recorder.
+// Routes are path prefixes, without routes all requests except the excluded ones are recorded.
+type RecorderSettings struct {
+	Enabled  bool     `json:"enabled" form:"enabled"`
+	Routes   []string `json:"routes" form:"routes"`
+	Capacity int      `json:"capacity"`
+}
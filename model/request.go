@@ -10,9 +10,14 @@ type ContextKey string
 
 const REQUEST_CONTEXT_KEY ContextKey = "request_context"
 
+// NAMESPACE_COOKIE_NAME is the cookie of the namespace selected with the namespace switcher of the web interface.
+const NAMESPACE_COOKIE_NAME = "queuer_manager_namespace"
+
 type RequestContext struct {
 	Url       string `json:"url"`
 	HxRequest bool   `json:"hx_request"`
+	User      *User  `json:"user"`
+	Namespace string `json:"namespace"`
 }
 
 func SetRequestContext(c *echo.Context, value any) {
@@ -0,0 +1,18 @@
+package model
+
+// Strategies to generate the RIDs of new rows of the manager tables.
+const (
+	RID_STRATEGY_RANDOM = "random"
+	RID_STRATEGY_UUIDV7 = "uuidv7"
+)
+
+// RID_STRATEGIES are the selectable strategies to generate RIDs, random UUIDv4 is the default.
+// UUIDv7 RIDs start with the creation time in milliseconds, so they are sortable and new rows are appended to the RID indexes.
+var RID_STRATEGIES = []string{RID_STRATEGY_RANDOM, RID_STRATEGY_UUIDV7}
+
+// RID_TABLES are the tables of the entities created by the manager whose RIDs are generated with the RID strategy.
+var RID_TABLES = []string{"task", "job_template", "batch", "schedule", "api_key", "forwarded_job", "manager_user", "manager_group", "health_incident"}
+
+// RID_MIGRATABLE_TABLES are the tables whose existing RIDs can be migrated to UUIDv7.
+// Users and groups are excluded, because group members and SCIM clients reference them by their RIDs.
+var RID_MIGRATABLE_TABLES = []string{"task", "job_template", "batch", "schedule", "api_key", "forwarded_job", "health_incident"}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// ScaleRequest is sent to an external orchestrator (eg. a K8s or Nomad autoscaler)
+// when an operator requests a worker count for a pool.
+type ScaleRequest struct {
+	Pool           string    `json:"pool"`
+	DesiredCount   int       `json:"desired_count"`
+	CurrentWorkers int       `json:"current_workers"`
+	RequestedBy    string    `json:"requested_by"`
+	RequestedAt    time.Time `json:"requested_at"`
+}
+
+// ScaleAudit is the audit trail entry of a scale request.
+// Status is "requested" if the scaler accepted the request, otherwise "failed" with the error.
+type ScaleAudit struct {
+	ID             int       `json:"id"`
+	Pool           string    `json:"pool"`
+	Scaler         string    `json:"scaler"`
+	DesiredCount   int       `json:"desired_count"`
+	CurrentWorkers int       `json:"current_workers"`
+	RequestedBy    string    `json:"requested_by"`
+	Status         string    `json:"status"`
+	Error          string    `json:"error"`
+	CreatedAt      time.Time `json:"created_at"`
+}
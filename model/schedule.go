@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/model"
+)
+
+// SCHEDULE_INITIATOR_PREFIX is the prefix of the initiator of the jobs added by a schedule, followed by the schedule name.
+const SCHEDULE_INITIATOR_PREFIX = "schedule:"
+
+// Schedule adds a job of a task with the default parameters at the times of the cron expression.
+// NextRunAt is only set for enabled schedules, LastError is the error of the last run if adding the job failed.
+type Schedule struct {
+	ID             int                   `json:"id"`
+	RID            uuid.UUID             `json:"rid"`
+	Name           string                `json:"name"`
+	TaskKey        string                `json:"task_key"`
+	CronExpression string                `json:"cron_expression"`
+	Parameters     model.ParametersKeyed `json:"parameters"`
+	Enabled        bool                  `json:"enabled"`
+	NextRunAt      *time.Time            `json:"next_run_at"`
+	LastRunAt      *time.Time            `json:"last_run_at"`
+	LastJobRID     *uuid.UUID            `json:"last_job_rid"`
+	LastError      string                `json:"last_error"`
+	CreatedBy      string                `json:"created_by"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
+
+// ScheduleRequest is the body to add or update a schedule.
+// The parameters are the JSON object of the parameters of a job of the task.
+type ScheduleRequest struct {
+	Name           string `json:"name" form:"name"`
+	TaskKey        string `json:"task_key" form:"task_key"`
+	CronExpression string `json:"cron_expression" form:"cron_expression"`
+	Parameters     string `json:"parameters" form:"parameters"`
+	Enabled        bool   `json:"enabled" form:"enabled"`
+}
+
+// ScheduleDefinition is the declarative definition of a schedule as used by the task export and import.
+// It references its task by key and contains no generated fields like the RID or run times.
+type ScheduleDefinition struct {
+	Name           string                `json:"name"`
+	TaskKey        string                `json:"task_key"`
+	CronExpression string                `json:"cron_expression"`
+	Parameters     model.ParametersKeyed `json:"parameters"`
+	Enabled        bool                  `json:"enabled"`
+}
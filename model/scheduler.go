@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// Modes of the scheduler policy, in which order the workers claim the queued jobs.
+const (
+	SCHEDULER_MODE_FIFO        = "fifo"
+	SCHEDULER_MODE_ROUND_ROBIN = "round_robin"
+)
+
+// SCHEDULER_DEFAULT_TASK_WEIGHT is the weight of tasks without weight in the round robin mode.
+const SCHEDULER_DEFAULT_TASK_WEIGHT = 1
+
+// SchedulerPolicy is the order in which the workers claim the queued jobs.
+// In the fifo mode the oldest jobs are claimed first, which is the default of the queuer.
+// In the round robin mode the workers take turns between the tasks, a task with weight 3
+// gets three jobs claimed for every job of a task with weight 1, so one task can not starve the others.
+type SchedulerPolicy struct {
+	Mode        string         `json:"mode"`
+	TaskWeights map[string]int `json:"task_weights"`
+	UpdatedBy   string         `json:"updated_by"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// SchedulerPolicyRequest is the body to update the scheduler policy.
+// The weights of the form are lines of `task=weight`, they are added to the task weights of the JSON body.
+type SchedulerPolicyRequest struct {
+	Mode        string         `json:"mode" form:"mode"`
+	TaskWeights map[string]int `json:"task_weights"`
+	Weights     string         `json:"-" form:"weights"`
+}
+
+// TaskWeight returns the weight of the task in the round robin mode.
+func (p *SchedulerPolicy) TaskWeight(taskName string) int {
+	if weight, ok := p.TaskWeights[taskName]; ok && weight > 0 {
+		return weight
+	}
+	return SCHEDULER_DEFAULT_TASK_WEIGHT
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Schemas of the SCIM 2.0 resources and messages, see RFC 7643 and RFC 7644
+const (
+	SCIM_SCHEMA_USER                    = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SCIM_SCHEMA_GROUP                   = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SCIM_SCHEMA_SERVICE_PROVIDER_CONFIG = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SCIM_SCHEMA_LIST_RESPONSE           = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SCIM_SCHEMA_PATCH_OP                = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SCIM_SCHEMA_ERROR                   = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// ScimMeta is the meta attribute of a SCIM resource
+type ScimMeta struct {
+	ResourceType string     `json:"resourceType"`
+	Created      *time.Time `json:"created,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Location     string     `json:"location,omitempty"`
+}
+
+// ScimName is the name of a SCIM user
+type ScimName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// ScimEmail is an email address of a SCIM user
+type ScimEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// ScimReference references a user (group members) or a group (groups of a user) by its id
+type ScimReference struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+}
+
+// ScimUser is the SCIM representation of a manager user, the id is the user RID.
+// Active is a pointer so a missing active attribute can default to true.
+type ScimUser struct {
+	Schemas     []string        `json:"schemas"`
+	ID          string          `json:"id,omitempty"`
+	ExternalID  string          `json:"externalId,omitempty"`
+	UserName    string          `json:"userName"`
+	Name        *ScimName       `json:"name,omitempty"`
+	DisplayName string          `json:"displayName,omitempty"`
+	Emails      []ScimEmail     `json:"emails,omitempty"`
+	Active      *bool           `json:"active,omitempty"`
+	Groups      []ScimReference `json:"groups,omitempty"`
+	Meta        *ScimMeta       `json:"meta,omitempty"`
+}
+
+// ScimGroup is the SCIM representation of a group, the id is the group RID.
+type ScimGroup struct {
+	Schemas     []string        `json:"schemas"`
+	ID          string          `json:"id,omitempty"`
+	ExternalID  string          `json:"externalId,omitempty"`
+	DisplayName string          `json:"displayName"`
+	Members     []ScimReference `json:"members"`
+	Meta        *ScimMeta       `json:"meta,omitempty"`
+}
+
+// ScimListResponse is the response of a SCIM list request, startIndex is 1-based
+type ScimListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    any      `json:"Resources"`
+}
+
+// ScimPatchRequest is a SCIM PATCH request with its operations
+type ScimPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []ScimPatchOperation `json:"Operations"`
+}
+
+// ScimPatchOperation is a single add, replace or remove operation of a SCIM PATCH request
+type ScimPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ScimError is the error response of the SCIM endpoints, the status is a string as required by the RFC
+type ScimError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
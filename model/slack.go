@@ -0,0 +1,13 @@
+package model
+
+const (
+	SLACK_RESPONSE_EPHEMERAL  = "ephemeral"
+	SLACK_RESPONSE_IN_CHANNEL = "in_channel"
+)
+
+// SlackResponse is the response message of a Slack slash command.
+// Ephemeral responses are only shown to the user that sent the command.
+type SlackResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
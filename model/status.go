@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+const (
+	STATUS_OK       = "ok"
+	STATUS_DEGRADED = "degraded"
+	STATUS_DOWN     = "down"
+	STATUS_DISABLED = "disabled"
+)
+
+const (
+	SUBSYSTEM_DATABASE  = "Database"
+	SUBSYSTEM_MASTER    = "Queuer Master"
+	SUBSYSTEM_STORAGE   = "Storage"
+	SUBSYSTEM_NOTIFIER  = "Notifier"
+	SUBSYSTEM_SCHEDULER = "Scheduler"
+)
+
+// SUBSYSTEMS are the subsystems of the manager in the order of the status page.
+var SUBSYSTEMS = []string{SUBSYSTEM_DATABASE, SUBSYSTEM_MASTER, SUBSYSTEM_STORAGE, SUBSYSTEM_NOTIFIER, SUBSYSTEM_SCHEDULER}
+
+// StatusError is an error of a subsystem and when it occurred.
+type StatusError struct {
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+// SubsystemStatus is the health of a subsystem of the manager.
+// LastCheck is the time of the last check or run of the subsystem, zero if it never ran.
+type SubsystemStatus struct {
+	Name         string        `json:"name"`
+	Status       string        `json:"status"`
+	Message      string        `json:"message"`
+	LastCheck    time.Time     `json:"last_check"`
+	RecentErrors []StatusError `json:"recent_errors"`
+}
+
+// SystemStatus is the health of all subsystems of the manager.
+// The status is the worst status of the subsystems, disabled subsystems are ignored.
+type SystemStatus struct {
+	Status     string             `json:"status"`
+	CheckedAt  time.Time          `json:"checked_at"`
+	Subsystems []*SubsystemStatus `json:"subsystems"`
+}
+
+// NewSystemStatus creates the system status of the subsystems.
+func NewSystemStatus(subsystems []*SubsystemStatus) *SystemStatus {
+	systemStatus := &SystemStatus{
+		Status:     STATUS_OK,
+		CheckedAt:  time.Now(),
+		Subsystems: subsystems,
+	}
+	for _, subsystem := range subsystems {
+		switch subsystem.Status {
+		case STATUS_DOWN:
+			systemStatus.Status = STATUS_DOWN
+		case STATUS_DEGRADED:
+			if systemStatus.Status == STATUS_OK {
+				systemStatus.Status = STATUS_DEGRADED
+			}
+		}
+	}
+	return systemStatus
+}
@@ -1,22 +1,175 @@
 package model
 
 import (
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 	vm "github.com/siherrmann/validator/model"
 )
 
-// Task represents a task configuration in the database
+// Worker version policies define if adding a job only warns or is blocked
+// if no connected worker satisfies the MinWorkerVersion of the task.
+const (
+	WORKER_VERSION_POLICY_WARN  = "warn"
+	WORKER_VERSION_POLICY_BLOCK = "block"
+)
+
+// Sort fields of the task list, the task list is sorted by ID if no sort field is set
+const (
+	TASK_SORT_NAME       = "name"
+	TASK_SORT_KEY        = "key"
+	TASK_SORT_CREATED_AT = "created_at"
+	TASK_SORT_UPDATED_AT = "updated_at"
+)
+
+// TASK_SORTS are the valid sort fields of the task list
+var TASK_SORTS = []string{TASK_SORT_NAME, TASK_SORT_KEY, TASK_SORT_CREATED_AT, TASK_SORT_UPDATED_AT}
+
+// ParameterDoc documents an input parameter of a task, the add job form shows the description
+// as help text and the example as placeholder of the input of the parameter.
+type ParameterDoc struct {
+	Description string `json:"description,omitempty"`
+	Example     string `json:"example,omitempty"`
+}
+
+// TaskDependency is a downstream task of a task, a job of the downstream task is added when a job of the task succeeds.
+// The parameter mapping maps the input parameter keys of the downstream task to the output parameter keys of the task,
+// the mapped results of the succeeded job are the parameters of the downstream job.
+type TaskDependency struct {
+	TaskKey          string            `json:"task_key"`
+	ParameterMapping map[string]string `json:"parameter_mapping,omitempty"`
+}
+
+// Task represents a task configuration in the database.
+// The owner is the user or team responsible for the task, eg. to know whom to ping if its jobs fail.
+// With a dedup window, adding a job with the same parameters as a job added within the window returns that job.
+// The parameter docs are the documentation of the input parameters by parameter key.
+// On success lists the downstream tasks, a job of each is added when a job of the task succeeds.
+// Alerts are the alert kinds the task opted in to, eg. job_failed to alert about its failed jobs.
+// The namespace groups the tasks and their jobs of a team sharing the manager, tasks without namespace are in no namespace.
+// With a max concurrency or a rate limit per minute, jobs of the task are rejected while the task has as many jobs
+// in the queue or added as many jobs in the last minute, so a single task can not saturate all workers.
 type Task struct {
-	ID                   int             `json:"id"`
-	RID                  uuid.UUID       `json:"rid"`
-	Key                  string          `json:"key"`
-	Name                 string          `json:"name"`
-	Description          string          `json:"description"`
-	InputParameters      []vm.Validation `json:"input_parameters"`
-	InputParametersKeyed []vm.Validation `json:"input_parameters_keyed"`
-	OutputParameters     []vm.Validation `json:"output_parameters"`
-	CreatedAt            time.Time       `json:"created_at"`
-	UpdatedAt            time.Time       `json:"updated_at"`
+	ID                   int                     `json:"id"`
+	RID                  uuid.UUID               `json:"rid"`
+	Key                  string                  `json:"key"`
+	Name                 string                  `json:"name"`
+	Description          string                  `json:"description"`
+	InputParameters      []vm.Validation         `json:"input_parameters"`
+	InputParametersKeyed []vm.Validation         `json:"input_parameters_keyed"`
+	OutputParameters     []vm.Validation         `json:"output_parameters"`
+	MinWorkerVersion     string                  `json:"min_worker_version,omitempty"`
+	WorkerVersionPolicy  string                  `json:"worker_version_policy,omitempty"`
+	Owner                string                  `json:"owner,omitempty"`
+	Namespace            string                  `json:"namespace,omitempty"`
+	DedupWindowMinutes   int                     `json:"dedup_window_minutes,omitempty"`
+	ParameterDocs        map[string]ParameterDoc `json:"parameter_docs,omitempty"`
+	OnSuccess            []TaskDependency        `json:"on_success,omitempty"`
+	Alerts               []string                `json:"alerts,omitempty"`
+	MaxConcurrency       int                     `json:"max_concurrency,omitempty"`
+	RateLimitPerMinute   int                     `json:"rate_limit_per_minute,omitempty"`
+	CreatedAt            time.Time               `json:"created_at"`
+	UpdatedAt            time.Time               `json:"updated_at"`
+}
+
+// HasParameter reports if the task has an input parameter or keyed input parameter with the key.
+func (t *Task) HasParameter(key string) bool {
+	for _, v := range append(append([]vm.Validation{}, t.InputParameters...), t.InputParametersKeyed...) {
+		if v.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAlert reports if the task opted in to the alerts of the kind.
+func (t *Task) HasAlert(kind string) bool {
+	return slices.Contains(t.Alerts, kind)
+}
+
+// TaskDefinition is the declarative definition of a task as used by the task export and import
+// and the create-or-update endpoint. It contains no generated fields like the RID or timestamps.
+type TaskDefinition struct {
+	Key                  string                  `json:"key"`
+	Name                 string                  `json:"name"`
+	Description          string                  `json:"description"`
+	InputParameters      []vm.Validation         `json:"input_parameters"`
+	InputParametersKeyed []vm.Validation         `json:"input_parameters_keyed"`
+	OutputParameters     []vm.Validation         `json:"output_parameters"`
+	MinWorkerVersion     string                  `json:"min_worker_version"`
+	WorkerVersionPolicy  string                  `json:"worker_version_policy"`
+	Owner                string                  `json:"owner"`
+	Namespace            string                  `json:"namespace,omitempty"`
+	DedupWindowMinutes   int                     `json:"dedup_window_minutes"`
+	ParameterDocs        map[string]ParameterDoc `json:"parameter_docs,omitempty"`
+	OnSuccess            []TaskDependency        `json:"on_success,omitempty"`
+	Alerts               []string                `json:"alerts,omitempty"`
+	MaxConcurrency       int                     `json:"max_concurrency,omitempty"`
+	RateLimitPerMinute   int                     `json:"rate_limit_per_minute,omitempty"`
+}
+
+// ToTask converts the definition to a task. Missing parameter lists are set to empty lists,
+// so an omitted list and an empty list are stored the same way.
+func (d *TaskDefinition) ToTask() *Task {
+	task := &Task{
+		Key:                  d.Key,
+		Name:                 d.Name,
+		Description:          d.Description,
+		InputParameters:      d.InputParameters,
+		InputParametersKeyed: d.InputParametersKeyed,
+		OutputParameters:     d.OutputParameters,
+		MinWorkerVersion:     d.MinWorkerVersion,
+		WorkerVersionPolicy:  d.WorkerVersionPolicy,
+		Owner:                d.Owner,
+		Namespace:            d.Namespace,
+		DedupWindowMinutes:   d.DedupWindowMinutes,
+		ParameterDocs:        d.ParameterDocs,
+		OnSuccess:            d.OnSuccess,
+		Alerts:               d.Alerts,
+		MaxConcurrency:       d.MaxConcurrency,
+		RateLimitPerMinute:   d.RateLimitPerMinute,
+	}
+	if task.InputParameters == nil {
+		task.InputParameters = []vm.Validation{}
+	}
+	if task.InputParametersKeyed == nil {
+		task.InputParametersKeyed = []vm.Validation{}
+	}
+	if task.OutputParameters == nil {
+		task.OutputParameters = []vm.Validation{}
+	}
+	return task
+}
+
+// PipelineDefinition is the task export format with the schedules and webhooks of the tasks.
+// The schedules and webhooks reference the tasks by key, so a pipeline can be moved between environments in one file.
+type PipelineDefinition struct {
+	Tasks     []TaskDefinition     `json:"tasks"`
+	Schedules []ScheduleDefinition `json:"schedules,omitempty"`
+	Webhooks  []NotificationRule   `json:"webhooks,omitempty"`
+}
+
+// TaskFilter are the sort and filters of the task list, empty fields do not filter.
+// Parameter matches the tasks with an input, keyed input or output parameter of the key,
+// created after and before are exclusive bounds of the creation time.
+type TaskFilter struct {
+	Namespace            string    `json:"namespace"`
+	Parameter            string    `json:"parameter"`
+	KeyPrefix            string    `json:"key_prefix"`
+	WithOutputParameters bool      `json:"with_output_parameters"`
+	CreatedAfter         time.Time `json:"created_after"`
+	CreatedBefore        time.Time `json:"created_before"`
+	Sort                 string    `json:"sort"`
+	Descending           bool      `json:"descending"`
+}
+
+// NamespaceRequest selects the namespace the lists of the web interface are filtered by, an empty namespace shows all namespaces.
+type NamespaceRequest struct {
+	Namespace string `json:"namespace" form:"namespace"`
+}
+
+// HasFilters reports whether any filter or sort is set.
+func (f *TaskFilter) HasFilters() bool {
+	return f.Namespace != "" || f.Parameter != "" || f.KeyPrefix != "" || f.WithOutputParameters || !f.CreatedAfter.IsZero() || !f.CreatedBefore.IsZero() || f.Sort != "" || f.Descending
 }
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	COST_MODEL_RUN    = "run"
+	COST_MODEL_SECOND = "second"
+)
+
+const (
+	COST_GROUP_TASK   = "task"
+	COST_GROUP_LABEL  = "label"
+	COST_GROUP_TENANT = "tenant"
+)
+
+// TaskCost is the cost model of a task, a rate per run or per second of the run time of its jobs.
+// Label and tenant group the estimated costs of tasks for chargeback, eg. a cost center and a customer.
+type TaskCost struct {
+	TaskKey   string    `json:"task_key"`
+	Model     string    `json:"model"`
+	Rate      float64   `json:"rate"`
+	Label     string    `json:"label"`
+	Tenant    string    `json:"tenant"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the cost model and the rate of the task cost.
+func (t *TaskCost) Validate() error {
+	if t.Model != COST_MODEL_RUN && t.Model != COST_MODEL_SECOND {
+		return fmt.Errorf("invalid cost model %s (must be %s or %s)", t.Model, COST_MODEL_RUN, COST_MODEL_SECOND)
+	}
+	if t.Rate < 0 {
+		return fmt.Errorf("invalid rate %v (must not be negative)", t.Rate)
+	}
+	return nil
+}
+
+// JobCost is the estimated cost of the jobs of a group (task key, label or tenant) that ended in a time range.
+// Only jobs of tasks with a cost model are accounted.
+type JobCost struct {
+	Group           string  `json:"group"`
+	Jobs            int     `json:"jobs"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Cost            float64 `json:"cost"`
+}
@@ -0,0 +1,29 @@
+package model
+
+import "github.com/google/uuid"
+
+// TaskDiffLine is a row of the side-by-side diff of a task field, Change is empty for unchanged lines
+// or one of the catalog changes added, removed and changed.
+type TaskDiffLine struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+	Change string `json:"change,omitempty"`
+}
+
+// TaskFieldDiff is a changed field of a task, Before and After are the values as indented JSON
+// (strings as they are) and Lines is their line by line diff.
+type TaskFieldDiff struct {
+	Field  string         `json:"field"`
+	Before string         `json:"before"`
+	After  string         `json:"after"`
+	Lines  []TaskDiffLine `json:"lines"`
+}
+
+// TaskDiff are the changes of an update of a task. Warnings describe changes that can break
+// existing jobs, schedules or workers, eg. removed parameters or a changed key.
+type TaskDiff struct {
+	RID      uuid.UUID        `json:"rid"`
+	Key      string           `json:"key"`
+	Fields   []*TaskFieldDiff `json:"fields"`
+	Warnings []string         `json:"warnings"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// TASK_RATE_LIMIT_WINDOW is the window the added jobs of a task are counted in for its rate limit per minute
+const TASK_RATE_LIMIT_WINDOW = time.Minute
+
+// MAX_TASK_LIMIT is the highest max concurrency and rate limit per minute of a task
+const MAX_TASK_LIMIT = 100000
+
+// TaskUtilization is the usage of the concurrency and rate limits of a task. Active are the queued, scheduled
+// and running jobs of the task, the max concurrency limits them. Added are the jobs added in the TASK_RATE_LIMIT_WINDOW,
+// including the jobs that already ended. A limit of 0 is disabled.
+type TaskUtilization struct {
+	TaskKey            string `json:"task_key"`
+	Running            int    `json:"running"`
+	Active             int    `json:"active"`
+	Added              int    `json:"added"`
+	MaxConcurrency     int    `json:"max_concurrency"`
+	RateLimitPerMinute int    `json:"rate_limit_per_minute"`
+}
+
+// ConcurrencyExceeded reports whether adding the jobs would exceed the max concurrency of the task.
+func (u *TaskUtilization) ConcurrencyExceeded(jobs int) bool {
+	return u.MaxConcurrency > 0 && u.Active+jobs > u.MaxConcurrency
+}
+
+// RateLimitExceeded reports whether adding the jobs would exceed the rate limit per minute of the task.
+func (u *TaskUtilization) RateLimitExceeded(jobs int) bool {
+	return u.RateLimitPerMinute > 0 && u.Added+jobs > u.RateLimitPerMinute
+}
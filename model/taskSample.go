@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// TaskSample are the stored sample values of the input parameters of a task by parameter key.
+// The test run of a task prefills the add job form with them, eg. to smoke test a task after editing it.
+type TaskSample struct {
+	TaskKey   string            `json:"task_key"`
+	Values    map[string]string `json:"values"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
@@ -0,0 +1,27 @@
+package model
+
+// TaskSignature describes a task function registered on a worker, eg. created with helper.NewTaskSignature.
+// The manager generates the task definition from it with validations inferred from the parameter types.
+type TaskSignature struct {
+	Key             string               `json:"key"`
+	Name            string               `json:"name"`
+	Description     string               `json:"description"`
+	Parameters      []ParameterSignature `json:"parameters"`
+	ParametersKeyed []ParameterSignature `json:"parameters_keyed"`
+	Results         []ParameterSignature `json:"results"`
+}
+
+// ParameterSignature is a parameter or result of a task function with its Go type,
+// eg. "string", "*int", "[]float64", "map[string]any" or "struct" with the fields of the struct.
+type ParameterSignature struct {
+	Name   string               `json:"name"`
+	Type   string               `json:"type"`
+	Fields []ParameterSignature `json:"fields,omitempty"`
+}
+
+// TaskSignatureImport is the result of importing task signatures with the keys of the created and updated tasks.
+type TaskSignatureImport struct {
+	Created []string `json:"created"`
+	Updated []string `json:"updated"`
+	Errors  []string `json:"errors"`
+}
@@ -1,8 +1,96 @@
 package model
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	qm "github.com/siherrmann/queuer/model"
+)
+
 // SidebarItem represents a custom item added to the navigation sidebar by an extension.
 type SidebarItem struct {
 	Title        string
 	MaterialIcon string
 	Href         string
 }
+
+// Colors of the status badges
+const (
+	STATUS_COLOR_GRAY   = "gray"
+	STATUS_COLOR_BLUE   = "blue"
+	STATUS_COLOR_GREEN  = "green"
+	STATUS_COLOR_RED    = "red"
+	STATUS_COLOR_YELLOW = "yellow"
+	STATUS_COLOR_ORANGE = "orange"
+	STATUS_COLOR_PURPLE = "purple"
+)
+
+var STATUS_COLORS = []string{STATUS_COLOR_GRAY, STATUS_COLOR_BLUE, STATUS_COLOR_GREEN, STATUS_COLOR_RED, STATUS_COLOR_YELLOW, STATUS_COLOR_ORANGE, STATUS_COLOR_PURPLE}
+
+// StatusDisplay is the display name and badge color of a job or worker status, empty fields keep the default.
+type StatusDisplay struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// defaultStatusColors are the badge colors of the statuses without a custom color
+var defaultStatusColors = map[string]string{
+	qm.WorkerStatusReady:   STATUS_COLOR_BLUE,
+	qm.JobStatusRunning:    STATUS_COLOR_BLUE,
+	qm.JobStatusSucceeded:  STATUS_COLOR_GREEN,
+	qm.WorkerStatusStopped: STATUS_COLOR_RED,
+	qm.JobStatusFailed:     STATUS_COLOR_RED,
+	qm.JobStatusQueued:     STATUS_COLOR_YELLOW,
+}
+
+var (
+	statusDisplaysMu sync.RWMutex
+	statusDisplays   = map[string]StatusDisplay{}
+)
+
+// SetStatusDisplays sets the custom displays of the statuses keyed by status, eg. {"CANCELLED": {"name": "Aborted"}}.
+// The statuses stay unchanged in the database and APIs, only views, exports and notifications show the display names.
+func SetStatusDisplays(displays map[string]StatusDisplay) error {
+	normalized := map[string]StatusDisplay{}
+	for status, display := range displays {
+		display.Color = strings.ToLower(strings.TrimSpace(display.Color))
+		if display.Color != "" && !slices.Contains(STATUS_COLORS, display.Color) {
+			return fmt.Errorf("invalid color %q of status %s (must be one of %s)", display.Color, status, strings.Join(STATUS_COLORS, ", "))
+		}
+		display.Name = strings.TrimSpace(display.Name)
+		normalized[strings.ToUpper(strings.TrimSpace(status))] = display
+	}
+
+	statusDisplaysMu.Lock()
+	defer statusDisplaysMu.Unlock()
+
+	statusDisplays = normalized
+	return nil
+}
+
+// StatusName returns the display name of a status, the status itself if it has no custom name.
+func StatusName(status string) string {
+	statusDisplaysMu.RLock()
+	defer statusDisplaysMu.RUnlock()
+
+	if display, ok := statusDisplays[status]; ok && display.Name != "" {
+		return display.Name
+	}
+	return status
+}
+
+// StatusColor returns the badge color of a status, gray for statuses without default or custom color.
+func StatusColor(status string) string {
+	statusDisplaysMu.RLock()
+	defer statusDisplaysMu.RUnlock()
+
+	if display, ok := statusDisplays[status]; ok && display.Color != "" {
+		return display.Color
+	}
+	if color, ok := defaultStatusColors[status]; ok {
+		return color
+	}
+	return STATUS_COLOR_GRAY
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Roles of the manager users, each role includes the permissions of the roles before it.
+// Viewers can only read, operators can also add and cancel jobs and batches, admins can also manage tasks, workers and files.
+const (
+	ROLE_VIEWER   = "viewer"
+	ROLE_OPERATOR = "operator"
+	ROLE_ADMIN    = "admin"
+)
+
+// Sources of the users, LDAP users are synced with their groups, SCIM users are provisioned by the identity provider
+// and OIDC users get the role of their groups on every login
+const (
+	USER_SOURCE_LDAP = "ldap"
+	USER_SOURCE_SCIM = "scim"
+	USER_SOURCE_OIDC = "oidc"
+)
+
+var roleRanks = map[string]int{
+	ROLE_VIEWER:   1,
+	ROLE_OPERATOR: 2,
+	ROLE_ADMIN:    3,
+}
+
+// IsValidRole reports if the role is one of the manager roles.
+func IsValidRole(role string) bool {
+	return roleRanks[role] > 0
+}
+
+// RoleIncludes reports if the role has at least the permissions of the required role.
+func RoleIncludes(role string, required string) bool {
+	return IsValidRole(role) && roleRanks[role] >= roleRanks[required]
+}
+
+// HighestRole returns the role with the most permissions, or an empty string if no role is valid.
+func HighestRole(roles ...string) string {
+	highest := ""
+	for _, role := range roles {
+		if roleRanks[role] > roleRanks[highest] {
+			highest = role
+		}
+	}
+	return highest
+}
+
+// User is a user of the manager, eg. authenticated with LDAP or provisioned with SCIM.
+// Users without role have no access, eg. after they were removed from all mapped groups,
+// as well as deactivated users.
+type User struct {
+	ID          int        `json:"id"`
+	RID         uuid.UUID  `json:"rid"`
+	ExternalID  string     `json:"external_id"`
+	Username    string     `json:"username"`
+	Name        string     `json:"name"`
+	Email       string     `json:"email"`
+	Role        string     `json:"role"`
+	Groups      []string   `json:"groups"`
+	Source      string     `json:"source"`
+	Active      bool       `json:"active"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// LoginOptions are the login methods shown on the login page.
+// Password is the login form of the authenticator, OIDCName the button of the OpenID Connect provider if configured.
+type LoginOptions struct {
+	Password bool
+	OIDCName string
+}
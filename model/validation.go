@@ -0,0 +1,28 @@
+package model
+
+// ValidationFunc checks a parameter value, which is already validated by the built-in validator,
+// against a custom requirement and returns an error if the value is invalid.
+type ValidationFunc func(value any) error
+
+// VALIDATION_TYPE_FILE is the type of task input parameters with the name of a file of the filesystem,
+// the job form uploads the file with the job submission.
+const VALIDATION_TYPE_FILE = "file"
+
+// REQUIREMENT_CUSTOM is the condition of the requirement builder for requirements that are entered as a whole,
+// eg. conditions combined with "&&" or "||" and custom validations.
+const REQUIREMENT_CUSTOM = "custom"
+
+// RequirementConditions are the conditions of the requirement builder of the task forms with their labels.
+var RequirementConditions = []KeyValuePair{
+	{Key: "-", Value: "None"},
+	{Key: "equ", Value: "Equal"},
+	{Key: "neq", Value: "Not equal"},
+	{Key: "min", Value: "Min"},
+	{Key: "max", Value: "Max"},
+	{Key: "con", Value: "Contains"},
+	{Key: "nco", Value: "Not contains"},
+	{Key: "frm", Value: "From"},
+	{Key: "nfr", Value: "Not from"},
+	{Key: "rex", Value: "Regex"},
+	{Key: REQUIREMENT_CUSTOM, Value: "Custom"},
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/siherrmann/queuer/model"
+)
+
+// Targets of the watches, a job watch is about one job, a task watch about all jobs of the task.
+const (
+	WATCH_TARGET_JOB  = "job"
+	WATCH_TARGET_TASK = "task"
+)
+
+// Watch is the subscription of a user to the state changes of a job or of the jobs of a task.
+// The target is the RID of the job or the key of the task. Watches with email also send an email to the user.
+type Watch struct {
+	ID         int       `json:"id"`
+	Username   string    `json:"username"`
+	TargetType string    `json:"target_type"`
+	Target     string    `json:"target"`
+	Email      bool      `json:"email"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// WatchNotification is the notification of a user about a state change of a watched job.
+type WatchNotification struct {
+	Username   string    `json:"username"`
+	TargetType string    `json:"target_type"`
+	Target     string    `json:"target"`
+	JobRID     uuid.UUID `json:"job_rid"`
+	TaskName   string    `json:"task_name"`
+	Status     string    `json:"status"`
+	StatusName string    `json:"status_name"`
+	Error      string    `json:"error,omitempty"`
+	Message    string    `json:"message"`
+	URL        string    `json:"url"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewWatchNotification creates the notification of the watch about the changed job.
+// The URL links the job view, absolute if the base URL is set.
+func NewWatchNotification(watch *Watch, job *model.Job, baseURL string) *WatchNotification {
+	return &WatchNotification{
+		Username:   watch.Username,
+		TargetType: watch.TargetType,
+		Target:     watch.Target,
+		JobRID:     job.RID,
+		TaskName:   job.TaskName,
+		Status:     job.Status,
+		StatusName: StatusName(job.Status),
+		Error:      job.Error,
+		Message:    fmt.Sprintf("Job %s of task %s is %s", job.RID, job.TaskName, strings.ToLower(StatusName(job.Status))),
+		URL:        strings.TrimRight(baseURL, "/") + "/job?rid=" + job.RID.String(),
+		CreatedAt:  time.Now(),
+	}
+}
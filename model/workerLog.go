@@ -0,0 +1,25 @@
+package model
+
+// Worker log levels in ascending severity, filtering by a level keeps the lines of the level and above.
+const (
+	WORKER_LOG_LEVEL_DEBUG = "debug"
+	WORKER_LOG_LEVEL_INFO  = "info"
+	WORKER_LOG_LEVEL_WARN  = "warn"
+	WORKER_LOG_LEVEL_ERROR = "error"
+)
+
+// WORKER_LOG_LEVELS are the worker log levels in ascending severity
+var WORKER_LOG_LEVELS = []string{WORKER_LOG_LEVEL_DEBUG, WORKER_LOG_LEVEL_INFO, WORKER_LOG_LEVEL_WARN, WORKER_LOG_LEVEL_ERROR}
+
+// DEFAULT_WORKER_LOG_LINES is the number of tailed worker log lines if none is requested
+const DEFAULT_WORKER_LOG_LINES = 200
+
+// MAX_WORKER_LOG_LINES is the maximum number of worker log lines that can be tailed at once
+const MAX_WORKER_LOG_LINES = 1000
+
+// WorkerLogLine is a line of the log output of a worker.
+// Level is empty if the line has no recognizable level, eg. the lines of a stack trace.
+type WorkerLogLine struct {
+	Line  string `json:"line"`
+	Level string `json:"level,omitempty"`
+}
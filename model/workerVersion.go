@@ -0,0 +1,15 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkerVersion is the version of the task code a worker reports with setWorkerVersion on start.
+// The minimum worker version of a task is checked against the versions of the workers having the task.
+type WorkerVersion struct {
+	WorkerRID uuid.UUID `json:"worker_rid"`
+	Version   string    `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,62 @@
+package queuerManager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/siherrmann/queuerManager/handler"
+	mw "github.com/siherrmann/queuerManager/middleware"
+
+	"github.com/labstack/echo/v5"
+)
+
+// NewManagerEcho initializes the manager with the configuration and returns its Echo instance with all routes and
+// middlewares and its handler without starting the HTTP server, eg. to serve it next to the routes of an existing
+// Echo application. The manager is shut down when the context is done.
+func NewManagerEcho(ctx context.Context, config *Config) (*echo.Echo, *handler.ManagerHandler, error) {
+	app := NewManagerAppWithConfig(config)
+	e, err := app.NewEcho(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return e, app.mh, nil
+}
+
+// MountOn mounts the manager under the path prefix of the group of an existing Echo application,
+// eg. app.MountOn(e.Group("/queuer")) serves the dashboard at /queuer/dashboard.
+// The manager is initialized with NewEcho if it was not initialized before.
+// It can not be combined with a configured base path, the path prefix of the group is the base path.
+func (app *ManagerApp) MountOn(group *echo.Group) error {
+	config, err := app.resolveConfig()
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if config.BasePath != "" {
+		return fmt.Errorf("manager with base path %s can not be mounted, the path prefix of the group is the base path", config.BasePath)
+	}
+
+	if app.echo == nil {
+		_, err = app.NewEcho(app.ctx)
+		if err != nil {
+			return fmt.Errorf("failed to initialize manager: %w", err)
+		}
+	}
+
+	mount := mountHandler(app.echo)
+	group.Any("", mount)
+	group.Any("/*", mount)
+	return nil
+}
+
+// mountHandler serves the requests of the mount with the Echo instance of the manager.
+// The base path of the mount is removed from the request path and added to the paths of the redirects,
+// the views get it from the request context to prefix their links.
+func mountHandler(managerEcho *echo.Echo) echo.HandlerFunc {
+	return func(c *echo.Context) error {
+		basePath := strings.TrimSuffix(strings.TrimSuffix(c.Path(), "/*"), "/")
+		req, w := mw.WithBasePath(c.Request(), c.Response(), basePath)
+		managerEcho.ServeHTTP(w, req)
+		return nil
+	}
+}
@@ -0,0 +1,100 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+)
+
+// Alerter sends the alerts of failed jobs, stale workers and missed schedule runs by email and to a Slack webhook
+type Alerter struct {
+	mailer     *Mailer
+	recipients []string
+	client     *http.Client
+	slackURL   string
+	templates  *Templates
+}
+
+// NewAlerter creates an alerter sending to the email recipients with the mailer and to the Slack incoming webhook.
+// Emails are only sent with a mailer and recipients, Slack messages only with a webhook URL.
+func NewAlerter(mailer *Mailer, recipients []string, slackURL string) (*Alerter, error) {
+	if len(recipients) > 0 && mailer == nil {
+		return nil, fmt.Errorf("alert emails require QUEUER_MANAGER_SMTP_HOST")
+	}
+	if len(recipients) == 0 && slackURL == "" {
+		return nil, fmt.Errorf("missing alert email recipients or slack webhook url")
+	}
+
+	return &Alerter{
+		mailer:     mailer,
+		recipients: recipients,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		slackURL:   slackURL,
+	}, nil
+}
+
+// CreateAlerterFromEnv creates an alerter with the comma separated recipients of QUEUER_MANAGER_ALERT_EMAILS
+// and the Slack incoming webhook of QUEUER_MANAGER_ALERT_SLACK_WEBHOOK_URL, emails are sent with the SMTP server of the mailer.
+// It returns nil if neither is configured.
+func CreateAlerterFromEnv(mailer *Mailer) (*Alerter, error) {
+	recipients := []string{}
+	for _, recipient := range strings.Split(os.Getenv("QUEUER_MANAGER_ALERT_EMAILS"), ",") {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			recipients = append(recipients, recipient)
+		}
+	}
+	slackURL := strings.TrimSpace(os.Getenv("QUEUER_MANAGER_ALERT_SLACK_WEBHOOK_URL"))
+	if len(recipients) == 0 && slackURL == "" {
+		return nil, nil
+	}
+
+	return NewAlerter(mailer, recipients, slackURL)
+}
+
+// SetTemplates sets the templates the alert emails are rendered with.
+func (a *Alerter) SetTemplates(templates *Templates) {
+	a.templates = templates
+}
+
+// Send sends the alert to all email recipients and to Slack and returns the errors of all failed deliveries
+func (a *Alerter) Send(ctx context.Context, alert *qmModel.Alert) error {
+	errs := []error{}
+
+	if len(a.recipients) > 0 {
+		email, err := a.templates.Render(qmModel.MESSAGE_TEMPLATE_ALERT_EMAIL, alert)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("email: %w", err))
+		} else {
+			for _, recipient := range a.recipients {
+				err := a.mailer.Send(ctx, recipient, email.Subject, email.Body)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("email %s: %w", recipient, err))
+				}
+			}
+		}
+	}
+
+	if a.slackURL != "" {
+		err := postJSON(ctx, a.client, a.slackURL, slackAlertPayload(alert))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("slack: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// slackAlertPayload returns the message of a Slack incoming webhook with the title in bold and a link to the alerted view
+func slackAlertPayload(alert *qmModel.Alert) map[string]any {
+	text := fmt.Sprintf("*%s*\n%s", alert.Title, truncate(alert.Message, 2000))
+	if alert.URL != "" {
+		text += fmt.Sprintf("\n<%s|Open in queuerManager>", alert.URL)
+	}
+	return map[string]any{"text": text}
+}
@@ -0,0 +1,138 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/siherrmann/queuerManager/helper"
+)
+
+// Mailer sends plain text emails with a SMTP server, STARTTLS is used if the server supports it
+type Mailer struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+	fromName string
+}
+
+// NewMailer creates a mailer for the SMTP server, the username is only used to authenticate if it is set
+func NewMailer(host string, port string, username string, password string, from string) (*Mailer, error) {
+	if host == "" {
+		return nil, fmt.Errorf("missing smtp host")
+	}
+	if from == "" {
+		return nil, fmt.Errorf("missing smtp from address")
+	}
+	return &Mailer{host: host, port: port, username: username, password: password, from: from}, nil
+}
+
+// CreateMailerFromEnv creates a mailer with QUEUER_MANAGER_SMTP_HOST, QUEUER_MANAGER_SMTP_PORT (default 587),
+// QUEUER_MANAGER_SMTP_USERNAME, QUEUER_MANAGER_SMTP_PASSWORD and QUEUER_MANAGER_SMTP_FROM.
+// It returns nil if no host is configured.
+func CreateMailerFromEnv() (*Mailer, error) {
+	host := strings.TrimSpace(os.Getenv("QUEUER_MANAGER_SMTP_HOST"))
+	if host == "" {
+		return nil, nil
+	}
+
+	return NewMailer(
+		host,
+		helper.GetEnvOrDefault("QUEUER_MANAGER_SMTP_PORT", "587"),
+		helper.GetEnvOrDefault("QUEUER_MANAGER_SMTP_USERNAME", ""),
+		helper.GetEnvOrDefault("QUEUER_MANAGER_SMTP_PASSWORD", ""),
+		helper.GetEnvOrDefault("QUEUER_MANAGER_SMTP_FROM", ""),
+	)
+}
+
+// SetSenderName sets the display name of the sender, eg. the brand of the deployment.
+func (m *Mailer) SetSenderName(name string) {
+	m.fromName = name
+}
+
+// Send sends the email to the recipient, the deadline of the context limits the whole SMTP session
+func (m *Mailer) Send(ctx context.Context, to string, subject string, body string) error {
+	if strings.ContainsAny(to, "\r\n") || strings.ContainsAny(subject, "\r\n") {
+		return fmt.Errorf("invalid email header")
+	}
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(m.host, m.port))
+	if err != nil {
+		return fmt.Errorf("error connecting to smtp server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("error creating smtp client: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: m.host})
+		if err != nil {
+			return fmt.Errorf("error starting tls: %w", err)
+		}
+	}
+	if m.username != "" {
+		err = client.Auth(smtp.PlainAuth("", m.username, m.password, m.host))
+		if err != nil {
+			return fmt.Errorf("error authenticating with smtp server: %w", err)
+		}
+	}
+
+	err = client.Mail(m.from)
+	if err != nil {
+		return fmt.Errorf("error setting sender: %w", err)
+	}
+	err = client.Rcpt(to)
+	if err != nil {
+		return fmt.Errorf("error setting recipient: %w", err)
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("error starting email data: %w", err)
+	}
+	_, err = writer.Write(m.message(to, subject, body))
+	if err != nil {
+		return fmt.Errorf("error writing email: %w", err)
+	}
+	err = writer.Close()
+	if err != nil {
+		return fmt.Errorf("error sending email: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// message returns the email with its headers, the lines of the body end with CRLF
+func (m *Mailer) message(to string, subject string, body string) []byte {
+	var message strings.Builder
+	if m.fromName != "" {
+		message.WriteString("From: " + (&mail.Address{Name: m.fromName, Address: m.from}).String() + "\r\n")
+	} else {
+		message.WriteString("From: " + m.from + "\r\n")
+	}
+	message.WriteString("To: " + to + "\r\n")
+	message.WriteString("Subject: " + subject + "\r\n")
+	message.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	message.WriteString("\r\n")
+	return []byte(message.String())
+}
@@ -0,0 +1,165 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/siherrmann/queuerManager/helper"
+	qmModel "github.com/siherrmann/queuerManager/model"
+
+	"github.com/siherrmann/queuer/model"
+)
+
+// Notifier sends a job notification to a chat or webhook
+type Notifier interface {
+	Notify(ctx context.Context, notification *qmModel.JobNotification) error
+}
+
+// NewNotifier creates the notifier for the format of a notification rule
+func NewNotifier(format string, webhookURL string) (Notifier, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	switch strings.ToLower(format) {
+	case qmModel.NOTIFICATION_FORMAT_WEBHOOK, "":
+		return &NotifierWebhook{client: client, url: webhookURL}, nil
+	case qmModel.NOTIFICATION_FORMAT_TEAMS:
+		return &NotifierTeams{client: client, url: webhookURL}, nil
+	case qmModel.NOTIFICATION_FORMAT_DISCORD:
+		return &NotifierDiscord{client: client, url: webhookURL}, nil
+	default:
+		return nil, fmt.Errorf("unsupported notification format: %s (supported: webhook, teams, discord)", format)
+	}
+}
+
+// Dispatcher sends the notifications of ended jobs to the notifiers of the matching rules
+type Dispatcher struct {
+	rules     []*qmModel.NotificationRule
+	notifiers []Notifier
+	baseURL   string
+}
+
+// NewDispatcher creates a dispatcher with a notifier for each rule.
+// The base URL is the public URL of the manager the notifications link the job view with.
+func NewDispatcher(rules []*qmModel.NotificationRule, baseURL string) (*Dispatcher, error) {
+	d := &Dispatcher{baseURL: baseURL}
+	for i, rule := range rules {
+		if rule.URL == "" {
+			return nil, fmt.Errorf("missing url of notification rule %d (%s)", i, rule.Name)
+		}
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule-%d", i)
+		}
+
+		notifier, err := NewNotifier(rule.Format, rule.URL)
+		if err != nil {
+			return nil, fmt.Errorf("error creating notifier of rule %s: %w", rule.Name, err)
+		}
+		d.rules = append(d.rules, rule)
+		d.notifiers = append(d.notifiers, notifier)
+	}
+	return d, nil
+}
+
+// CreateDispatcherFromEnv creates a dispatcher with the JSON rules of QUEUER_MANAGER_NOTIFICATION_RULES.
+// It returns nil if no rules are configured.
+func CreateDispatcherFromEnv() (*Dispatcher, error) {
+	rulesJSON := strings.TrimSpace(os.Getenv("QUEUER_MANAGER_NOTIFICATION_RULES"))
+	if rulesJSON == "" {
+		return nil, nil
+	}
+
+	rules := []*qmModel.NotificationRule{}
+	err := json.Unmarshal([]byte(rulesJSON), &rules)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing QUEUER_MANAGER_NOTIFICATION_RULES: %w", err)
+	}
+
+	return NewDispatcher(rules, helper.GetEnvOrDefault("QUEUER_MANAGER_BASE_URL", ""))
+}
+
+// Rules returns copies of the notification rules of the dispatcher
+func (d *Dispatcher) Rules() []qmModel.NotificationRule {
+	rules := []qmModel.NotificationRule{}
+	for _, rule := range d.rules {
+		rules = append(rules, *rule)
+	}
+	return rules
+}
+
+// SetTemplates sets the templates the notifiers with the webhook format render their payload with.
+func (d *Dispatcher) SetTemplates(templates *Templates) {
+	for _, notifier := range d.notifiers {
+		if webhook, ok := notifier.(*NotifierWebhook); ok {
+			webhook.templates = templates
+		}
+	}
+}
+
+// NotifyJob sends the notification of the job to all matching rules and returns the errors of all failed rules.
+// The owner of the task and the initiator of the job are added to the notification if they are known.
+func (d *Dispatcher) NotifyJob(ctx context.Context, job *model.Job, owner string, initiator string) error {
+	errs := []error{}
+	for i, rule := range d.rules {
+		if !rule.Matches(job) {
+			continue
+		}
+
+		notification := qmModel.NewJobNotification(rule.Name, job, d.baseURL)
+		notification.Owner = owner
+		notification.Initiator = initiator
+		err := d.notifiers[i].Notify(ctx, notification)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rule %s: %w", rule.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// postJSON posts the payload to the webhook and checks the response status
+func postJSON(ctx context.Context, client *http.Client, url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error encoding notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// statusTitle returns the title of the notification, eg. "Job failed: convert-file"
+func statusTitle(notification *qmModel.JobNotification) string {
+	status := strings.ToLower(notification.StatusName)
+	return fmt.Sprintf("Job %s: %s", status, notification.TaskName)
+}
+
+// truncate shortens long job errors to the limits of the chat messages
+func truncate(value string, maxLength int) string {
+	runes := []rune(value)
+	if len(runes) <= maxLength {
+		return value
+	}
+	return string(runes[:maxLength-1]) + "…"
+}
@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	qmodel "github.com/siherrmann/queuer/model"
+)
+
+// Embed colors of the Discord notifications
+const (
+	discordColorSucceeded = 0x16a34a
+	discordColorFailed    = 0xdc2626
+)
+
+// NotifierDiscord posts the job notification as embed to a Discord webhook
+type NotifierDiscord struct {
+	client *http.Client
+	url    string
+}
+
+// Notify posts the notification as embed with the job fields, the title links to the job
+func (n *NotifierDiscord) Notify(ctx context.Context, notification *model.JobNotification) error {
+	color := discordColorSucceeded
+	if notification.Status != qmodel.JobStatusSucceeded {
+		color = discordColorFailed
+	}
+
+	fields := []map[string]any{
+		{"name": "Task", "value": notification.TaskName, "inline": true},
+		{"name": "Status", "value": notification.StatusName, "inline": true},
+		{"name": "Attempts", "value": strconv.Itoa(notification.Attempts), "inline": true},
+		{"name": "Job", "value": notification.JobRID.String()},
+	}
+	if notification.Duration != "" {
+		fields = append(fields, map[string]any{"name": "Duration", "value": notification.Duration, "inline": true})
+	}
+	if notification.Owner != "" {
+		fields = append(fields, map[string]any{"name": "Owner", "value": notification.Owner, "inline": true})
+	}
+	if notification.Initiator != "" {
+		fields = append(fields, map[string]any{"name": "Initiated by", "value": notification.Initiator, "inline": true})
+	}
+
+	embed := map[string]any{
+		"title":     truncate(statusTitle(notification), 256),
+		"color":     color,
+		"fields":    fields,
+		"timestamp": notification.EndedAt.Format(time.RFC3339),
+	}
+	if notification.URL != "" {
+		embed["url"] = notification.URL
+	}
+	if notification.Error != "" {
+		embed["description"] = "```\n" + truncate(notification.Error, 4000) + "\n```"
+	}
+
+	return postJSON(ctx, n.client, n.url, map[string]any{
+		"username": "queuerManager",
+		"embeds":   []map[string]any{embed},
+	})
+}
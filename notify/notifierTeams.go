@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/siherrmann/queuerManager/model"
+
+	qmodel "github.com/siherrmann/queuer/model"
+)
+
+// NotifierTeams posts the job notification as adaptive card to a Microsoft Teams webhook
+// (incoming webhook or Workflows "post to a channel when a webhook request is received")
+type NotifierTeams struct {
+	client *http.Client
+	url    string
+}
+
+// Notify posts the notification as adaptive card with the job facts and a link to the job
+func (n *NotifierTeams) Notify(ctx context.Context, notification *model.JobNotification) error {
+	color := "Good"
+	if notification.Status != qmodel.JobStatusSucceeded {
+		color = "Attention"
+	}
+
+	facts := []map[string]string{
+		{"title": "Task", "value": notification.TaskName},
+		{"title": "Status", "value": notification.StatusName},
+		{"title": "Job", "value": notification.JobRID.String()},
+		{"title": "Attempts", "value": strconv.Itoa(notification.Attempts)},
+	}
+	if notification.Duration != "" {
+		facts = append(facts, map[string]string{"title": "Duration", "value": notification.Duration})
+	}
+	if notification.Owner != "" {
+		facts = append(facts, map[string]string{"title": "Owner", "value": notification.Owner})
+	}
+	if notification.Initiator != "" {
+		facts = append(facts, map[string]string{"title": "Initiated by", "value": notification.Initiator})
+	}
+
+	body := []map[string]any{
+		{"type": "TextBlock", "size": "Medium", "weight": "Bolder", "color": color, "text": statusTitle(notification), "wrap": true},
+		{"type": "FactSet", "facts": facts},
+	}
+	if notification.Error != "" {
+		body = append(body, map[string]any{"type": "TextBlock", "text": truncate(notification.Error, 2000), "wrap": true, "color": "Attention", "fontType": "Monospace"})
+	}
+
+	card := map[string]any{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+	if notification.URL != "" {
+		card["actions"] = []map[string]string{{"type": "Action.OpenUrl", "title": "Open job", "url": notification.URL}}
+	}
+
+	return postJSON(ctx, n.client, n.url, map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content":     card,
+		}},
+	})
+}
@@ -0,0 +1,32 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/siherrmann/queuerManager/model"
+)
+
+// NotifierWebhook posts the job notification as JSON rendered with the job webhook template
+type NotifierWebhook struct {
+	client    *http.Client
+	url       string
+	templates *Templates
+}
+
+// Notify posts the notification, without edited template it is posted as plain JSON
+func (n *NotifierWebhook) Notify(ctx context.Context, notification *model.JobNotification) error {
+	payload, err := n.templates.Render(model.MESSAGE_TEMPLATE_JOB_WEBHOOK, notification)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, n.client, n.url, json.RawMessage(payload.Body))
+}
+
+// PostWebhookPayload posts a rendered payload of the job webhook template to the webhook, eg. to test an edited template
+func PostWebhookPayload(ctx context.Context, url string, payload string) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	return postJSON(ctx, client, url, json.RawMessage(payload))
+}
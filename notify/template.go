@@ -0,0 +1,150 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	qmModel "github.com/siherrmann/queuerManager/model"
+)
+
+// templateCacheTTL is how long the edited templates are cached, other instances pick up changes after it.
+const templateCacheTTL = 30 * time.Second
+
+// Templates are the message templates of the notifications, the edited templates replace the default templates.
+// The edited templates are cached and reloaded after templateCacheTTL.
+type Templates struct {
+	mu       sync.Mutex
+	load     func() ([]*qmModel.MessageTemplate, error)
+	brand    string
+	edited   map[string]*qmModel.MessageTemplate
+	loadedAt time.Time
+}
+
+// NewTemplates creates the templates with the function loading the edited templates, eg. from the database.
+// The brand is returned by the `brand` function of the templates, without load only the default templates are used.
+func NewTemplates(load func() ([]*qmModel.MessageTemplate, error), brand string) *Templates {
+	return &Templates{load: load, brand: brand, edited: map[string]*qmModel.MessageTemplate{}}
+}
+
+// Get returns the edited template with the key or the default template, nil if the key is unknown.
+// If the edited templates can not be reloaded the cached templates are used.
+func (t *Templates) Get(key string) *qmModel.MessageTemplate {
+	defaultTemplate := qmModel.DefaultMessageTemplate(key)
+	if defaultTemplate == nil {
+		return nil
+	}
+	if t == nil || t.load == nil {
+		return defaultTemplate
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if time.Since(t.loadedAt) > templateCacheTTL {
+		editedTemplates, err := t.load()
+		if err != nil {
+			log.Printf("Failed to load message templates: %v", err)
+		} else {
+			t.edited = map[string]*qmModel.MessageTemplate{}
+			for _, editedTemplate := range editedTemplates {
+				t.edited[editedTemplate.Key] = editedTemplate
+			}
+		}
+		t.loadedAt = time.Now()
+	}
+
+	editedTemplate, ok := t.edited[key]
+	if !ok {
+		return defaultTemplate
+	}
+	messageTemplate := *editedTemplate
+	messageTemplate.Description = defaultTemplate.Description
+	return &messageTemplate
+}
+
+// Invalidate reloads the edited templates on the next use, eg. after they were changed.
+func (t *Templates) Invalidate() {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.loadedAt = time.Time{}
+}
+
+// Brand returns the brand of the templates, empty without templates.
+func (t *Templates) Brand() string {
+	if t == nil {
+		return ""
+	}
+	return t.brand
+}
+
+// Render renders the template with the key with the data.
+func (t *Templates) Render(key string, data any) (*qmModel.RenderedMessage, error) {
+	messageTemplate := t.Get(key)
+	if messageTemplate == nil {
+		return nil, fmt.Errorf("unknown message template %s", key)
+	}
+	return RenderTemplate(messageTemplate, data, t.Brand())
+}
+
+// RenderTemplate renders the subject and body of the template with the data.
+// Besides the functions of text/template the templates can use `json` to encode a value as JSON,
+// eg. for strings in webhook payloads, and `brand` for the brand of the deployment.
+// Webhook templates have to render valid JSON.
+func RenderTemplate(messageTemplate *qmModel.MessageTemplate, data any, brand string) (*qmModel.RenderedMessage, error) {
+	funcs := template.FuncMap{
+		"json": func(value any) (string, error) {
+			encoded, err := json.Marshal(value)
+			return string(encoded), err
+		},
+		"brand": func() string { return brand },
+	}
+
+	rendered := &qmModel.RenderedMessage{}
+	for _, part := range []struct {
+		name   string
+		source string
+		target *string
+	}{
+		{"subject", messageTemplate.Subject, &rendered.Subject},
+		{"body", messageTemplate.Body, &rendered.Body},
+	} {
+		parsed, err := template.New(part.name).Funcs(funcs).Option("missingkey=error").Parse(part.source)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s of template %s: %w", part.name, messageTemplate.Key, err)
+		}
+		var buffer bytes.Buffer
+		err = parsed.Execute(&buffer, data)
+		if err != nil {
+			return nil, fmt.Errorf("error rendering %s of template %s: %w", part.name, messageTemplate.Key, err)
+		}
+		*part.target = buffer.String()
+	}
+
+	if messageTemplate.IsWebhook() {
+		if !json.Valid([]byte(rendered.Body)) {
+			return nil, fmt.Errorf("template %s does not render valid JSON", messageTemplate.Key)
+		}
+		rendered.Subject = ""
+	} else {
+		rendered.Subject = strings.TrimSpace(rendered.Subject)
+		if rendered.Subject == "" {
+			return nil, fmt.Errorf("template %s renders an empty subject", messageTemplate.Key)
+		}
+		if strings.ContainsAny(rendered.Subject, "\r\n") {
+			return nil, fmt.Errorf("template %s renders a subject with line breaks", messageTemplate.Key)
+		}
+	}
+
+	return rendered, nil
+}
@@ -0,0 +1,363 @@
+// Package fake has in-memory implementations of the queuer and the task database of the manager handler,
+// so handlers can be unit tested without a database, eg.
+//
+//	q := fake.NewQueuer()
+//	q.AddWorker(&model.Worker{Name: "worker", Status: model.WorkerStatusRunning})
+//	m := handler.NewManagerHandler(upload.NewFilesystemMemory(), fake.NewTaskDB(), q)
+//
+// The fakes do not run jobs, tests move jobs to the archive with EndJob like a worker would.
+package fake
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuer/model"
+
+	"github.com/google/uuid"
+)
+
+// Queuer is an in-memory queuer with jobs, an archive of the ended jobs and workers.
+// Lists are ordered by ID descending, newest first, lastId is the ID of the last entry of the previous page.
+type Queuer struct {
+	mu          sync.Mutex
+	nextID      int
+	jobs        []*model.Job
+	jobsEnded   []*model.Job
+	workers     []*model.Worker
+	connections []*model.Connection
+	stopped     bool
+}
+
+// NewQueuer creates an empty in-memory queuer.
+func NewQueuer() *Queuer {
+	return &Queuer{
+		jobs:        []*model.Job{},
+		jobsEnded:   []*model.Job{},
+		workers:     []*model.Worker{},
+		connections: []*model.Connection{},
+	}
+}
+
+// AddJob adds a queued job of the task, the task is the name of the task or its function.
+func (q *Queuer) AddJob(task interface{}, parametersKeyed map[string]interface{}, parameters ...interface{}) (*model.Job, error) {
+	return q.AddJobWithOptions(nil, task, parametersKeyed, parameters...)
+}
+
+// AddJobWithOptions adds a job of the task with the options, a job with a schedule is added as scheduled.
+func (q *Queuer) AddJobWithOptions(options *model.Options, task interface{}, parametersKeyed map[string]interface{}, parameters ...interface{}) (*model.Job, error) {
+	job, err := model.NewJob(task, options, parametersKeyed, parameters...)
+	if err != nil {
+		return nil, helper.NewError("creating job", err)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	q.nextID++
+	job.ID = q.nextID
+	job.RID = uuid.New()
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	q.jobs = append(q.jobs, job)
+
+	return copyJob(job), nil
+}
+
+// AddJobTx adds a queued job of the task like AddJob, the fake has no database so the job is added without the transaction.
+func (q *Queuer) AddJobTx(tx *sql.Tx, task interface{}, parametersKeyed map[string]interface{}, parameters ...interface{}) (*model.Job, error) {
+	return q.AddJobWithOptions(nil, task, parametersKeyed, parameters...)
+}
+
+// AddJobWithOptionsTx adds a job of the task with the options like AddJobWithOptions, without the transaction.
+func (q *Queuer) AddJobWithOptionsTx(tx *sql.Tx, options *model.Options, task interface{}, parametersKeyed map[string]interface{}, parameters ...interface{}) (*model.Job, error) {
+	return q.AddJobWithOptions(options, task, parametersKeyed, parameters...)
+}
+
+// AddJobs adds a queued job for each batch job, the jobs are added one by one without a transaction.
+func (q *Queuer) AddJobs(batchJobs []model.BatchJob) error {
+	for _, batchJob := range batchJobs {
+		_, err := q.AddJobWithOptions(batchJob.Options, batchJob.Task, batchJob.ParametersKeyed, batchJob.Parameters...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CancelJob moves a queued, scheduled or running job to the archive as cancelled.
+func (q *Queuer) CancelJob(jobRid uuid.UUID) (*model.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := slices.IndexFunc(q.jobs, func(job *model.Job) bool { return job.RID == jobRid })
+	if i < 0 {
+		return nil, helper.NewError("selecting job", fmt.Errorf("no job with rid %s", jobRid))
+	}
+
+	job := q.endJob(i, model.JobStatusCancelled, nil, "")
+	return copyJob(job), nil
+}
+
+// EndJob moves a job to the archive with the status, its results and error, like a worker ending the job.
+func (q *Queuer) EndJob(jobRid uuid.UUID, status string, results []interface{}, jobErr string) (*model.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := slices.IndexFunc(q.jobs, func(job *model.Job) bool { return job.RID == jobRid })
+	if i < 0 {
+		return nil, helper.NewError("selecting job", fmt.Errorf("no job with rid %s", jobRid))
+	}
+
+	job := q.endJob(i, status, results, jobErr)
+	return copyJob(job), nil
+}
+
+// endJob moves the job at the index to the archive, the lock has to be held.
+func (q *Queuer) endJob(i int, status string, results []interface{}, jobErr string) *model.Job {
+	job := q.jobs[i]
+	q.jobs = slices.Delete(q.jobs, i, i+1)
+
+	job.Status = status
+	job.Results = results
+	job.Error = jobErr
+	job.UpdatedAt = time.Now()
+	q.jobsEnded = append(q.jobsEnded, job)
+	return job
+}
+
+// SetJobStatus changes the status of a job in the queue, eg. to running.
+func (q *Queuer) SetJobStatus(jobRid uuid.UUID, status string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, job := range q.jobs {
+		if job.RID == jobRid {
+			job.Status = status
+			job.UpdatedAt = time.Now()
+			return nil
+		}
+	}
+	return helper.NewError("selecting job", fmt.Errorf("no job with rid %s", jobRid))
+}
+
+// DeleteJob deletes a job from the queue.
+func (q *Queuer) DeleteJob(jobRid uuid.UUID) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i := slices.IndexFunc(q.jobs, func(job *model.Job) bool { return job.RID == jobRid })
+	if i < 0 {
+		return helper.NewError("deleting job", fmt.Errorf("no job with rid %s", jobRid))
+	}
+	q.jobs = slices.Delete(q.jobs, i, i+1)
+	return nil
+}
+
+// ReaddJobFromArchive adds a new job with the task and parameters of an ended job.
+func (q *Queuer) ReaddJobFromArchive(jobRid uuid.UUID) (*model.Job, error) {
+	job, err := q.GetJobEnded(jobRid)
+	if err != nil {
+		return nil, helper.NewError("selecting job from archive", err)
+	}
+	return q.AddJob(job.TaskName, job.ParametersKeyed, job.Parameters...)
+}
+
+// GetJob returns a job in the queue.
+func (q *Queuer) GetJob(jobRid uuid.UUID) (*model.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return findJob(q.jobs, jobRid)
+}
+
+// GetJobs returns a page of the jobs in the queue.
+func (q *Queuer) GetJobs(lastId int, entries int) ([]*model.Job, error) {
+	return q.GetJobsBySearch("", lastId, entries)
+}
+
+// GetJobsBySearch returns a page of the jobs in the queue with the search in their RID, task name or status.
+func (q *Queuer) GetJobsBySearch(search string, lastId int, entries int) ([]*model.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return pageJobs(q.jobs, search, lastId, entries), nil
+}
+
+// GetJobEnded returns an ended job of the archive.
+func (q *Queuer) GetJobEnded(jobRid uuid.UUID) (*model.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return findJob(q.jobsEnded, jobRid)
+}
+
+// GetJobsEnded returns a page of the ended jobs of the archive.
+func (q *Queuer) GetJobsEnded(lastId int, entries int) ([]*model.Job, error) {
+	return q.GetJobsEndedBySearch("", lastId, entries)
+}
+
+// GetJobsEndedBySearch returns a page of the ended jobs of the archive with the search in their RID, task name or status.
+func (q *Queuer) GetJobsEndedBySearch(search string, lastId int, entries int) ([]*model.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return pageJobs(q.jobsEnded, search, lastId, entries), nil
+}
+
+// AddWorker adds a worker with the next ID and a new RID if it has none.
+func (q *Queuer) AddWorker(worker *model.Worker) *model.Worker {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	q.nextID++
+	worker.ID = q.nextID
+	if worker.RID == uuid.Nil {
+		worker.RID = uuid.New()
+	}
+	worker.CreatedAt = now
+	worker.UpdatedAt = now
+	q.workers = append(q.workers, worker)
+	return worker
+}
+
+// GetWorker returns a worker.
+func (q *Queuer) GetWorker(workerRid uuid.UUID) (*model.Worker, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, worker := range q.workers {
+		if worker.RID == workerRid {
+			workerCopy := *worker
+			return &workerCopy, nil
+		}
+	}
+	return nil, helper.NewError("selecting worker", fmt.Errorf("no worker with rid %s", workerRid))
+}
+
+// GetWorkers returns a page of the workers.
+func (q *Queuer) GetWorkers(lastId int, entries int) ([]*model.Worker, error) {
+	return q.GetWorkersBySearch("", lastId, entries)
+}
+
+// GetWorkersBySearch returns a page of the workers with the search in their RID, name or status.
+func (q *Queuer) GetWorkersBySearch(search string, lastId int, entries int) ([]*model.Worker, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	workers := []*model.Worker{}
+	for i := len(q.workers) - 1; i >= 0 && len(workers) < entries; i-- {
+		worker := q.workers[i]
+		if lastId > 0 && worker.ID >= lastId {
+			continue
+		}
+		if !matchesSearch(search, worker.RID.String(), worker.Name, worker.Status) {
+			continue
+		}
+		workerCopy := *worker
+		workers = append(workers, &workerCopy)
+	}
+	return workers, nil
+}
+
+// StopWorker sets the status of the worker to stopped.
+func (q *Queuer) StopWorker(workerRid uuid.UUID) error {
+	return q.setWorkerStatus(workerRid, model.WorkerStatusStopped)
+}
+
+// StopWorkerGracefully sets the status of the worker to stopping.
+func (q *Queuer) StopWorkerGracefully(workerRid uuid.UUID) error {
+	return q.setWorkerStatus(workerRid, model.WorkerStatusStopping)
+}
+
+// setWorkerStatus changes the status of the worker.
+func (q *Queuer) setWorkerStatus(workerRid uuid.UUID, status string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, worker := range q.workers {
+		if worker.RID == workerRid {
+			worker.Status = status
+			worker.UpdatedAt = time.Now()
+			return nil
+		}
+	}
+	return helper.NewError("selecting worker", fmt.Errorf("no worker with rid %s", workerRid))
+}
+
+// AddConnection adds a database connection returned by GetConnections.
+func (q *Queuer) AddConnection(connection *model.Connection) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.connections = append(q.connections, connection)
+}
+
+// GetConnections returns the added database connections.
+func (q *Queuer) GetConnections() ([]*model.Connection, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return slices.Clone(q.connections), nil
+}
+
+// Stop marks the queuer as stopped.
+func (q *Queuer) Stop() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.stopped = true
+	return nil
+}
+
+// Stopped reports whether the queuer was stopped.
+func (q *Queuer) Stopped() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.stopped
+}
+
+// findJob returns a copy of the job with the RID.
+func findJob(jobs []*model.Job, jobRid uuid.UUID) (*model.Job, error) {
+	for _, job := range jobs {
+		if job.RID == jobRid {
+			return copyJob(job), nil
+		}
+	}
+	return nil, helper.NewError("selecting job", fmt.Errorf("no job with rid %s", jobRid))
+}
+
+// pageJobs returns copies of the jobs matching the search, newest first and before the job with lastId.
+func pageJobs(jobs []*model.Job, search string, lastId int, entries int) []*model.Job {
+	page := []*model.Job{}
+	for i := len(jobs) - 1; i >= 0 && len(page) < entries; i-- {
+		job := jobs[i]
+		if lastId > 0 && job.ID >= lastId {
+			continue
+		}
+		if !matchesSearch(search, job.RID.String(), job.TaskName, job.Status) {
+			continue
+		}
+		page = append(page, copyJob(job))
+	}
+	return page
+}
+
+// matchesSearch reports whether one of the values contains the search case-insensitively, an empty search matches all.
+func matchesSearch(search string, values ...string) bool {
+	if search == "" {
+		return true
+	}
+	for _, value := range values {
+		if strings.Contains(strings.ToLower(value), strings.ToLower(search)) {
+			return true
+		}
+	}
+	return false
+}
+
+// copyJob returns a copy of the job, so callers can not change the jobs of the fake.
+func copyJob(job *model.Job) *model.Job {
+	jobCopy := *job
+	return &jobCopy
+}
@@ -0,0 +1,423 @@
+package fake
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager/model"
+	vm "github.com/siherrmann/validator/model"
+
+	"github.com/google/uuid"
+)
+
+// TaskDB is an in-memory task database with the semantics of database.TaskDBHandler,
+// eg. unique keys, the same order of the lists and the same pagination by lastID.
+// Tasks are stored as copies, so changing a returned task does not change the stored task.
+type TaskDB struct {
+	mu     sync.Mutex
+	nextID int
+	tasks  []*model.Task
+	exists bool
+}
+
+// NewTaskDB creates an empty in-memory task database with its table.
+func NewTaskDB() *TaskDB {
+	return &TaskDB{
+		tasks:  []*model.Task{},
+		exists: true,
+	}
+}
+
+// CheckTableExistance reports whether the table was created and not dropped.
+func (r *TaskDB) CheckTableExistance() (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.exists, nil
+}
+
+// CreateTable creates the table if it does not exist.
+func (r *TaskDB) CreateTable() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.exists = true
+	return nil
+}
+
+// DropTable drops the table with all tasks.
+func (r *TaskDB) DropTable() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tasks = []*model.Task{}
+	r.exists = false
+	return nil
+}
+
+// InsertTask inserts a task with the next ID and a new RID, the key has to be unique.
+func (r *TaskDB) InsertTask(task *model.Task) (*model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.indexByKey(task.Key) >= 0 {
+		return nil, helper.NewError("insert task", fmt.Errorf("duplicate key %s", task.Key))
+	}
+	return copyTask(r.insert(task)), nil
+}
+
+// UpdateTask updates the task with the RID of the task.
+func (r *TaskDB) UpdateTask(task *model.Task) (*model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.tasks, func(t *model.Task) bool { return t.RID == task.RID })
+	if i < 0 {
+		return nil, helper.NewError("update task", fmt.Errorf("no task with rid %s", task.RID))
+	}
+	if j := r.indexByKey(task.Key); j >= 0 && j != i {
+		return nil, helper.NewError("update task", fmt.Errorf("duplicate key %s", task.Key))
+	}
+	return copyTask(r.update(i, task)), nil
+}
+
+// UpsertTask inserts the task or updates the task with its key, created is true if the task was inserted.
+// The update time of an existing task only changes if the task changed.
+func (r *TaskDB) UpsertTask(task *model.Task) (*model.Task, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexByKey(task.Key)
+	if i < 0 {
+		return copyTask(r.insert(task)), true, nil
+	}
+
+	if !sameDefinition(r.tasks[i], task) {
+		r.update(i, task)
+	}
+	return copyTask(r.tasks[i]), false, nil
+}
+
+// DeleteTask deletes a task by RID, it returns an error if no task with the RID exists.
+func (r *TaskDB) DeleteTask(rid uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.tasks, func(t *model.Task) bool { return t.RID == rid })
+	if i < 0 {
+		return helper.NewError("task not found", fmt.Errorf("no task with rid %s", rid))
+	}
+	r.tasks = slices.Delete(r.tasks, i, i+1)
+	return nil
+}
+
+// DeleteTaskByKey deletes a task by key, it returns false without an error if no task with the key exists.
+func (r *TaskDB) DeleteTaskByKey(key string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexByKey(key)
+	if i < 0 {
+		return false, nil
+	}
+	r.tasks = slices.Delete(r.tasks, i, i+1)
+	return true, nil
+}
+
+// SelectTask returns a task by RID.
+func (r *TaskDB) SelectTask(rid uuid.UUID) (*model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.tasks, func(t *model.Task) bool { return t.RID == rid })
+	if i < 0 {
+		return nil, helper.NewError("scan", fmt.Errorf("no task with rid %s", rid))
+	}
+	return copyTask(r.tasks[i]), nil
+}
+
+// SelectTaskByKey returns a task by key.
+func (r *TaskDB) SelectTaskByKey(key string) (*model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexByKey(key)
+	if i < 0 {
+		return nil, helper.NewError("scan", fmt.Errorf("no task with key %s", key))
+	}
+	return copyTask(r.tasks[i]), nil
+}
+
+// SelectAllTasks returns the tasks with an ID greater than lastID ordered by ID.
+func (r *TaskDB) SelectAllTasks(lastID int, entries int) ([]*model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.page(func(t *model.Task) bool { return t.ID > lastID }, entries), nil
+}
+
+// SelectAllTasksBySearch returns the tasks with the search in their RID, key, name, description or owner
+// and in the namespace if set, newest first and created before the task with lastID.
+func (r *TaskDB) SelectAllTasksBySearch(search string, namespace string, lastID int, entries int) ([]*model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lastTask := r.byID(lastID)
+	tasks := slices.Clone(r.tasks)
+	slices.SortStableFunc(tasks, func(a, b *model.Task) int { return b.CreatedAt.Compare(a.CreatedAt) })
+
+	result := []*model.Task{}
+	for _, task := range tasks {
+		if len(result) >= entries {
+			break
+		}
+		if !matchesSearch(search, task.RID.String(), task.Key, task.Name, task.Description, task.Owner) {
+			continue
+		}
+		if namespace != "" && task.Namespace != namespace {
+			continue
+		}
+		if lastID != 0 && (lastTask == nil || !task.CreatedAt.Before(lastTask.CreatedAt)) {
+			continue
+		}
+		result = append(result, copyTask(task))
+	}
+	return result, nil
+}
+
+// SelectAllTasksByParameter returns the tasks with an input, keyed input or output parameter with the key
+// and an ID greater than lastID ordered by ID.
+func (r *TaskDB) SelectAllTasksByParameter(parameterKey string, lastID int, entries int) ([]*model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.page(func(t *model.Task) bool { return t.ID > lastID && hasParameter(t, parameterKey) }, entries), nil
+}
+
+// SelectAllTasksByFilter returns the tasks matching the filter in the sort order of the filter,
+// after the task with lastID in that order.
+func (r *TaskDB) SelectAllTasksByFilter(filter *model.TaskFilter, lastID int, entries int) ([]*model.Task, error) {
+	compare, ok := taskSortCompare(filter.Sort)
+	if !ok {
+		return nil, helper.NewError("select tasks by filter", fmt.Errorf("invalid sort %q", filter.Sort))
+	}
+	if filter.Descending {
+		ascending := compare
+		compare = func(a, b *model.Task) int { return ascending(b, a) }
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lastTask := r.byID(lastID)
+	tasks := slices.Clone(r.tasks)
+	slices.SortFunc(tasks, compare)
+
+	result := []*model.Task{}
+	for _, task := range tasks {
+		if len(result) >= entries {
+			break
+		}
+		if !matchesFilter(task, filter) {
+			continue
+		}
+		if lastID != 0 && (lastTask == nil || compare(task, lastTask) <= 0) {
+			continue
+		}
+		result = append(result, copyTask(task))
+	}
+	return result, nil
+}
+
+// SelectUpstreamTasks returns the tasks with the task in their on success dependencies ordered by ID.
+func (r *TaskDB) SelectUpstreamTasks(taskKey string) ([]*model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.page(func(t *model.Task) bool {
+		return slices.ContainsFunc(t.OnSuccess, func(d model.TaskDependency) bool { return d.TaskKey == taskKey })
+	}, len(r.tasks)), nil
+}
+
+// SelectAllTasksCount returns the number of tasks.
+func (r *TaskDB) SelectAllTasksCount() (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.tasks), nil
+}
+
+// SelectAllTasksByParameterCount returns the number of tasks with an input, keyed input or output parameter with the key.
+func (r *TaskDB) SelectAllTasksByParameterCount(parameterKey string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, task := range r.tasks {
+		if hasParameter(task, parameterKey) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// SelectAllTasksByFilterCount returns the number of tasks matching the filter.
+func (r *TaskDB) SelectAllTasksByFilterCount(filter *model.TaskFilter) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, task := range r.tasks {
+		if matchesFilter(task, filter) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// SelectNamespaces returns the distinct namespaces of the tasks sorted by name, tasks without namespace are left out.
+func (r *TaskDB) SelectNamespaces() ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	namespaces := []string{}
+	for _, task := range r.tasks {
+		if task.Namespace != "" && !slices.Contains(namespaces, task.Namespace) {
+			namespaces = append(namespaces, task.Namespace)
+		}
+	}
+	slices.Sort(namespaces)
+	return namespaces, nil
+}
+
+// insert stores a copy of the task with the next ID and a new RID, the lock has to be held.
+func (r *TaskDB) insert(task *model.Task) *model.Task {
+	now := time.Now()
+	r.nextID++
+	newTask := copyTask(task)
+	newTask.ID = r.nextID
+	newTask.RID = uuid.New()
+	newTask.CreatedAt = now
+	newTask.UpdatedAt = now
+	r.tasks = append(r.tasks, newTask)
+	return newTask
+}
+
+// update replaces the definition of the task at the index and keeps its generated fields, the lock has to be held.
+func (r *TaskDB) update(i int, task *model.Task) *model.Task {
+	existing := r.tasks[i]
+	updatedTask := copyTask(task)
+	updatedTask.ID = existing.ID
+	updatedTask.RID = existing.RID
+	updatedTask.CreatedAt = existing.CreatedAt
+	updatedTask.UpdatedAt = time.Now()
+	r.tasks[i] = updatedTask
+	return updatedTask
+}
+
+// indexByKey returns the index of the task with the key or -1, the lock has to be held.
+func (r *TaskDB) indexByKey(key string) int {
+	return slices.IndexFunc(r.tasks, func(t *model.Task) bool { return t.Key == key })
+}
+
+// byID returns the task with the ID or nil, the lock has to be held.
+func (r *TaskDB) byID(id int) *model.Task {
+	i := slices.IndexFunc(r.tasks, func(t *model.Task) bool { return t.ID == id })
+	if i < 0 {
+		return nil
+	}
+	return r.tasks[i]
+}
+
+// page returns copies of the first entries tasks matching the condition ordered by ID, the lock has to be held.
+func (r *TaskDB) page(matches func(t *model.Task) bool, entries int) []*model.Task {
+	result := []*model.Task{}
+	for _, task := range r.tasks {
+		if len(result) >= entries {
+			break
+		}
+		if matches(task) {
+			result = append(result, copyTask(task))
+		}
+	}
+	return result
+}
+
+// hasParameter reports whether the task has an input, keyed input or output parameter with the key.
+func hasParameter(task *model.Task, parameterKey string) bool {
+	isKey := func(v vm.Validation) bool { return v.Key == parameterKey }
+	return slices.ContainsFunc(task.InputParameters, isKey) ||
+		slices.ContainsFunc(task.InputParametersKeyed, isKey) ||
+		slices.ContainsFunc(task.OutputParameters, isKey)
+}
+
+// matchesFilter reports whether the task matches all filters of the task list.
+func matchesFilter(task *model.Task, filter *model.TaskFilter) bool {
+	if filter.Parameter != "" && !hasParameter(task, filter.Parameter) {
+		return false
+	}
+	if filter.KeyPrefix != "" && !strings.HasPrefix(task.Key, filter.KeyPrefix) {
+		return false
+	}
+	if filter.WithOutputParameters && len(task.OutputParameters) == 0 {
+		return false
+	}
+	if !filter.CreatedAfter.IsZero() && !task.CreatedAt.After(filter.CreatedAfter) {
+		return false
+	}
+	if !filter.CreatedBefore.IsZero() && !task.CreatedAt.Before(filter.CreatedBefore) {
+		return false
+	}
+	if filter.Namespace != "" && task.Namespace != filter.Namespace {
+		return false
+	}
+	return true
+}
+
+// taskSortCompare returns the ascending comparison of the sort field, tasks with the same value are compared by ID.
+func taskSortCompare(sort string) (func(a, b *model.Task) int, bool) {
+	var compare func(a, b *model.Task) int
+	switch sort {
+	case "":
+		compare = func(a, b *model.Task) int { return 0 }
+	case model.TASK_SORT_NAME:
+		compare = func(a, b *model.Task) int { return strings.Compare(a.Name, b.Name) }
+	case model.TASK_SORT_KEY:
+		compare = func(a, b *model.Task) int { return strings.Compare(a.Key, b.Key) }
+	case model.TASK_SORT_CREATED_AT:
+		compare = func(a, b *model.Task) int { return a.CreatedAt.Compare(b.CreatedAt) }
+	case model.TASK_SORT_UPDATED_AT:
+		compare = func(a, b *model.Task) int { return a.UpdatedAt.Compare(b.UpdatedAt) }
+	default:
+		return nil, false
+	}
+	return func(a, b *model.Task) int {
+		return cmp.Or(compare(a, b), cmp.Compare(a.ID, b.ID))
+	}, true
+}
+
+// sameDefinition reports whether the tasks are equal apart from their generated fields.
+func sameDefinition(a *model.Task, b *model.Task) bool {
+	aCopy, bCopy := copyTask(a), copyTask(b)
+	for _, task := range []*model.Task{aCopy, bCopy} {
+		task.ID, task.RID, task.CreatedAt, task.UpdatedAt = 0, uuid.Nil, time.Time{}, time.Time{}
+	}
+	return reflect.DeepEqual(aCopy, bCopy)
+}
+
+// copyTask returns a deep copy of the task as stored in the JSON columns of the database.
+func copyTask(task *model.Task) *model.Task {
+	data, err := json.Marshal(task)
+	if err != nil {
+		panic(fmt.Sprintf("error marshaling task %s: %v", task.Key, err))
+	}
+	taskCopy := &model.Task{}
+	err = json.Unmarshal(data, taskCopy)
+	if err != nil {
+		panic(fmt.Sprintf("error unmarshaling task %s: %v", task.Key, err))
+	}
+	return taskCopy
+}
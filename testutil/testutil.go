@@ -0,0 +1,125 @@
+// Package testutil starts a full manager instance with its own TimescaleDB container for integration tests,
+// eg. of programs embedding the manager with extensions, hooks or custom tasks.
+//
+//	func TestMain(m *testing.M) {
+//		tm, err := testutil.StartTestManager(queuerManager.WithLogLevel("warn"))
+//		if err != nil {
+//			log.Fatalf("error starting test manager: %v", err)
+//		}
+//		tm.Queuer.AddTaskWithName(myTask, "my-task")
+//		exitCode := m.Run()
+//		tm.Stop()
+//		os.Exit(exitCode)
+//	}
+//
+// The container needs a running Docker daemon, see testcontainers-go.
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"time"
+
+	"github.com/siherrmann/queuer"
+	"github.com/siherrmann/queuer/helper"
+	"github.com/siherrmann/queuerManager"
+	"github.com/siherrmann/queuerManager/handler"
+	"github.com/siherrmann/queuerManager/upload"
+
+	"github.com/labstack/echo/v5"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// stopTimeout is the time Stop waits for the manager to stop its queuer.
+const stopTimeout = 30 * time.Second
+
+// TestManager is a manager served by a local HTTP test server with the database of its container.
+type TestManager struct {
+	// URL is the base URL of the test server, eg. http://127.0.0.1:41234
+	URL string
+	// Server serves the routes of the manager, its client sends requests to it
+	Server *httptest.Server
+	// Echo is the Echo instance with all routes and middlewares of the manager
+	Echo *echo.Echo
+	// Handler is the manager handler, eg. to register validations or to access the databases of the manager
+	Handler *handler.ManagerHandler
+	// Queuer is the started queuer of the manager, tasks can be added to it while it runs
+	Queuer *queuer.Queuer
+	// App is the manager app
+	App *queuerManager.ManagerApp
+	// Database is the configuration of the database in the container, eg. for a second queuer in the test
+	Database *helper.DatabaseConfiguration
+
+	cancel   context.CancelFunc
+	teardown func(ctx context.Context, opts ...testcontainers.TerminateOption) error
+}
+
+// NewTestDatabaseConfiguration returns the configuration of the database of the TimescaleDB container with the port.
+// The tables are dropped on startup, so every test manager starts with an empty database.
+func NewTestDatabaseConfiguration(dbPort string) *helper.DatabaseConfiguration {
+	return &helper.DatabaseConfiguration{
+		Host:          "localhost",
+		Port:          dbPort,
+		Database:      "database",
+		Username:      "user",
+		Password:      "password",
+		Schema:        "public",
+		SSLMode:       "disable",
+		WithTableDrop: true,
+	}
+}
+
+// StartTestManager starts a TimescaleDB container and a manager with its database and in memory storage,
+// served by a local HTTP test server. The options change the configuration, eg. WithBasePath or WithLogLevel,
+// options changing the database apply to the container database anyway.
+// The manager has to be stopped with Stop, which also removes the container.
+func StartTestManager(options ...queuerManager.Option) (*TestManager, error) {
+	teardown, dbPort, err := helper.MustStartTimescaleContainer()
+	if err != nil {
+		return nil, fmt.Errorf("error starting timescale container: %w", err)
+	}
+
+	tm := &TestManager{
+		Database: NewTestDatabaseConfiguration(dbPort),
+		teardown: teardown,
+	}
+
+	options = append([]queuerManager.Option{queuerManager.WithFilesystem(upload.NewFilesystemMemory())}, options...)
+	options = append(options, queuerManager.WithDatabase(tm.Database))
+	tm.App = queuerManager.NewManagerAppWithConfig(nil, options...)
+
+	var ctx context.Context
+	ctx, tm.cancel = context.WithCancel(context.Background())
+	tm.Echo, err = tm.App.NewEcho(ctx)
+	if err != nil {
+		tm.cancel()
+		_ = teardown(context.Background())
+		return nil, fmt.Errorf("error starting manager: %w", err)
+	}
+	tm.Handler = tm.App.Handler()
+	tm.Queuer = tm.App.Queuer()
+
+	tm.Server = httptest.NewServer(tm.Echo)
+	tm.URL = tm.Server.URL
+
+	return tm, nil
+}
+
+// Stop closes the test server, shuts down the manager and removes its database container.
+func (tm *TestManager) Stop() error {
+	tm.Server.Close()
+	tm.cancel()
+
+	select {
+	case <-tm.App.Done():
+	case <-time.After(stopTimeout):
+		return fmt.Errorf("manager did not stop within %s", stopTimeout)
+	}
+
+	err := tm.teardown(context.Background())
+	if err != nil {
+		return fmt.Errorf("error tearing down timescale container: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package queuerManager
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v5"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// tlsEnabled reports if the manager is served with TLS, with certificate files or with autocert.
+func (c *Config) tlsEnabled() bool {
+	return c.TLSCertFile != "" || len(c.AutocertDomains) > 0
+}
+
+// validateTLS checks that the TLS configuration is complete and not ambiguous.
+func (c *Config) validateTLS() error {
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("TLS needs both a certificate and a key file")
+	}
+	if c.TLSCertFile != "" && len(c.AutocertDomains) > 0 {
+		return fmt.Errorf("TLS certificate files and autocert can not be combined")
+	}
+	if c.HTTPRedirectPort == "" {
+		return nil
+	}
+	if !c.tlsEnabled() {
+		return fmt.Errorf("HTTP redirect port %s needs TLS", c.HTTPRedirectPort)
+	}
+	if _, err := strconv.Atoi(c.HTTPRedirectPort); err != nil {
+		return fmt.Errorf("invalid HTTP redirect port %q", c.HTTPRedirectPort)
+	}
+	if c.HTTPRedirectPort == c.Port {
+		return fmt.Errorf("HTTP redirect port %s must differ from the port", c.HTTPRedirectPort)
+	}
+	return nil
+}
+
+// newTLSConfig returns the TLS configuration of the server with HTTP/2 and the handler of the HTTP redirect port.
+// With autocert the handler answers the HTTP challenges of Let's Encrypt and redirects all other requests.
+func (c *Config) newTLSConfig() (*tls.Config, http.Handler, error) {
+	redirect := httpsRedirectHandler(c.Port)
+
+	if len(c.AutocertDomains) > 0 {
+		certManager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			Cache:      autocert.DirCache(c.AutocertCacheDir),
+			HostPolicy: autocert.HostWhitelist(c.AutocertDomains...),
+			Email:      c.AutocertEmail,
+		}
+		tlsConfig := certManager.TLSConfig()
+		tlsConfig.MinVersion = tls.VersionTLS12
+		return tlsConfig, certManager.HTTPHandler(redirect), nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   []string{"h2", "http/1.1"},
+		Certificates: []tls.Certificate{cert},
+	}, redirect, nil
+}
+
+// httpsRedirectHandler permanently redirects the requests to the same host and path with HTTPS on the port.
+func httpsRedirectHandler(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if hostname, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = hostname
+		}
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
+
+// serveHTTPRedirect serves the HTTP redirect port until the context is done.
+func serveHTTPRedirect(ctx context.Context, port string, handler http.Handler) {
+	startConfig := echo.StartConfig{
+		Address:    ":" + port,
+		HideBanner: true,
+	}
+	err := startConfig.Start(ctx, handler)
+	if err != nil {
+		slog.Error("Failed to serve HTTP redirect", "port", port, "error", err)
+	}
+}
@@ -0,0 +1,127 @@
+package upload
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// encryptionHeader marks files that were encrypted at rest, so plain files written
+// before encryption was enabled can still be read.
+var encryptionHeader = []byte("QMENC1")
+
+// KeyProvider provides the AES-256 key used to encrypt files at rest.
+// Implement it to fetch the key from a KMS.
+type KeyProvider interface {
+	Key() ([]byte, error)
+}
+
+// EnvKeyProvider reads a base64 encoded 32 byte key from an environment variable
+type EnvKeyProvider struct {
+	Variable string
+}
+
+// Key returns the decoded key from the environment variable
+func (p EnvKeyProvider) Key() ([]byte, error) {
+	value := os.Getenv(p.Variable)
+	if value == "" {
+		return nil, fmt.Errorf("encryption key variable %s is not set", p.Variable)
+	}
+	return decodeKey(value)
+}
+
+// FileKeyProvider reads a base64 encoded 32 byte key from a file, eg. a secret mounted by a KMS agent
+type FileKeyProvider struct {
+	Path string
+}
+
+// Key returns the decoded key from the key file
+func (p FileKeyProvider) Key() ([]byte, error) {
+	value, err := os.ReadFile(p.Path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading encryption key file: %w", err)
+	}
+	return decodeKey(strings.TrimSpace(string(value)))
+}
+
+func decodeKey(value string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("encryption key is not valid base64: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encrypt seals the plaintext with AES-GCM and prefixes it with the encryption header and nonce
+func encrypt(gcm cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	out := append([]byte{}, encryptionHeader...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, plaintext, encryptionHeader), nil
+}
+
+// decrypt opens data written by encrypt, data without the encryption header is returned unchanged
+func decrypt(gcm cipher.AEAD, data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, encryptionHeader) {
+		return data, nil
+	}
+
+	data = data[len(encryptionHeader):]
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("encrypted file is too short")
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, encryptionHeader)
+}
+
+// decryptedFile implements billy.File for the read-only decrypted content of an encrypted file
+type decryptedFile struct {
+	*bytes.Reader
+	name string
+}
+
+func (f *decryptedFile) Name() string {
+	return f.name
+}
+
+func (f *decryptedFile) Write(p []byte) (n int, err error) {
+	return 0, errors.New("write not supported on decrypted files")
+}
+
+func (f *decryptedFile) Close() error {
+	return nil
+}
+
+func (f *decryptedFile) Lock() error {
+	return nil
+}
+
+func (f *decryptedFile) Unlock() error {
+	return nil
+}
+
+func (f *decryptedFile) Truncate(size int64) error {
+	return errors.New("truncate not supported on decrypted files")
+}
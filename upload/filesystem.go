@@ -31,7 +31,16 @@ type Filesystem interface {
 
 // CreateFilesystemFromEnv creates a filesystem based on environment variables
 func CreateFilesystemFromEnv() (Filesystem, error) {
-	storageMode := strings.ToLower(helper.GetEnvOrDefault("QUEUER_MANAGER_STORAGE_MODE", STORAGE_MODE_LOCAL))
+	return CreateFilesystem(
+		helper.GetEnvOrDefault("QUEUER_MANAGER_STORAGE_MODE", STORAGE_MODE_LOCAL),
+		helper.GetEnvOrDefault("QUEUER_MANAGER_STORAGE_PATH", "./uploads"),
+	)
+}
+
+// CreateFilesystem creates a filesystem of the storage mode, the base path is the directory of the local storage.
+// The S3 storage and the encryption of the local storage are configured with environment variables.
+func CreateFilesystem(storageMode string, basePath string) (Filesystem, error) {
+	storageMode = strings.ToLower(storageMode)
 
 	switch storageMode {
 	case STORAGE_MODE_S3:
@@ -41,16 +50,28 @@ func CreateFilesystemFromEnv() (Filesystem, error) {
 			BucketName:      os.Getenv("S3_BUCKET_NAME"),
 			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
 			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("S3_SESSION_TOKEN"),
+			RoleARN:         os.Getenv("S3_ROLE_ARN"),
+			RoleSessionName: helper.GetEnvOrDefault("S3_ROLE_SESSION_NAME", "queuer-manager"),
 			UseSSL:          helper.GetEnvOrDefault("S3_USE_SSL", "true") == "true",
 		}
-		if config.BucketName == "" || config.AccessKeyID == "" || config.SecretAccessKey == "" {
-			return nil, fmt.Errorf("missing required S3 configuration: S3_BUCKET_NAME, S3_ACCESS_KEY_ID, S3_SECRET_ACCESS_KEY")
+		if config.BucketName == "" {
+			return nil, fmt.Errorf("missing required S3 configuration: S3_BUCKET_NAME")
+		}
+		// Without static keys the credentials are loaded from the environment, eg. web identity or instance profile
+		if (config.AccessKeyID == "") != (config.SecretAccessKey == "") {
+			return nil, fmt.Errorf("S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY must be set together")
 		}
 		return NewFilesystemS3(config)
 	case STORAGE_MODE_MEMORY:
 		return NewFilesystemMemory(), nil
 	case STORAGE_MODE_LOCAL:
-		basePath := helper.GetEnvOrDefault("QUEUER_MANAGER_STORAGE_PATH", "./uploads")
+		if os.Getenv("QUEUER_MANAGER_STORAGE_ENCRYPTION_KEY") != "" {
+			return NewFilesystemLocalEncrypted(basePath, EnvKeyProvider{Variable: "QUEUER_MANAGER_STORAGE_ENCRYPTION_KEY"})
+		}
+		if keyFile := os.Getenv("QUEUER_MANAGER_STORAGE_ENCRYPTION_KEY_FILE"); keyFile != "" {
+			return NewFilesystemLocalEncrypted(basePath, FileKeyProvider{Path: keyFile})
+		}
 		return NewFilesystemLocal(basePath), nil
 	default:
 		return nil, fmt.Errorf("unsupported storage mode: %s (supported: local, s3, memory)", storageMode)
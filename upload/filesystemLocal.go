@@ -1,7 +1,11 @@
 package upload
 
 import (
+	"bytes"
+	"crypto/cipher"
+	"fmt"
 	"io"
+	"os"
 	"path/filepath"
 
 	"github.com/go-git/go-billy/v5"
@@ -9,10 +13,12 @@ import (
 	"github.com/siherrmann/queuerManager/helper"
 )
 
-// FilesystemLocal implements the Filesystem interface for local file storage using go-billy's osfs
+// FilesystemLocal implements the Filesystem interface for local file storage using go-billy's osfs.
+// If created with an encryption key, file contents are encrypted at rest with AES-GCM.
 type FilesystemLocal struct {
 	billy.Filesystem
 	basePath string
+	gcm      cipher.AEAD
 }
 
 // NewFilesystemLocal creates a new local filesystem instance with the specified base path
@@ -23,8 +29,41 @@ func NewFilesystemLocal(basePath string) Filesystem {
 	}
 }
 
-// Write streams data from reader to a file at the specified path
+// NewFilesystemLocalEncrypted creates a new local filesystem instance with the specified base path
+// that encrypts file contents written with Write and transparently decrypts them on Open
+func NewFilesystemLocalEncrypted(basePath string, keyProvider KeyProvider) (Filesystem, error) {
+	key, err := keyProvider.Key()
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+
+	return &FilesystemLocal{
+		Filesystem: osfs.New(basePath),
+		basePath:   basePath,
+		gcm:        gcm,
+	}, nil
+}
+
+// Write streams data from reader to a file at the specified path.
+// With encryption enabled the whole content is read into memory and written encrypted.
 func (fs *FilesystemLocal) Write(path string, reader io.Reader, size int64) error {
+	if fs.gcm != nil {
+		plaintext, err := io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		ciphertext, err := encrypt(fs.gcm, plaintext)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(ciphertext)
+	}
+
 	file, err := fs.Create(path)
 	if err != nil {
 		return err
@@ -35,6 +74,31 @@ func (fs *FilesystemLocal) Write(path string, reader io.Reader, size int64) erro
 	return err
 }
 
+// Open opens the named file for reading, encrypted files are decrypted transparently
+func (fs *FilesystemLocal) Open(filename string) (billy.File, error) {
+	return fs.OpenFile(filename, os.O_RDONLY, 0)
+}
+
+// OpenFile opens the named file, encrypted files opened read-only are decrypted transparently
+func (fs *FilesystemLocal) OpenFile(filename string, flag int, perm os.FileMode) (billy.File, error) {
+	file, err := fs.Filesystem.OpenFile(filename, flag, perm)
+	if err != nil || fs.gcm == nil || flag != os.O_RDONLY {
+		return file, err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := decrypt(fs.gcm, data)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting file %s: %w", filename, err)
+	}
+
+	return &decryptedFile{Reader: bytes.NewReader(plaintext), name: file.Name()}, nil
+}
+
 // ListFiles returns a list of all files in the filesystem
 func (fs *FilesystemLocal) ListFiles() ([]File, error) {
 	var files []File
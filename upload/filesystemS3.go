@@ -17,9 +17,15 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
+// S3_CREDENTIALS_EXPIRY_WINDOW is the time before expiry temporary credentials are refreshed,
+// so requests and uploads don't start with credentials that expire while they run
+const S3_CREDENTIALS_EXPIRY_WINDOW = 5 * time.Minute
+
 // FilesystemS3 implements the billy.Filesystem interface for S3-compatible storage
 type FilesystemS3 struct {
 	client     *s3.Client
@@ -32,26 +38,48 @@ type S3Config struct {
 	Endpoint        string // S3 endpoint URL (for S3-compatible services)
 	Region          string // AWS region
 	BucketName      string // S3 bucket name
-	AccessKeyID     string // AWS access key ID
+	AccessKeyID     string // AWS access key ID, the default credential chain is used if empty
 	SecretAccessKey string // AWS secret access key
+	SessionToken    string // Optional session token of temporary static credentials
+	RoleARN         string // Optional role to assume with the static or default credentials
+	RoleSessionName string // Optional session name of the assumed role
 	UseSSL          bool   // Whether to use SSL/TLS
 }
 
-// NewFilesystemS3 creates a new S3 filesystem instance with the specified configuration
+// NewFilesystemS3 creates a new S3 filesystem instance with the specified configuration.
+// Without static keys the default credential chain of the AWS SDK is used, which supports web identity
+// (eg. IRSA in Kubernetes), ECS task roles and EC2 instance profiles. Temporary credentials are cached
+// and refreshed automatically before they expire.
 func NewFilesystemS3(cfg S3Config) (Filesystem, error) {
-	awsConfig, err := config.LoadDefaultConfig(
-		context.Background(),
+	options := []func(*config.LoadOptions) error{
 		config.WithRegion(cfg.Region),
-		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
+		config.WithCredentialsCacheOptions(func(o *aws.CredentialsCacheOptions) {
+			o.ExpiryWindow = S3_CREDENTIALS_EXPIRY_WINDOW
+		}),
+	}
+	if cfg.AccessKeyID != "" {
+		options = append(options, config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
 			cfg.AccessKeyID,
 			cfg.SecretAccessKey,
-			"",
-		)),
-	)
+			cfg.SessionToken,
+		)))
+	}
+
+	awsConfig, err := config.LoadDefaultConfig(context.Background(), options...)
 	if err != nil {
 		return nil, err
 	}
 
+	// Assume the role with the loaded credentials, eg. for a bucket in another account
+	if cfg.RoleARN != "" {
+		assumeRoleProvider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(awsConfig), cfg.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = cfg.RoleSessionName
+		})
+		awsConfig.Credentials = aws.NewCredentialsCache(assumeRoleProvider, func(o *aws.CredentialsCacheOptions) {
+			o.ExpiryWindow = S3_CREDENTIALS_EXPIRY_WINDOW
+		})
+	}
+
 	s3Client := s3.NewFromConfig(awsConfig, func(o *s3.Options) {
 		if cfg.Endpoint != "" {
 			o.BaseEndpoint = aws.String(cfg.Endpoint)